@@ -15,10 +15,10 @@ import (
 	"golang.org/x/term"
 
 	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/db/postgres"
 	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/secrets"
 	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
-
-	_ "github.com/lib/pq"
 )
 
 func main() {
@@ -33,16 +33,21 @@ func main() {
 
 	cfg := config.GetConfig()
 
-	// Подключение к базе данных
-	conn, err := sql.Open(cfg.Database.Driver, cfg.Database.Source)
+	// DSN может храниться вне конфигурации (secrets.provider: file, vault)
+	secretsProvider, err := secrets.New(cfg.Secrets)
 	if err != nil {
-		logger.Fatalf("error connecting to database: %v", err)
+		logger.Fatalf("error creating secrets provider: %v", err)
+	}
+	if err := secrets.Apply(context.Background(), secretsProvider, cfg); err != nil {
+		logger.Fatalf("error loading secrets (provider: %s): %v", cfg.Secrets.Provider, err)
 	}
-	defer conn.Close()
 
-	if err = conn.Ping(); err != nil {
-		logger.Fatalf("error pinging database: %v", err)
+	// Подключение к базе данных
+	conn, closeDB, err := postgres.Open(context.Background(), cfg.Database)
+	if err != nil {
+		logger.Fatalf("error connecting to database: %v", err)
 	}
+	defer closeDB()
 
 	logger.Info("Database connection established")
 
@@ -3,9 +3,13 @@
 package api_models
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/buildinfo"
 )
 
 // FullTenderData описывает полную структуру тендера, включая его метаданные,
@@ -17,6 +21,16 @@ type FullTenderData struct {
 	TenderAddress string         `json:"tender_address"` // Адрес объекта
 	ExecutorData  Executor       `json:"executor"`       // Данные об исполнителе, составившем тендер
 	LotsData      map[string]Lot `json:"lots"`           // Список лотов тендера, где ключ — идентификатор лота
+
+	// OrganizationID — организация-владелец тендера. Парсер передает ее из
+	// загрузки (см. ProxyUploadHandler); 0 — организация по умолчанию.
+	// Учитывается только при создании тендера.
+	OrganizationID int64 `json:"organization_id,omitempty"`
+
+	// EtpStatus — статус процедуры на ЭТП (например, accepting_proposals).
+	// Тендеры с незавершённой процедурой обновляются по расписанию (etp.sync);
+	// пустой — сохранённый статус не меняется.
+	EtpStatus string `json:"etp_status,omitempty"`
 }
 
 // Executor представляет информацию об исполнителе тендера.
@@ -44,6 +58,17 @@ type ContractorProposalDetails struct {
 	ContractorHeight     int                      `json:"contractor_height"`         // Высота блока подрядчика
 	ContractorItems      ContractorItemsContainer `json:"contractor_items"`          // Позиции и итоги предложения
 	AdditionalInfo       map[string]*string       `json:"additional_info,omitempty"` // Дополнительная информация (например, сроки, условия)
+	Currency             string                   `json:"currency,omitempty"`        // Валюта сумм (ISO 4217), по умолчанию RUB
+}
+
+// CurrencyCode возвращает валюту предложения; без явного указания — DefaultCurrency.
+// Вызывается после Validate, поэтому ошибка формата здесь не возникает.
+func (cpd *ContractorProposalDetails) CurrencyCode() string {
+	code, err := NormalizeCurrency(cpd.Currency)
+	if err != nil || code == "" {
+		return DefaultCurrency
+	}
+	return code
 }
 
 // ContractorItemsContainer группирует позиции и сводные строки предложения.
@@ -54,131 +79,179 @@ type ContractorItemsContainer struct {
 
 // PositionItem представляет одну позицию из предложения подрядчика.
 type PositionItem struct {
-	Number                        string   `json:"number"`                                      // Порядковый номер
-	ChapterNumber                 *string  `json:"chapter_number,omitempty"`                    // Номер главы (если применимо)
-	ArticleSMR                    *string  `json:"article_smr,omitempty"`                       // Артикул СМР
-	JobTitle                      string   `json:"job_title"`                                   // Название работы
-	CommentOrganizer              *string  `json:"comment_organizer,omitempty"`                 // Комментарий организатора
-	Unit                          *string  `json:"unit,omitempty"`                              // Единица измерения
-	Quantity                      *float64 `json:"quantity,omitempty"`                          // Количество по ТЗ организатора
-	SuggestedQuantity             *float64 `json:"suggested_quantity,omitempty"`                // Предложенное количество от подрядчика
-	UnitCost                      Cost     `json:"unit_cost"`                                   // Стоимость за единицу
-	TotalCost                     Cost     `json:"total_cost"`                                  // Общая стоимость
-	TotalCostForOrganizerQuantity *float64 `json:"total_cost_for_organizer_quantity,omitempty"` // Стоимость за объём по ТЗ, но по ценам подрядчика
-	CommentContractor             *string  `json:"comment_contractor,omitempty"`                // Комментарий подрядчика
-	JobTitleNormalized            *string  `json:"job_title_normalized,omitempty"`              // Нормализованное название работы
-	IsChapter                     bool     `json:"is_chapter"`                                  // Является ли это заголовком главы
-	ChapterRef                    *string  `json:"chapter_ref,omitempty"`                       // Ссылка на главу, если применимо
+	Number                        string           `json:"number"`                                      // Порядковый номер
+	ChapterNumber                 *string          `json:"chapter_number,omitempty"`                    // Номер главы (если применимо)
+	ArticleSMR                    *string          `json:"article_smr,omitempty"`                       // Артикул СМР
+	JobTitle                      string           `json:"job_title"`                                   // Название работы
+	CommentOrganizer              *string          `json:"comment_organizer,omitempty"`                 // Комментарий организатора
+	Unit                          *string          `json:"unit,omitempty"`                              // Единица измерения
+	Quantity                      *decimal.Decimal `json:"quantity,omitempty"`                          // Количество по ТЗ организатора
+	SuggestedQuantity             *decimal.Decimal `json:"suggested_quantity,omitempty"`                // Предложенное количество от подрядчика
+	UnitCost                      Cost             `json:"unit_cost"`                                   // Стоимость за единицу
+	TotalCost                     Cost             `json:"total_cost"`                                  // Общая стоимость
+	TotalCostForOrganizerQuantity *Money           `json:"total_cost_for_organizer_quantity,omitempty"` // Стоимость за объём по ТЗ, но по ценам подрядчика
+	DeviationFromBaselineCost     *Money           `json:"deviation_from_baseline_cost,omitempty"`      // Отклонение от стоимости baseline
+	CommentContractor             *string          `json:"comment_contractor,omitempty"`                // Комментарий подрядчика
+	JobTitleNormalized            *string          `json:"job_title_normalized,omitempty"`              // Нормализованное название работы
+	IsChapter                     bool             `json:"is_chapter"`                                  // Является ли это заголовком главы
+	ChapterRef                    *string          `json:"chapter_ref,omitempty"`                       // Ссылка на главу, если применимо
+	Currency                      string           `json:"currency,omitempty"`                          // Валюта стоимостей, если отличается от валюты предложения
 }
 
 // Cost представляет разбивку стоимости по компонентам.
 type Cost struct {
-	Materials     *float64 `json:"materials"`      // Стоимость материалов
-	Works         *float64 `json:"works"`          // Стоимость работ
-	IndirectCosts *float64 `json:"indirect_costs"` // Накладные расходы
-	Total         *float64 `json:"total"`          // Общая стоимость
+	Materials     *Money `json:"materials"`      // Стоимость материалов
+	Works         *Money `json:"works"`          // Стоимость работ
+	IndirectCosts *Money `json:"indirect_costs"` // Накладные расходы
+	Total         *Money `json:"total"`          // Общая стоимость
 }
 
 // SummaryLine описывает итог по группе работ/разделу.
 type SummaryLine struct {
-	JobTitle               string   `json:"job_title"`                              // Заголовок итога
-	SuggestedQuantity      *float64 `json:"suggested_quantity"`                     // Объём, предложенный подрядчиком
-	UnitCost               Cost     `json:"unit_cost"`                              // Цена за единицу
-	TotalCost              Cost     `json:"total_cost"`                             // Общая стоимость
-	OrganizierQuantityCost *float64 `json:"total_cost_for_organizer_quantity"`      // Стоимость по исходному объёму
-	CommentContractor      *string  `json:"comment_contractor,omitempty"`           // Комментарий подрядчика
-	Deviation              *float64 `json:"deviation_from_baseline_cost,omitempty"` // Отклонение от базовой стоимости
+	JobTitle               string           `json:"job_title"`                              // Заголовок итога
+	SuggestedQuantity      *decimal.Decimal `json:"suggested_quantity"`                     // Объём, предложенный подрядчиком
+	UnitCost               Cost             `json:"unit_cost"`                              // Цена за единицу
+	TotalCost              Cost             `json:"total_cost"`                             // Общая стоимость
+	OrganizierQuantityCost *Money           `json:"total_cost_for_organizer_quantity"`      // Стоимость по исходному объёму
+	CommentContractor      *string          `json:"comment_contractor,omitempty"`           // Комментарий подрядчика
+	Deviation              *Money           `json:"deviation_from_baseline_cost,omitempty"` // Отклонение от базовой стоимости
+	Currency               string           `json:"currency,omitempty"`                     // Валюта сумм, если отличается от валюты предложения
 }
 
-// Validate проверяет корректность данных предложения подрядчика.
-// В случае ошибки возвращает подробное описание проблемы.
+// Validate проверяет корректность данных предложения подрядчика и возвращает
+// ValidationErrors со всеми нарушениями; пути — относительно предложения.
 // Аргумент isBaseline указывает, является ли это базовым предложением.
 func (cpd *ContractorProposalDetails) Validate(isBaseline bool) error {
+	var errs ValidationErrors
+	cpd.validate(&errs, "", isBaseline)
+	return errs.err()
+}
+
+func (cpd *ContractorProposalDetails) validate(errs *ValidationErrors, path string, isBaseline bool) {
 	if strings.TrimSpace(cpd.Title) == "" {
-		return fmt.Errorf("название подрядчика (title) не может быть пустым")
+		errs.add(joinPath(path, "title"), RuleRequired, "название подрядчика не может быть пустым")
 	}
-	if !isBaseline && strings.TrimSpace(cpd.Inn) == "" {
-		return fmt.Errorf("ИНН подрядчика (inn) не может быть пустым для '%s'", cpd.Title)
-	}
-	if !isBaseline && strings.TrimSpace(cpd.Address) == "" {
-		return fmt.Errorf("адрес подрядчика (address) не может быть пустым")
-	}
-	if !isBaseline && cpd.ContractorCoordinate == "" {
-		return fmt.Errorf("координаты подрядчика (contractor_coordinate) не могут быть пустыми")
+	if !isBaseline {
+		if strings.TrimSpace(cpd.Inn) == "" {
+			errs.add(joinPath(path, "inn"), RuleRequired, "ИНН подрядчика не может быть пустым")
+		}
+		if strings.TrimSpace(cpd.Address) == "" {
+			errs.add(joinPath(path, "address"), RuleRequired, "адрес подрядчика не может быть пустым")
+		}
+		if cpd.ContractorCoordinate == "" {
+			errs.add(joinPath(path, "contractor_coordinate"), RuleRequired, "координаты подрядчика не могут быть пустыми")
+		}
+		if cpd.ContractorWidth <= 0 {
+			errs.add(joinPath(path, "contractor_width"), RulePositive, "ширина подрядчика должна быть положительной")
+		}
+		if cpd.ContractorHeight <= 0 {
+			errs.add(joinPath(path, "contractor_height"), RulePositive, "высота подрядчика должна быть положительной")
+		}
+		if len(cpd.ContractorItems.Positions) == 0 {
+			errs.add(joinPath(path, "contractor_items.positions"), RuleMinItems, "необходимо указать хотя бы одну позицию")
+		}
 	}
-	if !isBaseline && cpd.ContractorWidth <= 0 {
-		return fmt.Errorf("ширина подрядчика (contractor_width) должна быть положительной")
+	if _, err := NormalizeCurrency(cpd.Currency); err != nil {
+		errs.add(joinPath(path, "currency"), RuleCurrency, err.Error())
 	}
-	if !isBaseline && cpd.ContractorHeight <= 0 {
-		return fmt.Errorf("высота подрядчика (contractor_height) должна быть положительной")
+	itemsPath := joinPath(path, "contractor_items")
+	for _, key := range sortedKeys(cpd.ContractorItems.Positions) {
+		if _, err := NormalizeCurrency(cpd.ContractorItems.Positions[key].Currency); err != nil {
+			errs.add(joinPath(keyPath(itemsPath, "positions", key), "currency"), RuleCurrency, err.Error())
+		}
 	}
-	if !isBaseline && len(cpd.ContractorItems.Positions) == 0 {
-		return fmt.Errorf("необходимо указать хотя бы одну позицию")
+	for _, key := range sortedKeys(cpd.ContractorItems.Summary) {
+		if _, err := NormalizeCurrency(cpd.ContractorItems.Summary[key].Currency); err != nil {
+			errs.add(joinPath(keyPath(itemsPath, "summary", key), "currency"), RuleCurrency, err.Error())
+		}
 	}
-	return nil
 }
 
-// Validate проверяет корректность данных лота, включая базовое и подрядные предложения.
+// Validate проверяет корректность данных лота, включая базовое и подрядные
+// предложения. Пути нарушений — относительно лота (proposals["ООО Альфа"].inn).
 func (l *Lot) Validate() error {
+	var errs ValidationErrors
+	l.validate(&errs, "")
+	return errs.err()
+}
+
+func (l *Lot) validate(errs *ValidationErrors, path string) {
 	if strings.TrimSpace(l.LotTitle) == "" {
-		return fmt.Errorf("название лота (lot_title) не может быть пустым")
-	}
-	if err := l.BaseLineProposal.Validate(true); err != nil {
-		return fmt.Errorf("ошибка в базовом предложении лота '%s': %w", l.LotTitle, err)
+		errs.add(joinPath(path, "lot_title"), RuleRequired, "название лота не может быть пустым")
 	}
-	for key, proposal := range l.ProposalData {
-		if err := proposal.Validate(false); err != nil {
-			return fmt.Errorf("ошибка в предложении '%s' лота '%s': %w", key, l.LotTitle, err)
-		}
+	l.BaseLineProposal.validate(errs, joinPath(path, "baseline_proposal"), true)
+	for _, key := range sortedKeys(l.ProposalData) {
+		proposal := l.ProposalData[key]
+		proposal.validate(errs, keyPath(path, "proposals", key), false)
 	}
-	return nil
 }
 
 // Validate проверяет корректность данных исполнителя.
 func (e *Executor) Validate() error {
+	var errs ValidationErrors
+	e.validate(&errs, "")
+	return errs.err()
+}
+
+func (e *Executor) validate(errs *ValidationErrors, path string) {
 	if strings.TrimSpace(e.ExecutorName) == "" {
-		return fmt.Errorf("имя исполнителя (executor_name) не может быть пустым")
+		errs.add(joinPath(path, "executor_name"), RuleRequired, "имя исполнителя не может быть пустым")
 	}
 	if strings.TrimSpace(e.ExecutorPhone) == "" {
-		return fmt.Errorf("телефон исполнителя (executor_phone) не может быть пустым")
+		errs.add(joinPath(path, "executor_phone"), RuleRequired, "телефон исполнителя не может быть пустым")
 	}
-	return nil
 }
 
-// Validate проверяет полную структуру тендера, включая исполнителя и все лоты.
+// Validate проверяет полную структуру тендера, включая исполнителя и все лоты,
+// и возвращает ValidationErrors со всеми нарушениями.
 func (ftd *FullTenderData) Validate() error {
+	var errs ValidationErrors
+	ftd.validateHeader(&errs)
+	if len(ftd.LotsData) == 0 {
+		errs.add("lots", RuleMinItems, "необходимо указать хотя бы один лот")
+	}
+	for _, key := range sortedKeys(ftd.LotsData) {
+		lot := ftd.LotsData[key]
+		lot.validate(&errs, keyPath("", "lots", key))
+	}
+	return errs.err()
+}
+
+// ValidateHeader проверяет поля тендера и исполнителя без лотов.
+// Используется потоковым импортом, который проверяет лоты по мере чтения.
+func (ftd *FullTenderData) ValidateHeader() error {
+	var errs ValidationErrors
+	ftd.validateHeader(&errs)
+	return errs.err()
+}
+
+func (ftd *FullTenderData) validateHeader(errs *ValidationErrors) {
 	if strings.TrimSpace(ftd.TenderID) == "" {
-		return fmt.Errorf("ID тендера (tender_id) не может быть пустым")
+		errs.add("tender_id", RuleRequired, "ID тендера не может быть пустым")
 	}
 	if strings.TrimSpace(ftd.TenderTitle) == "" {
-		return fmt.Errorf("название тендера (tender_title) не может быть пустым")
+		errs.add("tender_title", RuleRequired, "название тендера не может быть пустым")
 	}
 	if strings.TrimSpace(ftd.TenderObject) == "" {
-		return fmt.Errorf("объект тендера (tender_object) не может быть пустым")
+		errs.add("tender_object", RuleRequired, "объект тендера не может быть пустым")
 	}
 	if strings.TrimSpace(ftd.TenderAddress) == "" {
-		return fmt.Errorf("адрес тендера (tender_address) не может быть пустым")
+		errs.add("tender_address", RuleRequired, "адрес тендера не может быть пустым")
 	}
 	if ftd.ExecutorData == (Executor{}) {
-		return fmt.Errorf("данные исполнителя (executor) не могут быть пустыми")
-	}
-	if len(ftd.LotsData) == 0 {
-		return fmt.Errorf("необходимо указать хотя бы один лот (lots)")
-	}
-	if err := ftd.ExecutorData.Validate(); err != nil {
-		return err
-	}
-	for key, lot := range ftd.LotsData {
-		if err := lot.Validate(); err != nil {
-			return fmt.Errorf("ошибка в лоте '%s': %w", key, err)
-		}
+		errs.add("executor", RuleRequired, "данные исполнителя не могут быть пустыми")
+		return
 	}
-	return nil
+	ftd.ExecutorData.validate(errs, "executor")
 }
 
-// SimpleLotAIResult представляет упрощенный результат AI обработки только с lot_id
+// SimpleLotAIResult представляет упрощенный результат AI обработки только с lot_id.
+// Поля происхождения необязательны: каждый запуск сохраняется в ai_analysis_results.
 type SimpleLotAIResult struct {
 	LotKeyParameters map[string]interface{} `json:"lot_key_parameters" binding:"required"` // Ключевые параметры, извлеченные AI
+	ModelName        string                 `json:"model_name"`                            // Модель, выполнившая анализ
+	PromptVersion    string                 `json:"prompt_version"`                        // Версия промпта
+	RawOutput        string                 `json:"raw_output"`                            // Сырой ответ модели
 }
 
 // Validate проверяет корректность данных упрощенного AI результата
@@ -189,13 +262,112 @@ func (slar *SimpleLotAIResult) Validate() error {
 	return nil
 }
 
+// AIAnalysisRun - один сохранённый запуск AI-анализа лота.
+// IsCurrent отмечает запуск, параметры которого применены к лоту последними.
+type AIAnalysisRun struct {
+	ID            int64           `json:"id"`
+	LotID         int64           `json:"lot_id"`
+	ModelName     string          `json:"model_name"`
+	PromptVersion string          `json:"prompt_version"`
+	RawOutput     string          `json:"raw_output"`
+	KeyParameters json.RawMessage `json:"key_parameters"`
+	CreatedAt     time.Time       `json:"created_at"`
+	PromotedAt    *time.Time      `json:"promoted_at"`
+	IsCurrent     bool            `json:"is_current"`
+}
+
+// AIAnalysisRunsResponse - DTO ответа для GET /api/v1/lots/:id/ai-results.
+// Запуски отсортированы от нового к старому.
+type AIAnalysisRunsResponse struct {
+	LotID int64           `json:"lot_id"`
+	Runs  []AIAnalysisRun `json:"runs"`
+}
+
+// LotKeyParameterSchemaResponse - JSON Schema ключевых параметров лота для
+// категории тендера (GET/PUT /api/v1/admin/key-parameter-schemas/:categoryId).
+type LotKeyParameterSchemaResponse struct {
+	TenderCategoryID int64           `json:"tender_category_id"`
+	Schema           json.RawMessage `json:"schema"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
 // MatchPositionRequest - это JSON, который Go-сервер ожидает от Python-воркера
 // при вызове POST /api/v1/positions/match
 type MatchPositionRequest struct {
 	PositionItemID    int64  `json:"position_item_id" binding:"required"`
 	CatalogPositionID int64  `json:"catalog_position_id" binding:"required"`
 	Hash              string `json:"hash" binding:"required"`
-	NormVersion       int    `json:"norm_version"` // Опционально, сервис Go подставит '1' по умолчанию
+	NormVersion       int    `json:"norm_version"` // Опционально, по умолчанию — активная версия (GET /internal/worker/norm-version)
+	// WorkerID - идентификатор экземпляра воркера (например, hostname:pid).
+	// Опционально: по умолчанию подставляется имя сервиса из аутентификации, но тогда
+	// все экземпляры считаются одним воркером и защита от конфликтов между ними не работает.
+	WorkerID string `json:"worker_id"`
+}
+
+// MatchPositionsBatchRequest - это JSON для POST /internal/worker/positions/match-batch.
+// WorkerID применяется к записям, в которых worker_id не задан.
+type MatchPositionsBatchRequest struct {
+	WorkerID string                 `json:"worker_id"`
+	Matches  []MatchPositionRequest `json:"matches" binding:"required"`
+}
+
+// MatchPositionBatchItemResult - результат одной записи пакетного сопоставления.
+// Status: matched | conflict | not_found | invalid | error.
+type MatchPositionBatchItemResult struct {
+	PositionItemID int64       `json:"position_item_id"`
+	Status         string      `json:"status"`
+	Error          string      `json:"error,omitempty"`
+	Conflict       interface{} `json:"conflict,omitempty"` // Текущее закрепление позиции (для status=conflict)
+}
+
+// MatchPositionsBatchResponse - ответ POST /internal/worker/positions/match-batch.
+// Results идут в порядке записей запроса.
+type MatchPositionsBatchResponse struct {
+	Matched int                            `json:"matched"`
+	Failed  int                            `json:"failed"`
+	Results []MatchPositionBatchItemResult `json:"results"`
+}
+
+// CorrectPositionMatchRequest - это JSON для PATCH /api/v1/positions/:id/catalog-match.
+// Аналитик заменяет ошибочное сопоставление RAG правильной позицией каталога.
+type CorrectPositionMatchRequest struct {
+	CatalogPositionID int64   `json:"catalog_position_id" binding:"required"`
+	Comment           *string `json:"comment"` // Опционально: почему сопоставление было ошибочным
+}
+
+// MatchingCorrectionResponse - ручное исправление сопоставления: ответ
+// PATCH /api/v1/positions/:id/catalog-match и запись GET /internal/worker/matching/corrections.
+// Названия позиций каталога заполняются только в выгрузке для воркера.
+type MatchingCorrectionResponse struct {
+	ID                        int64     `json:"id"`
+	PositionItemID            int64     `json:"position_item_id"`
+	JobTitleText              string    `json:"job_title_text"`
+	JobTitleHash              string    `json:"job_title_hash"`
+	NormVersion               int16     `json:"norm_version"`
+	PreviousCatalogPositionID *int64    `json:"previous_catalog_position_id"` // nil — позиция не была сопоставлена
+	PreviousStandardJobTitle  string    `json:"previous_standard_job_title,omitempty"`
+	PreviousMatchedBy         string    `json:"previous_matched_by,omitempty"` // Воркер (или user:<id>), чьё сопоставление исправлено
+	CatalogPositionID         int64     `json:"catalog_position_id"`
+	StandardJobTitle          string    `json:"standard_job_title,omitempty"`
+	Comment                   string    `json:"comment,omitempty"`
+	CreatedAt                 time.Time `json:"created_at"`
+}
+
+// UnmatchPositionResponse - ответ POST /api/v1/positions/:id/unmatch.
+// CacheInvalidated — удалена запись matching_cache, по которой позиция была сопоставлена.
+type UnmatchPositionResponse struct {
+	PositionItemID            int64  `json:"position_item_id"`
+	PreviousCatalogPositionID int64  `json:"previous_catalog_position_id"`
+	PreviousMatchedBy         string `json:"previous_matched_by,omitempty"`
+	CacheInvalidated          bool   `json:"cache_invalidated"`
+}
+
+// MatchingCorrectionsPage - ответ GET /internal/worker/matching/corrections.
+// NextAfterID — курсор для следующего запроса (nil, если исправлений не выдано).
+type MatchingCorrectionsPage struct {
+	Items       []MatchingCorrectionResponse `json:"items"`
+	NextAfterID *int64                       `json:"next_after_id,omitempty"`
 }
 
 // CatalogIndexedRequest - это JSON для POST /api/v1/catalog/indexed
@@ -204,6 +376,18 @@ type CatalogIndexedRequest struct {
 	CatalogIDs []int64 `json:"catalog_ids" binding:"required"`
 }
 
+// CatalogActivationReport - результат проверки или применения массовой активации каталога
+// (POST /api/v1/catalog/indexed, POST /api/v1/admin/catalog/activate).
+// Каждый запрошенный ID попадает ровно в один список; дубликаты во входе схлопываются.
+type CatalogActivationReport struct {
+	DryRun           bool    `json:"dry_run"`
+	Requested        int     `json:"requested"`          // Уникальных ID во входе
+	Activated        []int64 `json:"activated"`          // Активированы (в dry-run — будут активированы)
+	NotFound         []int64 `json:"not_found"`          // Нет в catalog_positions
+	AlreadyActive    []int64 `json:"already_active"`     // Уже в статусе active
+	MergedOrInactive []int64 `json:"merged_or_inactive"` // Влиты в другую позицию или deprecated/archived
+}
+
 // --- System Settings DTOs ---
 
 // UpdateSystemSettingRequest - это DTO запроса для PUT /api/v1/admin/settings.
@@ -229,12 +413,159 @@ type SystemSettingResponse struct {
 	UpdatedBy    string   `json:"updated_by"`
 }
 
+// ImportValidationIssue - одна проблема, найденная при проверке импорта.
+type ImportValidationIssue struct {
+	Code    string `json:"code"`           // validation, duplicate_json_key, summary_mismatch, duplicate_position_number, unknown_unit
+	Path    string `json:"path,omitempty"` // Например: lots["lot_1"].proposals["ООО Альфа"]
+	Message string `json:"message"`
+}
+
+// ImportValidationReport - ответ POST /internal/worker/import-tender?dry_run=true.
+// Valid=false, если есть ошибки; предупреждения импорт не блокируют.
+type ImportValidationReport struct {
+	DryRun         bool                    `json:"dry_run"`
+	Valid          bool                    `json:"valid"`
+	TenderID       string                  `json:"tender_id"`
+	LotsCount      int                     `json:"lots_count"`
+	ProposalsCount int                     `json:"proposals_count"` // Без baseline
+	PositionsCount int                     `json:"positions_count"` // Включая baseline и заголовки глав
+	Errors         []ImportValidationIssue `json:"errors"`
+	Warnings       []ImportValidationIssue `json:"warnings"`
+}
+
 // ImportTenderResponse - это DTO ответа для POST /api/v1/import-tender
 // Возвращает информацию о результате импорта тендера.
 type ImportTenderResponse struct {
 	TenderDBID             int64            `json:"tender_db_id"`
 	LotIDsMap              map[string]int64 `json:"lot_ids_map"`
 	NewCatalogItemsPending bool             `json:"new_catalog_items_pending"`
+	// ImportID - ID трассировки импорта (GET /api/v1/admin/imports/:id/trace).
+	// Отсутствует, если тайминги не удалось сохранить.
+	ImportID int64 `json:"import_id,omitempty"`
+}
+
+// ImportLotTiming - тайминг обработки одного лота при импорте.
+type ImportLotTiming struct {
+	LotKey         string  `json:"lot_key"`
+	DurationMs     float64 `json:"duration_ms"`
+	ProposalsCount int     `json:"proposals_count"`
+	PositionsCount int     `json:"positions_count"`
+}
+
+// ImportTraceResponse - DTO ответа для GET /api/v1/admin/imports/:id/trace.
+// Времена этапов в миллисекундах; positions_ms включает cache_lookup_ms,
+// lots_ms включает positions_ms.
+type ImportTraceResponse struct {
+	ID             int64             `json:"id"`
+	TenderID       *int64            `json:"tender_id"`
+	EtpID          string            `json:"etp_id"`
+	Status         string            `json:"status"`
+	Error          *string           `json:"error,omitempty"`
+	PayloadBytes   int64             `json:"payload_bytes"`
+	LotsCount      int32             `json:"lots_count"`
+	PositionsCount int32             `json:"positions_count"`
+	CacheHits      int32             `json:"cache_hits"`
+	CacheMisses    int32             `json:"cache_misses"`
+	TxWaitMs       float64           `json:"tx_wait_ms"`
+	CoreMs         float64           `json:"core_ms"`
+	LotsMs         float64           `json:"lots_ms"`
+	PositionsMs    float64           `json:"positions_ms"`
+	CacheLookupMs  float64           `json:"cache_lookup_ms"`
+	RawDataMs      float64           `json:"raw_data_ms"`
+	CommitMs       float64           `json:"commit_ms"`
+	TotalMs        float64           `json:"total_ms"`
+	Lots           []ImportLotTiming `json:"lots"`
+	StartedAt      time.Time         `json:"started_at"`
+}
+
+// TenderImportVersion - одна сохранённая версия исходного JSON тендера.
+type TenderImportVersion struct {
+	Version      int32     `json:"version"`
+	PayloadHash  string    `json:"payload_hash"`
+	PayloadBytes int64     `json:"payload_bytes"`
+	ImportedAt   time.Time `json:"imported_at"`
+}
+
+// TenderImportsResponse - DTO ответа для GET /api/v1/tenders/:id/imports.
+// Версии отсортированы от новой к старой.
+type TenderImportsResponse struct {
+	TenderID int64                 `json:"tender_id"`
+	Versions []TenderImportVersion `json:"versions"`
+}
+
+// DiffFieldChange - изменение одного поля между двумя версиями импорта.
+// Для числовых полей, заданных в обеих версиях, заполняется Delta (to - from).
+type DiffFieldChange struct {
+	Field string           `json:"field"`
+	From  interface{}      `json:"from"`
+	To    interface{}      `json:"to"`
+	Delta *decimal.Decimal `json:"delta,omitempty"`
+}
+
+// DiffItemRef - позиция или итоговая строка, добавленная или удалённая целиком.
+type DiffItemRef struct {
+	Key   string `json:"key"`
+	Title string `json:"title"`
+	Total *Money `json:"total"`
+}
+
+// DiffItemChange - позиция или итоговая строка, изменившаяся между версиями.
+type DiffItemChange struct {
+	Key     string            `json:"key"`
+	Title   string            `json:"title"`
+	Changes []DiffFieldChange `json:"changes"`
+}
+
+// DiffItems - изменения набора позиций или итоговых строк предложения.
+type DiffItems struct {
+	Added   []DiffItemRef    `json:"added"`
+	Removed []DiffItemRef    `json:"removed"`
+	Changed []DiffItemChange `json:"changed"`
+}
+
+// ProposalDiff - изменения предложения, присутствующего в обеих версиях.
+// Базовое предложение организатора имеет ключ "baseline".
+type ProposalDiff struct {
+	ProposalKey string            `json:"proposal_key"`
+	Changes     []DiffFieldChange `json:"changes"`
+	Positions   DiffItems         `json:"positions"`
+	Summary     DiffItems         `json:"summary"`
+}
+
+// LotDiff - изменения лота, присутствующего в обеих версиях.
+type LotDiff struct {
+	LotKey           string            `json:"lot_key"`
+	Changes          []DiffFieldChange `json:"changes"`
+	ProposalsAdded   []string          `json:"proposals_added"`
+	ProposalsRemoved []string          `json:"proposals_removed"`
+	Proposals        []ProposalDiff    `json:"proposals"`
+}
+
+// TenderImportDiffStats - счётчики изменений для быстрого обзора.
+type TenderImportDiffStats struct {
+	LotsAdded        int `json:"lots_added"`
+	LotsRemoved      int `json:"lots_removed"`
+	LotsChanged      int `json:"lots_changed"`
+	ProposalsAdded   int `json:"proposals_added"`
+	ProposalsRemoved int `json:"proposals_removed"`
+	ProposalsChanged int `json:"proposals_changed"`
+	PositionsAdded   int `json:"positions_added"`
+	PositionsRemoved int `json:"positions_removed"`
+	PositionsChanged int `json:"positions_changed"`
+}
+
+// TenderImportDiff - DTO ответа для GET /api/v1/tenders/:id/imports/diff.
+// В списках только лоты и предложения, которые есть в обеих версиях и изменились.
+type TenderImportDiff struct {
+	TenderID    int64                 `json:"tender_id"`
+	FromVersion int32                 `json:"from_version"`
+	ToVersion   int32                 `json:"to_version"`
+	Identical   bool                  `json:"identical"`
+	Changes     []DiffFieldChange     `json:"changes"`
+	LotsAdded   []string              `json:"lots_added"`
+	LotsRemoved []string              `json:"lots_removed"`
+	Lots        []LotDiff             `json:"lots"`
+	Stats       TenderImportDiffStats `json:"stats"`
 }
 
 // SuggestMergeRequest - это JSON для POST /api/v1/merges/suggest
@@ -253,6 +584,33 @@ type UnmatchedPositionResponse struct {
 	RichContextString  string `json:"rich_context_string"`
 	DraftCatalogID     *int64 `json:"draft_catalog_id,omitempty"` // ID записи catalog_positions со статусом pending (fallback для RAG)
 	StandardJobTitle   string `json:"standard_job_title"`         // Лемматизированная версия для поиска в catalog_positions
+	IsPinned           bool   `json:"is_pinned,omitempty"`        // Только для /catalog/active: позиция закреплена администратором
+}
+
+// ActiveCatalogItemsPage — ответ GET /api/v1/catalog/active с параметром cursor
+// (keyset-пагинация). Без cursor эндпоинт возвращает массив, как раньше.
+type ActiveCatalogItemsPage struct {
+	Items      []UnmatchedPositionResponse `json:"items"`
+	NextCursor string                      `json:"next_cursor,omitempty"` // Пусто — это последняя страница
+}
+
+// ClaimUnmatchedPositionsRequest - это JSON для POST /internal/worker/positions/claim.
+// Воркер арендует следующую порцию позиций после after_id на lease_seconds.
+type ClaimUnmatchedPositionsRequest struct {
+	WorkerID     string     `json:"worker_id"`
+	Limit        int32      `json:"limit"`         // По умолчанию 100, максимум 1000
+	AfterID      int64      `json:"after_id"`      // Курсор: позиции с id > after_id
+	TenderID     *int64     `json:"tender_id"`     // Опционально: только позиции тендера
+	CreatedAfter *time.Time `json:"created_after"` // Опционально: только позиции, созданные не раньше
+	LeaseSeconds int32      `json:"lease_seconds"` // По умолчанию 300, максимум 3600
+}
+
+// ClaimUnmatchedPositionsResponse - ответ POST /internal/worker/positions/claim.
+// NextAfterID — курсор для следующего запроса (nil, если позиций не выдано).
+type ClaimUnmatchedPositionsResponse struct {
+	Positions    []UnmatchedPositionResponse `json:"positions"`
+	ClaimedUntil *time.Time                  `json:"claimed_until,omitempty"`
+	NextAfterID  *int64                      `json:"next_after_id,omitempty"`
 }
 
 // MergeScenario — тип сценария слияния.
@@ -286,6 +644,18 @@ type ExecuteMergeResponse struct {
 	ResolvedAt              time.Time     `json:"resolved_at"`               // Время выполнения слияния
 }
 
+// ApproveMergeResponse - это DTO ответа для POST /api/v1/admin/merges/:id/approve.
+// Одобрение сразу исполняет слияние B → A и перевешивает ссылки на B.
+type ApproveMergeResponse struct {
+	MergeID                 int64     `json:"merge_id"`
+	MainPositionID          int64     `json:"main_position_id"`          // Мастер-позиция (A), остаётся active
+	DuplicatePositionID     int64     `json:"duplicate_position_id"`     // Дубликат (B), влит в A
+	DuplicateStatus         string    `json:"duplicate_status"`          // Новый статус дубликата ("deprecated")
+	RetargetedPositionItems int64     `json:"retargeted_position_items"` // Сколько position_items перевешено с B на A
+	RetargetedCacheEntries  int64     `json:"retargeted_cache_entries"`  // Сколько записей matching_cache перевешено с B на A
+	ResolvedAt              time.Time `json:"resolved_at"`
+}
+
 // ExecuteBatchMergeRequest - это DTO запроса для POST /api/v1/admin/merges/execute-batch
 //
 // Выбор сценария:
@@ -318,6 +688,9 @@ type CatalogPositionSummary struct {
 	Description      *string `json:"description,omitempty"`
 	Kind             string  `json:"kind"`
 	Status           string  `json:"status"`
+	IsPinned         bool    `json:"is_pinned"`
+	WorkGroupCode    *string `json:"work_group_code,omitempty"` // Узел классификатора видов работ
+	ReplacedByID     *int64  `json:"replaced_by_id,omitempty"`  // Замена выведенной из оборота позиции
 }
 
 // SuggestedMergeItem — одно предложение о слиянии с краткой информацией о дубликате.
@@ -404,6 +777,34 @@ type GroupBatchPositionsResponse struct {
 	ResolvedAt  time.Time `json:"resolved_at"`
 }
 
+// === Catalog Pinning (PATCH /api/v1/admin/catalog/positions/:id/pin) ===
+
+// SetCatalogPositionPinnedRequest — DTO запроса для закрепления/открепления позиции каталога.
+// Указатель нужен, чтобы отличить явный false от отсутствующего поля.
+type SetCatalogPositionPinnedRequest struct {
+	Pinned *bool `json:"pinned"`
+}
+
+// DeprecateCatalogPositionRequest — тело POST /api/v1/admin/catalog/positions/:id/deprecate.
+type DeprecateCatalogPositionRequest struct {
+	ReplacedByID int64 `json:"replaced_by_id" binding:"required,gt=0"` // Позиция, которая заменяет выводимую
+}
+
+// DeprecateCatalogPositionResponse — результат вывода позиции каталога из оборота.
+type DeprecateCatalogPositionResponse struct {
+	Position               CatalogPositionSummary `json:"position"`                 // Выведенная позиция (deprecated)
+	ReplacedBy             CatalogPositionSummary `json:"replaced_by"`              // Замена
+	RepointedReplacements  int64                  `json:"repointed_replacements"`   // Ранее выведенные в пользу этой позиции теперь ссылаются на замену
+	RetargetedCacheEntries int64                  `json:"retargeted_cache_entries"` // Записи matching_cache, перевешенные на замену
+	DeprecatedAt           time.Time              `json:"deprecated_at"`
+}
+
+// ListPinnedCatalogPositionsResponse — ответ GET /api/v1/admin/catalog/pinned.
+type ListPinnedCatalogPositionsResponse struct {
+	Positions []CatalogPositionSummary `json:"positions"`
+	Total     int                      `json:"total"`
+}
+
 // GroupConflict — описание конфликта при группировке: позиция уже в другой группе.
 type GroupConflict struct {
 	PositionID         int64  `json:"position_id"`
@@ -412,3 +813,1708 @@ type GroupConflict struct {
 	CurrentParentTitle string `json:"current_parent_title"`
 	SiblingsCount      int64  `json:"siblings_count"`
 }
+
+// === Service Credentials (/api/v1/admin/service-credentials) ===
+
+// CreateServiceCredentialRequest — DTO запроса на выпуск нового ключа внутреннего сервиса.
+type CreateServiceCredentialRequest struct {
+	ServiceName string   `json:"service_name"`          // Имя воркера, например "parser-1"
+	Scopes      []string `json:"scopes"`                // import | rag | ai-results, хотя бы один
+	Description string   `json:"description,omitempty"` // Для чего выпущен ключ (опционально)
+}
+
+// ServiceCredentialResponse — информация о ключе сервиса (без самого ключа и его хеша).
+type ServiceCredentialResponse struct {
+	ID            int64      `json:"id"`
+	ServiceName   string     `json:"service_name"`
+	KeyPrefix     string     `json:"key_prefix"`
+	Scopes        []string   `json:"scopes"`
+	Description   *string    `json:"description,omitempty"`
+	CreatedBy     string     `json:"created_by"`
+	CreatedAt     time.Time  `json:"created_at"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt    *time.Time `json:"last_used_at,omitempty"`    // Обновляется с задержкой до интервала обновления кэша
+	RotatedFromID *int64     `json:"rotated_from_id,omitempty"` // Отозванный предшественник, если ключ выпущен ротацией
+}
+
+// CreateServiceCredentialResponse — ответ на выпуск ключа. Key показывается только один раз.
+type CreateServiceCredentialResponse struct {
+	ServiceCredentialResponse
+	Key string `json:"key"`
+}
+
+// === Webhooks (/api/v1/admin/webhooks) ===
+
+// CreateWebhookRequest — DTO запроса на создание подписки.
+type CreateWebhookRequest struct {
+	URL         string   `json:"url"`                   // http(s)://...
+	Events      []string `json:"events"`                // tender.imported | lot.ai_results | winner.set, хотя бы одно
+	Description string   `json:"description,omitempty"` // Кто и зачем подписан (опционально)
+}
+
+// UpdateWebhookRequest — DTO частичного обновления подписки. Не переданные поля не меняются.
+type UpdateWebhookRequest struct {
+	URL         *string   `json:"url,omitempty"`
+	Events      *[]string `json:"events,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	IsActive    *bool     `json:"is_active,omitempty"`
+}
+
+// WebhookResponse — подписка без секрета.
+type WebhookResponse struct {
+	ID          int64     `json:"id"`
+	URL         string    `json:"url"`
+	Events      []string  `json:"events"`
+	Description *string   `json:"description,omitempty"`
+	IsActive    bool      `json:"is_active"`
+	CreatedBy   *int64    `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CreateWebhookResponse — ответ на создание подписки. Secret показывается только один раз.
+type CreateWebhookResponse struct {
+	WebhookResponse
+	Secret string `json:"secret"` // Ключ HMAC-SHA256 для проверки заголовка X-Webhook-Signature
+}
+
+// WebhookDeliveryResponse — запись журнала доставок.
+type WebhookDeliveryResponse struct {
+	ID             int64           `json:"id"`
+	SubscriptionID int64           `json:"subscription_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"` // pending | delivered | failed
+	Attempts       int32           `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"` // Только для pending
+	LastAttemptAt  *time.Time      `json:"last_attempt_at,omitempty"`
+	ResponseStatus *int32          `json:"response_status,omitempty"` // HTTP-код последней попытки
+	LastError      *string         `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+// WebhookDeliveriesResponse — ответ GET /api/v1/admin/webhooks/:id/deliveries.
+type WebhookDeliveriesResponse struct {
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+	Limit      int32                     `json:"limit"`
+	Offset     int32                     `json:"offset"`
+}
+
+// === Перенормализация каталога (/api/v1/admin/catalog/norm-version) ===
+
+// CatalogRenormalizationRunResponse — ход перенормализации после повышения norm_version.
+type CatalogRenormalizationRunResponse struct {
+	ID                  int64      `json:"id"`
+	NormVersion         int16      `json:"norm_version"`
+	Status              string     `json:"status"` // running | completed
+	TotalPositions      int64      `json:"total_positions"`
+	RequeuedPositions   int64      `json:"requeued_positions"`
+	CacheEntriesDeleted int64      `json:"cache_entries_deleted"`
+	StartedBy           *int64     `json:"started_by,omitempty"`
+	StartedAt           time.Time  `json:"started_at"`
+	FinishedAt          *time.Time `json:"finished_at,omitempty"`
+}
+
+// NormVersionResponse — активная версия нормализации и последний запуск перенормализации
+// (renormalization отсутствует, если версию ещё не повышали).
+type NormVersionResponse struct {
+	NormVersion     int16                              `json:"norm_version"`
+	Renormalization *CatalogRenormalizationRunResponse `json:"renormalization,omitempty"`
+}
+
+// === Фоновые задачи (GET /api/v1/admin/jobs) ===
+
+// JobStatusResponse — состояние фоновой задачи планировщика в этом экземпляре API.
+type JobStatusResponse struct {
+	Name           string     `json:"name"`
+	Interval       string     `json:"interval"` // Например, "1h0m0s"
+	Running        bool       `json:"running"`
+	NextRunAt      time.Time  `json:"next_run_at"`
+	LastStatus     string     `json:"last_status"` // never | success | failed
+	LastStartedAt  *time.Time `json:"last_started_at,omitempty"`
+	LastFinishedAt *time.Time `json:"last_finished_at,omitempty"`
+	LastDurationMs *int64     `json:"last_duration_ms,omitempty"`
+	LastResult     *string    `json:"last_result,omitempty"` // Краткий итог успешного запуска
+	LastError      *string    `json:"last_error,omitempty"`
+	RunCount       int64      `json:"run_count"`
+	FailureCount   int64      `json:"failure_count"`
+	SkippedCount   int64      `json:"skipped_count"` // Запуски, пропущенные из-за ещё не завершённого предыдущего
+	LockedCount    int64      `json:"locked_count"`  // Запуски, пропущенные, потому что задачу выполнял другой экземпляр API
+}
+
+// JobsResponse — ответ GET /api/v1/admin/jobs.
+type JobsResponse struct {
+	Jobs []JobStatusResponse `json:"jobs"`
+}
+
+// === Кэш справочников (GET /api/v1/admin/cache/stats) ===
+
+// RefCacheGroupStats — счётчики кэша одного справочника в этом экземпляре API.
+type RefCacheGroupStats struct {
+	Group         string `json:"group"` // tender_types | tender_chapters | tender_categories | units
+	TTL           string `json:"ttl"`   // Например, "10m0s"
+	Hits          int64  `json:"hits"`
+	Misses        int64  `json:"misses"`
+	Sets          int64  `json:"sets"`
+	Invalidations int64  `json:"invalidations"`
+	Errors        int64  `json:"errors"` // Ошибки хранилища (Redis); запрос при этом обслуживается из БД
+}
+
+// RefCacheStatsResponse — ответ GET /api/v1/admin/cache/stats.
+type RefCacheStatsResponse struct {
+	Driver  string               `json:"driver"`            // none | memory | redis
+	Entries *int                 `json:"entries,omitempty"` // Число ответов в памяти (только driver: memory)
+	Groups  []RefCacheGroupStats `json:"groups"`
+}
+
+// === Исходящие HTTP-запросы (GET /api/v1/admin/outbound/stats) ===
+
+// OutboundDestinationStats — circuit breaker и счётчики запросов к одному
+// внутреннему сервису в этом экземпляре API.
+type OutboundDestinationStats struct {
+	Destination         string     `json:"destination"` // host:port
+	State               string     `json:"state"`       // closed | open | half_open
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"` // Когда breaker разомкнулся (только не closed)
+	Requests            int64      `json:"requests"`            // Отправленные попытки, включая повторы
+	Failures            int64      `json:"failures"`            // Сетевые ошибки и ответы 5xx
+	Retries             int64      `json:"retries"`
+	Rejected            int64      `json:"rejected"` // Отклонены разомкнутым breaker без отправки
+	LastError           string     `json:"last_error,omitempty"`
+	LastErrorAt         *time.Time `json:"last_error_at,omitempty"`
+}
+
+// OutboundHTTPStatsResponse — ответ GET /api/v1/admin/outbound/stats.
+type OutboundHTTPStatsResponse struct {
+	Destinations []OutboundDestinationStats `json:"destinations"`
+}
+
+// === Качество матчинга (GET /api/v1/admin/matching/stats) ===
+
+// MatchingCacheStats — попадания в matching_cache при импортах за период или неделю.
+// HitRate — доля попаданий среди обращений к кэшу (nil — обращений не было).
+type MatchingCacheStats struct {
+	WeekStart   *time.Time `json:"week_start,omitempty"` // Только в weekly: понедельник недели (UTC)
+	Imports     int64      `json:"imports"`
+	CacheHits   int64      `json:"cache_hits"`
+	CacheMisses int64      `json:"cache_misses"`
+	HitRate     *float64   `json:"hit_rate"`
+}
+
+// MatchingPositionStats — позиции КП по способу сопоставления на момент запроса.
+// Доли — от Total; при Total = 0 — нули.
+type MatchingPositionStats struct {
+	Total          int64   `json:"total"`
+	AutoCache      int64   `json:"auto_cache"` // Сопоставлены при импорте по matching_cache
+	AutoRAG        int64   `json:"auto_rag"`   // Сопоставлены воркером
+	Manual         int64   `json:"manual"`     // Сопоставлены аналитиком
+	Unmatched      int64   `json:"unmatched"`
+	AutoShare      float64 `json:"auto_share"`
+	ManualShare    float64 `json:"manual_share"`
+	UnmatchedShare float64 `json:"unmatched_share"`
+}
+
+// MatchingMergeStats — решения по предложениям слияния за период. Средняя
+// схожесть — nil, если решений такого вида не было.
+type MatchingMergeStats struct {
+	Accepted              int64    `json:"accepted"` // APPROVED и EXECUTED
+	AcceptedAvgSimilarity *float64 `json:"accepted_avg_similarity"`
+	Rejected              int64    `json:"rejected"`
+	RejectedAvgSimilarity *float64 `json:"rejected_avg_similarity"`
+	Pending               int64    `json:"pending"` // Текущая очередь, без учёта периода
+}
+
+// MatchingBacklogStats — возраст позиций в очереди несопоставленных, в секундах.
+type MatchingBacklogStats struct {
+	Positions  int64   `json:"positions"`
+	P50Seconds float64 `json:"p50_seconds"`
+	P90Seconds float64 `json:"p90_seconds"`
+	P99Seconds float64 `json:"p99_seconds"`
+	MaxSeconds float64 `json:"max_seconds"`
+}
+
+// MatchingStatsResponse — ответ GET /api/v1/admin/matching/stats. Кэш, слияния
+// и исправления считаются за период [from, to]; позиции и очередь — на момент запроса.
+type MatchingStatsResponse struct {
+	From        string                `json:"from"` // ГГГГ-ММ-ДД
+	To          string                `json:"to"`   // ГГГГ-ММ-ДД, включительно
+	Cache       MatchingCacheStats    `json:"cache"`
+	Weekly      []MatchingCacheStats  `json:"weekly"`
+	Positions   MatchingPositionStats `json:"positions"`
+	Corrections int64                 `json:"corrections"` // Ручные исправления за период
+	Merges      MatchingMergeStats    `json:"merges"`
+	Backlog     MatchingBacklogStats  `json:"backlog"`
+}
+
+// === Классификатор видов работ (work_groups) ===
+
+// WorkGroupNode — узел классификатора с потомками (GET /api/v1/catalog/work-groups).
+type WorkGroupNode struct {
+	ID                    int64           `json:"id"`
+	ParentID              *int64          `json:"parent_id,omitempty"`
+	Code                  string          `json:"code"` // Например, "01.02"
+	Name                  string          `json:"name"`
+	PositionsCount        int64           `json:"positions_count"`         // Позиции каталога узла
+	SubtreePositionsCount int64           `json:"subtree_positions_count"` // Вместе с потомками
+	Children              []WorkGroupNode `json:"children"`
+}
+
+// WorkGroupTreeResponse — ответ GET /api/v1/catalog/work-groups.
+type WorkGroupTreeResponse struct {
+	Groups []WorkGroupNode `json:"groups"` // Корневые узлы, по коду
+}
+
+// WorkGroupRequest — тело POST /api/v1/admin/work-groups и
+// PUT /api/v1/admin/work-groups/:id. Без parent_id узел корневой.
+type WorkGroupRequest struct {
+	ParentID *int64 `json:"parent_id" binding:"omitempty,gt=0"`
+	Code     string `json:"code" binding:"required,max=32"`
+	Name     string `json:"name" binding:"required,max=255"`
+}
+
+// SetCatalogPositionWorkGroupRequest — тело PUT /api/v1/admin/catalog/positions/:id/work-group.
+// null — снять привязку.
+type SetCatalogPositionWorkGroupRequest struct {
+	WorkGroupCode *string `json:"work_group_code"`
+}
+
+// LotWorkGroupProposal — колонка свёртки: предложение лота.
+type LotWorkGroupProposal struct {
+	ProposalID     int64  `json:"proposal_id"`
+	ContractorName string `json:"contractor_name"`
+	IsBaseline     bool   `json:"is_baseline"`
+}
+
+// WorkGroupProposalCost — стоимость позиций предложения в узле.
+type WorkGroupProposalCost struct {
+	ProposalID     int64 `json:"proposal_id"`
+	TotalCost      Money `json:"total_cost"`
+	PositionsCount int64 `json:"positions_count"`
+}
+
+// LotWorkGroupCost — узел классификатора в свёртке лота. Costs включают
+// стоимость позиций всех потомков узла.
+type LotWorkGroupCost struct {
+	ID       int64                   `json:"id"`
+	Code     string                  `json:"code"`
+	Name     string                  `json:"name"`
+	Costs    []WorkGroupProposalCost `json:"costs"` // В порядке Proposals; предложения без позиций узла пропущены
+	Children []LotWorkGroupCost      `json:"children"`
+}
+
+// LotWorkGroupCostsResponse — ответ GET /api/v1/lots/:id/analytics/work-groups:
+// стоимость позиций предложений лота, свёрнутая по классификатору видов работ.
+// Суммы — в базовой валюте Currency; узлы без позиций лота не выводятся.
+type LotWorkGroupCostsResponse struct {
+	LotID        int64                   `json:"lot_id"`
+	LotTitle     string                  `json:"lot_title"`
+	Currency     string                  `json:"currency"`
+	Proposals    []LotWorkGroupProposal  `json:"proposals"` // Baseline первым
+	Groups       []LotWorkGroupCost      `json:"groups"`
+	Unclassified []WorkGroupProposalCost `json:"unclassified"` // Позиции без узла классификатора
+}
+
+// === Baseline Estimate (POST /api/v1/lots/:id/baseline/estimate) ===
+
+// Статусы позиции оценочного baseline.
+const (
+	BaselineEstimateEstimated  = "estimated"   // Цена — средняя по истории каталога
+	BaselineEstimateNotMatched = "not_matched" // Позиция не сопоставлена с каталогом
+	BaselineEstimateNoPrices   = "no_prices"   // За окно нет цен в той же единице измерения
+)
+
+// BaselineEstimatePosition — позиция оценочного baseline.
+type BaselineEstimatePosition struct {
+	PositionKey       string  `json:"position_key"`
+	JobTitle          string  `json:"job_title"`
+	CatalogPositionID *int64  `json:"catalog_position_id,omitempty"`
+	Quantity          *string `json:"quantity,omitempty"`
+	UnitCost          *Money  `json:"unit_cost,omitempty"`
+	TotalCost         *Money  `json:"total_cost,omitempty"` // UnitCost × Quantity
+	PricesCount       int64   `json:"prices_count"`         // Сколько цен усреднено
+	Status            string  `json:"status"`
+}
+
+// BaselineEstimateResponse — ответ POST /api/v1/lots/:id/baseline/estimate.
+// Цены — средние цены за единицу из истории каталога за WindowMonths
+// месяцев в базовой валюте Currency. При DryRun ничего не сохраняется и
+// ProposalID не заполнен.
+type BaselineEstimateResponse struct {
+	LotID            int64                      `json:"lot_id"`
+	ProposalID       *int64                     `json:"proposal_id,omitempty"` // Baseline лота
+	SourceProposalID int64                      `json:"source_proposal_id"`    // Откуда взят перечень работ
+	DryRun           bool                       `json:"dry_run"`
+	Currency         string                     `json:"currency"`
+	WindowMonths     int                        `json:"window_months"`
+	TotalCost        Money                      `json:"total_cost"` // Сумма TotalCost оценённых позиций
+	EstimatedCount   int                        `json:"estimated_count"`
+	MissingCount     int                        `json:"missing_count"` // Позиции без оценки
+	Positions        []BaselineEstimatePosition `json:"positions"`
+}
+
+// === Price Anomalies (GET /api/v1/lots/:id/anomalies) ===
+
+// Уровни подозрительной цены.
+const (
+	AnomalySeverityWarning  = "warning"
+	AnomalySeverityCritical = "critical"
+)
+
+// Ориентиры, с которыми сравнивается цена.
+const (
+	AnomalyReferencePeerMedian     = "peer_median"     // Медиана цен других подрядчиков лота
+	AnomalyReferenceCatalogHistory = "catalog_history" // Средняя цена позиции каталога по другим тендерам
+)
+
+// Подсказки о вероятной причине отклонения.
+const (
+	AnomalyHintPossibleTypo = "possible_typo" // Цена отличается от ориентира примерно в 10^n раз
+	AnomalyHintDumping      = "dumping"       // Цена ниже ориентира
+	AnomalyHintOverpriced   = "overpriced"    // Цена выше ориентира
+)
+
+// PriceAnomalyReason — отклонение цены от одного ориентира.
+type PriceAnomalyReason struct {
+	Reference        string `json:"reference"` // AnomalyReference*
+	ReferenceCost    Money  `json:"reference_cost"`
+	ReferenceCount   int64  `json:"reference_count"`   // Сколько цен в ориентире
+	DeviationPercent string `json:"deviation_percent"` // (цена - ориентир) / ориентир * 100
+	Severity         string `json:"severity"`
+	Hint             string `json:"hint"`
+}
+
+// PriceAnomaly — позиция предложения с подозрительной ценой за единицу.
+type PriceAnomaly struct {
+	PositionItemID    int64                `json:"position_item_id"`
+	ProposalID        int64                `json:"proposal_id"`
+	ContractorID      int64                `json:"contractor_id"`
+	ContractorName    string               `json:"contractor_name"`
+	PositionKey       string               `json:"position_key"`
+	JobTitle          string               `json:"job_title"`
+	CatalogPositionID int64                `json:"catalog_position_id"`
+	Unit              *string              `json:"unit,omitempty"`
+	UnitCost          Money                `json:"unit_cost"`
+	Severity          string               `json:"severity"` // Наибольший уровень среди Reasons
+	Reasons           []PriceAnomalyReason `json:"reasons"`
+}
+
+// LotAnomaliesResponse — ответ GET /api/v1/lots/:id/anomalies. Цены — в базовой
+// валюте Currency. Позиция помечается, если её цена отклоняется от ориентира
+// больше чем на ThresholdPercent (critical — больше CriticalPercent).
+type LotAnomaliesResponse struct {
+	LotID            int64          `json:"lot_id"`
+	CategoryID       *int64         `json:"category_id,omitempty"` // Категория тендера: от неё зависит порог
+	Currency         string         `json:"currency"`
+	ThresholdPercent float64        `json:"threshold_percent"`
+	CriticalPercent  float64        `json:"critical_percent"`
+	CheckedCount     int            `json:"checked_count"` // Сколько цен проверено
+	Anomalies        []PriceAnomaly `json:"anomalies"`     // Сначала critical, затем по величине отклонения
+}
+
+// === Lot Comparison (GET /api/v1/lots/:id/comparison) ===
+
+// LotComparisonContractor — колонка матрицы сравнения: одно предложение лота.
+type LotComparisonContractor struct {
+	ProposalID            int64  `json:"proposal_id"`
+	ContractorID          int64  `json:"contractor_id"`
+	ContractorName        string `json:"contractor_name"`
+	ContractorInn         string `json:"contractor_inn"`
+	IsBaseline            bool   `json:"is_baseline"`
+	TotalCost             *Money `json:"total_cost,omitempty"` // Итог КП с НДС из сводной таблицы
+	Currency              string `json:"currency"`             // Валюта итога
+	MissingPositionsCount int    `json:"missing_positions_count"`
+}
+
+// LotComparisonCell — значение одной позиции каталога в одном предложении.
+// Количество и процент передаются строками, деньги — как Money в валюте Currency.
+// Курсы не применяются: отклонение считается, только если валюта совпадает с baseline.
+type LotComparisonCell struct {
+	ProposalID                   int64   `json:"proposal_id"`
+	IsMissing                    bool    `json:"is_missing"` // Позиция отсутствует в предложении
+	Quantity                     *string `json:"quantity,omitempty"`
+	UnitCost                     *Money  `json:"unit_cost,omitempty"`
+	TotalCost                    *Money  `json:"total_cost,omitempty"`
+	Currency                     string  `json:"currency,omitempty"`
+	DeviationFromBaselinePercent *string `json:"deviation_from_baseline_percent,omitempty"` // (total - baseline) / baseline * 100
+}
+
+// LotComparisonRow — строка матрицы: позиция каталога и ячейки в порядке Contractors.
+type LotComparisonRow struct {
+	CatalogPositionID int64               `json:"catalog_position_id"`
+	StandardJobTitle  string              `json:"standard_job_title"`
+	UnitName          *string             `json:"unit_name,omitempty"`
+	Cells             []LotComparisonCell `json:"cells"`
+}
+
+// LotComparisonResponse — ответ GET /api/v1/lots/:id/comparison.
+type LotComparisonResponse struct {
+	LotID       int64                     `json:"lot_id"`
+	LotTitle    string                    `json:"lot_title"`
+	Contractors []LotComparisonContractor `json:"contractors"` // baseline (если есть) всегда первым
+	Positions   []LotComparisonRow        `json:"positions"`
+}
+
+// === Lot Analytics (GET /api/v1/lots/:id/analytics) ===
+
+// LotContractorAnalytics — показатели одного предложения подрядчика по лоту.
+// Суммы — в базовой валюте ответа; исходный итог — в OriginalTotalCost/Currency.
+type LotContractorAnalytics struct {
+	ProposalID                   int64   `json:"proposal_id"`
+	ContractorID                 int64   `json:"contractor_id"`
+	ContractorName               string  `json:"contractor_name"`
+	ContractorInn                string  `json:"contractor_inn"`
+	Currency                     string  `json:"currency"`                                  // Исходная валюта итога
+	OriginalTotalCost            *Money  `json:"original_total_cost,omitempty"`             // Итог в исходной валюте, если она не базовая
+	TotalCost                    *Money  `json:"total_cost,omitempty"`                      // nil, если нет итога или курса
+	DeviationFromBaseline        *Money  `json:"deviation_from_baseline,omitempty"`         // total - baseline
+	DeviationFromBaselinePercent *string `json:"deviation_from_baseline_percent,omitempty"` // (total - baseline) / baseline * 100
+	PricedPositionsCount         int64   `json:"priced_positions_count"`                    // Позиции, оценённые минимум двумя подрядчиками
+	CheapestPositionsCount       int64   `json:"cheapest_positions_count"`                  // Из них — где подрядчик самый дешёвый
+}
+
+// LotAnalyticsResponse — ответ GET /api/v1/lots/:id/analytics.
+// Статистика считается по предложениям подрядчиков; baseline в неё не входит.
+// Все суммы пересчитаны в базовую валюту Currency по курсам из конфигурации.
+type LotAnalyticsResponse struct {
+	LotID                     int64                    `json:"lot_id"`
+	LotTitle                  string                   `json:"lot_title"`
+	Currency                  string                   `json:"currency"`
+	BaselineTotal             *Money                   `json:"baseline_total,omitempty"`
+	ProposalsCount            int                      `json:"proposals_count"`
+	PricedProposalsCount      int64                    `json:"priced_proposals_count"`      // Предложения с итогом (в базовой валюте)
+	UnconvertedProposalsCount int64                    `json:"unconverted_proposals_count"` // С итогом в валюте без курса; в статистику не входят
+	MinTotal                  *Money                   `json:"min_total,omitempty"`
+	MaxTotal                  *Money                   `json:"max_total,omitempty"`
+	MedianTotal               *Money                   `json:"median_total,omitempty"`
+	AvgTotal                  *Money                   `json:"avg_total,omitempty"`
+	SpreadPercent             *string                  `json:"spread_percent,omitempty"` // (max - min) / min * 100
+	Contractors               []LotContractorAnalytics `json:"contractors"`              // От дешёвых к дорогим
+}
+
+// LotTotals — краткие агрегаты лота для страницы тендера (GET /api/v1/tenders/:id).
+// Суммы пересчитаны в базовую валюту Currency; baseline в ProposalsCount не входит.
+type LotTotals struct {
+	Currency             string  `json:"currency"`
+	BaselineTotal        *Money  `json:"baseline_total,omitempty"`
+	BestOffer            *Money  `json:"best_offer,omitempty"` // Минимальный итог предложения подрядчика
+	ProposalsCount       int64   `json:"proposals_count"`
+	PricedProposalsCount int64   `json:"priced_proposals_count"`
+	Savings              *Money  `json:"savings,omitempty"`         // baseline - best_offer
+	SavingsPercent       *string `json:"savings_percent,omitempty"` // (baseline - best_offer) / baseline * 100
+}
+
+// === Proposal Consistency (GET /api/v1/proposals/:id/consistency) ===
+
+// ProposalChapterCheck — сверка итога главы с суммой её позиций (включая вложенные главы).
+type ProposalChapterCheck struct {
+	Number         string `json:"number"`
+	Title          string `json:"title"`
+	StoredTotal    *Money `json:"stored_total,omitempty"`
+	ComputedTotal  Money  `json:"computed_total"`
+	PositionsCount int    `json:"positions_count"`
+	Difference     *Money `json:"difference,omitempty"` // stored_total - computed_total
+	Consistent     bool   `json:"consistent"`
+}
+
+// ProposalSummaryCheck — итоговая строка предложения и её отличие от суммы всех позиций.
+type ProposalSummaryCheck struct {
+	SummaryKey  string `json:"summary_key"`
+	JobTitle    string `json:"job_title"`
+	StoredTotal *Money `json:"stored_total,omitempty"`
+	Difference  *Money `json:"difference,omitempty"` // stored_total - positions_total
+	Matches     bool   `json:"matches"`
+}
+
+// ProposalConsistencyReport — ответ GET /api/v1/proposals/:id/consistency.
+// Consistent=false, если есть хотя бы одно расхождение сверх допуска.
+// Суммы пересчитываются из total_cost_total позиций.
+type ProposalConsistencyReport struct {
+	ProposalID     int64                   `json:"proposal_id"`
+	Consistent     bool                    `json:"consistent"`
+	AbsTolerance   float64                 `json:"abs_tolerance"`
+	RelTolerance   float64                 `json:"rel_tolerance"`
+	PositionsCount int                     `json:"positions_count"` // Позиции (не главы) с итогом
+	PositionsTotal Money                   `json:"positions_total"`
+	Chapters       []ProposalChapterCheck  `json:"chapters"`
+	SummaryLines   []ProposalSummaryCheck  `json:"summary_lines"`
+	Discrepancies  []ImportValidationIssue `json:"discrepancies"` // chapter_mismatch, summary_mismatch
+}
+
+// Статусы позиции в проверке полноты предложения.
+const (
+	CoverageMissing         = "missing"          // Позиции baseline нет в предложении
+	CoverageZeroPrice       = "zero_price"       // Позиция есть, но без цены или с нулевой ценой
+	CoverageQuantityChanged = "quantity_changed" // Подрядчик предложил другой объём
+	CoverageExtra           = "extra"            // Позиции предложения нет в baseline
+)
+
+// Как позиция предложения связана с позицией baseline.
+const (
+	CoverageLinkedByCatalog = "catalog_position"
+	CoverageLinkedByKey     = "position_key"
+)
+
+// ProposalCoverageIssue — позиция baseline, оценённая не полностью, или
+// лишняя позиция предложения.
+type ProposalCoverageIssue struct {
+	Status             string  `json:"status"` // Coverage*
+	PositionKey        string  `json:"position_key"`
+	JobTitle           string  `json:"job_title"`
+	CatalogPositionID  *int64  `json:"catalog_position_id,omitempty"`
+	BaselinePositionID *int64  `json:"baseline_position_id,omitempty"`
+	PositionItemID     *int64  `json:"position_item_id,omitempty"` // Позиция предложения
+	LinkedBy           string  `json:"linked_by,omitempty"`        // CoverageLinkedBy*
+	BaselineQuantity   *string `json:"baseline_quantity,omitempty"`
+	Quantity           *string `json:"quantity,omitempty"` // Объём подрядчика (suggested_quantity или quantity)
+}
+
+// ProposalCoverageReport — ответ GET /api/v1/proposals/:id/coverage: полнота
+// предложения относительно перечня работ baseline лота. Позиции связываются
+// по catalog_position_id, а без сопоставления — по ключу позиции.
+// CoveragePercent — доля позиций baseline, оценённых ненулевой ценой.
+type ProposalCoverageReport struct {
+	ProposalID           int64                   `json:"proposal_id"`
+	BaselineProposalID   int64                   `json:"baseline_proposal_id"`
+	BaselineCount        int                     `json:"baseline_count"` // Позиции baseline без глав
+	CoveredCount         int                     `json:"covered_count"`  // Включая quantity_changed
+	MissingCount         int                     `json:"missing_count"`
+	ZeroPriceCount       int                     `json:"zero_price_count"`
+	QuantityChangedCount int                     `json:"quantity_changed_count"`
+	ExtraCount           int                     `json:"extra_count"`
+	CoveragePercent      string                  `json:"coverage_percent"`
+	Issues               []ProposalCoverageIssue `json:"issues"` // В порядке позиций baseline, лишние — в конце
+}
+
+// === Catalog Price History (GET /api/v1/catalog/:id/price-history) ===
+
+// CatalogPriceHistoryItem — цена за единицу из одного предложения подрядчика.
+type CatalogPriceHistoryItem struct {
+	PositionItemID     int64     `json:"position_item_id"`
+	TenderID           int64     `json:"tender_id"`
+	TenderEtpID        string    `json:"tender_etp_id"`
+	TenderTitle        string    `json:"tender_title"`
+	LotID              int64     `json:"lot_id"`
+	Date               time.Time `json:"date"` // Дата подготовки тендера или дата импорта
+	ContractorID       int64     `json:"contractor_id"`
+	ContractorName     string    `json:"contractor_name"`
+	ContractorInn      string    `json:"contractor_inn"`
+	JobTitleInProposal string    `json:"job_title_in_proposal"`
+	Quantity           *string   `json:"quantity,omitempty"`
+	UnitCost           Money     `json:"unit_cost"`
+	Unit               *string   `json:"unit,omitempty"`
+	Region             *string   `json:"region,omitempty"`               // Регион тендера
+	NormalizedUnitCost *Money    `json:"normalized_unit_cost,omitempty"` // С normalize=true; нет индекса — null
+}
+
+// PositionSearchItem — позиция КП, найденная поиском по названию, с контекстом
+// тендера, лота и подрядчика. Цены — в валюте позиции (Currency).
+type PositionSearchItem struct {
+	PositionItemID     int64     `json:"position_item_id"`
+	JobTitleInProposal string    `json:"job_title_in_proposal"`
+	CatalogPositionID  *int64    `json:"catalog_position_id"`
+	Quantity           *string   `json:"quantity,omitempty"`
+	Unit               *string   `json:"unit,omitempty"`
+	UnitCost           *Money    `json:"unit_cost"`
+	TotalCost          *Money    `json:"total_cost"`
+	Currency           string    `json:"currency"`
+	ProposalID         int64     `json:"proposal_id"`
+	IsBaseline         bool      `json:"is_baseline"`
+	LotID              int64     `json:"lot_id"`
+	LotTitle           string    `json:"lot_title"`
+	TenderID           int64     `json:"tender_id"`
+	TenderEtpID        string    `json:"tender_etp_id"`
+	TenderTitle        string    `json:"tender_title"`
+	TenderDate         time.Time `json:"tender_date"` // Дата подготовки тендера или дата импорта
+	ContractorID       int64     `json:"contractor_id"`
+	ContractorName     string    `json:"contractor_name"`
+	ContractorInn      string    `json:"contractor_inn"`
+	Score              float32   `json:"score"` // Похожесть названия на запрос (0..1)
+}
+
+// PositionSearchResponse — DTO ответа для GET /api/v1/positions/search.
+type PositionSearchResponse struct {
+	Items      []PositionSearchItem `json:"items"`
+	TotalCount int64                `json:"total_count"`
+	Page       int32                `json:"page,omitempty"` // Нет при пагинации по cursor
+	PageSize   int32                `json:"page_size"`
+	NextCursor string               `json:"next_cursor,omitempty"` // Только при cursor; пусто — последняя страница
+}
+
+// CatalogPriceQuarter — статистика цены за единицу за квартал в одной единице измерения.
+type CatalogPriceQuarter struct {
+	Quarter      string    `json:"quarter"` // "2025-Q3"
+	QuarterStart time.Time `json:"quarter_start"`
+	Unit         *string   `json:"unit,omitempty"`
+	PricesCount  int64     `json:"prices_count"`
+	MinUnitCost  *Money    `json:"min_unit_cost,omitempty"`
+	AvgUnitCost  *Money    `json:"avg_unit_cost,omitempty"`
+	MaxUnitCost  *Money    `json:"max_unit_cost,omitempty"`
+
+	// С normalize=true: те же агрегаты по пересчитанным ценам (без цен, для
+	// которых не нашлось индекса)
+	NormalizedPricesCount int64  `json:"normalized_prices_count,omitempty"`
+	NormalizedMinUnitCost *Money `json:"normalized_min_unit_cost,omitempty"`
+	NormalizedAvgUnitCost *Money `json:"normalized_avg_unit_cost,omitempty"`
+	NormalizedMaxUnitCost *Money `json:"normalized_max_unit_cost,omitempty"`
+}
+
+// CatalogPriceHistoryResponse — ответ GET /api/v1/catalog/:id/price-history.
+type CatalogPriceHistoryResponse struct {
+	CatalogPositionID int64                     `json:"catalog_position_id"`
+	StandardJobTitle  string                    `json:"standard_job_title"`
+	Items             []CatalogPriceHistoryItem `json:"items"`    // От новых к старым
+	Quarters          []CatalogPriceQuarter     `json:"quarters"` // От старых к новым
+	Normalization     *PriceNormalization       `json:"normalization,omitempty"`
+}
+
+// PriceNormalization — к каким региону и дате пересчитаны цены истории.
+type PriceNormalization struct {
+	Region            string    `json:"region"`
+	Date              time.Time `json:"date"`
+	Index             string    `json:"index"`              // Индекс целевых региона и даты
+	UnnormalizedCount int64     `json:"unnormalized_count"` // Цены без индекса на свою дату
+}
+
+// === Индексы цен (GET/POST/PUT/DELETE /api/v1/price-indices) ===
+
+// PriceIndex — значение индекса стоимости строительства региона за месяц.
+type PriceIndex struct {
+	ID        int64     `json:"id"`
+	Region    string    `json:"region"` // RU — общероссийский ряд
+	Period    string    `json:"period"` // "2025-03"
+	Value     string    `json:"value"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ListPriceIndicesResponse — ответ GET /api/v1/price-indices.
+type ListPriceIndicesResponse struct {
+	Items []PriceIndex `json:"items"` // По региону и месяцу
+}
+
+// PriceIndexRequest — тело POST /api/v1/price-indices и PUT /api/v1/price-indices/:id.
+type PriceIndexRequest struct {
+	Region string `json:"region" binding:"required,max=16"`
+	Period string `json:"period" binding:"required"` // "2025-03"
+	Value  string `json:"value" binding:"required"`  // Положительное число, до 4 знаков после запятой
+}
+
+// === Contractors (GET /api/v1/contractors...) ===
+
+// ContractorResponse — карточка подрядчика.
+type ContractorResponse struct {
+	ID            int64                   `json:"id"`
+	Title         string                  `json:"title"`
+	Inn           string                  `json:"inn"`
+	Address       string                  `json:"address"`
+	Accreditation ContractorAccreditation `json:"accreditation"`
+	CreatedAt     time.Time               `json:"created_at"`
+	UpdatedAt     time.Time               `json:"updated_at"`
+}
+
+// Статусы аккредитации подрядчика (chk_contractors_accreditation_status, миграция 000054).
+const (
+	AccreditationUnknown       = "unknown"
+	AccreditationAccredited    = "accredited"
+	AccreditationNotAccredited = "not_accredited"
+	AccreditationSuspended     = "suspended"
+	AccreditationExpired       = "expired" // Срок истёк; выставляет задание contractor_accreditation
+)
+
+// AccreditationStatuses — все статусы аккредитации.
+var AccreditationStatuses = []string{
+	AccreditationUnknown,
+	AccreditationAccredited,
+	AccreditationNotAccredited,
+	AccreditationSuspended,
+	AccreditationExpired,
+}
+
+// Источники данных аккредитации (contractors.accreditation_source).
+const (
+	AccreditationSourceImport = "import" // Статус из данных импорта
+	AccreditationSourceManual = "manual" // Внесена вручную, импорт её не меняет
+)
+
+// accreditationTexts — строки аккредитации, которые присылает парсер, в нижнем регистре.
+var accreditationTexts = map[string]string{
+	"аккредитован":     AccreditationAccredited,
+	"аккредитована":    AccreditationAccredited,
+	"да":               AccreditationAccredited,
+	"active":           AccreditationAccredited,
+	"accredited":       AccreditationAccredited,
+	"yes":              AccreditationAccredited,
+	"не аккредитован":  AccreditationNotAccredited,
+	"не аккредитована": AccreditationNotAccredited,
+	"нет":              AccreditationNotAccredited,
+	"inactive":         AccreditationNotAccredited,
+	"not accredited":   AccreditationNotAccredited,
+	"no":               AccreditationNotAccredited,
+}
+
+// ParseAccreditationStatus переводит строку аккредитации из данных парсера
+// («Аккредитован», «Да», «N/A») в статус; нераспознанная строка — unknown.
+// Те же строки распознаёт миграция 000054.
+func ParseAccreditationStatus(text string) string {
+	if status, ok := accreditationTexts[strings.ToLower(strings.TrimSpace(text))]; ok {
+		return status
+	}
+	return AccreditationUnknown
+}
+
+// AccreditationDocument — документ, подтверждающий аккредитацию. Даты — "2006-01-02".
+type AccreditationDocument struct {
+	Title      string `json:"title"`
+	Number     string `json:"number,omitempty"`
+	IssuedOn   string `json:"issued_on,omitempty"`
+	ValidUntil string `json:"valid_until,omitempty"`
+	URL        string `json:"url,omitempty"`
+}
+
+// ContractorAccreditation — аккредитация подрядчика.
+type ContractorAccreditation struct {
+	Status     string                  `json:"status"`
+	ValidUntil *string                 `json:"valid_until"` // Последний день действия, "2026-03-31"; null — бессрочно или неизвестно
+	Documents  []AccreditationDocument `json:"documents"`
+	Source     string                  `json:"source"` // import | manual
+}
+
+// UpdateAccreditationRequest — тело PUT /api/v1/admin/contractors/:id/accreditation.
+// Статус, срок и документы заменяются целиком.
+type UpdateAccreditationRequest struct {
+	Status     string                  `json:"status" binding:"required"`
+	ValidUntil *string                 `json:"valid_until"` // "2026-03-31"; null — бессрочно
+	Documents  []AccreditationDocument `json:"documents"`
+}
+
+// ExpiringAccreditation — подрядчик, у которого аккредитация скоро истекает или уже истекла.
+type ExpiringAccreditation struct {
+	ContractorID int64                   `json:"contractor_id"`
+	Title        string                  `json:"title"`
+	Inn          string                  `json:"inn"`
+	Status       string                  `json:"status"` // accredited | expired
+	ValidUntil   string                  `json:"valid_until"`
+	DaysLeft     int                     `json:"days_left"` // 0 — последний день; меньше 0 — истекла столько дней назад
+	Documents    []AccreditationDocument `json:"documents"`
+}
+
+// ExpiringAccreditationsResponse — ответ GET /api/v1/contractors/expiring-accreditation.
+type ExpiringAccreditationsResponse struct {
+	Items      []ExpiringAccreditation `json:"items"` // Ближайший срок первым
+	AsOf       string                  `json:"as_of"` // Дата, от которой считается days_left
+	WithinDays int                     `json:"within_days"`
+	TotalCount int64                   `json:"total_count"`
+	Page       int32                   `json:"page"`
+	PageSize   int32                   `json:"page_size"`
+}
+
+// ContractorListItem — подрядчик в списке с числом его предложений.
+type ContractorListItem struct {
+	ContractorResponse
+	ProposalsCount int64 `json:"proposals_count"`
+}
+
+// ListContractorsResponse — ответ GET /api/v1/contractors.
+type ListContractorsResponse struct {
+	Contractors []ContractorListItem `json:"contractors"`
+	TotalCount  int64                `json:"total_count"`
+	Page        int32                `json:"page"`
+	PageSize    int32                `json:"page_size"`
+}
+
+// ContractorRecentProposal — одно из последних предложений подрядчика.
+type ContractorRecentProposal struct {
+	ProposalID       int64     `json:"proposal_id"`
+	LotID            int64     `json:"lot_id"`
+	LotTitle         string    `json:"lot_title"`
+	TenderID         int64     `json:"tender_id"`
+	TenderEtpID      string    `json:"tender_etp_id"`
+	TenderTitle      string    `json:"tender_title"`
+	TenderDate       time.Time `json:"tender_date"`
+	TotalCost        *Money    `json:"total_cost,omitempty"`
+	BaselineTotal    *Money    `json:"baseline_total,omitempty"`
+	DeviationPercent *string   `json:"deviation_percent,omitempty"` // (total - baseline) / baseline * 100
+	IsWinner         bool      `json:"is_winner"`
+}
+
+// ContractorStatsResponse — ответ GET /api/v1/contractors/:id/stats.
+// Baseline-предложения и удалённые тендеры не учитываются.
+type ContractorStatsResponse struct {
+	ContractorID           int64                      `json:"contractor_id"`
+	Title                  string                     `json:"title"`
+	Inn                    string                     `json:"inn"`
+	TendersCount           int64                      `json:"tenders_count"`
+	ProposalsCount         int64                      `json:"proposals_count"` // Одно предложение = участие в одном лоте
+	WinsCount              int64                      `json:"wins_count"`
+	WinRatePercent         *string                    `json:"win_rate_percent,omitempty"` // wins / proposals * 100
+	AvgDeviationPercent    *string                    `json:"avg_deviation_percent,omitempty"`
+	ComparedProposalsCount int64                      `json:"compared_proposals_count"` // Предложения, вошедшие в среднее отклонение
+	RecentProposals        []ContractorRecentProposal `json:"recent_proposals"`
+}
+
+// === Savings Report (GET /api/v1/reports/savings) ===
+
+// SavingsReportTender — экономия по одному тендеру: baseline против цены
+// победителя по сравнимым лотам (есть и baseline, и цена победителя).
+type SavingsReportTender struct {
+	TenderID          int64     `json:"tender_id"`
+	TenderEtpID       string    `json:"tender_etp_id"`
+	TenderTitle       string    `json:"tender_title"`
+	TenderDate        time.Time `json:"tender_date"`
+	CategoryID        *int64    `json:"category_id,omitempty"`
+	CategoryTitle     *string   `json:"category_title,omitempty"`
+	LotsCount         int64     `json:"lots_count"`
+	ComparedLotsCount int64     `json:"compared_lots_count"`
+	BaselineCost      *Money    `json:"baseline_cost,omitempty"`
+	WinningCost       *Money    `json:"winning_cost,omitempty"`
+	Savings           *Money    `json:"savings,omitempty"`         // baseline - winning
+	SavingsPercent    *string   `json:"savings_percent,omitempty"` // (baseline - winning) / baseline * 100
+}
+
+// SavingsTotals — итог экономии по группе тендеров.
+type SavingsTotals struct {
+	TendersCount      int64   `json:"tenders_count"`
+	ComparedLotsCount int64   `json:"compared_lots_count"`
+	BaselineCost      *Money  `json:"baseline_cost,omitempty"`
+	WinningCost       *Money  `json:"winning_cost,omitempty"`
+	Savings           *Money  `json:"savings,omitempty"`
+	SavingsPercent    *string `json:"savings_percent,omitempty"`
+}
+
+// SavingsCategoryTotals — итог по категории тендеров (CategoryID nil — без категории).
+type SavingsCategoryTotals struct {
+	CategoryID    *int64  `json:"category_id,omitempty"`
+	CategoryTitle *string `json:"category_title,omitempty"`
+	SavingsTotals
+}
+
+// SavingsMonthTotals — итог за месяц (по дате тендера, UTC).
+type SavingsMonthTotals struct {
+	Month string `json:"month"` // ГГГГ-ММ
+	SavingsTotals
+}
+
+// SavingsReportResponse — ответ GET /api/v1/reports/savings.
+// Суммы пересчитаны в базовую валюту Currency.
+type SavingsReportResponse struct {
+	Currency   string                  `json:"currency"`
+	DateFrom   *string                 `json:"date_from,omitempty"`
+	DateTo     *string                 `json:"date_to,omitempty"`
+	CategoryID *int64                  `json:"category_id,omitempty"`
+	Tenders    []SavingsReportTender   `json:"tenders"`
+	Categories []SavingsCategoryTotals `json:"categories"`
+	Months     []SavingsMonthTotals    `json:"months"`
+	Total      SavingsTotals           `json:"total"`
+}
+
+// === Scheduled Reports (/api/v1/admin/reports/schedules) ===
+
+// CreateScheduledReportRequest — DTO запроса на создание регулярного отчета.
+type CreateScheduledReportRequest struct {
+	Name           string   `json:"name"`
+	Kind           string   `json:"kind"`                      // savings | new_tenders | unmatched_positions
+	Format         string   `json:"format"`                    // xlsx | pdf
+	Frequency      string   `json:"frequency"`                 // daily | weekly | monthly
+	Recipients     []string `json:"recipients"`                // Адреса email, хотя бы один
+	OrganizationID *int64   `json:"organization_id,omitempty"` // nil — тендеры всех организаций
+	CategoryID     *int64   `json:"category_id,omitempty"`     // Только для savings и new_tenders
+	IsActive       *bool    `json:"is_active,omitempty"`       // По умолчанию true
+}
+
+// UpdateScheduledReportRequest — DTO частичного обновления регулярного отчета.
+// Не переданные поля не меняются; вид отчета и организация не меняются.
+type UpdateScheduledReportRequest struct {
+	Name          *string   `json:"name,omitempty"`
+	Format        *string   `json:"format,omitempty"`
+	Frequency     *string   `json:"frequency,omitempty"` // Смена периодичности переносит следующий запуск
+	Recipients    *[]string `json:"recipients,omitempty"`
+	CategoryID    *int64    `json:"category_id,omitempty"`
+	ClearCategory bool      `json:"clear_category,omitempty"` // Снять фильтр по категории
+	IsActive      *bool     `json:"is_active,omitempty"`
+}
+
+// ScheduledReportResponse — регулярный отчет.
+type ScheduledReportResponse struct {
+	ID             int64      `json:"id"`
+	Name           string     `json:"name"`
+	Kind           string     `json:"kind"`
+	Format         string     `json:"format"`
+	Frequency      string     `json:"frequency"`
+	Recipients     []string   `json:"recipients"`
+	OrganizationID *int64     `json:"organization_id,omitempty"`
+	CategoryID     *int64     `json:"category_id,omitempty"`
+	IsActive       bool       `json:"is_active"`
+	NextRunAt      time.Time  `json:"next_run_at"`
+	LastRunAt      *time.Time `json:"last_run_at,omitempty"`
+	CreatedBy      *int64     `json:"created_by,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// ReportRunResponse — запись истории формирования отчета.
+type ReportRunResponse struct {
+	ID                int64      `json:"id"`
+	ScheduledReportID int64      `json:"scheduled_report_id"`
+	Status            string     `json:"status"`      // running | sent | failed
+	PeriodFrom        time.Time  `json:"period_from"` // Период отчета [period_from, period_to)
+	PeriodTo          time.Time  `json:"period_to"`
+	Recipients        []string   `json:"recipients"`
+	FileName          *string    `json:"file_name,omitempty"`
+	FileSize          *int64     `json:"file_size,omitempty"` // Байт
+	RowsCount         *int32     `json:"rows_count,omitempty"`
+	Error             *string    `json:"error,omitempty"`
+	StartedAt         time.Time  `json:"started_at"`
+	FinishedAt        *time.Time `json:"finished_at,omitempty"`
+}
+
+// ReportRunsResponse — ответ GET /api/v1/admin/reports/schedules/:id/runs.
+type ReportRunsResponse struct {
+	Runs   []ReportRunResponse `json:"runs"`
+	Limit  int32               `json:"limit"`
+	Offset int32               `json:"offset"`
+}
+
+// === Contractor Deduplication (/api/v1/admin/contractors/...) ===
+
+// ContractorDuplicateCandidate — подрядчик в группе возможных дубликатов.
+type ContractorDuplicateCandidate struct {
+	ID              int64     `json:"id"`
+	Title           string    `json:"title"`
+	Inn             string    `json:"inn"`
+	Address         string    `json:"address"`
+	CreatedAt       time.Time `json:"created_at"`
+	ProposalsCount  int64     `json:"proposals_count"`
+	TitleSimilarity float64   `json:"title_similarity"` // 0..1, сходство нормализованного наименования с основным
+}
+
+// ContractorDuplicateGroup — подрядчики с одинаковым ИНН после нормализации.
+type ContractorDuplicateGroup struct {
+	NormalizedInn      string                         `json:"normalized_inn"`
+	SuggestedMasterID  int64                          `json:"suggested_master_id"` // Подрядчик с наибольшим числом предложений
+	MinTitleSimilarity float64                        `json:"min_title_similarity"`
+	Contractors        []ContractorDuplicateCandidate `json:"contractors"`
+}
+
+// ContractorDuplicatesResponse — ответ GET /api/v1/admin/contractors/duplicates.
+type ContractorDuplicatesResponse struct {
+	Groups []ContractorDuplicateGroup `json:"groups"`
+}
+
+// MergeContractorsRequest — слияние дубликата в основного подрядчика.
+type MergeContractorsRequest struct {
+	MasterID    int64 `json:"master_id" binding:"required"`
+	DuplicateID int64 `json:"duplicate_id" binding:"required"`
+}
+
+// MergeContractorsResponse — ответ POST /api/v1/admin/contractors/merge.
+type MergeContractorsResponse struct {
+	MasterID       int64 `json:"master_id"`
+	DuplicateID    int64 `json:"duplicate_id"` // Удалён
+	MovedProposals int64 `json:"moved_proposals"`
+	MovedPersons   int64 `json:"moved_persons"`
+}
+
+// === Units of Measurement (/api/v1/admin/units) ===
+
+// UnitAlias — синоним единицы измерения (нормализованное написание).
+type UnitAlias struct {
+	ID    int64  `json:"id"`
+	Alias string `json:"alias"`
+}
+
+// UnitOfMeasurementAdmin — единица справочника с синонимами и числом ссылок на неё.
+type UnitOfMeasurementAdmin struct {
+	ID                    int64       `json:"id"`
+	NormalizedName        string      `json:"normalized_name"`
+	FullName              *string     `json:"full_name,omitempty"`
+	Description           *string     `json:"description,omitempty"`
+	Aliases               []UnitAlias `json:"aliases"`
+	PositionItemsCount    int64       `json:"position_items_count"`
+	CatalogPositionsCount int64       `json:"catalog_positions_count"`
+}
+
+// ListUnitsResponse — ответ GET /api/v1/admin/units.
+type ListUnitsResponse struct {
+	Units []UnitOfMeasurementAdmin `json:"units"`
+}
+
+// CreateUnitRequest — каноническая единица и её синонимы.
+// Имя и синонимы нормализуются так же, как при импорте (trim, нижний регистр).
+type CreateUnitRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	FullName    *string  `json:"full_name"`
+	Description *string  `json:"description"`
+	Aliases     []string `json:"aliases"`
+}
+
+// CreateUnitAliasRequest — новый синоним существующей единицы.
+type CreateUnitAliasRequest struct {
+	Alias string `json:"alias" binding:"required"`
+}
+
+// MergeUnitsRequest — слияние единицы-дубликата в основную.
+type MergeUnitsRequest struct {
+	MasterID    int64 `json:"master_id" binding:"required"`
+	DuplicateID int64 `json:"duplicate_id" binding:"required"`
+}
+
+// MergeUnitsResponse — ответ POST /api/v1/admin/units/merge.
+type MergeUnitsResponse struct {
+	MasterID              int64  `json:"master_id"`
+	DuplicateID           int64  `json:"duplicate_id"` // Удалена
+	AddedAlias            string `json:"added_alias"`  // Имя дубликата, ставшее синонимом основной
+	MovedPositionItems    int64  `json:"moved_position_items"`
+	MovedCatalogPositions int64  `json:"moved_catalog_positions"`
+	MovedAliases          int64  `json:"moved_aliases"`
+}
+
+// === Загрузка справочников (POST /api/v1/admin/reference/import) ===
+
+// Виды строк файла загрузки справочников (ReferenceImportRow.Kind).
+const (
+	ReferenceKindType      = "type"       // Тип тендера; parent не указывается
+	ReferenceKindChapter   = "chapter"    // Раздел; parent — тип
+	ReferenceKindCategory  = "category"   // Категория; parent — раздел
+	ReferenceKindUnitAlias = "unit_alias" // Синоним единицы; parent — единица (имя или синоним)
+)
+
+// Действия со строкой (ReferenceImportRow.Action).
+const (
+	ReferenceActionCreate    = "create"    // Запись будет создана
+	ReferenceActionUpdate    = "update"    // Запись перенесена к другому родителю
+	ReferenceActionUnchanged = "unchanged" // Запись уже есть с тем же родителем
+)
+
+// ReferenceImportRow — строка файла и её результат. Row — номер строки в
+// файле (как в Excel), Sheet — лист книги (у CSV пусто). Строка с ошибкой
+// получает Code и Message вместо Action.
+type ReferenceImportRow struct {
+	Sheet   string `json:"sheet,omitempty"`
+	Row     int    `json:"row"`
+	Kind    string `json:"kind"`
+	Title   string `json:"title"`
+	Parent  string `json:"parent,omitempty"`
+	Action  string `json:"action,omitempty"`
+	Code    string `json:"code,omitempty"` // invalid_kind, required, parent_not_allowed, duplicate, too_long, forbidden, parent_not_found, unit_name
+	Message string `json:"message,omitempty"`
+}
+
+// ReferenceImportSummary — число строк по действиям.
+type ReferenceImportSummary struct {
+	Total     int `json:"total"`
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Errors    int `json:"errors"`
+}
+
+// ReferenceImportReport — отчёт загрузки справочников. Файл применяется
+// целиком или не применяется вовсе: Applied=false при dry_run и при любой
+// ошибке (Valid=false).
+type ReferenceImportReport struct {
+	DryRun  bool                   `json:"dry_run"`
+	Valid   bool                   `json:"valid"`
+	Applied bool                   `json:"applied"`
+	Summary ReferenceImportSummary `json:"summary"`
+	Rows    []ReferenceImportRow   `json:"rows"`
+}
+
+// === Health (GET /healthz, GET /readyz) ===
+
+// HealthResponse — ответ GET /healthz: процесс жив, зависимости не проверяются.
+type HealthResponse struct {
+	Status string         `json:"status"` // "ok"
+	Build  buildinfo.Info `json:"build"`
+}
+
+// ReadinessCheck — результат проверки одной зависимости.
+type ReadinessCheck struct {
+	Name       string `json:"name"`   // database, migrations, parser_service
+	Status     string `json:"status"` // "ok" или "fail"
+	Message    string `json:"message,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// ReadinessResponse — ответ GET /readyz. Status "ok" только если все проверки прошли.
+type ReadinessResponse struct {
+	Status string           `json:"status"` // "ok" или "fail"
+	Build  buildinfo.Info   `json:"build"`
+	Checks []ReadinessCheck `json:"checks"`
+}
+
+// TenderArchiveStatusResponse — ответ DELETE /api/v1/tenders/:id и POST /api/v1/tenders/:id/restore.
+// После восстановления DeletedAt и DeletedBy равны null.
+type TenderArchiveStatusResponse struct {
+	ID        int64      `json:"id"`
+	DeletedAt *time.Time `json:"deleted_at"`
+	DeletedBy *string    `json:"deleted_by"`
+}
+
+// TenderPurgeTable — число строк одной таблицы, удалённых (или удаляемых при dry_run) purge.
+type TenderPurgeTable struct {
+	Table string `json:"table"`
+	Rows  int64  `json:"rows"`
+}
+
+// TenderPurgeResponse — ответ POST /api/v1/admin/tenders/:id/purge.
+// Tables перечислены в порядке удаления; последняя запись — сам тендер.
+type TenderPurgeResponse struct {
+	TenderID  int64              `json:"tender_id"`
+	DryRun    bool               `json:"dry_run"`
+	TotalRows int64              `json:"total_rows"`
+	Tables    []TenderPurgeTable `json:"tables"`
+}
+
+// === Admin Users (/api/v1/admin/users) ===
+
+// CreateUserRequest — создание пользователя администратором.
+type CreateUserRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	Role     string `json:"role" binding:"required"` // admin | editor | analyst | viewer
+	// OrganizationID — организация пользователя; без значения — организация по умолчанию
+	OrganizationID *int64 `json:"organization_id"`
+}
+
+// UpdateUserRequest — частичное обновление пользователя (PATCH /api/v1/admin/users/:id).
+// Отсутствующие поля не меняются; нужно передать хотя бы одно.
+type UpdateUserRequest struct {
+	Email          *string `json:"email"`
+	IsActive       *bool   `json:"is_active"`
+	OrganizationID *int64  `json:"organization_id"`
+}
+
+// PasswordResetTokenResponse — одноразовый токен сброса пароля. Токен
+// показывается только в этом ответе; в БД хранится его хеш.
+type PasswordResetTokenResponse struct {
+	UserID    int64     `json:"user_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ResetPasswordRequest — установка нового пароля по токену (POST /api/v1/auth/reset-password).
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// ChangePasswordRequest — смена собственного пароля (POST /api/v1/auth/change-password).
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// UpdateUserRoleRequest — смена роли пользователя.
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" binding:"required"` // admin | editor | analyst | viewer
+}
+
+// UpdateUserStatusRequest — активация/деактивация пользователя.
+type UpdateUserStatusRequest struct {
+	IsActive *bool `json:"is_active" binding:"required"`
+}
+
+// CreateOrganizationRequest — создание организации (POST /api/v1/admin/organizations).
+type CreateOrganizationRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// OrganizationResponse — организация в админских эндпоинтах.
+type OrganizationResponse struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	IsDefault bool      `json:"is_default"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AdminUserResponse — пользователь в админских эндпоинтах.
+// RevokedSessions — сколько активных сессий было завершено изменением.
+type AdminUserResponse struct {
+	ID              int64      `json:"id"`
+	Email           string     `json:"email"`
+	Role            string     `json:"role"`
+	IsActive        bool       `json:"is_active"`
+	OrganizationID  int64      `json:"organization_id"`
+	LastLoginAt     *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	TokensRevokedAt *time.Time `json:"tokens_revoked_at,omitempty"`
+	RevokedSessions int64      `json:"revoked_sessions"`
+}
+
+// ImpersonationResponse — сессия имперсонации (POST /api/v1/admin/users/:id/impersonate).
+// Access-токен пользователя устанавливается в cookie до expires_at; cookie
+// refresh администратора не меняется, и после завершения сессии его access-токен
+// восстанавливается через POST /api/v1/auth/refresh.
+type ImpersonationResponse struct {
+	SessionID     int64             `json:"session_id"`
+	ActingAdminID int64             `json:"acting_admin_id"`
+	User          AdminUserResponse `json:"user"`
+	ExpiresAt     time.Time         `json:"expires_at"`
+}
+
+// === Notifications (/api/v1/notifications) ===
+
+// Notification — уведомление в ленте пользователя.
+type Notification struct {
+	ID        int64           `json:"id"`
+	Kind      string          `json:"kind"` // tender_new_proposals | tender_reimported | tender_winner_changed | lot_ai_analysis_completed | merge_review_pending
+	Title     string          `json:"title"`
+	Body      string          `json:"body"`
+	TenderID  *int64          `json:"tender_id,omitempty"`
+	LotID     *int64          `json:"lot_id,omitempty"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+	ReadAt    *time.Time      `json:"read_at,omitempty"`
+}
+
+// NotificationsResponse — ответ GET /api/v1/notifications.
+type NotificationsResponse struct {
+	Items       []Notification `json:"items"`
+	Total       int64          `json:"total"` // С учётом фильтра unread_only
+	UnreadCount int64          `json:"unread_count"`
+	Limit       int32          `json:"limit"`
+	Offset      int32          `json:"offset"`
+}
+
+// NotificationUnreadCountResponse — ответ GET /api/v1/notifications/unread-count.
+type NotificationUnreadCountResponse struct {
+	UnreadCount int64 `json:"unread_count"`
+}
+
+// === Подписки на тендеры (/api/v1/tenders/:id/follow, /api/v1/me/followed-tenders) ===
+
+// FollowedTender — тендер, за которым следит пользователь.
+type FollowedTender struct {
+	ID                 int64      `json:"id"`
+	EtpID              string     `json:"etp_id"`
+	Title              string     `json:"title"`
+	DataPreparedOnDate *time.Time `json:"data_prepared_on_date,omitempty"`
+	ProposalsCount     int64      `json:"proposals_count"` // Без baseline
+	FollowedAt         time.Time  `json:"followed_at"`
+}
+
+// FollowedTendersResponse — ответ GET /api/v1/me/followed-tenders.
+type FollowedTendersResponse struct {
+	Items  []FollowedTender `json:"items"`
+	Total  int64            `json:"total"`
+	Limit  int32            `json:"limit"`
+	Offset int32            `json:"offset"`
+}
+
+// TenderFollowResponse — ответ POST/DELETE /api/v1/tenders/:id/follow.
+type TenderFollowResponse struct {
+	TenderID  int64 `json:"tender_id"`
+	Following bool  `json:"following"`
+}
+
+// === Метки тендеров (/api/v1/tags, /api/v1/tenders/:id/tags) ===
+
+// CreateTagRequest — тело POST /api/v1/tags. Пробелы по краям отбрасываются,
+// повторные внутри сжимаются до одного.
+type CreateTagRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// Tag — метка тендера. TendersCount заполняется только в GET /api/v1/tags.
+type Tag struct {
+	ID             int64     `json:"id"`
+	OrganizationID int64     `json:"organization_id"`
+	Name           string    `json:"name"`
+	TendersCount   *int64    `json:"tenders_count,omitempty"` // Неудалённые тендеры с меткой
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// TagsResponse — ответ GET /api/v1/tags: метки организации по алфавиту.
+type TagsResponse struct {
+	Items []Tag `json:"items"`
+}
+
+// TenderTagsResponse — метки тендера: ответ GET /api/v1/tenders/:id/tags и
+// PUT/DELETE /api/v1/tenders/:id/tags/:tagId.
+type TenderTagsResponse struct {
+	TenderID int64 `json:"tender_id"`
+	Tags     []Tag `json:"tags"`
+}
+
+// === Сохранённые фильтры (/api/v1/me/saved-filters) ===
+
+// CreateSavedFilterRequest — тело POST /api/v1/me/saved-filters. Filters —
+// параметры GET /api/v1/tenders («category_id», «has_winner», «tag_ids», ...)
+// со строковыми, числовыми или логическими значениями; page и cursor не сохраняются.
+type CreateSavedFilterRequest struct {
+	Name    string                 `json:"name" binding:"required"`
+	Filters map[string]interface{} `json:"filters"`
+}
+
+// SavedFilter — сохранённый фильтр списка тендеров. Query — те же параметры
+// в виде query string для GET /api/v1/tenders.
+type SavedFilter struct {
+	ID        int64             `json:"id"`
+	Name      string            `json:"name"`
+	Filters   map[string]string `json:"filters"`
+	Query     string            `json:"query"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// SavedFiltersResponse — ответ GET /api/v1/me/saved-filters в порядке создания.
+type SavedFiltersResponse struct {
+	Items []SavedFilter `json:"items"`
+}
+
+// === История правок (/api/v1/tenders/:id/history, /api/v1/lots/:id/history) ===
+
+// FieldChange — правка одного поля. Field — колонка («title», «region», ...) или
+// ключевой параметр лота («lot_key_parameters.<ключ>»); null в OldValue/NewValue —
+// значения не было. ChangedBy пуст, если автор удалён.
+type FieldChange struct {
+	ID             int64           `json:"id"`
+	Field          string          `json:"field"`
+	OldValue       json.RawMessage `json:"old_value"`
+	NewValue       json.RawMessage `json:"new_value"`
+	ChangedBy      *int64          `json:"changed_by"`
+	ChangedByEmail *string         `json:"changed_by_email"`
+	ChangedAt      time.Time       `json:"changed_at"`
+}
+
+// FieldHistoryResponse — история правок тендера или лота, новые первыми.
+type FieldHistoryResponse struct {
+	EntityType string        `json:"entity_type"`
+	EntityID   int64         `json:"entity_id"`
+	Items      []FieldChange `json:"items"`
+	Total      int64         `json:"total"`
+	Limit      int32         `json:"limit"`
+	Offset     int32         `json:"offset"`
+}
+
+// === Задачи парсера (/api/v1/tasks) ===
+
+// ParseTask — задача парсера из истории загрузок (parse_tasks).
+type ParseTask struct {
+	TaskID       string          `json:"task_id"`
+	Status       string          `json:"status"`           // Последний известный статус; queued — еще не запрашивался
+	StatusSource string          `json:"status_source"`    // polled | pushed
+	Source       string          `json:"source"`           // upload — загрузка файла, fetch — получение с ЭТП, sync — обновление по расписанию
+	EtpID        string          `json:"etp_id,omitempty"` // ID тендера на ЭТП (source = fetch, sync)
+	FileName     string          `json:"file_name"`
+	FileSize     int64           `json:"file_size"`
+	EnableAI     bool            `json:"enable_ai"`
+	TenderID     *int64          `json:"tender_id,omitempty"` // Тендер, созданный импортом результата
+	Error        string          `json:"error,omitempty"`
+	LastStatus   json.RawMessage `json:"last_status,omitempty"` // Последний ответ парсера на опрос как есть
+	// Stored — ответ на запрос статуса построен по записи API, а не получен
+	// от парсера (парсер задачу не знает или недоступен)
+	Stored     bool       `json:"stored,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// ParseTasksResponse — ответ GET /api/v1/tasks.
+type ParseTasksResponse struct {
+	Items  []ParseTask `json:"items"`
+	Total  int64       `json:"total"`
+	Limit  int32       `json:"limit"`
+	Offset int32       `json:"offset"`
+}
+
+// TenderFetchRequest — тело POST /api/v1/tenders/fetch: получить тендер с ЭТП
+// через парсер и импортировать его.
+type TenderFetchRequest struct {
+	EtpID    string `json:"etp_id" binding:"required,max=255"`    // ID тендера на ЭТП (tender_id импорта)
+	URL      string `json:"url" binding:"omitempty,url,max=2048"` // Ссылка на тендер, если парсеру недостаточно ID
+	EnableAI bool   `json:"enable_ai"`
+	// Получить заново, даже если тендер уже импортирован (новая версия импорта)
+	Force bool `json:"force"`
+}
+
+// TenderFetchResponse — ответ POST /api/v1/tenders/fetch (202).
+type TenderFetchResponse struct {
+	ParseTask
+	// Тот же тендер уже получается: возвращена выполняющаяся задача, новая не ставилась
+	Deduplicated bool `json:"deduplicated"`
+}
+
+// TenderFetchConflict — conflicts ответа 409 на POST /api/v1/tenders/fetch:
+// тендер уже импортирован; получить его заново можно с force.
+type TenderFetchConflict struct {
+	TenderID int64 `json:"tender_id"`
+	Deleted  bool  `json:"deleted"` // Тендер помечен удалённым
+}
+
+// TenderSync — обновление тендера с ЭТП по расписанию и что оно изменило
+// между версиями исходного JSON (GET /api/v1/tenders/:id/syncs).
+type TenderSync struct {
+	ID             int64                  `json:"id"`
+	TaskID         string                 `json:"task_id"`
+	Status         string                 `json:"status"` // pending | changed | unchanged | failed
+	FromVersion    *int32                 `json:"from_version,omitempty"`
+	ToVersion      *int32                 `json:"to_version,omitempty"` // Версия, созданная обновлением
+	ProposalsAdded int32                  `json:"proposals_added"`
+	PricesChanged  int32                  `json:"prices_changed"` // Позиций с изменившейся стоимостью
+	Stats          *TenderImportDiffStats `json:"stats,omitempty"`
+	Error          string                 `json:"error,omitempty"`
+	RequestedAt    time.Time              `json:"requested_at"`
+	FinishedAt     *time.Time             `json:"finished_at,omitempty"`
+}
+
+// TenderSyncsResponse - ответ GET /api/v1/tenders/:id/syncs, последние первыми.
+type TenderSyncsResponse struct {
+	TenderID int64        `json:"tender_id"`
+	Items    []TenderSync `json:"items"`
+	Total    int64        `json:"total"`
+	Limit    int32        `json:"limit"`
+	Offset   int32        `json:"offset"`
+}
+
+// ParseTaskStatusPush — тело POST /internal/worker/tasks/:task_id/status:
+// парсер сообщает статус задачи. completed и failed — итоговые.
+type ParseTaskStatusPush struct {
+	Status   string `json:"status" binding:"required,max=64"`
+	TenderID *int64 `json:"tender_id" binding:"omitempty,gt=0"`
+	Error    string `json:"error" binding:"max=4000"`
+}
+
+// SearchHit — найденная сущность глобального поиска.
+type SearchHit struct {
+	Type     string     `json:"type"` // tender | contractor | object | catalog_position
+	ID       int64      `json:"id"`
+	Title    string     `json:"title"`
+	Subtitle string     `json:"subtitle,omitempty"` // etp_id и объект тендера, ИНН, адрес, описание
+	Date     *time.Time `json:"date,omitempty"`     // Дата тендера
+	Link     string     `json:"link"`               // Путь API карточки сущности
+}
+
+// SearchGroup — результаты глобального поиска одного типа: первые limit
+// совпадений, total — все совпадения.
+type SearchGroup struct {
+	Type  string      `json:"type"`
+	Total int64       `json:"total"`
+	Items []SearchHit `json:"items"`
+}
+
+// SearchResponse — ответ GET /api/v1/search: группы в порядке tender,
+// contractor, object, catalog_position (только запрошенные в types).
+type SearchResponse struct {
+	Query  string        `json:"query"`
+	Limit  int32         `json:"limit"`
+	Groups []SearchGroup `json:"groups"`
+}
+
+// LotAttachment — документ лота, сформированный API (без содержимого).
+type LotAttachment struct {
+	ID          int64     `json:"id"`
+	LotID       int64     `json:"lot_id"`
+	Kind        string    `json:"kind"` // winner_protocol
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	FileSize    int64     `json:"file_size"`
+	CreatedBy   *int64    `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	DownloadURL string    `json:"download_url"`
+}
+
+// LotAttachmentsResponse — ответ GET /api/v1/lots/:id/attachments, новые первыми.
+type LotAttachmentsResponse struct {
+	LotID int64           `json:"lot_id"`
+	Items []LotAttachment `json:"items"`
+}
+
+// === Удельные цены по ключевому параметру (GET /api/v1/analytics/lots/compare) ===
+
+// LotParameterBenchmarkItem — лот с ключевым параметром: его значение и цена
+// победителя за единицу параметра. Лот без удельной цены — со SkipReason.
+type LotParameterBenchmarkItem struct {
+	LotID          int64            `json:"lot_id"`
+	LotKey         string           `json:"lot_key"`
+	LotTitle       string           `json:"lot_title"`
+	TenderID       int64            `json:"tender_id"`
+	TenderEtpID    string           `json:"tender_etp_id"`
+	TenderTitle    string           `json:"tender_title"`
+	TenderDate     time.Time        `json:"tender_date"`
+	ContractorName string           `json:"contractor_name,omitempty"` // Победитель
+	RawValue       string           `json:"raw_value"`                 // Значение параметра как в lot_key_parameters
+	Value          *decimal.Decimal `json:"value,omitempty"`           // Разобранное число
+	Unit           string           `json:"unit,omitempty"`            // Единица из строкового значения ("1200 м²" → "м²")
+	WinningPrice   *Money           `json:"winning_price,omitempty"`   // Цена контракта, без неё — итог предложения победителя
+	UnitPrice      *Money           `json:"unit_price,omitempty"`      // WinningPrice / Value
+	SkipReason     string           `json:"skip_reason,omitempty"`     // no_winner | no_price | not_numeric | non_positive
+}
+
+// LotParameterBenchmark — удельная цена по лотам с разобранным значением и ценой.
+type LotParameterBenchmark struct {
+	Min    Money `json:"min"`
+	Max    Money `json:"max"`
+	Avg    Money `json:"avg"`
+	Median Money `json:"median"`
+}
+
+// LotParameterBenchmarkResponse — ответ GET /api/v1/analytics/lots/compare.
+// Суммы — в базовой валюте Currency; лоты — от новых тендеров к старым.
+type LotParameterBenchmarkResponse struct {
+	CategoryID        int64                       `json:"category_id"`
+	CategoryTitle     string                      `json:"category_title"`
+	Parameter         string                      `json:"parameter"`
+	SchemaTypes       []string                    `json:"schema_types,omitempty"` // Тип параметра по схеме категории
+	Unit              string                      `json:"unit,omitempty"`         // Самая частая единица в значениях
+	Currency          string                      `json:"currency"`
+	LotsCount         int                         `json:"lots_count"`          // Лоты с параметром
+	ComparedLotsCount int                         `json:"compared_lots_count"` // Из них с удельной ценой
+	Truncated         bool                        `json:"truncated"`           // Учтены только последние MaxBenchmarkLots лотов
+	Benchmark         *LotParameterBenchmark      `json:"benchmark,omitempty"`
+	Items             []LotParameterBenchmarkItem `json:"items"`
+}
+
+// RetentionRun — запуск политики хранения данных (retention_runs).
+type RetentionRun struct {
+	ID           int64     `json:"id"`
+	Policy       string    `json:"policy"`
+	DryRun       bool      `json:"dry_run"` // Только подсчёт, ничего не удалено
+	Status       string    `json:"status"`  // success | failed
+	Cutoff       time.Time `json:"cutoff"`  // Удаляются записи старше cutoff
+	MatchedCount int64     `json:"matched_count"`
+	DeletedCount int64     `json:"deleted_count"`
+	Error        string    `json:"error,omitempty"`
+	TriggeredBy  *int64    `json:"triggered_by,omitempty"` // Без него — планировщик
+	StartedAt    time.Time `json:"started_at"`
+	FinishedAt   time.Time `json:"finished_at"`
+}
+
+// RetentionPolicy — политика хранения с настройками и последним запуском.
+type RetentionPolicy struct {
+	Policy    string        `json:"policy"` // expired_sessions | revoked_sessions | matching_cache | raw_import_history
+	Enabled   bool          `json:"enabled"`
+	DryRun    bool          `json:"dry_run"`   // retention.dry_run: запуски только считают строки
+	Retention string        `json:"retention"` // Срок хранения: "168h0m0s", "6 мес.", "expires_at"
+	LastRun   *RetentionRun `json:"last_run,omitempty"`
+}
+
+// RetentionPoliciesResponse — ответ GET /api/v1/admin/retention.
+type RetentionPoliciesResponse struct {
+	Enabled   bool              `json:"enabled"` // Задание планировщика retention зарегистрировано
+	Interval  string            `json:"interval"`
+	BatchSize int32             `json:"batch_size"`
+	Policies  []RetentionPolicy `json:"policies"`
+}
+
+// RetentionRunsResponse — ответ GET /api/v1/admin/retention/runs, новые первыми.
+type RetentionRunsResponse struct {
+	Items  []RetentionRun `json:"items"`
+	Total  int64          `json:"total"`
+	Limit  int32          `json:"limit"`
+	Offset int32          `json:"offset"`
+}
+
+// TenderDuplicateTender — тендер пары вероятных дубликатов.
+type TenderDuplicateTender struct {
+	ID        int64     `json:"id"`
+	EtpID     string    `json:"etp_id"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TenderDuplicate — пара тендеров, похожих на один тендер под разными etp_id.
+// Tender — более поздний из двух.
+type TenderDuplicate struct {
+	ID                  int64                 `json:"id"`
+	Tender              TenderDuplicateTender `json:"tender"`
+	DuplicateOf         TenderDuplicateTender `json:"duplicate_of"`
+	Score               float64               `json:"score"`                // Взвешенная оценка сходства, 0..1
+	TitleSimilarity     float64               `json:"title_similarity"`     // Сходство наименований (триграммы)
+	SameObject          bool                  `json:"same_object"`          // Один объект строительства
+	PositionsSimilarity float64               `json:"positions_similarity"` // Доля общих наименований позиций
+	Status              string                `json:"status"`               // pending | linked | ignored
+	ResolvedBy          *int64                `json:"resolved_by,omitempty"`
+	ResolvedAt          *time.Time            `json:"resolved_at,omitempty"`
+	CreatedAt           time.Time             `json:"created_at"`
+}
+
+// TenderDuplicatesResponse — ответ GET /api/v1/admin/tender-duplicates:
+// ожидающие проверки пары первыми, самые похожие вверху.
+type TenderDuplicatesResponse struct {
+	Items  []TenderDuplicate `json:"items"`
+	Total  int64             `json:"total"`
+	Limit  int32             `json:"limit"`
+	Offset int32             `json:"offset"`
+}
+
+// TenderDuplicateStatusResponse — решение по паре вероятных дубликатов
+// (POST /api/v1/admin/tender-duplicates/:id/link, /ignore).
+type TenderDuplicateStatusResponse struct {
+	ID            int64     `json:"id"`
+	TenderID      int64     `json:"tender_id"`
+	DuplicateOfID int64     `json:"duplicate_of_id"`
+	Status        string    `json:"status"`
+	ResolvedBy    *int64    `json:"resolved_by,omitempty"`
+	ResolvedAt    time.Time `json:"resolved_at"`
+}
+
+// LinkedTender — тендер, связанный с данным как тот же тендер под другим etp_id.
+type LinkedTender struct {
+	DuplicateID int64     `json:"duplicate_id"` // Пара в tender_duplicates
+	ID          int64     `json:"id"`
+	EtpID       string    `json:"etp_id"`
+	Title       string    `json:"title"`
+	LinkedAt    time.Time `json:"linked_at"`
+}
+
+// LinkedTendersResponse — ответ GET /api/v1/tenders/:id/linked.
+type LinkedTendersResponse struct {
+	TenderID int64          `json:"tender_id"`
+	Items    []LinkedTender `json:"items"`
+}
+
+// DashboardTenderStatuses — тендеры по состоянию (без удалённых).
+type DashboardTenderStatuses struct {
+	Total       int64 `json:"total"`
+	Awarded     int64 `json:"awarded"`      // Есть победитель
+	InProgress  int64 `json:"in_progress"`  // Есть предложения подрядчиков, победителя нет
+	NoProposals int64 `json:"no_proposals"` // Предложений подрядчиков нет
+}
+
+// DashboardImportsDay — импорты тендеров за день.
+type DashboardImportsDay struct {
+	Date       string `json:"date"` // YYYY-MM-DD
+	Imports    int64  `json:"imports"`
+	NewTenders int64  `json:"new_tenders"` // Первая загрузка тендера
+}
+
+// DashboardImports — импорты за последние Days дней, по дням без пропусков.
+type DashboardImports struct {
+	Days       int                   `json:"days"`
+	Total      int64                 `json:"total"`
+	NewTenders int64                 `json:"new_tenders"`
+	ByDay      []DashboardImportsDay `json:"by_day"`
+}
+
+// DashboardCategory — категория тендеров по объёму предложений-победителей.
+type DashboardCategory struct {
+	CategoryID   int64  `json:"category_id"`
+	Title        string `json:"title"`
+	TendersCount int64  `json:"tenders_count"`
+	WinnersTotal Money  `json:"winners_total"` // Сумма цен контрактов победителей (winners.award_price)
+}
+
+// DashboardActivity — событие ленты активности (импорт тендера).
+type DashboardActivity struct {
+	Type       string    `json:"type"` // tender_imported | tender_reimported
+	TenderID   int64     `json:"tender_id"`
+	EtpID      string    `json:"etp_id"`
+	Title      string    `json:"title"`
+	Version    int32     `json:"version"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// DashboardResponse — ответ GET /api/v1/dashboard. Сводка кэшируется на
+// dashboard.cache_ttl: GeneratedAt — время расчёта.
+type DashboardResponse struct {
+	Tenders            DashboardTenderStatuses `json:"tenders"`
+	Imports            DashboardImports        `json:"imports"`
+	UnmatchedPositions int64                   `json:"unmatched_positions"` // Очередь сопоставления с каталогом
+	PendingMerges      int64                   `json:"pending_merges"`      // Предложения слияния позиций каталога (общий каталог)
+	TopCategories      []DashboardCategory     `json:"top_categories"`
+	RecentActivity     []DashboardActivity     `json:"recent_activity"`
+	GeneratedAt        time.Time               `json:"generated_at"`
+}
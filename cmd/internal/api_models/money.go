@@ -0,0 +1,112 @@
+package api_models
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultCurrency - валюта предложения, если в payload она не указана
+// (все данные, импортированные до появления поля currency).
+const DefaultCurrency = "RUB"
+
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// NormalizeCurrency приводит код валюты к виду ISO 4217: " usd" → "USD".
+// Пустая строка остаётся пустой — валюта не указана.
+func NormalizeCurrency(code string) (string, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(code))
+	if normalized == "" {
+		return "", nil
+	}
+	if !currencyCodePattern.MatchString(normalized) {
+		return "", fmt.Errorf("некорректный код валюты %q: ожидается код ISO 4217, например RUB", code)
+	}
+	return normalized, nil
+}
+
+// MoneyScale - число знаков после запятой у денежных значений в ответах API (копейки).
+const MoneyScale = 2
+
+// Money - денежная сумма без потери точности.
+//
+// Правила:
+//   - значение хранится как decimal.Decimal и пишется в NUMERIC как есть,
+//     без промежуточного float64 (см. util.NullableMoney);
+//   - в JSON принимается числом (1234.5) или строкой ("1234.5"); число
+//     разбирается из текста, а не через float64;
+//   - в JSON выводится строкой, округлённой до MoneyScale знаков по правилу
+//     «половина — от нуля» ("1234.50"), независимо от масштаба NUMERIC в БД.
+//
+// Округление выполняется только при выводе: арифметику (суммы, разницы)
+// нужно вести над неокруглёнными значениями.
+type Money struct {
+	decimal.Decimal
+}
+
+// NewMoney оборачивает decimal.Decimal.
+func NewMoney(d decimal.Decimal) Money {
+	return Money{Decimal: d}
+}
+
+// ParseMoney разбирает десятичную строку ("1234.56", "-0.5").
+func ParseMoney(s string) (Money, error) {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return Money{}, fmt.Errorf("некорректная денежная сумма %q: %w", s, err)
+	}
+	return Money{Decimal: d}, nil
+}
+
+// MoneyFromNullString преобразует NUMERIC из БД (sql.NullString) в *Money.
+// NULL и нечисловое значение дают nil.
+func MoneyFromNullString(ns sql.NullString) *Money {
+	if !ns.Valid {
+		return nil
+	}
+	m, err := ParseMoney(ns.String)
+	if err != nil {
+		return nil
+	}
+	return &m
+}
+
+// MoneyPtr возвращает указатель на Money для необязательных полей ответа.
+func MoneyPtr(d decimal.Decimal) *Money {
+	m := NewMoney(d)
+	return &m
+}
+
+// Rounded возвращает сумму, округлённую до MoneyScale знаков («половина — от нуля»).
+func (m Money) Rounded() decimal.Decimal {
+	return m.Decimal.Round(MoneyScale)
+}
+
+// String возвращает сумму с ровно MoneyScale знаками после запятой: "1234.50".
+func (m Money) String() string {
+	return m.Decimal.StringFixed(MoneyScale)
+}
+
+// MarshalJSON выводит сумму строкой с MoneyScale знаками, чтобы не терять копейки
+// на стороне клиента.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + m.String() + `"`), nil
+}
+
+// UnmarshalJSON принимает число или строку без потери точности.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	return m.Decimal.UnmarshalJSON(data)
+}
+
+// TypeScriptType - тип поля во фронтенде (см. tsgen).
+func (Money) TypeScriptType() string {
+	return "string"
+}
+
+// OpenAPIType - тип и формат поля в спецификации API (см. openapi).
+func (Money) OpenAPIType() (string, string) {
+	return "string", "decimal"
+}
@@ -0,0 +1,140 @@
+package api_models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR MONEY (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Lost kopecks — sums must not pass through float64 on the way in or out
+2. Inconsistent output — every money field is a string with exactly two decimals
+3. Client breakage — parser payloads with numbers and with strings are both accepted
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Input
+- GIVEN a JSON number or a JSON string
+  WHEN it is unmarshalled into Money
+  THEN the exact decimal value is kept (no rounding on input)
+
+SCENARIO 2: Output
+- GIVEN any Money value
+  WHEN it is marshalled
+  THEN it is a string with two decimals, rounded half away from zero
+
+SCENARIO 3: Database values
+- GIVEN a NULL or non-numeric NUMERIC string
+  WHEN MoneyFromNullString is called
+  THEN nil is returned
+
+SCENARIO 4: Currency codes
+- GIVEN a currency code in any case with surrounding spaces
+  WHEN NormalizeCurrency is called
+  THEN the upper-case ISO 4217 code is returned; an empty code stays empty
+- GIVEN a proposal whose position has a malformed currency
+  WHEN Validate is called
+  THEN an error naming the position is returned
+*/
+
+func TestMoney_UnmarshalJSON(t *testing.T) {
+	var payload struct {
+		FromNumber Money  `json:"from_number"`
+		FromString Money  `json:"from_string"`
+		Missing    *Money `json:"missing"`
+	}
+
+	err := json.Unmarshal([]byte(`{"from_number": 0.1, "from_string": "900.123456789", "missing": null}`), &payload)
+
+	require.NoError(t, err)
+	assert.Equal(t, "0.1", payload.FromNumber.Decimal.String())
+	assert.Equal(t, "900.123456789", payload.FromString.Decimal.String(), "точность входа не теряется")
+	assert.Nil(t, payload.Missing)
+}
+
+func TestMoney_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected string
+	}{
+		{"1234.5", `"1234.50"`},
+		{"1234.565", `"1234.57"`},
+		{"-1234.565", `"-1234.57"`},
+		{"0.004", `"0.00"`},
+		{"100", `"100.00"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			m, err := ParseMoney(tt.in)
+			require.NoError(t, err)
+
+			out, err := json.Marshal(m)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(out))
+		})
+	}
+
+	out, err := json.Marshal(struct {
+		Total *Money `json:"total"`
+	}{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"total": null}`, string(out))
+}
+
+func TestMoneyFromNullString(t *testing.T) {
+	assert.Nil(t, MoneyFromNullString(sql.NullString{}))
+	assert.Nil(t, MoneyFromNullString(sql.NullString{String: "n/a", Valid: true}))
+
+	m := MoneyFromNullString(sql.NullString{String: "1500.4000", Valid: true})
+	require.NotNil(t, m)
+	assert.Equal(t, "1500.40", m.String())
+}
+
+func TestParseMoney_Invalid(t *testing.T) {
+	_, err := ParseMoney("12,50")
+	assert.Error(t, err)
+}
+
+func TestNormalizeCurrency(t *testing.T) {
+	code, err := NormalizeCurrency(" usd ")
+	require.NoError(t, err)
+	assert.Equal(t, "USD", code)
+
+	code, err = NormalizeCurrency("")
+	require.NoError(t, err)
+	assert.Empty(t, code)
+
+	for _, invalid := range []string{"US", "RUBL", "$", "12A"} {
+		_, err := NormalizeCurrency(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestContractorProposalDetails_Currency(t *testing.T) {
+	proposal := ContractorProposalDetails{
+		Title: "Initiator",
+		ContractorItems: ContractorItemsContainer{
+			Positions: map[string]PositionItem{"1": {JobTitle: "Кладка", Currency: "евро"}},
+		},
+	}
+
+	err := proposal.Validate(true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `contractor_items.positions["1"].currency`)
+
+	proposal.ContractorItems.Positions["1"] = PositionItem{JobTitle: "Кладка"}
+	require.NoError(t, proposal.Validate(true))
+	assert.Equal(t, DefaultCurrency, proposal.CurrencyCode(), "без валюты — рубли")
+
+	proposal.Currency = "eur"
+	assert.Equal(t, "EUR", proposal.CurrencyCode())
+}
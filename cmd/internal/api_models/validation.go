@@ -0,0 +1,84 @@
+package api_models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Правила нарушений (FieldError.Rule).
+const (
+	RuleRequired = "required"  // Поле пустое
+	RulePositive = "positive"  // Число должно быть больше нуля
+	RuleMinItems = "min_items" // Коллекция пустая
+	RuleCurrency = "currency"  // Некорректный код валюты ISO 4217
+)
+
+// FieldError — одно нарушение в данных импорта. Path адресует поле так же, как
+// ImportValidationIssue.Path: lots["lot_1"].proposals["ООО Альфа"].inn.
+type FieldError struct {
+	Path    string `json:"path"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors — все нарушения, найденные Validate. Проверка не
+// останавливается на первом: парсер исправляет все ошибки за одну итерацию.
+// Порядок стабильный — ключи лотов, предложений и позиций по возрастанию.
+type ValidationErrors []FieldError
+
+// Error перечисляет нарушения через "; " в виде "путь: сообщение".
+func (e ValidationErrors) Error() string {
+	parts := make([]string, len(e))
+	for i, fe := range e {
+		parts[i] = fe.Path + ": " + fe.Message
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Prefixed возвращает нарушения с путями внутри объекта prefix: так ошибки
+// Lot.Validate получают путь лота в тендере (lots["lot_1"].lot_title).
+func (e ValidationErrors) Prefixed(prefix string) ValidationErrors {
+	result := make(ValidationErrors, len(e))
+	for i, fe := range e {
+		fe.Path = joinPath(prefix, fe.Path)
+		result[i] = fe
+	}
+	return result
+}
+
+func (e *ValidationErrors) add(path, rule, message string) {
+	*e = append(*e, FieldError{Path: path, Rule: rule, Message: message})
+}
+
+// err возвращает nil без нарушений: пустой срез в интерфейсе error не равен nil.
+func (e ValidationErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+func joinPath(prefix, field string) string {
+	if prefix == "" {
+		return field
+	}
+	if field == "" {
+		return prefix
+	}
+	return prefix + "." + field
+}
+
+// keyPath — путь элемента map: lots["lot_1"].
+func keyPath(prefix, field, key string) string {
+	return joinPath(prefix, fmt.Sprintf("%s[%q]", field, key))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
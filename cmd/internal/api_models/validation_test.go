@@ -0,0 +1,115 @@
+package api_models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR IMPORT PAYLOAD VALIDATION (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Parser developers fixing a payload one error per request
+2. Errors that do not say which lot or proposal is broken
+3. Error lists in a different order on every run (map iteration)
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: All violations at once
+- GIVEN a tender with an empty title, an empty executor phone and two broken proposals
+  WHEN Validate is called
+  THEN ValidationErrors lists every violation with its rule, sorted by lot and proposal key
+
+SCENARIO 2: Paths
+- GIVEN a broken proposal inside a lot
+  THEN its path is lots["<lot>"].proposals["<proposal>"].<field>
+- GIVEN Lot.Validate called on its own (stream import)
+  THEN paths are relative to the lot and Prefixed adds the lot path
+
+SCENARIO 3: Valid payload
+- GIVEN a valid tender THEN Validate returns a nil error (not an empty slice)
+*/
+
+func validTender() FullTenderData {
+	proposal := ContractorProposalDetails{
+		Title: "ООО Альфа", Inn: "7701000000", Address: "Москва",
+		ContractorCoordinate: "A1", ContractorWidth: 3, ContractorHeight: 10,
+		ContractorItems: ContractorItemsContainer{Positions: map[string]PositionItem{"1": {JobTitle: "Кладка"}}},
+	}
+	return FullTenderData{
+		TenderID: "ETP-1", TenderTitle: "Тендер", TenderObject: "Объект", TenderAddress: "Москва",
+		ExecutorData: Executor{ExecutorName: "Иванов", ExecutorPhone: "+7 900"},
+		LotsData: map[string]Lot{"lot_1": {
+			LotTitle:         "Лот 1",
+			BaseLineProposal: ContractorProposalDetails{Title: "Initiator"},
+			ProposalData:     map[string]ContractorProposalDetails{"ООО Альфа": proposal},
+		}},
+	}
+}
+
+func TestFullTenderData_Validate_Valid(t *testing.T) {
+	tender := validTender()
+	assert.NoError(t, tender.Validate())
+}
+
+func TestFullTenderData_Validate_CollectsAll(t *testing.T) {
+	tender := validTender()
+	tender.TenderTitle = " "
+	tender.ExecutorData.ExecutorPhone = ""
+	lot := tender.LotsData["lot_1"]
+	lot.ProposalData["ООО Бета"] = ContractorProposalDetails{Title: "ООО Бета", Inn: "", Address: "Москва",
+		ContractorCoordinate: "B1", ContractorWidth: 3, ContractorHeight: 10,
+		ContractorItems: ContractorItemsContainer{Positions: map[string]PositionItem{"1": {JobTitle: "Кладка", Currency: "евро"}}},
+	}
+	alpha := lot.ProposalData["ООО Альфа"]
+	alpha.ContractorWidth = 0
+	lot.ProposalData["ООО Альфа"] = alpha
+
+	err := tender.Validate()
+
+	var fieldErrs ValidationErrors
+	require.True(t, errors.As(err, &fieldErrs))
+	assert.Equal(t, ValidationErrors{
+		{Path: "tender_title", Rule: RuleRequired, Message: "название тендера не может быть пустым"},
+		{Path: "executor.executor_phone", Rule: RuleRequired, Message: "телефон исполнителя не может быть пустым"},
+		{Path: `lots["lot_1"].proposals["ООО Альфа"].contractor_width`, Rule: RulePositive, Message: "ширина подрядчика должна быть положительной"},
+		{Path: `lots["lot_1"].proposals["ООО Бета"].inn`, Rule: RuleRequired, Message: "ИНН подрядчика не может быть пустым"},
+		{Path: `lots["lot_1"].proposals["ООО Бета"].contractor_items.positions["1"].currency`, Rule: RuleCurrency,
+			Message: `некорректный код валюты "евро": ожидается код ISO 4217, например RUB`},
+	}, fieldErrs)
+	assert.Contains(t, err.Error(), "tender_title: название тендера не может быть пустым; executor.executor_phone")
+}
+
+func TestFullTenderData_Validate_NoLots(t *testing.T) {
+	tender := validTender()
+	tender.LotsData = nil
+	tender.ExecutorData = Executor{}
+
+	err := tender.Validate()
+
+	var fieldErrs ValidationErrors
+	require.True(t, errors.As(err, &fieldErrs))
+	assert.Equal(t, []string{"executor", "lots"}, []string{fieldErrs[0].Path, fieldErrs[1].Path})
+	assert.Len(t, fieldErrs, 2, "пустой исполнитель — одно нарушение, а не по каждому полю")
+}
+
+func TestLot_Validate_RelativePaths(t *testing.T) {
+	lot := validTender().LotsData["lot_1"]
+	lot.LotTitle = ""
+	lot.BaseLineProposal.Title = ""
+
+	err := lot.Validate()
+
+	var fieldErrs ValidationErrors
+	require.True(t, errors.As(err, &fieldErrs))
+	assert.Equal(t, []string{"lot_title", "baseline_proposal.title"}, []string{fieldErrs[0].Path, fieldErrs[1].Path})
+
+	prefixed := fieldErrs.Prefixed(`lots["lot_1"]`)
+	assert.Equal(t, `lots["lot_1"].lot_title`, prefixed[0].Path)
+	assert.Equal(t, "lot_title", fieldErrs[0].Path, "Prefixed не меняет исходный срез")
+}
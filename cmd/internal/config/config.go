@@ -2,10 +2,18 @@ package config
 
 import (
 	"fmt"
+	"net/mail"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/shopspring/decimal"
 	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
 )
 
@@ -20,8 +28,14 @@ var validCookieSameSiteValues = map[string]bool{
 	"none":   true,
 }
 
+// maxAccessTTL ограничивает срок жизни access-токена: отзыв через denylist
+// срабатывает не мгновенно, и долгоживущий токен сводит его на нет.
+const maxAccessTTL = 24 * time.Hour
+
 type ParserServiceConfig struct {
 	URL string `yaml:"url" env-required:"true"`
+	// Путь, который /readyz запрашивает для проверки доступности парсера
+	HealthPath string `yaml:"health_path" env-default:"/health"`
 }
 
 type ServicesConfig struct {
@@ -29,7 +43,12 @@ type ServicesConfig struct {
 }
 
 type AuthConfig struct {
-	JWTSecret         string `yaml:"jwt_secret" env:"JWT_SECRET" env-required:"true"`
+	// Секрет подписи access-токенов. Обязателен, но может прийти из secrets.provider
+	// (file, vault), поэтому проверяется в ValidateSecrets, а не env-required
+	JWTSecret string `yaml:"jwt_secret" env:"JWT_SECRET"`
+	// Предыдущий секрет на время ротации: токены, подписанные им, ещё принимаются,
+	// новые подписываются только JWTSecret. После access_ttl его можно убрать
+	JWTSecretPrevious string `yaml:"jwt_secret_previous" env:"JWT_SECRET_PREVIOUS"`
 	AccessTTL         string `yaml:"access_ttl" env-default:"15m"`
 	RefreshTTL        string `yaml:"refresh_ttl" env-default:"720h"` // 30 days
 	CookieAccessName  string `yaml:"cookie_access_name" env-default:"access_token"`
@@ -39,26 +58,39 @@ type AuthConfig struct {
 	CookieHttpOnly    bool   `yaml:"cookie_http_only" env-default:"true"`
 	CookieSameSite    string `yaml:"cookie_same_site" env-default:"lax"` // strict, lax, none
 
+	// Как часто перечитывать из БД отзывы access-токенов (смена роли, деактивация)
+	RevocationSyncInterval time.Duration `yaml:"revocation_sync_interval" env:"AUTH_REVOCATION_SYNC_INTERVAL" env-default:"15s"`
+
+	// Сколько экземпляр API кэширует версию access-токенов пользователя (users.token_version):
+	// на столько может опоздать отзыв, выполненный другим экземпляром
+	TokenVersionCacheTTL time.Duration `yaml:"token_version_cache_ttl" env:"AUTH_TOKEN_VERSION_CACHE_TTL" env-default:"5s"`
+
+	// Срок действия одноразового токена сброса пароля, выданного администратором
+	PasswordResetTTL time.Duration `yaml:"password_reset_ttl" env:"AUTH_PASSWORD_RESET_TTL" env-default:"24h"`
+
+	// Срок сессии имперсонации: администратор действует от имени пользователя
+	// (POST /api/v1/admin/users/:id/impersonate), продлить её нельзя
+	ImpersonationTTL time.Duration `yaml:"impersonation_ttl" env:"AUTH_IMPERSONATION_TTL" env-default:"30m"`
+
+	// Вход через корпоративный SSO (OIDC: Keycloak, AD FS)
+	OIDC OIDCConfig `yaml:"oidc"`
+
 	// Парсированные значения (заполняются после Validate)
-	AccessTokenTTL  time.Duration
-	RefreshTokenTTL time.Duration
+	AccessTokenTTL  time.Duration `yaml:"-"`
+	RefreshTokenTTL time.Duration `yaml:"-"`
 }
 
-// Validate проверяет корректность настроек auth конфигурации
+// Validate проверяет корректность настроек auth конфигурации.
+// Секреты проверяет ValidateSecrets: они могут быть получены позже, из secrets.provider.
 func (c *AuthConfig) Validate(isDebug bool) error {
-	// Проверка JWT secret
-	if c.JWTSecret == "" {
-		return fmt.Errorf("jwt_secret is required")
-	}
-	if len(c.JWTSecret) < 32 {
-		return fmt.Errorf("jwt_secret must be at least 32 characters (current: %d)", len(c.JWTSecret))
-	}
-
 	// Проверка и парсинг Access TTL
 	accessTTL, err := time.ParseDuration(c.AccessTTL)
 	if err != nil {
 		return fmt.Errorf("invalid access_ttl: %w", err)
 	}
+	if accessTTL <= 0 || accessTTL > maxAccessTTL {
+		return fmt.Errorf("access_ttl must be between 1s and %s (got: %s)", maxAccessTTL, accessTTL)
+	}
 	c.AccessTokenTTL = accessTTL
 
 	// Проверка и парсинг Refresh TTL
@@ -73,6 +105,26 @@ func (c *AuthConfig) Validate(isDebug bool) error {
 		return fmt.Errorf("refresh_ttl must be greater than access_ttl")
 	}
 
+	if c.RevocationSyncInterval <= 0 {
+		return fmt.Errorf("revocation_sync_interval must be positive")
+	}
+
+	if c.TokenVersionCacheTTL <= 0 {
+		return fmt.Errorf("token_version_cache_ttl must be positive")
+	}
+
+	if c.PasswordResetTTL <= 0 {
+		return fmt.Errorf("password_reset_ttl must be positive")
+	}
+
+	if c.ImpersonationTTL < time.Minute || c.ImpersonationTTL > maxAccessTTL {
+		return fmt.Errorf("impersonation_ttl must be between 1m and %s (got: %s)", maxAccessTTL, c.ImpersonationTTL)
+	}
+
+	if err := c.OIDC.Validate(); err != nil {
+		return fmt.Errorf("oidc: %w", err)
+	}
+
 	// Проверка CookieSameSite
 	if !validCookieSameSiteValues[c.CookieSameSite] {
 		return fmt.Errorf("cookie_same_site must be one of: strict, lax, none (got: %s)", c.CookieSameSite)
@@ -87,10 +139,1139 @@ func (c *AuthConfig) Validate(isDebug bool) error {
 	return nil
 }
 
+// ValidateSecrets проверяет секреты подписи токенов.
+func (c *AuthConfig) ValidateSecrets() error {
+	if c.JWTSecret == "" {
+		return fmt.Errorf("jwt_secret is required")
+	}
+	if len(c.JWTSecret) < 32 {
+		return fmt.Errorf("jwt_secret must be at least 32 characters (current: %d)", len(c.JWTSecret))
+	}
+	if c.JWTSecretPrevious != "" {
+		if len(c.JWTSecretPrevious) < 32 {
+			return fmt.Errorf("jwt_secret_previous must be at least 32 characters (current: %d)", len(c.JWTSecretPrevious))
+		}
+		if c.JWTSecretPrevious == c.JWTSecret {
+			return fmt.Errorf("jwt_secret_previous must differ from jwt_secret")
+		}
+	}
+	if c.OIDC.Enabled && c.OIDC.ClientSecret == "" {
+		return fmt.Errorf("oidc.client_secret is required when oidc is enabled")
+	}
+	return nil
+}
+
+// OIDCConfig - настройки входа через OpenID Connect (authorization code + PKCE).
+type OIDCConfig struct {
+	Enabled bool `yaml:"enabled" env:"OIDC_ENABLED" env-default:"false"`
+	// Issuer провайдера: discovery читается из <issuer>/.well-known/openid-configuration,
+	// claim iss в id_token должен совпадать с ним буквально
+	Issuer   string `yaml:"issuer" env:"OIDC_ISSUER"`
+	ClientID string `yaml:"client_id" env:"OIDC_CLIENT_ID"`
+	// Секрет клиента; как и jwt_secret, может прийти из secrets.provider
+	ClientSecret string `yaml:"client_secret" env:"OIDC_CLIENT_SECRET"`
+	// Абсолютный URL GET /api/v1/auth/oidc/callback, зарегистрированный у провайдера
+	RedirectURL string   `yaml:"redirect_url" env:"OIDC_REDIRECT_URL"`
+	Scopes      []string `yaml:"scopes" env:"OIDC_SCOPES" env-separator:"," env-default:"openid,email,profile"`
+	// Claim id_token со списком групп пользователя (Keycloak: groups, AD FS: group)
+	GroupsClaim string `yaml:"groups_claim" env:"OIDC_GROUPS_CLAIM" env-default:"groups"`
+	// Группа IdP → роль приложения. При нескольких совпадениях выбирается
+	// роль с наибольшими правами. Роль синхронизируется при каждом входе
+	RoleMapping map[string]string `yaml:"role_mapping" env:"OIDC_ROLE_MAPPING"`
+	// Если ни одна группа не сопоставлена: роль нового пользователя, существующий
+	// сохраняет свою. Пусто — вход без сопоставленной группы запрещён
+	DefaultRole string `yaml:"default_role" env:"OIDC_DEFAULT_ROLE"`
+	// Создавать пользователя при первом входе; без этого войти могут только
+	// пользователи, заранее заведённые администратором с тем же email
+	AutoProvision bool `yaml:"auto_provision" env:"OIDC_AUTO_PROVISION" env-default:"true"`
+	// Организация новых пользователей; 0 — организация по умолчанию
+	OrganizationID int64 `yaml:"organization_id" env:"OIDC_ORGANIZATION_ID" env-default:"0"`
+	// Доверять email без claim email_verified (AD FS его не выпускает)
+	AllowUnverifiedEmail bool `yaml:"allow_unverified_email" env:"OIDC_ALLOW_UNVERIFIED_EMAIL" env-default:"false"`
+	// Куда перенаправить браузер после входа (путь или URL фронтенда)
+	PostLoginURL string `yaml:"post_login_url" env:"OIDC_POST_LOGIN_URL" env-default:"/"`
+	// Таймаут запросов к провайдеру (discovery, token, JWKS)
+	Timeout time.Duration `yaml:"timeout" env:"OIDC_TIMEOUT" env-default:"10s"`
+}
+
+// Validate проверяет настройки OIDC. Выключенный OIDC не проверяется; имена
+// ролей в role_mapping проверяет auth.NewOIDCClient, секрет — ValidateSecrets.
+func (c *OIDCConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if u, err := url.Parse(c.Issuer); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("issuer must be an absolute http(s) URL")
+	}
+	if c.ClientID == "" {
+		return fmt.Errorf("client_id is required")
+	}
+	if u, err := url.Parse(c.RedirectURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("redirect_url must be an absolute http(s) URL")
+	}
+	if !slices.Contains(c.Scopes, "openid") {
+		return fmt.Errorf("scopes must include openid")
+	}
+	if c.GroupsClaim == "" {
+		return fmt.Errorf("groups_claim is required")
+	}
+	if c.OrganizationID < 0 {
+		return fmt.Errorf("organization_id must not be negative")
+	}
+	if c.PostLoginURL == "" {
+		return fmt.Errorf("post_login_url is required")
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+	return nil
+}
+
+// ServiceAuthConfig - настройки аутентификации внутренних сервисов (/internal/worker).
+type ServiceAuthConfig struct {
+	// Как часто перечитывать ключи из service_credentials
+	CredentialsRefreshInterval time.Duration `yaml:"credentials_refresh_interval" env:"SERVICE_CREDENTIALS_REFRESH_INTERVAL" env-default:"30s"`
+}
+
+// HealthConfig - настройки проверок /readyz.
+type HealthConfig struct {
+	// Общий таймаут всех проверок готовности (БД, миграции, парсер)
+	ReadinessTimeout time.Duration `yaml:"readiness_timeout" env:"HEALTH_READINESS_TIMEOUT" env-default:"2s"`
+}
+
+// ImportConfig - настройки импорта тендеров.
+type ImportConfig struct {
+	// Удалять при повторном импорте позиции и итоговые строки предложения,
+	// которых нет в новом payload. Без сверки импорт только добавляет/обновляет строки.
+	ReconcileStaleRows bool `yaml:"reconcile_stale_rows" env:"IMPORT_RECONCILE_STALE_ROWS" env-default:"false"`
+	// Максимальный размер тела POST /import-tender в байтах; больше — 413
+	MaxPayloadSize int64 `yaml:"max_payload_size" env:"IMPORT_MAX_PAYLOAD_SIZE" env-default:"268435456"`
+	// Тела больше порога (или без Content-Length) импортируются потоково:
+	// JSON разбирается по лотам, не загружаясь в память целиком
+	StreamThreshold int64 `yaml:"stream_threshold" env:"IMPORT_STREAM_THRESHOLD" env-default:"33554432"`
+	// Сохранять позиции предложения пачкой: COPY во временную таблицу и один
+	// INSERT ... ON CONFLICT вместо UpsertPositionItem на каждую строку
+	BulkPositions bool `yaml:"bulk_positions" env:"IMPORT_BULK_POSITIONS" env-default:"false"`
+	// Предложения с меньшим числом позиций сохраняются построчно: для них COPY не окупается
+	BulkMinPositions int `yaml:"bulk_min_positions" env:"IMPORT_BULK_MIN_POSITIONS" env-default:"200"`
+	// После импорта сравнивать тендер с другими тендерами организации и
+	// ставить вероятные дубликаты в очередь GET /api/v1/admin/tender-duplicates
+	DetectDuplicates bool `yaml:"detect_duplicates" env:"IMPORT_DETECT_DUPLICATES" env-default:"true"`
+	// Сколько лотов тендера сохранять параллельно. 1 — весь тендер в одной
+	// транзакции; больше — каждый лот в своей транзакции (см. importer
+	// parallel_lots.go). Потоковый импорт всегда сохраняет лоты по одному.
+	// Больше 1 требует database.max_open_conns не меньше lot_workers + 1
+	LotWorkers int `yaml:"lot_workers" env:"IMPORT_LOT_WORKERS" env-default:"1"`
+	// Загружать единицы измерения, позиции каталога и записи matching_cache
+	// всех позиций лота тремя запросами до сохранения позиций, а не
+	// запросами на каждую позицию каждого предложения
+	PrefetchLookups bool `yaml:"prefetch_lookups" env:"IMPORT_PREFETCH_LOOKUPS" env-default:"true"`
+}
+
+// maxImportLotWorkers — верхняя граница import.lot_workers: каждый воркер
+// держит соединение из пула database.max_open_conns.
+const maxImportLotWorkers = 32
+
+// Validate проверяет лимиты размера импорта, порог bulk-сохранения позиций и
+// число воркеров лотов.
+func (c *ImportConfig) Validate() error {
+	if c.MaxPayloadSize <= 0 {
+		return fmt.Errorf("max_payload_size must be positive")
+	}
+	if c.StreamThreshold <= 0 {
+		return fmt.Errorf("stream_threshold must be positive")
+	}
+	if c.StreamThreshold > c.MaxPayloadSize {
+		return fmt.Errorf("stream_threshold (%d) must not exceed max_payload_size (%d)", c.StreamThreshold, c.MaxPayloadSize)
+	}
+	if c.BulkPositions && c.BulkMinPositions <= 0 {
+		return fmt.Errorf("bulk_min_positions must be positive")
+	}
+	if c.LotWorkers < 1 || c.LotWorkers > maxImportLotWorkers {
+		return fmt.Errorf("lot_workers must be between 1 and %d", maxImportLotWorkers)
+	}
+	return nil
+}
+
+// UploadConfig - загрузка файлов тендеров через POST /api/v1/upload-tender.
+// Файл передаётся парсеру потоком, не собираясь в памяти целиком.
+type UploadConfig struct {
+	// Максимальный размер файла в байтах; больше — 413
+	MaxFileSize int64 `yaml:"max_file_size" env:"UPLOAD_MAX_FILE_SIZE" env-default:"52428800"`
+	// Допустимые Content-Type файла в форме (без параметров). Кроме типа
+	// проверяются расширение .xlsx и сигнатура ZIP в начале файла
+	AllowedContentTypes []string `yaml:"allowed_content_types" env:"UPLOAD_ALLOWED_CONTENT_TYPES" env-separator:"," env-default:"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"`
+	// Время на передачу файла парсеру и получение его ответа
+	ParserTimeout time.Duration `yaml:"parser_timeout" env:"UPLOAD_PARSER_TIMEOUT" env-default:"10m"`
+	// Таймаут запроса статуса задачи у парсера
+	StatusTimeout time.Duration `yaml:"status_timeout" env:"UPLOAD_STATUS_TIMEOUT" env-default:"30s"`
+}
+
+// Validate проверяет лимит размера, список типов и таймауты загрузки.
+func (c *UploadConfig) Validate() error {
+	if c.MaxFileSize <= 0 {
+		return fmt.Errorf("max_file_size must be positive")
+	}
+	if len(c.AllowedContentTypes) == 0 {
+		return fmt.Errorf("allowed_content_types must not be empty")
+	}
+	for _, contentType := range c.AllowedContentTypes {
+		if strings.TrimSpace(contentType) == "" {
+			return fmt.Errorf("allowed_content_types must not contain empty values")
+		}
+	}
+	if c.ParserTimeout <= 0 || c.StatusTimeout <= 0 {
+		return fmt.Errorf("parser_timeout and status_timeout must be positive")
+	}
+	return nil
+}
+
+// OutboundHTTPConfig - исходящие HTTP-запросы к внутренним сервисам (парсер):
+// повторы идемпотентных запросов и circuit breaker на каждый адрес назначения.
+// Загрузка файла (POST) не повторяется, но учитывается breaker'ом.
+type OutboundHTTPConfig struct {
+	// Попыток на идемпотентный запрос (GET, HEAD, PUT, DELETE) при сетевых
+	// ошибках и ответах 502/503/504; 1 — без повторов
+	MaxAttempts int `yaml:"max_attempts" env:"OUTBOUND_HTTP_MAX_ATTEMPTS" env-default:"3"`
+	// Пауза перед повтором удваивается с каждой попыткой, но не больше retry_max_delay
+	RetryBaseDelay time.Duration `yaml:"retry_base_delay" env:"OUTBOUND_HTTP_RETRY_BASE_DELAY" env-default:"200ms"`
+	RetryMaxDelay  time.Duration `yaml:"retry_max_delay" env:"OUTBOUND_HTTP_RETRY_MAX_DELAY" env-default:"2s"`
+	// Подряд неуспешных запросов (сеть, ответы 5xx), после которых адрес
+	// считается недоступным и запросы к нему сразу отклоняются
+	BreakerFailureThreshold int `yaml:"breaker_failure_threshold" env:"OUTBOUND_HTTP_BREAKER_FAILURE_THRESHOLD" env-default:"5"`
+	// Через сколько после размыкания пропускается пробный запрос
+	BreakerOpenTimeout time.Duration `yaml:"breaker_open_timeout" env:"OUTBOUND_HTTP_BREAKER_OPEN_TIMEOUT" env-default:"30s"`
+}
+
+// Validate проверяет число попыток, паузы и параметры breaker.
+func (c *OutboundHTTPConfig) Validate() error {
+	if c.MaxAttempts < 1 {
+		return fmt.Errorf("max_attempts must be at least 1, got %d", c.MaxAttempts)
+	}
+	if c.RetryBaseDelay <= 0 || c.RetryMaxDelay < c.RetryBaseDelay {
+		return fmt.Errorf("retry_base_delay must be positive and not greater than retry_max_delay")
+	}
+	if c.BreakerFailureThreshold < 1 {
+		return fmt.Errorf("breaker_failure_threshold must be at least 1, got %d", c.BreakerFailureThreshold)
+	}
+	if c.BreakerOpenTimeout <= 0 {
+		return fmt.Errorf("breaker_open_timeout must be positive")
+	}
+	return nil
+}
+
+// ConsistencyConfig - допуск сверки итогов предложения с суммой позиций
+// (GET /api/v1/proposals/:id/consistency). Расхождение считается ошибкой, если
+// превышает и абсолютный допуск, и относительный (доля от пересчитанной суммы).
+type ConsistencyConfig struct {
+	AbsTolerance float64 `yaml:"abs_tolerance" env:"CONSISTENCY_ABS_TOLERANCE" env-default:"1"`
+	RelTolerance float64 `yaml:"rel_tolerance" env:"CONSISTENCY_REL_TOLERANCE" env-default:"0.001"`
+}
+
+// DashboardConfig - сводка главной страницы (GET /api/v1/dashboard). Сводка
+// организации считается несколькими агрегатными запросами и кэшируется в памяти
+// процесса на CacheTTL; 0 отключает кэш.
+type DashboardConfig struct {
+	CacheTTL time.Duration `yaml:"cache_ttl" env:"DASHBOARD_CACHE_TTL" env-default:"30s"`
+	// Сколько категорий и событий активности показывать
+	TopCategories  int `yaml:"top_categories" env:"DASHBOARD_TOP_CATEGORIES" env-default:"5"`
+	RecentActivity int `yaml:"recent_activity" env:"DASHBOARD_RECENT_ACTIVITY" env-default:"10"`
+}
+
+// Validate проверяет секцию dashboard.
+func (c *DashboardConfig) Validate() error {
+	if c.CacheTTL < 0 {
+		return fmt.Errorf("cache_ttl must not be negative")
+	}
+	if c.TopCategories <= 0 || c.TopCategories > 50 {
+		return fmt.Errorf("top_categories must be between 1 and 50")
+	}
+	if c.RecentActivity <= 0 || c.RecentActivity > 100 {
+		return fmt.Errorf("recent_activity must be between 1 and 100")
+	}
+	return nil
+}
+
+// AnomaliesConfig - пороги поиска подозрительных цен предложений
+// (GET /api/v1/lots/:id/anomalies). Цена за единицу сравнивается с медианой
+// цен других подрядчиков лота и со средней ценой позиции каталога по истории.
+type AnomaliesConfig struct {
+	// Отклонение от ориентира, после которого позиция помечается (warning), %
+	ThresholdPercent float64 `yaml:"threshold_percent" env:"ANOMALIES_THRESHOLD_PERCENT" env-default:"30"`
+	// Во сколько раз отклонение должно превысить порог, чтобы стать critical
+	CriticalFactor float64 `yaml:"critical_factor" env:"ANOMALIES_CRITICAL_FACTOR" env-default:"3"`
+	// Пороги по категориям тендеров: ID категории → threshold_percent.
+	// В окружении: ANOMALIES_CATEGORY_THRESHOLDS="3:50,7:15"
+	CategoryThresholds map[string]float64 `yaml:"category_thresholds" env:"ANOMALIES_CATEGORY_THRESHOLDS"`
+	// Сколько цен других подрядчиков нужно для сравнения с медианой
+	MinPeers int `yaml:"min_peers" env:"ANOMALIES_MIN_PEERS" env-default:"2"`
+	// Окно истории цен каталога (месяцев) и сколько цен в нём нужно для сравнения
+	HistoryMonths    int `yaml:"history_months" env:"ANOMALIES_HISTORY_MONTHS" env-default:"24"`
+	MinHistoryPrices int `yaml:"min_history_prices" env:"ANOMALIES_MIN_HISTORY_PRICES" env-default:"3"`
+}
+
+// Validate проверяет пороги и минимальные выборки.
+func (c *AnomaliesConfig) Validate() error {
+	if c.ThresholdPercent <= 0 {
+		return fmt.Errorf("threshold_percent must be positive, got %v", c.ThresholdPercent)
+	}
+	if c.CriticalFactor <= 1 {
+		return fmt.Errorf("critical_factor must be greater than 1, got %v", c.CriticalFactor)
+	}
+	for category, threshold := range c.CategoryThresholds {
+		if id, err := strconv.ParseInt(category, 10, 64); err != nil || id <= 0 {
+			return fmt.Errorf("category_thresholds: category must be a positive category id, got %q", category)
+		}
+		if threshold <= 0 {
+			return fmt.Errorf("category_thresholds: threshold for category %s must be positive, got %v", category, threshold)
+		}
+	}
+	if c.MinPeers < 1 {
+		return fmt.Errorf("min_peers must be at least 1, got %d", c.MinPeers)
+	}
+	if c.HistoryMonths < 1 || c.HistoryMonths > 120 {
+		return fmt.Errorf("history_months must be between 1 and 120, got %d", c.HistoryMonths)
+	}
+	if c.MinHistoryPrices < 1 {
+		return fmt.Errorf("min_history_prices must be at least 1, got %d", c.MinHistoryPrices)
+	}
+	return nil
+}
+
+// Threshold возвращает порог warning для категории тендера (0 — без категории).
+func (c *AnomaliesConfig) Threshold(categoryID int64) float64 {
+	if threshold, ok := c.CategoryThresholds[strconv.FormatInt(categoryID, 10)]; ok && categoryID > 0 {
+		return threshold
+	}
+	return c.ThresholdPercent
+}
+
+// CurrencyConfig - курсы валют для сравнения предложений в разных валютах в аналитике
+// лота (GET /api/v1/lots/:id/analytics). Курс — стоимость единицы валюты в базовой:
+// "USD: 92.5" означает 1 USD = 92.5 RUB. Суммы в валютах без курса в агрегаты не входят.
+type CurrencyConfig struct {
+	Base string `yaml:"base" env:"CURRENCY_BASE" env-default:"RUB"`
+	// В окружении: CURRENCY_RATES="USD:92.5,EUR:100.1"
+	Rates map[string]string `yaml:"rates" env:"CURRENCY_RATES"`
+}
+
+// validCurrencyCode — код валюты ISO 4217: три заглавные латинские буквы.
+var validCurrencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Validate проверяет базовую валюту и курсы.
+func (c *CurrencyConfig) Validate() error {
+	if !validCurrencyCode.MatchString(c.Base) {
+		return fmt.Errorf("base must be an ISO 4217 code like RUB, got %q", c.Base)
+	}
+	for code, rate := range c.Rates {
+		if !validCurrencyCode.MatchString(code) {
+			return fmt.Errorf("rates: currency must be an ISO 4217 code like USD, got %q", code)
+		}
+		if code == c.Base {
+			return fmt.Errorf("rates: base currency %s must not have a rate", code)
+		}
+		d, err := decimal.NewFromString(rate)
+		if err != nil || !d.IsPositive() {
+			return fmt.Errorf("rates: rate for %s must be a positive number, got %q", code, rate)
+		}
+	}
+	return nil
+}
+
+// HTTPCacheConfig - заголовок Cache-Control для тяжёлых эндпоинтов чтения с ETag.
+// По умолчанию клиент хранит ответ только у себя и перепроверяет его при каждом
+// запросе (If-None-Match -> 304), поэтому изменения видны сразу.
+type HTTPCacheConfig struct {
+	TenderDetails   string `yaml:"tender_details" env:"HTTP_CACHE_TENDER_DETAILS" env-default:"private, no-cache"`
+	LotComparison   string `yaml:"lot_comparison" env:"HTTP_CACHE_LOT_COMPARISON" env-default:"private, no-cache"`
+	ProposalDetails string `yaml:"proposal_details" env:"HTTP_CACHE_PROPOSAL_DETAILS" env-default:"private, no-cache"`
+}
+
+// Validate проверяет, что для каждого маршрута задан Cache-Control.
+func (c *HTTPCacheConfig) Validate() error {
+	routes := []struct{ name, value string }{
+		{"tender_details", c.TenderDetails},
+		{"lot_comparison", c.LotComparison},
+		{"proposal_details", c.ProposalDetails},
+	}
+	for _, route := range routes {
+		if strings.TrimSpace(route.value) == "" {
+			return fmt.Errorf("%s must not be empty", route.name)
+		}
+	}
+	return nil
+}
+
+// RefCacheConfig - серверный кэш справочников (типы, разделы, категории тендеров,
+// единицы измерения). Кэш сбрасывается обработчиками, которые меняют справочник,
+// и после импорта тендера; TTL ограничивает устаревание при изменениях в обход API.
+type RefCacheConfig struct {
+	// none — кэш выключен; memory — в памяти процесса; redis — общий для всех реплик
+	Driver string `yaml:"driver" env:"REF_CACHE_DRIVER" env-default:"memory"`
+
+	TenderTypesTTL      time.Duration `yaml:"tender_types_ttl" env:"REF_CACHE_TENDER_TYPES_TTL" env-default:"10m"`
+	TenderChaptersTTL   time.Duration `yaml:"tender_chapters_ttl" env:"REF_CACHE_TENDER_CHAPTERS_TTL" env-default:"10m"`
+	TenderCategoriesTTL time.Duration `yaml:"tender_categories_ttl" env:"REF_CACHE_TENDER_CATEGORIES_TTL" env-default:"10m"`
+	// Справочник единиц содержит счётчики ссылок, поэтому TTL короче
+	UnitsTTL time.Duration `yaml:"units_ttl" env:"REF_CACHE_UNITS_TTL" env-default:"1m"`
+
+	// Предел числа ответов в памяти (driver: memory)
+	MaxEntries int `yaml:"max_entries" env:"REF_CACHE_MAX_ENTRIES" env-default:"1000"`
+
+	Redis RefCacheRedisConfig `yaml:"redis"`
+}
+
+type RefCacheRedisConfig struct {
+	// redis://[[user]:password@]host:6379[/db]; rediss:// — с TLS
+	URL       string        `yaml:"url" env:"REF_CACHE_REDIS_URL"`
+	KeyPrefix string        `yaml:"key_prefix" env:"REF_CACHE_REDIS_KEY_PREFIX" env-default:"tenders:refcache"`
+	Timeout   time.Duration `yaml:"timeout" env:"REF_CACHE_REDIS_TIMEOUT" env-default:"500ms"`
+}
+
+// Validate проверяет драйвер, TTL и настройки выбранного драйвера кэша справочников.
+func (c *RefCacheConfig) Validate() error {
+	switch c.Driver {
+	case "none":
+		return nil
+	case "memory":
+		if c.MaxEntries <= 0 {
+			return fmt.Errorf("max_entries must be positive")
+		}
+	case "redis":
+		u, err := url.Parse(c.Redis.URL)
+		if err != nil || (u.Scheme != "redis" && u.Scheme != "rediss") || u.Host == "" {
+			return fmt.Errorf("redis.url must be redis://host:port or rediss://host:port when driver is redis")
+		}
+		if strings.TrimSpace(c.Redis.KeyPrefix) == "" {
+			return fmt.Errorf("redis.key_prefix must not be empty")
+		}
+		if c.Redis.Timeout <= 0 {
+			return fmt.Errorf("redis.timeout must be positive")
+		}
+	default:
+		return fmt.Errorf("driver must be one of: none, memory, redis (got: %s)", c.Driver)
+	}
+
+	if c.TenderTypesTTL <= 0 || c.TenderChaptersTTL <= 0 || c.TenderCategoriesTTL <= 0 || c.UnitsTTL <= 0 {
+		return fmt.Errorf("tender_types_ttl, tender_chapters_ttl, tender_categories_ttl and units_ttl must be positive")
+	}
+	return nil
+}
+
+// SchedulerConfig - интервалы фоновых задач планировщика (GET /api/v1/admin/jobs).
+type SchedulerConfig struct {
+	// Перенормализация каталога после повышения norm_version: одна порция позиций за запуск
+	RenormalizationInterval  time.Duration `yaml:"renormalization_interval" env:"SCHEDULER_RENORMALIZATION_INTERVAL" env-default:"1m"`
+	RenormalizationBatchSize int32         `yaml:"renormalization_batch_size" env:"SCHEDULER_RENORMALIZATION_BATCH_SIZE" env-default:"1000"`
+	// Блокировки фоновых задач между экземплярами API: postgres — задачу планировщика,
+	// outbox и регулярные отчёты в каждый момент выполняет один экземпляр
+	// (advisory-блокировка); none — каждый экземпляр выполняет их сам
+	LockDriver string `yaml:"lock_driver" env:"SCHEDULER_LOCK_DRIVER" env-default:"postgres"`
+}
+
+// Политики хранения данных (RetentionConfig.DryRun, retention_runs.policy).
+const (
+	RetentionExpiredSessions = "expired_sessions"
+	RetentionRevokedSessions = "revoked_sessions"
+	RetentionMatchingCache   = "matching_cache"
+	RetentionRawImports      = "raw_import_history"
+)
+
+// RetentionPolicies — все политики хранения в порядке выполнения.
+var RetentionPolicies = []string{
+	RetentionExpiredSessions,
+	RetentionRevokedSessions,
+	RetentionMatchingCache,
+	RetentionRawImports,
+}
+
+// RetentionConfig - удаление устаревших данных по расписанию (задание
+// планировщика retention, GET /api/v1/admin/retention). Нулевой срок
+// отключает политику.
+type RetentionConfig struct {
+	Enabled  bool          `yaml:"enabled" env:"RETENTION_ENABLED" env-default:"true"`
+	Interval time.Duration `yaml:"interval" env:"RETENTION_INTERVAL" env-default:"1h"`
+	// Сколько строк одна политика удаляет за запуск: остаток удалит следующий
+	BatchSize int32 `yaml:"batch_size" env:"RETENTION_BATCH_SIZE" env-default:"10000"`
+	// Сессии (user_sessions) хранятся столько после истечения и после отзыва
+	ExpiredSessionsAfter time.Duration `yaml:"expired_sessions_after" env:"RETENTION_EXPIRED_SESSIONS_AFTER" env-default:"168h"`
+	RevokedSessionsAfter time.Duration `yaml:"revoked_sessions_after" env:"RETENTION_REVOKED_SESSIONS_AFTER" env-default:"720h"`
+	// Удаление записей matching_cache с истёкшим expires_at
+	MatchingCache bool `yaml:"matching_cache" env:"RETENTION_MATCHING_CACHE" env-default:"true"`
+	// Версии исходного JSON импортов старше стольких месяцев; последняя версия
+	// тендера хранится всегда. 0 — история не удаляется
+	RawImportHistoryMonths int `yaml:"raw_import_history_months" env:"RETENTION_RAW_IMPORT_HISTORY_MONTHS" env-default:"0"`
+	// Политики, которые только считают подходящие строки, ничего не удаляя
+	DryRun []string `yaml:"dry_run" env:"RETENTION_DRY_RUN" env-separator:","`
+}
+
+// Validate проверяет настройки политик хранения.
+func (c *RetentionConfig) Validate() error {
+	if c.ExpiredSessionsAfter < 0 || c.RevokedSessionsAfter < 0 {
+		return fmt.Errorf("expired_sessions_after and revoked_sessions_after must not be negative")
+	}
+	if c.RawImportHistoryMonths < 0 {
+		return fmt.Errorf("raw_import_history_months must not be negative (got: %d)", c.RawImportHistoryMonths)
+	}
+	for _, policy := range c.DryRun {
+		if !slices.Contains(RetentionPolicies, policy) {
+			return fmt.Errorf("dry_run: unknown policy %q (expected one of: %s)", policy, strings.Join(RetentionPolicies, ", "))
+		}
+	}
+	if !c.Enabled {
+		return nil
+	}
+	if c.Interval <= 0 || c.BatchSize <= 0 {
+		return fmt.Errorf("interval and batch_size must be positive")
+	}
+	return nil
+}
+
+// PolicyEnabled сообщает, задан ли для политики срок хранения.
+func (c *RetentionConfig) PolicyEnabled(policy string) bool {
+	switch policy {
+	case RetentionExpiredSessions:
+		return c.ExpiredSessionsAfter > 0
+	case RetentionRevokedSessions:
+		return c.RevokedSessionsAfter > 0
+	case RetentionMatchingCache:
+		return c.MatchingCache
+	case RetentionRawImports:
+		return c.RawImportHistoryMonths > 0
+	}
+	return false
+}
+
+// IsDryRun сообщает, что политика выполняется в пробном режиме.
+func (c *RetentionConfig) IsDryRun(policy string) bool {
+	return slices.Contains(c.DryRun, policy)
+}
+
+// ContractorsConfig - сроки аккредитации подрядчиков: задание планировщика
+// contractor_accreditation и GET /api/v1/contractors/expiring-accreditation.
+type ContractorsConfig struct {
+	// Задание помечает истекшие аккредитации и публикует события о скором окончании
+	AccreditationCheckEnabled  bool          `yaml:"accreditation_check_enabled" env:"CONTRACTORS_ACCREDITATION_CHECK_ENABLED" env-default:"true"`
+	AccreditationCheckInterval time.Duration `yaml:"accreditation_check_interval" env:"CONTRACTORS_ACCREDITATION_CHECK_INTERVAL" env-default:"6h"`
+	// За сколько дней до окончания аккредитация считается истекающей
+	AccreditationWarnDays int `yaml:"accreditation_warn_days" env:"CONTRACTORS_ACCREDITATION_WARN_DAYS" env-default:"30"`
+	// Сколько истекающих аккредитаций задание обрабатывает за запуск: остаток — следующим
+	AccreditationBatchSize int32 `yaml:"accreditation_batch_size" env:"CONTRACTORS_ACCREDITATION_BATCH_SIZE" env-default:"500"`
+}
+
+// MaxAccreditationWarnDays — верхняя граница accreditation_warn_days и within_days.
+const MaxAccreditationWarnDays = 365
+
+// Validate проверяет настройки сроков аккредитации.
+func (c *ContractorsConfig) Validate() error {
+	if c.AccreditationWarnDays < 1 || c.AccreditationWarnDays > MaxAccreditationWarnDays {
+		return fmt.Errorf("accreditation_warn_days must be between 1 and %d (got: %d)", MaxAccreditationWarnDays, c.AccreditationWarnDays)
+	}
+	if !c.AccreditationCheckEnabled {
+		return nil
+	}
+	if c.AccreditationCheckInterval <= 0 || c.AccreditationBatchSize <= 0 {
+		return fmt.Errorf("accreditation_check_interval and accreditation_batch_size must be positive")
+	}
+	return nil
+}
+
+// LiveConfig - поток событий для веб-интерфейса (GET /api/v1/events, Server-Sent Events).
+type LiveConfig struct {
+	// Как часто в простаивающее соединение пишется комментарий-heartbeat, чтобы
+	// прокси и балансировщики не закрывали его по таймауту
+	HeartbeatInterval time.Duration `yaml:"heartbeat_interval" env:"LIVE_HEARTBEAT_INTERVAL" env-default:"15s"`
+	// Сколько событий ждут отправки в одно соединение; если клиент не успевает читать,
+	// новые события для него отбрасываются
+	BufferSize int `yaml:"buffer_size" env:"LIVE_BUFFER_SIZE" env-default:"32"`
+	// Одновременных соединений одного пользователя (вкладок браузера); сверх лимита — 429
+	MaxConnectionsPerUser int `yaml:"max_connections_per_user" env:"LIVE_MAX_CONNECTIONS_PER_USER" env-default:"5"`
+}
+
+// Validate проверяет настройки потока событий.
+func (c *LiveConfig) Validate() error {
+	if c.HeartbeatInterval <= 0 {
+		return fmt.Errorf("heartbeat_interval must be positive")
+	}
+	if c.BufferSize <= 0 || c.MaxConnectionsPerUser <= 0 {
+		return fmt.Errorf("buffer_size and max_connections_per_user must be positive")
+	}
+	return nil
+}
+
+// ETPConfig - получение тендеров с ЭТП через парсер (POST /api/v1/tenders/fetch).
+type ETPConfig struct {
+	// Путь парсера, который скачивает тендер с ЭТП по etp_id и ставит задачу
+	// разбора (ответ — task_id, как у загрузки файла)
+	FetchPath string `yaml:"fetch_path" env:"ETP_FETCH_PATH" env-default:"/fetch-tender/"`
+	// Таймаут запроса к парсеру: постановки задачи и опроса её статуса
+	RequestTimeout time.Duration `yaml:"request_timeout" env:"ETP_REQUEST_TIMEOUT" env-default:"30s"`
+	// Сколько незавершённая задача получения считается выполняющейся: повторный
+	// запрос того же etp_id в это время возвращает её, а не ставит новую
+	PendingTTL time.Duration `yaml:"pending_ttl" env:"ETP_PENDING_TTL" env-default:"30m"`
+
+	Sync ETPSyncConfig `yaml:"sync"`
+}
+
+// ETPSyncConfig - повторное получение открытых тендеров с ЭТП по расписанию:
+// новые предложения и изменения цен попадают в БД без участия пользователя.
+type ETPSyncConfig struct {
+	Enabled bool `yaml:"enabled" env:"ETP_SYNC_ENABLED" env-default:"false"`
+	// Как часто задача планировщика ищет тендеры, которые пора обновить
+	Interval time.Duration `yaml:"interval" env:"ETP_SYNC_INTERVAL" env-default:"1h"`
+	// Один тендер запрашивается у парсера не чаще раза в refresh_after
+	RefreshAfter time.Duration `yaml:"refresh_after" env:"ETP_SYNC_REFRESH_AFTER" env-default:"24h"`
+	// Сколько тендеров запрашивается за один запуск задачи
+	BatchSize int32 `yaml:"batch_size" env:"ETP_SYNC_BATCH_SIZE" env-default:"20"`
+	// Итоговые статусы процедуры на ЭТП: такие тендеры больше не обновляются
+	FinalStatuses []string `yaml:"final_statuses" env:"ETP_SYNC_FINAL_STATUSES" env-separator:"," env-default:"completed,cancelled"`
+}
+
+// Validate проверяет настройки получения тендеров с ЭТП.
+func (c *ETPConfig) Validate() error {
+	if !strings.HasPrefix(c.FetchPath, "/") {
+		return fmt.Errorf("fetch_path must start with / (got: %q)", c.FetchPath)
+	}
+	if c.RequestTimeout <= 0 || c.PendingTTL <= 0 {
+		return fmt.Errorf("request_timeout and pending_ttl must be positive")
+	}
+	if !c.Sync.Enabled {
+		return nil
+	}
+	if c.Sync.Interval <= 0 || c.Sync.RefreshAfter <= 0 {
+		return fmt.Errorf("sync.interval and sync.refresh_after must be positive")
+	}
+	if c.Sync.BatchSize <= 0 {
+		return fmt.Errorf("sync.batch_size must be positive (got: %d)", c.Sync.BatchSize)
+	}
+	if len(c.Sync.FinalStatuses) == 0 {
+		return fmt.Errorf("sync.final_statuses must not be empty")
+	}
+	return nil
+}
+
+// WebhooksConfig - настройки доставки вебхуков внешним подписчикам.
+type WebhooksConfig struct {
+	// Как часто фоновый воркер проверяет очередь доставок
+	DeliveryInterval time.Duration `yaml:"delivery_interval" env:"WEBHOOKS_DELIVERY_INTERVAL" env-default:"5s"`
+	// Сколько доставок отправляется за один проход
+	BatchSize int32 `yaml:"batch_size" env:"WEBHOOKS_BATCH_SIZE" env-default:"50"`
+	// Таймаут одного POST подписчику
+	RequestTimeout time.Duration `yaml:"request_timeout" env:"WEBHOOKS_REQUEST_TIMEOUT" env-default:"10s"`
+	// Число попыток доставки, после которого доставка получает статус failed
+	MaxAttempts int32 `yaml:"max_attempts" env:"WEBHOOKS_MAX_ATTEMPTS" env-default:"8"`
+	// Задержка перед первым повтором; каждая следующая удваивается до RetryMaxDelay
+	RetryBaseDelay time.Duration `yaml:"retry_base_delay" env:"WEBHOOKS_RETRY_BASE_DELAY" env-default:"30s"`
+	RetryMaxDelay  time.Duration `yaml:"retry_max_delay" env:"WEBHOOKS_RETRY_MAX_DELAY" env-default:"1h"`
+}
+
+// Validate проверяет настройки доставки вебхуков.
+func (c *WebhooksConfig) Validate() error {
+	if c.DeliveryInterval <= 0 {
+		return fmt.Errorf("delivery_interval must be positive")
+	}
+	if c.BatchSize <= 0 {
+		return fmt.Errorf("batch_size must be positive")
+	}
+	if c.RequestTimeout <= 0 {
+		return fmt.Errorf("request_timeout must be positive")
+	}
+	if c.MaxAttempts <= 0 {
+		return fmt.Errorf("max_attempts must be positive")
+	}
+	if c.RetryBaseDelay <= 0 {
+		return fmt.Errorf("retry_base_delay must be positive")
+	}
+	if c.RetryMaxDelay < c.RetryBaseDelay {
+		return fmt.Errorf("retry_max_delay must not be less than retry_base_delay")
+	}
+	return nil
+}
+
+// OutboxConfig - доставка событий transactional outbox (outbox_events)
+// Python-воркеру HTTP-callback'ом. Пустой worker_callback_url отключает outbox:
+// импорт не пишет события, диспетчер не запускается.
+type OutboxConfig struct {
+	// Адрес, на который отправляется POST с событием
+	WorkerCallbackURL string `yaml:"worker_callback_url" env:"OUTBOX_WORKER_CALLBACK_URL"`
+	// Передаётся воркеру в заголовке Authorization: Bearer; пустой — заголовок не отправляется
+	WorkerCallbackToken string `yaml:"worker_callback_token" env:"OUTBOX_WORKER_CALLBACK_TOKEN"`
+	// Как часто диспетчер проверяет очередь событий
+	DeliveryInterval time.Duration `yaml:"delivery_interval" env:"OUTBOX_DELIVERY_INTERVAL" env-default:"2s"`
+	// Сколько событий отправляется за один проход
+	BatchSize int32 `yaml:"batch_size" env:"OUTBOX_BATCH_SIZE" env-default:"50"`
+	// Таймаут одного POST воркеру
+	RequestTimeout time.Duration `yaml:"request_timeout" env:"OUTBOX_REQUEST_TIMEOUT" env-default:"10s"`
+	// Задержка перед первым повтором; каждая следующая удваивается до RetryMaxDelay.
+	// Число попыток не ограничено: событие повторяется, пока воркер не ответит 2xx
+	RetryBaseDelay time.Duration `yaml:"retry_base_delay" env:"OUTBOX_RETRY_BASE_DELAY" env-default:"5s"`
+	RetryMaxDelay  time.Duration `yaml:"retry_max_delay" env:"OUTBOX_RETRY_MAX_DELAY" env-default:"5m"`
+}
+
+// Enabled сообщает, задан ли адрес callback'а воркера.
+func (c *OutboxConfig) Enabled() bool {
+	return c.WorkerCallbackURL != ""
+}
+
+// Validate проверяет настройки outbox.
+func (c *OutboxConfig) Validate() error {
+	if c.WorkerCallbackURL != "" {
+		u, err := url.Parse(c.WorkerCallbackURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("worker_callback_url must be an absolute http(s) URL")
+		}
+	}
+	if c.DeliveryInterval <= 0 {
+		return fmt.Errorf("delivery_interval must be positive")
+	}
+	if c.BatchSize <= 0 {
+		return fmt.Errorf("batch_size must be positive")
+	}
+	if c.RequestTimeout <= 0 {
+		return fmt.Errorf("request_timeout must be positive")
+	}
+	if c.RetryBaseDelay <= 0 {
+		return fmt.Errorf("retry_base_delay must be positive")
+	}
+	if c.RetryMaxDelay < c.RetryBaseDelay {
+		return fmt.Errorf("retry_max_delay must not be less than retry_base_delay")
+	}
+	return nil
+}
+
+// ReportsConfig - регулярные отчеты: формирование по расписанию и рассылка по почте.
+type ReportsConfig struct {
+	// Включает фоновый воркер рассылки; без него отчеты можно настраивать, но они не отправляются
+	Enabled bool `yaml:"enabled" env:"REPORTS_ENABLED" env-default:"false"`
+	// Как часто воркер проверяет, не наступил ли срок отчетов
+	CheckInterval time.Duration `yaml:"check_interval" env:"REPORTS_CHECK_INTERVAL" env-default:"1m"`
+	// Сколько отчетов формируется за один проход
+	BatchSize int32 `yaml:"batch_size" env:"REPORTS_BATCH_SIZE" env-default:"5"`
+	// Сколько строк попадает в отчет об очереди несопоставленных позиций
+	MaxRows int32 `yaml:"max_rows" env:"REPORTS_MAX_ROWS" env-default:"1000"`
+	// Сервис HTML -> PDF с API Gotenberg (POST /forms/chromium/convert/html).
+	// Пустое значение — PDF-отчеты недоступны
+	PDFConverterURL string `yaml:"pdf_converter_url" env:"REPORTS_PDF_CONVERTER_URL"`
+	// Таймаут конвертации PDF
+	RequestTimeout time.Duration `yaml:"request_timeout" env:"REPORTS_REQUEST_TIMEOUT" env-default:"60s"`
+}
+
+// Validate проверяет настройки отчетов. Наличие SMTP при включенном воркере
+// проверяет Load: почтовые настройки общие (mail).
+func (c *ReportsConfig) Validate() error {
+	if c.CheckInterval <= 0 {
+		return fmt.Errorf("check_interval must be positive")
+	}
+	if c.BatchSize <= 0 {
+		return fmt.Errorf("batch_size must be positive")
+	}
+	if c.MaxRows <= 0 {
+		return fmt.Errorf("max_rows must be positive")
+	}
+	if c.RequestTimeout <= 0 {
+		return fmt.Errorf("request_timeout must be positive")
+	}
+	if c.PDFConverterURL != "" {
+		u, err := url.Parse(c.PDFConverterURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("pdf_converter_url must be an absolute http(s) URL")
+		}
+	}
+	return nil
+}
+
+// MailConfig - SMTP-сервер для всех писем: отчеты, приглашения, сброс пароля,
+// оповещения об ошибках импорта. Пустой host отключает почту — письма только
+// пишутся в лог. Если сервер поддерживает STARTTLS, соединение шифруется;
+// логин и пароль необязательны.
+type MailConfig struct {
+	Host     string `yaml:"host" env:"SMTP_HOST"`
+	Port     int    `yaml:"port" env:"SMTP_PORT" env-default:"587"`
+	Username string `yaml:"username" env:"SMTP_USERNAME"`
+	Password string `yaml:"password" env:"SMTP_PASSWORD"`
+	// Адрес отправителя, например "Тендеры <noreply@example.com>"
+	From string `yaml:"from" env:"SMTP_FROM"`
+	// Ограничение на одну попытку отправки
+	Timeout time.Duration `yaml:"timeout" env:"SMTP_TIMEOUT" env-default:"30s"`
+	// Попыток на письмо при временных ошибках (сеть, ответы 4xx); 1 — без повторов
+	MaxAttempts int `yaml:"max_attempts" env:"SMTP_MAX_ATTEMPTS" env-default:"3"`
+	// Пауза перед повтором удваивается с каждой попыткой, но не больше retry_max_delay
+	RetryBaseDelay time.Duration `yaml:"retry_base_delay" env:"SMTP_RETRY_BASE_DELAY" env-default:"2s"`
+	RetryMaxDelay  time.Duration `yaml:"retry_max_delay" env:"SMTP_RETRY_MAX_DELAY" env-default:"30s"`
+}
+
+// Enabled сообщает, задан ли SMTP-сервер.
+func (c *MailConfig) Enabled() bool {
+	return c.Host != ""
+}
+
+// Validate проверяет настройки почты. Без host остальные поля не проверяются.
+func (c *MailConfig) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if c.Port < 1 || c.Port > 65535 {
+		return fmt.Errorf("port must be between 1 and 65535, got %d", c.Port)
+	}
+	if _, err := mail.ParseAddress(c.From); err != nil {
+		return fmt.Errorf("from must be an email address: %w", err)
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+	if c.MaxAttempts < 1 {
+		return fmt.Errorf("max_attempts must be at least 1, got %d", c.MaxAttempts)
+	}
+	if c.RetryBaseDelay <= 0 || c.RetryMaxDelay < c.RetryBaseDelay {
+		return fmt.Errorf("retry_base_delay must be positive and not greater than retry_max_delay")
+	}
+	return nil
+}
+
+// NotificationsConfig - содержимое и адресаты служебных писем.
+type NotificationsConfig struct {
+	// Адрес веб-интерфейса для ссылок в письмах (приглашение, сброс пароля)
+	AppURL string `yaml:"app_url" env:"APP_URL" env-default:"http://localhost:3000"`
+	// Кому сообщать о неудачном импорте тендера; пусто — оповещения отключены
+	ImportFailureRecipients []string `yaml:"import_failure_recipients" env:"NOTIFY_IMPORT_FAILURE_RECIPIENTS" env-separator:","`
+	// Повторные ошибки импорта одного тендера за это время не присылаются
+	ImportFailureCooldown time.Duration `yaml:"import_failure_cooldown" env:"NOTIFY_IMPORT_FAILURE_COOLDOWN" env-default:"15m"`
+}
+
+// Validate проверяет настройки уведомлений.
+func (c *NotificationsConfig) Validate() error {
+	u, err := url.Parse(c.AppURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("app_url must be an absolute http(s) URL")
+	}
+	for _, addr := range c.ImportFailureRecipients {
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return fmt.Errorf("import_failure_recipients: %q is not an email address", addr)
+		}
+	}
+	if c.ImportFailureCooldown < 0 {
+		return fmt.Errorf("import_failure_cooldown must not be negative")
+	}
+	return nil
+}
+
+// EventsConfig - публикация доменных событий во внешнюю шину для потребителей
+// вроде аналитики (tender.imported, lot.updated, position.matched, winner.created).
+type EventsConfig struct {
+	// none — публикация отключена; nats; kafka — только через Kafka REST Proxy
+	// (нативный протокол Kafka не поддерживается)
+	Driver string `yaml:"driver" env:"EVENTS_DRIVER" env-default:"none"`
+	// Таймаут подключения и отправки одного события
+	PublishTimeout time.Duration `yaml:"publish_timeout" env:"EVENTS_PUBLISH_TIMEOUT" env-default:"2s"`
+	// Сколько событий ждут отправки в памяти; при переполнении новые события отбрасываются
+	BufferSize int `yaml:"buffer_size" env:"EVENTS_BUFFER_SIZE" env-default:"1000"`
+
+	NATS  EventsNATSConfig  `yaml:"nats"`
+	Kafka EventsKafkaConfig `yaml:"kafka"`
+}
+
+type EventsNATSConfig struct {
+	// nats://[user:pass@]host:4222 или nats://token@host:4222; tls://… — с TLS
+	URL string `yaml:"url" env:"EVENTS_NATS_URL"`
+	// Событие публикуется в subject <prefix>.<type>, например tenders.tender.imported
+	SubjectPrefix string `yaml:"subject_prefix" env:"EVENTS_NATS_SUBJECT_PREFIX" env-default:"tenders"`
+}
+
+type EventsKafkaConfig struct {
+	// Адрес Kafka REST Proxy (Confluent REST Proxy API v2, Redpanda HTTP Proxy).
+	// Брокеры Kafka напрямую (bootstrap servers) не поддерживаются.
+	RESTProxyURL string `yaml:"rest_proxy_url" env:"EVENTS_KAFKA_REST_PROXY_URL"`
+	Topic        string `yaml:"topic" env:"EVENTS_KAFKA_TOPIC" env-default:"tenders.events"`
+}
+
+// validSubjectToken — допустимые токены subject NATS и имени топика Kafka (без wildcard и пробелов).
+var validSubjectToken = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)*$`)
+
+// Validate проверяет настройки выбранного драйвера публикации событий.
+func (c *EventsConfig) Validate() error {
+	switch c.Driver {
+	case "none":
+		return nil
+	case "nats":
+		u, err := url.Parse(c.NATS.URL)
+		if err != nil || (u.Scheme != "nats" && u.Scheme != "tls") || u.Host == "" {
+			return fmt.Errorf("nats.url must be nats://host:port or tls://host:port when driver is nats")
+		}
+		if !validSubjectToken.MatchString(c.NATS.SubjectPrefix) {
+			return fmt.Errorf("nats.subject_prefix %q must be dot-separated tokens of letters, digits, '-' and '_'", c.NATS.SubjectPrefix)
+		}
+	case "kafka":
+		u, err := url.Parse(c.Kafka.RESTProxyURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("kafka.rest_proxy_url must be an http(s) URL when driver is kafka")
+		}
+		if !validSubjectToken.MatchString(c.Kafka.Topic) {
+			return fmt.Errorf("kafka.topic %q must contain only letters, digits, '.', '-' and '_'", c.Kafka.Topic)
+		}
+	default:
+		return fmt.Errorf("driver must be one of: none, nats, kafka (got: %s)", c.Driver)
+	}
+
+	if c.PublishTimeout <= 0 {
+		return fmt.Errorf("publish_timeout must be positive")
+	}
+	if c.BufferSize <= 0 {
+		return fmt.Errorf("buffer_size must be positive")
+	}
+	return nil
+}
+
+// GRPCConfig - gRPC-сервер для Python-воркеров (импорт, очередь сопоставления,
+// индексация каталога). Слушает отдельный порт; аутентификация — те же ключи
+// сервисов и scopes, что и у /internal/worker.
+type GRPCConfig struct {
+	Enabled bool   `yaml:"enabled" env:"GRPC_ENABLED" env-default:"false"`
+	BindIP  string `yaml:"bind_ip" env:"GRPC_BIND_IP" env-default:"127.0.0.1"`
+	Port    string `yaml:"port" env:"GRPC_PORT" env-default:"9090"`
+	// Предел размера входящего сообщения. gRPC собирает сообщение в памяти целиком,
+	// поэтому тендеры больше предела импортируются через потоковый HTTP-импорт
+	MaxRecvMsgSize int `yaml:"max_recv_msg_size" env:"GRPC_MAX_RECV_MSG_SIZE" env-default:"52428800"`
+}
+
+// Validate проверяет адрес и лимиты gRPC-сервера. Выключенный сервер не проверяется.
+func (c *GRPCConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	port, err := strconv.Atoi(c.Port)
+	if err != nil || port <= 0 || port > 65535 {
+		return fmt.Errorf("port must be a number between 1 and 65535 (got: %q)", c.Port)
+	}
+	if c.MaxRecvMsgSize <= 0 {
+		return fmt.Errorf("max_recv_msg_size must be positive")
+	}
+	return nil
+}
+
+// DatabaseConfig - подключение к PostgreSQL и настройки пула соединений.
+type DatabaseConfig struct {
+	// pgx (pgxpool, по умолчанию) или postgres (lib/pq через database/sql)
+	Driver string `yaml:"driver" env:"DB_DRIVER" env-default:"pgx"`
+	// DSN; обязателен, но может прийти из secrets.provider (см. Config.ValidateSecrets)
+	Source string `yaml:"source" env:"DB_SOURCE"`
+	// Максимум открытых соединений с БД
+	MaxOpenConns int `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" env-default:"25"`
+	// Сколько простаивающих соединений database/sql держит открытыми (только
+	// driver: postgres, SetMaxIdleConns)
+	MaxIdleConns int `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" env-default:"5"`
+	// Сколько соединений pgxpool держит открытыми даже без нагрузки (только
+	// driver: pgx, MinConns)
+	MinConns int `yaml:"min_conns" env:"DB_MIN_CONNS" env-default:"0"`
+	// Соединение закрывается после этого времени жизни (0 — без ограничения)
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" env-default:"1h"`
+	// Простаивающее соединение сверх max_idle_conns (min_conns для pgx) закрывается после этого времени (0 — без ограничения)
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME" env-default:"30m"`
+	// Применять встроенные миграции при старте, если схема отстаёт. Выключено —
+	// сервер на отстающей схеме не запускается (для dev удобно включить)
+	AutoMigrate bool `yaml:"auto_migrate" env:"DB_AUTO_MIGRATE" env-default:"false"`
+	// Как pgx выполняет запросы (только driver: pgx): cache_statement —
+	// подготовленные операторы в кэше соединения, cache_describe — кэш только
+	// описаний, describe_exec — описание на каждый запрос, exec и
+	// simple_protocol — без подготовки (для PgBouncer в режиме transaction)
+	QueryExecMode string `yaml:"query_exec_mode" env:"DB_QUERY_EXEC_MODE" env-default:"cache_statement"`
+	// Размер кэша подготовленных операторов (или описаний) на соединение
+	StatementCacheCapacity int `yaml:"statement_cache_capacity" env:"DB_STATEMENT_CACHE_CAPACITY" env-default:"512"`
+}
+
+// QueryExecModes — допустимые значения database.query_exec_mode.
+var QueryExecModes = []string{"cache_statement", "cache_describe", "describe_exec", "exec", "simple_protocol"}
+
+// Validate проверяет драйвер и размеры пула соединений.
+func (c *DatabaseConfig) Validate() error {
+	if c.Driver != "pgx" && c.Driver != "postgres" {
+		return fmt.Errorf("unknown driver %q (expected pgx or postgres)", c.Driver)
+	}
+	if c.MaxOpenConns <= 0 {
+		return fmt.Errorf("max_open_conns must be positive")
+	}
+	if c.MaxIdleConns < 0 || c.MaxIdleConns > c.MaxOpenConns {
+		return fmt.Errorf("max_idle_conns must be between 0 and max_open_conns (%d)", c.MaxOpenConns)
+	}
+	if c.MinConns < 0 || c.MinConns > c.MaxOpenConns {
+		return fmt.Errorf("min_conns must be between 0 and max_open_conns (%d)", c.MaxOpenConns)
+	}
+	if c.ConnMaxLifetime < 0 || c.ConnMaxIdleTime < 0 {
+		return fmt.Errorf("conn_max_lifetime and conn_max_idle_time must not be negative")
+	}
+	if !slices.Contains(QueryExecModes, c.QueryExecMode) {
+		return fmt.Errorf("unknown query_exec_mode %q (expected one of %s)", c.QueryExecMode, strings.Join(QueryExecModes, ", "))
+	}
+	if c.StatementCacheCapacity <= 0 {
+		return fmt.Errorf("statement_cache_capacity must be positive")
+	}
+	return nil
+}
+
+// Провайдеры секретов (secrets.provider).
+const (
+	SecretsProviderEnv   = "env"
+	SecretsProviderFile  = "file"
+	SecretsProviderVault = "vault"
+)
+
+// SecretsConfig - откуда берутся jwt_secret, jwt_secret_previous и DSN базы
+// (пакет secrets). env — переменные окружения и YAML, как раньше.
+type SecretsConfig struct {
+	Provider string             `yaml:"provider" env:"SECRETS_PROVIDER" env-default:"env"`
+	File     FileSecretsConfig  `yaml:"file"`
+	Vault    VaultSecretsConfig `yaml:"vault"`
+}
+
+// FileSecretsConfig - каталог с файлами секретов (Docker/Kubernetes secrets),
+// по файлу на секрет: jwt_secret, jwt_secret_previous, db_source.
+type FileSecretsConfig struct {
+	Dir string `yaml:"dir" env:"SECRETS_FILE_DIR" env-default:"/run/secrets"`
+}
+
+// VaultSecretsConfig - секрет HashiCorp Vault KV v2, ключи которого совпадают
+// с именами секретов (jwt_secret, jwt_secret_previous, db_source).
+type VaultSecretsConfig struct {
+	Address string `yaml:"address" env:"VAULT_ADDR"`
+	Token   string `yaml:"token" env:"VAULT_TOKEN"`
+	// Точка монтирования KV v2 и путь секрета: GET /v1/<mount>/data/<path>
+	Mount   string        `yaml:"mount" env:"VAULT_KV_MOUNT" env-default:"secret"`
+	Path    string        `yaml:"path" env:"VAULT_SECRET_PATH" env-default:"tenders-go"`
+	Timeout time.Duration `yaml:"timeout" env:"VAULT_TIMEOUT" env-default:"5s"`
+}
+
+// Validate проверяет настройки провайдера секретов.
+func (c *SecretsConfig) Validate() error {
+	switch c.Provider {
+	case SecretsProviderEnv:
+	case SecretsProviderFile:
+		if c.File.Dir == "" {
+			return fmt.Errorf("file.dir is required when provider is file")
+		}
+	case SecretsProviderVault:
+		u, err := url.Parse(c.Vault.Address)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("vault.address must be an absolute http(s) URL when provider is vault")
+		}
+		if c.Vault.Token == "" {
+			return fmt.Errorf("vault.token is required when provider is vault")
+		}
+		if c.Vault.Mount == "" || c.Vault.Path == "" {
+			return fmt.Errorf("vault.mount and vault.path must not be empty")
+		}
+		if c.Vault.Timeout <= 0 {
+			return fmt.Errorf("vault.timeout must be positive")
+		}
+	default:
+		return fmt.Errorf("unknown provider %q (expected env, file or vault)", c.Provider)
+	}
+	return nil
+}
+
+// CORSConfig - origins фронтенда вне режима отладки. Перечитывается по SIGHUP.
 type CORSConfig struct {
 	AllowedOrigins []string `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS" env-separator:","`
 }
 
+// Validate проверяет, что каждый origin — схема http(s) и хост без пути.
+func (c *CORSConfig) Validate() error {
+	for _, origin := range c.AllowedOrigins {
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			return fmt.Errorf("allowed_origins: %q must be an http(s) origin like https://tenders.example.com", origin)
+		}
+	}
+	return nil
+}
+
+// RateLimitConfig - ограничение частоты запросов. Перечитывается по SIGHUP,
+// включая смену хранилища лимитов пользователей.
+type RateLimitConfig struct {
+	// Общий лимит запросов всех воркеров к /internal/worker в секунду и допустимый всплеск
+	ServiceRPS   float64 `yaml:"service_rps" env:"RATE_LIMIT_SERVICE_RPS" env-default:"100"`
+	ServiceBurst int     `yaml:"service_burst" env:"RATE_LIMIT_SERVICE_BURST" env-default:"200"`
+
+	// Хранилище лимитов /api/v1: none — выключены; memory — в памяти процесса,
+	// у каждой реплики свои счётчики; redis — общие для всех реплик
+	Driver string `yaml:"driver" env:"RATE_LIMIT_DRIVER" env-default:"memory"`
+	// Лимит каждого пользователя на защищённые роуты (ключ — user_id)
+	UserRPS   float64 `yaml:"user_rps" env:"RATE_LIMIT_USER_RPS" env-default:"20"`
+	UserBurst int     `yaml:"user_burst" env:"RATE_LIMIT_USER_BURST" env-default:"40"`
+	// Выгрузки CSV читают все строки выборки, поэтому лимит строже и считается отдельно
+	ExportRPS   float64 `yaml:"export_rps" env:"RATE_LIMIT_EXPORT_RPS" env-default:"0.2"`
+	ExportBurst int     `yaml:"export_burst" env:"RATE_LIMIT_EXPORT_BURST" env-default:"5"`
+	// Публичные auth-роуты (вход, refresh, сброс пароля): ключ — IP клиента
+	AuthRPS   float64 `yaml:"auth_rps" env:"RATE_LIMIT_AUTH_RPS" env-default:"1"`
+	AuthBurst int     `yaml:"auth_burst" env:"RATE_LIMIT_AUTH_BURST" env-default:"10"`
+	// Множители лимитов пользователя и выгрузок по ролям, например {admin: 5, viewer: 0.5}.
+	// Роль без множителя получает базовый лимит
+	RoleMultipliers map[string]float64 `yaml:"role_multipliers"`
+
+	Redis RateLimitRedisConfig `yaml:"redis"`
+}
+
+type RateLimitRedisConfig struct {
+	// redis://[[user]:password@]host:6379[/db]; rediss:// — с TLS
+	URL       string        `yaml:"url" env:"RATE_LIMIT_REDIS_URL"`
+	KeyPrefix string        `yaml:"key_prefix" env:"RATE_LIMIT_REDIS_KEY_PREFIX" env-default:"tenders:ratelimit"`
+	Timeout   time.Duration `yaml:"timeout" env:"RATE_LIMIT_REDIS_TIMEOUT" env-default:"200ms"`
+}
+
+// Validate проверяет лимиты запросов и настройки хранилища лимитов.
+func (c *RateLimitConfig) Validate() error {
+	if c.ServiceRPS <= 0 {
+		return fmt.Errorf("service_rps must be positive")
+	}
+	if c.ServiceBurst <= 0 {
+		return fmt.Errorf("service_burst must be positive")
+	}
+
+	switch c.Driver {
+	case "none":
+		return nil
+	case "memory":
+	case "redis":
+		u, err := url.Parse(c.Redis.URL)
+		if err != nil || (u.Scheme != "redis" && u.Scheme != "rediss") || u.Host == "" {
+			return fmt.Errorf("redis.url must be redis://host:port or rediss://host:port when driver is redis")
+		}
+		if strings.TrimSpace(c.Redis.KeyPrefix) == "" {
+			return fmt.Errorf("redis.key_prefix must not be empty")
+		}
+		if c.Redis.Timeout <= 0 {
+			return fmt.Errorf("redis.timeout must be positive")
+		}
+	default:
+		return fmt.Errorf("driver must be one of: none, memory, redis (got: %s)", c.Driver)
+	}
+
+	if c.UserRPS <= 0 || c.ExportRPS <= 0 || c.AuthRPS <= 0 {
+		return fmt.Errorf("user_rps, export_rps and auth_rps must be positive")
+	}
+	if c.UserBurst <= 0 || c.ExportBurst <= 0 || c.AuthBurst <= 0 {
+		return fmt.Errorf("user_burst, export_burst and auth_burst must be positive")
+	}
+	for role, multiplier := range c.RoleMultipliers {
+		if multiplier <= 0 {
+			return fmt.Errorf("role_multipliers.%s must be positive", role)
+		}
+	}
+	return nil
+}
+
+// LogConfig - настройки логирования. Перечитывается по SIGHUP.
+type LogConfig struct {
+	// trace, debug, info, warn, error
+	Level string `yaml:"level" env:"LOG_LEVEL" env-default:"trace"`
+}
+
+// Validate проверяет уровень логирования.
+func (c *LogConfig) Validate() error {
+	if err := logging.ValidateLevel(c.Level); err != nil {
+		return fmt.Errorf("level: %w", err)
+	}
+	return nil
+}
+
 type Config struct {
 	IsDebug *bool `yaml:"is_debug" env-required:"true"`
 	Listen  struct {
@@ -98,34 +1279,58 @@ type Config struct {
 		BindIP string `yaml:"bind_ip" env-default:"127.0.0.1"`
 		Port   string `yaml:"port" env-default:"8080"`
 	} `yaml:"listen"`
-	Database struct {
-		Driver string `yaml:"driver" env:"DB_DRIVER" env-default:"postgres"`
-		Source string `yaml:"source" env:"DB_SOURCE" env-required:"true"`
-	} `yaml:"database"`
-	CORS     CORSConfig     `yaml:"cors"`
-	Auth     AuthConfig     `yaml:"auth"`
-	Services ServicesConfig `yaml:"services"`
+	Database      DatabaseConfig      `yaml:"database"`
+	Secrets       SecretsConfig       `yaml:"secrets"`
+	CORS          CORSConfig          `yaml:"cors"`
+	RateLimit     RateLimitConfig     `yaml:"rate_limit"`
+	Log           LogConfig           `yaml:"log"`
+	Auth          AuthConfig          `yaml:"auth"`
+	ServiceAuth   ServiceAuthConfig   `yaml:"service_auth"`
+	Services      ServicesConfig      `yaml:"services"`
+	Health        HealthConfig        `yaml:"health"`
+	Import        ImportConfig        `yaml:"import"`
+	Upload        UploadConfig        `yaml:"upload"`
+	ETP           ETPConfig           `yaml:"etp"`
+	OutboundHTTP  OutboundHTTPConfig  `yaml:"outbound_http"`
+	Consistency   ConsistencyConfig   `yaml:"consistency"`
+	Dashboard     DashboardConfig     `yaml:"dashboard"`
+	Anomalies     AnomaliesConfig     `yaml:"anomalies"`
+	Currency      CurrencyConfig      `yaml:"currency"`
+	HTTPCache     HTTPCacheConfig     `yaml:"http_cache"`
+	RefCache      RefCacheConfig      `yaml:"ref_cache"`
+	Webhooks      WebhooksConfig      `yaml:"webhooks"`
+	Outbox        OutboxConfig        `yaml:"outbox"`
+	Reports       ReportsConfig       `yaml:"reports"`
+	Mail          MailConfig          `yaml:"mail"`
+	Notifications NotificationsConfig `yaml:"notifications"`
+	Scheduler     SchedulerConfig     `yaml:"scheduler"`
+	Retention     RetentionConfig     `yaml:"retention"`
+	Contractors   ContractorsConfig   `yaml:"contractors"`
+	Events        EventsConfig        `yaml:"events"`
+	Live          LiveConfig          `yaml:"live"`
+	GRPC          GRPCConfig          `yaml:"grpc"`
 }
 
 var instance *Config
 var once sync.Once
 
+// GetConfig загружает конфигурацию один раз за время жизни процесса.
+// Профиль берётся из APP_ENV, порядок слоёв описан в profiles.go.
 func GetConfig() *Config {
 	once.Do(func() {
 		logger := logging.GetLogger()
-		logger.Info("read application configuration")
-		instance = &Config{}
-		if err := cleanenv.ReadConfig("./cmd/config/config.yml", instance); err != nil {
-			help, _ := cleanenv.GetDescription(instance, nil)
+		profile := os.Getenv(ProfileEnvVar)
+		logger.Infof("read application configuration (profile: %q)", profile)
+
+		cfg, applied, err := Load(DefaultConfigDir, profile)
+		if err != nil {
+			help, _ := cleanenv.GetDescription(&Config{}, nil)
 			logger.Info(help)
 			logger.Fatal(err)
 		}
+		logger.Infof("configuration layers applied: %v (+ environment variables)", applied)
 
-		// Валидация auth конфигурации
-		isDebug := instance.IsDebug != nil && *instance.IsDebug
-		if err := instance.Auth.Validate(isDebug); err != nil {
-			logger.Fatal("invalid auth configuration: ", err)
-		}
+		instance = cfg
 	})
 
 	return instance
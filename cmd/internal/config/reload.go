@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// reloadableSections — секции верхнего уровня, которые процесс применяет по
+// SIGHUP без перезапуска (cmd/main/reload.go). Остальные изменения вступают в
+// силу только после перезапуска.
+var reloadableSections = map[string]bool{
+	"cors":       true,
+	"rate_limit": true,
+	"log":        true,
+}
+
+// RestartRequired возвращает отсортированные имена секций, которые в next
+// отличаются от cur, но применяются только при перезапуске.
+func RestartRequired(cur, next *Config) ([]string, error) {
+	curSections, err := topLevelSections(cur)
+	if err != nil {
+		return nil, err
+	}
+	nextSections, err := topLevelSections(next)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for name, value := range nextSections {
+		if !reloadableSections[name] && !reflect.DeepEqual(curSections[name], value) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range curSections {
+		if _, ok := nextSections[name]; !ok && !reloadableSections[name] {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// topLevelSections раскладывает конфигурацию по ключам верхнего уровня YAML.
+// Секреты JWT по SIGHUP ротируются (см. cmd/main/reload.go) и в сравнении не участвуют.
+func topLevelSections(c *Config) (map[string]any, error) {
+	masked := *c
+	masked.Auth.JWTSecret = ""
+	masked.Auth.JWTSecretPrevious = ""
+	out, err := yaml.Marshal(&masked)
+	if err != nil {
+		return nil, fmt.Errorf("marshal config: %w", err)
+	}
+	var sections map[string]any
+	if err := yaml.Unmarshal(out, &sections); err != nil {
+		return nil, fmt.Errorf("unmarshal config: %w", err)
+	}
+	return sections, nil
+}
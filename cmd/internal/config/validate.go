@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ValidationError собирает все ошибки конфигурации, найденные при запуске:
+// несколько опечаток видны за один запуск, а не по одной на перезапуск.
+type ValidationError struct {
+	Errors []error
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid configuration (%d problems):", len(e.Errors))
+	for _, err := range e.Errors {
+		b.WriteString("\n  - ")
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+// Unwrap позволяет искать отдельные ошибки через errors.Is / errors.As.
+func (e *ValidationError) Unwrap() []error {
+	return e.Errors
+}
+
+// Validate проверяет все секции и возвращает *ValidationError со всеми
+// найденными ошибками или nil. Заполняет вычисляемые поля (Auth.AccessTokenTTL,
+// Auth.RefreshTokenTTL), поэтому вызывается до использования конфигурации.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(section string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s configuration: %w", section, err))
+		}
+	}
+
+	if port, err := strconv.Atoi(c.Listen.Port); err != nil || port < 1 || port > 65535 {
+		check("listen", fmt.Errorf("port must be a number between 1 and 65535 (got: %q)", c.Listen.Port))
+	}
+	check("database", c.Database.Validate())
+	check("secrets", c.Secrets.Validate())
+	check("auth", c.Auth.Validate(c.IsDebug != nil && *c.IsDebug))
+	// Секреты из file/vault ещё не получены: их проверяет secrets.Apply
+	if c.Secrets.Provider == SecretsProviderEnv {
+		errs = append(errs, c.secretErrors()...)
+	}
+	check("cors", c.CORS.Validate())
+	check("rate_limit", c.RateLimit.Validate())
+	check("log", c.Log.Validate())
+	if u, err := url.Parse(c.Services.ParserService.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		check("services", fmt.Errorf("parser_service.url must be an absolute http(s) URL"))
+	}
+	if c.Health.ReadinessTimeout <= 0 {
+		check("health", fmt.Errorf("readiness_timeout must be positive"))
+	}
+	check("import", c.Import.Validate())
+	// Параллельный импорт держит соединение блокировки и по соединению на воркер
+	if c.Import.LotWorkers > 1 && c.Database.MaxOpenConns < c.Import.LotWorkers+1 {
+		check("import", fmt.Errorf("lot_workers (%d) requires database.max_open_conns of at least lot_workers + 1 (got: %d)", c.Import.LotWorkers, c.Database.MaxOpenConns))
+	}
+	check("upload", c.Upload.Validate())
+	check("etp", c.ETP.Validate())
+	check("outbound_http", c.OutboundHTTP.Validate())
+	if c.Consistency.AbsTolerance < 0 || c.Consistency.RelTolerance < 0 {
+		check("consistency", fmt.Errorf("abs_tolerance and rel_tolerance must not be negative"))
+	}
+	check("dashboard", c.Dashboard.Validate())
+	check("anomalies", c.Anomalies.Validate())
+	check("currency", c.Currency.Validate())
+	check("http_cache", c.HTTPCache.Validate())
+	check("ref_cache", c.RefCache.Validate())
+	if c.Scheduler.RenormalizationInterval <= 0 || c.Scheduler.RenormalizationBatchSize <= 0 {
+		check("scheduler", fmt.Errorf("renormalization_interval and renormalization_batch_size must be positive"))
+	}
+	if c.Scheduler.LockDriver != "postgres" && c.Scheduler.LockDriver != "none" {
+		check("scheduler", fmt.Errorf("lock_driver must be one of: postgres, none (got: %s)", c.Scheduler.LockDriver))
+	}
+	check("retention", c.Retention.Validate())
+	check("contractors", c.Contractors.Validate())
+	check("webhooks", c.Webhooks.Validate())
+	check("outbox", c.Outbox.Validate())
+	check("reports", c.Reports.Validate())
+	if c.Reports.Enabled && !c.Mail.Enabled() {
+		check("reports", fmt.Errorf("mail.host is required when reports are enabled"))
+	}
+	check("mail", c.Mail.Validate())
+	check("notifications", c.Notifications.Validate())
+	check("events", c.Events.Validate())
+	check("live", c.Live.Validate())
+	check("grpc", c.GRPC.Validate())
+
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// ValidateSecrets проверяет секреты после того, как их заполнил провайдер
+// (secrets.Apply): jwt_secret, jwt_secret_previous, auth.oidc.client_secret и database.source.
+func (c *Config) ValidateSecrets() error {
+	if errs := c.secretErrors(); len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+func (c *Config) secretErrors() []error {
+	var errs []error
+	if err := c.Auth.ValidateSecrets(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid auth configuration: %w", err))
+	}
+	if c.Database.Source == "" {
+		errs = append(errs, fmt.Errorf("invalid database configuration: source is required"))
+	}
+	return errs
+}
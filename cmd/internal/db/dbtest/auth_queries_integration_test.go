@@ -166,7 +166,7 @@ func TestIntegration_CreateUser_AllRoles(t *testing.T) {
 	queries := testQueries
 
 	// Given: all valid roles
-	roles := []string{"admin", "operator", "viewer"}
+	roles := []string{"admin", "editor", "analyst", "viewer"}
 
 	for _, role := range roles {
 		t.Run(role, func(t *testing.T) {
@@ -322,7 +322,7 @@ func TestIntegration_GetUserByID_Success(t *testing.T) {
 	queries := testQueries
 
 	// Given: an existing user
-	created := createTestUserInDB(t, queries, "byid@example.com", "operator", true)
+	created := createTestUserInDB(t, queries, "byid@example.com", "editor", true)
 
 	// When: retrieving by ID
 	user, err := queries.GetUserByID(context.Background(), created.ID)
@@ -331,7 +331,7 @@ func TestIntegration_GetUserByID_Success(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, created.ID, user.ID)
 	assert.Equal(t, "byid@example.com", user.Email)
-	assert.Equal(t, "operator", user.Role)
+	assert.Equal(t, "editor", user.Role)
 	assert.True(t, user.IsActive)
 }
 
@@ -466,11 +466,13 @@ func TestIntegration_UpdateUserRole(t *testing.T) {
 	created := createTestUserInDB(t, queries, "role@example.com", "viewer", true)
 
 	// When: promoting to admin
-	err := queries.UpdateUserRole(context.Background(), db.UpdateUserRoleParams{
+	row, err := queries.UpdateUserRole(context.Background(), db.UpdateUserRoleParams{
 		Role: "admin",
 		ID:   created.ID,
 	})
 	require.NoError(t, err)
+	assert.Equal(t, "admin", row.Role)
+	assert.True(t, row.TokensRevokedAt.Valid, "role change must mark issued tokens as revoked")
 
 	// Then: role is updated
 	updated, err := queries.GetUserByID(context.Background(), created.ID)
@@ -478,6 +480,20 @@ func TestIntegration_UpdateUserRole(t *testing.T) {
 	assert.Equal(t, "admin", updated.Role)
 }
 
+func TestIntegration_UpdateUserRole_NotFound(t *testing.T) {
+	cleanupUsers(t)
+	queries := testQueries
+
+	// When: updating a non-existent user
+	_, err := queries.UpdateUserRole(context.Background(), db.UpdateUserRoleParams{
+		Role: "admin",
+		ID:   999999,
+	})
+
+	// Then: no rows
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
 func TestIntegration_UpdateUserRole_InvalidRole(t *testing.T) {
 	cleanupUsers(t)
 	queries := testQueries
@@ -486,7 +502,7 @@ func TestIntegration_UpdateUserRole_InvalidRole(t *testing.T) {
 	created := createTestUserInDB(t, queries, "badrole@example.com", "viewer", true)
 
 	// When: updating to invalid role
-	err := queries.UpdateUserRole(context.Background(), db.UpdateUserRoleParams{
+	_, err := queries.UpdateUserRole(context.Background(), db.UpdateUserRoleParams{
 		Role: "superadmin",
 		ID:   created.ID,
 	})
@@ -525,11 +541,12 @@ func TestIntegration_UpdateUserActiveStatus(t *testing.T) {
 	created := createTestUserInDB(t, queries, "active@example.com", "admin", true)
 
 	// When: deactivating the user
-	err := queries.UpdateUserActiveStatus(context.Background(), db.UpdateUserActiveStatusParams{
+	row, err := queries.UpdateUserActiveStatus(context.Background(), db.UpdateUserActiveStatusParams{
 		IsActive: false,
 		ID:       created.ID,
 	})
 	require.NoError(t, err)
+	assert.True(t, row.TokensRevokedAt.Valid, "deactivation must mark issued tokens as revoked")
 
 	// Then: user is inactive
 	user, err := queries.GetUserByID(context.Background(), created.ID)
@@ -537,7 +554,7 @@ func TestIntegration_UpdateUserActiveStatus(t *testing.T) {
 	assert.False(t, user.IsActive)
 
 	// When: reactivating the user
-	err = queries.UpdateUserActiveStatus(context.Background(), db.UpdateUserActiveStatusParams{
+	_, err = queries.UpdateUserActiveStatus(context.Background(), db.UpdateUserActiveStatusParams{
 		IsActive: true,
 		ID:       created.ID,
 	})
@@ -1015,8 +1032,9 @@ func TestIntegration_RevokeAllActiveSessionsByUserID(t *testing.T) {
 	assert.Len(t, sessions, 3)
 
 	// When: revoking all sessions
-	err = queries.RevokeAllActiveSessionsByUserID(context.Background(), user.ID)
+	revoked, err := queries.RevokeAllActiveSessionsByUserID(context.Background(), user.ID)
 	require.NoError(t, err)
+	assert.Equal(t, int64(3), revoked)
 
 	// Then: no active sessions remain
 	sessions, err = queries.GetActiveSessionsByUserID(context.Background(), user.ID)
@@ -1024,6 +1042,35 @@ func TestIntegration_RevokeAllActiveSessionsByUserID(t *testing.T) {
 	assert.Empty(t, sessions)
 }
 
+func TestIntegration_ListUserTokenRevocationsSince(t *testing.T) {
+	cleanupUsers(t)
+	queries := testQueries
+
+	// Given: two users, only one of them had a role change
+	changed := createTestUserInDB(t, queries, "revoked-tokens@example.com", "viewer", true)
+	createTestUserInDB(t, queries, "untouched@example.com", "viewer", true)
+
+	since := time.Now().Add(-time.Minute)
+	_, err := queries.UpdateUserRole(context.Background(), db.UpdateUserRoleParams{
+		Role: "editor",
+		ID:   changed.ID,
+	})
+	require.NoError(t, err)
+
+	// When: listing revocations in the window
+	rows, err := queries.ListUserTokenRevocationsSince(context.Background(), since)
+	require.NoError(t, err)
+
+	// Then: only the changed user is returned
+	require.Len(t, rows, 1)
+	assert.Equal(t, changed.ID, rows[0].ID)
+
+	// And: a window after the change is empty
+	rows, err = queries.ListUserTokenRevocationsSince(context.Background(), time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, rows)
+}
+
 // ============================================================================
 // SECTION 9: DeleteExpiredSessions
 // ============================================================================
@@ -1093,7 +1140,7 @@ func TestIntegration_ExecTx_CreateUserAndSession(t *testing.T) {
 		user, err := q.CreateUser(context.Background(), db.CreateUserParams{
 			Email:        "tx-user@example.com",
 			PasswordHash: testutil.TestPasswordHash,
-			Role:         "operator",
+			Role:         "editor",
 			IsActive:     true,
 		})
 		if err != nil {
@@ -1135,7 +1182,7 @@ func TestIntegration_ExecTx_RollbackOnError(t *testing.T) {
 		user, err := q.CreateUser(context.Background(), db.CreateUserParams{
 			Email:        "rollback@example.com",
 			PasswordHash: testutil.TestPasswordHash,
-			Role:         "operator",
+			Role:         "editor",
 			IsActive:     true,
 		})
 		if err != nil {
@@ -1,3 +1,4 @@
-// Package dbtest contains integration tests for SQLC-generated database queries.
+// Package dbtest contains integration tests for SQLC-generated database queries
+// and benchmarks of the hottest queries (make bench-db).
 // Tests in this package require Docker and are run with: go test -tags integration
 package dbtest
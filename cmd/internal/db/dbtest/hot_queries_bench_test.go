@@ -0,0 +1,155 @@
+// Purpose: Measures the hottest sqlc queries against a real PostgreSQL database
+// with and without prepared statements (database.query_exec_mode).
+
+//go:build integration
+
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/db/postgres"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+)
+
+/*
+BENCHMARKS FOR HOT QUERIES (Integration)
+
+Benchmarks (go test -tags integration -run '^$' -bench HotQueries ./cmd/internal/db/dbtest, make bench-db):
+- BenchmarkHotQueries/<mode>/<query> runs the query through postgres.Open with
+  database.query_exec_mode = <mode>:
+    cache_statement — prepared once per connection, then executed by name (default)
+    cache_describe  — unnamed statement, cached parameter and result types
+    exec            — unnamed statement, no caching
+    simple_protocol — plain text query, parameters interpolated by pgx
+- Queries: UpsertPositionItem (import, 100 rotating keys → ON CONFLICT update),
+  GetCatalogPositionByTitleAndUnit (import, position lookup),
+  GetMatchingCache (matching cache lookup)
+*/
+
+// benchQueryExecModes — сравниваемые режимы database.query_exec_mode.
+var benchQueryExecModes = []string{"cache_statement", "cache_describe", "exec", "simple_protocol"}
+
+// hotQueriesFixture — строки, на которые ссылаются горячие запросы.
+type hotQueriesFixture struct {
+	proposalID    int64
+	unitID        int64
+	positionTitle string
+	jobTitleHash  string
+}
+
+var (
+	hotFixtureOnce sync.Once
+	hotFixture     hotQueriesFixture
+	hotFixtureErr  error
+)
+
+// seedHotQueries один раз создаёт тендер с лотом и предложением, позицию
+// каталога с единицей измерения и запись кэша сопоставления.
+func seedHotQueries(b *testing.B) hotQueriesFixture {
+	b.Helper()
+	hotFixtureOnce.Do(func() {
+		f := hotQueriesFixture{positionTitle: "бетонирование плиты перекрытия", jobTitleHash: "bench-hash"}
+		insert := func(query string, args ...any) int64 {
+			var id int64
+			if hotFixtureErr == nil {
+				hotFixtureErr = testDB.QueryRowContext(context.Background(), query+" RETURNING id", args...).Scan(&id)
+			}
+			return id
+		}
+
+		f.unitID = insert(`INSERT INTO units_of_measurement (normalized_name) VALUES ('bench-м3')`)
+		catalogPositionID := insert(`INSERT INTO catalog_positions (standard_job_title, unit_id, kind) VALUES ($1, $2, 'POSITION')`,
+			f.positionTitle, f.unitID)
+		objectID := insert(`INSERT INTO objects (title, address) VALUES ('bench-object', 'г. Москва')`)
+		executorID := insert(`INSERT INTO executors (name, phone) VALUES ('bench-executor', '+7-000')`)
+		tenderID := insert(`INSERT INTO tenders (etp_id, title, object_id, executor_id, organization_id)
+			VALUES ('BENCH-HOT', 'bench-tender', $1, $2, (SELECT id FROM organizations WHERE is_default))`, objectID, executorID)
+		lotID := insert(`INSERT INTO lots (lot_key, lot_title, tender_id) VALUES ('lot-1', 'bench-lot', $1)`, tenderID)
+		contractorID := insert(`INSERT INTO contractors (title, inn, address) VALUES ('bench-contractor', 'bench-inn', '-')`)
+		f.proposalID = insert(`INSERT INTO proposals (lot_id, contractor_id) VALUES ($1, $2)`, lotID, contractorID)
+		if hotFixtureErr == nil {
+			_, hotFixtureErr = testDB.ExecContext(context.Background(),
+				`INSERT INTO matching_cache (job_title_hash, norm_version, job_title_text, catalog_position_id) VALUES ($1, 1, $2, $3)`,
+				f.jobTitleHash, f.positionTitle, catalogPositionID)
+		}
+		hotFixture = f
+	})
+	require.NoError(b, hotFixtureErr)
+	return hotFixture
+}
+
+// openBenchDB открывает отдельный пул с заданным query_exec_mode, как cmd/main.
+func openBenchDB(b *testing.B, mode string) *sql.DB {
+	b.Helper()
+	conn, closeDB, err := postgres.Open(context.Background(), config.DatabaseConfig{
+		Driver:                 "pgx",
+		Source:                 testContainer.DSN,
+		MaxOpenConns:           4,
+		MinConns:               4,
+		QueryExecMode:          mode,
+		StatementCacheCapacity: 512,
+	})
+	require.NoError(b, err)
+	b.Cleanup(closeDB)
+	return conn
+}
+
+func BenchmarkHotQueries(b *testing.B) {
+	fixture := seedHotQueries(b)
+	ctx := context.Background()
+
+	for _, mode := range benchQueryExecModes {
+		queries := db.New(openBenchDB(b, mode))
+
+		b.Run(mode+"/UpsertPositionItem", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, err := queries.UpsertPositionItem(ctx, db.UpsertPositionItemParams{
+					ProposalID:            fixture.proposalID,
+					PositionKeyInProposal: fmt.Sprintf("pos-%d", i%100),
+					JobTitleInProposal:    fixture.positionTitle,
+					UnitID:                sql.NullInt64{Int64: fixture.unitID, Valid: true},
+					Quantity:              sql.NullString{String: "12.5", Valid: true},
+					UnitCostTotal:         sql.NullString{String: fmt.Sprintf("%d.00", 1000+i%7), Valid: true},
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(mode+"/GetCatalogPositionByTitleAndUnit", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, err := queries.GetCatalogPositionByTitleAndUnit(ctx, db.GetCatalogPositionByTitleAndUnitParams{
+					StandardJobTitle: fixture.positionTitle,
+					UnitID:           sql.NullInt64{Int64: fixture.unitID, Valid: true},
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(mode+"/GetMatchingCache", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, err := queries.GetMatchingCache(ctx, db.GetMatchingCacheParams{
+					JobTitleHash: fixture.jobTitleHash,
+					NormVersion:  1,
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
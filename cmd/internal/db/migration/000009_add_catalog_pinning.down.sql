@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_catalog_positions_pinned;
+
+ALTER TABLE catalog_positions
+DROP COLUMN IF EXISTS pinned_by,
+DROP COLUMN IF EXISTS pinned_at,
+DROP COLUMN IF EXISTS is_pinned;
@@ -0,0 +1,16 @@
+-- =====================================================================================
+-- Migration 000009: Add Catalog Position Pinning
+-- =====================================================================================
+-- Администратор может пометить позицию каталога как "закреплённую" (авторитетную).
+-- Флаг отдаётся Python-ранжировщику вместе с каталогом и используется как
+-- supervised prior при выборе кандидатов для матчинга.
+
+ALTER TABLE catalog_positions
+ADD COLUMN is_pinned BOOLEAN NOT NULL DEFAULT false,
+ADD COLUMN pinned_at TIMESTAMPTZ NULL,
+ADD COLUMN pinned_by TEXT NULL;
+
+-- Частичный индекс: закреплённых позиций единицы, выборка по ним должна быть мгновенной
+CREATE INDEX idx_catalog_positions_pinned
+ON catalog_positions(id)
+WHERE is_pinned = true;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_tender_chapters_type_id;
+DROP INDEX IF EXISTS idx_tender_categories_chapter_id;
+DROP INDEX IF EXISTS idx_tenders_object_id;
+DROP INDEX IF EXISTS idx_tenders_executor_id;
+DROP INDEX IF EXISTS idx_tenders_category_id;
+DROP INDEX IF EXISTS idx_tenders_data_prepared_on_date;
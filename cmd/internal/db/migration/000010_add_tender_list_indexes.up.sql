@@ -0,0 +1,11 @@
+-- =====================================================================================
+-- Migration 000010: Indexes for Tender List Filtering and Sorting
+-- =====================================================================================
+-- Поддержка фильтров и сортировки GET /api/v1/tenders (ListTenders / CountTenders).
+
+CREATE INDEX IF NOT EXISTS idx_tenders_data_prepared_on_date ON tenders (data_prepared_on_date DESC);
+CREATE INDEX IF NOT EXISTS idx_tenders_category_id ON tenders (category_id);
+CREATE INDEX IF NOT EXISTS idx_tenders_executor_id ON tenders (executor_id);
+CREATE INDEX IF NOT EXISTS idx_tenders_object_id ON tenders (object_id);
+CREATE INDEX IF NOT EXISTS idx_tender_categories_chapter_id ON tender_categories (tender_chapter_id);
+CREATE INDEX IF NOT EXISTS idx_tender_chapters_type_id ON tender_chapters (tender_type_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_service_credentials_active;
+DROP TABLE IF EXISTS service_credentials;
@@ -0,0 +1,24 @@
+-- =====================================================================================
+-- Migration 000011: Service Credentials Store
+-- =====================================================================================
+-- Bearer-ключи внутренних сервисов (python-worker и др.) переезжают из переменной
+-- окружения в БД. API держит активные ключи в памяти и периодически перечитывает их,
+-- поэтому ротация ключа не требует перезапуска сервера.
+--
+-- Хранится только SHA-256 хеш ключа; сам ключ показывается один раз при создании.
+
+CREATE TABLE service_credentials (
+    id           BIGSERIAL PRIMARY KEY,
+    service_name TEXT NOT NULL,
+    key_hash     TEXT NOT NULL UNIQUE,              -- hex(sha256(key))
+    key_prefix   TEXT NOT NULL,                     -- первые символы ключа, для идентификации в UI/логах
+    description  TEXT,
+    created_by   TEXT NOT NULL DEFAULT 'system',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    revoked_at   TIMESTAMPTZ NULL
+);
+
+-- Кэш перечитывает только активные ключи
+CREATE INDEX idx_service_credentials_active
+ON service_credentials(service_name)
+WHERE revoked_at IS NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE position_items
+    DROP COLUMN IF EXISTS article_smr;
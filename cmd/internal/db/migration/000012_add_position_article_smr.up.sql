@@ -0,0 +1,8 @@
+-- =====================================================================================
+-- Migration 000012: Article SMR for Position Items
+-- =====================================================================================
+-- Парсер присылает article_smr (статья СМР) для каждой позиции, но раньше это поле
+-- терялось при импорте. Колонка nullable: в старых импортах значения нет.
+
+ALTER TABLE position_items
+    ADD COLUMN IF NOT EXISTS article_smr TEXT;
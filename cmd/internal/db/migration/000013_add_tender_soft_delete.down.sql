@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_tenders_active_date;
+
+ALTER TABLE tenders
+    DROP COLUMN IF EXISTS deleted_by,
+    DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,15 @@
+-- =====================================================================================
+-- Migration 000013: Soft Delete for Tenders
+-- =====================================================================================
+-- DELETE /api/v1/tenders/:id больше не удаляет строку, а проставляет deleted_at.
+-- Лоты, предложения и позиции удалённого тендера не трогаются: они скрываются
+-- вместе с тендером и полностью возвращаются при восстановлении.
+
+ALTER TABLE tenders
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS deleted_by TEXT;
+
+-- Большинство запросов читают только активные тендеры
+CREATE INDEX IF NOT EXISTS idx_tenders_active_date
+    ON tenders (data_prepared_on_date DESC)
+    WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE position_items
+    DROP COLUMN IF EXISTS matched_at,
+    DROP COLUMN IF EXISTS matched_by;
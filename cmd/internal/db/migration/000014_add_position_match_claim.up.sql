@@ -0,0 +1,13 @@
+-- =====================================================================================
+-- Migration 000014: Position Match Claim
+-- =====================================================================================
+-- Несколько экземпляров Python-воркера могут одновременно сопоставить одну и ту же
+-- позицию с разными записями каталога. matched_by хранит идентификатор воркера,
+-- который первым закрепил результат; SetCatalogPositionID применяет результат
+-- только если позиция ещё не закреплена или закреплена тем же воркером.
+
+ALTER TABLE position_items
+    ADD COLUMN IF NOT EXISTS matched_by TEXT,
+    ADD COLUMN IF NOT EXISTS matched_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN position_items.matched_by IS 'Идентификатор воркера, закрепившего сопоставление (NULL — не закреплено)';
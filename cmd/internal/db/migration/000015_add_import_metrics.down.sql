@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_import_metrics_tender;
+DROP TABLE IF EXISTS import_metrics;
@@ -0,0 +1,39 @@
+-- =====================================================================================
+-- Migration 000015: Import Metrics
+-- =====================================================================================
+-- Тайминги этапов каждого импорта тендера. По ним видно, на что ушло время
+-- медленного импорта: ожидание соединения/коммита (конкуренция в БД), обращения
+-- к matching_cache или просто большой объём данных.
+-- Запись делается и для неудачных импортов; tender_id у них NULL, так как
+-- транзакция откатилась.
+
+CREATE TABLE IF NOT EXISTS import_metrics (
+    id              BIGSERIAL PRIMARY KEY,
+    tender_id       BIGINT REFERENCES tenders(id) ON DELETE CASCADE,
+    etp_id          TEXT NOT NULL,
+    status          TEXT NOT NULL CHECK (status IN ('success', 'failed')),
+    error_message   TEXT,
+    payload_bytes   BIGINT NOT NULL,
+    lots_count      INT NOT NULL,
+    positions_count INT NOT NULL,
+    cache_hits      INT NOT NULL,
+    cache_misses    INT NOT NULL,
+    tx_wait_ms      DOUBLE PRECISION NOT NULL,
+    core_ms         DOUBLE PRECISION NOT NULL,
+    lots_ms         DOUBLE PRECISION NOT NULL,
+    positions_ms    DOUBLE PRECISION NOT NULL,
+    cache_lookup_ms DOUBLE PRECISION NOT NULL,
+    raw_data_ms     DOUBLE PRECISION NOT NULL,
+    commit_ms       DOUBLE PRECISION NOT NULL,
+    total_ms        DOUBLE PRECISION NOT NULL,
+    lot_timings     JSONB NOT NULL DEFAULT '[]'::jsonb,
+    started_at      TIMESTAMPTZ NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_metrics_tender ON import_metrics(tender_id, started_at DESC);
+
+COMMENT ON TABLE import_metrics IS 'Тайминги этапов импорта тендера (трассировка медленных импортов)';
+COMMENT ON COLUMN import_metrics.tx_wait_ms IS 'Ожидание начала транзакции (свободное соединение из пула)';
+COMMENT ON COLUMN import_metrics.positions_ms IS 'Суммарное время обработки позиций, включая cache_lookup_ms';
+COMMENT ON COLUMN import_metrics.lot_timings IS 'Тайминги по лотам: [{lot_key, duration_ms, proposals_count, positions_count}]';
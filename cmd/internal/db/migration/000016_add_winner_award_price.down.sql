@@ -0,0 +1,2 @@
+ALTER TABLE winners
+    DROP COLUMN IF EXISTS award_price;
@@ -0,0 +1,21 @@
+-- =====================================================================================
+-- Migration 000016: Winner Award Price
+-- =====================================================================================
+-- Цена победителя раньше бралась строкой из proposal_summary_lines.total_cost
+-- (NUMERIC без масштаба) и могла прийти с любым числом знаков. Теперь у победителя
+-- своя цена контракта с точностью до копеек. Для существующих записей она
+-- заполняется итогом предложения с НДС, округлённым до копеек.
+
+ALTER TABLE winners
+    ADD COLUMN IF NOT EXISTS award_price NUMERIC(18,2)
+        CONSTRAINT chk_winners_award_price_non_negative CHECK (award_price >= 0);
+
+UPDATE winners w
+SET award_price = ROUND(psl.total_cost, 2)
+FROM proposal_summary_lines psl
+WHERE psl.proposal_id = w.proposal_id
+  AND psl.summary_key = 'total_cost_with_vat'
+  AND psl.total_cost >= 0
+  AND w.award_price IS NULL;
+
+COMMENT ON COLUMN winners.award_price IS 'Цена контракта победителя в рублях с копейками';
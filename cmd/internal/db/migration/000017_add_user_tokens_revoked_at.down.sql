@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_users_tokens_revoked_at;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS tokens_revoked_at;
@@ -0,0 +1,14 @@
+-- =====================================================================================
+-- Migration 000017: User Tokens Revoked At
+-- =====================================================================================
+-- Момент последнего принудительного отзыва токенов пользователя (смена роли,
+-- деактивация). Access-токены, выпущенные не позже этого момента, отклоняются
+-- middleware до истечения их срока. Значение читается всеми экземплярами API,
+-- чтобы отзыв действовал не только в процессе, который его выполнил.
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS tokens_revoked_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_users_tokens_revoked_at
+    ON users (tokens_revoked_at)
+    WHERE tokens_revoked_at IS NOT NULL;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS tender_raw_data_history;
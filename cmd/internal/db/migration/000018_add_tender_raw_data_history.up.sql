@@ -0,0 +1,35 @@
+-- =====================================================================================
+-- Migration 000018: Tender Raw Data History
+-- =====================================================================================
+-- tender_raw_data хранит только последний JSON тендера: UpsertTenderRawData
+-- перезаписывает его при каждом импорте. История хранит каждую загрузку с
+-- порядковым номером версии, чтобы сравнивать снимки при регрессиях парсера.
+-- Версия назначается в транзакции импорта после UpsertTender, который блокирует
+-- строку тендера, поэтому параллельные импорты одного тендера не получат
+-- одинаковый номер.
+
+CREATE TABLE IF NOT EXISTS tender_raw_data_history (
+    tender_id     BIGINT NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+    version       INT NOT NULL,
+    raw_data      JSONB NOT NULL,
+    payload_hash  TEXT NOT NULL,
+    payload_bytes BIGINT NOT NULL,
+    imported_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tender_id, version)
+);
+
+-- Текущие снимки становятся версией 1. Хэш считается от текста JSONB, а не от
+-- исходного тела запроса, поэтому с хэшем следующего импорта он может не совпасть.
+INSERT INTO tender_raw_data_history (tender_id, version, raw_data, payload_hash, payload_bytes, imported_at)
+SELECT tender_id,
+       1,
+       raw_data,
+       encode(sha256(convert_to(raw_data::text, 'UTF8')), 'hex'),
+       octet_length(raw_data::text),
+       updated_at
+FROM tender_raw_data
+ON CONFLICT DO NOTHING;
+
+COMMENT ON TABLE tender_raw_data_history IS 'Все загруженные версии исходного JSON тендера';
+COMMENT ON COLUMN tender_raw_data_history.payload_hash IS 'SHA-256 (hex) тела запроса импорта';
+COMMENT ON COLUMN tender_raw_data_history.payload_bytes IS 'Размер тела запроса импорта в байтах';
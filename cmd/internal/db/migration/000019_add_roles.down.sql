@@ -0,0 +1,11 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS fk_users_role;
+
+-- analyst не существовал до 000019: ближайшая роль с доступом на чтение — viewer
+UPDATE users SET role = 'operator' WHERE role = 'editor';
+UPDATE users SET role = 'viewer' WHERE role = 'analyst';
+
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'operator';
+ALTER TABLE users
+    ADD CONSTRAINT chk_users_role CHECK (role IN ('admin', 'operator', 'viewer'));
+
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,27 @@
+-- =====================================================================================
+-- Migration 000019: Roles
+-- =====================================================================================
+-- Вместо CHECK (admin, operator, viewer) набор ролей хранится в таблице roles,
+-- а users.role ссылается на неё. Матрица прав роли задаётся в коде
+-- (services/auth/permissions.go); таблица фиксирует допустимые значения.
+-- Роль operator переименована в editor; добавлена роль analyst.
+
+CREATE TABLE IF NOT EXISTS roles (
+    name        VARCHAR(50) PRIMARY KEY,
+    description TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO roles (name, description) VALUES
+    ('admin',   'Полный доступ: пользователи, каталог, системные настройки, удалённые тендеры'),
+    ('editor',  'Загрузка и правка тендеров, победители, справочники, аналитика'),
+    ('analyst', 'Просмотр данных и аналитика, без изменений'),
+    ('viewer',  'Только просмотр тендеров и справочников')
+ON CONFLICT (name) DO NOTHING;
+
+UPDATE users SET role = 'editor' WHERE role = 'operator';
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS chk_users_role;
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'editor';
+ALTER TABLE users
+    ADD CONSTRAINT fk_users_role FOREIGN KEY (role) REFERENCES roles(name);
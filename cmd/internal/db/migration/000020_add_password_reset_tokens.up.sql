@@ -0,0 +1,25 @@
+-- =====================================================================================
+-- Migration 000020: Password Reset Tokens
+-- =====================================================================================
+-- Одноразовые токены сброса пароля, которые выдаёт администратор
+-- (POST /api/v1/admin/users/:id/reset-password). Как и refresh-токены,
+-- хранится только SHA-256 хеш; сам токен показывается администратору один раз.
+-- Новый токен аннулирует предыдущие неиспользованные токены пользователя.
+
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash  CHAR(64) NOT NULL,
+    created_by  BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at  TIMESTAMPTZ NOT NULL,
+    used_at     TIMESTAMPTZ,
+
+    CONSTRAINT uq_password_reset_tokens_hash UNIQUE (token_hash),
+    CONSTRAINT chk_password_reset_tokens_expires_at CHECK (expires_at > created_at),
+    CONSTRAINT chk_password_reset_tokens_hash_hex CHECK (token_hash ~ '^[0-9a-f]{64}$')
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_unused
+    ON password_reset_tokens (user_id)
+    WHERE used_at IS NULL;
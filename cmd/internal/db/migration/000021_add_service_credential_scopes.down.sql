@@ -0,0 +1,6 @@
+ALTER TABLE service_credentials DROP CONSTRAINT IF EXISTS chk_service_credentials_scopes;
+
+ALTER TABLE service_credentials
+    DROP COLUMN IF EXISTS rotated_from_id,
+    DROP COLUMN IF EXISTS last_used_at,
+    DROP COLUMN IF EXISTS scopes;
@@ -0,0 +1,27 @@
+-- =====================================================================================
+-- Migration 000021: Service Credential Scopes
+-- =====================================================================================
+-- Ключи service_credentials становятся ключами отдельных воркеров: service_name —
+-- имя воркера (parser-1, rag-worker, …), scopes — группы роутов /internal/worker,
+-- к которым ключ открывает доступ:
+--   import      — POST /import-tender
+--   rag         — matching, индексация каталога, merge-предложения
+--   ai-results  — POST /lots/:lot_id/ai-results
+--
+-- Существующие ключи получают все scopes, чтобы текущий python-worker продолжил
+-- работать; у новых ключей scopes задаются явно.
+--
+-- last_used_at обновляется пачкой из in-memory кэша (не на каждый запрос),
+-- поэтому отстаёт от фактического использования на интервал обновления кэша.
+-- rotated_from_id связывает ключ, выпущенный ротацией, с отозванным предшественником.
+
+ALTER TABLE service_credentials
+    ADD COLUMN scopes TEXT[] NOT NULL DEFAULT ARRAY['import', 'rag', 'ai-results'],
+    ADD COLUMN last_used_at TIMESTAMPTZ NULL,
+    ADD COLUMN rotated_from_id BIGINT NULL REFERENCES service_credentials(id) ON DELETE SET NULL;
+
+ALTER TABLE service_credentials ALTER COLUMN scopes DROP DEFAULT;
+
+ALTER TABLE service_credentials
+    ADD CONSTRAINT chk_service_credentials_scopes
+    CHECK (cardinality(scopes) > 0 AND scopes <@ ARRAY['import', 'rag', 'ai-results']);
@@ -0,0 +1,56 @@
+-- =====================================================================================
+-- Migration 000022: Webhooks
+-- =====================================================================================
+-- Внешние системы подписываются на события (импорт тендера, AI-результаты лота,
+-- назначение победителя) и получают POST с подписью HMAC-SHA256.
+--
+-- Доставки ставятся в очередь webhook_deliveries и отправляются фоновым
+-- воркером API: неуспешная доставка повторяется с экспоненциальной задержкой,
+-- после исчерпания попыток получает статус failed. Таблица одновременно служит
+-- журналом доставок для админки.
+--
+-- secret хранится в открытом виде: он нужен для подписи каждого запроса.
+
+CREATE TABLE webhook_subscriptions (
+    id          BIGSERIAL PRIMARY KEY,
+    url         TEXT NOT NULL,
+    secret      TEXT NOT NULL,
+    events      TEXT[] NOT NULL,
+    description TEXT,
+    is_active   BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by  BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_webhook_subscriptions_url CHECK (url ~ '^https?://'),
+    CONSTRAINT chk_webhook_subscriptions_events CHECK (
+        cardinality(events) > 0
+        AND events <@ ARRAY['tender.imported', 'lot.ai_results', 'winner.set']::TEXT[]
+    )
+);
+
+CREATE TABLE webhook_deliveries (
+    id              BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event           TEXT NOT NULL,
+    payload         JSONB NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'pending',
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),  -- для pending: когда отправлять (или конец аренды воркером)
+    last_attempt_at TIMESTAMPTZ,
+    response_status INTEGER,                             -- HTTP-код последней попытки, NULL при сетевой ошибке
+    last_error      TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at    TIMESTAMPTZ,
+
+    CONSTRAINT chk_webhook_deliveries_status CHECK (status IN ('pending', 'delivered', 'failed'))
+);
+
+-- Воркер выбирает только ожидающие доставки
+CREATE INDEX idx_webhook_deliveries_due
+ON webhook_deliveries(next_attempt_at)
+WHERE status = 'pending';
+
+-- Журнал доставок подписки, новые первыми
+CREATE INDEX idx_webhook_deliveries_subscription
+ON webhook_deliveries(subscription_id, created_at DESC);
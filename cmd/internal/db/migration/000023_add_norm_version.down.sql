@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS catalog_renormalization_runs;
+DELETE FROM system_settings WHERE key = 'norm_version';
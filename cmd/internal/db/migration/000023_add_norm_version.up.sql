@@ -0,0 +1,41 @@
+-- =====================================================================================
+-- Migration 000023: Активная версия нормализации и перенормализация каталога
+-- =====================================================================================
+-- Ключи matching_cache включают norm_version — версию правил лемматизации
+-- Python-воркера. Активная версия хранится в system_settings (norm_version):
+-- импорт читает кэш только этой версии.
+--
+-- При смене правил администратор повышает версию: записи кэша старых версий
+-- удаляются, а активные позиции каталога порциями возвращаются в
+-- pending_indexing, чтобы воркер заново их нормализовал и проиндексировал.
+-- Ход перенормализации хранится в catalog_renormalization_runs; одновременно
+-- может выполняться только один запуск.
+
+INSERT INTO system_settings (key, value_numeric, description, updated_by)
+VALUES (
+    'norm_version',
+    1,
+    'Активная версия нормализации (лемматизации) для matching_cache',
+    'system'
+)
+ON CONFLICT (key) DO NOTHING;
+
+CREATE TABLE catalog_renormalization_runs (
+    id                    BIGSERIAL PRIMARY KEY,
+    norm_version          SMALLINT NOT NULL,
+    status                TEXT NOT NULL DEFAULT 'running',
+    total_positions       BIGINT NOT NULL DEFAULT 0,
+    requeued_positions    BIGINT NOT NULL DEFAULT 0,
+    last_position_id      BIGINT NOT NULL DEFAULT 0, -- Курсор: позиции с id <= last_position_id обработаны
+    cache_entries_deleted BIGINT NOT NULL DEFAULT 0,
+    started_by            BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    started_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at           TIMESTAMPTZ,
+
+    CONSTRAINT chk_catalog_renormalization_runs_status CHECK (status IN ('running', 'completed'))
+);
+
+CREATE UNIQUE INDEX uq_catalog_renormalization_runs_running
+    ON catalog_renormalization_runs ((TRUE)) WHERE status = 'running';
+CREATE INDEX idx_catalog_renormalization_runs_started_at
+    ON catalog_renormalization_runs (started_at DESC);
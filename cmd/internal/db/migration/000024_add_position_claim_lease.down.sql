@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_position_items_unmatched_queue;
+
+ALTER TABLE position_items
+    DROP COLUMN IF EXISTS claimed_until,
+    DROP COLUMN IF EXISTS claimed_by;
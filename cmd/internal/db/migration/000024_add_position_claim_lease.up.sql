@@ -0,0 +1,19 @@
+-- =====================================================================================
+-- Migration 000024: Position Claim Lease
+-- =====================================================================================
+-- Несколько экземпляров Python-воркера забирают несопоставленные позиции из одной
+-- очереди. POST /internal/worker/positions/claim выдаёт воркеру аренду на порцию
+-- позиций (claimed_by, claimed_until): пока аренда не истекла, другим воркерам эти
+-- позиции не выдаются. Истёкшая аренда не требует очистки — позиция снова доступна.
+
+ALTER TABLE position_items
+    ADD COLUMN IF NOT EXISTS claimed_by TEXT,
+    ADD COLUMN IF NOT EXISTS claimed_until TIMESTAMPTZ;
+
+COMMENT ON COLUMN position_items.claimed_by IS 'Воркер, арендовавший позицию для сопоставления (действует до claimed_until)';
+COMMENT ON COLUMN position_items.claimed_until IS 'Окончание аренды позиции воркером (NULL или в прошлом — позиция свободна)';
+
+-- Очередь несопоставленных позиций читается по курсору id
+CREATE INDEX IF NOT EXISTS idx_position_items_unmatched_queue
+    ON position_items (id)
+    WHERE catalog_position_id IS NULL AND is_chapter = false;
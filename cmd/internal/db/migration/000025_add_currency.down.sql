@@ -0,0 +1,8 @@
+ALTER TABLE proposal_summary_lines
+    DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE position_items
+    DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE proposals
+    DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,23 @@
+-- =====================================================================================
+-- Migration 000025: Currency
+-- =====================================================================================
+-- До этой миграции все суммы неявно считались рублёвыми. Теперь у предложения есть
+-- валюта (код ISO 4217), а строка стоимости — позиция или итоговая строка — может
+-- указать свою. NULL у строки означает «в валюте предложения». Существующие
+-- предложения получают RUB.
+
+ALTER TABLE proposals
+    ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'RUB'
+        CONSTRAINT chk_proposals_currency_format CHECK (currency ~ '^[A-Z]{3}$');
+
+ALTER TABLE position_items
+    ADD COLUMN IF NOT EXISTS currency VARCHAR(3)
+        CONSTRAINT chk_position_items_currency_format CHECK (currency ~ '^[A-Z]{3}$');
+
+ALTER TABLE proposal_summary_lines
+    ADD COLUMN IF NOT EXISTS currency VARCHAR(3)
+        CONSTRAINT chk_proposal_summary_lines_currency_format CHECK (currency ~ '^[A-Z]{3}$');
+
+COMMENT ON COLUMN proposals.currency IS 'Валюта сумм предложения (ISO 4217)';
+COMMENT ON COLUMN position_items.currency IS 'Валюта стоимостей позиции (ISO 4217); NULL — валюта предложения';
+COMMENT ON COLUMN proposal_summary_lines.currency IS 'Валюта сумм итоговой строки (ISO 4217); NULL — валюта предложения';
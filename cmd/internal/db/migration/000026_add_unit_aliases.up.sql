@@ -0,0 +1,22 @@
+-- =====================================================================================
+-- Migration 000026: Unit Aliases
+-- =====================================================================================
+-- Единицы измерения создавались при импорте «как пришли» (после trim и нижнего
+-- регистра), поэтому одна единица жила в справочнике под несколькими именами:
+-- "м2", "м.кв", "кв.м". Синоним связывает нормализованное написание с
+-- канонической единицей: импорт сначала ищет единицу по имени, затем по синониму
+-- и только потом создаёт новую. При слиянии единиц имя удалённой становится
+-- синонимом оставшейся.
+
+CREATE TABLE IF NOT EXISTS unit_aliases (
+    id BIGSERIAL PRIMARY KEY,
+    alias VARCHAR(50) NOT NULL,
+    unit_id BIGINT NOT NULL REFERENCES units_of_measurement (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_unit_aliases_alias UNIQUE (alias)
+);
+
+COMMENT ON TABLE unit_aliases IS 'Синонимы единиц измерения: нормализованное написание → каноническая единица';
+COMMENT ON COLUMN unit_aliases.alias IS 'Написание единицы после trim и нижнего регистра; не совпадает ни с одним units_of_measurement.normalized_name';
+
+CREATE INDEX IF NOT EXISTS idx_unit_aliases_unit_id ON unit_aliases (unit_id);
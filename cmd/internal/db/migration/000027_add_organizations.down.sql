@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_tenders_organization_id;
+DROP INDEX IF EXISTS idx_users_organization_id;
+
+ALTER TABLE tenders DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE users DROP COLUMN IF EXISTS organization_id;
+
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,40 @@
+-- =====================================================================================
+-- Migration 000027: Organizations
+-- =====================================================================================
+-- Тендеры и пользователи принадлежат организации: пользователь видит и меняет
+-- только тендеры своей организации (роль admin — все). Существующие записи
+-- переносятся в организацию по умолчанию; она же назначается новым
+-- пользователям и импортам, в которых организация не указана.
+
+CREATE TABLE IF NOT EXISTS organizations (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_organizations_name UNIQUE (name)
+);
+
+COMMENT ON TABLE organizations IS 'Организации: владельцы тендеров и места работы пользователей';
+COMMENT ON COLUMN organizations.is_default IS 'Организация по умолчанию: для существующих данных и записей без явной организации';
+
+-- Организация по умолчанию ровно одна
+CREATE UNIQUE INDEX IF NOT EXISTS uq_organizations_default ON organizations (is_default) WHERE is_default;
+
+INSERT INTO organizations (name, is_default)
+VALUES ('Организация по умолчанию', TRUE)
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS organization_id BIGINT REFERENCES organizations (id) ON DELETE RESTRICT;
+UPDATE users SET organization_id = (SELECT id FROM organizations WHERE is_default) WHERE organization_id IS NULL;
+ALTER TABLE users ALTER COLUMN organization_id SET NOT NULL;
+
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS organization_id BIGINT REFERENCES organizations (id) ON DELETE RESTRICT;
+UPDATE tenders SET organization_id = (SELECT id FROM organizations WHERE is_default) WHERE organization_id IS NULL;
+ALTER TABLE tenders ALTER COLUMN organization_id SET NOT NULL;
+
+COMMENT ON COLUMN users.organization_id IS 'Организация пользователя: ограничивает видимые тендеры (кроме роли admin)';
+COMMENT ON COLUMN tenders.organization_id IS 'Организация-владелец тендера; задается при первом импорте и не меняется повторным импортом';
+
+CREATE INDEX IF NOT EXISTS idx_users_organization_id ON users (organization_id);
+CREATE INDEX IF NOT EXISTS idx_tenders_organization_id ON tenders (organization_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_ai_analysis_results_lot_id;
+
+DROP TABLE IF EXISTS ai_analysis_results;
@@ -0,0 +1,29 @@
+-- =====================================================================================
+-- Migration 000028: AI Analysis Results
+-- =====================================================================================
+-- POST /internal/worker/lots/:lot_id/ai-results перезаписывал lot_key_parameters
+-- без следа. Теперь каждый запуск AI-анализа лота сохраняется с моделью, версией
+-- промпта, сырым ответом и извлечёнными параметрами, а в лот попадают параметры
+-- последнего запуска. Неудачную извлечённую версию можно заменить любым прежним
+-- запуском (promote). promoted_at — когда параметры запуска последний раз
+-- применялись к лоту; текущие параметры лота — от запуска с максимальным promoted_at,
+-- если после него их не правили вручную (PATCH /lots/:id/key-parameters).
+
+CREATE TABLE IF NOT EXISTS ai_analysis_results (
+    id             BIGSERIAL PRIMARY KEY,
+    lot_id         BIGINT NOT NULL REFERENCES lots(id) ON DELETE CASCADE,
+    model_name     TEXT NOT NULL DEFAULT '',
+    prompt_version TEXT NOT NULL DEFAULT '',
+    raw_output     TEXT NOT NULL DEFAULT '',
+    key_parameters JSONB NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    promoted_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_ai_analysis_results_lot_id ON ai_analysis_results (lot_id, created_at DESC);
+
+COMMENT ON TABLE ai_analysis_results IS 'Запуски AI-анализа лота: происхождение ключевых параметров';
+COMMENT ON COLUMN ai_analysis_results.model_name IS 'Модель, выполнившая анализ (пусто — воркер не передал)';
+COMMENT ON COLUMN ai_analysis_results.prompt_version IS 'Версия промпта (пусто — воркер не передал)';
+COMMENT ON COLUMN ai_analysis_results.raw_output IS 'Сырой ответ модели до извлечения параметров';
+COMMENT ON COLUMN ai_analysis_results.promoted_at IS 'Когда параметры запуска последний раз записаны в lots.lot_key_parameters';
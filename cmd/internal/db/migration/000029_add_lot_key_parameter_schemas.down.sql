@@ -0,0 +1 @@
+DROP TABLE IF EXISTS lot_key_parameter_schemas;
@@ -0,0 +1,16 @@
+-- =====================================================================================
+-- Migration 000029: Lot Key Parameter Schemas
+-- =====================================================================================
+-- lot_key_parameters приходят от AI-воркера в свободной форме, и неожиданный тип
+-- или лишнее поле ломает фронтенд. Для категории тендера можно задать JSON Schema
+-- (подмножество, см. cmd/pkg/jsonschema); параметры лотов тендеров этой категории
+-- проверяются по ней перед сохранением. Без схемы параметры не проверяются.
+
+CREATE TABLE IF NOT EXISTS lot_key_parameter_schemas (
+    tender_category_id BIGINT PRIMARY KEY REFERENCES tender_categories(id) ON DELETE CASCADE,
+    schema             JSONB NOT NULL,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE lot_key_parameter_schemas IS 'JSON Schema ключевых параметров лота для категории тендера';
@@ -0,0 +1,12 @@
+-- =====================================================================================
+-- Migration 000030: Position Item Trigram Search
+-- =====================================================================================
+-- Поиск позиций по названию во всех тендерах (GET /api/v1/positions/search):
+-- сметчику нужно найти, где уже встречалась такая строка. Названия в КП не
+-- лемматизированы и пишутся по-разному, поэтому вместо FTS — триграммы: индекс
+-- обслуживает и подстроку (ILIKE '%...%'), и нечёткое совпадение по словам (<%).
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_position_items_job_title_trgm
+    ON position_items USING GIN (job_title_in_proposal gin_trgm_ops);
@@ -0,0 +1,59 @@
+-- =====================================================================================
+-- Migration 000031: Scheduled Reports
+-- =====================================================================================
+-- Администратор задает регулярные отчеты (экономия, новые тендеры, очередь
+-- несопоставленных позиций) с форматом и адресатами. Фоновый воркер API
+-- забирает отчеты, срок которых наступил, формирует файл (XLSX или PDF) и
+-- отправляет его по почте. Каждый запуск записывается в report_runs.
+--
+-- Расписание задается границами периодов в UTC: daily — каждый день в 00:00,
+-- weekly — по понедельникам, monthly — первого числа. Отчет охватывает
+-- закончившийся период. next_run_at всегда лежит на такой границе.
+
+CREATE TABLE scheduled_reports (
+    id              BIGSERIAL PRIMARY KEY,
+    name            TEXT NOT NULL,
+    kind            TEXT NOT NULL,
+    format          TEXT NOT NULL,
+    frequency       TEXT NOT NULL,
+    recipients      TEXT[] NOT NULL,
+    organization_id BIGINT REFERENCES organizations(id) ON DELETE CASCADE,    -- NULL — все организации
+    category_id     BIGINT REFERENCES tender_categories(id) ON DELETE SET NULL, -- фильтр отчетов по тендерам
+    is_active       BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_at     TIMESTAMPTZ NOT NULL,
+    last_run_at     TIMESTAMPTZ,
+    created_by      BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_scheduled_reports_kind CHECK (kind IN ('savings', 'new_tenders', 'unmatched_positions')),
+    CONSTRAINT chk_scheduled_reports_format CHECK (format IN ('xlsx', 'pdf')),
+    CONSTRAINT chk_scheduled_reports_frequency CHECK (frequency IN ('daily', 'weekly', 'monthly')),
+    CONSTRAINT chk_scheduled_reports_recipients CHECK (cardinality(recipients) > 0)
+);
+
+-- Воркер выбирает только активные отчеты, срок которых наступил
+CREATE INDEX idx_scheduled_reports_due
+ON scheduled_reports(next_run_at)
+WHERE is_active;
+
+CREATE TABLE report_runs (
+    id                  BIGSERIAL PRIMARY KEY,
+    scheduled_report_id BIGINT NOT NULL REFERENCES scheduled_reports(id) ON DELETE CASCADE,
+    status              TEXT NOT NULL DEFAULT 'running',
+    period_from         TIMESTAMPTZ NOT NULL,
+    period_to           TIMESTAMPTZ NOT NULL,
+    recipients          TEXT[] NOT NULL,
+    file_name           TEXT,
+    file_size           BIGINT,
+    rows_count          INTEGER,
+    error               TEXT,
+    started_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at         TIMESTAMPTZ,
+
+    CONSTRAINT chk_report_runs_status CHECK (status IN ('running', 'sent', 'failed'))
+);
+
+-- История запусков отчета, новые первыми
+CREATE INDEX idx_report_runs_scheduled_report
+ON report_runs(scheduled_report_id, started_at DESC);
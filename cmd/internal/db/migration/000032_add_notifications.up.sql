@@ -0,0 +1,35 @@
+-- =====================================================================================
+-- Migration 000032: In-app Notifications
+-- =====================================================================================
+-- Лента уведомлений пользователя в веб-интерфейсе (колокольчик). Записи создаются
+-- по доменным событиям: в тендере появились новые предложения, завершён AI-анализ
+-- лота, в очереди слияний каталога есть заявки на проверку. Каждому адресату —
+-- своя строка; read_at IS NULL — уведомление не прочитано.
+
+CREATE TABLE notifications (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind       TEXT NOT NULL,
+    title      TEXT NOT NULL,
+    body       TEXT NOT NULL DEFAULT '',
+    tender_id  BIGINT REFERENCES tenders(id) ON DELETE CASCADE,
+    lot_id     BIGINT REFERENCES lots(id) ON DELETE CASCADE,
+    payload    JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    read_at    TIMESTAMPTZ,
+
+    CONSTRAINT chk_notifications_kind CHECK (kind IN ('tender_new_proposals', 'lot_ai_analysis_completed', 'merge_review_pending'))
+);
+
+COMMENT ON TABLE notifications IS 'Лента уведомлений пользователей в веб-интерфейсе';
+COMMENT ON COLUMN notifications.payload IS 'Данные события для фронтенда (число предложений, ID запуска AI-анализа и т.п.)';
+COMMENT ON COLUMN notifications.read_at IS 'Когда пользователь отметил уведомление прочитанным; NULL — не прочитано';
+
+-- Лента пользователя, новые первыми
+CREATE INDEX idx_notifications_user_created
+ON notifications(user_id, created_at DESC, id DESC);
+
+-- Счётчик непрочитанных для колокольчика
+CREATE INDEX idx_notifications_user_unread
+ON notifications(user_id, kind)
+WHERE read_at IS NULL;
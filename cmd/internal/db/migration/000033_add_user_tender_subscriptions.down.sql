@@ -0,0 +1,5 @@
+DELETE FROM notifications WHERE kind IN ('tender_reimported', 'tender_winner_changed');
+ALTER TABLE notifications DROP CONSTRAINT chk_notifications_kind;
+ALTER TABLE notifications ADD CONSTRAINT chk_notifications_kind CHECK (kind IN ('tender_new_proposals', 'lot_ai_analysis_completed', 'merge_review_pending'));
+
+DROP TABLE IF EXISTS user_tender_subscriptions;
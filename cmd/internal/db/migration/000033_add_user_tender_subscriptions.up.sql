@@ -0,0 +1,27 @@
+-- =====================================================================================
+-- Migration 000033: Tender Follow Subscriptions
+-- =====================================================================================
+-- Пользователь подписывается на тендер («Следить»), чтобы видеть его в списке
+-- отслеживаемых и получать уведомления в ленте: повторный импорт, новые
+-- предложения, завершённый AI-анализ лота, смена победителей. Уведомления о
+-- тендерах (000032) теперь получают только подписчики.
+
+CREATE TABLE user_tender_subscriptions (
+    user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    tender_id  BIGINT NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (user_id, tender_id)
+);
+
+COMMENT ON TABLE user_tender_subscriptions IS 'Тендеры, за которыми следит пользователь';
+
+-- Адресаты уведомлений по тендеру
+CREATE INDEX idx_user_tender_subscriptions_tender
+ON user_tender_subscriptions(tender_id);
+
+ALTER TABLE notifications DROP CONSTRAINT chk_notifications_kind;
+ALTER TABLE notifications ADD CONSTRAINT chk_notifications_kind CHECK (kind IN (
+    'tender_new_proposals', 'tender_reimported', 'tender_winner_changed',
+    'lot_ai_analysis_completed', 'merge_review_pending'
+));
@@ -0,0 +1,29 @@
+-- =====================================================================================
+-- Migration 000034: Upload Tasks
+-- =====================================================================================
+-- Загрузка XLSX (POST /api/v1/upload-tender) ставит в парсере фоновую задачу.
+-- Запись о задаче сохраняется в API, чтобы GET /api/v1/tasks/:task_id/status
+-- отвечал и после перезапуска парсера или API: пока парсер знает задачу, ответ
+-- берется у него (и запоминается в last_status), иначе — из этой таблицы.
+
+CREATE TABLE upload_tasks (
+    id              BIGSERIAL PRIMARY KEY,
+    task_id         TEXT NOT NULL UNIQUE,
+    user_id         BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    file_name       TEXT NOT NULL,
+    file_size       BIGINT NOT NULL,
+    enable_ai       BOOLEAN NOT NULL DEFAULT FALSE,
+    status          TEXT NOT NULL DEFAULT 'queued',
+    last_status     JSONB,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE upload_tasks IS 'Задачи парсера, поставленные загрузкой файлов тендеров';
+COMMENT ON COLUMN upload_tasks.status IS 'Последний статус задачи, полученный от парсера; queued — статус еще не запрашивался';
+COMMENT ON COLUMN upload_tasks.last_status IS 'Последний ответ парсера на запрос статуса';
+
+-- Последние загрузки организации
+CREATE INDEX idx_upload_tasks_organization_created
+ON upload_tasks(organization_id, created_at DESC);
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_parse_tasks_user_created;
+
+ALTER TABLE parse_tasks
+    DROP CONSTRAINT chk_parse_tasks_status_source,
+    DROP COLUMN finished_at,
+    DROP COLUMN error_message,
+    DROP COLUMN tender_id,
+    DROP COLUMN status_source;
+
+ALTER INDEX idx_parse_tasks_organization_created RENAME TO idx_upload_tasks_organization_created;
+ALTER TABLE parse_tasks RENAME TO upload_tasks;
@@ -0,0 +1,27 @@
+-- =====================================================================================
+-- Migration 000035: Parse Tasks History
+-- =====================================================================================
+-- upload_tasks (000034) становится историей задач парсера parse_tasks: кроме
+-- того, кто и что загрузил, хранится итог — ID созданного тендера или текст
+-- ошибки. Статус приходит двумя путями: API опрашивает парсер при запросе
+-- статуса (polled) или парсер сам сообщает его через
+-- POST /internal/worker/tasks/:task_id/status (pushed). Итоговый статус
+-- (completed / failed) от парсера опросом больше не перезаписывается.
+
+ALTER TABLE upload_tasks RENAME TO parse_tasks;
+ALTER INDEX idx_upload_tasks_organization_created RENAME TO idx_parse_tasks_organization_created;
+
+ALTER TABLE parse_tasks
+    ADD COLUMN status_source TEXT NOT NULL DEFAULT 'polled',
+    ADD COLUMN tender_id     BIGINT REFERENCES tenders(id) ON DELETE SET NULL,
+    ADD COLUMN error_message TEXT,
+    ADD COLUMN finished_at   TIMESTAMPTZ,
+    ADD CONSTRAINT chk_parse_tasks_status_source CHECK (status_source IN ('polled', 'pushed'));
+
+COMMENT ON TABLE parse_tasks IS 'Задачи парсера, поставленные загрузкой файлов тендеров, и их итог';
+COMMENT ON COLUMN parse_tasks.status_source IS 'Откуда последний статус: polled — опрос парсера, pushed — сообщил парсер';
+COMMENT ON COLUMN parse_tasks.finished_at IS 'Когда парсер сообщил итоговый статус completed или failed';
+
+-- Последние загрузки пользователя (GET /api/v1/tasks)
+CREATE INDEX idx_parse_tasks_user_created
+ON parse_tasks(user_id, created_at DESC, id DESC);
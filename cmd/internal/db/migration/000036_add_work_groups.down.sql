@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_catalog_positions_work_group_code;
+
+ALTER TABLE catalog_positions DROP COLUMN IF EXISTS work_group_code;
+
+DROP TABLE IF EXISTS work_groups;
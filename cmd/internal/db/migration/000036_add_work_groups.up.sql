@@ -0,0 +1,35 @@
+-- =====================================================================================
+-- Migration 000036: Work Groups Classifier
+-- =====================================================================================
+-- Классификатор видов работ (земляные работы → разработка грунта, бетонные
+-- работы, отделка...) — дерево work_groups с иерархическими кодами. Позиция
+-- каталога относится к узлу через work_group_code; аналитика лота сворачивает
+-- стоимость позиций по узлам классификатора вверх по дереву.
+--
+-- catalog_positions.parent_id (000007) остаётся группировкой вариантов одной
+-- работы (GROUP_TITLE): вариант без своего кода относится к узлу родителя.
+
+CREATE TABLE work_groups (
+    id         BIGSERIAL PRIMARY KEY,
+    parent_id  BIGINT REFERENCES work_groups(id) ON DELETE RESTRICT,
+    code       TEXT NOT NULL UNIQUE,
+    name       TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_work_groups_not_self_parent CHECK (id <> parent_id),
+    CONSTRAINT chk_work_groups_code CHECK (code ~ '^[0-9A-Za-z]+([.-][0-9A-Za-z]+)*$')
+);
+
+COMMENT ON TABLE work_groups IS 'Классификатор видов работ для свёртки аналитики по позициям каталога';
+COMMENT ON COLUMN work_groups.code IS 'Код узла классификатора, например 01.02; меняется вместе со ссылками позиций';
+
+CREATE INDEX idx_work_groups_parent_id ON work_groups(parent_id);
+
+-- Код, а не id: код виден в выгрузках и стабилен между окружениями.
+-- ON UPDATE CASCADE переносит переименование кода на позиции.
+ALTER TABLE catalog_positions
+    ADD COLUMN work_group_code TEXT REFERENCES work_groups(code) ON UPDATE CASCADE ON DELETE SET NULL;
+
+CREATE INDEX idx_catalog_positions_work_group_code
+ON catalog_positions(work_group_code)
+WHERE work_group_code IS NOT NULL;
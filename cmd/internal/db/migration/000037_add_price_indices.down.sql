@@ -0,0 +1,4 @@
+ALTER TABLE tenders DROP CONSTRAINT IF EXISTS chk_tenders_region;
+ALTER TABLE tenders DROP COLUMN IF EXISTS region;
+
+DROP TABLE IF EXISTS price_indices;
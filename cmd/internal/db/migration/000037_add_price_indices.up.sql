@@ -0,0 +1,31 @@
+-- =====================================================================================
+-- Migration 000037: Regional Price Indices
+-- =====================================================================================
+-- Индексы стоимости строительства по регионам и месяцам. История цен позиции
+-- каталога (GET /api/v1/catalog/:id/price-history?normalize=true) пересчитывает
+-- цену за единицу к выбранным региону и дате:
+--   normalized = unit_cost × index(целевой регион, целевая дата) / index(регион тендера, дата цены)
+-- Индекс на дату — значение за последний месяц, не позже даты. Регион без
+-- своего ряда (и тендер без региона) пересчитывается по общероссийскому ряду RU.
+
+CREATE TABLE price_indices (
+    id         BIGSERIAL PRIMARY KEY,
+    region     TEXT NOT NULL,
+    period     DATE NOT NULL,
+    value      NUMERIC(12, 4) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_price_indices_region_period UNIQUE (region, period),
+    CONSTRAINT chk_price_indices_region CHECK (region ~ '^[0-9A-Z-]{1,16}$'),
+    CONSTRAINT chk_price_indices_period CHECK (period = date_trunc('month', period)::date),
+    CONSTRAINT chk_price_indices_value CHECK (value > 0)
+);
+
+COMMENT ON TABLE price_indices IS 'Индексы стоимости строительства по регионам и месяцам для сопоставимых цен';
+COMMENT ON COLUMN price_indices.region IS 'Код региона (например, 77); RU — общероссийский ряд';
+COMMENT ON COLUMN price_indices.period IS 'Первое число месяца, к которому относится индекс';
+
+-- Регион строительства тендера; NULL — общероссийский ряд.
+ALTER TABLE tenders
+    ADD COLUMN region TEXT,
+    ADD CONSTRAINT chk_tenders_region CHECK (region ~ '^[0-9A-Z-]{1,16}$');
@@ -0,0 +1,34 @@
+-- =====================================================================================
+-- Migration 000038: Transactional Outbox
+-- =====================================================================================
+-- События для Python-воркера (например, появление новых pending-позиций
+-- каталога после импорта) записываются в outbox_events в той же транзакции,
+-- что и изменение данных: событие есть тогда и только тогда, когда изменение
+-- зафиксировано. Фоновый диспетчер API отправляет события HTTP-callback'ом
+-- воркеру и повторяет неуспешные попытки, пока воркер не ответит 2xx
+-- (at-least-once, без статуса failed).
+--
+-- В отличие от webhook_deliveries, у события нет подписки: получатель один —
+-- воркер, адрес которого задаётся в конфигурации (outbox.worker_callback_url).
+
+CREATE TABLE outbox_events (
+    id              BIGSERIAL PRIMARY KEY,
+    event           TEXT NOT NULL,
+    aggregate_id    BIGINT NOT NULL,                     -- ID сущности события (например, тендера)
+    payload         JSONB NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'pending',
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),  -- для pending: когда отправлять (или конец аренды диспетчером)
+    last_attempt_at TIMESTAMPTZ,
+    response_status INTEGER,                             -- HTTP-код последней попытки, NULL при сетевой ошибке
+    last_error      TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at    TIMESTAMPTZ,
+
+    CONSTRAINT chk_outbox_events_status CHECK (status IN ('pending', 'delivered'))
+);
+
+-- Диспетчер выбирает только ожидающие события
+CREATE INDEX idx_outbox_events_due
+ON outbox_events(next_attempt_at)
+WHERE status = 'pending';
@@ -0,0 +1,13 @@
+-- =====================================================================================
+-- Migration 000039: User Token Version
+-- =====================================================================================
+-- Счётчик версии access-токенов пользователя. Версия записывается в токен
+-- (claim ver) при выпуске и увеличивается при выходе на всех устройствах,
+-- смене или сбросе пароля, смене роли, организации или статуса. Токен с
+-- версией меньше текущей отклоняется middleware до истечения срока.
+--
+-- В отличие от tokens_revoked_at (миграция 000017), сравнение не зависит от
+-- часов экземпляров API: iat выставляет приложение, а now() — база.
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS token_version BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_parse_tasks_pending_fetch;
+
+ALTER TABLE parse_tasks
+    DROP CONSTRAINT IF EXISTS chk_parse_tasks_fetch_etp_id,
+    DROP CONSTRAINT IF EXISTS chk_parse_tasks_source,
+    DROP COLUMN IF EXISTS etp_id,
+    DROP COLUMN IF EXISTS source;
@@ -0,0 +1,22 @@
+-- =====================================================================================
+-- Migration 000040: Parse Task Fetch
+-- =====================================================================================
+-- Задачу парсера ставит не только загрузка файла: POST /api/v1/tenders/fetch
+-- просит парсер скачать тендер с ЭТП по etp_id, разобрать и импортировать его.
+-- Такие задачи хранятся в parse_tasks с source = 'fetch' и etp_id; файла у них
+-- нет (file_name пустой, file_size 0). По etp_id незавершённой задачи
+-- повторный запрос того же тендера не ставит вторую.
+
+ALTER TABLE parse_tasks
+    ADD COLUMN source TEXT NOT NULL DEFAULT 'upload',
+    ADD COLUMN etp_id TEXT,
+    ADD CONSTRAINT chk_parse_tasks_source CHECK (source IN ('upload', 'fetch')),
+    ADD CONSTRAINT chk_parse_tasks_fetch_etp_id CHECK (source <> 'fetch' OR etp_id IS NOT NULL);
+
+COMMENT ON COLUMN parse_tasks.source IS 'Как поставлена задача: upload — загрузка файла, fetch — получение тендера с ЭТП';
+COMMENT ON COLUMN parse_tasks.etp_id IS 'ID тендера на ЭТП для задач получения (source = fetch)';
+
+-- Незавершённая задача получения тендера (дедупликация POST /api/v1/tenders/fetch)
+CREATE INDEX idx_parse_tasks_pending_fetch
+ON parse_tasks(etp_id, created_at DESC)
+WHERE source = 'fetch' AND finished_at IS NULL;
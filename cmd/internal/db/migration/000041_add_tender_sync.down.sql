@@ -0,0 +1,22 @@
+DELETE FROM notifications WHERE kind = 'tender_synced';
+ALTER TABLE notifications DROP CONSTRAINT chk_notifications_kind;
+ALTER TABLE notifications ADD CONSTRAINT chk_notifications_kind CHECK (kind IN (
+    'tender_new_proposals', 'tender_reimported', 'tender_winner_changed',
+    'lot_ai_analysis_completed', 'merge_review_pending'
+));
+
+DROP TABLE IF EXISTS tender_syncs;
+
+DELETE FROM parse_tasks WHERE source = 'sync';
+ALTER TABLE parse_tasks
+    DROP CONSTRAINT IF EXISTS chk_parse_tasks_fetch_etp_id,
+    DROP CONSTRAINT IF EXISTS chk_parse_tasks_source;
+ALTER TABLE parse_tasks
+    ADD CONSTRAINT chk_parse_tasks_source CHECK (source IN ('upload', 'fetch')),
+    ADD CONSTRAINT chk_parse_tasks_fetch_etp_id CHECK (source <> 'fetch' OR etp_id IS NOT NULL);
+
+DROP INDEX IF EXISTS idx_tenders_sync_candidates;
+
+ALTER TABLE tenders
+    DROP COLUMN IF EXISTS synced_at,
+    DROP COLUMN IF EXISTS etp_status;
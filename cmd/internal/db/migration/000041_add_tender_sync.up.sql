@@ -0,0 +1,62 @@
+-- =====================================================================================
+-- Migration 000041: Tender Sync
+-- =====================================================================================
+-- Открытые тендеры периодически запрашиваются у парсера заново: на ЭТП
+-- появляются новые предложения и меняются цены. Статус процедуры на ЭТП
+-- присылает парсер в данных импорта (etp_status); тендеры с незавершённой
+-- процедурой раз в etp.sync.refresh_after получают задачу парсера
+-- (parse_tasks.source = 'sync'). Результат каждого обновления — что изменилось
+-- между версиями исходного JSON — хранится в tender_syncs.
+
+ALTER TABLE tenders
+    ADD COLUMN etp_status TEXT,
+    ADD COLUMN synced_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN tenders.etp_status IS 'Статус процедуры на ЭТП из последнего импорта; NULL — парсер статус не сообщал, тендер не обновляется';
+COMMENT ON COLUMN tenders.synced_at IS 'Когда тендер последний раз запрошен у парсера для обновления';
+
+-- Кандидаты на обновление: давно не запрашивавшиеся первыми
+CREATE INDEX idx_tenders_sync_candidates
+ON tenders(synced_at NULLS FIRST, id)
+WHERE etp_status IS NOT NULL AND deleted_at IS NULL;
+
+ALTER TABLE parse_tasks
+    DROP CONSTRAINT chk_parse_tasks_source,
+    DROP CONSTRAINT chk_parse_tasks_fetch_etp_id;
+ALTER TABLE parse_tasks
+    ADD CONSTRAINT chk_parse_tasks_source CHECK (source IN ('upload', 'fetch', 'sync')),
+    ADD CONSTRAINT chk_parse_tasks_fetch_etp_id CHECK (source = 'upload' OR etp_id IS NOT NULL);
+
+COMMENT ON COLUMN parse_tasks.source IS 'Как поставлена задача: upload — загрузка файла, fetch — получение тендера с ЭТП, sync — обновление тендера по расписанию';
+
+CREATE TABLE tender_syncs (
+    id              BIGSERIAL PRIMARY KEY,
+    tender_id       BIGINT NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+    task_id         TEXT NOT NULL UNIQUE REFERENCES parse_tasks(task_id) ON DELETE CASCADE,
+    status          TEXT NOT NULL DEFAULT 'pending',
+    from_version    INT,
+    to_version      INT,
+    proposals_added INT NOT NULL DEFAULT 0,
+    prices_changed  INT NOT NULL DEFAULT 0,
+    stats           JSONB,
+    error_message   TEXT,
+    requested_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at     TIMESTAMPTZ,
+
+    CONSTRAINT chk_tender_syncs_status CHECK (status IN ('pending', 'changed', 'unchanged', 'failed'))
+);
+
+COMMENT ON TABLE tender_syncs IS 'Обновления тендеров с ЭТП по расписанию и их результат';
+COMMENT ON COLUMN tender_syncs.from_version IS 'Последняя версия исходного JSON до обновления (tender_raw_data_history)';
+COMMENT ON COLUMN tender_syncs.to_version IS 'Версия, созданная обновлением; NULL — импорта не было';
+COMMENT ON COLUMN tender_syncs.prices_changed IS 'Позиций предложений, у которых изменилась стоимость';
+COMMENT ON COLUMN tender_syncs.stats IS 'Счётчики изменений между версиями (TenderImportDiffStats)';
+
+CREATE INDEX idx_tender_syncs_tender
+ON tender_syncs(tender_id, requested_at DESC);
+
+ALTER TABLE notifications DROP CONSTRAINT chk_notifications_kind;
+ALTER TABLE notifications ADD CONSTRAINT chk_notifications_kind CHECK (kind IN (
+    'tender_new_proposals', 'tender_reimported', 'tender_winner_changed',
+    'lot_ai_analysis_completed', 'merge_review_pending', 'tender_synced'
+));
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_position_items_proposal_chapter_ref;
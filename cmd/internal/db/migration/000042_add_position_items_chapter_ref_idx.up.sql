@@ -0,0 +1,10 @@
+-- =====================================================================================
+-- Migration 000042: Position Item Chapter Index
+-- =====================================================================================
+-- Постраничный просмотр позиций предложения деревом глав
+-- (GET /api/v1/proposals/:id/positions?view=tree): дочерние строки главы
+-- выбираются и считаются по ссылке chapter_ref_in_proposal. Ссылки в КП бывают
+-- с пробелами по краям, поэтому индекс — по TRIM, как и сравнение в запросах.
+
+CREATE INDEX IF NOT EXISTS idx_position_items_proposal_chapter_ref
+    ON position_items (proposal_id, (TRIM(chapter_ref_in_proposal)));
@@ -0,0 +1,25 @@
+-- =====================================================================================
+-- Migration 000043: Lot Attachments
+-- =====================================================================================
+-- Документы лота, сформированные API. Первый вид — протокол выбора победителя
+-- (POST /api/v1/lots/:id/winner-protocol): после решения о победителе его не
+-- нужно собирать вручную. Файл хранится в БД целиком — протоколы небольшие
+-- (десятки КБ), а отдельного файлового хранилища у API нет. Каждое
+-- формирование — новая запись: прежние протоколы остаются для истории.
+
+CREATE TABLE lot_attachments (
+    id           BIGSERIAL PRIMARY KEY,
+    lot_id       BIGINT NOT NULL REFERENCES lots(id) ON DELETE CASCADE,
+    kind         TEXT NOT NULL,
+    file_name    TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    file_size    BIGINT NOT NULL,
+    content      BYTEA NOT NULL,
+    created_by   BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_lot_attachments_kind CHECK (kind IN ('winner_protocol'))
+);
+
+-- Документы лота, новые первыми
+CREATE INDEX idx_lot_attachments_lot ON lot_attachments(lot_id, created_at DESC);
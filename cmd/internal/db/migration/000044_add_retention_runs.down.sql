@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tender_raw_data_history_imported_at;
+DROP INDEX IF EXISTS idx_user_sessions_revoked_at;
+DROP TABLE IF EXISTS retention_runs;
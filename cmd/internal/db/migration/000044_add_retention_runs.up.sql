@@ -0,0 +1,37 @@
+-- =====================================================================================
+-- Migration 000044: Retention Runs
+-- =====================================================================================
+-- Политики хранения (секция retention конфигурации) периодически удаляют
+-- устаревшие данные: истекшие и давно отозванные сессии, истекший
+-- matching_cache, старые версии исходного JSON импортов. Каждый запуск
+-- политики — в том числе пробный (dry_run), который только считает строки, —
+-- записывается в retention_runs: отчет для GET /api/v1/admin/retention.
+
+CREATE TABLE retention_runs (
+    id            BIGSERIAL PRIMARY KEY,
+    policy        TEXT NOT NULL,
+    dry_run       BOOLEAN NOT NULL,
+    status        TEXT NOT NULL,
+    cutoff        TIMESTAMPTZ NOT NULL,  -- удаляются записи старше cutoff
+    matched_count BIGINT NOT NULL DEFAULT 0, -- подходящих под политику на момент запуска
+    deleted_count BIGINT NOT NULL DEFAULT 0, -- удалено (не больше retention.batch_size)
+    error         TEXT,
+    triggered_by  BIGINT REFERENCES users(id) ON DELETE SET NULL, -- NULL — планировщик
+    started_at    TIMESTAMPTZ NOT NULL,
+    finished_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_retention_runs_policy CHECK (policy IN ('expired_sessions', 'revoked_sessions', 'matching_cache', 'raw_import_history')),
+    CONSTRAINT chk_retention_runs_status CHECK (status IN ('success', 'failed'))
+);
+
+-- История запусков политики, новые первыми; последний запуск каждой политики
+CREATE INDEX idx_retention_runs_policy ON retention_runs(policy, started_at DESC);
+
+-- Давно отозванные сессии: revoked_at заполнен у малой части строк
+CREATE INDEX IF NOT EXISTS idx_user_sessions_revoked_at
+ON user_sessions(revoked_at)
+WHERE revoked_at IS NOT NULL;
+
+-- Старые версии исходного JSON импортов
+CREATE INDEX IF NOT EXISTS idx_tender_raw_data_history_imported_at
+ON tender_raw_data_history(imported_at);
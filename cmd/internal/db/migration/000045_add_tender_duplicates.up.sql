@@ -0,0 +1,38 @@
+-- =====================================================================================
+-- Migration 000045: Tender Duplicates
+-- =====================================================================================
+-- Один и тот же тендер иногда загружается под разными etp_id (лоты
+-- переопубликованы на ЭТП). После импорта тендер сравнивается с другими
+-- тендерами организации по наименованию, объекту и составу позиций; вероятные
+-- дубликаты попадают в очередь на проверку. Администратор связывает пару
+-- (linked — один тендер, переопубликованный под другим etp_id) или отклоняет
+-- её (ignored — повторный импорт пару больше не предлагает).
+
+CREATE TABLE tender_duplicates (
+    id                   BIGSERIAL PRIMARY KEY,
+    tender_id            BIGINT NOT NULL REFERENCES tenders(id) ON DELETE CASCADE, -- более поздний
+    duplicate_of_id      BIGINT NOT NULL REFERENCES tenders(id) ON DELETE CASCADE, -- более ранний
+    score                NUMERIC(4, 3) NOT NULL,
+    title_similarity     NUMERIC(4, 3) NOT NULL,
+    same_object          BOOLEAN NOT NULL,
+    positions_similarity NUMERIC(4, 3) NOT NULL,
+    status               TEXT NOT NULL DEFAULT 'pending',
+    resolved_by          BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at          TIMESTAMPTZ,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_tender_duplicates_pair UNIQUE (tender_id, duplicate_of_id),
+    CONSTRAINT chk_tender_duplicates_order CHECK (duplicate_of_id < tender_id),
+    CONSTRAINT chk_tender_duplicates_status CHECK (status IN ('pending', 'linked', 'ignored')),
+    CONSTRAINT chk_tender_duplicates_resolved CHECK ((status = 'pending') = (resolved_at IS NULL))
+);
+
+COMMENT ON TABLE tender_duplicates IS 'Вероятные дубликаты тендеров (один тендер под разными etp_id) и решение администратора';
+
+-- Очередь на проверку: самые похожие пары первыми
+CREATE INDEX idx_tender_duplicates_pending
+ON tender_duplicates(score DESC, id)
+WHERE status = 'pending';
+
+CREATE INDEX idx_tender_duplicates_duplicate_of ON tender_duplicates(duplicate_of_id);
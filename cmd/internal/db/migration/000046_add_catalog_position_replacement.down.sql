@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_catalog_positions_replaced_by;
+
+ALTER TABLE catalog_positions
+DROP CONSTRAINT IF EXISTS chk_catalog_positions_replaced_by,
+DROP CONSTRAINT IF EXISTS fk_catalog_positions_replaced_by;
+
+ALTER TABLE catalog_positions
+DROP COLUMN IF EXISTS deprecated_by,
+DROP COLUMN IF EXISTS deprecated_at,
+DROP COLUMN IF EXISTS replaced_by_id;
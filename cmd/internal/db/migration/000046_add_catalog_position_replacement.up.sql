@@ -0,0 +1,29 @@
+-- =====================================================================================
+-- Migration 000046: Catalog Position Deprecation
+-- =====================================================================================
+-- Статус 'deprecated' уже есть в chk_catalog_positions_status (его ставит слияние).
+-- Вывод позиции из оборота без слияния: позиция остаётся в истории (position_items
+-- не перевешиваются), а новые сопоставления и кэш уходят на позицию-замену.
+
+ALTER TABLE catalog_positions
+ADD COLUMN replaced_by_id BIGINT NULL,
+ADD COLUMN deprecated_at TIMESTAMPTZ NULL,
+ADD COLUMN deprecated_by TEXT NULL;
+
+-- ON DELETE RESTRICT: нельзя удалить позицию, которая заменяет выведенные
+ALTER TABLE catalog_positions
+ADD CONSTRAINT fk_catalog_positions_replaced_by
+FOREIGN KEY (replaced_by_id)
+REFERENCES catalog_positions(id)
+ON DELETE RESTRICT;
+
+-- Замена бывает только у выведенной позиции и не может ссылаться на саму себя.
+-- Цепочки (A → B → C) сжимаются при выводе B, поэтому replaced_by_id всегда
+-- указывает на позицию в обороте.
+ALTER TABLE catalog_positions
+ADD CONSTRAINT chk_catalog_positions_replaced_by
+CHECK (replaced_by_id IS NULL OR (status = 'deprecated' AND replaced_by_id <> id));
+
+CREATE INDEX idx_catalog_positions_replaced_by
+ON catalog_positions(replaced_by_id)
+WHERE replaced_by_id IS NOT NULL;
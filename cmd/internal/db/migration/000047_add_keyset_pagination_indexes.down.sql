@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_tenders_created_at_id;
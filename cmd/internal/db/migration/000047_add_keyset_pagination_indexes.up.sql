@@ -0,0 +1,8 @@
+-- =====================================================================================
+-- Migration 000047: Indexes for Keyset Pagination
+-- =====================================================================================
+-- Параметр cursor в GET /api/v1/tenders (ListTenders) читает тендеры по
+-- (created_at, id) в обратном порядке: без индекса каждая страница сортирует
+-- всю таблицу. GET /catalog/active и поиск позиций идут по первичным ключам.
+
+CREATE INDEX IF NOT EXISTS idx_tenders_created_at_id ON tenders (created_at DESC, id DESC);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS matching_corrections;
+
+ALTER TABLE matching_cache
+DROP CONSTRAINT IF EXISTS chk_matching_cache_source,
+DROP COLUMN IF EXISTS source;
@@ -0,0 +1,31 @@
+-- =====================================================================================
+-- Migration 000048: Matching Corrections
+-- =====================================================================================
+-- Аналитик исправляет ошибочное сопоставление RAG вручную. Ручная запись
+-- matching_cache (source = 'manual') не истекает и не перезаписывается воркером:
+-- следующие импорты берут исправленную позицию каталога. Каждое исправление
+-- сохраняется в matching_corrections — Python-команда дообучает модель на них
+-- (GET /internal/worker/matching/corrections).
+
+ALTER TABLE matching_cache
+ADD COLUMN source TEXT NOT NULL DEFAULT 'rag'
+CONSTRAINT chk_matching_cache_source CHECK (source IN ('rag', 'manual'));
+
+COMMENT ON COLUMN matching_cache.source IS 'Происхождение записи: rag — результат воркера, manual — исправление аналитика (приоритетнее rag)';
+
+CREATE TABLE matching_corrections (
+    id BIGSERIAL PRIMARY KEY,
+    position_item_id BIGINT NOT NULL REFERENCES position_items(id) ON DELETE CASCADE,
+    job_title_text TEXT NOT NULL,
+    job_title_hash TEXT NOT NULL,
+    norm_version SMALLINT NOT NULL,
+    -- Сопоставление до исправления (NULL — позиция не была сопоставлена)
+    previous_catalog_position_id BIGINT REFERENCES catalog_positions(id) ON DELETE SET NULL,
+    previous_matched_by TEXT,
+    catalog_position_id BIGINT NOT NULL REFERENCES catalog_positions(id) ON DELETE CASCADE,
+    corrected_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    comment TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_matching_corrections_position_item ON matching_corrections(position_item_id);
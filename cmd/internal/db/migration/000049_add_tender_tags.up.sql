@@ -0,0 +1,35 @@
+-- =====================================================================================
+-- Migration 000049: Tender Tags
+-- =====================================================================================
+-- Категории — жёсткий справочник, а тендеры нужно группировать и ситуативно:
+-- «приоритет Q3», «рамочный договор». Метки заводят сами пользователи; у каждой
+-- организации свой набор, название уникально без учёта регистра. Тендер
+-- получает метки только своей организации.
+
+CREATE TABLE tags (
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name            TEXT NOT NULL,
+    created_by      BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_tags_name CHECK (name = btrim(name) AND char_length(name) BETWEEN 1 AND 64)
+);
+
+COMMENT ON TABLE tags IS 'Метки тендеров для произвольной группировки; свои у каждой организации';
+
+CREATE UNIQUE INDEX uq_tags_organization_name ON tags(organization_id, lower(name));
+
+CREATE TABLE tender_tags (
+    tender_id  BIGINT NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+    tag_id     BIGINT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (tender_id, tag_id)
+);
+
+COMMENT ON TABLE tender_tags IS 'Метки тендера (многие-ко-многим)';
+
+-- Фильтр списка тендеров по метке
+CREATE INDEX idx_tender_tags_tag ON tender_tags(tag_id);
@@ -0,0 +1,23 @@
+-- =====================================================================================
+-- Migration 000050: Saved Filters
+-- =====================================================================================
+-- Сохранённые представления списка тендеров («мой регион, бетонные работы, с
+-- победителем»): пользователь даёт набору параметров GET /api/v1/tenders имя,
+-- интерфейс показывает их вкладками. filters — объект «параметр → значение» в
+-- том же виде, что и query string списка; проверяется сервером при сохранении.
+
+CREATE TABLE saved_filters (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name       TEXT NOT NULL,
+    filters    JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_saved_filters_name CHECK (name = btrim(name) AND char_length(name) BETWEEN 1 AND 100),
+    CONSTRAINT chk_saved_filters_object CHECK (jsonb_typeof(filters) = 'object')
+);
+
+COMMENT ON TABLE saved_filters IS 'Именованные наборы фильтров списка тендеров пользователя';
+
+-- Имя уникально у пользователя без учёта регистра; индекс обслуживает и список пользователя
+CREATE UNIQUE INDEX uq_saved_filters_user_name ON saved_filters(user_id, lower(name));
@@ -0,0 +1,29 @@
+-- =====================================================================================
+-- Migration 000051: Field Changes
+-- =====================================================================================
+-- PATCH /api/v1/tenders/:id и правка ключевых параметров лота перезаписывали
+-- поля без следа. Сервисы в той же транзакции, что и правка, пишут сюда каждое
+-- изменённое поле: старое и новое значение (JSON, null — значения не было),
+-- автора и время. Ключевые параметры лота записываются по ключам:
+-- field = 'lot_key_parameters.<ключ>'.
+--
+-- Ссылки на тендер и лот нет (entity_type + entity_id): история тендера
+-- удаляется вместе с ним в purge (tender_purge.sql).
+
+CREATE TABLE field_changes (
+    id          BIGSERIAL PRIMARY KEY,
+    entity_type TEXT NOT NULL,
+    entity_id   BIGINT NOT NULL,
+    field       TEXT NOT NULL,
+    old_value   JSONB NOT NULL,
+    new_value   JSONB NOT NULL,
+    changed_by  BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    changed_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_field_changes_entity_type CHECK (entity_type IN ('tender', 'lot'))
+);
+
+COMMENT ON TABLE field_changes IS 'История ручных правок полей тендеров и лотов';
+
+-- Лента правок сущности: новые первыми
+CREATE INDEX idx_field_changes_entity ON field_changes(entity_type, entity_id, changed_at DESC, id DESC);
@@ -0,0 +1,19 @@
+-- =====================================================================================
+-- Migration 000052: User OIDC Identity
+-- =====================================================================================
+-- Вход через корпоративный SSO (GET /api/v1/auth/oidc/login). Пользователь
+-- привязывается к учётной записи провайдера парой (issuer, sub) при первом
+-- входе: по подтверждённому email к существующему пользователю или к новому.
+-- Дальше вход ищет пользователя по этой паре, а не по email: email в
+-- каталоге может смениться.
+--
+-- Пользователи, созданные через SSO, получают пустой password_hash: вход по
+-- паролю для них невозможен, пока администратор не выдаст ссылку сброса.
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS oidc_issuer TEXT,
+    ADD COLUMN IF NOT EXISTS oidc_subject TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_oidc_identity
+    ON users(oidc_issuer, oidc_subject)
+    WHERE oidc_subject IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS impersonation_audit_log;
+DROP TABLE IF EXISTS impersonation_sessions;
@@ -0,0 +1,44 @@
+-- =====================================================================================
+-- Migration 000053: Admin Impersonation
+-- =====================================================================================
+-- Режим имперсонации (POST /api/v1/admin/users/:id/impersonate): поддержка
+-- воспроизводит проблему конкретного пользователя, действуя от его имени.
+-- Сессия короткая (auth.impersonation_ttl), access-токен несёт acting_admin_id
+-- и id сессии; завершённая или просроченная сессия токен больше не пропускает.
+--
+-- Каждый запрос под имперсонацией записывается в impersonation_audit_log до
+-- выполнения: если запись не удалась, запрос отклоняется. Статус ответа
+-- дописывается после. Журнал не чистится каскадно — строки users не удаляются.
+
+CREATE TABLE impersonation_sessions (
+    id         BIGSERIAL PRIMARY KEY,
+    admin_id   BIGINT NOT NULL REFERENCES users(id),
+    user_id    BIGINT NOT NULL REFERENCES users(id),
+    ip_address INET,
+    user_agent TEXT,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    ended_at   TIMESTAMPTZ,
+
+    CONSTRAINT chk_impersonation_sessions_not_self CHECK (admin_id <> user_id),
+    CONSTRAINT chk_impersonation_sessions_expiry CHECK (expires_at > started_at)
+);
+
+COMMENT ON TABLE impersonation_sessions IS 'Сессии, в которых администратор действовал от имени пользователя';
+
+CREATE INDEX idx_impersonation_sessions_admin ON impersonation_sessions(admin_id, started_at DESC);
+CREATE INDEX idx_impersonation_sessions_user ON impersonation_sessions(user_id, started_at DESC);
+
+CREATE TABLE impersonation_audit_log (
+    id         BIGSERIAL PRIMARY KEY,
+    session_id BIGINT NOT NULL REFERENCES impersonation_sessions(id),
+    method     TEXT NOT NULL,
+    path       TEXT NOT NULL,
+    status     INTEGER, -- NULL: ответ не дописан (запрос прерван или ещё выполняется)
+    ip_address INET,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE impersonation_audit_log IS 'Журнал запросов, выполненных под имперсонацией';
+
+CREATE INDEX idx_impersonation_audit_log_session ON impersonation_audit_log(session_id, created_at);
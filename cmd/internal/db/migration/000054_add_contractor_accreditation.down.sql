@@ -0,0 +1,27 @@
+DELETE FROM notifications WHERE kind = 'contractor_accreditation_expiring';
+ALTER TABLE notifications DROP CONSTRAINT chk_notifications_kind;
+ALTER TABLE notifications ADD CONSTRAINT chk_notifications_kind CHECK (kind IN (
+    'tender_new_proposals', 'tender_reimported', 'tender_winner_changed',
+    'lot_ai_analysis_completed', 'merge_review_pending', 'tender_synced'
+));
+
+DROP INDEX IF EXISTS idx_contractors_accreditation_valid_until;
+
+ALTER TABLE contractors ADD COLUMN accreditation varchar NOT NULL DEFAULT 'N/A';
+UPDATE contractors
+SET accreditation = CASE accreditation_status
+    WHEN 'accredited' THEN 'Аккредитован'
+    WHEN 'not_accredited' THEN 'Не аккредитован'
+    ELSE 'N/A'
+END;
+ALTER TABLE contractors ALTER COLUMN accreditation DROP DEFAULT;
+
+ALTER TABLE contractors
+    DROP CONSTRAINT IF EXISTS chk_contractors_accreditation_documents,
+    DROP CONSTRAINT IF EXISTS chk_contractors_accreditation_source,
+    DROP CONSTRAINT IF EXISTS chk_contractors_accreditation_status,
+    DROP COLUMN IF EXISTS accreditation_alerted_until,
+    DROP COLUMN IF EXISTS accreditation_source,
+    DROP COLUMN IF EXISTS accreditation_documents,
+    DROP COLUMN IF EXISTS accreditation_valid_until,
+    DROP COLUMN IF EXISTS accreditation_status;
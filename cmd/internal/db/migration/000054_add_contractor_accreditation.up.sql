@@ -0,0 +1,51 @@
+-- =====================================================================================
+-- Migration 000054: Contractor Accreditation
+-- =====================================================================================
+-- Аккредитация подрядчика хранилась строкой из парсера («Аккредитован», «Да»,
+-- «N/A»), по которой нельзя было понять, когда она заканчивается. Теперь это
+-- статус, срок действия и подтверждающие документы. Распознанные строки
+-- становятся статусом accredited / not_accredited, остальные — unknown.
+--
+-- accreditation_source = manual — данные внесены вручную
+-- (PUT /api/v1/admin/contractors/:id/accreditation), импорт их не перезаписывает.
+-- accreditation_alerted_until — срок, о приближении которого уже оповестило
+-- задание планировщика contractor_accreditation: новый срок оповещает заново.
+
+ALTER TABLE contractors
+    ADD COLUMN accreditation_status TEXT NOT NULL DEFAULT 'unknown',
+    ADD COLUMN accreditation_valid_until DATE,
+    ADD COLUMN accreditation_documents JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN accreditation_source TEXT NOT NULL DEFAULT 'import',
+    ADD COLUMN accreditation_alerted_until DATE;
+
+UPDATE contractors
+SET accreditation_status = CASE
+    WHEN lower(btrim(accreditation)) IN ('аккредитован', 'аккредитована', 'да', 'active', 'accredited', 'yes') THEN 'accredited'
+    WHEN lower(btrim(accreditation)) IN ('не аккредитован', 'не аккредитована', 'нет', 'inactive', 'not accredited', 'no') THEN 'not_accredited'
+    ELSE 'unknown'
+END;
+
+ALTER TABLE contractors DROP COLUMN accreditation;
+
+ALTER TABLE contractors
+    ADD CONSTRAINT chk_contractors_accreditation_status CHECK (accreditation_status IN ('unknown', 'accredited', 'not_accredited', 'suspended', 'expired')),
+    ADD CONSTRAINT chk_contractors_accreditation_source CHECK (accreditation_source IN ('import', 'manual')),
+    ADD CONSTRAINT chk_contractors_accreditation_documents CHECK (jsonb_typeof(accreditation_documents) = 'array');
+
+COMMENT ON COLUMN contractors.accreditation_status IS 'unknown, accredited, not_accredited, suspended, expired (срок истёк, выставляет планировщик)';
+COMMENT ON COLUMN contractors.accreditation_valid_until IS 'Последний день действия аккредитации; NULL — бессрочно или неизвестно';
+COMMENT ON COLUMN contractors.accreditation_documents IS 'Подтверждающие документы: [{"title", "number", "issued_on", "valid_until", "url"}]';
+COMMENT ON COLUMN contractors.accreditation_source IS 'import — статус из данных импорта, manual — внесён вручную и импортом не меняется';
+COMMENT ON COLUMN contractors.accreditation_alerted_until IS 'Срок, о приближении которого уже оповещали';
+
+-- Истекающие аккредитации: задание планировщика и GET /api/v1/contractors/expiring-accreditation
+CREATE INDEX idx_contractors_accreditation_valid_until
+ON contractors(accreditation_valid_until, id)
+WHERE accreditation_valid_until IS NOT NULL AND accreditation_status IN ('accredited', 'expired');
+
+ALTER TABLE notifications DROP CONSTRAINT chk_notifications_kind;
+ALTER TABLE notifications ADD CONSTRAINT chk_notifications_kind CHECK (kind IN (
+    'tender_new_proposals', 'tender_reimported', 'tender_winner_changed',
+    'lot_ai_analysis_completed', 'merge_review_pending', 'tender_synced',
+    'contractor_accreditation_expiring'
+));
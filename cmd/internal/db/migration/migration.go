@@ -0,0 +1,45 @@
+// Package migration встраивает SQL-миграции в бинарник и применяет их.
+//
+// Формат и таблица версий — golang-migrate (schema_migrations), поэтому
+// встроенный мигратор (подкоманда migrate) и CLI migrate (make migrateup)
+// взаимозаменяемы. При старте сервер сверяет версию схемы со встроенными
+// миграциями (ReadStatus) и не запускается на отстающей схеме.
+package migration
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// LatestVersion возвращает номер последней миграции (000017_xxx.up.sql → 17).
+func LatestVersion() (uint, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return 0, fmt.Errorf("read embedded migrations: %w", err)
+	}
+
+	var latest uint
+	for _, entry := range entries {
+		prefix, _, found := strings.Cut(entry.Name(), "_")
+		if !found {
+			return 0, fmt.Errorf("migration file %q has no version prefix", entry.Name())
+		}
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("migration file %q: invalid version: %w", entry.Name(), err)
+		}
+		if uint(version) > latest {
+			latest = uint(version)
+		}
+	}
+	if latest == 0 {
+		return 0, fmt.Errorf("no embedded migrations found")
+	}
+	return latest, nil
+}
@@ -0,0 +1,90 @@
+package migration
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	pgxmigrate "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// Migrator применяет встроенные миграции через golang-migrate.
+// Параллельные запуски сериализуются advisory-блокировкой PostgreSQL.
+type Migrator struct {
+	m *migrate.Migrate
+}
+
+// NewMigrator создаёт мигратор поверх conn. conn переходит во владение
+// Migrator: Close закрывает его, поэтому передавать соединение Store нельзя.
+func NewMigrator(conn *sql.DB, logger logging.Logger) (*Migrator, error) {
+	source, err := iofs.New(files, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read embedded migrations: %w", err)
+	}
+	driver, err := pgxmigrate.WithInstance(conn, &pgxmigrate.Config{})
+	if err != nil {
+		source.Close()
+		return nil, fmt.Errorf("init migration driver: %w", err)
+	}
+	m, err := migrate.NewWithInstance("iofs", source, "postgres", driver)
+	if err != nil {
+		source.Close()
+		driver.Close()
+		return nil, fmt.Errorf("init migrator: %w", err)
+	}
+	m.Log = migrateLogger{logger}
+	return &Migrator{m: m}, nil
+}
+
+// Up применяет все неприменённые миграции. Актуальная схема — не ошибка.
+func (m *Migrator) Up() error {
+	if err := m.m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// Down откатывает steps последних миграций.
+func (m *Migrator) Down(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("number of steps must be positive (got: %d)", steps)
+	}
+	return m.m.Steps(-steps)
+}
+
+// Force записывает версию без выполнения миграций и снимает флаг dirty —
+// после того как упавшая миграция исправлена вручную.
+func (m *Migrator) Force(version int) error {
+	return m.m.Force(version)
+}
+
+// Version возвращает текущую версию схемы; 0 — миграции не применялись.
+func (m *Migrator) Version() (uint, bool, error) {
+	version, dirty, err := m.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// Close освобождает источник миграций и закрывает соединение с БД.
+func (m *Migrator) Close() error {
+	sourceErr, dbErr := m.m.Close()
+	return errors.Join(sourceErr, dbErr)
+}
+
+// migrateLogger передаёт лог golang-migrate в логгер приложения.
+type migrateLogger struct {
+	logger logging.Logger
+}
+
+func (l migrateLogger) Printf(format string, v ...any) {
+	l.logger.Info(strings.TrimSuffix(fmt.Sprintf(format, v...), "\n"))
+}
+
+func (l migrateLogger) Verbose() bool { return false }
@@ -0,0 +1,60 @@
+// Purpose: Applies and rolls back the embedded migrations on a real PostgreSQL database.
+
+//go:build integration
+
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR EMBEDDED MIGRATIONS (Integration Tests)
+
+SCENARIO 1: Up / Down
+- GIVEN an empty database
+  WHEN Up is called
+  THEN the schema is at LatestVersion, ReadStatus reports no error
+- GIVEN Up is called again → no change, no error
+- GIVEN Down(1) → version LatestVersion-1, ReadStatus reports the schema is behind
+*/
+
+func TestMigrator_UpDown(t *testing.T) {
+	conn, container, err := testutil.SetupTestDatabase(t)
+	require.NoError(t, err)
+	defer testutil.TeardownTestDatabase(t, conn, container)
+
+	// Мигратор закрывает своё соединение сам
+	migratorConn, err := sql.Open("pgx", container.DSN)
+	require.NoError(t, err)
+	migrator, err := NewMigrator(migratorConn, testutil.NewMockLogger())
+	require.NoError(t, err)
+	defer migrator.Close()
+
+	latest, err := LatestVersion()
+	require.NoError(t, err)
+
+	require.NoError(t, migrator.Up())
+	require.NoError(t, migrator.Up(), "second Up must be a no-op")
+	version, dirty, err := migrator.Version()
+	require.NoError(t, err)
+	assert.Equal(t, latest, version)
+	assert.False(t, dirty)
+
+	status, err := ReadStatus(context.Background(), conn)
+	require.NoError(t, err)
+	assert.NoError(t, status.Err())
+
+	require.NoError(t, migrator.Down(1))
+	status, err = ReadStatus(context.Background(), conn)
+	require.NoError(t, err)
+	assert.Equal(t, latest-1, status.Current)
+	assert.True(t, status.Behind())
+}
@@ -0,0 +1,54 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/db/postgres"
+)
+
+// Status — версия схемы БД относительно миграций, встроенных в бинарник.
+type Status struct {
+	Current  uint // версия из schema_migrations; 0 — миграции не применялись
+	Expected uint // последняя встроенная миграция
+	Dirty    bool // последняя миграция упала на середине
+}
+
+// Behind сообщает, что в БД применены не все встроенные миграции.
+func (s Status) Behind() bool { return s.Current < s.Expected }
+
+// Ahead сообщает, что схема новее сборки — так бывает во время выкатки,
+// когда старая версия сервиса работает с уже обновлённой схемой.
+func (s Status) Ahead() bool { return s.Current > s.Expected }
+
+// Err возвращает ошибку, если с такой схемой работать нельзя: миграция
+// применена не полностью или применены не все миграции.
+func (s Status) Err() error {
+	switch {
+	case s.Dirty:
+		return fmt.Errorf("миграция %d применена не полностью (dirty)", s.Current)
+	case s.Current == 0:
+		return fmt.Errorf("миграции не применялись, ожидается версия %d", s.Expected)
+	case s.Behind():
+		return fmt.Errorf("есть непримененные миграции: версия БД %d, ожидается %d", s.Current, s.Expected)
+	}
+	return nil
+}
+
+// ReadStatus читает версию схемы из schema_migrations. Отсутствие таблицы
+// или записи в ней означает, что миграции не применялись (Current == 0).
+func ReadStatus(ctx context.Context, conn *sql.DB) (Status, error) {
+	expected, err := LatestVersion()
+	if err != nil {
+		return Status{}, err
+	}
+
+	status := Status{Expected: expected}
+	err = conn.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&status.Current, &status.Dirty)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) && !postgres.IsUndefinedTable(err) {
+		return Status{}, fmt.Errorf("ошибка чтения schema_migrations: %w", err)
+	}
+	return status, nil
+}
@@ -0,0 +1,136 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR SCHEMA STATUS (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Schema drift — a server must not start on a database that is behind the
+   embedded migrations and fail later with SQL errors
+2. Broken rollouts — a schema newer than the build must not block the old version
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Embedded migrations
+- GIVEN the embedded files
+  THEN LatestVersion is positive and every up migration has a down pair
+
+SCENARIO 2: ReadStatus
+- GIVEN a row in schema_migrations → Current and Dirty are read
+- GIVEN no row or no table → Current is 0, no error
+- GIVEN another DB error → wrapped error
+
+SCENARIO 3: Status.Err
+- GIVEN dirty, never migrated or behind → error; current or ahead → nil
+*/
+
+func TestLatestVersion_MatchesEmbeddedPairs(t *testing.T) {
+	latest, err := LatestVersion()
+	require.NoError(t, err)
+	assert.Positive(t, latest)
+
+	ups, err := fs.Glob(files, "*.up.sql")
+	require.NoError(t, err)
+	downs, err := fs.Glob(files, "*.down.sql")
+	require.NoError(t, err)
+	assert.Len(t, downs, len(ups), "each up migration needs a down migration")
+}
+
+func TestReadStatus(t *testing.T) {
+	latest, err := LatestVersion()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		setup   func(mock sqlmock.Sqlmock)
+		want    Status
+		wantErr string
+	}{
+		{
+			name: "applied",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+					WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(int64(latest-1), true))
+			},
+			want: Status{Current: latest - 1, Expected: latest, Dirty: true},
+		},
+		{
+			name: "no row",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+					WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}))
+			},
+			want: Status{Expected: latest},
+		},
+		{
+			name: "no table",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+					WillReturnError(&pgconn.PgError{Code: "42P01"})
+			},
+			want: Status{Expected: latest},
+		},
+		{
+			name: "db error",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+					WillReturnError(errors.New("connection reset"))
+			},
+			wantErr: "ошибка чтения schema_migrations: connection reset",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer conn.Close()
+			tt.setup(mock)
+
+			status, err := ReadStatus(context.Background(), conn)
+
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, status)
+		})
+	}
+}
+
+func TestStatus_Err(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  Status
+		wantErr string
+	}{
+		{"current", Status{Current: 26, Expected: 26}, ""},
+		{"ahead", Status{Current: 27, Expected: 26}, ""},
+		{"behind", Status{Current: 25, Expected: 26}, "версия БД 25, ожидается 26"},
+		{"never migrated", Status{Expected: 26}, "миграции не применялись"},
+		{"dirty", Status{Current: 26, Expected: 26, Dirty: true}, "dirty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.status.Err()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
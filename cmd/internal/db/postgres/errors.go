@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+// SQLSTATE ошибок, которые обрабатываются отдельно.
+const (
+	foreignKeyViolation  = "23503"
+	uniqueViolation      = "23505"
+	undefinedTable       = "42P01"
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+)
+
+// IsUniqueViolation сообщает, нарушено ли ограничение уникальности. Понимает
+// ошибки обоих драйверов: *pgconn.PgError (pgx) и *pq.Error (lib/pq).
+func IsUniqueViolation(err error) bool {
+	return errorCode(err) == uniqueViolation
+}
+
+// IsForeignKeyViolation сообщает, что запись ссылается на несуществующую строку.
+func IsForeignKeyViolation(err error) bool {
+	return errorCode(err) == foreignKeyViolation
+}
+
+// errorCode возвращает SQLSTATE ошибки PostgreSQL или "", если err не от сервера.
+func errorCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return string(pqErr.Code)
+	}
+	return ""
+}
+
+// IsUndefinedTable сообщает, что запрос обратился к несуществующей таблице.
+func IsUndefinedTable(err error) bool {
+	return errorCode(err) == undefinedTable
+}
+
+// IsTransactionConflict сообщает, что транзакция прервана из-за параллельной:
+// взаимная блокировка или сбой сериализации. Такую транзакцию можно повторить.
+func IsTransactionConflict(err error) bool {
+	code := errorCode(err)
+	return code == deadlockDetected || code == serializationFailure
+}
@@ -0,0 +1,111 @@
+// Package postgres открывает подключение к PostgreSQL по config.DatabaseConfig
+// и распознаёт ошибки PostgreSQL независимо от драйвера.
+//
+// Оба драйвера отдают *sql.DB: sqlc-код (db.Querier, db.Store) работает поверх
+// database/sql и не зависит от того, какой драйвер под ним.
+//   - pgx (по умолчанию) — пул pgxpool, обёрнутый stdlib.OpenDBFromPool: пулом
+//     управляет pgxpool, database/sql не держит у себя простаивающих соединений.
+//     Отмена контекста прерывает запрос на сервере. Повторяющиеся запросы
+//     (upsert позиций при импорте, поиск позиции каталога, кэш сопоставления)
+//     выполняются подготовленными операторами из кэша соединения, см.
+//     database.query_exec_mode.
+//   - postgres — lib/pq со встроенным пулом database/sql; оставлен как запасной
+//     вариант на время перехода.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	_ "github.com/lib/pq" // драйвер "postgres"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+)
+
+// Open открывает пул соединений и проверяет доступность БД.
+// Возвращённую функцию закрытия нужно вызвать после conn.Close: пул pgxpool
+// не закрывается вместе с *sql.DB.
+func Open(ctx context.Context, cfg config.DatabaseConfig) (*sql.DB, func(), error) {
+	if cfg.Driver == "postgres" {
+		conn, err := sql.Open("postgres", cfg.Source)
+		if err != nil {
+			return nil, nil, err
+		}
+		conn.SetMaxOpenConns(cfg.MaxOpenConns)
+		conn.SetMaxIdleConns(cfg.MaxIdleConns)
+		conn.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+		conn.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+		if err := conn.PingContext(ctx); err != nil {
+			conn.Close()
+			return nil, nil, err
+		}
+		return conn, func() { conn.Close() }, nil
+	}
+
+	poolCfg, err := poolConfig(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, nil, err
+	}
+	conn := stdlib.OpenDBFromPool(pool)
+	return conn, func() {
+		conn.Close()
+		pool.Close()
+	}, nil
+}
+
+// queryExecModes сопоставляет database.query_exec_mode режимам pgx.
+var queryExecModes = map[string]pgx.QueryExecMode{
+	"cache_statement": pgx.QueryExecModeCacheStatement,
+	"cache_describe":  pgx.QueryExecModeCacheDescribe,
+	"describe_exec":   pgx.QueryExecModeDescribeExec,
+	"exec":            pgx.QueryExecModeExec,
+	"simple_protocol": pgx.QueryExecModeSimpleProtocol,
+}
+
+// poolConfig переносит настройки пула в pgxpool.Config. У pgxpool нет лимита
+// простаивающих соединений, поэтому max_idle_conns здесь не действует:
+// минимальный размер пула задаёт min_conns, соединения сверх него
+// закрываются через conn_max_idle_time.
+//
+// В режиме cache_statement запрос при первом выполнении на соединении
+// подготавливается (Parse/Describe), дальше выполняется по имени без разбора
+// и планирования заново: для горячих запросов вроде UpsertPositionItem это
+// заметная часть времени (make bench-db). Кэш ограничен
+// statement_cache_capacity операторами на соединение и вытесняет самые
+// давние, поэтому часто выполняемые запросы в нём остаются. После миграции,
+// меняющей типы колонок, pgx сам переподготавливает оператор.
+func poolConfig(cfg config.DatabaseConfig) (*pgxpool.Config, error) {
+	poolCfg, err := pgxpool.ParseConfig(cfg.Source)
+	if err != nil {
+		return nil, fmt.Errorf("некорректная строка подключения: %w", err)
+	}
+	poolCfg.MaxConns = int32(cfg.MaxOpenConns)
+	poolCfg.MinConns = int32(cfg.MinConns)
+	poolCfg.MaxConnLifetime = cfg.ConnMaxLifetime
+	poolCfg.MaxConnIdleTime = cfg.ConnMaxIdleTime
+
+	if cfg.QueryExecMode != "" {
+		mode, ok := queryExecModes[cfg.QueryExecMode]
+		if !ok {
+			return nil, fmt.Errorf("неизвестный query_exec_mode %q", cfg.QueryExecMode)
+		}
+		poolCfg.ConnConfig.DefaultQueryExecMode = mode
+	}
+	if cfg.StatementCacheCapacity > 0 {
+		poolCfg.ConnConfig.StatementCacheCapacity = cfg.StatementCacheCapacity
+		poolCfg.ConnConfig.DescriptionCacheCapacity = cfg.StatementCacheCapacity
+	}
+	return poolCfg, nil
+}
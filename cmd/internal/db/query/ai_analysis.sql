@@ -0,0 +1,34 @@
+-- ai_analysis.sql
+-- История запусков AI-анализа лотов. Каждый POST ai-results от воркера
+-- сохраняется отдельной строкой; lots.lot_key_parameters — копия параметров
+-- последнего применённого (promoted) запуска.
+
+-- name: CreateAIAnalysisResult :one
+-- Сохраняет запуск AI-анализа. promoted_at выставляется сразу: параметры нового
+-- запуска записываются в лот в той же транзакции.
+INSERT INTO ai_analysis_results (
+    lot_id,
+    model_name,
+    prompt_version,
+    raw_output,
+    key_parameters,
+    promoted_at
+) VALUES (
+    $1, $2, $3, $4, $5, NOW()
+)
+RETURNING *;
+
+-- name: ListAIAnalysisResultsByLot :many
+-- Запуски AI-анализа лота, новые первыми.
+SELECT * FROM ai_analysis_results
+WHERE lot_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: PromoteAIAnalysisResult :one
+-- Отмечает запуск как применённый к лоту. Запуск ищется в пределах лота:
+-- sql.ErrNoRows — запуска нет или он относится к другому лоту.
+UPDATE ai_analysis_results
+SET promoted_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND lot_id = sqlc.arg(lot_id)
+RETURNING *;
@@ -0,0 +1,420 @@
+-- analytics.sql
+--
+-- Агрегаты для аналитики стоимости по лоту (GET /api/v1/lots/:id/analytics)
+-- и история цен позиции каталога (GET /api/v1/catalog/:id/price-history),
+-- удельные цены по ключевому параметру лотов (GET /api/v1/analytics/lots/compare).
+-- Итог предложения — строка сводной таблицы summary_key = 'total_cost_with_vat'.
+-- Baseline (смета инициатора) в статистику предложений не входит и используется
+-- только как база для отклонений.
+--
+-- Агрегаты по лоту считаются в базовой валюте. Курсы передаются параллельными
+-- массивами currencies/rates: стоимость единицы валюты в базовой (у базовой — 1).
+-- Валюта суммы — своя валюта строки, если указана, иначе валюта предложения.
+
+-- name: GetLotCostStats :one
+-- Статистика итогов предложений подрядчиков по лоту в базовой валюте.
+-- Предложения без итога не учитываются (COUNT по total_cost).
+-- Итог в валюте без курса тоже не учитывается, а считается в unconverted_proposals_count.
+-- Медиана считается через percentile_cont (double precision) и округляется до копеек.
+WITH fx AS (
+    SELECT
+        unnest(sqlc.arg(currencies)::text[]) AS currency,
+        unnest(sqlc.arg(rates)::numeric[]) AS rate
+),
+totals AS (
+    SELECT
+        p.is_baseline,
+        psl.total_cost AS original_total,
+        psl.total_cost * fx.rate AS total_cost
+    FROM proposals p
+    LEFT JOIN proposal_summary_lines psl
+        ON psl.proposal_id = p.id AND psl.summary_key = 'total_cost_with_vat'
+    LEFT JOIN fx ON fx.currency = COALESCE(psl.currency, p.currency)
+    WHERE p.lot_id = sqlc.arg(lot_id)
+)
+SELECT
+    (SELECT bt.total_cost
+     FROM totals bt
+     WHERE bt.is_baseline AND bt.original_total IS NOT NULL
+     LIMIT 1)::numeric AS baseline_total,
+    COUNT(t.total_cost) AS priced_proposals_count,
+    COUNT(t.original_total) FILTER (WHERE t.total_cost IS NULL) AS unconverted_proposals_count,
+    MIN(t.total_cost)::numeric AS min_total,
+    MAX(t.total_cost)::numeric AS max_total,
+    ROUND(AVG(t.total_cost), 2)::numeric AS avg_total,
+    ROUND((percentile_cont(0.5) WITHIN GROUP (ORDER BY t.total_cost))::numeric, 2)::numeric AS median_total,
+    ROUND((MAX(t.total_cost) - MIN(t.total_cost)) / NULLIF(MIN(t.total_cost), 0) * 100, 2)::numeric AS spread_percent
+FROM totals t
+WHERE NOT t.is_baseline;
+
+-- name: ListLotTotalsByLotIDs :many
+-- Краткие агрегаты по нескольким лотам сразу (страница тендера, GET /api/v1/tenders/:id):
+-- итог baseline, лучшее (минимальное) предложение подрядчика, число предложений
+-- и экономия относительно baseline: savings_amount = baseline - best_offer,
+-- savings_percent = savings_amount / baseline * 100. Суммы — в базовой валюте,
+-- правила пересчёта те же, что в GetLotCostStats. Лоты без предложений
+-- (и без baseline) в результат не попадают.
+WITH fx AS (
+    SELECT
+        unnest(sqlc.arg(currencies)::text[]) AS currency,
+        unnest(sqlc.arg(rates)::numeric[]) AS rate
+),
+totals AS (
+    SELECT
+        p.lot_id,
+        p.is_baseline,
+        psl.total_cost AS original_total,
+        psl.total_cost * fx.rate AS total_cost
+    FROM proposals p
+    LEFT JOIN proposal_summary_lines psl
+        ON psl.proposal_id = p.id AND psl.summary_key = 'total_cost_with_vat'
+    LEFT JOIN fx ON fx.currency = COALESCE(psl.currency, p.currency)
+    WHERE p.lot_id = ANY(sqlc.arg(lot_ids)::bigint[])
+),
+per_lot AS (
+    SELECT
+        t.lot_id,
+        MIN(t.total_cost) FILTER (WHERE t.is_baseline AND t.original_total IS NOT NULL) AS baseline_total,
+        MIN(t.total_cost) FILTER (WHERE NOT t.is_baseline) AS best_offer,
+        COUNT(*) FILTER (WHERE NOT t.is_baseline) AS proposals_count,
+        COUNT(t.total_cost) FILTER (WHERE NOT t.is_baseline) AS priced_proposals_count
+    FROM totals t
+    GROUP BY t.lot_id
+)
+SELECT
+    pl.lot_id,
+    pl.baseline_total::numeric AS baseline_total,
+    pl.best_offer::numeric AS best_offer,
+    pl.proposals_count,
+    pl.priced_proposals_count,
+    (pl.baseline_total - pl.best_offer)::numeric AS savings_amount,
+    ROUND((pl.baseline_total - pl.best_offer) / NULLIF(pl.baseline_total, 0) * 100, 2)::numeric AS savings_percent
+FROM per_lot pl
+ORDER BY pl.lot_id;
+
+-- name: ListLotContractorDeviations :many
+-- Итог каждого предложения подрядчика в исходной валюте (original_total_cost, currency),
+-- в базовой валюте (total_cost) и его отклонение от baseline в базовой валюте:
+-- deviation_amount = total - baseline, deviation_percent = (total - baseline) / baseline * 100.
+-- Если итога, курса или baseline нет, отклонения NULL. Сортировка — от дешёвых к дорогим.
+WITH fx AS (
+    SELECT
+        unnest(sqlc.arg(currencies)::text[]) AS currency,
+        unnest(sqlc.arg(rates)::numeric[]) AS rate
+),
+baseline AS (
+    SELECT bpsl.total_cost * bfx.rate AS total_cost
+    FROM proposals bp
+    JOIN proposal_summary_lines bpsl
+      ON bpsl.proposal_id = bp.id AND bpsl.summary_key = 'total_cost_with_vat'
+    LEFT JOIN fx bfx ON bfx.currency = COALESCE(bpsl.currency, bp.currency)
+    WHERE bp.lot_id = sqlc.arg(lot_id) AND bp.is_baseline = true
+    LIMIT 1
+)
+SELECT
+    p.id AS proposal_id,
+    p.contractor_id,
+    c.title AS contractor_name,
+    c.inn AS contractor_inn,
+    COALESCE(psl.currency, p.currency)::text AS currency,
+    psl.total_cost AS original_total_cost,
+    (psl.total_cost * fx.rate)::numeric AS total_cost,
+    (psl.total_cost * fx.rate - b.total_cost)::numeric AS deviation_amount,
+    ROUND((psl.total_cost * fx.rate - b.total_cost) / NULLIF(b.total_cost, 0) * 100, 2)::numeric AS deviation_percent
+FROM proposals p
+JOIN contractors c ON c.id = p.contractor_id
+LEFT JOIN proposal_summary_lines psl
+    ON psl.proposal_id = p.id AND psl.summary_key = 'total_cost_with_vat'
+LEFT JOIN fx ON fx.currency = COALESCE(psl.currency, p.currency)
+LEFT JOIN baseline b ON true
+WHERE p.lot_id = sqlc.arg(lot_id)
+  AND p.is_baseline = false
+ORDER BY total_cost ASC NULLS LAST, p.id ASC;
+
+-- name: CountLotCheapestPositions :many
+-- Для каждого предложения подрядчика: сколько позиций каталога оно оценило
+-- (priced_positions_count) и в скольких из них оказалось самым дешёвым
+-- (cheapest_positions_count). Позиции сопоставляются по catalog_position_id,
+-- повторы позиции внутри КП суммируются. Стоимости сравниваются в базовой валюте;
+-- позиции в валюте без курса не учитываются. Учитываются только позиции, которые
+-- оценили минимум два подрядчика; при равной цене самыми дешёвыми считаются все.
+WITH fx AS (
+    SELECT
+        unnest(sqlc.arg(currencies)::text[]) AS currency,
+        unnest(sqlc.arg(rates)::numeric[]) AS rate
+),
+per_proposal AS (
+    SELECT
+        pi.catalog_position_id,
+        pi.proposal_id,
+        SUM(pi.total_cost_total * fx.rate) AS total
+    FROM position_items pi
+    JOIN proposals p ON p.id = pi.proposal_id
+    JOIN fx ON fx.currency = COALESCE(pi.currency, p.currency)
+    WHERE p.lot_id = sqlc.arg(lot_id)
+      AND p.is_baseline = false
+      AND pi.is_chapter = false
+      AND pi.catalog_position_id IS NOT NULL
+      AND pi.total_cost_total IS NOT NULL
+    GROUP BY pi.catalog_position_id, pi.proposal_id
+),
+ranked AS (
+    SELECT
+        proposal_id,
+        total,
+        MIN(total) OVER (PARTITION BY catalog_position_id) AS min_total,
+        COUNT(*) OVER (PARTITION BY catalog_position_id) AS offers
+    FROM per_proposal
+)
+SELECT
+    proposal_id,
+    COUNT(*) FILTER (WHERE offers > 1) AS priced_positions_count,
+    COUNT(*) FILTER (WHERE offers > 1 AND total = min_total) AS cheapest_positions_count
+FROM ranked
+GROUP BY proposal_id;
+
+-- name: ListCatalogPositionPriceHistory :many
+-- Все оценённые подрядчиками позиции тендеров, сопоставленные с позицией каталога.
+-- Дата цены — дата подготовки тендера, при её отсутствии — дата импорта;
+-- регион тендера и квартал (как в ListCatalogPositionQuarterlyPrices) нужны
+-- для пересчёта по индексам цен. Baseline, заголовки разделов, позиции без
+-- цены и мягко удалённые тендеры не входят.
+-- organization_id ограничивает выборку тендерами организации (NULL — все).
+SELECT
+    pi.id AS position_item_id,
+    t.id AS tender_id,
+    t.etp_id AS tender_etp_id,
+    t.title AS tender_title,
+    l.id AS lot_id,
+    COALESCE(t.data_prepared_on_date, t.created_at)::timestamptz AS price_date,
+    c.id AS contractor_id,
+    c.title AS contractor_name,
+    c.inn AS contractor_inn,
+    pi.job_title_in_proposal,
+    pi.quantity,
+    pi.unit_cost_total AS unit_cost,
+    u.normalized_name AS unit_name,
+    t.region AS tender_region,
+    to_char(date_trunc('quarter', COALESCE(t.data_prepared_on_date, t.created_at)), 'YYYY-"Q"Q')::text AS quarter
+FROM position_items pi
+JOIN proposals p ON p.id = pi.proposal_id
+JOIN lots l ON l.id = p.lot_id
+JOIN tenders t ON t.id = l.tender_id
+JOIN contractors c ON c.id = p.contractor_id
+LEFT JOIN units_of_measurement u ON u.id = pi.unit_id
+WHERE pi.catalog_position_id = sqlc.arg(catalog_position_id)
+  AND pi.is_chapter = false
+  AND pi.unit_cost_total IS NOT NULL
+  AND p.is_baseline = false
+  AND t.deleted_at IS NULL
+  AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint)
+ORDER BY price_date DESC, pi.id DESC;
+
+-- name: ListCatalogPositionQuarterlyPrices :many
+-- min/avg/max цены за единицу по кварталам (та же выборка, что и в
+-- ListCatalogPositionPriceHistory). Группировка ещё и по единице измерения:
+-- цены за м² и за м³ усреднять вместе нельзя. Подпись квартала ("2025-Q3")
+-- строится в БД, в том же часовом поясе, что и date_trunc.
+SELECT
+    date_trunc('quarter', COALESCE(t.data_prepared_on_date, t.created_at))::timestamptz AS quarter_start,
+    to_char(date_trunc('quarter', COALESCE(t.data_prepared_on_date, t.created_at)), 'YYYY-"Q"Q')::text AS quarter,
+    u.normalized_name AS unit_name,
+    COUNT(*) AS prices_count,
+    MIN(pi.unit_cost_total)::numeric AS min_unit_cost,
+    ROUND(AVG(pi.unit_cost_total), 2)::numeric AS avg_unit_cost,
+    MAX(pi.unit_cost_total)::numeric AS max_unit_cost
+FROM position_items pi
+JOIN proposals p ON p.id = pi.proposal_id
+JOIN lots l ON l.id = p.lot_id
+JOIN tenders t ON t.id = l.tender_id
+LEFT JOIN units_of_measurement u ON u.id = pi.unit_id
+WHERE pi.catalog_position_id = sqlc.arg(catalog_position_id)
+  AND pi.is_chapter = false
+  AND pi.unit_cost_total IS NOT NULL
+  AND p.is_baseline = false
+  AND t.deleted_at IS NULL
+  AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint)
+GROUP BY quarter_start, quarter, u.normalized_name
+ORDER BY quarter_start, u.normalized_name;
+
+-- name: SearchPositionItems :many
+-- Поиск позиций КП по названию во всех тендерах: подстрока (pattern — уже
+-- экранированный шаблон ILIKE) или нечёткое совпадение по словам (pg_trgm, <%).
+-- Сначала самые похожие, затем новые. Baseline входит с is_baseline = true.
+-- catalog_position_id, min_cost/max_cost (цена за единицу в валюте позиции) и
+-- organization_id — необязательные фильтры. Заголовки разделов и мягко
+-- удалённые тендеры не входят. after_score / after_date / after_id — keyset-курсор:
+-- позиции строго после ключа сортировки последней строки предыдущей страницы
+-- (тогда page_offset = 0).
+SELECT
+    pi.id AS position_item_id,
+    pi.job_title_in_proposal,
+    pi.catalog_position_id,
+    pi.quantity,
+    pi.unit_cost_total AS unit_cost,
+    pi.total_cost_total AS total_cost,
+    COALESCE(pi.currency, p.currency)::text AS currency,
+    u.normalized_name AS unit_name,
+    p.id AS proposal_id,
+    p.is_baseline,
+    l.id AS lot_id,
+    l.lot_title,
+    t.id AS tender_id,
+    t.etp_id AS tender_etp_id,
+    t.title AS tender_title,
+    COALESCE(t.data_prepared_on_date, t.created_at)::timestamptz AS tender_date,
+    c.id AS contractor_id,
+    c.title AS contractor_name,
+    c.inn AS contractor_inn,
+    word_similarity(sqlc.arg(query)::text, pi.job_title_in_proposal)::real AS score
+FROM position_items pi
+JOIN proposals p ON p.id = pi.proposal_id
+JOIN lots l ON l.id = p.lot_id
+JOIN tenders t ON t.id = l.tender_id
+JOIN contractors c ON c.id = p.contractor_id
+LEFT JOIN units_of_measurement u ON u.id = pi.unit_id
+WHERE (pi.job_title_in_proposal ILIKE sqlc.arg(pattern)::text OR sqlc.arg(query)::text <% pi.job_title_in_proposal)
+  AND pi.is_chapter = false
+  AND t.deleted_at IS NULL
+  AND (sqlc.narg(catalog_position_id)::bigint IS NULL OR pi.catalog_position_id = sqlc.narg(catalog_position_id)::bigint)
+  AND (sqlc.narg(min_cost)::numeric IS NULL OR pi.unit_cost_total >= sqlc.narg(min_cost)::numeric)
+  AND (sqlc.narg(max_cost)::numeric IS NULL OR pi.unit_cost_total <= sqlc.narg(max_cost)::numeric)
+  AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint)
+  AND (sqlc.narg(after_id)::bigint IS NULL
+       OR (word_similarity(sqlc.arg(query)::text, pi.job_title_in_proposal)::real, COALESCE(t.data_prepared_on_date, t.created_at)::timestamptz, pi.id)
+          < (sqlc.narg(after_score)::real, sqlc.narg(after_date)::timestamptz, sqlc.narg(after_id)::bigint))
+ORDER BY score DESC, tender_date DESC, pi.id DESC
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: CountSearchPositionItems :one
+-- Общее число позиций с тем же фильтром, что и SearchPositionItems.
+SELECT COUNT(*)
+FROM position_items pi
+JOIN proposals p ON p.id = pi.proposal_id
+JOIN lots l ON l.id = p.lot_id
+JOIN tenders t ON t.id = l.tender_id
+WHERE (pi.job_title_in_proposal ILIKE sqlc.arg(pattern)::text OR sqlc.arg(query)::text <% pi.job_title_in_proposal)
+  AND pi.is_chapter = false
+  AND t.deleted_at IS NULL
+  AND (sqlc.narg(catalog_position_id)::bigint IS NULL OR pi.catalog_position_id = sqlc.narg(catalog_position_id)::bigint)
+  AND (sqlc.narg(min_cost)::numeric IS NULL OR pi.unit_cost_total >= sqlc.narg(min_cost)::numeric)
+  AND (sqlc.narg(max_cost)::numeric IS NULL OR pi.unit_cost_total <= sqlc.narg(max_cost)::numeric)
+  AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint);
+
+-- name: ListLotWorkGroupCosts :many
+-- Стоимость позиций каждого предложения лота (включая baseline) по узлам
+-- классификатора видов работ в базовой валюте. Узел позиции — work_group_code
+-- позиции каталога, а у варианта без своего кода — код родителя (GROUP_TITLE).
+-- Позиции без сопоставления или без кода попадают в work_group_code = ''.
+-- Позиции в валюте без курса не учитываются. Свёртка по дереву — в сервисе.
+WITH fx AS (
+    SELECT
+        unnest(sqlc.arg(currencies)::text[]) AS currency,
+        unnest(sqlc.arg(rates)::numeric[]) AS rate
+)
+SELECT
+    p.id AS proposal_id,
+    p.is_baseline,
+    c.title AS contractor_name,
+    COALESCE(cp.work_group_code, parent.work_group_code, '')::text AS work_group_code,
+    SUM(pi.total_cost_total * fx.rate)::numeric AS total_cost,
+    COUNT(*) AS positions_count
+FROM position_items pi
+JOIN proposals p ON p.id = pi.proposal_id
+JOIN contractors c ON c.id = p.contractor_id
+JOIN fx ON fx.currency = COALESCE(pi.currency, p.currency)
+LEFT JOIN catalog_positions cp ON cp.id = pi.catalog_position_id
+LEFT JOIN catalog_positions parent ON parent.id = cp.parent_id
+WHERE p.lot_id = sqlc.arg(lot_id)
+  AND pi.is_chapter = false
+  AND pi.total_cost_total IS NOT NULL
+GROUP BY p.id, p.is_baseline, c.title, 4
+ORDER BY p.is_baseline DESC, p.id, 4;
+
+-- name: ListLotPositionPricesForAnomalies :many
+-- Цены за единицу позиций предложений подрядчиков лота в базовой валюте для
+-- поиска подозрительных цен. Только позиции, сопоставленные с каталогом:
+-- позиции разных предложений сравниваются по catalog_position_id и единице
+-- измерения. Baseline, заголовки и позиции в валюте без курса не входят.
+WITH fx AS (
+    SELECT
+        unnest(sqlc.arg(currencies)::text[]) AS currency,
+        unnest(sqlc.arg(rates)::numeric[]) AS rate
+)
+SELECT
+    pi.id AS position_item_id,
+    p.id AS proposal_id,
+    c.id AS contractor_id,
+    c.title AS contractor_name,
+    pi.catalog_position_id::bigint AS catalog_position_id,
+    pi.unit_id,
+    u.normalized_name AS unit_name,
+    pi.position_key_in_proposal,
+    pi.job_title_in_proposal,
+    (pi.unit_cost_total * fx.rate)::numeric AS unit_cost
+FROM position_items pi
+JOIN proposals p ON p.id = pi.proposal_id
+JOIN contractors c ON c.id = p.contractor_id
+JOIN fx ON fx.currency = COALESCE(pi.currency, p.currency)
+LEFT JOIN units_of_measurement u ON u.id = pi.unit_id
+WHERE p.lot_id = sqlc.arg(lot_id)
+  AND p.is_baseline = false
+  AND pi.is_chapter = false
+  AND pi.catalog_position_id IS NOT NULL
+  AND pi.unit_cost_total IS NOT NULL
+ORDER BY pi.catalog_position_id, p.id, pi.id;
+
+-- name: ListLotKeyParameterValues :many
+-- Значение ключевого параметра parameter у лотов тендеров категории и цена
+-- победителя лота в базовой валюте — для удельных цен (за сваю, за м² фасада)
+-- в GET /api/v1/analytics/lots/compare. Значение возвращается текстом вместе с
+-- типом JSON (jsonb_typeof): число из строки вида "1 200 м²" разбирает сервис.
+-- Победитель — лучший по rank; цена — цена контракта (winners.award_price, в
+-- рублях), а если её нет — итог его предложения с НДС. Лоты без победителя
+-- возвращаются с winning_price = NULL и пустым contractor_name. Мягко
+-- удалённые тендеры не учитываются.
+WITH fx AS (
+    SELECT
+        unnest(sqlc.arg(currencies)::text[]) AS currency,
+        unnest(sqlc.arg(rates)::numeric[]) AS rate
+)
+SELECT
+    l.id AS lot_id,
+    l.lot_key,
+    l.lot_title,
+    t.id AS tender_id,
+    t.etp_id AS tender_etp_id,
+    t.title AS tender_title,
+    COALESCE(t.data_prepared_on_date, t.created_at)::timestamptz AS tender_date,
+    jsonb_typeof(l.lot_key_parameters -> sqlc.arg(parameter)::text)::text AS value_type,
+    COALESCE(l.lot_key_parameters ->> sqlc.arg(parameter)::text, '')::text AS value,
+    COALESCE(win.contractor_name, '')::text AS contractor_name,
+    win.winning_price::numeric AS winning_price
+FROM lots l
+JOIN tenders t ON t.id = l.tender_id
+LEFT JOIN LATERAL (
+    SELECT
+        c.title AS contractor_name,
+        COALESCE(
+            w.award_price * rfx.rate,
+            (SELECT psl.total_cost * ofx.rate
+             FROM proposal_summary_lines psl
+             JOIN fx ofx ON ofx.currency = COALESCE(psl.currency, p.currency)
+             WHERE psl.proposal_id = p.id AND psl.summary_key = 'total_cost_with_vat'
+             LIMIT 1)
+        ) AS winning_price
+    FROM winners w
+    JOIN proposals p ON p.id = w.proposal_id
+    JOIN contractors c ON c.id = p.contractor_id
+    LEFT JOIN fx rfx ON rfx.currency = 'RUB'
+    WHERE p.lot_id = l.id AND NOT p.is_baseline
+    ORDER BY w.rank ASC NULLS LAST, w.created_at ASC
+    LIMIT 1
+) win ON true
+WHERE t.category_id = sqlc.arg(category_id)
+  AND t.deleted_at IS NULL
+  AND l.lot_key_parameters -> sqlc.arg(parameter)::text IS NOT NULL
+  AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint)
+ORDER BY COALESCE(t.data_prepared_on_date, t.created_at) DESC, l.id
+LIMIT sqlc.arg(max_lots);
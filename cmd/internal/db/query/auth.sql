@@ -1,13 +1,14 @@
 -- name: GetUserAuthByEmail :one
-SELECT id, email, password_hash, role, is_active, last_login_at, created_at, updated_at
+SELECT id, email, password_hash, role, is_active, organization_id, last_login_at, created_at, updated_at, token_version
 FROM users
 WHERE email = $1
 LIMIT 1;
 
 -- name: CreateUser :one
-INSERT INTO users (email, password_hash, role, is_active)
-VALUES ($1, $2, $3, $4)
-RETURNING id, email, role, is_active, created_at, updated_at;
+-- organization_id = NULL — организация по умолчанию (миграция 000027).
+INSERT INTO users (email, password_hash, role, is_active, organization_id)
+VALUES ($1, $2, $3, $4, COALESCE(sqlc.narg(organization_id)::bigint, (SELECT id FROM organizations WHERE is_default)))
+RETURNING id, email, role, is_active, organization_id, created_at, updated_at;
 
 -- name: UpdateUserLastLogin :exec
 UPDATE users
@@ -33,7 +34,7 @@ SET revoked_at = now()
 WHERE id = $1
   AND revoked_at IS NULL;
 
--- name: RevokeAllActiveSessionsByUserID :exec
+-- name: RevokeAllActiveSessionsByUserID :execrows
 UPDATE user_sessions
 SET revoked_at = now()
 WHERE user_id = $1
@@ -44,32 +45,97 @@ DELETE FROM user_sessions
 WHERE expires_at <= $1;
 
 -- name: GetUserByID :one
-SELECT id, email, role, is_active, last_login_at, created_at, updated_at
+SELECT id, email, role, is_active, organization_id, last_login_at, created_at, updated_at, token_version
 FROM users
 WHERE id = $1
 LIMIT 1;
 
 -- name: ListUsers :many
-SELECT id, email, role, is_active, last_login_at, created_at, updated_at
+SELECT id, email, role, is_active, organization_id, last_login_at, created_at, updated_at
 FROM users
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2;
 
--- name: UpdateUserRole :exec
+-- name: UpdateUserRole :one
+-- Смена роли отзывает выданные ранее access-токены (tokens_revoked_at и
+-- token_version), чтобы права менялись сразу, а не по истечении токена.
 UPDATE users
-SET role = $1, updated_at = now()
-WHERE id = $2;
+SET role = $1, tokens_revoked_at = now(), token_version = token_version + 1, updated_at = now()
+WHERE id = $2
+RETURNING id, email, role, is_active, organization_id, last_login_at, created_at, updated_at, tokens_revoked_at;
 
 -- name: UpdateUserPassword :exec
 UPDATE users
 SET password_hash = $1, updated_at = now()
 WHERE id = $2;
 
--- name: UpdateUserActiveStatus :exec
+-- name: UpdateUserActiveStatus :one
+-- Как и UpdateUserRole, отзывает выданные ранее access-токены.
+UPDATE users
+SET is_active = $1, tokens_revoked_at = now(), token_version = token_version + 1, updated_at = now()
+WHERE id = $2
+RETURNING id, email, role, is_active, organization_id, last_login_at, created_at, updated_at, tokens_revoked_at;
+
+-- name: UpdateUser :one
+-- Частичное обновление из админки (PATCH /api/v1/admin/users/:id): NULL
+-- оставляет поле без изменений. Как и UpdateUserRole, отзывает access-токены:
+-- организация входит в access-токен.
+UPDATE users
+SET email = COALESCE(sqlc.narg(email), email),
+    is_active = COALESCE(sqlc.narg(is_active), is_active),
+    organization_id = COALESCE(sqlc.narg(organization_id), organization_id),
+    tokens_revoked_at = now(),
+    token_version = token_version + 1,
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING id, email, role, is_active, organization_id, last_login_at, created_at, updated_at, tokens_revoked_at;
+
+-- name: GetUserAuthByIDForUpdate :one
+-- Блокирует строку пользователя на время смены пароля.
+SELECT id, email, password_hash, role, is_active, organization_id, token_version
+FROM users
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ChangeUserPassword :exec
+-- Момент отзыва access-токенов передается из приложения, чтобы совпадать
+-- с локальным denylist (см. Service.ChangePassword).
 UPDATE users
-SET is_active = $1, updated_at = now()
+SET password_hash = sqlc.arg(password_hash),
+    tokens_revoked_at = sqlc.arg(tokens_revoked_at)::timestamptz,
+    token_version = token_version + 1,
+    updated_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: ResetUserPassword :exec
+-- Сброс пароля по токену отзывает выданные ранее access-токены.
+UPDATE users
+SET password_hash = $1, tokens_revoked_at = now(), token_version = token_version + 1, updated_at = now()
 WHERE id = $2;
 
+-- name: RevokeUserTokens :one
+-- Выход на всех устройствах: отзывает выданные ранее access-токены.
+-- Момент отзыва передается из приложения, как в ChangeUserPassword.
+UPDATE users
+SET tokens_revoked_at = sqlc.arg(tokens_revoked_at)::timestamptz,
+    token_version = token_version + 1,
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING token_version;
+
+-- name: GetUserTokenVersion :one
+-- Текущая версия access-токенов пользователя (проверяется AuthMiddleware через кэш).
+SELECT token_version
+FROM users
+WHERE id = $1;
+
+-- name: ListUserTokenRevocationsSince :many
+-- Отзывы токенов, которые ещё могут затрагивать действующие access-токены
+-- (since = now() - access TTL). Используется для синхронизации denylist.
+SELECT id, tokens_revoked_at::timestamptz AS tokens_revoked_at
+FROM users
+WHERE tokens_revoked_at > sqlc.arg(since)::timestamptz;
+
 -- name: GetActiveSessionsByUserID :many
 SELECT id, user_agent, ip_address, created_at, expires_at
 FROM user_sessions
@@ -92,3 +158,79 @@ WHERE refresh_token_hash = $1
   AND revoked_at IS NULL
   AND expires_at > now()
 FOR UPDATE;
+
+-- name: InvalidatePasswordResetTokensByUserID :execrows
+-- Новый токен сброса аннулирует неиспользованные предыдущие.
+UPDATE password_reset_tokens
+SET used_at = now()
+WHERE user_id = $1
+  AND used_at IS NULL;
+
+-- name: CreatePasswordResetToken :one
+INSERT INTO password_reset_tokens (user_id, token_hash, created_by, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, created_at, expires_at;
+
+-- name: GetPasswordResetTokenByHashForUpdate :one
+SELECT id, user_id, expires_at
+FROM password_reset_tokens
+WHERE token_hash = $1
+  AND used_at IS NULL
+  AND expires_at > now()
+FOR UPDATE;
+
+-- name: MarkPasswordResetTokenUsed :exec
+UPDATE password_reset_tokens
+SET used_at = now()
+WHERE id = $1;
+
+-- name: GetUserAuthByOIDCSubject :one
+-- Пользователь, привязанный к учётной записи провайдера SSO (миграция 000052).
+-- Строка блокируется: вход синхронизирует роль из групп IdP.
+SELECT id, email, role, is_active, organization_id, created_at, updated_at, token_version
+FROM users
+WHERE oidc_issuer = sqlc.arg(oidc_issuer)::text
+  AND oidc_subject = sqlc.arg(oidc_subject)::text
+FOR UPDATE;
+
+-- name: GetUserAuthByEmailForUpdate :one
+-- Первый вход через SSO: пользователь с тем же email, к которому привязывается
+-- учётная запись провайдера. oidc_subject не NULL — уже привязан к другой.
+SELECT id, email, role, is_active, organization_id, oidc_subject, created_at, updated_at, token_version
+FROM users
+WHERE email = $1
+FOR UPDATE;
+
+-- name: LinkUserOIDCIdentity :exec
+UPDATE users
+SET oidc_issuer = sqlc.arg(oidc_issuer)::text,
+    oidc_subject = sqlc.arg(oidc_subject)::text,
+    updated_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: CreateOIDCUser :one
+-- Пользователь, созданный при первом входе через SSO. Пустой password_hash
+-- не совпадает ни с одним bcrypt-хешем: вход по паролю невозможен.
+-- organization_id = NULL — организация по умолчанию, как в CreateUser.
+INSERT INTO users (email, password_hash, role, is_active, organization_id, oidc_issuer, oidc_subject)
+VALUES (
+    sqlc.arg(email),
+    '',
+    sqlc.arg(role),
+    TRUE,
+    COALESCE(sqlc.narg(organization_id)::bigint, (SELECT id FROM organizations WHERE is_default)),
+    sqlc.arg(oidc_issuer)::text,
+    sqlc.arg(oidc_subject)::text
+)
+RETURNING id, email, role, is_active, organization_id, created_at, updated_at, token_version;
+
+-- name: SyncUserSSORole :one
+-- Роль из групп IdP изменилась: как UpdateUserRole, отзывает выданные ранее
+-- access-токены. Момент отзыва передается из приложения, как в ChangeUserPassword.
+UPDATE users
+SET role = sqlc.arg(role),
+    tokens_revoked_at = sqlc.arg(tokens_revoked_at)::timestamptz,
+    token_version = token_version + 1,
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING token_version;
@@ -0,0 +1,107 @@
+-- baseline_estimate.sql
+-- Оценочный baseline лота по средним ценам истории каталога.
+
+-- name: ListLotProposalsForEstimate :many
+-- Предложения лота с числом позиций (без заголовков разделов) и числом
+-- оценённых позиций. Первым идёт baseline, затем предложения с наибольшим
+-- числом позиций — из них берётся перечень работ для оценки.
+SELECT
+    p.id,
+    p.is_baseline,
+    COUNT(pi.id) FILTER (WHERE pi.is_chapter = false) AS positions_count,
+    COUNT(pi.id) FILTER (WHERE pi.is_chapter = false AND pi.unit_cost_total IS NOT NULL) AS priced_count
+FROM proposals p
+LEFT JOIN position_items pi ON pi.proposal_id = p.id
+WHERE p.lot_id = sqlc.arg(lot_id)
+GROUP BY p.id
+ORDER BY p.is_baseline DESC, positions_count DESC, p.id;
+
+-- name: ListProposalPositionsForEstimate :many
+-- Все позиции предложения (включая заголовки разделов) в порядке загрузки.
+SELECT * FROM position_items
+WHERE proposal_id = $1
+ORDER BY id;
+
+-- name: ListCatalogAverageUnitCosts :many
+-- Средняя цена за единицу по позициям каталога за окно (since — начало окна)
+-- в базовой валюте. Выборка та же, что и в ListCatalogPositionPriceHistory:
+-- без baseline, заголовков, позиций без цены и удалённых тендеров; позиции
+-- оцениваемого лота не учитываются. Цены в валюте без курса пропускаются.
+-- Группировка по единице измерения: цены за м² и за м³ усреднять нельзя.
+WITH fx AS (
+    SELECT
+        unnest(sqlc.arg(currencies)::text[]) AS currency,
+        unnest(sqlc.arg(rates)::numeric[]) AS rate
+)
+SELECT
+    pi.catalog_position_id::bigint AS catalog_position_id,
+    pi.unit_id,
+    ROUND(AVG(pi.unit_cost_total * fx.rate), 2)::numeric AS avg_unit_cost,
+    COUNT(*) AS prices_count
+FROM position_items pi
+JOIN proposals p ON p.id = pi.proposal_id
+JOIN lots l ON l.id = p.lot_id
+JOIN tenders t ON t.id = l.tender_id
+JOIN fx ON fx.currency = COALESCE(pi.currency, p.currency)
+WHERE pi.catalog_position_id = ANY(sqlc.arg(catalog_position_ids)::bigint[])
+  AND pi.is_chapter = false
+  AND pi.unit_cost_total IS NOT NULL
+  AND p.is_baseline = false
+  AND t.deleted_at IS NULL
+  AND l.id <> sqlc.arg(lot_id)
+  AND COALESCE(t.data_prepared_on_date, t.created_at) >= sqlc.arg(since)::timestamptz
+  AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint)
+GROUP BY pi.catalog_position_id, pi.unit_id;
+
+-- name: CopyProposalPositionsForEstimate :execrows
+-- Копирует перечень работ (без цен) из предложения-образца в baseline.
+-- Позиции, ключ которых в baseline уже есть, не трогаются.
+INSERT INTO position_items (
+    proposal_id,
+    catalog_position_id,
+    position_key_in_proposal,
+    comment_organazier,
+    item_number_in_proposal,
+    chapter_number_in_proposal,
+    job_title_in_proposal,
+    unit_id,
+    quantity,
+    is_chapter,
+    chapter_ref_in_proposal,
+    article_smr
+)
+SELECT
+    sqlc.arg(target_proposal_id)::bigint,
+    src.catalog_position_id,
+    src.position_key_in_proposal,
+    src.comment_organazier,
+    src.item_number_in_proposal,
+    src.chapter_number_in_proposal,
+    src.job_title_in_proposal,
+    src.unit_id,
+    src.quantity,
+    src.is_chapter,
+    src.chapter_ref_in_proposal,
+    src.article_smr
+FROM position_items src
+WHERE src.proposal_id = sqlc.arg(source_proposal_id)
+ORDER BY src.id
+ON CONFLICT (proposal_id, position_key_in_proposal) DO NOTHING;
+
+-- name: SetEstimatedPositionCosts :execrows
+-- Проставляет оценочные цены за единицу позициям baseline по ключам позиций;
+-- стоимость — цена × количество. Уже оценённые позиции не перезаписываются.
+UPDATE position_items pi
+SET unit_cost_total = e.unit_cost,
+    total_cost_total = ROUND(e.unit_cost * pi.quantity, 2),
+    currency = sqlc.arg(currency)::text,
+    updated_at = NOW()
+FROM (
+    SELECT
+        unnest(sqlc.arg(position_keys)::text[]) AS position_key,
+        unnest(sqlc.arg(unit_costs)::numeric[]) AS unit_cost
+) e
+WHERE pi.proposal_id = sqlc.arg(proposal_id)
+  AND pi.position_key_in_proposal = e.position_key
+  AND pi.is_chapter = false
+  AND pi.unit_cost_total IS NULL;
@@ -0,0 +1,122 @@
+-- cache_version.sql
+--
+-- Версии данных для ETag тяжёлых эндпоинтов чтения (см. server/http_cache.go).
+-- Версия — самый поздний updated_at среди всех строк, из которых собирается ответ,
+-- и их общее число: число ловит удаление строк, которое не сдвигает MAX(updated_at).
+-- GREATEST в PostgreSQL пропускает NULL, поэтому необязательные связи (LEFT JOIN)
+-- в версию не приходится оборачивать в COALESCE.
+-- Сущность не найдена (или скрыта мягким удалением тендера) — sql.ErrNoRows;
+-- обработчик тогда отвечает без кэширования.
+
+-- name: GetTenderCacheVersion :one
+-- Версия страницы тендера (GET /api/v1/tenders/:id): сам тендер со справочниками
+-- шапки, лоты, предложения с подрядчиками, итоговые строки, доп. информация и победители.
+SELECT
+    GREATEST(
+        t.updated_at, obj.updated_at, exc.updated_at,
+        cat.updated_at, chap.updated_at, typ.updated_at,
+        ch.max_updated_at
+    )::timestamptz AS version_at,
+    ch.rows_count::bigint AS rows_count
+FROM tenders t
+LEFT JOIN objects obj ON obj.id = t.object_id
+LEFT JOIN executors exc ON exc.id = t.executor_id
+LEFT JOIN tender_categories cat ON cat.id = t.category_id
+LEFT JOIN tender_chapters chap ON chap.id = cat.tender_chapter_id
+LEFT JOIN tender_types typ ON typ.id = chap.tender_type_id
+CROSS JOIN LATERAL (
+    SELECT MAX(x.updated_at) AS max_updated_at, COUNT(*) AS rows_count
+    FROM (
+        SELECT l.updated_at FROM lots l WHERE l.tender_id = t.id
+        UNION ALL
+        SELECT GREATEST(p.updated_at, c.updated_at)
+        FROM proposals p
+        JOIN lots l ON l.id = p.lot_id
+        JOIN contractors c ON c.id = p.contractor_id
+        WHERE l.tender_id = t.id
+        UNION ALL
+        SELECT psl.updated_at
+        FROM proposal_summary_lines psl
+        JOIN proposals p ON p.id = psl.proposal_id
+        JOIN lots l ON l.id = p.lot_id
+        WHERE l.tender_id = t.id
+        UNION ALL
+        SELECT pai.updated_at
+        FROM proposal_additional_info pai
+        JOIN proposals p ON p.id = pai.proposal_id
+        JOIN lots l ON l.id = p.lot_id
+        WHERE l.tender_id = t.id
+        UNION ALL
+        SELECT w.updated_at
+        FROM winners w
+        JOIN proposals p ON p.id = w.proposal_id
+        JOIN lots l ON l.id = p.lot_id
+        WHERE l.tender_id = t.id
+    ) x (updated_at)
+) ch
+WHERE t.id = sqlc.arg(tender_id)
+  AND (sqlc.arg(include_deleted)::boolean OR t.deleted_at IS NULL);
+
+-- name: GetLotCacheVersion :one
+-- Версия матрицы сравнения лота (GET /api/v1/lots/:id/comparison): лот, тендер,
+-- предложения с подрядчиками, итоговые строки и позиции вместе с позициями
+-- каталога и единицами измерения.
+SELECT
+    GREATEST(l.updated_at, t.updated_at, ch.max_updated_at)::timestamptz AS version_at,
+    ch.rows_count::bigint AS rows_count
+FROM lots l
+JOIN tenders t ON t.id = l.tender_id
+CROSS JOIN LATERAL (
+    SELECT MAX(x.updated_at) AS max_updated_at, COUNT(*) AS rows_count
+    FROM (
+        SELECT GREATEST(p.updated_at, c.updated_at)
+        FROM proposals p
+        JOIN contractors c ON c.id = p.contractor_id
+        WHERE p.lot_id = l.id
+        UNION ALL
+        SELECT psl.updated_at
+        FROM proposal_summary_lines psl
+        JOIN proposals p ON p.id = psl.proposal_id
+        WHERE p.lot_id = l.id
+        UNION ALL
+        SELECT GREATEST(pi.updated_at, cp.updated_at, u.updated_at)
+        FROM position_items pi
+        JOIN proposals p ON p.id = pi.proposal_id
+        LEFT JOIN catalog_positions cp ON cp.id = pi.catalog_position_id
+        LEFT JOIN units_of_measurement u ON u.id = pi.unit_id
+        WHERE p.lot_id = l.id
+    ) x (updated_at)
+) ch
+WHERE l.id = sqlc.arg(lot_id)
+  AND t.deleted_at IS NULL;
+
+-- name: GetProposalCacheVersion :one
+-- Версия страницы предложения (GET /api/v1/proposals/:id/details): предложение,
+-- подрядчик, лот и тендер шапки, итоговые строки, доп. информация и позиции
+-- вместе с позициями каталога и единицами измерения.
+SELECT
+    GREATEST(
+        p.updated_at, c.updated_at, l.updated_at, t.updated_at,
+        ch.max_updated_at
+    )::timestamptz AS version_at,
+    ch.rows_count::bigint AS rows_count
+FROM proposals p
+JOIN contractors c ON c.id = p.contractor_id
+JOIN lots l ON l.id = p.lot_id
+JOIN tenders t ON t.id = l.tender_id
+CROSS JOIN LATERAL (
+    SELECT MAX(x.updated_at) AS max_updated_at, COUNT(*) AS rows_count
+    FROM (
+        SELECT psl.updated_at FROM proposal_summary_lines psl WHERE psl.proposal_id = p.id
+        UNION ALL
+        SELECT pai.updated_at FROM proposal_additional_info pai WHERE pai.proposal_id = p.id
+        UNION ALL
+        SELECT GREATEST(pi.updated_at, cp.updated_at, u.updated_at)
+        FROM position_items pi
+        LEFT JOIN catalog_positions cp ON cp.id = pi.catalog_position_id
+        LEFT JOIN units_of_measurement u ON u.id = pi.unit_id
+        WHERE pi.proposal_id = p.id
+    ) x (updated_at)
+) ch
+WHERE p.id = sqlc.arg(proposal_id)
+  AND t.deleted_at IS NULL;
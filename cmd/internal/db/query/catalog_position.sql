@@ -21,7 +21,9 @@ ON CONFLICT (standard_job_title, COALESCE(unit_id, -1))
 DO UPDATE SET
     description = EXCLUDED.description,
     kind = EXCLUDED.kind,
-    status = 'pending_indexing',        -- Сбрасываем статус, чтобы обновить вектор
+    -- Сбрасываем статус, чтобы обновить вектор; выведенная позиция (deprecated)
+    -- в очередь индексации не возвращается
+    status = CASE WHEN catalog_positions.status = 'deprecated' THEN catalog_positions.status ELSE 'pending_indexing' END,
     embedding = NULL,                   -- Сбрасываем старый вектор
     updated_at = NOW()
 RETURNING *;
@@ -35,6 +37,13 @@ SELECT * FROM catalog_positions
 WHERE standard_job_title = $1
   AND (unit_id = sqlc.narg('unit_id') OR (unit_id IS NULL AND sqlc.narg('unit_id') IS NULL));
 
+-- name: ListCatalogPositionsByTitles :many
+-- (Для импорта) Все позиции каталога с данными названиями, с любой единицей
+-- измерения. Импорт загружает их одним запросом на лот и ищет позицию по
+-- паре (название, unit_id) в памяти, как GetCatalogPositionByTitleAndUnit.
+SELECT * FROM catalog_positions
+WHERE standard_job_title = ANY(sqlc.arg(titles)::text[]);
+
 -- name: ListCatalogPositions :many
 SELECT id, standard_job_title, description, kind, created_at, updated_at
 FROM catalog_positions
@@ -54,12 +63,13 @@ LIMIT $1;
 
 -- name: UpdateCatalogPositionDetails :one
 -- Обновляет детали, сбрасывает статус и удаляет старый вектор.
+-- Выведенная позиция (deprecated) остаётся выведенной.
 UPDATE catalog_positions
 SET
     standard_job_title = COALESCE(sqlc.narg(standard_job_title), standard_job_title),
     description = COALESCE(sqlc.narg(description), description),
     unit_id = COALESCE(sqlc.narg(unit_id), unit_id),
-    status = 'pending_indexing',
+    status = CASE WHEN status = 'deprecated' THEN status ELSE 'pending_indexing' END,
     embedding = NULL,
     updated_at = NOW()
 WHERE id = sqlc.arg(id)
@@ -101,23 +111,54 @@ ORDER BY created_at DESC
 LIMIT $1
 OFFSET $2;
 
--- name: SetCatalogStatusActive :exec
--- Оставляем на случай массовой активации, если понадобится.
+-- name: SetCatalogStatusActive :many
+-- Массовая активация после индексации (POST /api/v1/catalog/indexed).
+-- Уже активные, влитые (merged_into_id) и выведенные из оборота (deprecated, archived)
+-- позиции пропускаются. Возвращает ID фактически активированных позиций.
 UPDATE catalog_positions
 SET status = 'active'
-WHERE id = ANY($1::bigint[]);
+WHERE id = ANY(@ids::bigint[])
+  AND status NOT IN ('active', 'deprecated', 'archived')
+  AND merged_into_id IS NULL
+RETURNING id;
+
+-- name: GetCatalogPositionStatuses :many
+-- Статусы позиций для проверки массовой активации (dry-run и отчёт о пропусках).
+-- FOR UPDATE: внутри транзакции активации строки не меняются между проверкой и UPDATE;
+-- вне транзакции (dry-run) блокировка держится только на время запроса.
+SELECT id, status, merged_into_id
+FROM catalog_positions
+WHERE id = ANY(@ids::bigint[])
+ORDER BY id
+FOR UPDATE;
 
 -- name: GetActiveCatalogItems :many
--- Пагинация активных позиций каталога (для поиска дубликатов и др.)
-SELECT id AS catalog_id, standard_job_title, description
+-- Пагинация активных позиций каталога (для поиска дубликатов и др.).
+-- Закреплённые позиции идут первыми: ранжировщик получает их раньше остальных.
+SELECT id AS catalog_id, standard_job_title, description, is_pinned
 FROM catalog_positions
 WHERE kind = 'POSITION' AND status = 'active'
-ORDER BY id
+ORDER BY is_pinned DESC, id
 LIMIT $1
 OFFSET $2;
 
+-- name: GetActiveCatalogItemsAfter :many
+-- Keyset-пагинация активных позиций каталога в порядке (is_pinned DESC, id):
+-- сначала закреплённые, затем остальные. Курсор — is_pinned и id последней
+-- строки (true и 0 — первая страница). Не деградирует с глубиной, в отличие от OFFSET.
+SELECT id AS catalog_id, standard_job_title, description, is_pinned
+FROM catalog_positions
+WHERE kind = 'POSITION' AND status = 'active'
+  AND CASE WHEN sqlc.arg(after_pinned)::boolean
+           THEN NOT is_pinned OR id > sqlc.arg(after_id)::bigint
+           ELSE NOT is_pinned AND id > sqlc.arg(after_id)::bigint
+      END
+ORDER BY is_pinned DESC, id
+LIMIT sqlc.arg(page_limit)::int;
+
 -- name: HybridSearchCatalogPositions :many
--- Гибридный поиск (RRF) для матчинга.
+-- Гибридный поиск (RRF) для матчинга. Закреплённые позиции среди найденных
+-- кандидатов идут первыми как предпочтительные, внутри групп — по rrf_score.
 WITH semantic_search AS (
     SELECT id, row_number() OVER (ORDER BY embedding <=> $1::vector) as rank
     FROM catalog_positions
@@ -141,12 +182,13 @@ SELECT
     cp.standard_job_title, 
     cp.description, 
     cp.unit_id,
+    cp.is_pinned,
     -- Кастинг в float8 для Go
     (COALESCE(1.0 / (60 + s.rank), 0.0) + COALESCE(1.0 / (60 + k.rank), 0.0))::float8 AS rrf_score 
 FROM semantic_search s
 FULL OUTER JOIN keyword_search k ON s.id = k.id
 JOIN catalog_positions cp ON cp.id = COALESCE(s.id, k.id)
-ORDER BY rrf_score DESC
+ORDER BY cp.is_pinned DESC, rrf_score DESC
 LIMIT $3;
 
 -- name: MergeCatalogPosition :one
@@ -266,7 +308,7 @@ SELECT COUNT(*)::int FROM catalog_positions WHERE kind = 'GROUP_TITLE';
 
 -- name: ListGroupChildren :many
 -- Возвращает список позиций, привязанных к конкретной группе.
-SELECT id, standard_job_title, description, kind, status
+SELECT id, standard_job_title, description, kind, status, is_pinned
 FROM catalog_positions
 WHERE parent_id = $1
 ORDER BY standard_job_title;
@@ -294,4 +336,84 @@ WHERE id = $1
   AND parent_id IS NOT NULL
   AND merged_into_id IS NULL
   AND status != 'deprecated'
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: SetCatalogPositionPinned :one
+-- Закрепляет/открепляет позицию каталога.
+-- Закрепить можно только активную POSITION, не влитую в другую позицию.
+-- Открепление разрешено всегда (например, после слияния позиция могла стать deprecated).
+-- Возвращает sql.ErrNoRows если позиция не найдена или не подходит для закрепления.
+UPDATE catalog_positions
+SET
+    is_pinned = sqlc.arg(is_pinned)::boolean,
+    pinned_at = CASE WHEN sqlc.arg(is_pinned)::boolean THEN NOW() ELSE NULL END,
+    pinned_by = CASE WHEN sqlc.arg(is_pinned)::boolean THEN sqlc.narg(pinned_by)::text ELSE NULL END,
+    updated_at = NOW()
+WHERE
+    id = sqlc.arg(id)
+    AND (
+        NOT sqlc.arg(is_pinned)::boolean
+        OR (kind = 'POSITION' AND status = 'active' AND merged_into_id IS NULL)
+    )
+RETURNING *;
+
+-- name: ListPinnedCatalogPositions :many
+-- Возвращает все закреплённые позиции каталога (для админки и ранжировщика).
+SELECT id, standard_job_title, description, kind, status, is_pinned
+FROM catalog_positions
+WHERE is_pinned = true
+ORDER BY standard_job_title;
+
+-- name: ExportCatalogPositions :many
+-- Выгрузка каталога в CSV порциями. Keyset-пагинация по id (after_id = последний id
+-- предыдущей порции), чтобы выгрузка не замедлялась к концу большого каталога.
+-- Фильтры опциональны (NULL = не применяется).
+SELECT
+    cp.id,
+    cp.standard_job_title,
+    cp.description,
+    cp.kind,
+    cp.status,
+    u.normalized_name AS unit_name,
+    cp.parent_id,
+    cp.merged_into_id,
+    cp.is_pinned,
+    cp.created_at,
+    cp.updated_at
+FROM catalog_positions cp
+LEFT JOIN units_of_measurement u ON u.id = cp.unit_id
+WHERE
+    cp.id > sqlc.arg(after_id)::bigint
+    AND (sqlc.narg(kind)::text IS NULL OR cp.kind = sqlc.narg(kind)::text)
+    AND (sqlc.narg(status)::text IS NULL OR cp.status = sqlc.narg(status)::text)
+    AND (sqlc.narg(is_pinned)::boolean IS NULL OR cp.is_pinned = sqlc.narg(is_pinned)::boolean)
+    AND (sqlc.narg(parent_id)::bigint IS NULL OR cp.parent_id = sqlc.narg(parent_id)::bigint)
+ORDER BY cp.id
+LIMIT sqlc.arg(page_limit)::int;
+
+-- name: DeprecateCatalogPosition :one
+-- Выводит позицию из оборота с указанием замены. Закрепление снимается.
+-- Уже выведенная или влитая позиция не меняется (нет строки).
+UPDATE catalog_positions
+SET
+    status = 'deprecated',
+    replaced_by_id = sqlc.arg(replaced_by_id)::bigint,
+    deprecated_at = NOW(),
+    deprecated_by = sqlc.arg(deprecated_by)::text,
+    is_pinned = false,
+    pinned_at = NULL,
+    pinned_by = NULL,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND status <> 'deprecated'
+  AND merged_into_id IS NULL
+RETURNING *;
+
+-- name: FlattenReplacementChain :execrows
+-- Позиции, ранее заменённые old_id, теперь ссылаются сразу на new_id
+-- (сжатие цепочки A → B → C до A → C).
+UPDATE catalog_positions
+SET
+    replaced_by_id = sqlc.arg(new_id)::bigint,
+    updated_at = NOW()
+WHERE replaced_by_id = sqlc.arg(old_id)::bigint;
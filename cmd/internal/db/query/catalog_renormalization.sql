@@ -0,0 +1,72 @@
+-- catalog_renormalization.sql
+-- Запросы перенормализации каталога при смене версии правил лемматизации.
+
+-- name: SetActiveNormVersion :exec
+-- Обновляет активную версию нормализации в system_settings.
+INSERT INTO system_settings (key, value_numeric, description, updated_by)
+VALUES ('norm_version', sqlc.arg(norm_version)::smallint, 'Активная версия нормализации (лемматизации) для matching_cache', sqlc.arg(updated_by)::text)
+ON CONFLICT (key)
+DO UPDATE SET
+    value_numeric = EXCLUDED.value_numeric,
+    value_string  = NULL,
+    value_boolean = NULL,
+    updated_by    = EXCLUDED.updated_by;
+
+-- name: CountCatalogPositionsForRenormalization :one
+-- Сколько позиций будет возвращено в очередь индексации.
+SELECT COUNT(*) FROM catalog_positions
+WHERE kind IN ('POSITION', 'GROUP_TITLE')
+  AND status = 'active';
+
+-- name: CreateCatalogRenormalizationRun :one
+INSERT INTO catalog_renormalization_runs (
+    norm_version,
+    total_positions,
+    cache_entries_deleted,
+    started_by
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: LockRunningCatalogRenormalizationRun :one
+-- Захватывает выполняющийся запуск для обработки очередной порции.
+-- SKIP LOCKED: если порцию уже обрабатывает другой экземпляр API, строк нет.
+SELECT * FROM catalog_renormalization_runs
+WHERE status = 'running'
+FOR UPDATE SKIP LOCKED;
+
+-- name: RequeueCatalogPositionsBatch :many
+-- Возвращает в pending_indexing следующую порцию активных позиций после курсора.
+-- Эмбеддинг сохраняется до переиндексации воркером.
+WITH batch AS (
+    SELECT id FROM catalog_positions
+    WHERE id > sqlc.arg(after_id)::bigint
+      AND kind IN ('POSITION', 'GROUP_TITLE')
+      AND status = 'active'
+    ORDER BY id
+    LIMIT sqlc.arg(batch_size)::int
+)
+UPDATE catalog_positions cp
+SET
+    status = 'pending_indexing',
+    updated_at = NOW()
+FROM batch
+WHERE cp.id = batch.id
+RETURNING cp.id;
+
+-- name: AdvanceCatalogRenormalizationRun :one
+-- Сдвигает курсор запуска; пустая порция (requeued = 0) завершает запуск.
+UPDATE catalog_renormalization_runs
+SET
+    requeued_positions = requeued_positions + sqlc.arg(requeued)::bigint,
+    last_position_id   = GREATEST(last_position_id, sqlc.arg(last_position_id)::bigint),
+    status             = CASE WHEN sqlc.arg(requeued)::bigint = 0 THEN 'completed' ELSE status END,
+    finished_at        = CASE WHEN sqlc.arg(requeued)::bigint = 0 THEN NOW() ELSE finished_at END
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: GetLatestCatalogRenormalizationRun :one
+SELECT * FROM catalog_renormalization_runs
+ORDER BY started_at DESC, id DESC
+LIMIT 1;
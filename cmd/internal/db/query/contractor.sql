@@ -4,11 +4,12 @@
 -- Создает нового подрядчика.
 -- inn должен быть уникальным.
 -- created_at и updated_at будут установлены по умолчанию (DEFAULT now()).
+-- accreditation_status — статус из данных импорта; срок и документы вносятся вручную.
 INSERT INTO contractors (
     title,
     inn,
     address,
-    accreditation
+    accreditation_status
 ) VALUES (
     $1, $2, $3, $4
 )
@@ -38,7 +39,7 @@ SET
     title = COALESCE(sqlc.narg(title), title),
     inn = COALESCE(sqlc.narg(inn), inn),
     address = COALESCE(sqlc.narg(address), address),
-    accreditation = COALESCE(sqlc.narg(accreditation), accreditation),
+    accreditation_status = COALESCE(sqlc.narg(accreditation_status), accreditation_status),
     updated_at = NOW()
 WHERE
     id = sqlc.arg(id)
@@ -57,22 +58,251 @@ ORDER BY title
 LIMIT sqlc.arg(page_limit)::int
 OFFSET sqlc.arg(page_offset)::int;
 
+
+-- name: ListContractorsPage :many
+-- Страница подрядчиков для GET /api/v1/contractors.
+-- search (опционально) — подстрока наименования без учета регистра или начало ИНН.
+-- proposals_count — предложения подрядчика (без baseline) в неудаленных тендерах.
+SELECT
+    c.id,
+    c.title,
+    c.inn,
+    c.address,
+    c.created_at,
+    c.updated_at,
+    c.accreditation_status,
+    c.accreditation_valid_until,
+    c.accreditation_documents,
+    c.accreditation_source,
+    (SELECT COUNT(*)
+     FROM proposals p
+     JOIN lots l ON l.id = p.lot_id
+     JOIN tenders t ON t.id = l.tender_id
+     WHERE p.contractor_id = c.id
+       AND p.is_baseline = false
+       AND t.deleted_at IS NULL)::bigint AS proposals_count
+FROM contractors c
+WHERE sqlc.narg(search)::text IS NULL
+   OR c.title ILIKE '%' || sqlc.narg(search)::text || '%'
+   OR c.inn LIKE sqlc.narg(search)::text || '%'
+ORDER BY c.title, c.id
+LIMIT sqlc.arg(page_limit)::int
+OFFSET sqlc.arg(page_offset)::int;
+
+-- name: CountContractors :one
+-- Общее число подрядчиков с тем же фильтром, что и ListContractorsPage.
+SELECT COUNT(*)
+FROM contractors c
+WHERE sqlc.narg(search)::text IS NULL
+   OR c.title ILIKE '%' || sqlc.narg(search)::text || '%'
+   OR c.inn LIKE sqlc.narg(search)::text || '%';
+
+-- name: GetContractorStats :one
+-- Статистика участия подрядчика. Учитываются предложения без baseline
+-- в неудаленных тендерах; одно предложение = участие в одном лоте.
+-- win_rate_percent = победы / предложения * 100.
+-- avg_deviation_percent — среднее отклонение итога (с НДС) от baseline лота;
+-- предложения без итога или лоты без baseline не учитываются (compared_proposals_count).
+-- organization_id ограничивает статистику тендерами организации (NULL — все).
+WITH contractor_proposals AS (
+    SELECT
+        l.tender_id,
+        psl.total_cost,
+        (SELECT bpsl.total_cost
+         FROM proposals bp
+         JOIN proposal_summary_lines bpsl
+           ON bpsl.proposal_id = bp.id AND bpsl.summary_key = 'total_cost_with_vat'
+         WHERE bp.lot_id = p.lot_id AND bp.is_baseline = true
+         LIMIT 1) AS baseline_total,
+        (w.id IS NOT NULL) AS is_winner
+    FROM proposals p
+    JOIN lots l ON l.id = p.lot_id
+    JOIN tenders t ON t.id = l.tender_id
+    LEFT JOIN proposal_summary_lines psl
+        ON psl.proposal_id = p.id AND psl.summary_key = 'total_cost_with_vat'
+    LEFT JOIN winners w ON w.proposal_id = p.id
+    WHERE p.contractor_id = sqlc.arg(contractor_id)
+      AND p.is_baseline = false
+      AND t.deleted_at IS NULL
+      AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint)
+)
+SELECT
+    COUNT(DISTINCT tender_id) AS tenders_count,
+    COUNT(*) AS proposals_count,
+    COUNT(*) FILTER (WHERE is_winner) AS wins_count,
+    ROUND(COUNT(*) FILTER (WHERE is_winner)::numeric / NULLIF(COUNT(*), 0) * 100, 2)::numeric AS win_rate_percent,
+    ROUND(AVG((total_cost - baseline_total) / NULLIF(baseline_total, 0) * 100), 2)::numeric AS avg_deviation_percent,
+    COUNT(*) FILTER (WHERE total_cost IS NOT NULL AND baseline_total IS NOT NULL AND baseline_total <> 0) AS compared_proposals_count
+FROM contractor_proposals;
+
+-- name: ListContractorRecentProposals :many
+-- Последние предложения подрядчика (по дате тендера) с итогом, baseline лота,
+-- отклонением от него в процентах и признаком победы. organization_id — как в GetContractorStats.
+WITH recent AS (
+    SELECT
+        p.id AS proposal_id,
+        l.id AS lot_id,
+        l.lot_title,
+        t.id AS tender_id,
+        t.etp_id AS tender_etp_id,
+        t.title AS tender_title,
+        COALESCE(t.data_prepared_on_date, t.created_at) AS tender_date,
+        psl.total_cost,
+        (SELECT bpsl.total_cost
+         FROM proposals bp
+         JOIN proposal_summary_lines bpsl
+           ON bpsl.proposal_id = bp.id AND bpsl.summary_key = 'total_cost_with_vat'
+         WHERE bp.lot_id = p.lot_id AND bp.is_baseline = true
+         LIMIT 1) AS baseline_total,
+        (w.id IS NOT NULL) AS is_winner
+    FROM proposals p
+    JOIN lots l ON l.id = p.lot_id
+    JOIN tenders t ON t.id = l.tender_id
+    LEFT JOIN proposal_summary_lines psl
+        ON psl.proposal_id = p.id AND psl.summary_key = 'total_cost_with_vat'
+    LEFT JOIN winners w ON w.proposal_id = p.id
+    WHERE p.contractor_id = sqlc.arg(contractor_id)
+      AND p.is_baseline = false
+      AND t.deleted_at IS NULL
+      AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint)
+    ORDER BY tender_date DESC, p.id DESC
+    LIMIT sqlc.arg(page_limit)::int
+)
+SELECT
+    proposal_id,
+    lot_id,
+    lot_title,
+    tender_id,
+    tender_etp_id,
+    tender_title,
+    tender_date::timestamptz AS tender_date,
+    total_cost::numeric AS total_cost,
+    baseline_total::numeric AS baseline_total,
+    ROUND((total_cost - baseline_total) / NULLIF(baseline_total, 0) * 100, 2)::numeric AS deviation_percent,
+    is_winner::boolean AS is_winner
+FROM recent
+ORDER BY tender_date DESC, proposal_id DESC;
+
+-- name: ListContractorDuplicateCandidates :many
+-- Подрядчики, у которых ИНН совпадает после удаления всего, кроме цифр
+-- (пробелы, дефисы, префикс "ИНН"). UNIQUE(inn) такие варианты не ловит.
+-- Сходство наименований считается в сервисе.
+SELECT
+    c.id,
+    c.title,
+    c.inn,
+    c.address,
+    c.created_at,
+    regexp_replace(c.inn, '[^0-9]', '', 'g')::text AS normalized_inn,
+    (SELECT COUNT(*) FROM proposals p WHERE p.contractor_id = c.id)::bigint AS proposals_count
+FROM contractors c
+WHERE regexp_replace(c.inn, '[^0-9]', '', 'g') IN (
+    SELECT regexp_replace(inn, '[^0-9]', '', 'g')
+    FROM contractors
+    GROUP BY 1
+    HAVING COUNT(*) > 1
+)
+ORDER BY normalized_inn, c.id;
+
+-- name: GetContractorByIDForUpdate :one
+-- Блокирует подрядчика на время слияния.
+SELECT * FROM contractors
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListContractorMergeConflictLots :many
+-- Лоты, в которых есть предложения обоих подрядчиков: после переноса они
+-- нарушили бы uq_proposals_lot_contractor, поэтому слияние отклоняется.
+SELECT dp.lot_id
+FROM proposals dp
+JOIN proposals mp ON mp.lot_id = dp.lot_id AND mp.contractor_id = sqlc.arg(master_id)
+WHERE dp.contractor_id = sqlc.arg(duplicate_id)
+ORDER BY dp.lot_id;
+
+-- name: ReassignContractorProposals :execrows
+-- Переносит предложения дубликата на основного подрядчика.
+UPDATE proposals
+SET contractor_id = sqlc.arg(master_id), updated_at = NOW()
+WHERE contractor_id = sqlc.arg(duplicate_id);
+
+-- name: ReassignContractorPersons :execrows
+-- Переносит контактных лиц дубликата на основного подрядчика.
+UPDATE persons
+SET contractor_id = sqlc.arg(master_id), updated_at = NOW()
+WHERE contractor_id = sqlc.arg(duplicate_id);
+
+-- name: UpdateContractorAccreditation :one
+-- Аккредитация, внесённая вручную: статус, срок и документы заменяются целиком,
+-- источник становится manual, и импорт больше не меняет статус.
+UPDATE contractors
+SET
+    accreditation_status = sqlc.arg(accreditation_status),
+    accreditation_valid_until = sqlc.narg(accreditation_valid_until),
+    accreditation_documents = sqlc.arg(accreditation_documents),
+    accreditation_source = 'manual',
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListExpiringAccreditations :many
+-- Аккредитации со сроком не позже valid_until_before, включая уже истекшие
+-- (status = expired): ближайший срок первым. Для GET /api/v1/contractors/expiring-accreditation.
+SELECT * FROM contractors
+WHERE accreditation_status IN ('accredited', 'expired')
+  AND accreditation_valid_until IS NOT NULL
+  AND accreditation_valid_until <= sqlc.arg(valid_until_before)::date
+ORDER BY accreditation_valid_until, id
+LIMIT sqlc.arg(page_limit)::int
+OFFSET sqlc.arg(page_offset)::int;
+
+-- name: CountExpiringAccreditations :one
+-- Общее число строк ListExpiringAccreditations.
+SELECT COUNT(*) FROM contractors
+WHERE accreditation_status IN ('accredited', 'expired')
+  AND accreditation_valid_until IS NOT NULL
+  AND accreditation_valid_until <= sqlc.arg(valid_until_before)::date;
+
+-- name: ExpireContractorAccreditations :many
+-- Помечает истекшими аккредитации, последний день которых раньше today.
+-- Возвращает только изменённых подрядчиков: повторный запуск их не вернёт.
+UPDATE contractors
+SET accreditation_status = 'expired', updated_at = NOW()
+WHERE accreditation_status = 'accredited'
+  AND accreditation_valid_until < sqlc.arg(today)::date
+RETURNING *;
+
+-- name: ListAccreditationsToAlert :many
+-- Действующие аккредитации со сроком в окне [today, valid_until_before], о
+-- которых ещё не оповещали. Продлённая аккредитация (новый срок) попадает сюда снова.
+SELECT * FROM contractors
+WHERE accreditation_status = 'accredited'
+  AND accreditation_valid_until BETWEEN sqlc.arg(today)::date AND sqlc.arg(valid_until_before)::date
+  AND accreditation_alerted_until IS DISTINCT FROM accreditation_valid_until
+ORDER BY accreditation_valid_until, id
+LIMIT sqlc.arg(page_limit)::int;
+
+-- name: MarkAccreditationsAlerted :execrows
+-- Запоминает, что о текущем сроке аккредитации подрядчиков уже оповестили.
+UPDATE contractors
+SET accreditation_alerted_until = accreditation_valid_until
+WHERE id = ANY(sqlc.arg(ids)::bigint[]);
+
 /*
 Для информации, вот какие структуры параметров sqlc может сгенерировать:
 
 type CreateContractorParams struct {
-    Title         string `json:"title"`
-    Inn           string `json:"inn"`
-    Address       string `json:"address"`
-    Accreditation string `json:"accreditation"`
+    Title               string `json:"title"`
+    Inn                 string `json:"inn"`
+    Address             string `json:"address"`
+    AccreditationStatus string `json:"accreditation_status"`
 }
 
 type UpdateContractorParams struct {
-    ID            int64  `json:"id"`
-    Title         string `json:"title"`
-    Inn           string `json:"inn"`
-    Address       string `json:"address"`
-    Accreditation string `json:"accreditation"`
+    ID                  int64          `json:"id"`
+    Title               sql.NullString `json:"title"`
+    Inn                 sql.NullString `json:"inn"`
+    Address             sql.NullString `json:"address"`
+    AccreditationStatus sql.NullString `json:"accreditation_status"`
 }
 
 type SearchContractorsByTitleParams struct {
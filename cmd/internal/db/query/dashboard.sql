@@ -0,0 +1,97 @@
+-- dashboard.sql
+--
+-- Сводка для главной страницы (services/dashboard, GET /api/v1/dashboard).
+-- Запросы независимы и выполняются параллельно. organization_id = NULL —
+-- по всем организациям (администратор), иначе только тендеры организации.
+-- Удалённые тендеры не учитываются.
+
+-- name: GetDashboardTenderStatusCounts :one
+-- Тендеры по состоянию: есть победитель (awarded), есть предложения
+-- подрядчиков без победителя (in_progress), предложений нет (no_proposals).
+SELECT
+    COUNT(*)::bigint AS total,
+    COUNT(*) FILTER (WHERE s.has_winner)::bigint AS awarded,
+    COUNT(*) FILTER (WHERE NOT s.has_winner AND s.has_proposals)::bigint AS in_progress,
+    COUNT(*) FILTER (WHERE NOT s.has_winner AND NOT s.has_proposals)::bigint AS no_proposals
+FROM tenders t
+CROSS JOIN LATERAL (
+    SELECT
+        EXISTS (
+            SELECT 1 FROM winners w
+            JOIN proposals pr ON pr.id = w.proposal_id
+            JOIN lots l ON l.id = pr.lot_id
+            WHERE l.tender_id = t.id
+        ) AS has_winner,
+        EXISTS (
+            SELECT 1 FROM proposals pr
+            JOIN lots l ON l.id = pr.lot_id
+            WHERE l.tender_id = t.id AND pr.is_baseline = false
+        ) AS has_proposals
+) s
+WHERE t.deleted_at IS NULL
+  AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint);
+
+-- name: ListDashboardImportsByDay :many
+-- Импорты по дням начиная с since: всего и новых тендеров (версия 1).
+-- Дни без импортов не возвращаются.
+SELECT
+    date_trunc('day', h.imported_at)::date AS day,
+    COUNT(*)::bigint AS imports,
+    COUNT(*) FILTER (WHERE h.version = 1)::bigint AS new_tenders
+FROM tender_raw_data_history h
+JOIN tenders t ON t.id = h.tender_id
+WHERE h.imported_at >= sqlc.arg(since)::timestamptz
+  AND t.deleted_at IS NULL
+  AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint)
+GROUP BY day
+ORDER BY day;
+
+-- name: CountDashboardUnmatchedPositions :one
+-- Размер очереди сопоставления: позиции без позиции каталога или с позицией,
+-- ожидающей индексации (то же условие, что в GetUnmatchedPositions).
+SELECT COUNT(*)::bigint
+FROM position_items pi
+LEFT JOIN catalog_positions cp ON cp.id = pi.catalog_position_id
+JOIN proposals pr ON pr.id = pi.proposal_id
+JOIN lots l ON l.id = pr.lot_id
+JOIN tenders t ON t.id = l.tender_id
+WHERE (pi.catalog_position_id IS NULL OR cp.status = 'pending_indexing')
+  AND pi.is_chapter = false
+  AND t.deleted_at IS NULL
+  AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint);
+
+-- name: ListDashboardTopCategories :many
+-- Категории с наибольшим объёмом: сумма цен контрактов победителей
+-- (winners.award_price, в рублях), число тендеров категории. Тендеры без
+-- категории не учитываются.
+SELECT
+    tc.id AS category_id,
+    tc.title AS category_title,
+    COUNT(DISTINCT t.id)::bigint AS tenders_count,
+    COALESCE(SUM(w.award_price), 0)::numeric AS winners_total
+FROM tenders t
+JOIN tender_categories tc ON tc.id = t.category_id
+LEFT JOIN lots l ON l.tender_id = t.id
+LEFT JOIN proposals pr ON pr.lot_id = l.id
+LEFT JOIN winners w ON w.proposal_id = pr.id
+WHERE t.deleted_at IS NULL
+  AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint)
+GROUP BY tc.id, tc.title
+ORDER BY winners_total DESC, tenders_count DESC, tc.id
+LIMIT sqlc.arg(max_categories)::int;
+
+-- name: ListDashboardRecentImports :many
+-- Последние импорты тендеров (лента активности): версия 1 — новый тендер,
+-- больше 1 — повторная загрузка.
+SELECT
+    t.id AS tender_id,
+    t.etp_id,
+    t.title,
+    h.version,
+    h.imported_at
+FROM tender_raw_data_history h
+JOIN tenders t ON t.id = h.tender_id
+WHERE t.deleted_at IS NULL
+  AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint)
+ORDER BY h.imported_at DESC, h.tender_id DESC, h.version DESC
+LIMIT sqlc.arg(max_items)::int;
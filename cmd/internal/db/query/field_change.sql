@@ -0,0 +1,38 @@
+-- field_change.sql
+-- История правок полей тендеров и лотов (GET /api/v1/tenders/:id/history,
+-- GET /api/v1/lots/:id/history). Пишется сервисами в транзакции правки.
+
+-- name: CreateFieldChange :exec
+INSERT INTO field_changes (entity_type, entity_id, field, old_value, new_value, changed_by)
+VALUES (
+    sqlc.arg(entity_type),
+    sqlc.arg(entity_id),
+    sqlc.arg(field),
+    sqlc.arg(old_value),
+    sqlc.arg(new_value),
+    sqlc.narg(changed_by)
+);
+
+-- name: ListFieldChanges :many
+-- Правки сущности, новые первыми, с email автора (NULL — пользователь удалён).
+SELECT
+    fc.id,
+    fc.field,
+    fc.old_value,
+    fc.new_value,
+    fc.changed_by,
+    u.email AS changed_by_email,
+    fc.changed_at
+FROM field_changes fc
+LEFT JOIN users u ON u.id = fc.changed_by
+WHERE fc.entity_type = sqlc.arg(entity_type)
+  AND fc.entity_id = sqlc.arg(entity_id)
+ORDER BY fc.changed_at DESC, fc.id DESC
+LIMIT sqlc.arg(page_limit)::int
+OFFSET sqlc.arg(page_offset)::int;
+
+-- name: CountFieldChanges :one
+SELECT COUNT(*)::bigint
+FROM field_changes
+WHERE entity_type = sqlc.arg(entity_type)
+  AND entity_id = sqlc.arg(entity_id);
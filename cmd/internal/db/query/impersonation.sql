@@ -0,0 +1,36 @@
+-- impersonation.sql
+-- Режим имперсонации (POST /api/v1/admin/users/:id/impersonate) и журнал
+-- запросов под ним (миграция 000053).
+
+-- name: CreateImpersonationSession :one
+INSERT INTO impersonation_sessions (admin_id, user_id, ip_address, user_agent, expires_at)
+VALUES (sqlc.arg(admin_id), sqlc.arg(user_id), sqlc.arg(ip_address), sqlc.arg(user_agent), sqlc.arg(expires_at))
+RETURNING *;
+
+-- name: InsertImpersonationAudit :one
+-- Запись добавляется только для действующей сессии, администратор которой
+-- по-прежнему активен и остаётся admin: 0 строк (sql.ErrNoRows) — сессия
+-- завершена, просрочена или администратор лишён прав.
+INSERT INTO impersonation_audit_log (session_id, method, path, ip_address)
+SELECT s.id, sqlc.arg(method)::text, sqlc.arg(path)::text, sqlc.narg(ip_address)::inet
+FROM impersonation_sessions s
+JOIN users a ON a.id = s.admin_id
+WHERE s.id = sqlc.arg(session_id)
+  AND s.ended_at IS NULL
+  AND s.expires_at > now()
+  AND a.is_active
+  AND a.role = 'admin'
+RETURNING id;
+
+-- name: CompleteImpersonationAudit :exec
+UPDATE impersonation_audit_log
+SET status = sqlc.arg(status)::int
+WHERE id = sqlc.arg(id);
+
+-- name: EndImpersonationSession :execrows
+-- Повторное завершение не меняет ended_at: 0 строк.
+UPDATE impersonation_sessions
+SET ended_at = now()
+WHERE id = sqlc.arg(id)
+  AND admin_id = sqlc.arg(admin_id)
+  AND ended_at IS NULL;
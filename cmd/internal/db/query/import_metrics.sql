@@ -0,0 +1,50 @@
+-- name: CreateImportMetrics :one
+-- Сохраняет тайминги одного импорта тендера. Возвращает ID записи (import_id).
+INSERT INTO import_metrics (
+        tender_id,
+        etp_id,
+        status,
+        error_message,
+        payload_bytes,
+        lots_count,
+        positions_count,
+        cache_hits,
+        cache_misses,
+        tx_wait_ms,
+        core_ms,
+        lots_ms,
+        positions_ms,
+        cache_lookup_ms,
+        raw_data_ms,
+        commit_ms,
+        total_ms,
+        lot_timings,
+        started_at
+    )
+VALUES (
+        sqlc.narg(tender_id),
+        sqlc.arg(etp_id),
+        sqlc.arg(status),
+        sqlc.narg(error_message),
+        sqlc.arg(payload_bytes),
+        sqlc.arg(lots_count),
+        sqlc.arg(positions_count),
+        sqlc.arg(cache_hits),
+        sqlc.arg(cache_misses),
+        sqlc.arg(tx_wait_ms),
+        sqlc.arg(core_ms),
+        sqlc.arg(lots_ms),
+        sqlc.arg(positions_ms),
+        sqlc.arg(cache_lookup_ms),
+        sqlc.arg(raw_data_ms),
+        sqlc.arg(commit_ms),
+        sqlc.arg(total_ms),
+        sqlc.arg(lot_timings)::jsonb,
+        sqlc.arg(started_at)
+    )
+RETURNING id;
+-- name: GetImportMetrics :one
+-- Получает тайминги импорта по ID записи.
+SELECT *
+FROM import_metrics
+WHERE id = $1;
@@ -0,0 +1,177 @@
+-- import_snapshot.sql
+-- Снимки тендера и лотов для компенсации параллельного импорта лотов
+-- (services/importer/parallel_lots.go). Каждый лот коммитится в своей
+-- транзакции, поэтому до UpsertTender и до сохранения лота их строки
+-- читаются в jsonb. Если импорт не удался, компенсация возвращает строки из
+-- снимка: удаляет строки, которых в снимке нет, и записывает прежние значения.
+-- Снимок держит в памяти импорт, в базе он не хранится.
+--
+-- Восстанавливаются колонки, которые пишет импорт (Upsert* и
+-- mergeStagedPositionItems); при их изменении обновите и эти запросы.
+
+-- name: SnapshotTenderForImport :one
+-- Строка тендера до импорта. sql.ErrNoRows — тендера ещё нет.
+SELECT to_jsonb(t)::jsonb AS snapshot
+FROM tenders t
+WHERE t.etp_id = $1;
+
+-- name: RestoreTenderFromSnapshot :execrows
+-- Возвращает поля тендера, которые обновляет UpsertTender, из снимка.
+UPDATE tenders t SET
+    title = s.title,
+    object_id = s.object_id,
+    executor_id = s.executor_id,
+    data_prepared_on_date = s.data_prepared_on_date,
+    category_id = s.category_id,
+    etp_status = s.etp_status,
+    updated_at = s.updated_at
+FROM jsonb_populate_record(NULL::tenders, sqlc.arg(snapshot)::jsonb) s
+WHERE t.id = s.id;
+
+-- name: SnapshotLotForImport :one
+-- Лот до импорта со всеми строками, которые пишет импорт лота.
+-- sql.ErrNoRows — лота ещё нет.
+SELECT jsonb_build_object(
+    'lot', to_jsonb(l),
+    'proposals', COALESCE((
+        SELECT jsonb_agg(p) FROM proposals p
+        WHERE p.lot_id = l.id
+    ), '[]'::jsonb),
+    'proposal_additional_info', COALESCE((
+        SELECT jsonb_agg(pai) FROM proposal_additional_info pai
+        JOIN proposals p ON p.id = pai.proposal_id
+        WHERE p.lot_id = l.id
+    ), '[]'::jsonb),
+    'proposal_summary_lines', COALESCE((
+        SELECT jsonb_agg(psl) FROM proposal_summary_lines psl
+        JOIN proposals p ON p.id = psl.proposal_id
+        WHERE p.lot_id = l.id
+    ), '[]'::jsonb),
+    'position_items', COALESCE((
+        SELECT jsonb_agg(pi) FROM position_items pi
+        JOIN proposals p ON p.id = pi.proposal_id
+        WHERE p.lot_id = l.id
+    ), '[]'::jsonb)
+)::jsonb AS snapshot
+FROM lots l
+WHERE l.tender_id = sqlc.arg(tender_id) AND l.lot_key = sqlc.arg(lot_key);
+
+-- name: PruneLotPositionItemsToSnapshot :execrows
+-- Удаляет позиции лота, которых нет в снимке (добавленные импортом).
+DELETE FROM position_items pi
+USING proposals p
+WHERE pi.proposal_id = p.id
+  AND p.lot_id = (sqlc.arg(snapshot)::jsonb -> 'lot' ->> 'id')::bigint
+  AND pi.id NOT IN (
+      SELECT (e ->> 'id')::bigint
+      FROM jsonb_array_elements(sqlc.arg(snapshot)::jsonb -> 'position_items') e
+  );
+
+-- name: PruneLotSummaryLinesToSnapshot :execrows
+-- Удаляет итоговые строки лота, которых нет в снимке.
+DELETE FROM proposal_summary_lines psl
+USING proposals p
+WHERE psl.proposal_id = p.id
+  AND p.lot_id = (sqlc.arg(snapshot)::jsonb -> 'lot' ->> 'id')::bigint
+  AND psl.id NOT IN (
+      SELECT (e ->> 'id')::bigint
+      FROM jsonb_array_elements(sqlc.arg(snapshot)::jsonb -> 'proposal_summary_lines') e
+  );
+
+-- name: PruneLotAdditionalInfoToSnapshot :execrows
+-- Удаляет доп. информацию лота, которой нет в снимке. Импорт пересоздаёт её
+-- целиком (DeleteAllAdditionalInfoForProposal), поэтому здесь удаляется вся
+-- записанная им доп. информация.
+DELETE FROM proposal_additional_info pai
+USING proposals p
+WHERE pai.proposal_id = p.id
+  AND p.lot_id = (sqlc.arg(snapshot)::jsonb -> 'lot' ->> 'id')::bigint
+  AND pai.id NOT IN (
+      SELECT (e ->> 'id')::bigint
+      FROM jsonb_array_elements(sqlc.arg(snapshot)::jsonb -> 'proposal_additional_info') e
+  );
+
+-- name: PruneLotProposalsToSnapshot :execrows
+-- Удаляет предложения лота, которых нет в снимке, вместе с их строками (ON DELETE CASCADE).
+DELETE FROM proposals p
+WHERE p.lot_id = (sqlc.arg(snapshot)::jsonb -> 'lot' ->> 'id')::bigint
+  AND p.id NOT IN (
+      SELECT (e ->> 'id')::bigint
+      FROM jsonb_array_elements(sqlc.arg(snapshot)::jsonb -> 'proposals') e
+  );
+
+-- name: RestoreLotFromSnapshot :execrows
+-- Возвращает поля лота, которые обновляет UpsertLot.
+UPDATE lots l SET
+    lot_title = s.lot_title,
+    lot_key_parameters = s.lot_key_parameters,
+    updated_at = s.updated_at
+FROM jsonb_populate_record(NULL::lots, sqlc.arg(snapshot)::jsonb -> 'lot') s
+WHERE l.id = s.id;
+
+-- name: RestoreLotProposals :execrows
+-- Возвращает предложения лота из снимка: обновлённые — прежние значения.
+INSERT INTO proposals
+SELECT * FROM jsonb_populate_recordset(NULL::proposals, sqlc.arg(snapshot)::jsonb -> 'proposals')
+ON CONFLICT (id) DO UPDATE SET
+    is_baseline = EXCLUDED.is_baseline,
+    contractor_coordinate = EXCLUDED.contractor_coordinate,
+    contractor_width = EXCLUDED.contractor_width,
+    contractor_height = EXCLUDED.contractor_height,
+    currency = EXCLUDED.currency,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: RestoreLotAdditionalInfo :execrows
+-- Возвращает доп. информацию лота из снимка с прежними ID.
+INSERT INTO proposal_additional_info
+SELECT * FROM jsonb_populate_recordset(NULL::proposal_additional_info, sqlc.arg(snapshot)::jsonb -> 'proposal_additional_info')
+ON CONFLICT (id) DO UPDATE SET
+    info_value = EXCLUDED.info_value,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: RestoreLotSummaryLines :execrows
+-- Возвращает итоговые строки лота из снимка: удалённые сверкой — вставкой
+-- с прежними ID, обновлённые — прежними значениями.
+INSERT INTO proposal_summary_lines
+SELECT * FROM jsonb_populate_recordset(NULL::proposal_summary_lines, sqlc.arg(snapshot)::jsonb -> 'proposal_summary_lines')
+ON CONFLICT (id) DO UPDATE SET
+    job_title = EXCLUDED.job_title,
+    materials_cost = EXCLUDED.materials_cost,
+    works_cost = EXCLUDED.works_cost,
+    indirect_costs_cost = EXCLUDED.indirect_costs_cost,
+    total_cost = EXCLUDED.total_cost,
+    currency = EXCLUDED.currency,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: RestoreLotPositionItems :execrows
+-- Возвращает позиции лота из снимка: удалённые сверкой — вставкой с прежними
+-- ID, обновлённые — прежними значениями и прежним закреплением воркера.
+INSERT INTO position_items
+SELECT * FROM jsonb_populate_recordset(NULL::position_items, sqlc.arg(snapshot)::jsonb -> 'position_items')
+ON CONFLICT (id) DO UPDATE SET
+    catalog_position_id = EXCLUDED.catalog_position_id,
+    comment_organazier = EXCLUDED.comment_organazier,
+    comment_contractor = EXCLUDED.comment_contractor,
+    item_number_in_proposal = EXCLUDED.item_number_in_proposal,
+    chapter_number_in_proposal = EXCLUDED.chapter_number_in_proposal,
+    job_title_in_proposal = EXCLUDED.job_title_in_proposal,
+    unit_id = EXCLUDED.unit_id,
+    quantity = EXCLUDED.quantity,
+    suggested_quantity = EXCLUDED.suggested_quantity,
+    total_cost_for_organizer_quantity = EXCLUDED.total_cost_for_organizer_quantity,
+    unit_cost_materials = EXCLUDED.unit_cost_materials,
+    unit_cost_works = EXCLUDED.unit_cost_works,
+    unit_cost_indirect_costs = EXCLUDED.unit_cost_indirect_costs,
+    unit_cost_total = EXCLUDED.unit_cost_total,
+    total_cost_materials = EXCLUDED.total_cost_materials,
+    total_cost_works = EXCLUDED.total_cost_works,
+    total_cost_indirect_costs = EXCLUDED.total_cost_indirect_costs,
+    total_cost_total = EXCLUDED.total_cost_total,
+    deviation_from_baseline_cost = EXCLUDED.deviation_from_baseline_cost,
+    is_chapter = EXCLUDED.is_chapter,
+    chapter_ref_in_proposal = EXCLUDED.chapter_ref_in_proposal,
+    article_smr = EXCLUDED.article_smr,
+    currency = EXCLUDED.currency,
+    matched_by = EXCLUDED.matched_by,
+    matched_at = EXCLUDED.matched_at,
+    updated_at = EXCLUDED.updated_at;
@@ -30,6 +30,12 @@ RETURNING *;
 SELECT * FROM lots
 WHERE id = $1;
 
+-- name: GetLotForUpdate :one
+-- Лот с блокировкой строки: ключевые параметры до правки для истории (field_changes).
+SELECT * FROM lots
+WHERE id = $1
+FOR UPDATE;
+
 -- name: GetLotByTenderAndKey :one
 -- Получает одну запись лота по его "бизнес-ключу" - комбинации ID тендера и ключа лота.
 -- Запрос очень быстрый, так как использует уникальный композитный индекс по (tender_id, lot_key).
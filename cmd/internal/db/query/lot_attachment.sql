@@ -0,0 +1,97 @@
+-- lot_attachment.sql
+--
+-- Документы лота, сформированные API (lot_attachments, миграция 000043), и
+-- данные для протокола выбора победителя (POST /api/v1/lots/:id/winner-protocol).
+-- Суммы протокола — в базовой валюте по курсам (как в analytics.sql): итог
+-- предложения пересчитывается из его валюты, цена контракта
+-- (winners.award_price) хранится в рублях. Суммы без курса — NULL.
+
+-- name: GetWinnerProtocolLot :one
+-- Тендер, объект и лот для шапки протокола; baseline_total — итог сметы
+-- инициатора с НДС. Лот мягко удалённого тендера не найден.
+WITH fx AS (
+    SELECT
+        unnest(sqlc.arg(currencies)::text[]) AS currency,
+        unnest(sqlc.arg(rates)::numeric[]) AS rate
+)
+SELECT
+    l.id AS lot_id,
+    l.lot_key,
+    l.lot_title,
+    t.id AS tender_id,
+    t.etp_id AS tender_etp_id,
+    t.title AS tender_title,
+    COALESCE(t.data_prepared_on_date, t.created_at)::timestamptz AS tender_date,
+    o.title AS object_title,
+    o.address AS object_address,
+    (SELECT COUNT(*)
+     FROM proposals p
+     WHERE p.lot_id = l.id AND NOT p.is_baseline)::bigint AS proposals_count,
+    (SELECT bpsl.total_cost * bfx.rate
+     FROM proposals bp
+     JOIN proposal_summary_lines bpsl
+         ON bpsl.proposal_id = bp.id AND bpsl.summary_key = 'total_cost_with_vat'
+     JOIN fx bfx ON bfx.currency = COALESCE(bpsl.currency, bp.currency)
+     WHERE bp.lot_id = l.id AND bp.is_baseline
+     LIMIT 1)::numeric AS baseline_total
+FROM lots l
+JOIN tenders t ON t.id = l.tender_id
+JOIN objects o ON o.id = t.object_id
+WHERE l.id = sqlc.arg(lot_id)
+  AND t.deleted_at IS NULL;
+
+-- name: ListWinnerProtocolWinners :many
+-- Победители лота по месту: подрядчик, итог его предложения с НДС и цена
+-- контракта. Baseline победителем не бывает и в протокол не входит.
+WITH fx AS (
+    SELECT
+        unnest(sqlc.arg(currencies)::text[]) AS currency,
+        unnest(sqlc.arg(rates)::numeric[]) AS rate
+)
+SELECT
+    w.id AS winner_id,
+    w.rank,
+    w.awarded_share,
+    w.notes,
+    c.title AS contractor_name,
+    c.inn AS contractor_inn,
+    (psl.total_cost * ofx.rate)::numeric AS offer_total,
+    (w.award_price * rfx.rate)::numeric AS award_price
+FROM winners w
+JOIN proposals p ON p.id = w.proposal_id
+JOIN contractors c ON c.id = p.contractor_id
+LEFT JOIN proposal_summary_lines psl
+    ON psl.proposal_id = p.id AND psl.summary_key = 'total_cost_with_vat'
+LEFT JOIN fx ofx ON ofx.currency = COALESCE(psl.currency, p.currency)
+LEFT JOIN fx rfx ON rfx.currency = 'RUB'
+WHERE p.lot_id = sqlc.arg(lot_id)
+  AND NOT p.is_baseline
+ORDER BY w.rank ASC NULLS LAST, w.created_at ASC, w.id ASC;
+
+-- name: CreateLotAttachment :one
+-- Сохраняет сформированный документ лота. Возвращает запись без содержимого.
+INSERT INTO lot_attachments (lot_id, kind, file_name, content_type, file_size, content, created_by)
+VALUES (
+    sqlc.arg(lot_id),
+    sqlc.arg(kind),
+    sqlc.arg(file_name),
+    sqlc.arg(content_type),
+    octet_length(sqlc.arg(content)::bytea),
+    sqlc.arg(content)::bytea,
+    sqlc.narg(created_by)
+)
+RETURNING id, lot_id, kind, file_name, content_type, file_size, created_by, created_at;
+
+-- name: ListLotAttachments :many
+-- Документы лота без содержимого, новые первыми.
+SELECT id, lot_id, kind, file_name, content_type, file_size, created_by, created_at
+FROM lot_attachments
+WHERE lot_id = sqlc.arg(lot_id)
+ORDER BY created_at DESC, id DESC;
+
+-- name: GetLotAttachment :one
+-- Документ лота с содержимым для скачивания; документ другого лота не найден.
+SELECT *
+FROM lot_attachments
+WHERE id = sqlc.arg(id)
+  AND lot_id = sqlc.arg(lot_id);
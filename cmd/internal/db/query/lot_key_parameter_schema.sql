@@ -0,0 +1,31 @@
+-- lot_key_parameter_schema.sql
+-- JSON Schema ключевых параметров лота по категориям тендеров. Схема
+-- проверяется при сохранении (jsonschema.Compile), поэтому в таблице лежат
+-- только корректные схемы.
+
+-- name: UpsertLotKeyParameterSchema :one
+INSERT INTO lot_key_parameter_schemas (tender_category_id, schema)
+VALUES ($1, $2)
+ON CONFLICT (tender_category_id) DO UPDATE
+SET schema = EXCLUDED.schema,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: GetLotKeyParameterSchema :one
+SELECT * FROM lot_key_parameter_schemas
+WHERE tender_category_id = $1;
+
+-- name: ListLotKeyParameterSchemas :many
+SELECT * FROM lot_key_parameter_schemas
+ORDER BY tender_category_id;
+
+-- name: DeleteLotKeyParameterSchema :execrows
+DELETE FROM lot_key_parameter_schemas
+WHERE tender_category_id = $1;
+
+-- name: GetLotKeyParameterSchemaByTender :one
+-- Схема для лотов тендера по его категории. sql.ErrNoRows — у тендера нет
+-- категории или для категории схема не задана: параметры не проверяются.
+SELECT s.* FROM lot_key_parameter_schemas s
+JOIN tenders t ON t.category_id = s.tender_category_id
+WHERE t.id = $1;
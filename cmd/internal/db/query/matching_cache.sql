@@ -10,6 +10,8 @@ WHERE
 
 -- name: UpsertMatchingCache :exec
 -- (Для Python-воркера) Записывает результат RAG-поиска в кэш.
+-- Выведенная из оборота позиция каталога подменяется своей заменой (replaced_by_id).
+-- Ручное исправление аналитика (source = 'manual') воркер не перезаписывает.
 INSERT INTO matching_cache (
     job_title_hash,
     norm_version,
@@ -17,23 +19,108 @@ INSERT INTO matching_cache (
     catalog_position_id,
     expires_at
 ) VALUES (
-    $1, $2, $3, $4, $5
+    sqlc.arg(job_title_hash)::text,
+    sqlc.arg(norm_version)::smallint,
+    sqlc.narg(job_title_text)::text,
+    COALESCE(
+        (SELECT cp.replaced_by_id FROM catalog_positions cp WHERE cp.id = sqlc.arg(catalog_position_id)::bigint),
+        sqlc.arg(catalog_position_id)::bigint
+    ),
+    sqlc.narg(expires_at)::timestamptz
 )
 ON CONFLICT (job_title_hash, norm_version) 
 DO UPDATE SET
     catalog_position_id = EXCLUDED.catalog_position_id,
     expires_at = EXCLUDED.expires_at,
-    job_title_text = EXCLUDED.job_title_text;
+    job_title_text = EXCLUDED.job_title_text
+WHERE matching_cache.source = 'rag';
 -- (RETURNING * удален)
 
--- name: RetargetMatchingCache :exec
--- (Для Go-сервера, при слиянии) Перенаправляет все кэшированные
+-- name: UpsertManualMatchingCache :exec
+-- (Для Go-сервера, ручное исправление сопоставления) Записывает в кэш позицию,
+-- выбранную аналитиком: запись без TTL, приоритетнее результата RAG.
+INSERT INTO matching_cache (
+    job_title_hash,
+    norm_version,
+    job_title_text,
+    catalog_position_id,
+    expires_at,
+    source
+) VALUES (
+    sqlc.arg(job_title_hash)::text,
+    sqlc.arg(norm_version)::smallint,
+    sqlc.narg(job_title_text)::text,
+    sqlc.arg(catalog_position_id)::bigint,
+    NULL,
+    'manual'
+)
+ON CONFLICT (job_title_hash, norm_version)
+DO UPDATE SET
+    catalog_position_id = EXCLUDED.catalog_position_id,
+    expires_at = NULL,
+    job_title_text = EXCLUDED.job_title_text,
+    source = 'manual';
+
+-- name: DeleteMatchingCacheEntry :execrows
+-- (Для отмены сопоставления) Удаляет запись кэша, если она всё ещё указывает
+-- на отменяемую позицию каталога.
+DELETE FROM matching_cache
+WHERE
+    job_title_hash = sqlc.arg(job_title_hash)::text
+    AND norm_version = sqlc.arg(norm_version)::smallint
+    AND catalog_position_id = sqlc.arg(catalog_position_id)::bigint;
+
+-- name: FindMatchingCacheForPosition :one
+-- (Для ручного исправления) Запись кэша активной версии нормализации, по которой
+-- позиция получила текущее сопоставление: воркер и импорт сохраняют в
+-- job_title_text название позиции КП.
+SELECT * FROM matching_cache
+WHERE
+    job_title_text = sqlc.arg(job_title_text)::text
+    AND catalog_position_id = sqlc.arg(catalog_position_id)::bigint
+    AND norm_version = COALESCE(
+        (SELECT value_numeric FROM system_settings WHERE key = 'norm_version'),
+        1
+    )::smallint
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: RetargetMatchingCache :execrows
+-- (Для Go-сервера, при слиянии и выводе позиции из оборота) Перенаправляет все кэшированные
 -- записи со старого ID дубликата на новый ID.
+-- Возвращает количество перенаправленных записей.
 UPDATE matching_cache
-SET catalog_position_id = $1 -- $1 = main_id
-WHERE catalog_position_id = $2; -- $2 = duplicate_id
+SET catalog_position_id = sqlc.arg(main_id)::bigint
+WHERE catalog_position_id = sqlc.arg(duplicate_id)::bigint;
+
+-- name: GetActiveNormVersion :one
+-- Активная версия нормализации (system_settings.norm_version, по умолчанию 1).
+SELECT COALESCE(
+    (SELECT value_numeric FROM system_settings WHERE key = 'norm_version'),
+    1
+)::smallint AS norm_version;
+
+-- name: GetActiveMatchingCache :one
+-- (Для импорта) Проверяет кэш по хешу в активной версии нормализации.
+SELECT * FROM matching_cache
+WHERE
+    job_title_hash = $1
+    AND norm_version = COALESCE(
+        (SELECT value_numeric FROM system_settings WHERE key = 'norm_version'),
+        1
+    )::smallint;
+
+-- name: ListActiveMatchingCacheByHashes :many
+-- (Для импорта) GetActiveMatchingCache для нескольких хешей одним запросом.
+SELECT * FROM matching_cache
+WHERE
+    job_title_hash = ANY(sqlc.arg(hashes)::text[])
+    AND norm_version = COALESCE(
+        (SELECT value_numeric FROM system_settings WHERE key = 'norm_version'),
+        1
+    )::smallint;
 
--- name: ClearExpiredMatchingCache :exec
--- (Для Cron-джоба) Очищает "тухлый" кэш.
+-- name: DeleteMatchingCacheBelowVersion :execrows
+-- (Для перенормализации) Удаляет записи кэша устаревших версий нормализации.
 DELETE FROM matching_cache
-WHERE expires_at IS NOT NULL AND expires_at < now();
\ No newline at end of file
+WHERE norm_version < sqlc.arg(norm_version)::smallint;
@@ -0,0 +1,96 @@
+-- matching_correction.sql
+-- Ручные исправления сопоставлений RAG (обратная связь для дообучения).
+
+-- name: LockPositionItemForCorrection :one
+-- Текущее сопоставление позиции КП; строка блокируется до конца транзакции,
+-- чтобы параллельное исправление или воркер не записали поверх.
+SELECT
+    id,
+    catalog_position_id,
+    matched_by,
+    job_title_in_proposal,
+    is_chapter
+FROM position_items
+WHERE id = $1
+FOR UPDATE;
+
+-- name: GetCatalogPositionMatchTarget :one
+-- Позиция каталога, выбранная аналитиком: вид, статус и куда она ушла из
+-- оборота (замена при выводе или мастер-позиция при слиянии).
+SELECT id, kind, status, replaced_by_id, merged_into_id
+FROM catalog_positions
+WHERE id = $1;
+
+-- name: SetCatalogPositionIDManual :exec
+-- Записывает сопоставление, выбранное аналитиком, независимо от того, какой
+-- воркер закрепил позицию. matched_by = 'user:<id>' — воркеры RAG больше не
+-- перезаписывают позицию (SetCatalogPositionID), аренда снимается.
+UPDATE position_items
+SET
+    catalog_position_id = sqlc.arg(catalog_position_id)::bigint,
+    matched_by = sqlc.arg(matched_by)::text,
+    matched_at = NOW(),
+    claimed_by = NULL,
+    claimed_until = NULL,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)::bigint;
+
+-- name: ClearCatalogPositionID :exec
+-- Отменяет ошибочное сопоставление: позиция без catalog_position_id, закрепления
+-- и аренды снова попадает в очередь несопоставленных (GetUnmatchedPositions).
+UPDATE position_items
+SET
+    catalog_position_id = NULL,
+    matched_by = NULL,
+    matched_at = NULL,
+    claimed_by = NULL,
+    claimed_until = NULL,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)::bigint;
+
+-- name: CreateMatchingCorrection :one
+INSERT INTO matching_corrections (
+    position_item_id,
+    job_title_text,
+    job_title_hash,
+    norm_version,
+    previous_catalog_position_id,
+    previous_matched_by,
+    catalog_position_id,
+    corrected_by,
+    comment
+) VALUES (
+    sqlc.arg(position_item_id)::bigint,
+    sqlc.arg(job_title_text)::text,
+    sqlc.arg(job_title_hash)::text,
+    sqlc.arg(norm_version)::smallint,
+    sqlc.narg(previous_catalog_position_id)::bigint,
+    sqlc.narg(previous_matched_by)::text,
+    sqlc.arg(catalog_position_id)::bigint,
+    sqlc.narg(corrected_by)::bigint,
+    sqlc.narg(comment)::text
+)
+RETURNING *;
+
+-- name: ListMatchingCorrections :many
+-- (Для Python-воркера) Исправления после курсора after_id в порядке id — с
+-- названиями ошибочной и правильной позиций каталога.
+SELECT
+    mc.id,
+    mc.position_item_id,
+    mc.job_title_text,
+    mc.job_title_hash,
+    mc.norm_version,
+    mc.previous_catalog_position_id,
+    prev.standard_job_title AS previous_standard_job_title,
+    mc.previous_matched_by,
+    mc.catalog_position_id,
+    cp.standard_job_title,
+    mc.comment,
+    mc.created_at
+FROM matching_corrections mc
+JOIN catalog_positions cp ON cp.id = mc.catalog_position_id
+LEFT JOIN catalog_positions prev ON prev.id = mc.previous_catalog_position_id
+WHERE mc.id > sqlc.arg(after_id)::bigint
+ORDER BY mc.id
+LIMIT sqlc.arg(row_limit)::int;
@@ -0,0 +1,86 @@
+-- matching_stats.sql
+-- Метрики качества матчинга (GET /api/v1/admin/matching/stats).
+
+-- name: ListImportCacheStatsByWeek :many
+-- Попадания в matching_cache при успешных импортах периода [date_from, date_to) по неделям.
+SELECT
+    date_trunc('week', started_at)::timestamptz AS week_start,
+    COUNT(*)::bigint AS imports,
+    COALESCE(SUM(cache_hits), 0)::bigint AS cache_hits,
+    COALESCE(SUM(cache_misses), 0)::bigint AS cache_misses
+FROM import_metrics
+WHERE
+    status = 'success'
+    AND started_at >= sqlc.arg(date_from)::timestamptz
+    AND started_at < sqlc.arg(date_to)::timestamptz
+GROUP BY 1
+ORDER BY 1;
+
+-- name: GetPositionMatchSourceStats :one
+-- Позиции КП (без заголовков разделов) по способу сопоставления на текущий момент:
+--   unmatched — в очереди RAG (нет позиции каталога или она ещё pending_indexing);
+--   manual    — сопоставлены аналитиком (matched_by = user:<id>);
+--   rag       — сопоставлены воркером;
+--   cache     — сопоставлены при импорте по matching_cache (matched_by не задан).
+SELECT
+    COUNT(*) FILTER (
+        WHERE pi.catalog_position_id IS NULL OR cp.status = 'pending_indexing'
+    )::bigint AS unmatched,
+    COUNT(*) FILTER (
+        WHERE cp.status <> 'pending_indexing' AND pi.matched_by LIKE 'user:%'
+    )::bigint AS manual,
+    COUNT(*) FILTER (
+        WHERE cp.status <> 'pending_indexing' AND pi.matched_by IS NOT NULL AND pi.matched_by NOT LIKE 'user:%'
+    )::bigint AS rag,
+    COUNT(*) FILTER (
+        WHERE cp.status <> 'pending_indexing' AND pi.matched_by IS NULL
+    )::bigint AS cache
+FROM position_items pi
+LEFT JOIN catalog_positions cp ON cp.id = pi.catalog_position_id
+WHERE pi.is_chapter = false;
+
+-- name: GetMergeDecisionStats :one
+-- Решения по предложениям слияния за период [date_from, date_to) и текущая очередь.
+-- Принятые — APPROVED и EXECUTED; GROUPED (объединение под общим родителем) не учитывается.
+-- Средняя схожесть без решений — 0 (сервис отдаёт null).
+SELECT
+    COUNT(*) FILTER (
+        WHERE status IN ('APPROVED', 'EXECUTED')
+          AND resolved_at >= sqlc.arg(date_from)::timestamptz AND resolved_at < sqlc.arg(date_to)::timestamptz
+    )::bigint AS accepted,
+    COALESCE(AVG(similarity_score) FILTER (
+        WHERE status IN ('APPROVED', 'EXECUTED')
+          AND resolved_at >= sqlc.arg(date_from)::timestamptz AND resolved_at < sqlc.arg(date_to)::timestamptz
+    ), 0)::float8 AS accepted_avg_similarity,
+    COUNT(*) FILTER (
+        WHERE status = 'REJECTED'
+          AND resolved_at >= sqlc.arg(date_from)::timestamptz AND resolved_at < sqlc.arg(date_to)::timestamptz
+    )::bigint AS rejected,
+    COALESCE(AVG(similarity_score) FILTER (
+        WHERE status = 'REJECTED'
+          AND resolved_at >= sqlc.arg(date_from)::timestamptz AND resolved_at < sqlc.arg(date_to)::timestamptz
+    ), 0)::float8 AS rejected_avg_similarity,
+    COUNT(*) FILTER (WHERE status = 'PENDING')::bigint AS pending
+FROM suggested_merges;
+
+-- name: GetUnmatchedBacklogAge :one
+-- Возраст позиций в очереди несопоставленных (секунды с создания позиции).
+SELECT
+    COUNT(*)::bigint AS positions,
+    COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM NOW() - pi.created_at)), 0)::float8 AS p50_seconds,
+    COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM NOW() - pi.created_at)), 0)::float8 AS p90_seconds,
+    COALESCE(percentile_cont(0.99) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM NOW() - pi.created_at)), 0)::float8 AS p99_seconds,
+    COALESCE(MAX(EXTRACT(EPOCH FROM NOW() - pi.created_at)), 0)::float8 AS max_seconds
+FROM position_items pi
+LEFT JOIN catalog_positions cp ON cp.id = pi.catalog_position_id
+WHERE
+    pi.is_chapter = false
+    AND (pi.catalog_position_id IS NULL OR cp.status = 'pending_indexing');
+
+-- name: CountMatchingCorrections :one
+-- Ручные исправления сопоставлений за период [date_from, date_to).
+SELECT COUNT(*)::bigint
+FROM matching_corrections
+WHERE
+    created_at >= sqlc.arg(date_from)::timestamptz
+    AND created_at < sqlc.arg(date_to)::timestamptz;
@@ -0,0 +1,67 @@
+-- notification.sql
+-- Лента уведомлений пользователей (notifications). Уведомления создаются
+-- по доменным событиям сразу для всех адресатов одним INSERT ... SELECT.
+
+-- name: CreateTenderNotifications :execrows
+-- Уведомление о событии тендера активным пользователям, которые следят за ним
+-- (user_tender_subscriptions, миграция 000033).
+INSERT INTO notifications (user_id, kind, title, body, tender_id, lot_id, payload)
+SELECT u.id,
+       sqlc.arg(kind)::text,
+       sqlc.arg(title)::text,
+       sqlc.arg(body)::text,
+       s.tender_id,
+       sqlc.narg(lot_id)::bigint,
+       sqlc.arg(payload)::jsonb
+FROM user_tender_subscriptions s
+JOIN users u ON u.id = s.user_id
+WHERE s.tender_id = sqlc.arg(tender_id)::bigint
+  AND u.is_active;
+
+-- name: CreateRoleNotifications :execrows
+-- Уведомление активным пользователям с одной из ролей. Пользователь, у которого
+-- уже есть непрочитанное уведомление того же вида, второе не получает: очередь
+-- заявок пополняется по одной, а в ленте достаточно одного напоминания.
+INSERT INTO notifications (user_id, kind, title, body, payload)
+SELECT u.id,
+       sqlc.arg(kind)::text,
+       sqlc.arg(title)::text,
+       sqlc.arg(body)::text,
+       sqlc.arg(payload)::jsonb
+FROM users u
+WHERE u.is_active
+  AND u.role = ANY(sqlc.arg(roles)::text[])
+  AND NOT EXISTS (
+      SELECT 1 FROM notifications n
+      WHERE n.user_id = u.id
+        AND n.kind = sqlc.arg(kind)::text
+        AND n.read_at IS NULL
+  );
+
+-- name: ListNotificationsByUser :many
+-- Лента пользователя, новые первыми. unread_only = true — только непрочитанные.
+SELECT * FROM notifications
+WHERE user_id = sqlc.arg(user_id)
+  AND (NOT sqlc.arg(unread_only)::boolean OR read_at IS NULL)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit)::int
+OFFSET sqlc.arg(page_offset)::int;
+
+-- name: CountNotificationsByUser :one
+SELECT COUNT(*)::bigint FROM notifications
+WHERE user_id = sqlc.arg(user_id)
+  AND (NOT sqlc.arg(unread_only)::boolean OR read_at IS NULL);
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*)::bigint FROM notifications
+WHERE user_id = $1
+  AND read_at IS NULL;
+
+-- name: MarkNotificationRead :one
+-- Отмечает уведомление прочитанным. Повторная отметка не меняет read_at.
+-- sql.ErrNoRows — уведомления нет или оно принадлежит другому пользователю.
+UPDATE notifications
+SET read_at = COALESCE(read_at, NOW())
+WHERE id = sqlc.arg(id)
+  AND user_id = sqlc.arg(user_id)
+RETURNING *;
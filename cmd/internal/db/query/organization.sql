@@ -0,0 +1,55 @@
+-- organization.sql
+-- Организации и принадлежность им тендеров. Пользователь без права
+-- organizations:manage видит только тендеры своей организации; лоты,
+-- предложения и победители наследуют организацию тендера.
+
+-- name: CreateOrganization :one
+-- Создает организацию. name уникален (uq_organizations_name).
+INSERT INTO organizations (name)
+VALUES ($1)
+RETURNING *;
+
+-- name: ListOrganizations :many
+SELECT * FROM organizations
+ORDER BY name;
+
+-- name: GetOrganizationByID :one
+SELECT * FROM organizations
+WHERE id = $1;
+
+-- name: GetDefaultOrganization :one
+-- Организация по умолчанию (миграция 000027): для пользователей и импортов,
+-- в которых организация не указана.
+SELECT * FROM organizations
+WHERE is_default;
+
+-- name: GetTenderOrganizationID :one
+-- Организация-владелец тендера. Используется проверкой доступа к /tenders/:id
+-- до выполнения хендлера; sql.ErrNoRows — тендера нет.
+SELECT organization_id FROM tenders
+WHERE id = $1;
+
+-- name: GetLotOrganizationID :one
+SELECT t.organization_id FROM lots l
+JOIN tenders t ON t.id = l.tender_id
+WHERE l.id = $1;
+
+-- name: GetProposalOrganizationID :one
+SELECT t.organization_id FROM proposals p
+JOIN lots l ON l.id = p.lot_id
+JOIN tenders t ON t.id = l.tender_id
+WHERE p.id = $1;
+
+-- name: GetWinnerOrganizationID :one
+SELECT t.organization_id FROM winners w
+JOIN proposals p ON p.id = w.proposal_id
+JOIN lots l ON l.id = p.lot_id
+JOIN tenders t ON t.id = l.tender_id
+WHERE w.id = $1;
+
+-- name: GetPositionItemOrganizationID :one
+SELECT t.organization_id FROM position_items pi
+JOIN proposals p ON p.id = pi.proposal_id
+JOIN lots l ON l.id = p.lot_id
+JOIN tenders t ON t.id = l.tender_id
+WHERE pi.id = $1;
@@ -0,0 +1,43 @@
+-- name: EnqueueOutboxEvent :one
+-- Вызывается внутри транзакции, изменение которой описывает событие.
+INSERT INTO outbox_events (event, aggregate_id, payload)
+VALUES (sqlc.arg(event), sqlc.arg(aggregate_id), sqlc.arg(payload)::jsonb)
+RETURNING id;
+
+-- name: ClaimDueOutboxEvents :many
+-- Забирает пачку событий, срок которых наступил, и сдвигает next_attempt_at на
+-- leased_until: если инстанс упадёт посреди отправки, событие вернётся в
+-- очередь после окончания аренды. SKIP LOCKED позволяет нескольким инстансам
+-- API разбирать очередь параллельно без двойной отправки.
+UPDATE outbox_events
+SET next_attempt_at = sqlc.arg(leased_until)::timestamptz
+WHERE id IN (
+    SELECT id
+    FROM outbox_events
+    WHERE status = 'pending'
+      AND next_attempt_at <= NOW()
+    ORDER BY next_attempt_at, id
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, event, aggregate_id, payload, attempts, created_at;
+
+-- name: MarkOutboxEventDelivered :exec
+UPDATE outbox_events
+SET status          = 'delivered',
+    attempts        = attempts + 1,
+    last_attempt_at = NOW(),
+    response_status = sqlc.arg(response_status)::integer,
+    last_error      = NULL,
+    delivered_at    = NOW()
+WHERE id = sqlc.arg(id);
+
+-- name: MarkOutboxEventAttemptFailed :exec
+-- Фиксирует неуспешную попытку; событие остаётся pending до следующей.
+UPDATE outbox_events
+SET attempts        = attempts + 1,
+    last_attempt_at = NOW(),
+    next_attempt_at = sqlc.arg(next_attempt_at),
+    response_status = sqlc.narg(response_status)::integer,
+    last_error      = sqlc.arg(last_error)::text
+WHERE id = sqlc.arg(id);
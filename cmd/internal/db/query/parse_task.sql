@@ -0,0 +1,104 @@
+-- parse_task.sql
+-- История задач парсера, поставленных загрузкой файлов (POST /api/v1/upload-tender)
+-- и получением тендеров с ЭТП (POST /api/v1/tenders/fetch), а также
+-- обновлениями открытых тендеров по расписанию (tender_sync.sql).
+
+-- name: CreateParseTask :one
+-- Повтор task_id (парсер вернул уже известную задачу) не создает вторую запись.
+INSERT INTO parse_tasks (task_id, user_id, organization_id, file_name, file_size, enable_ai)
+VALUES (
+    sqlc.arg(task_id),
+    sqlc.narg(user_id),
+    sqlc.arg(organization_id),
+    sqlc.arg(file_name),
+    sqlc.arg(file_size),
+    sqlc.arg(enable_ai)
+)
+ON CONFLICT (task_id) DO UPDATE SET updated_at = NOW()
+RETURNING *;
+
+-- name: CreateFetchParseTask :one
+-- Задача получения тендера с ЭТП: файла нет, вместо него etp_id.
+INSERT INTO parse_tasks (task_id, user_id, organization_id, file_name, file_size, enable_ai, source, etp_id)
+VALUES (
+    sqlc.arg(task_id),
+    sqlc.narg(user_id),
+    sqlc.arg(organization_id),
+    '',
+    0,
+    sqlc.arg(enable_ai),
+    'fetch',
+    sqlc.arg(etp_id)::text
+)
+ON CONFLICT (task_id) DO UPDATE SET updated_at = NOW()
+RETURNING *;
+
+-- name: CreateSyncParseTask :one
+-- Задача обновления тендера по расписанию: ставит планировщик, пользователя нет.
+INSERT INTO parse_tasks (task_id, organization_id, file_name, file_size, enable_ai, source, etp_id)
+VALUES (
+    sqlc.arg(task_id),
+    sqlc.arg(organization_id),
+    '',
+    0,
+    FALSE,
+    'sync',
+    sqlc.arg(etp_id)::text
+)
+ON CONFLICT (task_id) DO UPDATE SET updated_at = NOW()
+RETURNING *;
+
+-- name: GetPendingFetchParseTask :one
+-- Последняя незавершённая задача получения тендера организации, поставленная
+-- не раньше pending_seconds назад. Более старая считается потерянной парсером.
+SELECT * FROM parse_tasks
+WHERE source = 'fetch'
+  AND etp_id = sqlc.arg(etp_id)::text
+  AND organization_id = sqlc.arg(organization_id)
+  AND finished_at IS NULL
+  AND created_at > NOW() - make_interval(secs => sqlc.arg(pending_seconds)::int)
+ORDER BY created_at DESC, id DESC
+LIMIT 1;
+
+-- name: GetParseTaskByTaskID :one
+SELECT * FROM parse_tasks
+WHERE task_id = $1;
+
+-- name: UpdateParseTaskPolledStatus :exec
+-- Запоминает ответ парсера на запрос статуса. Итоговый статус, о котором
+-- парсер сообщил сам (finished_at), опросом не перезаписывается.
+UPDATE parse_tasks
+SET status = sqlc.arg(status),
+    last_status = sqlc.arg(last_status)::jsonb,
+    status_source = 'polled',
+    updated_at = NOW()
+WHERE task_id = sqlc.arg(task_id)
+  AND finished_at IS NULL;
+
+-- name: UpdateParseTaskPushedStatus :one
+-- Статус, о котором сообщил парсер. tender_id и error_message, если не
+-- переданы, сохраняют прежние значения. completed / failed завершают задачу.
+UPDATE parse_tasks
+SET status = sqlc.arg(status),
+    status_source = 'pushed',
+    tender_id = COALESCE(sqlc.narg(tender_id)::bigint, tender_id),
+    error_message = COALESCE(sqlc.narg(error_message)::text, error_message),
+    finished_at = CASE
+        WHEN sqlc.arg(status) IN ('completed', 'failed') THEN COALESCE(finished_at, NOW())
+        ELSE finished_at
+    END,
+    updated_at = NOW()
+WHERE task_id = sqlc.arg(task_id)
+RETURNING *;
+
+-- name: ListParseTasksByUser :many
+-- Последние загрузки пользователя, новые первыми.
+SELECT * FROM parse_tasks
+WHERE user_id = sqlc.arg(user_id)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit)::int
+OFFSET sqlc.arg(page_offset)::int;
+
+-- name: CountParseTasksByUser :one
+SELECT COUNT(*)::bigint FROM parse_tasks
+WHERE user_id = $1;
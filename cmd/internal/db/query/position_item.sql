@@ -8,6 +8,11 @@
 --
 -- ИЗМЕНЕНИЕ v4 (RAG Workflow):
 -- `catalog_position_id` ($2) теперь `NULLABLE` (тип sql.NullInt64).
+--
+-- Bulk-импорт (import.bulk_positions) пишет позиции через COPY и свой запрос
+-- слияния — mergeStagedPositionItems в services/importer/bulk_positions.go.
+-- При изменении колонок или ON CONFLICT обновите и его, а также
+-- RestoreLotPositionItems (import_snapshot.sql).
 -- #####################################################################
 INSERT INTO position_items (
     proposal_id,
@@ -32,11 +37,13 @@ INSERT INTO position_items (
     total_cost_total,
     deviation_from_baseline_cost,
     is_chapter,
-    chapter_ref_in_proposal
+    chapter_ref_in_proposal,
+    article_smr,
+    currency
 ) VALUES (
     $1, 
     $2, -- <-- ИСПРАВЛЕНО: Просто $2. sqlc сам увидит NULLABLE.
-    $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23
+    $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25
 )
 ON CONFLICT (proposal_id, position_key_in_proposal) DO UPDATE SET
     catalog_position_id = EXCLUDED.catalog_position_id,
@@ -60,6 +67,17 @@ ON CONFLICT (proposal_id, position_key_in_proposal) DO UPDATE SET
     deviation_from_baseline_cost = EXCLUDED.deviation_from_baseline_cost,
     is_chapter = EXCLUDED.is_chapter,
     chapter_ref_in_proposal = EXCLUDED.chapter_ref_in_proposal,
+    article_smr = EXCLUDED.article_smr,
+    currency = EXCLUDED.currency,
+    -- Закрепление воркера снимается, только если повторный импорт сменил сопоставление
+    matched_by = CASE
+        WHEN position_items.catalog_position_id IS NOT DISTINCT FROM EXCLUDED.catalog_position_id
+        THEN position_items.matched_by
+    END,
+    matched_at = CASE
+        WHEN position_items.catalog_position_id IS NOT DISTINCT FROM EXCLUDED.catalog_position_id
+        THEN position_items.matched_at
+    END,
     updated_at = NOW()
 RETURNING *;
 
@@ -101,17 +119,26 @@ WHERE id = $1;
 DELETE FROM position_items
 WHERE proposal_id = $1;
 
+-- name: DeleteStalePositionItems :execrows
+-- Сверка при повторном импорте: удаляет позиции предложения, ключей которых
+-- нет в новом payload. Пустой массив keys удаляет все позиции предложения.
+-- Возвращает количество удаленных строк.
+DELETE FROM position_items
+WHERE proposal_id = sqlc.arg(proposal_id)
+  AND position_key_in_proposal <> ALL(sqlc.arg(keys)::text[]);
+
 -- #####################################################################
 -- НОВЫЕ ЗАПРОСЫ ДЛЯ RAG-ВОРКФЛОУ (добавлены в v4)
 -- #####################################################################
 
--- name: RetargetPositionItems :exec
+-- name: RetargetPositionItems :execrows
 -- (Для Go-сервера / Админки) Атомарно "перевешивает" все position_items
 -- со старого ID дубликата на новый ID основной (канонической) записи.
--- Используется при слиянии дубликатов.
+-- Используется при одобрении слияния (POST /api/v1/admin/merges/:id/approve).
+-- Возвращает количество перевешенных строк.
 UPDATE position_items
-SET catalog_position_id = $1 -- $1 = main_id
-WHERE catalog_position_id = $2; -- $2 = duplicate_id
+SET catalog_position_id = sqlc.arg(main_id)::bigint, updated_at = NOW()
+WHERE catalog_position_id = sqlc.arg(duplicate_id)::bigint;
 
 -- name: ListOrphanPositionItems :many
 -- (Для Python-воркера) Находит "осиротевшие" position_items
@@ -120,15 +147,44 @@ SELECT * FROM position_items
 WHERE catalog_position_id IS NULL
 LIMIT $1;
 
--- name: SetCatalogPositionID :exec
+-- name: SetCatalogPositionID :execrows
 -- (Для Python-воркера) "Закрывает" "осиротевшую" запись,
--- установив catalog_position_id после RAG-поиска.
+-- установив catalog_position_id после RAG-поиска, и закрепляет её за воркером.
+--
+-- Результат применяется, только если позиция ещё не закреплена (matched_by IS NULL),
+-- закреплена тем же воркером или уже сопоставлена с тем же catalog_position_id
+-- (повтор запроса). Параллельный UPDATE того же id ждёт блокировку строки и
+-- перепроверяет условие на новой версии, поэтому выигрывает ровно один воркер.
+-- 0 затронутых строк означает конфликт (или отсутствие позиции).
+-- Аренда позиции (claimed_by/claimed_until) при сопоставлении снимается.
+-- Выведенная из оборота позиция каталога подменяется своей заменой (replaced_by_id).
 UPDATE position_items
-SET catalog_position_id = $1 -- $1 = main_id (найденный RAG-поиском)
-WHERE id = $2; -- $2 = id "осиротевшей" записи
+SET
+    catalog_position_id = target.id,
+    matched_by = sqlc.arg(matched_by)::text,
+    matched_at = NOW(),
+    claimed_by = NULL,
+    claimed_until = NULL,
+    updated_at = NOW()
+FROM (
+    SELECT COALESCE(
+        (SELECT cp.replaced_by_id FROM catalog_positions cp WHERE cp.id = sqlc.arg(catalog_position_id)::bigint),
+        sqlc.arg(catalog_position_id)::bigint
+    ) AS id
+) target
+WHERE
+    position_items.id = sqlc.arg(id)
+    AND (
+        position_items.matched_by IS NULL
+        OR position_items.matched_by = sqlc.arg(matched_by)::text
+        OR position_items.catalog_position_id = target.id
+    );
 
 -- name: GetUnmatchedPositions :many
--- (Версия 6: Hardened - с TRIM, защитой от циклов и ORDER BY)
+-- (Версия 7: курсор after_id, фильтры по тендеру и дате создания, аренда воркеров)
+--
+-- Позиции, арендованные другим воркером (claimed_until в будущем), не выдаются;
+-- при worker_id = NULL исключаются все арендованные позиции.
 
 -- 1. (CTE) Рекурсивно строим "дерево" разделов
 WITH RECURSIVE Breadcrumbs AS (
@@ -189,12 +245,57 @@ LEFT JOIN
                      AND TRIM(b.item_number_in_proposal) = TRIM(pi.chapter_ref_in_proposal)
 LEFT JOIN
     catalog_positions AS cp ON pi.catalog_position_id = cp.id
+JOIN
+    proposals AS pr ON pr.id = pi.proposal_id
+JOIN
+    lots AS lt ON lt.id = pr.lot_id
 WHERE 
     (pi.catalog_position_id IS NULL OR cp.status = 'pending_indexing')
     AND pi.is_chapter = false
+    AND pi.id > sqlc.arg(after_id)::bigint
+    AND (sqlc.narg(tender_id)::bigint IS NULL OR lt.tender_id = sqlc.narg(tender_id)::bigint)
+    AND (sqlc.narg(created_after)::timestamptz IS NULL OR pi.created_at >= sqlc.narg(created_after)::timestamptz)
+    AND (
+        pi.claimed_until IS NULL
+        OR pi.claimed_until <= NOW()
+        OR pi.claimed_by = sqlc.narg(worker_id)::text
+    )
 ORDER BY
-    pi.id -- (ВКЛЮЧЕНО: Детерминированный LIMIT)
-LIMIT $1;
+    pi.id -- (ВКЛЮЧЕНО: Детерминированный LIMIT и курсор)
+LIMIT sqlc.arg(row_limit)::int;
+
+-- name: ClaimUnmatchedPositions :many
+-- (Для Python-воркера) Арендует следующую порцию несопоставленных позиций:
+-- те же условия, что и GetUnmatchedPositions. SKIP LOCKED — параллельные
+-- воркеры получают непересекающиеся порции, не дожидаясь друг друга.
+WITH candidates AS (
+    SELECT pi.id
+    FROM position_items AS pi
+    LEFT JOIN catalog_positions AS cp ON pi.catalog_position_id = cp.id
+    JOIN proposals AS pr ON pr.id = pi.proposal_id
+    JOIN lots AS lt ON lt.id = pr.lot_id
+    WHERE
+        (pi.catalog_position_id IS NULL OR cp.status = 'pending_indexing')
+        AND pi.is_chapter = false
+        AND pi.id > sqlc.arg(after_id)::bigint
+        AND (sqlc.narg(tender_id)::bigint IS NULL OR lt.tender_id = sqlc.narg(tender_id)::bigint)
+        AND (sqlc.narg(created_after)::timestamptz IS NULL OR pi.created_at >= sqlc.narg(created_after)::timestamptz)
+        AND (
+            pi.claimed_until IS NULL
+            OR pi.claimed_until <= NOW()
+            OR pi.claimed_by = sqlc.arg(worker_id)::text
+        )
+    ORDER BY pi.id
+    LIMIT sqlc.arg(row_limit)::int
+    FOR UPDATE OF pi SKIP LOCKED
+)
+UPDATE position_items AS pi
+SET
+    claimed_by = sqlc.arg(worker_id)::text,
+    claimed_until = NOW() + make_interval(secs => sqlc.arg(lease_seconds)::int)
+FROM candidates
+WHERE pi.id = candidates.id
+RETURNING pi.id, pi.claimed_until;
 
 -- name: ListPositionsForEstimate :many
 -- Полный список строк КП (позиции + главы) для страницы просмотра предложения.
@@ -238,6 +339,7 @@ SELECT
     pi.total_cost_total,
 
     pi.deviation_from_baseline_cost,
+    pi.currency,
 
     pi.created_at,
     pi.updated_at,
@@ -259,3 +361,138 @@ ORDER BY
     pi.position_key_in_proposal ASC,
     pi.id ASC
 LIMIT 10000; -- Защитный лимит для предотвращения OOM на экстремальных объемах
+
+-- name: ListProposalPositionsPage :many
+-- Страница строк КП для GET /api/v1/proposals/:id/positions.
+-- Столбцы и порядок совпадают с ListPositionsForEstimate: строка страницы
+-- приводится к её типу, меняйте запросы вместе.
+-- by_parent = true — только непосредственные дочерние строки главы parent_ref
+-- (режим дерева); parent_ref = '' — корень: строки без ссылки на главу или со
+-- ссылкой на главу, которой в КП нет.
+SELECT
+    pi.id,
+    pi.proposal_id,
+    pi.catalog_position_id,
+    pi.position_key_in_proposal,
+
+    pi.item_number_in_proposal,
+    pi.chapter_number_in_proposal,
+    pi.chapter_ref_in_proposal,
+    pi.job_title_in_proposal,
+    pi.is_chapter,
+
+    pi.comment_organazier,
+    pi.comment_contractor,
+
+    pi.unit_id,
+    u.normalized_name AS unit_name,
+
+    pi.quantity,
+    pi.suggested_quantity,
+    pi.total_cost_for_organizer_quantity,
+
+    pi.unit_cost_materials,
+    pi.unit_cost_works,
+    pi.unit_cost_indirect_costs,
+    pi.unit_cost_total,
+
+    pi.total_cost_materials,
+    pi.total_cost_works,
+    pi.total_cost_indirect_costs,
+    pi.total_cost_total,
+
+    pi.deviation_from_baseline_cost,
+    pi.currency,
+
+    pi.created_at,
+    pi.updated_at,
+
+    cp.standard_job_title AS catalog_name
+FROM
+    position_items pi
+LEFT JOIN
+    units_of_measurement u ON pi.unit_id = u.id
+LEFT JOIN
+    catalog_positions cp ON pi.catalog_position_id = cp.id
+WHERE
+    pi.proposal_id = sqlc.arg(proposal_id)
+    AND (
+        NOT sqlc.arg(by_parent)::boolean
+        OR (sqlc.arg(parent_ref)::text <> '' AND TRIM(pi.chapter_ref_in_proposal) = sqlc.arg(parent_ref)::text)
+        OR (sqlc.arg(parent_ref)::text = '' AND NOT EXISTS (
+            SELECT 1 FROM position_items ch
+            WHERE ch.proposal_id = pi.proposal_id
+              AND ch.is_chapter
+              AND TRIM(ch.item_number_in_proposal) = NULLIF(TRIM(pi.chapter_ref_in_proposal), '')
+        ))
+    )
+ORDER BY
+    CASE
+        WHEN pi.position_key_in_proposal ~ '^[0-9]+(\.[0-9]+)?$'
+            THEN pi.position_key_in_proposal::numeric
+    END ASC NULLS LAST,
+    pi.position_key_in_proposal ASC,
+    pi.id ASC
+LIMIT sqlc.arg(page_limit)::int
+OFFSET sqlc.arg(page_offset)::int;
+
+-- name: CountProposalPositions :one
+-- Число строк КП для пагинации ListProposalPositionsPage (те же условия).
+SELECT COUNT(*)::bigint
+FROM position_items pi
+WHERE
+    pi.proposal_id = sqlc.arg(proposal_id)
+    AND (
+        NOT sqlc.arg(by_parent)::boolean
+        OR (sqlc.arg(parent_ref)::text <> '' AND TRIM(pi.chapter_ref_in_proposal) = sqlc.arg(parent_ref)::text)
+        OR (sqlc.arg(parent_ref)::text = '' AND NOT EXISTS (
+            SELECT 1 FROM position_items ch
+            WHERE ch.proposal_id = pi.proposal_id
+              AND ch.is_chapter
+              AND TRIM(ch.item_number_in_proposal) = NULLIF(TRIM(pi.chapter_ref_in_proposal), '')
+        ))
+    );
+
+-- name: CountChapterChildren :many
+-- Число непосредственных дочерних строк у глав страницы (режим дерева):
+-- фронтенд показывает раскрываемый узел и догружает его отдельным запросом.
+SELECT
+    TRIM(pi.chapter_ref_in_proposal)::text AS chapter_ref,
+    COUNT(*)::bigint AS children_count
+FROM position_items pi
+WHERE
+    pi.proposal_id = sqlc.arg(proposal_id)
+    AND TRIM(pi.chapter_ref_in_proposal) = ANY(sqlc.arg(chapter_refs)::text[])
+GROUP BY TRIM(pi.chapter_ref_in_proposal);
+
+-- name: ListLotComparisonItems :many
+-- Строки для матрицы сравнения предложений по лоту (GET /api/v1/lots/:id/comparison).
+-- Возвращает все сопоставленные с каталогом позиции (без глав) всех предложений лота,
+-- включая baseline. Агрегация по catalog_position_id и расчет отклонений
+-- выполняются в Go (точная десятичная арифметика), поэтому здесь только сырые значения.
+-- currency — валюта стоимостей строки (своя или предложения); курсы здесь не применяются.
+SELECT
+    pi.proposal_id,
+    pi.catalog_position_id,
+    cp.standard_job_title AS catalog_name,
+    u.normalized_name AS unit_name,
+    pi.quantity,
+    pi.unit_cost_total,
+    pi.total_cost_total,
+    COALESCE(pi.currency, p.currency)::text AS currency
+FROM
+    position_items pi
+JOIN
+    proposals p ON pi.proposal_id = p.id
+JOIN
+    catalog_positions cp ON pi.catalog_position_id = cp.id
+LEFT JOIN
+    units_of_measurement u ON pi.unit_id = u.id
+WHERE
+    p.lot_id = $1
+    AND pi.is_chapter = false
+ORDER BY
+    cp.standard_job_title ASC,
+    pi.catalog_position_id ASC,
+    pi.proposal_id ASC,
+    pi.id ASC;
@@ -0,0 +1,27 @@
+-- price_index.sql
+-- Индексы стоимости строительства по регионам и месяцам (price_indices).
+
+-- name: ListPriceIndices :many
+-- Ряды индексов; region фильтрует один регион (NULL — все). Строк немного
+-- (регион × месяц), поэтому без пагинации.
+SELECT * FROM price_indices
+WHERE sqlc.narg(region)::text IS NULL OR region = sqlc.narg(region)::text
+ORDER BY region, period;
+
+-- name: CreatePriceIndex :one
+INSERT INTO price_indices (region, period, value)
+VALUES (sqlc.arg(region), sqlc.arg(period), sqlc.arg(value))
+RETURNING *;
+
+-- name: UpdatePriceIndex :one
+UPDATE price_indices
+SET region = sqlc.arg(region),
+    period = sqlc.arg(period),
+    value = sqlc.arg(value),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeletePriceIndex :execrows
+DELETE FROM price_indices
+WHERE id = $1;
@@ -13,15 +13,17 @@ INSERT INTO proposals (
     is_baseline,
     contractor_coordinate,
     contractor_width,
-    contractor_height
+    contractor_height,
+    currency
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
+    $1, $2, $3, $4, $5, $6, $7
 )
 ON CONFLICT (lot_id, contractor_id) DO UPDATE SET
     is_baseline = EXCLUDED.is_baseline,
     contractor_coordinate = EXCLUDED.contractor_coordinate,
     contractor_width = EXCLUDED.contractor_width,
     contractor_height = EXCLUDED.contractor_height,
+    currency = EXCLUDED.currency,
     updated_at = NOW()
 RETURNING *;
 
@@ -99,6 +101,7 @@ WHERE id = $1;
 -- name: ListProposalsForTender :many
 -- Получает полный, обогащенный список предложений для указанного тендера.
 -- Включает данные о подрядчике, итоговую стоимость, статус победителя и доп. информацию в виде JSON.
+-- currency — валюта итоговой стоимости: валюта итоговой строки, если она указана, иначе предложения.
 -- Запрос безопасен благодаря пагинации.
 -- ############### ЗАМЕЧАНИЕ ПО ПРОИЗВОДИТЕЛЬНОСТИ (НА БУДУЩЕЕ) ###############
 -- Сортировка `ORDER BY is_winner DESC, total_cost ASC` по вычисляемым вложенными
@@ -112,6 +115,7 @@ SELECT
     c.title as contractor_title,
     c.inn as contractor_inn,
     (SELECT total_cost FROM proposal_summary_lines psl WHERE psl.proposal_id = p.id AND psl.summary_key = 'total_cost_with_vat' LIMIT 1) as total_cost,
+    COALESCE((SELECT psl.currency FROM proposal_summary_lines psl WHERE psl.proposal_id = p.id AND psl.summary_key = 'total_cost_with_vat' LIMIT 1), p.currency)::text as currency,
     (SELECT EXISTS (SELECT 1 FROM winners w WHERE w.proposal_id = p.id)) as is_winner,
     (
         SELECT jsonb_object_agg(pai.info_key, pai.info_value)
@@ -124,8 +128,11 @@ JOIN
     contractors c ON p.contractor_id = c.id
 JOIN
     lots l ON p.lot_id = l.id
+JOIN
+    tenders t ON l.tender_id = t.id
 WHERE
     l.tender_id = $1
+    AND t.deleted_at IS NULL -- предложения удалённого тендера скрыты вместе с ним
 ORDER BY
     is_winner DESC, total_cost ASC
 LIMIT $2
@@ -133,7 +140,7 @@ OFFSET $3;
 
 -- name: ListRichProposalsForLot :many
 -- Получает полный, обогащенный список предложений для указанного лота,
--- исключая baseline-предложения.
+-- исключая baseline-предложения. currency — валюта итоговой стоимости.
 -- Примечание: безопасен благодаря пагинации и фильтрации.
 SELECT
     p.id AS proposal_id,
@@ -146,6 +153,12 @@ SELECT
         WHERE psl.proposal_id = p.id AND psl.summary_key = 'total_cost_with_vat'
         LIMIT 1
     ) AS total_cost,
+    COALESCE((
+        SELECT psl.currency
+        FROM proposal_summary_lines psl
+        WHERE psl.proposal_id = p.id AND psl.summary_key = 'total_cost_with_vat'
+        LIMIT 1
+    ), p.currency)::text AS currency,
     (
         SELECT EXISTS (
             SELECT 1 FROM winners w WHERE w.proposal_id = p.id
@@ -163,6 +176,12 @@ JOIN
 WHERE
     p.lot_id = $1
     AND NOT p.is_baseline
+    AND EXISTS (
+        SELECT 1
+        FROM lots l
+        JOIN tenders t ON l.tender_id = t.id
+        WHERE l.id = p.lot_id AND t.deleted_at IS NULL
+    )
 ORDER BY
     is_winner DESC,
     total_cost ASC
@@ -176,6 +195,7 @@ OFFSET $3;
 -- Оптимизирован для эффективной загрузки всех связанных данных за один раз.
 -- Используется совместно с пагинацией лотов: загружает предложения только
 -- для текущей страницы лотов, избегая загрузки лишних данных.
+-- currency — валюта итоговой стоимости (итоговой строки или предложения).
 SELECT
     p.id,
     p.lot_id,
@@ -184,9 +204,11 @@ SELECT
     c.title AS contractor_name,
     c.inn AS contractor_inn,
     psl.total_cost,
+    COALESCE(psl.currency, p.currency)::text AS currency,
     w.id AS winner_id,
     w.rank AS winner_rank,
     w.notes AS winner_notes,
+    w.award_price AS winner_award_price,
     (w.id IS NOT NULL) AS is_winner,
     COALESCE((
         SELECT jsonb_object_agg(pai.info_key, pai.info_value)
@@ -214,6 +236,7 @@ ORDER BY
 SELECT 
     p.id,
     p.is_baseline,
+    p.currency,
     c.title as contractor_name,
     c.inn as contractor_inn,
     t.title as tender_title,
@@ -223,4 +246,5 @@ FROM proposals p
 JOIN contractors c ON p.contractor_id = c.id
 JOIN lots l ON p.lot_id = l.id
 JOIN tenders t ON l.tender_id = t.id
-WHERE p.id = $1;
\ No newline at end of file
+WHERE p.id = $1
+  AND t.deleted_at IS NULL;
\ No newline at end of file
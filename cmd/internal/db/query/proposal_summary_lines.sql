@@ -15,9 +15,10 @@ INSERT INTO proposal_summary_lines (
     materials_cost,
     works_cost,
     indirect_costs_cost,
-    total_cost
+    total_cost,
+    currency
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8
 )
 ON CONFLICT (proposal_id, summary_key) DO UPDATE SET
     job_title = EXCLUDED.job_title,
@@ -25,6 +26,7 @@ ON CONFLICT (proposal_id, summary_key) DO UPDATE SET
     works_cost = EXCLUDED.works_cost,
     indirect_costs_cost = EXCLUDED.indirect_costs_cost,
     total_cost = EXCLUDED.total_cost,
+    currency = EXCLUDED.currency,
     updated_at = NOW()
 RETURNING *;
 
@@ -74,6 +76,13 @@ WHERE id = $1;
 DELETE FROM proposal_summary_lines
 WHERE proposal_id = $1;
 
+-- name: DeleteStaleSummaryLines :execrows
+-- Сверка при повторном импорте: удаляет итоговые строки предложения, ключей
+-- которых нет в новом payload. Возвращает количество удаленных строк.
+DELETE FROM proposal_summary_lines
+WHERE proposal_id = sqlc.arg(proposal_id)
+  AND summary_key <> ALL(sqlc.arg(keys)::text[]);
+
 /*
 Для информации, вот какие структуры параметров sqlc может сгенерировать:
 
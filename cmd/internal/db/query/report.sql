@@ -0,0 +1,199 @@
+-- report.sql
+--
+-- Отчет об экономии (GET /api/v1/reports/savings): baseline (смета инициатора)
+-- против цены победителя по тендерам за период.
+--
+-- Лот сравнивается, если у него есть и итог baseline (summary_key =
+-- 'total_cost_with_vat'), и победитель с ценой контракта (winners.award_price).
+-- У лота с несколькими победителями берется лучший по rank. Итог baseline
+-- пересчитывается в базовую валюту по курсам (как в analytics.sql), цена
+-- победителя хранится в рублях и пересчитывается по курсу RUB. Суммы без курса
+-- в отчет не входят.
+--
+-- Экономия: savings_amount = baseline - winning, savings_percent =
+-- savings_amount / baseline * 100. Дата тендера — data_prepared_on_date,
+-- а если ее нет — created_at; период — полуинтервал [date_from, date_to).
+-- Мягко удаленные тендеры не учитываются.
+
+-- name: ListTenderSavings :many
+-- Экономия по каждому тендеру периода, у которого есть хотя бы один сравнимый лот.
+-- Сортировка — от новых тендеров к старым.
+WITH fx AS (
+    SELECT
+        unnest(sqlc.arg(currencies)::text[]) AS currency,
+        unnest(sqlc.arg(rates)::numeric[]) AS rate
+),
+scoped_tenders AS (
+    SELECT
+        t.id,
+        t.etp_id,
+        t.title,
+        t.category_id,
+        COALESCE(t.data_prepared_on_date, t.created_at) AS tender_date
+    FROM tenders t
+    WHERE t.deleted_at IS NULL
+      AND (sqlc.narg(date_from)::timestamptz IS NULL OR COALESCE(t.data_prepared_on_date, t.created_at) >= sqlc.narg(date_from)::timestamptz)
+      AND (sqlc.narg(date_to)::timestamptz IS NULL OR COALESCE(t.data_prepared_on_date, t.created_at) < sqlc.narg(date_to)::timestamptz)
+      AND (sqlc.narg(category_id)::bigint IS NULL OR t.category_id = sqlc.narg(category_id)::bigint)
+      AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint)
+),
+lot_costs AS (
+    SELECT
+        l.tender_id,
+        (SELECT psl.total_cost * fx.rate
+         FROM proposals p
+         JOIN proposal_summary_lines psl
+             ON psl.proposal_id = p.id AND psl.summary_key = 'total_cost_with_vat'
+         JOIN fx ON fx.currency = COALESCE(psl.currency, p.currency)
+         WHERE p.lot_id = l.id AND p.is_baseline
+         LIMIT 1) AS baseline_cost,
+        (SELECT w.award_price * fx.rate
+         FROM winners w
+         JOIN proposals p ON p.id = w.proposal_id
+         JOIN fx ON fx.currency = 'RUB'
+         WHERE p.lot_id = l.id AND NOT p.is_baseline AND w.award_price IS NOT NULL
+         ORDER BY w.rank ASC NULLS LAST, w.created_at ASC
+         LIMIT 1) AS winning_cost
+    FROM lots l
+    JOIN scoped_tenders st ON st.id = l.tender_id
+)
+SELECT
+    st.id AS tender_id,
+    st.etp_id AS tender_etp_id,
+    st.title AS tender_title,
+    st.category_id,
+    tc.title AS category_title,
+    st.tender_date::timestamptz AS tender_date,
+    COUNT(*) AS lots_count,
+    COUNT(*) FILTER (WHERE lc.baseline_cost IS NOT NULL AND lc.winning_cost IS NOT NULL) AS compared_lots_count,
+    SUM(lc.baseline_cost) FILTER (WHERE lc.winning_cost IS NOT NULL)::numeric AS baseline_cost,
+    SUM(lc.winning_cost) FILTER (WHERE lc.baseline_cost IS NOT NULL)::numeric AS winning_cost,
+    (SUM(lc.baseline_cost - lc.winning_cost))::numeric AS savings_amount,
+    ROUND(SUM(lc.baseline_cost - lc.winning_cost)
+          / NULLIF(SUM(lc.baseline_cost) FILTER (WHERE lc.winning_cost IS NOT NULL), 0) * 100, 2)::numeric AS savings_percent
+FROM scoped_tenders st
+JOIN lot_costs lc ON lc.tender_id = st.id
+LEFT JOIN tender_categories tc ON tc.id = st.category_id
+GROUP BY st.id, st.etp_id, st.title, st.category_id, tc.title, st.tender_date
+HAVING COUNT(*) FILTER (WHERE lc.baseline_cost IS NOT NULL AND lc.winning_cost IS NOT NULL) > 0
+ORDER BY st.tender_date DESC, st.id DESC;
+
+-- name: ListSavingsTotals :many
+-- Итоги экономии по сравнимым лотам тех же тендеров: по категориям
+-- (group_by = 'category', category_id NULL — тендеры без категории),
+-- по месяцам (group_by = 'month', month — 'ГГГГ-ММ' по UTC, в остальных строках пустой)
+-- и общий итог (group_by = 'total'). Фильтры те же, что в ListTenderSavings.
+WITH fx AS (
+    SELECT
+        unnest(sqlc.arg(currencies)::text[]) AS currency,
+        unnest(sqlc.arg(rates)::numeric[]) AS rate
+),
+scoped_tenders AS (
+    SELECT
+        t.id,
+        t.category_id,
+        COALESCE(t.data_prepared_on_date, t.created_at) AS tender_date
+    FROM tenders t
+    WHERE t.deleted_at IS NULL
+      AND (sqlc.narg(date_from)::timestamptz IS NULL OR COALESCE(t.data_prepared_on_date, t.created_at) >= sqlc.narg(date_from)::timestamptz)
+      AND (sqlc.narg(date_to)::timestamptz IS NULL OR COALESCE(t.data_prepared_on_date, t.created_at) < sqlc.narg(date_to)::timestamptz)
+      AND (sqlc.narg(category_id)::bigint IS NULL OR t.category_id = sqlc.narg(category_id)::bigint)
+      AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint)
+),
+lot_costs AS (
+    SELECT
+        l.tender_id,
+        (SELECT psl.total_cost * fx.rate
+         FROM proposals p
+         JOIN proposal_summary_lines psl
+             ON psl.proposal_id = p.id AND psl.summary_key = 'total_cost_with_vat'
+         JOIN fx ON fx.currency = COALESCE(psl.currency, p.currency)
+         WHERE p.lot_id = l.id AND p.is_baseline
+         LIMIT 1) AS baseline_cost,
+        (SELECT w.award_price * fx.rate
+         FROM winners w
+         JOIN proposals p ON p.id = w.proposal_id
+         JOIN fx ON fx.currency = 'RUB'
+         WHERE p.lot_id = l.id AND NOT p.is_baseline AND w.award_price IS NOT NULL
+         ORDER BY w.rank ASC NULLS LAST, w.created_at ASC
+         LIMIT 1) AS winning_cost
+    FROM lots l
+    JOIN scoped_tenders st ON st.id = l.tender_id
+),
+compared AS (
+    SELECT
+        st.id AS tender_id,
+        st.category_id,
+        date_trunc('month', st.tender_date AT TIME ZONE 'UTC') AS month,
+        lc.baseline_cost,
+        lc.winning_cost
+    FROM lot_costs lc
+    JOIN scoped_tenders st ON st.id = lc.tender_id
+    WHERE lc.baseline_cost IS NOT NULL AND lc.winning_cost IS NOT NULL
+)
+SELECT
+    (CASE
+        WHEN GROUPING(c.category_id, tc.title) = 0 THEN 'category'
+        WHEN GROUPING(c.month) = 0 THEN 'month'
+        ELSE 'total'
+    END)::text AS group_by,
+    c.category_id,
+    tc.title AS category_title,
+    COALESCE(to_char(c.month, 'YYYY-MM'), '')::text AS month,
+    COUNT(DISTINCT c.tender_id) AS tenders_count,
+    COUNT(*) AS compared_lots_count,
+    SUM(c.baseline_cost)::numeric AS baseline_cost,
+    SUM(c.winning_cost)::numeric AS winning_cost,
+    SUM(c.baseline_cost - c.winning_cost)::numeric AS savings_amount,
+    ROUND(SUM(c.baseline_cost - c.winning_cost) / NULLIF(SUM(c.baseline_cost), 0) * 100, 2)::numeric AS savings_percent
+FROM compared c
+LEFT JOIN tender_categories tc ON tc.id = c.category_id
+GROUP BY GROUPING SETS ((c.category_id, tc.title), (c.month), ())
+ORDER BY group_by, tc.title NULLS LAST, c.month;
+
+-- name: ListNewTenders :many
+-- Тендеры, загруженные в систему за период [date_from, date_to) (по created_at),
+-- с числом лотов и предложений подрядчиков. Для регулярного отчета «новые тендеры».
+SELECT
+    t.id,
+    t.etp_id,
+    t.title,
+    tc.title AS category_title,
+    t.data_prepared_on_date,
+    t.created_at,
+    (SELECT COUNT(*) FROM lots l WHERE l.tender_id = t.id) AS lots_count,
+    (SELECT COUNT(*)
+     FROM proposals p
+     JOIN lots l ON l.id = p.lot_id
+     WHERE l.tender_id = t.id AND NOT p.is_baseline) AS proposals_count
+FROM tenders t
+LEFT JOIN tender_categories tc ON tc.id = t.category_id
+WHERE t.deleted_at IS NULL
+  AND t.created_at >= sqlc.arg(date_from)::timestamptz
+  AND t.created_at < sqlc.arg(date_to)::timestamptz
+  AND (sqlc.narg(category_id)::bigint IS NULL OR t.category_id = sqlc.narg(category_id)::bigint)
+  AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint)
+ORDER BY t.created_at DESC, t.id DESC;
+
+-- name: ListUnmatchedBacklog :many
+-- Очередь несопоставленных позиций по тендерам: позиции без позиции каталога
+-- или с позицией в статусе pending_indexing (условия GetUnmatchedPositions),
+-- их число и время загрузки самой старой. Тендеры с самой длинной очередью первыми.
+SELECT
+    t.id AS tender_id,
+    t.etp_id AS tender_etp_id,
+    t.title AS tender_title,
+    COUNT(*) AS unmatched_count,
+    MIN(pi.created_at)::timestamptz AS oldest_created_at
+FROM position_items pi
+LEFT JOIN catalog_positions cp ON cp.id = pi.catalog_position_id
+JOIN proposals p ON p.id = pi.proposal_id
+JOIN lots l ON l.id = p.lot_id
+JOIN tenders t ON t.id = l.tender_id
+WHERE (pi.catalog_position_id IS NULL OR cp.status = 'pending_indexing')
+  AND pi.is_chapter = false
+  AND t.deleted_at IS NULL
+  AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint)
+GROUP BY t.id, t.etp_id, t.title
+ORDER BY unmatched_count DESC, t.id
+LIMIT sqlc.arg(row_limit);
@@ -0,0 +1,105 @@
+-- retention.sql
+--
+-- Политики хранения (services/retention): для каждой политики — подсчет
+-- подходящих строк (пробный запуск и отчет) и удаление порции не больше
+-- batch_size строк, чтобы длинный DELETE не держал блокировки. Остаток
+-- удаляется следующими запусками. Граница cutoff считается в сервисе.
+
+-- name: CountExpiredSessions :one
+-- Сессии, истекшие до cutoff (отозванные тоже).
+SELECT COUNT(*) FROM user_sessions
+WHERE expires_at < sqlc.arg(cutoff)::timestamptz;
+
+-- name: DeleteExpiredSessionsBatch :execrows
+DELETE FROM user_sessions
+WHERE id IN (
+    SELECT s.id FROM user_sessions s
+    WHERE s.expires_at < sqlc.arg(cutoff)::timestamptz
+    ORDER BY s.id
+    LIMIT sqlc.arg(batch_size)
+);
+
+-- name: CountRevokedSessions :one
+-- Сессии, отозванные до cutoff (выход, смена пароля), в том числе ещё не истекшие.
+SELECT COUNT(*) FROM user_sessions
+WHERE revoked_at IS NOT NULL AND revoked_at < sqlc.arg(cutoff)::timestamptz;
+
+-- name: DeleteRevokedSessionsBatch :execrows
+DELETE FROM user_sessions
+WHERE id IN (
+    SELECT s.id FROM user_sessions s
+    WHERE s.revoked_at IS NOT NULL AND s.revoked_at < sqlc.arg(cutoff)::timestamptz
+    ORDER BY s.id
+    LIMIT sqlc.arg(batch_size)
+);
+
+-- name: CountExpiredMatchingCache :one
+-- Записи matching_cache, истекшие до cutoff; записи без TTL не удаляются.
+SELECT COUNT(*) FROM matching_cache
+WHERE expires_at IS NOT NULL AND expires_at < sqlc.arg(cutoff)::timestamptz;
+
+-- name: DeleteExpiredMatchingCacheBatch :execrows
+DELETE FROM matching_cache
+WHERE (job_title_hash, norm_version) IN (
+    SELECT mc.job_title_hash, mc.norm_version FROM matching_cache mc
+    WHERE mc.expires_at IS NOT NULL AND mc.expires_at < sqlc.arg(cutoff)::timestamptz
+    ORDER BY mc.expires_at
+    LIMIT sqlc.arg(batch_size)
+);
+
+-- name: CountOldRawImportVersions :one
+-- Версии исходного JSON импортов, загруженные до cutoff. Последняя версия
+-- тендера не удаляется никогда: с ней сравнивается следующий импорт.
+SELECT COUNT(*) FROM tender_raw_data_history h
+WHERE h.imported_at < sqlc.arg(cutoff)::timestamptz
+  AND EXISTS (
+      SELECT 1 FROM tender_raw_data_history newer
+      WHERE newer.tender_id = h.tender_id AND newer.version > h.version
+  );
+
+-- name: DeleteOldRawImportVersionsBatch :execrows
+DELETE FROM tender_raw_data_history
+WHERE (tender_id, version) IN (
+    SELECT h.tender_id, h.version FROM tender_raw_data_history h
+    WHERE h.imported_at < sqlc.arg(cutoff)::timestamptz
+      AND EXISTS (
+          SELECT 1 FROM tender_raw_data_history newer
+          WHERE newer.tender_id = h.tender_id AND newer.version > h.version
+      )
+    ORDER BY h.imported_at
+    LIMIT sqlc.arg(batch_size)
+);
+
+-- name: CreateRetentionRun :one
+INSERT INTO retention_runs (
+    policy, dry_run, status, cutoff, matched_count, deleted_count, error, triggered_by, started_at
+) VALUES (
+    sqlc.arg(policy),
+    sqlc.arg(dry_run),
+    sqlc.arg(status),
+    sqlc.arg(cutoff),
+    sqlc.arg(matched_count),
+    sqlc.arg(deleted_count),
+    sqlc.narg(error),
+    sqlc.narg(triggered_by),
+    sqlc.arg(started_at)
+)
+RETURNING *;
+
+-- name: ListLatestRetentionRuns :many
+-- Последний запуск каждой политики.
+SELECT DISTINCT ON (policy) *
+FROM retention_runs
+ORDER BY policy, started_at DESC, id DESC;
+
+-- name: ListRetentionRuns :many
+-- История запусков, новые первыми; policy = NULL — все политики.
+SELECT * FROM retention_runs
+WHERE sqlc.narg(policy)::text IS NULL OR policy = sqlc.narg(policy)::text
+ORDER BY started_at DESC, id DESC
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: CountRetentionRuns :one
+SELECT COUNT(*) FROM retention_runs
+WHERE sqlc.narg(policy)::text IS NULL OR policy = sqlc.narg(policy)::text;
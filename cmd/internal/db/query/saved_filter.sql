@@ -0,0 +1,24 @@
+-- saved_filter.sql
+-- Сохранённые фильтры списка тендеров (GET/POST/DELETE /api/v1/me/saved-filters).
+
+-- name: CreateSavedFilter :one
+-- Дубликат имени у пользователя (без учёта регистра) даёт unique_violation.
+INSERT INTO saved_filters (user_id, name, filters)
+VALUES (sqlc.arg(user_id), sqlc.arg(name), sqlc.arg(filters))
+RETURNING *;
+
+-- name: ListSavedFilters :many
+-- Фильтры пользователя в порядке создания (порядок вкладок в интерфейсе).
+SELECT * FROM saved_filters
+WHERE user_id = $1
+ORDER BY created_at, id;
+
+-- name: CountSavedFilters :one
+SELECT COUNT(*)::bigint FROM saved_filters
+WHERE user_id = $1;
+
+-- name: DeleteSavedFilter :execrows
+-- Чужой фильтр не удаляется: 0 строк, как у несуществующего.
+DELETE FROM saved_filters
+WHERE id = sqlc.arg(id)
+  AND user_id = sqlc.arg(user_id);
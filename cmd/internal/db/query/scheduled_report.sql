@@ -0,0 +1,123 @@
+-- scheduled_report.sql
+--
+-- Регулярные отчеты (scheduled_reports) и журнал их запусков (report_runs).
+-- Следующий запуск — ближайшая граница периода после текущего момента в UTC:
+-- начало следующего дня (daily), понедельника (weekly) или месяца (monthly).
+
+-- name: ListScheduledReports :many
+SELECT * FROM scheduled_reports
+ORDER BY created_at DESC, id DESC;
+
+-- name: GetScheduledReport :one
+SELECT * FROM scheduled_reports
+WHERE id = $1;
+
+-- name: CreateScheduledReport :one
+INSERT INTO scheduled_reports (
+    name, kind, format, frequency, recipients, organization_id, category_id, is_active, next_run_at, created_by
+) VALUES (
+    sqlc.arg(name),
+    sqlc.arg(kind),
+    sqlc.arg(format),
+    sqlc.arg(frequency)::text,
+    sqlc.arg(recipients)::text[],
+    sqlc.narg(organization_id),
+    sqlc.narg(category_id),
+    sqlc.arg(is_active),
+    (date_trunc(
+        CASE sqlc.arg(frequency)::text WHEN 'daily' THEN 'day' WHEN 'weekly' THEN 'week' ELSE 'month' END,
+        NOW() AT TIME ZONE 'UTC'
+    ) + CASE sqlc.arg(frequency)::text WHEN 'daily' THEN INTERVAL '1 day' WHEN 'weekly' THEN INTERVAL '1 week' ELSE INTERVAL '1 month' END
+    ) AT TIME ZONE 'UTC',
+    sqlc.narg(created_by)
+)
+RETURNING *;
+
+-- name: UpdateScheduledReport :one
+-- Частичное обновление: NULL-аргументы оставляют поле без изменений.
+-- При смене периодичности следующий запуск пересчитывается.
+-- Возвращает sql.ErrNoRows если отчет не найден.
+UPDATE scheduled_reports
+SET name        = COALESCE(sqlc.narg(name), name),
+    format      = COALESCE(sqlc.narg(format), format),
+    frequency   = COALESCE(sqlc.narg(frequency)::text, frequency),
+    recipients  = COALESCE(sqlc.narg(recipients)::text[], recipients),
+    category_id = CASE WHEN sqlc.arg(clear_category)::boolean THEN NULL
+                       ELSE COALESCE(sqlc.narg(category_id), category_id) END,
+    is_active   = COALESCE(sqlc.narg(is_active), is_active),
+    next_run_at = CASE
+        WHEN sqlc.narg(frequency)::text IS NULL OR sqlc.narg(frequency)::text = frequency THEN next_run_at
+        ELSE (date_trunc(
+            CASE sqlc.narg(frequency)::text WHEN 'daily' THEN 'day' WHEN 'weekly' THEN 'week' ELSE 'month' END,
+            NOW() AT TIME ZONE 'UTC'
+        ) + CASE sqlc.narg(frequency)::text WHEN 'daily' THEN INTERVAL '1 day' WHEN 'weekly' THEN INTERVAL '1 week' ELSE INTERVAL '1 month' END
+        ) AT TIME ZONE 'UTC'
+    END,
+    updated_at  = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteScheduledReport :execrows
+-- Удаляет отчет вместе с историей запусков.
+DELETE FROM scheduled_reports
+WHERE id = $1;
+
+-- name: ScheduledReportExists :one
+SELECT EXISTS (SELECT 1 FROM scheduled_reports WHERE id = $1);
+
+-- name: ClaimDueScheduledReports :many
+-- Забирает пачку активных отчетов, срок которых наступил, и сразу переносит
+-- next_run_at на следующую границу периода. scheduled_at — граница, за которую
+-- формируется отчет. Пропущенные периоды (API был остановлен) не догоняются:
+-- отчет формируется один раз, за последний наступивший период. SKIP LOCKED
+-- позволяет нескольким инстансам API разбирать отчеты без двойной отправки.
+WITH due AS (
+    SELECT id, next_run_at
+    FROM scheduled_reports
+    WHERE is_active
+      AND next_run_at <= NOW()
+    ORDER BY next_run_at
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+)
+UPDATE scheduled_reports sr
+SET next_run_at = (date_trunc(
+        CASE sr.frequency WHEN 'daily' THEN 'day' WHEN 'weekly' THEN 'week' ELSE 'month' END,
+        NOW() AT TIME ZONE 'UTC'
+    ) + CASE sr.frequency WHEN 'daily' THEN INTERVAL '1 day' WHEN 'weekly' THEN INTERVAL '1 week' ELSE INTERVAL '1 month' END
+    ) AT TIME ZONE 'UTC',
+    last_run_at = NOW()
+FROM due
+WHERE sr.id = due.id
+RETURNING sr.id, sr.name, sr.kind, sr.format, sr.frequency, sr.recipients,
+          sr.organization_id, sr.category_id,
+          due.next_run_at::timestamptz AS scheduled_at;
+
+-- name: CreateReportRun :one
+INSERT INTO report_runs (scheduled_report_id, period_from, period_to, recipients)
+VALUES (
+    sqlc.arg(scheduled_report_id),
+    sqlc.arg(period_from),
+    sqlc.arg(period_to),
+    sqlc.arg(recipients)::text[]
+)
+RETURNING *;
+
+-- name: FinishReportRun :exec
+-- Фиксирует итог запуска: status = 'sent' или 'failed' (error — причина).
+UPDATE report_runs
+SET status      = sqlc.arg(status),
+    file_name   = sqlc.narg(file_name),
+    file_size   = sqlc.narg(file_size),
+    rows_count  = sqlc.narg(rows_count),
+    error       = sqlc.narg(error),
+    finished_at = NOW()
+WHERE id = sqlc.arg(id);
+
+-- name: ListReportRuns :many
+-- История запусков отчета, новые первыми.
+SELECT * FROM report_runs
+WHERE scheduled_report_id = sqlc.arg(scheduled_report_id)
+ORDER BY started_at DESC, id DESC
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
@@ -0,0 +1,71 @@
+-- search.sql
+-- Запросы глобального поиска (GET /api/v1/search): одна строка поиска —
+-- несколько групп результатов. pattern — экранированный шаблон подстроки
+-- ILIKE ('%...%'), prefix — экранированный шаблон начала строки ('...%').
+-- total — число всех совпадений группы (COUNT(*) OVER () до LIMIT), строки
+-- с совпадением с начала названия идут первыми.
+
+-- name: GlobalSearchTenders :many
+-- Тендеры по названию или началу etp_id. Мягко удалённые не входят;
+-- organization_id — область видимости пользователя (NULL — все организации).
+SELECT
+    t.id,
+    t.etp_id,
+    t.title,
+    o.title AS object_title,
+    COALESCE(t.data_prepared_on_date, t.created_at)::timestamptz AS tender_date,
+    COUNT(*) OVER ()::bigint AS total
+FROM tenders t
+JOIN objects o ON o.id = t.object_id
+WHERE (t.title ILIKE sqlc.arg(pattern)::text OR t.etp_id ILIKE sqlc.arg(prefix)::text)
+  AND t.deleted_at IS NULL
+  AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint)
+ORDER BY (t.title ILIKE sqlc.arg(prefix)::text OR t.etp_id ILIKE sqlc.arg(prefix)::text) DESC,
+         tender_date DESC, t.id DESC
+LIMIT sqlc.arg(page_limit)::int;
+
+-- name: GlobalSearchContractors :many
+-- Подрядчики по наименованию или началу ИНН. Справочник общий для всех организаций.
+SELECT
+    c.id,
+    c.title,
+    c.inn,
+    COUNT(*) OVER ()::bigint AS total
+FROM contractors c
+WHERE c.title ILIKE sqlc.arg(pattern)::text OR c.inn LIKE sqlc.arg(prefix)::text
+ORDER BY (c.title ILIKE sqlc.arg(prefix)::text OR c.inn LIKE sqlc.arg(prefix)::text) DESC,
+         c.title, c.id
+LIMIT sqlc.arg(page_limit)::int;
+
+-- name: GlobalSearchObjects :many
+-- Объекты по названию или адресу. Только объекты, у которых есть неудалённые
+-- тендеры в области видимости пользователя: названия объектов других
+-- организаций не раскрываются.
+SELECT
+    o.id,
+    o.title,
+    o.address,
+    COUNT(*) OVER ()::bigint AS total
+FROM objects o
+WHERE (o.title ILIKE sqlc.arg(pattern)::text OR o.address ILIKE sqlc.arg(pattern)::text)
+  AND EXISTS (
+      SELECT 1 FROM tenders t
+      WHERE t.object_id = o.id
+        AND t.deleted_at IS NULL
+        AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint)
+  )
+ORDER BY (o.title ILIKE sqlc.arg(prefix)::text) DESC, o.title, o.id
+LIMIT sqlc.arg(page_limit)::int;
+
+-- name: GlobalSearchCatalogPositions :many
+-- Позиции каталога (kind = POSITION) по эталонному названию.
+SELECT
+    cp.id,
+    cp.standard_job_title,
+    cp.description,
+    COUNT(*) OVER ()::bigint AS total
+FROM catalog_positions cp
+WHERE cp.kind = 'POSITION'
+  AND cp.standard_job_title ILIKE sqlc.arg(pattern)::text
+ORDER BY (cp.standard_job_title ILIKE sqlc.arg(prefix)::text) DESC, cp.standard_job_title, cp.id
+LIMIT sqlc.arg(page_limit)::int;
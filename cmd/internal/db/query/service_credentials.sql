@@ -0,0 +1,52 @@
+-- name: ListActiveServiceCredentials :many
+-- Возвращает все активные (не отозванные) ключи сервисов.
+-- Используется in-memory кэшем ServiceBearerAuthMiddleware при каждом обновлении.
+SELECT id, service_name, key_hash, scopes
+FROM service_credentials
+WHERE revoked_at IS NULL;
+
+-- name: ListServiceCredentials :many
+-- Список всех ключей для админки (без хешей).
+SELECT id, service_name, key_prefix, description, created_by, created_at, revoked_at,
+       scopes, last_used_at, rotated_from_id
+FROM service_credentials
+ORDER BY created_at DESC;
+
+-- name: CreateServiceCredential :one
+-- Регистрирует новый ключ сервиса. Сам ключ в БД не хранится, только его хеш.
+INSERT INTO service_credentials (service_name, key_hash, key_prefix, description, created_by, scopes, rotated_from_id)
+VALUES (
+    sqlc.arg(service_name),
+    sqlc.arg(key_hash),
+    sqlc.arg(key_prefix),
+    sqlc.narg(description),
+    sqlc.arg(created_by),
+    sqlc.arg(scopes)::text[],
+    sqlc.narg(rotated_from_id)
+)
+RETURNING id, service_name, key_prefix, description, created_by, created_at, revoked_at,
+          scopes, last_used_at, rotated_from_id;
+
+-- name: GetActiveServiceCredentialForUpdate :one
+-- Блокирует активный ключ на время ротации, чтобы параллельная ротация
+-- или отзыв не выпустили два преемника.
+SELECT id, service_name, description, scopes
+FROM service_credentials
+WHERE id = $1
+  AND revoked_at IS NULL
+FOR UPDATE;
+
+-- name: RevokeServiceCredential :one
+-- Отзывает ключ. Возвращает sql.ErrNoRows если ключ не найден или уже отозван.
+UPDATE service_credentials
+SET revoked_at = NOW()
+WHERE id = $1
+  AND revoked_at IS NULL
+RETURNING id, service_name, key_prefix, description, created_by, created_at, revoked_at,
+          scopes, last_used_at, rotated_from_id;
+
+-- name: TouchServiceCredentials :exec
+-- Отмечает использование ключей. Вызывается пачкой из in-memory кэша.
+UPDATE service_credentials
+SET last_used_at = sqlc.arg(used_at)::timestamptz
+WHERE id = ANY(sqlc.arg(ids)::bigint[]);
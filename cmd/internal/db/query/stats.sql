@@ -1,4 +1,4 @@
 -- file: cmd/internal/db/query/stats.sql
 
 -- name: GetTendersCount :one
-SELECT count(*) FROM tenders;
\ No newline at end of file
+SELECT count(*) FROM tenders WHERE deleted_at IS NULL;
\ No newline at end of file
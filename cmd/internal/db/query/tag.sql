@@ -0,0 +1,48 @@
+-- tag.sql
+-- Метки тендеров (tags) и их привязка к тендерам (tender_tags).
+
+-- name: CreateTag :one
+-- Уникальность названия в организации (без учёта регистра) — индекс
+-- uq_tags_organization_name; дубликат даёт unique_violation.
+INSERT INTO tags (organization_id, name, created_by)
+VALUES (sqlc.arg(organization_id), sqlc.arg(name), sqlc.narg(created_by))
+RETURNING *;
+
+-- name: GetTag :one
+SELECT * FROM tags
+WHERE id = $1;
+
+-- name: ListTags :many
+-- Метки организации по алфавиту с числом неудалённых тендеров.
+-- organization_id — организация пользователя; NULL только для admin (все метки).
+SELECT
+    tg.id,
+    tg.organization_id,
+    tg.name,
+    tg.created_at,
+    COUNT(t.id)::bigint AS tenders_count
+FROM tags tg
+LEFT JOIN tender_tags tt ON tt.tag_id = tg.id
+LEFT JOIN tenders t ON t.id = tt.tender_id AND t.deleted_at IS NULL
+WHERE sqlc.narg(organization_id)::bigint IS NULL OR tg.organization_id = sqlc.narg(organization_id)::bigint
+GROUP BY tg.id
+ORDER BY lower(tg.name), tg.id;
+
+-- name: AttachTenderTag :exec
+-- Повторная привязка ничего не меняет.
+INSERT INTO tender_tags (tender_id, tag_id, created_by)
+VALUES (sqlc.arg(tender_id), sqlc.arg(tag_id), sqlc.narg(created_by))
+ON CONFLICT (tender_id, tag_id) DO NOTHING;
+
+-- name: DetachTenderTag :execrows
+DELETE FROM tender_tags
+WHERE tender_id = sqlc.arg(tender_id)
+  AND tag_id = sqlc.arg(tag_id);
+
+-- name: ListTenderTags :many
+-- Метки тендера по алфавиту.
+SELECT tg.*
+FROM tender_tags tt
+JOIN tags tg ON tg.id = tt.tag_id
+WHERE tt.tender_id = $1
+ORDER BY lower(tg.name), tg.id;
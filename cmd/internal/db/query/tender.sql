@@ -7,15 +7,22 @@
 -- внешним ID (`etp_id`) уже существует.
 -- Этот подход "создай или обнови" (Upsert) является атомарным и основным для импорта данных.
 -- Возвращает полную запись созданного или обновленного тендера.
+-- organization_id задается только при создании (NULL — организация по умолчанию).
+-- Тендер другой организации не обновляется: запрос возвращает sql.ErrNoRows.
+-- etp_status NULL (парсер статус не прислал) не стирает сохранённый.
 INSERT INTO tenders (
     etp_id,
     title,
     object_id,
     executor_id,
     data_prepared_on_date,
-    category_id
+    category_id,
+    organization_id,
+    etp_status
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
+    $1, $2, $3, $4, $5, $6,
+    COALESCE(sqlc.narg(organization_id)::bigint, (SELECT id FROM organizations WHERE is_default)),
+    sqlc.narg(etp_status)::text
 )
 ON CONFLICT (etp_id) DO UPDATE SET
     title = EXCLUDED.title,
@@ -23,9 +30,19 @@ ON CONFLICT (etp_id) DO UPDATE SET
     executor_id = EXCLUDED.executor_id,
     data_prepared_on_date = EXCLUDED.data_prepared_on_date,
     category_id = EXCLUDED.category_id,
+    etp_status = COALESCE(EXCLUDED.etp_status, tenders.etp_status),
     updated_at = NOW()
+WHERE tenders.organization_id = EXCLUDED.organization_id
 RETURNING *;
 
+-- name: LockTenderForImport :exec
+-- Блокирует строку тендера до конца транзакции. Нужен завершающей транзакции
+-- параллельного импорта лотов: там нет UpsertTender, а версию исходного JSON
+-- нужно назначать под блокировкой тендера (см. CreateTenderRawDataVersion).
+SELECT id FROM tenders
+WHERE id = $1
+FOR UPDATE;
+
 -- name: GetTenderByID :one
 -- Получает один тендер по его уникальному внутреннему идентификатору (primary key).
 -- Запрос очень быстрый благодаря индексу ПК.
@@ -39,16 +56,27 @@ SELECT * FROM tenders
 WHERE etp_id = $1;
 
 -- name: ListTenders :many
--- Получает обогащенный пагинированный список всех тендеров.
+-- Получает обогащенный пагинированный список тендеров с фильтрами и сортировкой.
 -- Запрос через JOIN подтягивает адрес объекта и имя исполнителя.
--- Вложенный подзапрос подсчитывает общее количество предложений для каждого тендера.
--- ############### РЕКОМЕНДАЦИЯ ПО ОПТИМИЗАЦИИ (НА БУДУЩЕЕ) ###############
--- Сортировка `ORDER BY t.data_prepared_on_date` может быть медленной
--- на очень большом количестве тендеров, так как это поле не проиндексировано.
--- Решение: Если производительность этого списка станет важна, в будущей
--- миграции можно добавить следующий индекс:
--- CREATE INDEX idx_tenders_data_prepared_on_date ON tenders (data_prepared_on_date DESC);
--- #####################################################################
+-- LATERAL-подзапросы считают количество предложений (без baseline) и
+-- суммарную стоимость победителей (total_cost_with_vat) по всем лотам тендера.
+--
+-- Фильтры (все опциональны, NULL = фильтр не применяется):
+--   category_id, chapter_id, type_id — иерархия справочников (категория → раздел → тип)
+--   executor_id, object_id          — исполнитель и объект
+--   date_from / date_to             — полуинтервал [date_from, date_to) по data_prepared_on_date
+--   has_winner                      — есть ли хотя бы один победитель в любом лоте
+--   include_deleted                 — показывать удалённые (soft delete) тендеры; только для админов
+--   organization_id                 — организация пользователя; NULL только для admin
+--   tag_ids                         — тендер должен иметь все перечисленные метки;
+--                                     пустой массив (или NULL) — без фильтра
+--   after_created_at / after_id     — keyset-курсор: тендеры строго после (created_at, id)
+--                                     последней строки предыдущей страницы
+--
+-- Сортировка: sort_by ∈ {date, title, proposals_count, total_cost}, sort_desc — направление.
+-- sort_by = 'created' — порядок keyset-пагинации (created_at DESC, id DESC), только с курсором.
+-- Значение sort_by валидируется в Go-хендлере; неизвестное значение даёт сортировку по id.
+-- Индексы для фильтров и сортировки добавлены в миграциях 000010 и 000047.
 SELECT
     t.id,
     t.etp_id,
@@ -57,26 +85,121 @@ SELECT
     t.category_id,
     o.address as object_address,
     e.name as executor_name,
-    (
-        SELECT COUNT(*)
-        FROM proposals pr
-        JOIN lots l_sub ON pr.lot_id = l_sub.id
-        WHERE l_sub.tender_id = t.id
-          AND pr.is_baseline = false
-    ) as proposals_count
+    pc.proposals_count::bigint as proposals_count,
+    wc.total_cost as total_cost,
+    (wc.winners_count > 0)::boolean as has_winner,
+    t.deleted_at,
+    t.created_at
 FROM
     tenders t
 JOIN
     objects o ON t.object_id = o.id
 JOIN
     executors e ON t.executor_id = e.id
+LEFT JOIN
+    tender_categories tc ON t.category_id = tc.id
+LEFT JOIN
+    tender_chapters tch ON tc.tender_chapter_id = tch.id
+CROSS JOIN LATERAL (
+    SELECT COUNT(*) AS proposals_count
+    FROM proposals pr
+    JOIN lots l_sub ON pr.lot_id = l_sub.id
+    WHERE l_sub.tender_id = t.id
+      AND pr.is_baseline = false
+) pc
+CROSS JOIN LATERAL (
+    SELECT
+        SUM(psl.total_cost)::numeric AS total_cost,
+        COUNT(w.id) AS winners_count
+    FROM winners w
+    JOIN proposals pr ON w.proposal_id = pr.id
+    JOIN lots l_sub ON pr.lot_id = l_sub.id
+    LEFT JOIN proposal_summary_lines psl
+        ON psl.proposal_id = pr.id AND psl.summary_key = 'total_cost_with_vat'
+    WHERE l_sub.tender_id = t.id
+) wc
+WHERE
+    (sqlc.narg(category_id)::bigint IS NULL OR t.category_id = sqlc.narg(category_id)::bigint)
+    AND (sqlc.narg(chapter_id)::bigint IS NULL OR tc.tender_chapter_id = sqlc.narg(chapter_id)::bigint)
+    AND (sqlc.narg(type_id)::bigint IS NULL OR tch.tender_type_id = sqlc.narg(type_id)::bigint)
+    AND (sqlc.narg(executor_id)::bigint IS NULL OR t.executor_id = sqlc.narg(executor_id)::bigint)
+    AND (sqlc.narg(object_id)::bigint IS NULL OR t.object_id = sqlc.narg(object_id)::bigint)
+    AND (sqlc.narg(date_from)::timestamptz IS NULL OR t.data_prepared_on_date >= sqlc.narg(date_from)::timestamptz)
+    AND (sqlc.narg(date_to)::timestamptz IS NULL OR t.data_prepared_on_date < sqlc.narg(date_to)::timestamptz)
+    AND (sqlc.narg(has_winner)::boolean IS NULL OR (wc.winners_count > 0) = sqlc.narg(has_winner)::boolean)
+    AND (sqlc.arg(include_deleted)::boolean OR t.deleted_at IS NULL)
+    AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint)
+    AND (
+        COALESCE(cardinality(sqlc.arg(tag_ids)::bigint[]), 0) = 0
+        OR (
+            SELECT COUNT(*) FROM tender_tags tt
+            WHERE tt.tender_id = t.id AND tt.tag_id = ANY(sqlc.arg(tag_ids)::bigint[])
+        ) = cardinality(sqlc.arg(tag_ids)::bigint[])
+    )
+    AND (sqlc.narg(after_id)::bigint IS NULL OR (t.created_at, t.id) < (sqlc.narg(after_created_at)::timestamptz, sqlc.narg(after_id)::bigint))
 ORDER BY
-    t.data_prepared_on_date DESC
-LIMIT $1
-OFFSET $2;
+    CASE WHEN sqlc.arg(sort_by)::text = 'created' THEN t.created_at END DESC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'date' AND NOT sqlc.arg(sort_desc)::boolean THEN t.data_prepared_on_date END ASC NULLS LAST,
+    CASE WHEN sqlc.arg(sort_by)::text = 'date' AND sqlc.arg(sort_desc)::boolean THEN t.data_prepared_on_date END DESC NULLS LAST,
+    CASE WHEN sqlc.arg(sort_by)::text = 'title' AND NOT sqlc.arg(sort_desc)::boolean THEN t.title END ASC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'title' AND sqlc.arg(sort_desc)::boolean THEN t.title END DESC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'proposals_count' AND NOT sqlc.arg(sort_desc)::boolean THEN pc.proposals_count END ASC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'proposals_count' AND sqlc.arg(sort_desc)::boolean THEN pc.proposals_count END DESC,
+    CASE WHEN sqlc.arg(sort_by)::text = 'total_cost' AND NOT sqlc.arg(sort_desc)::boolean THEN wc.total_cost END ASC NULLS LAST,
+    CASE WHEN sqlc.arg(sort_by)::text = 'total_cost' AND sqlc.arg(sort_desc)::boolean THEN wc.total_cost END DESC NULLS LAST,
+    -- Стабильный порядок для пагинации при равных значениях
+    t.id DESC
+LIMIT sqlc.arg(page_limit)::int
+OFFSET sqlc.arg(page_offset)::int;
+
+-- name: CountTenders :one
+-- Считает общее количество тендеров с теми же фильтрами, что и ListTenders (для пагинации).
+-- При изменении фильтров в ListTenders нужно синхронно обновить и этот запрос.
+SELECT COUNT(*)::bigint
+FROM
+    tenders t
+LEFT JOIN
+    tender_categories tc ON t.category_id = tc.id
+LEFT JOIN
+    tender_chapters tch ON tc.tender_chapter_id = tch.id
+WHERE
+    (sqlc.narg(category_id)::bigint IS NULL OR t.category_id = sqlc.narg(category_id)::bigint)
+    AND (sqlc.narg(chapter_id)::bigint IS NULL OR tc.tender_chapter_id = sqlc.narg(chapter_id)::bigint)
+    AND (sqlc.narg(type_id)::bigint IS NULL OR tch.tender_type_id = sqlc.narg(type_id)::bigint)
+    AND (sqlc.narg(executor_id)::bigint IS NULL OR t.executor_id = sqlc.narg(executor_id)::bigint)
+    AND (sqlc.narg(object_id)::bigint IS NULL OR t.object_id = sqlc.narg(object_id)::bigint)
+    AND (sqlc.narg(date_from)::timestamptz IS NULL OR t.data_prepared_on_date >= sqlc.narg(date_from)::timestamptz)
+    AND (sqlc.narg(date_to)::timestamptz IS NULL OR t.data_prepared_on_date < sqlc.narg(date_to)::timestamptz)
+    AND (
+        sqlc.narg(has_winner)::boolean IS NULL
+        OR EXISTS (
+            SELECT 1
+            FROM winners w
+            JOIN proposals pr ON w.proposal_id = pr.id
+            JOIN lots l_sub ON pr.lot_id = l_sub.id
+            WHERE l_sub.tender_id = t.id
+        ) = sqlc.narg(has_winner)::boolean
+    )
+    AND (sqlc.arg(include_deleted)::boolean OR t.deleted_at IS NULL)
+    AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint)
+    AND (
+        COALESCE(cardinality(sqlc.arg(tag_ids)::bigint[]), 0) = 0
+        OR (
+            SELECT COUNT(*) FROM tender_tags tt
+            WHERE tt.tender_id = t.id AND tt.tag_id = ANY(sqlc.arg(tag_ids)::bigint[])
+        ) = cardinality(sqlc.arg(tag_ids)::bigint[])
+    );
+
+-- name: GetTenderForUpdate :one
+-- Неудалённый тендер с блокировкой строки: значения до правки для истории
+-- (field_changes) в транзакции PATCH /api/v1/tenders/:id.
+SELECT * FROM tenders
+WHERE id = $1
+  AND deleted_at IS NULL
+FOR UPDATE;
 
 -- name: UpdateTenderDetails :one
--- Обновляет детали существующего тендера по его внутреннему ID.
+-- Обновляет детали существующего (не удалённого) тендера по его внутреннему ID.
 -- Запрос использует паттерн COALESCE, что позволяет обновлять только те поля,
 -- для которых были переданы не-NULL значения, делая API гибким.
 UPDATE tenders
@@ -87,13 +210,16 @@ SET
     executor_id = COALESCE(sqlc.narg(executor_id), executor_id),
     data_prepared_on_date = COALESCE(sqlc.narg(data_prepared_on_date), data_prepared_on_date),
     category_id = COALESCE(sqlc.narg(category_id), category_id),
+    region = COALESCE(sqlc.narg(region), region),
     updated_at = NOW()
 WHERE
     id = sqlc.arg(id)
+    AND deleted_at IS NULL -- удалённый тендер сначала нужно восстановить
 RETURNING *;
 
 -- name: DeleteTender :exec
--- Удаляет тендер по его внутреннему ID.
+-- Физически удаляет тендер по его внутреннему ID.
+-- API использует SoftDeleteTender; этот запрос оставлен для служебных операций.
 -- ############### ЗАМЕЧАНИЕ ПО ЛОГИКЕ (ВАЖНО!) ###############
 -- ДАННАЯ ОПЕРАЦИЯ ЗАВЕРШИТСЯ С ОШИБКОЙ, если у этого тендера существует
 -- хотя бы одна связанная запись в таблице `lots`.
@@ -120,7 +246,9 @@ SELECT
     exc.name as executor_name,
     cat.title as category_title,
     chap.title as chapter_title,
-    typ.title as type_title
+    typ.title as type_title,
+    t.deleted_at,
+    t.deleted_by
 FROM
     tenders t
 LEFT JOIN
@@ -134,4 +262,30 @@ LEFT JOIN
 LEFT JOIN
     tender_types typ ON chap.tender_type_id = typ.id
 WHERE
-    t.id = $1;
\ No newline at end of file
+    t.id = sqlc.arg(id)
+    AND (sqlc.arg(include_deleted)::boolean OR t.deleted_at IS NULL);
+
+-- name: SoftDeleteTender :one
+-- Помечает тендер удалённым. Лоты, предложения и позиции не изменяются.
+-- Возвращает sql.ErrNoRows, если тендер не найден или уже удалён.
+UPDATE tenders
+SET
+    deleted_at = NOW(),
+    deleted_by = sqlc.narg(deleted_by)::text,
+    updated_at = NOW()
+WHERE
+    id = sqlc.arg(id)
+    AND deleted_at IS NULL
+RETURNING id, deleted_at, deleted_by;
+
+-- name: RestoreTender :one
+-- Снимает пометку удаления. Возвращает sql.ErrNoRows, если тендер не найден или не удалён.
+UPDATE tenders
+SET
+    deleted_at = NULL,
+    deleted_by = NULL,
+    updated_at = NOW()
+WHERE
+    id = sqlc.arg(id)
+    AND deleted_at IS NOT NULL
+RETURNING id, deleted_at, deleted_by;
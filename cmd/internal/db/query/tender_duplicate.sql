@@ -0,0 +1,129 @@
+-- tender_duplicate.sql
+-- Вероятные дубликаты тендеров (миграция 000045): отпечатки тендера для
+-- сравнения после импорта и очередь пар на проверку администратором.
+
+-- name: GetTenderDuplicateFingerprint :one
+-- Наименование, объект и организация импортированного тендера.
+SELECT t.id, t.etp_id, t.title, t.object_id, t.organization_id
+FROM tenders t
+WHERE t.id = $1 AND t.deleted_at IS NULL;
+
+-- name: ListTenderDuplicateCandidates :many
+-- Тендеры той же организации на том же объекте или с похожим наименованием
+-- (триграммы pg_trgm, миграция 000030), самые похожие первыми. Пары, по
+-- которым администратор уже принял решение, не предлагаются.
+SELECT
+    t.id,
+    t.etp_id,
+    t.title,
+    t.object_id,
+    similarity(lower(t.title), lower(sqlc.arg(title)::text))::real AS title_similarity
+FROM tenders t
+WHERE t.organization_id = sqlc.arg(organization_id)
+  AND t.id <> sqlc.arg(tender_id)
+  AND t.deleted_at IS NULL
+  AND (
+      t.object_id = sqlc.arg(object_id)
+      OR similarity(lower(t.title), lower(sqlc.arg(title)::text)) >= sqlc.arg(min_title_similarity)::real
+  )
+  AND NOT EXISTS (
+      SELECT 1 FROM tender_duplicates d
+      WHERE d.tender_id = GREATEST(t.id, sqlc.arg(tender_id))
+        AND d.duplicate_of_id = LEAST(t.id, sqlc.arg(tender_id))
+        AND d.status <> 'pending'
+  )
+ORDER BY title_similarity DESC, t.id DESC
+LIMIT sqlc.arg(max_candidates)::int;
+
+-- name: ListTenderPositionTitles :many
+-- Различные наименования позиций тендера (без заголовков разделов) — отпечаток
+-- состава работ. Нормализация (ё, пунктуация, пробелы) — в сервисе.
+SELECT DISTINCT lower(btrim(pi.job_title_in_proposal))::text AS title
+FROM position_items pi
+JOIN proposals p ON p.id = pi.proposal_id
+JOIN lots l ON l.id = p.lot_id
+WHERE l.tender_id = sqlc.arg(tender_id)
+  AND NOT pi.is_chapter
+LIMIT sqlc.arg(max_titles)::int;
+
+-- name: UpsertTenderDuplicate :execrows
+-- Пара хранится один раз: tender_id — более поздний тендер. Повторный импорт
+-- обновляет оценку только ожидающей проверки пары.
+INSERT INTO tender_duplicates (
+    tender_id, duplicate_of_id, score, title_similarity, same_object, positions_similarity
+) VALUES (
+    sqlc.arg(tender_id),
+    sqlc.arg(duplicate_of_id),
+    sqlc.arg(score),
+    sqlc.arg(title_similarity),
+    sqlc.arg(same_object),
+    sqlc.arg(positions_similarity)
+)
+ON CONFLICT (tender_id, duplicate_of_id) DO UPDATE SET
+    score                = EXCLUDED.score,
+    title_similarity     = EXCLUDED.title_similarity,
+    same_object          = EXCLUDED.same_object,
+    positions_similarity = EXCLUDED.positions_similarity,
+    updated_at           = NOW()
+WHERE tender_duplicates.status = 'pending';
+
+-- name: ListTenderDuplicates :many
+-- Пары с данными обоих тендеров; status = NULL — все. Ожидающие проверки —
+-- самые похожие первыми, решённые — последние решения первыми.
+SELECT
+    d.id,
+    d.score,
+    d.title_similarity,
+    d.same_object,
+    d.positions_similarity,
+    d.status,
+    d.resolved_by,
+    d.resolved_at,
+    d.created_at,
+    t.id AS tender_id,
+    t.etp_id AS tender_etp_id,
+    t.title AS tender_title,
+    t.created_at AS tender_created_at,
+    o.id AS duplicate_of_id,
+    o.etp_id AS duplicate_of_etp_id,
+    o.title AS duplicate_of_title,
+    o.created_at AS duplicate_of_created_at
+FROM tender_duplicates d
+JOIN tenders t ON t.id = d.tender_id
+JOIN tenders o ON o.id = d.duplicate_of_id
+WHERE (sqlc.narg(status)::text IS NULL OR d.status = sqlc.narg(status)::text)
+ORDER BY (d.status = 'pending') DESC, d.resolved_at DESC NULLS FIRST, d.score DESC, d.id
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: CountTenderDuplicates :one
+SELECT COUNT(*) FROM tender_duplicates
+WHERE sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text;
+
+-- name: GetTenderDuplicate :one
+SELECT * FROM tender_duplicates WHERE id = $1;
+
+-- name: ResolveTenderDuplicate :one
+-- Решение по паре: linked или ignored. Решённая пара не меняется (нет строки).
+UPDATE tender_duplicates
+SET status      = sqlc.arg(status),
+    resolved_by = sqlc.narg(resolved_by),
+    resolved_at = NOW(),
+    updated_at  = NOW()
+WHERE id = sqlc.arg(id) AND status = 'pending'
+RETURNING *;
+
+-- name: ListLinkedTenders :many
+-- Тендеры, связанные с данным как один тендер под другим etp_id.
+SELECT
+    d.id AS duplicate_id,
+    t.id,
+    t.etp_id,
+    t.title,
+    d.resolved_at
+FROM tender_duplicates d
+JOIN tenders t ON t.id = CASE WHEN d.tender_id = sqlc.arg(tender_id) THEN d.duplicate_of_id ELSE d.tender_id END
+WHERE d.status = 'linked'
+  AND (d.tender_id = sqlc.arg(tender_id) OR d.duplicate_of_id = sqlc.arg(tender_id))
+  AND t.deleted_at IS NULL
+ORDER BY t.id;
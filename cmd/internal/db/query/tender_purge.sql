@@ -0,0 +1,128 @@
+-- tender_purge.sql
+-- Физическое удаление тендера со всеми зависимыми строками (POST /api/v1/admin/tenders/:id/purge).
+-- Удаление идёт от листьев к корню в одной транзакции (services/tender): у lots.tender_id
+-- нет ON DELETE CASCADE, а явные DELETE по каждой таблице дают число удалённых строк.
+-- Общие справочники (подрядчики, позиции каталога, единицы) не удаляются;
+-- parse_tasks остаются в истории с tender_id = NULL (ON DELETE SET NULL).
+
+-- name: CountTenderPurgeRows :one
+-- Число строк, которые удалит purge, по таблицам (режим dry_run).
+SELECT
+    (SELECT COUNT(*) FROM position_items pi
+        JOIN proposals p ON p.id = pi.proposal_id
+        JOIN lots l ON l.id = p.lot_id
+        WHERE l.tender_id = sqlc.arg(tender_id))::bigint AS position_items,
+    (SELECT COUNT(*) FROM proposal_summary_lines psl
+        JOIN proposals p ON p.id = psl.proposal_id
+        JOIN lots l ON l.id = p.lot_id
+        WHERE l.tender_id = sqlc.arg(tender_id))::bigint AS proposal_summary_lines,
+    (SELECT COUNT(*) FROM proposal_additional_info pai
+        JOIN proposals p ON p.id = pai.proposal_id
+        JOIN lots l ON l.id = p.lot_id
+        WHERE l.tender_id = sqlc.arg(tender_id))::bigint AS proposal_additional_info,
+    (SELECT COUNT(*) FROM winners w
+        JOIN proposals p ON p.id = w.proposal_id
+        JOIN lots l ON l.id = p.lot_id
+        WHERE l.tender_id = sqlc.arg(tender_id))::bigint AS winners,
+    (SELECT COUNT(*) FROM proposals p
+        JOIN lots l ON l.id = p.lot_id
+        WHERE l.tender_id = sqlc.arg(tender_id))::bigint AS proposals,
+    (SELECT COUNT(*) FROM ai_analysis_results ar
+        JOIN lots l ON l.id = ar.lot_id
+        WHERE l.tender_id = sqlc.arg(tender_id))::bigint AS ai_analysis_results,
+    (SELECT COUNT(*) FROM lots_chunks lc
+        JOIN lots_md_documents d ON d.id = lc.lot_document_id
+        JOIN lots l ON l.id = d.lot_id
+        WHERE l.tender_id = sqlc.arg(tender_id))::bigint AS lots_chunks,
+    (SELECT COUNT(*) FROM lots_md_documents d
+        JOIN lots l ON l.id = d.lot_id
+        WHERE l.tender_id = sqlc.arg(tender_id))::bigint AS lots_md_documents,
+    (SELECT COUNT(*) FROM notifications n
+        WHERE n.tender_id = sqlc.arg(tender_id)
+           OR n.lot_id IN (SELECT l.id FROM lots l WHERE l.tender_id = sqlc.arg(tender_id)))::bigint AS notifications,
+    (SELECT COUNT(*) FROM lots l WHERE l.tender_id = sqlc.arg(tender_id))::bigint AS lots,
+    (SELECT COUNT(*) FROM tender_raw_data_history h WHERE h.tender_id = sqlc.arg(tender_id))::bigint AS tender_raw_data_history,
+    (SELECT COUNT(*) FROM tender_raw_data r WHERE r.tender_id = sqlc.arg(tender_id))::bigint AS tender_raw_data,
+    (SELECT COUNT(*) FROM import_metrics m WHERE m.tender_id = sqlc.arg(tender_id))::bigint AS import_metrics,
+    (SELECT COUNT(*) FROM user_tender_subscriptions s WHERE s.tender_id = sqlc.arg(tender_id))::bigint AS user_tender_subscriptions,
+    (SELECT COUNT(*) FROM field_changes fc
+        WHERE (fc.entity_type = 'tender' AND fc.entity_id = sqlc.arg(tender_id))
+           OR (fc.entity_type = 'lot' AND fc.entity_id IN (SELECT l.id FROM lots l WHERE l.tender_id = sqlc.arg(tender_id))))::bigint AS field_changes;
+
+-- name: PurgeTenderPositionItems :execrows
+DELETE FROM position_items
+WHERE proposal_id IN (
+    SELECT p.id FROM proposals p JOIN lots l ON l.id = p.lot_id WHERE l.tender_id = $1
+);
+
+-- name: PurgeTenderSummaryLines :execrows
+DELETE FROM proposal_summary_lines
+WHERE proposal_id IN (
+    SELECT p.id FROM proposals p JOIN lots l ON l.id = p.lot_id WHERE l.tender_id = $1
+);
+
+-- name: PurgeTenderAdditionalInfo :execrows
+DELETE FROM proposal_additional_info
+WHERE proposal_id IN (
+    SELECT p.id FROM proposals p JOIN lots l ON l.id = p.lot_id WHERE l.tender_id = $1
+);
+
+-- name: PurgeTenderWinners :execrows
+DELETE FROM winners
+WHERE proposal_id IN (
+    SELECT p.id FROM proposals p JOIN lots l ON l.id = p.lot_id WHERE l.tender_id = $1
+);
+
+-- name: PurgeTenderProposals :execrows
+DELETE FROM proposals
+WHERE lot_id IN (SELECT l.id FROM lots l WHERE l.tender_id = $1);
+
+-- name: PurgeTenderAIResults :execrows
+DELETE FROM ai_analysis_results
+WHERE lot_id IN (SELECT l.id FROM lots l WHERE l.tender_id = $1);
+
+-- name: PurgeTenderLotChunks :execrows
+DELETE FROM lots_chunks
+WHERE lot_document_id IN (
+    SELECT d.id FROM lots_md_documents d JOIN lots l ON l.id = d.lot_id WHERE l.tender_id = $1
+);
+
+-- name: PurgeTenderLotDocuments :execrows
+DELETE FROM lots_md_documents
+WHERE lot_id IN (SELECT l.id FROM lots l WHERE l.tender_id = $1);
+
+-- name: PurgeTenderNotifications :execrows
+DELETE FROM notifications
+WHERE tender_id = $1
+   OR lot_id IN (SELECT l.id FROM lots l WHERE l.tender_id = $1);
+
+-- name: PurgeTenderLots :execrows
+DELETE FROM lots
+WHERE tender_id = $1;
+
+-- name: PurgeTenderRawDataHistory :execrows
+DELETE FROM tender_raw_data_history
+WHERE tender_id = $1;
+
+-- name: PurgeTenderRawData :execrows
+DELETE FROM tender_raw_data
+WHERE tender_id = $1;
+
+-- name: PurgeTenderImportMetrics :execrows
+DELETE FROM import_metrics
+WHERE tender_id = $1;
+
+-- name: PurgeTenderSubscriptions :execrows
+DELETE FROM user_tender_subscriptions
+WHERE tender_id = $1;
+
+-- name: PurgeTenderFieldChanges :execrows
+-- История правок тендера и его лотов; выполняется до удаления лотов.
+DELETE FROM field_changes
+WHERE (entity_type = 'tender' AND entity_id = $1)
+   OR (entity_type = 'lot' AND entity_id IN (SELECT id FROM lots WHERE tender_id = $1));
+
+-- name: PurgeTender :execrows
+-- Последний шаг: зависимых строк уже нет, RESTRICT по lots.tender_id не срабатывает.
+DELETE FROM tenders
+WHERE id = $1;
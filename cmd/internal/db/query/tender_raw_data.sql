@@ -25,4 +25,44 @@ WHERE tender_id = $1;
 -- name: DeleteTenderRawData :exec
 -- Удаляет запись с исходным JSON для указанного тендера.
 DELETE FROM tender_raw_data
-WHERE tender_id = $1;
\ No newline at end of file
+WHERE tender_id = $1;
+
+-- name: CreateTenderRawDataVersion :one
+-- Добавляет очередную версию исходного JSON тендера в историю импортов.
+-- Номер версии — следующий после последнего для тендера; вызывается в транзакции
+-- импорта после UpsertTender, который держит блокировку строки тендера.
+INSERT INTO tender_raw_data_history (
+        tender_id,
+        version,
+        raw_data,
+        payload_hash,
+        payload_bytes
+    )
+SELECT sqlc.arg(tender_id)::bigint,
+    COALESCE(MAX(h.version), 0) + 1,
+    sqlc.arg(raw_data)::jsonb,
+    sqlc.arg(payload_hash)::text,
+    sqlc.arg(payload_bytes)::bigint
+FROM tender_raw_data_history h
+WHERE h.tender_id = sqlc.arg(tender_id)::bigint
+RETURNING tender_id,
+    version,
+    payload_hash,
+    payload_bytes,
+    imported_at;
+-- name: ListTenderRawDataVersions :many
+-- Список версий исходного JSON тендера без самих данных, новые первыми.
+SELECT tender_id,
+    version,
+    payload_hash,
+    payload_bytes,
+    imported_at
+FROM tender_raw_data_history
+WHERE tender_id = $1
+ORDER BY version DESC;
+-- name: GetTenderRawDataVersion :one
+-- Получает снимок исходного JSON тендера конкретной версии.
+SELECT *
+FROM tender_raw_data_history
+WHERE tender_id = sqlc.arg(tender_id)
+    AND version = sqlc.arg(version);
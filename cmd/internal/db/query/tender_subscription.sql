@@ -0,0 +1,50 @@
+-- tender_subscription.sql
+-- Подписки пользователей на тендеры («Следить»). Подписчики получают
+-- уведомления о тендере в ленте (notification.sql, CreateTenderNotifications).
+
+-- name: FollowTender :exec
+-- Повторная подписка ничего не меняет: created_at остаётся от первой.
+INSERT INTO user_tender_subscriptions (user_id, tender_id)
+VALUES (sqlc.arg(user_id), sqlc.arg(tender_id))
+ON CONFLICT (user_id, tender_id) DO NOTHING;
+
+-- name: UnfollowTender :execrows
+DELETE FROM user_tender_subscriptions
+WHERE user_id = sqlc.arg(user_id)
+  AND tender_id = sqlc.arg(tender_id);
+
+-- name: ListFollowedTenders :many
+-- Тендеры, за которыми следит пользователь, последние подписки первыми.
+-- Удалённые тендеры не показываются, подписка на них сохраняется до
+-- восстановления. organization_id — организация пользователя; NULL только
+-- для admin (подписка могла остаться от прежней организации).
+SELECT
+    t.id,
+    t.etp_id,
+    t.title,
+    t.data_prepared_on_date,
+    s.created_at AS followed_at,
+    pc.proposals_count::bigint AS proposals_count
+FROM user_tender_subscriptions s
+JOIN tenders t ON t.id = s.tender_id
+CROSS JOIN LATERAL (
+    SELECT COUNT(*) AS proposals_count
+    FROM lots l
+    JOIN proposals p ON p.lot_id = l.id
+    WHERE l.tender_id = t.id
+      AND p.is_baseline = FALSE
+) pc
+WHERE s.user_id = sqlc.arg(user_id)
+  AND t.deleted_at IS NULL
+  AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint)
+ORDER BY s.created_at DESC, t.id DESC
+LIMIT sqlc.arg(page_limit)::int
+OFFSET sqlc.arg(page_offset)::int;
+
+-- name: CountFollowedTenders :one
+SELECT COUNT(*)::bigint
+FROM user_tender_subscriptions s
+JOIN tenders t ON t.id = s.tender_id
+WHERE s.user_id = sqlc.arg(user_id)
+  AND t.deleted_at IS NULL
+  AND (sqlc.narg(organization_id)::bigint IS NULL OR t.organization_id = sqlc.narg(organization_id)::bigint);
@@ -0,0 +1,59 @@
+-- tender_sync.sql
+-- Обновление открытых тендеров с ЭТП по расписанию (миграция 000041): выбор
+-- тендеров, которые пора запросить у парсера, и журнал обновлений с тем, что
+-- изменилось между версиями исходного JSON.
+
+-- name: ListTendersForSync :many
+-- Тендеры с незавершённой процедурой на ЭТП, которые не запрашивались дольше
+-- refresh_seconds; давно не обновлявшиеся первыми. Тендеры без etp_status
+-- (парсер статус не сообщал) и удалённые не обновляются.
+SELECT id, etp_id, organization_id, etp_status
+FROM tenders
+WHERE etp_status IS NOT NULL
+  AND deleted_at IS NULL
+  AND NOT (etp_status = ANY(sqlc.arg(final_statuses)::text[]))
+  AND (synced_at IS NULL OR synced_at < NOW() - make_interval(secs => sqlc.arg(refresh_seconds)::int))
+ORDER BY synced_at NULLS FIRST, id
+LIMIT sqlc.arg(batch_size)::int;
+
+-- name: MarkTenderSynced :exec
+-- updated_at не меняется: данные тендера обновит импорт.
+UPDATE tenders
+SET synced_at = NOW()
+WHERE id = $1;
+
+-- name: CreateTenderSync :one
+INSERT INTO tender_syncs (tender_id, task_id, from_version)
+VALUES (sqlc.arg(tender_id), sqlc.arg(task_id), sqlc.narg(from_version))
+RETURNING *;
+
+-- name: GetTenderSyncByTaskID :one
+SELECT * FROM tender_syncs
+WHERE task_id = $1;
+
+-- name: FinishTenderSync :one
+-- Записывает результат обновления. Завершённое обновление не перезаписывается:
+-- sql.ErrNoRows — парсер повторно сообщил итог той же задачи.
+UPDATE tender_syncs
+SET status = sqlc.arg(status),
+    to_version = sqlc.narg(to_version),
+    proposals_added = sqlc.arg(proposals_added),
+    prices_changed = sqlc.arg(prices_changed),
+    stats = sqlc.narg(stats)::jsonb,
+    error_message = sqlc.narg(error_message),
+    finished_at = NOW()
+WHERE task_id = sqlc.arg(task_id)
+  AND status = 'pending'
+RETURNING *;
+
+-- name: ListTenderSyncs :many
+-- Обновления тендера, последние первыми.
+SELECT * FROM tender_syncs
+WHERE tender_id = sqlc.arg(tender_id)
+ORDER BY requested_at DESC, id DESC
+LIMIT sqlc.arg(page_limit)::int
+OFFSET sqlc.arg(page_offset)::int;
+
+-- name: CountTenderSyncs :one
+SELECT COUNT(*)::bigint FROM tender_syncs
+WHERE tender_id = $1;
@@ -0,0 +1,40 @@
+-- unit_aliases.sql
+-- Синонимы единиц измерения: нормализованное написание ("м.кв", "кв.м")
+-- указывает на каноническую единицу ("м2"). Синоним не может совпадать с
+-- именем существующей единицы — такие единицы объединяются слиянием.
+
+-- name: GetUnitOfMeasurementByAlias :one
+-- Находит каноническую единицу по синониму. Используется импортом после
+-- неудачного поиска по normalized_name и до создания новой единицы.
+SELECT u.* FROM unit_aliases a
+JOIN units_of_measurement u ON u.id = a.unit_id
+WHERE a.alias = $1;
+
+-- name: CreateUnitAlias :one
+-- Добавляет синоним единице. alias уникален (uq_unit_aliases_alias).
+INSERT INTO unit_aliases (alias, unit_id)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: ListUnitAliases :many
+-- Все синонимы справочника, сгруппированные по единице.
+SELECT * FROM unit_aliases
+ORDER BY unit_id, alias;
+
+-- name: DeleteUnitAlias :execrows
+-- Удаляет синоним единицы. 0 строк — синонима с таким id у единицы нет.
+DELETE FROM unit_aliases
+WHERE id = sqlc.arg(id) AND unit_id = sqlc.arg(unit_id);
+
+-- name: ReassignUnitAliases :execrows
+-- Переносит синонимы единицы-дубликата на основную при слиянии.
+UPDATE unit_aliases
+SET unit_id = sqlc.arg(master_id)
+WHERE unit_id = sqlc.arg(duplicate_id);
+
+-- name: MoveUnitAlias :execrows
+-- Переносит синоним на другую единицу (массовая загрузка справочников).
+-- 0 строк — синонима с таким написанием нет.
+UPDATE unit_aliases
+SET unit_id = sqlc.arg(unit_id)
+WHERE alias = sqlc.arg(alias);
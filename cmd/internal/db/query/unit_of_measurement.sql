@@ -29,6 +29,19 @@ WHERE id = $1;
 SELECT * FROM units_of_measurement
 WHERE normalized_name = $1;
 
+-- name: ListUnitIDsByNames :many
+-- (Для импорта) Единицы измерения по нормализованным написаниям: по имени
+-- единицы или по синониму (unit_aliases), как GetUnitOfMeasurementByNormalizedName
+-- и GetUnitOfMeasurementByAlias вместе. lookup_name — написание из запроса;
+-- синоним не совпадает с именем единицы, поэтому написание встречается один раз.
+SELECT u.normalized_name::text AS lookup_name, u.id AS unit_id
+FROM units_of_measurement u
+WHERE u.normalized_name = ANY(sqlc.arg(names)::text[])
+UNION ALL
+SELECT a.alias::text AS lookup_name, a.unit_id
+FROM unit_aliases a
+WHERE a.alias = ANY(sqlc.arg(names)::text[]);
+
 -- name: ListUnitsOfMeasurement :many
 -- Получает пагинированный список всех единиц измерения.
 -- Сортировка по `normalized_name` эффективна, так как это поле проиндексировано.
@@ -37,6 +50,59 @@ ORDER BY normalized_name
 LIMIT $1
 OFFSET $2;
 
+-- name: ListExistingUnitNames :many
+-- Возвращает те из переданных нормализованных наименований, что уже есть в справочнике
+-- как имя единицы или как синоним (unit_aliases).
+-- Используется dry-run импорта, чтобы сообщить о единицах, которые импорт создал бы.
+SELECT normalized_name::text AS name FROM units_of_measurement
+WHERE normalized_name = ANY(sqlc.arg(names)::text[])
+UNION
+SELECT alias::text AS name FROM unit_aliases
+WHERE alias = ANY(sqlc.arg(names)::text[]);
+
+-- name: ListUnitsWithUsage :many
+-- Справочник единиц для админки: сколько позиций КП и каталога ссылается на каждую.
+-- Синонимы читаются отдельно (ListUnitAliases) и раскладываются по единицам в сервисе.
+SELECT
+    u.id,
+    u.normalized_name,
+    u.full_name,
+    u.description,
+    (SELECT COUNT(*) FROM position_items pi WHERE pi.unit_id = u.id)::bigint AS position_items_count,
+    (SELECT COUNT(*) FROM catalog_positions cp WHERE cp.unit_id = u.id)::bigint AS catalog_positions_count
+FROM units_of_measurement u
+ORDER BY u.normalized_name;
+
+-- name: GetUnitOfMeasurementByIDForUpdate :one
+-- Блокирует единицу измерения на время слияния.
+SELECT * FROM units_of_measurement
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListUnitMergeConflictCatalogPositions :many
+-- Позиции каталога дубликата, у которых есть позиция с тем же названием в основной
+-- единице: после переноса они нарушили бы uq_catalog_positions_title_unit,
+-- поэтому слияние отклоняется — сначала нужно объединить эти позиции каталога.
+SELECT dcp.id
+FROM catalog_positions dcp
+JOIN catalog_positions mcp
+    ON mcp.standard_job_title = dcp.standard_job_title
+   AND mcp.unit_id = sqlc.arg(master_id)
+WHERE dcp.unit_id = sqlc.arg(duplicate_id)
+ORDER BY dcp.id;
+
+-- name: ReassignUnitPositionItems :execrows
+-- Переносит позиции КП с единицы-дубликата на основную.
+UPDATE position_items
+SET unit_id = sqlc.arg(master_id), updated_at = NOW()
+WHERE unit_id = sqlc.arg(duplicate_id);
+
+-- name: ReassignUnitCatalogPositions :execrows
+-- Переносит позиции каталога с единицы-дубликата на основную.
+UPDATE catalog_positions
+SET unit_id = sqlc.arg(master_id), updated_at = NOW()
+WHERE unit_id = sqlc.arg(duplicate_id);
+
 -- name: UpdateUnitOfMeasurement :one
 -- Обновляет существующую единицу измерения по ее ID.
 -- Запрос использует паттерн COALESCE, что позволяет обновлять только те поля,
@@ -0,0 +1,97 @@
+-- name: ListWebhookSubscriptions :many
+-- Список подписок для админки (без секретов).
+SELECT id, url, events, description, is_active, created_by, created_at, updated_at
+FROM webhook_subscriptions
+ORDER BY created_at DESC;
+
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (url, secret, events, description, created_by)
+VALUES (
+    sqlc.arg(url),
+    sqlc.arg(secret),
+    sqlc.arg(events)::text[],
+    sqlc.narg(description),
+    sqlc.narg(created_by)
+)
+RETURNING id, url, events, description, is_active, created_by, created_at, updated_at;
+
+-- name: UpdateWebhookSubscription :one
+-- Частичное обновление: NULL-аргументы оставляют поле без изменений.
+-- Возвращает sql.ErrNoRows если подписка не найдена.
+UPDATE webhook_subscriptions
+SET url         = COALESCE(sqlc.narg(url), url),
+    events      = COALESCE(sqlc.narg(events)::text[], events),
+    description = COALESCE(sqlc.narg(description), description),
+    is_active   = COALESCE(sqlc.narg(is_active), is_active),
+    updated_at  = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING id, url, events, description, is_active, created_by, created_at, updated_at;
+
+-- name: DeleteWebhookSubscription :execrows
+-- Удаляет подписку вместе с журналом её доставок.
+DELETE FROM webhook_subscriptions
+WHERE id = $1;
+
+-- name: EnqueueWebhookDeliveries :execrows
+-- Ставит событие в очередь для всех активных подписок на него.
+INSERT INTO webhook_deliveries (subscription_id, event, payload)
+SELECT s.id, sqlc.arg(event)::text, sqlc.arg(payload)::jsonb
+FROM webhook_subscriptions s
+WHERE s.is_active
+  AND sqlc.arg(event)::text = ANY(s.events);
+
+-- name: ClaimDueWebhookDeliveries :many
+-- Забирает пачку доставок, срок которых наступил, и сдвигает next_attempt_at на
+-- leased_until: если инстанс упадёт посреди отправки, доставка вернётся в
+-- очередь после окончания аренды. SKIP LOCKED позволяет нескольким инстансам
+-- API разбирать очередь параллельно без двойной отправки.
+UPDATE webhook_deliveries d
+SET next_attempt_at = sqlc.arg(leased_until)::timestamptz
+FROM webhook_subscriptions s
+WHERE d.subscription_id = s.id
+  AND d.id IN (
+      SELECT id
+      FROM webhook_deliveries
+      WHERE status = 'pending'
+        AND next_attempt_at <= NOW()
+      ORDER BY next_attempt_at
+      LIMIT sqlc.arg(batch_size)
+      FOR UPDATE SKIP LOCKED
+  )
+RETURNING d.id, d.subscription_id, d.event, d.payload, d.attempts, s.url, s.secret;
+
+-- name: MarkWebhookDeliveryDelivered :exec
+UPDATE webhook_deliveries
+SET status          = 'delivered',
+    attempts        = attempts + 1,
+    last_attempt_at = NOW(),
+    response_status = sqlc.arg(response_status)::integer,
+    last_error      = NULL,
+    delivered_at    = NOW()
+WHERE id = sqlc.arg(id);
+
+-- name: MarkWebhookDeliveryAttemptFailed :exec
+-- Фиксирует неуспешную попытку. status = 'pending' с новым next_attempt_at —
+-- повтор, status = 'failed' — попытки исчерпаны.
+UPDATE webhook_deliveries
+SET status          = sqlc.arg(status),
+    attempts        = attempts + 1,
+    last_attempt_at = NOW(),
+    next_attempt_at = sqlc.arg(next_attempt_at),
+    response_status = sqlc.narg(response_status)::integer,
+    last_error      = sqlc.arg(last_error)::text
+WHERE id = sqlc.arg(id);
+
+-- name: ListWebhookDeliveries :many
+-- Журнал доставок подписки, новые первыми. status = NULL — все статусы.
+SELECT id, subscription_id, event, payload, status, attempts, next_attempt_at,
+       last_attempt_at, response_status, last_error, created_at, delivered_at
+FROM webhook_deliveries
+WHERE subscription_id = sqlc.arg(subscription_id)
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: WebhookSubscriptionExists :one
+SELECT EXISTS (SELECT 1 FROM webhook_subscriptions WHERE id = $1);
@@ -46,14 +46,17 @@ RETURNING *;
 --             Используется в REST API для ручного назначения победителей.
 -- 
 -- Параметры:
---   $1 (proposal_id) - ID предложения, которое назначается победителем
---   $2 (rank)        - Место победителя (1, 2, 3, ...)
---   $3 (notes)       - Дополнительные заметки (может быть NULL)
+--   sqlc.arg(proposal_id)  - ID предложения, которое назначается победителем
+--   sqlc.arg(rank)         - Место победителя (1, 2, 3, ...)
+--   sqlc.arg(notes)        - Дополнительные заметки (может быть NULL)
+--   sqlc.narg(award_price) - Цена контракта (NUMERIC(18,2), может быть NULL)
 -- 
 -- Поведение:
 --   - Создает новую запись победителя
 --   - Выбрасывает ошибку (constraint violation), если proposal_id уже победитель
 --   - awarded_share устанавливается в NULL (может быть обновлен позже)
+--   - Если award_price не передана, берётся итог предложения с НДС,
+--     округлённый до копеек
 -- 
 -- Возвращает: Базовую информацию о созданном победителе
 -- 
@@ -65,11 +68,22 @@ RETURNING *;
 INSERT INTO winners (
     proposal_id,
     rank,
-    notes
+    notes,
+    award_price
 ) VALUES (
-    $1, $2, $3
+    sqlc.arg(proposal_id),
+    sqlc.arg(rank),
+    sqlc.arg(notes),
+    COALESCE(
+        sqlc.narg(award_price)::numeric,
+        (SELECT ROUND(psl.total_cost, 2)
+         FROM proposal_summary_lines psl
+         WHERE psl.proposal_id = sqlc.arg(proposal_id)
+           AND psl.summary_key = 'total_cost_with_vat'
+           AND psl.total_cost >= 0)
+    )
 )
-RETURNING id, proposal_id, rank, created_at;
+RETURNING id, proposal_id, rank, award_price, created_at;
 
 -- ============================================================================
 -- ЧТЕНИЕ (ПОЛУЧЕНИЕ ДАННЫХ)
@@ -117,7 +131,7 @@ WHERE proposal_id = $1;
 --   - Отображение списка победителей на странице лота
 -- 
 -- Примечание: JOIN с proposals необходим для фильтрации по lot_id
-SELECT w.id, w.proposal_id, w.rank, w.awarded_share, w.notes, w.created_at, w.updated_at FROM winners w
+SELECT w.id, w.proposal_id, w.rank, w.awarded_share, w.notes, w.created_at, w.updated_at, w.award_price FROM winners w
 JOIN proposals p ON w.proposal_id = p.id
 WHERE p.lot_id = $1
 ORDER BY w.rank ASC, w.created_at ASC
@@ -137,6 +151,7 @@ OFFSET $3;
 --   sqlc.narg(rank)         - Новое место (опционально)
 --   sqlc.narg(awarded_share) - Новая доля контракта (опционально)
 --   sqlc.narg(notes)        - Новые заметки (опционально)
+--   sqlc.narg(award_price)  - Новая цена контракта (опционально, NUMERIC(18,2))
 -- 
 -- Поведение:
 --   - Использует COALESCE для сохранения старых значений, если новое NULL
@@ -159,6 +174,7 @@ SET
     rank = COALESCE(sqlc.narg(rank), rank),
     awarded_share = COALESCE(sqlc.narg(awarded_share), awarded_share),
     notes = COALESCE(sqlc.narg(notes), notes),
+    award_price = COALESCE(sqlc.narg(award_price)::numeric, award_price),
     updated_at = NOW()
 WHERE
     id = sqlc.arg(id)
@@ -221,12 +237,10 @@ RETURNING *;
 --   - rank              - Место победителя (1, 2, 3, ...)
 --   - contractor_name   - Название компании-победителя
 --   - contractor_inn    - ИНН компании-победителя
---   - price             - Итоговая стоимость предложения (из proposal_summary_lines)
+--   - price             - Цена контракта победителя (winners.award_price, NUMERIC(18,2))
 -- 
 -- Поведение:
 --   - JOIN с 4 таблицами: winners → proposals → lots → contractors
---   - LEFT JOIN с proposal_summary_lines для получения цены
---   - Фильтр по summary_key = 'total_cost_with_vat' (итоговая цена с НДС)
 --   - Сортировка: сначала по lot_id, затем по рангу победителя
 --   - Возвращает все записи (без пагинации)
 -- 
@@ -246,7 +260,7 @@ SELECT
     w.rank                        AS rank,
     c.title                       AS contractor_name,
     c.inn                         AS contractor_inn,
-    psl.total_cost                AS price
+    w.award_price                 AS price
 FROM winners w
 JOIN proposals p
   ON p.id = w.proposal_id
@@ -254,9 +268,6 @@ JOIN lots l
   ON l.id = p.lot_id
 JOIN contractors c
   ON c.id = p.contractor_id
-LEFT JOIN proposal_summary_lines psl
-  ON psl.proposal_id = p.id
- AND psl.summary_key = 'total_cost_with_vat'
 WHERE l.tender_id = $1
 ORDER BY p.lot_id, w.rank ASC;
 
@@ -0,0 +1,72 @@
+-- work_group.sql
+-- Классификатор видов работ (work_groups) и привязка к нему позиций каталога.
+
+-- name: ListWorkGroups :many
+-- Все узлы классификатора с числом позиций каталога, отнесённых к узлу напрямую.
+-- Дерево собирается в сервисе; узлов немного, поэтому без пагинации.
+SELECT
+    wg.id,
+    wg.parent_id,
+    wg.code,
+    wg.name,
+    wg.created_at,
+    wg.updated_at,
+    COUNT(cp.id) AS positions_count
+FROM work_groups wg
+LEFT JOIN catalog_positions cp ON cp.work_group_code = wg.code
+GROUP BY wg.id
+ORDER BY wg.code;
+
+-- name: GetWorkGroupByID :one
+SELECT * FROM work_groups
+WHERE id = $1;
+
+-- name: GetWorkGroupByCode :one
+SELECT * FROM work_groups
+WHERE code = $1;
+
+-- name: CreateWorkGroup :one
+INSERT INTO work_groups (parent_id, code, name)
+VALUES (sqlc.narg(parent_id), sqlc.arg(code), sqlc.arg(name))
+RETURNING *;
+
+-- name: UpdateWorkGroup :one
+-- Новый код переносится на позиции каталога (ON UPDATE CASCADE).
+UPDATE work_groups
+SET parent_id = sqlc.narg(parent_id),
+    code = sqlc.arg(code),
+    name = sqlc.arg(name),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: IsWorkGroupInSubtree :one
+-- Входит ли candidate_id в поддерево root_id (включая сам root_id).
+-- Перенос узла под собственного потомка создал бы цикл.
+WITH RECURSIVE subtree AS (
+    SELECT id FROM work_groups WHERE id = sqlc.arg(root_id)
+    UNION ALL
+    SELECT wg.id
+    FROM work_groups wg
+    JOIN subtree s ON wg.parent_id = s.id
+)
+SELECT EXISTS (
+    SELECT 1 FROM subtree WHERE id = sqlc.arg(candidate_id)
+)::boolean AS in_subtree;
+
+-- name: CountWorkGroupChildren :one
+SELECT COUNT(*) FROM work_groups
+WHERE parent_id = $1;
+
+-- name: DeleteWorkGroup :execrows
+-- Позиции каталога узла остаются без кода (ON DELETE SET NULL).
+DELETE FROM work_groups
+WHERE id = $1;
+
+-- name: SetCatalogPositionWorkGroup :one
+-- Относит позицию каталога к узлу классификатора; NULL — снимает привязку.
+UPDATE catalog_positions
+SET work_group_code = sqlc.narg(work_group_code),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
@@ -0,0 +1,76 @@
+package grpcapi
+
+import (
+	"context"
+	"strings"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/grpcapi/workerpb"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/servicecreds"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// methodScopes — scope ключа, который требует каждый метод. Метод, которого
+// здесь нет, отклоняется: новый RPC не станет доступен любому ключу по ошибке.
+var methodScopes = map[string]servicecreds.Scope{
+	workerpb.WorkerService_ImportTender_FullMethodName:          servicecreds.ScopeImport,
+	workerpb.WorkerService_GetUnmatchedPositions_FullMethodName: servicecreds.ScopeRAG,
+	workerpb.WorkerService_MatchPositionBatch_FullMethodName:    servicecreds.ScopeRAG,
+	workerpb.WorkerService_CatalogIndexed_FullMethodName:        servicecreds.ScopeRAG,
+}
+
+// authenticator — проверка ключа сервиса (servicecreds.Service).
+type authenticator interface {
+	Authenticate(token string) (servicecreds.Identity, bool)
+}
+
+type identityKey struct{}
+
+// identityFromContext возвращает воркера, аутентифицированного ServiceAuthInterceptor.
+func identityFromContext(ctx context.Context) (servicecreds.Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(servicecreds.Identity)
+	return identity, ok
+}
+
+// ServiceAuthInterceptor — аналог ServiceBearerAuthMiddleware и RequireServiceScope
+// для gRPC: ключ берётся из metadata "authorization: Bearer <key>", scope — из methodScopes.
+func ServiceAuthInterceptor(creds authenticator, logger logging.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		client := "unknown"
+		if p, ok := peer.FromContext(ctx); ok {
+			client = p.Addr.String()
+		}
+
+		var token string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get("authorization"); len(values) > 0 && strings.HasPrefix(values[0], "Bearer ") {
+				token = values[0][7:]
+			}
+		}
+		if token == "" {
+			logger.Warnf("gRPC service auth failed: missing or invalid authorization metadata from %s", client)
+			return nil, status.Error(codes.Unauthenticated, "service auth required")
+		}
+
+		identity, ok := creds.Authenticate(token)
+		if !ok {
+			logger.Warnf("gRPC service auth failed: invalid token from %s", client)
+			return nil, status.Error(codes.Unauthenticated, "invalid service token")
+		}
+
+		scope, known := methodScopes[info.FullMethod]
+		if !known || !identity.HasScope(scope) {
+			logger.Warnf("Service %s denied: scope %s required for %s", identity.ServiceName, scope, info.FullMethod)
+			return nil, status.Errorf(codes.PermissionDenied, "insufficient scope: %s required", scope)
+		}
+
+		logger.Infof("Service authenticated: %s (credential %d) from %s -> %s",
+			identity.ServiceName, identity.CredentialID, client, info.FullMethod)
+
+		return handler(context.WithValue(ctx, identityKey{}, identity), req)
+	}
+}
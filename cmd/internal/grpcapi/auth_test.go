@@ -0,0 +1,117 @@
+package grpcapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/grpcapi/workerpb"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/servicecreds"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR GRPC SERVICE AUTH (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Unauthenticated access — RPCs without a valid service key never reach the services
+2. Scope escalation — a key without the method's scope is rejected, as on /internal/worker
+3. Forgotten RPC — a method missing from methodScopes is denied to every key
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Authentication
+- GIVEN no authorization metadata, a non-Bearer value or an unknown key
+  WHEN any RPC is called
+  THEN Unauthenticated and the handler is not invoked
+
+SCENARIO 2: Scopes
+- GIVEN a key with scope rag
+  WHEN ImportTender is called → PermissionDenied
+  WHEN GetUnmatchedPositions is called → handler runs with the identity in context
+- GIVEN a key with every scope
+  WHEN a method absent from methodScopes is called → PermissionDenied
+*/
+
+type fakeAuthenticator map[string]servicecreds.Identity
+
+func (f fakeAuthenticator) Authenticate(token string) (servicecreds.Identity, bool) {
+	identity, ok := f[token]
+	return identity, ok
+}
+
+var testKeys = fakeAuthenticator{
+	"rag-key": {CredentialID: 7, ServiceName: "rag-worker", Scopes: []string{string(servicecreds.ScopeRAG)}},
+	"all-key": {ServiceName: "python-worker", Scopes: []string{
+		string(servicecreds.ScopeImport), string(servicecreds.ScopeRAG), string(servicecreds.ScopeAIResults),
+	}},
+}
+
+// callWithAuth вызывает перехватчик для метода и возвращает код ответа и identity,
+// которую увидел обработчик (nil, если обработчик не вызывался).
+func callWithAuth(t *testing.T, method string, authorization ...string) (codes.Code, *servicecreds.Identity) {
+	t.Helper()
+	ctx := context.Background()
+	if len(authorization) > 0 {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", authorization[0]))
+	}
+
+	var seen *servicecreds.Identity
+	handler := func(ctx context.Context, req any) (any, error) {
+		identity, ok := identityFromContext(ctx)
+		require.True(t, ok)
+		seen = &identity
+		return "ok", nil
+	}
+
+	interceptor := ServiceAuthInterceptor(testKeys, testutil.NewMockLogger())
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	return status.Code(err), seen
+}
+
+func TestServiceAuthInterceptor_Authentication(t *testing.T) {
+	method := workerpb.WorkerService_GetUnmatchedPositions_FullMethodName
+
+	for name, authorization := range map[string][]string{
+		"no metadata": nil,
+		"not bearer":  {"Basic rag-key"},
+		"unknown key": {"Bearer wrong-key"},
+		"empty key":   {"Bearer "},
+	} {
+		t.Run(name, func(t *testing.T) {
+			code, seen := callWithAuth(t, method, authorization...)
+			assert.Equal(t, codes.Unauthenticated, code)
+			assert.Nil(t, seen)
+		})
+	}
+}
+
+func TestServiceAuthInterceptor_Scopes(t *testing.T) {
+	code, seen := callWithAuth(t, workerpb.WorkerService_ImportTender_FullMethodName, "Bearer rag-key")
+	assert.Equal(t, codes.PermissionDenied, code)
+	assert.Nil(t, seen)
+
+	code, seen = callWithAuth(t, workerpb.WorkerService_GetUnmatchedPositions_FullMethodName, "Bearer rag-key")
+	assert.Equal(t, codes.OK, code)
+	require.NotNil(t, seen)
+	assert.Equal(t, "rag-worker", seen.ServiceName)
+
+	code, seen = callWithAuth(t, "/tenders.worker.v1.WorkerService/DropDatabase", "Bearer all-key")
+	assert.Equal(t, codes.PermissionDenied, code)
+	assert.Nil(t, seen)
+}
+
+func TestMethodScopes_CoverService(t *testing.T) {
+	for _, method := range workerpb.WorkerService_ServiceDesc.Methods {
+		fullMethod := "/" + workerpb.WorkerService_ServiceDesc.ServiceName + "/" + method.MethodName
+		assert.Contains(t, methodScopes, fullMethod, "RPC %s без scope недоступен ни одному ключу", method.MethodName)
+	}
+}
@@ -0,0 +1,232 @@
+package grpcapi
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/grpcapi/workerpb"
+)
+
+// tenderFromProto переводит тендер из protobuf в api_models.FullTenderData.
+// Ошибка возвращается только для нечисловых сумм и объёмов; бизнес-правила
+// проверяет FullTenderData.Validate.
+func tenderFromProto(t *workerpb.Tender) (*api_models.FullTenderData, error) {
+	if t == nil {
+		return nil, fmt.Errorf("поле tender обязательно")
+	}
+	data := &api_models.FullTenderData{
+		TenderID:       t.GetTenderId(),
+		TenderTitle:    t.GetTenderTitle(),
+		TenderObject:   t.GetTenderObject(),
+		TenderAddress:  t.GetTenderAddress(),
+		OrganizationID: t.GetOrganizationId(),
+		ExecutorData: api_models.Executor{
+			ExecutorName:  t.GetExecutor().GetExecutorName(),
+			ExecutorPhone: t.GetExecutor().GetExecutorPhone(),
+			ExecutorDate:  t.GetExecutor().GetExecutorDate(),
+		},
+		LotsData: make(map[string]api_models.Lot, len(t.GetLots())),
+	}
+	for lotKey, l := range t.GetLots() {
+		lot := api_models.Lot{
+			LotTitle:     l.GetLotTitle(),
+			ProposalData: make(map[string]api_models.ContractorProposalDetails, len(l.GetProposals())),
+		}
+		baseline, err := proposalFromProto(l.GetBaselineProposal())
+		if err != nil {
+			return nil, fmt.Errorf("лот '%s', базовое предложение: %w", lotKey, err)
+		}
+		lot.BaseLineProposal = baseline
+		for proposalKey, p := range l.GetProposals() {
+			proposal, err := proposalFromProto(p)
+			if err != nil {
+				return nil, fmt.Errorf("лот '%s', предложение '%s': %w", lotKey, proposalKey, err)
+			}
+			lot.ProposalData[proposalKey] = proposal
+		}
+		data.LotsData[lotKey] = lot
+	}
+	return data, nil
+}
+
+func proposalFromProto(p *workerpb.Proposal) (api_models.ContractorProposalDetails, error) {
+	proposal := api_models.ContractorProposalDetails{
+		Title:                p.GetTitle(),
+		Inn:                  p.GetInn(),
+		Address:              p.GetAddress(),
+		Accreditation:        p.GetAccreditation(),
+		ContractorCoordinate: p.GetContractorCoordinate(),
+		ContractorWidth:      int(p.GetContractorWidth()),
+		ContractorHeight:     int(p.GetContractorHeight()),
+		Currency:             p.GetCurrency(),
+		ContractorItems: api_models.ContractorItemsContainer{
+			Positions: make(map[string]api_models.PositionItem, len(p.GetPositions())),
+			Summary:   make(map[string]api_models.SummaryLine, len(p.GetSummary())),
+		},
+	}
+	if len(p.GetAdditionalInfo()) > 0 {
+		proposal.AdditionalInfo = make(map[string]*string, len(p.GetAdditionalInfo()))
+		for key, value := range p.GetAdditionalInfo() {
+			if value == nil {
+				proposal.AdditionalInfo[key] = nil
+				continue
+			}
+			v := value.GetValue()
+			proposal.AdditionalInfo[key] = &v
+		}
+	}
+
+	for key, item := range p.GetPositions() {
+		position, err := positionFromProto(item)
+		if err != nil {
+			return proposal, fmt.Errorf("позиция '%s': %w", key, err)
+		}
+		proposal.ContractorItems.Positions[key] = position
+	}
+	for key, line := range p.GetSummary() {
+		summary, err := summaryFromProto(line)
+		if err != nil {
+			return proposal, fmt.Errorf("итоговая строка '%s': %w", key, err)
+		}
+		proposal.ContractorItems.Summary[key] = summary
+	}
+	return proposal, nil
+}
+
+func positionFromProto(p *workerpb.PositionItem) (api_models.PositionItem, error) {
+	if p == nil {
+		return api_models.PositionItem{}, fmt.Errorf("пустое значение")
+	}
+	item := api_models.PositionItem{
+		Number:             p.GetNumber(),
+		ChapterNumber:      p.ChapterNumber,
+		ArticleSMR:         p.ArticleSmr,
+		JobTitle:           p.GetJobTitle(),
+		CommentOrganizer:   p.CommentOrganizer,
+		Unit:               p.Unit,
+		CommentContractor:  p.CommentContractor,
+		JobTitleNormalized: p.JobTitleNormalized,
+		IsChapter:          p.GetIsChapter(),
+		ChapterRef:         p.ChapterRef,
+		Currency:           p.GetCurrency(),
+	}
+	var err error
+	if item.Quantity, err = decimalFromProto("quantity", p.Quantity); err != nil {
+		return item, err
+	}
+	if item.SuggestedQuantity, err = decimalFromProto("suggested_quantity", p.SuggestedQuantity); err != nil {
+		return item, err
+	}
+	if item.UnitCost, err = costFromProto("unit_cost", p.GetUnitCost()); err != nil {
+		return item, err
+	}
+	if item.TotalCost, err = costFromProto("total_cost", p.GetTotalCost()); err != nil {
+		return item, err
+	}
+	if item.TotalCostForOrganizerQuantity, err = moneyFromProto("total_cost_for_organizer_quantity", p.TotalCostForOrganizerQuantity); err != nil {
+		return item, err
+	}
+	if item.DeviationFromBaselineCost, err = moneyFromProto("deviation_from_baseline_cost", p.DeviationFromBaselineCost); err != nil {
+		return item, err
+	}
+	return item, nil
+}
+
+func summaryFromProto(s *workerpb.SummaryLine) (api_models.SummaryLine, error) {
+	if s == nil {
+		return api_models.SummaryLine{}, fmt.Errorf("пустое значение")
+	}
+	line := api_models.SummaryLine{
+		JobTitle:          s.GetJobTitle(),
+		CommentContractor: s.CommentContractor,
+		Currency:          s.GetCurrency(),
+	}
+	var err error
+	if line.SuggestedQuantity, err = decimalFromProto("suggested_quantity", s.SuggestedQuantity); err != nil {
+		return line, err
+	}
+	if line.UnitCost, err = costFromProto("unit_cost", s.GetUnitCost()); err != nil {
+		return line, err
+	}
+	if line.TotalCost, err = costFromProto("total_cost", s.GetTotalCost()); err != nil {
+		return line, err
+	}
+	if line.OrganizierQuantityCost, err = moneyFromProto("total_cost_for_organizer_quantity", s.TotalCostForOrganizerQuantity); err != nil {
+		return line, err
+	}
+	if line.Deviation, err = moneyFromProto("deviation_from_baseline_cost", s.DeviationFromBaselineCost); err != nil {
+		return line, err
+	}
+	return line, nil
+}
+
+func costFromProto(field string, c *workerpb.Cost) (api_models.Cost, error) {
+	var cost api_models.Cost
+	if c == nil {
+		return cost, nil
+	}
+	var err error
+	if cost.Materials, err = moneyFromProto(field+".materials", c.Materials); err != nil {
+		return cost, err
+	}
+	if cost.Works, err = moneyFromProto(field+".works", c.Works); err != nil {
+		return cost, err
+	}
+	if cost.IndirectCosts, err = moneyFromProto(field+".indirect_costs", c.IndirectCosts); err != nil {
+		return cost, err
+	}
+	if cost.Total, err = moneyFromProto(field+".total", c.Total); err != nil {
+		return cost, err
+	}
+	return cost, nil
+}
+
+func moneyFromProto(field string, s *string) (*api_models.Money, error) {
+	if s == nil {
+		return nil, nil
+	}
+	m, err := api_models.ParseMoney(*s)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", field, err)
+	}
+	return &m, nil
+}
+
+func decimalFromProto(field string, s *string) (*decimal.Decimal, error) {
+	if s == nil {
+		return nil, nil
+	}
+	d, err := decimal.NewFromString(*s)
+	if err != nil {
+		return nil, fmt.Errorf("%s: некорректное число %q", field, *s)
+	}
+	return &d, nil
+}
+
+// matchBatchToProto переводит результат пакетного сопоставления; текущее
+// закрепление позиции (conflict) передаётся JSON-строкой, как в HTTP-ответе.
+func matchBatchToProto(r *api_models.MatchPositionsBatchResponse) (*workerpb.MatchPositionBatchResponse, error) {
+	resp := &workerpb.MatchPositionBatchResponse{
+		Matched: int32(r.Matched),
+		Failed:  int32(r.Failed),
+		Results: make([]*workerpb.MatchPositionResult, len(r.Results)),
+	}
+	for i, item := range r.Results {
+		result := &workerpb.MatchPositionResult{
+			PositionItemId: item.PositionItemID,
+			Status:         item.Status,
+			Error:          item.Error,
+		}
+		if item.Conflict != nil {
+			conflict, err := json.Marshal(item.Conflict)
+			if err != nil {
+				return nil, fmt.Errorf("сериализация conflict позиции %d: %w", item.PositionItemID, err)
+			}
+			result.ConflictJson = string(conflict)
+		}
+		resp.Results[i] = result
+	}
+	return resp, nil
+}
@@ -0,0 +1,153 @@
+package grpcapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/grpcapi/workerpb"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR PROTOBUF CONVERSION (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Lost data — a tender imported over gRPC must produce the same FullTenderData as over JSON
+2. Lost precision — amounts travel as decimal strings and are parsed without float64
+3. Silent garbage — a non-numeric amount is rejected with the path to the field
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Tender conversion
+- GIVEN a tender with a lot, a baseline and a contractor proposal
+  WHEN tenderFromProto is called
+  THEN keys, optional fields, amounts and additional_info (including null) are preserved
+  AND unset optional fields stay nil, like omitted JSON keys
+
+SCENARIO 2: Invalid input
+- GIVEN no tender or a non-numeric amount
+  WHEN tenderFromProto is called
+  THEN error naming the lot, the proposal and the field
+
+SCENARIO 3: Match batch response
+- GIVEN a batch result with a conflict
+  WHEN matchBatchToProto is called
+  THEN counters and statuses are copied and the conflict is JSON
+*/
+
+func testProtoTender() *workerpb.Tender {
+	return &workerpb.Tender{
+		TenderId:       "T-1",
+		TenderTitle:    "Корпус 1",
+		TenderObject:   "ЖК",
+		TenderAddress:  "Москва",
+		Executor:       &workerpb.Executor{ExecutorName: "Иванов", ExecutorPhone: "+7", ExecutorDate: "01.02.2025"},
+		OrganizationId: 2,
+		Lots: map[string]*workerpb.Lot{
+			"lot_1": {
+				LotTitle:         "Лот 1",
+				BaselineProposal: &workerpb.Proposal{Title: "Расчётная стоимость"},
+				Proposals: map[string]*workerpb.Proposal{
+					"contractor_1": {
+						Title:                "ООО Ромашка",
+						Inn:                  "7700000000",
+						Address:              "Москва",
+						ContractorCoordinate: "A1",
+						ContractorWidth:      3,
+						ContractorHeight:     10,
+						Currency:             "usd",
+						AdditionalInfo: map[string]*wrapperspb.StringValue{
+							"срок":  wrapperspb.String("90 дней"),
+							"аванс": nil,
+						},
+						Positions: map[string]*workerpb.PositionItem{
+							"1": {
+								Number:   "1",
+								JobTitle: "Кладка",
+								Unit:     proto.String("м3"),
+								Quantity: proto.String("12.345"),
+								UnitCost: &workerpb.Cost{Total: proto.String("1000.005")},
+								TotalCost: &workerpb.Cost{
+									Materials: proto.String("10000"),
+									Total:     proto.String("12345.06"),
+								},
+							},
+						},
+						Summary: map[string]*workerpb.SummaryLine{
+							"total": {JobTitle: "Итого", TotalCost: &workerpb.Cost{Total: proto.String("12345.06")}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestTenderFromProto(t *testing.T) {
+	data, err := tenderFromProto(testProtoTender())
+	require.NoError(t, err)
+	require.NoError(t, data.Validate())
+
+	assert.Equal(t, "T-1", data.TenderID)
+	assert.Equal(t, int64(2), data.OrganizationID)
+	assert.Equal(t, "Иванов", data.ExecutorData.ExecutorName)
+	require.Contains(t, data.LotsData, "lot_1")
+	lot := data.LotsData["lot_1"]
+	assert.Equal(t, "Расчётная стоимость", lot.BaseLineProposal.Title)
+
+	proposal := lot.ProposalData["contractor_1"]
+	assert.Equal(t, 3, proposal.ContractorWidth)
+	assert.Equal(t, "USD", proposal.CurrencyCode())
+	require.Contains(t, proposal.AdditionalInfo, "аванс")
+	assert.Nil(t, proposal.AdditionalInfo["аванс"])
+	assert.Equal(t, "90 дней", *proposal.AdditionalInfo["срок"])
+
+	position := proposal.ContractorItems.Positions["1"]
+	assert.Equal(t, "м3", *position.Unit)
+	assert.Equal(t, "12.345", position.Quantity.String())
+	assert.Nil(t, position.SuggestedQuantity)
+	assert.Nil(t, position.ChapterNumber)
+	assert.Equal(t, "1000.005", position.UnitCost.Total.Decimal.String())
+	assert.Nil(t, position.UnitCost.Works)
+	assert.Equal(t, "10000", position.TotalCost.Materials.Decimal.String())
+
+	assert.Equal(t, "12345.06", proposal.ContractorItems.Summary["total"].TotalCost.Total.Decimal.String())
+}
+
+func TestTenderFromProto_Errors(t *testing.T) {
+	_, err := tenderFromProto(nil)
+	require.Error(t, err)
+
+	tender := testProtoTender()
+	tender.Lots["lot_1"].Proposals["contractor_1"].Positions["1"].TotalCost.Total = proto.String("много")
+	_, err = tenderFromProto(tender)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "lot_1")
+	assert.Contains(t, err.Error(), "contractor_1")
+	assert.Contains(t, err.Error(), "total_cost.total")
+}
+
+func TestMatchBatchToProto(t *testing.T) {
+	resp, err := matchBatchToProto(&api_models.MatchPositionsBatchResponse{
+		Matched: 1,
+		Failed:  1,
+		Results: []api_models.MatchPositionBatchItemResult{
+			{PositionItemID: 1, Status: "matched"},
+			{PositionItemID: 2, Status: "conflict", Error: "занято", Conflict: map[string]int64{"catalog_position_id": 9}},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), resp.GetMatched())
+	assert.Equal(t, int32(1), resp.GetFailed())
+	require.Len(t, resp.GetResults(), 2)
+	assert.Empty(t, resp.GetResults()[0].GetConflictJson())
+	assert.Equal(t, "conflict", resp.GetResults()[1].GetStatus())
+	assert.JSONEq(t, `{"catalog_position_id": 9}`, resp.GetResults()[1].GetConflictJson())
+}
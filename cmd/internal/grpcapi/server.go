@@ -0,0 +1,76 @@
+// Package grpcapi — gRPC-сервер для Python-воркеров. Повторяет часть
+// /internal/worker (импорт, очередь сопоставления, индексация каталога)
+// поверх тех же сервисов, но с protobuf вместо JSON.
+//
+// Контракт описан в proto/worker/v1/worker.proto; пакет workerpb генерируется
+// командой `make proto`.
+package grpcapi
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/grpcapi/workerpb"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/catalog"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/importer"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/matching"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/refcache"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/servicecreds"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/webhooks"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+	"google.golang.org/grpc"
+)
+
+type Server struct {
+	workerpb.UnimplementedWorkerServiceServer
+
+	logger          logging.Logger
+	tenderService   *importer.TenderImportService
+	catalogService  *catalog.CatalogService
+	matchingService *matching.MatchingService
+	webhooks        *webhooks.Service
+	refCache        *refcache.Service
+	grpcServer      *grpc.Server
+}
+
+func NewServer(
+	logger logging.Logger,
+	tenderService *importer.TenderImportService,
+	catalogService *catalog.CatalogService,
+	matchingService *matching.MatchingService,
+	serviceCreds *servicecreds.Service,
+	webhookService *webhooks.Service,
+	refCache *refcache.Service,
+	cfg config.GRPCConfig,
+) *Server {
+	server := &Server{
+		logger:          logger,
+		tenderService:   tenderService,
+		catalogService:  catalogService,
+		matchingService: matchingService,
+		webhooks:        webhookService,
+		refCache:        refCache,
+	}
+
+	server.grpcServer = grpc.NewServer(
+		grpc.MaxRecvMsgSize(cfg.MaxRecvMsgSize),
+		grpc.UnaryInterceptor(ServiceAuthInterceptor(serviceCreds, logger)),
+	)
+	workerpb.RegisterWorkerServiceServer(server.grpcServer, server)
+	return server
+}
+
+// Start слушает address и обслуживает запросы до Stop.
+func (s *Server) Start(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("listen %s: %w", address, err)
+	}
+	return s.grpcServer.Serve(listener)
+}
+
+// Stop дожидается завершения текущих вызовов и закрывает сервер.
+func (s *Server) Stop() {
+	s.grpcServer.GracefulStop()
+}
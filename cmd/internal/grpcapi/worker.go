@@ -0,0 +1,221 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/grpcapi/workerpb"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/matching"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/refcache"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/webhooks"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultUnmatchedLimit = 100
+	importTimeout         = 5 * time.Minute // Как у POST /internal/worker/import-tender
+)
+
+// ImportTender — аналог ImportTenderHandler. Слепок для tender_raw_data
+// собирается из FullTenderData, потому что исходного JSON у gRPC-запроса нет.
+func (s *Server) ImportTender(ctx context.Context, req *workerpb.ImportTenderRequest) (*workerpb.ImportTenderResponse, error) {
+	logger := s.logger.WithField("rpc", "ImportTender")
+
+	payload, err := tenderFromProto(req.GetTender())
+	if err != nil {
+		logger.Warnf("Некорректные данные тендера: %v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := payload.Validate(); err != nil {
+		logger.Warnf("Невалидные данные для импорта тендера: %v", err)
+		var fieldErrs api_models.ValidationErrors
+		errors.As(err, &fieldErrs)
+		return nil, invalidArgument(err, fieldErrs)
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		logger.Errorf("Ошибка сериализации тендера: %v", err)
+		return nil, status.Error(codes.Internal, "ошибка сериализации тендера")
+	}
+
+	importCtx, cancel := context.WithTimeout(ctx, importTimeout)
+	defer cancel()
+
+	dbID, lotsMap, newItemsPending, importID, err := s.tenderService.ImportFullTender(importCtx, payload, raw)
+	if err != nil {
+		logger.Errorf("Ошибка импорта тендера: %v", err)
+		return nil, toStatus(err)
+	}
+
+	// Импорт создаёт недостающие типы, разделы, категории и единицы измерения
+	s.refCache.Invalidate(ctx, refcache.Groups...)
+
+	logger.Infof("Импорт завершён. TenderID=%s, DB_ID=%d, lots=%v, new_pending=%v, import_id=%d", payload.TenderID, dbID, lotsMap, newItemsPending, importID)
+
+	if err := s.webhooks.Publish(ctx, webhooks.EventTenderImported, webhooks.TenderImportedData{
+		TenderDBID: dbID,
+		TenderID:   payload.TenderID,
+		ImportID:   importID,
+		LotIDsMap:  lotsMap,
+	}); err != nil {
+		logger.Errorf("Не удалось поставить событие %s в очередь вебхуков: %v", webhooks.EventTenderImported, err)
+	}
+
+	return &workerpb.ImportTenderResponse{
+		TenderDbId:             dbID,
+		LotIdsMap:              lotsMap,
+		NewCatalogItemsPending: newItemsPending,
+		ImportId:               importID,
+	}, nil
+}
+
+// GetUnmatchedPositions — аналог UnmatchedPositionsHandler.
+func (s *Server) GetUnmatchedPositions(ctx context.Context, req *workerpb.GetUnmatchedPositionsRequest) (*workerpb.GetUnmatchedPositionsResponse, error) {
+	logger := s.logger.WithField("rpc", "GetUnmatchedPositions")
+
+	filter := matching.UnmatchedPositionsFilter{
+		Limit:    req.GetLimit(),
+		AfterID:  req.GetAfterId(),
+		TenderID: req.TenderId,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultUnmatchedLimit
+	}
+	if filter.AfterID < 0 {
+		return nil, status.Error(codes.InvalidArgument, "after_id должен быть >= 0")
+	}
+	if filter.TenderID != nil && *filter.TenderID <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "tender_id должен быть > 0")
+	}
+	if req.GetCreatedAfter() != nil {
+		if err := req.GetCreatedAfter().CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "некорректный created_after: %v", err)
+		}
+		createdAfter := req.GetCreatedAfter().AsTime()
+		filter.CreatedAfter = &createdAfter
+	}
+
+	positions, err := s.matchingService.GetUnmatchedPositions(ctx, filter)
+	if err != nil {
+		logger.Errorf("Ошибка GetUnmatchedPositions: %v", err)
+		return nil, toStatus(err)
+	}
+
+	resp := &workerpb.GetUnmatchedPositionsResponse{
+		Positions: make([]*workerpb.UnmatchedPosition, len(positions)),
+	}
+	for i, p := range positions {
+		resp.Positions[i] = &workerpb.UnmatchedPosition{
+			PositionItemId:     p.PositionItemID,
+			JobTitleInProposal: p.JobTitleInProposal,
+			RichContextString:  p.RichContextString,
+			DraftCatalogId:     p.DraftCatalogID,
+			StandardJobTitle:   p.StandardJobTitle,
+		}
+	}
+	return resp, nil
+}
+
+// MatchPositionBatch — аналог MatchPositionsBatchHandler.
+func (s *Server) MatchPositionBatch(ctx context.Context, req *workerpb.MatchPositionBatchRequest) (*workerpb.MatchPositionBatchResponse, error) {
+	logger := s.logger.WithField("rpc", "MatchPositionBatch")
+
+	batch := api_models.MatchPositionsBatchRequest{
+		WorkerID: req.GetWorkerId(),
+		Matches:  make([]api_models.MatchPositionRequest, len(req.GetMatches())),
+	}
+	for i, m := range req.GetMatches() {
+		batch.Matches[i] = api_models.MatchPositionRequest{
+			PositionItemID:    m.GetPositionItemId(),
+			CatalogPositionID: m.GetCatalogPositionId(),
+			Hash:              m.GetHash(),
+			NormVersion:       int(m.GetNormVersion()),
+			WorkerID:          m.GetWorkerId(),
+		}
+	}
+	// Воркер без worker_id идентифицируется именем сервиса из ключа
+	if batch.WorkerID == "" {
+		if identity, ok := identityFromContext(ctx); ok {
+			batch.WorkerID = identity.ServiceName
+		}
+	}
+
+	result, err := s.matchingService.MatchPositionsBatch(ctx, batch)
+	if err != nil {
+		logger.Errorf("Ошибка MatchPositionsBatch: %v", err)
+		return nil, toStatus(err)
+	}
+	resp, err := matchBatchToProto(result)
+	if err != nil {
+		logger.Errorf("Ошибка формирования ответа MatchPositionBatch: %v", err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return resp, nil
+}
+
+// CatalogIndexed — аналог CatalogIndexedHandler.
+func (s *Server) CatalogIndexed(ctx context.Context, req *workerpb.CatalogIndexedRequest) (*workerpb.CatalogIndexedResponse, error) {
+	logger := s.logger.WithField("rpc", "CatalogIndexed")
+
+	report, err := s.catalogService.MarkCatalogItemsAsActive(ctx, req.GetCatalogIds())
+	if err != nil {
+		logger.Errorf("Ошибка MarkCatalogItemsAsActive: %v", err)
+		return nil, toStatus(err)
+	}
+
+	return &workerpb.CatalogIndexedResponse{
+		Requested:        int32(report.Requested),
+		Activated:        report.Activated,
+		NotFound:         report.NotFound,
+		AlreadyActive:    report.AlreadyActive,
+		MergedOrInactive: report.MergedOrInactive,
+	}, nil
+}
+
+// toStatus переводит ошибки сервисного слоя в коды gRPC так же, как
+// HTTP-хендлеры переводят их в 400/404/409/500.
+func toStatus(err error) error {
+	var validationErr *apierrors.ValidationError
+	var notFoundErr *apierrors.NotFoundError
+	var conflictErr *apierrors.ConflictError
+	switch {
+	case errors.As(err, &validationErr):
+		fieldErrs, _ := validationErr.Details.(api_models.ValidationErrors)
+		return invalidArgument(err, fieldErrs)
+	case errors.As(err, &notFoundErr):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.As(err, &conflictErr):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// invalidArgument — INVALID_ARGUMENT, в деталях которого нарушения по полям
+// (google.rpc.BadRequest, field — путь как в HTTP-ответе validation_failed).
+func invalidArgument(err error, fieldErrs api_models.ValidationErrors) error {
+	st := status.New(codes.InvalidArgument, err.Error())
+	if len(fieldErrs) == 0 {
+		return st.Err()
+	}
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: fe.Path, Description: fe.Message})
+	}
+	withDetails, detailsErr := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if detailsErr != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
@@ -0,0 +1,130 @@
+// Package i18n — каталоги сообщений API об ошибках (ru, en) и выбор языка
+// ответа по заголовку Accept-Language.
+//
+// Хендлеры и сервисы передают не текст, а ключ сообщения (catalog.position_not_found)
+// с аргументами; текст на языке клиента подставляется при формировании ответа.
+// Ключ отдаётся клиенту в поле message_key, поэтому фронтенд может показать
+// собственный перевод, не разбирая message.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Locale — язык сообщений (базовый подтег BCP 47).
+type Locale string
+
+const (
+	RU Locale = "ru"
+	EN Locale = "en"
+
+	// Default — язык ответа, если клиент не передал Accept-Language или
+	// не принимает ни один из поддерживаемых языков.
+	Default = RU
+)
+
+// Supported — языки, для которых есть каталог; первый — язык по умолчанию.
+var Supported = []Locale{RU, EN}
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// catalogs: язык → ключ → шаблон fmt. Шаблоны с разным порядком слов в языках
+// используют явные индексы аргументов (%[2]s).
+var catalogs = mustLoadCatalogs()
+
+var matcher = language.NewMatcher([]language.Tag{language.Russian, language.English})
+
+func mustLoadCatalogs() map[Locale]map[string]string {
+	result := make(map[Locale]map[string]string, len(Supported))
+	for _, locale := range Supported {
+		raw, err := localeFiles.ReadFile("locales/" + string(locale) + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: каталог %s не найден: %v", locale, err))
+		}
+		messages := make(map[string]string)
+		if err := json.Unmarshal(raw, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: некорректный каталог %s: %v", locale, err))
+		}
+		result[locale] = messages
+	}
+	return result
+}
+
+// Negotiate выбирает язык ответа по значению заголовка Accept-Language
+// (с учётом q-весов). Пустой или нераспознанный заголовок — Default.
+func Negotiate(acceptLanguage string) Locale {
+	if strings.TrimSpace(acceptLanguage) == "" {
+		return Default
+	}
+	tag, _ := language.MatchStrings(matcher, acceptLanguage)
+	base, _ := tag.Base()
+	locale := Locale(base.String())
+	if _, ok := catalogs[locale]; !ok {
+		return Default
+	}
+	return locale
+}
+
+// Has сообщает, есть ли ключ в каталоге языка по умолчанию.
+func Has(key string) bool {
+	_, ok := catalogs[Default][key]
+	return ok
+}
+
+// T форматирует сообщение key на языке locale. Ключа нет в каталоге языка —
+// берётся каталог по умолчанию, нет и там — key считается шаблоном fmt (так
+// проходят транзитные сообщения вида "%v"). Аргументы-*Error переводятся на
+// тот же язык.
+func T(locale Locale, key string, args ...any) string {
+	template, ok := catalogs[locale][key]
+	if !ok {
+		if template, ok = catalogs[Default][key]; !ok {
+			template = key
+		}
+	}
+	if len(args) == 0 {
+		return template
+	}
+	localized := make([]any, len(args))
+	for i, arg := range args {
+		if msg, ok := arg.(*Error); ok {
+			localized[i] = T(locale, msg.Key, msg.Args...)
+			continue
+		}
+		localized[i] = arg
+	}
+	return fmt.Sprintf(template, localized...)
+}
+
+// Error — ошибка с ключом сообщения: хендлер отдаёт её клиенту на языке
+// запроса. Error() возвращает текст на языке по умолчанию (для логов).
+type Error struct {
+	Key  string
+	Args []any
+}
+
+// Errorf создаёт *Error. Аргументы-ошибки доступны через errors.Is/As, как
+// при %w в fmt.Errorf.
+func Errorf(key string, args ...any) error {
+	return &Error{Key: key, Args: args}
+}
+
+func (e *Error) Error() string {
+	return T(Default, e.Key, e.Args...)
+}
+
+func (e *Error) Unwrap() []error {
+	var wrapped []error
+	for _, arg := range e.Args {
+		if err, ok := arg.(error); ok {
+			wrapped = append(wrapped, err)
+		}
+	}
+	return wrapped
+}
@@ -0,0 +1,193 @@
+package i18n
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR MESSAGE CATALOGS (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Mixed languages — an English client must not get a Russian error because a
+   key is missing from one catalog
+2. Broken messages — a translation with different placeholders prints
+   %!d(MISSING) or leaks arguments into the wrong place
+3. Raw keys in the UI — a handler must not reference a key absent from the
+   catalogs
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Catalogs
+- GIVEN the ru and en catalogs
+  THEN they contain the same keys with the same fmt verbs
+
+SCENARIO 2: Locale negotiation
+- GIVEN an Accept-Language header
+  WHEN Negotiate is called
+  THEN the best supported language is chosen by q-weights
+  AND an empty or unsupported header falls back to ru
+
+SCENARIO 3: Formatting
+- GIVEN a key and arguments
+  THEN T formats the template of the requested language
+  AND an unknown key is used as a fmt template
+  AND *Error arguments are translated to the same language and stay reachable via errors.Is
+
+SCENARIO 4: Call sites
+- GIVEN the handlers and services under cmd
+  THEN every message key passed to apierrors, respondCode and i18n.Errorf exists in the catalogs
+*/
+
+var verbPattern = regexp.MustCompile(`%(\[\d+\])?[-+# 0]*\d*(\.\d+)?[a-zA-Z%]`)
+
+func verbs(template string) []string {
+	found := verbPattern.FindAllString(template, -1)
+	sort.Strings(found)
+	return found
+}
+
+// ========== Тесты каталогов ==========
+
+func TestCatalogs_SameKeysAndVerbs(t *testing.T) {
+	ru, en := catalogs[RU], catalogs[EN]
+	require.NotEmpty(t, ru)
+
+	for key, template := range ru {
+		translation, ok := en[key]
+		if !assert.True(t, ok, "ключа %s нет в en.json", key) {
+			continue
+		}
+		assert.Equal(t, verbs(template), verbs(translation), key)
+		assert.NotContains(t, template, "%w", key)
+	}
+	for key := range en {
+		_, ok := ru[key]
+		assert.True(t, ok, "ключа %s нет в ru.json", key)
+	}
+}
+
+// ========== Тесты Negotiate ==========
+
+func TestNegotiate(t *testing.T) {
+	cases := []struct {
+		header string
+		want   Locale
+	}{
+		{"", RU},
+		{"ru-RU,ru;q=0.9", RU},
+		{"en-US,en;q=0.9", EN},
+		{"en-GB", EN},
+		{"de-DE", RU},
+		{"de-DE,en;q=0.5", EN},
+		{"ru;q=0.3,en;q=0.8", EN},
+		{"*", RU},
+		{"не заголовок", RU},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, Negotiate(tc.header), tc.header)
+	}
+}
+
+// ========== Тесты T и Error ==========
+
+func TestT(t *testing.T) {
+	assert.Equal(t, "лот с ID 7 не найден", T(RU, "lot.not_found", 7))
+	assert.Equal(t, "lot with ID 7 not found", T(EN, "lot.not_found", 7))
+	assert.Equal(t, "неизвестная ошибка 5", T(EN, "неизвестная ошибка %d", 5))
+	assert.Equal(t, "лот с ID 7 не найден", T(Locale("de"), "lot.not_found", 7), "неизвестный язык — каталог по умолчанию")
+}
+
+func TestError_TranslatesNestedAndUnwraps(t *testing.T) {
+	sentinel := Errorf("upload.unsupported")
+	err := Errorf("upload.unsupported_extension", sentinel, ".pdf")
+
+	assert.Equal(t, `поддерживаются только файлы XLSX: расширение файла ".pdf"`, err.Error())
+	var msgErr *Error
+	require.True(t, errors.As(err, &msgErr))
+	assert.Equal(t, `only XLSX files are supported: file extension ".pdf"`, T(EN, msgErr.Key, msgErr.Args...))
+	assert.ErrorIs(t, err, sentinel)
+}
+
+// ========== Проверка вызовов в коде ==========
+
+// keyedCalls — функции, принимающие ключ сообщения, и индекс аргумента-ключа.
+var keyedCalls = map[string]int{
+	"apierrors.NewValidationError":            0,
+	"apierrors.NewNotFoundError":              0,
+	"apierrors.NewConflictError":              0,
+	"apierrors.NewValidationErrorWithDetails": 1,
+	"apierrors.NewConflictErrorf":             1,
+	"i18n.Errorf":                             0,
+	"i18n.T":                                  1,
+	"respondCode":                             3,
+	"localizedError":                          2,
+}
+
+// passthrough — шаблоны, которыми сервисы передают текст вложенной ошибки.
+var passthrough = map[string]bool{"%s": true, "%v": true}
+
+func TestCallSites_UseCatalogKeys(t *testing.T) {
+	root := filepath.Join("..", "..")
+	fset := token.NewFileSet()
+	checked := 0
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			idx, ok := keyedCalls[callName(call)]
+			if !ok || len(call.Args) <= idx {
+				return true
+			}
+			lit, ok := call.Args[idx].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+			key, _ := strconv.Unquote(lit.Value)
+			if passthrough[key] {
+				return true
+			}
+			checked++
+			assert.True(t, Has(key), "%s: ключа %q нет в каталоге", fset.Position(lit.Pos()), key)
+			return true
+		})
+		return nil
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, checked)
+}
+
+func callName(call *ast.CallExpr) string {
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		return fn.Name
+	case *ast.SelectorExpr:
+		if x, ok := fn.X.(*ast.Ident); ok {
+			return x.Name + "." + fn.Sel.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,326 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/db/postgres"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/jsonschema"
+)
+
+// Коды ошибок API (поле code). Фронтенд и интеграции ветвятся по коду, а не по
+// тексту message: текст может меняться, код — нет. Кроме общих кодов ниже
+// хендлеры отдают собственные (access_token_expired, csrf_invalid,
+// contractor_merge_conflict и т.п.), они перечислены в README.
+const (
+	CodeBadRequest           = "bad_request"
+	CodeValidationFailed     = "validation_failed"
+	CodeUnauthorized         = "unauthorized"
+	CodeForbidden            = "forbidden"
+	CodeNotFound             = "not_found"
+	CodeConflict             = "conflict"
+	CodeAlreadyExists        = "already_exists"
+	CodePayloadTooLarge      = "payload_too_large"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeRateLimited          = "rate_limited"
+	CodeInternal             = "internal_error"
+	CodeNotImplemented       = "not_implemented"
+	CodeBadGateway           = "bad_gateway"
+	CodeServiceUnavailable   = "service_unavailable"
+)
+
+// internalErrorKey — сообщение ответа 500: исходная ошибка (часто текст
+// драйвера БД) клиенту не отдаётся, она попадает в лог запроса через c.Error.
+const internalErrorKey = "request.internal_error"
+
+// APIError — тело любого ответа с ошибкой. message — текст на языке запроса
+// (Accept-Language, по умолчанию ru), message_key — ключ сообщения в каталоге
+// i18n: по нему фронтенд может показать собственный перевод.
+type APIError struct {
+	Code       string        `json:"code"`
+	Message    string        `json:"message"`
+	MessageKey string        `json:"message_key,omitempty"`
+	Details    []ErrorDetail `json:"details,omitempty"`
+	// Данные конфликта для формы его разрешения (apierrors.ConflictError.Conflicts)
+	Conflicts any `json:"conflicts,omitempty"`
+	// Совпадает с message: прежний формат {"error": "..."} на переходный период
+	Error string `json:"error"`
+}
+
+// ErrorDetail — одна подробность ошибки: нарушение по полю запроса или
+// недостающее право.
+type ErrorDetail struct {
+	Field   string `json:"field,omitempty"` // Имя поля JSON/query или JSON Pointer
+	Rule    string `json:"rule,omitempty"`  // Нарушенное правило: required, min, type, permission...
+	Value   string `json:"value,omitempty"` // Параметр правила (min=1 → "1", permission → имя права)
+	Message string `json:"message"`
+}
+
+func newAPIError(code, message string) APIError {
+	return APIError{Code: code, Message: message, Error: message}
+}
+
+// localizedError — тело ошибки с сообщением key из каталога i18n на языке locale.
+func localizedError(locale i18n.Locale, code, key string, args ...any) APIError {
+	body := newAPIError(code, i18n.T(locale, key, args...))
+	if i18n.Has(key) {
+		body.MessageKey = key
+	}
+	return body
+}
+
+// errorBody — тело ошибки err: сообщение с ключом (apierrors, i18n.Error)
+// переводится на язык locale, прочие ошибки отдаются текстом как есть.
+func errorBody(locale i18n.Locale, code string, err error) APIError {
+	key, args, ok := errorMessageKey(err)
+	if !ok {
+		return newAPIError(code, err.Error())
+	}
+	return localizedError(locale, code, key, args...)
+}
+
+// errorMessageKey возвращает ключ сообщения и аргументы первой ошибки в
+// цепочке err, созданной по ключу каталога.
+func errorMessageKey(err error) (string, []any, bool) {
+	var validationErr *apierrors.ValidationError
+	var notFoundErr *apierrors.NotFoundError
+	var conflictErr *apierrors.ConflictError
+	var msgErr *i18n.Error
+	switch {
+	case errors.As(err, &validationErr):
+		return validationErr.Key, validationErr.Args, validationErr.Key != ""
+	case errors.As(err, &notFoundErr):
+		return notFoundErr.Key, notFoundErr.Args, notFoundErr.Key != ""
+	case errors.As(err, &conflictErr):
+		return conflictErr.Key, conflictErr.Args, conflictErr.Key != ""
+	case errors.As(err, &msgErr):
+		return msgErr.Key, msgErr.Args, i18n.Has(msgErr.Key)
+	}
+	return "", nil, false
+}
+
+// requestLocale — язык сообщений ответа по заголовку Accept-Language.
+func requestLocale(c *gin.Context) i18n.Locale {
+	return i18n.Negotiate(c.GetHeader("Accept-Language"))
+}
+
+// writeError отправляет тело ошибки. Content-Language сообщает язык message,
+// Vary — что ответ зависит от Accept-Language (для кэширующих прокси).
+func writeError(c *gin.Context, status int, body APIError) {
+	c.Header("Content-Language", string(requestLocale(c)))
+	c.Writer.Header().Add("Vary", "Accept-Language")
+	c.JSON(status, body)
+}
+
+// statusCodes — код по умолчанию для статуса, который выбрал хендлер.
+var statusCodes = map[int]string{
+	http.StatusBadRequest:            CodeBadRequest,
+	http.StatusUnauthorized:          CodeUnauthorized,
+	http.StatusForbidden:             CodeForbidden,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusConflict:              CodeConflict,
+	http.StatusRequestEntityTooLarge: CodePayloadTooLarge,
+	http.StatusUnsupportedMediaType:  CodeUnsupportedMediaType,
+	http.StatusTooManyRequests:       CodeRateLimited,
+	http.StatusInternalServerError:   CodeInternal,
+	http.StatusNotImplemented:        CodeNotImplemented,
+	http.StatusBadGateway:            CodeBadGateway,
+	http.StatusServiceUnavailable:    CodeServiceUnavailable,
+}
+
+func codeForStatus(status int) string {
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
+
+// mapError — центральный маппинг ошибок сервисов в HTTP: ValidationError и
+// api_models.ValidationErrors → 400 (с подробностями), NotFoundError и sql.ErrNoRows → 404, ConflictError → 409,
+// нарушение уникальности в БД → 409 already_exists. ok=false — ошибка не
+// распознана. Сообщения переводятся на язык locale.
+func mapError(err error, locale i18n.Locale) (int, APIError, bool) {
+	var validationErr *apierrors.ValidationError
+	var notFoundErr *apierrors.NotFoundError
+	var conflictErr *apierrors.ConflictError
+	var fieldErrs api_models.ValidationErrors
+	switch {
+	case errors.As(err, &validationErr):
+		body := errorBody(locale, CodeValidationFailed, validationErr)
+		body.Details = validationDetails(validationErr.Details)
+		return http.StatusBadRequest, body, true
+	case errors.As(err, &fieldErrs):
+		body := localizedError(locale, CodeValidationFailed, "request.validation_failed", len(fieldErrs))
+		body.Details = validationDetails(fieldErrs)
+		return http.StatusBadRequest, body, true
+	case errors.As(err, &notFoundErr):
+		return http.StatusNotFound, errorBody(locale, CodeNotFound, notFoundErr), true
+	case errors.As(err, &conflictErr):
+		body := errorBody(locale, CodeConflict, conflictErr)
+		body.Conflicts = conflictErr.Conflicts
+		return http.StatusConflict, body, true
+	case postgres.IsUniqueViolation(err):
+		return http.StatusConflict, localizedError(locale, CodeAlreadyExists, "request.already_exists"), true
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound, localizedError(locale, CodeNotFound, "request.resource_not_found"), true
+	}
+	return 0, APIError{}, false
+}
+
+// respondError отвечает на ошибку сервиса по mapError; нераспознанная ошибка —
+// 500 без исходного текста.
+func respondError(c *gin.Context, err error) {
+	respondErrorCode(c, err, "")
+}
+
+// respondErrorCode — как respondError, но 409 получает код conflictCode
+// (contractor_merge_conflict, unit_conflict...), по которому фронтенд
+// открывает форму разрешения конфликта.
+func respondErrorCode(c *gin.Context, err error, conflictCode string) {
+	status, body, ok := mapError(err, requestLocale(c))
+	if !ok {
+		respondInternal(c, err)
+		return
+	}
+	if status == http.StatusConflict && conflictCode != "" {
+		body.Code = conflictCode
+	}
+	writeError(c, status, body)
+}
+
+// respondStatus отвечает статусом, который выбрал хендлер (неверный ID,
+// отсутствующий ресурс, ошибка валидации параметра). На 500 сначала
+// пробуется mapError: хендлер мог не разобрать ошибку сервиса. Ошибки с
+// ключом сообщения (i18n.Errorf, apierrors) переводятся на язык запроса.
+func respondStatus(c *gin.Context, status int, err error) {
+	if status == http.StatusInternalServerError {
+		respondError(c, err)
+		return
+	}
+	body := errorBody(requestLocale(c), codeForStatus(status), err)
+	var validationErr *apierrors.ValidationError
+	if status == http.StatusBadRequest && errors.As(err, &validationErr) {
+		body.Code = CodeValidationFailed
+		body.Details = validationDetails(validationErr.Details)
+	}
+	writeError(c, status, body)
+}
+
+// respondCode отвечает ошибкой с явным кодом (аутентификация, CSRF, лимиты)
+// и сообщением key из каталога i18n.
+func respondCode(c *gin.Context, status int, code, key string, args ...any) {
+	writeError(c, status, localizedError(requestLocale(c), code, key, args...))
+}
+
+// respondInternal отвечает 500 и сохраняет исходную ошибку в контексте
+// запроса: её печатает логгер gin вместе со строкой запроса.
+func respondInternal(c *gin.Context, err error) {
+	if err != nil {
+		_ = c.Error(err)
+	}
+	writeError(c, http.StatusInternalServerError, localizedError(requestLocale(c), CodeInternal, internalErrorKey))
+}
+
+// respondBindError отвечает 400 на ошибку ShouldBindJSON/ShouldBindQuery:
+// нарушения тегов binding — validation_failed с подробностями по полям,
+// синтаксис и типы JSON — bad_request.
+func respondBindError(c *gin.Context, err error) {
+	locale := requestLocale(c)
+	var fieldErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &fieldErrs):
+		body := localizedError(locale, CodeValidationFailed, "request.invalid_params")
+		for _, fe := range fieldErrs {
+			body.Details = append(body.Details, ErrorDetail{
+				Field:   fe.Field(),
+				Rule:    fe.Tag(),
+				Value:   fe.Param(),
+				Message: ruleMessage(locale, fe.Tag(), fe.Param()),
+			})
+		}
+		writeError(c, http.StatusBadRequest, body)
+	case errors.As(err, &typeErr):
+		body := localizedError(locale, CodeBadRequest, "request.json_type_mismatch")
+		body.Details = []ErrorDetail{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Value:   typeErr.Type.String(),
+			Message: i18n.T(locale, "validation.type_expected", typeErr.Type, typeErr.Value),
+		}}
+		writeError(c, http.StatusBadRequest, body)
+	case errors.As(err, &syntaxErr):
+		writeError(c, http.StatusBadRequest, localizedError(locale, CodeBadRequest, "request.invalid_json", syntaxErr))
+	default:
+		writeError(c, http.StatusBadRequest, localizedError(locale, CodeBadRequest, "request.invalid_request", err))
+	}
+}
+
+// ruleMessage — текст нарушения тега binding на языке locale.
+func ruleMessage(locale i18n.Locale, tag, param string) string {
+	switch tag {
+	case "required", "email", "url":
+		return i18n.T(locale, "validation."+tag)
+	case "gt", "lt", "len", "oneof":
+		return i18n.T(locale, "validation."+tag, param)
+	case "min", "gte":
+		return i18n.T(locale, "validation.min", param)
+	case "max", "lte":
+		return i18n.T(locale, "validation.max", param)
+	}
+	return i18n.T(locale, "validation.rule", tag)
+}
+
+// validationDetails приводит apierrors.ValidationError.Details к списку
+// подробностей ответа.
+func validationDetails(details any) []ErrorDetail {
+	switch d := details.(type) {
+	case []ErrorDetail:
+		return d
+	case []jsonschema.FieldError:
+		result := make([]ErrorDetail, 0, len(d))
+		for _, fe := range d {
+			result = append(result, ErrorDetail{Field: fe.Path, Rule: "schema", Message: fe.Message})
+		}
+		return result
+	case api_models.ValidationErrors:
+		result := make([]ErrorDetail, 0, len(d))
+		for _, fe := range d {
+			result = append(result, ErrorDetail{Field: fe.Path, Rule: fe.Rule, Message: fe.Message})
+		}
+		return result
+	}
+	return nil
+}
+
+// useJSONFieldNames называет поля в ошибках binding так же, как в запросе
+// (json, затем form), а не именами полей структур Go.
+func useJSONFieldNames() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+			if name != "" && name != "-" {
+				return name
+			}
+		}
+		return ""
+	})
+}
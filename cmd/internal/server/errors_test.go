@@ -0,0 +1,243 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/jsonschema"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR THE API ERROR FORMAT (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. The frontend parsing free-text (often Russian DB) messages to decide what to show
+2. Internal details (SQL, driver errors) leaking to the client in 500 responses
+3. A duplicate key surfacing as a 500 instead of a 409 the user can act on
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Central mapper
+- GIVEN ValidationError with schema violations THEN 400 validation_failed with details per field
+- GIVEN import payload ValidationErrors THEN every violation is a detail with its path and rule
+- GIVEN NotFoundError or sql.ErrNoRows (wrapped) THEN 404 not_found
+- GIVEN ConflictError THEN 409 with conflicts; a domain conflict code replaces "conflict"
+- GIVEN a unique violation from the driver (wrapped) THEN 409 already_exists
+- GIVEN any other error THEN 500 internal_error with a generic message
+
+SCENARIO 2: Request binding
+- GIVEN a body violating binding tags THEN 400 validation_failed, fields named as in JSON
+- GIVEN malformed JSON or a wrong field type THEN 400 bad_request
+
+SCENARIO 3: Handler-chosen status
+- GIVEN respondStatus(400, plain error) THEN bad_request; every body keeps "error" = message
+
+SCENARIO 4: Localization
+- GIVEN Accept-Language: en and an error created by message key
+  THEN message is in English, message_key is the catalog key
+  AND Content-Language is en, Vary includes Accept-Language
+- GIVEN no Accept-Language THEN messages stay in Russian
+- GIVEN an error built from plain text THEN it is returned as is, without message_key
+*/
+
+func recordError(t *testing.T, respond func(c *gin.Context)) (int, APIError) {
+	t.Helper()
+	status, body, _ := recordLocalizedError(t, "", respond)
+	return status, body
+}
+
+// recordLocalizedError — как recordError, но с заголовком Accept-Language;
+// возвращает и заголовки ответа.
+func recordLocalizedError(t *testing.T, acceptLanguage string, respond func(c *gin.Context)) (int, APIError, http.Header) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if acceptLanguage != "" {
+		c.Request.Header.Set("Accept-Language", acceptLanguage)
+	}
+	respond(c)
+
+	var body APIError
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, body.Message, body.Error, "legacy error field mirrors message")
+	return w.Code, body, w.Header()
+}
+
+func TestRespondError_Mapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		code    string
+		message string
+	}{
+		{"not found", fmt.Errorf("get: %w", apierrors.NewNotFoundError("лот %d не найден", 5)), http.StatusNotFound, CodeNotFound, "лот 5 не найден"},
+		{"no rows", fmt.Errorf("get tender: %w", sql.ErrNoRows), http.StatusNotFound, CodeNotFound, "ресурс не найден"},
+		{"conflict", apierrors.NewConflictError("код занят", nil), http.StatusConflict, CodeConflict, "код занят"},
+		{"unique violation", fmt.Errorf("insert unit: %w", &pq.Error{Code: "23505", Message: "повторяющееся значение ключа нарушает ограничение уникальности"}),
+			http.StatusConflict, CodeAlreadyExists, "запись с такими значениями уже существует"},
+		{"unknown", errors.New("pq: relation \"lots\" does not exist"), http.StatusInternalServerError, CodeInternal, "внутренняя ошибка сервера"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := recordError(t, func(c *gin.Context) { respondError(c, tt.err) })
+			assert.Equal(t, tt.status, status)
+			assert.Equal(t, tt.code, body.Code)
+			assert.Equal(t, tt.message, body.Message)
+		})
+	}
+}
+
+func TestRespondError_ValidationDetails(t *testing.T) {
+	err := apierrors.NewValidationErrorWithDetails(
+		[]jsonschema.FieldError{{Path: "/power_kw", Message: "ожидается number"}},
+		"ключевые параметры не соответствуют схеме")
+
+	status, body := recordError(t, func(c *gin.Context) { respondError(c, err) })
+
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, CodeValidationFailed, body.Code)
+	assert.Equal(t, []ErrorDetail{{Field: "/power_kw", Rule: "schema", Message: "ожидается number"}}, body.Details)
+}
+
+func TestRespondError_ImportValidationErrors(t *testing.T) {
+	tender := api_models.FullTenderData{TenderID: "ETP-1"}
+	err := tender.Validate()
+
+	status, body := recordError(t, func(c *gin.Context) { respondError(c, err) })
+
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, CodeValidationFailed, body.Code)
+	require.Len(t, body.Details, 5)
+	assert.Equal(t, ErrorDetail{Field: "tender_title", Rule: "required", Message: "название тендера не может быть пустым"}, body.Details[0])
+	assert.Equal(t, "lots", body.Details[4].Field)
+
+	// Потоковый импорт оборачивает нарушения лота в ValidationError
+	wrapped := apierrors.NewValidationErrorWithDetails(api_models.ValidationErrors{{Path: `lots["lot_1"].lot_title`, Rule: "required", Message: "пусто"}}, "ошибка в лоте")
+	_, body = recordError(t, func(c *gin.Context) { respondError(c, wrapped) })
+	assert.Equal(t, []ErrorDetail{{Field: `lots["lot_1"].lot_title`, Rule: "required", Message: "пусто"}}, body.Details)
+}
+
+func TestRespondErrorCode_Conflict(t *testing.T) {
+	err := apierrors.NewConflictError("у подрядчиков разные ИНН", map[string]string{"inn": "7701"})
+
+	status, body := recordError(t, func(c *gin.Context) { respondErrorCode(c, err, "contractor_merge_conflict") })
+
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Equal(t, "contractor_merge_conflict", body.Code)
+	assert.Equal(t, map[string]any{"inn": "7701"}, body.Conflicts)
+
+	// Код конфликта не меняет ответы на другие ошибки
+	_, body = recordError(t, func(c *gin.Context) {
+		respondErrorCode(c, apierrors.NewNotFoundError("подрядчик не найден"), "contractor_merge_conflict")
+	})
+	assert.Equal(t, CodeNotFound, body.Code)
+}
+
+func TestRespondBindError(t *testing.T) {
+	useJSONFieldNames()
+
+	bind := func(payload string) func(c *gin.Context) {
+		return func(c *gin.Context) {
+			c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload))
+			c.Request.Header.Set("Content-Type", "application/json")
+			var req LoginRequest
+			err := c.ShouldBindJSON(&req)
+			require.Error(t, err)
+			respondBindError(c, err)
+		}
+	}
+
+	status, body := recordError(t, bind(`{"email": "user@example.com", "password": "123"}`))
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, CodeValidationFailed, body.Code)
+	assert.Equal(t, []ErrorDetail{{Field: "password", Rule: "min", Value: "6", Message: "значение должно быть не меньше 6"}}, body.Details)
+
+	_, body = recordError(t, bind(`{"email": 42}`))
+	assert.Equal(t, CodeBadRequest, body.Code)
+	require.Len(t, body.Details, 1)
+	assert.Equal(t, "email", body.Details[0].Field)
+
+	_, body = recordError(t, bind(`{"email": `))
+	assert.Equal(t, CodeBadRequest, body.Code)
+}
+
+func TestRespondStatus(t *testing.T) {
+	status, body := recordError(t, func(c *gin.Context) {
+		respondStatus(c, http.StatusBadRequest, errors.New("параметр id должен быть целым числом > 0"))
+	})
+
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, CodeBadRequest, body.Code)
+	assert.Equal(t, "параметр id должен быть целым числом > 0", body.Message)
+	assert.Empty(t, body.Details)
+}
+
+func TestRespondError_Localized(t *testing.T) {
+	err := fmt.Errorf("get: %w", apierrors.NewNotFoundError("lot.not_found", 5))
+
+	status, body, header := recordLocalizedError(t, "en-US,en;q=0.9,ru;q=0.5", func(c *gin.Context) { respondError(c, err) })
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, "lot with ID 5 not found", body.Message)
+	assert.Equal(t, "lot.not_found", body.MessageKey)
+	assert.Equal(t, "en", header.Get("Content-Language"))
+	assert.Contains(t, header.Values("Vary"), "Accept-Language")
+
+	_, body, header = recordLocalizedError(t, "", func(c *gin.Context) { respondError(c, err) })
+	assert.Equal(t, "лот с ID 5 не найден", body.Message)
+	assert.Equal(t, "lot.not_found", body.MessageKey)
+	assert.Equal(t, "ru", header.Get("Content-Language"))
+
+	// Сообщения сервера без сервиса: 500 и ответы по статусу
+	_, body, _ = recordLocalizedError(t, "en", func(c *gin.Context) { respondError(c, errors.New("pq: timeout")) })
+	assert.Equal(t, "internal server error", body.Message)
+	_, body, _ = recordLocalizedError(t, "en", func(c *gin.Context) {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.param_positive_int", "lot_id"))
+	})
+	assert.Equal(t, "parameter lot_id must be an integer > 0", body.Message)
+	assert.Equal(t, "request.param_positive_int", body.MessageKey)
+	_, body, _ = recordLocalizedError(t, "en", func(c *gin.Context) {
+		respondCode(c, http.StatusUnauthorized, "invalid_credentials", "auth.invalid_credentials")
+	})
+	assert.Equal(t, "invalid email or password", body.Message)
+	assert.Equal(t, "invalid_credentials", body.Code)
+
+	// Текст без ключа каталога отдаётся как есть
+	_, body, _ = recordLocalizedError(t, "en", func(c *gin.Context) {
+		respondError(c, apierrors.NewConflictError("код занят", nil))
+	})
+	assert.Equal(t, "код занят", body.Message)
+	assert.Empty(t, body.MessageKey)
+}
+
+func TestRespondBindError_Localized(t *testing.T) {
+	useJSONFieldNames()
+
+	_, body, _ := recordLocalizedError(t, "en", func(c *gin.Context) {
+		c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"email": "user@example.com", "password": "123"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.Header.Set("Accept-Language", "en")
+		var req LoginRequest
+		respondBindError(c, c.ShouldBindJSON(&req))
+	})
+
+	assert.Equal(t, "invalid request parameters", body.Message)
+	assert.Equal(t, []ErrorDetail{{Field: "password", Rule: "min", Value: "6", Message: "value must be at least 6"}}, body.Details)
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"database/sql"
+	"net/url"
+	"strconv"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+)
+
+// exportBatchSize - сколько строк читается из БД за один запрос при CSV-выгрузке.
+// Выгрузка идёт порциями, чтобы не держать весь результат в памяти.
+const exportBatchSize = 500
+
+// catalogExportKinds и catalogExportStatuses - допустимые значения фильтров каталога
+// (совпадают с CHECK-ограничениями catalog_positions).
+var (
+	catalogExportKinds = map[string]struct{}{
+		"POSITION":    {},
+		"HEADER":      {},
+		"LOT_HEADER":  {},
+		"TRASH":       {},
+		"TO_REVIEW":   {},
+		"GROUP_TITLE": {},
+	}
+	catalogExportStatuses = map[string]struct{}{
+		"pending_indexing": {},
+		"active":           {},
+		"deprecated":       {},
+		"archived":         {},
+		"na":               {},
+	}
+)
+
+// parseCSVDelimiter разбирает параметр delimiter. Excel в русской локали
+// ожидает ';', поэтому помимо запятой поддерживается точка с запятой.
+func parseCSVDelimiter(values url.Values) (rune, error) {
+	switch values.Get("delimiter") {
+	case "", "comma":
+		return ',', nil
+	case "semicolon":
+		return ';', nil
+	default:
+		return 0, i18n.Errorf("request.invalid_delimiter")
+	}
+}
+
+// catalogExportQuery - разобранные фильтры GET /api/v1/catalog/export.csv.
+type catalogExportQuery struct {
+	Kind     sql.NullString
+	Status   sql.NullString
+	IsPinned sql.NullBool
+	ParentID sql.NullInt64
+}
+
+// parseCatalogExportQuery разбирает и валидирует фильтры выгрузки каталога.
+// Все фильтры опциональны.
+func parseCatalogExportQuery(values url.Values) (catalogExportQuery, error) {
+	var q catalogExportQuery
+
+	if v := values.Get("kind"); v != "" {
+		if _, ok := catalogExportKinds[v]; !ok {
+			return q, i18n.Errorf("request.invalid_kind", v)
+		}
+		q.Kind = sql.NullString{String: v, Valid: true}
+	}
+
+	if v := values.Get("status"); v != "" {
+		if _, ok := catalogExportStatuses[v]; !ok {
+			return q, i18n.Errorf("request.invalid_status", v)
+		}
+		q.Status = sql.NullString{String: v, Valid: true}
+	}
+
+	if v := values.Get("pinned"); v != "" {
+		pinned, err := strconv.ParseBool(v)
+		if err != nil {
+			return q, i18n.Errorf("request.pinned_bool")
+		}
+		q.IsPinned = sql.NullBool{Bool: pinned, Valid: true}
+	}
+
+	if v := values.Get("parent_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			return q, i18n.Errorf("request.parent_id_positive_integer")
+		}
+		q.ParentID = sql.NullInt64{Int64: id, Valid: true}
+	}
+
+	return q, nil
+}
+
+// batchParams собирает параметры ExportCatalogPositions для порции после afterID.
+func (q catalogExportQuery) batchParams(afterID int64) db.ExportCatalogPositionsParams {
+	return db.ExportCatalogPositionsParams{
+		AfterID:   afterID,
+		Kind:      q.Kind,
+		Status:    q.Status,
+		IsPinned:  q.IsPinned,
+		ParentID:  q.ParentID,
+		PageLimit: exportBatchSize,
+	}
+}
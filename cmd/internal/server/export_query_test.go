@@ -0,0 +1,97 @@
+// Purpose: Protects the query layer of the CSV export endpoints. Ensures that catalog
+// filters are validated against the DB CHECK values before any DB call, that the
+// delimiter switch only accepts known values, and that tender export reuses the list
+// filters while ignoring pagination.
+package server
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+BEHAVIORAL SCENARIOS:
+
+Given all catalog filters set
+When parseCatalogExportQuery is called
+Then every filter is Valid and batch params carry after_id and the batch size
+
+Given an unknown kind/status or a bad pinned/parent_id
+When parseCatalogExportQuery is called
+Then an error is returned
+
+Given delimiter=semicolon
+When parseCSVDelimiter is called
+Then ';' is returned; unknown values are rejected
+
+Given page/page_size in the tender list query
+When exportBatchParams is called
+Then filters are kept and pagination is replaced by the export batch
+*/
+
+func TestParseCatalogExportQuery_AllFilters(t *testing.T) {
+	q, err := parseCatalogExportQuery(url.Values{
+		"kind":      {"POSITION"},
+		"status":    {"active"},
+		"pinned":    {"true"},
+		"parent_id": {"42"},
+	})
+
+	require.NoError(t, err)
+	params := q.batchParams(100)
+	assert.Equal(t, int64(100), params.AfterID)
+	assert.Equal(t, "POSITION", params.Kind.String)
+	assert.Equal(t, "active", params.Status.String)
+	assert.True(t, params.IsPinned.Valid && params.IsPinned.Bool)
+	assert.Equal(t, int64(42), params.ParentID.Int64)
+	assert.Equal(t, int32(exportBatchSize), params.PageLimit)
+}
+
+func TestParseCatalogExportQuery_InvalidInput_ReturnsError(t *testing.T) {
+	cases := map[string]url.Values{
+		"unknown kind":   {"kind": {"position"}},
+		"unknown status": {"status": {"deleted"}},
+		"bad pinned":     {"pinned": {"yes please"}},
+		"zero parent":    {"parent_id": {"0"}},
+	}
+
+	for name, values := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseCatalogExportQuery(values)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseCSVDelimiter(t *testing.T) {
+	comma, err := parseCSVDelimiter(url.Values{})
+	require.NoError(t, err)
+	assert.Equal(t, ',', comma)
+
+	comma, err = parseCSVDelimiter(url.Values{"delimiter": {"semicolon"}})
+	require.NoError(t, err)
+	assert.Equal(t, ';', comma)
+
+	_, err = parseCSVDelimiter(url.Values{"delimiter": {"tab"}})
+	assert.Error(t, err)
+}
+
+func TestTenderListQuery_ExportBatchParams_IgnoresPagination(t *testing.T) {
+	q, err := parseTenderListQuery(url.Values{
+		"page":        {"3"},
+		"page_size":   {"20"},
+		"executor_id": {"7"},
+		"sort_by":     {"title"},
+	})
+	require.NoError(t, err)
+
+	params := q.exportBatchParams(1000)
+
+	assert.Equal(t, int32(exportBatchSize), params.PageLimit)
+	assert.Equal(t, int32(1000), params.PageOffset)
+	assert.Equal(t, int64(7), params.ExecutorID.Int64)
+	assert.Equal(t, "title", params.SortBy)
+}
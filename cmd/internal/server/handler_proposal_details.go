@@ -1,14 +1,17 @@
 package server
 
 import (
+	"context"
 	"database/sql"
 	"errors"
-	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
 	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -24,13 +27,14 @@ type SummaryLineResponse struct {
 	SummaryKey string               `json:"summary_key"`
 	JobTitle   string               `json:"job_title"`
 	TotalCost  SummaryCostBreakdown `json:"total_cost"`
+	Currency   *string              `json:"currency,omitempty"` // Только если отличается от валюты предложения
 }
 
 type SummaryCostBreakdown struct {
-	Materials     *string `json:"materials"`
-	Works         *string `json:"works"`
-	IndirectCosts *string `json:"indirect_costs"`
-	Total         *string `json:"total"`
+	Materials     *api_models.Money `json:"materials"`
+	Works         *api_models.Money `json:"works"`
+	IndirectCosts *api_models.Money `json:"indirect_costs"`
+	Total         *api_models.Money `json:"total"`
 }
 
 type ProposalMetaResponse struct {
@@ -41,6 +45,7 @@ type ProposalMetaResponse struct {
 	TenderEtpID    string `json:"tender_etp_id"`
 	LotTitle       string `json:"lot_title"`
 	IsBaseline     bool   `json:"is_baseline"`
+	Currency       string `json:"currency"` // Валюта предложения (ISO 4217)
 }
 
 type ProposalPositionItemResponse struct {
@@ -53,20 +58,44 @@ type ProposalPositionItemResponse struct {
 	Quantity      *string `json:"quantity,omitempty"`
 
 	// Новые поля детализации
-	PriceTotal    *string `json:"price_total,omitempty"`    // unit_cost_total
-	CostTotal     *string `json:"cost_total,omitempty"`     // total_cost_total
-	CostMaterials *string `json:"cost_materials,omitempty"` // total_cost_materials
-	CostWorks     *string `json:"cost_works,omitempty"`     // total_cost_works
+	PriceTotal    *api_models.Money `json:"price_total,omitempty"`    // unit_cost_total
+	CostTotal     *api_models.Money `json:"cost_total,omitempty"`     // total_cost_total
+	CostMaterials *api_models.Money `json:"cost_materials,omitempty"` // total_cost_materials
+	CostWorks     *api_models.Money `json:"cost_works,omitempty"`     // total_cost_works
 
 	CommentContractor *string `json:"comment_contractor,omitempty"`
 	CatalogName       *string `json:"catalog_name,omitempty"`
+	Currency          *string `json:"currency,omitempty"` // Только если отличается от валюты предложения
+
+	ChildrenCount *int64 `json:"children_count,omitempty"` // Только view=tree, у глав: строк для догрузки
+}
+
+// ProposalPositionsResponse - страница строк КП (GET /api/v1/proposals/:id/positions).
+type ProposalPositionsResponse struct {
+	ProposalID int64                          `json:"proposal_id"`
+	View       string                         `json:"view"`              // flat | tree
+	Chapter    string                         `json:"chapter,omitempty"` // view=tree: чьи строки; пусто - корень
+	Items      []ProposalPositionItemResponse `json:"items"`
+	Total      int64                          `json:"total"`
+	Limit      int32                          `json:"limit"`
+	Offset     int32                          `json:"offset"`
 }
 
 // GET /api/v1/proposals/:id/details
+// Поддерживает If-None-Match: ответ 304, если данные предложения не изменились.
 func (s *Server) getProposalFullDetailsHandler(c *gin.Context) {
 	proposalID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный ID предложения")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_proposal_id"))
+		return
+	}
+
+	cacheControl := s.config.HTTPCache.ProposalDetails
+	etag, notModified := s.checkNotModified(c, "proposal_details", proposalID, cacheControl, func(ctx context.Context) (cacheVersion, error) {
+		row, err := s.store.GetProposalCacheVersion(ctx, proposalID)
+		return cacheVersion{UpdatedAt: row.VersionAt, RowsCount: row.RowsCount}, err
+	})
+	if notModified {
 		return
 	}
 
@@ -125,10 +154,10 @@ func (s *Server) getProposalFullDetailsHandler(c *gin.Context) {
 
 	if err := g.Wait(); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("предложение не найдено")))
+			respondStatus(c, http.StatusNotFound, i18n.Errorf("tender.proposal_not_found"))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
@@ -143,100 +172,26 @@ func (s *Server) getProposalFullDetailsHandler(c *gin.Context) {
 
 	apiPositions := make([]ProposalPositionItemResponse, len(dbPositions))
 	for i, p := range dbPositions {
-		// Подготовка указателей для Nullable полей
-		var itemNum, chapterNum, unitName, qty, price, cost, catName, costMat, costWorks, comment *string
-
-		// Базовые поля
-		if p.ItemNumberInProposal.Valid {
-			itemNum = &p.ItemNumberInProposal.String
-		}
-		if p.ChapterNumberInProposal.Valid {
-			chapterNum = &p.ChapterNumberInProposal.String
-		}
-		if p.UnitName.Valid {
-			unitName = &p.UnitName.String
-		}
-		if p.Quantity.Valid {
-			q := p.Quantity.String
-			qty = &q
-		}
-		if p.UnitCostTotal.Valid {
-			pr := p.UnitCostTotal.String
-			price = &pr
-		}
-		if p.TotalCostTotal.Valid {
-			co := p.TotalCostTotal.String
-			cost = &co
-		}
-		if p.CatalogName.Valid {
-			catName = &p.CatalogName.String
-		}
-
-		// ИСПРАВЛЕНИЕ 3: Маппинг новых полей (материалы, работы, комментарии)
-		// Убедись, что sqlc сгенерировал именно такие имена полей (обычно CamelCase от snake_case в SQL)
-		if p.TotalCostMaterials.Valid {
-			cm := p.TotalCostMaterials.String
-			costMat = &cm
-		}
-		if p.TotalCostWorks.Valid {
-			cw := p.TotalCostWorks.String
-			costWorks = &cw
-		}
-		if p.CommentContractor.Valid {
-			cmt := p.CommentContractor.String
-			comment = &cmt
-		}
-
-		apiPositions[i] = ProposalPositionItemResponse{
-			ID:            p.ID,
-			Number:        itemNum,
-			ChapterNumber: chapterNum,
-			Title:         p.JobTitleInProposal,
-			IsChapter:     p.IsChapter,
-			UnitName:      unitName,
-			Quantity:      qty,
-			// ИСПРАВЛЕНИЕ 4: Используем правильные имена полей структуры (PriceTotal, а не Price)
-			PriceTotal:        price,
-			CostTotal:         cost,
-			CostMaterials:     costMat,
-			CostWorks:         costWorks,
-			CommentContractor: comment,
-			CatalogName:       catName,
-		}
+		apiPositions[i] = toProposalPositionItemResponse(p)
 	}
 
 	// Маппинг summaries в API response структуру
 	apiSummaries := make([]SummaryLineResponse, len(summaries))
 	for i, summ := range summaries {
-		var materials, works, indirectCosts, total *string
-
-		if summ.MaterialsCost.Valid {
-			val := summ.MaterialsCost.String
-			materials = &val
-		}
-		if summ.WorksCost.Valid {
-			val := summ.WorksCost.String
-			works = &val
-		}
-		if summ.IndirectCostsCost.Valid {
-			val := summ.IndirectCostsCost.String
-			indirectCosts = &val
-		}
-		if summ.TotalCost.Valid {
-			val := summ.TotalCost.String
-			total = &val
-		}
-
 		apiSummaries[i] = SummaryLineResponse{
 			SummaryKey: summ.SummaryKey,
 			JobTitle:   summ.JobTitle,
 			TotalCost: SummaryCostBreakdown{
-				Materials:     materials,
-				Works:         works,
-				IndirectCosts: indirectCosts,
-				Total:         total,
+				Materials:     api_models.MoneyFromNullString(summ.MaterialsCost),
+				Works:         api_models.MoneyFromNullString(summ.WorksCost),
+				IndirectCosts: api_models.MoneyFromNullString(summ.IndirectCostsCost),
+				Total:         api_models.MoneyFromNullString(summ.TotalCost),
 			},
 		}
+		if summ.Currency.Valid {
+			cur := summ.Currency.String
+			apiSummaries[i].Currency = &cur
+		}
 	}
 
 	response := ProposalFullDetailsResponse{
@@ -248,11 +203,148 @@ func (s *Server) getProposalFullDetailsHandler(c *gin.Context) {
 			TenderEtpID:    meta.TenderEtpID,
 			LotTitle:       meta.LotTitle,
 			IsBaseline:     meta.IsBaseline,
+			Currency:       meta.Currency,
 		},
 		Summaries: apiSummaries,
 		Info:      infoMap,
 		Positions: apiPositions,
 	}
 
+	setCacheHeaders(c, etag, cacheControl)
 	c.JSON(http.StatusOK, response)
 }
+
+// toProposalPositionItemResponse переводит строку КП в ответ API.
+func toProposalPositionItemResponse(p db.ListPositionsForEstimateRow) ProposalPositionItemResponse {
+	// Подготовка указателей для Nullable полей
+	var itemNum, chapterNum, unitName, qty, catName, comment, currency *string
+
+	// Базовые поля
+	if p.ItemNumberInProposal.Valid {
+		itemNum = &p.ItemNumberInProposal.String
+	}
+	if p.ChapterNumberInProposal.Valid {
+		chapterNum = &p.ChapterNumberInProposal.String
+	}
+	if p.UnitName.Valid {
+		unitName = &p.UnitName.String
+	}
+	if p.Quantity.Valid {
+		q := p.Quantity.String
+		qty = &q
+	}
+	if p.CatalogName.Valid {
+		catName = &p.CatalogName.String
+	}
+	if p.CommentContractor.Valid {
+		cmt := p.CommentContractor.String
+		comment = &cmt
+	}
+	if p.Currency.Valid {
+		cur := p.Currency.String
+		currency = &cur
+	}
+
+	return ProposalPositionItemResponse{
+		ID:                p.ID,
+		Number:            itemNum,
+		ChapterNumber:     chapterNum,
+		Title:             p.JobTitleInProposal,
+		IsChapter:         p.IsChapter,
+		UnitName:          unitName,
+		Quantity:          qty,
+		PriceTotal:        api_models.MoneyFromNullString(p.UnitCostTotal),
+		CostTotal:         api_models.MoneyFromNullString(p.TotalCostTotal),
+		CostMaterials:     api_models.MoneyFromNullString(p.TotalCostMaterials),
+		CostWorks:         api_models.MoneyFromNullString(p.TotalCostWorks),
+		CommentContractor: comment,
+		CatalogName:       catName,
+		Currency:          currency,
+	}
+}
+
+// GET /api/v1/proposals/:id/positions
+// Строки КП постранично: view=flat (по умолчанию) - подряд, как в /details;
+// view=tree - непосредственные дочерние строки главы chapter (без chapter -
+// корня), у глав children_count. Фронтенд раскрывает главу запросом
+// view=tree&chapter=<номер главы>.
+func (s *Server) listProposalPositionsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "listProposalPositionsHandler")
+
+	proposalID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_proposal_id"))
+		return
+	}
+	q, err := parseProposalPositionsQuery(proposalID, c.Request.URL.Query())
+	if err != nil {
+		respondStatus(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var (
+		rows  []db.ListProposalPositionsPageRow
+		total int64
+	)
+	g, ctx := errgroup.WithContext(c.Request.Context())
+	g.Go(func() error {
+		var err error
+		rows, err = s.store.ListProposalPositionsPage(ctx, q.listParams())
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		total, err = s.store.CountProposalPositions(ctx, q.countParams())
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		logger.Errorf("Ошибка получения позиций предложения %d: %v", proposalID, err)
+		respondError(c, err)
+		return
+	}
+
+	items := make([]ProposalPositionItemResponse, len(rows))
+	var chapterRefs []string
+	for i, row := range rows {
+		items[i] = toProposalPositionItemResponse(db.ListPositionsForEstimateRow(row))
+		if q.View == proposalPositionsViewTree && row.IsChapter {
+			if ref := strings.TrimSpace(row.ItemNumberInProposal.String); ref != "" {
+				chapterRefs = append(chapterRefs, ref)
+			}
+		}
+	}
+
+	if len(chapterRefs) > 0 {
+		counts, err := s.store.CountChapterChildren(c.Request.Context(), db.CountChapterChildrenParams{
+			ProposalID:  proposalID,
+			ChapterRefs: chapterRefs,
+		})
+		if err != nil {
+			logger.Errorf("Ошибка CountChapterChildren(proposal_id=%d): %v", proposalID, err)
+			respondError(c, err)
+			return
+		}
+		byRef := make(map[string]int64, len(counts))
+		for _, row := range counts {
+			byRef[row.ChapterRef] = row.ChildrenCount
+		}
+		for i, row := range rows {
+			ref := strings.TrimSpace(row.ItemNumberInProposal.String)
+			if !row.IsChapter || ref == "" {
+				continue
+			}
+			count := byRef[ref]
+			items[i].ChildrenCount = &count
+		}
+	}
+
+	c.JSON(http.StatusOK, ProposalPositionsResponse{
+		ProposalID: proposalID,
+		View:       q.View,
+		Chapter:    q.Chapter,
+		Items:      items,
+		Total:      total,
+		Limit:      q.Limit,
+		Offset:     q.Offset,
+	})
+}
@@ -10,23 +10,272 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
 )
 
 // listUsersHandler обрабатывает GET /api/v1/admin/users
 // Список всех пользователей (только для admin)
 func (s *Server) listUsersHandler(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{
-		"error": "not implemented yet",
-	})
+	respondCode(c, http.StatusNotImplemented, CodeNotImplemented, "request.not_implemented")
 }
 
-// updateUserRoleHandler обрабатывает PATCH /api/v1/admin/users/:id/role
-// Изменение роли пользователя (только для admin)
+// createUserHandler обрабатывает POST /api/v1/admin/users.
+//
+// Создает активного пользователя с паролем и ролью.
+//
+// Request:  CreateUserRequest
+// Response: 201 + AdminUserResponse
+// Errors:   400 (валидация), 409 (email занят), 500 (БД)
+func (s *Server) createUserHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "createUserHandler")
+
+	actorID, ok := s.adminActorID(c, logger)
+	if !ok {
+		return
+	}
+
+	var req api_models.CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := s.authService.CreateUser(c.Request.Context(), actorID, req)
+	if err != nil {
+		logger.Errorf("Ошибка CreateUser: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// updateUserHandler обрабатывает PATCH /api/v1/admin/users/:id.
+//
+// Меняет email, статус активности и/или организацию и завершает все сессии пользователя.
+//
+// Request:  UpdateUserRequest
+// Response: 200 + AdminUserResponse
+// Errors:   400 (валидация, деактивация себя), 404 (нет пользователя), 409 (email занят), 500 (БД)
+func (s *Server) updateUserHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "updateUserHandler")
+
+	userID, actorID, ok := s.parseAdminUserRequest(c, logger)
+	if !ok {
+		return
+	}
+
+	var req api_models.UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := s.authService.UpdateUser(c.Request.Context(), actorID, userID, req)
+	if err != nil {
+		logger.Errorf("Ошибка UpdateUser: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// deleteUserHandler обрабатывает DELETE /api/v1/admin/users/:id.
+//
+// Отключает учетную запись: пользователь деактивируется, все сессии и
+// access-токены отзываются. Запись сохраняется для аудита.
+//
+// Response: 200 + AdminUserResponse
+// Errors:   400 (удаление себя), 404 (нет пользователя), 500 (БД)
+func (s *Server) deleteUserHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "deleteUserHandler")
+
+	userID, actorID, ok := s.parseAdminUserRequest(c, logger)
+	if !ok {
+		return
+	}
+
+	result, err := s.authService.DeleteUser(c.Request.Context(), actorID, userID)
+	if err != nil {
+		logger.Errorf("Ошибка DeleteUser: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// resetUserPasswordHandler обрабатывает POST /api/v1/admin/users/:id/reset-password.
+//
+// Выдает одноразовый токен сброса пароля (срок — auth.password_reset_ttl).
+// Администратор передает токен пользователю, тот задает новый пароль через
+// POST /api/v1/auth/reset-password.
+//
+// Response: 201 + PasswordResetTokenResponse
+// Errors:   400 (валидация), 404 (нет пользователя), 500 (БД)
+func (s *Server) resetUserPasswordHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "resetUserPasswordHandler")
+
+	userID, actorID, ok := s.parseAdminUserRequest(c, logger)
+	if !ok {
+		return
+	}
+
+	result, err := s.authService.IssuePasswordReset(c.Request.Context(), actorID, userID)
+	if err != nil {
+		logger.Errorf("Ошибка IssuePasswordReset: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, result)
+}
+
+// updateUserRoleHandler обрабатывает PATCH /api/v1/admin/users/:id/role.
+//
+// Меняет роль пользователя и завершает все его сессии; выданные ранее
+// access-токены перестают приниматься сразу.
+//
+// Request:  UpdateUserRoleRequest
+// Response: 200 + AdminUserResponse
+// Errors:   400 (валидация, своя роль), 404 (нет пользователя), 500 (БД)
 func (s *Server) updateUserRoleHandler(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{
-		"error": "not implemented yet",
-	})
+	logger := s.logger.WithField("handler", "updateUserRoleHandler")
+
+	userID, actorID, ok := s.parseAdminUserRequest(c, logger)
+	if !ok {
+		return
+	}
+
+	var req api_models.UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := s.authService.ChangeUserRole(c.Request.Context(), actorID, userID, req.Role)
+	if err != nil {
+		logger.Errorf("Ошибка ChangeUserRole: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// updateUserStatusHandler обрабатывает PATCH /api/v1/admin/users/:id/status.
+//
+// Активирует или деактивирует пользователя и завершает все его сессии.
+//
+// Request:  UpdateUserStatusRequest
+// Response: 200 + AdminUserResponse
+// Errors:   400 (валидация, деактивация себя), 404 (нет пользователя), 500 (БД)
+func (s *Server) updateUserStatusHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "updateUserStatusHandler")
+
+	userID, actorID, ok := s.parseAdminUserRequest(c, logger)
+	if !ok {
+		return
+	}
+
+	var req api_models.UpdateUserStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := s.authService.SetUserActive(c.Request.Context(), actorID, userID, *req.IsActive)
+	if err != nil {
+		logger.Errorf("Ошибка SetUserActive: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// listOrganizationsHandler обрабатывает GET /api/v1/admin/organizations.
+//
+// Response: 200 + []OrganizationResponse
+// Errors:   500 (БД)
+func (s *Server) listOrganizationsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "listOrganizationsHandler")
+
+	result, err := s.authService.ListOrganizations(c.Request.Context())
+	if err != nil {
+		logger.Errorf("Ошибка ListOrganizations: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// createOrganizationHandler обрабатывает POST /api/v1/admin/organizations.
+//
+// Request:  CreateOrganizationRequest
+// Response: 201 + OrganizationResponse
+// Errors:   400 (валидация), 409 (название занято), 500 (БД)
+func (s *Server) createOrganizationHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "createOrganizationHandler")
+
+	actorID, ok := s.adminActorID(c, logger)
+	if !ok {
+		return
+	}
+
+	var req api_models.CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := s.authService.CreateOrganization(c.Request.Context(), actorID, req)
+	if err != nil {
+		logger.Errorf("Ошибка CreateOrganization: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// parseAdminUserRequest извлекает :id пользователя и id администратора из JWT-контекста.
+// При ошибке ответ уже отправлен и возвращается ok=false.
+func (s *Server) parseAdminUserRequest(c *gin.Context, logger logging.Logger) (userID, actorID int64, ok bool) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || userID <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return 0, 0, false
+	}
+
+	actorID, ok = s.adminActorID(c, logger)
+	if !ok {
+		return 0, 0, false
+	}
+	return userID, actorID, true
+}
+
+// adminActorID извлекает id администратора из JWT-контекста.
+// При ошибке ответ уже отправлен и возвращается ok=false.
+func (s *Server) adminActorID(c *gin.Context, logger logging.Logger) (int64, bool) {
+	value, exists := c.Get("user_id")
+	if !exists {
+		logger.Errorf("user_id отсутствует в контексте")
+		respondStatus(c, http.StatusUnauthorized, i18n.Errorf("auth.not_authenticated"))
+		return 0, false
+	}
+	actorID, isInt := value.(int64)
+	if !isInt {
+		logger.Errorf("user_id имеет неожиданный тип: %T", value)
+		respondInternal(c, fmt.Errorf("invalid user_id type"))
+		return 0, false
+	}
+	return actorID, true
 }
 
 // HandleUpdateSystemSetting обрабатывает PUT /api/v1/admin/settings.
@@ -45,7 +294,7 @@ func (s *Server) HandleUpdateSystemSetting(c *gin.Context) {
 	body, err := c.GetRawData()
 	if err != nil {
 		logger.Errorf("Ошибка чтения тела запроса: %v", err)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("ошибка чтения тела запроса: %v", err)))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.body_read_failed", err))
 		return
 	}
 
@@ -54,7 +303,7 @@ func (s *Server) HandleUpdateSystemSetting(c *gin.Context) {
 	decoder.DisallowUnknownFields()
 	if err := decoder.Decode(&req); err != nil {
 		logger.Errorf("Ошибка парсинга JSON: %v", err)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("некорректный JSON: %v", err)))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_json", err))
 		return
 	}
 
@@ -62,13 +311,13 @@ func (s *Server) HandleUpdateSystemSetting(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
 		logger.Errorf("user_id отсутствует в контексте")
-		c.JSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("user not authenticated")))
+		respondStatus(c, http.StatusUnauthorized, i18n.Errorf("auth.not_authenticated"))
 		return
 	}
 	uid, ok := userID.(int64)
 	if !ok {
 		logger.Errorf("user_id имеет неожиданный тип: %T", userID)
-		c.JSON(http.StatusInternalServerError, errorResponse(fmt.Errorf("invalid user_id type")))
+		respondInternal(c, fmt.Errorf("invalid user_id type"))
 		return
 	}
 	updatedBy := strconv.FormatInt(uid, 10)
@@ -78,16 +327,7 @@ func (s *Server) HandleUpdateSystemSetting(c *gin.Context) {
 	if err != nil {
 		logger.Errorf("Ошибка UpdateSetting: %v", err)
 
-		var validationErr *apierrors.ValidationError
-		var notFoundErr *apierrors.NotFoundError
-		switch {
-		case errors.As(err, &validationErr):
-			c.JSON(http.StatusBadRequest, errorResponse(err))
-		case errors.As(err, &notFoundErr):
-			c.JSON(http.StatusNotFound, errorResponse(err))
-		default:
-			c.JSON(http.StatusInternalServerError, errorResponse(err))
-		}
+		respondError(c, err)
 		return
 	}
 
@@ -102,7 +342,7 @@ func (s *Server) HandleListSystemSettings(c *gin.Context) {
 	settings, err := s.settingsService.ListSettings(c.Request.Context())
 	if err != nil {
 		logger.Errorf("Ошибка ListSettings: %v", err)
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
@@ -116,7 +356,7 @@ func (s *Server) HandleGetSystemSetting(c *gin.Context) {
 
 	key := c.Param("key")
 	if key == "" {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("параметр key обязателен")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.key_required"))
 		return
 	}
 
@@ -124,19 +364,14 @@ func (s *Server) HandleGetSystemSetting(c *gin.Context) {
 	if err != nil {
 		logger.Errorf("Ошибка GetSetting(%s): %v", key, err)
 
-		var notFoundErr *apierrors.NotFoundError
-		if errors.As(err, &notFoundErr) {
-			c.JSON(http.StatusNotFound, errorResponse(err))
-		} else {
-			c.JSON(http.StatusInternalServerError, errorResponse(err))
-		}
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, setting)
 }
 
-// ListSuggestedMergesHandler обрабатывает GET /api/v1/admin/suggested_merges.
+// ListSuggestedMergesHandler обрабатывает GET /api/v1/admin/suggested_merges (и GET /api/v1/admin/merges).
 // Возвращает список PENDING merge-предложений, сгруппированных по main_position_id.
 //
 // Query-параметры:
@@ -154,14 +389,14 @@ func (s *Server) ListSuggestedMergesHandler(c *gin.Context) {
 	page, err := strconv.ParseInt(pageStr, 10, 32)
 	if err != nil {
 		logger.Errorf("Некорректное значение page: %s", pageStr)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("параметр page должен быть целым числом")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.page_int"))
 		return
 	}
 
 	pageSize, err := strconv.ParseInt(pageSizeStr, 10, 32)
 	if err != nil {
 		logger.Errorf("Некорректное значение page_size: %s", pageSizeStr)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("параметр page_size должен быть целым числом")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.page_size_int"))
 		return
 	}
 
@@ -169,14 +404,351 @@ func (s *Server) ListSuggestedMergesHandler(c *gin.Context) {
 	if err != nil {
 		logger.Errorf("Ошибка ListPendingMerges: %v", err)
 
-		var validationErr *apierrors.ValidationError
-		if errors.As(err, &validationErr) {
-			c.JSON(http.StatusBadRequest, errorResponse(err))
-		} else {
-			c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// HandleListServiceCredentials обрабатывает GET /api/v1/admin/service-credentials.
+// Возвращает все ключи внутренних сервисов (без самих ключей и хешей).
+func (s *Server) HandleListServiceCredentials(c *gin.Context) {
+	logger := s.logger.WithField("handler", "HandleListServiceCredentials")
+
+	creds, err := s.serviceCreds.ListCredentials(c.Request.Context())
+	if err != nil {
+		logger.Errorf("Ошибка ListCredentials: %v", err)
+		respondInternal(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, creds)
+}
+
+// HandleCreateServiceCredential обрабатывает POST /api/v1/admin/service-credentials.
+//
+// Выпускает новый ключ для внутреннего сервиса. Ключ возвращается в ответе
+// один раз и сразу принимается ServiceBearerAuthMiddleware — перезапуск не нужен.
+//
+// Request:  CreateServiceCredentialRequest (strict JSON: DisallowUnknownFields)
+// Response: 201 + CreateServiceCredentialResponse
+func (s *Server) HandleCreateServiceCredential(c *gin.Context) {
+	logger := s.logger.WithField("handler", "HandleCreateServiceCredential")
+
+	body, err := c.GetRawData()
+	if err != nil {
+		logger.Errorf("Ошибка чтения тела запроса: %v", err)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.body_read_failed", err))
+		return
+	}
+
+	var req api_models.CreateServiceCredentialRequest
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		logger.Errorf("Ошибка парсинга JSON: %v", err)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_json", err))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		logger.Errorf("user_id отсутствует в контексте")
+		respondStatus(c, http.StatusUnauthorized, i18n.Errorf("auth.not_authenticated"))
+		return
+	}
+	uid, ok := userID.(int64)
+	if !ok {
+		logger.Errorf("user_id имеет неожиданный тип: %T", userID)
+		respondInternal(c, fmt.Errorf("invalid user_id type"))
+		return
+	}
+	createdBy := strconv.FormatInt(uid, 10)
+
+	result, err := s.serviceCreds.CreateCredential(c.Request.Context(), req, createdBy)
+	if err != nil {
+		logger.Errorf("Ошибка CreateCredential: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// HandleRevokeServiceCredential обрабатывает DELETE /api/v1/admin/service-credentials/:id.
+// Отзывает ключ; на текущем инстансе он перестаёт приниматься сразу,
+// на остальных — после ближайшего обновления кэша.
+func (s *Server) HandleRevokeServiceCredential(c *gin.Context) {
+	logger := s.logger.WithField("handler", "HandleRevokeServiceCredential")
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		logger.Errorf("Некорректный ID ключа: %s", idStr)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		logger.Errorf("user_id отсутствует в контексте")
+		respondStatus(c, http.StatusUnauthorized, i18n.Errorf("auth.not_authenticated"))
+		return
+	}
+	uid, ok := userID.(int64)
+	if !ok {
+		logger.Errorf("user_id имеет неожиданный тип: %T", userID)
+		respondInternal(c, fmt.Errorf("invalid user_id type"))
+		return
+	}
+	revokedBy := strconv.FormatInt(uid, 10)
+
+	result, err := s.serviceCreds.RevokeCredential(c.Request.Context(), id, revokedBy)
+	if err != nil {
+		logger.Errorf("Ошибка RevokeCredential(id=%d): %v", id, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ActivateCatalogPositionsHandler обрабатывает POST /api/v1/admin/catalog/activate.
+// Массовая активация позиций каталога с отчётом о пропущенных ID.
+//
+// Query-параметры:
+//   - dry_run (bool, default false): только проверить, ничего не меняя
+//
+// Body: {"catalog_ids": [1, 2, 3]}
+//
+// Response: 200 + CatalogActivationReport
+// Errors:   400 (невалидное тело или dry_run), 500 (ошибка БД)
+func (s *Server) ActivateCatalogPositionsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "ActivateCatalogPositionsHandler")
+
+	dryRun := false
+	if v := c.Query("dry_run"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.dry_run_bool"))
+			return
+		}
+		dryRun = parsed
+	}
+
+	var req api_models.CatalogIndexedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	var (
+		report *api_models.CatalogActivationReport
+		err    error
+	)
+	if dryRun {
+		report, err = s.catalogService.ValidateCatalogActivation(c.Request.Context(), req.CatalogIDs)
+	} else {
+		report, err = s.catalogService.MarkCatalogItemsAsActive(c.Request.Context(), req.CatalogIDs)
+	}
+	if err != nil {
+		logger.Errorf("Ошибка активации каталога (dry_run=%t): %v", dryRun, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// HandleRotateServiceCredential обрабатывает POST /api/v1/admin/service-credentials/:id/rotate.
+//
+// Выпускает ключ-преемник (тот же воркер и scopes) и отзывает старый в одной
+// транзакции. Новый ключ возвращается в ответе один раз.
+//
+// Response: 201 + CreateServiceCredentialResponse
+// Errors:   400 (id), 404 (ключ не найден или уже отозван), 500 (БД)
+func (s *Server) HandleRotateServiceCredential(c *gin.Context) {
+	logger := s.logger.WithField("handler", "HandleRotateServiceCredential")
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		logger.Errorf("Некорректный ID ключа: %s", idStr)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return
+	}
+
+	uid, ok := s.adminActorID(c, logger)
+	if !ok {
+		return
+	}
+	rotatedBy := strconv.FormatInt(uid, 10)
+
+	result, err := s.serviceCreds.RotateCredential(c.Request.Context(), id, rotatedBy)
+	if err != nil {
+		logger.Errorf("Ошибка RotateCredential(id=%d): %v", id, err)
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, result)
+}
+
+// HandleListJobs обрабатывает GET /api/v1/admin/jobs.
+// Возвращает фоновые задачи планировщика этого экземпляра API с результатом
+// последнего запуска.
+func (s *Server) HandleListJobs(c *gin.Context) {
+	c.JSON(http.StatusOK, s.scheduler.Statuses())
+}
+
+// GetNormVersionHandler обрабатывает GET /api/v1/admin/catalog/norm-version
+// и GET /internal/worker/norm-version.
+// Возвращает активную версию нормализации и ход последней перенормализации.
+//
+// Response: 200 + NormVersionResponse
+// Errors:   500 (БД)
+func (s *Server) GetNormVersionHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "GetNormVersionHandler")
+
+	resp, err := s.catalogService.GetNormVersion(c.Request.Context())
+	if err != nil {
+		logger.Errorf("Ошибка GetNormVersion: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// BumpNormVersionHandler обрабатывает POST /api/v1/admin/catalog/norm-version/bump.
+//
+// Повышает norm_version после смены правил лемматизации: кэш старых версий
+// удаляется, активные позиции каталога порциями возвращаются на переиндексацию
+// фоновой задачей catalog_renormalization.
+//
+// Response: 202 + CatalogRenormalizationRunResponse
+// Errors:   400 (версия достигла максимума), 409 (перенормализация уже идёт), 500 (БД)
+func (s *Server) BumpNormVersionHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "BumpNormVersionHandler")
+
+	actorID, ok := s.adminActorID(c, logger)
+	if !ok {
+		return
+	}
+
+	run, err := s.catalogService.BumpNormVersion(c.Request.Context(), actorID)
+	if err != nil {
+		logger.Errorf("Ошибка BumpNormVersion: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, run)
+}
+
+// listKeyParameterSchemasHandler - GET /api/v1/admin/key-parameter-schemas.
+// Схемы ключевых параметров лота всех категорий тендеров.
+func (s *Server) listKeyParameterSchemasHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "listKeyParameterSchemasHandler")
+
+	schemas, err := s.lotService.ListKeyParameterSchemas(c.Request.Context())
+	if err != nil {
+		logger.Errorf("Ошибка ListKeyParameterSchemas: %v", err)
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, schemas)
+}
+
+// getKeyParameterSchemaHandler - GET /api/v1/admin/key-parameter-schemas/:categoryId.
+func (s *Server) getKeyParameterSchemaHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "getKeyParameterSchemaHandler")
+
+	categoryID, ok := parseCategoryIDParam(c)
+	if !ok {
+		return
+	}
+
+	schema, err := s.lotService.GetKeyParameterSchema(c.Request.Context(), categoryID)
+	if err != nil {
+		var notFoundErr *apierrors.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			respondStatus(c, http.StatusNotFound, err)
+			return
 		}
+		logger.Errorf("Ошибка GetKeyParameterSchema(category_id=%d): %v", categoryID, err)
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, schema)
+}
+
+// putKeyParameterSchemaHandler - PUT /api/v1/admin/key-parameter-schemas/:categoryId.
+// Тело — JSON Schema (подмножество из cmd/pkg/jsonschema). Неподдерживаемые
+// ключевые слова отклоняются с 400, несуществующая категория — 404.
+func (s *Server) putKeyParameterSchemaHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "putKeyParameterSchemaHandler")
+
+	categoryID, ok := parseCategoryIDParam(c)
+	if !ok {
 		return
 	}
 
+	var schema json.RawMessage
+	if err := c.ShouldBindJSON(&schema); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := s.lotService.PutKeyParameterSchema(c.Request.Context(), categoryID, schema)
+	if err != nil {
+		var validationErr *apierrors.ValidationError
+		var notFoundErr *apierrors.NotFoundError
+		switch {
+		case errors.As(err, &validationErr):
+			respondStatus(c, http.StatusBadRequest, err)
+		case errors.As(err, &notFoundErr):
+			respondStatus(c, http.StatusNotFound, err)
+		default:
+			logger.Errorf("Ошибка PutKeyParameterSchema(category_id=%d): %v", categoryID, err)
+			respondError(c, err)
+		}
+		return
+	}
 	c.JSON(http.StatusOK, result)
 }
+
+// deleteKeyParameterSchemaHandler - DELETE /api/v1/admin/key-parameter-schemas/:categoryId.
+// После удаления параметры лотов категории не проверяются.
+func (s *Server) deleteKeyParameterSchemaHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "deleteKeyParameterSchemaHandler")
+
+	categoryID, ok := parseCategoryIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := s.lotService.DeleteKeyParameterSchema(c.Request.Context(), categoryID); err != nil {
+		var notFoundErr *apierrors.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			respondStatus(c, http.StatusNotFound, err)
+			return
+		}
+		logger.Errorf("Ошибка DeleteKeyParameterSchema(category_id=%d): %v", categoryID, err)
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// parseCategoryIDParam читает :categoryId; при ошибке отвечает 400.
+func parseCategoryIDParam(c *gin.Context) (int64, bool) {
+	categoryID, err := strconv.ParseInt(c.Param("categoryId"), 10, 64)
+	if err != nil || categoryID <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.category_id_camel_positive"))
+		return 0, false
+	}
+	return categoryID, true
+}
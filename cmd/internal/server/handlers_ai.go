@@ -2,13 +2,15 @@ package server
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/webhooks"
 )
 
 // SimpleLotAIResultsHandler — упрощенный обработчик AI результатов только по lot_id.
@@ -18,11 +20,13 @@ import (
 //  1. Извлекает lot_id из URL параметра.
 //  2. Принимает JSON с результатами AI обработки в теле запроса.
 //  3. Валидирует входящие данные.
-//  4. Обновляет lot_key_parameters напрямую по lot_id без проверки tender_id.
+//  4. Сохраняет запуск в ai_analysis_results (модель, версия промпта, сырой ответ)
+//     и обновляет lot_key_parameters напрямую по lot_id без проверки tender_id.
 //
 // Возможные ответы:
 //   - 200 OK — успешное обновление
-//   - 400 Bad Request — невалидный JSON или провал валидации
+//   - 400 Bad Request — невалидный JSON, провал валидации или нарушение схемы
+//     ключевых параметров категории тендера (нарушения по полям — в details)
 //   - 404 Not Found — лот не найден
 //   - 500 Internal Server Error — ошибка бизнес-логики/БД
 func (s *Server) SimpleLotAIResultsHandler(c *gin.Context) {
@@ -34,7 +38,7 @@ func (s *Server) SimpleLotAIResultsHandler(c *gin.Context) {
 
 	if strings.TrimSpace(lotID) == "" {
 		logger.Warn("Отсутствует параметр lot_id в URL")
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("параметр lot_id обязателен")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.lot_id_required"))
 		return
 	}
 
@@ -42,22 +46,22 @@ func (s *Server) SimpleLotAIResultsHandler(c *gin.Context) {
 	var payload api_models.SimpleLotAIResult
 	if err := c.ShouldBindJSON(&payload); err != nil {
 		logger.Errorf("Ошибка парсинга JSON: %v", err)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("некорректный JSON: %w", err)))
+		respondBindError(c, err)
 		return
 	}
 
 	// --- 3) Валидация бизнес-правил ---
 	if err := payload.Validate(); err != nil {
 		logger.Warnf("Невалидные данные для обновления ключевых параметров: %v", err)
-		c.JSON(http.StatusBadRequest, errorResponse(err))
+		respondStatus(c, http.StatusBadRequest, err)
 		return
 	}
 
 	// --- 4) Сервисный слой: упрощенное обновление ключевых параметров ---
-	err := s.lotService.UpdateLotKeyParametersDirectly(
+	runID, err := s.lotService.UpdateLotKeyParametersDirectly(
 		c.Request.Context(),
 		lotID,
-		payload.LotKeyParameters,
+		payload,
 	)
 	if err != nil {
 		logger.Errorf("Ошибка обновления ключевых параметров: %v", err)
@@ -66,21 +70,30 @@ func (s *Server) SimpleLotAIResultsHandler(c *gin.Context) {
 		var notFoundErr *apierrors.NotFoundError
 		var validationErr *apierrors.ValidationError
 		if errors.As(err, &notFoundErr) {
-			c.JSON(http.StatusNotFound, errorResponse(err))
+			respondStatus(c, http.StatusNotFound, err)
 		} else if errors.As(err, &validationErr) {
-			c.JSON(http.StatusBadRequest, errorResponse(err))
+			respondError(c, validationErr)
 		} else {
-			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			respondError(c, err)
 		}
 		return
 	}
 
-	logger.Infof("AI результаты успешно обработаны для лота %s", lotID)
+	logger.Infof("AI результаты успешно обработаны для лота %s (запуск %d)", lotID, runID)
+
+	// lot_id уже проверен сервисом
+	lotDBID, _ := strconv.ParseInt(lotID, 10, 64)
+	s.publishWebhook(c, logger, webhooks.EventLotAIResults, webhooks.LotAIResultsData{
+		LotID:              lotDBID,
+		LotKeyParameters:   payload.LotKeyParameters,
+		AIAnalysisResultID: runID,
+	})
 
 	// --- 5) Успешный ответ ---
 	c.JSON(http.StatusOK, gin.H{
-		"message":    "AI результаты успешно обработаны",
-		"lot_id":     lotID,
-		"updated_at": "now",
+		"message":               "AI результаты успешно обработаны",
+		"lot_id":                lotID,
+		"ai_analysis_result_id": runID,
+		"updated_at":            "now",
 	})
 }
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getLotAnomaliesHandler обрабатывает GET /api/v1/lots/:id/anomalies.
+// Позиции предложений, цена за единицу которых подозрительно отклоняется от
+// медианы других подрядчиков или от истории цен каталога. Порог — по категории
+// тендера (config anomalies.category_thresholds).
+func (s *Server) getLotAnomaliesHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "getLotAnomaliesHandler")
+
+	lotID, ok := parseUnitParam(c, "id")
+	if !ok {
+		return
+	}
+
+	response, err := s.anomalies.GetLotAnomalies(c.Request.Context(), lotID, requestOrganizationScope(c))
+	if err != nil {
+		logger.Errorf("Ошибка GetLotAnomalies(id=%d): %v", lotID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -8,6 +8,8 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/auth"
 )
 
@@ -22,7 +24,7 @@ type LoginRequest struct {
 func (s *Server) loginHandler(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request format"})
+		respondBindError(c, err)
 		return
 	}
 
@@ -34,11 +36,11 @@ func (s *Server) loginHandler(c *gin.Context) {
 	result, err := s.authService.Login(c.Request.Context(), req.Email, req.Password, ipAddress, userAgent)
 	if err != nil {
 		if errors.Is(err, auth.ErrInvalidCredentials) {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid email or password"})
+			respondCode(c, http.StatusUnauthorized, "invalid_credentials", "auth.invalid_credentials")
 			return
 		}
 		s.logger.WithError(err).Error("login failed")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		respondInternal(c, err)
 		return
 	}
 
@@ -48,9 +50,10 @@ func (s *Server) loginHandler(c *gin.Context) {
 	// Возвращаем информацию о пользователе
 	c.JSON(http.StatusOK, gin.H{
 		"user": gin.H{
-			"id":    result.User.ID,
-			"email": result.User.Email,
-			"role":  result.User.Role,
+			"id":              result.User.ID,
+			"email":           result.User.Email,
+			"role":            result.User.Role,
+			"organization_id": result.User.OrganizationID,
 		},
 	})
 }
@@ -61,7 +64,7 @@ func (s *Server) refreshHandler(c *gin.Context) {
 	// Извлекаем refresh token из cookie
 	refreshToken, err := c.Cookie(s.config.Auth.CookieRefreshName)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "refresh token not found"})
+		respondCode(c, http.StatusUnauthorized, "refresh_token_missing", "auth.refresh_token_missing")
 		return
 	}
 
@@ -75,11 +78,11 @@ func (s *Server) refreshHandler(c *gin.Context) {
 		if errors.Is(err, auth.ErrSessionNotFound) || errors.Is(err, auth.ErrInvalidToken) {
 			// Очищаем cookies при невалидном refresh token
 			s.clearAuthCookies(c)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired refresh token"})
+			respondCode(c, http.StatusUnauthorized, "refresh_token_invalid", "auth.refresh_token_invalid")
 			return
 		}
 		s.logger.WithError(err).Error("refresh failed")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		respondInternal(c, err)
 		return
 	}
 
@@ -115,20 +118,132 @@ func (s *Server) logoutHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "logged out successfully"})
 }
 
+// logoutAllHandler обрабатывает POST /api/v1/auth/logout-all
+// Выход на всех устройствах: сессии завершаются, выданные access-токены
+// отзываются (users.token_version), cookies текущего клиента очищаются.
+func (s *Server) logoutAllHandler(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondCode(c, http.StatusUnauthorized, CodeUnauthorized, "auth.not_authenticated")
+		return
+	}
+	userIDVal, ok := userID.(int64)
+	if !ok {
+		respondInternal(c, errors.New("invalid user_id type"))
+		return
+	}
+
+	revoked, err := s.authService.LogoutAll(c.Request.Context(), userIDVal)
+	if err != nil {
+		if errors.Is(err, auth.ErrSessionNotFound) {
+			s.clearAuthCookies(c)
+			respondCode(c, http.StatusUnauthorized, CodeUnauthorized, "auth.user_not_found")
+			return
+		}
+		s.logger.WithError(err).Error("logout on all devices failed")
+		respondInternal(c, err)
+		return
+	}
+
+	s.clearAuthCookies(c)
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "logged out on all devices",
+		"revoked_sessions": revoked,
+	})
+}
+
+// resetPasswordHandler обрабатывает POST /api/v1/auth/reset-password
+// Установка нового пароля по одноразовому токену, выданному администратором.
+// Все сессии пользователя завершаются; cookies текущего клиента очищаются.
+func (s *Server) resetPasswordHandler(c *gin.Context) {
+	var req api_models.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	err := s.authService.ResetPassword(c.Request.Context(), req.Token, req.NewPassword)
+	if err != nil {
+		var validationErr *apierrors.ValidationError
+		switch {
+		case errors.Is(err, auth.ErrInvalidToken):
+			respondCode(c, http.StatusBadRequest, "reset_token_invalid", "auth.reset_token_invalid")
+		case errors.As(err, &validationErr):
+			respondStatus(c, http.StatusBadRequest, err)
+		default:
+			s.logger.WithError(err).Error("password reset failed")
+			respondInternal(c, err)
+		}
+		return
+	}
+
+	s.clearAuthCookies(c)
+	c.JSON(http.StatusOK, gin.H{"message": "password reset successfully"})
+}
+
+// changePasswordHandler обрабатывает POST /api/v1/auth/change-password
+// Смена собственного пароля: все сессии пользователя завершаются, текущий
+// клиент получает новые токены в cookies, остальные устройства входят заново.
+func (s *Server) changePasswordHandler(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondCode(c, http.StatusUnauthorized, CodeUnauthorized, "auth.not_authenticated")
+		return
+	}
+	userIDVal, ok := userID.(int64)
+	if !ok {
+		respondInternal(c, errors.New("invalid user_id type"))
+		return
+	}
+
+	var req api_models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	ipAddress := parseIPAddress(c.ClientIP())
+	userAgent := c.Request.UserAgent()
+
+	result, err := s.authService.ChangePassword(c.Request.Context(), userIDVal, req.CurrentPassword, req.NewPassword, ipAddress, userAgent)
+	if err != nil {
+		var validationErr *apierrors.ValidationError
+		switch {
+		// 400, а не 401: фронтенд трактует 401 как истекшую сессию
+		case errors.Is(err, auth.ErrInvalidCredentials):
+			respondCode(c, http.StatusBadRequest, "current_password_incorrect", "auth.current_password_incorrect")
+		case errors.As(err, &validationErr):
+			respondStatus(c, http.StatusBadRequest, err)
+		default:
+			s.logger.WithError(err).Error("password change failed")
+			respondInternal(c, err)
+		}
+		return
+	}
+
+	s.setAuthCookies(c, result.AccessToken, result.RefreshToken)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "password changed successfully",
+		"revoked_sessions": result.RevokedSessions,
+	})
+}
+
 // meHandler обрабатывает GET /api/v1/auth/me
-// Возврат информации о текущем аутентифицированном пользователе
+// Возврат информации о текущем аутентифицированном пользователе; под
+// имперсонацией — и о сессии имперсонации
 func (s *Server) meHandler(c *gin.Context) {
 	// Извлекаем user_id из context (установлен AuthMiddleware)
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		respondCode(c, http.StatusUnauthorized, CodeUnauthorized, "auth.not_authenticated")
 		return
 	}
 
 	// Проверяем тип
 	userIDVal, ok := userID.(int64)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user_id type"})
+		respondInternal(c, errors.New("invalid user_id type"))
 		return
 	}
 
@@ -136,17 +251,29 @@ func (s *Server) meHandler(c *gin.Context) {
 	user, err := s.store.GetUserByID(c.Request.Context(), userIDVal)
 	if err != nil {
 		s.logger.WithError(err).Error("failed to get user")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		respondInternal(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"user": gin.H{
-			"id":    user.ID,
-			"email": user.Email,
-			"role":  user.Role,
+			"id":              user.ID,
+			"email":           user.Email,
+			"role":            user.Role,
+			"organization_id": user.OrganizationID,
+			// Права роли — чтобы фронтенд скрывал недоступные действия
+			"permissions": auth.PermissionsForRole(user.Role),
 		},
-	})
+	}
+	// Под имперсонацией фронтенд показывает, кто действует от имени пользователя
+	if claims, impersonated := impersonationClaims(c); impersonated {
+		response["impersonation"] = gin.H{
+			"session_id":      claims.ImpersonationID,
+			"acting_admin_id": claims.ActingAdminID,
+			"expires_at":      claims.ExpiresAt.Time,
+		}
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 // setSameSiteMode устанавливает SameSite атрибут на основе конфигурации
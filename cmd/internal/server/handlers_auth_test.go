@@ -40,10 +40,14 @@ What user problems does this protect us from?
 3. Logout — session is revoked, auth cookies are cleared; works even without refresh cookie
 4. CSRF protection — state-changing endpoints (logout) require CSRF token
 5. Auth middleware — protected endpoints (me) reject unauthenticated requests
+6. Legacy tokens — an access token without org_id is reported as expired so the
+   frontend refreshes it instead of forcing a re-login
+7. Stolen access tokens — after logout on all devices the old token is rejected
+   (users.token_version), not only after its TTL
 
 NOTE: POST /api/auth/register is NOT implemented in the current codebase.
       User creation is handled via CLI tool (cmd/createadmin) and admin API.
-      Tests below cover all existing auth endpoints: login, refresh, logout, me.
+      Tests below cover all existing auth endpoints: login, refresh, logout, logout-all, me.
 
 Approach:
   - Auth handlers delegate to auth.Service, which uses db.Store.
@@ -121,7 +125,7 @@ func setupAuthTestServer(t *testing.T) (*gin.Engine, *db.MockStore, *testutil.Mo
 	logger := testutil.NewMockLogger()
 	cfg := testConfig()
 
-	authService := auth.NewService(mockStore, cfg, logger)
+	authService := auth.NewService(mockStore, cfg, logger, nil)
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
@@ -141,16 +145,22 @@ func setupAuthTestServer(t *testing.T) (*gin.Engine, *db.MockStore, *testutil.Mo
 		v1.POST("/auth/logout", CsrfMiddleware(), server.logoutHandler)
 
 		protected := v1.Group("/")
-		protected.Use(AuthMiddleware(cfg, mockStore, logger))
+		protected.Use(AuthMiddleware(cfg, authService))
 		protected.Use(CsrfMiddleware())
 		{
 			protected.GET("/auth/me", server.meHandler)
+			protected.POST("/auth/logout-all", server.logoutAllHandler)
 		}
 	}
 
 	return router, mockStore, logger, cfg
 }
 
+// expectTokenVersion mocks the users.token_version lookup made by AuthMiddleware.
+func expectTokenVersion(mockStore *db.MockStore, userID, version int64) {
+	mockStore.EXPECT().GetUserTokenVersion(gomock.Any(), userID).Return(version, nil)
+}
+
 // makeJSONRequest creates an HTTP request with a JSON-encoded body.
 func makeJSONRequest(t *testing.T, method, path string, body interface{}) *http.Request {
 	t.Helper()
@@ -163,12 +173,14 @@ func makeJSONRequest(t *testing.T, method, path string, body interface{}) *http.
 }
 
 // makeTestAccessToken creates a valid JWT access token signed with testJWTSecret.
+// The user belongs to organization 1.
 func makeTestAccessToken(t *testing.T, userID int64, role string) string {
 	t.Helper()
 	now := time.Now()
 	claims := auth.JWTClaims{
-		UserID: userID,
-		Role:   role,
+		UserID:         userID,
+		Role:           role,
+		OrganizationID: 1,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(now.Add(15 * time.Minute)),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -211,7 +223,7 @@ func addCSRF(req *http.Request, csrfToken string) {
 
 var (
 	sessionColumns  = []string{"id", "user_id", "refresh_token_hash", "created_at", "expires_at", "revoked_at"}
-	userByIDColumns = []string{"id", "email", "role", "is_active", "last_login_at", "created_at", "updated_at"}
+	userByIDColumns = []string{"id", "email", "role", "is_active", "organization_id", "last_login_at", "created_at", "updated_at", "token_version"}
 )
 
 // newMockQueries creates a sqlmock-backed *db.Queries for use inside ExecTx DoAndReturn.
@@ -356,6 +368,7 @@ func TestLoginHandler_ValidationErrors(t *testing.T) {
 			body := parseBody(t, w)
 			_, hasError := body["error"]
 			assert.True(t, hasError, "expected 'error' field in response")
+			assert.Equal(t, "validation_failed", body["code"])
 		})
 	}
 }
@@ -390,7 +403,8 @@ func TestLoginHandler_WrongPassword(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 
 	body := parseBody(t, w)
-	assert.Equal(t, "invalid email or password", body["error"])
+	assert.Equal(t, "неверный email или пароль", body["error"])
+	assert.Equal(t, "invalid_credentials", body["code"])
 
 	// Assert logger: wrong password attempt logged as Warn by auth service
 	testutil.AssertLogEntry(t, logger, testutil.LevelWarn, "failed login attempt")
@@ -414,7 +428,7 @@ func TestLoginHandler_UserNotFound(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 
 	body := parseBody(t, w)
-	assert.Equal(t, "invalid email or password", body["error"])
+	assert.Equal(t, "неверный email или пароль", body["error"])
 
 	// Assert logger: non-existent email logged as Warn by auth service
 	testutil.AssertLogEntry(t, logger, testutil.LevelWarn, "non-existent email")
@@ -440,7 +454,7 @@ func TestLoginHandler_InactiveUser(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 
 	body := parseBody(t, w)
-	assert.Equal(t, "invalid email or password", body["error"])
+	assert.Equal(t, "неверный email или пароль", body["error"])
 
 	// Assert logger: inactive user login attempt logged as Warn by auth service
 	testutil.AssertLogEntry(t, logger, testutil.LevelWarn, "inactive user")
@@ -463,7 +477,7 @@ func TestLoginHandler_DBError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 
 	body := parseBody(t, w)
-	assert.Equal(t, "internal server error", body["error"])
+	assert.Equal(t, "internal_error", body["code"])
 
 	// Assert logger: handler logs Error with attached error
 	testutil.AssertLogEntryWithError(t, logger, testutil.LevelError, "login failed")
@@ -490,7 +504,7 @@ func TestLoginHandler_SessionCreationFailed(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 
 	body := parseBody(t, w)
-	assert.Equal(t, "internal server error", body["error"])
+	assert.Equal(t, "internal_error", body["code"])
 
 	// Assert logger: auth service logs session creation error + handler logs "login failed"
 	testutil.AssertLogEntry(t, logger, testutil.LevelError, "failed to create session")
@@ -530,7 +544,7 @@ func TestRefreshHandler_Success(t *testing.T) {
 			mock.ExpectQuery("SELECT .+ FROM users WHERE id").
 				WithArgs(int64(1)).
 				WillReturnRows(sqlmock.NewRows(userByIDColumns).
-					AddRow(int64(1), testEmail, "user", true, nil, now, now))
+					AddRow(int64(1), testEmail, "user", true, int64(1), nil, now, now, int64(0)))
 		}),
 	)
 
@@ -564,7 +578,7 @@ func TestRefreshHandler_NoCookie(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 
 	body := parseBody(t, w)
-	assert.Equal(t, "refresh token not found", body["error"])
+	assert.Equal(t, "refresh-токен не найден", body["error"])
 }
 
 func TestRefreshHandler_InvalidTokenFormat(t *testing.T) {
@@ -581,7 +595,7 @@ func TestRefreshHandler_InvalidTokenFormat(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 
 	body := parseBody(t, w)
-	assert.Equal(t, "invalid or expired refresh token", body["error"])
+	assert.Equal(t, "refresh-токен недействителен или истёк", body["error"])
 
 	// clearAuthCookies always sets access_token with MaxAge = -1
 	accessCookie := testutil.FindResponseCookie(w, "access_token")
@@ -613,7 +627,7 @@ func TestRefreshHandler_SessionNotFound(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 
 	body := parseBody(t, w)
-	assert.Equal(t, "invalid or expired refresh token", body["error"])
+	assert.Equal(t, "refresh-токен недействителен или истёк", body["error"])
 }
 
 func TestRefreshHandler_DBError(t *testing.T) {
@@ -639,7 +653,7 @@ func TestRefreshHandler_DBError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 
 	body := parseBody(t, w)
-	assert.Equal(t, "internal server error", body["error"])
+	assert.Equal(t, "internal_error", body["code"])
 
 	// Assert logger: handler logs Error with attached error
 	testutil.AssertLogEntryWithError(t, logger, testutil.LevelError, "refresh failed")
@@ -718,7 +732,7 @@ func TestLogoutHandler_MissingCSRF(t *testing.T) {
 	assert.Equal(t, http.StatusForbidden, w.Code)
 
 	body := parseBody(t, w)
-	assert.Equal(t, "csrf_token_missing", body["error"])
+	assert.Equal(t, "csrf_token_missing", body["code"])
 }
 
 func TestLogoutHandler_CSRFMismatch(t *testing.T) {
@@ -738,7 +752,7 @@ func TestLogoutHandler_CSRFMismatch(t *testing.T) {
 	assert.Equal(t, http.StatusForbidden, w.Code)
 
 	body := parseBody(t, w)
-	assert.Equal(t, "csrf_invalid", body["error"])
+	assert.Equal(t, "csrf_invalid", body["code"])
 }
 
 func TestLogoutHandler_ServiceError(t *testing.T) {
@@ -779,6 +793,7 @@ func TestMeHandler_Success(t *testing.T) {
 	now := time.Now()
 
 	accessToken := makeTestAccessToken(t, 1, "user")
+	expectTokenVersion(mockStore, 1, 0)
 
 	// Mock: GetUserByID (called by meHandler after AuthMiddleware sets user_id)
 	mockStore.EXPECT().
@@ -809,6 +824,72 @@ func TestMeHandler_Success(t *testing.T) {
 	assert.Equal(t, "user", userResp["role"])
 }
 
+func TestMeHandler_ReturnsRolePermissions(t *testing.T) {
+	router, mockStore, _, _ := setupAuthTestServer(t)
+	now := time.Now()
+
+	accessToken := makeTestAccessToken(t, 2, auth.RoleAnalyst)
+	expectTokenVersion(mockStore, 2, 0)
+	mockStore.EXPECT().
+		GetUserByID(gomock.Any(), int64(2)).
+		Return(db.GetUserByIDRow{ID: 2, Email: testEmail, Role: auth.RoleAnalyst, IsActive: true, CreatedAt: now, UpdatedAt: now}, nil)
+
+	req, err := http.NewRequest(http.MethodGet, "/api/v1/auth/me", nil)
+	require.NoError(t, err)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: accessToken})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	userResp := parseBody(t, w)["user"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"analytics:read", "matching:correct", "tenders:read"}, userResp["permissions"])
+}
+
+// =============================================================================
+// REQUIRE PERMISSION TESTS
+// =============================================================================
+
+func TestRequirePermission(t *testing.T) {
+	cfg := testConfig()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	mockStore.EXPECT().GetUserTokenVersion(gomock.Any(), int64(1)).Return(int64(0), nil).AnyTimes()
+	authService := auth.NewService(mockStore, cfg, testutil.NewMockLogger(), nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/winners", AuthMiddleware(cfg, authService), RequirePermission(auth.PermissionWinnersManage),
+		func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	cases := map[string]struct {
+		role string
+		want int
+	}{
+		"viewer is read-only":         {auth.RoleViewer, http.StatusForbidden},
+		"analyst cannot manage":       {auth.RoleAnalyst, http.StatusForbidden},
+		"editor manages winners":      {auth.RoleEditor, http.StatusNoContent},
+		"admin has every permission":  {auth.RoleAdmin, http.StatusNoContent},
+		"legacy operator token works": {"operator", http.StatusNoContent},
+		"unknown role has no rights":  {"user", http.StatusForbidden},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/winners", nil)
+			require.NoError(t, err)
+			req.AddCookie(&http.Cookie{Name: "access_token", Value: makeTestAccessToken(t, 1, tc.role)})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.want, w.Code)
+			if tc.want == http.StatusForbidden {
+				assert.Equal(t, "winners:manage", parseBody(t, w)["permission"])
+			}
+		})
+	}
+}
+
 func TestMeHandler_NoAuth(t *testing.T) {
 	router, _, _, _ := setupAuthTestServer(t)
 
@@ -835,13 +916,14 @@ func TestMeHandler_InvalidToken(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 
 	body := parseBody(t, w)
-	assert.Equal(t, "access_token_invalid", body["error"])
+	assert.Equal(t, "access_token_invalid", body["code"])
 }
 
 func TestMeHandler_DBError(t *testing.T) {
 	router, mockStore, logger, _ := setupAuthTestServer(t)
 
 	accessToken := makeTestAccessToken(t, 1, "user")
+	expectTokenVersion(mockStore, 1, 0)
 
 	mockStore.EXPECT().
 		GetUserByID(gomock.Any(), int64(1)).
@@ -857,12 +939,101 @@ func TestMeHandler_DBError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 
 	body := parseBody(t, w)
-	assert.Equal(t, "internal server error", body["error"])
+	assert.Equal(t, "internal_error", body["code"])
+	assert.NotContains(t, body["message"], "database unreachable", "DB errors must not leak to the client")
 
 	// Assert logger: handler logs Error with attached error
 	testutil.AssertLogEntryWithError(t, logger, testutil.LevelError, "failed to get user")
 }
 
+// =============================================================================
+// TOKEN VERSION / LOGOUT ALL TESTS
+// =============================================================================
+
+func TestMeHandler_RevokedTokenVersion(t *testing.T) {
+	router, mockStore, _, _ := setupAuthTestServer(t)
+
+	// GIVEN: the user logged out on all devices after the token was issued
+	accessToken := makeTestAccessToken(t, 1, "user")
+	expectTokenVersion(mockStore, 1, 1)
+
+	req, err := http.NewRequest(http.MethodGet, "/api/v1/auth/me", nil)
+	require.NoError(t, err)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: accessToken})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// THEN: rejected as invalid (re-login), the access cookie is cleared
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "access_token_invalid", parseBody(t, w)["code"])
+	accessCookie := testutil.FindResponseCookie(w, "access_token")
+	require.NotNil(t, accessCookie)
+	assert.True(t, accessCookie.MaxAge < 0)
+}
+
+func TestMeHandler_TokenVersionLookupFails(t *testing.T) {
+	router, mockStore, _, _ := setupAuthTestServer(t)
+
+	mockStore.EXPECT().GetUserTokenVersion(gomock.Any(), int64(1)).Return(int64(0), fmt.Errorf("database unreachable"))
+
+	req, err := http.NewRequest(http.MethodGet, "/api/v1/auth/me", nil)
+	require.NoError(t, err)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: makeTestAccessToken(t, 1, "user")})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Not 401: the frontend must not drop the session because the DB blinked
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestLogoutAllHandler_Success(t *testing.T) {
+	router, mockStore, _, _ := setupAuthTestServer(t)
+
+	expectTokenVersion(mockStore, 1, 0)
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("UPDATE users").
+				WithArgs(sqlmock.AnyArg(), int64(1)).
+				WillReturnRows(sqlmock.NewRows([]string{"token_version"}).AddRow(int64(1)))
+			mock.ExpectExec("UPDATE user_sessions").
+				WithArgs(int64(1)).
+				WillReturnResult(sqlmock.NewResult(0, 3))
+		}),
+	)
+
+	req, err := http.NewRequest(http.MethodPost, "/api/v1/auth/logout-all", nil)
+	require.NoError(t, err)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: makeTestAccessToken(t, 1, "user")})
+	addCSRF(req, "test-csrf-token-32chars-minimum-value")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, float64(3), parseBody(t, w)["revoked_sessions"])
+
+	refreshCookie := testutil.FindResponseCookie(w, "refresh_token")
+	require.NotNil(t, refreshCookie, "refresh_token cookie should be present (cleared)")
+	assert.True(t, refreshCookie.MaxAge < 0)
+}
+
+func TestLogoutAllHandler_MissingCSRF(t *testing.T) {
+	router, mockStore, _, _ := setupAuthTestServer(t)
+
+	expectTokenVersion(mockStore, 1, 0)
+
+	req, err := http.NewRequest(http.MethodPost, "/api/v1/auth/logout-all", nil)
+	require.NoError(t, err)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: makeTestAccessToken(t, 1, "user")})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
 // =============================================================================
 // CRITICAL MISSING TESTS — SECURITY, COOKIE ATTRIBUTES, EDGE CASES
 // =============================================================================
@@ -996,6 +1167,39 @@ func TestMeHandler_ExpiredToken(t *testing.T) {
 	assert.Nil(t, refreshCookie, "refresh_token cookie must NOT be set/cleared by middleware")
 }
 
+// TestMeHandler_TokenWithoutOrganization verifies that a token issued before
+// organizations existed is answered like an expired one: the frontend refreshes
+// it and gets a token with org_id instead of a forced re-login.
+func TestMeHandler_TokenWithoutOrganization(t *testing.T) {
+	router, _, _, _ := setupAuthTestServer(t)
+
+	now := time.Now()
+	claims := auth.JWTClaims{
+		UserID: 1,
+		Role:   "admin",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(15 * time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "tenders-go",
+			Subject:   "1",
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	legacyToken, err := token.SignedString([]byte(testJWTSecret))
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, "/api/v1/auth/me", nil)
+	require.NoError(t, err)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: legacyToken})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "access_token_expired", w.Header().Get("X-Auth-Error"))
+}
+
 // TestMeHandler_WrongSigningKey verifies that a JWT signed with a different key is rejected.
 // This protects against tokens forged with a compromised key from another service.
 func TestMeHandler_WrongSigningKey(t *testing.T) {
@@ -1073,7 +1277,7 @@ func TestRefreshHandler_ExpiredSessionTimeMismatch(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 
 	body := parseBody(t, w)
-	assert.Equal(t, "invalid or expired refresh token", body["error"])
+	assert.Equal(t, "refresh-токен недействителен или истёк", body["error"])
 
 	// Cookies must be cleared (same as session-not-found path)
 	accessCookie := testutil.FindResponseCookie(w, "access_token")
@@ -1157,7 +1361,7 @@ func TestRefreshHandler_RevokeOldSessionFails(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 
 	body := parseBody(t, w)
-	assert.Equal(t, "internal server error", body["error"])
+	assert.Equal(t, "internal_error", body["code"])
 
 	testutil.AssertLogEntryWithError(t, logger, testutil.LevelError, "refresh failed")
 }
@@ -1201,7 +1405,7 @@ func TestRefreshHandler_CreateNewSessionFails(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 
 	body := parseBody(t, w)
-	assert.Equal(t, "internal server error", body["error"])
+	assert.Equal(t, "internal_error", body["code"])
 
 	testutil.AssertLogEntryWithError(t, logger, testutil.LevelError, "refresh failed")
 }
@@ -1250,7 +1454,7 @@ func TestRefreshHandler_GetUserFailsInsideTx(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 
 	body := parseBody(t, w)
-	assert.Equal(t, "internal server error", body["error"])
+	assert.Equal(t, "internal_error", body["code"])
 
 	testutil.AssertLogEntryWithError(t, logger, testutil.LevelError, "refresh failed")
 }
@@ -1324,7 +1528,7 @@ func TestRefreshHandler_CookieSecurityAttributes(t *testing.T) {
 			mock.ExpectQuery("SELECT .+ FROM users WHERE id").
 				WithArgs(int64(1)).
 				WillReturnRows(sqlmock.NewRows(userByIDColumns).
-					AddRow(int64(1), testEmail, "user", true, nil, now, now))
+					AddRow(int64(1), testEmail, "user", true, int64(1), nil, now, now, int64(0)))
 		}),
 	)
 
@@ -1398,6 +1602,6 @@ func TestCSRF_HeaderMissingCookiePresent(t *testing.T) {
 	assert.Equal(t, http.StatusForbidden, w.Code)
 
 	body := parseBody(t, w)
-	assert.Equal(t, "csrf_header_missing", body["error"],
+	assert.Equal(t, "csrf_header_missing", body["code"],
 		"must distinguish between missing cookie and missing header for frontend debugging")
 }
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/baseline"
+)
+
+// estimateLotBaselineHandler обрабатывает POST /api/v1/lots/:id/baseline/estimate.
+// Строит baseline лота по средним ценам истории каталога.
+//
+// Query:
+//   - dry_run (bool, default false): только рассчитать, ничего не сохраняя
+//   - window_months (int, default 24): окно истории цен, 1..120
+//
+// Errors: 400 (параметры, в лоте нет позиций), 404 (лот), 409 (в baseline уже есть цены)
+func (s *Server) estimateLotBaselineHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "estimateLotBaselineHandler")
+
+	lotID, ok := parseUnitParam(c, "id")
+	if !ok {
+		return
+	}
+
+	opts := baseline.EstimateOptions{OrganizationID: requestOrganizationScope(c)}
+	if v := c.Query("dry_run"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.dry_run_bool"))
+			return
+		}
+		opts.DryRun = parsed
+	}
+	if v := c.Query("window_months"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.window_months_int"))
+			return
+		}
+		opts.WindowMonths = parsed
+	}
+
+	response, err := s.baselines.Estimate(c.Request.Context(), lotID, opts)
+	if err != nil {
+		logger.Errorf("Ошибка оценки baseline лота %d (dry_run=%t): %v", lotID, opts.DryRun, err)
+		respondErrorCode(c, err, "baseline_conflict")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
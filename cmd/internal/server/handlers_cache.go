@@ -0,0 +1,43 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getRefCacheStatsHandler обрабатывает GET /api/v1/admin/cache/stats.
+// Возвращает счётчики кэша справочников этого экземпляра API с момента запуска.
+func (s *Server) getRefCacheStatsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, s.refCache.Stats())
+}
+
+// refCacheKey — ключ ответа справочника: путь и параметры запроса в каноническом
+// порядке, чтобы ?page=1&page_size=20 и ?page_size=20&page=1 попадали в одно значение.
+func refCacheKey(c *gin.Context) string {
+	return c.Request.URL.Path + "?" + c.Request.URL.Query().Encode()
+}
+
+// serveReferenceFromCache отвечает закэшированным списком справочника.
+// Возвращает false при промахе — тогда обработчик читает список из БД.
+func (s *Server) serveReferenceFromCache(c *gin.Context, group string) bool {
+	body, found := s.refCache.Get(c.Request.Context(), group, refCacheKey(c))
+	if !found {
+		return false
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+	return true
+}
+
+// respondReference отвечает списком справочника и сохраняет ответ в кэш группы.
+func (s *Server) respondReference(c *gin.Context, group string, value any) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		s.logger.Errorf("ошибка сериализации справочника %s: %v", group, err)
+		respondInternal(c, err)
+		return
+	}
+	s.refCache.Set(c.Request.Context(), group, refCacheKey(c), body)
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/analytics"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/priceindex"
+)
+
+// getCatalogPriceHistoryHandler обрабатывает GET /api/v1/catalog/:id/price-history.
+// Возвращает цены за единицу позиции каталога во всех тендерах организации
+// пользователя (admin — всех) и min/avg/max по кварталам.
+// Query: normalize=true — пересчитать цены по индексам цен к index_region
+// (по умолчанию RU) и index_date (YYYY-MM-DD, по умолчанию сегодня).
+func (s *Server) getCatalogPriceHistoryHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "getCatalogPriceHistoryHandler")
+
+	idStr := c.Param("id")
+	positionID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || positionID <= 0 {
+		logger.Errorf("Некорректный ID позиции каталога: %s", idStr)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return
+	}
+
+	var normalization *analytics.PriceNormalizationTarget
+	if c.Query("normalize") == "true" {
+		region, err := priceindex.NormalizeRegion(c.DefaultQuery("index_region", priceindex.NationalRegion))
+		if err != nil {
+			respondStatus(c, http.StatusBadRequest, err)
+			return
+		}
+		date := time.Now()
+		if value := c.Query("index_date"); value != "" {
+			date, err = time.Parse("2006-01-02", value)
+			if err != nil {
+				respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.index_date_format"))
+				return
+			}
+		}
+		normalization = &analytics.PriceNormalizationTarget{Region: region, Date: date}
+	}
+
+	response, err := s.analytics.GetCatalogPriceHistory(c.Request.Context(), positionID, requestOrganizationScope(c), normalization)
+	if err != nil {
+		logger.Errorf("Ошибка GetCatalogPriceHistory(id=%d): %v", positionID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// searchPositionsHandler обрабатывает GET /api/v1/positions/search.
+// Query: q (обязателен, от 3 символов), catalog_id, min_cost, max_cost (цена за
+// единицу в валюте позиции), page, page_size. cursor вместо page — keyset-пагинация
+// с next_cursor в ответе. Ищет позиции КП по названию во всех тендерах
+// организации пользователя (admin — всех).
+func (s *Server) searchPositionsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "searchPositionsHandler")
+
+	query := analytics.PositionSearchQuery{
+		Query:          c.Query("q"),
+		OrganizationID: requestOrganizationScope(c),
+	}
+
+	page, err := strconv.ParseInt(c.DefaultQuery("page", "1"), 10, 32)
+	if err != nil {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_page"))
+		return
+	}
+	pageSize, err := strconv.ParseInt(c.DefaultQuery("page_size", "20"), 10, 32)
+	if err != nil {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_page_size_range", analytics.MaxSearchPageSize))
+		return
+	}
+	query.Page, query.PageSize = int32(page), int32(pageSize)
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		if _, hasPage := c.GetQuery("page"); hasPage {
+			respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.cursor_with_page"))
+			return
+		}
+		query.Page, query.Cursor = 0, &cursor
+	}
+
+	if raw := c.Query("catalog_id"); raw != "" {
+		catalogID, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || catalogID <= 0 {
+			respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.catalog_id_positive"))
+			return
+		}
+		query.CatalogPositionID = sql.NullInt64{Int64: catalogID, Valid: true}
+	}
+	for name, target := range map[string]**decimal.Decimal{"min_cost": &query.MinCost, "max_cost": &query.MaxCost} {
+		raw := c.Query(name)
+		if raw == "" {
+			continue
+		}
+		value, err := decimal.NewFromString(raw)
+		if err != nil {
+			respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.param_number", name))
+			return
+		}
+		*target = &value
+	}
+
+	response, err := s.analytics.SearchPositions(c.Request.Context(), query)
+	if err != nil {
+		var validationErr *apierrors.ValidationError
+		if errors.As(err, &validationErr) {
+			respondStatus(c, http.StatusBadRequest, err)
+			return
+		}
+		logger.Errorf("Ошибка SearchPositions(q=%q): %v", query.Query, err)
+		respondInternal(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
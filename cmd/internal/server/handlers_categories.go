@@ -2,12 +2,13 @@ package server
 
 import (
 	"database/sql"
-	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/refcache"
 )
 
 // Структура для JSON-запросов на создание/обновление категории
@@ -24,13 +25,17 @@ func (s *Server) listTenderCategoriesHandler(c *gin.Context) {
 
 	pageID, err := strconv.ParseInt(pageIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный параметр page")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_page"))
 		return
 	}
 
 	pageSize, err := strconv.ParseInt(pageSizeStr, 10, 32)
 	if err != nil || pageSize < 1 {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный параметр page_size")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_page_size"))
+		return
+	}
+
+	if s.serveReferenceFromCache(c, refcache.GroupTenderCategories) {
 		return
 	}
 
@@ -41,7 +46,7 @@ func (s *Server) listTenderCategoriesHandler(c *gin.Context) {
 
 	categories, err := s.store.ListTenderCategories(c.Request.Context(), params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
@@ -51,14 +56,14 @@ func (s *Server) listTenderCategoriesHandler(c *gin.Context) {
 		categories = make([]db.ListTenderCategoriesRow, 0) // Возвращаем пустой массив, если нет данных
 	}
 
-	c.JSON(http.StatusOK, categories)
+	s.respondReference(c, refcache.GroupTenderCategories, categories)
 }
 
 // createTenderCategoryHandler создает новую категорию
 func (s *Server) createTenderCategoryHandler(c *gin.Context) {
 	var req tenderCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(err))
+		respondBindError(c, err)
 		return
 	}
 	// Используем ваш надежный Upsert
@@ -68,9 +73,10 @@ func (s *Server) createTenderCategoryHandler(c *gin.Context) {
 	}
 	category, err := s.store.UpsertTenderCategory(c.Request.Context(), params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
+	s.refCache.Invalidate(c.Request.Context(), refcache.GroupTenderCategories)
 	c.JSON(http.StatusCreated, category)
 }
 
@@ -78,12 +84,12 @@ func (s *Server) createTenderCategoryHandler(c *gin.Context) {
 func (s *Server) updateTenderCategoryHandler(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный ID")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_id"))
 		return
 	}
 	var req tenderCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(err))
+		respondBindError(c, err)
 		return
 	}
 	params := db.UpdateTenderCategoryParams{
@@ -93,9 +99,10 @@ func (s *Server) updateTenderCategoryHandler(c *gin.Context) {
 	}
 	category, err := s.store.UpdateTenderCategory(c.Request.Context(), params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
+	s.refCache.Invalidate(c.Request.Context(), refcache.GroupTenderCategories)
 	c.JSON(http.StatusOK, category)
 }
 
@@ -103,21 +110,22 @@ func (s *Server) updateTenderCategoryHandler(c *gin.Context) {
 func (s *Server) deleteTenderCategoryHandler(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный ID")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_id"))
 		return
 	}
 	err = s.store.DeleteTenderCategory(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
+	s.refCache.Invalidate(c.Request.Context(), refcache.GroupTenderCategories)
 	c.Status(http.StatusNoContent)
 }
 
 func (s *Server) listCategoriesByChapterHandler(c *gin.Context) {
 	chapterID, err := strconv.ParseInt(c.Param("chapter_id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный ID раздела")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_chapter_id"))
 		return
 	}
 
@@ -134,6 +142,10 @@ func (s *Server) listCategoriesByChapterHandler(c *gin.Context) {
 		pageSize = 100
 	}
 
+	if s.serveReferenceFromCache(c, refcache.GroupTenderCategories) {
+		return
+	}
+
 	params := db.ListTenderCategoriesByChapterParams{
 		TenderChapterID: chapterID,
 		Limit:           int32(pageSize),
@@ -142,7 +154,7 @@ func (s *Server) listCategoriesByChapterHandler(c *gin.Context) {
 
 	categories, err := s.store.ListTenderCategoriesByChapter(c.Request.Context(), params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
@@ -150,5 +162,5 @@ func (s *Server) listCategoriesByChapterHandler(c *gin.Context) {
 		categories = make([]db.TenderCategory, 0)
 	}
 
-	c.JSON(http.StatusOK, categories)
+	s.respondReference(c, refcache.GroupTenderCategories, categories)
 }
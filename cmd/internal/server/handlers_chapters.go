@@ -2,12 +2,13 @@ package server
 
 import (
 	"database/sql"
-	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/refcache"
 )
 
 // listTenderChaptersHandler - обработчик для получения списка разделов тендеров с пагинацией.
@@ -18,13 +19,17 @@ func (s *Server) listTenderChaptersHandler(c *gin.Context) {
 
 	pageID, err := strconv.ParseInt(pageIDStr, 10, 32)
 	if err != nil || pageID < 1 {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный параметр page")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_page"))
 		return
 	}
 
 	pageSize, err := strconv.ParseInt(pageSizeStr, 10, 32)
 	if err != nil || pageSize < 1 || pageSize > 100 {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный параметр page_size (допустимо от 1 до 100)")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_page_size_100"))
+		return
+	}
+
+	if s.serveReferenceFromCache(c, refcache.GroupTenderChapters) {
 		return
 	}
 
@@ -37,7 +42,7 @@ func (s *Server) listTenderChaptersHandler(c *gin.Context) {
 	tenderChapters, err := s.store.ListTenderChapters(c.Request.Context(), params)
 	if err != nil {
 		s.logger.Errorf("ошибка получения списка разделов тендеров: %v", err)
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
@@ -45,7 +50,7 @@ func (s *Server) listTenderChaptersHandler(c *gin.Context) {
 		tenderChapters = make([]db.ListTenderChaptersRow, 0)
 	}
 
-	c.JSON(http.StatusOK, tenderChapters)
+	s.respondReference(c, refcache.GroupTenderChapters, tenderChapters)
 }
 
 // Структура для входящего JSON остается той же
@@ -59,7 +64,7 @@ func (s *Server) createTenderChapterHandler(c *gin.Context) {
 	var req createTenderChapterRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(err))
+		respondBindError(c, err)
 		return
 	}
 
@@ -73,10 +78,12 @@ func (s *Server) createTenderChapterHandler(c *gin.Context) {
 	chapter, err := s.store.UpsertTenderChapter(c.Request.Context(), params)
 	if err != nil {
 		s.logger.Errorf("ошибка при создании/обновлении раздела тендера: %v", err)
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
+	s.refCache.Invalidate(c.Request.Context(), refcache.Dependents(refcache.GroupTenderChapters)...)
+
 	// Возвращаем созданный/обновленный объект
 	c.JSON(http.StatusCreated, chapter)
 }
@@ -92,13 +99,13 @@ func (s *Server) updateTenderChapterHandler(c *gin.Context) {
 	// Получаем ID из URL
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный ID раздела")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_chapter_id"))
 		return
 	}
 
 	var req updateTenderChapterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(err))
+		respondBindError(c, err)
 		return
 	}
 
@@ -111,10 +118,12 @@ func (s *Server) updateTenderChapterHandler(c *gin.Context) {
 	updatedChapter, err := s.store.UpdateTenderChapter(c.Request.Context(), params)
 	if err != nil {
 		s.logger.Errorf("ошибка обновления раздела тендера: %v", err)
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
+	s.refCache.Invalidate(c.Request.Context(), refcache.Dependents(refcache.GroupTenderChapters)...)
+
 	c.JSON(http.StatusOK, updatedChapter)
 }
 
@@ -122,17 +131,19 @@ func (s *Server) updateTenderChapterHandler(c *gin.Context) {
 func (s *Server) deleteTenderChapterHandler(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный ID раздела")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_chapter_id"))
 		return
 	}
 
 	err = s.store.DeleteTenderChapter(c.Request.Context(), id)
 	if err != nil {
 		s.logger.Errorf("ошибка удаления раздела тендера: %v", err)
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
+	s.refCache.Invalidate(c.Request.Context(), refcache.Dependents(refcache.GroupTenderChapters)...)
+
 	c.Status(http.StatusNoContent)
 }
 
@@ -141,7 +152,7 @@ func (s *Server) listChaptersByTypeHandler(c *gin.Context) {
 	// Получаем ID типа из URL, например /api/v1/tender-types/1/chapters
 	typeID, err := strconv.ParseInt(c.Param("type_id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный ID типа тендера")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_tender_type_id"))
 		return
 	}
 
@@ -158,6 +169,10 @@ func (s *Server) listChaptersByTypeHandler(c *gin.Context) {
 		pageSize = 100
 	}
 
+	if s.serveReferenceFromCache(c, refcache.GroupTenderChapters) {
+		return
+	}
+
 	params := db.ListTenderChaptersByTypeParams{
 		TenderTypeID: typeID,
 		Limit:        int32(pageSize),
@@ -166,7 +181,7 @@ func (s *Server) listChaptersByTypeHandler(c *gin.Context) {
 
 	chapters, err := s.store.ListTenderChaptersByType(c.Request.Context(), params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
@@ -174,5 +189,5 @@ func (s *Server) listChaptersByTypeHandler(c *gin.Context) {
 		chapters = make([]db.TenderChapter, 0)
 	}
 
-	c.JSON(http.StatusOK, chapters)
+	s.respondReference(c, refcache.GroupTenderChapters, chapters)
 }
\ No newline at end of file
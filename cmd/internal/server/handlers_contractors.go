@@ -0,0 +1,202 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/contractor"
+)
+
+// listContractorsHandler обрабатывает GET /api/v1/contractors.
+// Query: search (наименование или начало ИНН), page (с 1), page_size (1..100).
+func (s *Server) listContractorsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "listContractorsHandler")
+
+	page, err := strconv.ParseInt(c.DefaultQuery("page", "1"), 10, 32)
+	if err != nil {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_page"))
+		return
+	}
+	pageSize, err := strconv.ParseInt(c.DefaultQuery("page_size", "20"), 10, 32)
+	if err != nil {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_page_size_range", contractor.MaxPageSize))
+		return
+	}
+
+	response, err := s.contractors.ListContractors(c.Request.Context(), c.Query("search"), int32(page), int32(pageSize))
+	if err != nil {
+		logger.Errorf("Ошибка ListContractors: %v", err)
+		respondErrorCode(c, err, "contractor_merge_conflict")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// getContractorHandler обрабатывает GET /api/v1/contractors/:id.
+func (s *Server) getContractorHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "getContractorHandler")
+
+	contractorID, ok := parseContractorID(c)
+	if !ok {
+		return
+	}
+
+	response, err := s.contractors.GetContractor(c.Request.Context(), contractorID)
+	if err != nil {
+		logger.Errorf("Ошибка GetContractor(id=%d): %v", contractorID, err)
+		respondErrorCode(c, err, "contractor_merge_conflict")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// getContractorStatsHandler обрабатывает GET /api/v1/contractors/:id/stats.
+// Query: recent — сколько последних предложений вернуть (0..50, по умолчанию 10).
+// Учитываются только тендеры организации пользователя (admin — все).
+func (s *Server) getContractorStatsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "getContractorStatsHandler")
+
+	contractorID, ok := parseContractorID(c)
+	if !ok {
+		return
+	}
+
+	recent, err := strconv.ParseInt(c.DefaultQuery("recent", strconv.Itoa(contractor.DefaultRecentProposals)), 10, 32)
+	if err != nil {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_recent_range", contractor.MaxRecentProposals))
+		return
+	}
+
+	response, err := s.contractors.GetContractorStats(c.Request.Context(), contractorID, int32(recent), requestOrganizationScope(c))
+	if err != nil {
+		logger.Errorf("Ошибка GetContractorStats(id=%d): %v", contractorID, err)
+		respondErrorCode(c, err, "contractor_merge_conflict")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// listExpiringAccreditationsHandler обрабатывает GET /api/v1/contractors/expiring-accreditation.
+// Query: within_days (0..365, по умолчанию contractors.accreditation_warn_days),
+// page (с 1), page_size (1..100). Истекшие аккредитации входят в список всегда.
+func (s *Server) listExpiringAccreditationsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "listExpiringAccreditationsHandler")
+
+	withinDays, err := strconv.Atoi(c.DefaultQuery("within_days", strconv.Itoa(s.config.Contractors.AccreditationWarnDays)))
+	if err != nil {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_within_days_range", config.MaxAccreditationWarnDays))
+		return
+	}
+	page, err := strconv.ParseInt(c.DefaultQuery("page", "1"), 10, 32)
+	if err != nil {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_page"))
+		return
+	}
+	pageSize, err := strconv.ParseInt(c.DefaultQuery("page_size", "20"), 10, 32)
+	if err != nil {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_page_size_range", contractor.MaxPageSize))
+		return
+	}
+
+	response, err := s.contractors.ListExpiringAccreditations(c.Request.Context(), withinDays, int32(page), int32(pageSize))
+	if err != nil {
+		logger.Errorf("Ошибка ListExpiringAccreditations(within_days=%d): %v", withinDays, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// updateContractorAccreditationHandler обрабатывает PUT /api/v1/admin/contractors/:id/accreditation.
+// Заменяет статус, срок и документы аккредитации; импорт их больше не меняет.
+func (s *Server) updateContractorAccreditationHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "updateContractorAccreditationHandler")
+
+	contractorID, ok := parseContractorID(c)
+	if !ok {
+		return
+	}
+
+	var req api_models.UpdateAccreditationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	response, err := s.contractors.UpdateAccreditation(c.Request.Context(), contractorID, req)
+	if err != nil {
+		logger.Errorf("Ошибка UpdateAccreditation(id=%d): %v", contractorID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// listContractorDuplicatesHandler обрабатывает GET /api/v1/admin/contractors/duplicates.
+// Возвращает группы подрядчиков с одинаковым ИНН (без учёта нецифровых символов)
+// и сходство их наименований.
+func (s *Server) listContractorDuplicatesHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "listContractorDuplicatesHandler")
+
+	response, err := s.contractors.ListDuplicates(c.Request.Context())
+	if err != nil {
+		logger.Errorf("Ошибка ListDuplicates: %v", err)
+		respondErrorCode(c, err, "contractor_merge_conflict")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// mergeContractorsHandler обрабатывает POST /api/v1/admin/contractors/merge.
+// Переносит предложения и контакты duplicate_id на master_id и удаляет дубликат.
+func (s *Server) mergeContractorsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "mergeContractorsHandler")
+
+	var req api_models.MergeContractorsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		logger.Errorf("user_id отсутствует в контексте")
+		respondStatus(c, http.StatusUnauthorized, i18n.Errorf("auth.not_authenticated"))
+		return
+	}
+	uid, ok := userID.(int64)
+	if !ok {
+		logger.Errorf("user_id имеет неожиданный тип: %T", userID)
+		respondInternal(c, fmt.Errorf("invalid user_id type"))
+		return
+	}
+
+	response, err := s.contractors.MergeContractors(c.Request.Context(), req, uid)
+	if err != nil {
+		logger.Errorf("Ошибка MergeContractors(master=%d, duplicate=%d): %v", req.MasterID, req.DuplicateID, err)
+		respondErrorCode(c, err, "contractor_merge_conflict")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func parseContractorID(c *gin.Context) (int64, bool) {
+	contractorID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || contractorID <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return 0, false
+	}
+	return contractorID, true
+}
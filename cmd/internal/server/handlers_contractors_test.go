@@ -0,0 +1,132 @@
+package server_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil/servertest"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR CONTRACTOR ACCREDITATION (Integration: Router + Handler + Service)
+
+What user problems does this protect us from?
+================================================================================
+1. Accreditation edited by users without contractors:manage
+2. The expiring list shadowed by /contractors/:id ("expiring-accreditation" is not an id)
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: GET /contractors/expiring-accreditation
+- GIVEN a viewer and no within_days
+  WHEN the list is requested
+  THEN 200 with within_days from contractors.accreditation_warn_days
+- GIVEN within_days=abc → 400
+
+SCENARIO 2: PUT /admin/contractors/:id/accreditation
+- GIVEN an editor → 403, no DB calls
+- GIVEN an admin and a valid body → 200 with the stored accreditation
+- GIVEN an admin and an unknown status → 400 validation_failed
+- GIVEN an unknown contractor → 404
+*/
+
+func TestListExpiringAccreditations_Handler(t *testing.T) {
+	t.Run("default within_days", func(t *testing.T) {
+		h := servertest.New(t)
+		h.Store.EXPECT().ListExpiringAccreditations(gomock.Any(), gomock.Any()).Return([]db.Contractor{}, nil)
+		h.Store.EXPECT().CountExpiringAccreditations(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+
+		w := h.Do(t, http.MethodGet, "/api/v1/contractors/expiring-accreditation", nil, servertest.Viewer)
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp api_models.ExpiringAccreditationsResponse
+		servertest.DecodeJSON(t, w, &resp)
+		assert.Equal(t, h.Config.Contractors.AccreditationWarnDays, resp.WithinDays)
+		assert.Empty(t, resp.Items)
+	})
+
+	t.Run("invalid within_days", func(t *testing.T) {
+		h := servertest.New(t)
+
+		w := h.Do(t, http.MethodGet, "/api/v1/contractors/expiring-accreditation?within_days=abc", nil, servertest.Viewer)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestUpdateContractorAccreditation_Handler(t *testing.T) {
+	validUntil := time.Now().AddDate(1, 0, 0).Format(time.DateOnly)
+	body := map[string]any{
+		"status":      api_models.AccreditationAccredited,
+		"valid_until": validUntil,
+		"documents":   []map[string]string{{"title": "Свидетельство СРО"}},
+	}
+
+	t.Run("editor", func(t *testing.T) {
+		h := servertest.New(t)
+
+		w := h.Do(t, http.MethodPut, "/api/v1/admin/contractors/5/accreditation", body, servertest.Editor)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("admin", func(t *testing.T) {
+		h := servertest.New(t)
+		h.Store.EXPECT().UpdateContractorAccreditation(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.UpdateContractorAccreditationParams) (db.Contractor, error) {
+				return db.Contractor{
+					ID:                      arg.ID,
+					Title:                   "ООО Альфа",
+					Inn:                     "7700000001",
+					AccreditationStatus:     arg.AccreditationStatus,
+					AccreditationValidUntil: arg.AccreditationValidUntil,
+					AccreditationDocuments:  json.RawMessage(arg.AccreditationDocuments),
+					AccreditationSource:     api_models.AccreditationSourceManual,
+				}, nil
+			})
+
+		w := h.Do(t, http.MethodPut, "/api/v1/admin/contractors/5/accreditation", body, servertest.Admin)
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp api_models.ContractorResponse
+		servertest.DecodeJSON(t, w, &resp)
+		assert.Equal(t, api_models.AccreditationAccredited, resp.Accreditation.Status)
+		require.NotNil(t, resp.Accreditation.ValidUntil)
+		assert.Equal(t, validUntil, *resp.Accreditation.ValidUntil)
+		assert.Equal(t, api_models.AccreditationSourceManual, resp.Accreditation.Source)
+		require.Len(t, resp.Accreditation.Documents, 1)
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		h := servertest.New(t)
+
+		w := h.Do(t, http.MethodPut, "/api/v1/admin/contractors/5/accreditation", map[string]any{"status": "active"}, servertest.Admin)
+
+		require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		var resp struct {
+			Code string `json:"code"`
+		}
+		servertest.DecodeJSON(t, w, &resp)
+		assert.Equal(t, "validation_failed", resp.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		h := servertest.New(t)
+		h.Store.EXPECT().UpdateContractorAccreditation(gomock.Any(), gomock.Any()).Return(db.Contractor{}, sql.ErrNoRows)
+
+		w := h.Do(t, http.MethodPut, "/api/v1/admin/contractors/5/accreditation", body, servertest.Admin)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,23 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getDashboardHandler обрабатывает GET /api/v1/dashboard.
+// Сводка главной страницы по тендерам организации пользователя (администратор
+// видит все организации); см. services/dashboard.
+func (s *Server) getDashboardHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "getDashboardHandler")
+
+	response, err := s.dashboard.GetSummary(c.Request.Context(), requestOrganizationScope(c))
+	if err != nil {
+		logger.Errorf("Ошибка GetSummary: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
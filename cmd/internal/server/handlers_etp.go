@@ -0,0 +1,112 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/etp"
+)
+
+// fetchTenderHandler обрабатывает POST /api/v1/tenders/fetch — получение
+// тендера с ЭТП по etp_id: парсер скачивает и разбирает тендер, затем
+// импортирует его в организацию пользователя. Ход задачи — в
+// GET /api/v1/tenders/fetch/:task_id и в потоке GET /api/v1/events.
+//
+// Request:  TenderFetchRequest
+// Response: 202 + TenderFetchResponse (deduplicated=true — тот же тендер уже
+// получается, возвращена его задача)
+// Errors:   400, 404 (тендер не найден на ЭТП), 409 tender_already_exists
+// (тендер импортирован, conflicts — TenderFetchConflict; повтор — force=true),
+// 502 (парсер недоступен), 503 (circuit breaker разомкнут)
+func (s *Server) fetchTenderHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "fetchTenderHandler")
+
+	var req api_models.TenderFetchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	result, err := s.etpFetch.Fetch(c.Request.Context(), etp.FetchRequest{
+		TenderFetchRequest: req,
+		UserID:             sql.NullInt64{Int64: userID, Valid: true},
+		OrganizationID:     c.GetInt64("organization_id"),
+	})
+	if err != nil {
+		if errors.Is(err, etp.ErrParserUnavailable) {
+			logger.Errorf("Ошибка получения тендера %s: %v", req.EtpID, err)
+			respondParserUnavailable(c, err)
+			return
+		}
+		logger.Warnf("Получение тендера %s отклонено: %v", req.EtpID, err)
+		respondErrorCode(c, err, "tender_already_exists")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, result)
+}
+
+// getTenderFetchHandler обрабатывает GET /api/v1/tenders/fetch/:task_id —
+// статус задачи получения тендера. Пока итог не известен, статус
+// запрашивается у парсера; stored=true — ответ построен по записи.
+//
+// Response: 200 + ParseTask (tender_id — импортированный тендер)
+// Errors:   404 (нет такой задачи получения или она другой организации), 500
+func (s *Server) getTenderFetchHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "getTenderFetchHandler")
+	taskID := c.Param("task_id")
+
+	task, err := s.etpFetch.Status(c.Request.Context(), taskID, requestOrganizationScope(c))
+	if err != nil {
+		logger.Errorf("Ошибка Status(%s): %v", taskID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// listTenderSyncsHandler обрабатывает GET /api/v1/tenders/:id/syncs —
+// обновления тендера с ЭТП по расписанию, последние первыми: статус,
+// версии исходного JSON до и после, новые предложения и изменения цен.
+//
+// Query:    limit (по умолчанию 20, до 100), offset
+// Response: 200 + TenderSyncsResponse
+// Errors:   400, 404 (тендер другой организации), 500
+func (s *Server) listTenderSyncsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "listTenderSyncsHandler")
+
+	tenderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || tenderID <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_tender_id_format"))
+		return
+	}
+	limit64, err := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 32)
+	if err != nil || limit64 <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.limit_positive"))
+		return
+	}
+	offset64, err := strconv.ParseInt(c.DefaultQuery("offset", "0"), 10, 32)
+	if err != nil || offset64 < 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.offset_non_negative"))
+		return
+	}
+
+	result, err := s.etpSync.List(c.Request.Context(), tenderID, int32(limit64), int32(offset64))
+	if err != nil {
+		logger.Errorf("Ошибка List(tender_id=%d): %v", tenderID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
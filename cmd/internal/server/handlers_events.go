@@ -0,0 +1,79 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/live"
+)
+
+// streamEventsHandler обрабатывает GET /api/v1/events — поток событий
+// текущего пользователя в формате Server-Sent Events (EventSource в браузере;
+// cookie сессии передаются как у любого GET).
+//
+// Поле event — тип события (live.TypeParseTaskStatus, TypeImportProgress,
+// TypeLotAIAnalyzed), data — JSON. В простаивающий поток раз в
+// live.heartbeat_interval пишется комментарий, чтобы прокси не закрывали
+// соединение. Поток завершается, когда клиент закрывает соединение; события,
+// пропущенные во время переподключения, не повторяются.
+//
+// Errors: 401, 429 (открыто live.max_connections_per_user потоков)
+func (s *Server) streamEventsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "streamEventsHandler")
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	sub, err := s.live.Subscribe(userID, c.GetInt64("organization_id"))
+	if err != nil {
+		if errors.Is(err, live.ErrTooManyConnections) {
+			respondCode(c, http.StatusTooManyRequests, "too_many_streams", "request.too_many_streams")
+			return
+		}
+		logger.Errorf("Ошибка подписки на поток событий пользователя %d: %v", userID, err)
+		respondInternal(c, err)
+		return
+	}
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// nginx не должен буферизовать поток
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	if !writeHeartbeat(c) {
+		return
+	}
+	logger.Debugf("Открыт поток событий пользователя %d", userID)
+
+	heartbeat := time.NewTicker(s.config.Live.HeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			logger.Debugf("Поток событий пользователя %d закрыт клиентом", userID)
+			return
+		case msg := <-sub.C():
+			c.SSEvent(msg.Type, msg.Data)
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			if !writeHeartbeat(c) {
+				return
+			}
+		}
+	}
+}
+
+// writeHeartbeat пишет в поток SSE-комментарий. false — соединение закрыто.
+func writeHeartbeat(c *gin.Context) bool {
+	if _, err := io.WriteString(c.Writer, ": heartbeat\n\n"); err != nil {
+		return false
+	}
+	c.Writer.Flush()
+	return true
+}
@@ -0,0 +1,114 @@
+package server_test
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/live"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil/servertest"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR THE LIVE EVENT STREAM (servertest harness)
+
+What user problems does this protect us from?
+================================================================================
+1. The upload page polling the parser every second to show progress
+2. Proxies closing an idle stream while a long import is running
+3. Anonymous clients subscribing to someone's events
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Stream
+- GIVEN an authenticated user opens GET /api/v1/events
+  THEN the response is text/event-stream and starts with a heartbeat comment
+- WHEN an event is published to the user THEN it arrives as "event: <type>" + JSON data
+- WHEN nothing happens for heartbeat_interval THEN another heartbeat is written
+
+SCENARIO 2: Access
+- GIVEN no session cookie THEN 401
+- GIVEN max_connections_per_user open streams THEN the next one gets 429
+*/
+
+func withFastHeartbeat(cfg *config.Config) {
+	cfg.Live.HeartbeatInterval = 50 * time.Millisecond
+	cfg.Live.MaxConnectionsPerUser = 1
+}
+
+// openStream открывает поток событий user на реальном HTTP-сервере: запись
+// в поток и чтение ответа идут параллельно.
+func openStream(t *testing.T, h *servertest.Harness, user *servertest.User) *bufio.Reader {
+	t.Helper()
+	srv := httptest.NewServer(h.Handler)
+	t.Cleanup(srv.Close)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/v1/events", nil)
+	require.NoError(t, err)
+	req.AddCookie(&http.Cookie{Name: h.Config.Auth.CookieAccessName, Value: h.AccessToken(t, user)})
+	resp, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	return bufio.NewReader(resp.Body)
+}
+
+// readFrame читает одно сообщение SSE (до пустой строки).
+func readFrame(t *testing.T, stream *bufio.Reader) []string {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := stream.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+		if line == "" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
+
+func TestEvents_Stream(t *testing.T) {
+	h := servertest.New(t, withFastHeartbeat)
+	stream := openStream(t, h, servertest.Editor)
+
+	assert.Equal(t, []string{": heartbeat"}, readFrame(t, stream))
+
+	h.Live.PublishUser(servertest.Editor.ID, live.Message{Type: live.TypeImportProgress, Data: live.ImportProgressData{
+		TaskID: "t-1", TenderID: "ETP-1", LotsDone: 2, LotsTotal: 5,
+	}})
+
+	// Между подпиской и публикацией мог пройти heartbeat
+	frame := readFrame(t, stream)
+	for len(frame) == 1 && frame[0] == ": heartbeat" {
+		frame = readFrame(t, stream)
+	}
+	require.Len(t, frame, 2)
+	assert.Equal(t, "event:"+live.TypeImportProgress, strings.ReplaceAll(frame[0], " ", ""))
+	assert.JSONEq(t, `{"task_id":"t-1","tender_id":"ETP-1","lots_done":2,"lots_total":5}`, strings.TrimSpace(strings.TrimPrefix(frame[1], "data:")))
+
+	assert.Equal(t, []string{": heartbeat"}, readFrame(t, stream))
+}
+
+func TestEvents_Access(t *testing.T) {
+	h := servertest.New(t, withFastHeartbeat)
+
+	w := h.Do(t, http.MethodGet, "/api/v1/events", nil, nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	stream := openStream(t, h, servertest.Editor)
+	readFrame(t, stream)
+
+	w = h.Do(t, http.MethodGet, "/api/v1/events", nil, servertest.Editor)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
@@ -0,0 +1,232 @@
+package server
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+)
+
+// utf8BOM в начале файла нужен Excel, чтобы он открыл кириллицу в UTF-8, а не в cp1251.
+const utf8BOM = "\ufeff"
+
+var (
+	tenderExportHeader = []string{
+		"id", "etp_id", "title", "data_prepared_on_date", "object_address",
+		"executor_name", "proposals_count", "category_id", "total_cost", "has_winner",
+	}
+	catalogExportHeader = []string{
+		"id", "standard_job_title", "description", "kind", "status", "unit",
+		"parent_id", "merged_into_id", "is_pinned", "created_at", "updated_at",
+	}
+)
+
+// csvStream пишет CSV прямо в ответ, сбрасывая буфер после каждой порции.
+// Заголовки ответа отправляются только при первой записи, поэтому ошибка
+// первой порции ещё может вернуться клиенту обычным JSON.
+type csvStream struct {
+	c        *gin.Context
+	filename string
+	comma    rune
+	w        *csv.Writer
+}
+
+func newCSVStream(c *gin.Context, filename string, comma rune) *csvStream {
+	return &csvStream{c: c, filename: filename, comma: comma}
+}
+
+// started сообщает, ушли ли уже заголовки ответа клиенту.
+func (s *csvStream) started() bool {
+	return s.w != nil
+}
+
+func (s *csvStream) start(header []string) error {
+	s.c.Header("Content-Type", "text/csv; charset=utf-8")
+	s.c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, s.filename))
+	s.c.Status(http.StatusOK)
+
+	if _, err := s.c.Writer.WriteString(utf8BOM); err != nil {
+		return err
+	}
+	s.w = csv.NewWriter(s.c.Writer)
+	s.w.Comma = s.comma
+	return s.w.Write(header)
+}
+
+// writeBatch пишет порцию строк и отправляет её клиенту.
+func (s *csvStream) writeBatch(rows [][]string) error {
+	if err := s.w.WriteAll(rows); err != nil {
+		return err
+	}
+	s.c.Writer.Flush()
+	return nil
+}
+
+// fail обрабатывает ошибку выгрузки. До начала потока возвращается 500.
+// После начала статус уже отправлен, поэтому ответ просто завершается:
+// ошибка остаётся в логе, а клиент получает неполный файл.
+func (s *csvStream) fail(err error) {
+	if !s.started() {
+		respondError(s.c, err)
+		return
+	}
+	s.c.Abort()
+}
+
+// exportTendersCSVHandler - GET /api/v1/tenders/export.csv.
+// Принимает те же фильтры и сортировку, что и GET /api/v1/tenders (page/page_size игнорируются),
+// и выгружает все подходящие тендеры своей организации (admin — всех). Параметр delimiter=semicolon — для Excel в русской локали.
+func (s *Server) exportTendersCSVHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "exportTendersCSVHandler")
+
+	query, err := parseTenderListQuery(c.Request.URL.Query())
+	if err != nil {
+		respondStatus(c, http.StatusBadRequest, err)
+		return
+	}
+	comma, err := parseCSVDelimiter(c.Request.URL.Query())
+	if err != nil {
+		respondStatus(c, http.StatusBadRequest, err)
+		return
+	}
+	query.OrganizationID = requestOrganizationScope(c)
+
+	stream := newCSVStream(c, "tenders_"+time.Now().Format("20060102")+".csv", comma)
+	var offset, total int32
+
+	for {
+		batch, err := s.store.ListTenders(c.Request.Context(), query.exportBatchParams(offset))
+		if err != nil {
+			logger.Errorf("Ошибка выгрузки тендеров (offset=%d): %v", offset, err)
+			stream.fail(err)
+			return
+		}
+
+		if !stream.started() {
+			if err := stream.start(tenderExportHeader); err != nil {
+				logger.Warnf("Клиент прервал выгрузку тендеров: %v", err)
+				return
+			}
+		}
+
+		rows := make([][]string, 0, len(batch))
+		for _, t := range batch {
+			rows = append(rows, tenderExportRow(t))
+		}
+		if err := stream.writeBatch(rows); err != nil {
+			logger.Warnf("Клиент прервал выгрузку тендеров: %v", err)
+			return
+		}
+
+		total += int32(len(batch))
+		if len(batch) < exportBatchSize {
+			break
+		}
+		offset += exportBatchSize
+	}
+
+	logger.Infof("Выгружено тендеров в CSV: %d", total)
+}
+
+// exportCatalogCSVHandler - GET /api/v1/catalog/export.csv.
+// Фильтры: kind, status, pinned, parent_id (все опциональны), delimiter — как у тендеров.
+func (s *Server) exportCatalogCSVHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "exportCatalogCSVHandler")
+
+	query, err := parseCatalogExportQuery(c.Request.URL.Query())
+	if err != nil {
+		respondStatus(c, http.StatusBadRequest, err)
+		return
+	}
+	comma, err := parseCSVDelimiter(c.Request.URL.Query())
+	if err != nil {
+		respondStatus(c, http.StatusBadRequest, err)
+		return
+	}
+
+	stream := newCSVStream(c, "catalog_"+time.Now().Format("20060102")+".csv", comma)
+	var afterID int64
+	total := 0
+
+	for {
+		batch, err := s.store.ExportCatalogPositions(c.Request.Context(), query.batchParams(afterID))
+		if err != nil {
+			logger.Errorf("Ошибка выгрузки каталога (after_id=%d): %v", afterID, err)
+			stream.fail(err)
+			return
+		}
+
+		if !stream.started() {
+			if err := stream.start(catalogExportHeader); err != nil {
+				logger.Warnf("Клиент прервал выгрузку каталога: %v", err)
+				return
+			}
+		}
+
+		rows := make([][]string, 0, len(batch))
+		for _, p := range batch {
+			rows = append(rows, catalogExportRow(p))
+		}
+		if err := stream.writeBatch(rows); err != nil {
+			logger.Warnf("Клиент прервал выгрузку каталога: %v", err)
+			return
+		}
+
+		total += len(batch)
+		if len(batch) < exportBatchSize {
+			break
+		}
+		afterID = batch[len(batch)-1].ID
+	}
+
+	logger.Infof("Выгружено позиций каталога в CSV: %d", total)
+}
+
+func tenderExportRow(t db.ListTendersRow) []string {
+	date := ""
+	if t.DataPreparedOnDate.Valid {
+		date = t.DataPreparedOnDate.Time.Format(tenderListDateLayout)
+	}
+	categoryID := ""
+	if t.CategoryID.Valid {
+		categoryID = strconv.FormatInt(t.CategoryID.Int64, 10)
+	}
+	return []string{
+		strconv.FormatInt(t.ID, 10),
+		t.EtpID,
+		t.Title,
+		date,
+		t.ObjectAddress,
+		t.ExecutorName,
+		strconv.FormatInt(t.ProposalsCount, 10),
+		categoryID,
+		t.TotalCost.String,
+		strconv.FormatBool(t.HasWinner),
+	}
+}
+
+func catalogExportRow(p db.ExportCatalogPositionsRow) []string {
+	optionalID := func(v int64, valid bool) string {
+		if !valid {
+			return ""
+		}
+		return strconv.FormatInt(v, 10)
+	}
+	return []string{
+		strconv.FormatInt(p.ID, 10),
+		p.StandardJobTitle,
+		p.Description.String,
+		p.Kind,
+		p.Status,
+		p.UnitName.String,
+		optionalID(p.ParentID.Int64, p.ParentID.Valid),
+		optionalID(p.MergedIntoID.Int64, p.MergedIntoID.Valid),
+		strconv.FormatBool(p.IsPinned),
+		p.CreatedAt.Format(time.RFC3339),
+		p.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,101 @@
+package server_test
+
+import (
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil/servertest"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR HANDLERS THROUGH THE FULL ROUTER (servertest harness)
+
+What user problems does this protect us from?
+================================================================================
+1. A route wired without its permission or organization check
+2. Handlers that pass tests in isolation but break behind the real middleware chain
+3. Refactoring NewServer without noticing that a route changed its contract
+
+Approach:
+  - servertest.New builds the server exactly like cmd/main, with db.MockStore.
+  - Requests go through CORS, auth, CSRF, permission and organization middleware.
+  - Every DB call a request makes must be declared on h.Store; unexpected calls fail the test.
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Authentication
+- GIVEN no access token WHEN GET /api/v1/proposals/7/coverage THEN 401, no DB calls
+- GIVEN no worker key WHEN calling /internal/worker THEN 401
+
+SCENARIO 2: Permissions
+- GIVEN an editor WHEN POST /api/v1/admin/tenders/7/purge THEN 403, no DB calls
+- GIVEN an admin WHEN POST /api/v1/admin/tenders/7/purge?dry_run=true
+  THEN 200 with row counts, nothing is deleted (no ExecTx)
+
+SCENARIO 3: Organization scope
+- GIVEN an analyst of organization 1 and a proposal of organization 2
+  WHEN GET /api/v1/proposals/7/coverage THEN 404 and the service is not called
+*/
+
+func TestHarness_RequiresAuthentication(t *testing.T) {
+	h := servertest.New(t)
+
+	w := h.Do(t, http.MethodGet, "/api/v1/proposals/7/coverage", nil, nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = h.Do(t, http.MethodGet, "/internal/worker/norm-version", nil, nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestHarness_PurgeTender_ForbiddenForEditor(t *testing.T) {
+	h := servertest.New(t)
+
+	w := h.Do(t, http.MethodPost, "/api/v1/admin/tenders/7/purge", nil, servertest.Editor)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestHarness_PurgeTender_DryRun(t *testing.T) {
+	h := servertest.New(t)
+
+	h.Store.EXPECT().GetTenderByID(gomock.Any(), int64(7)).Return(db.Tender{ID: 7}, nil)
+	h.Store.EXPECT().CountTenderPurgeRows(gomock.Any(), int64(7)).
+		Return(db.CountTenderPurgeRowsRow{Lots: 1, Proposals: 2, PositionItems: 40}, nil)
+
+	w := h.Do(t, http.MethodPost, "/api/v1/admin/tenders/7/purge?dry_run=true", nil, servertest.Admin)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp api_models.TenderPurgeResponse
+	servertest.DecodeJSON(t, w, &resp)
+	assert.True(t, resp.DryRun)
+	assert.Equal(t, int64(44), resp.TotalRows)
+}
+
+func TestHarness_ProposalCoverage_OtherOrganization(t *testing.T) {
+	h := servertest.New(t)
+
+	h.Store.EXPECT().GetProposalOrganizationID(gomock.Any(), int64(7)).Return(int64(2), nil)
+
+	w := h.Do(t, http.MethodGet, "/api/v1/proposals/7/coverage", nil, servertest.Analyst)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHarness_ProposalCoverage_NotFound(t *testing.T) {
+	h := servertest.New(t)
+
+	h.Store.EXPECT().GetProposalOrganizationID(gomock.Any(), int64(7)).Return(int64(0), sql.ErrNoRows)
+	h.Store.EXPECT().GetProposalByID(gomock.Any(), int64(7)).Return(db.Proposal{}, sql.ErrNoRows)
+
+	w := h.Do(t, http.MethodGet, "/api/v1/proposals/7/coverage", nil, servertest.Analyst)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,25 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/health"
+)
+
+// healthzHandler обрабатывает GET /healthz (liveness): процесс жив и обслуживает HTTP.
+// Зависимости не проверяются, аутентификация не требуется.
+func (s *Server) healthzHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, s.health.Liveness())
+}
+
+// readyzHandler обрабатывает GET /readyz (readiness): БД, версия схемы и парсер.
+// 200 — под готов принимать трафик, 503 — хотя бы одна проверка не пройдена.
+func (s *Server) readyzHandler(c *gin.Context) {
+	response := s.health.Readiness(c.Request.Context())
+	if response.Status != health.StatusOK {
+		c.JSON(http.StatusServiceUnavailable, response)
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/history"
+)
+
+// getTenderHistoryHandler - GET /api/v1/tenders/:id/history.
+// Правки полей тендера через PATCH /api/v1/tenders/:id, новые первыми.
+func (s *Server) getTenderHistoryHandler(c *gin.Context) {
+	s.listFieldHistory(c, "getTenderHistoryHandler", history.EntityTender, "request.invalid_tender_id_format")
+}
+
+// getLotHistoryHandler - GET /api/v1/lots/:id/history.
+// Правки ключевых параметров лота, в том числе применение запуска AI-анализа.
+func (s *Server) getLotHistoryHandler(c *gin.Context) {
+	s.listFieldHistory(c, "getLotHistoryHandler", history.EntityLot, "request.invalid_lot_id")
+}
+
+// listFieldHistory - общая часть обработчиков истории правок.
+func (s *Server) listFieldHistory(c *gin.Context, handlerName, entityType, invalidIDKey string) {
+	logger := s.logger.WithField("handler", handlerName)
+
+	entityID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || entityID <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf(invalidIDKey))
+		return
+	}
+	limit64, err := strconv.ParseInt(c.DefaultQuery("limit", "50"), 10, 32)
+	if err != nil || limit64 <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.limit_positive"))
+		return
+	}
+	offset64, err := strconv.ParseInt(c.DefaultQuery("offset", "0"), 10, 32)
+	if err != nil || offset64 < 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.offset_non_negative"))
+		return
+	}
+
+	result, err := s.history.List(c.Request.Context(), entityType, entityID, int32(limit64), int32(offset64))
+	if err != nil {
+		logger.Errorf("Ошибка List(%s_id=%d): %v", entityType, entityID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
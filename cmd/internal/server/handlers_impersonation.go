@@ -0,0 +1,76 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/auth"
+)
+
+// impersonateUserHandler обрабатывает POST /api/v1/admin/users/:id/impersonate.
+//
+// Начинает сессию имперсонации: access cookie заменяется токеном пользователя
+// с acting_admin_id сроком auth.impersonation_ttl, refresh cookie
+// администратора не трогается. Все запросы под имперсонацией пишутся в
+// журнал, ответы помечаются заголовком X-Impersonated.
+//
+// Response: 201 + ImpersonationResponse
+// Errors:   400 (себя, неактивного пользователя или администратора), 404 (нет пользователя), 500 (БД)
+func (s *Server) impersonateUserHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "impersonateUserHandler")
+
+	userID, actorID, ok := s.parseAdminUserRequest(c, logger)
+	if !ok {
+		return
+	}
+
+	result, err := s.authService.Impersonate(c.Request.Context(), actorID, userID, parseIPAddress(c.ClientIP()), c.Request.UserAgent())
+	if err != nil {
+		logger.Errorf("Ошибка Impersonate: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	s.setSameSiteMode(c)
+	c.SetCookie(
+		s.config.Auth.CookieAccessName,
+		result.AccessToken,
+		int(time.Until(result.Session.ExpiresAt).Seconds()),
+		"/",
+		s.config.Auth.CookieDomain,
+		s.config.Auth.CookieSecure,
+		s.config.Auth.CookieHttpOnly,
+	)
+
+	c.JSON(http.StatusCreated, result.Session)
+}
+
+// endImpersonationHandler обрабатывает POST /api/v1/auth/impersonation/end
+// Завершает сессию имперсонации и удаляет access cookie: фронтенд
+// восстанавливает сессию администратора через POST /api/v1/auth/refresh.
+func (s *Server) endImpersonationHandler(c *gin.Context) {
+	claims, impersonated := impersonationClaims(c)
+	if !impersonated {
+		respondCode(c, http.StatusBadRequest, "not_impersonating", "auth.not_impersonating")
+		return
+	}
+
+	if err := s.authService.EndImpersonation(c.Request.Context(), claims); err != nil {
+		if errors.Is(err, auth.ErrNotImpersonating) {
+			respondCode(c, http.StatusBadRequest, "not_impersonating", "auth.not_impersonating")
+			return
+		}
+		s.logger.WithError(err).Error("failed to end impersonation")
+		respondInternal(c, err)
+		return
+	}
+
+	clearAccessCookie(c, s.config)
+	c.JSON(http.StatusOK, gin.H{
+		"message":         "impersonation ended",
+		"session_id":      claims.ImpersonationID,
+		"acting_admin_id": claims.ActingAdminID,
+	})
+}
@@ -0,0 +1,231 @@
+package server
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/auth"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR ADMIN IMPERSONATION (Integration: Middleware + Handlers + Auth Service)
+
+What user problems does this protect us from?
+================================================================================
+1. Support acting invisibly — every impersonated request carries X-Impersonated and
+   is written to the audit log before the handler runs, with its status after
+2. Support harming the account — password change and logout-all are refused
+3. Stuck impersonation — ending the session clears the access cookie but keeps the
+   admin's refresh cookie, so /auth/refresh restores the admin
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Start
+- GIVEN an admin and an active viewer
+  WHEN POST /admin/users/7/impersonate
+  THEN 201, the access cookie holds the viewer's token for impersonation_ttl,
+  no refresh cookie is issued
+
+SCENARIO 2: Impersonated requests
+- GIVEN an impersonation token WHEN GET /auth/me
+  THEN X-Impersonated: <admin id>, the audit row is written and completed with 200,
+  the body names the acting admin
+- GIVEN an ended session → 401 impersonation_ended, the handler does not run
+- GIVEN POST /auth/logout-all → 403 impersonation_forbidden, audited with 403
+
+SCENARIO 3: End
+- GIVEN an impersonation token → session ended, access cookie cleared, refresh kept
+- GIVEN a regular token → 400 not_impersonating
+*/
+
+func setupImpersonationTestServer(t *testing.T) (*gin.Engine, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	logger := testutil.NewMockLogger()
+	cfg := testConfig()
+	cfg.Auth.ImpersonationTTL = 30 * time.Minute
+
+	authService := auth.NewService(mockStore, cfg, logger, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	server := &Server{store: mockStore, logger: logger, authService: authService, config: cfg}
+
+	protected := router.Group("/api/v1")
+	protected.Use(AuthMiddleware(cfg, authService))
+	protected.Use(CsrfMiddleware())
+	protected.GET("/auth/me", server.meHandler)
+	protected.POST("/auth/logout-all", DenyImpersonated(), server.logoutAllHandler)
+	protected.POST("/auth/impersonation/end", server.endImpersonationHandler)
+	protected.POST("/admin/users/:id/impersonate", RequirePermission(auth.PermissionUsersManage), server.impersonateUserHandler)
+	return router, mockStore
+}
+
+// makeImpersonationToken выпускает токен пользователя userID, от имени
+// которого действует администратор adminID в сессии sessionID.
+func makeImpersonationToken(t *testing.T, userID, adminID, sessionID int64) string {
+	t.Helper()
+	now := time.Now()
+	claims := auth.JWTClaims{
+		UserID:          userID,
+		Role:            auth.RoleViewer,
+		OrganizationID:  1,
+		ActingAdminID:   adminID,
+		ImpersonationID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(30 * time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "tenders-go",
+			Subject:   fmt.Sprintf("%d", userID),
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testJWTSecret))
+	require.NoError(t, err)
+	return signed
+}
+
+func newImpersonationRequest(method, path, accessToken string) *http.Request {
+	req := httptest.NewRequest(method, path, nil)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: accessToken})
+	addCSRF(req, "csrf-token")
+	return req
+}
+
+func TestImpersonateUser_SetsShortLivedAccessCookie(t *testing.T) {
+	router, mockStore := setupImpersonationTestServer(t)
+	now := time.Now()
+	expiresAt := now.Add(30 * time.Minute)
+
+	expectTokenVersion(mockStore, 1, 0)
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT id, email, role").
+				WithArgs(int64(7)).
+				WillReturnRows(sqlmock.NewRows(userByIDColumns).
+					AddRow(int64(7), testEmail, auth.RoleViewer, true, int64(1), nil, now, now, int64(0)))
+			mock.ExpectQuery("INSERT INTO impersonation_sessions").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "admin_id", "user_id", "ip_address", "user_agent", "started_at", "expires_at", "ended_at"}).
+					AddRow(int64(11), int64(1), int64(7), nil, nil, now, expiresAt, nil))
+		}),
+	)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newImpersonationRequest(http.MethodPost, "/api/v1/admin/users/7/impersonate", makeTestAccessToken(t, 1, auth.RoleAdmin)))
+
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	body := parseBody(t, w)
+	assert.Equal(t, float64(11), body["session_id"])
+	assert.Equal(t, float64(1), body["acting_admin_id"])
+
+	access := responseCookie(w, "access_token")
+	require.NotNil(t, access)
+	assert.InDelta(t, (30 * time.Minute).Seconds(), access.MaxAge, 2)
+	assert.Nil(t, responseCookie(w, "refresh_token"), "the admin keeps their refresh cookie")
+	assert.Empty(t, w.Header().Get(impersonatedHeader), "the admin's own request is not impersonated")
+}
+
+func TestImpersonatedRequest_MarkedAndAudited(t *testing.T) {
+	router, mockStore := setupImpersonationTestServer(t)
+	token := makeImpersonationToken(t, 7, 1, 11)
+
+	expectTokenVersion(mockStore, 7, 0)
+	gomock.InOrder(
+		mockStore.EXPECT().InsertImpersonationAudit(gomock.Any(), db.InsertImpersonationAuditParams{
+			Method: http.MethodGet, Path: "/api/v1/auth/me", SessionID: 11,
+		}).Return(int64(42), nil),
+		mockStore.EXPECT().GetUserByID(gomock.Any(), int64(7)).
+			Return(db.GetUserByIDRow{ID: 7, Email: testEmail, Role: auth.RoleViewer, IsActive: true, OrganizationID: 1}, nil),
+		mockStore.EXPECT().CompleteImpersonationAudit(gomock.Any(), db.CompleteImpersonationAuditParams{Status: http.StatusOK, ID: 42}).
+			Return(nil),
+	)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newImpersonationRequest(http.MethodGet, "/api/v1/auth/me", token))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "1", w.Header().Get(impersonatedHeader))
+	impersonation, ok := parseBody(t, w)["impersonation"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, float64(1), impersonation["acting_admin_id"])
+	assert.Equal(t, float64(11), impersonation["session_id"])
+}
+
+func TestImpersonatedRequest_EndedSessionRejected(t *testing.T) {
+	router, mockStore := setupImpersonationTestServer(t)
+
+	expectTokenVersion(mockStore, 7, 0)
+	mockStore.EXPECT().InsertImpersonationAudit(gomock.Any(), gomock.Any()).Return(int64(0), sql.ErrNoRows)
+	// No GetUserByID expected: the handler must not run
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newImpersonationRequest(http.MethodGet, "/api/v1/auth/me", makeImpersonationToken(t, 7, 1, 11)))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "impersonation_ended", parseBody(t, w)["code"])
+	assert.Equal(t, "access_token_expired", w.Header().Get("X-Auth-Error"))
+}
+
+func TestImpersonatedRequest_AccountActionsForbidden(t *testing.T) {
+	router, mockStore := setupImpersonationTestServer(t)
+
+	expectTokenVersion(mockStore, 7, 0)
+	mockStore.EXPECT().InsertImpersonationAudit(gomock.Any(), gomock.Any()).Return(int64(42), nil)
+	mockStore.EXPECT().CompleteImpersonationAudit(gomock.Any(), db.CompleteImpersonationAuditParams{Status: http.StatusForbidden, ID: 42}).
+		Return(nil)
+	// No ExecTx expected: the user's sessions must stay untouched
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newImpersonationRequest(http.MethodPost, "/api/v1/auth/logout-all", makeImpersonationToken(t, 7, 1, 11)))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, "impersonation_forbidden", parseBody(t, w)["code"])
+}
+
+func TestEndImpersonation(t *testing.T) {
+	t.Run("impersonation token", func(t *testing.T) {
+		router, mockStore := setupImpersonationTestServer(t)
+
+		expectTokenVersion(mockStore, 7, 0)
+		mockStore.EXPECT().InsertImpersonationAudit(gomock.Any(), gomock.Any()).Return(int64(42), nil)
+		mockStore.EXPECT().EndImpersonationSession(gomock.Any(), db.EndImpersonationSessionParams{ID: 11, AdminID: 1}).
+			Return(int64(1), nil)
+		mockStore.EXPECT().CompleteImpersonationAudit(gomock.Any(), db.CompleteImpersonationAuditParams{Status: http.StatusOK, ID: 42}).
+			Return(nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newImpersonationRequest(http.MethodPost, "/api/v1/auth/impersonation/end", makeImpersonationToken(t, 7, 1, 11)))
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		access := responseCookie(w, "access_token")
+		require.NotNil(t, access)
+		assert.Less(t, access.MaxAge, 0)
+		assert.Nil(t, responseCookie(w, "refresh_token"), "the admin's refresh cookie restores their session")
+	})
+
+	t.Run("regular token", func(t *testing.T) {
+		router, mockStore := setupImpersonationTestServer(t)
+		expectTokenVersion(mockStore, 7, 0)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newImpersonationRequest(http.MethodPost, "/api/v1/auth/impersonation/end", makeTestAccessToken(t, 7, auth.RoleViewer)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "not_impersonating", parseBody(t, w)["code"])
+	})
+}
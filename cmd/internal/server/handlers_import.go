@@ -3,19 +3,26 @@ package server
 import (
 	"bytes"
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/importer"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/live"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/refcache"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/webhooks"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
 )
 
-const (
-	defaultImportTimeout = 5 * time.Minute
-	maxRequestBodySize   = 50 * 1024 * 1024 // 50 MB
-)
+const defaultImportTimeout = 5 * time.Minute
 
 // ImportTenderHandler — импорт полного тендера через POST /api/v1/import-tender.
 //
@@ -26,27 +33,79 @@ const (
 //  4. Передаёт payload + raw в сервисный слой. Сервис в одной транзакции:
 //     - создаёт/обновляет тендер и связанные сущности,
 //     - делает UPSERT в tender_raw_data(raw_data) тем самым исходным raw.
-//  5. Возвращает 201 с db_id и map ID лотов.
+//  5. Возвращает 201 с db_id, map ID лотов и import_id для трассировки.
+//
+// Организацию-владельца задает поле organization_id (парсер берет его из
+// загрузки, см. ProxyUploadHandler); без него тендер создается в организации
+// по умолчанию. Повторный импорт тендера от имени другой организации
+// отклоняется с 409.
+//
+// Тело больше import.max_payload_size отклоняется с 413. Тело больше
+// import.stream_threshold или без Content-Length импортируется потоково
+// (ImportTenderStream): JSON разбирается по одному лоту и целиком в памяти не
+// собирается. Ответ тот же; поля тендера в таком JSON должны идти до "lots".
+//
+// С ?dry_run=true ничего не пишет: после разбора JSON выполняет валидацию и
+// дополнительные проверки (повторяющиеся ключи, сверка итогов, неизвестные
+// единицы измерения) и возвращает 200 с ImportValidationReport — даже если
+// payload невалиден, чтобы парсер получил все найденные проблемы. Dry-run
+// всегда читает тело целиком.
+//
+// ?task_id= — задача парсера, загрузившая файл (ProxyUploadHandler): после
+// каждого лота пользователь, загрузивший файл, получает import.progress в
+// потоке GET /api/v1/events. Неизвестная задача импорту не мешает.
 //
 // Возможные ответы:
 //   - 201 Created — успешный импорт
-//   - 400 Bad Request — невалидный JSON или провал валидации
+//   - 200 OK — отчёт dry-run
+//   - 400 Bad Request — невалидный JSON или dry_run; провал валидации —
+//     validation_failed со всеми нарушениями в details (field — путь поля,
+//     например lots["lot_1"].proposals["ООО Альфа"].inn)
+//   - 409 Conflict — тендер с этим tender_id принадлежит другой организации
+//   - 413 Request Entity Too Large — тело больше import.max_payload_size
 //   - 500 Internal Server Error — ошибка бизнес-логики/БД
 func (s *Server) ImportTenderHandler(c *gin.Context) {
 	logger := s.logger.WithField("handler", "ImportTenderHandler")
 	logger.Info("Начало обработки запроса на импорт тендера")
 
+	dryRun := false
+	if v := c.Query("dry_run"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.dry_run_bool"))
+			return
+		}
+		dryRun = parsed
+	}
+
+	// Ограничиваем размер для защиты от OOM: заявленный размер проверяем сразу,
+	// фактический — при чтении (Content-Length может отсутствовать)
+	maxSize := s.config.Import.MaxPayloadSize
+	if c.Request.ContentLength > maxSize {
+		s.payloadTooLarge(c, logger)
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize)
+
+	if taskID := c.Query("task_id"); taskID != "" && !dryRun {
+		s.withImportProgress(c, logger, taskID)
+	}
+
+	if !dryRun && (c.Request.ContentLength < 0 || c.Request.ContentLength > s.config.Import.StreamThreshold) {
+		s.importTenderStream(c, logger)
+		return
+	}
+
 	// --- 1) Считываем исходный JSON один раз в raw ---
-	// Ограничиваем размер для защиты от OOM (читаем +1 байт для детектирования превышения)
-	raw, err := io.ReadAll(io.LimitReader(c.Request.Body, maxRequestBodySize+1))
+	raw, err := io.ReadAll(c.Request.Body)
 	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			s.payloadTooLarge(c, logger)
+			return
+		}
 		logger.Errorf("Ошибка чтения тела запроса: %v", err)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("не удалось прочитать тело запроса: %w", err)))
-		return
-	}
-	if int64(len(raw)) > maxRequestBodySize {
-		logger.Warnf("Тело запроса превышает лимит %d байт", maxRequestBodySize)
-		c.JSON(http.StatusRequestEntityTooLarge, errorResponse(fmt.Errorf("тело запроса превышает лимит %d байт", maxRequestBodySize)))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.body_read_failed_wrapped", err))
 		return
 	}
 	// Важно: вернуть тело, чтобы биндер смог его прочитать повторно
@@ -56,14 +115,25 @@ func (s *Server) ImportTenderHandler(c *gin.Context) {
 	var payload api_models.FullTenderData
 	if err := c.ShouldBindJSON(&payload); err != nil {
 		logger.Errorf("Ошибка парсинга JSON: %v", err)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("некорректный JSON: %w", err)))
+		respondBindError(c, err)
+		return
+	}
+
+	if dryRun {
+		report, err := s.tenderService.ValidateImport(c.Request.Context(), &payload, raw)
+		if err != nil {
+			logger.Errorf("Ошибка dry-run импорта: %v", err)
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, report)
 		return
 	}
 
 	// --- 3) Валидация бизнес-правил ---
 	if err := payload.Validate(); err != nil {
 		logger.Warnf("Невалидные данные для импорта тендера: %v", err)
-		c.JSON(http.StatusBadRequest, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
@@ -74,20 +144,235 @@ func (s *Server) ImportTenderHandler(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), defaultImportTimeout)
 	defer cancel()
 
-	dbID, lotsMap, newItemsPending, err := s.tenderService.ImportFullTender(ctx, &payload, raw)
+	dbID, lotsMap, newItemsPending, importID, err := s.tenderService.ImportFullTender(ctx, &payload, raw)
 	if err != nil {
 		// Ошибка уже должна быть залогирована в сервисе
+		var conflictErr *apierrors.ConflictError
+		if errors.As(err, &conflictErr) {
+			logger.Warnf("Импорт тендера отклонен: %v", err)
+			respondError(c, err)
+			return
+		}
 		logger.Errorf("Ошибка импорта тендера: %v", err)
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
-	logger.Infof("Импорт завершён. TenderID=%s, DB_ID=%d, lots=%v, new_pending=%v", payload.TenderID, dbID, lotsMap, newItemsPending)
-
 	// --- 5) Ответ ---
-	c.JSON(http.StatusCreated, api_models.ImportTenderResponse{
+	s.respondTenderImported(c, logger, payload.TenderID, api_models.ImportTenderResponse{
 		TenderDBID:             dbID,
 		LotIDsMap:              lotsMap,
 		NewCatalogItemsPending: newItemsPending,
+		ImportID:               importID,
+	})
+}
+
+// importTenderStream — потоковая ветка ImportTenderHandler для больших тел.
+// Таймаут импорта здесь включает и чтение тела: сервис сохраняет его во
+// временный файл до открытия транзакции.
+func (s *Server) importTenderStream(c *gin.Context, logger logging.Logger) {
+	logger.Infof("Потоковый импорт тендера, Content-Length: %d", c.Request.ContentLength)
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), defaultImportTimeout)
+	defer cancel()
+
+	result, err := s.tenderService.ImportTenderStream(ctx, c.Request.Body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		var validationErr *apierrors.ValidationError
+		var conflictErr *apierrors.ConflictError
+		switch {
+		case errors.As(err, &tooLarge):
+			s.payloadTooLarge(c, logger)
+		case errors.As(err, &validationErr):
+			logger.Warnf("Невалидные данные для импорта тендера: %v", err)
+			respondStatus(c, http.StatusBadRequest, err)
+		case errors.As(err, &conflictErr):
+			logger.Warnf("Импорт тендера отклонен: %v", err)
+			respondError(c, err)
+		default:
+			logger.Errorf("Ошибка потокового импорта тендера: %v", err)
+			respondError(c, err)
+		}
+		return
+	}
+
+	s.respondTenderImported(c, logger, result.TenderID, result.ImportTenderResponse)
+}
+
+// withImportProgress подключает прогресс импорта к потоку событий
+// пользователя, загрузившего файл задачи taskID. Без открытых потоков задача
+// не запрашивается; задача без пользователя или неизвестная — импорт без прогресса.
+func (s *Server) withImportProgress(c *gin.Context, logger logging.Logger, taskID string) {
+	if !s.live.HasSubscribers() {
+		return
+	}
+	task, found, err := s.parseTasks.Lookup(c.Request.Context(), taskID, sql.NullInt64{})
+	if err != nil {
+		logger.Warnf("Прогресс импорта задачи %s не публикуется: %v", taskID, err)
+		return
+	}
+	if !found || !task.UserID.Valid {
+		return
+	}
+
+	userID := task.UserID.Int64
+	ctx := importer.WithProgress(c.Request.Context(), func(etpID string, lotsDone, lotsTotal int) {
+		s.live.PublishUser(userID, live.Message{Type: live.TypeImportProgress, Data: live.ImportProgressData{
+			TaskID:    taskID,
+			TenderID:  etpID,
+			LotsDone:  lotsDone,
+			LotsTotal: lotsTotal,
+		}})
 	})
+	c.Request = c.Request.WithContext(ctx)
+}
+
+// respondTenderImported завершает успешный импорт: сбрасывает кэш справочников,
+// публикует вебхук tender.imported и отвечает 201.
+func (s *Server) respondTenderImported(c *gin.Context, logger logging.Logger, tenderID string, resp api_models.ImportTenderResponse) {
+	// Импорт создаёт недостающие типы, разделы, категории и единицы измерения
+	s.refCache.Invalidate(c.Request.Context(), refcache.Groups...)
+
+	logger.Infof("Импорт завершён. TenderID=%s, DB_ID=%d, lots=%v, new_pending=%v, import_id=%d",
+		tenderID, resp.TenderDBID, resp.LotIDsMap, resp.NewCatalogItemsPending, resp.ImportID)
+
+	s.publishWebhook(c, logger, webhooks.EventTenderImported, webhooks.TenderImportedData{
+		TenderDBID: resp.TenderDBID,
+		TenderID:   tenderID,
+		ImportID:   resp.ImportID,
+		LotIDsMap:  resp.LotIDsMap,
+	})
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// payloadTooLarge отвечает 413 с лимитом из конфигурации.
+func (s *Server) payloadTooLarge(c *gin.Context, logger logging.Logger) {
+	limit := s.config.Import.MaxPayloadSize
+	logger.Warnf("Тело запроса превышает лимит %d байт", limit)
+	respondStatus(c, http.StatusRequestEntityTooLarge, i18n.Errorf("request.import_payload_too_large", limit))
+}
+
+// GetImportTraceHandler - GET /api/v1/admin/imports/:id/trace.
+// Возвращает тайминги этапов импорта: ожидание транзакции, core-данные, лоты,
+// позиции (с отдельным временем обращений к matching_cache), raw JSON и коммит.
+func (s *Server) GetImportTraceHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "GetImportTraceHandler")
+
+	idStr := c.Param("id")
+	importID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || importID <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive_number"))
+		return
+	}
+
+	trace, err := s.tenderService.GetImportTrace(c.Request.Context(), importID)
+	if err != nil {
+		var notFoundErr *apierrors.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			respondStatus(c, http.StatusNotFound, err)
+			return
+		}
+		logger.Errorf("Ошибка получения трассировки импорта %d: %v", importID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trace)
+}
+
+// listTenderImportsHandler - GET /api/v1/tenders/:id/imports.
+// Возвращает сохранённые версии исходного JSON тендера (без самих данных).
+func (s *Server) listTenderImportsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "listTenderImportsHandler")
+
+	tenderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || tenderID <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_tender_id_format"))
+		return
+	}
+
+	imports, err := s.tenderService.ListTenderImports(c.Request.Context(), tenderID)
+	if err != nil {
+		var notFoundErr *apierrors.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			respondStatus(c, http.StatusNotFound, err)
+			return
+		}
+		logger.Errorf("Ошибка получения истории импортов тендера %d: %v", tenderID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, imports)
+}
+
+// getTenderImportRawHandler - GET /api/v1/tenders/:id/imports/:version/raw.
+// Отдаёт снимок исходного JSON указанной версии файлом для скачивания.
+func (s *Server) getTenderImportRawHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "getTenderImportRawHandler")
+
+	tenderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || tenderID <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_tender_id_format"))
+		return
+	}
+	version, err := strconv.ParseInt(c.Param("version"), 10, 32)
+	if err != nil || version <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.version_positive"))
+		return
+	}
+
+	raw, err := s.tenderService.GetTenderImportRaw(c.Request.Context(), tenderID, int32(version))
+	if err != nil {
+		var notFoundErr *apierrors.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			respondStatus(c, http.StatusNotFound, err)
+			return
+		}
+		logger.Errorf("Ошибка получения версии %d исходного JSON тендера %d: %v", version, tenderID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="tender-%d-v%d.json"`, tenderID, version))
+	c.Data(http.StatusOK, "application/json; charset=utf-8", raw)
+}
+
+// diffTenderImportsHandler - GET /api/v1/tenders/:id/imports/diff?from=1&to=2.
+// Структурированная разница между двумя версиями исходного JSON: добавленные,
+// удалённые и изменённые лоты, предложения, позиции и итоговые строки.
+func (s *Server) diffTenderImportsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "diffTenderImportsHandler")
+
+	tenderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || tenderID <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_tender_id_format"))
+		return
+	}
+	fromVersion, err := strconv.ParseInt(c.Query("from"), 10, 32)
+	if err != nil || fromVersion <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.from_positive"))
+		return
+	}
+	toVersion, err := strconv.ParseInt(c.Query("to"), 10, 32)
+	if err != nil || toVersion <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.to_positive"))
+		return
+	}
+
+	diff, err := s.tenderService.DiffTenderImports(c.Request.Context(), tenderID, int32(fromVersion), int32(toVersion))
+	if err != nil {
+		var notFoundErr *apierrors.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			respondStatus(c, http.StatusNotFound, err)
+			return
+		}
+		logger.Errorf("Ошибка сравнения версий %d и %d тендера %d: %v", fromVersion, toVersion, tenderID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
 }
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+)
+
+// generateWinnerProtocolHandler обрабатывает POST /api/v1/lots/:id/winner-protocol.
+//
+// Query:    format — docx (по умолчанию) или pdf (нужен reports.pdf_converter_url)
+// Response: 201 + LotAttachment (download_url — ссылка на файл)
+// Errors:   400 (формат), 404 (лот не найден), 409 (у лота нет победителей),
+// 500 (БД, конвертер PDF)
+func (s *Server) generateWinnerProtocolHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "generateWinnerProtocolHandler")
+
+	idStr := c.Param("id")
+	lotID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || lotID <= 0 {
+		logger.Errorf("Некорректный ID лота: %s", idStr)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	attachment, err := s.reports.GenerateWinnerProtocol(
+		c.Request.Context(), lotID, c.Query("format"), sql.NullInt64{Int64: userID, Valid: true})
+	if err != nil {
+		logger.Errorf("Ошибка GenerateWinnerProtocol(lot_id=%d): %v", lotID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// listLotAttachmentsHandler обрабатывает GET /api/v1/lots/:id/attachments.
+// Возвращает документы лота без содержимого, новые первыми.
+func (s *Server) listLotAttachmentsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "listLotAttachmentsHandler")
+
+	idStr := c.Param("id")
+	lotID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || lotID <= 0 {
+		logger.Errorf("Некорректный ID лота: %s", idStr)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return
+	}
+
+	response, err := s.reports.ListLotAttachments(c.Request.Context(), lotID)
+	if err != nil {
+		logger.Errorf("Ошибка ListLotAttachments(lot_id=%d): %v", lotID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// downloadLotAttachmentHandler обрабатывает GET /api/v1/lots/:id/attachments/:attachmentId.
+// Отдаёт файл документа (Content-Disposition: attachment).
+func (s *Server) downloadLotAttachmentHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "downloadLotAttachmentHandler")
+
+	idStr := c.Param("id")
+	lotID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || lotID <= 0 {
+		logger.Errorf("Некорректный ID лота: %s", idStr)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return
+	}
+	attachmentStr := c.Param("attachmentId")
+	attachmentID, err := strconv.ParseInt(attachmentStr, 10, 64)
+	if err != nil || attachmentID <= 0 {
+		logger.Errorf("Некорректный ID документа: %s", attachmentStr)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.attachment_id_positive"))
+		return
+	}
+
+	attachment, err := s.reports.GetLotAttachment(c.Request.Context(), lotID, attachmentID)
+	if err != nil {
+		logger.Errorf("Ошибка GetLotAttachment(lot_id=%d, id=%d): %v", lotID, attachmentID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"; filename*=UTF-8''%s`,
+		attachment.FileName, url.PathEscape(attachment.FileName)))
+	c.Data(http.StatusOK, attachment.ContentType, attachment.Content)
+}
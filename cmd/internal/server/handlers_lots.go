@@ -1,30 +1,35 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"github.com/sqlc-dev/pqtype"
-	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/analytics"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/webhooks"
 )
 
+type patchLotKeyParametersRequest struct {
+	LotKeyParameters map[string]string `json:"lot_key_parameters"`
+}
+
 // PATCH /api/v1/lots/:id/key-parameters
 func (s *Server) patchLotKeyParametersHandler(c *gin.Context) {
 	lotID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный ID лота")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_lot_id"))
 		return
 	}
 
 	// Шаг 1. Парсим тело запроса
-	var req struct {
-		LotKeyParameters map[string]string `json:"lot_key_parameters"`
-	}
+	var req patchLotKeyParametersRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный JSON: %v", err)))
+		respondBindError(c, err)
 		return
 	}
 
@@ -37,26 +42,182 @@ func (s *Server) patchLotKeyParametersHandler(c *gin.Context) {
 		}
 	}
 
-	// Шаг 2. Сериализуем map в json.RawMessage
-	raw, err := json.Marshal(parsed)
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	// Шаг 2. Обновляем через сервис: он же пишет изменённые ключи в историю правок
+	updated, err := s.lotService.PatchKeyParameters(c.Request.Context(), lotID, parsed, userID)
 	if err != nil {
-		s.logger.Errorf("ошибка сериализации lot_key_parameters: %v", err)
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		s.logger.Errorf("ошибка обновления параметров лота %d: %v", lotID, err)
+		respondError(c, err)
 		return
 	}
 
-	// Шаг 3. Вызываем sqlc-метод обновления
-	params := db.UpdateLotDetailsParams{
-		ID:               lotID,
-		LotKeyParameters: pqtype.NullRawMessage{RawMessage: raw, Valid: true},
+	c.JSON(http.StatusOK, updated)
+}
+
+// getLotComparisonHandler - GET /api/v1/lots/:id/comparison
+// Матрица сравнения предложений: позиции каталога x подрядчики,
+// со стоимостями, отклонением от baseline и флагами отсутствующих позиций.
+// Поддерживает If-None-Match: ответ 304, если данные лота не изменились.
+func (s *Server) getLotComparisonHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "getLotComparisonHandler")
+
+	idStr := c.Param("id")
+	lotID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || lotID <= 0 {
+		logger.Errorf("Некорректный ID лота: %s", idStr)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return
+	}
+
+	cacheControl := s.config.HTTPCache.LotComparison
+	etag, notModified := s.checkNotModified(c, "lot_comparison", lotID, cacheControl, func(ctx context.Context) (cacheVersion, error) {
+		row, err := s.store.GetLotCacheVersion(ctx, lotID)
+		return cacheVersion{UpdatedAt: row.VersionAt, RowsCount: row.RowsCount}, err
+	})
+	if notModified {
+		return
 	}
 
-	updated, err := s.store.UpdateLotDetails(c.Request.Context(), params)
+	response, err := s.lotService.GetLotComparison(c.Request.Context(), lotID)
 	if err != nil {
-		s.logger.Errorf("ошибка обновления параметров лота %d: %v", lotID, err)
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		logger.Errorf("Ошибка GetLotComparison(id=%d): %v", lotID, err)
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, updated)
+	setCacheHeaders(c, etag, cacheControl)
+	c.JSON(http.StatusOK, response)
+}
+
+// getLotAnalyticsHandler - GET /api/v1/lots/:id/analytics.
+// Агрегаты стоимости по лоту: baseline, min/max/медиана/среднее итогов предложений,
+// разброс, отклонение каждого подрядчика от baseline и число позиций,
+// в которых подрядчик самый дешёвый.
+func (s *Server) getLotAnalyticsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "getLotAnalyticsHandler")
+
+	idStr := c.Param("id")
+	lotID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || lotID <= 0 {
+		logger.Errorf("Некорректный ID лота: %s", idStr)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return
+	}
+
+	response, err := s.analytics.GetLotAnalytics(c.Request.Context(), lotID)
+	if err != nil {
+		logger.Errorf("Ошибка GetLotAnalytics(id=%d): %v", lotID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// compareLotKeyParameterHandler - GET /api/v1/analytics/lots/compare.
+// Query: category_id и parameter (имя ключевого параметра лота) — обязательны.
+// Удельные цены победителей за единицу параметра по лотам категории; тендеры —
+// только организации пользователя (admin — все).
+func (s *Server) compareLotKeyParameterHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "compareLotKeyParameterHandler")
+
+	categoryID, err := strconv.ParseInt(c.Query("category_id"), 10, 64)
+	if err != nil || categoryID <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.category_id_positive"))
+		return
+	}
+
+	response, err := s.analytics.CompareLotKeyParameter(c.Request.Context(), analytics.KeyParameterBenchmarkQuery{
+		CategoryID:     categoryID,
+		Parameter:      c.Query("parameter"),
+		OrganizationID: requestOrganizationScope(c),
+	})
+	if err != nil {
+		logger.Errorf("Ошибка CompareLotKeyParameter(category_id=%d): %v", categoryID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// listLotAIResultsHandler - GET /api/v1/lots/:id/ai-results.
+// История запусков AI-анализа лота с моделью, версией промпта и сырым ответом.
+func (s *Server) listLotAIResultsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "listLotAIResultsHandler")
+
+	lotID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || lotID <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return
+	}
+
+	response, err := s.lotService.ListAIAnalysisRuns(c.Request.Context(), lotID)
+	if err != nil {
+		var notFoundErr *apierrors.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			respondStatus(c, http.StatusNotFound, err)
+			return
+		}
+		logger.Errorf("Ошибка ListAIAnalysisRuns(lot_id=%d): %v", lotID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// promoteLotAIResultHandler - POST /api/v1/lots/:id/ai-results/:runId/promote.
+// Записывает в лот ключевые параметры выбранного запуска AI-анализа. Параметры
+// проверяются по схеме категории тендера: запуск мог быть сохранен до ее появления.
+func (s *Server) promoteLotAIResultHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "promoteLotAIResultHandler")
+
+	lotID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || lotID <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return
+	}
+	runID, err := strconv.ParseInt(c.Param("runId"), 10, 64)
+	if err != nil || runID <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.run_id_positive"))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	run, err := s.lotService.PromoteAIAnalysisRun(c.Request.Context(), lotID, runID, userID)
+	if err != nil {
+		var notFoundErr *apierrors.NotFoundError
+		var validationErr *apierrors.ValidationError
+		switch {
+		case errors.As(err, &notFoundErr):
+			respondStatus(c, http.StatusNotFound, err)
+			return
+		case errors.As(err, &validationErr):
+			respondError(c, validationErr)
+			return
+		}
+		logger.Errorf("Ошибка PromoteAIAnalysisRun(lot_id=%d, run_id=%d): %v", lotID, runID, err)
+		respondError(c, err)
+		return
+	}
+
+	var keyParameters map[string]interface{}
+	if err := json.Unmarshal(run.KeyParameters, &keyParameters); err == nil {
+		s.publishWebhook(c, logger, webhooks.EventLotAIResults, webhooks.LotAIResultsData{
+			LotID:              lotID,
+			LotKeyParameters:   keyParameters,
+			AIAnalysisResultID: run.ID,
+		})
+	}
+
+	c.JSON(http.StatusOK, run)
 }
@@ -16,7 +16,7 @@ func (s *Server) getStatsHandler(c *gin.Context) {
 	count, err := s.store.GetTendersCount(c.Request.Context())
 	if err != nil {
 		s.logger.Errorf("Ошибка при получении количества тендеров: %v", err)
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
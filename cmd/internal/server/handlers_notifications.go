@@ -0,0 +1,225 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// listNotificationsHandler обрабатывает GET /api/v1/notifications.
+//
+// Query-параметры:
+//   - unread_only: true — только непрочитанные (по умолчанию false)
+//   - limit (default 20, max 100), offset (default 0)
+//
+// Response: 200 + NotificationsResponse
+// Errors:   400 (параметры), 401, 500 (БД)
+func (s *Server) listNotificationsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "listNotificationsHandler")
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	unreadOnly := false
+	if v := c.Query("unread_only"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.unread_only_bool"))
+			return
+		}
+		unreadOnly = parsed
+	}
+
+	limitStr := c.DefaultQuery("limit", "20")
+	limit64, err := strconv.ParseInt(limitStr, 10, 32)
+	if err != nil || limit64 <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.limit_positive"))
+		return
+	}
+
+	offsetStr := c.DefaultQuery("offset", "0")
+	offset64, err := strconv.ParseInt(offsetStr, 10, 32)
+	if err != nil || offset64 < 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.offset_non_negative"))
+		return
+	}
+
+	result, err := s.feed.List(c.Request.Context(), userID, unreadOnly, int32(limit64), int32(offset64))
+	if err != nil {
+		logger.Errorf("Ошибка List(user_id=%d): %v", userID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// getUnreadNotificationsCountHandler обрабатывает GET /api/v1/notifications/unread-count.
+// Лёгкий запрос для счётчика на иконке колокольчика.
+func (s *Server) getUnreadNotificationsCountHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "getUnreadNotificationsCountHandler")
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	count, err := s.feed.UnreadCount(c.Request.Context(), userID)
+	if err != nil {
+		logger.Errorf("Ошибка UnreadCount(user_id=%d): %v", userID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, api_models.NotificationUnreadCountResponse{UnreadCount: count})
+}
+
+// markNotificationReadHandler обрабатывает POST /api/v1/notifications/:id/read.
+// Повторная отметка не меняет read_at. Чужое уведомление — 404.
+func (s *Server) markNotificationReadHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "markNotificationReadHandler")
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		logger.Errorf("Некорректный ID уведомления: %s", idStr)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return
+	}
+
+	notification, err := s.feed.MarkRead(c.Request.Context(), userID, id)
+	if err != nil {
+		logger.Errorf("Ошибка MarkRead(user_id=%d, id=%d): %v", userID, id, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notification)
+}
+
+// followTenderHandler обрабатывает POST /api/v1/tenders/:id/follow.
+// Подписывает текущего пользователя на уведомления по тендеру; повторный
+// запрос не ошибка.
+//
+// Response: 200 + TenderFollowResponse
+// Errors:   400 (id), 404 (тендер не найден или удалён), 500 (БД)
+func (s *Server) followTenderHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "followTenderHandler")
+
+	userID, tenderID, ok := parseFollowRequest(c, logger)
+	if !ok {
+		return
+	}
+
+	resp, err := s.feed.Follow(c.Request.Context(), userID, tenderID)
+	if err != nil {
+		logger.Errorf("Ошибка Follow(user_id=%d, tender_id=%d): %v", userID, tenderID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// unfollowTenderHandler обрабатывает DELETE /api/v1/tenders/:id/follow.
+// Отсутствующая подписка не ошибка.
+func (s *Server) unfollowTenderHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "unfollowTenderHandler")
+
+	userID, tenderID, ok := parseFollowRequest(c, logger)
+	if !ok {
+		return
+	}
+
+	resp, err := s.feed.Unfollow(c.Request.Context(), userID, tenderID)
+	if err != nil {
+		logger.Errorf("Ошибка Unfollow(user_id=%d, tender_id=%d): %v", userID, tenderID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// listFollowedTendersHandler обрабатывает GET /api/v1/me/followed-tenders.
+//
+// Query-параметры:
+//   - limit (default 20, max 100), offset (default 0)
+//
+// Response: 200 + FollowedTendersResponse
+// Errors:   400 (параметры), 401, 500 (БД)
+func (s *Server) listFollowedTendersHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "listFollowedTendersHandler")
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	limitStr := c.DefaultQuery("limit", "20")
+	limit64, err := strconv.ParseInt(limitStr, 10, 32)
+	if err != nil || limit64 <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.limit_positive"))
+		return
+	}
+
+	offsetStr := c.DefaultQuery("offset", "0")
+	offset64, err := strconv.ParseInt(offsetStr, 10, 32)
+	if err != nil || offset64 < 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.offset_non_negative"))
+		return
+	}
+
+	result, err := s.feed.ListFollowed(c.Request.Context(), userID, requestOrganizationScope(c), int32(limit64), int32(offset64))
+	if err != nil {
+		logger.Errorf("Ошибка ListFollowed(user_id=%d): %v", userID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func parseFollowRequest(c *gin.Context, logger logging.Logger) (userID, tenderID int64, ok bool) {
+	userID, ok = currentUserID(c)
+	if !ok {
+		return 0, 0, false
+	}
+
+	idStr := c.Param("id")
+	tenderID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || tenderID <= 0 {
+		logger.Errorf("Некорректный ID тендера: %s", idStr)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return 0, 0, false
+	}
+	return userID, tenderID, true
+}
+
+// currentUserID извлекает user_id, установленный AuthMiddleware. При ошибке
+// ответ уже отправлен.
+func currentUserID(c *gin.Context) (int64, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondCode(c, http.StatusUnauthorized, CodeUnauthorized, "auth.not_authenticated")
+		return 0, false
+	}
+	userIDVal, ok := userID.(int64)
+	if !ok {
+		respondInternal(c, errors.New("invalid user_id type"))
+		return 0, false
+	}
+	return userIDVal, true
+}
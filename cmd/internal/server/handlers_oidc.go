@@ -0,0 +1,121 @@
+package server
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/auth"
+)
+
+const (
+	// oidcStateCookieName — cookie с состоянием входа через SSO (state, nonce, PKCE)
+	oidcStateCookieName = "oidc_state"
+	// oidcStateCookiePath ограничивает cookie маршрутами SSO
+	oidcStateCookiePath = "/api/v1/auth/oidc"
+	// oidcStateCookieMaxAge совпадает со сроком состояния в auth.OIDCState
+	oidcStateCookieMaxAge = 600
+)
+
+// oidcLoginHandler обрабатывает GET /api/v1/auth/oidc/login
+// Перенаправляет браузер на страницу входа провайдера SSO. return_to —
+// относительный путь фронтенда, куда вернуться после входа.
+func (s *Server) oidcLoginHandler(c *gin.Context) {
+	if !s.authService.OIDCEnabled() {
+		respondCode(c, http.StatusNotFound, "oidc_disabled", "auth.oidc_disabled")
+		return
+	}
+
+	authURL, state, err := s.authService.OIDCAuthRequest(c.Request.Context(), c.Query("return_to"))
+	if err != nil {
+		s.respondOIDCError(c, err)
+		return
+	}
+
+	s.setOIDCStateCookie(c, state.Encode(), oidcStateCookieMaxAge)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// oidcCallbackHandler обрабатывает GET /api/v1/auth/oidc/callback
+// Провайдер возвращает браузер с кодом авторизации: код обменивается на
+// id_token, пользователь находится, привязывается по email или создается,
+// устанавливаются те же cookies, что при входе по паролю, и браузер
+// перенаправляется на фронтенд (auth.oidc.post_login_url).
+func (s *Server) oidcCallbackHandler(c *gin.Context) {
+	if !s.authService.OIDCEnabled() {
+		respondCode(c, http.StatusNotFound, "oidc_disabled", "auth.oidc_disabled")
+		return
+	}
+
+	// Состояние одноразовое: cookie удаляется при любом исходе
+	stateCookie, cookieErr := c.Cookie(oidcStateCookieName)
+	s.setOIDCStateCookie(c, "", -1)
+
+	// Провайдер сообщил об ошибке (например, access_denied — пользователь отказался)
+	if providerErr := c.Query("error"); providerErr != "" {
+		respondCode(c, http.StatusUnauthorized, "oidc_login_failed", "auth.oidc_login_failed", providerErr)
+		return
+	}
+
+	if cookieErr != nil {
+		respondCode(c, http.StatusBadRequest, "oidc_state_invalid", "auth.oidc_state_invalid")
+		return
+	}
+	state, err := auth.DecodeOIDCState(stateCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(state.State), []byte(c.Query("state"))) != 1 {
+		respondCode(c, http.StatusBadRequest, "oidc_state_invalid", "auth.oidc_state_invalid")
+		return
+	}
+	code := c.Query("code")
+	if code == "" {
+		respondCode(c, http.StatusBadRequest, "oidc_code_missing", "auth.oidc_code_missing")
+		return
+	}
+
+	result, err := s.authService.LoginOIDC(c.Request.Context(), code, state, parseIPAddress(c.ClientIP()), c.Request.UserAgent())
+	if err != nil {
+		s.respondOIDCError(c, err)
+		return
+	}
+
+	s.setAuthCookies(c, result.AccessToken, result.RefreshToken)
+	c.Redirect(http.StatusFound, s.authService.OIDCPostLoginURL(state))
+}
+
+// respondOIDCError отвечает на ошибки входа через SSO.
+func (s *Server) respondOIDCError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, auth.ErrOIDCDisabled):
+		respondCode(c, http.StatusNotFound, "oidc_disabled", "auth.oidc_disabled")
+	case errors.Is(err, auth.ErrOIDCEmailNotVerified):
+		respondCode(c, http.StatusForbidden, "oidc_email_unverified", "auth.oidc_email_unverified")
+	case errors.Is(err, auth.ErrOIDCAccessDenied):
+		respondCode(c, http.StatusForbidden, "oidc_access_denied", "auth.oidc_access_denied")
+	case errors.Is(err, auth.ErrOIDCToken):
+		s.logger.WithError(err).Warn("oidc id_token rejected")
+		respondCode(c, http.StatusUnauthorized, "oidc_token_invalid", "auth.oidc_token_invalid")
+	case errors.Is(err, auth.ErrOIDCProvider):
+		s.logger.WithError(err).Error("oidc provider request failed")
+		respondCode(c, http.StatusBadGateway, "oidc_provider_error", "auth.oidc_provider_error")
+	default:
+		s.logger.WithError(err).Error("oidc login failed")
+		respondInternal(c, err)
+	}
+}
+
+// setOIDCStateCookie устанавливает (maxAge < 0 — удаляет) cookie состояния SSO.
+// SameSite=Lax независимо от cookie_same_site: callback — переход с сайта
+// провайдера, и со Strict браузер не отправил бы cookie.
+func (s *Server) setOIDCStateCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(
+		oidcStateCookieName,
+		value,
+		maxAge,
+		oidcStateCookiePath,
+		s.config.Auth.CookieDomain,
+		s.config.Auth.CookieSecure,
+		true,
+	)
+}
@@ -0,0 +1,251 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/auth"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR OIDC HANDLERS (Integration: Handler + Auth Service + fake IdP)
+
+What user problems does this protect us from?
+================================================================================
+1. Broken SSO — the browser must go IdP → callback → frontend with the same
+   auth cookies as a password login
+2. Login CSRF — a callback without the matching state cookie must not log anyone in
+3. Misconfiguration noise — with OIDC disabled the routes answer 404
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Full flow
+- GIVEN OIDC enabled and a user linked to the IdP account
+  WHEN /oidc/login is followed by /oidc/callback with the issued state and a code
+  THEN 302 to post_login_url + return_to, access/refresh/csrf cookies set,
+  the state cookie cleared
+
+SCENARIO 2: Rejected callbacks
+- GIVEN a state that differs from the cookie, or no cookie → 400 oidc_state_invalid
+- GIVEN the IdP reports error=access_denied → 401 oidc_login_failed
+- Both without touching the store
+
+SCENARIO 3: OIDC disabled → 404 oidc_disabled on both routes
+*/
+
+const testOIDCClientSecret = "oidc-client-secret"
+
+// newTestIdP поднимает провайдер OIDC: discovery, JWKS и token endpoint,
+// который выдает id_token для sub-1 с nonce из запроса авторизации.
+func newTestIdP(t *testing.T, nonce *string) *httptest.Server {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 server.URL,
+			"authorization_endpoint": server.URL + "/authorize",
+			"token_endpoint":         server.URL + "/token",
+			"jwks_uri":               server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA", "kid": "k1",
+			"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":            server.URL,
+			"aud":            "tenders",
+			"sub":            "sub-1",
+			"exp":            time.Now().Add(time.Minute).Unix(),
+			"nonce":          *nonce,
+			"email":          testEmail,
+			"email_verified": true,
+			"groups":         []string{"tender-editors"},
+		})
+		token.Header["kid"] = "k1"
+		signed, err := token.SignedString(key)
+		require.NoError(t, err)
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": signed})
+	})
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// setupOIDCTestServer — роутер с маршрутами SSO; idp == nil — OIDC выключен.
+func setupOIDCTestServer(t *testing.T, idp *httptest.Server) (*gin.Engine, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	logger := testutil.NewMockLogger()
+	cfg := testConfig()
+
+	authService := auth.NewService(mockStore, cfg, logger, nil)
+	if idp != nil {
+		cfg.Auth.OIDC = config.OIDCConfig{
+			Enabled:      true,
+			Issuer:       idp.URL,
+			ClientID:     "tenders",
+			ClientSecret: testOIDCClientSecret,
+			RedirectURL:  "https://tenders.example.com/api/v1/auth/oidc/callback",
+			Scopes:       []string{"openid", "email"},
+			GroupsClaim:  "groups",
+			RoleMapping:  map[string]string{"tender-editors": auth.RoleEditor},
+			PostLoginURL: "https://app.example.com/",
+			Timeout:      5 * time.Second,
+		}
+		client, err := auth.NewOIDCClient(cfg.Auth.OIDC)
+		require.NoError(t, err)
+		authService.EnableOIDC(client)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	server := &Server{store: mockStore, logger: logger, authService: authService, config: cfg}
+	router.GET("/api/v1/auth/oidc/login", server.oidcLoginHandler)
+	router.GET("/api/v1/auth/oidc/callback", server.oidcCallbackHandler)
+	return router, mockStore
+}
+
+func responseCookie(w *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestOIDCHandlers_FullFlow(t *testing.T) {
+	var nonce string
+	idp := newTestIdP(t, &nonce)
+	router, mockStore := setupOIDCTestServer(t, idp)
+
+	// WHEN: the browser starts the login
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/auth/oidc/login?return_to=/tenders/5", nil))
+
+	// THEN: redirect to the IdP with a state cookie scoped to the SSO routes
+	require.Equal(t, http.StatusFound, w.Code)
+	location, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, idp.URL+"/authorize", location.Scheme+"://"+location.Host+location.Path)
+	stateCookie := responseCookie(w, oidcStateCookieName)
+	require.NotNil(t, stateCookie)
+	assert.True(t, stateCookie.HttpOnly)
+	assert.Equal(t, oidcStateCookiePath, stateCookie.Path)
+	assert.Equal(t, http.SameSiteLaxMode, stateCookie.SameSite)
+	nonce = location.Query().Get("nonce")
+
+	now := time.Now()
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT .+ FROM users WHERE oidc_issuer").
+				WithArgs(idp.URL, "sub-1").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "email", "role", "is_active", "organization_id", "created_at", "updated_at", "token_version"}).
+					AddRow(int64(1), testEmail, auth.RoleEditor, true, int64(1), now, now, int64(0)))
+			mock.ExpectQuery("INSERT INTO user_sessions").
+				WillReturnRows(sqlmock.NewRows(sessionColumns).
+					AddRow(int64(1), int64(1), "hash", now, now.Add(time.Hour), nil))
+			mock.ExpectExec("UPDATE users SET last_login_at").
+				WithArgs(int64(1)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}),
+	)
+
+	// WHEN: the IdP sends the browser back
+	req := httptest.NewRequest(http.MethodGet,
+		"/api/v1/auth/oidc/callback?code=abc&state="+url.QueryEscape(location.Query().Get("state")), nil)
+	req.AddCookie(stateCookie)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// THEN: the same cookies as a password login, redirect back to the frontend
+	require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+	assert.Equal(t, "https://app.example.com/tenders/5", w.Header().Get("Location"))
+	assert.NotNil(t, responseCookie(w, "access_token"))
+	assert.NotNil(t, responseCookie(w, "refresh_token"))
+	assert.NotNil(t, responseCookie(w, csrfCookieName))
+	cleared := responseCookie(w, oidcStateCookieName)
+	require.NotNil(t, cleared)
+	assert.Less(t, cleared.MaxAge, 0)
+}
+
+func TestOIDCCallback_RejectedWithoutStore(t *testing.T) {
+	var nonce string
+	idp := newTestIdP(t, &nonce)
+	router, _ := setupOIDCTestServer(t, idp)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/auth/oidc/login", nil))
+	require.Equal(t, http.StatusFound, w.Code)
+	stateCookie := responseCookie(w, oidcStateCookieName)
+	require.NotNil(t, stateCookie)
+
+	cases := []struct {
+		name       string
+		query      string
+		withCookie bool
+		wantStatus int
+		wantCode   string
+	}{
+		{"state mismatch", "?code=abc&state=forged", true, http.StatusBadRequest, "oidc_state_invalid"},
+		{"no state cookie", "?code=abc&state=any", false, http.StatusBadRequest, "oidc_state_invalid"},
+		{"provider error", "?error=access_denied", true, http.StatusUnauthorized, "oidc_login_failed"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/oidc/callback"+tc.query, nil)
+			if tc.withCookie {
+				req.AddCookie(stateCookie)
+			}
+			w := httptest.NewRecorder()
+
+			// No store expectations: gomock fails the test if the store is touched
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatus, w.Code)
+			assert.Equal(t, tc.wantCode, parseBody(t, w)["code"])
+			assert.Nil(t, responseCookie(w, "access_token"))
+		})
+	}
+}
+
+func TestOIDCHandlers_Disabled(t *testing.T) {
+	router, _ := setupOIDCTestServer(t, nil)
+
+	for _, path := range []string{"/api/v1/auth/oidc/login", "/api/v1/auth/oidc/callback?code=abc&state=s"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+		assert.Equal(t, "oidc_disabled", parseBody(t, w)["code"])
+	}
+}
@@ -0,0 +1,14 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getOutboundStatsHandler обрабатывает GET /api/v1/admin/outbound/stats.
+// Возвращает состояние circuit breaker и счётчики запросов к каждому
+// внутреннему сервису (парсеру) этого экземпляра API с момента запуска.
+func (s *Server) getOutboundStatsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, s.httpClient.Stats())
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+)
+
+// listPriceIndicesHandler обрабатывает GET /api/v1/price-indices.
+// Query: region — один регион (без параметра — все).
+func (s *Server) listPriceIndicesHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "listPriceIndicesHandler")
+
+	response, err := s.priceIndices.List(c.Request.Context(), c.Query("region"))
+	if err != nil {
+		logger.Errorf("Ошибка получения индексов цен: %v", err)
+		respondErrorCode(c, err, "price_index_conflict")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// createPriceIndexHandler обрабатывает POST /api/v1/price-indices.
+func (s *Server) createPriceIndexHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "createPriceIndexHandler")
+
+	var req api_models.PriceIndexRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	response, err := s.priceIndices.Create(c.Request.Context(), req)
+	if err != nil {
+		logger.Errorf("Ошибка добавления индекса цен %s за %s: %v", req.Region, req.Period, err)
+		respondErrorCode(c, err, "price_index_conflict")
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// updatePriceIndexHandler обрабатывает PUT /api/v1/price-indices/:id.
+func (s *Server) updatePriceIndexHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "updatePriceIndexHandler")
+
+	id, ok := parseUnitParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req api_models.PriceIndexRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	response, err := s.priceIndices.Update(c.Request.Context(), id, req)
+	if err != nil {
+		logger.Errorf("Ошибка изменения индекса цен %d: %v", id, err)
+		respondErrorCode(c, err, "price_index_conflict")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// deletePriceIndexHandler обрабатывает DELETE /api/v1/price-indices/:id.
+func (s *Server) deletePriceIndexHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "deletePriceIndexHandler")
+
+	id, ok := parseUnitParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := s.priceIndices.Delete(c.Request.Context(), id); err != nil {
+		logger.Errorf("Ошибка удаления индекса цен %d: %v", id, err)
+		respondErrorCode(c, err, "price_index_conflict")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -2,22 +2,24 @@ package server
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
 	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
 )
 
 // Обновляем структуру для API-ответа
 type proposalResponse struct {
-	ProposalID      int64    `json:"proposal_id"`
-	ContractorID    int64    `json:"contractor_id"`
-	ContractorTitle string   `json:"contractor_title"`
-	ContractorInn   string   `json:"contractor_inn"`
-	IsWinner        bool     `json:"is_winner"`
-	TotalCost       *float64 `json:"total_cost"`
+	ProposalID      int64             `json:"proposal_id"`
+	ContractorID    int64             `json:"contractor_id"`
+	ContractorTitle string            `json:"contractor_title"`
+	ContractorInn   string            `json:"contractor_inn"`
+	IsWinner        bool              `json:"is_winner"`
+	TotalCost       *api_models.Money `json:"total_cost"`
+	Currency        string            `json:"currency"` // Валюта итога (ISO 4217)
 	// Добавляем поле для всего объекта additional_info
 	AdditionalInfo json.RawMessage `json:"additional_info"`
 }
@@ -26,7 +28,7 @@ type proposalResponse struct {
 func (s *Server) listProposalsHandler(c *gin.Context) {
 	tenderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный ID тендера")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_tender_id"))
 		return
 	}
 
@@ -52,7 +54,7 @@ func (s *Server) listProposalsHandler(c *gin.Context) {
 	dbProposals, err := s.store.ListProposalsForTender(c.Request.Context(), params)
 	if err != nil {
 		s.logger.Errorf("ошибка получения списка предложений: %v", err)
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
@@ -66,19 +68,11 @@ func (s *Server) listProposalsHandler(c *gin.Context) {
 			ContractorTitle: p.ContractorTitle,
 			ContractorInn:   p.ContractorInn,
 			IsWinner:        p.IsWinner,
+			TotalCost:       api_models.MoneyFromNullString(p.TotalCost), // NULL или нечисловое значение — nil
+			Currency:        p.Currency,
 			AdditionalInfo:  p.AdditionalInfo,
 		}
 
-		// Проверяем, что строка с ценой не NULL
-		if p.TotalCost.Valid {
-			// Конвертируем строку (p.TotalCost.String) в float64
-			cost, err := strconv.ParseFloat(p.TotalCost.String, 64)
-			if err == nil { // Если конвертация прошла успешно
-				apiProp.TotalCost = &cost // Присваиваем указатель на полученное число
-			}
-			// Если конвертация не удалась, поле останется nil, что тоже корректно
-		}
-
 		apiResponse = append(apiResponse, apiProp)
 	}
 
@@ -90,7 +84,7 @@ func (s *Server) listProposalsForLotHandler(c *gin.Context) {
 	// Получаем ID лота из URL
 	lotID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный ID лота")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_lot_id"))
 		return
 	}
 
@@ -117,7 +111,7 @@ func (s *Server) listProposalsForLotHandler(c *gin.Context) {
 	dbProposals, err := s.store.ListRichProposalsForLot(c.Request.Context(), params)
 	if err != nil {
 		s.logger.Errorf("ошибка получения списка предложений для лота %d: %v", lotID, err)
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
@@ -139,16 +133,11 @@ func (s *Server) listProposalsForLotHandler(c *gin.Context) {
 			ContractorTitle: p.ContractorTitle,
 			ContractorInn:   p.ContractorInn,
 			IsWinner:        p.IsWinner,
+			TotalCost:       api_models.MoneyFromNullString(p.TotalCost),
+			Currency:        p.Currency,
 			AdditionalInfo:  rawInfo,
 		}
 
-		if p.TotalCost.Valid {
-			cost, err := strconv.ParseFloat(p.TotalCost.String, 64)
-			if err == nil {
-				apiProp.TotalCost = &cost
-			}
-		}
-
 		apiResponse = append(apiResponse, apiProp)
 	}
 
@@ -156,3 +145,51 @@ func (s *Server) listProposalsForLotHandler(c *gin.Context) {
 
 	c.JSON(http.StatusOK, apiResponse)
 }
+
+// getProposalConsistencyHandler - GET /api/v1/proposals/:id/consistency.
+// Пересчитывает итоги глав и предложения по позициям и сравнивает их с
+// сохранёнными итогами; расхождения сверх допуска попадают в discrepancies.
+func (s *Server) getProposalConsistencyHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "getProposalConsistencyHandler")
+
+	idStr := c.Param("id")
+	proposalID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || proposalID <= 0 {
+		logger.Errorf("Некорректный ID предложения: %s", idStr)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return
+	}
+
+	report, err := s.consistency.CheckProposal(c.Request.Context(), proposalID)
+	if err != nil {
+		logger.Errorf("Ошибка CheckProposal(id=%d): %v", proposalID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// getProposalCoverageHandler - GET /api/v1/proposals/:id/coverage.
+// Сравнивает позиции предложения с baseline лота: что подрядчик не оценил,
+// оценил нулём или с изменённым объёмом, и какой процент объёма покрыт.
+func (s *Server) getProposalCoverageHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "getProposalCoverageHandler")
+
+	idStr := c.Param("id")
+	proposalID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || proposalID <= 0 {
+		logger.Errorf("Некорректный ID предложения: %s", idStr)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return
+	}
+
+	report, err := s.consistency.CheckCoverage(c.Request.Context(), proposalID)
+	if err != nil {
+		logger.Errorf("Ошибка CheckCoverage(id=%d): %v", proposalID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
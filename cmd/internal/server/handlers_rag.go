@@ -7,42 +7,52 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/matching"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/report"
 )
 
 // === 1. GET /api/v1/positions/unmatched ===
 
 // UnmatchedPositionsHandler - хендлер для GET /api/v1/positions/unmatched
+//
+// Query-параметры:
+//   - limit (int, default 100, max 1000)
+//   - after_id (int64, default 0): курсор — позиции с id > after_id
+//   - tender_id (int64, опционально): только позиции тендера
+//   - created_after (RFC3339, опционально): только позиции, созданные не раньше
 func (s *Server) UnmatchedPositionsHandler(c *gin.Context) {
 	logger := s.logger.WithField("handler", "UnmatchedPositionsHandler")
 
 	// Получаем limit из query-параметров
 	limitStr := c.DefaultQuery("limit", "100")
-	limit, err := strconv.Atoi(limitStr)
+	limit, err := strconv.ParseInt(limitStr, 10, 32)
 	if err != nil {
 		logger.Errorf("Некорректное значение limit: %s", limitStr)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("параметр limit должен быть целым числом")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.limit_int"))
+		return
+	}
+
+	filter, err := parseUnmatchedPositionsFilter(c)
+	if err != nil {
+		logger.Errorf("Некорректные параметры фильтра: %v", err)
+		respondStatus(c, http.StatusBadRequest, err)
 		return
 	}
+	filter.Limit = int32(limit)
 
 	// 1. Вызываем логику из tender_services (там есть валидация limit)
-	response, err := s.matchingService.GetUnmatchedPositions(c.Request.Context(), int32(limit))
+	response, err := s.matchingService.GetUnmatchedPositions(c.Request.Context(), filter)
 	if err != nil {
 		logger.Errorf("Ошибка GetUnmatchedPositions: %v", err)
 
 		// Используем проверку типа для определения ошибок валидации
-		var validationErr *apierrors.ValidationError
-		if errors.As(err, &validationErr) {
-			// Ошибка валидации - возвращаем 400 Bad Request
-			c.JSON(http.StatusBadRequest, errorResponse(err))
-		} else {
-			// Все остальные ошибки (БД и т.д.) - возвращаем 500 Internal Server Error
-			c.JSON(http.StatusInternalServerError, errorResponse(err))
-		}
+		respondError(c, err)
 		return
 	}
 
@@ -55,6 +65,78 @@ func (s *Server) UnmatchedPositionsHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// parseUnmatchedPositionsFilter разбирает курсор и фильтры очереди несопоставленных позиций.
+func parseUnmatchedPositionsFilter(c *gin.Context) (matching.UnmatchedPositionsFilter, error) {
+	var filter matching.UnmatchedPositionsFilter
+
+	if v := c.Query("after_id"); v != "" {
+		afterID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || afterID < 0 {
+			return filter, i18n.Errorf("request.after_id_non_negative")
+		}
+		filter.AfterID = afterID
+	}
+	if v := c.Query("tender_id"); v != "" {
+		tenderID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || tenderID <= 0 {
+			return filter, i18n.Errorf("request.tender_id_positive")
+		}
+		filter.TenderID = &tenderID
+	}
+	if v := c.Query("created_after"); v != "" {
+		createdAfter, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, i18n.Errorf("request.created_after_format")
+		}
+		filter.CreatedAfter = &createdAfter
+	}
+	return filter, nil
+}
+
+// === 1a. POST /internal/worker/positions/claim ===
+
+// ClaimUnmatchedPositionsHandler - хендлер для POST /internal/worker/positions/claim.
+// Арендует за воркером следующую порцию несопоставленных позиций: другие воркеры
+// не получат их до сопоставления или истечения аренды.
+//
+// Request:  ClaimUnmatchedPositionsRequest
+// Response: 200 + ClaimUnmatchedPositionsResponse (positions пуст, если очередь исчерпана)
+// Errors:   400 (валидация), 500 (БД)
+func (s *Server) ClaimUnmatchedPositionsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "ClaimUnmatchedPositionsHandler")
+
+	var payload api_models.ClaimUnmatchedPositionsRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		logger.Errorf("Ошибка парсинга JSON для ClaimUnmatchedPositions: %v", err)
+		respondBindError(c, err)
+		return
+	}
+
+	// Воркер без worker_id идентифицируется именем сервиса из ServiceAuthMiddleware
+	if payload.WorkerID == "" {
+		if service, ok := c.Get("service"); ok {
+			payload.WorkerID, _ = service.(string)
+		}
+	}
+
+	resp, err := s.matchingService.ClaimUnmatchedPositions(c.Request.Context(), payload.WorkerID,
+		matching.UnmatchedPositionsFilter{
+			Limit:        payload.Limit,
+			AfterID:      payload.AfterID,
+			TenderID:     payload.TenderID,
+			CreatedAfter: payload.CreatedAfter,
+		},
+		time.Duration(payload.LeaseSeconds)*time.Second,
+	)
+	if err != nil {
+		logger.Errorf("Ошибка ClaimUnmatchedPositions: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // === 2. POST /api/v1/positions/match ===
 
 // MatchPositionHandler - хендлер для POST /api/v1/positions/match
@@ -65,22 +147,28 @@ func (s *Server) MatchPositionHandler(c *gin.Context) {
 	var payload api_models.MatchPositionRequest
 	if err := c.ShouldBindJSON(&payload); err != nil {
 		logger.Errorf("Ошибка парсинга JSON для MatchPosition: %v", err)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("некорректный JSON: %w", err)))
+		respondBindError(c, err)
 		return
 	}
 
-	// (Можно добавить Валидацию DTO, если нужно)
+	// Воркер без worker_id идентифицируется именем сервиса из ServiceAuthMiddleware
+	if payload.WorkerID == "" {
+		if service, ok := c.Get("service"); ok {
+			payload.WorkerID, _ = service.(string)
+		}
+	}
 
 	// 2. Вызываем логику из tender_services
 	err := s.matchingService.MatchPosition(c.Request.Context(), payload)
 	if err != nil {
-		logger.Errorf("Ошибка MatchPosition: %v", err)
-		var validationErr *apierrors.ValidationError
-		if errors.As(err, &validationErr) {
-			c.JSON(http.StatusBadRequest, errorResponse(err))
+		var conflictErr *apierrors.ConflictError
+		if errors.As(err, &conflictErr) {
+			// Ожидаемая ситуация при нескольких воркерах: позицию уже закрепил другой
+			logger.Warnf("Конфликт MatchPosition: %v", err)
 		} else {
-			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			logger.Errorf("Ошибка MatchPosition: %v", err)
 		}
+		respondErrorCode(c, err, "position_already_matched")
 		return
 	}
 
@@ -88,6 +176,185 @@ func (s *Server) MatchPositionHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"status": "ok"})
 }
 
+// === 2a. POST /internal/worker/positions/match-batch ===
+
+// MatchPositionsBatchHandler - хендлер для POST /internal/worker/positions/match-batch.
+// Принимает до matching.MaxMatchBatchSize записей; результат каждой записи — в results.
+//
+// Response: 200 + MatchPositionsBatchResponse (в том числе при частичном успехе)
+// Errors:   400 (некорректный JSON, пустой или слишком большой пакет)
+func (s *Server) MatchPositionsBatchHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "MatchPositionsBatchHandler")
+
+	var payload api_models.MatchPositionsBatchRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		logger.Errorf("Ошибка парсинга JSON для MatchPositionsBatch: %v", err)
+		respondBindError(c, err)
+		return
+	}
+
+	// Воркер без worker_id идентифицируется именем сервиса из ServiceAuthMiddleware
+	if payload.WorkerID == "" {
+		if service, ok := c.Get("service"); ok {
+			payload.WorkerID, _ = service.(string)
+		}
+	}
+
+	resp, err := s.matchingService.MatchPositionsBatch(c.Request.Context(), payload)
+	if err != nil {
+		logger.Errorf("Ошибка MatchPositionsBatch: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// === 2b. PATCH /api/v1/positions/:id/catalog-match ===
+
+// CorrectPositionMatchHandler - хендлер для PATCH /api/v1/positions/:id/catalog-match.
+// Аналитик заменяет ошибочное сопоставление RAG: позиция, matching_cache
+// (source=manual) и журнал исправлений для дообучения обновляются вместе.
+//
+// Request:  CorrectPositionMatchRequest
+// Response: 200 + MatchingCorrectionResponse
+// Errors:   400 (валидация), 404 (позиция или позиция каталога), 500 (БД)
+func (s *Server) CorrectPositionMatchHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "CorrectPositionMatchHandler")
+
+	positionItemID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || positionItemID <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return
+	}
+
+	var payload api_models.CorrectPositionMatchRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		logger.Errorf("Ошибка парсинга JSON для CorrectPositionMatch: %v", err)
+		respondBindError(c, err)
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := s.matchingService.CorrectPositionMatch(c.Request.Context(), positionItemID, payload, userID)
+	if err != nil {
+		logger.Errorf("Ошибка CorrectPositionMatch: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// === 2c. POST /api/v1/positions/:id/unmatch ===
+
+// UnmatchPositionHandler - хендлер для POST /api/v1/positions/:id/unmatch.
+// Отменяет ошибочное сопоставление: позиция возвращается в очередь
+// несопоставленных, запись matching_cache удаляется.
+//
+// Response: 200 + UnmatchPositionResponse
+// Errors:   400 (позиция не сопоставлена или заголовок раздела), 404, 500 (БД)
+func (s *Server) UnmatchPositionHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "UnmatchPositionHandler")
+
+	positionItemID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || positionItemID <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := s.matchingService.UnmatchPosition(c.Request.Context(), positionItemID, userID)
+	if err != nil {
+		logger.Errorf("Ошибка UnmatchPosition: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// === 2d. GET /internal/worker/matching/corrections ===
+
+// MatchingCorrectionsHandler - хендлер для GET /internal/worker/matching/corrections.
+// Ручные исправления сопоставлений в порядке id — обучающие примеры для RAG.
+//
+// Query-параметры:
+//   - after_id (int64, default 0): курсор — исправления с id > after_id
+//   - limit (int, default 500, max 5000)
+func (s *Server) MatchingCorrectionsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "MatchingCorrectionsHandler")
+
+	var afterID int64
+	if v := c.Query("after_id"); v != "" {
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || parsed < 0 {
+			respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.after_id_non_negative"))
+			return
+		}
+		afterID = parsed
+	}
+	var limit int64
+	if v := c.Query("limit"); v != "" {
+		parsed, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || parsed <= 0 {
+			respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.limit_positive"))
+			return
+		}
+		limit = parsed
+	}
+
+	page, err := s.matchingService.ListCorrections(c.Request.Context(), afterID, int32(limit))
+	if err != nil {
+		logger.Errorf("Ошибка ListCorrections: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// === 2e. GET /api/v1/admin/matching/stats ===
+
+// GetMatchingStatsHandler - хендлер для GET /api/v1/admin/matching/stats.
+// Метрики качества матчинга: hit rate matching_cache при импортах, доли
+// автоматических, ручных и несопоставленных позиций, средняя схожесть принятых
+// слияний и перцентили возраста очереди.
+//
+// Query-параметры:
+//   - from, to (ГГГГ-ММ-ДД, to включительно; по умолчанию — последние 30 дней)
+func (s *Server) GetMatchingStatsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "GetMatchingStatsHandler")
+
+	var query matching.StatsQuery
+	var err error
+	if query.From, err = report.ParseDate("from", c.Query("from")); err != nil {
+		respondStatus(c, http.StatusBadRequest, err)
+		return
+	}
+	if query.To, err = report.ParseDate("to", c.Query("to")); err != nil {
+		respondStatus(c, http.StatusBadRequest, err)
+		return
+	}
+
+	resp, err := s.matchingService.GetMatchingStats(c.Request.Context(), query)
+	if err != nil {
+		logger.Errorf("Ошибка GetMatchingStats: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // === 3. GET /api/v1/catalog/unindexed ===
 
 // UnindexedCatalogItemsHandler - хендлер для GET /api/v1/catalog/unindexed
@@ -98,12 +365,12 @@ func (s *Server) UnindexedCatalogItemsHandler(c *gin.Context) {
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil {
 		logger.Errorf("Некорректное значение limit: %s", limitStr)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("параметр limit должен быть целым числом")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.limit_int"))
 		return
 	}
 	if limit < 0 {
 		logger.Errorf("Некорректное значение limit: %d (должно быть >= 0)", limit)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("параметр limit должен быть >= 0")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.limit_non_negative"))
 		return
 	}
 
@@ -111,12 +378,7 @@ func (s *Server) UnindexedCatalogItemsHandler(c *gin.Context) {
 	response, err := s.catalogService.GetUnindexedCatalogItems(c.Request.Context(), int32(limit))
 	if err != nil {
 		logger.Errorf("Ошибка GetUnindexedCatalogItems: %v", err)
-		var validationErr *apierrors.ValidationError
-		if errors.As(err, &validationErr) {
-			c.JSON(http.StatusBadRequest, errorResponse(err))
-		} else {
-			c.JSON(http.StatusInternalServerError, errorResponse(err))
-		}
+		respondError(c, err)
 		return
 	}
 
@@ -136,24 +398,23 @@ func (s *Server) CatalogIndexedHandler(c *gin.Context) {
 	var payload api_models.CatalogIndexedRequest // DTO: { CatalogIDs: []int64 }
 	if err := c.ShouldBindJSON(&payload); err != nil {
 		logger.Errorf("Ошибка парсинга JSON для CatalogIndexed: %v", err)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("некорректный JSON: %w", err)))
+		respondBindError(c, err)
 		return
 	}
 
-	// 1. Вызываем логику (этот метод нам еще нужно создать в tender_services.go)
-	err := s.catalogService.MarkCatalogItemsAsActive(c.Request.Context(), payload.CatalogIDs)
+	// 1. Активируем; пропущенные ID (не найдены, уже активны, влиты) попадают в отчёт
+	report, err := s.catalogService.MarkCatalogItemsAsActive(c.Request.Context(), payload.CatalogIDs)
 	if err != nil {
 		logger.Errorf("Ошибка MarkCatalogItemsAsActive: %v", err)
-		var validationErr *apierrors.ValidationError
-		if errors.As(err, &validationErr) {
-			c.JSON(http.StatusBadRequest, errorResponse(err))
-		} else {
-			c.JSON(http.StatusInternalServerError, errorResponse(err))
-		}
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"status": "ok", "indexed_count": len(payload.CatalogIDs)})
+	c.JSON(http.StatusOK, gin.H{
+		"status":        "ok",
+		"indexed_count": len(report.Activated),
+		"report":        report,
+	})
 }
 
 // === 5. POST /api/v1/merges/suggest ===
@@ -165,7 +426,7 @@ func (s *Server) SuggestMergeHandler(c *gin.Context) {
 	var payload api_models.SuggestMergeRequest // DTO: { MainPositionID: ..., DuplicatePositionID: ..., ... }
 	if err := c.ShouldBindJSON(&payload); err != nil {
 		logger.Errorf("Ошибка парсинга JSON для SuggestMerge: %v", err)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("некорректный JSON: %w", err)))
+		respondBindError(c, err)
 		return
 	}
 
@@ -173,12 +434,7 @@ func (s *Server) SuggestMergeHandler(c *gin.Context) {
 	err := s.catalogService.SuggestMerge(c.Request.Context(), payload)
 	if err != nil {
 		logger.Errorf("Ошибка SuggestMerge: %v", err)
-		var validationErr *apierrors.ValidationError
-		if errors.As(err, &validationErr) {
-			c.JSON(http.StatusBadRequest, errorResponse(err))
-		} else {
-			c.JSON(http.StatusInternalServerError, errorResponse(err))
-		}
+		respondError(c, err)
 		return
 	}
 
@@ -187,7 +443,8 @@ func (s *Server) SuggestMergeHandler(c *gin.Context) {
 
 // === 6. GET /api/v1/catalog/active ===
 
-// ActiveCatalogItemsHandler - хендлер для GET /api/v1/catalog/active (с пагинацией)
+// ActiveCatalogItemsHandler - хендлер для GET /api/v1/catalog/active (с пагинацией).
+// limit/offset возвращают массив; cursor — keyset-страницу с next_cursor.
 func (s *Server) ActiveCatalogItemsHandler(c *gin.Context) {
 	logger := s.logger.WithField("handler", "ActiveCatalogItemsHandler")
 
@@ -196,12 +453,12 @@ func (s *Server) ActiveCatalogItemsHandler(c *gin.Context) {
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil {
 		logger.Errorf("Некорректное значение limit: %s", limitStr)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("параметр limit должен быть целым числом")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.limit_int"))
 		return
 	}
 	if limit <= 0 {
 		logger.Errorf("Некорректное значение limit: %d (должно быть > 0)", limit)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("параметр limit должен быть > 0")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.limit_gt_zero"))
 		return
 	}
 
@@ -212,16 +469,32 @@ func (s *Server) ActiveCatalogItemsHandler(c *gin.Context) {
 		limit = matching.MaxUnmatchedPositionsLimit
 	}
 
+	// cursor (даже пустой) включает keyset-пагинацию: ответ — страница с next_cursor
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		if _, hasOffset := c.GetQuery("offset"); hasOffset {
+			respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.cursor_with_offset"))
+			return
+		}
+		page, err := s.catalogService.GetActiveCatalogItemsPage(c.Request.Context(), int32(limit), cursor)
+		if err != nil {
+			logger.Errorf("Ошибка GetActiveCatalogItemsPage: %v", err)
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, page)
+		return
+	}
+
 	offsetStr := c.DefaultQuery("offset", "0")
 	offset, err := strconv.Atoi(offsetStr)
 	if err != nil {
 		logger.Errorf("Некорректное значение offset: %s", offsetStr)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("параметр offset должен быть целым числом")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.offset_int"))
 		return
 	}
 	if offset < 0 {
 		logger.Errorf("Некорректное значение offset: %d (должно быть >= 0)", offset)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("параметр offset должен быть >= 0")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.offset_gte_zero"))
 		return
 	}
 	// --- Конец логики пагинации ---
@@ -230,7 +503,7 @@ func (s *Server) ActiveCatalogItemsHandler(c *gin.Context) {
 	response, err := s.catalogService.GetAllActiveCatalogItems(c.Request.Context(), int32(limit), int32(offset))
 	if err != nil {
 		logger.Errorf("Ошибка GetAllActiveCatalogItems: %v", err)
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
@@ -254,7 +527,7 @@ func (s *Server) ExecuteMergeHandler(c *gin.Context) {
 	mergeID, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
 		logger.Errorf("Некорректный ID слияния: %s", idStr)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("параметр id должен быть целым числом")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_int"))
 		return
 	}
 
@@ -264,7 +537,7 @@ func (s *Server) ExecuteMergeHandler(c *gin.Context) {
 	body, readErr := c.GetRawData()
 	if readErr != nil {
 		logger.Errorf("Ошибка чтения тела запроса: %v", readErr)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("ошибка чтения тела запроса: %v", readErr)))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.body_read_failed", readErr))
 		return
 	}
 	if len(body) > 0 {
@@ -272,22 +545,22 @@ func (s *Server) ExecuteMergeHandler(c *gin.Context) {
 		decoder.DisallowUnknownFields()
 		if err := decoder.Decode(&req); err != nil {
 			logger.Errorf("Ошибка парсинга тела запроса: %v", err)
-			c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("некорректное тело запроса: %v", err)))
+			respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_body", err))
 			return
 		}
 	}
 
-	// 3. Извлекаем user_id из JWT-контекста (обязателен — роут за RequireRole("admin"))
+	// 3. Извлекаем user_id из JWT-контекста (обязателен — роут за RequirePermission)
 	userID, exists := c.Get("user_id")
 	if !exists {
 		logger.Errorf("user_id отсутствует в контексте (middleware не установил)")
-		c.JSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("user not authenticated")))
+		respondStatus(c, http.StatusUnauthorized, i18n.Errorf("auth.not_authenticated"))
 		return
 	}
 	uid, ok := userID.(int64)
 	if !ok {
 		logger.Errorf("user_id имеет неожиданный тип: %T", userID)
-		c.JSON(http.StatusInternalServerError, errorResponse(fmt.Errorf("invalid user_id type")))
+		respondInternal(c, fmt.Errorf("invalid user_id type"))
 		return
 	}
 	executedBy := strconv.FormatInt(uid, 10)
@@ -296,23 +569,57 @@ func (s *Server) ExecuteMergeHandler(c *gin.Context) {
 	result, err := s.catalogService.ExecuteMerge(c.Request.Context(), mergeID, executedBy, req.NewMainTitle)
 	if err != nil {
 		logger.Errorf("Ошибка ExecuteMerge: %v", err)
-		var validationErr *apierrors.ValidationError
-		var notFoundErr *apierrors.NotFoundError
-		switch {
-		case errors.As(err, &validationErr):
-			c.JSON(http.StatusBadRequest, errorResponse(err))
-		case errors.As(err, &notFoundErr):
-			c.JSON(http.StatusNotFound, errorResponse(err))
-		default:
-			c.JSON(http.StatusInternalServerError, errorResponse(err))
-		}
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// === 7a. POST /api/v1/admin/merges/:id/approve ===
+
+// ApproveMergeHandler одобряет предложение о слиянии: дубликат вливается в мастер,
+// а position_items и matching_cache перевешиваются на мастер-позицию.
+// Требует роль admin. ID берётся из URL, approvedBy — из JWT.
+func (s *Server) ApproveMergeHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "ApproveMergeHandler")
+
+	// 1. Парсим ID из URL
+	idStr := c.Param("id")
+	mergeID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || mergeID <= 0 {
+		logger.Errorf("Некорректный ID слияния: %s", idStr)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive_number"))
+		return
+	}
+
+	// 2. Извлекаем user_id из JWT-контекста
+	userID, exists := c.Get("user_id")
+	if !exists {
+		logger.Errorf("user_id отсутствует в контексте (middleware не установил)")
+		respondStatus(c, http.StatusUnauthorized, i18n.Errorf("auth.not_authenticated"))
+		return
+	}
+	uid, ok := userID.(int64)
+	if !ok {
+		logger.Errorf("user_id имеет неожиданный тип: %T", userID)
+		respondInternal(c, fmt.Errorf("invalid user_id type"))
+		return
+	}
+	approvedBy := strconv.FormatInt(uid, 10)
+
+	// 3. Одобряем и исполняем через сервис
+	result, err := s.catalogService.ApproveMerge(c.Request.Context(), mergeID, approvedBy)
+	if err != nil {
+		logger.Errorf("Ошибка ApproveMerge: %v", err)
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, result)
 }
 
-// === 7b. PATCH /api/v1/admin/merges/:id/reject ===
+// === 7b. POST|PATCH /api/v1/admin/merges/:id/reject ===
 
 // RejectMergeHandler отклоняет предложение о слиянии.
 // Требует роль admin. ID берётся из URL, rejectedBy — из JWT.
@@ -324,12 +631,12 @@ func (s *Server) RejectMergeHandler(c *gin.Context) {
 	mergeID, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
 		logger.Errorf("Некорректный ID слияния: %s", idStr)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("параметр id должен быть целым числом")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_int"))
 		return
 	}
 	if mergeID <= 0 {
 		logger.Errorf("Некорректный ID слияния: %d (должен быть > 0)", mergeID)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("параметр id должен быть положительным числом")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive_number"))
 		return
 	}
 
@@ -337,13 +644,13 @@ func (s *Server) RejectMergeHandler(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
 		logger.Errorf("user_id отсутствует в контексте (middleware не установил)")
-		c.JSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("user not authenticated")))
+		respondStatus(c, http.StatusUnauthorized, i18n.Errorf("auth.not_authenticated"))
 		return
 	}
 	uid, ok := userID.(int64)
 	if !ok {
 		logger.Errorf("user_id имеет неожиданный тип: %T", userID)
-		c.JSON(http.StatusInternalServerError, errorResponse(fmt.Errorf("invalid user_id type")))
+		respondInternal(c, fmt.Errorf("invalid user_id type"))
 		return
 	}
 	rejectedBy := strconv.FormatInt(uid, 10)
@@ -352,18 +659,7 @@ func (s *Server) RejectMergeHandler(c *gin.Context) {
 	err = s.catalogService.RejectMerge(c.Request.Context(), mergeID, rejectedBy)
 	if err != nil {
 		logger.Errorf("Ошибка RejectMerge: %v", err)
-		var validationErr *apierrors.ValidationError
-		var notFoundErr *apierrors.NotFoundError
-		switch {
-		case errors.As(err, &validationErr):
-			c.JSON(http.StatusBadRequest, errorResponse(err))
-		case errors.As(err, &notFoundErr):
-			c.JSON(http.StatusNotFound, errorResponse(err))
-		default:
-			// TODO: заменить на gin.H{"error": "internal server error"} после унификации
-			// всех хендлеров (см. issue «Sanitize 500 error responses across all handlers»).
-			c.JSON(http.StatusInternalServerError, errorResponse(err))
-		}
+		respondError(c, err)
 		return
 	}
 
@@ -382,19 +678,19 @@ func (s *Server) ExecuteBatchMergeHandler(c *gin.Context) {
 	body, readErr := c.GetRawData()
 	if readErr != nil {
 		logger.Errorf("Ошибка чтения тела запроса: %v", readErr)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("ошибка чтения тела запроса: %v", readErr)))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.body_read_failed", readErr))
 		return
 	}
 	if len(body) == 0 {
 		logger.Errorf("Пустое тело запроса")
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("тело запроса обязательно")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.body_required"))
 		return
 	}
 	decoder := json.NewDecoder(bytes.NewReader(body))
 	decoder.DisallowUnknownFields()
 	if err := decoder.Decode(&req); err != nil {
 		logger.Errorf("Ошибка парсинга тела запроса: %v", err)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("некорректное тело запроса: %v", err)))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_body", err))
 		return
 	}
 
@@ -402,13 +698,13 @@ func (s *Server) ExecuteBatchMergeHandler(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
 		logger.Errorf("user_id отсутствует в контексте (middleware не установил)")
-		c.JSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("user not authenticated")))
+		respondStatus(c, http.StatusUnauthorized, i18n.Errorf("auth.not_authenticated"))
 		return
 	}
 	uid, ok := userID.(int64)
 	if !ok {
 		logger.Errorf("user_id имеет неожиданный тип: %T", userID)
-		c.JSON(http.StatusInternalServerError, errorResponse(fmt.Errorf("invalid user_id type")))
+		respondInternal(c, fmt.Errorf("invalid user_id type"))
 		return
 	}
 	executedBy := strconv.FormatInt(uid, 10)
@@ -417,16 +713,7 @@ func (s *Server) ExecuteBatchMergeHandler(c *gin.Context) {
 	result, err := s.catalogService.ExecuteBatchMerge(c.Request.Context(), req, executedBy)
 	if err != nil {
 		logger.Errorf("Ошибка ExecuteBatchMerge: %v", err)
-		var validationErr *apierrors.ValidationError
-		var notFoundErr *apierrors.NotFoundError
-		switch {
-		case errors.As(err, &validationErr):
-			c.JSON(http.StatusBadRequest, errorResponse(err))
-		case errors.As(err, &notFoundErr):
-			c.JSON(http.StatusNotFound, errorResponse(err))
-		default:
-			c.JSON(http.StatusInternalServerError, errorResponse(err))
-		}
+		respondError(c, err)
 		return
 	}
 
@@ -445,12 +732,12 @@ func (s *Server) GroupPositionsHandler(c *gin.Context) {
 	mergeID, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
 		logger.Errorf("Некорректный ID слияния: %s", idStr)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("параметр id должен быть целым числом")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_int"))
 		return
 	}
 	if mergeID <= 0 {
 		logger.Errorf("ID слияния должен быть положительным, получено: %d", mergeID)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("параметр id должен быть положительным целым числом")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive_integer"))
 		return
 	}
 
@@ -459,19 +746,19 @@ func (s *Server) GroupPositionsHandler(c *gin.Context) {
 	body, readErr := c.GetRawData()
 	if readErr != nil {
 		logger.Errorf("Ошибка чтения тела запроса: %v", readErr)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("ошибка чтения тела запроса: %v", readErr)))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.body_read_failed", readErr))
 		return
 	}
 	if len(body) == 0 {
 		logger.Errorf("Пустое тело запроса")
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("тело запроса обязательно")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.body_required"))
 		return
 	}
 	decoder := json.NewDecoder(bytes.NewReader(body))
 	decoder.DisallowUnknownFields()
 	if err := decoder.Decode(&req); err != nil {
 		logger.Errorf("Ошибка парсинга тела запроса: %v", err)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("некорректное тело запроса: %v", err)))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_body", err))
 		return
 	}
 
@@ -479,13 +766,13 @@ func (s *Server) GroupPositionsHandler(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
 		logger.Errorf("user_id отсутствует в контексте (middleware не установил)")
-		c.JSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("user not authenticated")))
+		respondStatus(c, http.StatusUnauthorized, i18n.Errorf("auth.not_authenticated"))
 		return
 	}
 	uid, ok := userID.(int64)
 	if !ok {
 		logger.Errorf("user_id имеет неожиданный тип: %T", userID)
-		c.JSON(http.StatusInternalServerError, errorResponse(fmt.Errorf("invalid user_id type")))
+		respondInternal(c, fmt.Errorf("invalid user_id type"))
 		return
 	}
 	executedBy := strconv.FormatInt(uid, 10)
@@ -494,23 +781,7 @@ func (s *Server) GroupPositionsHandler(c *gin.Context) {
 	result, err := s.catalogService.GroupPositions(c.Request.Context(), mergeID, executedBy, req)
 	if err != nil {
 		logger.Errorf("Ошибка GroupPositions: %v", err)
-		var validationErr *apierrors.ValidationError
-		var notFoundErr *apierrors.NotFoundError
-		var conflictErr *apierrors.ConflictError
-		switch {
-		case errors.As(err, &conflictErr):
-			c.JSON(http.StatusConflict, gin.H{
-				"error":     "positions_already_grouped",
-				"conflicts": conflictErr.Conflicts,
-				"message":   conflictErr.Message,
-			})
-		case errors.As(err, &validationErr):
-			c.JSON(http.StatusBadRequest, errorResponse(err))
-		case errors.As(err, &notFoundErr):
-			c.JSON(http.StatusNotFound, errorResponse(err))
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal_server_error", "message": "internal server error"})
-		}
+		respondErrorCode(c, err, "positions_already_grouped")
 		return
 	}
 
@@ -529,19 +800,19 @@ func (s *Server) GroupBatchPositionsHandler(c *gin.Context) {
 	body, readErr := c.GetRawData()
 	if readErr != nil {
 		logger.Errorf("Ошибка чтения тела запроса: %v", readErr)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("ошибка чтения тела запроса: %v", readErr)))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.body_read_failed", readErr))
 		return
 	}
 	if len(body) == 0 {
 		logger.Errorf("Пустое тело запроса")
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("тело запроса обязательно")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.body_required"))
 		return
 	}
 	decoder := json.NewDecoder(bytes.NewReader(body))
 	decoder.DisallowUnknownFields()
 	if err := decoder.Decode(&req); err != nil {
 		logger.Errorf("Ошибка парсинга тела запроса: %v", err)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("некорректное тело запроса: %v", err)))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_body", err))
 		return
 	}
 
@@ -549,13 +820,13 @@ func (s *Server) GroupBatchPositionsHandler(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
 		logger.Errorf("user_id отсутствует в контексте (middleware не установил)")
-		c.JSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("user not authenticated")))
+		respondStatus(c, http.StatusUnauthorized, i18n.Errorf("auth.not_authenticated"))
 		return
 	}
 	uid, ok := userID.(int64)
 	if !ok {
 		logger.Errorf("user_id имеет неожиданный тип: %T", userID)
-		c.JSON(http.StatusInternalServerError, errorResponse(fmt.Errorf("invalid user_id type")))
+		respondInternal(c, fmt.Errorf("invalid user_id type"))
 		return
 	}
 	executedBy := strconv.FormatInt(uid, 10)
@@ -564,23 +835,7 @@ func (s *Server) GroupBatchPositionsHandler(c *gin.Context) {
 	result, err := s.catalogService.GroupBatchPositions(c.Request.Context(), req, executedBy)
 	if err != nil {
 		logger.Errorf("Ошибка GroupBatchPositions: %v", err)
-		var validationErr *apierrors.ValidationError
-		var notFoundErr *apierrors.NotFoundError
-		var conflictErr *apierrors.ConflictError
-		switch {
-		case errors.As(err, &conflictErr):
-			c.JSON(http.StatusConflict, gin.H{
-				"error":     "positions_already_grouped",
-				"conflicts": conflictErr.Conflicts,
-				"message":   conflictErr.Message,
-			})
-		case errors.As(err, &validationErr):
-			c.JSON(http.StatusBadRequest, errorResponse(err))
-		case errors.As(err, &notFoundErr):
-			c.JSON(http.StatusNotFound, errorResponse(err))
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal_server_error", "message": "internal server error"})
-		}
+		respondErrorCode(c, err, "positions_already_grouped")
 		return
 	}
 
@@ -598,7 +853,7 @@ func (s *Server) ListGroupsHandler(c *gin.Context) {
 	limit64, err := strconv.ParseInt(limitStr, 10, 32)
 	if err != nil || limit64 <= 0 {
 		logger.Errorf("Некорректное значение limit: %s", limitStr)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("параметр limit должен быть целым числом > 0")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.limit_positive"))
 		return
 	}
 
@@ -606,14 +861,14 @@ func (s *Server) ListGroupsHandler(c *gin.Context) {
 	offset64, err := strconv.ParseInt(offsetStr, 10, 32)
 	if err != nil || offset64 < 0 {
 		logger.Errorf("Некорректное значение offset: %s", offsetStr)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("параметр offset должен быть целым числом >= 0")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.offset_non_negative"))
 		return
 	}
 
 	response, err := s.catalogService.ListGroups(c.Request.Context(), int32(limit64), int32(offset64))
 	if err != nil {
 		logger.Errorf("Ошибка ListGroups: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal_server_error", "message": "internal server error"})
+		respondInternal(c, err)
 		return
 	}
 
@@ -628,20 +883,20 @@ func (s *Server) UngroupPositionHandler(c *gin.Context) {
 	positionID, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil || positionID <= 0 {
 		logger.Errorf("Некорректный ID позиции: %s", idStr)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("параметр id должен быть целым числом > 0")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
 		return
 	}
 
 	userID, exists := c.Get("user_id")
 	if !exists {
 		logger.Errorf("user_id отсутствует в контексте")
-		c.JSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("user not authenticated")))
+		respondStatus(c, http.StatusUnauthorized, i18n.Errorf("auth.not_authenticated"))
 		return
 	}
 	uid, ok := userID.(int64)
 	if !ok {
 		logger.Errorf("user_id имеет неожиданный тип: %T", userID)
-		c.JSON(http.StatusInternalServerError, errorResponse(fmt.Errorf("invalid user_id type")))
+		respondInternal(c, fmt.Errorf("invalid user_id type"))
 		return
 	}
 	executedBy := strconv.FormatInt(uid, 10)
@@ -649,16 +904,7 @@ func (s *Server) UngroupPositionHandler(c *gin.Context) {
 	err = s.catalogService.UngroupPosition(c.Request.Context(), positionID, executedBy)
 	if err != nil {
 		logger.Errorf("Ошибка UngroupPosition(id=%d): %v", positionID, err)
-		var validationErr *apierrors.ValidationError
-		var notFoundErr *apierrors.NotFoundError
-		switch {
-		case errors.As(err, &validationErr):
-			c.JSON(http.StatusBadRequest, errorResponse(err))
-		case errors.As(err, &notFoundErr):
-			c.JSON(http.StatusNotFound, errorResponse(err))
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal_server_error", "message": "internal server error"})
-		}
+		respondError(c, err)
 		return
 	}
 
@@ -673,14 +919,125 @@ func (s *Server) ListGroupChildrenHandler(c *gin.Context) {
 	groupID, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil || groupID <= 0 {
 		logger.Errorf("Некорректный ID группы: %s", idStr)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("параметр id должен быть целым числом > 0")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
 		return
 	}
 
 	response, err := s.catalogService.ListGroupChildren(c.Request.Context(), groupID)
 	if err != nil {
 		logger.Errorf("Ошибка ListGroupChildren(id=%d): %v", groupID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal_server_error", "message": "internal server error"})
+		respondInternal(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SetCatalogPositionPinnedHandler — PATCH /api/v1/admin/catalog/positions/:id/pin
+// Требует роль admin. Тело: {"pinned": true|false}.
+func (s *Server) SetCatalogPositionPinnedHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "SetCatalogPositionPinnedHandler")
+
+	idStr := c.Param("id")
+	positionID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || positionID <= 0 {
+		logger.Errorf("Некорректный ID позиции: %s", idStr)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return
+	}
+
+	// Парсим тело запроса (strict: запрещаем неизвестные поля)
+	var req api_models.SetCatalogPositionPinnedRequest
+	body, readErr := c.GetRawData()
+	if readErr != nil {
+		logger.Errorf("Ошибка чтения тела запроса: %v", readErr)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.body_read_failed", readErr))
+		return
+	}
+	if len(body) == 0 {
+		logger.Errorf("Пустое тело запроса")
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.body_required"))
+		return
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		logger.Errorf("Ошибка парсинга тела запроса: %v", err)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_body", err))
+		return
+	}
+	if req.Pinned == nil {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.pinned_required"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		logger.Errorf("user_id отсутствует в контексте")
+		respondStatus(c, http.StatusUnauthorized, i18n.Errorf("auth.not_authenticated"))
+		return
+	}
+	uid, ok := userID.(int64)
+	if !ok {
+		logger.Errorf("user_id имеет неожиданный тип: %T", userID)
+		respondInternal(c, fmt.Errorf("invalid user_id type"))
+		return
+	}
+	executedBy := strconv.FormatInt(uid, 10)
+
+	response, err := s.catalogService.SetCatalogPositionPinned(c.Request.Context(), positionID, *req.Pinned, executedBy)
+	if err != nil {
+		logger.Errorf("Ошибка SetCatalogPositionPinned(id=%d): %v", positionID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeprecateCatalogPositionHandler — POST /api/v1/admin/catalog/positions/:id/deprecate
+// Требует право catalog:manage. Тело: {"replaced_by_id": 123}.
+// Ошибки: 400 (id, тело, неподходящая замена), 404, 409 (уже выведена или влита).
+func (s *Server) DeprecateCatalogPositionHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "DeprecateCatalogPositionHandler")
+
+	idStr := c.Param("id")
+	positionID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || positionID <= 0 {
+		logger.Errorf("Некорректный ID позиции: %s", idStr)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return
+	}
+
+	var req api_models.DeprecateCatalogPositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	uid, ok := s.adminActorID(c, logger)
+	if !ok {
+		return
+	}
+
+	response, err := s.catalogService.DeprecateCatalogPosition(c.Request.Context(), positionID, req.ReplacedByID, strconv.FormatInt(uid, 10))
+	if err != nil {
+		logger.Errorf("Ошибка DeprecateCatalogPosition(id=%d): %v", positionID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListPinnedCatalogPositionsHandler — GET /api/v1/admin/catalog/pinned
+func (s *Server) ListPinnedCatalogPositionsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "ListPinnedCatalogPositionsHandler")
+
+	response, err := s.catalogService.ListPinnedCatalogPositions(c.Request.Context())
+	if err != nil {
+		logger.Errorf("Ошибка ListPinnedCatalogPositions: %v", err)
+		respondInternal(c, err)
 		return
 	}
 
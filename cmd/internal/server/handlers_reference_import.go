@@ -0,0 +1,93 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/auth"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/refcache"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/refimport"
+)
+
+// importReferenceHandler обрабатывает POST /api/v1/admin/reference/import.
+//
+// Загружает типы тендеров, разделы, категории и синонимы единиц из xlsx или
+// CSV (multipart, поле file, не больше upload.max_file_size). Файл
+// применяется целиком в одной транзакции; строки unit_alias требуют ещё и
+// права catalog:manage.
+//
+// Query-параметры:
+//   - dry_run (bool, default false): только проверить и показать действия
+//
+// Response: 200 + ReferenceImportReport (при dry_run — и с ошибками в строках)
+// Errors:   400 (нечитаемый файл, ошибки в строках — details по строкам), 413 (файл больше лимита), 500 (БД)
+func (s *Server) importReferenceHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "importReferenceHandler")
+
+	dryRun := false
+	if v := c.Query("dry_run"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.dry_run_bool"))
+			return
+		}
+		dryRun = parsed
+	}
+
+	maxSize := s.config.Upload.MaxFileSize
+	if c.Request.ContentLength > maxSize+uploadFormOverhead {
+		s.uploadTooLarge(c, logger)
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+uploadFormOverhead)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			s.uploadTooLarge(c, logger)
+		case errors.Is(err, http.ErrMissingFile):
+			respondStatus(c, http.StatusBadRequest, errUploadFileMissing)
+		default:
+			logger.Errorf("ошибка чтения формы: %v", err)
+			respondStatus(c, http.StatusBadRequest, i18n.Errorf("upload.multipart_expected"))
+		}
+		return
+	}
+	if header.Size > maxSize {
+		s.uploadTooLarge(c, logger)
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		respondInternal(c, err)
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		respondInternal(c, err)
+		return
+	}
+
+	report, err := s.refImport.Import(c.Request.Context(), data, refimport.Options{
+		DryRun:      dryRun,
+		UnitAliases: auth.HasPermission(c.GetString("role"), auth.PermissionCatalogManage),
+	})
+	if err != nil {
+		logger.Errorf("Ошибка загрузки справочников из %s (dry_run=%t): %v", header.Filename, dryRun, err)
+		respondError(c, err)
+		return
+	}
+
+	if report.Applied {
+		s.refCache.Invalidate(c.Request.Context(), refcache.Groups...)
+	}
+	c.JSON(http.StatusOK, report)
+}
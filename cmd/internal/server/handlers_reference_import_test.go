@@ -0,0 +1,144 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil/servertest"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR REFERENCE IMPORT (Integration: Router + Handler + Service)
+
+What user problems does this protect us from?
+================================================================================
+1. Reference edits by users without reference:manage
+2. Unit aliases (a catalog concern) imported by editors without catalog:manage
+3. A file with errors reported as a generic 500 instead of per-row details
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Permissions
+- GIVEN a viewer WHEN POST /admin/reference/import THEN 403, no DB calls
+- GIVEN an editor and a unit_alias row WHEN dry_run=true
+  THEN 200, the row is reported as forbidden, nothing applied
+
+SCENARIO 2: Errors
+- GIVEN an admin and a row of unknown kind WHEN applying
+  THEN 400 validation_failed with a detail for rows[2]
+- GIVEN no file part → 400; dry_run=maybe → 400
+*/
+
+func withUploadLimit(cfg *config.Config) {
+	cfg.Upload.MaxFileSize = 1 << 20
+}
+
+// doReferenceImport отправляет file как часть file формы multipart.
+func doReferenceImport(t *testing.T, h *servertest.Harness, query string, file []byte, user *servertest.User) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if file != nil {
+		part, err := writer.CreateFormFile("file", "reference.csv")
+		require.NoError(t, err)
+		_, err = part.Write(file)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/reference/import"+query, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.AddCookie(&http.Cookie{Name: h.Config.Auth.CookieAccessName, Value: h.AccessToken(t, user)})
+	req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "reference-csrf"})
+	req.Header.Set("X-CSRF-Token", "reference-csrf")
+
+	w := httptest.NewRecorder()
+	h.Handler.ServeHTTP(w, req)
+	return w
+}
+
+// expectEmptyTx — транзакция без запросов: все строки файла отклонены до БД.
+func expectEmptyTx(store *db.MockStore) {
+	store.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, fn func(*db.Queries) error) error { return fn(nil) })
+}
+
+func TestReferenceImport_Permissions(t *testing.T) {
+	file := []byte("kind,title,parent\nunit_alias,кв.м,м2\n")
+
+	t.Run("viewer", func(t *testing.T) {
+		h := servertest.New(t, withUploadLimit)
+
+		w := doReferenceImport(t, h, "", file, servertest.Viewer)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("editor without catalog:manage", func(t *testing.T) {
+		h := servertest.New(t, withUploadLimit)
+		expectEmptyTx(h.Store)
+
+		w := doReferenceImport(t, h, "?dry_run=true", file, servertest.Editor)
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var report api_models.ReferenceImportReport
+		servertest.DecodeJSON(t, w, &report)
+		assert.True(t, report.DryRun)
+		assert.False(t, report.Valid)
+		assert.False(t, report.Applied)
+		require.Len(t, report.Rows, 1)
+		assert.Equal(t, "forbidden", report.Rows[0].Code)
+	})
+}
+
+func TestReferenceImport_Errors(t *testing.T) {
+	t.Run("row errors on apply", func(t *testing.T) {
+		h := servertest.New(t, withUploadLimit)
+		expectEmptyTx(h.Store)
+
+		w := doReferenceImport(t, h, "", []byte("kind;title\nsection;Кровля\n"), servertest.Admin)
+
+		require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		var body struct {
+			Code    string `json:"code"`
+			Details []struct {
+				Field string `json:"field"`
+				Rule  string `json:"rule"`
+			} `json:"details"`
+		}
+		servertest.DecodeJSON(t, w, &body)
+		assert.Equal(t, "validation_failed", body.Code)
+		require.Len(t, body.Details, 1)
+		assert.Equal(t, "rows[2]", body.Details[0].Field)
+		assert.Equal(t, "invalid_kind", body.Details[0].Rule)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		h := servertest.New(t, withUploadLimit)
+
+		w := doReferenceImport(t, h, "", nil, servertest.Admin)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid dry_run", func(t *testing.T) {
+		h := servertest.New(t, withUploadLimit)
+
+		w := doReferenceImport(t, h, "?dry_run=maybe", []byte("kind,title\ntype,Ремонт\n"), servertest.Admin)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,191 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/report"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// getSavingsReportHandler обрабатывает GET /api/v1/reports/savings.
+// Query: from, to (ГГГГ-ММ-ДД, to включительно), category_id — все опциональны.
+// Учитываются только тендеры организации пользователя (admin — все).
+func (s *Server) getSavingsReportHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "getSavingsReportHandler")
+
+	query := report.SavingsQuery{OrganizationID: requestOrganizationScope(c)}
+	var err error
+	if query.From, err = report.ParseDate("from", c.Query("from")); err != nil {
+		respondStatus(c, http.StatusBadRequest, err)
+		return
+	}
+	if query.To, err = report.ParseDate("to", c.Query("to")); err != nil {
+		respondStatus(c, http.StatusBadRequest, err)
+		return
+	}
+	if v := c.Query("category_id"); v != "" {
+		categoryID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || categoryID <= 0 {
+			respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.category_id_positive_integer"))
+			return
+		}
+		query.CategoryID = sql.NullInt64{Int64: categoryID, Valid: true}
+	}
+
+	response, err := s.reports.GetSavingsReport(c.Request.Context(), query)
+	if err != nil {
+		var validationErr *apierrors.ValidationError
+		if errors.As(err, &validationErr) {
+			respondStatus(c, http.StatusBadRequest, err)
+			return
+		}
+		logger.Errorf("Ошибка GetSavingsReport: %v", err)
+		respondInternal(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// HandleListScheduledReports обрабатывает GET /api/v1/admin/reports/schedules.
+func (s *Server) HandleListScheduledReports(c *gin.Context) {
+	logger := s.logger.WithField("handler", "HandleListScheduledReports")
+
+	schedules, err := s.reports.ListSchedules(c.Request.Context())
+	if err != nil {
+		logger.Errorf("Ошибка ListSchedules: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedules)
+}
+
+// HandleCreateScheduledReport обрабатывает POST /api/v1/admin/reports/schedules.
+//
+// Request:  CreateScheduledReportRequest (strict JSON: DisallowUnknownFields)
+// Response: 201 + ScheduledReportResponse
+// Errors:   400 (вид, формат, периодичность, адреса), 500 (БД)
+func (s *Server) HandleCreateScheduledReport(c *gin.Context) {
+	logger := s.logger.WithField("handler", "HandleCreateScheduledReport")
+
+	var req api_models.CreateScheduledReportRequest
+	if !decodeStrictJSON(c, logger, &req) {
+		return
+	}
+
+	actorID, ok := s.adminActorID(c, logger)
+	if !ok {
+		return
+	}
+
+	result, err := s.reports.CreateSchedule(c.Request.Context(), req, actorID)
+	if err != nil {
+		logger.Errorf("Ошибка CreateSchedule: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// HandleUpdateScheduledReport обрабатывает PATCH /api/v1/admin/reports/schedules/:id.
+//
+// Request:  UpdateScheduledReportRequest (strict JSON: DisallowUnknownFields)
+// Response: 200 + ScheduledReportResponse
+// Errors:   400 (валидация), 404 (отчет не найден), 500 (БД)
+func (s *Server) HandleUpdateScheduledReport(c *gin.Context) {
+	logger := s.logger.WithField("handler", "HandleUpdateScheduledReport")
+
+	id, ok := parseScheduledReportID(c, logger)
+	if !ok {
+		return
+	}
+
+	var req api_models.UpdateScheduledReportRequest
+	if !decodeStrictJSON(c, logger, &req) {
+		return
+	}
+
+	result, err := s.reports.UpdateSchedule(c.Request.Context(), id, req)
+	if err != nil {
+		logger.Errorf("Ошибка UpdateSchedule(id=%d): %v", id, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// HandleDeleteScheduledReport обрабатывает DELETE /api/v1/admin/reports/schedules/:id.
+// Удаляет отчет вместе с историей запусков; чтобы сохранить историю,
+// отчет деактивируют через PATCH {"is_active": false}.
+func (s *Server) HandleDeleteScheduledReport(c *gin.Context) {
+	logger := s.logger.WithField("handler", "HandleDeleteScheduledReport")
+
+	id, ok := parseScheduledReportID(c, logger)
+	if !ok {
+		return
+	}
+
+	if err := s.reports.DeleteSchedule(c.Request.Context(), id); err != nil {
+		logger.Errorf("Ошибка DeleteSchedule(id=%d): %v", id, err)
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// HandleListReportRuns обрабатывает GET /api/v1/admin/reports/schedules/:id/runs.
+//
+// Query-параметры: limit (default 50, max 200), offset (default 0)
+//
+// Response: 200 + ReportRunsResponse
+// Errors:   400 (параметры), 404 (отчет не найден), 500 (БД)
+func (s *Server) HandleListReportRuns(c *gin.Context) {
+	logger := s.logger.WithField("handler", "HandleListReportRuns")
+
+	id, ok := parseScheduledReportID(c, logger)
+	if !ok {
+		return
+	}
+
+	limit64, err := strconv.ParseInt(c.DefaultQuery("limit", "50"), 10, 32)
+	if err != nil || limit64 <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.limit_positive"))
+		return
+	}
+	offset64, err := strconv.ParseInt(c.DefaultQuery("offset", "0"), 10, 32)
+	if err != nil || offset64 < 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.offset_non_negative"))
+		return
+	}
+
+	result, err := s.reports.ListRuns(c.Request.Context(), id, int32(limit64), int32(offset64))
+	if err != nil {
+		logger.Errorf("Ошибка ListRuns(id=%d): %v", id, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func parseScheduledReportID(c *gin.Context, logger logging.Logger) (int64, bool) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		logger.Errorf("Некорректный ID регулярного отчета: %s", idStr)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return 0, false
+	}
+	return id, true
+}
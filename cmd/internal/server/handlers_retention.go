@@ -0,0 +1,89 @@
+package server
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+)
+
+// listRetentionPoliciesHandler обрабатывает GET /api/v1/admin/retention.
+// Возвращает политики хранения с настройками и последним запуском каждой.
+func (s *Server) listRetentionPoliciesHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "listRetentionPoliciesHandler")
+
+	resp, err := s.retention.ListPolicies(c.Request.Context())
+	if err != nil {
+		logger.Errorf("Ошибка ListPolicies: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// listRetentionRunsHandler обрабатывает GET /api/v1/admin/retention/runs.
+//
+// Query:    policy — только запуски этой политики; limit (50), offset
+// Response: 200 + RetentionRunsResponse, новые первыми
+// Errors:   400 (неизвестная политика, limit, offset), 500 (БД)
+func (s *Server) listRetentionRunsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "listRetentionRunsHandler")
+
+	limit64, err := strconv.ParseInt(c.DefaultQuery("limit", "50"), 10, 32)
+	if err != nil || limit64 <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.limit_positive"))
+		return
+	}
+	offset64, err := strconv.ParseInt(c.DefaultQuery("offset", "0"), 10, 32)
+	if err != nil || offset64 < 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.offset_non_negative"))
+		return
+	}
+
+	resp, err := s.retention.ListRuns(c.Request.Context(), c.Query("policy"), int32(limit64), int32(offset64))
+	if err != nil {
+		logger.Errorf("Ошибка ListRuns: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// runRetentionPolicyHandler обрабатывает POST /api/v1/admin/retention/:policy/run.
+//
+// Query:    dry_run (bool, default false) — только посчитать подходящие строки
+// Response: 200 + RetentionRun
+// Errors:   400 (dry_run), 404 (неизвестная политика), 409 (политика отключена),
+// 500 (БД; неудачный запуск записан в журнал)
+func (s *Server) runRetentionPolicyHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "runRetentionPolicyHandler")
+
+	dryRun := false
+	if v := c.Query("dry_run"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.dry_run_bool"))
+			return
+		}
+		dryRun = parsed
+	}
+
+	actorID, ok := s.adminActorID(c, logger)
+	if !ok {
+		return
+	}
+
+	policy := c.Param("policy")
+	run, err := s.retention.RunPolicy(c.Request.Context(), policy, dryRun, sql.NullInt64{Int64: actorID, Valid: true})
+	if err != nil {
+		logger.Errorf("Ошибка RunPolicy(%s, dry_run=%t): %v", policy, dryRun, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+)
+
+// listSavedFiltersHandler обрабатывает GET /api/v1/me/saved-filters —
+// сохранённые фильтры списка тендеров текущего пользователя.
+func (s *Server) listSavedFiltersHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "listSavedFiltersHandler")
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := s.tenderArchive.ListSavedFilters(c.Request.Context(), userID)
+	if err != nil {
+		logger.Errorf("Ошибка ListSavedFilters(%d): %v", userID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// createSavedFilterHandler обрабатывает POST /api/v1/me/saved-filters.
+// filters проверяются так же, как query string GET /api/v1/tenders, поэтому
+// сохранённый фильтр всегда открывается без ошибки.
+//
+// Response: 201 + SavedFilter
+// Errors:   400 (имя, параметры, лимит фильтров), 403 (include_deleted без права
+// tenders:manage_deleted), 409 (имя занято), 500 (БД)
+func (s *Server) createSavedFilterHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "createSavedFilterHandler")
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	var req api_models.CreateSavedFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	filters, query, err := parseSavedFilter(req.Filters)
+	if err != nil {
+		respondStatus(c, http.StatusBadRequest, err)
+		return
+	}
+	if !includeDeletedAllowed(c, query.IncludeDeleted) {
+		return
+	}
+
+	resp, err := s.tenderArchive.CreateSavedFilter(c.Request.Context(), userID, req.Name, filters)
+	if err != nil {
+		logger.Errorf("Ошибка CreateSavedFilter(user_id=%d, %q): %v", userID, req.Name, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// deleteSavedFilterHandler обрабатывает DELETE /api/v1/me/saved-filters/:id.
+// Чужой фильтр — 404.
+func (s *Server) deleteSavedFilterHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "deleteSavedFilterHandler")
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return
+	}
+
+	if err := s.tenderArchive.DeleteSavedFilter(c.Request.Context(), userID, id); err != nil {
+		logger.Errorf("Ошибка DeleteSavedFilter(user_id=%d, id=%d): %v", userID, id, err)
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
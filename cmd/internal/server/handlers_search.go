@@ -0,0 +1,43 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/search"
+)
+
+// globalSearchHandler обрабатывает GET /api/v1/search — поиск одной строкой
+// по тендерам, подрядчикам, объектам и позициям каталога.
+// Query: q (обязателен, от 2 символов), types (через запятую, по умолчанию
+// все группы), limit (совпадений в группе, 1..20). Тендеры и объекты — только
+// организации пользователя (admin — всех).
+func (s *Server) globalSearchHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "globalSearchHandler")
+
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", strconv.Itoa(search.DefaultLimit)), 10, 32)
+	if err != nil {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_limit_range", search.MaxLimit))
+		return
+	}
+	query := search.Query{
+		Text:           c.Query("q"),
+		Limit:          int32(limit),
+		OrganizationID: requestOrganizationScope(c),
+	}
+	if raw := c.Query("types"); raw != "" {
+		query.Types = strings.Split(raw, ",")
+	}
+
+	response, err := s.search.Search(c.Request.Context(), query)
+	if err != nil {
+		logger.Errorf("Ошибка Search(%q): %v", query.Text, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
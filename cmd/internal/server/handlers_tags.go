@@ -0,0 +1,132 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+)
+
+// listTagsHandler обрабатывает GET /api/v1/tags — метки организации
+// пользователя по алфавиту (admin — всех организаций).
+func (s *Server) listTagsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "listTagsHandler")
+
+	resp, err := s.tenderArchive.ListTags(c.Request.Context(), requestOrganizationScope(c))
+	if err != nil {
+		logger.Errorf("Ошибка ListTags: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// createTagHandler обрабатывает POST /api/v1/tags. Метка создаётся в
+// организации пользователя.
+//
+// Response: 201 + Tag
+// Errors:   400 (название пустое или длиннее 64 символов), 409 (название занято), 500 (БД)
+func (s *Server) createTagHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "createTagHandler")
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	var req api_models.CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := s.tenderArchive.CreateTag(c.Request.Context(), c.GetInt64("organization_id"), req, userID)
+	if err != nil {
+		logger.Errorf("Ошибка CreateTag(%q): %v", req.Name, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// listTenderTagsHandler обрабатывает GET /api/v1/tenders/:id/tags.
+func (s *Server) listTenderTagsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "listTenderTagsHandler")
+
+	tenderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || tenderID <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return
+	}
+
+	resp, err := s.tenderArchive.TenderTags(c.Request.Context(), tenderID)
+	if err != nil {
+		logger.Errorf("Ошибка TenderTags(%d): %v", tenderID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// attachTenderTagHandler обрабатывает PUT /api/v1/tenders/:id/tags/:tagId.
+// Повторная привязка не ошибка; метка чужой организации — 404.
+func (s *Server) attachTenderTagHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "attachTenderTagHandler")
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	tenderID, tagID, ok := parseTenderTagParams(c)
+	if !ok {
+		return
+	}
+
+	resp, err := s.tenderArchive.AttachTag(c.Request.Context(), tenderID, tagID, userID)
+	if err != nil {
+		logger.Errorf("Ошибка AttachTag(tender_id=%d, tag_id=%d): %v", tenderID, tagID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// detachTenderTagHandler обрабатывает DELETE /api/v1/tenders/:id/tags/:tagId.
+// Отсутствующая привязка не ошибка.
+func (s *Server) detachTenderTagHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "detachTenderTagHandler")
+
+	tenderID, tagID, ok := parseTenderTagParams(c)
+	if !ok {
+		return
+	}
+
+	resp, err := s.tenderArchive.DetachTag(c.Request.Context(), tenderID, tagID)
+	if err != nil {
+		logger.Errorf("Ошибка DetachTag(tender_id=%d, tag_id=%d): %v", tenderID, tagID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// parseTenderTagParams разбирает :id и :tagId. При ошибке ответ уже отправлен.
+func parseTenderTagParams(c *gin.Context) (tenderID, tagID int64, ok bool) {
+	tenderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || tenderID <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return 0, 0, false
+	}
+	tagID, err = strconv.ParseInt(c.Param("tagId"), 10, 64)
+	if err != nil || tagID <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.param_positive_integer", "tagId"))
+		return 0, 0, false
+	}
+	return tenderID, tagID, true
+}
@@ -1,16 +1,27 @@
 package server
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/lib/pq"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/db/postgres"
 	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/auth"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/priceindex"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/webhooks"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/util"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -21,53 +32,78 @@ const (
 )
 
 type listTendersResponse struct {
-	ID                 int64         `json:"id"`
-	EtpID              string        `json:"etp_id"`
-	Title              string        `json:"title"`
-	DataPreparedOnDate string        `json:"data_prepared_on_date"` // <--- ТЕПЕРЬ ПРОСТО string
-	ObjectAddress      string        `json:"object_address"`
-	ExecutorName       string        `json:"executor_name"`
-	ProposalsCount     int64         `json:"proposals_count"`
-	CategoryID         sql.NullInt64 `json:"category_id"` // Добавили поле
+	ID                 int64             `json:"id"`
+	EtpID              string            `json:"etp_id"`
+	Title              string            `json:"title"`
+	DataPreparedOnDate string            `json:"data_prepared_on_date"` // <--- ТЕПЕРЬ ПРОСТО string
+	ObjectAddress      string            `json:"object_address"`
+	ExecutorName       string            `json:"executor_name"`
+	ProposalsCount     int64             `json:"proposals_count"`
+	CategoryID         sql.NullInt64     `json:"category_id"`          // Добавили поле
+	TotalCost          *api_models.Money `json:"total_cost,omitempty"` // Сумма победителей
+	HasWinner          bool              `json:"has_winner"`
+	DeletedAt          *time.Time        `json:"deleted_at,omitempty"` // Только при include_deleted=true
 }
 
+// listTendersPageResponse - ответ GET /api/v1/tenders с метаданными пагинации.
+type listTendersPageResponse struct {
+	Tenders    []listTendersResponse `json:"tenders"`
+	TotalCount int64                 `json:"total_count"`
+	Page       int32                 `json:"page,omitempty"` // Нет при пагинации по cursor
+	PageSize   int32                 `json:"page_size"`
+	NextCursor string                `json:"next_cursor,omitempty"` // Только при cursor; пусто - последняя страница
+}
+
+// listTendersHandler - GET /api/v1/tenders.
+// Поддерживает пагинацию (page, page_size), фильтры (category_id, chapter_id, type_id,
+// executor_id, object_id, date_from, date_to, has_winner) и сортировку (sort_by, sort_order).
+// cursor (даже пустой) вместо page включает keyset-пагинацию от новых к старым
+// по (created_at, id): глубокие страницы не дороже первой, в ответе next_cursor.
+// include_deleted=true (только admin) добавляет в выборку мягко удалённые тендеры.
+// Пользователь видит только тендеры своей организации (admin — всех).
+// Разбор параметров вынесен в parseTenderListQuery.
 func (s *Server) listTendersHandler(c *gin.Context) {
-	// 1. Получаем параметры пагинации из URL query string.
-	// Используем DefaultQuery, чтобы задать значения по умолчанию, если параметры не переданы.
-	pageIDStr := c.DefaultQuery("page", "1")
-	pageSizeStr := c.DefaultQuery("page_size", "10")
+	logger := s.logger.WithField("handler", "listTendersHandler")
 
-	// 2. Конвертируем строковые параметры в числа.
-	pageID, err := strconv.ParseInt(pageIDStr, 10, 32)
-	if err != nil || pageID < 1 {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный параметр page")))
+	// 1. Разбираем и валидируем query string.
+	query, err := parseTenderListQuery(c.Request.URL.Query())
+	if err != nil {
+		respondStatus(c, http.StatusBadRequest, err)
 		return
 	}
-
-	pageSize, err := strconv.ParseInt(pageSizeStr, 10, 32)
-	if err != nil || pageSize < 1 || pageSize > 100 { // Ограничиваем максимальный размер страницы
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный параметр page_size (допустимо от 1 до 100)")))
+	if !includeDeletedAllowed(c, query.IncludeDeleted) {
 		return
 	}
+	query.OrganizationID = requestOrganizationScope(c)
 
-	// 3. Создаем структуру с параметрами для sqlc.
-	params := db.ListTendersParams{
-		Limit:  int32(pageSize),
-		Offset: (int32(pageID) - 1) * int32(pageSize),
-	}
-
-	dbTenders, err := s.store.ListTenders(c.Request.Context(), params)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+	// 2. Страница и общее количество запрашиваются параллельно.
+	var (
+		dbTenders  []db.ListTendersRow
+		totalCount int64
+	)
+	g, ctx := errgroup.WithContext(c.Request.Context())
+	g.Go(func() error {
+		var err error
+		dbTenders, err = s.store.ListTenders(ctx, query.listParams())
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		totalCount, err = s.store.CountTenders(ctx, query.countParams())
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		logger.Errorf("Ошибка получения списка тендеров: %v", err)
+		respondError(c, err)
 		return
 	}
 
+	dbTenders, nextCursor := query.nextCursor(dbTenders)
 	apiResponse := make([]listTendersResponse, 0, len(dbTenders))
 
 	for _, dbTender := range dbTenders {
 		formattedDate := ""
 		if dbTender.DataPreparedOnDate.Valid {
-			// --- ИЗМЕНЕНИЕ ЗДЕСЬ ---
 			// Форматируем дату в нужный вид "ДД-ММ-ГГГГ"
 			formattedDate = dbTender.DataPreparedOnDate.Time.Format("02-01-2006")
 		}
@@ -81,11 +117,35 @@ func (s *Server) listTendersHandler(c *gin.Context) {
 			ExecutorName:       dbTender.ExecutorName,
 			ProposalsCount:     dbTender.ProposalsCount,
 			CategoryID:         dbTender.CategoryID,
+			TotalCost:          api_models.MoneyFromNullString(dbTender.TotalCost),
+			HasWinner:          dbTender.HasWinner,
+		}
+		if dbTender.DeletedAt.Valid {
+			apiTender.DeletedAt = &dbTender.DeletedAt.Time
 		}
 		apiResponse = append(apiResponse, apiTender)
 	}
 
-	c.JSON(http.StatusOK, apiResponse)
+	c.JSON(http.StatusOK, listTendersPageResponse{
+		Tenders:    apiResponse,
+		TotalCount: totalCount,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+		NextCursor: nextCursor,
+	})
+}
+
+// includeDeletedAllowed проверяет право на include_deleted=true: мягко удалённые
+// тендеры видит только роль с tenders:manage_deleted. При отказе пишет 403 и возвращает false.
+func includeDeletedAllowed(c *gin.Context, includeDeleted bool) bool {
+	if !includeDeleted {
+		return true
+	}
+	if role := c.GetString("role"); !auth.HasPermission(role, auth.PermissionTendersManageDeleted) {
+		respondCode(c, http.StatusForbidden, CodeForbidden, "auth.insufficient_permissions")
+		return false
+	}
+	return true
 }
 
 // Определяем структуры для нашего комплексного API-ответа
@@ -101,31 +161,34 @@ type ProposalResponse struct {
 	ContractorName string            `json:"contractor_name"`
 	ContractorInn  string            `json:"contractor_inn"`
 	IsBaseline     bool              `json:"is_baseline"`
-	TotalCost      *string           `json:"total_cost,omitempty"`
+	TotalCost      *api_models.Money `json:"total_cost,omitempty"`
+	Currency       string            `json:"currency"` // Валюта итога (ISO 4217)
 	IsWinner       bool              `json:"is_winner"`
 	AdditionalInfo map[string]string `json:"additional_info,omitempty"`
 }
 
 type WinnerResponse struct {
-	ID             int64   `json:"id"` // ID записи победителя (для редактирования/удаления)
-	ProposalID     int64   `json:"proposal_id"`
-	ContractorName string  `json:"contractor_name"` // Название подрядчика
-	Inn            string  `json:"inn"`             // ИНН
-	Price          *string `json:"price,omitempty"` // Цена (строкой, чтобы не терять копейки), nil если не установлена
-	Rank           *int32  `json:"rank,omitempty"`  // Место, nil если не установлено
-	Notes          *string `json:"notes,omitempty"`
+	ID             int64             `json:"id"` // ID записи победителя (для редактирования/удаления)
+	ProposalID     int64             `json:"proposal_id"`
+	ContractorName string            `json:"contractor_name"`         // Название подрядчика
+	Inn            string            `json:"inn"`                     // ИНН
+	Price          *api_models.Money `json:"price,omitempty"`         // Цена контракта "1234567.89", nil если не установлена
+	PriceDisplay   *string           `json:"price_display,omitempty"` // Та же цена для отображения: "1 234 567,89 ₽"
+	Rank           *int32            `json:"rank,omitempty"`          // Место, nil если не установлено
+	Notes          *string           `json:"notes,omitempty"`
 }
 
 type LotResponse struct {
-	ID            int64              `json:"id"`
-	LotKey        string             `json:"lot_key"`
-	LotTitle      string             `json:"lot_title"`
-	TenderID      int64              `json:"tender_id"`
-	KeyParameters json.RawMessage    `json:"key_parameters"`
-	CreatedAt     string             `json:"created_at"`
-	UpdatedAt     string             `json:"updated_at"`
-	Proposals     []ProposalResponse `json:"proposals"`
-	Winners       []WinnerResponse   `json:"winners"`
+	ID            int64                 `json:"id"`
+	LotKey        string                `json:"lot_key"`
+	LotTitle      string                `json:"lot_title"`
+	TenderID      int64                 `json:"tender_id"`
+	KeyParameters json.RawMessage       `json:"key_parameters"`
+	CreatedAt     string                `json:"created_at"`
+	UpdatedAt     string                `json:"updated_at"`
+	Proposals     []ProposalResponse    `json:"proposals"`
+	Winners       []WinnerResponse      `json:"winners"`
+	Totals        *api_models.LotTotals `json:"totals,omitempty"` // Итоги лота; nil, если расчёт не удался
 }
 
 // getTenderDetailsHandler возвращает детальную информацию о тендере с его лотами и победителями.
@@ -134,6 +197,8 @@ type LotResponse struct {
 //   - Основная информация о тендере
 //   - Список лотов тендера с пагинацией
 //   - Победители по каждому лоту (опционально, graceful degradation)
+//   - Итоги по каждому лоту: baseline, лучшее предложение, число предложений,
+//     экономия (опционально, graceful degradation)
 //
 // HTTP метод: GET
 // Путь: /api/v1/tenders/:id
@@ -144,11 +209,14 @@ type LotResponse struct {
 // Query параметры:
 //   - limit (int): Количество лотов на странице (по умолчанию 100, диапазон: 1-100)
 //   - offset (int): Смещение для пагинации (по умолчанию 0, минимум: 0)
+//   - include_deleted (bool): Вернуть тендер, даже если он мягко удалён (только admin)
 //
 // Ответы:
-//   - 200: Успешное получение данных (TenderDetailsResponse)
+//   - 200: Успешное получение данных (TenderDetailsResponse) с заголовками ETag и Cache-Control
+//   - 304: Данные не изменились с версии из If-None-Match
 //   - 400: Неверный формат ID или параметров запроса
-//   - 404: Тендер не найден
+//   - 403: include_deleted=true без роли admin
+//   - 404: Тендер не найден (или удалён)
 //   - 500: Внутренняя ошибка сервера
 //
 // Примечания:
@@ -158,7 +226,7 @@ type LotResponse struct {
 func (s *Server) getTenderDetailsHandler(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный формат ID тендера")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_tender_id_format"))
 		return
 	}
 
@@ -167,7 +235,7 @@ func (s *Server) getTenderDetailsHandler(c *gin.Context) {
 		Offset int `form:"offset"`
 	}
 	if err := c.ShouldBindQuery(&queryParams); err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(err))
+		respondBindError(c, err)
 		return
 	}
 
@@ -177,11 +245,31 @@ func (s *Server) getTenderDetailsHandler(c *gin.Context) {
 	}
 	// Валидация границ параметров
 	if queryParams.Limit < 1 || queryParams.Limit > 100 {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("параметр limit должен быть от 1 до 100")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.limit_range_100"))
 		return
 	}
 	if queryParams.Offset < 0 {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("параметр offset должен быть >= 0")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.offset_gte_zero"))
+		return
+	}
+	includeDeleted, err := parseIncludeDeleted(c.Request.URL.Query())
+	if err != nil {
+		respondStatus(c, http.StatusBadRequest, err)
+		return
+	}
+	if !includeDeletedAllowed(c, includeDeleted) {
+		return
+	}
+
+	cacheControl := s.config.HTTPCache.TenderDetails
+	etag, notModified := s.checkNotModified(c, "tender_details", id, cacheControl, func(ctx context.Context) (cacheVersion, error) {
+		row, err := s.store.GetTenderCacheVersion(ctx, db.GetTenderCacheVersionParams{
+			TenderID:       id,
+			IncludeDeleted: includeDeleted,
+		})
+		return cacheVersion{UpdatedAt: row.VersionAt, RowsCount: row.RowsCount}, err
+	})
+	if notModified {
 		return
 	}
 
@@ -189,6 +277,7 @@ func (s *Server) getTenderDetailsHandler(c *gin.Context) {
 		tenderDetails db.GetTenderDetailsRow
 		lots          []db.Lot
 		proposalsRaw  []db.GetProposalsByLotIDsRow
+		lotTotals     map[int64]api_models.LotTotals
 	)
 
 	g, ctx := errgroup.WithContext(c.Request.Context())
@@ -196,7 +285,10 @@ func (s *Server) getTenderDetailsHandler(c *gin.Context) {
 	// 1. Детали тендера
 	g.Go(func() error {
 		var err error
-		tenderDetails, err = s.store.GetTenderDetails(ctx, id)
+		tenderDetails, err = s.store.GetTenderDetails(ctx, db.GetTenderDetailsParams{
+			ID:             id,
+			IncludeDeleted: includeDeleted,
+		})
 		if err != nil {
 			if err == sql.ErrNoRows {
 				return fmt.Errorf("тендер с ID '%d' не найден: %w", id, err)
@@ -223,9 +315,9 @@ func (s *Server) getTenderDetailsHandler(c *gin.Context) {
 
 	if err := g.Wait(); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			c.JSON(http.StatusNotFound, errorResponse(err))
+			respondStatus(c, http.StatusNotFound, err)
 		} else {
-			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			respondError(c, err)
 		}
 		return
 	}
@@ -244,6 +336,12 @@ func (s *Server) getTenderDetailsHandler(c *gin.Context) {
 			// Логируем ошибку, но не валим весь запрос - предложений может не быть
 			s.logger.Warnf("не удалось получить предложения для лотов %v: %v", lotIDs, err)
 		}
+
+		// Итоги лотов одним запросом вместо отдельного вызова аналитики на каждый лот
+		lotTotals, err = s.analytics.GetLotTotals(c.Request.Context(), lotIDs)
+		if err != nil {
+			s.logger.Warnf("не удалось посчитать итоги лотов %v: %v", lotIDs, err)
+		}
 	}
 
 	// 4. Агрегация: Группируем предложения и победителей по лотам (in-memory)
@@ -269,11 +367,6 @@ func (s *Server) getTenderDetailsHandler(c *gin.Context) {
 		}
 
 		// Создаем ProposalResponse
-		var totalCostPtr *string
-		if row.TotalCost.Valid {
-			totalCostPtr = &row.TotalCost.String
-		}
-
 		isWinner := false
 		if row.IsWinner != nil {
 			if v, ok := row.IsWinner.(bool); ok {
@@ -298,7 +391,8 @@ func (s *Server) getTenderDetailsHandler(c *gin.Context) {
 			ContractorName: row.ContractorName,
 			ContractorInn:  row.ContractorInn,
 			IsBaseline:     row.IsBaseline,
-			TotalCost:      totalCostPtr,
+			TotalCost:      api_models.MoneyFromNullString(row.TotalCost),
+			Currency:       row.Currency,
 			IsWinner:       isWinner,
 			AdditionalInfo: additionalInfo,
 		}
@@ -310,9 +404,14 @@ func (s *Server) getTenderDetailsHandler(c *gin.Context) {
 
 		// Если это предложение-победитель, создаем WinnerResponse
 		if isWinner && row.WinnerID.Valid {
-			var pricePtr *string
-			if row.TotalCost.Valid {
-				pricePtr = &row.TotalCost.String
+			var priceDisplayPtr *string
+			pricePtr := api_models.MoneyFromNullString(row.WinnerAwardPrice)
+			if row.WinnerAwardPrice.Valid {
+				if display, err := util.FormatRubles(row.WinnerAwardPrice.String); err == nil {
+					priceDisplayPtr = &display
+				} else {
+					s.logger.Warnf("не удалось отформатировать цену победителя %d: %v", row.WinnerID.Int64, err)
+				}
 			}
 
 			var rankPtr *int32
@@ -331,6 +430,7 @@ func (s *Server) getTenderDetailsHandler(c *gin.Context) {
 				ContractorName: row.ContractorName,
 				Inn:            row.ContractorInn,
 				Price:          pricePtr,
+				PriceDisplay:   priceDisplayPtr,
 				Rank:           rankPtr,
 				Notes:          notesPtr,
 			}
@@ -351,6 +451,9 @@ func (s *Server) getTenderDetailsHandler(c *gin.Context) {
 			lr.Proposals = bucket.Proposals
 			lr.Winners = bucket.Winners
 		}
+		if totals, ok := lotTotals[lot.ID]; ok {
+			lr.Totals = &totals
+		}
 
 		lotResponses[i] = lr
 	}
@@ -360,6 +463,7 @@ func (s *Server) getTenderDetailsHandler(c *gin.Context) {
 		Lots:    lotResponses,
 	}
 
+	setCacheHeaders(c, etag, cacheControl)
 	c.JSON(http.StatusOK, response)
 }
 
@@ -367,6 +471,7 @@ func (s *Server) getTenderDetailsHandler(c *gin.Context) {
 type patchTenderRequest struct {
 	CategoryID *int64  `json:"category_id" binding:"omitempty,gte=1"`
 	Title      *string `json:"title" binding:"omitempty,min=3,max=255"`
+	Region     *string `json:"region" binding:"omitempty,max=16"` // Код региона для индексов цен
 	// В будущем сюда можно добавить любые другие поля, которые можно обновлять
 }
 
@@ -374,7 +479,7 @@ type patchTenderRequest struct {
 func (s *Server) patchTenderHandler(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный ID тендера")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_tender_id"))
 		return
 	}
 
@@ -382,7 +487,7 @@ func (s *Server) patchTenderHandler(c *gin.Context) {
 	var req patchTenderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		s.logger.Warnf("invalid patchTender input: %v", err)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid input: %v", err)))
+		respondBindError(c, err)
 		return
 	}
 
@@ -403,43 +508,180 @@ func (s *Server) patchTenderHandler(c *gin.Context) {
 		params.Title = sql.NullString{String: *req.Title, Valid: true}
 	}
 
+	if req.Region != nil {
+		region, err := priceindex.NormalizeRegion(*req.Region)
+		if err != nil {
+			respondStatus(c, http.StatusBadRequest, err)
+			return
+		}
+		params.Region = sql.NullString{String: region, Valid: true}
+	}
+
 	// ... в будущем здесь можно добавить проверки для других полей ...
 
-	// Шаг D: Вызываем универсальную функцию обновления с правильно подготовленными параметрами.
-	tender, err := s.store.UpdateTenderDetails(c.Request.Context(), params)
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	// Шаг D: Обновляем через сервис: он же пишет изменённые поля в историю правок.
+	// Удалённый тендер не обновляется: для клиента он не существует (404).
+	tender, err := s.tenderArchive.UpdateTender(c.Request.Context(), params, userID)
 	if err != nil {
 		s.logger.Errorf("ошибка частичного обновления тендера: %v", err)
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, tender)
 }
 
+// deleteTenderHandler - DELETE /api/v1/tenders/:id.
+// Мягкое удаление: тендер помечается deleted_at и пропадает из списков и деталей,
+// лоты и предложения остаются в БД (см. пакет services/tender).
+func (s *Server) deleteTenderHandler(c *gin.Context) {
+	s.changeTenderArchiveStatus(c, "deleteTenderHandler", s.tenderArchive.SoftDeleteTender)
+}
+
+// restoreTenderHandler - POST /api/v1/tenders/:id/restore (только admin).
+func (s *Server) restoreTenderHandler(c *gin.Context) {
+	s.changeTenderArchiveStatus(c, "restoreTenderHandler", s.tenderArchive.RestoreTender)
+}
+
+// changeTenderArchiveStatus - общая часть удаления и восстановления тендера.
+func (s *Server) changeTenderArchiveStatus(
+	c *gin.Context,
+	handlerName string,
+	action func(ctx context.Context, tenderID int64, actor string) (*api_models.TenderArchiveStatusResponse, error),
+) {
+	logger := s.logger.WithField("handler", handlerName)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_tender_id"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		logger.Errorf("user_id отсутствует в контексте (middleware не установил)")
+		respondStatus(c, http.StatusUnauthorized, i18n.Errorf("auth.not_authenticated"))
+		return
+	}
+	uid, ok := userID.(int64)
+	if !ok {
+		logger.Errorf("user_id имеет неожиданный тип: %T", userID)
+		respondInternal(c, fmt.Errorf("invalid user_id type"))
+		return
+	}
+
+	resp, err := action(c.Request.Context(), id, strconv.FormatInt(uid, 10))
+	if err != nil {
+		var notFoundErr *apierrors.NotFoundError
+		var conflictErr *apierrors.ConflictError
+		if !errors.As(err, &notFoundErr) && !errors.As(err, &conflictErr) {
+			logger.Errorf("Ошибка изменения статуса удаления тендера %d: %v", id, err)
+		}
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// purgeTenderHandler - POST /api/v1/admin/tenders/:id/purge.
+// Безвозвратно удаляет тендер со всеми лотами, предложениями, позициями и
+// историей импорта в одной транзакции (см. пакет services/tender).
+//
+// Query:
+//   - dry_run (bool, default false): только посчитать строки по таблицам
+func (s *Server) purgeTenderHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "purgeTenderHandler")
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_tender_id"))
+		return
+	}
+
+	dryRun := false
+	if v := c.Query("dry_run"); v != "" {
+		dryRun, err = strconv.ParseBool(v)
+		if err != nil {
+			respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.dry_run_bool"))
+			return
+		}
+	}
+
+	userID, _ := c.Get("user_id")
+	uid, ok := userID.(int64)
+	if !ok {
+		logger.Errorf("user_id отсутствует в контексте или имеет неожиданный тип: %T", userID)
+		respondStatus(c, http.StatusUnauthorized, i18n.Errorf("auth.not_authenticated"))
+		return
+	}
+
+	resp, err := s.tenderArchive.PurgeTender(c.Request.Context(), id, dryRun, strconv.FormatInt(uid, 10))
+	if err != nil {
+		var notFoundErr *apierrors.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			respondStatus(c, http.StatusNotFound, err)
+			return
+		}
+		logger.Errorf("Ошибка удаления тендера %d (dry_run=%t): %v", id, dryRun, err)
+		respondInternal(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // --- CRUD Победителей ---
 
 type createWinnerRequest struct {
 	ProposalID int64  `json:"proposal_id" binding:"required"`
 	Rank       int32  `json:"rank" binding:"required,gte=1"`
 	Notes      string `json:"notes" binding:"omitempty,max=2000"`
+	// AwardPrice - цена контракта в рублях с копейками ("1234567.89").
+	// Не указана — берётся итог предложения с НДС.
+	AwardPrice *string `json:"award_price"`
 }
 
 type updateWinnerRequest struct {
-	Rank  *int32  `json:"rank"`
-	Notes *string `json:"notes" binding:"omitempty,max=2000"`
+	Rank       *int32  `json:"rank"`
+	Notes      *string `json:"notes" binding:"omitempty,max=2000"`
+	AwardPrice *string `json:"award_price"`
+}
+
+// parseAwardPrice проверяет цену победителя: не больше двух знаков после запятой.
+func parseAwardPrice(price *string) (sql.NullString, error) {
+	if price == nil {
+		return sql.NullString{}, nil
+	}
+	normalized, err := util.ParseRubles(*price)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("award_price: %w", err)
+	}
+	return sql.NullString{String: normalized, Valid: true}, nil
 }
 
 // POST /api/v1/lots/:lotId/winners
 func (s *Server) createWinnerHandler(c *gin.Context) {
 	lotID, err := strconv.ParseInt(c.Param("lotId"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный ID лота")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_lot_id"))
 		return
 	}
 
 	var req createWinnerRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(err))
+		respondBindError(c, err)
+		return
+	}
+
+	awardPrice, err := parseAwardPrice(req.AwardPrice)
+	if err != nil {
+		respondStatus(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -450,11 +692,11 @@ func (s *Server) createWinnerHandler(c *gin.Context) {
 	}
 	isValid, err := s.store.CheckProposalBelongsToLot(c.Request.Context(), paramsCheck)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 	if !isValid {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("proposal_id %d не принадлежит лоту %d", req.ProposalID, lotID)))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("tender.proposal_not_in_lot", req.ProposalID, lotID))
 		return
 	}
 
@@ -463,20 +705,33 @@ func (s *Server) createWinnerHandler(c *gin.Context) {
 		ProposalID: req.ProposalID,
 		Rank:       sql.NullInt32{Int32: req.Rank, Valid: true},
 		Notes:      sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+		AwardPrice: awardPrice,
 	}
 
 	winner, err := s.store.CreateWinner(c.Request.Context(), createParams)
 	if err != nil {
 		// Проверяем, является ли ошибка (или обернутая ошибка) PostgreSQL ошибкой нарушения уникальности
-		var pqErr *pq.Error
-		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
-			c.JSON(http.StatusConflict, errorResponse(fmt.Errorf("это предложение уже является победителем")))
+		if postgres.IsUniqueViolation(err) {
+			respondStatus(c, http.StatusConflict, i18n.Errorf("tender.already_winner"))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
+	data := webhooks.WinnerSetData{
+		WinnerID:   winner.ID,
+		LotID:      lotID,
+		ProposalID: req.ProposalID,
+		Rank:       req.Rank,
+	}
+	if winner.AwardPrice.Valid {
+		data.AwardPrice = &winner.AwardPrice.String
+	}
+	logger := s.logger.WithField("handler", "createWinnerHandler")
+	s.publishWebhook(c, logger, webhooks.EventWinnerSet, data)
+	events.Emit(c.Request.Context(), s.events, logger, events.TypeWinnerCreated, lotID, events.WinnerCreatedData(data))
+
 	c.JSON(http.StatusCreated, winner)
 }
 
@@ -484,19 +739,19 @@ func (s *Server) createWinnerHandler(c *gin.Context) {
 func (s *Server) updateWinnerHandler(c *gin.Context) {
 	winnerID, err := strconv.ParseInt(c.Param("winnerId"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный ID победителя")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_winner_id"))
 		return
 	}
 
 	var req updateWinnerRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(err))
+		respondBindError(c, err)
 		return
 	}
 
 	// Требуем хотя бы одно поле для обновления
-	if req.Rank == nil && req.Notes == nil {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("необходимо указать хотя бы одно поле для обновления")))
+	if req.Rank == nil && req.Notes == nil && req.AwardPrice == nil {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.no_fields_to_update"))
 		return
 	}
 
@@ -506,7 +761,7 @@ func (s *Server) updateWinnerHandler(c *gin.Context) {
 	// Заполняем только переданные поля
 	if req.Rank != nil {
 		if *req.Rank < 1 {
-			c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("rank должен быть >= 1")))
+			respondStatus(c, http.StatusBadRequest, i18n.Errorf("tender.rank_positive"))
 			return
 		}
 		params.Rank = sql.NullInt32{Int32: *req.Rank, Valid: true}
@@ -515,17 +770,23 @@ func (s *Server) updateWinnerHandler(c *gin.Context) {
 		// Пустая строка сохраняется как NULL для консистентности с createWinnerHandler
 		params.Notes = sql.NullString{String: *req.Notes, Valid: *req.Notes != ""}
 	}
+	if params.AwardPrice, err = parseAwardPrice(req.AwardPrice); err != nil {
+		respondStatus(c, http.StatusBadRequest, err)
+		return
+	}
 
 	updatedWinner, err := s.store.UpdateWinnerDetails(c.Request.Context(), params)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("победитель не найден")))
+			respondStatus(c, http.StatusNotFound, i18n.Errorf("tender.winner_not_found"))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
+	s.emitWinnerChanged(c, s.logger.WithField("handler", "updateWinnerHandler"), events.TypeWinnerUpdated, updatedWinner)
+
 	c.JSON(http.StatusOK, updatedWinner)
 }
 
@@ -533,7 +794,7 @@ func (s *Server) updateWinnerHandler(c *gin.Context) {
 func (s *Server) deleteWinnerHandler(c *gin.Context) {
 	winnerID, err := strconv.ParseInt(c.Param("winnerId"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный ID победителя")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_winner_id"))
 		return
 	}
 
@@ -541,12 +802,29 @@ func (s *Server) deleteWinnerHandler(c *gin.Context) {
 	deletedWinner, err := s.store.DeleteWinnerByID(c.Request.Context(), winnerID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("победитель не найден")))
+			respondStatus(c, http.StatusNotFound, i18n.Errorf("tender.winner_not_found"))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
+	s.emitWinnerChanged(c, s.logger.WithField("handler", "deleteWinnerHandler"), events.TypeWinnerDeleted, deletedWinner)
+
 	c.JSON(http.StatusOK, deletedWinner)
 }
+
+// emitWinnerChanged публикует winner.updated / winner.deleted. Изменение уже
+// сохранено, поэтому ошибка поиска лота только логируется.
+func (s *Server) emitWinnerChanged(c *gin.Context, logger logging.Logger, eventType string, winner db.Winner) {
+	proposal, err := s.store.GetProposalByID(c.Request.Context(), winner.ProposalID)
+	if err != nil {
+		logger.Errorf("Не удалось опубликовать %s: ошибка GetProposalByID(%d): %v", eventType, winner.ProposalID, err)
+		return
+	}
+	events.Emit(c.Request.Context(), s.events, logger, eventType, proposal.LotID, events.WinnerChangedData{
+		WinnerID:   winner.ID,
+		LotID:      proposal.LotID,
+		ProposalID: winner.ProposalID,
+	})
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/tender"
+)
+
+// listTenderDuplicatesHandler обрабатывает GET /api/v1/admin/tender-duplicates.
+//
+// Query:    status (pending | linked | ignored; пусто — все), limit (50), offset
+// Response: 200 + TenderDuplicatesResponse, ожидающие проверки первыми
+// Errors:   400 (status, limit, offset), 500 (БД)
+func (s *Server) listTenderDuplicatesHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "listTenderDuplicatesHandler")
+
+	limit64, err := strconv.ParseInt(c.DefaultQuery("limit", "50"), 10, 32)
+	if err != nil || limit64 <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.limit_positive"))
+		return
+	}
+	offset64, err := strconv.ParseInt(c.DefaultQuery("offset", "0"), 10, 32)
+	if err != nil || offset64 < 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.offset_non_negative"))
+		return
+	}
+
+	resp, err := s.tenderArchive.ListDuplicates(c.Request.Context(), c.Query("status"), int32(limit64), int32(offset64))
+	if err != nil {
+		logger.Errorf("Ошибка ListDuplicates: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// linkTenderDuplicateHandler обрабатывает POST /api/v1/admin/tender-duplicates/:id/link.
+func (s *Server) linkTenderDuplicateHandler(c *gin.Context) {
+	s.resolveTenderDuplicate(c, "linkTenderDuplicateHandler", tender.DuplicateLinked)
+}
+
+// ignoreTenderDuplicateHandler обрабатывает POST /api/v1/admin/tender-duplicates/:id/ignore.
+func (s *Server) ignoreTenderDuplicateHandler(c *gin.Context) {
+	s.resolveTenderDuplicate(c, "ignoreTenderDuplicateHandler", tender.DuplicateIgnored)
+}
+
+// resolveTenderDuplicate — общая часть link/ignore.
+//
+// Response: 200 + TenderDuplicateStatusResponse
+// Errors:   400 (ID), 404 (пары нет), 409 (решение уже принято), 500 (БД)
+func (s *Server) resolveTenderDuplicate(c *gin.Context, handler, status string) {
+	logger := s.logger.WithField("handler", handler)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_duplicate_id_format"))
+		return
+	}
+
+	actorID, ok := s.adminActorID(c, logger)
+	if !ok {
+		return
+	}
+
+	resp, err := s.tenderArchive.ResolveDuplicate(c.Request.Context(), id, status, actorID)
+	if err != nil {
+		logger.Errorf("Ошибка ResolveDuplicate(id=%d, %s): %v", id, status, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// listLinkedTendersHandler обрабатывает GET /api/v1/tenders/:id/linked —
+// тендеры, которые администратор подтвердил как этот же тендер под другим etp_id.
+//
+// Response: 200 + LinkedTendersResponse
+// Errors:   400, 404 (тендера нет или он другой организации), 500
+func (s *Server) listLinkedTendersHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "listLinkedTendersHandler")
+
+	tenderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || tenderID <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_tender_id_format"))
+		return
+	}
+
+	resp, err := s.tenderArchive.LinkedTenders(c.Request.Context(), tenderID)
+	if err != nil {
+		logger.Errorf("Ошибка LinkedTenders(tender_id=%d): %v", tenderID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -1,12 +1,13 @@
 package server
 
 import (
-	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/refcache"
 )
 
 // listTenderTypesHandler - обработчик для получения списка типов тендеров с пагинацией.
@@ -17,13 +18,17 @@ func (s *Server) listTenderTypesHandler(c *gin.Context) {
 
 	pageID, err := strconv.ParseInt(pageIDStr, 10, 32)
 	if err != nil || pageID < 1 {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный параметр page")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_page"))
 		return
 	}
 
 	pageSize, err := strconv.ParseInt(pageSizeStr, 10, 32)
 	if err != nil || pageSize < 1 || pageSize > 100 {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный параметр page_size (допустимо от 1 до 100)")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_page_size_100"))
+		return
+	}
+
+	if s.serveReferenceFromCache(c, refcache.GroupTenderTypes) {
 		return
 	}
 
@@ -37,7 +42,7 @@ func (s *Server) listTenderTypesHandler(c *gin.Context) {
 	tenderTypes, err := s.store.ListTenderTypes(c.Request.Context(), params)
 	if err != nil {
 		s.logger.Errorf("ошибка получения списка типов тендеров: %v", err)
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
@@ -47,7 +52,7 @@ func (s *Server) listTenderTypesHandler(c *gin.Context) {
 		tenderTypes = make([]db.TenderType, 0)
 	}
 
-	c.JSON(http.StatusOK, tenderTypes)
+	s.respondReference(c, refcache.GroupTenderTypes, tenderTypes)
 }
 
 // createTenderTypeRequest определяет структуру входящего JSON при создании типа
@@ -61,7 +66,7 @@ func (s *Server) createTenderTypeHandler(c *gin.Context) {
 
 	// Парсим и валидируем входящий JSON
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(err))
+		respondBindError(c, err)
 		return
 	}
 
@@ -73,10 +78,12 @@ func (s *Server) createTenderTypeHandler(c *gin.Context) {
 
 	if err != nil {
 		s.logger.Errorf("ошибка при создании/обновлении типа тендера: %v", err)
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
+	s.refCache.Invalidate(c.Request.Context(), refcache.Dependents(refcache.GroupTenderTypes)...)
+
 	// Возвращаем созданный/найденный объект со статусом 201 Created
 	c.JSON(http.StatusCreated, tenderType)
 }
@@ -91,13 +98,13 @@ func (s *Server) updateTenderTypeHandler(c *gin.Context) {
 	// Получаем ID из URL
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный ID")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_id"))
 		return
 	}
 
 	var req updateTenderTypeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(err))
+		respondBindError(c, err)
 		return
 	}
 
@@ -109,10 +116,12 @@ func (s *Server) updateTenderTypeHandler(c *gin.Context) {
 	updatedType, err := s.store.UpdateTenderType(c.Request.Context(), params)
 	if err != nil {
 		s.logger.Errorf("ошибка обновления типа тендера: %v", err)
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
+	s.refCache.Invalidate(c.Request.Context(), refcache.Dependents(refcache.GroupTenderTypes)...)
+
 	c.JSON(http.StatusOK, updatedType)
 }
 
@@ -120,17 +129,19 @@ func (s *Server) updateTenderTypeHandler(c *gin.Context) {
 func (s *Server) deleteTenderTypeHandler(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("неверный ID")))
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_id"))
 		return
 	}
 
 	err = s.store.DeleteTenderType(c.Request.Context(), id)
 	if err != nil {
 		s.logger.Errorf("ошибка удаления типа тендера: %v", err)
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondError(c, err)
 		return
 	}
 
+	s.refCache.Invalidate(c.Request.Context(), refcache.Dependents(refcache.GroupTenderTypes)...)
+
 	// При успешном удалении возвращаем статус 204 No Content
 	c.Status(http.StatusNoContent)
 }
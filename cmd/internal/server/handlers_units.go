@@ -0,0 +1,148 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/refcache"
+)
+
+// listUnitsHandler обрабатывает GET /api/v1/admin/units.
+// Возвращает справочник единиц измерения с синонимами и числом ссылок на каждую.
+// Ответ кэшируется (ref_cache.units_ttl), поэтому счётчики ссылок могут отставать.
+func (s *Server) listUnitsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "listUnitsHandler")
+
+	if s.serveReferenceFromCache(c, refcache.GroupUnits) {
+		return
+	}
+
+	response, err := s.units.ListUnits(c.Request.Context())
+	if err != nil {
+		logger.Errorf("Ошибка ListUnits: %v", err)
+		respondErrorCode(c, err, "unit_conflict")
+		return
+	}
+
+	s.respondReference(c, refcache.GroupUnits, response)
+}
+
+// createUnitHandler обрабатывает POST /api/v1/admin/units.
+// Создаёт каноническую единицу измерения вместе с синонимами.
+func (s *Server) createUnitHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "createUnitHandler")
+
+	var req api_models.CreateUnitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	response, err := s.units.CreateUnit(c.Request.Context(), req)
+	if err != nil {
+		logger.Errorf("Ошибка CreateUnit(name=%q): %v", req.Name, err)
+		respondErrorCode(c, err, "unit_conflict")
+		return
+	}
+	s.refCache.Invalidate(c.Request.Context(), refcache.GroupUnits)
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// addUnitAliasHandler обрабатывает POST /api/v1/admin/units/:id/aliases.
+func (s *Server) addUnitAliasHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "addUnitAliasHandler")
+
+	unitID, ok := parseUnitParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req api_models.CreateUnitAliasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	response, err := s.units.AddAlias(c.Request.Context(), unitID, req.Alias)
+	if err != nil {
+		logger.Errorf("Ошибка AddAlias(unit=%d, alias=%q): %v", unitID, req.Alias, err)
+		respondErrorCode(c, err, "unit_conflict")
+		return
+	}
+	s.refCache.Invalidate(c.Request.Context(), refcache.GroupUnits)
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// deleteUnitAliasHandler обрабатывает DELETE /api/v1/admin/units/:id/aliases/:aliasId.
+func (s *Server) deleteUnitAliasHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "deleteUnitAliasHandler")
+
+	unitID, ok := parseUnitParam(c, "id")
+	if !ok {
+		return
+	}
+	aliasID, ok := parseUnitParam(c, "aliasId")
+	if !ok {
+		return
+	}
+
+	if err := s.units.DeleteAlias(c.Request.Context(), unitID, aliasID); err != nil {
+		logger.Errorf("Ошибка DeleteAlias(unit=%d, alias=%d): %v", unitID, aliasID, err)
+		respondErrorCode(c, err, "unit_conflict")
+		return
+	}
+	s.refCache.Invalidate(c.Request.Context(), refcache.GroupUnits)
+
+	c.Status(http.StatusNoContent)
+}
+
+// mergeUnitsHandler обрабатывает POST /api/v1/admin/units/merge.
+// Переносит позиции КП и каталога duplicate_id на master_id, удаляет дубликат
+// и делает его имя синонимом основной единицы.
+func (s *Server) mergeUnitsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "mergeUnitsHandler")
+
+	var req api_models.MergeUnitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		logger.Errorf("user_id отсутствует в контексте")
+		respondStatus(c, http.StatusUnauthorized, i18n.Errorf("auth.not_authenticated"))
+		return
+	}
+	uid, ok := userID.(int64)
+	if !ok {
+		logger.Errorf("user_id имеет неожиданный тип: %T", userID)
+		respondInternal(c, fmt.Errorf("invalid user_id type"))
+		return
+	}
+
+	response, err := s.units.MergeUnits(c.Request.Context(), req, uid)
+	if err != nil {
+		logger.Errorf("Ошибка MergeUnits(master=%d, duplicate=%d): %v", req.MasterID, req.DuplicateID, err)
+		respondErrorCode(c, err, "unit_conflict")
+		return
+	}
+	s.refCache.Invalidate(c.Request.Context(), refcache.GroupUnits)
+
+	c.JSON(http.StatusOK, response)
+}
+
+func parseUnitParam(c *gin.Context, name string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil || id <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.param_positive_int", name))
+		return 0, false
+	}
+	return id, true
+}
@@ -1,126 +1,363 @@
 package server
 
 import (
-	"bytes"
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/outbound"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/parsetask"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
 )
 
-func (s *Server) ProxyUploadHandler(c *gin.Context) {
-	// Получаем файл из формы
-	sourceFile, sourceHeader, err := c.Request.FormFile("file")
-	if err != nil {
-		s.logger.Errorf("ошибка получения файла из формы: %v", err)
-		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("файл 'file' не предоставлен")))
-		return
-	}
-	defer sourceFile.Close()
-
-	// Получаем параметр enable_ai (по умолчанию false, как в Python)
-	enableAI := c.DefaultPostForm("enable_ai", "false")
-
-	// Создаем новый multipart writer для прокси запроса
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+// parserResponseLimit — предел ответа парсера на загрузку и запрос статуса:
+// ответы небольшие JSON, больший ответ обрезается.
+const parserResponseLimit = 1 << 20
 
-	// Добавляем файл (меняем имя поля с tenderFile на file для Python)
-	part, err := writer.CreateFormFile("file", sourceHeader.Filename)
-	if err != nil {
-		s.logger.Errorf("ошибка создания form-file для прокси: %v", err)
-		c.JSON(http.StatusInternalServerError, errorResponse(fmt.Errorf("внутренняя ошибка сервера")))
-		return
-	}
+// ProxyUploadHandler обрабатывает POST /api/v1/upload-tender.
+//
+// Файл из формы (поле file) передаётся парсеру потоком через io.Pipe и
+// целиком в памяти не собирается. До обращения к парсеру проверяются
+// расширение .xlsx, Content-Type из upload.allowed_content_types и сигнатура
+// ZIP; размер ограничен upload.max_file_size. Поля enable_ai и
+// organization_id (организация пользователя) дописываются после файла.
+//
+// Ответ парсера передаётся клиенту без изменений. Если парсер вернул
+// task_id, задача записывается в parse_tasks (см. GetTaskStatusHandler).
+//
+// Errors: 400 (нет файла, некорректная форма), 413 (файл больше лимита),
+// 415 (не xlsx), 502 (парсер недоступен), 503 (circuit breaker разомкнут).
+func (s *Server) ProxyUploadHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "ProxyUploadHandler")
+	cfg := s.config.Upload
 
-	if _, err = io.Copy(part, sourceFile); err != nil {
-		s.logger.Errorf("ошибка копирования файла для прокси: %v", err)
-		c.JSON(http.StatusInternalServerError, errorResponse(fmt.Errorf("внутренняя ошибка сервера")))
+	// Заявленный размер проверяем сразу, фактический — при чтении
+	if c.Request.ContentLength > cfg.MaxFileSize+uploadFormOverhead {
+		s.uploadTooLarge(c, logger)
 		return
 	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, cfg.MaxFileSize+uploadFormOverhead)
 
-	// Добавляем параметр enable_ai
-	if err := writer.WriteField("enable_ai", enableAI); err != nil {
-		s.logger.Errorf("ошибка добавления поля enable_ai: %v", err)
-		c.JSON(http.StatusInternalServerError, errorResponse(fmt.Errorf("внутренняя ошибка сервера")))
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		logger.Errorf("ошибка чтения формы: %v", err)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("upload.multipart_expected"))
 		return
 	}
-
-	if err := writer.Close(); err != nil {
-		s.logger.Errorf("ошибка закрытия multipart writer: %v", err)
-		c.JSON(http.StatusInternalServerError, errorResponse(fmt.Errorf("внутренняя ошибка сервера")))
+	form, err := readUploadForm(reader, cfg.AllowedContentTypes)
+	if err != nil {
+		s.respondUploadError(c, logger, err)
 		return
 	}
 
-	// Отправляем запрос на Python сервис
-	pythonParserBaseUrl := s.config.Services.ParserService.URL
-	pythonParserUrl := fmt.Sprintf("%s/parse-tender/", pythonParserBaseUrl)
+	organizationID := c.GetInt64("organization_id")
+	pipeReader, pipeWriter := io.Pipe()
+	writer := multipart.NewWriter(pipeWriter)
+	streamDone := make(chan error, 1)
+	go func() {
+		err := form.writeTo(writer, cfg.MaxFileSize, organizationID)
+		pipeWriter.CloseWithError(err)
+		streamDone <- err
+	}()
 
-	// Создаем контекст с таймаутом 10 минут
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Minute)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), cfg.ParserTimeout)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, pythonParserUrl, body)
+	parserURL := fmt.Sprintf("%s/parse-tender/", s.config.Services.ParserService.URL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, parserURL, pipeReader)
 	if err != nil {
-		s.logger.Errorf("ошибка создания HTTP-запроса для прокси: %v", err)
-		c.JSON(http.StatusInternalServerError, errorResponse(fmt.Errorf("внутренняя ошибка сервера")))
+		logger.Errorf("ошибка создания HTTP-запроса для прокси: %v", err)
+		pipeReader.CloseWithError(err)
+		respondInternal(c, err)
 		return
 	}
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 
-	// Логируем информацию о запросе
-	s.logger.Infof("Проксирование файла %s на Python сервис (enable_ai=%s, timeout=10min)", sourceHeader.Filename, enableAI)
+	logger.Infof("Проксирование файла %s на Python сервис (enable_ai=%s, organization_id=%d, timeout=%s)",
+		form.fileName, form.enableAI, organizationID, cfg.ParserTimeout)
+	started := time.Now()
 
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
-		s.logger.Errorf("сервис парсера недоступен: %v", err)
-		c.JSON(http.StatusBadGateway, errorResponse(fmt.Errorf("сервис обработки файлов временно недоступен")))
+		// Ошибка передачи формы (файл больше лимита, обрыв у клиента) важнее
+		// ошибки транспорта, которую она вызвала
+		pipeReader.CloseWithError(err)
+		if streamErr := waitUploadStream(ctx, streamDone); streamErr != nil && !errors.Is(streamErr, io.ErrClosedPipe) {
+			s.respondUploadError(c, logger, streamErr)
+			return
+		}
+		logger.Errorf("сервис парсера недоступен: %v", err)
+		respondParserUnavailable(c, err)
 		return
 	}
 	defer resp.Body.Close()
 
+	body, err := io.ReadAll(io.LimitReader(resp.Body, parserResponseLimit))
+	// Парсер ответил: непереданный остаток формы ему уже не нужен
+	pipeReader.Close()
+	waitUploadStream(ctx, streamDone)
+	if err != nil {
+		logger.Errorf("ошибка чтения ответа парсера: %v", err)
+		respondStatus(c, http.StatusBadGateway, i18n.Errorf("upload.parser_unavailable"))
+		return
+	}
+	logger.Infof("Файл %s (%d байт) передан парсеру за %s, статус ответа %d",
+		form.fileName, form.size, time.Since(started).Round(time.Millisecond), resp.StatusCode)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		s.recordUploadTask(c, logger, form, organizationID, body)
+	}
+
 	// Перенаправляем ответ от Python обратно клиенту
-	c.Status(resp.StatusCode)
 	for key, values := range resp.Header {
+		if key == "Content-Length" {
+			continue
+		}
 		for _, value := range values {
 			c.Writer.Header().Add(key, value)
 		}
 	}
-	io.Copy(c.Writer, resp.Body)
+	c.Status(resp.StatusCode)
+	c.Writer.Write(body)
 }
 
+// waitUploadStream ждёт окончания передачи формы парсеру. Запись в закрытый
+// pipe сразу завершается ошибкой, поэтому ожидание ограничено чтением
+// остатка формы у клиента (и таймаутом ctx).
+func waitUploadStream(ctx context.Context, done <-chan error) error {
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// recordUploadTask сохраняет задачу парсера из его ответа на загрузку.
+// Ошибка только логируется: файл уже принят парсером.
+func (s *Server) recordUploadTask(c *gin.Context, logger logging.Logger, form *uploadForm, organizationID int64, parserBody []byte) {
+	var parsed struct {
+		TaskID string `json:"task_id"`
+	}
+	if err := json.Unmarshal(parserBody, &parsed); err != nil || parsed.TaskID == "" {
+		logger.Warnf("Ответ парсера на загрузку %s не содержит task_id, задача не сохранена", form.fileName)
+		return
+	}
+
+	enableAI, _ := strconv.ParseBool(form.enableAI)
+	upload := parsetask.Upload{
+		TaskID:         parsed.TaskID,
+		OrganizationID: organizationID,
+		FileName:       form.fileName,
+		FileSize:       form.size,
+		EnableAI:       enableAI,
+	}
+	if userID, ok := c.Get("user_id"); ok {
+		if id, ok := userID.(int64); ok {
+			upload.UserID = sql.NullInt64{Int64: id, Valid: true}
+		}
+	}
+	if err := s.parseTasks.Record(c.Request.Context(), upload); err != nil {
+		logger.Errorf("Не удалось сохранить задачу загрузки %s: %v", parsed.TaskID, err)
+	}
+}
+
+// GetTaskStatusHandler обрабатывает GET /api/v1/tasks/:task_id/status.
+//
+// Статус запрашивается у парсера; ответ передаётся клиенту без изменений и
+// запоминается в parse_tasks. Если парсер задачу не знает (404, например
+// после перезапуска) или недоступен, а задача есть в parse_tasks, отвечает
+// 200 + ParseTask из записи. Итоговый статус, о котором парсер сообщил сам
+// (finished_at заполнен), отдаётся из записи без обращения к парсеру.
+// Задача чужой организации — 404.
 func (s *Server) GetTaskStatusHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "GetTaskStatusHandler")
 	taskID := c.Param("task_id")
-	pythonParserBaseUrl := s.config.Services.ParserService.URL
-	pythonStatusURL := fmt.Sprintf("%s/tasks/%s/status", pythonParserBaseUrl, taskID)
 
-	// Добавляем контекст с коротким таймаутом для статуса
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	task, found, err := s.parseTasks.Lookup(c.Request.Context(), taskID, requestOrganizationScope(c))
+	if err != nil {
+		var notFoundErr *apierrors.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			respondError(c, err)
+			return
+		}
+		// Ошибка БД не мешает запросить статус у парсера
+		logger.Errorf("Ошибка Lookup(%s): %v", taskID, err)
+	}
+	if found && task.FinishedAt.Valid {
+		c.JSON(http.StatusOK, parsetask.ToResponse(task, true))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), s.config.Upload.StatusTimeout)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, "GET", pythonStatusURL, nil)
+	statusURL := fmt.Sprintf("%s/tasks/%s/status", s.config.Services.ParserService.URL, url.PathEscape(taskID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, statusURL, nil)
 	if err != nil {
 		s.logger.Errorf("ошибка создания HTTP-запроса для статуса: %v", err)
-		c.JSON(http.StatusInternalServerError, errorResponse(fmt.Errorf("внутренняя ошибка сервера")))
+		respondInternal(c, err)
 		return
 	}
 
-	// Используем созданный при старте сервера httpClient
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
-		c.JSON(http.StatusBadGateway, errorResponse(fmt.Errorf("сервис обработки файлов временно недоступен")))
+		if found {
+			logger.Warnf("Парсер недоступен (%v), статус задачи %s отдан из parse_tasks", err, taskID)
+			c.JSON(http.StatusOK, parsetask.ToResponse(task, true))
+			return
+		}
+		logger.Errorf("сервис парсера недоступен: %v", err)
+		respondParserUnavailable(c, err)
 		return
 	}
 	defer resp.Body.Close()
 
+	body, err := io.ReadAll(io.LimitReader(resp.Body, parserResponseLimit))
+	if err != nil {
+		logger.Errorf("ошибка чтения ответа парсера: %v", err)
+		respondStatus(c, http.StatusBadGateway, i18n.Errorf("upload.parser_unavailable"))
+		return
+	}
+
+	if found {
+		switch {
+		case resp.StatusCode == http.StatusNotFound:
+			c.JSON(http.StatusOK, parsetask.ToResponse(task, true))
+			return
+		case resp.StatusCode >= 200 && resp.StatusCode < 300:
+			if err := s.parseTasks.ApplyPolledStatus(c.Request.Context(), task, body); err != nil {
+				logger.Errorf("Не удалось сохранить статус задачи %s: %v", taskID, err)
+			}
+		}
+	}
+
 	// Просто перенаправляем ответ от Python обратно клиенту
-	c.Status(resp.StatusCode)
 	for key, values := range resp.Header {
+		if key == "Content-Length" {
+			continue
+		}
 		c.Writer.Header().Set(key, values[0])
 	}
-	io.Copy(c.Writer, resp.Body)
+	c.Status(resp.StatusCode)
+	c.Writer.Write(body)
+}
+
+// ListParseTasksHandler обрабатывает GET /api/v1/tasks — последние загрузки
+// текущего пользователя, новые первыми.
+//
+// Query-параметры:
+//   - limit (default 20, max 100), offset (default 0)
+//
+// Response: 200 + ParseTasksResponse
+// Errors:   400 (параметры), 401, 500 (БД)
+func (s *Server) ListParseTasksHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "ListParseTasksHandler")
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	limitStr := c.DefaultQuery("limit", "20")
+	limit64, err := strconv.ParseInt(limitStr, 10, 32)
+	if err != nil || limit64 <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.limit_positive"))
+		return
+	}
+
+	offsetStr := c.DefaultQuery("offset", "0")
+	offset64, err := strconv.ParseInt(offsetStr, 10, 32)
+	if err != nil || offset64 < 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.offset_non_negative"))
+		return
+	}
+
+	result, err := s.parseTasks.ListForUser(c.Request.Context(), userID, int32(limit64), int32(offset64))
+	if err != nil {
+		logger.Errorf("Ошибка ListForUser(user_id=%d): %v", userID, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// PushParseTaskStatusHandler обрабатывает POST /internal/worker/tasks/:task_id/status:
+// парсер сообщает статус задачи, ID созданного тендера или ошибку.
+// completed и failed — итоговые: после них опрос парсера запись не меняет,
+// а для задачи обновления тендера (source = sync) записывается его результат.
+//
+// Request:  ParseTaskStatusPush
+// Response: 200 + ParseTask
+// Errors:   400 (тело), 404 (задача не загружалась через API), 500 (БД)
+func (s *Server) PushParseTaskStatusHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "PushParseTaskStatusHandler")
+	taskID := c.Param("task_id")
+
+	var req api_models.ParseTaskStatusPush
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warnf("Некорректное тело статуса задачи %s: %v", taskID, err)
+		respondBindError(c, err)
+		return
+	}
+
+	task, err := s.parseTasks.ApplyPushedStatus(c.Request.Context(), taskID, req)
+	if err != nil {
+		logger.Errorf("Ошибка ApplyPushedStatus(%s): %v", taskID, err)
+		respondError(c, err)
+		return
+	}
+	// Итог обновления тендера по расписанию: что изменилось и уведомления
+	// подписчикам. Ошибка не мешает принять статус — парсер бы его повторил
+	if err := s.etpSync.Complete(c.Request.Context(), *task); err != nil {
+		logger.Errorf("Ошибка записи результата обновления по задаче %s: %v", taskID, err)
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// respondParserUnavailable отвечает на ошибку запроса к парсеру: 503, если
+// circuit breaker разомкнут и запрос не отправлялся, иначе 502.
+func respondParserUnavailable(c *gin.Context, err error) {
+	status := http.StatusBadGateway
+	if errors.Is(err, outbound.ErrCircuitOpen) {
+		status = http.StatusServiceUnavailable
+	}
+	respondStatus(c, status, i18n.Errorf("upload.parser_unavailable"))
+}
+
+// respondUploadError отвечает на ошибку чтения или проверки формы загрузки.
+func (s *Server) respondUploadError(c *gin.Context, logger logging.Logger, err error) {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge), errors.Is(err, errUploadTooLarge):
+		s.uploadTooLarge(c, logger)
+	case errors.Is(err, errUploadUnsupported):
+		logger.Warnf("Отклонена загрузка: %v", err)
+		respondStatus(c, http.StatusUnsupportedMediaType, err)
+	case errors.Is(err, errUploadFileMissing):
+		respondStatus(c, http.StatusBadRequest, err)
+	default:
+		logger.Errorf("ошибка чтения формы загрузки: %v", err)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("upload.invalid_form"))
+	}
+}
+
+func (s *Server) uploadTooLarge(c *gin.Context, logger logging.Logger) {
+	limit := s.config.Upload.MaxFileSize
+	logger.Warnf("Файл превышает лимит %d байт", limit)
+	respondStatus(c, http.StatusRequestEntityTooLarge, i18n.Errorf("upload.file_too_large", limit))
 }
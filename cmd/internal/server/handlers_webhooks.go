@@ -0,0 +1,188 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/webhooks"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// HandleListWebhooks обрабатывает GET /api/v1/admin/webhooks.
+// Возвращает все подписки (без секретов).
+func (s *Server) HandleListWebhooks(c *gin.Context) {
+	logger := s.logger.WithField("handler", "HandleListWebhooks")
+
+	subs, err := s.webhooks.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		logger.Errorf("Ошибка ListSubscriptions: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, subs)
+}
+
+// HandleCreateWebhook обрабатывает POST /api/v1/admin/webhooks.
+//
+// Создаёт подписку. Секрет для проверки X-Webhook-Signature возвращается
+// в ответе один раз.
+//
+// Request:  CreateWebhookRequest (strict JSON: DisallowUnknownFields)
+// Response: 201 + CreateWebhookResponse
+// Errors:   400 (url, events), 500 (БД)
+func (s *Server) HandleCreateWebhook(c *gin.Context) {
+	logger := s.logger.WithField("handler", "HandleCreateWebhook")
+
+	var req api_models.CreateWebhookRequest
+	if !decodeStrictJSON(c, logger, &req) {
+		return
+	}
+
+	actorID, ok := s.adminActorID(c, logger)
+	if !ok {
+		return
+	}
+
+	result, err := s.webhooks.CreateSubscription(c.Request.Context(), req, actorID)
+	if err != nil {
+		logger.Errorf("Ошибка CreateSubscription: %v", err)
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, result)
+}
+
+// HandleUpdateWebhook обрабатывает PATCH /api/v1/admin/webhooks/:id.
+//
+// Request:  UpdateWebhookRequest (strict JSON: DisallowUnknownFields)
+// Response: 200 + WebhookResponse
+// Errors:   400 (валидация), 404 (подписка не найдена), 500 (БД)
+func (s *Server) HandleUpdateWebhook(c *gin.Context) {
+	logger := s.logger.WithField("handler", "HandleUpdateWebhook")
+
+	id, ok := parseWebhookID(c, logger)
+	if !ok {
+		return
+	}
+
+	var req api_models.UpdateWebhookRequest
+	if !decodeStrictJSON(c, logger, &req) {
+		return
+	}
+
+	result, err := s.webhooks.UpdateSubscription(c.Request.Context(), id, req)
+	if err != nil {
+		logger.Errorf("Ошибка UpdateSubscription(id=%d): %v", id, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// HandleDeleteWebhook обрабатывает DELETE /api/v1/admin/webhooks/:id.
+// Удаляет подписку вместе с журналом доставок; чтобы сохранить журнал,
+// подписку деактивируют через PATCH {"is_active": false}.
+func (s *Server) HandleDeleteWebhook(c *gin.Context) {
+	logger := s.logger.WithField("handler", "HandleDeleteWebhook")
+
+	id, ok := parseWebhookID(c, logger)
+	if !ok {
+		return
+	}
+
+	if err := s.webhooks.DeleteSubscription(c.Request.Context(), id); err != nil {
+		logger.Errorf("Ошибка DeleteSubscription(id=%d): %v", id, err)
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// HandleListWebhookDeliveries обрабатывает GET /api/v1/admin/webhooks/:id/deliveries.
+//
+// Query-параметры:
+//   - status: pending | delivered | failed (по умолчанию все)
+//   - limit (default 50, max 200), offset (default 0)
+//
+// Response: 200 + WebhookDeliveriesResponse
+// Errors:   400 (параметры), 404 (подписка не найдена), 500 (БД)
+func (s *Server) HandleListWebhookDeliveries(c *gin.Context) {
+	logger := s.logger.WithField("handler", "HandleListWebhookDeliveries")
+
+	id, ok := parseWebhookID(c, logger)
+	if !ok {
+		return
+	}
+
+	limitStr := c.DefaultQuery("limit", "50")
+	limit64, err := strconv.ParseInt(limitStr, 10, 32)
+	if err != nil || limit64 <= 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.limit_positive"))
+		return
+	}
+
+	offsetStr := c.DefaultQuery("offset", "0")
+	offset64, err := strconv.ParseInt(offsetStr, 10, 32)
+	if err != nil || offset64 < 0 {
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.offset_non_negative"))
+		return
+	}
+
+	result, err := s.webhooks.ListDeliveries(c.Request.Context(), id, c.Query("status"), int32(limit64), int32(offset64))
+	if err != nil {
+		logger.Errorf("Ошибка ListDeliveries(id=%d): %v", id, err)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// publishWebhook ставит событие в очередь вебхуков. Изменение к этому моменту
+// уже сохранено, поэтому ошибка только логируется и не влияет на ответ.
+func (s *Server) publishWebhook(c *gin.Context, logger logging.Logger, event webhooks.Event, data any) {
+	if err := s.webhooks.Publish(c.Request.Context(), event, data); err != nil {
+		logger.Errorf("Не удалось поставить событие %s в очередь вебхуков: %v", event, err)
+	}
+}
+
+func parseWebhookID(c *gin.Context, logger logging.Logger) (int64, bool) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		logger.Errorf("Некорректный ID подписки: %s", idStr)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.id_positive"))
+		return 0, false
+	}
+	return id, true
+}
+
+// decodeStrictJSON разбирает тело запроса, отклоняя неизвестные поля.
+// При ошибке ответ уже отправлен и возвращается false.
+func decodeStrictJSON(c *gin.Context, logger logging.Logger, dst any) bool {
+	body, err := c.GetRawData()
+	if err != nil {
+		logger.Errorf("Ошибка чтения тела запроса: %v", err)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.body_read_failed", err))
+		return false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		logger.Errorf("Ошибка парсинга JSON: %v", err)
+		respondStatus(c, http.StatusBadRequest, i18n.Errorf("request.invalid_json", err))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/catalog"
+)
+
+// listWorkGroupsHandler обрабатывает GET /api/v1/catalog/work-groups.
+// Возвращает классификатор видов работ деревом с числом позиций каталога.
+func (s *Server) listWorkGroupsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "listWorkGroupsHandler")
+
+	response, err := s.workGroups.Tree(c.Request.Context())
+	if err != nil {
+		logger.Errorf("Ошибка получения классификатора: %v", err)
+		respondErrorCode(c, err, "work_group_conflict")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// createWorkGroupHandler обрабатывает POST /api/v1/admin/work-groups.
+func (s *Server) createWorkGroupHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "createWorkGroupHandler")
+
+	var req api_models.WorkGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	group, err := s.workGroups.Create(c.Request.Context(), req)
+	if err != nil {
+		logger.Errorf("Ошибка создания узла %q: %v", req.Code, err)
+		respondErrorCode(c, err, "work_group_conflict")
+		return
+	}
+
+	c.JSON(http.StatusCreated, group)
+}
+
+// updateWorkGroupHandler обрабатывает PUT /api/v1/admin/work-groups/:id.
+// Меняет код, название и родителя узла; новый код переносится на позиции каталога.
+func (s *Server) updateWorkGroupHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "updateWorkGroupHandler")
+
+	id, ok := parseUnitParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req api_models.WorkGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	group, err := s.workGroups.Update(c.Request.Context(), id, req)
+	if err != nil {
+		logger.Errorf("Ошибка изменения узла %d: %v", id, err)
+		respondErrorCode(c, err, "work_group_conflict")
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// deleteWorkGroupHandler обрабатывает DELETE /api/v1/admin/work-groups/:id.
+// Узел с вложенными узлами не удаляется (409).
+func (s *Server) deleteWorkGroupHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "deleteWorkGroupHandler")
+
+	id, ok := parseUnitParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := s.workGroups.Delete(c.Request.Context(), id); err != nil {
+		logger.Errorf("Ошибка удаления узла %d: %v", id, err)
+		respondErrorCode(c, err, "work_group_conflict")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// setCatalogPositionWorkGroupHandler обрабатывает
+// PUT /api/v1/admin/catalog/positions/:id/work-group.
+func (s *Server) setCatalogPositionWorkGroupHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "setCatalogPositionWorkGroupHandler")
+
+	positionID, ok := parseUnitParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req api_models.SetCatalogPositionWorkGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	pos, err := s.workGroups.AssignPosition(c.Request.Context(), positionID, req.WorkGroupCode)
+	if err != nil {
+		logger.Errorf("Ошибка привязки позиции %d к классификатору: %v", positionID, err)
+		respondErrorCode(c, err, "work_group_conflict")
+		return
+	}
+
+	c.JSON(http.StatusOK, catalog.PositionToSummary(*pos))
+}
+
+// getLotWorkGroupCostsHandler обрабатывает GET /api/v1/lots/:id/analytics/work-groups.
+// Стоимость позиций предложений лота, свёрнутая по классификатору видов работ.
+func (s *Server) getLotWorkGroupCostsHandler(c *gin.Context) {
+	logger := s.logger.WithField("handler", "getLotWorkGroupCostsHandler")
+
+	lotID, ok := parseUnitParam(c, "id")
+	if !ok {
+		return
+	}
+
+	response, err := s.analytics.GetLotWorkGroupCosts(c.Request.Context(), lotID)
+	if err != nil {
+		logger.Errorf("Ошибка GetLotWorkGroupCosts(id=%d): %v", lotID, err)
+		respondErrorCode(c, err, "work_group_conflict")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/currency"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/buildinfo"
+)
+
+// cacheVersion — версия данных, из которых собран ответ (запросы cache_version.sql):
+// самый поздний updated_at среди строк ответа и их число.
+type cacheVersion struct {
+	UpdatedAt time.Time
+	RowsCount int64
+}
+
+// newETagSalt возвращает часть ETag, общую для всех ответов процесса: сборку
+// (меняется формат ответа) и курсы валют (меняются итоги лотов). Значение
+// детерминировано, поэтому реплики одной сборки выдают одинаковые ETag.
+func newETagSalt(cfg config.CurrencyConfig) string {
+	info := buildinfo.Get()
+	currencies, rates := currency.NewRates(cfg).QueryArgs()
+	return fmt.Sprintf("%s|%s|%s|%v|%v", info.Version, info.Commit, cfg.Base, currencies, rates)
+}
+
+// computeETag строит сильный ETag ответа: хеш маршрута, id сущности, версии данных
+// и строки запроса (limit/offset/include_deleted меняют тело ответа).
+func computeETag(salt, route string, id int64, version cacheVersion, rawQuery string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%d\n%d\n%d\n%s", salt, route, id, version.UpdatedAt.UnixNano(), version.RowsCount, rawQuery)
+	return `"` + hex.EncodeToString(h.Sum(nil))[:32] + `"`
+}
+
+// etagMatches проверяет заголовок If-None-Match: список ETag через запятую или "*".
+// Сравнение слабое (RFC 9110, 13.1.2): префикс W/ не учитывается.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// checkNotModified получает версию данных ответа (loadVersion), считает ETag и,
+// если клиент прислал совпадающий If-None-Match, отвечает 304 без тела.
+// Возвращает ETag для успешного ответа и true, если ответ уже отправлен.
+//
+// Версия читается до загрузки данных: если данные изменятся между этими шагами,
+// клиент получит новое тело со старым ETag и при следующем запросе — снова 200.
+// Если версию получить не удалось (в том числе sql.ErrNoRows), ETag пустой и
+// обработчик отвечает как обычно — без кэширования.
+func (s *Server) checkNotModified(
+	c *gin.Context,
+	route string,
+	id int64,
+	cacheControl string,
+	loadVersion func(ctx context.Context) (cacheVersion, error),
+) (string, bool) {
+	version, err := loadVersion(c.Request.Context())
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warnf("не удалось получить версию данных %s id=%d для ETag: %v", route, id, err)
+		}
+		return "", false
+	}
+
+	etag := computeETag(s.etagSalt, route, id, version, c.Request.URL.RawQuery)
+	if ifNoneMatch := c.GetHeader("If-None-Match"); ifNoneMatch != "" && etagMatches(ifNoneMatch, etag) {
+		setCacheHeaders(c, etag, cacheControl)
+		c.Status(http.StatusNotModified)
+		return etag, true
+	}
+	return etag, false
+}
+
+// setCacheHeaders выставляет ETag и Cache-Control успешного ответа.
+// Пустой ETag (версия неизвестна) — ответ без заголовков кэширования.
+func setCacheHeaders(c *gin.Context, etag, cacheControl string) {
+	if etag == "" {
+		return
+	}
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", cacheControl)
+}
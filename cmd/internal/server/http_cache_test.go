@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR HTTP CACHING (ETag / If-None-Match)
+
+What user problems does this protect us from?
+================================================================================
+1. Stale pages — any change of the underlying rows must change the ETag
+2. Wasted traffic — an unchanged entity is answered with 304 and no body
+3. Broken pages — a failed version lookup must not break the normal response
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: computeETag
+- GIVEN the same version, route, id and query → the same quoted ETag
+- GIVEN a newer updated_at, a different rows count, id or query string → a different ETag
+
+SCENARIO 2: etagMatches
+- GIVEN If-None-Match with a list, a weak W/ tag or "*" → match
+- GIVEN another tag → no match
+
+SCENARIO 3: checkNotModified
+- GIVEN If-None-Match equal to the current ETag
+  WHEN the handler runs
+  THEN 304 with ETag and Cache-Control, no body, data is not loaded
+- GIVEN no If-None-Match → 200 with ETag and Cache-Control
+- GIVEN the version lookup fails (sql.ErrNoRows or DB error) → 200 without caching headers
+*/
+
+func TestComputeETag(t *testing.T) {
+	version := cacheVersion{UpdatedAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC), RowsCount: 10}
+
+	etag := computeETag("salt", "lot_comparison", 5, version, "")
+	assert.Equal(t, etag, computeETag("salt", "lot_comparison", 5, version, ""))
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+
+	newer := version
+	newer.UpdatedAt = newer.UpdatedAt.Add(time.Microsecond)
+	fewer := version
+	fewer.RowsCount = 9
+
+	for name, other := range map[string]string{
+		"updated_at": computeETag("salt", "lot_comparison", 5, newer, ""),
+		"rows_count": computeETag("salt", "lot_comparison", 5, fewer, ""),
+		"id":         computeETag("salt", "lot_comparison", 6, version, ""),
+		"route":      computeETag("salt", "tender_details", 5, version, ""),
+		"query":      computeETag("salt", "lot_comparison", 5, version, "limit=10"),
+		"salt":       computeETag("other", "lot_comparison", 5, version, ""),
+	} {
+		assert.NotEqual(t, etag, other, name)
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	etag := `"abc"`
+
+	assert.True(t, etagMatches(`"abc"`, etag))
+	assert.True(t, etagMatches(`"old", "abc"`, etag))
+	assert.True(t, etagMatches(`W/"abc"`, etag))
+	assert.True(t, etagMatches(`*`, etag))
+	assert.False(t, etagMatches(`"abd"`, etag))
+}
+
+// newCacheTestRouter — маршрут, который ведёт себя как тяжёлый обработчик чтения:
+// сначала checkNotModified, затем "загрузка данных" и ответ 200.
+func newCacheTestRouter(version cacheVersion, versionErr error, loaded *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	s := &Server{logger: testutil.NewMockLogger(), etagSalt: "test"}
+
+	router := gin.New()
+	router.GET("/lots/:id/comparison", func(c *gin.Context) {
+		etag, notModified := s.checkNotModified(c, "lot_comparison", 5, "private, no-cache", func(ctx context.Context) (cacheVersion, error) {
+			return version, versionErr
+		})
+		if notModified {
+			return
+		}
+		*loaded++
+		setCacheHeaders(c, etag, "private, no-cache")
+		c.JSON(http.StatusOK, gin.H{"lot_id": 5})
+	})
+	return router
+}
+
+func TestCheckNotModified(t *testing.T) {
+	version := cacheVersion{UpdatedAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC), RowsCount: 10}
+	loaded := 0
+	router := newCacheTestRouter(version, nil, &loaded)
+
+	// WHEN first request without If-None-Match
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/lots/5/comparison", nil))
+
+	// THEN 200 with caching headers
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+	assert.Equal(t, 1, loaded)
+
+	// WHEN repeated with the received ETag
+	req := httptest.NewRequest(http.MethodGet, "/lots/5/comparison", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// THEN 304 without body, data is not loaded again
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+	assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+	assert.Equal(t, 1, loaded)
+}
+
+func TestCheckNotModified_VersionUnavailable_NoCaching(t *testing.T) {
+	for name, versionErr := range map[string]error{
+		"not found": sql.ErrNoRows,
+		"db error":  errors.New("connection refused"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			loaded := 0
+			router := newCacheTestRouter(cacheVersion{}, versionErr, &loaded)
+
+			req := httptest.NewRequest(http.MethodGet, "/lots/5/comparison", nil)
+			req.Header.Set("If-None-Match", "*")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Empty(t, w.Header().Get("ETag"))
+			assert.Empty(t, w.Header().Get("Cache-Control"))
+			assert.Equal(t, 1, loaded)
+		})
+	}
+}
@@ -6,9 +6,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
-	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/auth"
-	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
 )
 
 // setSameSiteModeFromConfig устанавливает SameSite атрибут на основе конфигурации.
@@ -42,18 +41,18 @@ func clearAccessCookie(c *gin.Context, cfg *config.Config) {
 }
 
 // AuthMiddleware проверяет наличие и валидность JWT access токена из httpOnly cookie
-// При успешной валидации помещает user_id и role в gin.Context
-func AuthMiddleware(cfg *config.Config, store db.Store, logger logging.Logger) gin.HandlerFunc {
-	// Создаем auth service для валидации токенов
-	authService := auth.NewService(store, cfg, logger)
-
+// При успешной валидации помещает user_id, role и organization_id в gin.Context.
+// Запросы с токеном имперсонации дополнительно проходят auditImpersonation.
+// authService должен быть тем же экземпляром, что выполняет смену ролей:
+// его denylist отклоняет токены, выпущенные до отзыва. Версия токена сверяется
+// с users.token_version (auth.Service.CheckTokenVersion, с кэшем), поэтому
+// отзыв на другом экземпляре API действует через token_version_cache_ttl.
+func AuthMiddleware(cfg *config.Config, authService *auth.Service) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Извлекаем access token из cookie
 		accessToken, err := c.Cookie(cfg.Auth.CookieAccessName)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "access token not found",
-			})
+			respondCode(c, http.StatusUnauthorized, "access_token_missing", "auth.access_token_missing")
 			c.Abort()
 			return
 		}
@@ -68,54 +67,82 @@ func AuthMiddleware(cfg *config.Config, store db.Store, logger logging.Logger) g
 			// Различаем истекшие и невалидные токены для фронтенда:
 			// - "access_token_expired" — можно обновить через /auth/refresh
 			// - "access_token_invalid" — необходим полный re-login
-			authError := "access_token_invalid"
+			//   (в том числе отозванный токен: сессии пользователя тоже отозваны)
+			authError, messageKey := "access_token_invalid", "auth.access_token_invalid"
 			if errors.Is(err, auth.ErrTokenExpired) {
-				authError = "access_token_expired"
+				authError, messageKey = "access_token_expired", "auth.access_token_expired"
 			}
 			c.Header("X-Auth-Error", authError)
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": authError,
-			})
+			respondCode(c, http.StatusUnauthorized, authError, messageKey)
+			c.Abort()
+			return
+		}
+
+		// Токены, выпущенные до появления организаций (миграция 000027), не
+		// содержат org_id: отвечаем как на истекший, чтобы фронтенд выполнил refresh
+		if claims.OrganizationID == 0 {
+			clearAccessCookie(c, cfg)
+			c.Header("X-Auth-Error", "access_token_expired")
+			respondCode(c, http.StatusUnauthorized, "access_token_expired", "auth.access_token_expired")
+			c.Abort()
+			return
+		}
+
+		// Токен отозван выходом на всех устройствах, сменой пароля, роли или статуса
+		if err := authService.CheckTokenVersion(c.Request.Context(), claims); err != nil {
+			if errors.Is(err, auth.ErrTokenRevoked) {
+				clearAccessCookie(c, cfg)
+				c.Header("X-Auth-Error", "access_token_invalid")
+				respondCode(c, http.StatusUnauthorized, "access_token_invalid", "auth.access_token_invalid")
+				c.Abort()
+				return
+			}
+			// БД недоступна: токен не принимается без проверки отзыва
+			respondCode(c, http.StatusServiceUnavailable, CodeServiceUnavailable, "auth.access_token_verification_failed")
 			c.Abort()
 			return
 		}
 
-		// Сохраняем user_id и role в context
+		// Сохраняем user_id, role и organization_id в context
 		c.Set("user_id", claims.UserID)
 		c.Set("role", claims.Role)
+		c.Set("organization_id", claims.OrganizationID)
+
+		// Токен имперсонации: каждый запрос пишется в журнал (см. auditImpersonation)
+		if claims.Impersonated() {
+			auditImpersonation(c, cfg, authService, claims)
+			return
+		}
 
 		c.Next()
 	}
 }
 
-// RequireRole проверяет, что у пользователя есть требуемая роль
-// Должна использоваться после AuthMiddleware
-func RequireRole(requiredRole string) gin.HandlerFunc {
+// RequirePermission проверяет, что роли пользователя разрешено действие
+// (матрица прав — auth.HasPermission). Должна использоваться после AuthMiddleware.
+func RequirePermission(permission auth.Permission) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Извлекаем role из context
 		roleValue, exists := c.Get("role")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "authentication required",
-			})
+			respondCode(c, http.StatusUnauthorized, CodeUnauthorized, "auth.authentication_required")
 			c.Abort()
 			return
 		}
 
 		role, ok := roleValue.(string)
 		if !ok {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "invalid role type in context",
-			})
+			respondInternal(c, errors.New("invalid role type in context"))
 			c.Abort()
 			return
 		}
 
-		// Проверяем роль
-		if role != requiredRole {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error": "insufficient permissions",
-			})
+		// Проверяем право роли
+		if !auth.HasPermission(role, permission) {
+			locale := requestLocale(c)
+			body := localizedError(locale, CodeForbidden, "auth.insufficient_permissions")
+			body.Details = []ErrorDetail{{Rule: "permission", Value: string(permission), Message: i18n.T(locale, "auth.permission_detail", permission)}}
+			writeError(c, http.StatusForbidden, body)
 			c.Abort()
 			return
 		}
@@ -36,20 +36,20 @@ func CsrfMiddleware() gin.HandlerFunc {
 
 		csrfCookie, err := c.Cookie(csrfCookieName)
 		if err != nil || csrfCookie == "" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "csrf_token_missing"})
+			respondCode(c, http.StatusForbidden, "csrf_token_missing", "auth.csrf_cookie_missing")
 			c.Abort()
 			return
 		}
 
 		csrfHeader := c.GetHeader(csrfHeaderName)
 		if csrfHeader == "" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "csrf_header_missing"})
+			respondCode(c, http.StatusForbidden, "csrf_header_missing", "auth.csrf_header_missing")
 			c.Abort()
 			return
 		}
 
 		if subtle.ConstantTimeCompare([]byte(csrfCookie), []byte(csrfHeader)) != 1 {
-			c.JSON(http.StatusForbidden, gin.H{"error": "csrf_invalid"})
+			respondCode(c, http.StatusForbidden, "csrf_invalid", "auth.csrf_invalid")
 			c.Abort()
 			return
 		}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/auth"
+)
+
+const (
+	// impersonatedHeader помечает ответы на запросы под имперсонацией;
+	// значение — id администратора, действующего от имени пользователя
+	impersonatedHeader = "X-Impersonated"
+	// impersonationContextKey — claims токена имперсонации в gin.Context
+	impersonationContextKey = "impersonation"
+)
+
+// auditImpersonation обслуживает запрос с токеном имперсонации (вызывается из
+// AuthMiddleware): помечает ответ заголовком X-Impersonated, записывает
+// запрос в журнал до выполнения и дописывает статус после. Без записи в
+// журнал запрос не выполняется: завершенная сессия — 401 (фронтенд
+// восстанавливает сессию администратора через /auth/refresh), недоступная
+// БД — 503.
+func auditImpersonation(c *gin.Context, cfg *config.Config, authService *auth.Service, claims *auth.JWTClaims) {
+	c.Header(impersonatedHeader, strconv.FormatInt(claims.ActingAdminID, 10))
+
+	auditID, err := authService.AuditImpersonatedRequest(c.Request.Context(), claims,
+		c.Request.Method, c.Request.URL.RequestURI(), parseIPAddress(c.ClientIP()))
+	if err != nil {
+		if errors.Is(err, auth.ErrImpersonationEnded) {
+			clearAccessCookie(c, cfg)
+			c.Header("X-Auth-Error", "access_token_expired")
+			respondCode(c, http.StatusUnauthorized, "impersonation_ended", "auth.impersonation_ended")
+			c.Abort()
+			return
+		}
+		_ = c.Error(err)
+		respondCode(c, http.StatusServiceUnavailable, CodeServiceUnavailable, "auth.impersonation_audit_failed")
+		c.Abort()
+		return
+	}
+
+	c.Set(impersonationContextKey, claims)
+	c.Next()
+
+	// Запрос уже выполнен: статус дописывается и при отмене контекста клиентом
+	if err := authService.CompleteImpersonationAudit(context.WithoutCancel(c.Request.Context()), auditID, c.Writer.Status()); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+// impersonationClaims возвращает claims токена имперсонации текущего запроса.
+func impersonationClaims(c *gin.Context) (*auth.JWTClaims, bool) {
+	value, exists := c.Get(impersonationContextKey)
+	if !exists {
+		return nil, false
+	}
+	claims, ok := value.(*auth.JWTClaims)
+	return claims, ok
+}
+
+// DenyImpersonated запрещает действие под имперсонацией: операции с учетной
+// записью (пароль, выход на всех устройствах) затронули бы самого пользователя.
+// Должна использоваться после AuthMiddleware.
+func DenyImpersonated() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, impersonated := impersonationClaims(c); impersonated {
+			respondCode(c, http.StatusForbidden, "impersonation_forbidden", "auth.impersonation_forbidden")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/auth"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// organizationLookup возвращает организацию-владельца ресурса по его id
+// (GetTenderOrganizationID, GetLotOrganizationID и т.п.).
+type organizationLookup func(ctx context.Context, id int64) (int64, error)
+
+// requestOrganizationScope возвращает организацию, которой ограничены запросы
+// пользователя. Для ролей с organizations:manage фильтра нет (Valid=false).
+// Должна использоваться после AuthMiddleware.
+func requestOrganizationScope(c *gin.Context) sql.NullInt64 {
+	if auth.HasPermission(c.GetString("role"), auth.PermissionOrganizationsManage) {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: c.GetInt64("organization_id"), Valid: true}
+}
+
+// RequireOrganizationAccess пропускает запрос к ресурсу из параметра пути
+// param, только если ресурс принадлежит организации пользователя. Чужой ресурс
+// выглядит как несуществующий (404), чтобы не раскрывать его наличие.
+// Некорректный id и отсутствующий ресурс передаются хендлеру: он сам отвечает
+// 400/404. Должна использоваться после AuthMiddleware.
+func RequireOrganizationAccess(param string, lookup organizationLookup) gin.HandlerFunc {
+	logger := logging.GetLogger()
+
+	return func(c *gin.Context) {
+		scope := requestOrganizationScope(c)
+		if !scope.Valid {
+			c.Next()
+			return
+		}
+
+		id, err := strconv.ParseInt(c.Param(param), 10, 64)
+		if err != nil || id <= 0 {
+			c.Next()
+			return
+		}
+
+		organizationID, err := lookup(c.Request.Context(), id)
+		if errors.Is(err, sql.ErrNoRows) {
+			c.Next()
+			return
+		}
+		if err != nil {
+			logger.Errorf("Ошибка проверки организации ресурса %s=%d: %v", param, id, err)
+			respondInternal(c, err)
+			c.Abort()
+			return
+		}
+
+		if organizationID != scope.Int64 {
+			logger.Warnf("Доступ к %s %s запрещен: ресурс организации %d, пользователь организации %d",
+				c.Request.Method, c.Request.URL.Path, organizationID, scope.Int64)
+			respondCode(c, http.StatusNotFound, CodeNotFound, "request.resource_not_found")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -1,25 +1,55 @@
 package server
 
 import (
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/time/rate"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/ratelimit"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
 )
 
 // ServiceRateLimitMiddleware создает middleware для rate limiting внутренних сервисов.
 // Ограничивает количество запросов для защиты от злоупотреблений в случае компрометации API ключа.
-// requests - максимальное количество запросов в секунду
-// burst - максимальный размер всплеска запросов
+// Лимит общий для всех воркеров и задаётся limiter'ом: его SetLimit/SetBurst
+// меняют ограничение без перезапуска (Server.ApplyReloadable).
 // Примечание: rate.Limiter потокобезопасен, дополнительная синхронизация не требуется.
-func ServiceRateLimitMiddleware(requests int, burst int) gin.HandlerFunc {
-	limiter := rate.NewLimiter(rate.Limit(requests), burst)
-
+func ServiceRateLimitMiddleware(limiter *rate.Limiter) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if !limiter.Allow() {
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"error": "rate limit exceeded",
-			})
+			respondCode(c, http.StatusTooManyRequests, CodeRateLimited, "auth.rate_limited")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RateLimitMiddleware ограничивает частоту запросов к /api/v1 в группе group
+// (ratelimit.GroupUser, GroupExport, GroupAuth). Ключ — user_id, если запрос
+// прошёл AuthMiddleware, иначе IP клиента; роль выбирает множитель лимита.
+// При превышении отвечает 429 с Retry-After в секундах. Ошибка хранилища
+// лимитов не блокирует запрос — пропускаем его и пишем предупреждение.
+func RateLimitMiddleware(limiter *ratelimit.Limiter, group string, logger logging.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := "ip:" + c.ClientIP()
+		if userID, ok := c.Get("user_id"); ok {
+			key = fmt.Sprintf("user:%v", userID)
+		}
+
+		allowed, retryAfter, err := limiter.Allow(c.Request.Context(), group, c.GetString("role"), key)
+		if err != nil {
+			logger.Warnf("Лимит запросов %s для %s не проверен: %v", group, key, err)
+		}
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			respondCode(c, http.StatusTooManyRequests, CodeRateLimited, "auth.rate_limited")
+			c.Abort()
 			return
 		}
 
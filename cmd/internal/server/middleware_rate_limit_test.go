@@ -0,0 +1,86 @@
+package server_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil/servertest"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR API RATE LIMITS (servertest harness)
+
+What user problems does this protect us from?
+================================================================================
+1. One user's script degrading the API for the whole organization
+2. Password guessing on POST /api/v1/auth/login
+3. Clients retrying immediately instead of waiting for the limit to reset
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Per-user limit
+- GIVEN user_burst 2 and a very low user_rps
+  WHEN an editor sends three requests THEN the third gets 429 with Retry-After
+- GIVEN another user THEN their requests still pass (buckets are per user_id)
+
+SCENARIO 2: Public auth routes
+- GIVEN auth_burst 1
+  WHEN two logins come from the same IP THEN the second gets 429 before the handler
+
+SCENARIO 3: Reload
+- GIVEN an exhausted bucket WHEN ApplyReloadable switches the driver to none
+  THEN requests pass again
+*/
+
+func withUserLimits(cfg *config.Config) {
+	cfg.RateLimit.Driver = "memory"
+	cfg.RateLimit.UserRPS = 0.001
+	cfg.RateLimit.UserBurst = 2
+	cfg.RateLimit.AuthRPS = 0.001
+	cfg.RateLimit.AuthBurst = 1
+}
+
+func TestRateLimit_PerUser(t *testing.T) {
+	h := servertest.New(t, withUserLimits)
+
+	// RequirePermission отвечает 403 без обращений к БД — лимит проверяется раньше
+	for i := 0; i < 2; i++ {
+		w := h.Do(t, http.MethodPost, "/api/v1/admin/tenders/7/purge", nil, servertest.Editor)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	}
+	w := h.Do(t, http.MethodPost, "/api/v1/admin/tenders/7/purge", nil, servertest.Editor)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1000", w.Header().Get("Retry-After"))
+
+	w = h.Do(t, http.MethodPost, "/api/v1/admin/tenders/7/purge", nil, servertest.Viewer)
+	assert.Equal(t, http.StatusForbidden, w.Code, "other users are not affected")
+}
+
+func TestRateLimit_LoginByIP(t *testing.T) {
+	h := servertest.New(t, withUserLimits)
+
+	// Пустое тело отклоняется обработчиком до обращения к БД
+	first := h.Do(t, http.MethodPost, "/api/v1/auth/login", nil, nil)
+	second := h.Do(t, http.MethodPost, "/api/v1/auth/login", nil, nil)
+
+	assert.Equal(t, http.StatusBadRequest, first.Code)
+	assert.Equal(t, http.StatusTooManyRequests, second.Code)
+	assert.NotEmpty(t, second.Header().Get("Retry-After"))
+}
+
+func TestRateLimit_ReloadDisables(t *testing.T) {
+	h := servertest.New(t, withUserLimits)
+
+	for i := 0; i < 3; i++ {
+		h.Do(t, http.MethodPost, "/api/v1/admin/tenders/7/purge", nil, servertest.Editor)
+	}
+
+	h.Server.ApplyReloadable(servertest.Config())
+
+	w := h.Do(t, http.MethodPost, "/api/v1/admin/tenders/7/purge", nil, servertest.Editor)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -1,48 +1,72 @@
 package server
 
 import (
-	"crypto/subtle"
 	"net/http"
-	"os"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/servicecreds"
 	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
 )
 
 // ServiceBearerAuthMiddleware создает middleware для аутентификации внутренних сервисов
 // используя Bearer токен из заголовка Authorization.
-// serviceName используется для идентификации сервиса в контексте запроса.
-func ServiceBearerAuthMiddleware(serviceName string) gin.HandlerFunc {
-	secret := os.Getenv("GO_SERVER_API_KEY")
-	if secret == "" {
-		panic("GO_SERVER_API_KEY environment variable is not set - run 'make generate-env' to create one")
-	}
-
-	secretBytes := []byte(secret)
+// Ключи проверяются по in-memory кэшу creds, который обновляется без перезапуска сервера.
+// Имя воркера, которому выдан ключ, сохраняется в контексте ("service"), id ключа —
+// в "service_credential_id" (0 для статического ключа из окружения).
+// Доступ к конкретным роутам проверяет RequireServiceScope.
+func ServiceBearerAuthMiddleware(creds *servicecreds.Service) gin.HandlerFunc {
 	logger := logging.GetLogger()
 
 	return func(c *gin.Context) {
 		h := c.GetHeader("Authorization")
 		if !strings.HasPrefix(h, "Bearer ") {
 			logger.Warnf("Service auth failed: missing or invalid Authorization header from %s", c.ClientIP())
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "service auth required"})
+			respondCode(c, http.StatusUnauthorized, "service_auth_required", "auth.service_auth_required")
+			c.Abort()
 			return
 		}
 
-		token := []byte(h[7:])
-		if subtle.ConstantTimeCompare(token, secretBytes) != 1 {
+		identity, ok := creds.Authenticate(h[7:])
+		if !ok {
 			logger.Warnf("Service auth failed: invalid token from %s", c.ClientIP())
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid service token"})
+			respondCode(c, http.StatusUnauthorized, "service_token_invalid", "auth.service_token_invalid")
+			c.Abort()
 			return
 		}
 
-		// Сохраняем имя сервиса в контексте для использования в хендлерах
-		c.Set("service", serviceName)
+		// Сохраняем воркера в контексте для использования в хендлерах
+		c.Set("service", identity.ServiceName)
+		c.Set("service_credential_id", identity.CredentialID)
+		c.Set("service_identity", identity)
 
 		// Логируем успешную аутентификацию сервиса
-		logger.Infof("Service authenticated: %s from %s -> %s %s", serviceName, c.ClientIP(), c.Request.Method, c.Request.URL.Path)
+		logger.Infof("Service authenticated: %s (credential %d) from %s -> %s %s",
+			identity.ServiceName, identity.CredentialID, c.ClientIP(), c.Request.Method, c.Request.URL.Path)
+
+		c.Next()
+	}
+}
 
+// RequireServiceScope пропускает запрос, только если ключ воркера содержит scope.
+// Должен использоваться после ServiceBearerAuthMiddleware.
+func RequireServiceScope(scope servicecreds.Scope) gin.HandlerFunc {
+	logger := logging.GetLogger()
+
+	return func(c *gin.Context) {
+		value, _ := c.Get("service_identity")
+		identity, ok := value.(servicecreds.Identity)
+		if !ok || !identity.HasScope(scope) {
+			logger.Warnf("Service %s denied: scope %s required for %s %s",
+				identity.ServiceName, scope, c.Request.Method, c.Request.URL.Path)
+			locale := requestLocale(c)
+			body := localizedError(locale, "insufficient_scope", "auth.insufficient_scope")
+			body.Details = []ErrorDetail{{Rule: "scope", Value: string(scope), Message: i18n.T(locale, "auth.scope_detail", scope)}}
+			c.Abort()
+			writeError(c, http.StatusForbidden, body)
+			return
+		}
 		c.Next()
 	}
 }
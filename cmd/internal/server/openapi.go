@@ -0,0 +1,1167 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/auth"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/baseline"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/report"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/servicecreds"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/tender"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/buildinfo"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/openapi"
+)
+
+// Ответы, которые хендлеры собирают через gin.H. Описаны только для спецификации;
+// при изменении gin.H в хендлере их нужно поправить вместе с ним.
+type (
+	openAPIMessage struct {
+		Message string `json:"message"`
+	}
+	openAPIStatus struct {
+		Status string `json:"status"`
+	}
+	openAPIUser struct {
+		ID             int64  `json:"id"`
+		Email          string `json:"email"`
+		Role           string `json:"role"`
+		OrganizationID int64  `json:"organization_id"`
+	}
+	openAPILoginResponse struct {
+		User openAPIUser `json:"user"`
+	}
+	openAPIMeResponse struct {
+		User struct {
+			openAPIUser
+			Permissions []auth.Permission `json:"permissions"`
+		} `json:"user"`
+		// Только под имперсонацией
+		Impersonation *struct {
+			SessionID     int64     `json:"session_id"`
+			ActingAdminID int64     `json:"acting_admin_id"`
+			ExpiresAt     time.Time `json:"expires_at"`
+		} `json:"impersonation,omitempty"`
+	}
+	openAPIChangePasswordResponse struct {
+		Message         string `json:"message"`
+		RevokedSessions int64  `json:"revoked_sessions"`
+	}
+	openAPILogoutAllResponse struct {
+		Message         string `json:"message"`
+		RevokedSessions int64  `json:"revoked_sessions"`
+	}
+	openAPIEndImpersonationResponse struct {
+		Message       string `json:"message"`
+		SessionID     int64  `json:"session_id"`
+		ActingAdminID int64  `json:"acting_admin_id"`
+	}
+	openAPIStatsResponse struct {
+		TendersCount int64  `json:"tenders_count"`
+		Message      string `json:"message"`
+	}
+	openAPIAIResultsResponse struct {
+		Message            string `json:"message"`
+		LotID              string `json:"lot_id"`
+		AIAnalysisResultID int64  `json:"ai_analysis_result_id"`
+		UpdatedAt          string `json:"updated_at"`
+	}
+	openAPICatalogIndexedResponse struct {
+		Status       string                              `json:"status"`
+		IndexedCount int                                 `json:"indexed_count"`
+		Report       *api_models.CatalogActivationReport `json:"report"`
+	}
+	openAPIRejectMergeResponse struct {
+		Status  string `json:"status"`
+		MergeID int64  `json:"merge_id"`
+	}
+)
+
+func pageParams(defaultPageSize int) []openapi.Param {
+	return []openapi.Param{
+		{Name: "page", Default: 1},
+		{Name: "page_size", Default: defaultPageSize},
+	}
+}
+
+func limitOffsetParams(defaultLimit int) []openapi.Param {
+	return []openapi.Param{
+		{Name: "limit", Default: defaultLimit},
+		{Name: "offset", Default: 0},
+	}
+}
+
+var (
+	includeDeletedParam = openapi.Param{
+		Name: "include_deleted", Type: "boolean", Default: false,
+		Description: "Учитывать мягко удалённые тендеры (право tenders:manage_deleted)",
+	}
+	dryRunParam = openapi.Param{
+		Name: "dry_run", Type: "boolean", Default: false,
+		Description: "Только проверить и вернуть отчёт, ничего не записывая",
+	}
+	cursorParam = openapi.Param{
+		Name: "cursor", Type: "string",
+		Description: "Keyset-пагинация: next_cursor предыдущей страницы, пустое значение — первая страница. Несовместим с page/offset",
+	}
+	csvDelimiterParam = openapi.Param{
+		Name: "delimiter", Type: "string", Enum: []string{"comma", "semicolon"}, Default: "comma",
+	}
+)
+
+// tenderFilterParams — фильтры и сортировка списка тендеров (см. parseTenderListQuery).
+func tenderFilterParams() []openapi.Param {
+	return []openapi.Param{
+		{Name: "category_id"},
+		{Name: "chapter_id"},
+		{Name: "type_id"},
+		{Name: "executor_id"},
+		{Name: "object_id"},
+		{Name: "date_from", Type: "string", Format: "date"},
+		{Name: "date_to", Type: "string", Format: "date"},
+		{Name: "has_winner", Type: "boolean"},
+		{Name: "tag_ids", Type: "string", Description: "ID меток через запятую (до 20): тендер должен иметь все"},
+		{Name: "sort_by", Type: "string", Enum: []string{"date", "title", "proposals_count", "total_cost"}, Default: "date"},
+		{Name: "sort_order", Type: "string", Enum: []string{"asc", "desc"}, Default: "desc"},
+		includeDeletedParam,
+	}
+}
+
+// APIRoutes описывает все маршруты сервера для спецификации OpenAPI.
+// Регистрация маршрута в NewServer без описания здесь (и наоборот) ломает
+// TestOpenAPI_DescribesAllRoutes.
+func APIRoutes() []openapi.Route {
+	const (
+		v1       = "/api/v1"
+		admin    = "/api/v1/admin"
+		internal = "/internal/worker"
+	)
+	user := func(route openapi.Route) openapi.Route {
+		route.Auth = openapi.AuthUser
+		return route
+	}
+	withPermission := func(p auth.Permission, route openapi.Route) openapi.Route {
+		route = user(route)
+		route.Permission = string(p)
+		return route
+	}
+	worker := func(scope servicecreds.Scope, route openapi.Route) openapi.Route {
+		route.Tag = "worker"
+		route.Auth = openapi.AuthService
+		route.Permission = string(scope)
+		return route
+	}
+
+	return []openapi.Route{
+		// --- Служебные ---
+		{Method: http.MethodGet, Path: "/healthz", Tag: "health", Summary: "Проба живости", Response: api_models.HealthResponse{}},
+		{
+			Method: http.MethodGet, Path: "/readyz", Tag: "health", Summary: "Проба готовности",
+			Description: "503 с тем же телом, если одна из проверок не прошла",
+			Response:    api_models.ReadinessResponse{},
+		},
+		{Method: http.MethodGet, Path: "/home", Tag: "health", Summary: "Приветствие API", Response: openAPIMessage{}},
+		{Method: http.MethodGet, Path: "/api/stats", Tag: "health", Summary: "Число тендеров", Response: openAPIStatsResponse{}},
+		{Method: http.MethodGet, Path: v1 + "/openapi.json", Tag: "health", Summary: "Спецификация OpenAPI этого API", Response: map[string]any{}},
+
+		// --- Внутренние сервисы (Python-воркеры) ---
+		worker(servicecreds.ScopeImport, openapi.Route{
+			Method: http.MethodPost, Path: internal + "/import-tender", Summary: "Импорт тендера",
+			Description: "С dry_run=true возвращает 200 и ImportValidationReport, ничего не записывая",
+			Query: []openapi.Param{
+				dryRunParam,
+				{Name: "task_id", Type: "string", Description: "Задача парсера, загрузившая файл: её пользователь получает import.progress в GET /api/v1/events"},
+			},
+			Request: api_models.FullTenderData{}, Status: http.StatusCreated, Response: api_models.ImportTenderResponse{},
+		}),
+		worker(servicecreds.ScopeImport, openapi.Route{
+			Method: http.MethodPost, Path: internal + "/tasks/:task_id/status", Summary: "Статус задачи загрузки от парсера",
+			Description: "completed и failed — итоговые: после них опрос парсера запись не меняет",
+			PathParams:  []openapi.Param{{Name: "task_id", Type: "string"}},
+			Request:     api_models.ParseTaskStatusPush{}, Response: api_models.ParseTask{},
+		}),
+		worker(servicecreds.ScopeAIResults, openapi.Route{
+			Method: http.MethodPost, Path: internal + "/lots/:lot_id/ai-results", Summary: "Результаты AI-анализа лота",
+			PathParams: []openapi.Param{{Name: "lot_id", Type: "string"}},
+			Request:    api_models.SimpleLotAIResult{}, Response: openAPIAIResultsResponse{},
+		}),
+		worker(servicecreds.ScopeRAG, openapi.Route{
+			Method: http.MethodGet, Path: internal + "/positions/unmatched", Summary: "Позиции без сопоставления с каталогом",
+			Query: []openapi.Param{
+				{Name: "limit", Default: 100},
+				{Name: "after_id"},
+				{Name: "tender_id"},
+				{Name: "created_after", Type: "string", Format: "date-time"},
+			},
+			Response: []api_models.UnmatchedPositionResponse{},
+		}),
+		worker(servicecreds.ScopeRAG, openapi.Route{
+			Method: http.MethodPost, Path: internal + "/positions/claim", Summary: "Захват порции позиций воркером",
+			Request: api_models.ClaimUnmatchedPositionsRequest{}, Response: api_models.ClaimUnmatchedPositionsResponse{},
+		}),
+		worker(servicecreds.ScopeRAG, openapi.Route{
+			Method: http.MethodPost, Path: internal + "/positions/match", Summary: "Сопоставление позиции с каталогом",
+			Request: api_models.MatchPositionRequest{}, Response: openAPIStatus{},
+		}),
+		worker(servicecreds.ScopeRAG, openapi.Route{
+			Method: http.MethodPost, Path: internal + "/positions/match-batch", Summary: "Пакетное сопоставление позиций",
+			Request: api_models.MatchPositionsBatchRequest{}, Response: api_models.MatchPositionsBatchResponse{},
+		}),
+		worker(servicecreds.ScopeRAG, openapi.Route{
+			Method: http.MethodGet, Path: internal + "/matching/corrections", Summary: "Ручные исправления сопоставлений",
+			Description: "Обучающие примеры для RAG в порядке id: название позиции КП, ошибочная и правильная позиции каталога. Следующая страница — after_id=next_after_id",
+			Query: []openapi.Param{
+				{Name: "after_id"},
+				{Name: "limit", Default: 500},
+			},
+			Response: api_models.MatchingCorrectionsPage{},
+		}),
+		worker(servicecreds.ScopeRAG, openapi.Route{
+			Method: http.MethodGet, Path: internal + "/catalog/unindexed", Summary: "Позиции каталога, ожидающие индексации",
+			Query:    []openapi.Param{{Name: "limit", Default: 1000}},
+			Response: []api_models.UnmatchedPositionResponse{},
+		}),
+		worker(servicecreds.ScopeRAG, openapi.Route{
+			Method: http.MethodPost, Path: internal + "/catalog/indexed", Summary: "Отметка позиций каталога проиндексированными",
+			Request: api_models.CatalogIndexedRequest{}, Response: openAPICatalogIndexedResponse{},
+		}),
+		worker(servicecreds.ScopeRAG, openapi.Route{
+			Method: http.MethodPost, Path: internal + "/merges/suggest", Summary: "Предложение слияния дубликатов каталога",
+			Request: api_models.SuggestMergeRequest{}, Status: http.StatusCreated, Response: openAPIStatus{},
+		}),
+		worker(servicecreds.ScopeRAG, openapi.Route{
+			Method: http.MethodGet, Path: internal + "/catalog/active", Summary: "Активные позиции каталога",
+			Description: "С параметром cursor вместо массива возвращается страница {items, next_cursor} в порядке id",
+			Query:       append(limitOffsetParams(1000), cursorParam),
+			Response:    []api_models.UnmatchedPositionResponse{},
+		}),
+		worker(servicecreds.ScopeRAG, openapi.Route{
+			Method: http.MethodGet, Path: internal + "/norm-version", Summary: "Текущая версия нормализации",
+			Response: api_models.NormVersionResponse{},
+		}),
+
+		// --- Аутентификация ---
+		{
+			Method: http.MethodPost, Path: v1 + "/auth/login", Tag: "auth", Summary: "Вход",
+			Description: "Устанавливает cookies с access- и refresh-токенами и csrf_token",
+			Request:     LoginRequest{}, Response: openAPILoginResponse{},
+		},
+		{Method: http.MethodPost, Path: v1 + "/auth/refresh", Tag: "auth", Summary: "Обновление токенов по refresh-cookie", Response: openAPIMessage{}},
+		{Method: http.MethodPost, Path: v1 + "/auth/logout", Tag: "auth", Summary: "Выход", Response: openAPIMessage{}},
+		{
+			Method: http.MethodPost, Path: v1 + "/auth/reset-password", Tag: "auth", Summary: "Сброс пароля по одноразовому токену",
+			Request: api_models.ResetPasswordRequest{}, Response: openAPIMessage{},
+		},
+		{
+			Method: http.MethodGet, Path: v1 + "/auth/oidc/login", Tag: "auth", Summary: "Вход через SSO (OIDC)",
+			Description: "Перенаправляет на страницу входа провайдера и ставит cookie oidc_state; 404, если auth.oidc выключен",
+			Query: []openapi.Param{
+				{Name: "return_to", Type: "string", Description: "Относительный путь фронтенда, куда вернуться после входа"},
+			},
+			Status: http.StatusFound,
+		},
+		{
+			Method: http.MethodGet, Path: v1 + "/auth/oidc/callback", Tag: "auth", Summary: "Возврат от провайдера SSO",
+			Description: "Обменивает код на id_token, находит, привязывает по подтверждённому email или создает пользователя, " +
+				"синхронизирует роль по группам IdP, устанавливает cookies как вход и перенаправляет на auth.oidc.post_login_url",
+			Query: []openapi.Param{
+				{Name: "code", Type: "string"},
+				{Name: "state", Type: "string"},
+				{Name: "error", Type: "string", Description: "Ошибка провайдера (например, access_denied)"},
+			},
+			Status: http.StatusFound,
+		},
+		user(openapi.Route{Method: http.MethodGet, Path: v1 + "/auth/me", Tag: "auth", Summary: "Текущий пользователь и его права", Response: openAPIMeResponse{}}),
+		user(openapi.Route{
+			Method: http.MethodPost, Path: v1 + "/auth/change-password", Tag: "auth", Summary: "Смена собственного пароля",
+			Request: api_models.ChangePasswordRequest{}, Response: openAPIChangePasswordResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodPost, Path: v1 + "/auth/logout-all", Tag: "auth", Summary: "Выход на всех устройствах",
+			Description: "Завершает все сессии и отзывает выданные access-токены, включая текущий; cookies очищаются",
+			Response:    openAPILogoutAllResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodPost, Path: v1 + "/auth/impersonation/end", Tag: "auth", Summary: "Завершение имперсонации",
+			Description: "Завершает сессию имперсонации и удаляет access cookie; сессия администратора восстанавливается через /auth/refresh",
+			Response:    openAPIEndImpersonationResponse{},
+		}),
+
+		// --- Лента уведомлений ---
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/notifications", Tag: "notifications", Summary: "Лента уведомлений текущего пользователя",
+			Query: append([]openapi.Param{
+				{Name: "unread_only", Type: "boolean", Default: false, Description: "Только непрочитанные"},
+			}, limitOffsetParams(20)...),
+			Response: api_models.NotificationsResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/notifications/unread-count", Tag: "notifications", Summary: "Число непрочитанных уведомлений",
+			Response: api_models.NotificationUnreadCountResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodPost, Path: v1 + "/notifications/:id/read", Tag: "notifications", Summary: "Отметка уведомления прочитанным",
+			Response: api_models.Notification{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/events", Tag: "notifications", Summary: "Поток событий пользователя (Server-Sent Events)",
+			Description: "События parse_task.status (ParseTask), import.progress (task_id, tender_id, lots_done, lots_total) и lot.ai_analyzed " +
+				"(лоты тендеров организации); комментарий-heartbeat раз в live.heartbeat_interval. 429 — открыто live.max_connections_per_user потоков",
+			Response: "", ResponseContentType: "text/event-stream",
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/me/followed-tenders", Tag: "notifications", Summary: "Тендеры, за которыми следит пользователь",
+			Query: limitOffsetParams(20), Response: api_models.FollowedTendersResponse{},
+		}),
+
+		// --- Загрузка тендеров ---
+		withPermission(auth.PermissionTendersWrite, openapi.Route{
+			Method: http.MethodPost, Path: v1 + "/upload-tender", Tag: "tenders", Summary: "Загрузка файла тендера в парсер",
+			Description:        "Только XLSX не больше upload.max_file_size (413/415); ответ парсера передаётся клиенту без изменений",
+			RequestContentType: "multipart/form-data",
+			Form: []openapi.Param{
+				{Name: "file", Type: "file", Required: true},
+				{Name: "enable_ai", Type: "boolean", Default: false},
+			},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/tasks", Tag: "tenders", Summary: "Последние загрузки пользователя",
+			Query: limitOffsetParams(20), Response: api_models.ParseTasksResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/tasks/:task_id/status", Tag: "tenders", Summary: "Статус задачи парсера",
+			Description: "Ответ парсера передаётся клиенту без изменений; если парсер задачу не знает или недоступен, либо сам сообщил итог — ParseTask из parse_tasks",
+			PathParams:  []openapi.Param{{Name: "task_id", Type: "string"}},
+		}),
+		withPermission(auth.PermissionTendersWrite, openapi.Route{
+			Method: http.MethodPost, Path: v1 + "/tenders/fetch", Tag: "tenders", Summary: "Получение тендера с ЭТП",
+			Description: "Парсер скачивает тендер по etp_id и импортирует его в организацию пользователя. " +
+				"Тендер уже импортирован — 409 tender_already_exists (повтор — force=true); " +
+				"незавершённая задача того же etp_id возвращается с deduplicated=true; 404 — тендер не найден на ЭТП",
+			Request: api_models.TenderFetchRequest{}, Status: http.StatusAccepted, Response: api_models.TenderFetchResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/tenders/fetch/:task_id", Tag: "tenders", Summary: "Статус получения тендера с ЭТП",
+			Description: "Пока итог не известен, статус запрашивается у парсера; stored=true — ответ по записи parse_tasks",
+			PathParams:  []openapi.Param{{Name: "task_id", Type: "string"}},
+			Response:    api_models.ParseTask{},
+		}),
+
+		// --- Тендеры ---
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/tenders", Tag: "tenders", Summary: "Список тендеров",
+			Description: "С параметром cursor — keyset-пагинация от новых к старым (created_at, id): sort_by и page не передаются, в ответе next_cursor",
+			Query:       append(append(pageParams(20), cursorParam), tenderFilterParams()...),
+			Response:    listTendersPageResponse{},
+		}),
+		withPermission(auth.PermissionAnalyticsRead, openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/tenders/export.csv", Tag: "tenders", Summary: "Выгрузка списка тендеров в CSV",
+			Query:    append(tenderFilterParams(), csvDelimiterParam),
+			Response: "", ResponseContentType: "text/csv",
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/tenders/:id", Tag: "tenders", Summary: "Тендер с лотами и предложениями",
+			Description: "Отдаёт ETag; запрос с If-None-Match по неизменившимся данным получает 304",
+			Query: []openapi.Param{
+				{Name: "limit", Default: 100},
+				{Name: "offset", Default: 0},
+				includeDeletedParam,
+			},
+			Response: tenderPageResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/tenders/:id/proposals", Tag: "proposals", Summary: "Предложения тендера",
+			Query: pageParams(20), Response: []proposalResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodPost, Path: v1 + "/tenders/:id/follow", Tag: "notifications", Summary: "Следить за тендером",
+			Response: api_models.TenderFollowResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodDelete, Path: v1 + "/tenders/:id/follow", Tag: "notifications", Summary: "Перестать следить за тендером",
+			Response: api_models.TenderFollowResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/me/saved-filters", Tag: "tenders", Summary: "Сохранённые фильтры списка тендеров",
+			Response: api_models.SavedFiltersResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodPost, Path: v1 + "/me/saved-filters", Tag: "tenders", Summary: "Сохранение фильтра списка тендеров",
+			Description: "filters — параметры GET /api/v1/tenders (кроме page и cursor), проверяются по тем же правилам; имя уникально у пользователя (409), не больше 50 фильтров",
+			Request:     api_models.CreateSavedFilterRequest{}, Status: http.StatusCreated, Response: api_models.SavedFilter{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodDelete, Path: v1 + "/me/saved-filters/:id", Tag: "tenders", Summary: "Удаление сохранённого фильтра",
+			Status: http.StatusNoContent,
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/tags", Tag: "tenders", Summary: "Метки тендеров организации",
+			Response: api_models.TagsResponse{},
+		}),
+		withPermission(auth.PermissionTendersWrite, openapi.Route{
+			Method: http.MethodPost, Path: v1 + "/tags", Tag: "tenders", Summary: "Создание метки",
+			Description: "Метка создаётся в организации пользователя; название уникально без учёта регистра (409)",
+			Request:     api_models.CreateTagRequest{}, Status: http.StatusCreated, Response: api_models.Tag{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/tenders/:id/tags", Tag: "tenders", Summary: "Метки тендера",
+			Response: api_models.TenderTagsResponse{},
+		}),
+		withPermission(auth.PermissionTendersWrite, openapi.Route{
+			Method: http.MethodPut, Path: v1 + "/tenders/:id/tags/:tagId", Tag: "tenders", Summary: "Повесить метку на тендер",
+			Description: "Повторная привязка не ошибка; метка другой организации — 404",
+			Response:    api_models.TenderTagsResponse{},
+		}),
+		withPermission(auth.PermissionTendersWrite, openapi.Route{
+			Method: http.MethodDelete, Path: v1 + "/tenders/:id/tags/:tagId", Tag: "tenders", Summary: "Снять метку с тендера",
+			Response: api_models.TenderTagsResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/tenders/:id/syncs", Tag: "tenders", Summary: "Обновления тендера с ЭТП по расписанию",
+			Description: "Последние первыми: версии исходного JSON до и после, новые предложения и позиции с изменённой стоимостью",
+			Query:       limitOffsetParams(20), Response: api_models.TenderSyncsResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/tenders/:id/linked", Tag: "tenders", Summary: "Тот же тендер под другими etp_id",
+			Description: "Пары вероятных дубликатов, подтверждённые администратором",
+			Response:    api_models.LinkedTendersResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/tenders/:id/history", Tag: "tenders", Summary: "История правок тендера",
+			Description: "Изменённые поля с прежним и новым значением, автором и временем; новые первыми",
+			Query:       limitOffsetParams(50), Response: api_models.FieldHistoryResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/lots/:id/history", Tag: "lots", Summary: "История правок лота",
+			Description: "Ключевые параметры по ключам (lot_key_parameters.<ключ>), включая применение запуска AI-анализа; новые первыми",
+			Query:       limitOffsetParams(50), Response: api_models.FieldHistoryResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/proposals/:id/details", Tag: "proposals", Summary: "Предложение с позициями",
+			Description: "Отдаёт ETag; запрос с If-None-Match по неизменившимся данным получает 304",
+			Response:    ProposalFullDetailsResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/proposals/:id/positions", Tag: "proposals", Summary: "Строки предложения постранично",
+			Description: "view=flat — строки подряд, как в /details; view=tree — непосредственные дочерние строки главы chapter (без chapter — корня), у глав children_count",
+			Query: append([]openapi.Param{
+				{Name: "view", Type: "string", Enum: []string{"flat", "tree"}, Default: "flat"},
+				{Name: "chapter", Type: "string", Description: "Номер главы (item_number), чьи строки запрошены; только с view=tree"},
+			}, limitOffsetParams(100)...),
+			Response: ProposalPositionsResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/proposals/:id/consistency", Tag: "proposals", Summary: "Сверка итогов предложения с суммой позиций",
+			Response: api_models.ProposalConsistencyReport{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/proposals/:id/coverage", Tag: "proposals", Summary: "Полнота предложения относительно baseline лота",
+			Description: "Позиции baseline без цены подрядчика (missing), с нулевой ценой (zero_price), с изменённым объёмом (quantity_changed) и лишние позиции (extra)",
+			Response:    api_models.ProposalCoverageReport{},
+		}),
+		withPermission(auth.PermissionTendersWrite, openapi.Route{
+			Method: http.MethodPatch, Path: v1 + "/tenders/:id", Tag: "tenders", Summary: "Изменение тендера",
+			Request: patchTenderRequest{}, Response: db.Tender{},
+		}),
+		withPermission(auth.PermissionTendersWrite, openapi.Route{
+			Method: http.MethodDelete, Path: v1 + "/tenders/:id", Tag: "tenders", Summary: "Мягкое удаление тендера",
+			Response: api_models.TenderArchiveStatusResponse{},
+		}),
+		withPermission(auth.PermissionTendersManageDeleted, openapi.Route{
+			Method: http.MethodPost, Path: v1 + "/tenders/:id/restore", Tag: "tenders", Summary: "Восстановление мягко удалённого тендера",
+			Response: api_models.TenderArchiveStatusResponse{},
+		}),
+		withPermission(auth.PermissionImportsInspect, openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/tenders/:id/imports", Tag: "imports", Summary: "История импортов тендера",
+			Response: api_models.TenderImportsResponse{},
+		}),
+		withPermission(auth.PermissionImportsInspect, openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/tenders/:id/imports/diff", Tag: "imports", Summary: "Сравнение двух версий импорта",
+			Query:    []openapi.Param{{Name: "from", Format: "int32", Required: true}, {Name: "to", Format: "int32", Required: true}},
+			Response: api_models.TenderImportDiff{},
+		}),
+		withPermission(auth.PermissionImportsInspect, openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/tenders/:id/imports/:version/raw", Tag: "imports", Summary: "Исходный JSON версии импорта",
+			PathParams: []openapi.Param{{Name: "version", Format: "int32"}},
+			Response:   json.RawMessage{},
+		}),
+
+		// --- Лоты ---
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/lots/:id/proposals", Tag: "lots", Summary: "Предложения лота",
+			Query: pageParams(20), Response: []proposalResponse{},
+		}),
+		withPermission(auth.PermissionTendersWrite, openapi.Route{
+			Method: http.MethodPatch, Path: v1 + "/lots/:id/key-parameters", Tag: "lots", Summary: "Ключевые параметры лота",
+			Request: patchLotKeyParametersRequest{}, Response: db.Lot{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/lots/:id/ai-results", Tag: "lots", Summary: "Запуски AI-анализа лота",
+			Description: "Новые первыми; is_current — запуск, параметры которого применены к лоту последними",
+			Response:    api_models.AIAnalysisRunsResponse{},
+		}),
+		withPermission(auth.PermissionTendersWrite, openapi.Route{
+			Method: http.MethodPost, Path: v1 + "/lots/:id/ai-results/:runId/promote", Tag: "lots", Summary: "Применить параметры запуска AI-анализа к лоту",
+			Response: api_models.AIAnalysisRun{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/lots/:id/comparison", Tag: "lots", Summary: "Сравнение предложений лота",
+			Description: "Отдаёт ETag; запрос с If-None-Match по неизменившимся данным получает 304",
+			Response:    api_models.LotComparisonResponse{},
+		}),
+		withPermission(auth.PermissionAnalyticsRead, openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/lots/:id/analytics", Tag: "lots", Summary: "Аналитика стоимости лота",
+			Response: api_models.LotAnalyticsResponse{},
+		}),
+		withPermission(auth.PermissionAnalyticsRead, openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/lots/:id/analytics/work-groups", Tag: "lots", Summary: "Стоимость лота по видам работ",
+			Description: "Суммы предложений свёрнуты по классификатору видов работ в базовой валюте; позиции без узла — в unclassified",
+			Response:    api_models.LotWorkGroupCostsResponse{},
+		}),
+		withPermission(auth.PermissionAnalyticsRead, openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/lots/:id/anomalies", Tag: "lots", Summary: "Подозрительные цены предложений",
+			Description: "Цена за единицу сравнивается с медианой других подрядчиков и со средней ценой каталога; " +
+				"порог — по категории тендера (anomalies.category_thresholds), critical — порог × critical_factor",
+			Response: api_models.LotAnomaliesResponse{},
+		}),
+		withPermission(auth.PermissionTendersWrite, openapi.Route{
+			Method: http.MethodPost, Path: v1 + "/lots/:id/baseline/estimate", Tag: "lots", Summary: "Оценочный baseline по ценам каталога",
+			Description: "Цена за единицу — средняя цена сопоставленной позиции каталога за окно, в базовой валюте. " +
+				"Перечень работ — из baseline или из предложения с наибольшим числом позиций; baseline с ценами не перезаписывается (409)",
+			Query: []openapi.Param{
+				{Name: "dry_run", Type: "boolean", Default: false, Description: "Только рассчитать, ничего не сохраняя"},
+				{Name: "window_months", Type: "integer", Default: baseline.DefaultWindowMonths, Description: "Окно истории цен в месяцах, 1..120"},
+			},
+			Response: api_models.BaselineEstimateResponse{},
+		}),
+		withPermission(auth.PermissionAnalyticsRead, openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/analytics/lots/compare", Tag: "lots", Summary: "Удельные цены по ключевому параметру лотов категории",
+			Description: "Цена победителя лота делится на значение ключевого параметра (число или строка вида \"1 200 м²\"); " +
+				"лоты без удельной цены перечислены со skip_reason. Параметр, запрещённый схемой категории или не числовой по ней, — 400",
+			Query: []openapi.Param{
+				{Name: "category_id", Type: "integer", Format: "int64", Required: true},
+				{Name: "parameter", Type: "string", Required: true, Description: "Имя ключевого параметра в lot_key_parameters"},
+			},
+			Response: api_models.LotParameterBenchmarkResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/lots/:id/attachments", Tag: "lots", Summary: "Документы лота",
+			Description: "Сформированные API документы лота без содержимого, новые первыми",
+			Response:    api_models.LotAttachmentsResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/lots/:id/attachments/:attachmentId", Tag: "lots", Summary: "Скачивание документа лота",
+			Description: "Файл с Content-Disposition: attachment; тип — content_type документа",
+			Response:    "", ResponseContentType: "application/octet-stream",
+		}),
+
+		// --- Подрядчики ---
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/contractors", Tag: "contractors", Summary: "Список подрядчиков",
+			Query:    append([]openapi.Param{{Name: "search", Type: "string", Description: "Наименование или начало ИНН"}}, pageParams(20)...),
+			Response: api_models.ListContractorsResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/contractors/expiring-accreditation", Tag: "contractors", Summary: "Истекающие аккредитации подрядчиков",
+			Description: "Действующие аккредитации со сроком в ближайшие within_days дней и уже истекшие, ближайший срок первым",
+			Query: append([]openapi.Param{
+				{Name: "within_days", Default: 30, Description: "Окно в днях (0..365), по умолчанию contractors.accreditation_warn_days"},
+			}, pageParams(20)...),
+			Response: api_models.ExpiringAccreditationsResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/contractors/:id", Tag: "contractors", Summary: "Карточка подрядчика",
+			Response: api_models.ContractorResponse{},
+		}),
+		withPermission(auth.PermissionAnalyticsRead, openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/contractors/:id/stats", Tag: "contractors", Summary: "Статистика участия подрядчика",
+			Query:    []openapi.Param{{Name: "recent", Default: 10, Description: "Число последних предложений"}},
+			Response: api_models.ContractorStatsResponse{},
+		}),
+
+		// --- Каталог ---
+		withPermission(auth.PermissionAnalyticsRead, openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/catalog/export.csv", Tag: "catalog", Summary: "Выгрузка каталога в CSV",
+			Query: []openapi.Param{
+				{Name: "kind", Type: "string", Enum: []string{"POSITION", "HEADER", "LOT_HEADER", "TRASH"}},
+				{Name: "status", Type: "string", Enum: []string{"pending_indexing", "active", "deprecated", "archived"}},
+				{Name: "pinned", Type: "boolean"},
+				{Name: "parent_id"},
+				csvDelimiterParam,
+			},
+			Response: "", ResponseContentType: "text/csv",
+		}),
+		withPermission(auth.PermissionAnalyticsRead, openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/catalog/:id/price-history", Tag: "catalog", Summary: "История цен позиции каталога",
+			Description: "normalize=true — цены дополнительно пересчитываются по индексам цен к index_region и index_date",
+			Query: []openapi.Param{
+				{Name: "normalize", Type: "boolean", Description: "Пересчитать цены по индексам цен"},
+				{Name: "index_region", Type: "string", Description: "Целевой регион, по умолчанию RU"},
+				{Name: "index_date", Type: "string", Format: "date", Description: "Целевая дата, по умолчанию сегодня"},
+			},
+			Response: api_models.CatalogPriceHistoryResponse{},
+		}),
+		withPermission(auth.PermissionAnalyticsRead, openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/catalog/work-groups", Tag: "catalog", Summary: "Классификатор видов работ",
+			Response: api_models.WorkGroupTreeResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/positions/search", Tag: "catalog", Summary: "Поиск позиций КП во всех тендерах",
+			Description: "Подстрока или нечёткое совпадение названия (pg_trgm), самые похожие первыми; цены — за единицу в валюте позиции. С параметром cursor вместо page — keyset-пагинация в том же порядке, в ответе next_cursor",
+			Query: append([]openapi.Param{
+				{Name: "q", Type: "string", Required: true, Description: "Название позиции, от 3 символов"},
+				{Name: "catalog_id", Format: "int64"},
+				{Name: "min_cost", Type: "number"},
+				{Name: "max_cost", Type: "number"},
+			}, append(pageParams(20), cursorParam)...),
+			Response: api_models.PositionSearchResponse{},
+		}),
+		withPermission(auth.PermissionMatchingCorrect, openapi.Route{
+			Method: http.MethodPatch, Path: v1 + "/positions/:id/catalog-match", Tag: "catalog", Summary: "Ручное исправление сопоставления позиции",
+			Description: "Позиция КП получает выбранную позицию каталога (воркеры её больше не перезаписывают), matching_cache — запись source=manual для следующих импортов, исправление попадает в GET /internal/worker/matching/corrections",
+			Request:     api_models.CorrectPositionMatchRequest{}, Response: api_models.MatchingCorrectionResponse{},
+		}),
+		withPermission(auth.PermissionMatchingCorrect, openapi.Route{
+			Method: http.MethodPost, Path: v1 + "/positions/:id/unmatch", Tag: "catalog", Summary: "Отмена сопоставления позиции",
+			Description: "Снимает catalog_position_id, закрепление и аренду — позиция снова в очереди несопоставленных; запись matching_cache, по которой позиция была сопоставлена, удаляется",
+			Response:    api_models.UnmatchPositionResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/search", Tag: "search", Summary: "Глобальный поиск",
+			Description: "Тендеры (название, начало etp_id), подрядчики (наименование, начало ИНН), объекты (название, адрес) и позиции каталога; группы в порядке tender, contractor, object, catalog_position, в каждой — первые limit совпадений и total",
+			Query: []openapi.Param{
+				{Name: "q", Type: "string", Required: true, Description: "Строка поиска, от 2 символов"},
+				{Name: "types", Type: "string", Description: "Группы через запятую: tender, contractor, object, catalog_position; по умолчанию все"},
+				{Name: "limit", Default: 5, Description: "Совпадений в группе, до 20"},
+			},
+			Response: api_models.SearchResponse{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/dashboard", Tag: "dashboard", Summary: "Сводка главной страницы",
+			Description: "Тендеры по состоянию, импорты за 30 дней по дням, очередь сопоставления с каталогом, ожидающие предложения слияния, " +
+				"категории с наибольшим объёмом победителей и последние импорты; по тендерам организации пользователя. " +
+				"Сводка кэшируется на dashboard.cache_ttl, время расчёта — generated_at",
+			Response: api_models.DashboardResponse{},
+		}),
+		withPermission(auth.PermissionAnalyticsRead, openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/reports/savings", Tag: "reports", Summary: "Отчет об экономии",
+			Description: "Baseline против цены победителя по тендерам периода, итоги по категориям и месяцам; суммы в базовой валюте",
+			Query: []openapi.Param{
+				{Name: "from", Type: "string", Format: "date"},
+				{Name: "to", Type: "string", Format: "date", Description: "Включительно"},
+				{Name: "category_id", Format: "int64"},
+			},
+			Response: api_models.SavingsReportResponse{},
+		}),
+
+		// --- Победители ---
+		withPermission(auth.PermissionWinnersManage, openapi.Route{
+			Method: http.MethodPost, Path: v1 + "/lots/:lotId/winners", Tag: "winners", Summary: "Назначение победителя лота",
+			Request: createWinnerRequest{}, Status: http.StatusCreated, Response: db.CreateWinnerRow{},
+		}),
+		withPermission(auth.PermissionWinnersManage, openapi.Route{
+			Method: http.MethodPatch, Path: v1 + "/winners/:winnerId", Tag: "winners", Summary: "Изменение победителя",
+			Request: updateWinnerRequest{}, Response: db.Winner{},
+		}),
+		withPermission(auth.PermissionWinnersManage, openapi.Route{
+			Method: http.MethodDelete, Path: v1 + "/winners/:winnerId", Tag: "winners", Summary: "Удаление победителя",
+			Response: db.Winner{},
+		}),
+		withPermission(auth.PermissionWinnersManage, openapi.Route{
+			Method: http.MethodPost, Path: v1 + "/lots/:id/winner-protocol", Tag: "winners", Summary: "Протокол выбора победителя",
+			Description: "Формирует протокол (тендер, лот, победители по месту, цены и экономия от baseline) и сохраняет его документом лота; " +
+				"лот без победителей — 409, pdf без reports.pdf_converter_url — 400",
+			Query: []openapi.Param{
+				{Name: "format", Type: "string", Enum: []string{report.FormatDOCX, report.FormatPDF}, Default: report.FormatDOCX},
+			},
+			Status: http.StatusCreated, Response: api_models.LotAttachment{},
+		}),
+
+		// --- Справочники ---
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/tender-types", Tag: "reference", Summary: "Типы тендеров",
+			Query: pageParams(20), Response: []db.TenderType{},
+		}),
+		withPermission(auth.PermissionReferenceManage, openapi.Route{
+			Method: http.MethodPost, Path: v1 + "/tender-types", Tag: "reference", Summary: "Создание типа тендера",
+			Request: createTenderTypeRequest{}, Status: http.StatusCreated, Response: db.TenderType{},
+		}),
+		withPermission(auth.PermissionReferenceManage, openapi.Route{
+			Method: http.MethodPut, Path: v1 + "/tender-types/:id", Tag: "reference", Summary: "Изменение типа тендера",
+			Request: updateTenderTypeRequest{}, Response: db.TenderType{},
+		}),
+		withPermission(auth.PermissionReferenceManage, openapi.Route{
+			Method: http.MethodDelete, Path: v1 + "/tender-types/:id", Tag: "reference", Summary: "Удаление типа тендера",
+			Status: http.StatusNoContent,
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/tender-types/:type_id/chapters", Tag: "reference", Summary: "Разделы типа тендера",
+			Query: pageParams(100), Response: []db.TenderChapter{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/tender-chapters", Tag: "reference", Summary: "Разделы тендеров",
+			Query: pageParams(20), Response: []db.ListTenderChaptersRow{},
+		}),
+		withPermission(auth.PermissionReferenceManage, openapi.Route{
+			Method: http.MethodPost, Path: v1 + "/tender-chapters", Tag: "reference", Summary: "Создание раздела",
+			Request: createTenderChapterRequest{}, Status: http.StatusCreated, Response: db.TenderChapter{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/tender-chapters/:chapter_id/categories", Tag: "reference", Summary: "Категории раздела",
+			Query: pageParams(100), Response: []db.TenderCategory{},
+		}),
+		withPermission(auth.PermissionReferenceManage, openapi.Route{
+			Method: http.MethodPut, Path: v1 + "/tender-chapters/:id", Tag: "reference", Summary: "Изменение раздела",
+			Request: updateTenderChapterRequest{}, Response: db.TenderChapter{},
+		}),
+		withPermission(auth.PermissionReferenceManage, openapi.Route{
+			Method: http.MethodDelete, Path: v1 + "/tender-chapters/:id", Tag: "reference", Summary: "Удаление раздела",
+			Status: http.StatusNoContent,
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/tender-categories", Tag: "reference", Summary: "Категории тендеров",
+			Query: pageParams(100), Response: []db.ListTenderCategoriesRow{},
+		}),
+		withPermission(auth.PermissionReferenceManage, openapi.Route{
+			Method: http.MethodPost, Path: v1 + "/tender-categories", Tag: "reference", Summary: "Создание категории",
+			Request: tenderCategoryRequest{}, Status: http.StatusCreated, Response: db.TenderCategory{},
+		}),
+		withPermission(auth.PermissionReferenceManage, openapi.Route{
+			Method: http.MethodPut, Path: v1 + "/tender-categories/:id", Tag: "reference", Summary: "Изменение категории",
+			Request: tenderCategoryRequest{}, Response: db.TenderCategory{},
+		}),
+		withPermission(auth.PermissionReferenceManage, openapi.Route{
+			Method: http.MethodDelete, Path: v1 + "/tender-categories/:id", Tag: "reference", Summary: "Удаление категории",
+			Status: http.StatusNoContent,
+		}),
+		withPermission(auth.PermissionReferenceManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/reference/import", Tag: "reference", Summary: "Загрузка справочников из xlsx/CSV",
+			Description: "Столбцы kind (type, chapter, category, unit_alias), title, parent; файл применяется целиком в одной транзакции. " +
+				"Ошибки в строках — 400 с details по строкам (при dry_run — отчёт 200). Строки unit_alias требуют права catalog:manage",
+			RequestContentType: "multipart/form-data",
+			Form:               []openapi.Param{{Name: "file", Type: "file", Required: true}},
+			Query:              []openapi.Param{dryRunParam},
+			Response:           api_models.ReferenceImportReport{},
+		}),
+		user(openapi.Route{
+			Method: http.MethodGet, Path: v1 + "/price-indices", Tag: "reference", Summary: "Индексы цен по регионам и месяцам",
+			Query:    []openapi.Param{{Name: "region", Type: "string", Description: "Код региона; RU — общероссийский ряд"}},
+			Response: api_models.ListPriceIndicesResponse{},
+		}),
+		withPermission(auth.PermissionReferenceManage, openapi.Route{
+			Method: http.MethodPost, Path: v1 + "/price-indices", Tag: "reference", Summary: "Добавление индекса цен",
+			Request: api_models.PriceIndexRequest{}, Status: http.StatusCreated, Response: api_models.PriceIndex{},
+		}),
+		withPermission(auth.PermissionReferenceManage, openapi.Route{
+			Method: http.MethodPut, Path: v1 + "/price-indices/:id", Tag: "reference", Summary: "Изменение индекса цен",
+			Request: api_models.PriceIndexRequest{}, Response: api_models.PriceIndex{},
+		}),
+		withPermission(auth.PermissionReferenceManage, openapi.Route{
+			Method: http.MethodDelete, Path: v1 + "/price-indices/:id", Tag: "reference", Summary: "Удаление индекса цен",
+			Status: http.StatusNoContent,
+		}),
+
+		// --- Администрирование: пользователи ---
+		withPermission(auth.PermissionUsersManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/users", Tag: "admin", Summary: "Список пользователей (не реализован)",
+			Status: http.StatusNotImplemented,
+		}),
+		withPermission(auth.PermissionUsersManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/users", Tag: "admin", Summary: "Создание пользователя",
+			Request: api_models.CreateUserRequest{}, Status: http.StatusCreated, Response: api_models.AdminUserResponse{},
+		}),
+		withPermission(auth.PermissionUsersManage, openapi.Route{
+			Method: http.MethodPatch, Path: admin + "/users/:id", Tag: "admin", Summary: "Изменение пользователя",
+			Request: api_models.UpdateUserRequest{}, Response: api_models.AdminUserResponse{},
+		}),
+		withPermission(auth.PermissionUsersManage, openapi.Route{
+			Method: http.MethodDelete, Path: admin + "/users/:id", Tag: "admin", Summary: "Удаление пользователя",
+			Response: api_models.AdminUserResponse{},
+		}),
+		withPermission(auth.PermissionUsersManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/users/:id/reset-password", Tag: "admin", Summary: "Выдача токена сброса пароля",
+			Status: http.StatusCreated, Response: api_models.PasswordResetTokenResponse{},
+		}),
+		withPermission(auth.PermissionUsersManage, openapi.Route{
+			Method: http.MethodPatch, Path: admin + "/users/:id/role", Tag: "admin", Summary: "Смена роли пользователя",
+			Request: api_models.UpdateUserRoleRequest{}, Response: api_models.AdminUserResponse{},
+		}),
+		withPermission(auth.PermissionUsersManage, openapi.Route{
+			Method: http.MethodPatch, Path: admin + "/users/:id/status", Tag: "admin", Summary: "Блокировка и разблокировка пользователя",
+			Request: api_models.UpdateUserStatusRequest{}, Response: api_models.AdminUserResponse{},
+		}),
+		withPermission(auth.PermissionUsersManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/users/:id/impersonate", Tag: "admin", Summary: "Вход от имени пользователя",
+			Description: "Access cookie заменяется токеном пользователя с acting_admin_id на auth.impersonation_ttl; " +
+				"запросы пишутся в журнал, ответы помечаются заголовком X-Impersonated",
+			Status: http.StatusCreated, Response: api_models.ImpersonationResponse{},
+		}),
+
+		// --- Администрирование: организации ---
+		withPermission(auth.PermissionOrganizationsManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/organizations", Tag: "admin", Summary: "Список организаций",
+			Response: []api_models.OrganizationResponse{},
+		}),
+		withPermission(auth.PermissionOrganizationsManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/organizations", Tag: "admin", Summary: "Создание организации",
+			Request: api_models.CreateOrganizationRequest{}, Status: http.StatusCreated, Response: api_models.OrganizationResponse{},
+		}),
+
+		// --- Администрирование: система ---
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/settings", Tag: "admin", Summary: "Системные настройки",
+			Response: []api_models.SystemSettingResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/settings/:key", Tag: "admin", Summary: "Системная настройка",
+			PathParams: []openapi.Param{{Name: "key", Type: "string"}},
+			Response:   api_models.SystemSettingResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodPut, Path: admin + "/settings", Tag: "admin", Summary: "Изменение системной настройки",
+			Request: api_models.UpdateSystemSettingRequest{}, Response: api_models.SystemSettingResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/service-credentials", Tag: "admin", Summary: "Ключи внутренних сервисов",
+			Response: []api_models.ServiceCredentialResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/service-credentials", Tag: "admin", Summary: "Выпуск ключа сервиса",
+			Request: api_models.CreateServiceCredentialRequest{}, Status: http.StatusCreated, Response: api_models.CreateServiceCredentialResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodDelete, Path: admin + "/service-credentials/:id", Tag: "admin", Summary: "Отзыв ключа сервиса",
+			Response: api_models.ServiceCredentialResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/service-credentials/:id/rotate", Tag: "admin", Summary: "Ротация ключа сервиса",
+			Status: http.StatusCreated, Response: api_models.CreateServiceCredentialResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/webhooks", Tag: "admin", Summary: "Подписки на вебхуки",
+			Response: []api_models.WebhookResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/webhooks", Tag: "admin", Summary: "Создание подписки",
+			Request: api_models.CreateWebhookRequest{}, Status: http.StatusCreated, Response: api_models.CreateWebhookResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodPatch, Path: admin + "/webhooks/:id", Tag: "admin", Summary: "Изменение подписки",
+			Request: api_models.UpdateWebhookRequest{}, Response: api_models.WebhookResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodDelete, Path: admin + "/webhooks/:id", Tag: "admin", Summary: "Удаление подписки с журналом",
+			Status: http.StatusNoContent,
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/webhooks/:id/deliveries", Tag: "admin", Summary: "Журнал доставок подписки",
+			Query: append([]openapi.Param{
+				{Name: "status", Type: "string", Enum: []string{"pending", "delivered", "failed"}},
+			}, limitOffsetParams(50)...),
+			Response: api_models.WebhookDeliveriesResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/reports/schedules", Tag: "admin", Summary: "Регулярные отчеты",
+			Response: []api_models.ScheduledReportResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/reports/schedules", Tag: "admin", Summary: "Создание регулярного отчета",
+			Description: "Отчет (savings, new_tenders, unmatched_positions) в XLSX или PDF рассылается по почте за закончившийся день, неделю или месяц",
+			Request:     api_models.CreateScheduledReportRequest{}, Status: http.StatusCreated, Response: api_models.ScheduledReportResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodPatch, Path: admin + "/reports/schedules/:id", Tag: "admin", Summary: "Изменение регулярного отчета",
+			Request: api_models.UpdateScheduledReportRequest{}, Response: api_models.ScheduledReportResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodDelete, Path: admin + "/reports/schedules/:id", Tag: "admin", Summary: "Удаление регулярного отчета с историей",
+			Status: http.StatusNoContent,
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/reports/schedules/:id/runs", Tag: "admin", Summary: "История формирования отчета",
+			Query: limitOffsetParams(50), Response: api_models.ReportRunsResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/jobs", Tag: "admin", Summary: "Фоновые задачи",
+			Response: api_models.JobsResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/retention", Tag: "admin", Summary: "Политики хранения данных",
+			Response: api_models.RetentionPoliciesResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/retention/runs", Tag: "admin", Summary: "Журнал запусков политик хранения",
+			Query: append([]openapi.Param{
+				{Name: "policy", Type: "string", Enum: config.RetentionPolicies, Description: "Только запуски этой политики"},
+			}, limitOffsetParams(50)...),
+			Response: api_models.RetentionRunsResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/retention/:policy/run", Tag: "admin", Summary: "Запуск политики хранения",
+			Description: "Удаляет не больше retention.batch_size строк; dry_run=true (или политика в retention.dry_run) — только подсчёт. Запуск записывается в журнал",
+			PathParams:  []openapi.Param{{Name: "policy", Type: "string", Enum: config.RetentionPolicies}},
+			Query:       []openapi.Param{{Name: "dry_run", Type: "boolean", Default: false, Description: "Только посчитать подходящие строки"}},
+			Response:    api_models.RetentionRun{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/cache/stats", Tag: "admin", Summary: "Статистика кэша справочников",
+			Response: api_models.RefCacheStatsResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/outbound/stats", Tag: "admin", Summary: "Circuit breaker и счётчики запросов к парсеру",
+			Response: api_models.OutboundHTTPStatsResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/key-parameter-schemas", Tag: "admin", Summary: "Схемы ключевых параметров лота",
+			Response: []api_models.LotKeyParameterSchemaResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/key-parameter-schemas/:categoryId", Tag: "admin", Summary: "Схема ключевых параметров категории",
+			Response: api_models.LotKeyParameterSchemaResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodPut, Path: admin + "/key-parameter-schemas/:categoryId", Tag: "admin", Summary: "Задание схемы ключевых параметров категории",
+			Description: "Тело — JSON Schema: type, properties, required, additionalProperties, items, enum, minimum, maximum, minLength, maxLength, pattern",
+			Request:     json.RawMessage{}, Response: api_models.LotKeyParameterSchemaResponse{},
+		}),
+		withPermission(auth.PermissionSystemManage, openapi.Route{
+			Method: http.MethodDelete, Path: admin + "/key-parameter-schemas/:categoryId", Tag: "admin", Summary: "Удаление схемы ключевых параметров категории",
+			Status: http.StatusNoContent,
+		}),
+
+		// --- Администрирование: каталог ---
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/suggested_merges", Tag: "catalog", Summary: "Предложения слияния (устаревший путь)",
+			Query: pageParams(100), Response: api_models.ListSuggestedMergesResponse{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/merges", Tag: "catalog", Summary: "Предложения слияния",
+			Query: pageParams(100), Response: api_models.ListSuggestedMergesResponse{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/merges/execute-batch", Tag: "catalog", Summary: "Пакетное слияние",
+			Request: api_models.ExecuteBatchMergeRequest{}, Response: api_models.ExecuteBatchMergeResponse{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/merges/group-batch", Tag: "catalog", Summary: "Пакетная группировка",
+			Request: api_models.GroupBatchPositionsRequest{}, Response: api_models.GroupBatchPositionsResponse{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/merges/:id/execute", Tag: "catalog", Summary: "Слияние позиций",
+			Request: api_models.ExecuteMergeRequest{}, Response: api_models.ExecuteMergeResponse{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/merges/:id/group", Tag: "catalog", Summary: "Группировка позиций",
+			Request: api_models.GroupPositionsRequest{}, Response: api_models.GroupPositionsResponse{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/merges/:id/approve", Tag: "catalog", Summary: "Одобрение слияния",
+			Response: api_models.ApproveMergeResponse{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/merges/:id/reject", Tag: "catalog", Summary: "Отклонение слияния",
+			Response: openAPIRejectMergeResponse{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodPatch, Path: admin + "/merges/:id/reject", Tag: "catalog", Summary: "Отклонение слияния (устаревший метод)",
+			Response: openAPIRejectMergeResponse{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/matching/stats", Tag: "catalog", Summary: "Метрики качества матчинга",
+			Description: "Hit rate matching_cache при импортах (за период и по неделям), доли позиций по способу сопоставления, средняя схожесть принятых слияний, перцентили возраста очереди несопоставленных",
+			Query: []openapi.Param{
+				{Name: "from", Type: "string", Format: "date", Description: "По умолчанию — to минус 30 дней"},
+				{Name: "to", Type: "string", Format: "date", Description: "Включительно, по умолчанию — сегодня"},
+			},
+			Response: api_models.MatchingStatsResponse{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/catalog/groups", Tag: "catalog", Summary: "Группы каталога",
+			Query: limitOffsetParams(50), Response: api_models.ListGroupsResponse{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/catalog/groups/:id/children", Tag: "catalog", Summary: "Позиции группы",
+			Response: api_models.ListGroupChildrenResponse{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/catalog/positions/:id/ungroup", Tag: "catalog", Summary: "Исключение позиции из группы",
+			Status: http.StatusNoContent,
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/catalog/pinned", Tag: "catalog", Summary: "Закреплённые позиции каталога",
+			Response: api_models.ListPinnedCatalogPositionsResponse{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodPatch, Path: admin + "/catalog/positions/:id/pin", Tag: "catalog", Summary: "Закрепление позиции каталога",
+			Request: api_models.SetCatalogPositionPinnedRequest{}, Response: api_models.CatalogPositionSummary{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/catalog/positions/:id/deprecate", Tag: "catalog", Summary: "Вывод позиции каталога из оборота",
+			Description: "Позиция получает status=deprecated и replaced_by_id; сопоставления и matching_cache уходят на замену, уже сопоставленные позиции тендеров не меняются",
+			Request:     api_models.DeprecateCatalogPositionRequest{}, Response: api_models.DeprecateCatalogPositionResponse{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodPut, Path: admin + "/catalog/positions/:id/work-group", Tag: "catalog", Summary: "Привязка позиции каталога к виду работ",
+			Request: api_models.SetCatalogPositionWorkGroupRequest{}, Response: api_models.CatalogPositionSummary{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/work-groups", Tag: "catalog", Summary: "Создание узла классификатора видов работ",
+			Request: api_models.WorkGroupRequest{}, Status: http.StatusCreated, Response: db.WorkGroup{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodPut, Path: admin + "/work-groups/:id", Tag: "catalog", Summary: "Изменение узла классификатора видов работ",
+			Request: api_models.WorkGroupRequest{}, Response: db.WorkGroup{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodDelete, Path: admin + "/work-groups/:id", Tag: "catalog", Summary: "Удаление узла классификатора видов работ",
+			Status: http.StatusNoContent,
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/catalog/activate", Tag: "catalog", Summary: "Массовая активация позиций каталога",
+			Query:   []openapi.Param{dryRunParam},
+			Request: api_models.CatalogIndexedRequest{}, Response: api_models.CatalogActivationReport{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/catalog/norm-version", Tag: "catalog", Summary: "Версия нормализации",
+			Response: api_models.NormVersionResponse{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/catalog/norm-version/bump", Tag: "catalog", Summary: "Запуск перенормализации каталога",
+			Status: http.StatusAccepted, Response: api_models.CatalogRenormalizationRunResponse{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/units", Tag: "units", Summary: "Справочник единиц измерения",
+			Response: api_models.ListUnitsResponse{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/units", Tag: "units", Summary: "Создание единицы измерения",
+			Request: api_models.CreateUnitRequest{}, Status: http.StatusCreated, Response: api_models.UnitOfMeasurementAdmin{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/units/merge", Tag: "units", Summary: "Слияние единиц измерения",
+			Request: api_models.MergeUnitsRequest{}, Response: api_models.MergeUnitsResponse{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/units/:id/aliases", Tag: "units", Summary: "Добавление синонима",
+			Request: api_models.CreateUnitAliasRequest{}, Status: http.StatusCreated, Response: api_models.UnitAlias{},
+		}),
+		withPermission(auth.PermissionCatalogManage, openapi.Route{
+			Method: http.MethodDelete, Path: admin + "/units/:id/aliases/:aliasId", Tag: "units", Summary: "Удаление синонима",
+			Status: http.StatusNoContent,
+		}),
+
+		// --- Администрирование: подрядчики и импорты ---
+		withPermission(auth.PermissionContractorsManage, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/contractors/duplicates", Tag: "contractors", Summary: "Подрядчики с одинаковым ИНН",
+			Response: api_models.ContractorDuplicatesResponse{},
+		}),
+		withPermission(auth.PermissionContractorsManage, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/contractors/merge", Tag: "contractors", Summary: "Слияние подрядчиков",
+			Request: api_models.MergeContractorsRequest{}, Response: api_models.MergeContractorsResponse{},
+		}),
+		withPermission(auth.PermissionContractorsManage, openapi.Route{
+			Method: http.MethodPut, Path: admin + "/contractors/:id/accreditation", Tag: "contractors", Summary: "Аккредитация подрядчика",
+			Description: "Статус, срок и документы заменяются целиком; импорт их больше не меняет",
+			Request:     api_models.UpdateAccreditationRequest{}, Response: api_models.ContractorResponse{},
+		}),
+		withPermission(auth.PermissionImportsInspect, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/imports/:id/trace", Tag: "imports", Summary: "Трассировка импорта",
+			Response: api_models.ImportTraceResponse{},
+		}),
+		withPermission(auth.PermissionImportsInspect, openapi.Route{
+			Method: http.MethodGet, Path: admin + "/tender-duplicates", Tag: "imports", Summary: "Очередь вероятных дубликатов тендеров",
+			Description: "Пары, найденные после импорта по наименованию, объекту и составу позиций; ожидающие проверки первыми",
+			Query: append([]openapi.Param{
+				{Name: "status", Type: "string", Enum: []string{tender.DuplicatePending, tender.DuplicateLinked, tender.DuplicateIgnored}},
+			}, limitOffsetParams(50)...),
+			Response: api_models.TenderDuplicatesResponse{},
+		}),
+		withPermission(auth.PermissionImportsInspect, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/tender-duplicates/:id/link", Tag: "imports", Summary: "Подтвердить дубликат",
+			Description: "Пара — один тендер под разными etp_id; решённую пару изменить нельзя (409)",
+			Response:    api_models.TenderDuplicateStatusResponse{},
+		}),
+		withPermission(auth.PermissionImportsInspect, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/tender-duplicates/:id/ignore", Tag: "imports", Summary: "Отклонить дубликат",
+			Description: "Пара — разные тендеры и больше не предлагается; решённую пару изменить нельзя (409)",
+			Response:    api_models.TenderDuplicateStatusResponse{},
+		}),
+		withPermission(auth.PermissionTendersManageDeleted, openapi.Route{
+			Method: http.MethodPost, Path: admin + "/tenders/:id/purge", Tag: "tenders", Summary: "Безвозвратное удаление тендера",
+			Description: "Удаляет тендер и все зависимые строки в одной транзакции; dry_run=true — только число строк по таблицам",
+			Query:       []openapi.Param{dryRunParam},
+			Response:    api_models.TenderPurgeResponse{},
+		}),
+	}
+}
+
+// buildOpenAPISpec собирает JSON спецификации. Вызывается один раз при создании сервера.
+func buildOpenAPISpec(cfg *config.Config) ([]byte, error) {
+	doc, err := openapi.Build(openapi.Spec{
+		Info: openapi.Info{
+			Title:       "Tenders API",
+			Version:     buildinfo.Get().Version,
+			Description: "API тендеров: фронтенд (/api/v1, cookie-сессия) и внутренние воркеры (/internal/worker, ключ сервиса).",
+		},
+		AccessCookie: cfg.Auth.CookieAccessName,
+		Routes:       APIRoutes(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+// getOpenAPISpecHandler обрабатывает GET /api/v1/openapi.json.
+func (s *Server) getOpenAPISpecHandler(c *gin.Context) {
+	if s.openAPISpec == nil {
+		respondInternal(c, fmt.Errorf("спецификация API недоступна"))
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", s.openAPISpec)
+}
+
+// swaggerUIPage — Swagger UI с CDN. Запросы идут с cookie сессии, а для
+// изменяющих методов requestInterceptor копирует csrf_token в X-CSRF-Token,
+// как это делает фронтенд.
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="ru">
+<head>
+  <meta charset="utf-8">
+  <title>Tenders API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    function csrfToken() {
+      var match = document.cookie.match(/(?:^|;\s*)csrf_token=([^;]*)/);
+      return match ? decodeURIComponent(match[1]) : "";
+    }
+    window.ui = SwaggerUIBundle({
+      url: "openapi.json",
+      dom_id: "#swagger-ui",
+      withCredentials: true,
+      requestInterceptor: function (req) {
+        if (req.method && req.method.toUpperCase() !== "GET") {
+          req.headers["X-CSRF-Token"] = csrfToken();
+        }
+        return req;
+      }
+    });
+  </script>
+</body>
+</html>
+`
+
+// swaggerUIHandler обрабатывает GET /api/v1/docs (только в режиме отладки).
+func (s *Server) swaggerUIHandler(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR OPENAPI SPECIFICATION (Integration: NewServer router vs route descriptors)
+
+What user problems does this protect us from?
+================================================================================
+1. Stale documentation — a route added to NewServer without a descriptor (or removed
+   from NewServer but still documented) fails the build instead of silently drifting
+2. Broken endpoint — the specification is served as valid JSON without authentication
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Every registered route is described
+- GIVEN the router built by NewServer
+  WHEN its routes are compared with APIRoutes()
+  THEN both sets of "METHOD path" are equal (Swagger UI page excluded)
+
+SCENARIO 2: Specification endpoint
+- GIVEN a server built by NewServer
+  WHEN GET /api/v1/openapi.json is called without cookies
+  THEN 200 with an OpenAPI 3 document containing /api/v1/tenders/{id}
+*/
+
+func newOpenAPITestServer(t *testing.T) *Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	return NewServer(db.NewMockStore(ctrl), testutil.NewMockLogger(),
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, testConfig())
+}
+
+func TestOpenAPI_DescribesAllRoutes(t *testing.T) {
+	server := newOpenAPITestServer(t)
+
+	var registered []string
+	for _, r := range server.router.Routes() {
+		if r.Path == "/api/v1/docs" {
+			continue
+		}
+		registered = append(registered, r.Method+" "+r.Path)
+	}
+	var described []string
+	for _, r := range APIRoutes() {
+		described = append(described, r.Method+" "+r.Path)
+	}
+	sort.Strings(registered)
+	sort.Strings(described)
+
+	assert.Equal(t, registered, described)
+}
+
+func TestOpenAPI_SpecEndpoint(t *testing.T) {
+	server := newOpenAPITestServer(t)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil)
+	server.router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var doc struct {
+		OpenAPI string                     `json:"openapi"`
+		Paths   map[string]json.RawMessage `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+	assert.Contains(t, doc.Paths, "/api/v1/tenders/{id}")
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"net/url"
+	"strconv"
+	"strings"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+)
+
+const (
+	proposalPositionsDefaultLimit = 100
+	proposalPositionsMaxLimit     = 1000
+
+	// proposalPositionsViewFlat - строки КП подряд, главы вперемешку с позициями.
+	proposalPositionsViewFlat = "flat"
+	// proposalPositionsViewTree - непосредственные дочерние строки одной главы
+	// (chapter) или корня; главы догружаются отдельными запросами.
+	proposalPositionsViewTree = "tree"
+)
+
+// proposalPositionsQuery - разобранные параметры GET /api/v1/proposals/:id/positions.
+// Отвечает только за валидацию и сборку параметров sqlc, без обращения к БД.
+type proposalPositionsQuery struct {
+	ProposalID int64
+	View       string
+	// Chapter - номер главы (item_number_in_proposal), чьи строки запрошены;
+	// пустой - корень дерева. Только для view=tree.
+	Chapter string
+	Limit   int32
+	Offset  int32
+}
+
+// parseProposalPositionsQuery разбирает и валидирует query string списка позиций предложения.
+func parseProposalPositionsQuery(proposalID int64, values url.Values) (proposalPositionsQuery, error) {
+	q := proposalPositionsQuery{
+		ProposalID: proposalID,
+		View:       proposalPositionsViewFlat,
+		Limit:      proposalPositionsDefaultLimit,
+	}
+
+	if v := values.Get("view"); v != "" {
+		if v != proposalPositionsViewFlat && v != proposalPositionsViewTree {
+			return q, i18n.Errorf("request.invalid_view", proposalPositionsViewFlat, proposalPositionsViewTree)
+		}
+		q.View = v
+	}
+
+	q.Chapter = strings.TrimSpace(values.Get("chapter"))
+	if q.Chapter != "" && q.View != proposalPositionsViewTree {
+		return q, i18n.Errorf("request.chapter_requires_view", proposalPositionsViewTree)
+	}
+
+	if v := values.Get("limit"); v != "" {
+		limit, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || limit < 1 || limit > proposalPositionsMaxLimit {
+			return q, i18n.Errorf("request.invalid_limit_range", proposalPositionsMaxLimit)
+		}
+		q.Limit = int32(limit)
+	}
+
+	if v := values.Get("offset"); v != "" {
+		offset, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || offset < 0 {
+			return q, i18n.Errorf("request.offset_non_negative")
+		}
+		q.Offset = int32(offset)
+	}
+
+	return q, nil
+}
+
+func (q proposalPositionsQuery) listParams() db.ListProposalPositionsPageParams {
+	return db.ListProposalPositionsPageParams{
+		ProposalID: q.ProposalID,
+		ByParent:   q.View == proposalPositionsViewTree,
+		ParentRef:  q.Chapter,
+		PageLimit:  q.Limit,
+		PageOffset: q.Offset,
+	}
+}
+
+func (q proposalPositionsQuery) countParams() db.CountProposalPositionsParams {
+	return db.CountProposalPositionsParams{
+		ProposalID: q.ProposalID,
+		ByParent:   q.View == proposalPositionsViewTree,
+		ParentRef:  q.Chapter,
+	}
+}
@@ -0,0 +1,88 @@
+// Purpose: Protects the query-builder layer of GET /api/v1/proposals/:id/positions.
+// Ensures that defaults, pagination bounds and the view/chapter combination are
+// translated into sqlc parameters correctly, and that invalid input is rejected
+// before any DB call.
+package server
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+BEHAVIORAL SCENARIOS:
+
+Given an empty query string
+When parseProposalPositionsQuery is called
+Then view=flat, limit=100, offset=0 and the list is not filtered by parent
+
+Given view=tree without chapter
+When parseProposalPositionsQuery is called
+Then the list and count params select the root of the chapter tree
+
+Given view=tree with a chapter number padded by spaces
+When parseProposalPositionsQuery is called
+Then the chapter is trimmed and becomes parent_ref of list and count params
+
+Given chapter without view=tree, an unknown view or limit/offset out of bounds
+When parseProposalPositionsQuery is called
+Then an error is returned
+*/
+
+func TestParseProposalPositionsQuery_Defaults(t *testing.T) {
+	q, err := parseProposalPositionsQuery(7, url.Values{})
+
+	require.NoError(t, err)
+	assert.Equal(t, proposalPositionsViewFlat, q.View)
+
+	params := q.listParams()
+	assert.Equal(t, int64(7), params.ProposalID)
+	assert.False(t, params.ByParent)
+	assert.Equal(t, int32(100), params.PageLimit)
+	assert.Equal(t, int32(0), params.PageOffset)
+	assert.False(t, q.countParams().ByParent)
+}
+
+func TestParseProposalPositionsQuery_TreeRoot(t *testing.T) {
+	q, err := parseProposalPositionsQuery(7, url.Values{"view": {"tree"}, "limit": {"50"}, "offset": {"100"}})
+
+	require.NoError(t, err)
+	params := q.listParams()
+	assert.True(t, params.ByParent)
+	assert.Equal(t, "", params.ParentRef)
+	assert.Equal(t, int32(50), params.PageLimit)
+	assert.Equal(t, int32(100), params.PageOffset)
+
+	count := q.countParams()
+	assert.True(t, count.ByParent)
+	assert.Equal(t, "", count.ParentRef)
+}
+
+func TestParseProposalPositionsQuery_TreeChapter(t *testing.T) {
+	q, err := parseProposalPositionsQuery(7, url.Values{"view": {"tree"}, "chapter": {" 2.1 "}})
+
+	require.NoError(t, err)
+	assert.Equal(t, "2.1", q.Chapter)
+	assert.Equal(t, "2.1", q.listParams().ParentRef)
+	assert.Equal(t, "2.1", q.countParams().ParentRef)
+}
+
+func TestParseProposalPositionsQuery_Invalid(t *testing.T) {
+	cases := map[string]url.Values{
+		"chapter without tree": {"chapter": {"1"}},
+		"unknown view":         {"view": {"nested"}},
+		"zero limit":           {"limit": {"0"}},
+		"limit above max":      {"limit": {"1001"}},
+		"negative offset":      {"offset": {"-1"}},
+		"non-numeric limit":    {"limit": {"abc"}},
+	}
+	for name, values := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseProposalPositionsQuery(7, values)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"golang.org/x/time/rate"
+)
+
+// ApplyReloadable применяет настройки, перечитанные по SIGHUP: origins CORS
+// (вне режима отладки), лимит запросов к /internal/worker и лимиты
+// пользователей /api/v1. Остальные секции cfg игнорируются — они применяются
+// только при перезапуске.
+func (s *Server) ApplyReloadable(cfg *config.Config) {
+	s.setCORSOrigins(cfg.CORS.AllowedOrigins)
+	s.serviceLimiter.SetLimit(rate.Limit(cfg.RateLimit.ServiceRPS))
+	s.serviceLimiter.SetBurst(cfg.RateLimit.ServiceBurst)
+	if err := s.rateLimiter.Apply(cfg.RateLimit); err != nil {
+		s.logger.Errorf("SIGHUP: лимиты запросов API не применены: %v", err)
+	}
+}
+
+func (s *Server) setCORSOrigins(origins []string) {
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		allowed[strings.TrimSuffix(origin, "/")] = true
+	}
+	s.corsOrigins.Store(&allowed)
+}
+
+// allowCORSOrigin — AllowOriginFunc для cors: сравнение с текущим списком
+// cors.allowed_origins.
+func (s *Server) allowCORSOrigin(origin string) bool {
+	return (*s.corsOrigins.Load())[origin]
+}
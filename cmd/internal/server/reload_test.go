@@ -0,0 +1,76 @@
+package server_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil/servertest"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR SIGHUP RELOAD (servertest harness)
+
+What user problems does this protect us from?
+================================================================================
+1. Restarting the API (and dropping in-flight imports) just to add a frontend origin
+2. A noisy worker that can only be throttled by a redeploy
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: CORS origins
+- GIVEN production mode with allowed_origins [https://a.example.com]
+  WHEN a preflight comes from https://b.example.com THEN it is not allowed
+- WHEN ApplyReloadable adds https://b.example.com/ (trailing slash)
+  THEN the same preflight is allowed and https://a.example.com no longer is
+
+SCENARIO 2: Worker rate limit
+- GIVEN ApplyReloadable lowers the burst to 1
+  WHEN a worker sends two requests at once THEN the second gets 429
+*/
+
+func preflight(h *servertest.Harness, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/tenders", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	w := httptest.NewRecorder()
+	h.Handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestReload_CORSOrigins(t *testing.T) {
+	h := servertest.New(t, func(cfg *config.Config) {
+		cfg.CORS.AllowedOrigins = []string{"https://a.example.com"}
+	})
+
+	assert.Empty(t, preflight(h, "https://b.example.com").Header().Get("Access-Control-Allow-Origin"))
+
+	next := servertest.Config()
+	next.CORS.AllowedOrigins = []string{"https://b.example.com/"}
+	h.Server.ApplyReloadable(next)
+
+	assert.Equal(t, "https://b.example.com", preflight(h, "https://b.example.com").Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, preflight(h, "https://a.example.com").Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestReload_WorkerRateLimit(t *testing.T) {
+	h := servertest.New(t)
+
+	next := servertest.Config()
+	next.RateLimit = config.RateLimitConfig{ServiceRPS: 0.001, ServiceBurst: 1}
+	h.Server.ApplyReloadable(next)
+
+	h.Store.EXPECT().GetActiveNormVersion(gomock.Any()).Return(int16(0), errors.New("db down"))
+
+	first := h.DoWorker(t, http.MethodGet, "/internal/worker/norm-version", nil)
+	second := h.DoWorker(t, http.MethodGet, "/internal/worker/norm-version", nil)
+
+	assert.Equal(t, http.StatusInternalServerError, first.Code)
+	assert.Equal(t, http.StatusTooManyRequests, second.Code)
+}
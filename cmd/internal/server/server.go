@@ -2,19 +2,48 @@ package server
 
 import (
 	"net/http"
-	"time"
+	"sync/atomic"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
 	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/analytics"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/anomalies"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/auth"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/baseline"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/catalog"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/consistency"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/contractor"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/currency"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/dashboard"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/etp"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/feed"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/health"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/history"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/importer"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/live"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/lot"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/matching"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/outbound"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/parsetask"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/priceindex"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/ratelimit"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/refcache"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/refimport"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/report"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/retention"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/scheduler"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/search"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/servicecreds"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/settings"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/tender"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/units"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/webhooks"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/workgroups"
 	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+	"golang.org/x/time/rate"
 )
 
 type Server struct {
@@ -27,8 +56,41 @@ type Server struct {
 	lotService      *lot.LotService
 	matchingService *matching.MatchingService
 	settingsService *settings.SettingsService
-	httpClient      *http.Client
+	tenderArchive   *tender.TenderService
+	history         *history.HistoryService // История правок тендеров и лотов
+	analytics       *analytics.AnalyticsService
+	anomalies       *anomalies.AnomalyService
+	reports         *report.ReportService
+	feed            *feed.FeedService
+	parseTasks      *parsetask.ParseTaskService
+	etpFetch        *etp.FetchService // Получение тендеров с ЭТП через парсер
+	etpSync         *etp.SyncService  // Обновление открытых тендеров по расписанию
+	contractors     *contractor.ContractorService
+	search          *search.SearchService
+	dashboard       *dashboard.DashboardService
+	consistency     *consistency.ConsistencyService
+	units           *units.UnitService
+	refImport       *refimport.ImportService // Загрузка справочников из xlsx/CSV
+	workGroups      *workgroups.WorkGroupService
+	priceIndices    *priceindex.PriceIndexService
+	baselines       *baseline.EstimateService
+	retention       *retention.Service // Политики хранения данных
+	serviceCreds    *servicecreds.Service
+	webhooks        *webhooks.Service
+	events          events.Publisher
+	scheduler       *scheduler.Scheduler
+	health          *health.Checker
+	refCache        *refcache.Service
+	live            *live.Hub        // Поток событий пользователей (GET /api/v1/events)
+	httpClient      *outbound.Client // Запросы к парсеру: повторы и circuit breaker
 	config          *config.Config
+	etagSalt        string // Общая часть ETag: сборка и курсы валют (см. http_cache.go)
+	openAPISpec     []byte // Спецификация OpenAPI, собранная при старте (см. openapi.go)
+
+	// Перечитываются по SIGHUP (см. reload.go)
+	corsOrigins    atomic.Pointer[map[string]bool]
+	serviceLimiter *rate.Limiter
+	rateLimiter    *ratelimit.Limiter // Лимиты пользователей /api/v1 (rate_limit.driver)
 }
 
 func NewServer(
@@ -38,15 +100,39 @@ func NewServer(
 	catalogService *catalog.CatalogService,
 	lotService *lot.LotService,
 	matchingService *matching.MatchingService,
+	serviceCreds *servicecreds.Service,
+	webhookService *webhooks.Service,
+	reportService *report.ReportService,
+	feedService *feed.FeedService,
+	eventPublisher events.Publisher,
+	jobScheduler *scheduler.Scheduler,
+	authService *auth.Service,
+	healthChecker *health.Checker,
+	refCache *refcache.Service,
+	rateLimiter *ratelimit.Limiter,
+	liveHub *live.Hub,
+	parserClient *outbound.Client,
+	etpFetch *etp.FetchService,
+	etpSync *etp.SyncService,
+	retentionService *retention.Service,
 	cfg *config.Config,
 ) *Server {
-	httpClient := &http.Client{
-		Timeout: time.Minute * 5,
-	}
-
-	authService := auth.NewService(store, cfg, logger)
-
 	settingsService := settings.NewSettingsService(store, logger)
+	tenderArchive := tender.NewTenderService(store, logger)
+	historyService := history.NewHistoryService(store, logger)
+	rates := currency.NewRates(cfg.Currency)
+	analyticsService := analytics.NewAnalyticsService(store, logger, rates)
+	anomalyService := anomalies.NewAnomalyService(store, logger, rates, cfg.Anomalies)
+	contractorService := contractor.NewContractorService(store, logger)
+	searchService := search.NewSearchService(store, logger)
+	dashboardService := dashboard.NewDashboardService(store, logger, cfg.Dashboard)
+	consistencyService := consistency.NewConsistencyService(store, logger, cfg.Consistency)
+	unitService := units.NewUnitService(store, logger)
+	refImportService := refimport.NewImportService(store, logger)
+	parseTaskService := parsetask.NewParseTaskService(store, logger, liveHub)
+	workGroupService := workgroups.NewWorkGroupService(store, logger)
+	priceIndexService := priceindex.NewPriceIndexService(store, logger)
+	baselineEstimateService := baseline.NewEstimateService(store, logger, rates)
 
 	server := &Server{
 		store:           store,
@@ -57,10 +143,46 @@ func NewServer(
 		lotService:      lotService,
 		matchingService: matchingService,
 		settingsService: settingsService,
-		httpClient:      httpClient,
+		tenderArchive:   tenderArchive,
+		history:         historyService,
+		analytics:       analyticsService,
+		anomalies:       anomalyService,
+		reports:         reportService,
+		feed:            feedService,
+		parseTasks:      parseTaskService,
+		etpFetch:        etpFetch,
+		etpSync:         etpSync,
+		contractors:     contractorService,
+		search:          searchService,
+		dashboard:       dashboardService,
+		consistency:     consistencyService,
+		units:           unitService,
+		refImport:       refImportService,
+		workGroups:      workGroupService,
+		priceIndices:    priceIndexService,
+		baselines:       baselineEstimateService,
+		retention:       retentionService,
+		serviceCreds:    serviceCreds,
+		webhooks:        webhookService,
+		events:          eventPublisher,
+		scheduler:       jobScheduler,
+		health:          healthChecker,
+		refCache:        refCache,
+		live:            liveHub,
+		httpClient:      parserClient,
 		config:          cfg,
+		etagSalt:        newETagSalt(cfg.Currency),
+		serviceLimiter:  rate.NewLimiter(rate.Limit(cfg.RateLimit.ServiceRPS), cfg.RateLimit.ServiceBurst),
+		rateLimiter:     rateLimiter,
+	}
+	server.setCORSOrigins(cfg.CORS.AllowedOrigins)
+	if spec, err := buildOpenAPISpec(cfg); err != nil {
+		logger.Errorf("не удалось собрать спецификацию OpenAPI: %v", err)
+	} else {
+		server.openAPISpec = spec
 	}
 	router := gin.Default()
+	useJSONFieldNames()
 
 	// Настройка CORS
 	corsConfig := cors.DefaultConfig()
@@ -72,138 +194,387 @@ func NewServer(
 			"http://local-api.dev:5173",
 		}
 		corsConfig.AllowMethods = []string{"GET", "POST", "OPTIONS", "PUT", "PATCH", "DELETE"}
-		corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Authorization", "Accept", "X-Requested-With", "X-CSRF-Token"}
+		corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Authorization", "Accept", "X-Requested-With", "X-CSRF-Token", "If-None-Match"}
 		corsConfig.AllowCredentials = true
 	} else {
-		// В production режиме - строгие настройки
-		if len(cfg.CORS.AllowedOrigins) > 0 {
-			corsConfig.AllowOrigins = cfg.CORS.AllowedOrigins
-		} else {
+		// В production режиме - строгие настройки. Список origins читается при
+		// каждом запросе, чтобы cors.allowed_origins применялся по SIGHUP.
+		if len(cfg.CORS.AllowedOrigins) == 0 {
 			// В production CORS origins должны быть явно настроены
 			logger.Warn("CORS allowed_origins not configured in production - using restrictive default")
-			corsConfig.AllowOrigins = []string{} // No origins allowed
 		}
+		corsConfig.AllowOriginFunc = server.allowCORSOrigin
 		corsConfig.AllowMethods = []string{"GET", "POST", "OPTIONS", "PUT", "PATCH", "DELETE"}
-		corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Authorization", "X-CSRF-Token"}
+		corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Authorization", "X-CSRF-Token", "If-None-Match"}
 		corsConfig.AllowCredentials = true
 	}
-	corsConfig.ExposeHeaders = []string{"Content-Length", "X-Auth-Error"}
+	corsConfig.ExposeHeaders = []string{"Content-Length", "X-Auth-Error", "ETag"}
 	router.Use(cors.New(corsConfig))
 
+	// Пробы оркестратора: без аутентификации, CSRF и rate limiting
+	router.GET("/healthz", server.healthzHandler)
+	router.GET("/readyz", server.readyzHandler)
+
 	router.GET("/home", server.HomeHandler)
 	router.GET("/api/stats", server.getStatsHandler)
 
 	// --- INTERNAL (Python workers) ---
 	// Отдельная группа для server-to-server взаимодействия.
-	// Здесь НЕ используется cookie/JWT/CSRF. Только service-auth.
+	// Здесь НЕ используется cookie/JWT/CSRF. Только service-auth: у каждого воркера
+	// свой ключ, доступ к роутам ограничен scopes ключа.
 	// Rate limiting добавлен для defense-in-depth защиты на случай компрометации API ключа.
 	internal := router.Group("/internal/worker")
-	internal.Use(ServiceBearerAuthMiddleware("python-worker"))
-	internal.Use(ServiceRateLimitMiddleware(100, 200)) // 100 req/s, burst 200
+	internal.Use(ServiceBearerAuthMiddleware(server.serviceCreds))
+	internal.Use(ServiceRateLimitMiddleware(server.serviceLimiter)) // rate_limit.service_rps / service_burst
 	{
 		// Импорт тендера (используется парсером/воркерами)
-		internal.POST("/import-tender", server.ImportTenderHandler)
+		internal.POST("/import-tender", RequireServiceScope(servicecreds.ScopeImport), server.ImportTenderHandler)
+
+		// Парсер сообщает статус задачи загрузки, ID тендера или ошибку
+		internal.POST("/tasks/:task_id/status", RequireServiceScope(servicecreds.ScopeImport), server.PushParseTaskStatusHandler)
 
 		// AI Results endpoint для Python сервиса
 		// Принимает результаты AI анализа для лота
 		// Request: JSON body с полями analysis результата
 		// Response: 200 OK при успехе, 400/500 при ошибке
-		internal.POST("/lots/:lot_id/ai-results", server.SimpleLotAIResultsHandler)
+		internal.POST("/lots/:lot_id/ai-results", RequireServiceScope(servicecreds.ScopeAIResults), server.SimpleLotAIResultsHandler)
 
 		// RAG-воркфлоу (процессы matching/cleaning/indexing)
-		internal.GET("/positions/unmatched", server.UnmatchedPositionsHandler)
-		internal.POST("/positions/match", server.MatchPositionHandler)
-
-		internal.GET("/catalog/unindexed", server.UnindexedCatalogItemsHandler)
-		internal.POST("/catalog/indexed", server.CatalogIndexedHandler)
-
-		internal.POST("/merges/suggest", server.SuggestMergeHandler)
-		internal.GET("/catalog/active", server.ActiveCatalogItemsHandler)
+		rag := internal.Group("/", RequireServiceScope(servicecreds.ScopeRAG))
+		rag.GET("/positions/unmatched", server.UnmatchedPositionsHandler)
+		rag.POST("/positions/claim", server.ClaimUnmatchedPositionsHandler)
+		rag.POST("/positions/match", server.MatchPositionHandler)
+		rag.POST("/positions/match-batch", server.MatchPositionsBatchHandler)
+		// Ручные исправления сопоставлений — обучающие примеры для RAG
+		rag.GET("/matching/corrections", server.MatchingCorrectionsHandler)
+
+		rag.GET("/catalog/unindexed", server.UnindexedCatalogItemsHandler)
+		rag.POST("/catalog/indexed", server.CatalogIndexedHandler)
+
+		rag.POST("/merges/suggest", server.SuggestMergeHandler)
+		rag.GET("/catalog/active", server.ActiveCatalogItemsHandler)
+		rag.GET("/norm-version", server.GetNormVersionHandler)
 	}
 
 	// --- API V1 ---
 	v1 := router.Group("/api/v1")
 	{
-		// Публичные auth-роуты
-		v1.POST("/auth/login", server.loginHandler)
+		// Публичные auth-роуты. Лимит по IP клиента (rate_limit.auth_rps / auth_burst)
+		// защищает от перебора паролей и токенов сброса
+		authLimit := RateLimitMiddleware(server.rateLimiter, ratelimit.GroupAuth, logger)
+		v1.POST("/auth/login", authLimit, server.loginHandler)
 		// Refresh без CSRF: защищен через DB-валидацию refresh token + переустанавливает CSRF cookie
-		v1.POST("/auth/refresh", server.refreshHandler)
+		v1.POST("/auth/refresh", authLimit, server.refreshHandler)
 		// Logout с CSRF: state-changing операция без восстановления
 		v1.POST("/auth/logout", CsrfMiddleware(), server.logoutHandler)
+		// Сброс пароля по одноразовому токену: пользователь не аутентифицирован,
+		// CSRF-cookie у него может не быть; токен передается в теле запроса
+		v1.POST("/auth/reset-password", authLimit, server.resetPasswordHandler)
+		// Вход через SSO (auth.oidc): переходы браузера, CSRF закрывает параметр state.
+		// При выключенном OIDC — 404
+		v1.GET("/auth/oidc/login", authLimit, server.oidcLoginHandler)
+		v1.GET("/auth/oidc/callback", authLimit, server.oidcCallbackHandler)
+
+		// Спецификация API открыта: она не раскрывает данных, а клиентам нужна до входа.
+		// Swagger UI — только в режиме отладки.
+		v1.GET("/openapi.json", server.getOpenAPISpecHandler)
+		if cfg.IsDebug != nil && *cfg.IsDebug {
+			v1.GET("/docs", server.swaggerUIHandler)
+		}
 
-		// Приватные роуты (требуют аутентификацию)
+		// Приватные роуты (требуют аутентификацию).
+		// GET-роуты без RequirePermission доступны всем ролям (tenders:read);
+		// изменяющие действия проверяют право по матрице auth.HasPermission.
 		protected := v1.Group("/")
-		protected.Use(AuthMiddleware(server.config, server.store, server.logger))
+		protected.Use(AuthMiddleware(server.config, server.authService))
 		protected.Use(CsrfMiddleware())
+		protected.Use(RateLimitMiddleware(server.rateLimiter, ratelimit.GroupUser, logger)) // rate_limit.user_rps / user_burst
+		// Выгрузки CSV дополнительно ограничены строже (rate_limit.export_rps / export_burst)
+		exportLimit := RateLimitMiddleware(server.rateLimiter, ratelimit.GroupExport, logger)
 		{
 			// Информация о текущем пользователе
 			protected.GET("/auth/me", server.meHandler)
-
-			protected.POST("/upload-tender", server.ProxyUploadHandler)
+			// Под имперсонацией операции с учетной записью пользователя запрещены
+			protected.POST("/auth/change-password", DenyImpersonated(), server.changePasswordHandler)
+			protected.POST("/auth/logout-all", DenyImpersonated(), server.logoutAllHandler)
+			// Завершение имперсонации (токен с acting_admin_id)
+			protected.POST("/auth/impersonation/end", server.endImpersonationHandler)
+
+			// Поток событий текущего пользователя (Server-Sent Events): прогресс
+			// загрузки и импорта, завершение AI-анализа
+			protected.GET("/events", server.streamEventsHandler)
+
+			// Лента уведомлений текущего пользователя
+			protected.GET("/notifications", server.listNotificationsHandler)
+			protected.GET("/notifications/unread-count", server.getUnreadNotificationsCountHandler)
+			protected.POST("/notifications/:id/read", server.markNotificationReadHandler)
+
+			protected.POST("/upload-tender", RequirePermission(auth.PermissionTendersWrite), server.ProxyUploadHandler)
+			protected.GET("/tasks", server.ListParseTasksHandler)
 			protected.GET("/tasks/:task_id/status", server.GetTaskStatusHandler)
+			// Получение тендера с ЭТП через парсер
+			protected.POST("/tenders/fetch", RequirePermission(auth.PermissionTendersWrite), server.fetchTenderHandler)
+			protected.GET("/tenders/fetch/:task_id", server.getTenderFetchHandler)
 
 			protected.GET("/tenders", server.listTendersHandler)
-			protected.GET("/tenders/:id", server.getTenderDetailsHandler)
-			protected.GET("/tenders/:id/proposals", server.listProposalsHandler)
-			protected.GET("/proposals/:id/details", server.getProposalFullDetailsHandler)
+			protected.GET("/tenders/export.csv", RequirePermission(auth.PermissionAnalyticsRead), exportLimit, server.exportTendersCSVHandler)
+
+			// Тендеры, лоты, предложения и победители доступны только своей
+			// организации (роль admin — всем): чужой ресурс отвечает 404
+			tenderAccess := RequireOrganizationAccess("id", server.store.GetTenderOrganizationID)
+			lotAccess := RequireOrganizationAccess("id", server.store.GetLotOrganizationID)
+			proposalAccess := RequireOrganizationAccess("id", server.store.GetProposalOrganizationID)
+
+			protected.GET("/tenders/:id", tenderAccess, server.getTenderDetailsHandler)
+			protected.GET("/tenders/:id/proposals", tenderAccess, server.listProposalsHandler)
+			// Подписка на уведомления по тендеру
+			protected.POST("/tenders/:id/follow", tenderAccess, server.followTenderHandler)
+			protected.DELETE("/tenders/:id/follow", tenderAccess, server.unfollowTenderHandler)
+			protected.GET("/me/followed-tenders", server.listFollowedTendersHandler)
+			// Сохранённые фильтры списка тендеров (вкладки в интерфейсе)
+			protected.GET("/me/saved-filters", server.listSavedFiltersHandler)
+			protected.POST("/me/saved-filters", server.createSavedFilterHandler)
+			protected.DELETE("/me/saved-filters/:id", server.deleteSavedFilterHandler)
+			// Метки тендеров для произвольной группировки (фильтр списка — tag_ids)
+			protected.GET("/tags", server.listTagsHandler)
+			protected.POST("/tags", RequirePermission(auth.PermissionTendersWrite), server.createTagHandler)
+			protected.GET("/tenders/:id/tags", tenderAccess, server.listTenderTagsHandler)
+			protected.PUT("/tenders/:id/tags/:tagId", RequirePermission(auth.PermissionTendersWrite), tenderAccess, server.attachTenderTagHandler)
+			protected.DELETE("/tenders/:id/tags/:tagId", RequirePermission(auth.PermissionTendersWrite), tenderAccess, server.detachTenderTagHandler)
+			// Обновления тендера с ЭТП по расписанию и что они изменили
+			protected.GET("/tenders/:id/syncs", tenderAccess, server.listTenderSyncsHandler)
+			// Тендеры, подтверждённые как тот же тендер под другим etp_id
+			protected.GET("/tenders/:id/linked", tenderAccess, server.listLinkedTendersHandler)
+			// История ручных правок полей (PATCH тендера, ключевые параметры лота)
+			protected.GET("/tenders/:id/history", tenderAccess, server.getTenderHistoryHandler)
+			protected.GET("/lots/:id/history", lotAccess, server.getLotHistoryHandler)
+			protected.GET("/proposals/:id/details", proposalAccess, server.getProposalFullDetailsHandler)
+			protected.GET("/proposals/:id/positions", proposalAccess, server.listProposalPositionsHandler)
+			// Сверка итогов глав и итоговых строк с суммой позиций
+			protected.GET("/proposals/:id/consistency", proposalAccess, server.getProposalConsistencyHandler)
+			protected.GET("/proposals/:id/coverage", proposalAccess, server.getProposalCoverageHandler)
 
 			// Используем PATCH для частичного обновления всего ресурса 'tenders'
-			protected.PATCH("/tenders/:id", server.patchTenderHandler)
-
-			protected.GET("/lots/:id/proposals", server.listProposalsForLotHandler)
-			protected.PATCH("/lots/:id/key-parameters", server.patchLotKeyParametersHandler)
+			protected.PATCH("/tenders/:id", RequirePermission(auth.PermissionTendersWrite), tenderAccess, server.patchTenderHandler)
+			// Мягкое удаление; восстановление и include_deleted=true — tenders:manage_deleted
+			protected.DELETE("/tenders/:id", RequirePermission(auth.PermissionTendersWrite), tenderAccess, server.deleteTenderHandler)
+			protected.POST("/tenders/:id/restore", RequirePermission(auth.PermissionTendersManageDeleted), tenderAccess, server.restoreTenderHandler)
+			// История импортов: версии исходного JSON для отладки регрессий парсера
+			protected.GET("/tenders/:id/imports", RequirePermission(auth.PermissionImportsInspect), tenderAccess, server.listTenderImportsHandler)
+			protected.GET("/tenders/:id/imports/diff", RequirePermission(auth.PermissionImportsInspect), tenderAccess, server.diffTenderImportsHandler)
+			protected.GET("/tenders/:id/imports/:version/raw", RequirePermission(auth.PermissionImportsInspect), tenderAccess, server.getTenderImportRawHandler)
+
+			protected.GET("/lots/:id/proposals", lotAccess, server.listProposalsForLotHandler)
+			protected.PATCH("/lots/:id/key-parameters", RequirePermission(auth.PermissionTendersWrite), lotAccess, server.patchLotKeyParametersHandler)
+			// История запусков AI-анализа и откат параметров лота к выбранному запуску
+			protected.GET("/lots/:id/ai-results", lotAccess, server.listLotAIResultsHandler)
+			protected.POST("/lots/:id/ai-results/:runId/promote", RequirePermission(auth.PermissionTendersWrite), lotAccess, server.promoteLotAIResultHandler)
+			protected.GET("/lots/:id/comparison", lotAccess, server.getLotComparisonHandler)
+			protected.GET("/lots/:id/analytics", RequirePermission(auth.PermissionAnalyticsRead), lotAccess, server.getLotAnalyticsHandler)
+			protected.GET("/lots/:id/analytics/work-groups", RequirePermission(auth.PermissionAnalyticsRead), lotAccess, server.getLotWorkGroupCostsHandler)
+			protected.GET("/lots/:id/anomalies", RequirePermission(auth.PermissionAnalyticsRead), lotAccess, server.getLotAnomaliesHandler)
+			// Оценочный baseline по средним ценам каталога (dry_run=true — только расчёт)
+			protected.POST("/lots/:id/baseline/estimate", RequirePermission(auth.PermissionTendersWrite), lotAccess, server.estimateLotBaselineHandler)
+			// Документы лота (протоколы выбора победителя)
+			protected.GET("/lots/:id/attachments", lotAccess, server.listLotAttachmentsHandler)
+			protected.GET("/lots/:id/attachments/:attachmentId", lotAccess, server.downloadLotAttachmentHandler)
+
+			protected.GET("/contractors", server.listContractorsHandler)
+			protected.GET("/contractors/expiring-accreditation", server.listExpiringAccreditationsHandler)
+			protected.GET("/contractors/:id", server.getContractorHandler)
+			protected.GET("/contractors/:id/stats", RequirePermission(auth.PermissionAnalyticsRead), server.getContractorStatsHandler)
+
+			// Выгрузка каталога для аналитиков
+			protected.GET("/catalog/export.csv", RequirePermission(auth.PermissionAnalyticsRead), exportLimit, server.exportCatalogCSVHandler)
+			// Классификатор видов работ
+			protected.GET("/catalog/work-groups", RequirePermission(auth.PermissionAnalyticsRead), server.listWorkGroupsHandler)
+			// Справочник цен: история цен позиции по всем тендерам
+			protected.GET("/catalog/:id/price-history", RequirePermission(auth.PermissionAnalyticsRead), server.getCatalogPriceHistoryHandler)
+			// Поиск позиций КП по названию во всех тендерах: «где мы это уже видели»
+			protected.GET("/positions/search", server.searchPositionsHandler)
+			// Ручное исправление ошибочного сопоставления RAG (позиция своей организации)
+			positionAccess := RequireOrganizationAccess("id", server.store.GetPositionItemOrganizationID)
+			protected.PATCH("/positions/:id/catalog-match", RequirePermission(auth.PermissionMatchingCorrect), positionAccess, server.CorrectPositionMatchHandler)
+			// Отмена ошибочного сопоставления: позиция возвращается в очередь RAG
+			protected.POST("/positions/:id/unmatch", RequirePermission(auth.PermissionMatchingCorrect), positionAccess, server.UnmatchPositionHandler)
+			// Глобальный поиск: тендеры, подрядчики, объекты, позиции каталога
+			protected.GET("/search", server.globalSearchHandler)
+			// Сводка главной страницы (кэшируется на dashboard.cache_ttl)
+			protected.GET("/dashboard", server.getDashboardHandler)
+			// Удельные цены победителей по ключевому параметру лотов категории
+			protected.GET("/analytics/lots/compare", RequirePermission(auth.PermissionAnalyticsRead), server.compareLotKeyParameterHandler)
+			// Отчет об экономии: baseline против цены победителя по тендерам за период
+			protected.GET("/reports/savings", RequirePermission(auth.PermissionAnalyticsRead), server.getSavingsReportHandler)
 
 			// Роуты для победителей
-			protected.POST("/lots/:lotId/winners", server.createWinnerHandler)
-			protected.PATCH("/winners/:winnerId", server.updateWinnerHandler)
-			protected.DELETE("/winners/:winnerId", server.deleteWinnerHandler)
+			winners := protected.Group("/", RequirePermission(auth.PermissionWinnersManage))
+			winners.POST("/lots/:lotId/winners", RequireOrganizationAccess("lotId", server.store.GetLotOrganizationID), server.createWinnerHandler)
+			winnerAccess := RequireOrganizationAccess("winnerId", server.store.GetWinnerOrganizationID)
+			winners.PATCH("/winners/:winnerId", winnerAccess, server.updateWinnerHandler)
+			winners.DELETE("/winners/:winnerId", winnerAccess, server.deleteWinnerHandler)
+			// Протокол выбора победителя (DOCX/PDF) сохраняется документом лота
+			winners.POST("/lots/:id/winner-protocol", lotAccess, server.generateWinnerProtocolHandler)
+
+			// Справочники: чтение — всем, изменение — reference:manage
+			reference := protected.Group("/", RequirePermission(auth.PermissionReferenceManage))
 
 			protected.GET("/tender-types", server.listTenderTypesHandler)
-			protected.POST("/tender-types", server.createTenderTypeHandler)
+			reference.POST("/tender-types", server.createTenderTypeHandler)
 
-			protected.PUT("/tender-types/:id", server.updateTenderTypeHandler)
-			protected.DELETE("/tender-types/:id", server.deleteTenderTypeHandler)
+			reference.PUT("/tender-types/:id", server.updateTenderTypeHandler)
+			reference.DELETE("/tender-types/:id", server.deleteTenderTypeHandler)
 			protected.GET("/tender-types/:type_id/chapters", server.listChaptersByTypeHandler)
 
 			protected.GET("/tender-chapters", server.listTenderChaptersHandler)
-			protected.POST("/tender-chapters", server.createTenderChapterHandler)
+			reference.POST("/tender-chapters", server.createTenderChapterHandler)
 			protected.GET("/tender-chapters/:chapter_id/categories", server.listCategoriesByChapterHandler)
 
 			// --- ДОБАВЛЯЕМ НОВЫЕ РОУТЫ ДЛЯ UPDATE И DELETE ---
-			protected.PUT("/tender-chapters/:id", server.updateTenderChapterHandler)
-			protected.DELETE("/tender-chapters/:id", server.deleteTenderChapterHandler)
+			reference.PUT("/tender-chapters/:id", server.updateTenderChapterHandler)
+			reference.DELETE("/tender-chapters/:id", server.deleteTenderChapterHandler)
 
 			// --- НОВЫЕ РОУТЫ ДЛЯ КАТЕГОРИЙ ---
 			protected.GET("/tender-categories", server.listTenderCategoriesHandler)
-			protected.POST("/tender-categories", server.createTenderCategoryHandler)
-			protected.PUT("/tender-categories/:id", server.updateTenderCategoryHandler)
-			protected.DELETE("/tender-categories/:id", server.deleteTenderCategoryHandler)
+			reference.POST("/tender-categories", server.createTenderCategoryHandler)
+			reference.PUT("/tender-categories/:id", server.updateTenderCategoryHandler)
+			reference.DELETE("/tender-categories/:id", server.deleteTenderCategoryHandler)
+
+			// Индексы цен по регионам и месяцам (пересчёт истории цен каталога)
+			protected.GET("/price-indices", server.listPriceIndicesHandler)
+			reference.POST("/price-indices", server.createPriceIndexHandler)
+			reference.PUT("/price-indices/:id", server.updatePriceIndexHandler)
+			reference.DELETE("/price-indices/:id", server.deletePriceIndexHandler)
+
+			// Загрузка справочников таблицей: путь под /admin, но право то же, что у
+			// правки по одной записи (синонимы единиц — ещё и catalog:manage)
+			reference.POST("/admin/reference/import", server.importReferenceHandler)
 		}
 
-		// Админские роуты (требуют роль admin)
+		// Админские роуты: каждый блок требует своё право (по матрице — только роль admin)
 		admin := protected.Group("/admin")
-		admin.Use(RequireRole("admin"))
 		{
-			admin.GET("/users", server.listUsersHandler)
-			admin.PATCH("/users/:id/role", server.updateUserRoleHandler)
-
+			users := admin.Group("/", RequirePermission(auth.PermissionUsersManage))
+			users.GET("/users", server.listUsersHandler)
+			users.POST("/users", server.createUserHandler)
+			users.PATCH("/users/:id", server.updateUserHandler)
+			users.DELETE("/users/:id", server.deleteUserHandler)
+			users.POST("/users/:id/reset-password", server.resetUserPasswordHandler)
+			users.PATCH("/users/:id/role", server.updateUserRoleHandler)
+			users.PATCH("/users/:id/status", server.updateUserStatusHandler)
+			// Имперсонация: короткая сессия от имени пользователя с журналом запросов
+			users.POST("/users/:id/impersonate", server.impersonateUserHandler)
+
+			// Организации: владельцы тендеров и места работы пользователей
+			organizations := admin.Group("/", RequirePermission(auth.PermissionOrganizationsManage))
+			organizations.GET("/organizations", server.listOrganizationsHandler)
+			organizations.POST("/organizations", server.createOrganizationHandler)
+
+			system := admin.Group("/", RequirePermission(auth.PermissionSystemManage))
 			// Системные настройки
-			admin.GET("/settings", server.HandleListSystemSettings)
-			admin.GET("/settings/:key", server.HandleGetSystemSetting)
-			admin.PUT("/settings", server.HandleUpdateSystemSetting)
-
+			system.GET("/settings", server.HandleListSystemSettings)
+			system.GET("/settings/:key", server.HandleGetSystemSetting)
+			system.PUT("/settings", server.HandleUpdateSystemSetting)
+
+			// Ключи внутренних сервисов (ротация без перезапуска)
+			system.GET("/service-credentials", server.HandleListServiceCredentials)
+			system.POST("/service-credentials", server.HandleCreateServiceCredential)
+			system.DELETE("/service-credentials/:id", server.HandleRevokeServiceCredential)
+			system.POST("/service-credentials/:id/rotate", server.HandleRotateServiceCredential)
+
+			// Вебхуки: подписки внешних систем и журнал доставок
+			system.GET("/webhooks", server.HandleListWebhooks)
+			system.POST("/webhooks", server.HandleCreateWebhook)
+			system.PATCH("/webhooks/:id", server.HandleUpdateWebhook)
+			system.DELETE("/webhooks/:id", server.HandleDeleteWebhook)
+			system.GET("/webhooks/:id/deliveries", server.HandleListWebhookDeliveries)
+
+			// Регулярные отчеты: расписание рассылки и история запусков
+			system.GET("/reports/schedules", server.HandleListScheduledReports)
+			system.POST("/reports/schedules", server.HandleCreateScheduledReport)
+			system.PATCH("/reports/schedules/:id", server.HandleUpdateScheduledReport)
+			system.DELETE("/reports/schedules/:id", server.HandleDeleteScheduledReport)
+			system.GET("/reports/schedules/:id/runs", server.HandleListReportRuns)
+
+			// Фоновые задачи: состояние последнего запуска
+			system.GET("/jobs", server.HandleListJobs)
+
+			// Политики хранения: настройки, журнал запусков, запуск вручную
+			system.GET("/retention", server.listRetentionPoliciesHandler)
+			system.GET("/retention/runs", server.listRetentionRunsHandler)
+			system.POST("/retention/:policy/run", server.runRetentionPolicyHandler)
+
+			// Кэш справочников: счётчики попаданий этого экземпляра
+			system.GET("/cache/stats", server.getRefCacheStatsHandler)
+			system.GET("/outbound/stats", server.getOutboundStatsHandler)
+
+			// JSON Schema ключевых параметров лота по категориям тендеров
+			system.GET("/key-parameter-schemas", server.listKeyParameterSchemasHandler)
+			system.GET("/key-parameter-schemas/:categoryId", server.getKeyParameterSchemaHandler)
+			system.PUT("/key-parameter-schemas/:categoryId", server.putKeyParameterSchemaHandler)
+			system.DELETE("/key-parameter-schemas/:categoryId", server.deleteKeyParameterSchemaHandler)
+
+			catalogAdmin := admin.Group("/", RequirePermission(auth.PermissionCatalogManage))
 			// Слияние дубликатов каталога
-			admin.GET("/suggested_merges", server.ListSuggestedMergesHandler)
-			admin.POST("/merges/execute-batch", server.ExecuteBatchMergeHandler)
-			admin.POST("/merges/group-batch", server.GroupBatchPositionsHandler)
-			admin.POST("/merges/:id/execute", server.ExecuteMergeHandler)
-			admin.POST("/merges/:id/group", server.GroupPositionsHandler)
-			admin.PATCH("/merges/:id/reject", server.RejectMergeHandler)
+			catalogAdmin.GET("/suggested_merges", server.ListSuggestedMergesHandler)
+			catalogAdmin.GET("/merges", server.ListSuggestedMergesHandler)
+			catalogAdmin.POST("/merges/execute-batch", server.ExecuteBatchMergeHandler)
+			catalogAdmin.POST("/merges/group-batch", server.GroupBatchPositionsHandler)
+			catalogAdmin.POST("/merges/:id/execute", server.ExecuteMergeHandler)
+			catalogAdmin.POST("/merges/:id/group", server.GroupPositionsHandler)
+			catalogAdmin.POST("/merges/:id/approve", server.ApproveMergeHandler)
+			catalogAdmin.POST("/merges/:id/reject", server.RejectMergeHandler)
+			catalogAdmin.PATCH("/merges/:id/reject", server.RejectMergeHandler) // Устаревший вариант, оставлен для совместимости
+			// Метрики качества матчинга
+			catalogAdmin.GET("/matching/stats", server.GetMatchingStatsHandler)
 
 			// Просмотр групп каталога
-			admin.GET("/catalog/groups", server.ListGroupsHandler)
-			admin.GET("/catalog/groups/:id/children", server.ListGroupChildrenHandler)
-			admin.POST("/catalog/positions/:id/ungroup", server.UngroupPositionHandler)
+			catalogAdmin.GET("/catalog/groups", server.ListGroupsHandler)
+			catalogAdmin.GET("/catalog/groups/:id/children", server.ListGroupChildrenHandler)
+			catalogAdmin.POST("/catalog/positions/:id/ungroup", server.UngroupPositionHandler)
+
+			// Закрепление авторитетных позиций каталога
+			catalogAdmin.GET("/catalog/pinned", server.ListPinnedCatalogPositionsHandler)
+			catalogAdmin.PATCH("/catalog/positions/:id/pin", server.SetCatalogPositionPinnedHandler)
+			// Вывод позиции из оборота: новые сопоставления уходят на замену
+			catalogAdmin.POST("/catalog/positions/:id/deprecate", server.DeprecateCatalogPositionHandler)
+
+			// Массовая активация каталога (dry_run=true — только отчёт)
+			catalogAdmin.POST("/catalog/activate", server.ActivateCatalogPositionsHandler)
+
+			// Версия нормализации и перенормализация каталога
+			catalogAdmin.GET("/catalog/norm-version", server.GetNormVersionHandler)
+			catalogAdmin.POST("/catalog/norm-version/bump", server.BumpNormVersionHandler)
+
+			// Классификатор видов работ и привязка к нему позиций каталога
+			catalogAdmin.POST("/work-groups", server.createWorkGroupHandler)
+			catalogAdmin.PUT("/work-groups/:id", server.updateWorkGroupHandler)
+			catalogAdmin.DELETE("/work-groups/:id", server.deleteWorkGroupHandler)
+			catalogAdmin.PUT("/catalog/positions/:id/work-group", server.setCatalogPositionWorkGroupHandler)
+
+			// Справочник единиц измерения: канонические единицы, синонимы, слияние
+			catalogAdmin.GET("/units", server.listUnitsHandler)
+			catalogAdmin.POST("/units", server.createUnitHandler)
+			catalogAdmin.POST("/units/merge", server.mergeUnitsHandler)
+			catalogAdmin.POST("/units/:id/aliases", server.addUnitAliasHandler)
+			catalogAdmin.DELETE("/units/:id/aliases/:aliasId", server.deleteUnitAliasHandler)
+
+			// Дубликаты подрядчиков (одинаковый ИНН) и их слияние, аккредитация
+			contractorsAdmin := admin.Group("/", RequirePermission(auth.PermissionContractorsManage))
+			contractorsAdmin.GET("/contractors/duplicates", server.listContractorDuplicatesHandler)
+			contractorsAdmin.POST("/contractors/merge", server.mergeContractorsHandler)
+			contractorsAdmin.PUT("/contractors/:id/accreditation", server.updateContractorAccreditationHandler)
+
+			// Трассировка импортов
+			admin.GET("/imports/:id/trace", RequirePermission(auth.PermissionImportsInspect), server.GetImportTraceHandler)
+
+			// Вероятные дубликаты тендеров (тот же тендер под другим etp_id)
+			duplicatesAdmin := admin.Group("/", RequirePermission(auth.PermissionImportsInspect))
+			duplicatesAdmin.GET("/tender-duplicates", server.listTenderDuplicatesHandler)
+			duplicatesAdmin.POST("/tender-duplicates/:id/link", server.linkTenderDuplicateHandler)
+			duplicatesAdmin.POST("/tender-duplicates/:id/ignore", server.ignoreTenderDuplicateHandler)
+
+			// Безвозвратное удаление тендера (dry_run=true — только счётчики строк)
+			admin.POST("/tenders/:id/purge", RequirePermission(auth.PermissionTendersManageDeleted), server.purgeTenderHandler)
 		}
 	}
 
@@ -215,6 +586,7 @@ func (s *Server) Start(address string) error {
 	return s.router.Run(address)
 }
 
-func errorResponse(err error) gin.H {
-	return gin.H{"error": err.Error()}
+// Handler возвращает роутер сервера (для httptest, см. testutil/servertest).
+func (s *Server) Handler() http.Handler {
+	return s.router
 }
@@ -0,0 +1,356 @@
+package server
+
+import (
+	"database/sql"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/util"
+)
+
+const (
+	tenderListDefaultPageSize = 10
+	tenderListMaxPageSize     = 100
+
+	// tenderListMaxTags - сколько меток можно передать в tag_ids.
+	tenderListMaxTags = 20
+
+	// tenderListDateLayout - формат дат в query-параметрах date_from / date_to.
+	tenderListDateLayout = "2006-01-02"
+)
+
+// tenderListSortFields - допустимые значения параметра sort_by.
+// Значения передаются в SQL как есть, поэтому список должен совпадать с CASE-ветками ListTenders.
+var tenderListSortFields = map[string]struct{}{
+	"date":            {},
+	"title":           {},
+	"proposals_count": {},
+	"total_cost":      {},
+}
+
+// tenderListSavedParams - параметры списка, которые можно сохранить в фильтре
+// (/api/v1/me/saved-filters). page и cursor относятся к конкретному просмотру.
+var tenderListSavedParams = map[string]struct{}{
+	"page_size":       {},
+	"category_id":     {},
+	"chapter_id":      {},
+	"type_id":         {},
+	"executor_id":     {},
+	"object_id":       {},
+	"date_from":       {},
+	"date_to":         {},
+	"has_winner":      {},
+	"tag_ids":         {},
+	"include_deleted": {},
+	"sort_by":         {},
+	"sort_order":      {},
+}
+
+// tenderListQuery - разобранные параметры GET /api/v1/tenders.
+// Отвечает только за валидацию и сборку параметров sqlc, без обращения к БД.
+type tenderListQuery struct {
+	Page     int32
+	PageSize int32
+
+	CategoryID sql.NullInt64
+	ChapterID  sql.NullInt64
+	TypeID     sql.NullInt64
+	ExecutorID sql.NullInt64
+	ObjectID   sql.NullInt64
+	DateFrom   sql.NullTime
+	DateTo     sql.NullTime // Уже сдвинута на +1 день: в SQL используется полуинтервал [date_from, date_to)
+	HasWinner  sql.NullBool
+	TagIDs     []int64 // Тендер должен иметь все метки; пусто - без фильтра
+
+	// IncludeDeleted - показывать мягко удалённые тендеры. Право (роль admin)
+	// проверяет хендлер: здесь разбирается только значение параметра.
+	IncludeDeleted bool
+
+	// OrganizationID - организация пользователя (requestOrganizationScope).
+	// Задается хендлером, не из query string.
+	OrganizationID sql.NullInt64
+
+	SortBy   string
+	SortDesc bool
+
+	// UseCursor - keyset-пагинация (параметр cursor, даже пустой): порядок
+	// created_at DESC, id DESC, Page не используется. After - ключ последней
+	// строки предыдущей страницы (nil - первая страница).
+	UseCursor bool
+	After     *util.PageCursor
+}
+
+// parseTenderListQuery разбирает и валидирует query string списка тендеров.
+// Все фильтры опциональны. date_to включительная: тендеры за этот день попадают в выборку.
+func parseTenderListQuery(values url.Values) (tenderListQuery, error) {
+	q := tenderListQuery{
+		Page:     1,
+		PageSize: tenderListDefaultPageSize,
+		SortBy:   "date",
+		SortDesc: true,
+	}
+
+	if v := values.Get("page"); v != "" {
+		page, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || page < 1 {
+			return q, i18n.Errorf("request.invalid_page")
+		}
+		q.Page = int32(page)
+	}
+
+	if v := values.Get("page_size"); v != "" {
+		pageSize, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || pageSize < 1 || pageSize > tenderListMaxPageSize {
+			return q, i18n.Errorf("request.invalid_page_size_range", tenderListMaxPageSize)
+		}
+		q.PageSize = int32(pageSize)
+	}
+
+	idFilters := []struct {
+		name string
+		dst  *sql.NullInt64
+	}{
+		{"category_id", &q.CategoryID},
+		{"chapter_id", &q.ChapterID},
+		{"type_id", &q.TypeID},
+		{"executor_id", &q.ExecutorID},
+		{"object_id", &q.ObjectID},
+	}
+	for _, f := range idFilters {
+		v := values.Get(f.name)
+		if v == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			return q, i18n.Errorf("request.param_positive_integer", f.name)
+		}
+		*f.dst = sql.NullInt64{Int64: id, Valid: true}
+	}
+
+	if v := values.Get("date_from"); v != "" {
+		t, err := time.Parse(tenderListDateLayout, v)
+		if err != nil {
+			return q, i18n.Errorf("request.date_from_format")
+		}
+		q.DateFrom = sql.NullTime{Time: t, Valid: true}
+	}
+	if v := values.Get("date_to"); v != "" {
+		t, err := time.Parse(tenderListDateLayout, v)
+		if err != nil {
+			return q, i18n.Errorf("request.date_to_format")
+		}
+		q.DateTo = sql.NullTime{Time: t.AddDate(0, 0, 1), Valid: true}
+	}
+	if q.DateFrom.Valid && q.DateTo.Valid && !q.DateFrom.Time.Before(q.DateTo.Time) {
+		return q, i18n.Errorf("request.date_from_after_to")
+	}
+
+	if v := values.Get("tag_ids"); v != "" {
+		tagIDs, err := parseTagIDs(v)
+		if err != nil {
+			return q, err
+		}
+		q.TagIDs = tagIDs
+	}
+
+	if v := values.Get("has_winner"); v != "" {
+		hasWinner, err := strconv.ParseBool(v)
+		if err != nil {
+			return q, i18n.Errorf("request.has_winner_bool")
+		}
+		q.HasWinner = sql.NullBool{Bool: hasWinner, Valid: true}
+	}
+
+	includeDeleted, err := parseIncludeDeleted(values)
+	if err != nil {
+		return q, err
+	}
+	q.IncludeDeleted = includeDeleted
+
+	if v := values.Get("sort_by"); v != "" {
+		if _, ok := tenderListSortFields[v]; !ok {
+			return q, i18n.Errorf("request.invalid_sort_by")
+		}
+		q.SortBy = v
+	}
+
+	if v := values.Get("sort_order"); v != "" {
+		switch strings.ToLower(v) {
+		case "asc":
+			q.SortDesc = false
+		case "desc":
+			q.SortDesc = true
+		default:
+			return q, i18n.Errorf("request.invalid_sort_order")
+		}
+	}
+
+	if values.Has("cursor") {
+		// Keyset-пагинация идёт только в своём порядке: смещение и сортировка ей противоречат
+		for _, name := range []string{"page", "sort_by", "sort_order"} {
+			if values.Has(name) {
+				return q, i18n.Errorf("request.cursor_incompatible", name)
+			}
+		}
+		cursor, ok, err := util.DecodeCursor(values.Get("cursor"))
+		if err != nil {
+			return q, err
+		}
+		q.UseCursor = true
+		if ok {
+			q.After = &cursor
+		}
+		q.Page = 0
+		q.SortBy = "created"
+	}
+
+	return q, nil
+}
+
+// listParams собирает параметры для ListTenders. В режиме cursor запрашивается
+// на строку больше страницы: лишняя строка означает, что есть следующая (см. nextCursor).
+func (q tenderListQuery) listParams() db.ListTendersParams {
+	params := q.filterParams()
+	if !q.UseCursor {
+		params.PageLimit = q.PageSize
+		params.PageOffset = (q.Page - 1) * q.PageSize
+		return params
+	}
+	params.PageLimit = q.PageSize + 1
+	if q.After != nil {
+		params.AfterCreatedAt = sql.NullTime{Time: q.After.CreatedAt, Valid: true}
+		params.AfterID = sql.NullInt64{Int64: q.After.ID, Valid: true}
+	}
+	return params
+}
+
+// filterParams - параметры ListTenders без пагинации.
+func (q tenderListQuery) filterParams() db.ListTendersParams {
+	return db.ListTendersParams{
+		CategoryID:     q.CategoryID,
+		ChapterID:      q.ChapterID,
+		TypeID:         q.TypeID,
+		ExecutorID:     q.ExecutorID,
+		ObjectID:       q.ObjectID,
+		DateFrom:       q.DateFrom,
+		DateTo:         q.DateTo,
+		HasWinner:      q.HasWinner,
+		IncludeDeleted: q.IncludeDeleted,
+		OrganizationID: q.OrganizationID,
+		TagIds:         q.TagIDs,
+		SortBy:         q.SortBy,
+		SortDesc:       q.SortDesc,
+	}
+}
+
+// exportBatchParams собирает параметры ListTenders для очередной порции CSV-выгрузки.
+// page/page_size игнорируются: выгружаются все тендеры, подходящие под фильтры.
+func (q tenderListQuery) exportBatchParams(offset int32) db.ListTendersParams {
+	params := q.filterParams()
+	params.PageLimit = exportBatchSize
+	params.PageOffset = offset
+	return params
+}
+
+// nextCursor обрезает лишнюю строку страницы в режиме cursor и возвращает
+// next_cursor (пусто - последняя страница).
+func (q tenderListQuery) nextCursor(rows []db.ListTendersRow) ([]db.ListTendersRow, string) {
+	if !q.UseCursor || len(rows) <= int(q.PageSize) {
+		return rows, ""
+	}
+	rows = rows[:q.PageSize]
+	last := rows[len(rows)-1]
+	return rows, util.EncodeCursor(util.PageCursor{ID: last.ID, CreatedAt: last.CreatedAt})
+}
+
+// countParams собирает параметры для CountTenders (те же фильтры, без сортировки и пагинации).
+func (q tenderListQuery) countParams() db.CountTendersParams {
+	return db.CountTendersParams{
+		CategoryID:     q.CategoryID,
+		ChapterID:      q.ChapterID,
+		TypeID:         q.TypeID,
+		ExecutorID:     q.ExecutorID,
+		ObjectID:       q.ObjectID,
+		DateFrom:       q.DateFrom,
+		DateTo:         q.DateTo,
+		HasWinner:      q.HasWinner,
+		IncludeDeleted: q.IncludeDeleted,
+		OrganizationID: q.OrganizationID,
+		TagIds:         q.TagIDs,
+	}
+}
+
+// parseTagIDs разбирает tag_ids - ID меток через запятую. Повторы отбрасываются:
+// SQL сравнивает число найденных меток тендера с длиной массива.
+func parseTagIDs(raw string) ([]int64, error) {
+	parts := strings.Split(raw, ",")
+	if len(parts) > tenderListMaxTags {
+		return nil, i18n.Errorf("request.tag_ids_max", tenderListMaxTags)
+	}
+	ids := make([]int64, 0, len(parts))
+	seen := make(map[int64]struct{}, len(parts))
+	for _, part := range parts {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil || id <= 0 {
+			return nil, i18n.Errorf("request.tag_ids_format")
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parseSavedFilter приводит JSON-описание сохранённого фильтра к параметрам
+// списка тендеров и проверяет их parseTenderListQuery. Значения - строки, числа
+// или логические; пустая строка означает «параметр не задан».
+func parseSavedFilter(raw map[string]interface{}) (map[string]string, tenderListQuery, error) {
+	filters := make(map[string]string, len(raw))
+	values := url.Values{}
+	for name, value := range raw {
+		if _, ok := tenderListSavedParams[name]; !ok {
+			return nil, tenderListQuery{}, i18n.Errorf("request.saved_filter_unknown_param", name)
+		}
+		var str string
+		switch v := value.(type) {
+		case string:
+			str = strings.TrimSpace(v)
+		case float64:
+			str = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			str = strconv.FormatBool(v)
+		default:
+			return nil, tenderListQuery{}, i18n.Errorf("request.saved_filter_value", name)
+		}
+		if str == "" {
+			continue
+		}
+		filters[name] = str
+		values.Set(name, str)
+	}
+	q, err := parseTenderListQuery(values)
+	if err != nil {
+		return nil, q, err
+	}
+	return filters, q, nil
+}
+
+// parseIncludeDeleted разбирает параметр include_deleted (по умолчанию false).
+func parseIncludeDeleted(values url.Values) (bool, error) {
+	v := values.Get("include_deleted")
+	if v == "" {
+		return false, nil
+	}
+	includeDeleted, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, i18n.Errorf("request.include_deleted_bool")
+	}
+	return includeDeleted, nil
+}
@@ -0,0 +1,237 @@
+// Purpose: Protects the query-builder layer of GET /api/v1/tenders. Ensures that
+// defaults, pagination bounds, optional filters, the inclusive date_to semantics
+// and the sort whitelist are translated into sqlc parameters correctly, and that
+// invalid input is rejected before any DB call.
+package server
+
+import (
+	"database/sql"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+)
+
+/*
+BEHAVIORAL SCENARIOS:
+
+Given an empty query string
+When parseTenderListQuery is called
+Then page=1, page_size=10, sort by date desc, no filters
+
+Given all filters set
+When parseTenderListQuery is called
+Then every filter is Valid and date_to is shifted by one day (inclusive day)
+
+Given an unknown sort_by or sort_order
+When parseTenderListQuery is called
+Then an error is returned (value never reaches SQL)
+
+Given date_from after date_to
+When parseTenderListQuery is called
+Then an error is returned
+
+Given include_deleted=true
+When parseTenderListQuery is called
+Then both list and count params include soft-deleted tenders (default: excluded)
+
+Given tag_ids as a comma-separated list with repeats
+When parseTenderListQuery is called
+Then list and count params carry the distinct tag IDs (tenders must have all of them)
+
+Given a saved filter definition with string, number and boolean values
+When parseSavedFilter is called
+Then values are normalized to query-string form, empty strings are dropped,
+and unknown or view-specific parameters (page, cursor) and invalid values are rejected
+
+Given organization_id in the query string
+When parseTenderListQuery is called
+Then it is ignored: the organization scope is set by the handler and reaches list and count params
+
+Given cursor (empty for the first page, next_cursor afterwards)
+When parseTenderListQuery is called
+Then list params order by created_at/id, request page_size+1 rows without offset and carry the cursor key
+And nextCursor drops the extra row and encodes the last returned row
+
+Given cursor together with page, sort_by or sort_order, or a malformed cursor
+When parseTenderListQuery is called
+Then an error is returned
+*/
+
+func TestParseTenderListQuery_Defaults(t *testing.T) {
+	q, err := parseTenderListQuery(url.Values{})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), q.Page)
+	assert.Equal(t, int32(10), q.PageSize)
+	assert.Equal(t, "date", q.SortBy)
+	assert.True(t, q.SortDesc)
+	assert.False(t, q.CategoryID.Valid)
+	assert.False(t, q.DateFrom.Valid)
+	assert.False(t, q.HasWinner.Valid)
+	assert.False(t, q.IncludeDeleted)
+
+	params := q.listParams()
+	assert.Equal(t, int32(10), params.PageLimit)
+	assert.Equal(t, int32(0), params.PageOffset)
+}
+
+func TestParseTenderListQuery_AllFilters(t *testing.T) {
+	values := url.Values{
+		"page":        {"3"},
+		"page_size":   {"20"},
+		"category_id": {"1"},
+		"chapter_id":  {"2"},
+		"type_id":     {"3"},
+		"executor_id": {"4"},
+		"object_id":   {"5"},
+		"date_from":   {"2025-01-01"},
+		"date_to":     {"2025-01-31"},
+		"has_winner":  {"true"},
+		"sort_by":     {"total_cost"},
+		"sort_order":  {"asc"},
+
+		"include_deleted": {"true"},
+	}
+
+	q, err := parseTenderListQuery(values)
+
+	require.NoError(t, err)
+	params := q.listParams()
+	assert.Equal(t, int32(20), params.PageLimit)
+	assert.Equal(t, int32(40), params.PageOffset)
+	assert.Equal(t, int64(1), params.CategoryID.Int64)
+	assert.Equal(t, int64(2), params.ChapterID.Int64)
+	assert.Equal(t, int64(3), params.TypeID.Int64)
+	assert.Equal(t, int64(4), params.ExecutorID.Int64)
+	assert.Equal(t, int64(5), params.ObjectID.Int64)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), params.DateFrom.Time)
+	assert.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), params.DateTo.Time, "date_to is inclusive")
+	assert.True(t, params.HasWinner.Valid && params.HasWinner.Bool)
+	assert.Equal(t, "total_cost", params.SortBy)
+	assert.False(t, params.SortDesc)
+
+	count := q.countParams()
+	assert.Equal(t, params.CategoryID, count.CategoryID)
+	assert.Equal(t, params.DateTo, count.DateTo)
+	assert.Equal(t, params.HasWinner, count.HasWinner)
+	assert.True(t, params.IncludeDeleted)
+	assert.True(t, count.IncludeDeleted)
+}
+
+func TestParseTenderListQuery_TagIDs(t *testing.T) {
+	q, err := parseTenderListQuery(url.Values{"tag_ids": {"7, 3,7"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int64{7, 3}, q.listParams().TagIds)
+	assert.Equal(t, []int64{7, 3}, q.countParams().TagIds)
+}
+
+func TestParseSavedFilter(t *testing.T) {
+	filters, q, err := parseSavedFilter(map[string]interface{}{
+		"category_id": float64(3),
+		"has_winner":  true,
+		"tag_ids":     "7,8",
+		"date_from":   "",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"category_id": "3", "has_winner": "true", "tag_ids": "7,8"}, filters)
+	assert.Equal(t, int64(3), q.CategoryID.Int64)
+	assert.Equal(t, []int64{7, 8}, q.TagIDs)
+}
+
+func TestParseSavedFilter_InvalidInput_ReturnsError(t *testing.T) {
+	cases := map[string]map[string]interface{}{
+		"unknown param":  {"region": "Москва"},
+		"page":           {"page": float64(2)},
+		"cursor":         {"cursor": ""},
+		"array value":    {"tag_ids": []interface{}{float64(1)}},
+		"invalid value":  {"category_id": "abc"},
+		"fractional id":  {"object_id": 1.5},
+		"bad sort field": {"sort_by": "etp_id"},
+	}
+
+	for name, raw := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := parseSavedFilter(raw)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseTenderListQuery_OrganizationScope(t *testing.T) {
+	q, err := parseTenderListQuery(url.Values{"organization_id": {"2"}})
+	require.NoError(t, err)
+	assert.False(t, q.OrganizationID.Valid, "organization is never taken from the query string")
+
+	q.OrganizationID = sql.NullInt64{Int64: 3, Valid: true}
+	assert.Equal(t, q.OrganizationID, q.listParams().OrganizationID)
+	assert.Equal(t, q.OrganizationID, q.countParams().OrganizationID)
+	assert.Equal(t, q.OrganizationID, q.exportBatchParams(0).OrganizationID)
+}
+
+func TestParseTenderListQuery_Cursor(t *testing.T) {
+	q, err := parseTenderListQuery(url.Values{"cursor": {""}, "page_size": {"2"}})
+	require.NoError(t, err)
+	assert.True(t, q.UseCursor)
+	assert.Nil(t, q.After)
+
+	params := q.listParams()
+	assert.Equal(t, "created", params.SortBy)
+	assert.Equal(t, int32(3), params.PageLimit)
+	assert.Zero(t, params.PageOffset)
+	assert.False(t, params.AfterID.Valid)
+
+	created := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	rows, next := q.nextCursor([]db.ListTendersRow{
+		{ID: 30, CreatedAt: created.Add(time.Hour)},
+		{ID: 20, CreatedAt: created},
+		{ID: 10, CreatedAt: created},
+	})
+	require.Len(t, rows, 2)
+	require.NotEmpty(t, next)
+
+	q, err = parseTenderListQuery(url.Values{"cursor": {next}, "page_size": {"2"}})
+	require.NoError(t, err)
+	params = q.listParams()
+	assert.Equal(t, sql.NullInt64{Int64: 20, Valid: true}, params.AfterID)
+	assert.True(t, params.AfterCreatedAt.Valid && params.AfterCreatedAt.Time.Equal(created))
+
+	rows, next = q.nextCursor([]db.ListTendersRow{{ID: 10, CreatedAt: created}})
+	assert.Len(t, rows, 1)
+	assert.Empty(t, next, "last page")
+}
+
+func TestParseTenderListQuery_InvalidInput_ReturnsError(t *testing.T) {
+	cases := map[string]url.Values{
+		"page zero":         {"page": {"0"}},
+		"page_size too big": {"page_size": {"101"}},
+		"negative id":       {"category_id": {"-1"}},
+		"non-numeric id":    {"executor_id": {"abc"}},
+		"bad date":          {"date_from": {"01-01-2025"}},
+		"date range":        {"date_from": {"2025-02-01"}, "date_to": {"2025-01-01"}},
+		"bad bool":          {"has_winner": {"maybe"}},
+		"unknown sort":      {"sort_by": {"etp_id; DROP TABLE tenders"}},
+		"unknown order":     {"sort_order": {"sideways"}},
+		"bad deleted flag":  {"include_deleted": {"all"}},
+		"bad tag id":        {"tag_ids": {"3,x"}},
+		"zero tag id":       {"tag_ids": {"0"}},
+		"too many tags":     {"tag_ids": {"1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21"}},
+		"cursor with page":  {"cursor": {""}, "page": {"2"}},
+		"cursor with sort":  {"cursor": {""}, "sort_by": {"title"}},
+		"cursor with order": {"cursor": {""}, "sort_order": {"asc"}},
+		"malformed cursor":  {"cursor": {"!!!"}},
+	}
+
+	for name, values := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseTenderListQuery(values)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package server
+
+import (
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/tsgen"
+)
+
+//go:generate go run ../../tsgen -out ../../../docs/api/types.gen.ts
+
+// TypeScriptDTOs возвращает корневые типы ответов API, для которых генерируются
+// TypeScript-интерфейсы фронтенда (make ts-types). Вложенные структуры
+// (лоты, предложения, ячейки матрицы) попадают в вывод автоматически.
+//
+// При добавлении нового ответа, который использует фронтенд, его нужно
+// зарегистрировать здесь — иначе типы на фронте снова начнут расходиться с Go.
+func TypeScriptDTOs() []tsgen.Root {
+	return []tsgen.Root{
+		// Тендеры
+		{Name: "TenderListPage", Value: listTendersPageResponse{}},
+		{Name: "TenderPage", Value: tenderPageResponse{}},
+
+		// Предложения
+		{Name: "ProposalSummary", Value: proposalResponse{}},
+		{Value: ProposalFullDetailsResponse{}},
+
+		// Сравнение и победители
+		{Value: api_models.LotComparisonResponse{}},
+		{Value: WinnerResponse{}},
+	}
+}
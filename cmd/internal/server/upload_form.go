@@ -0,0 +1,155 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+)
+
+// uploadFormOverhead — запас сверх upload.max_file_size на заголовки частей
+// и текстовые поля формы при ограничении всего тела запроса.
+const uploadFormOverhead = 1 << 20
+
+// uploadFieldLimit — максимальная длина текстового поля формы (enable_ai).
+const uploadFieldLimit = 64
+
+var (
+	// errUploadFileMissing — в форме нет части file.
+	errUploadFileMissing = i18n.Errorf("upload.file_missing")
+	// errUploadUnsupported — файл не xlsx (расширение, Content-Type или содержимое).
+	errUploadUnsupported = i18n.Errorf("upload.unsupported")
+	// errUploadTooLarge — файл больше upload.max_file_size.
+	errUploadTooLarge = i18n.Errorf("upload.too_large")
+)
+
+// xlsxSignature — сигнатура локального заголовка ZIP: xlsx — ZIP-архив.
+var xlsxSignature = []byte("PK\x03\x04")
+
+// uploadForm — форма загрузки, прочитанная до начала файла. Остаток формы
+// (содержимое файла и поля после него) читается потоком в writeTo.
+type uploadForm struct {
+	reader   *multipart.Reader
+	content  *bufio.Reader // Содержимое файла; первые байты уже проверены
+	fileName string
+	enableAI string
+	size     int64 // Размер файла; известен после writeTo
+}
+
+// readUploadForm читает части формы до файла и проверяет файл: расширение
+// .xlsx, Content-Type из allowedTypes и сигнатуру ZIP в начале содержимого.
+// Сам файл не читается дальше сигнатуры.
+func readUploadForm(reader *multipart.Reader, allowedTypes []string) (*uploadForm, error) {
+	form := &uploadForm{reader: reader, enableAI: "false"}
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, errUploadFileMissing
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if part.FormName() != "file" {
+			if err := form.readField(part); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		form.fileName = part.FileName()
+		if strings.ToLower(filepath.Ext(form.fileName)) != ".xlsx" {
+			return nil, i18n.Errorf("upload.unsupported_extension", errUploadUnsupported, filepath.Ext(form.fileName))
+		}
+		contentType, _, err := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if err != nil || !containsFold(allowedTypes, contentType) {
+			return nil, i18n.Errorf("upload.unsupported_content_type", errUploadUnsupported, part.Header.Get("Content-Type"))
+		}
+
+		form.content = bufio.NewReader(part)
+		signature, err := form.content.Peek(len(xlsxSignature))
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if !bytes.Equal(signature, xlsxSignature) {
+			return nil, i18n.Errorf("upload.not_xlsx_archive", errUploadUnsupported)
+		}
+		return form, nil
+	}
+}
+
+// readField запоминает enable_ai; остальные поля клиента пропускаются
+// (organization_id задаётся сервером).
+func (f *uploadForm) readField(part *multipart.Part) error {
+	value, err := io.ReadAll(io.LimitReader(part, uploadFieldLimit+1))
+	if err != nil {
+		return err
+	}
+	if part.FormName() != "enable_ai" {
+		return nil
+	}
+	if len(value) > uploadFieldLimit {
+		return i18n.Errorf("upload.enable_ai_too_long")
+	}
+	f.enableAI = strings.TrimSpace(string(value))
+	return nil
+}
+
+// writeTo передаёт форму парсеру: файл под именем file, затем enable_ai и
+// organization_id. Поля после файла дочитываются из исходной формы. Файл
+// больше maxSize прерывает передачу с errUploadTooLarge.
+func (f *uploadForm) writeTo(writer *multipart.Writer, maxSize, organizationID int64) error {
+	part, err := writer.CreateFormFile("file", f.fileName)
+	if err != nil {
+		return err
+	}
+	f.size, err = io.Copy(part, io.LimitReader(f.content, maxSize+1))
+	if err != nil {
+		return err
+	}
+	if f.size > maxSize {
+		return errUploadTooLarge
+	}
+
+	for {
+		next, err := f.reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if next.FormName() == "file" {
+			return i18n.Errorf("upload.single_file")
+		}
+		if err := f.readField(next); err != nil {
+			return err
+		}
+	}
+
+	if err := writer.WriteField("enable_ai", f.enableAI); err != nil {
+		return err
+	}
+	// Организация пользователя: парсер возвращает ее в JSON тендера
+	// (FullTenderData.OrganizationID), и импорт создает тендер в ней
+	if err := writer.WriteField("organization_id", strconv.FormatInt(organizationID, 10)); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
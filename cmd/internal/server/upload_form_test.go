@@ -0,0 +1,138 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR UPLOAD FORM STREAMING (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Wasted parser time — a PDF or a renamed text file must be rejected before
+   it reaches the parser
+2. OOM — a huge file must be cut off at upload.max_file_size while streaming
+3. Lost options — enable_ai must reach the parser wherever it is in the form,
+   and organization_id must always come from the server
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: readUploadForm
+- GIVEN a form without the file part → errUploadFileMissing
+- GIVEN a wrong extension, a Content-Type outside the whitelist or content
+  without the ZIP signature → errUploadUnsupported
+
+SCENARIO 2: writeTo
+- GIVEN a valid xlsx with enable_ai after the file and a forged organization_id
+  THEN the parser form has the file, enable_ai=true and the server organization
+- GIVEN a file above the limit → errUploadTooLarge
+*/
+
+const xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+type testFormPart struct {
+	field       string
+	fileName    string
+	contentType string
+	content     string
+}
+
+func buildUploadForm(t *testing.T, parts ...testFormPart) *multipart.Reader {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for _, p := range parts {
+		header := textproto.MIMEHeader{}
+		if p.fileName != "" {
+			header.Set("Content-Disposition", `form-data; name="`+p.field+`"; filename="`+p.fileName+`"`)
+			header.Set("Content-Type", p.contentType)
+		} else {
+			header.Set("Content-Disposition", `form-data; name="`+p.field+`"`)
+		}
+		w, err := writer.CreatePart(header)
+		require.NoError(t, err)
+		_, err = io.WriteString(w, p.content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return multipart.NewReader(body, writer.Boundary())
+}
+
+func xlsxPart(content string) testFormPart {
+	return testFormPart{field: "file", fileName: "tender.xlsx", contentType: xlsxContentType, content: "PK\x03\x04" + content}
+}
+
+func TestReadUploadForm_Rejects(t *testing.T) {
+	testCases := []struct {
+		name    string
+		parts   []testFormPart
+		wantErr error
+	}{
+		{
+			name:    "no file",
+			parts:   []testFormPart{{field: "enable_ai", content: "true"}},
+			wantErr: errUploadFileMissing,
+		},
+		{
+			name:    "wrong extension",
+			parts:   []testFormPart{{field: "file", fileName: "tender.pdf", contentType: xlsxContentType, content: "PK\x03\x04"}},
+			wantErr: errUploadUnsupported,
+		},
+		{
+			name:    "content type outside whitelist",
+			parts:   []testFormPart{{field: "file", fileName: "tender.xlsx", contentType: "text/plain", content: "PK\x03\x04"}},
+			wantErr: errUploadUnsupported,
+		},
+		{
+			name:    "not a zip archive",
+			parts:   []testFormPart{{field: "file", fileName: "tender.xlsx", contentType: xlsxContentType, content: "id;title"}},
+			wantErr: errUploadUnsupported,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := readUploadForm(buildUploadForm(t, tc.parts...), []string{xlsxContentType})
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestUploadForm_WriteTo(t *testing.T) {
+	reader := buildUploadForm(t,
+		testFormPart{field: "organization_id", content: "999"},
+		xlsxPart("sheet data"),
+		testFormPart{field: "enable_ai", content: "true"},
+	)
+	form, err := readUploadForm(reader, []string{xlsxContentType})
+	require.NoError(t, err)
+	assert.Equal(t, "tender.xlsx", form.fileName)
+
+	out := &bytes.Buffer{}
+	writer := multipart.NewWriter(out)
+	require.NoError(t, form.writeTo(writer, 1024, 7))
+	assert.Equal(t, int64(len("PK\x03\x04sheet data")), form.size)
+
+	parsed, err := multipart.NewReader(out, writer.Boundary()).ReadForm(1 << 20)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"true"}, parsed.Value["enable_ai"])
+	assert.Equal(t, []string{"7"}, parsed.Value["organization_id"])
+	require.Len(t, parsed.File["file"], 1)
+	assert.Equal(t, "tender.xlsx", parsed.File["file"][0].Filename)
+}
+
+func TestUploadForm_WriteTo_TooLarge(t *testing.T) {
+	form, err := readUploadForm(buildUploadForm(t, xlsxPart("0123456789")), []string{xlsxContentType})
+	require.NoError(t, err)
+
+	err = form.writeTo(multipart.NewWriter(io.Discard), 8, 7)
+	assert.ErrorIs(t, err, errUploadTooLarge)
+}
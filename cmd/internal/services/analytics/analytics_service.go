@@ -0,0 +1,322 @@
+// Package analytics предоставляет сервисный слой для аналитики стоимости:
+// агрегаты по лоту и история цен позиции каталога.
+//
+// Агрегаты считаются в БД (запросы analytics.sql), сервис только проверяет
+// доступность лота и собирает ответ. Денежные значения не переводятся во float:
+// они передаются строками NUMERIC, как их вернул PostgreSQL. Суммы лота
+// пересчитываются в базовую валюту по курсам currency.Rates прямо в запросах.
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/currency"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/priceindex"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// AnalyticsService считает аналитику по лотам.
+type AnalyticsService struct {
+	store  db.Store
+	logger logging.Logger
+	rates  *currency.Rates
+}
+
+// NewAnalyticsService создаёт новый экземпляр AnalyticsService.
+func NewAnalyticsService(store db.Store, logger logging.Logger, rates *currency.Rates) *AnalyticsService {
+	return &AnalyticsService{
+		store:  store,
+		logger: logger,
+		rates:  rates,
+	}
+}
+
+// GetLotAnalytics возвращает агрегаты стоимости по лоту: итог baseline,
+// min/max/медиану/среднее итогов предложений, разброс в процентах,
+// отклонение каждого подрядчика от baseline и число позиций, в которых
+// подрядчик предложил самую низкую цену.
+//
+// Суммы в ответе — в базовой валюте. Предложения в валюте без курса остаются
+// в списке подрядчиков с исходным итогом, но в статистику и отклонения не входят.
+func (s *AnalyticsService) GetLotAnalytics(ctx context.Context, lotID int64) (*api_models.LotAnalyticsResponse, error) {
+	logger := s.logger.WithField("method", "GetLotAnalytics").WithField("lot_id", lotID)
+
+	lot, err := s.getVisibleLot(ctx, lotID)
+	if err != nil {
+		return nil, err
+	}
+
+	currencies, rates := s.rates.QueryArgs()
+
+	stats, err := s.store.GetLotCostStats(ctx, db.GetLotCostStatsParams{
+		Currencies: currencies,
+		Rates:      rates,
+		LotID:      lotID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка расчёта статистики лота %d: %w", lotID, err)
+	}
+
+	deviations, err := s.store.ListLotContractorDeviations(ctx, db.ListLotContractorDeviationsParams{
+		Currencies: currencies,
+		Rates:      rates,
+		LotID:      lotID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка расчёта отклонений лота %d: %w", lotID, err)
+	}
+
+	cheapest, err := s.store.CountLotCheapestPositions(ctx, db.CountLotCheapestPositionsParams{
+		Currencies: currencies,
+		Rates:      rates,
+		LotID:      lotID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подсчёта самых дешёвых позиций лота %d: %w", lotID, err)
+	}
+
+	response := buildLotAnalytics(lot, s.rates.Base(), stats, deviations, cheapest)
+	if response.UnconvertedProposalsCount > 0 {
+		logger.Warnf("Итоги %d предложений не пересчитаны в %s: нет курса валюты", response.UnconvertedProposalsCount, response.Currency)
+	}
+	logger.Infof("Аналитика лота: %d предложений, с итогом %d", response.ProposalsCount, response.PricedProposalsCount)
+	return response, nil
+}
+
+// getVisibleLot возвращает лот для аналитики. Лоты мягко удалённого тендера
+// скрываются вместе с ним.
+func (s *AnalyticsService) getVisibleLot(ctx context.Context, lotID int64) (db.Lot, error) {
+	if lotID <= 0 {
+		return db.Lot{}, apierrors.NewValidationError("request.id_positive_got", lotID)
+	}
+
+	lot, err := s.store.GetLotByID(ctx, lotID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.Lot{}, apierrors.NewNotFoundError("common.lot_not_found", lotID)
+		}
+		return db.Lot{}, fmt.Errorf("ошибка получения лота %d: %w", lotID, err)
+	}
+
+	tender, err := s.store.GetTenderByID(ctx, lot.TenderID)
+	if err != nil {
+		return db.Lot{}, fmt.Errorf("ошибка получения тендера лота %d: %w", lotID, err)
+	}
+	if tender.DeletedAt.Valid {
+		return db.Lot{}, apierrors.NewNotFoundError("common.lot_not_found", lotID)
+	}
+	return lot, nil
+}
+
+// GetLotTotals возвращает краткие агрегаты (итог baseline, лучшее предложение,
+// число предложений, экономия) для набора лотов одним запросом. Результат
+// содержит запись для каждого переданного лота: лоты без предложений получают
+// нулевые счётчики. Доступность лотов не проверяется — это делает вызывающий код.
+func (s *AnalyticsService) GetLotTotals(ctx context.Context, lotIDs []int64) (map[int64]api_models.LotTotals, error) {
+	if len(lotIDs) == 0 {
+		return map[int64]api_models.LotTotals{}, nil
+	}
+
+	currencies, rates := s.rates.QueryArgs()
+	rows, err := s.store.ListLotTotalsByLotIDs(ctx, db.ListLotTotalsByLotIDsParams{
+		Currencies: currencies,
+		Rates:      rates,
+		LotIds:     lotIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка расчёта итогов лотов %v: %w", lotIDs, err)
+	}
+
+	return buildLotTotals(lotIDs, s.rates.Base(), rows), nil
+}
+
+// GetCatalogPriceHistory возвращает цены за единицу позиции каталога во всех
+// тендерах (дата, подрядчик, единица измерения) и min/avg/max по кварталам.
+// Позиция, с которой ещё ничего не сопоставлено, возвращается с пустой историей.
+// organizationID ограничивает цены тендерами организации (Valid=false — все).
+// normalization != nil — цены дополнительно пересчитываются по индексам цен
+// к указанным региону и дате (см. applyPriceNormalization).
+func (s *AnalyticsService) GetCatalogPriceHistory(
+	ctx context.Context,
+	catalogPositionID int64,
+	organizationID sql.NullInt64,
+	normalization *PriceNormalizationTarget,
+) (*api_models.CatalogPriceHistoryResponse, error) {
+	logger := s.logger.WithField("method", "GetCatalogPriceHistory").WithField("catalog_position_id", catalogPositionID)
+
+	if catalogPositionID <= 0 {
+		return nil, apierrors.NewValidationError("request.id_positive_got", catalogPositionID)
+	}
+
+	position, err := s.store.GetCatalogPositionByID(ctx, catalogPositionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewNotFoundError("common.catalog_position_not_found", catalogPositionID)
+		}
+		return nil, fmt.Errorf("ошибка получения позиции каталога %d: %w", catalogPositionID, err)
+	}
+
+	items, err := s.store.ListCatalogPositionPriceHistory(ctx, db.ListCatalogPositionPriceHistoryParams{
+		CatalogPositionID: catalogPositionID,
+		OrganizationID:    organizationID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения истории цен позиции %d: %w", catalogPositionID, err)
+	}
+
+	quarters, err := s.store.ListCatalogPositionQuarterlyPrices(ctx, db.ListCatalogPositionQuarterlyPricesParams{
+		CatalogPositionID: catalogPositionID,
+		OrganizationID:    organizationID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка расчёта квартальных цен позиции %d: %w", catalogPositionID, err)
+	}
+
+	response := buildCatalogPriceHistory(position, items, quarters)
+	if normalization != nil {
+		indices, err := s.store.ListPriceIndices(ctx, sql.NullString{})
+		if err != nil {
+			return nil, fmt.Errorf("ошибка получения индексов цен: %w", err)
+		}
+		if err := applyPriceNormalization(response, items, priceindex.NewTable(indices), *normalization); err != nil {
+			return nil, err
+		}
+	}
+	logger.Infof("История цен: %d цен, %d кварталов", len(response.Items), len(response.Quarters))
+	return response, nil
+}
+
+// buildCatalogPriceHistory собирает ответ из строк БД.
+func buildCatalogPriceHistory(
+	position db.CatalogPosition,
+	items []db.ListCatalogPositionPriceHistoryRow,
+	quarters []db.ListCatalogPositionQuarterlyPricesRow,
+) *api_models.CatalogPriceHistoryResponse {
+	history := make([]api_models.CatalogPriceHistoryItem, 0, len(items))
+	for _, row := range items {
+		unitCost, _ := api_models.ParseMoney(row.UnitCost.String) // NULL отфильтрован в запросе
+		history = append(history, api_models.CatalogPriceHistoryItem{
+			PositionItemID:     row.PositionItemID,
+			TenderID:           row.TenderID,
+			TenderEtpID:        row.TenderEtpID,
+			TenderTitle:        row.TenderTitle,
+			LotID:              row.LotID,
+			Date:               row.PriceDate,
+			ContractorID:       row.ContractorID,
+			ContractorName:     row.ContractorName,
+			ContractorInn:      row.ContractorInn,
+			JobTitleInProposal: row.JobTitleInProposal,
+			Quantity:           nullStringPtr(row.Quantity),
+			UnitCost:           unitCost,
+			Unit:               nullStringPtr(row.UnitName),
+			Region:             nullStringPtr(row.TenderRegion),
+		})
+	}
+
+	stats := make([]api_models.CatalogPriceQuarter, 0, len(quarters))
+	for _, row := range quarters {
+		stats = append(stats, api_models.CatalogPriceQuarter{
+			Quarter:      row.Quarter,
+			QuarterStart: row.QuarterStart,
+			Unit:         nullStringPtr(row.UnitName),
+			PricesCount:  row.PricesCount,
+			MinUnitCost:  api_models.MoneyFromNullString(row.MinUnitCost),
+			AvgUnitCost:  api_models.MoneyFromNullString(row.AvgUnitCost),
+			MaxUnitCost:  api_models.MoneyFromNullString(row.MaxUnitCost),
+		})
+	}
+
+	return &api_models.CatalogPriceHistoryResponse{
+		CatalogPositionID: position.ID,
+		StandardJobTitle:  position.StandardJobTitle,
+		Items:             history,
+		Quarters:          stats,
+	}
+}
+
+// buildLotTotals раскладывает строки ListLotTotalsByLotIDs по лотам.
+func buildLotTotals(lotIDs []int64, baseCurrency string, rows []db.ListLotTotalsByLotIDsRow) map[int64]api_models.LotTotals {
+	totals := make(map[int64]api_models.LotTotals, len(lotIDs))
+	for _, id := range lotIDs {
+		totals[id] = api_models.LotTotals{Currency: baseCurrency}
+	}
+	for _, row := range rows {
+		totals[row.LotID] = api_models.LotTotals{
+			Currency:             baseCurrency,
+			BaselineTotal:        api_models.MoneyFromNullString(row.BaselineTotal),
+			BestOffer:            api_models.MoneyFromNullString(row.BestOffer),
+			ProposalsCount:       row.ProposalsCount,
+			PricedProposalsCount: row.PricedProposalsCount,
+			Savings:              api_models.MoneyFromNullString(row.SavingsAmount),
+			SavingsPercent:       nullStringPtr(row.SavingsPercent),
+		}
+	}
+	return totals
+}
+
+// buildLotAnalytics собирает ответ из строк БД. Вынесена отдельно, чтобы
+// сборку можно было тестировать без моков БД.
+func buildLotAnalytics(
+	lot db.Lot,
+	baseCurrency string,
+	stats db.GetLotCostStatsRow,
+	deviations []db.ListLotContractorDeviationsRow,
+	cheapest []db.CountLotCheapestPositionsRow,
+) *api_models.LotAnalyticsResponse {
+	cheapestByProposal := make(map[int64]db.CountLotCheapestPositionsRow, len(cheapest))
+	for _, row := range cheapest {
+		cheapestByProposal[row.ProposalID] = row
+	}
+
+	contractors := make([]api_models.LotContractorAnalytics, 0, len(deviations))
+	for _, d := range deviations {
+		counts := cheapestByProposal[d.ProposalID]
+		var originalTotal *api_models.Money
+		if d.Currency != baseCurrency {
+			originalTotal = api_models.MoneyFromNullString(d.OriginalTotalCost)
+		}
+		contractors = append(contractors, api_models.LotContractorAnalytics{
+			ProposalID:                   d.ProposalID,
+			ContractorID:                 d.ContractorID,
+			ContractorName:               d.ContractorName,
+			ContractorInn:                d.ContractorInn,
+			Currency:                     d.Currency,
+			OriginalTotalCost:            originalTotal,
+			TotalCost:                    api_models.MoneyFromNullString(d.TotalCost),
+			DeviationFromBaseline:        api_models.MoneyFromNullString(d.DeviationAmount),
+			DeviationFromBaselinePercent: nullStringPtr(d.DeviationPercent),
+			PricedPositionsCount:         counts.PricedPositionsCount,
+			CheapestPositionsCount:       counts.CheapestPositionsCount,
+		})
+	}
+
+	return &api_models.LotAnalyticsResponse{
+		LotID:                     lot.ID,
+		LotTitle:                  lot.LotTitle,
+		Currency:                  baseCurrency,
+		BaselineTotal:             api_models.MoneyFromNullString(stats.BaselineTotal),
+		ProposalsCount:            len(deviations),
+		PricedProposalsCount:      stats.PricedProposalsCount,
+		UnconvertedProposalsCount: stats.UnconvertedProposalsCount,
+		MinTotal:                  api_models.MoneyFromNullString(stats.MinTotal),
+		MaxTotal:                  api_models.MoneyFromNullString(stats.MaxTotal),
+		MedianTotal:               api_models.MoneyFromNullString(stats.MedianTotal),
+		AvgTotal:                  api_models.MoneyFromNullString(stats.AvgTotal),
+		SpreadPercent:             nullStringPtr(stats.SpreadPercent),
+		Contractors:               contractors,
+	}
+}
+
+func nullStringPtr(ns sql.NullString) *string {
+	if !ns.Valid {
+		return nil
+	}
+	s := ns.String
+	return &s
+}
@@ -0,0 +1,415 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/currency"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR LOT ANALYTICS (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Wrong numbers — NUMERIC aggregates reach the client as decimals (no float64), rounded to kopecks
+2. Misattributed counts — cheapest-position counts must land on the right contractor
+3. Leaks — lots of a soft-deleted tender must not be visible
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: GetLotAnalytics
+- GIVEN a lot with baseline and two priced proposals
+  WHEN GetLotAnalytics is called
+  THEN stats, deviations and cheapest counts are returned per contractor
+
+- GIVEN a proposal without total and without position prices
+  WHEN GetLotAnalytics is called
+  THEN its money fields are null and counts are zero
+
+- GIVEN proposals in USD (rate configured) and EUR (no rate)
+  WHEN GetLotAnalytics is called
+  THEN configured rates are passed to every aggregate query, sums are in the base currency,
+       the original total is kept for foreign currencies and unconverted proposals are counted
+
+SCENARIO 2: Errors
+- GIVEN id <= 0 → ValidationError, no DB calls
+- GIVEN unknown lot → NotFoundError
+- GIVEN lot of a soft-deleted tender → NotFoundError
+- GIVEN a DB error in aggregates → wrapped error
+
+SCENARIO 3: GetCatalogPriceHistory
+- GIVEN a catalog position matched in several tenders
+  WHEN GetCatalogPriceHistory is called
+  THEN every price is returned with tender, contractor and unit, plus quarterly stats,
+       both queries limited to the requester's organization
+
+- GIVEN a position nobody matched yet → empty (non-nil) items and quarters
+- GIVEN an unknown position → NotFoundError
+- GIVEN id <= 0 → ValidationError, no DB calls
+
+SCENARIO 4: GetLotTotals (tender page)
+- GIVEN a page of lots, one of them without proposals
+  WHEN GetLotTotals is called
+  THEN one query computes totals for all lots, and the lot without proposals gets zero counts
+- GIVEN no lots → empty map, no DB calls
+- GIVEN a DB error → wrapped error
+*/
+
+func setupTestService(t *testing.T) (*AnalyticsService, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	rates := currency.NewRates(config.CurrencyConfig{Base: "RUB", Rates: map[string]string{"USD": "90"}})
+	return NewAnalyticsService(mockStore, testutil.NewMockLogger(), rates), mockStore
+}
+
+// Курсы тестового сервиса в виде аргументов запросов.
+var (
+	testCurrencies = []string{"RUB", "USD"}
+	testRates      = []string{"1", "90"}
+)
+
+func costStatsParams(lotID int64) db.GetLotCostStatsParams {
+	return db.GetLotCostStatsParams{Currencies: testCurrencies, Rates: testRates, LotID: lotID}
+}
+
+func deviationsParams(lotID int64) db.ListLotContractorDeviationsParams {
+	return db.ListLotContractorDeviationsParams{Currencies: testCurrencies, Rates: testRates, LotID: lotID}
+}
+
+func cheapestParams(lotID int64) db.CountLotCheapestPositionsParams {
+	return db.CountLotCheapestPositionsParams{Currencies: testCurrencies, Rates: testRates, LotID: lotID}
+}
+
+func ns(v string) sql.NullString {
+	return sql.NullString{String: v, Valid: true}
+}
+
+func expectVisibleLot(mockStore *db.MockStore, lotID int64) {
+	mockStore.EXPECT().GetLotByID(gomock.Any(), lotID).
+		Return(db.Lot{ID: lotID, LotTitle: "Лот №1", TenderID: 10}, nil)
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(10)).
+		Return(db.Tender{ID: 10}, nil)
+}
+
+func TestGetLotAnalytics_Success(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	// GIVEN baseline 1000.00 and two priced proposals + one without total
+	expectVisibleLot(mockStore, 5)
+	mockStore.EXPECT().GetLotCostStats(gomock.Any(), costStatsParams(5)).Return(db.GetLotCostStatsRow{
+		BaselineTotal:        ns("1000.00"),
+		PricedProposalsCount: 2,
+		MinTotal:             ns("900.00"),
+		MaxTotal:             ns("1200.00"),
+		AvgTotal:             ns("1050.00"),
+		MedianTotal:          ns("1050.00"),
+		SpreadPercent:        ns("33.33"),
+	}, nil)
+	mockStore.EXPECT().ListLotContractorDeviations(gomock.Any(), deviationsParams(5)).Return([]db.ListLotContractorDeviationsRow{
+		{ProposalID: 101, ContractorID: 1, ContractorName: "ООО Альфа", ContractorInn: "7700000001", Currency: "RUB",
+			OriginalTotalCost: ns("900.00"), TotalCost: ns("900.00"), DeviationAmount: ns("-100.00"), DeviationPercent: ns("-10.00")},
+		{ProposalID: 102, ContractorID: 2, ContractorName: "ООО Бета", ContractorInn: "7700000002", Currency: "RUB",
+			OriginalTotalCost: ns("1200.00"), TotalCost: ns("1200.00"), DeviationAmount: ns("200.00"), DeviationPercent: ns("20.00")},
+		{ProposalID: 103, ContractorID: 3, ContractorName: "ООО Гамма", ContractorInn: "7700000003", Currency: "RUB"},
+	}, nil)
+	mockStore.EXPECT().CountLotCheapestPositions(gomock.Any(), cheapestParams(5)).Return([]db.CountLotCheapestPositionsRow{
+		{ProposalID: 102, PricedPositionsCount: 10, CheapestPositionsCount: 3},
+		{ProposalID: 101, PricedPositionsCount: 10, CheapestPositionsCount: 7},
+	}, nil)
+
+	// WHEN
+	resp, err := service.GetLotAnalytics(context.Background(), 5)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), resp.LotID)
+	assert.Equal(t, "Лот №1", resp.LotTitle)
+	assert.Equal(t, "RUB", resp.Currency)
+	assert.Equal(t, "1000.00", resp.BaselineTotal.String())
+	assert.Equal(t, 3, resp.ProposalsCount)
+	assert.Equal(t, int64(2), resp.PricedProposalsCount)
+	assert.Equal(t, "900.00", resp.MinTotal.String())
+	assert.Equal(t, "1200.00", resp.MaxTotal.String())
+	assert.Equal(t, "1050.00", resp.MedianTotal.String())
+	assert.Equal(t, "33.33", *resp.SpreadPercent)
+
+	require.Len(t, resp.Contractors, 3)
+	alpha := resp.Contractors[0]
+	assert.Equal(t, int64(101), alpha.ProposalID)
+	assert.Equal(t, "-10.00", *alpha.DeviationFromBaselinePercent)
+	assert.Equal(t, "-100.00", alpha.DeviationFromBaseline.String())
+	assert.Nil(t, alpha.OriginalTotalCost, "original total is only reported for foreign currencies")
+	assert.Equal(t, int64(7), alpha.CheapestPositionsCount)
+	assert.Equal(t, int64(3), resp.Contractors[1].CheapestPositionsCount)
+
+	gamma := resp.Contractors[2]
+	assert.Nil(t, gamma.TotalCost)
+	assert.Nil(t, gamma.DeviationFromBaselinePercent)
+	assert.Zero(t, gamma.PricedPositionsCount)
+	assert.Zero(t, gamma.CheapestPositionsCount)
+}
+
+func TestGetLotAnalytics_ForeignCurrencies(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	// GIVEN a USD proposal (10.00 USD = 900.00 RUB) and a EUR proposal without a configured rate
+	expectVisibleLot(mockStore, 5)
+	mockStore.EXPECT().GetLotCostStats(gomock.Any(), costStatsParams(5)).Return(db.GetLotCostStatsRow{
+		BaselineTotal:             ns("1000.00"),
+		PricedProposalsCount:      1,
+		UnconvertedProposalsCount: 1,
+		MinTotal:                  ns("900.00"),
+		MaxTotal:                  ns("900.00"),
+	}, nil)
+	mockStore.EXPECT().ListLotContractorDeviations(gomock.Any(), deviationsParams(5)).Return([]db.ListLotContractorDeviationsRow{
+		{ProposalID: 101, ContractorName: "Acme Inc", Currency: "USD",
+			OriginalTotalCost: ns("10.00"), TotalCost: ns("900.00"), DeviationAmount: ns("-100.00"), DeviationPercent: ns("-10.00")},
+		{ProposalID: 102, ContractorName: "Euro GmbH", Currency: "EUR", OriginalTotalCost: ns("12.00")},
+	}, nil)
+	mockStore.EXPECT().CountLotCheapestPositions(gomock.Any(), cheapestParams(5)).Return(nil, nil)
+
+	// WHEN
+	resp, err := service.GetLotAnalytics(context.Background(), 5)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, "RUB", resp.Currency)
+	assert.Equal(t, int64(1), resp.PricedProposalsCount)
+	assert.Equal(t, int64(1), resp.UnconvertedProposalsCount)
+
+	require.Len(t, resp.Contractors, 2)
+	usd := resp.Contractors[0]
+	assert.Equal(t, "USD", usd.Currency)
+	assert.Equal(t, "10.00", usd.OriginalTotalCost.String())
+	assert.Equal(t, "900.00", usd.TotalCost.String())
+
+	eur := resp.Contractors[1]
+	assert.Equal(t, "EUR", eur.Currency)
+	assert.Equal(t, "12.00", eur.OriginalTotalCost.String())
+	assert.Nil(t, eur.TotalCost, "no rate — no base-currency total")
+	assert.Nil(t, eur.DeviationFromBaseline)
+}
+
+func TestGetLotAnalytics_NoProposals_EmptyContractors(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	// GIVEN a lot with only the baseline
+	expectVisibleLot(mockStore, 5)
+	mockStore.EXPECT().GetLotCostStats(gomock.Any(), costStatsParams(5)).
+		Return(db.GetLotCostStatsRow{BaselineTotal: ns("1000.00")}, nil)
+	mockStore.EXPECT().ListLotContractorDeviations(gomock.Any(), deviationsParams(5)).Return(nil, nil)
+	mockStore.EXPECT().CountLotCheapestPositions(gomock.Any(), cheapestParams(5)).Return(nil, nil)
+
+	// WHEN
+	resp, err := service.GetLotAnalytics(context.Background(), 5)
+
+	// THEN
+	require.NoError(t, err)
+	assert.NotNil(t, resp.Contractors)
+	assert.Empty(t, resp.Contractors)
+	assert.Nil(t, resp.MinTotal)
+	assert.Nil(t, resp.SpreadPercent)
+}
+
+func TestGetLotAnalytics_InvalidID_ReturnsValidationError(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.GetLotAnalytics(context.Background(), 0)
+
+	var validationErr *apierrors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestGetLotAnalytics_LotNotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetLotByID(gomock.Any(), int64(5)).Return(db.Lot{}, sql.ErrNoRows)
+
+	_, err := service.GetLotAnalytics(context.Background(), 5)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestGetLotAnalytics_DeletedTender_ReturnsNotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetLotByID(gomock.Any(), int64(5)).
+		Return(db.Lot{ID: 5, TenderID: 10}, nil)
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(10)).
+		Return(db.Tender{ID: 10, DeletedAt: sql.NullTime{Time: time.Now(), Valid: true}}, nil)
+
+	_, err := service.GetLotAnalytics(context.Background(), 5)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestGetLotAnalytics_DBError_ReturnsWrappedError(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	dbErr := errors.New("canceling statement due to statement timeout")
+	expectVisibleLot(mockStore, 5)
+	mockStore.EXPECT().GetLotCostStats(gomock.Any(), costStatsParams(5)).Return(db.GetLotCostStatsRow{}, dbErr)
+
+	_, err := service.GetLotAnalytics(context.Background(), 5)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, dbErr)
+	var notFoundErr *apierrors.NotFoundError
+	assert.False(t, errors.As(err, &notFoundErr))
+}
+
+func TestGetLotTotals_Success(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	// GIVEN lots 5 and 6; only lot 5 has proposals
+	mockStore.EXPECT().ListLotTotalsByLotIDs(gomock.Any(), db.ListLotTotalsByLotIDsParams{
+		Currencies: testCurrencies,
+		Rates:      testRates,
+		LotIds:     []int64{5, 6},
+	}).Return([]db.ListLotTotalsByLotIDsRow{
+		{LotID: 5, BaselineTotal: ns("1000.00"), BestOffer: ns("900.00"), ProposalsCount: 3, PricedProposalsCount: 2,
+			SavingsAmount: ns("100.00"), SavingsPercent: ns("10.00")},
+	}, nil)
+
+	// WHEN
+	totals, err := service.GetLotTotals(context.Background(), []int64{5, 6})
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, totals, 2)
+
+	lot5 := totals[5]
+	assert.Equal(t, "RUB", lot5.Currency)
+	assert.Equal(t, "1000.00", lot5.BaselineTotal.String())
+	assert.Equal(t, "900.00", lot5.BestOffer.String())
+	assert.Equal(t, int64(3), lot5.ProposalsCount)
+	assert.Equal(t, int64(2), lot5.PricedProposalsCount)
+	assert.Equal(t, "100.00", lot5.Savings.String())
+	assert.Equal(t, "10.00", *lot5.SavingsPercent)
+
+	lot6 := totals[6]
+	assert.Equal(t, "RUB", lot6.Currency)
+	assert.Zero(t, lot6.ProposalsCount)
+	assert.Nil(t, lot6.BestOffer)
+	assert.Nil(t, lot6.SavingsPercent)
+}
+
+func TestGetLotTotals_NoLots_NoQuery(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	totals, err := service.GetLotTotals(context.Background(), nil)
+
+	require.NoError(t, err)
+	assert.Empty(t, totals)
+}
+
+func TestGetLotTotals_DBError_ReturnsWrappedError(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	dbErr := errors.New("connection refused")
+
+	mockStore.EXPECT().ListLotTotalsByLotIDs(gomock.Any(), gomock.Any()).Return(nil, dbErr)
+
+	_, err := service.GetLotTotals(context.Background(), []int64{5})
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, dbErr)
+}
+
+// testOrganization — организация пользователя, запрашивающего историю цен.
+var testOrganization = sql.NullInt64{Int64: 1, Valid: true}
+
+func TestGetCatalogPriceHistory_Success(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	q3 := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	// GIVEN position 42 priced in two tenders
+	mockStore.EXPECT().GetCatalogPositionByID(gomock.Any(), int64(42)).
+		Return(db.CatalogPosition{ID: 42, StandardJobTitle: "устройство бетонный основание"}, nil)
+	mockStore.EXPECT().ListCatalogPositionPriceHistory(gomock.Any(), db.ListCatalogPositionPriceHistoryParams{CatalogPositionID: 42, OrganizationID: testOrganization}).
+		Return([]db.ListCatalogPositionPriceHistoryRow{
+			{PositionItemID: 2, TenderID: 20, TenderEtpID: "ETP-20", LotID: 200, PriceDate: q3.AddDate(0, 1, 0),
+				ContractorID: 2, ContractorName: "ООО Бета", ContractorInn: "7700000002",
+				JobTitleInProposal: "Бетонное основание", Quantity: ns("12.5"), UnitCost: ns("1449.9950"), UnitName: ns("м3")},
+			{PositionItemID: 1, TenderID: 10, TenderEtpID: "ETP-10", LotID: 100, PriceDate: q3,
+				ContractorID: 1, ContractorName: "ООО Альфа", ContractorInn: "7700000001",
+				JobTitleInProposal: "Устройство основания", UnitCost: ns("1300.00")},
+		}, nil)
+	mockStore.EXPECT().ListCatalogPositionQuarterlyPrices(gomock.Any(), db.ListCatalogPositionQuarterlyPricesParams{CatalogPositionID: 42, OrganizationID: testOrganization}).
+		Return([]db.ListCatalogPositionQuarterlyPricesRow{
+			{QuarterStart: q3, Quarter: "2025-Q3", UnitName: ns("м3"), PricesCount: 2,
+				MinUnitCost: ns("1300.00"), AvgUnitCost: ns("1375.00"), MaxUnitCost: ns("1450.00")},
+		}, nil)
+
+	// WHEN
+	resp, err := service.GetCatalogPriceHistory(context.Background(), 42, testOrganization, nil)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), resp.CatalogPositionID)
+	require.Len(t, resp.Items, 2)
+	assert.Equal(t, "1450.00", resp.Items[0].UnitCost.String(), "копейки округляются половиной от нуля")
+	assert.Equal(t, "м3", *resp.Items[0].Unit)
+	assert.Equal(t, "12.5", *resp.Items[0].Quantity)
+	assert.Equal(t, "ООО Альфа", resp.Items[1].ContractorName)
+	assert.Nil(t, resp.Items[1].Unit)
+	assert.Nil(t, resp.Items[1].Quantity)
+
+	require.Len(t, resp.Quarters, 1)
+	assert.Equal(t, "2025-Q3", resp.Quarters[0].Quarter)
+	assert.Equal(t, int64(2), resp.Quarters[0].PricesCount)
+	assert.Equal(t, "1375.00", resp.Quarters[0].AvgUnitCost.String())
+}
+
+func TestGetCatalogPriceHistory_NoMatches_EmptyLists(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetCatalogPositionByID(gomock.Any(), int64(42)).
+		Return(db.CatalogPosition{ID: 42}, nil)
+	mockStore.EXPECT().ListCatalogPositionPriceHistory(gomock.Any(), db.ListCatalogPositionPriceHistoryParams{CatalogPositionID: 42, OrganizationID: testOrganization}).Return(nil, nil)
+	mockStore.EXPECT().ListCatalogPositionQuarterlyPrices(gomock.Any(), db.ListCatalogPositionQuarterlyPricesParams{CatalogPositionID: 42, OrganizationID: testOrganization}).Return(nil, nil)
+
+	resp, err := service.GetCatalogPriceHistory(context.Background(), 42, testOrganization, nil)
+
+	require.NoError(t, err)
+	assert.NotNil(t, resp.Items)
+	assert.Empty(t, resp.Items)
+	assert.NotNil(t, resp.Quarters)
+	assert.Empty(t, resp.Quarters)
+}
+
+func TestGetCatalogPriceHistory_NotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetCatalogPositionByID(gomock.Any(), int64(42)).
+		Return(db.CatalogPosition{}, sql.ErrNoRows)
+
+	_, err := service.GetCatalogPriceHistory(context.Background(), 42, testOrganization, nil)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestGetCatalogPriceHistory_InvalidID_ReturnsValidationError(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.GetCatalogPriceHistory(context.Background(), -1, sql.NullInt64{}, nil)
+
+	var validationErr *apierrors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
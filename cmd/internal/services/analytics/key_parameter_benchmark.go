@@ -0,0 +1,241 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/jsonschema"
+)
+
+const (
+	// MaxBenchmarkLots — лотов в сравнении; более старые не учитываются.
+	MaxBenchmarkLots = 500
+	// MaxParameterNameLength — верхняя граница длины имени параметра в символах.
+	MaxParameterNameLength = 100
+)
+
+// Причины, по которым лот не вошёл в удельные цены (SkipReason).
+const (
+	SkipNoWinner    = "no_winner"    // У лота нет победителя
+	SkipNoPrice     = "no_price"     // Нет цены контракта и итога предложения (или курса)
+	SkipNotNumeric  = "not_numeric"  // Значение параметра не число
+	SkipNonPositive = "non_positive" // Значение <= 0: на него нельзя делить
+)
+
+// KeyParameterBenchmarkQuery — параметры сравнения лотов по ключевому параметру.
+type KeyParameterBenchmarkQuery struct {
+	CategoryID     int64
+	Parameter      string
+	OrganizationID sql.NullInt64 // Valid=false — все организации
+}
+
+// numberWithUnit — число в начале строки и единица после него: "1 200,5 м²",
+// "12 свай". Пробелы (в том числе неразрывные) — разделители разрядов, запятая
+// или точка — десятичный разделитель.
+var numberWithUnit = regexp.MustCompile(`^([+-]?\d[\d \x{00A0}\x{202F}]*(?:[.,]\d+)?)\s*(.*)$`)
+
+// CompareLotKeyParameter сравнивает лоты тендеров категории по ключевому
+// параметру: берёт значение parameter из lot_key_parameters, делит на него цену
+// победителя и возвращает удельные цены (за сваю, за м² фасада) с min/max/avg/
+// медианой. Если для категории задана схема ключевых параметров, параметр
+// должен быть в ней и иметь числовой или строковый тип.
+func (s *AnalyticsService) CompareLotKeyParameter(
+	ctx context.Context,
+	query KeyParameterBenchmarkQuery,
+) (*api_models.LotParameterBenchmarkResponse, error) {
+	parameter := strings.TrimSpace(query.Parameter)
+	if query.CategoryID <= 0 {
+		return nil, apierrors.NewValidationError("request.category_id_positive_got", query.CategoryID)
+	}
+	if parameter == "" || utf8.RuneCountInString(parameter) > MaxParameterNameLength {
+		return nil, apierrors.NewValidationError("analytics.parameter_length", MaxParameterNameLength)
+	}
+
+	category, err := s.store.GetTenderCategoryByID(ctx, query.CategoryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewNotFoundError("analytics.category_not_found", query.CategoryID)
+		}
+		return nil, fmt.Errorf("ошибка получения категории %d: %w", query.CategoryID, err)
+	}
+
+	schemaTypes, err := s.keyParameterTypes(ctx, query.CategoryID, parameter)
+	if err != nil {
+		return nil, err
+	}
+
+	currencies, rates := s.rates.QueryArgs()
+	rows, err := s.store.ListLotKeyParameterValues(ctx, db.ListLotKeyParameterValuesParams{
+		Currencies:     currencies,
+		Rates:          rates,
+		Parameter:      parameter,
+		CategoryID:     query.CategoryID,
+		OrganizationID: query.OrganizationID,
+		MaxLots:        MaxBenchmarkLots,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения параметра %q лотов категории %d: %w", parameter, query.CategoryID, err)
+	}
+
+	response := buildParameterBenchmark(rows)
+	response.CategoryID = category.ID
+	response.CategoryTitle = category.Title
+	response.Parameter = parameter
+	response.SchemaTypes = schemaTypes
+	response.Currency = s.rates.Base()
+	response.Truncated = len(rows) == MaxBenchmarkLots
+
+	s.logger.Infof("Удельные цены по параметру %q категории %d: лотов %d, с удельной ценой %d",
+		parameter, query.CategoryID, response.LotsCount, response.ComparedLotsCount)
+	return response, nil
+}
+
+// keyParameterTypes возвращает тип параметра по схеме ключевых параметров
+// категории. Без схемы тип не известен (nil): значения разбираются как есть.
+func (s *AnalyticsService) keyParameterTypes(ctx context.Context, categoryID int64, parameter string) ([]string, error) {
+	row, err := s.store.GetLotKeyParameterSchema(ctx, categoryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ошибка получения схемы ключевых параметров категории %d: %w", categoryID, err)
+	}
+	schema, err := jsonschema.Compile(row.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("некорректная схема ключевых параметров категории %d: %w", categoryID, err)
+	}
+
+	types, ok := schema.PropertyTypes(parameter)
+	if !ok {
+		return nil, apierrors.NewValidationError("analytics.parameter_not_in_schema", parameter, categoryID)
+	}
+	if len(types) > 0 && !slices.ContainsFunc(types, func(t string) bool {
+		return t == jsonschema.TypeNumber || t == jsonschema.TypeInteger || t == jsonschema.TypeString
+	}) {
+		return nil, apierrors.NewValidationError("analytics.parameter_not_numeric",
+			parameter, strings.Join(types, ", "))
+	}
+	return types, nil
+}
+
+// buildParameterBenchmark разбирает значения параметра, считает удельные цены
+// и их статистику. Единица ответа — самая частая единица строковых значений.
+func buildParameterBenchmark(rows []db.ListLotKeyParameterValuesRow) *api_models.LotParameterBenchmarkResponse {
+	response := &api_models.LotParameterBenchmarkResponse{
+		LotsCount: len(rows),
+		Items:     make([]api_models.LotParameterBenchmarkItem, 0, len(rows)),
+	}
+	units := make(map[string]int)
+	unitPrices := make([]decimal.Decimal, 0, len(rows))
+
+	for _, row := range rows {
+		item := api_models.LotParameterBenchmarkItem{
+			LotID:          row.LotID,
+			LotKey:         row.LotKey,
+			LotTitle:       row.LotTitle,
+			TenderID:       row.TenderID,
+			TenderEtpID:    row.TenderEtpID,
+			TenderTitle:    row.TenderTitle,
+			TenderDate:     row.TenderDate,
+			ContractorName: row.ContractorName,
+			RawValue:       row.Value,
+			WinningPrice:   api_models.MoneyFromNullString(row.WinningPrice),
+		}
+
+		value, unit, ok := parseParameterValue(row.ValueType, row.Value)
+		if ok {
+			item.Value = &value
+			item.Unit = unit
+			if unit != "" {
+				units[unit]++
+			}
+		}
+
+		switch {
+		case row.ContractorName == "":
+			item.SkipReason = SkipNoWinner
+		case item.WinningPrice == nil:
+			item.SkipReason = SkipNoPrice
+		case !ok:
+			item.SkipReason = SkipNotNumeric
+		case !value.IsPositive():
+			item.SkipReason = SkipNonPositive
+		default:
+			unitPrice := item.WinningPrice.Div(value)
+			item.UnitPrice = api_models.MoneyPtr(unitPrice)
+			unitPrices = append(unitPrices, unitPrice)
+		}
+		response.Items = append(response.Items, item)
+	}
+
+	response.ComparedLotsCount = len(unitPrices)
+	response.Benchmark = benchmarkStats(unitPrices)
+	for unit, count := range units {
+		if count > units[response.Unit] || (count == units[response.Unit] && unit < response.Unit) {
+			response.Unit = unit
+		}
+	}
+	return response
+}
+
+// parseParameterValue разбирает значение ключевого параметра: число JSON или
+// строку, начинающуюся с числа ("1 200 м²", "12,5"). Для строки возвращается и
+// единица после числа.
+func parseParameterValue(valueType, value string) (decimal.Decimal, string, bool) {
+	switch valueType {
+	case jsonschema.TypeNumber:
+		d, err := decimal.NewFromString(value)
+		return d, "", err == nil
+	case jsonschema.TypeString:
+		match := numberWithUnit.FindStringSubmatch(strings.TrimSpace(value))
+		if match == nil {
+			return decimal.Decimal{}, "", false
+		}
+		number := strings.Map(func(r rune) rune {
+			switch r {
+			case ' ', '\u00a0', '\u202f':
+				return -1
+			case ',':
+				return '.'
+			}
+			return r
+		}, match[1])
+		d, err := decimal.NewFromString(number)
+		if err != nil {
+			return decimal.Decimal{}, "", false
+		}
+		return d, strings.TrimSpace(match[2]), true
+	}
+	return decimal.Decimal{}, "", false
+}
+
+// benchmarkStats считает min/max/среднее/медиану удельных цен; без цен — nil.
+func benchmarkStats(values []decimal.Decimal) *api_models.LotParameterBenchmark {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := slices.Clone(values)
+	slices.SortFunc(sorted, func(a, b decimal.Decimal) int { return a.Cmp(b) })
+
+	n := len(sorted)
+	median := sorted[n/2]
+	if n%2 == 0 {
+		median = sorted[n/2-1].Add(sorted[n/2]).Div(decimal.NewFromInt(2))
+	}
+	return &api_models.LotParameterBenchmark{
+		Min:    api_models.NewMoney(sorted[0]),
+		Max:    api_models.NewMoney(sorted[n-1]),
+		Avg:    api_models.NewMoney(decimal.Sum(sorted[0], sorted[1:]...).Div(decimal.NewFromInt(int64(n)))),
+		Median: api_models.NewMoney(median),
+	}
+}
@@ -0,0 +1,207 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR KEY PARAMETER BENCHMARKS (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Apples to oranges — lots of different size are compared by price per unit of a key parameter
+2. Free-form AI values — "1 200,5 м²" and 12 must both be read as numbers
+3. Silent garbage — lots that cannot be compared are listed with the reason, not dropped
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Benchmark
+- GIVEN lots with numeric, string and broken values, with and without winners
+  WHEN CompareLotKeyParameter is called
+  THEN unit prices are winning price / value, min/max/avg/median are over them only
+  AND skipped lots carry no_winner, no_price, not_numeric or non_positive
+  AND the most frequent unit of string values is reported
+
+SCENARIO 2: Parameter typing
+- GIVEN a category schema where the parameter is boolean or not allowed → ValidationError
+- GIVEN a schema declaring the parameter as number → its types are in the response
+
+SCENARIO 3: Validation
+- GIVEN an empty parameter or category_id <= 0 → ValidationError, no DB calls
+- GIVEN a missing category → NotFoundError
+
+SCENARIO 4: parseParameterValue
+- GIVEN strings with thousand separators, decimal commas and units → number and unit
+*/
+
+func parameterRow(lotID int64, valueType, value, contractor string, price sql.NullString) db.ListLotKeyParameterValuesRow {
+	return db.ListLotKeyParameterValuesRow{
+		LotID:          lotID,
+		LotKey:         "LOT_1",
+		LotTitle:       "Свайное поле",
+		TenderID:       lotID * 10,
+		TenderEtpID:    "T-1",
+		TenderTitle:    "ЖК Север",
+		TenderDate:     time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		ValueType:      valueType,
+		Value:          value,
+		ContractorName: contractor,
+		WinningPrice:   price,
+	}
+}
+
+func priceOf(v string) sql.NullString { return sql.NullString{String: v, Valid: true} }
+
+func TestCompareLotKeyParameter_Benchmark(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	org := sql.NullInt64{Int64: 2, Valid: true}
+
+	mockStore.EXPECT().GetTenderCategoryByID(gomock.Any(), int64(4)).Return(db.TenderCategory{ID: 4, Title: "Сваи"}, nil)
+	mockStore.EXPECT().GetLotKeyParameterSchema(gomock.Any(), int64(4)).Return(db.LotKeyParameterSchema{}, sql.ErrNoRows)
+	mockStore.EXPECT().ListLotKeyParameterValues(gomock.Any(), db.ListLotKeyParameterValuesParams{
+		Currencies:     []string{"RUB", "USD"},
+		Rates:          []string{"1", "90"},
+		Parameter:      "piles",
+		CategoryID:     4,
+		OrganizationID: org,
+		MaxLots:        MaxBenchmarkLots,
+	}).Return([]db.ListLotKeyParameterValuesRow{
+		parameterRow(1, "number", "100", "ООО Альфа", priceOf("1000000")),
+		parameterRow(2, "string", "1 200 шт", "ООО Бета", priceOf("6000000")),
+		parameterRow(3, "string", "50,5 шт", "ООО Гамма", priceOf("505000")),
+		parameterRow(4, "number", "300", "", sql.NullString{}),
+		parameterRow(5, "number", "300", "ООО Дельта", sql.NullString{}),
+		parameterRow(6, "string", "много", "ООО Эпсилон", priceOf("1")),
+		parameterRow(7, "number", "0", "ООО Альфа", priceOf("1")),
+		parameterRow(8, "boolean", "true", "ООО Альфа", priceOf("1")),
+	}, nil)
+
+	resp, err := service.CompareLotKeyParameter(context.Background(), KeyParameterBenchmarkQuery{
+		CategoryID: 4, Parameter: " piles ", OrganizationID: org,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Сваи", resp.CategoryTitle)
+	assert.Equal(t, "piles", resp.Parameter)
+	assert.Nil(t, resp.SchemaTypes)
+	assert.Equal(t, "RUB", resp.Currency)
+	assert.Equal(t, "шт", resp.Unit)
+	assert.Equal(t, 8, resp.LotsCount)
+	assert.Equal(t, 3, resp.ComparedLotsCount)
+	assert.False(t, resp.Truncated)
+
+	require.Len(t, resp.Items, 8)
+	assert.Equal(t, "10000.00", resp.Items[0].UnitPrice.String())
+	assert.Equal(t, "1200", resp.Items[1].Value.String())
+	assert.Equal(t, "5000.00", resp.Items[1].UnitPrice.String())
+	assert.Equal(t, "10000.00", resp.Items[2].UnitPrice.String())
+
+	reasons := make([]string, 0, 5)
+	for _, item := range resp.Items[3:] {
+		assert.Nil(t, item.UnitPrice)
+		reasons = append(reasons, item.SkipReason)
+	}
+	assert.Equal(t, []string{SkipNoWinner, SkipNoPrice, SkipNotNumeric, SkipNonPositive, SkipNotNumeric}, reasons)
+
+	require.NotNil(t, resp.Benchmark)
+	assert.Equal(t, "5000.00", resp.Benchmark.Min.String())
+	assert.Equal(t, "10000.00", resp.Benchmark.Max.String())
+	assert.Equal(t, "8333.33", resp.Benchmark.Avg.String())
+	assert.Equal(t, "10000.00", resp.Benchmark.Median.String())
+}
+
+func TestCompareLotKeyParameter_SchemaTyping(t *testing.T) {
+	schema := json.RawMessage(`{
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {"piles": {"type": "integer"}, "has_basement": {"type": "boolean"}}
+	}`)
+
+	t.Run("numeric parameter", func(t *testing.T) {
+		service, mockStore := setupTestService(t)
+		mockStore.EXPECT().GetTenderCategoryByID(gomock.Any(), int64(4)).Return(db.TenderCategory{ID: 4}, nil)
+		mockStore.EXPECT().GetLotKeyParameterSchema(gomock.Any(), int64(4)).Return(db.LotKeyParameterSchema{Schema: schema}, nil)
+		mockStore.EXPECT().ListLotKeyParameterValues(gomock.Any(), gomock.Any()).Return([]db.ListLotKeyParameterValuesRow{}, nil)
+
+		resp, err := service.CompareLotKeyParameter(context.Background(), KeyParameterBenchmarkQuery{CategoryID: 4, Parameter: "piles"})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"integer"}, resp.SchemaTypes)
+		assert.Nil(t, resp.Benchmark)
+		assert.NotNil(t, resp.Items)
+	})
+
+	for _, parameter := range []string{"has_basement", "facade_area"} {
+		t.Run(parameter, func(t *testing.T) {
+			service, mockStore := setupTestService(t)
+			mockStore.EXPECT().GetTenderCategoryByID(gomock.Any(), int64(4)).Return(db.TenderCategory{ID: 4}, nil)
+			mockStore.EXPECT().GetLotKeyParameterSchema(gomock.Any(), int64(4)).Return(db.LotKeyParameterSchema{Schema: schema}, nil)
+
+			_, err := service.CompareLotKeyParameter(context.Background(), KeyParameterBenchmarkQuery{CategoryID: 4, Parameter: parameter})
+
+			var validationErr *apierrors.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+		})
+	}
+}
+
+func TestCompareLotKeyParameter_Validation(t *testing.T) {
+	cases := map[string]KeyParameterBenchmarkQuery{
+		"empty parameter": {CategoryID: 4, Parameter: "  "},
+		"zero category":   {CategoryID: 0, Parameter: "piles"},
+	}
+	for name, query := range cases {
+		t.Run(name, func(t *testing.T) {
+			service, _ := setupTestService(t)
+			_, err := service.CompareLotKeyParameter(context.Background(), query)
+			var validationErr *apierrors.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+		})
+	}
+
+	t.Run("missing category", func(t *testing.T) {
+		service, mockStore := setupTestService(t)
+		mockStore.EXPECT().GetTenderCategoryByID(gomock.Any(), int64(9)).Return(db.TenderCategory{}, sql.ErrNoRows)
+		_, err := service.CompareLotKeyParameter(context.Background(), KeyParameterBenchmarkQuery{CategoryID: 9, Parameter: "piles"})
+		var notFoundErr *apierrors.NotFoundError
+		assert.ErrorAs(t, err, &notFoundErr)
+	})
+}
+
+func TestParseParameterValue(t *testing.T) {
+	cases := []struct {
+		valueType, value string
+		number, unit     string
+		ok               bool
+	}{
+		{"number", "12.5", "12.5", "", true},
+		{"string", "1 200,5 м²", "1200.5", "м²", true},
+		{"string", "1 200 м2", "1200", "м2", true},
+		{"string", " 36 свай ", "36", "свай", true},
+		{"string", "-3", "-3", "", true},
+		{"string", "около 10", "", "", false},
+		{"boolean", "true", "", "", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.value, func(t *testing.T) {
+			number, unit, ok := parseParameterValue(tc.valueType, tc.value)
+			assert.Equal(t, tc.ok, ok)
+			if tc.ok {
+				assert.Equal(t, tc.number, number.String())
+				assert.Equal(t, tc.unit, unit)
+			}
+		})
+	}
+}
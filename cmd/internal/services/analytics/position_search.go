@@ -0,0 +1,177 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/util"
+)
+
+const (
+	// MinSearchQueryLength — короче триграммный индекс не используется.
+	MinSearchQueryLength = 3
+	// MaxSearchQueryLength — верхняя граница длины запроса в символах.
+	MaxSearchQueryLength = 200
+	// MaxSearchPageSize — максимальный размер страницы поиска позиций.
+	MaxSearchPageSize = 100
+)
+
+// PositionSearchQuery — параметры поиска позиций КП по названию.
+type PositionSearchQuery struct {
+	Query             string
+	CatalogPositionID sql.NullInt64
+	MinCost           *decimal.Decimal // цена за единицу, в валюте позиции
+	MaxCost           *decimal.Decimal
+	OrganizationID    sql.NullInt64 // Valid=false — все организации
+	Page              int32
+	PageSize          int32
+	// Cursor — keyset-пагинация вместо Page: next_cursor предыдущей страницы,
+	// "" — первая страница, nil — пагинация по Page.
+	Cursor *string
+}
+
+// likeEscaper экранирует спецсимволы ILIKE: запрос ищется как подстрока буквально.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchPositions ищет позиции КП во всех тендерах по подстроке или нечёткому
+// совпадению названия (pg_trgm) и возвращает их с контекстом тендера, лота и
+// подрядчика, самые похожие первыми. С Cursor страница начинается после ключа
+// сортировки (score, tender_date, id) последней строки предыдущей: запрашивается
+// на строку больше, и лишняя строка означает, что есть следующая страница.
+func (s *AnalyticsService) SearchPositions(ctx context.Context, query PositionSearchQuery) (*api_models.PositionSearchResponse, error) {
+	text := strings.TrimSpace(query.Query)
+	if length := utf8.RuneCountInString(text); length < MinSearchQueryLength || length > MaxSearchQueryLength {
+		return nil, apierrors.NewValidationError("request.query_length", MinSearchQueryLength, MaxSearchQueryLength)
+	}
+	var after *util.PageCursor
+	if query.Cursor != nil {
+		cursor, ok, err := util.DecodeCursor(*query.Cursor)
+		if err != nil {
+			return nil, apierrors.NewValidationError("request.invalid_cursor")
+		}
+		if ok {
+			after = &cursor
+		}
+	} else if query.Page < 1 {
+		return nil, apierrors.NewValidationError("request.page_positive_got", query.Page)
+	}
+	if query.PageSize < 1 || query.PageSize > MaxSearchPageSize {
+		return nil, apierrors.NewValidationError("request.page_size_range_got", MaxSearchPageSize, query.PageSize)
+	}
+	if query.MinCost != nil && query.MaxCost != nil && query.MinCost.GreaterThan(*query.MaxCost) {
+		return nil, apierrors.NewValidationError("analytics.min_cost_above_max")
+	}
+
+	pattern := "%" + likeEscaper.Replace(text) + "%"
+	minCost, maxCost := nullDecimal(query.MinCost), nullDecimal(query.MaxCost)
+
+	params := db.SearchPositionItemsParams{
+		Query:             text,
+		Pattern:           pattern,
+		CatalogPositionID: query.CatalogPositionID,
+		MinCost:           minCost,
+		MaxCost:           maxCost,
+		OrganizationID:    query.OrganizationID,
+		PageLimit:         query.PageSize,
+		PageOffset:        (query.Page - 1) * query.PageSize,
+	}
+	if query.Cursor != nil {
+		params.PageLimit, params.PageOffset = query.PageSize+1, 0
+		if after != nil {
+			params.AfterScore = sql.NullFloat64{Float64: float64(after.Score), Valid: true}
+			params.AfterDate = sql.NullTime{Time: after.CreatedAt, Valid: true}
+			params.AfterID = sql.NullInt64{Int64: after.ID, Valid: true}
+		}
+	}
+	rows, err := s.store.SearchPositionItems(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска позиций: %w", err)
+	}
+
+	total, err := s.store.CountSearchPositionItems(ctx, db.CountSearchPositionItemsParams{
+		Query:             text,
+		Pattern:           pattern,
+		CatalogPositionID: query.CatalogPositionID,
+		MinCost:           minCost,
+		MaxCost:           maxCost,
+		OrganizationID:    query.OrganizationID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подсчёта найденных позиций: %w", err)
+	}
+
+	var nextCursor string
+	if query.Cursor != nil && len(rows) > int(query.PageSize) {
+		rows = rows[:query.PageSize]
+		last := rows[len(rows)-1]
+		nextCursor = util.EncodeCursor(util.PageCursor{ID: last.PositionItemID, CreatedAt: last.TenderDate, Score: last.Score})
+	}
+
+	items := make([]api_models.PositionSearchItem, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, positionSearchItem(row))
+	}
+
+	return &api_models.PositionSearchResponse{
+		Items:      items,
+		TotalCount: total,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+func positionSearchItem(row db.SearchPositionItemsRow) api_models.PositionSearchItem {
+	item := api_models.PositionSearchItem{
+		PositionItemID:     row.PositionItemID,
+		JobTitleInProposal: row.JobTitleInProposal,
+		Quantity:           nullStringPtr(row.Quantity),
+		Unit:               nullStringPtr(row.UnitName),
+		UnitCost:           nullMoney(row.UnitCost),
+		TotalCost:          nullMoney(row.TotalCost),
+		Currency:           row.Currency,
+		ProposalID:         row.ProposalID,
+		IsBaseline:         row.IsBaseline,
+		LotID:              row.LotID,
+		LotTitle:           row.LotTitle,
+		TenderID:           row.TenderID,
+		TenderEtpID:        row.TenderEtpID,
+		TenderTitle:        row.TenderTitle,
+		TenderDate:         row.TenderDate,
+		ContractorID:       row.ContractorID,
+		ContractorName:     row.ContractorName,
+		ContractorInn:      row.ContractorInn,
+		Score:              row.Score,
+	}
+	if row.CatalogPositionID.Valid {
+		id := row.CatalogPositionID.Int64
+		item.CatalogPositionID = &id
+	}
+	return item
+}
+
+func nullDecimal(d *decimal.Decimal) sql.NullString {
+	if d == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: d.String(), Valid: true}
+}
+
+// nullMoney возвращает nil для NULL и нераспознанных значений NUMERIC.
+func nullMoney(ns sql.NullString) *api_models.Money {
+	if !ns.Valid {
+		return nil
+	}
+	money, err := api_models.ParseMoney(ns.String)
+	if err != nil {
+		return nil
+	}
+	return &money
+}
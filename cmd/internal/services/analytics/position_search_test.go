@@ -0,0 +1,194 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR POSITION SEARCH (Unit Tests)
+
+SCENARIO 1: SearchPositions
+- GIVEN a query with filters and a page
+  WHEN SearchPositions is called
+  THEN the trimmed query, an escaped ILIKE pattern, the filters, the organization
+       and limit/offset reach both the search and the count query
+  AND rows are returned with tender, lot and contractor context, NULL costs as null
+
+- GIVEN a query with % or _
+  WHEN SearchPositions is called
+  THEN they are escaped and matched literally
+
+SCENARIO 2: Cursor (keyset pagination)
+- GIVEN cursor = "" WHEN SearchPositions is called
+  THEN page_size+1 rows are requested without offset or key
+  AND an extra row is dropped and next_cursor points at the last returned row
+- GIVEN next_cursor of the previous page
+  THEN its (score, tender_date, id) reaches the search query
+  AND a short page has no next_cursor
+
+SCENARIO 3: Validation (no DB calls)
+- GIVEN a query shorter than 3 characters, page < 1, page_size out of range,
+  min_cost > max_cost or a malformed cursor
+  WHEN SearchPositions is called
+  THEN ValidationError is returned
+*/
+
+func TestSearchPositions_PassesFiltersAndMapsRows(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	minCost, maxCost := decimal.RequireFromString("100"), decimal.RequireFromString("2500.5")
+	organization := sql.NullInt64{Int64: 2, Valid: true}
+	date := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	mockStore.EXPECT().SearchPositionItems(gomock.Any(), db.SearchPositionItemsParams{
+		Query:             "бетон В25",
+		Pattern:           "%бетон В25%",
+		CatalogPositionID: sql.NullInt64{Int64: 7, Valid: true},
+		MinCost:           sql.NullString{String: "100", Valid: true},
+		MaxCost:           sql.NullString{String: "2500.5", Valid: true},
+		OrganizationID:    organization,
+		PageLimit:         10,
+		PageOffset:        10,
+	}).Return([]db.SearchPositionItemsRow{
+		{
+			PositionItemID:     11,
+			JobTitleInProposal: "Бетон В25 W6",
+			CatalogPositionID:  sql.NullInt64{Int64: 7, Valid: true},
+			Quantity:           sql.NullString{String: "12.5", Valid: true},
+			UnitCost:           sql.NullString{String: "2400.00", Valid: true},
+			Currency:           "RUB",
+			UnitName:           sql.NullString{String: "м3", Valid: true},
+			ProposalID:         5,
+			LotID:              3,
+			LotTitle:           "Корпус 1",
+			TenderID:           1,
+			TenderEtpID:        "T-1",
+			TenderTitle:        "ЖК Север",
+			TenderDate:         date,
+			ContractorID:       9,
+			ContractorName:     "ООО Строй",
+			ContractorInn:      "7700000000",
+			Score:              0.8,
+		},
+	}, nil)
+	mockStore.EXPECT().CountSearchPositionItems(gomock.Any(), db.CountSearchPositionItemsParams{
+		Query:             "бетон В25",
+		Pattern:           "%бетон В25%",
+		CatalogPositionID: sql.NullInt64{Int64: 7, Valid: true},
+		MinCost:           sql.NullString{String: "100", Valid: true},
+		MaxCost:           sql.NullString{String: "2500.5", Valid: true},
+		OrganizationID:    organization,
+	}).Return(int64(11), nil)
+
+	resp, err := service.SearchPositions(context.Background(), PositionSearchQuery{
+		Query:             "  бетон В25 ",
+		CatalogPositionID: sql.NullInt64{Int64: 7, Valid: true},
+		MinCost:           &minCost,
+		MaxCost:           &maxCost,
+		OrganizationID:    organization,
+		Page:              2,
+		PageSize:          10,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(11), resp.TotalCount)
+	assert.Equal(t, int32(2), resp.Page)
+	require.Len(t, resp.Items, 1)
+	item := resp.Items[0]
+	assert.Equal(t, int64(7), *item.CatalogPositionID)
+	assert.Equal(t, "2400.00", item.UnitCost.String())
+	assert.Nil(t, item.TotalCost)
+	assert.Equal(t, "м3", *item.Unit)
+	assert.Equal(t, "T-1", item.TenderEtpID)
+	assert.Equal(t, "ООО Строй", item.ContractorName)
+	assert.Equal(t, date, item.TenderDate)
+}
+
+func TestSearchPositions_EscapesLikeWildcards(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().SearchPositionItems(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.SearchPositionItemsParams) ([]db.SearchPositionItemsRow, error) {
+			assert.Equal(t, `%100\% арм\_ра%`, arg.Pattern)
+			assert.Equal(t, `100% арм_ра`, arg.Query)
+			return []db.SearchPositionItemsRow{}, nil
+		})
+	mockStore.EXPECT().CountSearchPositionItems(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+
+	resp, err := service.SearchPositions(context.Background(), PositionSearchQuery{Query: "100% арм_ра", Page: 1, PageSize: 20})
+	require.NoError(t, err)
+	assert.NotNil(t, resp.Items, "пустой список, а не null")
+}
+
+func TestSearchPositions_Cursor(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	date := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	rows := []db.SearchPositionItemsRow{
+		{PositionItemID: 30, Score: 0.9, TenderDate: date},
+		{PositionItemID: 20, Score: 0.75, TenderDate: date},
+		{PositionItemID: 10, Score: 0.75, TenderDate: date},
+	}
+
+	first := ""
+	mockStore.EXPECT().SearchPositionItems(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.SearchPositionItemsParams) ([]db.SearchPositionItemsRow, error) {
+			assert.Equal(t, int32(3), arg.PageLimit)
+			assert.Zero(t, arg.PageOffset)
+			assert.False(t, arg.AfterID.Valid)
+			return rows, nil
+		})
+	mockStore.EXPECT().CountSearchPositionItems(gomock.Any(), gomock.Any()).Return(int64(3), nil)
+
+	resp, err := service.SearchPositions(context.Background(), PositionSearchQuery{Query: "бетон", PageSize: 2, Cursor: &first})
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 2)
+	assert.Equal(t, int64(20), resp.Items[1].PositionItemID)
+	assert.Zero(t, resp.Page)
+	require.NotEmpty(t, resp.NextCursor)
+
+	mockStore.EXPECT().SearchPositionItems(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.SearchPositionItemsParams) ([]db.SearchPositionItemsRow, error) {
+			assert.Equal(t, sql.NullInt64{Int64: 20, Valid: true}, arg.AfterID)
+			assert.Equal(t, sql.NullFloat64{Float64: float64(float32(0.75)), Valid: true}, arg.AfterScore)
+			assert.True(t, arg.AfterDate.Valid && arg.AfterDate.Time.Equal(date))
+			return rows[2:], nil
+		})
+	mockStore.EXPECT().CountSearchPositionItems(gomock.Any(), gomock.Any()).Return(int64(3), nil)
+
+	resp, err = service.SearchPositions(context.Background(), PositionSearchQuery{Query: "бетон", PageSize: 2, Cursor: &resp.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 1)
+	assert.Empty(t, resp.NextCursor, "последняя страница")
+}
+
+func TestSearchPositions_Validation(t *testing.T) {
+	service, _ := setupTestService(t)
+	low, high := decimal.RequireFromString("10"), decimal.RequireFromString("5")
+	malformed := "не курсор"
+
+	cases := map[string]PositionSearchQuery{
+		"short query":       {Query: " бе ", Page: 1, PageSize: 20},
+		"page below 1":      {Query: "бетон", Page: 0, PageSize: 20},
+		"page size too big": {Query: "бетон", Page: 1, PageSize: MaxSearchPageSize + 1},
+		"min above max":     {Query: "бетон", Page: 1, PageSize: 20, MinCost: &low, MaxCost: &high},
+		"malformed cursor":  {Query: "бетон", PageSize: 20, Cursor: &malformed},
+	}
+	for name, query := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.SearchPositions(context.Background(), query)
+			var validationErr *apierrors.ValidationError
+			assert.True(t, errors.As(err, &validationErr), "expected ValidationError, got: %v", err)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package analytics
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/priceindex"
+)
+
+// PriceNormalizationTarget — к каким региону и дате пересчитать цены истории.
+type PriceNormalizationTarget struct {
+	Region string // Код региона в виде priceindex.NormalizeRegion
+	Date   time.Time
+}
+
+// quarterKey — квартал и единица измерения, как в ListCatalogPositionQuarterlyPrices.
+type quarterKey struct {
+	quarter string
+	unit    sql.NullString
+}
+
+// normalizedStats — агрегаты пересчитанных цен квартала.
+type normalizedStats struct {
+	count    int64
+	min, max decimal.Decimal
+	sum      decimal.Decimal
+}
+
+// applyPriceNormalization дополняет историю цен пересчитанными ценами:
+// цена × индекс(цель) / индекс(регион тендера, дата цены). Цены, для которых
+// индекса нет, остаются без пересчёта и учитываются в UnnormalizedCount.
+// rows — строки, из которых собран response.Items, в том же порядке.
+func applyPriceNormalization(
+	response *api_models.CatalogPriceHistoryResponse,
+	rows []db.ListCatalogPositionPriceHistoryRow,
+	table *priceindex.Table,
+	target PriceNormalizationTarget,
+) error {
+	targetIndex, ok := table.Lookup(target.Region, target.Date)
+	if !ok {
+		return apierrors.NewValidationError("analytics.price_index_missing",
+			target.Region, priceindex.NationalRegion, target.Date.Format("2006-01-02"))
+	}
+
+	info := &api_models.PriceNormalization{
+		Region: target.Region,
+		Date:   target.Date,
+		Index:  targetIndex.String(),
+	}
+	stats := make(map[quarterKey]*normalizedStats)
+	for i, row := range rows {
+		unitCost, err := decimal.NewFromString(row.UnitCost.String)
+		if err != nil {
+			continue // NULL отфильтрован в запросе
+		}
+		sourceIndex, ok := table.Lookup(row.TenderRegion.String, row.PriceDate)
+		if !ok {
+			info.UnnormalizedCount++
+			continue
+		}
+		normalized := unitCost.Mul(targetIndex).Div(sourceIndex)
+		response.Items[i].NormalizedUnitCost = api_models.MoneyPtr(normalized)
+
+		key := quarterKey{quarter: row.Quarter, unit: row.UnitName}
+		st, exists := stats[key]
+		if !exists {
+			st = &normalizedStats{min: normalized, max: normalized}
+			stats[key] = st
+		}
+		st.count++
+		st.sum = st.sum.Add(normalized)
+		st.min = decimal.Min(st.min, normalized)
+		st.max = decimal.Max(st.max, normalized)
+	}
+
+	for i := range response.Quarters {
+		q := &response.Quarters[i]
+		key := quarterKey{quarter: q.Quarter}
+		if q.Unit != nil {
+			key.unit = sql.NullString{String: *q.Unit, Valid: true}
+		}
+		st, ok := stats[key]
+		if !ok {
+			continue
+		}
+		q.NormalizedPricesCount = st.count
+		q.NormalizedMinUnitCost = api_models.MoneyPtr(st.min)
+		q.NormalizedAvgUnitCost = api_models.MoneyPtr(st.sum.Div(decimal.NewFromInt(st.count)))
+		q.NormalizedMaxUnitCost = api_models.MoneyPtr(st.max)
+	}
+
+	response.Normalization = info
+	return nil
+}
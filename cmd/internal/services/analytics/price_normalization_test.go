@@ -0,0 +1,111 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR INDEX-ADJUSTED PRICE HISTORY (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. A 2023 price looks cheap next to a 2025 one only because of inflation
+2. A Moscow price is compared with a regional one as if they were equal
+3. A price without an index silently gets a made-up value
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: normalize to region and date
+- GIVEN a 2023 price in region 77 and a 2025 price of a tender without region,
+  indices for 77 and RU
+  THEN each price is multiplied by index(target) / index(own region and month)
+       and quarterly normalized min/avg/max are computed from the adjusted prices
+
+SCENARIO 2: missing indices
+- GIVEN a price older than every index
+  THEN it has no normalized price and is counted as unnormalized
+- GIVEN no index for the target region and date
+  THEN ValidationError
+*/
+
+func priceIndex(region string, year int, month time.Month, value string) db.PriceIndex {
+	return db.PriceIndex{Region: region, Period: time.Date(year, month, 1, 0, 0, 0, 0, time.UTC), Value: value}
+}
+
+func TestGetCatalogPriceHistory_Normalized(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	q1of2023 := time.Date(2023, 2, 10, 0, 0, 0, 0, time.UTC)
+	q1of2025 := time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	mockStore.EXPECT().GetCatalogPositionByID(gomock.Any(), int64(42)).Return(db.CatalogPosition{ID: 42}, nil)
+	mockStore.EXPECT().ListCatalogPositionPriceHistory(gomock.Any(), gomock.Any()).
+		Return([]db.ListCatalogPositionPriceHistoryRow{
+			{PositionItemID: 2, PriceDate: q1of2025, UnitCost: ns("1200.00"), UnitName: ns("м3"), Quarter: "2025-Q1"},
+			{PositionItemID: 1, PriceDate: q1of2023, UnitCost: ns("1000.00"), UnitName: ns("м3"), Quarter: "2023-Q1", TenderRegion: ns("77")},
+			{PositionItemID: 3, PriceDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), UnitCost: ns("500.00"), UnitName: ns("м3"), Quarter: "2020-Q1"},
+		}, nil)
+	mockStore.EXPECT().ListCatalogPositionQuarterlyPrices(gomock.Any(), gomock.Any()).
+		Return([]db.ListCatalogPositionQuarterlyPricesRow{
+			{Quarter: "2020-Q1", UnitName: ns("м3"), PricesCount: 1},
+			{Quarter: "2023-Q1", UnitName: ns("м3"), PricesCount: 1},
+			{Quarter: "2025-Q1", UnitName: ns("м3"), PricesCount: 1},
+		}, nil)
+	mockStore.EXPECT().ListPriceIndices(gomock.Any(), sql.NullString{}).Return([]db.PriceIndex{
+		priceIndex("77", 2023, time.January, "125.0000"),
+		priceIndex("RU", 2023, time.January, "100.0000"),
+		priceIndex("RU", 2025, time.January, "120.0000"),
+		priceIndex("RU", 2025, time.June, "125.0000"),
+	}, nil)
+
+	resp, err := service.GetCatalogPriceHistory(context.Background(), 42, testOrganization, &PriceNormalizationTarget{
+		Region: "RU",
+		Date:   time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	require.NotNil(t, resp.Normalization)
+	assert.Equal(t, "125", resp.Normalization.Index)
+	assert.Equal(t, int64(1), resp.Normalization.UnnormalizedCount)
+
+	// 1200 × 125 / 120 (RU, январь 2025)
+	assert.Equal(t, "1250.00", resp.Items[0].NormalizedUnitCost.String())
+	// 1000 × 125 / 125 (77, январь 2023)
+	assert.Equal(t, "1000.00", resp.Items[1].NormalizedUnitCost.String())
+	assert.Equal(t, "77", *resp.Items[1].Region)
+	assert.Nil(t, resp.Items[2].NormalizedUnitCost, "индексов до 2023 года нет")
+
+	assert.Zero(t, resp.Quarters[0].NormalizedPricesCount)
+	assert.Nil(t, resp.Quarters[0].NormalizedAvgUnitCost)
+	assert.Equal(t, "1250.00", resp.Quarters[2].NormalizedAvgUnitCost.String())
+	assert.Equal(t, int64(1), resp.Quarters[2].NormalizedPricesCount)
+}
+
+func TestGetCatalogPriceHistory_NoTargetIndex(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetCatalogPositionByID(gomock.Any(), int64(42)).Return(db.CatalogPosition{ID: 42}, nil)
+	mockStore.EXPECT().ListCatalogPositionPriceHistory(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockStore.EXPECT().ListCatalogPositionQuarterlyPrices(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockStore.EXPECT().ListPriceIndices(gomock.Any(), gomock.Any()).Return([]db.PriceIndex{
+		priceIndex("RU", 2025, time.January, "120.0000"),
+	}, nil)
+
+	_, err := service.GetCatalogPriceHistory(context.Background(), 42, testOrganization, &PriceNormalizationTarget{
+		Region: "77",
+		Date:   time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+	})
+
+	var validationErr *apierrors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
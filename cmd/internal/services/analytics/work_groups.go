@@ -0,0 +1,173 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+)
+
+// GetLotWorkGroupCosts возвращает стоимость позиций каждого предложения лота,
+// свёрнутую по классификатору видов работ: сумма узла включает позиции всех
+// его потомков. Суммы — в базовой валюте; позиции без узла классификатора
+// выводятся отдельно (Unclassified).
+func (s *AnalyticsService) GetLotWorkGroupCosts(ctx context.Context, lotID int64) (*api_models.LotWorkGroupCostsResponse, error) {
+	logger := s.logger.WithField("method", "GetLotWorkGroupCosts").WithField("lot_id", lotID)
+
+	lot, err := s.getVisibleLot(ctx, lotID)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := s.store.ListWorkGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения классификатора видов работ: %w", err)
+	}
+
+	currencies, rates := s.rates.QueryArgs()
+	rows, err := s.store.ListLotWorkGroupCosts(ctx, db.ListLotWorkGroupCostsParams{
+		Currencies: currencies,
+		Rates:      rates,
+		LotID:      lotID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка расчёта стоимости лота %d по видам работ: %w", lotID, err)
+	}
+
+	response := buildLotWorkGroupCosts(lot, s.rates.Base(), groups, rows)
+	logger.Infof("Свёртка по видам работ: %d предложений, %d корневых узлов", len(response.Proposals), len(response.Groups))
+	return response, nil
+}
+
+// groupCost — сумма и число позиций одного предложения в узле.
+type groupCost struct {
+	total decimal.Decimal
+	count int64
+}
+
+// buildLotWorkGroupCosts сворачивает суммы узлов вверх по дереву. Код позиции,
+// которого нет в классификаторе (узел удалён между запросами), считается
+// неклассифицированным.
+func buildLotWorkGroupCosts(
+	lot db.Lot,
+	baseCurrency string,
+	groups []db.ListWorkGroupsRow,
+	rows []db.ListLotWorkGroupCostsRow,
+) *api_models.LotWorkGroupCostsResponse {
+	known := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		known[g.Code] = true
+	}
+
+	// Строки отсортированы запросом: baseline первым, затем по id предложения
+	var proposals []api_models.LotWorkGroupProposal
+	seen := make(map[int64]bool)
+	direct := make(map[string]map[int64]groupCost)
+	for _, row := range rows {
+		if !seen[row.ProposalID] {
+			seen[row.ProposalID] = true
+			proposals = append(proposals, api_models.LotWorkGroupProposal{
+				ProposalID:     row.ProposalID,
+				ContractorName: row.ContractorName,
+				IsBaseline:     row.IsBaseline,
+			})
+		}
+		total, err := decimal.NewFromString(row.TotalCost.String)
+		if err != nil {
+			continue // NULL-суммы отфильтрованы в запросе
+		}
+		code := row.WorkGroupCode
+		if !known[code] {
+			code = ""
+		}
+		if direct[code] == nil {
+			direct[code] = make(map[int64]groupCost)
+		}
+		cost := direct[code][row.ProposalID]
+		cost.total = cost.total.Add(total)
+		cost.count += row.PositionsCount
+		direct[code][row.ProposalID] = cost
+	}
+
+	byParent := make(map[int64][]db.ListWorkGroupsRow)
+	ids := make(map[int64]bool, len(groups))
+	for _, g := range groups {
+		ids[g.ID] = true
+	}
+	var roots []db.ListWorkGroupsRow
+	for _, g := range groups {
+		if g.ParentID.Valid && ids[g.ParentID.Int64] {
+			byParent[g.ParentID.Int64] = append(byParent[g.ParentID.Int64], g)
+			continue
+		}
+		roots = append(roots, g)
+	}
+
+	// rollUp возвращает узел со свёрнутыми суммами; ok=false — в поддереве нет позиций лота
+	var rollUp func(g db.ListWorkGroupsRow) (node api_models.LotWorkGroupCost, sums map[int64]groupCost, ok bool)
+	rollUp = func(g db.ListWorkGroupsRow) (api_models.LotWorkGroupCost, map[int64]groupCost, bool) {
+		sums := make(map[int64]groupCost, len(direct[g.Code]))
+		for proposalID, cost := range direct[g.Code] {
+			sums[proposalID] = cost
+		}
+		node := api_models.LotWorkGroupCost{
+			ID:       g.ID,
+			Code:     g.Code,
+			Name:     g.Name,
+			Children: []api_models.LotWorkGroupCost{},
+		}
+		for _, child := range byParent[g.ID] {
+			childNode, childSums, ok := rollUp(child)
+			if !ok {
+				continue
+			}
+			node.Children = append(node.Children, childNode)
+			for proposalID, cost := range childSums {
+				sum := sums[proposalID]
+				sum.total = sum.total.Add(cost.total)
+				sum.count += cost.count
+				sums[proposalID] = sum
+			}
+		}
+		node.Costs = proposalCosts(proposals, sums)
+		return node, sums, len(sums) > 0
+	}
+
+	response := &api_models.LotWorkGroupCostsResponse{
+		LotID:        lot.ID,
+		LotTitle:     lot.LotTitle,
+		Currency:     baseCurrency,
+		Proposals:    proposals,
+		Groups:       []api_models.LotWorkGroupCost{},
+		Unclassified: proposalCosts(proposals, direct[""]),
+	}
+	if response.Proposals == nil {
+		response.Proposals = []api_models.LotWorkGroupProposal{}
+	}
+	for _, root := range roots {
+		if node, _, ok := rollUp(root); ok {
+			response.Groups = append(response.Groups, node)
+		}
+	}
+	return response
+}
+
+// proposalCosts раскладывает суммы узла в порядке колонок; предложения без
+// позиций в узле пропускаются.
+func proposalCosts(proposals []api_models.LotWorkGroupProposal, sums map[int64]groupCost) []api_models.WorkGroupProposalCost {
+	costs := make([]api_models.WorkGroupProposalCost, 0, len(sums))
+	for _, p := range proposals {
+		cost, ok := sums[p.ProposalID]
+		if !ok {
+			continue
+		}
+		costs = append(costs, api_models.WorkGroupProposalCost{
+			ProposalID:     p.ProposalID,
+			TotalCost:      api_models.NewMoney(cost.total),
+			PositionsCount: cost.count,
+		})
+	}
+	return costs
+}
@@ -0,0 +1,107 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR LOT COSTS BY WORK GROUP (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. "Земляные работы" shows only its own positions instead of the whole subtree
+2. Empty classifier branches clutter the comparison
+3. Positions without a work group silently disappear from the totals
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: GetLotWorkGroupCosts
+- GIVEN 01 → 01.01, 01.02 and 02, costs of baseline and a contractor in
+  01.01, 01.02 and without a code
+  THEN 01 holds the sum of 01.01 and 01.02 for each proposal, 02 is pruned,
+       baseline is the first column and the rest is Unclassified
+- GIVEN a code missing from the classifier
+  THEN its positions are reported as Unclassified
+- GIVEN a lot of a soft-deleted tender
+  THEN NotFoundError, no cost query
+*/
+
+func workGroup(id, parentID int64, code string) db.ListWorkGroupsRow {
+	row := db.ListWorkGroupsRow{ID: id, Code: code, Name: "Узел " + code}
+	if parentID > 0 {
+		row.ParentID = sql.NullInt64{Int64: parentID, Valid: true}
+	}
+	return row
+}
+
+func TestGetLotWorkGroupCosts_RollsUpTree(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	expectVisibleLot(mockStore, 5)
+	mockStore.EXPECT().ListWorkGroups(gomock.Any()).Return([]db.ListWorkGroupsRow{
+		workGroup(1, 0, "01"),
+		workGroup(2, 1, "01.01"),
+		workGroup(3, 1, "01.02"),
+		workGroup(4, 0, "02"),
+	}, nil)
+	mockStore.EXPECT().ListLotWorkGroupCosts(gomock.Any(), db.ListLotWorkGroupCostsParams{
+		Currencies: testCurrencies,
+		Rates:      testRates,
+		LotID:      5,
+	}).Return([]db.ListLotWorkGroupCostsRow{
+		{ProposalID: 1, IsBaseline: true, ContractorName: "Смета", WorkGroupCode: "", TotalCost: ns("50.00"), PositionsCount: 1},
+		{ProposalID: 1, IsBaseline: true, ContractorName: "Смета", WorkGroupCode: "01.01", TotalCost: ns("100.00"), PositionsCount: 2},
+		{ProposalID: 1, IsBaseline: true, ContractorName: "Смета", WorkGroupCode: "01.02", TotalCost: ns("200.50"), PositionsCount: 1},
+		{ProposalID: 7, ContractorName: "ООО Ромашка", WorkGroupCode: "01.02", TotalCost: ns("180.00"), PositionsCount: 1},
+		{ProposalID: 7, ContractorName: "ООО Ромашка", WorkGroupCode: "99", TotalCost: ns("10.00"), PositionsCount: 1},
+	}, nil)
+
+	resp, err := service.GetLotWorkGroupCosts(context.Background(), 5)
+	require.NoError(t, err)
+
+	assert.Equal(t, "RUB", resp.Currency)
+	require.Len(t, resp.Proposals, 2)
+	assert.True(t, resp.Proposals[0].IsBaseline)
+	assert.Equal(t, int64(7), resp.Proposals[1].ProposalID)
+
+	require.Len(t, resp.Groups, 1, "узел 02 без позиций лота не выводится")
+	root := resp.Groups[0]
+	assert.Equal(t, "01", root.Code)
+	require.Len(t, root.Costs, 2)
+	assert.Equal(t, "300.50", root.Costs[0].TotalCost.String())
+	assert.Equal(t, int64(3), root.Costs[0].PositionsCount)
+	assert.Equal(t, "180.00", root.Costs[1].TotalCost.String())
+
+	require.Len(t, root.Children, 2)
+	assert.Equal(t, "01.01", root.Children[0].Code)
+	require.Len(t, root.Children[0].Costs, 1, "у подрядчика нет позиций в 01.01")
+	assert.Equal(t, int64(1), root.Children[0].Costs[0].ProposalID)
+
+	require.Len(t, resp.Unclassified, 2)
+	assert.Equal(t, "50.00", resp.Unclassified[0].TotalCost.String())
+	assert.Equal(t, "10.00", resp.Unclassified[1].TotalCost.String())
+}
+
+func TestGetLotWorkGroupCosts_DeletedTender(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetLotByID(gomock.Any(), int64(5)).Return(db.Lot{ID: 5, TenderID: 10}, nil)
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(10)).
+		Return(db.Tender{ID: 10, DeletedAt: sql.NullTime{Valid: true}}, nil)
+	mockStore.EXPECT().ListLotWorkGroupCosts(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := service.GetLotWorkGroupCosts(context.Background(), 5)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
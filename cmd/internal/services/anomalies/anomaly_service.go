@@ -0,0 +1,317 @@
+// Package anomalies ищет подозрительные цены в предложениях лота: опечатки
+// (цена в 10, 100, 1000 раз больше или меньше обычной) и демпинг.
+//
+// Цена за единицу позиции сравнивается с двумя ориентирами:
+//   - медианой цен той же позиции каталога у других подрядчиков лота
+//     (нужно не меньше min_peers цен);
+//   - средней ценой позиции каталога по другим тендерам за history_months
+//     (нужно не меньше min_history_prices цен).
+//
+// Позиция помечается, если отклонение от любого ориентира больше порога
+// категории тендера (config.AnomaliesConfig). Сравнение — в той же единице
+// измерения и в базовой валюте; суммы считаются точно (decimal).
+package anomalies
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/currency"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// typoTolerance — насколько отношение цены к ориентиру может отличаться от
+// степени десяти, чтобы отклонение считалось вероятной опечаткой.
+const typoTolerance = 0.1
+
+// AnomalyService ищет подозрительные цены в предложениях.
+type AnomalyService struct {
+	store  db.Store
+	logger logging.Logger
+	rates  *currency.Rates
+	cfg    config.AnomaliesConfig
+	now    func() time.Time // Подменяется в тестах
+}
+
+// NewAnomalyService создаёт новый экземпляр AnomalyService.
+func NewAnomalyService(store db.Store, logger logging.Logger, rates *currency.Rates, cfg config.AnomaliesConfig) *AnomalyService {
+	return &AnomalyService{
+		store:  store,
+		logger: logger,
+		rates:  rates,
+		cfg:    cfg,
+		now:    time.Now,
+	}
+}
+
+// priceKey — позиция каталога и единица измерения: цены сравниваются только внутри ключа.
+type priceKey struct {
+	catalogPositionID int64
+	unitID            sql.NullInt64
+}
+
+// thresholds — пороги отклонения, %.
+type thresholds struct {
+	warning, critical decimal.Decimal
+}
+
+// GetLotAnomalies возвращает позиции предложений лота с подозрительными ценами.
+// organizationID ограничивает историю цен каталога тендерами организации (NULL — все).
+func (s *AnomalyService) GetLotAnomalies(ctx context.Context, lotID int64, organizationID sql.NullInt64) (*api_models.LotAnomaliesResponse, error) {
+	logger := s.logger.WithField("method", "GetLotAnomalies").WithField("lot_id", lotID)
+
+	if lotID <= 0 {
+		return nil, apierrors.NewValidationError("request.id_positive_got", lotID)
+	}
+	lot, err := s.store.GetLotByID(ctx, lotID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewNotFoundError("common.lot_not_found", lotID)
+		}
+		return nil, fmt.Errorf("ошибка получения лота %d: %w", lotID, err)
+	}
+	tender, err := s.store.GetTenderByID(ctx, lot.TenderID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения тендера лота %d: %w", lotID, err)
+	}
+	if tender.DeletedAt.Valid {
+		return nil, apierrors.NewNotFoundError("common.lot_not_found", lotID)
+	}
+
+	currencies, rates := s.rates.QueryArgs()
+	rows, err := s.store.ListLotPositionPricesForAnomalies(ctx, db.ListLotPositionPricesForAnomaliesParams{
+		Currencies: currencies,
+		Rates:      rates,
+		LotID:      lotID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения цен позиций лота %d: %w", lotID, err)
+	}
+
+	history, err := s.loadHistory(ctx, lotID, rows, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	warning := s.cfg.Threshold(tender.CategoryID.Int64)
+	critical := warning * s.cfg.CriticalFactor
+	response := &api_models.LotAnomaliesResponse{
+		LotID:            lotID,
+		Currency:         s.rates.Base(),
+		ThresholdPercent: warning,
+		CriticalPercent:  critical,
+	}
+	if tender.CategoryID.Valid {
+		categoryID := tender.CategoryID.Int64
+		response.CategoryID = &categoryID
+	}
+	response.CheckedCount, response.Anomalies = findAnomalies(rows, history, thresholds{
+		warning:  decimal.NewFromFloat(warning),
+		critical: decimal.NewFromFloat(critical),
+	}, s.cfg.MinPeers)
+
+	logger.Infof("Проверено %d цен, подозрительных %d (порог %.2f%%)", response.CheckedCount, len(response.Anomalies), warning)
+	return response, nil
+}
+
+// historyAverage — средняя цена позиции каталога по другим тендерам.
+type historyAverage struct {
+	cost  decimal.Decimal
+	count int64
+}
+
+// loadHistory получает средние цены каталога для позиций лота. Средние по
+// выборке меньше min_history_prices не возвращаются.
+func (s *AnomalyService) loadHistory(ctx context.Context, lotID int64, rows []db.ListLotPositionPricesForAnomaliesRow, organizationID sql.NullInt64) (map[priceKey]historyAverage, error) {
+	history := make(map[priceKey]historyAverage)
+	seen := make(map[int64]bool)
+	var catalogIDs []int64
+	for _, row := range rows {
+		if !seen[row.CatalogPositionID] {
+			seen[row.CatalogPositionID] = true
+			catalogIDs = append(catalogIDs, row.CatalogPositionID)
+		}
+	}
+	if len(catalogIDs) == 0 {
+		return history, nil
+	}
+
+	currencies, rates := s.rates.QueryArgs()
+	averages, err := s.store.ListCatalogAverageUnitCosts(ctx, db.ListCatalogAverageUnitCostsParams{
+		Currencies:         currencies,
+		Rates:              rates,
+		CatalogPositionIds: catalogIDs,
+		LotID:              lotID,
+		Since:              s.now().AddDate(0, -s.cfg.HistoryMonths, 0),
+		OrganizationID:     organizationID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка расчёта средних цен каталога для лота %d: %w", lotID, err)
+	}
+	for _, avg := range averages {
+		if avg.PricesCount < int64(s.cfg.MinHistoryPrices) {
+			continue
+		}
+		cost, err := decimal.NewFromString(avg.AvgUnitCost.String)
+		if err != nil {
+			continue
+		}
+		history[priceKey{catalogPositionID: avg.CatalogPositionID, unitID: avg.UnitID}] = historyAverage{cost: cost, count: avg.PricesCount}
+	}
+	return history, nil
+}
+
+// findAnomalies сравнивает каждую цену с ориентирами и возвращает число
+// проверенных цен и подозрительные позиции (сначала critical, затем по
+// наибольшему отклонению).
+func findAnomalies(rows []db.ListLotPositionPricesForAnomaliesRow, history map[priceKey]historyAverage, limits thresholds, minPeers int) (int, []api_models.PriceAnomaly) {
+	type priced struct {
+		row  db.ListLotPositionPricesForAnomaliesRow
+		cost decimal.Decimal
+	}
+	groups := make(map[priceKey][]priced)
+	var all []priced
+	for _, row := range rows {
+		cost, err := decimal.NewFromString(row.UnitCost.String)
+		if err != nil {
+			continue
+		}
+		p := priced{row: row, cost: cost}
+		key := priceKey{catalogPositionID: row.CatalogPositionID, unitID: row.UnitID}
+		groups[key] = append(groups[key], p)
+		all = append(all, p)
+	}
+
+	anomalies := make([]api_models.PriceAnomaly, 0)
+	maxDeviation := make(map[int64]decimal.Decimal) // position_item_id → наибольшее |отклонение|
+	for _, p := range all {
+		key := priceKey{catalogPositionID: p.row.CatalogPositionID, unitID: p.row.UnitID}
+		var reasons []api_models.PriceAnomalyReason
+		var worst decimal.Decimal
+
+		var peers []decimal.Decimal
+		for _, other := range groups[key] {
+			if other.row.ProposalID != p.row.ProposalID {
+				peers = append(peers, other.cost)
+			}
+		}
+		if len(peers) >= minPeers {
+			if reason, deviation, ok := compare(p.cost, median(peers), int64(len(peers)), api_models.AnomalyReferencePeerMedian, limits); ok {
+				reasons = append(reasons, reason)
+				worst = decimal.Max(worst, deviation)
+			}
+		}
+		if avg, ok := history[key]; ok {
+			if reason, deviation, ok := compare(p.cost, avg.cost, avg.count, api_models.AnomalyReferenceCatalogHistory, limits); ok {
+				reasons = append(reasons, reason)
+				worst = decimal.Max(worst, deviation)
+			}
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+
+		anomaly := api_models.PriceAnomaly{
+			PositionItemID:    p.row.PositionItemID,
+			ProposalID:        p.row.ProposalID,
+			ContractorID:      p.row.ContractorID,
+			ContractorName:    p.row.ContractorName,
+			PositionKey:       p.row.PositionKeyInProposal,
+			JobTitle:          p.row.JobTitleInProposal,
+			CatalogPositionID: p.row.CatalogPositionID,
+			UnitCost:          api_models.NewMoney(p.cost),
+			Severity:          api_models.AnomalySeverityWarning,
+			Reasons:           reasons,
+		}
+		if p.row.UnitName.Valid {
+			unit := p.row.UnitName.String
+			anomaly.Unit = &unit
+		}
+		for _, reason := range reasons {
+			if reason.Severity == api_models.AnomalySeverityCritical {
+				anomaly.Severity = api_models.AnomalySeverityCritical
+			}
+		}
+		maxDeviation[anomaly.PositionItemID] = worst
+		anomalies = append(anomalies, anomaly)
+	}
+
+	sort.SliceStable(anomalies, func(i, j int) bool {
+		a, b := anomalies[i], anomalies[j]
+		if a.Severity != b.Severity {
+			return a.Severity == api_models.AnomalySeverityCritical
+		}
+		if cmp := maxDeviation[a.PositionItemID].Cmp(maxDeviation[b.PositionItemID]); cmp != 0 {
+			return cmp > 0
+		}
+		return a.PositionItemID < b.PositionItemID
+	})
+	return len(all), anomalies
+}
+
+// compare возвращает причину, если цена отклоняется от ориентира больше
+// порога, и модуль отклонения в процентах.
+func compare(cost, reference decimal.Decimal, count int64, kind string, limits thresholds) (api_models.PriceAnomalyReason, decimal.Decimal, bool) {
+	if !reference.IsPositive() {
+		return api_models.PriceAnomalyReason{}, decimal.Zero, false
+	}
+	deviation := cost.Sub(reference).Div(reference).Mul(decimal.NewFromInt(100))
+	abs := deviation.Abs()
+	if abs.LessThanOrEqual(limits.warning) {
+		return api_models.PriceAnomalyReason{}, decimal.Zero, false
+	}
+
+	reason := api_models.PriceAnomalyReason{
+		Reference:        kind,
+		ReferenceCost:    api_models.NewMoney(reference),
+		ReferenceCount:   count,
+		DeviationPercent: deviation.StringFixed(2),
+		Severity:         api_models.AnomalySeverityWarning,
+		Hint:             hint(cost, reference),
+	}
+	if abs.GreaterThan(limits.critical) {
+		reason.Severity = api_models.AnomalySeverityCritical
+	}
+	return reason, abs, true
+}
+
+// hint подсказывает вероятную причину: отношение цены к ориентиру, близкое
+// к 10, 100, 1000 (или обратным), — скорее опечатка или ошибка единиц.
+func hint(cost, reference decimal.Decimal) string {
+	if cost.IsPositive() {
+		ratio := cost.Div(reference).InexactFloat64()
+		if ratio < 1 {
+			ratio = 1 / ratio
+		}
+		power := math.Round(math.Log10(ratio))
+		if power >= 1 && math.Abs(ratio/math.Pow(10, power)-1) <= typoTolerance {
+			return api_models.AnomalyHintPossibleTypo
+		}
+	}
+	if cost.LessThan(reference) {
+		return api_models.AnomalyHintDumping
+	}
+	return api_models.AnomalyHintOverpriced
+}
+
+// median возвращает медиану непустого набора цен.
+func median(values []decimal.Decimal) decimal.Decimal {
+	sorted := append([]decimal.Decimal(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].LessThan(sorted[j]) })
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return sorted[mid-1].Add(sorted[mid]).Div(decimal.NewFromInt(2))
+}
@@ -0,0 +1,204 @@
+package anomalies
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/currency"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR PRICE ANOMALIES (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. A price typed with an extra zero wins (or loses) the comparison unnoticed
+2. A dumping price looks like the best offer
+3. One threshold for all kinds of work: earthworks vary more than finishing
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: peer median
+- GIVEN three contractors at ~1000 and one at 10000 for the same catalog position
+  THEN the 10000 price is critical with hint possible_typo; the others are not flagged
+- GIVEN fewer other prices than min_peers THEN no peer comparison
+- GIVEN the same catalog position in another unit THEN it is not a peer
+
+SCENARIO 2: catalog history
+- GIVEN history average 1000 from enough prices and a price of 600
+  THEN warning with hint dumping
+- GIVEN history from fewer than min_history_prices prices THEN it is ignored
+
+SCENARIO 3: thresholds per category
+- GIVEN a tender category with its own threshold
+  THEN the response reports it and uses it
+
+SCENARIO 4: errors
+- GIVEN an unknown lot → NotFoundError
+*/
+
+var testNow = time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+
+func testConfig() config.AnomaliesConfig {
+	return config.AnomaliesConfig{
+		ThresholdPercent:   30,
+		CriticalFactor:     3,
+		CategoryThresholds: map[string]float64{"4": 50},
+		MinPeers:           2,
+		HistoryMonths:      24,
+		MinHistoryPrices:   3,
+	}
+}
+
+func setupTestService(t *testing.T) (*AnomalyService, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	rates := currency.NewRates(config.CurrencyConfig{Base: "RUB"})
+	service := NewAnomalyService(mockStore, testutil.NewMockLogger(), rates, testConfig())
+	service.now = func() time.Time { return testNow }
+	return service, mockStore
+}
+
+func ns(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: true}
+}
+
+func ni(v int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: v, Valid: true}
+}
+
+func price(itemID, proposalID, catalogID, unitID int64, cost string) db.ListLotPositionPricesForAnomaliesRow {
+	return db.ListLotPositionPricesForAnomaliesRow{
+		PositionItemID:        itemID,
+		ProposalID:            proposalID,
+		ContractorID:          proposalID * 10,
+		ContractorName:        "Подрядчик",
+		CatalogPositionID:     catalogID,
+		UnitID:                ni(unitID),
+		UnitName:              ns("м3"),
+		PositionKeyInProposal: "1.1",
+		JobTitleInProposal:    "Разработка грунта",
+		UnitCost:              ns(cost),
+	}
+}
+
+func limits(warning, critical int64) thresholds {
+	return thresholds{warning: decimal.NewFromInt(warning), critical: decimal.NewFromInt(critical)}
+}
+
+func TestFindAnomalies_PeerMedian(t *testing.T) {
+	rows := []db.ListLotPositionPricesForAnomaliesRow{
+		price(1, 1, 10, 3, "1000"),
+		price(2, 2, 10, 3, "1050"),
+		price(3, 3, 10, 3, "980"),
+		price(4, 4, 10, 3, "10000"),
+		price(5, 4, 10, 7, "5"), // Та же позиция в другой единице — не сравнивается
+	}
+
+	checked, anomalies := findAnomalies(rows, nil, limits(30, 90), 2)
+
+	assert.Equal(t, 5, checked)
+	require.Len(t, anomalies, 1)
+	assert.Equal(t, int64(4), anomalies[0].PositionItemID)
+	assert.Equal(t, api_models.AnomalySeverityCritical, anomalies[0].Severity)
+	require.Len(t, anomalies[0].Reasons, 1)
+	reason := anomalies[0].Reasons[0]
+	assert.Equal(t, api_models.AnomalyReferencePeerMedian, reason.Reference)
+	assert.Equal(t, "1000", reason.ReferenceCost.String())
+	assert.Equal(t, int64(3), reason.ReferenceCount)
+	assert.Equal(t, "900.00", reason.DeviationPercent)
+	assert.Equal(t, api_models.AnomalyHintPossibleTypo, reason.Hint)
+}
+
+func TestFindAnomalies_NotEnoughPeers(t *testing.T) {
+	rows := []db.ListLotPositionPricesForAnomaliesRow{
+		price(1, 1, 10, 3, "1000"),
+		price(2, 2, 10, 3, "10000"),
+	}
+
+	_, anomalies := findAnomalies(rows, nil, limits(30, 90), 2)
+
+	assert.Empty(t, anomalies)
+}
+
+func TestFindAnomalies_CatalogHistory(t *testing.T) {
+	rows := []db.ListLotPositionPricesForAnomaliesRow{
+		price(1, 1, 10, 3, "600"),
+		price(2, 2, 11, 3, "1100"),
+	}
+	history := map[priceKey]historyAverage{
+		{catalogPositionID: 10, unitID: ni(3)}: {cost: decimal.NewFromInt(1000), count: 5},
+		{catalogPositionID: 11, unitID: ni(3)}: {cost: decimal.NewFromInt(1000), count: 5},
+	}
+
+	_, anomalies := findAnomalies(rows, history, limits(30, 90), 2)
+
+	require.Len(t, anomalies, 1)
+	assert.Equal(t, int64(1), anomalies[0].PositionItemID)
+	assert.Equal(t, api_models.AnomalySeverityWarning, anomalies[0].Severity)
+	assert.Equal(t, api_models.AnomalyReferenceCatalogHistory, anomalies[0].Reasons[0].Reference)
+	assert.Equal(t, "-40.00", anomalies[0].Reasons[0].DeviationPercent)
+	assert.Equal(t, api_models.AnomalyHintDumping, anomalies[0].Reasons[0].Hint)
+}
+
+func TestGetLotAnomalies_CategoryThreshold(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	org := ni(1)
+
+	mockStore.EXPECT().GetLotByID(gomock.Any(), int64(100)).Return(db.Lot{ID: 100, TenderID: 7}, nil)
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(7)).Return(db.Tender{ID: 7, CategoryID: ni(4)}, nil)
+	mockStore.EXPECT().ListLotPositionPricesForAnomalies(gomock.Any(), db.ListLotPositionPricesForAnomaliesParams{
+		Currencies: []string{"RUB"},
+		Rates:      []string{"1"},
+		LotID:      100,
+	}).Return([]db.ListLotPositionPricesForAnomaliesRow{
+		price(1, 1, 10, 3, "600"),
+		price(2, 2, 11, 3, "400"),
+	}, nil)
+	mockStore.EXPECT().ListCatalogAverageUnitCosts(gomock.Any(), db.ListCatalogAverageUnitCostsParams{
+		Currencies:         []string{"RUB"},
+		Rates:              []string{"1"},
+		CatalogPositionIds: []int64{10, 11},
+		LotID:              100,
+		Since:              time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC),
+		OrganizationID:     org,
+	}).Return([]db.ListCatalogAverageUnitCostsRow{
+		{CatalogPositionID: 10, UnitID: ni(3), AvgUnitCost: ns("1000.00"), PricesCount: 3},
+		{CatalogPositionID: 11, UnitID: ni(3), AvgUnitCost: ns("1000.00"), PricesCount: 2}, // Мало цен
+	}, nil)
+
+	resp, err := service.GetLotAnomalies(context.Background(), 100, org)
+	require.NoError(t, err)
+
+	require.NotNil(t, resp.CategoryID)
+	assert.Equal(t, int64(4), *resp.CategoryID)
+	assert.Equal(t, 50.0, resp.ThresholdPercent)
+	assert.Equal(t, 150.0, resp.CriticalPercent)
+	assert.Equal(t, 2, resp.CheckedCount)
+	assert.Empty(t, resp.Anomalies, "-40% меньше порога категории, история позиции 11 слишком мала")
+}
+
+func TestGetLotAnomalies_LotNotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetLotByID(gomock.Any(), int64(100)).Return(db.Lot{}, sql.ErrNoRows)
+
+	_, err := service.GetLotAnomalies(context.Background(), 100, sql.NullInt64{})
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
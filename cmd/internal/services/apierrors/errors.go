@@ -1,21 +1,43 @@
 package apierrors
 
-import "fmt"
+import "github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+
+// Ошибки ниже создаются по ключу сообщения из каталога i18n (lot.not_found) с
+// аргументами шаблона. Message — текст на языке по умолчанию (для логов и
+// Error()), хендлер отдаёт клиенту перевод по Key на языке запроса. Строка,
+// которой нет в каталоге, форматируется как обычный шаблон fmt, а Key
+// остаётся пустым.
 
 // ValidationError представляет ошибку валидации входных данных.
 // Используется для разделения ошибок валидации (HTTP 400) от серверных ошибок (HTTP 500).
 type ValidationError struct {
 	Message string
+	Key     string
+	Args    []interface{}
+	Details interface{} // структурированные подробности (например, нарушения схемы по полям)
 }
 
 func (e *ValidationError) Error() string {
 	return e.Message
 }
 
-// NewValidationError formats its arguments using format and returns a *ValidationError whose Message field is set to the formatted string.
-func NewValidationError(format string, args ...interface{}) error {
+// NewValidationError создает ValidationError по ключу сообщения key и аргументам его шаблона.
+func NewValidationError(key string, args ...interface{}) error {
+	return &ValidationError{
+		Message: i18n.T(i18n.Default, key, args...),
+		Key:     messageKey(key),
+		Args:    args,
+	}
+}
+
+// NewValidationErrorWithDetails создает ValidationError со структурированными
+// подробностями, которые хендлер отдает клиенту в поле details.
+func NewValidationErrorWithDetails(details interface{}, key string, args ...interface{}) error {
 	return &ValidationError{
-		Message: fmt.Sprintf(format, args...),
+		Message: i18n.T(i18n.Default, key, args...),
+		Key:     messageKey(key),
+		Args:    args,
+		Details: details,
 	}
 }
 
@@ -23,16 +45,20 @@ func NewValidationError(format string, args ...interface{}) error {
 // Используется для возврата HTTP 404 Not Found.
 type NotFoundError struct {
 	Message string
+	Key     string
+	Args    []interface{}
 }
 
 func (e *NotFoundError) Error() string {
 	return e.Message
 }
 
-// NewNotFoundError creates a NotFoundError whose Message is the result of formatting the given format string with the provided args.
-func NewNotFoundError(format string, args ...interface{}) error {
+// NewNotFoundError создает NotFoundError по ключу сообщения key и аргументам его шаблона.
+func NewNotFoundError(key string, args ...interface{}) error {
 	return &NotFoundError{
-		Message: fmt.Sprintf(format, args...),
+		Message: i18n.T(i18n.Default, key, args...),
+		Key:     messageKey(key),
+		Args:    args,
 	}
 }
 
@@ -40,6 +66,8 @@ func NewNotFoundError(format string, args ...interface{}) error {
 // Содержит структурированные данные о конфликтах для отображения на фронте.
 type ConflictError struct {
 	Message   string
+	Key       string
+	Args      []interface{}
 	Conflicts interface{} // структурированные данные конфликтов
 }
 
@@ -47,10 +75,26 @@ func (e *ConflictError) Error() string {
 	return e.Message
 }
 
-// NewConflictError creates a ConflictError with the given message and structured conflict data.
-func NewConflictError(message string, conflicts interface{}) error {
+// NewConflictError создает ConflictError с сообщением key (без аргументов) и
+// структурированными данными конфликта.
+func NewConflictError(key string, conflicts interface{}) error {
+	return NewConflictErrorf(conflicts, key)
+}
+
+// NewConflictErrorf — как NewConflictError, но с аргументами шаблона сообщения.
+func NewConflictErrorf(conflicts interface{}, key string, args ...interface{}) error {
 	return &ConflictError{
-		Message:   message,
+		Message:   i18n.T(i18n.Default, key, args...),
+		Key:       messageKey(key),
+		Args:      args,
 		Conflicts: conflicts,
 	}
 }
+
+// messageKey возвращает key, если он есть в каталоге сообщений.
+func messageKey(key string) string {
+	if i18n.Has(key) {
+		return key
+	}
+	return ""
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -18,6 +19,8 @@ import (
 
 	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
 	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/notifications"
 	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
 )
 
@@ -26,6 +29,7 @@ var (
 	ErrInvalidToken       = errors.New("invalid or expired token")
 	ErrTokenExpired       = errors.New("access token expired")
 	ErrSessionNotFound    = errors.New("session not found or expired")
+	ErrTokenRevoked       = errors.New("access token revoked")
 )
 
 // dummyPasswordHash используется для защиты от timing attacks
@@ -87,23 +91,76 @@ func ipToInet(ip *net.IP) pqtype.Inet {
 type JWTClaims struct {
 	UserID int64  `json:"user_id"`
 	Role   string `json:"role"`
+	// OrganizationID — организация пользователя; 0 в токенах, выпущенных до миграции 000027
+	OrganizationID int64 `json:"org_id"`
+	// TokenVersion — users.token_version на момент выпуска; 0 в токенах, выпущенных до миграции 000039
+	TokenVersion int64 `json:"ver"`
+	// ActingAdminID — администратор, действующий от имени пользователя
+	// (режим имперсонации, миграция 000053); 0 в обычных токенах
+	ActingAdminID int64 `json:"acting_admin_id,omitempty"`
+	// ImpersonationID — impersonation_sessions.id токена имперсонации
+	ImpersonationID int64 `json:"imp_sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// Impersonated сообщает, выпущен ли токен для имперсонации.
+func (c *JWTClaims) Impersonated() bool {
+	return c.ActingAdminID != 0
+}
+
 // Service предоставляет методы для аутентификации
 type Service struct {
-	store  db.Store
-	config *config.Config
-	logger logging.Logger
+	store    db.Store
+	config   *config.Config
+	logger   logging.Logger
+	denylist *AccessTokenDenylist
+	versions *tokenVersionCache
+	notifier *notifications.Service // Приглашения и ссылки сброса пароля; nil — письма не отправляются
+	oidc     *OIDCClient            // Вход через SSO; nil — выключен (см. EnableOIDC)
+	// Секреты подписи access-токенов; nil — берутся из config.Auth
+	keys atomic.Pointer[jwtKeys]
+}
+
+// jwtKeys — секреты подписи access-токенов. Новые токены подписываются current,
+// при проверке принимаются оба: previous нужен на время ротации секрета.
+type jwtKeys struct {
+	current  []byte
+	previous []byte
+}
+
+// NewService создает новый auth service. notifier может быть nil.
+func NewService(store db.Store, cfg *config.Config, logger logging.Logger, notifier *notifications.Service) *Service {
+	s := &Service{
+		store:    store,
+		config:   cfg,
+		logger:   logger,
+		denylist: NewAccessTokenDenylist(cfg.Auth.AccessTokenTTL),
+		versions: newTokenVersionCache(cfg.Auth.TokenVersionCacheTTL),
+		notifier: notifier,
+	}
+	s.RotateJWTSecrets(cfg.Auth.JWTSecret, cfg.Auth.JWTSecretPrevious)
+	return s
+}
+
+// RotateJWTSecrets заменяет секреты подписи access-токенов без перезапуска.
+// Токены, подписанные previous, принимаются до истечения; previous может быть пустым.
+func (s *Service) RotateJWTSecrets(current, previous string) {
+	keys := &jwtKeys{current: []byte(current)}
+	if previous != "" {
+		keys.previous = []byte(previous)
+	}
+	s.keys.Store(keys)
 }
 
-// NewService создает новый auth service
-func NewService(store db.Store, cfg *config.Config, logger logging.Logger) *Service {
-	return &Service{
-		store:  store,
-		config: cfg,
-		logger: logger,
+func (s *Service) signingKeys() *jwtKeys {
+	if keys := s.keys.Load(); keys != nil {
+		return keys
+	}
+	keys := &jwtKeys{current: []byte(s.config.Auth.JWTSecret)}
+	if s.config.Auth.JWTSecretPrevious != "" {
+		keys.previous = []byte(s.config.Auth.JWTSecretPrevious)
 	}
+	return keys
 }
 
 // LoginResult содержит результат успешной аутентификации
@@ -148,23 +205,9 @@ func (s *Service) Login(ctx context.Context, email, password string, ipAddress *
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
-	// Валидация и обрезка User-Agent
-	userAgent = validateUserAgent(userAgent)
-
 	// Создание сессии + обновление last_login_at в одной транзакции
 	err = s.store.ExecTx(ctx, func(q *db.Queries) error {
-		sessionParams := db.CreateUserSessionParams{
-			UserID:           userAuth.ID,
-			RefreshTokenHash: refreshHash,
-			UserAgent: sql.NullString{
-				String: userAgent,
-				Valid:  userAgent != "",
-			},
-			IpAddress: ipToInet(ipAddress),
-			ExpiresAt: time.Now().Add(s.config.Auth.RefreshTokenTTL),
-		}
-
-		_, err := q.CreateUserSession(ctx, sessionParams)
+		_, err := q.CreateUserSession(ctx, s.newSessionParams(userAuth.ID, refreshHash, ipAddress, userAgent))
 		if err != nil {
 			return fmt.Errorf("failed to create session: %w", err)
 		}
@@ -184,7 +227,7 @@ func (s *Service) Login(ctx context.Context, email, password string, ipAddress *
 	s.logger.Infof("successful login for user (id_hash: %s)", hashUserID(userAuth.ID))
 
 	// Генерация access token
-	accessToken, err := s.generateAccessToken(userAuth.ID, userAuth.Role)
+	accessToken, err := s.generateAccessToken(userAuth.ID, userAuth.Role, userAuth.OrganizationID, userAuth.TokenVersion)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
@@ -193,11 +236,12 @@ func (s *Service) Login(ctx context.Context, email, password string, ipAddress *
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 		User: db.User{
-			ID:        userAuth.ID,
-			Email:     userAuth.Email,
-			Role:      userAuth.Role,
-			CreatedAt: userAuth.CreatedAt,
-			UpdatedAt: userAuth.UpdatedAt,
+			ID:             userAuth.ID,
+			Email:          userAuth.Email,
+			Role:           userAuth.Role,
+			OrganizationID: userAuth.OrganizationID,
+			CreatedAt:      userAuth.CreatedAt,
+			UpdatedAt:      userAuth.UpdatedAt,
 		},
 	}, nil
 }
@@ -247,22 +291,8 @@ func (s *Service) Refresh(ctx context.Context, refreshToken string, ipAddress *n
 			return fmt.Errorf("failed to generate refresh token: %w", err)
 		}
 
-		// Валидация и обрезка User-Agent
-		userAgent = validateUserAgent(userAgent)
-
 		// Создаем новую сессию
-		sessionParams := db.CreateUserSessionParams{
-			UserID:           session.UserID,
-			RefreshTokenHash: newRefreshHash,
-			UserAgent: sql.NullString{
-				String: userAgent,
-				Valid:  userAgent != "",
-			},
-			IpAddress: ipToInet(ipAddress),
-			ExpiresAt: time.Now().Add(s.config.Auth.RefreshTokenTTL),
-		}
-
-		_, err = q.CreateUserSession(ctx, sessionParams)
+		_, err = q.CreateUserSession(ctx, s.newSessionParams(session.UserID, newRefreshHash, ipAddress, userAgent))
 		if err != nil {
 			return fmt.Errorf("failed to create new session: %w", err)
 		}
@@ -273,8 +303,14 @@ func (s *Service) Refresh(ctx context.Context, refreshToken string, ipAddress *n
 			return fmt.Errorf("failed to get user: %w", err)
 		}
 
+		// Деактивация отзывает сессии, но проверяем и здесь: сессия могла
+		// быть создана параллельно с деактивацией
+		if !user.IsActive {
+			return ErrSessionNotFound
+		}
+
 		// Генерируем новый access token
-		accessToken, err := s.generateAccessToken(user.ID, user.Role)
+		accessToken, err := s.generateAccessToken(user.ID, user.Role, user.OrganizationID, user.TokenVersion)
 		if err != nil {
 			return fmt.Errorf("failed to generate access token: %w", err)
 		}
@@ -314,6 +350,155 @@ func (s *Service) Logout(ctx context.Context, refreshToken string) error {
 	return nil
 }
 
+// LogoutAll завершает все сессии пользователя и отзывает его access-токены
+// на всех устройствах, включая текущее (users.token_version и denylist).
+// Возвращает число завершенных сессий.
+func (s *Service) LogoutAll(ctx context.Context, userID int64) (int64, error) {
+	revokedAt := time.Now()
+
+	var revoked int64
+	err := s.store.ExecTx(ctx, func(q *db.Queries) error {
+		if _, err := q.RevokeUserTokens(ctx, db.RevokeUserTokensParams{TokensRevokedAt: revokedAt, ID: userID}); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrSessionNotFound
+			}
+			return fmt.Errorf("failed to revoke access tokens: %w", err)
+		}
+
+		var err error
+		revoked, err = q.RevokeAllActiveSessionsByUserID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to revoke user sessions: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.denylist.Revoke(userID, revokedAt)
+	s.versions.forget(userID)
+	s.logger.Infof("user (id_hash: %s) logged out on all devices, revoked sessions: %d", hashUserID(userID), revoked)
+	return revoked, nil
+}
+
+// ChangePassword меняет пароль пользователя после проверки текущего.
+//
+// В одной транзакции обновляется хеш, завершаются все сессии пользователя и
+// создается новая сессия для текущего клиента: остальные устройства должны
+// войти заново, а текущий клиент получает новую пару токенов. Неверный
+// текущий пароль — ErrInvalidCredentials.
+func (s *Service) ChangePassword(ctx context.Context, userID int64, currentPassword, newPassword string, ipAddress *net.IP, userAgent string) (*ChangePasswordResult, error) {
+	if err := validatePassword(newPassword); err != nil {
+		return nil, err
+	}
+	if currentPassword == newPassword {
+		return nil, apierrors.NewValidationError("auth.new_password_same")
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	refreshToken, refreshHash, err := generateRefreshToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	// Access-токены отзываются на конец предыдущей секунды: iat хранится с
+	// точностью до секунды, и отзыв "на сейчас" отклонил бы и новый токен
+	// текущего клиента (см. AccessTokenDenylist.IsRevoked).
+	revokedAt := time.Now().Add(-time.Second)
+
+	var (
+		role           string
+		organizationID int64
+		tokenVersion   int64
+		revoked        int64
+	)
+	err = s.store.ExecTx(ctx, func(q *db.Queries) error {
+		user, err := q.GetUserAuthByIDForUpdate(ctx, userID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvalidCredentials
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(currentPassword)); err != nil {
+			return ErrInvalidCredentials
+		}
+		if !user.IsActive {
+			return ErrInvalidCredentials
+		}
+		role = user.Role
+		organizationID = user.OrganizationID
+		// ChangeUserPassword увеличивает версию; строка заблокирована до коммита
+		tokenVersion = user.TokenVersion + 1
+
+		if err := q.ChangeUserPassword(ctx, db.ChangeUserPasswordParams{
+			PasswordHash:    string(passwordHash),
+			TokensRevokedAt: revokedAt,
+			ID:              userID,
+		}); err != nil {
+			return fmt.Errorf("failed to update password: %w", err)
+		}
+
+		revoked, err = q.RevokeAllActiveSessionsByUserID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to revoke user sessions: %w", err)
+		}
+
+		if _, err := q.CreateUserSession(ctx, s.newSessionParams(userID, refreshHash, ipAddress, userAgent)); err != nil {
+			return fmt.Errorf("failed to create session: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidCredentials) {
+			s.logger.Warnf("password change for user (id_hash: %s) rejected: invalid current password", hashUserID(userID))
+		}
+		return nil, err
+	}
+
+	s.denylist.Revoke(userID, revokedAt)
+	s.versions.forget(userID)
+	s.logger.Infof("password of user (id_hash: %s) changed, revoked sessions: %d", hashUserID(userID), revoked)
+
+	accessToken, err := s.generateAccessToken(userID, role, organizationID, tokenVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	return &ChangePasswordResult{
+		AccessToken:     accessToken,
+		RefreshToken:    refreshToken,
+		RevokedSessions: revoked,
+	}, nil
+}
+
+// ChangePasswordResult содержит новую пару токенов текущего клиента.
+type ChangePasswordResult struct {
+	AccessToken     string
+	RefreshToken    string
+	RevokedSessions int64
+}
+
+// newSessionParams собирает параметры новой сессии; User-Agent обрезается.
+func (s *Service) newSessionParams(userID int64, refreshHash string, ipAddress *net.IP, userAgent string) db.CreateUserSessionParams {
+	userAgent = validateUserAgent(userAgent)
+	return db.CreateUserSessionParams{
+		UserID:           userID,
+		RefreshTokenHash: refreshHash,
+		UserAgent: sql.NullString{
+			String: userAgent,
+			Valid:  userAgent != "",
+		},
+		IpAddress: ipToInet(ipAddress),
+		ExpiresAt: time.Now().Add(s.config.Auth.RefreshTokenTTL),
+	}
+}
+
 // ValidateAccessToken валидирует JWT access token и возвращает claims
 func (s *Service) ValidateAccessToken(tokenString string) (*JWTClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
@@ -321,7 +506,12 @@ func (s *Service) ValidateAccessToken(tokenString string) (*JWTClaims, error) {
 		if token.Method != jwt.SigningMethodHS256 {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(s.config.Auth.JWTSecret), nil
+		keys := s.signingKeys()
+		if keys.previous == nil {
+			return keys.current, nil
+		}
+		// Во время ротации подпись проверяется обоими секретами
+		return jwt.VerificationKeySet{Keys: []jwt.VerificationKey{keys.current, keys.previous}}, nil
 	})
 
 	if err != nil {
@@ -333,30 +523,41 @@ func (s *Service) ValidateAccessToken(tokenString string) (*JWTClaims, error) {
 	}
 
 	claims, ok := token.Claims.(*JWTClaims)
-	if !ok || !token.Valid {
+	if !ok || !token.Valid || claims.IssuedAt == nil {
 		return nil, ErrInvalidToken
 	}
 
+	// Токены, выпущенные до смены роли или деактивации, отклоняются сразу
+	if s.denylist.IsRevoked(claims.UserID, claims.IssuedAt.Time) {
+		return nil, ErrTokenRevoked
+	}
+
 	return claims, nil
 }
 
 // generateAccessToken создает JWT access token
-func (s *Service) generateAccessToken(userID int64, role string) (string, error) {
+func (s *Service) generateAccessToken(userID int64, role string, organizationID, tokenVersion int64) (string, error) {
+	return s.signAccessToken(JWTClaims{
+		UserID:         userID,
+		Role:           role,
+		OrganizationID: organizationID,
+		TokenVersion:   tokenVersion,
+	}, time.Now().Add(s.config.Auth.AccessTokenTTL))
+}
+
+// signAccessToken заполняет registered claims и подписывает токен текущим секретом.
+func (s *Service) signAccessToken(claims JWTClaims, expiresAt time.Time) (string, error) {
 	now := time.Now()
-	claims := JWTClaims{
-		UserID: userID,
-		Role:   role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(s.config.Auth.AccessTokenTTL)),
-			IssuedAt:  jwt.NewNumericDate(now),
-			NotBefore: jwt.NewNumericDate(now),
-			Issuer:    "tenders-go",
-			Subject:   fmt.Sprintf("%d", userID),
-		},
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		Issuer:    "tenders-go",
+		Subject:   fmt.Sprintf("%d", claims.UserID),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.config.Auth.JWTSecret))
+	return token.SignedString(s.signingKeys().current)
 }
 
 // generateRefreshToken генерирует случайный refresh token и его SHA-256 хеш
@@ -50,6 +50,15 @@ SCENARIO 1: Access Token Generation and Validation
   WHEN token is validated
   THEN validation fails with ErrInvalidToken
 
+- GIVEN the JWT secret was rotated (old secret kept as previous)
+  WHEN a token signed with the old secret is validated
+  THEN it passes, new tokens are signed with the new secret
+  AND once previous is dropped the old token fails with ErrInvalidToken
+
+- GIVEN a token issued before the user's tokens were revoked
+  WHEN token is validated
+  THEN validation fails with ErrTokenRevoked, tokens of other users still pass
+
 SCENARIO 2: Refresh Token Generation
 - GIVEN refresh token generation is requested
   WHEN tokens are generated
@@ -81,9 +90,11 @@ func setupTestService(t *testing.T) *Service {
 
 	// Store is nil for token-only tests (no DB operations)
 	return &Service{
-		store:  nil,
-		config: cfg,
-		logger: logger,
+		store:    nil,
+		config:   cfg,
+		logger:   logger,
+		denylist: NewAccessTokenDenylist(cfg.Auth.AccessTokenTTL),
+		versions: newTokenVersionCache(5 * time.Second),
 	}
 }
 
@@ -96,9 +107,10 @@ func TestGenerateAccessToken_Success(t *testing.T) {
 	service := setupTestService(t)
 	userID := int64(123)
 	role := "admin"
+	organizationID := int64(3)
 
 	// WHEN: Access token is generated
-	token, err := service.generateAccessToken(userID, role)
+	token, err := service.generateAccessToken(userID, role, organizationID, 0)
 
 	// THEN: Token is valid and contains correct data
 	require.NoError(t, err)
@@ -110,6 +122,7 @@ func TestGenerateAccessToken_Success(t *testing.T) {
 
 	assert.Equal(t, userID, claims.UserID)
 	assert.Equal(t, role, claims.Role)
+	assert.Equal(t, organizationID, claims.OrganizationID)
 	assert.Equal(t, "tenders-go", claims.Issuer)
 	assert.NotNil(t, claims.ExpiresAt)
 	assert.True(t, claims.ExpiresAt.After(time.Now()))
@@ -123,7 +136,7 @@ func TestValidateAccessToken_Success(t *testing.T) {
 	userID := int64(456)
 	role := "user"
 
-	token, err := service.generateAccessToken(userID, role)
+	token, err := service.generateAccessToken(userID, role, 1, 0)
 	require.NoError(t, err)
 
 	// WHEN: Token is validated
@@ -154,7 +167,7 @@ func TestValidateAccessToken_Expired(t *testing.T) {
 	}
 
 	// Generate token that's already expired
-	expiredToken, err := service.generateAccessToken(123, "user")
+	expiredToken, err := service.generateAccessToken(123, "user", 1, 0)
 	require.NoError(t, err)
 
 	// WHEN: Expired token is validated
@@ -183,7 +196,7 @@ func TestValidateAccessToken_WrongSignature(t *testing.T) {
 	}
 
 	// Generate token with service1's secret
-	token, err := service1.generateAccessToken(123, "user")
+	token, err := service1.generateAccessToken(123, "user", 1, 0)
 	require.NoError(t, err)
 
 	// WHEN: Token is validated with service2's secret (different key)
@@ -195,6 +208,37 @@ func TestValidateAccessToken_WrongSignature(t *testing.T) {
 	assert.Equal(t, ErrInvalidToken, err)
 }
 
+func TestValidateAccessToken_PreviousSecretDuringRotation(t *testing.T) {
+	// GIVEN: A token signed before the secret was rotated
+	service := setupTestService(t)
+	oldSecret := service.config.Auth.JWTSecret
+	newSecret := "rotated-secret-key-minimum-32-chars-long"
+
+	oldToken, err := service.generateAccessToken(123, "user", 1, 0)
+	require.NoError(t, err)
+
+	// WHEN: The secret is rotated, the old one kept as previous
+	service.RotateJWTSecrets(newSecret, oldSecret)
+
+	// THEN: The old token is still accepted
+	claims, err := service.ValidateAccessToken(oldToken)
+	require.NoError(t, err)
+	assert.Equal(t, int64(123), claims.UserID)
+
+	// AND: New tokens are signed with the new secret only
+	newToken, err := service.generateAccessToken(456, "user", 1, 0)
+	require.NoError(t, err)
+	_, err = jwt.Parse(newToken, func(*jwt.Token) (interface{}, error) { return []byte(newSecret), nil })
+	require.NoError(t, err)
+
+	// AND: After previous is dropped the old token is rejected
+	service.RotateJWTSecrets(newSecret, "")
+	_, err = service.ValidateAccessToken(oldToken)
+	assert.Equal(t, ErrInvalidToken, err)
+	_, err = service.ValidateAccessToken(newToken)
+	assert.NoError(t, err)
+}
+
 func TestValidateAccessToken_Malformed(t *testing.T) {
 	// GIVEN: Various malformed token strings
 	service := setupTestService(t)
@@ -253,7 +297,7 @@ func TestValidateAccessToken_UnsafeAlgorithm(t *testing.T) {
 func TestValidateAccessToken_TamperedPayload(t *testing.T) {
 	// GIVEN: A valid token
 	service := setupTestService(t)
-	token, err := service.generateAccessToken(123, "user")
+	token, err := service.generateAccessToken(123, "user", 1, 0)
 	require.NoError(t, err)
 
 	// Split token into parts
@@ -491,6 +535,50 @@ func TestValidateUserAgent_PreservesStructure(t *testing.T) {
 	assert.Contains(t, result, "Mozilla/5.0")
 }
 
+func TestValidateAccessToken_RevokedUser(t *testing.T) {
+	// GIVEN: Tokens of two users, then tokens of the first one are revoked
+	service := setupTestService(t)
+	revokedToken, err := service.generateAccessToken(1, "admin", 1, 0)
+	require.NoError(t, err)
+	otherToken, err := service.generateAccessToken(2, "admin", 1, 0)
+	require.NoError(t, err)
+
+	service.revokeAccessTokens(1)
+
+	// WHEN/THEN: The revoked user's token is rejected
+	claims, err := service.ValidateAccessToken(revokedToken)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+	assert.Nil(t, claims)
+
+	// AND: Other users are not affected
+	claims, err = service.ValidateAccessToken(otherToken)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), claims.UserID)
+}
+
+func TestAccessTokenDenylist(t *testing.T) {
+	revokedAt := time.Date(2026, 1, 1, 12, 0, 0, 500_000_000, time.UTC)
+	d := NewAccessTokenDenylist(15 * time.Minute)
+	d.Revoke(1, revokedAt)
+
+	// Tokens issued earlier or within the same second are revoked
+	assert.True(t, d.IsRevoked(1, revokedAt.Add(-time.Minute)))
+	assert.True(t, d.IsRevoked(1, revokedAt.Truncate(time.Second)))
+	// Tokens issued after the next second are valid
+	assert.False(t, d.IsRevoked(1, revokedAt.Add(time.Second)))
+	assert.False(t, d.IsRevoked(2, revokedAt.Add(-time.Minute)))
+
+	// An older revocation does not overwrite a newer one
+	d.Revoke(1, revokedAt.Add(-time.Hour))
+	assert.True(t, d.IsRevoked(1, revokedAt.Add(-time.Minute)))
+
+	// Entries older than access TTL are pruned
+	d.Prune(revokedAt.Add(10 * time.Minute))
+	assert.Equal(t, 1, d.Len())
+	d.Prune(revokedAt.Add(16 * time.Minute))
+	assert.Equal(t, 0, d.Len())
+}
+
 // =============================================================================
 // ERROR CONSTANTS TESTS
 // =============================================================================
@@ -502,6 +590,7 @@ func TestErrorConstants(t *testing.T) {
 	assert.NotEqual(t, ErrInvalidCredentials, ErrSessionNotFound)
 	assert.NotEqual(t, ErrTokenExpired, ErrInvalidToken)
 	assert.NotEqual(t, ErrTokenExpired, ErrInvalidCredentials)
+	assert.NotEqual(t, ErrTokenRevoked, ErrInvalidToken)
 
 	// Verify error messages are meaningful
 	assert.Contains(t, ErrInvalidCredentials.Error(), "invalid")
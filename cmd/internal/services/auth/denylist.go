@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// AccessTokenDenylist хранит для пользователей момент принудительного отзыва
+// токенов. Access-токен пользователя, выпущенный не позже этого момента,
+// считается отозванным.
+//
+// JWT не хранится на сервере, поэтому отозвать конкретный токен нельзя —
+// отзываются все токены пользователя, выпущенные до смены роли или статуса.
+// Запись нужна только пока живут такие токены (access TTL), после чего
+// удаляется.
+type AccessTokenDenylist struct {
+	mu            sync.RWMutex
+	revokedBefore map[int64]time.Time
+	ttl           time.Duration
+}
+
+// NewAccessTokenDenylist создает пустой denylist. ttl — время жизни access-токена.
+func NewAccessTokenDenylist(ttl time.Duration) *AccessTokenDenylist {
+	return &AccessTokenDenylist{
+		revokedBefore: make(map[int64]time.Time),
+		ttl:           ttl,
+	}
+}
+
+// Revoke отзывает все access-токены пользователя, выпущенные не позже at.
+// Более ранний момент не перезаписывает уже известный более поздний.
+func (d *AccessTokenDenylist) Revoke(userID int64, at time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if current, ok := d.revokedBefore[userID]; ok && !at.After(current) {
+		return
+	}
+	d.revokedBefore[userID] = at
+}
+
+// IsRevoked сообщает, отозван ли токен пользователя, выпущенный в issuedAt.
+//
+// iat в JWT хранится с точностью до секунды, поэтому сравнение идет по секундам
+// и токен, выпущенный в ту же секунду, что и отзыв, тоже считается отозванным:
+// лишний повторный вход безопаснее, чем пропущенный старый токен.
+func (d *AccessTokenDenylist) IsRevoked(userID int64, issuedAt time.Time) bool {
+	d.mu.RLock()
+	revokedAt, ok := d.revokedBefore[userID]
+	d.mu.RUnlock()
+
+	if !ok {
+		return false
+	}
+	return !issuedAt.Truncate(time.Second).After(revokedAt.Truncate(time.Second))
+}
+
+// Prune удаляет записи, которые уже не могут затронуть действующий токен.
+func (d *AccessTokenDenylist) Prune(now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for userID, revokedAt := range d.revokedBefore {
+		if now.Sub(revokedAt) > d.ttl {
+			delete(d.revokedBefore, userID)
+		}
+	}
+}
+
+// Len возвращает число пользователей в denylist.
+func (d *AccessTokenDenylist) Len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.revokedBefore)
+}
@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+var (
+	// ErrImpersonationEnded — сессия имперсонации завершена, просрочена или
+	// администратор лишился прав: токен больше не принимается.
+	ErrImpersonationEnded = errors.New("impersonation session ended")
+	// ErrNotImpersonating — завершение имперсонации обычным токеном.
+	ErrNotImpersonating = errors.New("request is not impersonated")
+)
+
+// ImpersonationResult содержит access-токен имперсонации и описание сессии.
+// Refresh-токен не выдается: сессия не продлевается, по истечении
+// администратор возвращается к своей сессии через /auth/refresh.
+type ImpersonationResult struct {
+	AccessToken string
+	Session     api_models.ImpersonationResponse
+}
+
+// Impersonate начинает сессию, в которой администратор actorID действует от
+// имени пользователя userID: выдается access-токен пользователя со сроком
+// auth.impersonation_ttl, помеченный acting_admin_id. Нельзя действовать от
+// своего имени, от имени неактивного пользователя и пользователя, которому
+// разрешено управлять пользователями (другого администратора).
+func (s *Service) Impersonate(ctx context.Context, actorID, userID int64, ipAddress *net.IP, userAgent string) (*ImpersonationResult, error) {
+	if userID <= 0 {
+		return nil, apierrors.NewValidationError("request.id_positive_got", userID)
+	}
+	if actorID == userID {
+		return nil, apierrors.NewValidationError("auth.cannot_impersonate_self")
+	}
+
+	userAgent = validateUserAgent(userAgent)
+
+	var (
+		claims   JWTClaims
+		session  db.ImpersonationSession
+		response api_models.AdminUserResponse
+	)
+	err := s.store.ExecTx(ctx, func(q *db.Queries) error {
+		user, err := q.GetUserByID(ctx, userID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return apierrors.NewNotFoundError("auth.user_id_not_found", userID)
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		if !user.IsActive {
+			return apierrors.NewValidationError("auth.cannot_impersonate_inactive")
+		}
+		if HasPermission(user.Role, PermissionUsersManage) {
+			return apierrors.NewValidationError("auth.cannot_impersonate_admin")
+		}
+
+		session, err = q.CreateImpersonationSession(ctx, db.CreateImpersonationSessionParams{
+			AdminID:   actorID,
+			UserID:    userID,
+			IpAddress: ipToInet(ipAddress),
+			UserAgent: sql.NullString{String: userAgent, Valid: userAgent != ""},
+			ExpiresAt: time.Now().Add(s.config.Auth.ImpersonationTTL),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create impersonation session: %w", err)
+		}
+
+		claims = JWTClaims{
+			UserID:          user.ID,
+			Role:            user.Role,
+			OrganizationID:  user.OrganizationID,
+			TokenVersion:    user.TokenVersion,
+			ActingAdminID:   actorID,
+			ImpersonationID: session.ID,
+		}
+		response = *adminUserResponse(user.ID, user.Email, user.Role, user.IsActive, user.OrganizationID,
+			user.LastLoginAt, user.CreatedAt, user.UpdatedAt, sql.NullTime{}, 0)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.signAccessToken(claims, session.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	s.logger.Warnf("admin (id_hash: %s) started impersonating user (id_hash: %s), session %d expires at %s",
+		hashUserID(actorID), hashUserID(userID), session.ID, session.ExpiresAt.Format(time.RFC3339))
+
+	return &ImpersonationResult{
+		AccessToken: accessToken,
+		Session: api_models.ImpersonationResponse{
+			SessionID:     session.ID,
+			ActingAdminID: actorID,
+			User:          response,
+			ExpiresAt:     session.ExpiresAt,
+		},
+	}, nil
+}
+
+// AuditImpersonatedRequest записывает запрос под имперсонацией в журнал до
+// его выполнения и возвращает id записи (статус дописывает
+// CompleteImpersonationAudit). Сессия проверяется той же вставкой:
+// ErrImpersonationEnded — запрос выполнять нельзя.
+func (s *Service) AuditImpersonatedRequest(ctx context.Context, claims *JWTClaims, method, path string, ipAddress *net.IP) (int64, error) {
+	if !claims.Impersonated() {
+		return 0, ErrNotImpersonating
+	}
+
+	auditID, err := s.store.InsertImpersonationAudit(ctx, db.InsertImpersonationAuditParams{
+		Method:    method,
+		Path:      path,
+		IpAddress: ipToInet(ipAddress),
+		SessionID: claims.ImpersonationID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrImpersonationEnded
+		}
+		return 0, fmt.Errorf("failed to write impersonation audit: %w", err)
+	}
+	return auditID, nil
+}
+
+// CompleteImpersonationAudit дописывает в запись журнала статус ответа.
+func (s *Service) CompleteImpersonationAudit(ctx context.Context, auditID int64, status int) error {
+	err := s.store.CompleteImpersonationAudit(ctx, db.CompleteImpersonationAuditParams{
+		Status: int32(status),
+		ID:     auditID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete impersonation audit: %w", err)
+	}
+	return nil
+}
+
+// EndImpersonation завершает сессию имперсонации: выданный для нее токен
+// перестает приниматься (см. AuditImpersonatedRequest). Повторное
+// завершение не считается ошибкой.
+func (s *Service) EndImpersonation(ctx context.Context, claims *JWTClaims) error {
+	if !claims.Impersonated() {
+		return ErrNotImpersonating
+	}
+
+	ended, err := s.store.EndImpersonationSession(ctx, db.EndImpersonationSessionParams{
+		ID:      claims.ImpersonationID,
+		AdminID: claims.ActingAdminID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to end impersonation session: %w", err)
+	}
+
+	if ended > 0 {
+		s.logger.Infof("admin (id_hash: %s) ended impersonation of user (id_hash: %s), session %d",
+			hashUserID(claims.ActingAdminID), hashUserID(claims.UserID), claims.ImpersonationID)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR ADMIN IMPERSONATION (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Invisible support actions — the token is marked with acting_admin_id and every
+   request is audited before it runs
+2. Privilege escalation — an admin cannot impersonate another admin, an inactive
+   user or themselves
+3. Lingering access — the session is short and stops working once ended
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Impersonate
+- GIVEN an active viewer → session stored, the access token carries the viewer's
+  identity plus acting_admin_id and the session id, expiring with the session
+- GIVEN self → ValidationError without a transaction
+- GIVEN an admin or inactive target → ValidationError, no session created
+- GIVEN a missing user → NotFoundError
+
+SCENARIO 2: AuditImpersonatedRequest
+- GIVEN an active session → audit row id returned
+- GIVEN an ended/expired session (no row inserted) → ErrImpersonationEnded
+- GIVEN a regular token → ErrNotImpersonating without touching the store
+
+SCENARIO 3: EndImpersonation
+- GIVEN an impersonation token → the session is ended for its admin
+- GIVEN a regular token → ErrNotImpersonating
+*/
+
+var impersonationSessionColumns = []string{"id", "admin_id", "user_id", "ip_address", "user_agent", "started_at", "expires_at", "ended_at"}
+
+func TestImpersonate_IssuesMarkedShortLivedToken(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+	now := time.Now()
+	expiresAt := now.Add(30 * time.Minute).Truncate(time.Second)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT id, email, role").
+				WithArgs(int64(7)).
+				WillReturnRows(sqlmock.NewRows(userByIDColumns).
+					AddRow(int64(7), "user@example.com", RoleViewer, true, int64(3), nil, now, now, int64(2)))
+			mock.ExpectQuery("INSERT INTO impersonation_sessions").
+				WithArgs(int64(1), int64(7), sqlmock.AnyArg(), "support-browser", sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows(impersonationSessionColumns).
+					AddRow(int64(11), int64(1), int64(7), nil, "support-browser", now, expiresAt, nil))
+		}),
+	)
+
+	result, err := service.Impersonate(context.Background(), 1, 7, nil, "support-browser")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), result.Session.SessionID)
+	assert.Equal(t, int64(1), result.Session.ActingAdminID)
+	assert.Equal(t, "user@example.com", result.Session.User.Email)
+	assert.WithinDuration(t, expiresAt, result.Session.ExpiresAt, 0)
+
+	claims, err := service.ValidateAccessToken(result.AccessToken)
+	require.NoError(t, err)
+	assert.True(t, claims.Impersonated())
+	assert.Equal(t, int64(7), claims.UserID)
+	assert.Equal(t, RoleViewer, claims.Role)
+	assert.Equal(t, int64(3), claims.OrganizationID)
+	assert.Equal(t, int64(2), claims.TokenVersion)
+	assert.Equal(t, int64(1), claims.ActingAdminID)
+	assert.Equal(t, int64(11), claims.ImpersonationID)
+	assert.WithinDuration(t, expiresAt, claims.ExpiresAt.Time, 0)
+}
+
+func TestImpersonate_RejectsForbiddenTargets(t *testing.T) {
+	now := time.Now()
+
+	t.Run("self", func(t *testing.T) {
+		service, _ := setupUserAdminService(t)
+
+		// No store expectations: gomock fails the test if the store is touched
+		_, err := service.Impersonate(context.Background(), 1, 1, nil, "")
+
+		var validationErr *apierrors.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "auth.cannot_impersonate_self", validationErr.Key)
+	})
+
+	cases := []struct {
+		name    string
+		role    string
+		active  bool
+		wantKey string
+	}{
+		{"admin", RoleAdmin, true, "auth.cannot_impersonate_admin"},
+		{"inactive", RoleEditor, false, "auth.cannot_impersonate_inactive"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, mockStore := setupUserAdminService(t)
+			mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+				execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+					mock.ExpectQuery("SELECT id, email, role").
+						WithArgs(int64(7)).
+						WillReturnRows(sqlmock.NewRows(userByIDColumns).
+							AddRow(int64(7), "user@example.com", tc.role, tc.active, int64(1), nil, now, now, int64(0)))
+					// No INSERT expected: sqlmock fails on an unexpected session
+				}),
+			)
+
+			_, err := service.Impersonate(context.Background(), 1, 7, nil, "")
+
+			var validationErr *apierrors.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tc.wantKey, validationErr.Key)
+		})
+	}
+
+	t.Run("missing user", func(t *testing.T) {
+		service, mockStore := setupUserAdminService(t)
+		mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+			execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT id, email, role").
+					WithArgs(int64(7)).
+					WillReturnError(sql.ErrNoRows)
+			}),
+		)
+
+		_, err := service.Impersonate(context.Background(), 1, 7, nil, "")
+
+		var notFoundErr *apierrors.NotFoundError
+		assert.ErrorAs(t, err, &notFoundErr)
+	})
+}
+
+func TestAuditImpersonatedRequest(t *testing.T) {
+	claims := &JWTClaims{UserID: 7, ActingAdminID: 1, ImpersonationID: 11}
+
+	t.Run("active session", func(t *testing.T) {
+		service, mockStore := setupUserAdminService(t)
+		mockStore.EXPECT().InsertImpersonationAudit(gomock.Any(), db.InsertImpersonationAuditParams{
+			Method:    "PATCH",
+			Path:      "/api/v1/tenders/5",
+			SessionID: 11,
+		}).Return(int64(42), nil)
+
+		auditID, err := service.AuditImpersonatedRequest(context.Background(), claims, "PATCH", "/api/v1/tenders/5", nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), auditID)
+	})
+
+	t.Run("ended session", func(t *testing.T) {
+		service, mockStore := setupUserAdminService(t)
+		mockStore.EXPECT().InsertImpersonationAudit(gomock.Any(), gomock.Any()).Return(int64(0), sql.ErrNoRows)
+
+		_, err := service.AuditImpersonatedRequest(context.Background(), claims, "GET", "/api/v1/tenders", nil)
+
+		assert.ErrorIs(t, err, ErrImpersonationEnded)
+	})
+
+	t.Run("database failure", func(t *testing.T) {
+		service, mockStore := setupUserAdminService(t)
+		mockStore.EXPECT().InsertImpersonationAudit(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("connection refused"))
+
+		_, err := service.AuditImpersonatedRequest(context.Background(), claims, "GET", "/api/v1/tenders", nil)
+
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrImpersonationEnded)
+	})
+
+	t.Run("regular token", func(t *testing.T) {
+		service, _ := setupUserAdminService(t)
+
+		_, err := service.AuditImpersonatedRequest(context.Background(), &JWTClaims{UserID: 7}, "GET", "/api/v1/tenders", nil)
+
+		assert.ErrorIs(t, err, ErrNotImpersonating)
+	})
+}
+
+func TestEndImpersonation(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+	mockStore.EXPECT().EndImpersonationSession(gomock.Any(), db.EndImpersonationSessionParams{ID: 11, AdminID: 1}).
+		Return(int64(1), nil)
+
+	require.NoError(t, service.EndImpersonation(context.Background(), &JWTClaims{UserID: 7, ActingAdminID: 1, ImpersonationID: 11}))
+	assert.ErrorIs(t, service.EndImpersonation(context.Background(), &JWTClaims{UserID: 7}), ErrNotImpersonating)
+}
@@ -0,0 +1,525 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+)
+
+var (
+	ErrOIDCDisabled     = errors.New("oidc login is disabled")
+	ErrOIDCState        = errors.New("invalid or expired oidc state")
+	ErrOIDCProvider     = errors.New("oidc provider request failed")
+	ErrOIDCToken        = errors.New("invalid oidc id token")
+	ErrOIDCAccessDenied = errors.New("oidc login denied")
+	// Провайдер не подтвердил email, а привязка и создание пользователя по
+	// неподтверждённому email запрещены (auth.oidc.allow_unverified_email)
+	ErrOIDCEmailNotVerified = errors.New("oidc email is not verified")
+)
+
+const (
+	// oidcStateTTL — сколько ждать возврата браузера от провайдера
+	oidcStateTTL = 10 * time.Minute
+	// oidcKeysRefreshInterval — не чаще этого JWKS перечитывается из-за
+	// неизвестного kid: иначе мусорные токены превращались бы в запросы к IdP
+	oidcKeysRefreshInterval = time.Minute
+	// oidcClockSkew — допуск расхождения часов с провайдером для exp/iat
+	oidcClockSkew = time.Minute
+	// oidcMaxResponseSize ограничивает ответы провайдера (discovery, token, JWKS)
+	oidcMaxResponseSize = 1 << 20
+)
+
+// oidcSigningMethods — алгоритмы подписи id_token. HS* не принимаются:
+// секрет клиента не должен служить ключом проверки.
+var oidcSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// OIDCClient выполняет вход через OpenID Connect: authorization code flow с
+// PKCE (S256) и проверкой подписи id_token по JWKS провайдера. Discovery и
+// ключи запрашиваются при первом входе и кэшируются; безопасен для
+// параллельного использования.
+type OIDCClient struct {
+	cfg    config.OIDCConfig
+	client *http.Client
+
+	// fetch объединяет параллельные загрузки discovery и JWKS в одну;
+	// запросы к провайдеру идут без mu, mu защищает только кэш
+	fetch         singleflight.Group
+	mu            sync.Mutex
+	discovery     *oidcDiscovery
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+// oidcDiscovery — нужные поля <issuer>/.well-known/openid-configuration.
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// OIDCIdentity — пользователь по проверенному id_token.
+type OIDCIdentity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Groups        []string
+}
+
+// OIDCState хранится в cookie браузера между /oidc/login и /oidc/callback:
+// state защищает callback от CSRF, nonce — от подмены id_token, verifier — PKCE.
+type OIDCState struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	// Относительный путь фронтенда, куда вернуть пользователя после входа
+	ReturnTo  string `json:"return_to,omitempty"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Encode сериализует состояние для cookie.
+func (s OIDCState) Encode() string {
+	raw, _ := json.Marshal(s)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeOIDCState разбирает cookie состояния; истекшее состояние — ErrOIDCState.
+func DecodeOIDCState(value string) (OIDCState, error) {
+	var state OIDCState
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || json.Unmarshal(raw, &state) != nil {
+		return OIDCState{}, ErrOIDCState
+	}
+	if state.State == "" || state.Nonce == "" || state.Verifier == "" || time.Now().Unix() > state.ExpiresAt {
+		return OIDCState{}, ErrOIDCState
+	}
+	return state, nil
+}
+
+// NewOIDCClient создает клиент по секции auth.oidc. Проверяет роли в
+// role_mapping и default_role: опечатка в роли иначе молча запрещала бы вход.
+func NewOIDCClient(cfg config.OIDCConfig) (*OIDCClient, error) {
+	for group, role := range cfg.RoleMapping {
+		if !IsValidRole(role) {
+			return nil, fmt.Errorf("oidc.role_mapping: invalid role %q for group %q: expected one of %s",
+				role, group, strings.Join(Roles(), ", "))
+		}
+	}
+	if cfg.DefaultRole != "" && !IsValidRole(cfg.DefaultRole) {
+		return nil, fmt.Errorf("oidc.default_role: invalid role %q: expected one of %s",
+			cfg.DefaultRole, strings.Join(Roles(), ", "))
+	}
+	return &OIDCClient{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+// AuthRequest готовит перенаправление на страницу входа провайдера: URL и
+// состояние, которое вызывающий сохраняет в cookie до callback.
+func (c *OIDCClient) AuthRequest(ctx context.Context, returnTo string) (string, OIDCState, error) {
+	discovery, err := c.getDiscovery(ctx)
+	if err != nil {
+		return "", OIDCState{}, err
+	}
+
+	state := OIDCState{
+		ReturnTo:  sanitizeReturnTo(returnTo),
+		ExpiresAt: time.Now().Add(oidcStateTTL).Unix(),
+	}
+	for _, field := range []*string{&state.State, &state.Nonce, &state.Verifier} {
+		if *field, err = randomURLToken(); err != nil {
+			return "", OIDCState{}, fmt.Errorf("failed to generate oidc state: %w", err)
+		}
+	}
+
+	challenge := sha256.Sum256([]byte(state.Verifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {c.cfg.ClientID},
+		"redirect_uri":          {c.cfg.RedirectURL},
+		"scope":                 {strings.Join(c.cfg.Scopes, " ")},
+		"state":                 {state.State},
+		"nonce":                 {state.Nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), state, nil
+}
+
+// Exchange обменивает код авторизации на токены и проверяет id_token:
+// подпись по JWKS, iss, aud, exp и nonce из состояния.
+func (c *OIDCClient) Exchange(ctx context.Context, code string, state OIDCState) (*OIDCIdentity, error) {
+	discovery, err := c.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.cfg.RedirectURL},
+		"code_verifier": {state.Verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// client_secret_basic: id и секрет кодируются по RFC 6749 §2.3.1
+	req.SetBasicAuth(url.QueryEscape(c.cfg.ClientID), url.QueryEscape(c.cfg.ClientSecret))
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := c.doJSON(req, &tokens); err != nil {
+		return nil, err
+	}
+	if tokens.IDToken == "" {
+		return nil, fmt.Errorf("%w: token response has no id_token", ErrOIDCProvider)
+	}
+	return c.verifyIDToken(ctx, tokens.IDToken, state.Nonce)
+}
+
+// verifyIDToken проверяет id_token и извлекает из него пользователя.
+func (c *OIDCClient) verifyIDToken(ctx context.Context, rawToken, nonce string) (*OIDCIdentity, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(rawToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return c.getKey(ctx, kid)
+	},
+		jwt.WithValidMethods(oidcSigningMethods),
+		jwt.WithIssuer(c.cfg.Issuer),
+		jwt.WithAudience(c.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(oidcClockSkew),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOIDCToken, err)
+	}
+
+	if got, _ := claims["nonce"].(string); subtle.ConstantTimeCompare([]byte(got), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrOIDCToken)
+	}
+	// При нескольких аудиториях токен должен быть выпущен именно нашему клиенту
+	if azp, ok := claims["azp"].(string); ok && azp != c.cfg.ClientID {
+		return nil, fmt.Errorf("%w: unexpected azp %q", ErrOIDCToken, azp)
+	}
+
+	identity := &OIDCIdentity{Issuer: c.cfg.Issuer}
+	identity.Subject, _ = claims["sub"].(string)
+	if identity.Subject == "" {
+		return nil, fmt.Errorf("%w: sub claim is missing", ErrOIDCToken)
+	}
+	email, _ := claims["email"].(string)
+	identity.Email = strings.ToLower(strings.TrimSpace(email))
+	// Keycloak выпускает email_verified как bool, некоторые провайдеры — строкой
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		identity.EmailVerified = verified
+	case string:
+		identity.EmailVerified = verified == "true"
+	}
+	identity.Groups = claimStrings(claims[c.cfg.GroupsClaim])
+	return identity, nil
+}
+
+// claimStrings читает claim групп: массив строк или одна строка (AD FS
+// выпускает одну группу строкой).
+func claimStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// MapRole выбирает роль по группам IdP (role_mapping): из сопоставленных —
+// с наибольшим числом прав. Пустая строка — ни одна группа не сопоставлена.
+func (c *OIDCClient) MapRole(groups []string) string {
+	role := ""
+	for _, group := range groups {
+		mapped, ok := c.cfg.RoleMapping[group]
+		if !ok {
+			continue
+		}
+		if role == "" || len(PermissionsForRole(mapped)) > len(PermissionsForRole(role)) {
+			role = mapped
+		}
+	}
+	return role
+}
+
+// getDiscovery загружает метаданные провайдера один раз; неудачная загрузка
+// повторяется при следующем входе.
+func (c *OIDCClient) getDiscovery(ctx context.Context) (*oidcDiscovery, error) {
+	c.mu.Lock()
+	discovery := c.discovery
+	c.mu.Unlock()
+	if discovery != nil {
+		return discovery, nil
+	}
+
+	v, err, _ := c.fetch.Do("discovery", func() (interface{}, error) {
+		discovery, err := c.fetchDiscovery(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		c.discovery = discovery
+		c.mu.Unlock()
+		return discovery, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*oidcDiscovery), nil
+}
+
+func (c *OIDCClient) fetchDiscovery(ctx context.Context) (*oidcDiscovery, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		strings.TrimRight(c.cfg.Issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	var discovery oidcDiscovery
+	if err := c.doJSON(req, &discovery); err != nil {
+		return nil, err
+	}
+	// OIDC Discovery §4.3: issuer в метаданных должен совпадать с настроенным
+	if discovery.Issuer != c.cfg.Issuer {
+		return nil, fmt.Errorf("%w: discovery issuer %q does not match %q", ErrOIDCProvider, discovery.Issuer, c.cfg.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: discovery document is incomplete", ErrOIDCProvider)
+	}
+	return &discovery, nil
+}
+
+// getKey возвращает ключ проверки подписи по kid. Неизвестный kid — повод
+// перечитать JWKS: провайдер мог ротировать ключи.
+func (c *OIDCClient) getKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	if key, ok, fresh := c.cachedKey(kid); ok {
+		return key, nil
+	} else if fresh {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	discovery, err := c.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, err, _ = c.fetch.Do("jwks", func() (interface{}, error) {
+		// Пока вызов ждал, JWKS мог перечитать другой вход
+		if _, _, fresh := c.cachedKey(kid); fresh {
+			return nil, nil
+		}
+		keys, err := c.fetchKeys(ctx, discovery.JWKSURI)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		c.keys = keys
+		c.keysFetchedAt = time.Now()
+		c.mu.Unlock()
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if key, ok, _ := c.cachedKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// cachedKey ищет ключ в кэше; fresh — JWKS загружен недавно и перечитывать
+// его ради неизвестного kid рано.
+func (c *OIDCClient) cachedKey(kid string) (key crypto.PublicKey, ok, fresh bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key, ok = c.lookupKey(kid)
+	fresh = c.keys != nil && time.Since(c.keysFetchedAt) < oidcKeysRefreshInterval
+	return key, ok, fresh
+}
+
+func (c *OIDCClient) fetchKeys(ctx context.Context, jwksURI string) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := c.doJSON(req, &set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		// Ключи неподдерживаемых типов (например, OKP) пропускаются
+		if key, err := jwk.publicKey(); err == nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+// lookupKey ищет ключ в кэше; токен без kid принимается, только если ключ один.
+// Вызывается под c.mu.
+func (c *OIDCClient) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(c.keys) == 1 {
+		for _, key := range c.keys {
+			return key, true
+		}
+	}
+	key, ok := c.keys[kid]
+	return key, ok
+}
+
+// doJSON выполняет запрос к провайдеру и разбирает JSON-ответ.
+func (c *OIDCClient) doJSON(req *http.Request, out interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOIDCProvider, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, oidcMaxResponseSize))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOIDCProvider, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		// Ответ об ошибке (invalid_grant и т.п.) не содержит секретов, но может быть длинным
+		if len(body) > 512 {
+			body = body[:512]
+		}
+		return fmt.Errorf("%w: %s returned status %d: %s", ErrOIDCProvider, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("%w: invalid response from %s: %v", ErrOIDCProvider, req.URL.Path, err)
+	}
+	return nil
+}
+
+// jsonWebKey — открытый ключ из JWKS (RFC 7517), поддерживаются RSA и EC.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		// ECDH проверяет, что точка лежит на кривой
+		if _, err := key.ECDH(); err != nil {
+			return nil, fmt.Errorf("invalid EC key: %w", err)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(raw) == 0 {
+		return nil, fmt.Errorf("invalid key parameter")
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
+
+// randomURLToken — 32 случайных байта в base64url (state, nonce, PKCE verifier).
+func randomURLToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// sanitizeReturnTo оставляет только относительный путь приложения: иначе
+// вход через SSO стал бы открытым редиректом на чужой сайт.
+func sanitizeReturnTo(returnTo string) string {
+	if !strings.HasPrefix(returnTo, "/") || strings.HasPrefix(returnTo, "//") || strings.ContainsAny(returnTo, "\\\r\n") {
+		return ""
+	}
+	return returnTo
+}
@@ -0,0 +1,250 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+)
+
+// EnableOIDC включает вход через SSO; вызывается при старте, если auth.oidc.enabled.
+func (s *Service) EnableOIDC(client *OIDCClient) {
+	s.oidc = client
+}
+
+// OIDCEnabled сообщает, включен ли вход через SSO.
+func (s *Service) OIDCEnabled() bool {
+	return s.oidc != nil
+}
+
+// OIDCAuthRequest возвращает адрес страницы входа провайдера и состояние для
+// cookie. returnTo — относительный путь фронтенда; остальное отбрасывается.
+func (s *Service) OIDCAuthRequest(ctx context.Context, returnTo string) (string, OIDCState, error) {
+	if s.oidc == nil {
+		return "", OIDCState{}, ErrOIDCDisabled
+	}
+	return s.oidc.AuthRequest(ctx, returnTo)
+}
+
+// OIDCPostLoginURL — куда перенаправить браузер после входа: auth.oidc.post_login_url
+// с путем из состояния.
+func (s *Service) OIDCPostLoginURL(state OIDCState) string {
+	base := s.config.Auth.OIDC.PostLoginURL
+	if state.ReturnTo == "" {
+		return base
+	}
+	return strings.TrimRight(base, "/") + state.ReturnTo
+}
+
+// oidcUser — пользователь, найденный или созданный при входе через SSO.
+type oidcUser struct {
+	ID             int64
+	Email          string
+	Role           string
+	IsActive       bool
+	OrganizationID int64
+	TokenVersion   int64
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// LoginOIDC завершает вход через SSO: обменивает код на id_token и создает
+// сессию, как Login.
+//
+// Пользователь ищется по (issuer, sub). При первом входе учётная запись
+// провайдера привязывается к пользователю с тем же подтверждённым email, а
+// если его нет и включен auto_provision — создается новый пользователь.
+// Роль из групп IdP (role_mapping) синхронизируется при каждом входе; если
+// ни одна группа не сопоставлена, существующий пользователь сохраняет роль,
+// а новый получает default_role. Без сопоставленной группы и default_role,
+// а также для деактивированного пользователя — ErrOIDCAccessDenied.
+func (s *Service) LoginOIDC(ctx context.Context, code string, state OIDCState, ipAddress *net.IP, userAgent string) (*LoginResult, error) {
+	if s.oidc == nil {
+		return nil, ErrOIDCDisabled
+	}
+
+	identity, err := s.oidc.Exchange(ctx, code, state)
+	if err != nil {
+		return nil, err
+	}
+
+	mappedRole := s.oidc.MapRole(identity.Groups)
+	if mappedRole == "" && s.config.Auth.OIDC.DefaultRole == "" {
+		s.logger.Warnf("oidc login denied (sub_hash: %s): no role mapped from groups", hashIdentifier(identity.Subject))
+		return nil, fmt.Errorf("%w: no role mapped from groups", ErrOIDCAccessDenied)
+	}
+
+	refreshToken, refreshHash, err := generateRefreshToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	// Как в ChangePassword: отзыв на конец предыдущей секунды, чтобы не
+	// отклонить access-токен, выпущенный этим входом
+	revokedAt := time.Now().Add(-time.Second)
+
+	var (
+		user        oidcUser
+		roleChanged bool
+	)
+	err = s.store.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		user, err = s.findOrProvisionOIDCUser(ctx, q, identity, mappedRole)
+		if err != nil {
+			return err
+		}
+		if !user.IsActive {
+			return fmt.Errorf("%w: user is inactive", ErrOIDCAccessDenied)
+		}
+
+		if mappedRole != "" && user.Role != mappedRole {
+			version, err := q.SyncUserSSORole(ctx, db.SyncUserSSORoleParams{
+				Role:            mappedRole,
+				TokensRevokedAt: revokedAt,
+				ID:              user.ID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to sync role: %w", err)
+			}
+			user.Role = mappedRole
+			user.TokenVersion = version
+			roleChanged = true
+		}
+
+		if _, err := q.CreateUserSession(ctx, s.newSessionParams(user.ID, refreshHash, ipAddress, userAgent)); err != nil {
+			return fmt.Errorf("failed to create session: %w", err)
+		}
+		if err := q.UpdateUserLastLogin(ctx, user.ID); err != nil {
+			return fmt.Errorf("failed to update last_login_at: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrOIDCAccessDenied) || errors.Is(err, ErrOIDCEmailNotVerified) {
+			s.logger.Warnf("oidc login denied (sub_hash: %s): %v", hashIdentifier(identity.Subject), err)
+		}
+		return nil, err
+	}
+
+	if roleChanged {
+		s.denylist.Revoke(user.ID, revokedAt)
+		s.versions.forget(user.ID)
+		s.logger.Infof("role of user (id_hash: %s) synced from oidc groups: %s", hashUserID(user.ID), user.Role)
+	}
+	s.logger.Infof("successful oidc login for user (id_hash: %s)", hashUserID(user.ID))
+
+	accessToken, err := s.generateAccessToken(user.ID, user.Role, user.OrganizationID, user.TokenVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	return &LoginResult{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		User: db.User{
+			ID:             user.ID,
+			Email:          user.Email,
+			Role:           user.Role,
+			OrganizationID: user.OrganizationID,
+			CreatedAt:      user.CreatedAt,
+			UpdatedAt:      user.UpdatedAt,
+		},
+	}, nil
+}
+
+// findOrProvisionOIDCUser находит пользователя по учётной записи провайдера,
+// при первом входе привязывает ее по email или создает пользователя.
+func (s *Service) findOrProvisionOIDCUser(ctx context.Context, q *db.Queries, identity *OIDCIdentity, mappedRole string) (oidcUser, error) {
+	linked, err := q.GetUserAuthByOIDCSubject(ctx, db.GetUserAuthByOIDCSubjectParams{
+		OidcIssuer:  identity.Issuer,
+		OidcSubject: identity.Subject,
+	})
+	if err == nil {
+		return oidcUser{
+			ID:             linked.ID,
+			Email:          linked.Email,
+			Role:           linked.Role,
+			IsActive:       linked.IsActive,
+			OrganizationID: linked.OrganizationID,
+			TokenVersion:   linked.TokenVersion,
+			CreatedAt:      linked.CreatedAt,
+			UpdatedAt:      linked.UpdatedAt,
+		}, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return oidcUser{}, fmt.Errorf("failed to get user by oidc subject: %w", err)
+	}
+
+	// Первый вход: привязка и создание доверяют только подтверждённому email,
+	// иначе учётная запись IdP с чужим адресом получила бы чужого пользователя
+	if !identity.EmailVerified && !s.config.Auth.OIDC.AllowUnverifiedEmail {
+		return oidcUser{}, ErrOIDCEmailNotVerified
+	}
+	email, err := normalizeEmail(identity.Email)
+	if err != nil {
+		return oidcUser{}, fmt.Errorf("%w: id_token has no valid email", ErrOIDCAccessDenied)
+	}
+
+	existing, err := q.GetUserAuthByEmailForUpdate(ctx, email)
+	if err == nil {
+		if existing.OidcSubject.Valid {
+			return oidcUser{}, fmt.Errorf("%w: user is linked to another oidc account", ErrOIDCAccessDenied)
+		}
+		if err := q.LinkUserOIDCIdentity(ctx, db.LinkUserOIDCIdentityParams{
+			OidcIssuer:  identity.Issuer,
+			OidcSubject: identity.Subject,
+			ID:          existing.ID,
+		}); err != nil {
+			return oidcUser{}, fmt.Errorf("failed to link oidc identity: %w", err)
+		}
+		s.logger.Infof("oidc identity linked to user (id_hash: %s)", hashUserID(existing.ID))
+		return oidcUser{
+			ID:             existing.ID,
+			Email:          existing.Email,
+			Role:           existing.Role,
+			IsActive:       existing.IsActive,
+			OrganizationID: existing.OrganizationID,
+			TokenVersion:   existing.TokenVersion,
+			CreatedAt:      existing.CreatedAt,
+			UpdatedAt:      existing.UpdatedAt,
+		}, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return oidcUser{}, fmt.Errorf("failed to get user by email: %w", err)
+	}
+
+	cfg := s.config.Auth.OIDC
+	if !cfg.AutoProvision {
+		return oidcUser{}, fmt.Errorf("%w: user does not exist and auto_provision is disabled", ErrOIDCAccessDenied)
+	}
+	role := mappedRole
+	if role == "" {
+		role = cfg.DefaultRole
+	}
+	created, err := q.CreateOIDCUser(ctx, db.CreateOIDCUserParams{
+		Email:          email,
+		Role:           role,
+		OrganizationID: sql.NullInt64{Int64: cfg.OrganizationID, Valid: cfg.OrganizationID > 0},
+		OidcIssuer:     identity.Issuer,
+		OidcSubject:    identity.Subject,
+	})
+	if err != nil {
+		return oidcUser{}, fmt.Errorf("failed to create oidc user: %w", err)
+	}
+	s.logger.Infof("user (id_hash: %s) provisioned from oidc with role %s", hashUserID(created.ID), created.Role)
+	return oidcUser{
+		ID:             created.ID,
+		Email:          created.Email,
+		Role:           created.Role,
+		IsActive:       created.IsActive,
+		OrganizationID: created.OrganizationID,
+		TokenVersion:   created.TokenVersion,
+		CreatedAt:      created.CreatedAt,
+		UpdatedAt:      created.UpdatedAt,
+	}, nil
+}
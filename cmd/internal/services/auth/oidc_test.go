@@ -0,0 +1,570 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR OIDC LOGIN (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Forged logins — an id_token not signed by the IdP, issued to another client,
+   expired or replayed with another nonce must be rejected
+2. Account takeover — an IdP account is linked to an existing user only by a
+   verified email, and never to a user already linked to another IdP account
+3. Stale permissions — the role follows IdP groups on every login, and tokens
+   issued with the old role stop working
+4. Open redirect — return_to only accepts paths of the application
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: OIDCClient against a fake IdP
+- GIVEN a configured client
+  WHEN AuthRequest and Exchange run
+  THEN the URL carries state, nonce and an S256 PKCE challenge, the token request
+  sends the verifier with client_secret_basic, and the identity is extracted
+- GIVEN a bad id_token (nonce, audience, issuer, expiry, foreign key, HS256)
+  THEN ErrOIDCToken
+- GIVEN a slow JWKS refresh for an unknown kid
+  THEN cached keys are still served meanwhile, and concurrent refreshes
+  share one JWKS request
+
+SCENARIO 2: Role mapping and configuration
+- GIVEN several mapped groups → the role with most permissions wins; none → ""
+- GIVEN an unknown role in role_mapping → NewOIDCClient fails
+
+SCENARIO 3: State cookie
+- GIVEN an encoded state → decodes back; expired or garbage → ErrOIDCState
+- GIVEN return_to → only relative application paths are kept
+
+SCENARIO 4: LoginOIDC
+- GIVEN a linked user whose groups now map to another role
+  THEN the role is synced, old access tokens revoked, a session created
+- GIVEN the first login with a verified email of an existing user → identity linked
+- GIVEN the first login of an unknown user with auto_provision → user created
+  with default_role
+- GIVEN an unverified email on first login → ErrOIDCEmailNotVerified
+- GIVEN no mapped group and no default_role → ErrOIDCAccessDenied without a transaction
+*/
+
+const (
+	testOIDCClientID     = "tenders"
+	testOIDCClientSecret = "client-secret"
+)
+
+// fakeIdP — провайдер OIDC на httptest: discovery, token и JWKS.
+type fakeIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	// claims id_token, который выдаст token endpoint
+	claims jwt.MapClaims
+	// signKey подменяет ключ подписи (чужой ключ); nil — key
+	signKey *rsa.PrivateKey
+	// tokenForm — форма последнего запроса к token endpoint
+	tokenForm url.Values
+	// jwksHits — число запросов JWKS; jwksGate, если задан, задерживает ответ JWKS
+	jwksHits atomic.Int32
+	jwksGate chan struct{}
+}
+
+func newFakeIdP(t *testing.T) *fakeIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	idp := &fakeIdP{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, secret, ok := r.BasicAuth()
+		if !ok || clientID != testOIDCClientID || secret != testOIDCClientSecret {
+			http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+			return
+		}
+		require.NoError(t, r.ParseForm())
+		idp.tokenForm = r.PostForm
+		if r.PostForm.Get("code") != "good-code" {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": idp.sign(t, jwt.SigningMethodRS256)})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		idp.jwksHits.Add(1)
+		if idp.jwksGate != nil {
+			<-idp.jwksGate
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "k1",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+func (idp *fakeIdP) sign(t *testing.T, method jwt.SigningMethod) string {
+	token := jwt.NewWithClaims(method, idp.claims)
+	token.Header["kid"] = "k1"
+	if method == jwt.SigningMethodHS256 {
+		signed, err := token.SignedString([]byte(testOIDCClientSecret))
+		require.NoError(t, err)
+		return signed
+	}
+	key := idp.key
+	if idp.signKey != nil {
+		key = idp.signKey
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+// validClaims — claims корректного id_token для nonce из состояния.
+func (idp *fakeIdP) validClaims(nonce string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            idp.server.URL,
+		"aud":            testOIDCClientID,
+		"sub":            "sub-1",
+		"exp":            time.Now().Add(5 * time.Minute).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          nonce,
+		"email":          "Ivan@Example.com",
+		"email_verified": true,
+		"groups":         []string{"tender-editors", "unrelated"},
+	}
+}
+
+func (idp *fakeIdP) config() config.OIDCConfig {
+	return config.OIDCConfig{
+		Enabled:       true,
+		Issuer:        idp.server.URL,
+		ClientID:      testOIDCClientID,
+		ClientSecret:  testOIDCClientSecret,
+		RedirectURL:   "https://tenders.example.com/api/v1/auth/oidc/callback",
+		Scopes:        []string{"openid", "email", "profile"},
+		GroupsClaim:   "groups",
+		RoleMapping:   map[string]string{"tender-editors": RoleEditor, "tender-admins": RoleAdmin},
+		AutoProvision: true,
+		PostLoginURL:  "/",
+		Timeout:       5 * time.Second,
+	}
+}
+
+func TestOIDCClient_AuthRequestAndExchange(t *testing.T) {
+	idp := newFakeIdP(t)
+	client, err := NewOIDCClient(idp.config())
+	require.NoError(t, err)
+
+	// WHEN: the browser is sent to the IdP
+	authURL, state, err := client.AuthRequest(context.Background(), "/tenders/5")
+	require.NoError(t, err)
+
+	// THEN: state, nonce and the S256 challenge of the verifier are in the URL
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, idp.server.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	query := u.Query()
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, testOIDCClientID, query.Get("client_id"))
+	assert.Equal(t, "openid email profile", query.Get("scope"))
+	assert.Equal(t, state.State, query.Get("state"))
+	assert.Equal(t, state.Nonce, query.Get("nonce"))
+	challenge := sha256.Sum256([]byte(state.Verifier))
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(challenge[:]), query.Get("code_challenge"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, "/tenders/5", state.ReturnTo)
+
+	// WHEN: the code is exchanged
+	idp.claims = idp.validClaims(state.Nonce)
+	identity, err := client.Exchange(context.Background(), "good-code", state)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, state.Verifier, idp.tokenForm.Get("code_verifier"))
+	assert.Equal(t, "authorization_code", idp.tokenForm.Get("grant_type"))
+	assert.Equal(t, idp.server.URL, identity.Issuer)
+	assert.Equal(t, "sub-1", identity.Subject)
+	assert.Equal(t, "ivan@example.com", identity.Email)
+	assert.True(t, identity.EmailVerified)
+	assert.Equal(t, []string{"tender-editors", "unrelated"}, identity.Groups)
+}
+
+func TestOIDCClient_Exchange_ProviderError(t *testing.T) {
+	idp := newFakeIdP(t)
+	client, err := NewOIDCClient(idp.config())
+	require.NoError(t, err)
+
+	_, err = client.Exchange(context.Background(), "reused-code", OIDCState{Nonce: "n", Verifier: "v"})
+
+	assert.ErrorIs(t, err, ErrOIDCProvider)
+}
+
+func TestOIDCClient_Exchange_RejectsInvalidIDToken(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	cases := map[string]func(idp *fakeIdP){
+		"nonce mismatch": func(idp *fakeIdP) { idp.claims["nonce"] = "other" },
+		"wrong audience": func(idp *fakeIdP) { idp.claims["aud"] = "another-client" },
+		"wrong issuer":   func(idp *fakeIdP) { idp.claims["iss"] = "https://evil.example.com" },
+		"expired":        func(idp *fakeIdP) { idp.claims["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"foreign azp":    func(idp *fakeIdP) { idp.claims["azp"] = "another-client" },
+		"no subject":     func(idp *fakeIdP) { delete(idp.claims, "sub") },
+		"foreign key":    func(idp *fakeIdP) { idp.signKey = otherKey },
+	}
+	for name, mutate := range cases {
+		t.Run(name, func(t *testing.T) {
+			idp := newFakeIdP(t)
+			client, err := NewOIDCClient(idp.config())
+			require.NoError(t, err)
+			state := OIDCState{Nonce: "nonce-1", Verifier: "verifier"}
+			idp.claims = idp.validClaims(state.Nonce)
+			mutate(idp)
+
+			_, err = client.Exchange(context.Background(), "good-code", state)
+
+			assert.ErrorIs(t, err, ErrOIDCToken)
+		})
+	}
+
+	t.Run("HS256 signed with the client secret", func(t *testing.T) {
+		idp := newFakeIdP(t)
+		client, err := NewOIDCClient(idp.config())
+		require.NoError(t, err)
+		idp.claims = idp.validClaims("nonce-1")
+
+		_, err = client.verifyIDToken(context.Background(), idp.sign(t, jwt.SigningMethodHS256), "nonce-1")
+
+		assert.ErrorIs(t, err, ErrOIDCToken)
+	})
+}
+
+func TestOIDCClient_GetKey_RefreshDoesNotBlockCachedKeys(t *testing.T) {
+	idp := newFakeIdP(t)
+	client, err := NewOIDCClient(idp.config())
+	require.NoError(t, err)
+
+	// GIVEN: k1 is cached and the JWKS is old enough to be refreshed
+	_, err = client.getKey(context.Background(), "k1")
+	require.NoError(t, err)
+	client.mu.Lock()
+	client.keysFetchedAt = time.Now().Add(-2 * oidcKeysRefreshInterval)
+	client.mu.Unlock()
+	gate := make(chan struct{})
+	release := sync.OnceFunc(func() { close(gate) })
+	t.Cleanup(release) // Иначе при падении теста сервер ждёт зависший запрос JWKS
+	idp.jwksGate = gate
+
+	// WHEN: several logins with an unknown kid wait for the provider
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.getKey(context.Background(), "k2")
+			assert.Error(t, err)
+		}()
+	}
+	require.Eventually(t, func() bool { return idp.jwksHits.Load() == 2 }, time.Second, 10*time.Millisecond)
+
+	// THEN: the cached key is served without waiting for the refresh
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := client.getKey(context.Background(), "k1")
+		assert.NoError(t, err)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("cached key lookup blocked by the JWKS refresh")
+	}
+
+	// AND: the waiting logins shared one JWKS request
+	release()
+	wg.Wait()
+	assert.Equal(t, int32(2), idp.jwksHits.Load())
+}
+
+func TestOIDCClient_MapRole(t *testing.T) {
+	client, err := NewOIDCClient(config.OIDCConfig{
+		RoleMapping: map[string]string{"viewers": RoleViewer, "admins": RoleAdmin, "editors": RoleEditor},
+		DefaultRole: RoleViewer,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, RoleAdmin, client.MapRole([]string{"viewers", "admins", "editors"}))
+	assert.Equal(t, RoleEditor, client.MapRole([]string{"editors", "viewers"}))
+	// default_role применяет LoginOIDC, а не MapRole
+	assert.Equal(t, "", client.MapRole([]string{"unrelated"}))
+	assert.Equal(t, "", client.MapRole(nil))
+}
+
+func TestNewOIDCClient_InvalidRole(t *testing.T) {
+	_, err := NewOIDCClient(config.OIDCConfig{RoleMapping: map[string]string{"admins": "superuser"}})
+	assert.ErrorContains(t, err, "superuser")
+
+	_, err = NewOIDCClient(config.OIDCConfig{DefaultRole: "guest"})
+	assert.ErrorContains(t, err, "guest")
+}
+
+func TestOIDCState_EncodeDecode(t *testing.T) {
+	state := OIDCState{State: "s", Nonce: "n", Verifier: "v", ReturnTo: "/tenders", ExpiresAt: time.Now().Add(time.Minute).Unix()}
+
+	decoded, err := DecodeOIDCState(state.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, state, decoded)
+
+	state.ExpiresAt = time.Now().Add(-time.Second).Unix()
+	_, err = DecodeOIDCState(state.Encode())
+	assert.ErrorIs(t, err, ErrOIDCState)
+
+	_, err = DecodeOIDCState("not-a-state")
+	assert.ErrorIs(t, err, ErrOIDCState)
+}
+
+func TestSanitizeReturnTo(t *testing.T) {
+	cases := map[string]string{
+		"/tenders/5?tab=lots":    "/tenders/5?tab=lots",
+		"":                       "",
+		"https://evil.example":   "",
+		"//evil.example/path":    "",
+		"/\\evil.example":        "",
+		"tenders":                "",
+		"/ok\r\nSet-Cookie: x=1": "",
+	}
+	for input, want := range cases {
+		assert.Equal(t, want, sanitizeReturnTo(input), "input %q", input)
+	}
+}
+
+// =============================================================================
+// LoginOIDC
+// =============================================================================
+
+var (
+	oidcUserColumns        = []string{"id", "email", "role", "is_active", "organization_id", "created_at", "updated_at", "token_version"}
+	oidcUserByEmailColumns = []string{"id", "email", "role", "is_active", "organization_id", "oidc_subject", "created_at", "updated_at", "token_version"}
+	sessionColumns         = []string{"id", "user_id", "refresh_token_hash", "created_at", "expires_at", "revoked_at"}
+)
+
+// setupOIDCService — сервис с mock store и включенным OIDC на fake IdP.
+// Возвращает состояние, для nonce которого IdP выпустит корректный id_token.
+func setupOIDCService(t *testing.T, modify func(cfg *config.OIDCConfig)) (*Service, *db.MockStore, *fakeIdP, OIDCState) {
+	t.Helper()
+	service, mockStore := setupUserAdminService(t)
+	idp := newFakeIdP(t)
+
+	cfg := idp.config()
+	if modify != nil {
+		modify(&cfg)
+	}
+	service.config.Auth.OIDC = cfg
+	client, err := NewOIDCClient(cfg)
+	require.NoError(t, err)
+	service.EnableOIDC(client)
+
+	state := OIDCState{State: "state-1", Nonce: "nonce-1", Verifier: "verifier-1"}
+	idp.claims = idp.validClaims(state.Nonce)
+	return service, mockStore, idp, state
+}
+
+// expectSessionCreated — создание сессии и обновление last_login_at.
+func expectSessionCreated(mock sqlmock.Sqlmock, userID int64) {
+	mock.ExpectQuery("INSERT INTO user_sessions").
+		WithArgs(userID, hashedTokenArg{}, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(sessionColumns).
+			AddRow(int64(1), userID, "", time.Now(), time.Now().Add(time.Hour), nil))
+	mock.ExpectExec("UPDATE users SET last_login_at").
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func TestLoginOIDC_LinkedUser_SyncsRoleFromGroups(t *testing.T) {
+	service, mockStore, idp, state := setupOIDCService(t, nil)
+	now := time.Now()
+
+	// GIVEN: user 7 holds a token issued while being a viewer
+	oldToken := tokenIssuedAt(t, service, 7, now.Add(-time.Minute))
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT .+ FROM users WHERE oidc_issuer").
+				WithArgs(idp.server.URL, "sub-1").
+				WillReturnRows(sqlmock.NewRows(oidcUserColumns).
+					AddRow(int64(7), "ivan@example.com", RoleViewer, true, int64(1), now, now, int64(4)))
+			mock.ExpectQuery("UPDATE users SET role").
+				WithArgs(RoleEditor, sqlmock.AnyArg(), int64(7)).
+				WillReturnRows(sqlmock.NewRows([]string{"token_version"}).AddRow(int64(5)))
+			expectSessionCreated(mock, 7)
+		}),
+	)
+
+	// WHEN
+	result, err := service.LoginOIDC(context.Background(), "good-code", state, nil, "test-agent")
+
+	// THEN: the new token carries the synced role and version, the old one is revoked
+	require.NoError(t, err)
+	assert.Equal(t, RoleEditor, result.User.Role)
+	assert.NoError(t, validateRefreshTokenFormat(result.RefreshToken))
+	claims, err := service.ValidateAccessToken(result.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, RoleEditor, claims.Role)
+	assert.Equal(t, int64(5), claims.TokenVersion)
+	_, err = service.ValidateAccessToken(oldToken)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+}
+
+func TestLoginOIDC_FirstLogin_LinksByVerifiedEmail(t *testing.T) {
+	service, mockStore, idp, state := setupOIDCService(t, nil)
+	now := time.Now()
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT .+ FROM users WHERE oidc_issuer").
+				WillReturnRows(sqlmock.NewRows(oidcUserColumns))
+			mock.ExpectQuery("SELECT .+ FROM users WHERE email").
+				WithArgs("ivan@example.com").
+				WillReturnRows(sqlmock.NewRows(oidcUserByEmailColumns).
+					AddRow(int64(9), "ivan@example.com", RoleEditor, true, int64(2), nil, now, now, int64(0)))
+			mock.ExpectExec("UPDATE users SET oidc_issuer").
+				WithArgs(idp.server.URL, "sub-1", int64(9)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			expectSessionCreated(mock, 9)
+		}),
+	)
+
+	result, err := service.LoginOIDC(context.Background(), "good-code", state, nil, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(9), result.User.ID)
+	assert.Equal(t, int64(2), result.User.OrganizationID)
+	assert.Equal(t, 0, service.denylist.Len(), "role unchanged — nothing to revoke")
+}
+
+func TestLoginOIDC_FirstLogin_EmailLinkedToAnotherAccount(t *testing.T) {
+	service, mockStore, _, state := setupOIDCService(t, nil)
+	now := time.Now()
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT .+ FROM users WHERE oidc_issuer").
+				WillReturnRows(sqlmock.NewRows(oidcUserColumns))
+			mock.ExpectQuery("SELECT .+ FROM users WHERE email").
+				WillReturnRows(sqlmock.NewRows(oidcUserByEmailColumns).
+					AddRow(int64(9), "ivan@example.com", RoleEditor, true, int64(2), "other-sub", now, now, int64(0)))
+		}),
+	)
+
+	result, err := service.LoginOIDC(context.Background(), "good-code", state, nil, "")
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrOIDCAccessDenied)
+}
+
+func TestLoginOIDC_FirstLogin_ProvisionsUserWithDefaultRole(t *testing.T) {
+	service, mockStore, idp, state := setupOIDCService(t, func(cfg *config.OIDCConfig) {
+		cfg.DefaultRole = RoleViewer
+		cfg.OrganizationID = 3
+	})
+	idp.claims["groups"] = []string{"unrelated"}
+	now := time.Now()
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT .+ FROM users WHERE oidc_issuer").
+				WillReturnRows(sqlmock.NewRows(oidcUserColumns))
+			mock.ExpectQuery("SELECT .+ FROM users WHERE email").
+				WillReturnRows(sqlmock.NewRows(oidcUserByEmailColumns))
+			mock.ExpectQuery("INSERT INTO users").
+				WithArgs("ivan@example.com", RoleViewer, int64(3), idp.server.URL, "sub-1").
+				WillReturnRows(sqlmock.NewRows(oidcUserColumns).
+					AddRow(int64(12), "ivan@example.com", RoleViewer, true, int64(3), now, now, int64(0)))
+			expectSessionCreated(mock, 12)
+		}),
+	)
+
+	result, err := service.LoginOIDC(context.Background(), "good-code", state, nil, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), result.User.ID)
+	assert.Equal(t, RoleViewer, result.User.Role)
+}
+
+func TestLoginOIDC_FirstLogin_UnverifiedEmail(t *testing.T) {
+	service, mockStore, idp, state := setupOIDCService(t, nil)
+	idp.claims["email_verified"] = false
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT .+ FROM users WHERE oidc_issuer").
+				WillReturnRows(sqlmock.NewRows(oidcUserColumns))
+		}),
+	)
+
+	_, err := service.LoginOIDC(context.Background(), "good-code", state, nil, "")
+
+	assert.ErrorIs(t, err, ErrOIDCEmailNotVerified)
+}
+
+func TestLoginOIDC_NoMappedRole_DeniedWithoutTransaction(t *testing.T) {
+	service, _, idp, state := setupOIDCService(t, nil)
+	idp.claims["groups"] = []string{"unrelated"}
+
+	// No ExecTx expectation: gomock fails the test if the store is touched
+	_, err := service.LoginOIDC(context.Background(), "good-code", state, nil, "")
+
+	assert.ErrorIs(t, err, ErrOIDCAccessDenied)
+}
+
+func TestLoginOIDC_Disabled(t *testing.T) {
+	service, _ := setupUserAdminService(t)
+
+	assert.False(t, service.OIDCEnabled())
+	_, err := service.LoginOIDC(context.Background(), "good-code", OIDCState{}, nil, "")
+	assert.ErrorIs(t, err, ErrOIDCDisabled)
+}
+
+func TestOIDCPostLoginURL(t *testing.T) {
+	service, _ := setupUserAdminService(t)
+	service.config.Auth.OIDC.PostLoginURL = "https://app.example.com/"
+
+	assert.Equal(t, "https://app.example.com/", service.OIDCPostLoginURL(OIDCState{}))
+	assert.Equal(t, "https://app.example.com/tenders/5", service.OIDCPostLoginURL(OIDCState{ReturnTo: "/tenders/5"}))
+}
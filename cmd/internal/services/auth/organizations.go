@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+// maxOrganizationNameLength — длина колонки organizations.name.
+const maxOrganizationNameLength = 255
+
+// ListOrganizations возвращает все организации по алфавиту.
+func (s *Service) ListOrganizations(ctx context.Context) ([]api_models.OrganizationResponse, error) {
+	organizations, err := s.store.ListOrganizations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+
+	response := make([]api_models.OrganizationResponse, 0, len(organizations))
+	for _, o := range organizations {
+		response = append(response, organizationResponse(o))
+	}
+	return response, nil
+}
+
+// CreateOrganization создает организацию. Пользователи переводятся в нее
+// через UpdateUser, тендеры попадают в нее при импорте.
+func (s *Service) CreateOrganization(ctx context.Context, actorID int64, req api_models.CreateOrganizationRequest) (*api_models.OrganizationResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, apierrors.NewValidationError("auth.organization_name_empty")
+	}
+	if utf8.RuneCountInString(name) > maxOrganizationNameLength {
+		return nil, apierrors.NewValidationError("auth.organization_name_too_long", maxOrganizationNameLength)
+	}
+
+	organization, err := s.store.CreateOrganization(ctx, name)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, apierrors.NewConflictError("auth.organization_exists", nil)
+		}
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	s.logger.Infof("organization %d created by admin (id_hash: %s)", organization.ID, hashUserID(actorID))
+
+	response := organizationResponse(organization)
+	return &response, nil
+}
+
+func organizationResponse(o db.Organization) api_models.OrganizationResponse {
+	return api_models.OrganizationResponse{
+		ID:        o.ID,
+		Name:      o.Name,
+		IsDefault: o.IsDefault,
+		CreatedAt: o.CreatedAt,
+		UpdatedAt: o.UpdatedAt,
+	}
+}
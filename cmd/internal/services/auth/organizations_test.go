@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR ORGANIZATIONS (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Duplicate organizations — two organizations with the same name would make
+   user assignment ambiguous in the admin UI
+2. Garbage names — empty or oversized names must be rejected before the DB
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: CreateOrganization
+- GIVEN a name with surrounding spaces → organization created with a trimmed name
+- GIVEN a name already taken (23505) → ConflictError
+- GIVEN an empty or too long name → ValidationError, no DB call
+
+SCENARIO 2: ListOrganizations
+- GIVEN organizations in the DB → all returned, default flag preserved
+*/
+
+func TestCreateOrganization_Success(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+	now := time.Now()
+
+	mockStore.EXPECT().CreateOrganization(gomock.Any(), "ООО Альфа").
+		Return(db.Organization{ID: 2, Name: "ООО Альфа", CreatedAt: now, UpdatedAt: now}, nil)
+
+	resp, err := service.CreateOrganization(context.Background(), 1, api_models.CreateOrganizationRequest{Name: "  ООО Альфа "})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), resp.ID)
+	assert.Equal(t, "ООО Альфа", resp.Name)
+	assert.False(t, resp.IsDefault)
+}
+
+func TestCreateOrganization_DuplicateName_ReturnsConflict(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+
+	mockStore.EXPECT().CreateOrganization(gomock.Any(), "ООО Альфа").
+		Return(db.Organization{}, &pq.Error{Code: "23505", Constraint: "uq_organizations_name"})
+
+	_, err := service.CreateOrganization(context.Background(), 1, api_models.CreateOrganizationRequest{Name: "ООО Альфа"})
+
+	var conflictErr *apierrors.ConflictError
+	assert.ErrorAs(t, err, &conflictErr)
+}
+
+func TestCreateOrganization_ValidationErrors(t *testing.T) {
+	cases := map[string]string{
+		"empty":    "   ",
+		"too long": strings.Repeat("я", maxOrganizationNameLength+1),
+	}
+	for name, orgName := range cases {
+		t.Run(name, func(t *testing.T) {
+			service, _ := setupUserAdminService(t)
+
+			_, err := service.CreateOrganization(context.Background(), 1, api_models.CreateOrganizationRequest{Name: orgName})
+
+			var validationErr *apierrors.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+		})
+	}
+}
+
+func TestListOrganizations(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+
+	mockStore.EXPECT().ListOrganizations(gomock.Any()).Return([]db.Organization{
+		{ID: 1, Name: "Организация по умолчанию", IsDefault: true},
+		{ID: 2, Name: "ООО Альфа"},
+	}, nil)
+
+	resp, err := service.ListOrganizations(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resp, 2)
+	assert.True(t, resp[0].IsDefault)
+	assert.Equal(t, "ООО Альфа", resp[1].Name)
+}
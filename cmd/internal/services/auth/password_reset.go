@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/notifications"
+)
+
+const (
+	// minPasswordLength совпадает с требованием cmd/createadmin.
+	minPasswordLength = 8
+	// maxPasswordBytes — bcrypt отклоняет пароли длиннее 72 байт.
+	maxPasswordBytes = 72
+)
+
+// validatePassword проверяет длину нового пароля.
+func validatePassword(password string) error {
+	if utf8.RuneCountInString(password) < minPasswordLength {
+		return apierrors.NewValidationError("auth.password_too_short", minPasswordLength)
+	}
+	if len(password) > maxPasswordBytes {
+		return apierrors.NewValidationError("auth.password_too_long", maxPasswordBytes)
+	}
+	return nil
+}
+
+// IssuePasswordReset выдает одноразовый токен сброса пароля для пользователя.
+// Предыдущие неиспользованные токены аннулируются. В БД сохраняется только
+// SHA-256 хеш, сам токен возвращается администратору один раз и отправляется
+// пользователю ссылкой на почту.
+func (s *Service) IssuePasswordReset(ctx context.Context, actorID, userID int64) (*api_models.PasswordResetTokenResponse, error) {
+	if userID <= 0 {
+		return nil, apierrors.NewValidationError("request.id_positive_got", userID)
+	}
+
+	// Формат тот же, что у refresh-токена: 32 случайных байта в hex
+	token, tokenHash, err := generateRefreshToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate reset token: %w", err)
+	}
+
+	var (
+		created db.CreatePasswordResetTokenRow
+		email   string
+	)
+	err = s.store.ExecTx(ctx, func(q *db.Queries) error {
+		user, err := q.GetUserByID(ctx, userID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return apierrors.NewNotFoundError("auth.user_id_not_found", userID)
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		email = user.Email
+
+		if _, err := q.InvalidatePasswordResetTokensByUserID(ctx, userID); err != nil {
+			return fmt.Errorf("failed to invalidate previous reset tokens: %w", err)
+		}
+
+		created, err = q.CreatePasswordResetToken(ctx, db.CreatePasswordResetTokenParams{
+			UserID:    userID,
+			TokenHash: tokenHash,
+			CreatedBy: sql.NullInt64{Int64: actorID, Valid: true},
+			ExpiresAt: time.Now().Add(s.config.Auth.PasswordResetTTL),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create reset token: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("password reset token for user (id_hash: %s) issued by admin (id_hash: %s), expires at %s",
+		hashUserID(userID), hashUserID(actorID), created.ExpiresAt.Format(time.RFC3339))
+	if s.notifier != nil {
+		s.notifier.NotifyPasswordReset(notifications.PasswordReset{Email: email, Token: token, ExpiresAt: created.ExpiresAt})
+	}
+
+	return &api_models.PasswordResetTokenResponse{
+		UserID:    userID,
+		Token:     token,
+		ExpiresAt: created.ExpiresAt,
+	}, nil
+}
+
+// ResetPassword устанавливает новый пароль по одноразовому токену. Токен
+// гасится, все сессии пользователя завершаются, а выданные ранее
+// access-токены отклоняются — в одной транзакции с заменой пароля.
+// Неизвестный, использованный и просроченный токены неразличимы: ErrInvalidToken.
+func (s *Service) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if err := validateRefreshTokenFormat(token); err != nil {
+		return ErrInvalidToken
+	}
+	if err := validatePassword(newPassword); err != nil {
+		return err
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	var (
+		userID  int64
+		revoked int64
+	)
+	err = s.store.ExecTx(ctx, func(q *db.Queries) error {
+		resetToken, err := q.GetPasswordResetTokenByHashForUpdate(ctx, hashRefreshToken(token))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvalidToken
+			}
+			return fmt.Errorf("failed to get reset token: %w", err)
+		}
+		userID = resetToken.UserID
+
+		if err := q.MarkPasswordResetTokenUsed(ctx, resetToken.ID); err != nil {
+			return fmt.Errorf("failed to mark reset token used: %w", err)
+		}
+		if err := q.ResetUserPassword(ctx, db.ResetUserPasswordParams{PasswordHash: string(passwordHash), ID: userID}); err != nil {
+			return fmt.Errorf("failed to update password: %w", err)
+		}
+
+		revoked, err = q.RevokeAllActiveSessionsByUserID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to revoke user sessions: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidToken) {
+			s.logger.Warnf("password reset attempt with invalid or expired token")
+		}
+		return err
+	}
+
+	s.revokeAccessTokens(userID)
+	s.logger.Infof("password of user (id_hash: %s) reset by token, revoked sessions: %d", hashUserID(userID), revoked)
+	return nil
+}
@@ -0,0 +1,275 @@
+package auth
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR PASSWORD RESET AND CHANGE (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Token leakage — only the SHA-256 hash is stored, older unused tokens stop working
+2. Token replay — a token works once and only before expiry
+3. Stolen sessions surviving a reset or change — all sessions and access tokens are revoked
+4. Self-lockout — after changing the password the current client stays logged in
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: IssuePasswordReset
+- GIVEN an existing user → previous tokens invalidated, hash stored, plain token returned once
+  and mailed to the user as a reset link
+- GIVEN a non-existent user → NotFoundError
+
+SCENARIO 2: ResetPassword
+- GIVEN a valid token → token marked used, password replaced, sessions and old tokens revoked
+- GIVEN an unknown/used/expired token → ErrInvalidToken
+- GIVEN a malformed token or short password → rejected without a transaction
+
+SCENARIO 3: ChangePassword
+- GIVEN the correct current password → hash updated, all sessions revoked, a new
+  session created; old access tokens rejected, the new one accepted and carries
+  the incremented token version
+- GIVEN a wrong current password → ErrInvalidCredentials, nothing revoked
+- GIVEN a short or unchanged new password → ValidationError without a transaction
+*/
+
+var userByIDColumns = []string{"id", "email", "role", "is_active", "organization_id", "last_login_at", "created_at", "updated_at", "token_version"}
+
+func TestIssuePasswordReset_StoresHashAndReturnsToken(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+	mail := attachMailRecorder(service)
+	now := time.Now()
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT id, email, role").
+				WithArgs(int64(7)).
+				WillReturnRows(sqlmock.NewRows(userByIDColumns).
+					AddRow(int64(7), "user@example.com", RoleViewer, true, int64(1), nil, now, now, int64(0)))
+			mock.ExpectExec("UPDATE password_reset_tokens").
+				WithArgs(int64(7)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectQuery("INSERT INTO password_reset_tokens").
+				WithArgs(int64(7), hashedTokenArg{}, int64(1), sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "created_at", "expires_at"}).
+					AddRow(int64(3), int64(7), now, now.Add(24*time.Hour)))
+		}),
+	)
+
+	resp, err := service.IssuePasswordReset(context.Background(), 1, 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), resp.UserID)
+	assert.Len(t, resp.Token, 64)
+	assert.NoError(t, validateRefreshTokenFormat(resp.Token))
+	assert.WithinDuration(t, now.Add(24*time.Hour), resp.ExpiresAt, time.Second)
+
+	sent := mail.messages(t)
+	require.Len(t, sent, 1)
+	assert.Equal(t, []string{"user@example.com"}, sent[0].To)
+	assert.Contains(t, sent[0].Body, "https://tenders.example.com/reset-password?token="+resp.Token)
+}
+
+func TestIssuePasswordReset_UserNotFound(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT id, email, role").
+				WithArgs(int64(7)).
+				WillReturnRows(sqlmock.NewRows(userByIDColumns))
+		}),
+	)
+
+	resp, err := service.IssuePasswordReset(context.Background(), 1, 7)
+
+	assert.Nil(t, resp)
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestResetPassword_Success_RevokesSessionsAndTokens(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+	token, tokenHash, err := generateRefreshToken()
+	require.NoError(t, err)
+
+	oldToken, err := service.generateAccessToken(7, RoleViewer, 1, 0)
+	require.NoError(t, err)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT id, user_id, expires_at").
+				WithArgs(tokenHash).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "expires_at"}).
+					AddRow(int64(3), int64(7), time.Now().Add(time.Hour)))
+			mock.ExpectExec("UPDATE password_reset_tokens").
+				WithArgs(int64(3)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec("UPDATE users").
+				WithArgs(sqlmock.AnyArg(), int64(7)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec("UPDATE user_sessions").
+				WithArgs(int64(7)).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+		}),
+	)
+
+	err = service.ResetPassword(context.Background(), token, "new-secret-pass")
+
+	require.NoError(t, err)
+	_, err = service.ValidateAccessToken(oldToken)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+}
+
+func TestResetPassword_UnknownToken_ReturnsErrInvalidToken(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+	token, _, err := generateRefreshToken()
+	require.NoError(t, err)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT id, user_id, expires_at").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "expires_at"}))
+		}),
+	)
+
+	err = service.ResetPassword(context.Background(), token, "new-secret-pass")
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	assert.Equal(t, 0, service.denylist.Len())
+}
+
+func TestResetPassword_RejectedWithoutTransaction(t *testing.T) {
+	service, _ := setupUserAdminService(t)
+	token, _, err := generateRefreshToken()
+	require.NoError(t, err)
+
+	err = service.ResetPassword(context.Background(), "not-a-token", "new-secret-pass")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	err = service.ResetPassword(context.Background(), token, "short")
+	var validationErr *apierrors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+var userAuthColumns = []string{"id", "email", "password_hash", "role", "is_active", "organization_id", "token_version"}
+
+func TestChangePassword_RevokesSessionsAndIssuesNewTokens(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+	currentHash, err := bcrypt.GenerateFromPassword([]byte("old-secret-pass"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	// GIVEN: a token issued well before the change (another device)
+	oldToken := tokenIssuedAt(t, service, 7, time.Now().Add(-time.Minute))
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT id, email, password_hash").
+				WithArgs(int64(7)).
+				WillReturnRows(sqlmock.NewRows(userAuthColumns).
+					AddRow(int64(7), "user@example.com", string(currentHash), RoleEditor, true, int64(1), int64(2)))
+			mock.ExpectExec("UPDATE users").
+				WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), int64(7)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec("UPDATE user_sessions").
+				WithArgs(int64(7)).
+				WillReturnResult(sqlmock.NewResult(0, 3))
+			mock.ExpectQuery("INSERT INTO user_sessions").
+				WithArgs(int64(7), hashedTokenArg{}, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "refresh_token_hash", "created_at", "expires_at", "revoked_at"}).
+					AddRow(int64(1), int64(7), "", time.Now(), time.Now().Add(time.Hour), nil))
+		}),
+	)
+
+	// WHEN
+	result, err := service.ChangePassword(context.Background(), 7, "old-secret-pass", "new-secret-pass", nil, "test-agent")
+
+	// THEN: other sessions revoked, old token rejected, the new pair works
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), result.RevokedSessions)
+	assert.NoError(t, validateRefreshTokenFormat(result.RefreshToken))
+
+	_, err = service.ValidateAccessToken(oldToken)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+	claims, err := service.ValidateAccessToken(result.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, RoleEditor, claims.Role)
+	// ChangeUserPassword увеличил token_version: новый токен несет новую версию
+	assert.Equal(t, int64(3), claims.TokenVersion)
+}
+
+func TestChangePassword_WrongCurrentPassword(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+	currentHash, err := bcrypt.GenerateFromPassword([]byte("old-secret-pass"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT id, email, password_hash").
+				WithArgs(int64(7)).
+				WillReturnRows(sqlmock.NewRows(userAuthColumns).
+					AddRow(int64(7), "user@example.com", string(currentHash), RoleEditor, true, int64(1), int64(2)))
+		}),
+	)
+
+	result, err := service.ChangePassword(context.Background(), 7, "wrong-password", "new-secret-pass", nil, "")
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.Equal(t, 0, service.denylist.Len())
+}
+
+func TestChangePassword_ValidationErrors(t *testing.T) {
+	cases := map[string]string{
+		"too short": "short",
+		"unchanged": "old-secret-pass",
+	}
+	for name, newPassword := range cases {
+		t.Run(name, func(t *testing.T) {
+			service, _ := setupUserAdminService(t)
+
+			_, err := service.ChangePassword(context.Background(), 7, "old-secret-pass", newPassword, nil, "")
+
+			var validationErr *apierrors.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+		})
+	}
+}
+
+// tokenIssuedAt подписывает access-токен с заданным iat.
+func tokenIssuedAt(t *testing.T, service *Service, userID int64, issuedAt time.Time) string {
+	t.Helper()
+	claims := JWTClaims{
+		UserID: userID,
+		Role:   RoleEditor,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(issuedAt.Add(service.config.Auth.AccessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(service.config.Auth.JWTSecret))
+	require.NoError(t, err)
+	return token
+}
+
+// hashedTokenArg проверяет, что в БД уходит hex-строка формата SHA-256 (64 символа).
+type hashedTokenArg struct{}
+
+func (hashedTokenArg) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	return ok && validateRefreshTokenFormat(s) == nil
+}
@@ -0,0 +1,135 @@
+package auth
+
+import "sort"
+
+// Permission — действие, доступ к которому проверяет RequirePermission.
+type Permission string
+
+const (
+	// Чтение тендеров, лотов, предложений, справочников и подрядчиков
+	PermissionTendersRead Permission = "tenders:read"
+	// Загрузка тендеров, правка и мягкое удаление тендеров, ключевые параметры лотов
+	PermissionTendersWrite Permission = "tenders:write"
+	// Просмотр (include_deleted) и восстановление мягко удалённых тендеров, безвозвратное удаление
+	PermissionTendersManageDeleted Permission = "tenders:manage_deleted"
+	// Победители лотов
+	PermissionWinnersManage Permission = "winners:manage"
+	// Типы, разделы и категории тендеров
+	PermissionReferenceManage Permission = "reference:manage"
+	// Аналитика лотов, история цен, статистика подрядчиков, CSV-выгрузки
+	PermissionAnalyticsRead Permission = "analytics:read"
+	// Ручное исправление сопоставления позиций КП с каталогом
+	PermissionMatchingCorrect Permission = "matching:correct"
+	// Пользователи и их роли
+	PermissionUsersManage Permission = "users:manage"
+	// Слияние, группировка, закрепление и активация позиций каталога
+	PermissionCatalogManage Permission = "catalog:manage"
+	// Дубликаты подрядчиков, их слияние и аккредитация
+	PermissionContractorsManage Permission = "contractors:manage"
+	// Системные настройки и ключи внутренних сервисов
+	PermissionSystemManage Permission = "system:manage"
+	// Трассировка и история импортов, проверка вероятных дубликатов тендеров
+	PermissionImportsInspect Permission = "imports:inspect"
+	// Тендеры всех организаций (без права — только своей) и справочник организаций
+	PermissionOrganizationsManage Permission = "organizations:manage"
+)
+
+const (
+	RoleAdmin   = "admin"
+	RoleEditor  = "editor"
+	RoleAnalyst = "analyst"
+	RoleViewer  = "viewer"
+)
+
+// rolePermissions — матрица прав. Набор ролей совпадает с таблицей roles
+// (миграция 000019); при добавлении роли нужны и миграция, и запись здесь.
+var rolePermissions = map[string][]Permission{
+	RoleViewer: {
+		PermissionTendersRead,
+	},
+	RoleAnalyst: {
+		PermissionTendersRead,
+		PermissionAnalyticsRead,
+		PermissionMatchingCorrect,
+	},
+	RoleEditor: {
+		PermissionTendersRead,
+		PermissionAnalyticsRead,
+		PermissionMatchingCorrect,
+		PermissionTendersWrite,
+		PermissionWinnersManage,
+		PermissionReferenceManage,
+	},
+	RoleAdmin: {
+		PermissionTendersRead,
+		PermissionAnalyticsRead,
+		PermissionMatchingCorrect,
+		PermissionTendersWrite,
+		PermissionWinnersManage,
+		PermissionReferenceManage,
+		PermissionTendersManageDeleted,
+		PermissionUsersManage,
+		PermissionCatalogManage,
+		PermissionContractorsManage,
+		PermissionSystemManage,
+		PermissionImportsInspect,
+		PermissionOrganizationsManage,
+	},
+}
+
+// legacyRoles — роли из access-токенов, выпущенных до миграции 000019
+// (operator переименован в editor). Такие токены живут не дольше access TTL.
+var legacyRoles = map[string]string{
+	"operator": RoleEditor,
+}
+
+// HasPermission сообщает, разрешено ли роли действие. Неизвестная роль не имеет прав.
+func HasPermission(role string, permission Permission) bool {
+	if current, ok := legacyRoles[role]; ok {
+		role = current
+	}
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionsForRole возвращает права роли (например, для скрытия действий во фронтенде).
+func PermissionsForRole(role string) []Permission {
+	if current, ok := legacyRoles[role]; ok {
+		role = current
+	}
+	permissions := append([]Permission{}, rolePermissions[role]...)
+	sort.Slice(permissions, func(i, j int) bool { return permissions[i] < permissions[j] })
+	return permissions
+}
+
+// IsValidRole сообщает, можно ли назначить роль пользователю.
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// Roles возвращает назначаемые роли в алфавитном порядке.
+func Roles() []string {
+	roles := make([]string, 0, len(rolePermissions))
+	for role := range rolePermissions {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// RolesWithPermission возвращает роли, которым разрешено действие, в алфавитном
+// порядке (например, чтобы выбрать адресатов уведомления).
+func RolesWithPermission(permission Permission) []string {
+	var roles []string
+	for _, role := range Roles() {
+		if HasPermission(role, permission) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
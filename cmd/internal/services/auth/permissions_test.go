@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR ROLE PERMISSIONS (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Privilege escalation — viewers must stay read-only, only admins manage users
+   and catalog merges; only admins see tenders of other organizations
+2. Lockout after deploy — tokens issued with the old "operator" role must keep
+   editor rights until they expire
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Permission matrix per role (viewer ⊂ analyst ⊂ editor ⊂ admin)
+SCENARIO 2: Unknown role → no permissions, not assignable
+SCENARIO 3: Legacy "operator" → editor permissions, not assignable
+SCENARIO 4: RolesWithPermission — assignable roles allowed to act, sorted
+*/
+
+func TestHasPermission_Matrix(t *testing.T) {
+	cases := []struct {
+		permission Permission
+		allowed    []string
+	}{
+		{PermissionTendersRead, []string{RoleViewer, RoleAnalyst, RoleEditor, RoleAdmin}},
+		{PermissionAnalyticsRead, []string{RoleAnalyst, RoleEditor, RoleAdmin}},
+		{PermissionMatchingCorrect, []string{RoleAnalyst, RoleEditor, RoleAdmin}},
+		{PermissionTendersWrite, []string{RoleEditor, RoleAdmin}},
+		{PermissionWinnersManage, []string{RoleEditor, RoleAdmin}},
+		{PermissionReferenceManage, []string{RoleEditor, RoleAdmin}},
+		{PermissionTendersManageDeleted, []string{RoleAdmin}},
+		{PermissionUsersManage, []string{RoleAdmin}},
+		{PermissionCatalogManage, []string{RoleAdmin}},
+		{PermissionContractorsManage, []string{RoleAdmin}},
+		{PermissionSystemManage, []string{RoleAdmin}},
+		{PermissionImportsInspect, []string{RoleAdmin}},
+		{PermissionOrganizationsManage, []string{RoleAdmin}},
+	}
+
+	for _, tc := range cases {
+		for _, role := range Roles() {
+			want := false
+			for _, allowed := range tc.allowed {
+				want = want || allowed == role
+			}
+			assert.Equal(t, want, HasPermission(role, tc.permission), "%s → %s", role, tc.permission)
+		}
+	}
+}
+
+func TestHasPermission_UnknownRole(t *testing.T) {
+	assert.False(t, HasPermission("superuser", PermissionTendersRead))
+	assert.False(t, HasPermission("", PermissionTendersRead))
+	assert.Empty(t, PermissionsForRole("superuser"))
+	assert.False(t, IsValidRole("superuser"))
+}
+
+func TestHasPermission_LegacyOperator(t *testing.T) {
+	assert.True(t, HasPermission("operator", PermissionWinnersManage))
+	assert.False(t, HasPermission("operator", PermissionUsersManage))
+	assert.Equal(t, PermissionsForRole(RoleEditor), PermissionsForRole("operator"))
+	assert.False(t, IsValidRole("operator"), "legacy role cannot be assigned")
+}
+
+func TestRoles(t *testing.T) {
+	assert.Equal(t, []string{RoleAdmin, RoleAnalyst, RoleEditor, RoleViewer}, Roles())
+}
+
+func TestRolesWithPermission(t *testing.T) {
+	assert.Equal(t, []string{RoleAdmin}, RolesWithPermission(PermissionCatalogManage))
+	assert.Equal(t, []string{RoleAdmin, RoleEditor}, RolesWithPermission(PermissionTendersWrite))
+}
@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// tokenVersionCache хранит прочитанные из БД версии access-токенов
+// пользователей (users.token_version). Запись живет ttl: после этого версия
+// читается заново, и отзыв, выполненный другим экземпляром API, начинает
+// действовать не позже чем через ttl.
+type tokenVersionCache struct {
+	mu       sync.RWMutex
+	entries  map[int64]tokenVersionEntry
+	ttl      time.Duration
+	prunedAt time.Time
+}
+
+type tokenVersionEntry struct {
+	version   int64
+	expiresAt time.Time
+}
+
+func newTokenVersionCache(ttl time.Duration) *tokenVersionCache {
+	return &tokenVersionCache{
+		entries: make(map[int64]tokenVersionEntry),
+		ttl:     ttl,
+	}
+}
+
+func (c *tokenVersionCache) get(userID int64, now time.Time) (int64, bool) {
+	c.mu.RLock()
+	entry, ok := c.entries[userID]
+	c.mu.RUnlock()
+
+	if !ok || !now.Before(entry.expiresAt) {
+		return 0, false
+	}
+	return entry.version, true
+}
+
+func (c *tokenVersionCache) set(userID, version int64, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Истекшие записи удаляются не чаще раза в ttl: кэш не растет больше
+	// числа пользователей, обращавшихся к API за последние два ttl
+	if now.Sub(c.prunedAt) > c.ttl {
+		for id, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, id)
+			}
+		}
+		c.prunedAt = now
+	}
+	c.entries[userID] = tokenVersionEntry{version: version, expiresAt: now.Add(c.ttl)}
+}
+
+// forget удаляет версию пользователя: следующий запрос прочитает ее из БД.
+func (c *tokenVersionCache) forget(userID int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, userID)
+}
+
+// CheckTokenVersion сверяет версию в access-токене с текущей версией
+// пользователя (users.token_version, с кэшем на auth.token_version_cache_ttl).
+// Токен с меньшей версией отозван: после выхода на всех устройствах, смены
+// пароля, роли, организации или статуса — ErrTokenRevoked. Удаленный
+// пользователь — тоже ErrTokenRevoked.
+//
+// Вызывается AuthMiddleware после ValidateAccessToken: проверка подписи не
+// обращается к БД, а версия — только при промахе кэша.
+func (s *Service) CheckTokenVersion(ctx context.Context, claims *JWTClaims) error {
+	now := time.Now()
+	current, ok := s.versions.get(claims.UserID, now)
+	if !ok {
+		version, err := s.store.GetUserTokenVersion(ctx, claims.UserID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrTokenRevoked
+			}
+			return fmt.Errorf("failed to get token version: %w", err)
+		}
+		current = version
+		s.versions.set(claims.UserID, current, now)
+	}
+
+	if claims.TokenVersion < current {
+		return ErrTokenRevoked
+	}
+	return nil
+}
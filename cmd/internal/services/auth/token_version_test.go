@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR ACCESS TOKEN VERSIONS (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. A stolen access token staying valid until its TTL after the user logs out everywhere
+2. Revocation on one API instance not reaching the others
+3. A database round-trip on every authenticated request
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: CheckTokenVersion
+- GIVEN a token carrying the user's current token_version
+  WHEN it is checked twice within token_version_cache_ttl
+  THEN both checks pass and the version is read from the DB once
+- GIVEN a token with an older version → ErrTokenRevoked
+- GIVEN the user no longer exists → ErrTokenRevoked
+- GIVEN the DB fails → the error is returned, not ErrTokenRevoked
+
+SCENARIO 2: LogoutAll
+- GIVEN a user with active sessions and a token of version 0
+  WHEN LogoutAll is called
+  THEN token_version is bumped and sessions revoked in one transaction
+  AND the cached version is dropped, so the old token is rejected immediately
+*/
+
+func TestCheckTokenVersion_CachesCurrentVersion(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+
+	mockStore.EXPECT().GetUserTokenVersion(gomock.Any(), int64(7)).Return(int64(2), nil).Times(1)
+
+	claims := &JWTClaims{UserID: 7, TokenVersion: 2}
+	require.NoError(t, service.CheckTokenVersion(context.Background(), claims))
+	require.NoError(t, service.CheckTokenVersion(context.Background(), claims))
+
+	stale := &JWTClaims{UserID: 7, TokenVersion: 1}
+	assert.ErrorIs(t, service.CheckTokenVersion(context.Background(), stale), ErrTokenRevoked)
+}
+
+func TestCheckTokenVersion_UserGone(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+
+	mockStore.EXPECT().GetUserTokenVersion(gomock.Any(), int64(7)).Return(int64(0), sql.ErrNoRows)
+
+	err := service.CheckTokenVersion(context.Background(), &JWTClaims{UserID: 7})
+
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+}
+
+func TestCheckTokenVersion_DBError(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+
+	mockStore.EXPECT().GetUserTokenVersion(gomock.Any(), int64(7)).Return(int64(0), errors.New("connection refused"))
+
+	err := service.CheckTokenVersion(context.Background(), &JWTClaims{UserID: 7})
+
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrTokenRevoked)
+}
+
+func TestLogoutAll_BumpsVersionAndRevokesSessions(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+	claims := &JWTClaims{UserID: 7, TokenVersion: 0}
+
+	// GIVEN: the current version is cached
+	mockStore.EXPECT().GetUserTokenVersion(gomock.Any(), int64(7)).Return(int64(0), nil)
+	require.NoError(t, service.CheckTokenVersion(context.Background(), claims))
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("UPDATE users").
+				WithArgs(sqlmock.AnyArg(), int64(7)).
+				WillReturnRows(sqlmock.NewRows([]string{"token_version"}).AddRow(int64(1)))
+			mock.ExpectExec("UPDATE user_sessions").
+				WithArgs(int64(7)).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+		}),
+	)
+
+	// WHEN
+	revoked, err := service.LogoutAll(context.Background(), 7)
+
+	// THEN: the cached version is dropped and the old token fails at once
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), revoked)
+
+	mockStore.EXPECT().GetUserTokenVersion(gomock.Any(), int64(7)).Return(int64(1), nil)
+	assert.ErrorIs(t, service.CheckTokenVersion(context.Background(), claims), ErrTokenRevoked)
+	assert.Equal(t, 1, service.denylist.Len())
+}
+
+func TestTokenVersionCache_Expires(t *testing.T) {
+	cache := newTokenVersionCache(time.Second)
+	now := time.Now()
+
+	cache.set(7, 3, now)
+
+	version, ok := cache.get(7, now.Add(500*time.Millisecond))
+	assert.True(t, ok)
+	assert.Equal(t, int64(3), version)
+
+	_, ok = cache.get(7, now.Add(time.Second))
+	assert.False(t, ok)
+}
@@ -0,0 +1,360 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/db/postgres"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/notifications"
+)
+
+// maxEmailLength — длина колонки users.email.
+const maxEmailLength = 255
+
+// CreateUser создает активного пользователя с указанной ролью. Email
+// нормализуется так же, как при входе (trim + lower). Без organization_id
+// пользователь попадает в организацию по умолчанию. Пользователю уходит
+// письмо-приглашение с адресом входа (пароль в письмо не попадает).
+func (s *Service) CreateUser(ctx context.Context, actorID int64, req api_models.CreateUserRequest) (*api_models.AdminUserResponse, error) {
+	email, err := normalizeEmail(req.Email)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePassword(req.Password); err != nil {
+		return nil, err
+	}
+	if !IsValidRole(req.Role) {
+		return nil, apierrors.NewValidationError("auth.invalid_role", req.Role, strings.Join(Roles(), ", "))
+	}
+	organizationID, err := optionalOrganizationID(req.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user, err := s.store.CreateUser(ctx, db.CreateUserParams{
+		Email:          email,
+		PasswordHash:   string(passwordHash),
+		Role:           req.Role,
+		IsActive:       true,
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, apierrors.NewConflictError("auth.email_exists", nil)
+		}
+		if postgres.IsForeignKeyViolation(err) {
+			return nil, organizationNotFound(organizationID)
+		}
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	s.logger.Infof("user (id_hash: %s) with role %s created by admin (id_hash: %s)",
+		hashUserID(user.ID), user.Role, hashUserID(actorID))
+	if s.notifier != nil {
+		s.notifier.NotifyInvitation(notifications.Invitation{Email: user.Email, Role: user.Role})
+	}
+
+	return adminUserResponse(user.ID, user.Email, user.Role, user.IsActive, user.OrganizationID,
+		sql.NullTime{}, user.CreatedAt, user.UpdatedAt, sql.NullTime{}, 0), nil
+}
+
+// UpdateUser меняет email, статус активности и/или организацию пользователя и
+// в той же транзакции отзывает все его сессии: после смены email пользователь
+// входит заново с новым логином, организация входит в access-токен.
+// Деактивировать себя нельзя.
+func (s *Service) UpdateUser(ctx context.Context, actorID, userID int64, req api_models.UpdateUserRequest) (*api_models.AdminUserResponse, error) {
+	if userID <= 0 {
+		return nil, apierrors.NewValidationError("request.id_positive_got", userID)
+	}
+	if req.Email == nil && req.IsActive == nil && req.OrganizationID == nil {
+		return nil, apierrors.NewValidationError("auth.user_update_empty")
+	}
+	if actorID == userID && req.IsActive != nil && !*req.IsActive {
+		return nil, apierrors.NewValidationError("auth.cannot_deactivate_self")
+	}
+
+	params := db.UpdateUserParams{ID: userID}
+	if req.Email != nil {
+		email, err := normalizeEmail(*req.Email)
+		if err != nil {
+			return nil, err
+		}
+		params.Email = sql.NullString{String: email, Valid: true}
+	}
+	if req.IsActive != nil {
+		params.IsActive = sql.NullBool{Bool: *req.IsActive, Valid: true}
+	}
+	organizationID, err := optionalOrganizationID(req.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	params.OrganizationID = organizationID
+
+	var (
+		user    db.UpdateUserRow
+		revoked int64
+	)
+	err = s.store.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		user, err = q.UpdateUser(ctx, params)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return apierrors.NewNotFoundError("auth.user_id_not_found", userID)
+			}
+			if isUniqueViolation(err) {
+				return apierrors.NewConflictError("auth.email_exists", nil)
+			}
+			if postgres.IsForeignKeyViolation(err) {
+				return organizationNotFound(organizationID)
+			}
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+
+		revoked, err = q.RevokeAllActiveSessionsByUserID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to revoke user sessions: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.revokeAccessTokens(userID)
+	s.logger.Infof("user (id_hash: %s) updated by admin (id_hash: %s): email_changed=%t, is_active=%t, organization_id=%d, revoked sessions: %d",
+		hashUserID(userID), hashUserID(actorID), req.Email != nil, user.IsActive, user.OrganizationID, revoked)
+
+	return adminUserResponse(user.ID, user.Email, user.Role, user.IsActive, user.OrganizationID,
+		user.LastLoginAt, user.CreatedAt, user.UpdatedAt, user.TokensRevokedAt, revoked), nil
+}
+
+// DeleteUser отключает учетную запись: деактивирует пользователя и отзывает
+// все его сессии и access-токены. Строка users не удаляется — на id
+// пользователя ссылаются аудитные поля (deleted_by, updated_by), а отзыв
+// токенов доходит до других экземпляров API через users.tokens_revoked_at.
+func (s *Service) DeleteUser(ctx context.Context, actorID, userID int64) (*api_models.AdminUserResponse, error) {
+	if actorID == userID {
+		return nil, apierrors.NewValidationError("auth.cannot_delete_self")
+	}
+	return s.SetUserActive(ctx, actorID, userID, false)
+}
+
+// ChangeUserRole меняет роль пользователя и в той же транзакции отзывает все
+// его сессии. Выданные ранее access-токены попадают в denylist, поэтому новые
+// права действуют сразу, а не по истечении токена.
+//
+// actorID — администратор, выполняющий изменение: менять собственную роль
+// нельзя, иначе единственный admin может случайно лишить себя доступа.
+func (s *Service) ChangeUserRole(ctx context.Context, actorID, userID int64, role string) (*api_models.AdminUserResponse, error) {
+	if userID <= 0 {
+		return nil, apierrors.NewValidationError("request.id_positive_got", userID)
+	}
+	if !IsValidRole(role) {
+		return nil, apierrors.NewValidationError("auth.invalid_role", role, strings.Join(Roles(), ", "))
+	}
+	if actorID == userID {
+		return nil, apierrors.NewValidationError("auth.cannot_change_own_role")
+	}
+
+	var (
+		user    db.UpdateUserRoleRow
+		revoked int64
+	)
+	err := s.store.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		user, err = q.UpdateUserRole(ctx, db.UpdateUserRoleParams{Role: role, ID: userID})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return apierrors.NewNotFoundError("auth.user_id_not_found", userID)
+			}
+			return fmt.Errorf("failed to update user role: %w", err)
+		}
+
+		revoked, err = q.RevokeAllActiveSessionsByUserID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to revoke user sessions: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.revokeAccessTokens(userID)
+	s.logger.Infof("role of user (id_hash: %s) changed to %s by admin (id_hash: %s), revoked sessions: %d",
+		hashUserID(userID), role, hashUserID(actorID), revoked)
+
+	return adminUserResponse(user.ID, user.Email, user.Role, user.IsActive, user.OrganizationID,
+		user.LastLoginAt, user.CreatedAt, user.UpdatedAt, user.TokensRevokedAt, revoked), nil
+}
+
+// SetUserActive активирует или деактивирует пользователя и в той же транзакции
+// отзывает все его сессии, как и ChangeUserRole. Деактивировать себя нельзя.
+func (s *Service) SetUserActive(ctx context.Context, actorID, userID int64, isActive bool) (*api_models.AdminUserResponse, error) {
+	if userID <= 0 {
+		return nil, apierrors.NewValidationError("request.id_positive_got", userID)
+	}
+	if actorID == userID && !isActive {
+		return nil, apierrors.NewValidationError("auth.cannot_deactivate_self")
+	}
+
+	var (
+		user    db.UpdateUserActiveStatusRow
+		revoked int64
+	)
+	err := s.store.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		user, err = q.UpdateUserActiveStatus(ctx, db.UpdateUserActiveStatusParams{IsActive: isActive, ID: userID})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return apierrors.NewNotFoundError("auth.user_id_not_found", userID)
+			}
+			return fmt.Errorf("failed to update user status: %w", err)
+		}
+
+		revoked, err = q.RevokeAllActiveSessionsByUserID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to revoke user sessions: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.revokeAccessTokens(userID)
+	s.logger.Infof("user (id_hash: %s) is_active=%t set by admin (id_hash: %s), revoked sessions: %d",
+		hashUserID(userID), isActive, hashUserID(actorID), revoked)
+
+	return adminUserResponse(user.ID, user.Email, user.Role, user.IsActive, user.OrganizationID,
+		user.LastLoginAt, user.CreatedAt, user.UpdatedAt, user.TokensRevokedAt, revoked), nil
+}
+
+// revokeAccessTokens заносит пользователя в denylist после коммита транзакции
+// и сбрасывает закэшированную версию токенов, увеличенную той же транзакцией.
+// Используется локальное время: iat токенов этого процесса тоже берется из него.
+func (s *Service) revokeAccessTokens(userID int64) {
+	s.denylist.Revoke(userID, time.Now())
+	s.versions.forget(userID)
+}
+
+// SyncTokenRevocations подтягивает в denylist отзывы, записанные в БД
+// (в том числе другими экземплярами API), и удаляет устаревшие записи.
+// Учитываются только отзывы за последний access TTL: более старые не могут
+// затронуть действующий токен.
+func (s *Service) SyncTokenRevocations(ctx context.Context) error {
+	now := time.Now()
+	rows, err := s.store.ListUserTokenRevocationsSince(ctx, now.Add(-s.config.Auth.AccessTokenTTL))
+	if err != nil {
+		return fmt.Errorf("failed to list token revocations: %w", err)
+	}
+
+	for _, row := range rows {
+		s.denylist.Revoke(row.ID, row.TokensRevokedAt)
+	}
+	s.denylist.Prune(now)
+	return nil
+}
+
+// RunTokenRevocationSync периодически вызывает SyncTokenRevocations до отмены ctx.
+// Предназначен для запуска в отдельной горутине.
+func (s *Service) RunTokenRevocationSync(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.SyncTokenRevocations(ctx); err != nil {
+				s.logger.Errorf("failed to sync token revocations (keeping previous denylist): %v", err)
+			}
+		}
+	}
+}
+
+func adminUserResponse(
+	id int64,
+	email, role string,
+	isActive bool,
+	organizationID int64,
+	lastLoginAt sql.NullTime,
+	createdAt, updatedAt time.Time,
+	tokensRevokedAt sql.NullTime,
+	revokedSessions int64,
+) *api_models.AdminUserResponse {
+	resp := &api_models.AdminUserResponse{
+		ID:              id,
+		Email:           email,
+		Role:            role,
+		IsActive:        isActive,
+		OrganizationID:  organizationID,
+		CreatedAt:       createdAt,
+		UpdatedAt:       updatedAt,
+		RevokedSessions: revokedSessions,
+	}
+	if lastLoginAt.Valid {
+		t := lastLoginAt.Time
+		resp.LastLoginAt = &t
+	}
+	if tokensRevokedAt.Valid {
+		t := tokensRevokedAt.Time
+		resp.TokensRevokedAt = &t
+	}
+	return resp
+}
+
+// normalizeEmail приводит email к виду, который хранится в users (trim + lower),
+// и проверяет его формат.
+func normalizeEmail(raw string) (string, error) {
+	email := strings.ToLower(strings.TrimSpace(raw))
+	if email == "" {
+		return "", apierrors.NewValidationError("auth.email_empty")
+	}
+	if len(email) > maxEmailLength {
+		return "", apierrors.NewValidationError("auth.email_too_long", maxEmailLength)
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", apierrors.NewValidationError("auth.invalid_email", raw)
+	}
+	return email, nil
+}
+
+// optionalOrganizationID проверяет organization_id из запроса админки;
+// nil — значение не передано.
+func optionalOrganizationID(id *int64) (sql.NullInt64, error) {
+	if id == nil {
+		return sql.NullInt64{}, nil
+	}
+	if *id <= 0 {
+		return sql.NullInt64{}, apierrors.NewValidationError("auth.organization_id_positive", *id)
+	}
+	return sql.NullInt64{Int64: *id, Valid: true}, nil
+}
+
+// organizationNotFound — ответ на нарушение внешнего ключа users.organization_id.
+func organizationNotFound(id sql.NullInt64) error {
+	return apierrors.NewValidationError("auth.organization_not_found", id.Int64)
+}
+
+// isUniqueViolation сообщает, нарушено ли ограничение уникальности (uq_users_email).
+func isUniqueViolation(err error) bool {
+	return postgres.IsUniqueViolation(err)
+}
@@ -0,0 +1,476 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/notifications"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/mailer"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR USER ROLE/STATUS CHANGES (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Stale permissions — after a role change or deactivation, old sessions and
+   access tokens must stop working immediately, not at expiry
+2. Partial updates — user update and session revocation happen in one transaction
+3. Self-lockout — an admin cannot demote or deactivate themselves
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: ChangeUserRole
+- GIVEN an existing user with a valid access token
+  WHEN ChangeUserRole is called
+  THEN role is updated, sessions are revoked in the same tx, the old token is rejected
+
+- GIVEN an unknown role, own id or id <= 0
+  WHEN ChangeUserRole is called
+  THEN ValidationError is returned without calling ExecTx
+
+- GIVEN a non-existent user
+  WHEN ChangeUserRole is called
+  THEN NotFoundError is returned and no tokens are revoked
+
+- GIVEN session revocation fails
+  WHEN ChangeUserRole is called
+  THEN the error is returned and the old token stays valid (tx rolled back)
+
+SCENARIO 2: SetUserActive
+- GIVEN an active user
+  WHEN SetUserActive(false) is called
+  THEN user is deactivated, sessions are revoked, the old token is rejected
+
+- GIVEN the admin deactivates themselves
+  WHEN SetUserActive(false) is called
+  THEN ValidationError is returned
+
+SCENARIO 3: SyncTokenRevocations
+- GIVEN revocations recorded by another API instance
+  WHEN SyncTokenRevocations is called
+  THEN tokens issued before them are rejected locally
+
+SCENARIO 4: CreateUser
+- GIVEN a valid email, password and role → user created active, email normalized,
+  an invitation with the login link (without the password) is mailed
+- GIVEN an email already taken (pq 23505) → ConflictError
+- GIVEN an unknown organization_id (FK 23503) → ValidationError
+- GIVEN a bad email, short password or unknown role → ValidationError, no DB call
+
+SCENARIO 5: UpdateUser / DeleteUser
+- GIVEN a new email → email updated, sessions revoked, old token rejected
+- GIVEN no fields, or the admin deactivating/deleting themselves → ValidationError
+- GIVEN DeleteUser → user deactivated (row kept), sessions revoked
+*/
+
+var adminUserColumns = []string{"id", "email", "role", "is_active", "organization_id", "last_login_at", "created_at", "updated_at", "tokens_revoked_at"}
+
+func setupUserAdminService(t *testing.T) (*Service, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+
+	cfg := &config.Config{
+		Auth: config.AuthConfig{
+			JWTSecret:        "test-secret-key-minimum-32-chars-long",
+			AccessTokenTTL:   15 * time.Minute,
+			RefreshTokenTTL:  7 * 24 * time.Hour,
+			PasswordResetTTL: 24 * time.Hour,
+			ImpersonationTTL: 30 * time.Minute,
+
+			TokenVersionCacheTTL: 5 * time.Second,
+		},
+	}
+	return NewService(mockStore, cfg, testutil.NewMockLogger(), nil), mockStore
+}
+
+// mailRecorder запоминает письма сервиса уведомлений.
+type mailRecorder struct {
+	mu       sync.Mutex
+	sent     []mailer.Message
+	notifier *notifications.Service
+}
+
+func (r *mailRecorder) Send(_ context.Context, msg mailer.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = append(r.sent, msg)
+	return nil
+}
+
+// messages дожидается фоновой отправки и возвращает письма.
+func (r *mailRecorder) messages(t *testing.T) []mailer.Message {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, r.notifier.Wait(ctx))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]mailer.Message(nil), r.sent...)
+}
+
+// attachMailRecorder подключает к сервису уведомления с записью писем.
+func attachMailRecorder(service *Service) *mailRecorder {
+	rec := &mailRecorder{}
+	rec.notifier = notifications.NewService(rec, config.MailConfig{From: "noreply@example.com"},
+		config.NotificationsConfig{AppURL: "https://tenders.example.com"}, testutil.NewMockLogger())
+	service.notifier = rec.notifier
+	return rec
+}
+
+// execTxDoAndReturn выполняет callback ExecTx на *db.Queries поверх go-sqlmock.
+func execTxDoAndReturn(t *testing.T, setupFn func(mock sqlmock.Sqlmock)) func(ctx context.Context, fn func(*db.Queries) error) error {
+	t.Helper()
+	return func(ctx context.Context, fn func(*db.Queries) error) error {
+		sqlDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet(), "sqlmock: unmet expectations")
+			sqlDB.Close()
+		}()
+		setupFn(mock)
+		return fn(db.New(sqlDB))
+	}
+}
+
+func TestChangeUserRole_RevokesSessionsAndTokens(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+	now := time.Now()
+
+	// GIVEN: user 7 holds a valid access token
+	oldToken, err := service.generateAccessToken(7, "admin", 1, 0)
+	require.NoError(t, err)
+	_, err = service.ValidateAccessToken(oldToken)
+	require.NoError(t, err)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("UPDATE users").
+				WithArgs("viewer", int64(7)).
+				WillReturnRows(sqlmock.NewRows(adminUserColumns).
+					AddRow(int64(7), "user@example.com", "viewer", true, int64(1), nil, now, now, now))
+			mock.ExpectExec("UPDATE user_sessions").
+				WithArgs(int64(7)).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+		}),
+	)
+
+	// WHEN: admin 1 demotes user 7
+	resp, err := service.ChangeUserRole(context.Background(), 1, 7, "viewer")
+
+	// THEN: role changed, both sessions revoked, old token rejected
+	require.NoError(t, err)
+	assert.Equal(t, "viewer", resp.Role)
+	assert.Equal(t, int64(2), resp.RevokedSessions)
+	require.NotNil(t, resp.TokensRevokedAt)
+
+	_, err = service.ValidateAccessToken(oldToken)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+}
+
+func TestChangeUserRole_ValidationErrors(t *testing.T) {
+	cases := map[string]struct {
+		actorID, userID int64
+		role            string
+	}{
+		"unknown role": {1, 7, "superadmin"},
+		"own role":     {7, 7, "viewer"},
+		"invalid id":   {1, 0, "viewer"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			service, _ := setupUserAdminService(t)
+
+			_, err := service.ChangeUserRole(context.Background(), tc.actorID, tc.userID, tc.role)
+
+			var validationErr *apierrors.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+		})
+	}
+}
+
+func TestChangeUserRole_UserNotFound(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("UPDATE users").
+				WithArgs("viewer", int64(7)).
+				WillReturnRows(sqlmock.NewRows(adminUserColumns))
+		}),
+	)
+
+	_, err := service.ChangeUserRole(context.Background(), 1, 7, "viewer")
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+	assert.Equal(t, 0, service.denylist.Len())
+}
+
+func TestChangeUserRole_RevokeFails_TokenStaysValid(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+	now := time.Now()
+
+	oldToken, err := service.generateAccessToken(7, "admin", 1, 0)
+	require.NoError(t, err)
+
+	dbErr := errors.New("connection reset")
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("UPDATE users").
+				WillReturnRows(sqlmock.NewRows(adminUserColumns).
+					AddRow(int64(7), "user@example.com", "viewer", true, int64(1), nil, now, now, now))
+			mock.ExpectExec("UPDATE user_sessions").
+				WillReturnError(dbErr)
+		}),
+	)
+
+	_, err = service.ChangeUserRole(context.Background(), 1, 7, "viewer")
+
+	assert.ErrorIs(t, err, dbErr)
+	_, err = service.ValidateAccessToken(oldToken)
+	assert.NoError(t, err, "role change was rolled back, token must stay valid")
+}
+
+func TestSetUserActive_Deactivate(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+	now := time.Now()
+
+	oldToken, err := service.generateAccessToken(7, "editor", 1, 0)
+	require.NoError(t, err)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("UPDATE users").
+				WithArgs(false, int64(7)).
+				WillReturnRows(sqlmock.NewRows(adminUserColumns).
+					AddRow(int64(7), "user@example.com", "editor", false, int64(1), now, now, now, now))
+			mock.ExpectExec("UPDATE user_sessions").
+				WithArgs(int64(7)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}),
+	)
+
+	resp, err := service.SetUserActive(context.Background(), 1, 7, false)
+
+	require.NoError(t, err)
+	assert.False(t, resp.IsActive)
+	assert.Equal(t, int64(1), resp.RevokedSessions)
+	require.NotNil(t, resp.LastLoginAt)
+
+	_, err = service.ValidateAccessToken(oldToken)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+}
+
+func TestSetUserActive_DeactivateSelf_ReturnsValidationError(t *testing.T) {
+	service, _ := setupUserAdminService(t)
+
+	_, err := service.SetUserActive(context.Background(), 7, 7, false)
+
+	var validationErr *apierrors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestSyncTokenRevocations(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+
+	oldToken, err := service.generateAccessToken(7, "admin", 1, 0)
+	require.NoError(t, err)
+
+	// GIVEN: another instance revoked user 7 a moment later
+	mockStore.EXPECT().ListUserTokenRevocationsSince(gomock.Any(), gomock.Any()).
+		Return([]db.ListUserTokenRevocationsSinceRow{
+			{ID: 7, TokensRevokedAt: time.Now().Add(time.Second)},
+		}, nil)
+
+	// WHEN
+	require.NoError(t, service.SyncTokenRevocations(context.Background()))
+
+	// THEN
+	_, err = service.ValidateAccessToken(oldToken)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+}
+
+func TestCreateUser_Success(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+	mail := attachMailRecorder(service)
+	now := time.Now()
+
+	mockStore.EXPECT().CreateUser(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateUserParams) (db.CreateUserRow, error) {
+			assert.Equal(t, "new@example.com", arg.Email)
+			assert.Equal(t, RoleAnalyst, arg.Role)
+			assert.True(t, arg.IsActive)
+			assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(arg.PasswordHash), []byte("secret-pass")))
+			return db.CreateUserRow{ID: 9, Email: arg.Email, Role: arg.Role, IsActive: true, CreatedAt: now, UpdatedAt: now}, nil
+		})
+
+	resp, err := service.CreateUser(context.Background(), 1, api_models.CreateUserRequest{
+		Email: "  New@Example.com ", Password: "secret-pass", Role: RoleAnalyst,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(9), resp.ID)
+	assert.Equal(t, "new@example.com", resp.Email)
+	assert.Nil(t, resp.LastLoginAt)
+
+	sent := mail.messages(t)
+	require.Len(t, sent, 1)
+	assert.Equal(t, []string{"new@example.com"}, sent[0].To)
+	assert.Contains(t, sent[0].Body, "https://tenders.example.com/login")
+	assert.NotContains(t, sent[0].Body, "secret-pass")
+}
+
+func TestCreateUser_DuplicateEmail_ReturnsConflict(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+
+	mockStore.EXPECT().CreateUser(gomock.Any(), gomock.Any()).
+		Return(db.CreateUserRow{}, &pq.Error{Code: "23505", Constraint: "uq_users_email"})
+
+	_, err := service.CreateUser(context.Background(), 1, api_models.CreateUserRequest{
+		Email: "taken@example.com", Password: "secret-pass", Role: RoleViewer,
+	})
+
+	var conflictErr *apierrors.ConflictError
+	assert.ErrorAs(t, err, &conflictErr)
+}
+
+func TestCreateUser_UnknownOrganization_ReturnsValidationError(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+	organizationID := int64(42)
+
+	mockStore.EXPECT().CreateUser(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateUserParams) (db.CreateUserRow, error) {
+			assert.Equal(t, sql.NullInt64{Int64: 42, Valid: true}, arg.OrganizationID)
+			return db.CreateUserRow{}, &pq.Error{Code: "23503", Constraint: "users_organization_id_fkey"}
+		})
+
+	_, err := service.CreateUser(context.Background(), 1, api_models.CreateUserRequest{
+		Email: "new@example.com", Password: "secret-pass", Role: RoleViewer, OrganizationID: &organizationID,
+	})
+
+	var validationErr *apierrors.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Contains(t, err.Error(), "организация с id=42 не найдена")
+}
+
+func TestCreateUser_ValidationErrors(t *testing.T) {
+	cases := map[string]api_models.CreateUserRequest{
+		"bad email":      {Email: "not-an-email", Password: "secret-pass", Role: RoleViewer},
+		"display name":   {Email: "Bob <bob@example.com>", Password: "secret-pass", Role: RoleViewer},
+		"short password": {Email: "a@example.com", Password: "short", Role: RoleViewer},
+		"legacy role":    {Email: "a@example.com", Password: "secret-pass", Role: "operator"},
+	}
+	for name, req := range cases {
+		t.Run(name, func(t *testing.T) {
+			service, _ := setupUserAdminService(t)
+
+			_, err := service.CreateUser(context.Background(), 1, req)
+
+			var validationErr *apierrors.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+		})
+	}
+}
+
+func TestUpdateUser_ChangeEmail_RevokesSessions(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+	now := time.Now()
+
+	oldToken, err := service.generateAccessToken(7, RoleEditor, 1, 0)
+	require.NoError(t, err)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("UPDATE users").
+				WithArgs("renamed@example.com", nil, nil, int64(7)).
+				WillReturnRows(sqlmock.NewRows(adminUserColumns).
+					AddRow(int64(7), "renamed@example.com", RoleEditor, true, int64(1), nil, now, now, now))
+			mock.ExpectExec("UPDATE user_sessions").
+				WithArgs(int64(7)).
+				WillReturnResult(sqlmock.NewResult(0, 3))
+		}),
+	)
+
+	email := "Renamed@example.com"
+	resp, err := service.UpdateUser(context.Background(), 1, 7, api_models.UpdateUserRequest{Email: &email})
+
+	require.NoError(t, err)
+	assert.Equal(t, "renamed@example.com", resp.Email)
+	assert.Equal(t, int64(3), resp.RevokedSessions)
+	_, err = service.ValidateAccessToken(oldToken)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+}
+
+func TestUpdateUser_ValidationErrors(t *testing.T) {
+	inactive := false
+	badOrganization := int64(0)
+	cases := map[string]struct {
+		actorID int64
+		req     api_models.UpdateUserRequest
+	}{
+		"no fields":        {1, api_models.UpdateUserRequest{}},
+		"deactivate self":  {7, api_models.UpdateUserRequest{IsActive: &inactive}},
+		"bad organization": {1, api_models.UpdateUserRequest{OrganizationID: &badOrganization}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			service, _ := setupUserAdminService(t)
+
+			_, err := service.UpdateUser(context.Background(), tc.actorID, 7, tc.req)
+
+			var validationErr *apierrors.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+		})
+	}
+}
+
+func TestDeleteUser_DeactivatesAndRevokes(t *testing.T) {
+	service, mockStore := setupUserAdminService(t)
+	now := time.Now()
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("UPDATE users").
+				WithArgs(false, int64(7)).
+				WillReturnRows(sqlmock.NewRows(adminUserColumns).
+					AddRow(int64(7), "user@example.com", RoleViewer, false, int64(1), nil, now, now, now))
+			mock.ExpectExec("UPDATE user_sessions").
+				WithArgs(int64(7)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}),
+	)
+
+	resp, err := service.DeleteUser(context.Background(), 1, 7)
+
+	require.NoError(t, err)
+	assert.False(t, resp.IsActive)
+	assert.Equal(t, int64(1), resp.RevokedSessions)
+}
+
+func TestDeleteUser_Self_ReturnsValidationError(t *testing.T) {
+	service, _ := setupUserAdminService(t)
+
+	_, err := service.DeleteUser(context.Background(), 7, 7)
+
+	var validationErr *apierrors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
@@ -0,0 +1,334 @@
+// Package baseline строит оценочный baseline лота, когда организатор не
+// приложил к тендеру свою смету.
+//
+// Перечень работ берётся из baseline лота (если в нём есть позиции) или из
+// предложения подрядчика с наибольшим числом позиций. Цена за единицу каждой
+// позиции — средняя цена сопоставленной позиции каталога по истории других
+// тендеров за окно WindowMonths, в той же единице измерения и в базовой
+// валюте. Позиции без сопоставления или без истории цен остаются без цены.
+package baseline
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/currency"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/entities"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+const (
+	// DefaultWindowMonths — окно истории цен по умолчанию.
+	DefaultWindowMonths = 24
+	// MaxWindowMonths — наибольшее допустимое окно.
+	MaxWindowMonths = 120
+
+	// SourceInfoKey и SourceCatalogEstimate помечают baseline, цены которого
+	// оценены по каталогу (proposal_additional_info).
+	SourceInfoKey         = "baseline_source"
+	SourceCatalogEstimate = "catalog_estimate"
+
+	// Подрядчик baseline — тот же служебный "Initiator", что и при импорте.
+	initiatorINN   = "0000000000"
+	initiatorTitle = "Initiator"
+)
+
+// EstimateOptions — параметры оценки.
+type EstimateOptions struct {
+	WindowMonths   int           // 0 — DefaultWindowMonths
+	DryRun         bool          // Только рассчитать, ничего не сохранять
+	OrganizationID sql.NullInt64 // Цены только тендеров организации (NULL — все)
+}
+
+// EstimateService строит оценочный baseline лота.
+type EstimateService struct {
+	store    db.Store
+	logger   logging.Logger
+	rates    *currency.Rates
+	entities *entities.EntityManager
+	now      func() time.Time // Подменяется в тестах
+}
+
+// NewEstimateService создаёт новый экземпляр EstimateService.
+func NewEstimateService(store db.Store, logger logging.Logger, rates *currency.Rates) *EstimateService {
+	return &EstimateService{
+		store:    store,
+		logger:   logger,
+		rates:    rates,
+		entities: entities.NewEntityManager(logger),
+		now:      time.Now,
+	}
+}
+
+// averageKey — позиция каталога и единица измерения средней цены.
+type averageKey struct {
+	catalogPositionID int64
+	unitID            sql.NullInt64
+}
+
+type average struct {
+	unitCost decimal.Decimal
+	count    int64
+}
+
+// Estimate рассчитывает оценочный baseline лота и, если не DryRun, сохраняет
+// его: создаёт baseline (подрядчик "Initiator"), копирует в него перечень
+// работ и проставляет цены позициям, у которых цены нет. Baseline, в котором
+// уже есть цены, не перезаписывается (ConflictError).
+func (s *EstimateService) Estimate(ctx context.Context, lotID int64, opts EstimateOptions) (*api_models.BaselineEstimateResponse, error) {
+	logger := s.logger.WithField("method", "Estimate").WithField("lot_id", lotID)
+
+	if lotID <= 0 {
+		return nil, apierrors.NewValidationError("request.id_positive_got", lotID)
+	}
+	if opts.WindowMonths == 0 {
+		opts.WindowMonths = DefaultWindowMonths
+	}
+	if opts.WindowMonths < 1 || opts.WindowMonths > MaxWindowMonths {
+		return nil, apierrors.NewValidationError("baseline.window_months_range", MaxWindowMonths, opts.WindowMonths)
+	}
+	if err := s.checkLotVisible(ctx, lotID); err != nil {
+		return nil, err
+	}
+
+	proposals, err := s.store.ListLotProposalsForEstimate(ctx, lotID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения предложений лота %d: %w", lotID, err)
+	}
+	baselineID, sourceID, err := pickSource(lotID, proposals)
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := s.store.ListProposalPositionsForEstimate(ctx, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения позиций предложения %d: %w", sourceID, err)
+	}
+	averages, err := s.loadAverages(ctx, lotID, positions, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	response := buildEstimate(positions, averages)
+	response.LotID = lotID
+	response.SourceProposalID = sourceID
+	response.DryRun = opts.DryRun
+	response.Currency = s.rates.Base()
+	response.WindowMonths = opts.WindowMonths
+
+	if opts.DryRun {
+		logger.Infof("Оценка baseline (dry run): оценено %d позиций, без оценки %d", response.EstimatedCount, response.MissingCount)
+		return response, nil
+	}
+
+	proposalID, err := s.save(ctx, lotID, baselineID, sourceID, response.Positions)
+	if err != nil {
+		return nil, err
+	}
+	response.ProposalID = &proposalID
+	logger.Infof("Сохранён оценочный baseline %d: оценено %d позиций, без оценки %d, итог %s %s",
+		proposalID, response.EstimatedCount, response.MissingCount, response.TotalCost.StringFixed(2), response.Currency)
+	return response, nil
+}
+
+// checkLotVisible проверяет, что лот существует и его тендер не удалён.
+func (s *EstimateService) checkLotVisible(ctx context.Context, lotID int64) error {
+	lot, err := s.store.GetLotByID(ctx, lotID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apierrors.NewNotFoundError("common.lot_not_found", lotID)
+		}
+		return fmt.Errorf("ошибка получения лота %d: %w", lotID, err)
+	}
+	tender, err := s.store.GetTenderByID(ctx, lot.TenderID)
+	if err != nil {
+		return fmt.Errorf("ошибка получения тендера лота %d: %w", lotID, err)
+	}
+	if tender.DeletedAt.Valid {
+		return apierrors.NewNotFoundError("common.lot_not_found", lotID)
+	}
+	return nil
+}
+
+// pickSource выбирает предложение, из которого берётся перечень работ.
+// baselineID == 0 — baseline у лота ещё нет.
+func pickSource(lotID int64, proposals []db.ListLotProposalsForEstimateRow) (baselineID, sourceID int64, err error) {
+	for _, p := range proposals {
+		if !p.IsBaseline {
+			continue
+		}
+		if p.PricedCount > 0 {
+			return 0, 0, apierrors.NewConflictErrorf(map[string]int64{"proposal_id": p.ID, "priced_count": p.PricedCount}, "baseline.already_priced", lotID, p.PricedCount)
+		}
+		baselineID = p.ID
+		if p.PositionsCount > 0 {
+			return baselineID, p.ID, nil
+		}
+	}
+	// Запрос упорядочивает предложения по числу позиций
+	for _, p := range proposals {
+		if !p.IsBaseline && p.PositionsCount > 0 {
+			return baselineID, p.ID, nil
+		}
+	}
+	return 0, 0, apierrors.NewValidationError("baseline.no_positions", lotID)
+}
+
+// loadAverages получает средние цены сопоставленных позиций каталога.
+func (s *EstimateService) loadAverages(ctx context.Context, lotID int64, positions []db.PositionItem, opts EstimateOptions) (map[averageKey]average, error) {
+	seen := make(map[int64]bool)
+	var catalogIDs []int64
+	for _, p := range positions {
+		if p.IsChapter || !p.CatalogPositionID.Valid || seen[p.CatalogPositionID.Int64] {
+			continue
+		}
+		seen[p.CatalogPositionID.Int64] = true
+		catalogIDs = append(catalogIDs, p.CatalogPositionID.Int64)
+	}
+	averages := make(map[averageKey]average)
+	if len(catalogIDs) == 0 {
+		return averages, nil
+	}
+
+	currencies, rates := s.rates.QueryArgs()
+	rows, err := s.store.ListCatalogAverageUnitCosts(ctx, db.ListCatalogAverageUnitCostsParams{
+		Currencies:         currencies,
+		Rates:              rates,
+		CatalogPositionIds: catalogIDs,
+		LotID:              lotID,
+		Since:              s.now().AddDate(0, -opts.WindowMonths, 0),
+		OrganizationID:     opts.OrganizationID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка расчёта средних цен каталога для лота %d: %w", lotID, err)
+	}
+	for _, row := range rows {
+		unitCost, err := decimal.NewFromString(row.AvgUnitCost.String)
+		if err != nil {
+			continue
+		}
+		averages[averageKey{catalogPositionID: row.CatalogPositionID, unitID: row.UnitID}] = average{
+			unitCost: unitCost,
+			count:    row.PricesCount,
+		}
+	}
+	return averages, nil
+}
+
+// buildEstimate оценивает позиции (заголовки разделов не выводятся).
+// Стоимость позиции округляется до копеек так же, как в SetEstimatedPositionCosts.
+func buildEstimate(positions []db.PositionItem, averages map[averageKey]average) *api_models.BaselineEstimateResponse {
+	response := &api_models.BaselineEstimateResponse{
+		Positions: make([]api_models.BaselineEstimatePosition, 0, len(positions)),
+	}
+	total := decimal.Zero
+	for _, p := range positions {
+		if p.IsChapter {
+			continue
+		}
+		item := api_models.BaselineEstimatePosition{
+			PositionKey: p.PositionKeyInProposal,
+			JobTitle:    p.JobTitleInProposal,
+			Status:      api_models.BaselineEstimateNotMatched,
+		}
+		if p.Quantity.Valid {
+			quantity := p.Quantity.String
+			item.Quantity = &quantity
+		}
+		if p.CatalogPositionID.Valid {
+			catalogID := p.CatalogPositionID.Int64
+			item.CatalogPositionID = &catalogID
+			item.Status = api_models.BaselineEstimateNoPrices
+			if avg, ok := averages[averageKey{catalogPositionID: catalogID, unitID: p.UnitID}]; ok {
+				item.Status = api_models.BaselineEstimateEstimated
+				item.UnitCost = api_models.MoneyPtr(avg.unitCost)
+				item.PricesCount = avg.count
+				if quantity, err := decimal.NewFromString(p.Quantity.String); err == nil {
+					cost := avg.unitCost.Mul(quantity).Round(2)
+					item.TotalCost = api_models.MoneyPtr(cost)
+					total = total.Add(cost)
+				}
+			}
+		}
+		if item.Status == api_models.BaselineEstimateEstimated {
+			response.EstimatedCount++
+		} else {
+			response.MissingCount++
+		}
+		response.Positions = append(response.Positions, item)
+	}
+	response.TotalCost = api_models.NewMoney(total)
+	return response
+}
+
+// save сохраняет оценку в одной транзакции и возвращает id baseline.
+func (s *EstimateService) save(ctx context.Context, lotID, baselineID, sourceID int64, positions []api_models.BaselineEstimatePosition) (int64, error) {
+	var keys, unitCosts []string
+	for _, p := range positions {
+		if p.UnitCost != nil {
+			keys = append(keys, p.PositionKey)
+			unitCosts = append(unitCosts, p.UnitCost.String())
+		}
+	}
+
+	err := s.store.ExecTx(ctx, func(qtx *db.Queries) error {
+		if baselineID == 0 {
+			initiator, err := s.entities.GetOrCreateContractor(ctx, qtx, initiatorINN, initiatorTitle, "N/A", "N/A")
+			if err != nil {
+				return fmt.Errorf("не удалось получить подрядчика baseline: %w", err)
+			}
+			proposal, err := qtx.UpsertProposal(ctx, db.UpsertProposalParams{
+				LotID:        lotID,
+				ContractorID: initiator.ID,
+				IsBaseline:   true,
+				Currency:     s.rates.Base(),
+			})
+			if err != nil {
+				return fmt.Errorf("не удалось создать baseline лота %d: %w", lotID, err)
+			}
+			baselineID = proposal.ID
+		}
+
+		if sourceID != baselineID {
+			copied, err := qtx.CopyProposalPositionsForEstimate(ctx, db.CopyProposalPositionsForEstimateParams{
+				TargetProposalID: baselineID,
+				SourceProposalID: sourceID,
+			})
+			if err != nil {
+				return fmt.Errorf("не удалось скопировать позиции предложения %d в baseline %d: %w", sourceID, baselineID, err)
+			}
+			s.logger.Debugf("В baseline %d скопировано %d позиций предложения %d", baselineID, copied, sourceID)
+		}
+
+		if len(keys) > 0 {
+			if _, err := qtx.SetEstimatedPositionCosts(ctx, db.SetEstimatedPositionCostsParams{
+				Currency:     s.rates.Base(),
+				PositionKeys: keys,
+				UnitCosts:    unitCosts,
+				ProposalID:   baselineID,
+			}); err != nil {
+				return fmt.Errorf("не удалось сохранить цены baseline %d: %w", baselineID, err)
+			}
+		}
+
+		if _, err := qtx.UpsertProposalAdditionalInfo(ctx, db.UpsertProposalAdditionalInfoParams{
+			ProposalID: baselineID,
+			InfoKey:    SourceInfoKey,
+			InfoValue:  sql.NullString{String: SourceCatalogEstimate, Valid: true},
+		}); err != nil {
+			return fmt.Errorf("не удалось пометить baseline %d: %w", baselineID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return baselineID, nil
+}
@@ -0,0 +1,227 @@
+package baseline
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/currency"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR BASELINE ESTIMATE (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. A tender arrives without the organizer's estimate and there is nothing to
+   compare the contractors' prices with
+2. An estimate overwrites the organizer's real baseline prices
+3. Prices per m² are averaged into a position measured in m³
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: choosing the list of works
+- GIVEN a baseline with positions and no prices THEN its positions are estimated
+- GIVEN an empty baseline (or none) THEN the contractor proposal with most positions is used
+- GIVEN a baseline with prices → ConflictError
+- GIVEN a lot without positions → ValidationError
+
+SCENARIO 2: estimating positions
+- GIVEN a matched position with prices in the same unit
+  THEN unit cost is the catalog average and total = unit cost × quantity
+- GIVEN prices only in another unit THEN status no_prices
+- GIVEN an unmatched position THEN status not_matched; chapters are skipped
+
+SCENARIO 3: dry run and saving
+- GIVEN dry_run THEN averages are read for the window and nothing is written
+- GIVEN a baseline without prices THEN prices are set in one transaction
+  and the baseline is marked baseline_source=catalog_estimate
+*/
+
+var testNow = time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+
+func setupTestService(t *testing.T) (*EstimateService, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	rates := currency.NewRates(config.CurrencyConfig{Base: "RUB", Rates: map[string]string{"USD": "90"}})
+	service := NewEstimateService(mockStore, testutil.NewMockLogger(), rates)
+	service.now = func() time.Time { return testNow }
+	return service, mockStore
+}
+
+func ns(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: true}
+}
+
+func ni(v int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: v, Valid: true}
+}
+
+// testPositions — раздел и три работы: с историей цен, с ценами только в
+// другой единице и без сопоставления.
+func testPositions(proposalID int64) []db.PositionItem {
+	return []db.PositionItem{
+		{ID: 1, ProposalID: proposalID, PositionKeyInProposal: "1", JobTitleInProposal: "Земляные работы", IsChapter: true},
+		{ID: 2, ProposalID: proposalID, PositionKeyInProposal: "1.1", JobTitleInProposal: "Разработка грунта", CatalogPositionID: ni(10), UnitID: ni(3), Quantity: ns("12.5")},
+		{ID: 3, ProposalID: proposalID, PositionKeyInProposal: "1.2", JobTitleInProposal: "Обратная засыпка", CatalogPositionID: ni(11), UnitID: ni(3), Quantity: ns("4")},
+		{ID: 4, ProposalID: proposalID, PositionKeyInProposal: "1.3", JobTitleInProposal: "Вывоз мусора", Quantity: ns("1")},
+	}
+}
+
+func testAverages() []db.ListCatalogAverageUnitCostsRow {
+	return []db.ListCatalogAverageUnitCostsRow{
+		{CatalogPositionID: 10, UnitID: ni(3), AvgUnitCost: ns("1000.50"), PricesCount: 4},
+		{CatalogPositionID: 11, UnitID: ni(5), AvgUnitCost: ns("300.00"), PricesCount: 2},
+	}
+}
+
+func expectVisibleLot(mockStore *db.MockStore, lotID int64) {
+	mockStore.EXPECT().GetLotByID(gomock.Any(), lotID).Return(db.Lot{ID: lotID, TenderID: 7}, nil)
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(7)).Return(db.Tender{ID: 7}, nil)
+}
+
+func TestPickSource(t *testing.T) {
+	baselineID, sourceID, err := pickSource(1, []db.ListLotProposalsForEstimateRow{
+		{ID: 5, IsBaseline: true, PositionsCount: 3},
+		{ID: 6, PositionsCount: 4},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), baselineID)
+	assert.Equal(t, int64(5), sourceID, "позиции baseline важнее позиций подрядчика")
+
+	baselineID, sourceID, err = pickSource(1, []db.ListLotProposalsForEstimateRow{
+		{ID: 5, IsBaseline: true},
+		{ID: 7, PositionsCount: 9},
+		{ID: 6, PositionsCount: 4},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), baselineID)
+	assert.Equal(t, int64(7), sourceID)
+
+	baselineID, sourceID, err = pickSource(1, []db.ListLotProposalsForEstimateRow{{ID: 6, PositionsCount: 4}})
+	require.NoError(t, err)
+	assert.Zero(t, baselineID)
+	assert.Equal(t, int64(6), sourceID)
+
+	_, _, err = pickSource(1, []db.ListLotProposalsForEstimateRow{{ID: 5, IsBaseline: true, PositionsCount: 3, PricedCount: 1}})
+	var conflictErr *apierrors.ConflictError
+	assert.ErrorAs(t, err, &conflictErr)
+
+	_, _, err = pickSource(1, []db.ListLotProposalsForEstimateRow{{ID: 5, IsBaseline: true}})
+	var validationErr *apierrors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestEstimate_DryRun(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	org := ni(1)
+
+	expectVisibleLot(mockStore, 100)
+	mockStore.EXPECT().ListLotProposalsForEstimate(gomock.Any(), int64(100)).Return([]db.ListLotProposalsForEstimateRow{
+		{ID: 6, PositionsCount: 3},
+	}, nil)
+	mockStore.EXPECT().ListProposalPositionsForEstimate(gomock.Any(), int64(6)).Return(testPositions(6), nil)
+	mockStore.EXPECT().ListCatalogAverageUnitCosts(gomock.Any(), db.ListCatalogAverageUnitCostsParams{
+		Currencies:         []string{"RUB", "USD"},
+		Rates:              []string{"1", "90"},
+		CatalogPositionIds: []int64{10, 11},
+		LotID:              100,
+		Since:              time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC),
+		OrganizationID:     org,
+	}).Return(testAverages(), nil)
+
+	resp, err := service.Estimate(context.Background(), 100, EstimateOptions{WindowMonths: 12, DryRun: true, OrganizationID: org})
+	require.NoError(t, err)
+
+	assert.True(t, resp.DryRun)
+	assert.Nil(t, resp.ProposalID)
+	assert.Equal(t, int64(6), resp.SourceProposalID)
+	assert.Equal(t, "RUB", resp.Currency)
+	require.Len(t, resp.Positions, 3, "раздел не выводится")
+
+	assert.Equal(t, api_models.BaselineEstimateEstimated, resp.Positions[0].Status)
+	assert.Equal(t, "1000.5", resp.Positions[0].UnitCost.String())
+	assert.Equal(t, "12506.25", resp.Positions[0].TotalCost.String())
+	assert.Equal(t, int64(4), resp.Positions[0].PricesCount)
+
+	assert.Equal(t, api_models.BaselineEstimateNoPrices, resp.Positions[1].Status, "цены только в другой единице")
+	assert.Nil(t, resp.Positions[1].UnitCost)
+	assert.Equal(t, api_models.BaselineEstimateNotMatched, resp.Positions[2].Status)
+
+	assert.Equal(t, 1, resp.EstimatedCount)
+	assert.Equal(t, 2, resp.MissingCount)
+	assert.Equal(t, "12506.25", resp.TotalCost.String())
+}
+
+func TestEstimate_FillsEmptyBaseline(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	expectVisibleLot(mockStore, 100)
+	mockStore.EXPECT().ListLotProposalsForEstimate(gomock.Any(), int64(100)).Return([]db.ListLotProposalsForEstimateRow{
+		{ID: 5, IsBaseline: true, PositionsCount: 3},
+		{ID: 6, PositionsCount: 3, PricedCount: 3},
+	}, nil)
+	mockStore.EXPECT().ListProposalPositionsForEstimate(gomock.Any(), int64(5)).Return(testPositions(5), nil)
+	mockStore.EXPECT().ListCatalogAverageUnitCosts(gomock.Any(), gomock.Any()).Return(testAverages(), nil)
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(*db.Queries) error) error {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer func() {
+				assert.NoError(t, mock.ExpectationsWereMet(), "sqlmock: unmet expectations")
+				sqlDB.Close()
+			}()
+
+			// Baseline уже есть и сам служит перечнем работ: без копирования позиций
+			mock.ExpectExec("UPDATE position_items").
+				WithArgs("RUB", sqlmock.AnyArg(), sqlmock.AnyArg(), int64(5)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectQuery("INSERT INTO proposal_additional_info").
+				WithArgs(int64(5), SourceInfoKey, SourceCatalogEstimate).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "proposal_id", "info_key", "info_value", "created_at", "updated_at"}).
+					AddRow(int64(1), int64(5), SourceInfoKey, SourceCatalogEstimate, testNow, testNow))
+			return fn(db.New(sqlDB))
+		},
+	)
+
+	resp, err := service.Estimate(context.Background(), 100, EstimateOptions{})
+	require.NoError(t, err)
+
+	require.NotNil(t, resp.ProposalID)
+	assert.Equal(t, int64(5), *resp.ProposalID)
+	assert.Equal(t, DefaultWindowMonths, resp.WindowMonths)
+	assert.False(t, resp.DryRun)
+}
+
+func TestEstimate_InvalidWindow(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.Estimate(context.Background(), 100, EstimateOptions{WindowMonths: MaxWindowMonths + 1})
+
+	var validationErr *apierrors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestEstimate_LotNotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetLotByID(gomock.Any(), int64(100)).Return(db.Lot{}, sql.ErrNoRows)
+
+	_, err := service.Estimate(context.Background(), 100, EstimateOptions{DryRun: true})
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
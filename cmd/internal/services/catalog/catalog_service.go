@@ -15,6 +15,14 @@
 //     - Пагинированное получение активных позиций для векторного поиска
 //     - Регистрация предложений о слиянии дубликатов
 //
+//  3. Закрепление авторитетных позиций:
+//     - Администратор помечает позицию как is_pinned
+//     - Флаг отдается в GET /catalog/active как supervised prior для ранжировщика
+//
+//  4. Вывод позиций из оборота (deprecation.go):
+//     - Позиция получает status='deprecated' и replaced_by_id
+//     - Матчинг и импорт прозрачно подставляют замену
+//
 // # Принцип работы с контекстом для RAG
 //
 // Для векторного поиска используется принцип "чистых описаний":
@@ -33,7 +41,7 @@
 //
 // # Используемые таблицы БД
 //
-//   - catalog_positions: основная таблица справочника (поля: id, standard_job_title, description, kind, status)
+//   - catalog_positions: основная таблица справочника (поля: id, standard_job_title, description, kind, status, is_pinned)
 //   - suggested_merges: таблица предложений о слиянии дубликатов
 package catalog
 
@@ -46,10 +54,12 @@ import (
 	"slices"
 	"strings"
 
-	"github.com/lib/pq"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/db/postgres"
 	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/util"
 	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
 )
 
@@ -58,8 +68,9 @@ import (
 // Этот сервис предоставляет высокоуровневые методы для работы с catalog_positions,
 // скрывая детали работы с БД и реализуя бизнес-логику валидации и обработки данных.
 type CatalogService struct {
-	store  db.Store       // Интерфейс для доступа к БД (SQLC-сгенерированные запросы)
-	logger logging.Logger // Логгер для отслеживания операций (интерфейс для тестируемости)
+	store     db.Store         // Интерфейс для доступа к БД (SQLC-сгенерированные запросы)
+	logger    logging.Logger   // Логгер для отслеживания операций (интерфейс для тестируемости)
+	publisher events.Publisher // Доменные события (merge.suggested)
 }
 
 // NewCatalogService создает новый экземпляр CatalogService.
@@ -67,12 +78,14 @@ type CatalogService struct {
 // Параметры:
 //   - store: интерфейс db.Store для выполнения операций с БД
 //   - logger: экземпляр логгера для записи событий и ошибок
+//   - publisher: получатель доменных событий (events.Noop — без публикации)
 //
 // Возвращает готовый к использованию сервис каталога.
-func NewCatalogService(store db.Store, logger logging.Logger) *CatalogService {
+func NewCatalogService(store db.Store, logger logging.Logger, publisher events.Publisher) *CatalogService {
 	return &CatalogService{
-		store:  store,
-		logger: logger,
+		store:     store,
+		logger:    logger,
+		publisher: publisher,
 	}
 }
 
@@ -143,7 +156,7 @@ func (s *CatalogService) GetUnindexedCatalogItems(
 	// Validate parameters
 	if limit <= 0 {
 		s.logger.Warnf("Получен некорректный limit: %d (должен быть > 0)", limit)
-		return nil, apierrors.NewValidationError("параметр limit должен быть положительным числом, получено: %d", limit)
+		return nil, apierrors.NewValidationError("request.limit_positive_got", limit)
 	}
 
 	// 1. Вызываем наш SQLC-запрос
@@ -196,33 +209,123 @@ func (s *CatalogService) GetUnindexedCatalogItems(
 //
 // # Возвращаемое значение
 //
+//   - *api_models.CatalogActivationReport: какие ID активированы, а какие пропущены и почему
 //   - error: ошибка БД или nil при успехе
 //
 // # Важные детали
 //
 //   - Пустой массив catalogIDs не считается ошибкой (просто логируется warning)
+//   - Несуществующие, уже активные и влитые/выведенные позиции пропускаются и попадают в отчёт
 //   - Операция идемпотентна: повторный вызов с теми же ID безопасен
-//   - Обновление атомарно (все ID в одной транзакции)
+//   - Проверка и обновление выполняются в одной транзакции (строки блокируются FOR UPDATE)
 func (s *CatalogService) MarkCatalogItemsAsActive(
 	ctx context.Context,
 	catalogIDs []int64,
-) error {
+) (*api_models.CatalogActivationReport, error) {
 
-	if len(catalogIDs) == 0 {
+	ids := uniqueIDs(catalogIDs)
+	if len(ids) == 0 {
 		s.logger.Warn("MarkCatalogItemsAsActive: получен пустой список ID, действие не требуется.")
-		return nil
+		return buildActivationReport(ids, nil, false), nil
 	}
 
-	// 1. Вызываем наш SQLC-запрос
-	err := s.store.SetCatalogStatusActive(ctx, catalogIDs)
+	var report *api_models.CatalogActivationReport
+	err := s.store.ExecTx(ctx, func(q *db.Queries) error {
+		statuses, err := q.GetCatalogPositionStatuses(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("ошибка БД: %w", err)
+		}
+
+		report = buildActivationReport(ids, statuses, false)
+		if len(report.Activated) == 0 {
+			return nil
+		}
 
+		// Guard в SQL повторяет проверку: активируются только позиции из report.Activated
+		if _, err := q.SetCatalogStatusActive(ctx, report.Activated); err != nil {
+			return fmt.Errorf("ошибка БД: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
 		s.logger.Errorf("Ошибка MarkCatalogItemsAsActive: %v", err)
-		return fmt.Errorf("ошибка БД: %w", err)
+		return nil, err
 	}
 
-	s.logger.Infof("Установлен статус 'active' для %d записей каталога", len(catalogIDs))
-	return nil
+	s.logger.Infof("Установлен статус 'active' для %d записей каталога (пропущено: не найдено %d, уже активны %d, влиты/выведены %d)",
+		len(report.Activated), len(report.NotFound), len(report.AlreadyActive), len(report.MergedOrInactive))
+	return report, nil
+}
+
+// ValidateCatalogActivation реализует dry-run массовой активации
+// (POST /api/v1/admin/catalog/activate?dry_run=true): возвращает тот же отчёт,
+// что и MarkCatalogItemsAsActive, но ничего не меняет.
+func (s *CatalogService) ValidateCatalogActivation(
+	ctx context.Context,
+	catalogIDs []int64,
+) (*api_models.CatalogActivationReport, error) {
+	ids := uniqueIDs(catalogIDs)
+	if len(ids) == 0 {
+		return buildActivationReport(ids, nil, true), nil
+	}
+
+	statuses, err := s.store.GetCatalogPositionStatuses(ctx, ids)
+	if err != nil {
+		s.logger.Errorf("Ошибка ValidateCatalogActivation: %v", err)
+		return nil, fmt.Errorf("ошибка БД: %w", err)
+	}
+	return buildActivationReport(ids, statuses, true), nil
+}
+
+// buildActivationReport раскладывает запрошенные ID по категориям отчёта.
+// Порядок ID внутри каждой категории совпадает с порядком во входе.
+func buildActivationReport(
+	ids []int64,
+	statuses []db.GetCatalogPositionStatusesRow,
+	dryRun bool,
+) *api_models.CatalogActivationReport {
+	report := &api_models.CatalogActivationReport{
+		DryRun:           dryRun,
+		Requested:        len(ids),
+		Activated:        []int64{},
+		NotFound:         []int64{},
+		AlreadyActive:    []int64{},
+		MergedOrInactive: []int64{},
+	}
+
+	byID := make(map[int64]db.GetCatalogPositionStatusesRow, len(statuses))
+	for _, row := range statuses {
+		byID[row.ID] = row
+	}
+
+	for _, id := range ids {
+		row, ok := byID[id]
+		switch {
+		case !ok:
+			report.NotFound = append(report.NotFound, id)
+		case row.MergedIntoID.Valid || row.Status == "deprecated" || row.Status == "archived":
+			report.MergedOrInactive = append(report.MergedOrInactive, id)
+		case row.Status == "active":
+			report.AlreadyActive = append(report.AlreadyActive, id)
+		default:
+			report.Activated = append(report.Activated, id)
+		}
+	}
+	return report
+}
+
+// uniqueIDs убирает повторы, сохраняя порядок первого появления.
+func uniqueIDs(ids []int64) []int64 {
+	seen := make(map[int64]struct{}, len(ids))
+	result := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		result = append(result, id)
+	}
+	return result
 }
 
 // SuggestMerge реализует POST /api/v1/merges/suggest.
@@ -258,6 +361,7 @@ func (s *CatalogService) MarkCatalogItemsAsActive(
 //   - Защита от self-merge: позиция не может быть слита сама с собой
 //   - Используется UPSERT: повторные предложения обновляют similarity_score
 //   - Не выполняет фактическое слияние - только регистрирует предложение
+//   - Публикует merge.suggested (напоминание в ленте уведомлений администраторов)
 func (s *CatalogService) SuggestMerge(
 	ctx context.Context,
 	req api_models.SuggestMergeRequest,
@@ -283,6 +387,11 @@ func (s *CatalogService) SuggestMerge(
 
 	s.logger.Infof("Успешно предложено/обновлено слияние: %d -> %d (Score: %.2f)",
 		req.DuplicatePositionID, req.MainPositionID, req.SimilarityScore)
+	events.Emit(ctx, s.publisher, s.logger, events.TypeMergeSuggested, req.MainPositionID, events.MergeSuggestedData{
+		MainPositionID:      req.MainPositionID,
+		DuplicatePositionID: req.DuplicatePositionID,
+		SimilarityScore:     req.SimilarityScore,
+	})
 	return nil
 }
 
@@ -310,17 +419,17 @@ func (s *CatalogService) ListPendingMerges(
 ) (*api_models.ListSuggestedMergesResponse, error) {
 
 	if page < 1 {
-		return nil, apierrors.NewValidationError("page должен быть >= 1")
+		return nil, apierrors.NewValidationError("catalog.page_positive")
 	}
 	if pageSize < 1 || pageSize > 500 {
-		return nil, apierrors.NewValidationError("page_size должен быть от 1 до 500")
+		return nil, apierrors.NewValidationError("catalog.page_size_range")
 	}
 
 	// Вычисляем offset в int64, чтобы избежать переполнения int32
 	// при больших значениях page (например, page=2_000_000_000, pageSize=500).
 	offset64 := (int64(page) - 1) * int64(pageSize)
 	if offset64 > math.MaxInt32 {
-		return nil, apierrors.NewValidationError("page слишком велик для данного page_size")
+		return nil, apierrors.NewValidationError("catalog.page_too_large")
 	}
 	offset := int32(offset64)
 
@@ -356,7 +465,7 @@ func (s *CatalogService) ListPendingMerges(
 		grp, exists := groupMap[mainID]
 		if !exists {
 			grp = &api_models.SuggestedMergeGroup{
-				MainPosition: catalogPositionToSummary(row.CatalogPosition),
+				MainPosition: PositionToSummary(row.CatalogPosition),
 			}
 			groupMap[mainID] = grp
 			groupOrder = append(groupOrder, mainID)
@@ -365,7 +474,7 @@ func (s *CatalogService) ListPendingMerges(
 		grp.Merges = append(grp.Merges, api_models.SuggestedMergeItem{
 			MergeID:         row.SuggestedMerge.ID,
 			SimilarityScore: row.SuggestedMerge.SimilarityScore,
-			Duplicate:       catalogPositionToSummary(row.CatalogPosition_2),
+			Duplicate:       PositionToSummary(row.CatalogPosition_2),
 			CreatedAt:       row.SuggestedMerge.CreatedAt,
 		})
 	}
@@ -382,19 +491,33 @@ func (s *CatalogService) ListPendingMerges(
 	}, nil
 }
 
-// catalogPositionToSummary конвертирует db.CatalogPosition в краткую API-модель.
-func catalogPositionToSummary(pos db.CatalogPosition) api_models.CatalogPositionSummary {
+// PositionToSummary конвертирует db.CatalogPosition в краткую API-модель.
+// Используется и вне каталога (привязка позиции к классификатору видов работ).
+func PositionToSummary(pos db.CatalogPosition) api_models.CatalogPositionSummary {
 	var desc *string
 	if pos.Description.Valid && strings.TrimSpace(pos.Description.String) != "" {
 		s := pos.Description.String
 		desc = &s
 	}
+	var workGroupCode *string
+	if pos.WorkGroupCode.Valid {
+		code := pos.WorkGroupCode.String
+		workGroupCode = &code
+	}
+	var replacedByID *int64
+	if pos.ReplacedByID.Valid {
+		id := pos.ReplacedByID.Int64
+		replacedByID = &id
+	}
 	return api_models.CatalogPositionSummary{
 		ID:               pos.ID,
 		StandardJobTitle: pos.StandardJobTitle,
 		Description:      desc,
 		Kind:             pos.Kind,
 		Status:           pos.Status,
+		IsPinned:         pos.IsPinned,
+		WorkGroupCode:    workGroupCode,
+		ReplacedByID:     replacedByID,
 	}
 }
 
@@ -417,11 +540,11 @@ func (s *CatalogService) RejectMerge(ctx context.Context, mergeID int64, rejecte
 	logger := s.logger.WithField("method", "RejectMerge").WithField("merge_id", mergeID)
 
 	if mergeID <= 0 {
-		return apierrors.NewValidationError("mergeID должен быть положительным")
+		return apierrors.NewValidationError("catalog.merge_id_positive")
 	}
 	rejectedBy = strings.TrimSpace(rejectedBy)
 	if rejectedBy == "" {
-		return apierrors.NewValidationError("rejectedBy не может быть пустым")
+		return apierrors.NewValidationError("catalog.rejected_by_empty")
 	}
 
 	// Атомарный UPDATE с guard status = 'PENDING' (защита от race condition).
@@ -436,12 +559,12 @@ func (s *CatalogService) RejectMerge(ctx context.Context, mergeID int64, rejecte
 			merge, getErr := s.store.GetSuggestedMergeByID(ctx, mergeID)
 			if getErr != nil {
 				if errors.Is(getErr, sql.ErrNoRows) {
-					return apierrors.NewNotFoundError("suggested_merge с id=%d не найден", mergeID)
+					return apierrors.NewNotFoundError("catalog.suggested_merge_not_found", mergeID)
 				}
 				return fmt.Errorf("ошибка получения merge %d: %w", mergeID, getErr)
 			}
 			return apierrors.NewValidationError(
-				"нельзя отклонить merge в статусе %s (ожидается PENDING)", merge.Status,
+				"catalog.reject_invalid_status", merge.Status,
 			)
 		}
 		return fmt.Errorf("ошибка RejectPendingMerge для merge %d: %w", mergeID, err)
@@ -492,7 +615,7 @@ func (s *CatalogService) ExecuteMerge(
 
 	// Валидация: executedBy обязателен (гарантируется JWT-middleware, но проверяем для безопасности)
 	if executedBy == "" {
-		return nil, apierrors.NewValidationError("executedBy не может быть пустым")
+		return nil, apierrors.NewValidationError("catalog.executed_by_empty")
 	}
 
 	// Определяем сценарий
@@ -511,49 +634,18 @@ func (s *CatalogService) ExecuteMerge(
 	err := s.store.ExecTx(ctx, func(q *db.Queries) error {
 		// 1. Атомарно переводим PENDING/APPROVED → EXECUTED (one-click merge)
 		var txErr error
-		merge, txErr = q.ExecuteMerge(ctx, db.ExecuteMergeParams{
-			ResolvedBy: sql.NullString{String: executedBy, Valid: true},
-			ID:         mergeID,
-		})
+		merge, txErr = claimMergeForExecution(ctx, q, mergeID, executedBy)
 		if txErr != nil {
-			if errors.Is(txErr, sql.ErrNoRows) {
-				// Нужно определить причину: не найдено или неверный статус
-				existing, checkErr := q.GetSuggestedMergeByID(ctx, mergeID)
-				if checkErr != nil {
-					if errors.Is(checkErr, sql.ErrNoRows) {
-						return apierrors.NewNotFoundError("предложение о слиянии с ID %d не найдено", mergeID)
-					}
-					return fmt.Errorf("ошибка GetSuggestedMergeByID: %w", checkErr)
-				}
-				return apierrors.NewValidationError(
-					"слияние %d не может быть выполнено: текущий статус=%s (ожидается PENDING/APPROVED)",
-					mergeID, existing.Status,
-				)
-			}
-			return fmt.Errorf("ошибка ExecuteMerge: %w", txErr)
+			return txErr
 		}
 
 		if !isRename {
 			// ===== Сценарий 1: Default Merge (B → A) =====
 			scenario = api_models.MergeScenarioDefault
 
-			mergedPos, mergeErr := q.MergeCatalogPosition(ctx, db.MergeCatalogPositionParams{
-				MasterID:    sql.NullInt64{Int64: merge.MainPositionID, Valid: true},
-				DuplicateID: merge.DuplicatePositionID,
-			})
+			mergedPos, mergeErr := mergeDuplicateIntoMain(ctx, q, merge)
 			if mergeErr != nil {
-				if errors.Is(mergeErr, sql.ErrNoRows) {
-					return diagnoseMergeFailure(ctx, q, merge)
-				}
-				return fmt.Errorf("ошибка MergeCatalogPosition: %w", mergeErr)
-			}
-
-			// Path Compression: перевешиваем все позиции, ранее влитые в B, напрямую на A
-			if flattenErr := q.FlattenMergeChain(ctx, db.FlattenMergeChainParams{
-				NewMasterID: sql.NullInt64{Int64: merge.MainPositionID, Valid: true},
-				OldMasterID: sql.NullInt64{Int64: merge.DuplicatePositionID, Valid: true},
-			}); flattenErr != nil {
-				return fmt.Errorf("ошибка FlattenMergeChain для дубликата %d: %w", merge.DuplicatePositionID, flattenErr)
+				return mergeErr
 			}
 
 			resultingPositionID = merge.MainPositionID // A остаётся активной
@@ -568,10 +660,9 @@ func (s *CatalogService) ExecuteMerge(
 			// 2a. Создаём новую позицию C
 			newPos, createErr := q.CreateSimpleCatalogPosition(ctx, newMainTitle)
 			if createErr != nil {
-				var pqErr *pq.Error
-				if errors.As(createErr, &pqErr) && pqErr.Code == "23505" {
+				if postgres.IsUniqueViolation(createErr) {
 					return apierrors.NewValidationError(
-						"позиция с названием %q уже существует в каталоге", newMainTitle,
+						"catalog.title_exists", newMainTitle,
 					)
 				}
 				return fmt.Errorf("ошибка CreateSimpleCatalogPosition: %w", createErr)
@@ -586,7 +677,7 @@ func (s *CatalogService) ExecuteMerge(
 			if mergeAErr != nil {
 				if errors.Is(mergeAErr, sql.ErrNoRows) {
 					return apierrors.NewValidationError(
-						"слияние невозможно: мастер-позиция %d уже deprecated или влита",
+						"catalog.merge_master_deprecated",
 						merge.MainPositionID,
 					)
 				}
@@ -601,7 +692,7 @@ func (s *CatalogService) ExecuteMerge(
 			if mergeBErr != nil {
 				if errors.Is(mergeBErr, sql.ErrNoRows) {
 					return apierrors.NewValidationError(
-						"слияние невозможно: дубликат %d уже deprecated или влит",
+						"catalog.merge_duplicate_deprecated",
 						merge.DuplicatePositionID,
 					)
 				}
@@ -664,6 +755,158 @@ func (s *CatalogService) ExecuteMerge(
 	}, nil
 }
 
+// claimMergeForExecution атомарно переводит заявку из PENDING/APPROVED в EXECUTED.
+// При sql.ErrNoRows различает «не найдена» (NotFoundError) и «неверный статус» (ValidationError).
+// Вызывается внутри транзакции.
+func claimMergeForExecution(
+	ctx context.Context,
+	q *db.Queries,
+	mergeID int64,
+	executedBy string,
+) (db.SuggestedMerge, error) {
+	merge, err := q.ExecuteMerge(ctx, db.ExecuteMergeParams{
+		ResolvedBy: sql.NullString{String: executedBy, Valid: true},
+		ID:         mergeID,
+	})
+	if err == nil {
+		return merge, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return merge, fmt.Errorf("ошибка ExecuteMerge: %w", err)
+	}
+
+	// Нужно определить причину: не найдено или неверный статус
+	existing, checkErr := q.GetSuggestedMergeByID(ctx, mergeID)
+	if checkErr != nil {
+		if errors.Is(checkErr, sql.ErrNoRows) {
+			return merge, apierrors.NewNotFoundError("catalog.merge_not_found", mergeID)
+		}
+		return merge, fmt.Errorf("ошибка GetSuggestedMergeByID: %w", checkErr)
+	}
+	return merge, apierrors.NewValidationError(
+		"catalog.merge_invalid_status",
+		mergeID, existing.Status,
+	)
+}
+
+// mergeDuplicateIntoMain вливает дубликат B в мастер A (Сценарий 1):
+// B получает merged_into_id = A и статус deprecated, а позиции, ранее
+// влитые в B, перевешиваются напрямую на A. Вызывается внутри транзакции.
+func mergeDuplicateIntoMain(ctx context.Context, q *db.Queries, merge db.SuggestedMerge) (db.CatalogPosition, error) {
+	mergedPos, err := q.MergeCatalogPosition(ctx, db.MergeCatalogPositionParams{
+		MasterID:    sql.NullInt64{Int64: merge.MainPositionID, Valid: true},
+		DuplicateID: merge.DuplicatePositionID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return mergedPos, diagnoseMergeFailure(ctx, q, merge)
+		}
+		return mergedPos, fmt.Errorf("ошибка MergeCatalogPosition: %w", err)
+	}
+
+	// Path Compression: перевешиваем все позиции, ранее влитые в B, напрямую на A
+	if err := q.FlattenMergeChain(ctx, db.FlattenMergeChainParams{
+		NewMasterID: sql.NullInt64{Int64: merge.MainPositionID, Valid: true},
+		OldMasterID: sql.NullInt64{Int64: merge.DuplicatePositionID, Valid: true},
+	}); err != nil {
+		return mergedPos, fmt.Errorf("ошибка FlattenMergeChain для дубликата %d: %w", merge.DuplicatePositionID, err)
+	}
+	return mergedPos, nil
+}
+
+// ApproveMerge реализует POST /api/v1/admin/merges/:id/approve.
+//
+// # Назначение
+//
+// Одобряет предложение о слиянии и сразу исполняет его по Сценарию 1 (B → A).
+// В отличие от ExecuteMerge, ссылки на дубликат не остаются в истории:
+// все position_items и записи matching_cache перевешиваются с B на A,
+// поэтому отчёты и повторный матчинг сразу видят каноническую позицию.
+//
+// # Логика выполнения (целиком в транзакции)
+//
+//  1. Атомарно переводит suggested_merge из PENDING/APPROVED в EXECUTED
+//  2. Помечает B как влитую в A (merged_into_id, deprecated) и сжимает цепочку слияний
+//  3. Перевешивает position_items и matching_cache с B на A
+//  4. Инвалидирует другие незавершённые заявки с участием B
+//
+// # Возвращаемое значение
+//
+//   - *api_models.ApproveMergeResponse: позиции и количество перевешенных строк
+//   - error: ValidationError, NotFoundError или ошибка БД
+func (s *CatalogService) ApproveMerge(
+	ctx context.Context,
+	mergeID int64,
+	approvedBy string,
+) (*api_models.ApproveMergeResponse, error) {
+	logger := s.logger.WithField("method", "ApproveMerge").WithField("merge_id", mergeID)
+
+	if mergeID <= 0 {
+		return nil, apierrors.NewValidationError("catalog.merge_id_positive")
+	}
+	approvedBy = strings.TrimSpace(approvedBy)
+	if approvedBy == "" {
+		return nil, apierrors.NewValidationError("catalog.approved_by_empty")
+	}
+
+	var (
+		merge          db.SuggestedMerge
+		retargetedPI   int64
+		retargetedMC   int64
+		mergedPosition db.CatalogPosition
+	)
+
+	err := s.store.ExecTx(ctx, func(q *db.Queries) error {
+		var txErr error
+		merge, txErr = claimMergeForExecution(ctx, q, mergeID, approvedBy)
+		if txErr != nil {
+			return txErr
+		}
+
+		mergedPosition, txErr = mergeDuplicateIntoMain(ctx, q, merge)
+		if txErr != nil {
+			return txErr
+		}
+
+		retargetedPI, txErr = q.RetargetPositionItems(ctx, db.RetargetPositionItemsParams{
+			MainID:      merge.MainPositionID,
+			DuplicateID: merge.DuplicatePositionID,
+		})
+		if txErr != nil {
+			return fmt.Errorf("ошибка RetargetPositionItems (%d → %d): %w",
+				merge.DuplicatePositionID, merge.MainPositionID, txErr)
+		}
+
+		retargetedMC, txErr = q.RetargetMatchingCache(ctx, db.RetargetMatchingCacheParams{
+			MainID:      merge.MainPositionID,
+			DuplicateID: merge.DuplicatePositionID,
+		})
+		if txErr != nil {
+			return fmt.Errorf("ошибка RetargetMatchingCache (%d → %d): %w",
+				merge.DuplicatePositionID, merge.MainPositionID, txErr)
+		}
+
+		return invalidateActionableMerges(ctx, q, []int64{merge.DuplicatePositionID})
+	})
+	if err != nil {
+		logger.Errorf("Ошибка одобрения слияния: %v", err)
+		return nil, err
+	}
+
+	logger.Infof("Merge одобрен пользователем %s: позиция %d влита в %d (position_items: %d, matching_cache: %d)",
+		approvedBy, merge.DuplicatePositionID, merge.MainPositionID, retargetedPI, retargetedMC)
+
+	return &api_models.ApproveMergeResponse{
+		MergeID:                 mergeID,
+		MainPositionID:          merge.MainPositionID,
+		DuplicatePositionID:     merge.DuplicatePositionID,
+		DuplicateStatus:         mergedPosition.Status,
+		RetargetedPositionItems: retargetedPI,
+		RetargetedCacheEntries:  retargetedMC,
+		ResolvedAt:              merge.ResolvedAt.Time,
+	}, nil
+}
+
 // ExecuteBatchMerge реализует POST /api/v1/admin/merges/execute-batch.
 //
 // # Назначение
@@ -700,17 +943,17 @@ func (s *CatalogService) ExecuteBatchMerge(
 
 	// === Валидация входных данных ===
 	if executedBy == "" {
-		return nil, apierrors.NewValidationError("executedBy не может быть пустым")
+		return nil, apierrors.NewValidationError("catalog.executed_by_empty")
 	}
 	if len(req.MergeIDs) == 0 {
-		return nil, apierrors.NewValidationError("merge_ids не может быть пустым")
+		return nil, apierrors.NewValidationError("catalog.merge_ids_empty")
 	}
 
 	// Проверка на дубликаты в merge_ids
 	seen := make(map[int64]struct{}, len(req.MergeIDs))
 	for _, id := range req.MergeIDs {
 		if _, exists := seen[id]; exists {
-			return nil, apierrors.NewValidationError("дубликат merge_id: %d", id)
+			return nil, apierrors.NewValidationError("catalog.duplicate_merge_id", id)
 		}
 		seen[id] = struct{}{}
 	}
@@ -722,7 +965,7 @@ func (s *CatalogService) ExecuteBatchMerge(
 
 	if !isMergeToNew && req.TargetPositionID == 0 {
 		return nil, apierrors.NewValidationError(
-			"target_position_id обязателен для Сценария 1 (без new_main_title)")
+			"catalog.target_position_required")
 	}
 
 	// === Транзакция ===
@@ -756,7 +999,7 @@ func (s *CatalogService) ExecuteBatchMerge(
 				}
 			}
 			return apierrors.NewValidationError(
-				"не удалось выполнить merge_ids %v: не найдены или имеют неверный статус",
+				"catalog.merge_ids_not_executable",
 				failedIDs,
 			)
 		}
@@ -786,7 +1029,7 @@ func (s *CatalogService) ExecuteBatchMerge(
 			// Проверяем что target входит в собранные позиции
 			if _, ok := positionSet[req.TargetPositionID]; !ok {
 				return apierrors.NewValidationError(
-					"target_position_id=%d не входит в позиции группы merge-записей",
+					"catalog.target_position_not_in_group",
 					req.TargetPositionID,
 				)
 			}
@@ -796,14 +1039,14 @@ func (s *CatalogService) ExecuteBatchMerge(
 			if targetErr != nil {
 				if errors.Is(targetErr, sql.ErrNoRows) {
 					return apierrors.NewValidationError(
-						"target_position_id=%d не найден в каталоге", req.TargetPositionID,
+						"catalog.target_position_not_found", req.TargetPositionID,
 					)
 				}
 				return fmt.Errorf("ошибка проверки target_position_id=%d: %w", req.TargetPositionID, targetErr)
 			}
 			if targetPos.Status != "active" || targetPos.MergedIntoID.Valid {
 				return apierrors.NewValidationError(
-					"target_position_id=%d имеет невалидный статус %q (merged_into_id=%v)",
+					"catalog.target_position_invalid_status",
 					req.TargetPositionID, targetPos.Status, targetPos.MergedIntoID,
 				)
 			}
@@ -820,7 +1063,7 @@ func (s *CatalogService) ExecuteBatchMerge(
 				if mergeErr != nil {
 					if errors.Is(mergeErr, sql.ErrNoRows) {
 						return apierrors.NewValidationError(
-							"позиция %d уже deprecated или влита", posID,
+							"catalog.position_already_deprecated", posID,
 						)
 					}
 					return fmt.Errorf("ошибка SetPositionMerged (pos=%d): %w", posID, mergeErr)
@@ -867,10 +1110,9 @@ func (s *CatalogService) ExecuteBatchMerge(
 			// Создаём новую позицию C
 			newPos, createErr := q.CreateSimpleCatalogPosition(ctx, newMainTitle)
 			if createErr != nil {
-				var pqErr *pq.Error
-				if errors.As(createErr, &pqErr) && pqErr.Code == "23505" {
+				if postgres.IsUniqueViolation(createErr) {
 					return apierrors.NewValidationError(
-						"позиция с названием %q уже существует в каталоге", newMainTitle,
+						"catalog.title_exists", newMainTitle,
 					)
 				}
 				return fmt.Errorf("ошибка CreateSimpleCatalogPosition: %w", createErr)
@@ -887,7 +1129,7 @@ func (s *CatalogService) ExecuteBatchMerge(
 				if mergeErr != nil {
 					if errors.Is(mergeErr, sql.ErrNoRows) {
 						return apierrors.NewValidationError(
-							"позиция %d уже deprecated или влита", posID,
+							"catalog.position_already_deprecated", posID,
 						)
 					}
 					return fmt.Errorf("ошибка SetPositionMerged (pos=%d): %w", posID, mergeErr)
@@ -960,7 +1202,7 @@ func diagnoseMergeFailure(
 	}
 	if dupErr == nil && (dup.MergedIntoID.Valid || dup.Status == "deprecated") {
 		return apierrors.NewValidationError(
-			"слияние невозможно: дубликат %d уже влит в другую позицию",
+			"catalog.merge_duplicate_merged",
 			merge.DuplicatePositionID,
 		)
 	}
@@ -972,13 +1214,13 @@ func diagnoseMergeFailure(
 	}
 	if masterErr == nil && (master.MergedIntoID.Valid || master.Status != "active") {
 		return apierrors.NewValidationError(
-			"слияние невозможно: мастер-позиция %d неактивна или влита в другую",
+			"catalog.merge_master_inactive",
 			merge.MainPositionID,
 		)
 	}
 
 	return apierrors.NewValidationError(
-		"слияние невозможно: дубликат %d или мастер-позиция %d не удовлетворяют условиям",
+		"catalog.merge_preconditions",
 		merge.DuplicatePositionID, merge.MainPositionID,
 	)
 }
@@ -1027,7 +1269,8 @@ func diagnoseMergeFailure(
 //   - Переиспользуется DTO UnmatchedPositionResponse
 //   - PositionItemID содержит catalog_id
 //   - Возвращаются только записи с kind='POSITION' и status='active'
-//   - Сортировка по id для детерминированной пагинации
+//   - Сначала закреплённые позиции (is_pinned), затем остальные; внутри — по id
+//     для детерминированной пагинации
 func (s *CatalogService) GetAllActiveCatalogItems(
 	ctx context.Context,
 	limit int32,
@@ -1037,11 +1280,11 @@ func (s *CatalogService) GetAllActiveCatalogItems(
 	// Validate parameters
 	if limit <= 0 {
 		s.logger.Warnf("Получен некорректный limit: %d (должен быть > 0)", limit)
-		return nil, apierrors.NewValidationError("параметр limit должен быть положительным числом, получено: %d", limit)
+		return nil, apierrors.NewValidationError("request.limit_positive_got", limit)
 	}
 	if offset < 0 {
 		s.logger.Warnf("Получен некорректный offset: %d (должен быть >= 0)", offset)
-		return nil, apierrors.NewValidationError("параметр offset не может быть отрицательным, получено: %d", offset)
+		return nil, apierrors.NewValidationError("catalog.offset_negative", offset)
 	}
 
 	// 1. Вызываем наш обновленный SQLC-запрос
@@ -1062,6 +1305,7 @@ func (s *CatalogService) GetAllActiveCatalogItems(
 			PositionItemID:     row.CatalogID, // 👈 Передаем ID каталога
 			JobTitleInProposal: row.StandardJobTitle,
 			RichContextString:  buildContextString(row.Description, row.StandardJobTitle), // <-- Чистая строка для чистого поиска
+			IsPinned:           row.IsPinned,                                              // Supervised prior для ранжировщика
 		})
 	}
 
@@ -1070,6 +1314,59 @@ func (s *CatalogService) GetAllActiveCatalogItems(
 	return response, nil
 }
 
+// GetActiveCatalogItemsPage — GetAllActiveCatalogItems с keyset-пагинацией
+// (GET /api/v1/catalog/active?cursor=). cursor — next_cursor предыдущей
+// страницы, пустая строка — первая страница. Запрашивается limit+1 строка:
+// лишняя означает, что есть следующая страница, и не возвращается.
+// Порядок тот же, что у GetAllActiveCatalogItems: сначала закреплённые,
+// поэтому курсор хранит и is_pinned последней строки.
+// Некорректный курсор — ValidationError.
+func (s *CatalogService) GetActiveCatalogItemsPage(
+	ctx context.Context,
+	limit int32,
+	cursor string,
+) (*api_models.ActiveCatalogItemsPage, error) {
+	if limit <= 0 {
+		return nil, apierrors.NewValidationError("request.limit_positive_got", limit)
+	}
+	after, ok, err := util.DecodeCursor(cursor)
+	if err != nil {
+		return nil, apierrors.NewValidationError("request.invalid_cursor")
+	}
+
+	dbRows, err := s.store.GetActiveCatalogItemsAfter(ctx, db.GetActiveCatalogItemsAfterParams{
+		// Первая страница начинается с закреплённых позиций
+		AfterPinned: !ok || after.Pinned,
+		AfterID:     after.ID,
+		PageLimit:   limit + 1,
+	})
+	if err != nil {
+		s.logger.Errorf("Ошибка GetActiveCatalogItemsAfter: %v", err)
+		return nil, fmt.Errorf("ошибка БД: %w", err)
+	}
+
+	page := &api_models.ActiveCatalogItemsPage{
+		Items: make([]api_models.UnmatchedPositionResponse, 0, min(len(dbRows), int(limit))),
+	}
+	if len(dbRows) > int(limit) {
+		dbRows = dbRows[:limit]
+		last := dbRows[len(dbRows)-1]
+		page.NextCursor = util.EncodeCursor(util.PageCursor{ID: last.CatalogID, Pinned: last.IsPinned})
+	}
+	for _, row := range dbRows {
+		page.Items = append(page.Items, api_models.UnmatchedPositionResponse{
+			PositionItemID:     row.CatalogID,
+			JobTitleInProposal: row.StandardJobTitle,
+			RichContextString:  buildContextString(row.Description, row.StandardJobTitle),
+			IsPinned:           row.IsPinned,
+		})
+	}
+
+	s.logger.Infof("Найдено %d АКТИВНЫХ записей каталога для поиска дубликатов (Limit: %d, после ID: %d)",
+		len(page.Items), limit, after.ID)
+	return page, nil
+}
+
 // GroupPositions реализует POST /api/v1/admin/merges/:id/group.
 //
 // # Назначение
@@ -1099,27 +1396,27 @@ func (s *CatalogService) GroupPositions(
 
 	// Валидация: mergeID должен быть положительным
 	if mergeID <= 0 {
-		return nil, apierrors.NewValidationError("mergeID должен быть положительным")
+		return nil, apierrors.NewValidationError("catalog.merge_id_positive")
 	}
 
 	// Валидация: executedBy обязателен
 	if executedBy == "" {
-		return nil, apierrors.NewValidationError("executedBy не может быть пустым")
+		return nil, apierrors.NewValidationError("catalog.executed_by_empty")
 	}
 
 	// Валидация: ровно одно из ParentID / NewParentTitle
 	if req.ParentID < 0 {
-		return nil, apierrors.NewValidationError("parent_id должен быть положительным")
+		return nil, apierrors.NewValidationError("catalog.parent_id_positive")
 	}
 	req.NewParentTitle = strings.TrimSpace(req.NewParentTitle)
 	hasParentID := req.ParentID > 0
 	hasNewTitle := req.NewParentTitle != ""
 
 	if hasParentID && hasNewTitle {
-		return nil, apierrors.NewValidationError("нельзя указать одновременно parent_id и new_parent_title")
+		return nil, apierrors.NewValidationError("catalog.parent_both")
 	}
 	if !hasParentID && !hasNewTitle {
-		return nil, apierrors.NewValidationError("необходимо указать parent_id или new_parent_title")
+		return nil, apierrors.NewValidationError("catalog.parent_required")
 	}
 
 	var merge db.SuggestedMerge
@@ -1137,12 +1434,12 @@ func (s *CatalogService) GroupPositions(
 				existing, checkErr := q.GetSuggestedMergeByID(ctx, mergeID)
 				if checkErr != nil {
 					if errors.Is(checkErr, sql.ErrNoRows) {
-						return apierrors.NewNotFoundError("предложение о слиянии с ID %d не найдено", mergeID)
+						return apierrors.NewNotFoundError("catalog.merge_not_found", mergeID)
 					}
 					return fmt.Errorf("ошибка GetSuggestedMergeByID: %w", checkErr)
 				}
 				return apierrors.NewValidationError(
-					"группировка %d невозможна: текущий статус=%s (ожидается PENDING/APPROVED)",
+					"catalog.group_invalid_status",
 					mergeID, existing.Status,
 				)
 			}
@@ -1167,11 +1464,7 @@ func (s *CatalogService) GroupPositions(
 				return conflictErr
 			}
 			if len(conflicts) > 0 {
-				return apierrors.NewConflictError(
-					fmt.Sprintf("%d из %d позиций уже входит в группу. Передайте force=true для переноса.",
-						len(conflicts), len(positionIDs)),
-					conflicts,
-				)
+				return apierrors.NewConflictErrorf(conflicts, "catalog.positions_already_grouped", len(conflicts), len(positionIDs))
 			}
 		}
 
@@ -1183,7 +1476,7 @@ func (s *CatalogService) GroupPositions(
 		if mainErr != nil {
 			if errors.Is(mainErr, sql.ErrNoRows) {
 				return apierrors.NewValidationError(
-					"группировка невозможна: позиция %d deprecated или влита",
+					"catalog.group_position_deprecated",
 					merge.MainPositionID,
 				)
 			}
@@ -1197,7 +1490,7 @@ func (s *CatalogService) GroupPositions(
 		if dupErr != nil {
 			if errors.Is(dupErr, sql.ErrNoRows) {
 				return apierrors.NewValidationError(
-					"группировка невозможна: позиция %d deprecated или влита",
+					"catalog.group_position_deprecated",
 					merge.DuplicatePositionID,
 				)
 			}
@@ -1236,10 +1529,9 @@ func (s *CatalogService) resolveParentID(
 	if newTitle != "" {
 		parent, createErr := q.CreateParentCatalogPosition(ctx, newTitle)
 		if createErr != nil {
-			var pqErr *pq.Error
-			if errors.As(createErr, &pqErr) && pqErr.Code == "23505" {
+			if postgres.IsUniqueViolation(createErr) {
 				return 0, apierrors.NewValidationError(
-					"родительская позиция с названием %q уже существует",
+					"catalog.parent_title_exists",
 					newTitle,
 				)
 			}
@@ -1251,30 +1543,30 @@ func (s *CatalogService) resolveParentID(
 	parent, getErr := q.GetCatalogPositionByID(ctx, parentID)
 	if getErr != nil {
 		if errors.Is(getErr, sql.ErrNoRows) {
-			return 0, apierrors.NewNotFoundError("родительская позиция с ID %d не найдена", parentID)
+			return 0, apierrors.NewNotFoundError("catalog.parent_not_found", parentID)
 		}
 		return 0, fmt.Errorf("ошибка GetCatalogPositionByID (parent=%d): %w", parentID, getErr)
 	}
 	if parent.Status == "deprecated" {
 		return 0, apierrors.NewValidationError(
-			"родительская позиция %d имеет статус deprecated", parentID,
+			"catalog.parent_deprecated", parentID,
 		)
 	}
 	if parent.MergedIntoID.Valid {
 		return 0, apierrors.NewValidationError(
-			"родительская позиция %d влита в другую позицию", parentID,
+			"catalog.parent_merged", parentID,
 		)
 	}
 	if parent.Kind != "GROUP_TITLE" {
 		return 0, apierrors.NewValidationError(
-			"родительская позиция %d должна иметь kind=GROUP_TITLE (текущий kind=%s)",
+			"catalog.parent_not_group",
 			parentID, parent.Kind,
 		)
 	}
 	for _, id := range forbiddenIDs {
 		if parent.ID == id {
 			return 0, apierrors.NewValidationError(
-				"родительская позиция %d не может совпадать с группируемыми позициями",
+				"catalog.parent_in_group",
 				parentID,
 			)
 		}
@@ -1353,36 +1645,36 @@ func (s *CatalogService) GroupBatchPositions(
 		WithField("merge_ids_count", len(req.MergeIDs))
 
 	if executedBy == "" {
-		return nil, apierrors.NewValidationError("executedBy не может быть пустым")
+		return nil, apierrors.NewValidationError("catalog.executed_by_empty")
 	}
 	if len(req.MergeIDs) == 0 {
-		return nil, apierrors.NewValidationError("merge_ids не может быть пустым")
+		return nil, apierrors.NewValidationError("catalog.merge_ids_empty")
 	}
 
 	// Проверка на дубликаты и валидность merge_ids
 	seen := make(map[int64]struct{}, len(req.MergeIDs))
 	for _, id := range req.MergeIDs {
 		if id <= 0 {
-			return nil, apierrors.NewValidationError("merge_id должен быть положительным, получено: %d", id)
+			return nil, apierrors.NewValidationError("catalog.merge_id_positive_got", id)
 		}
 		if _, exists := seen[id]; exists {
-			return nil, apierrors.NewValidationError("дубликат merge_id: %d", id)
+			return nil, apierrors.NewValidationError("catalog.duplicate_merge_id", id)
 		}
 		seen[id] = struct{}{}
 	}
 
 	if req.ParentID < 0 {
-		return nil, apierrors.NewValidationError("parent_id должен быть положительным")
+		return nil, apierrors.NewValidationError("catalog.parent_id_positive")
 	}
 	req.NewParentTitle = strings.TrimSpace(req.NewParentTitle)
 	hasParentID := req.ParentID > 0
 	hasNewTitle := req.NewParentTitle != ""
 
 	if hasParentID && hasNewTitle {
-		return nil, apierrors.NewValidationError("нельзя указать одновременно parent_id и new_parent_title")
+		return nil, apierrors.NewValidationError("catalog.parent_both")
 	}
 	if !hasParentID && !hasNewTitle {
-		return nil, apierrors.NewValidationError("необходимо указать parent_id или new_parent_title")
+		return nil, apierrors.NewValidationError("catalog.parent_required")
 	}
 
 	var finalParentID int64
@@ -1412,7 +1704,7 @@ func (s *CatalogService) GroupBatchPositions(
 				}
 			}
 			return apierrors.NewValidationError(
-				"не удалось сгруппировать merge_ids %v: не найдены или имеют неверный статус",
+				"catalog.merge_ids_not_groupable",
 				failedIDs,
 			)
 		}
@@ -1447,11 +1739,7 @@ func (s *CatalogService) GroupBatchPositions(
 				return conflictErr
 			}
 			if len(conflicts) > 0 {
-				return apierrors.NewConflictError(
-					fmt.Sprintf("%d из %d позиций уже входит в группу. Передайте force=true для переноса.",
-						len(conflicts), len(sortedPositionIDs)),
-					conflicts,
-				)
+				return apierrors.NewConflictErrorf(conflicts, "catalog.positions_already_grouped", len(conflicts), len(sortedPositionIDs))
 			}
 		}
 
@@ -1464,7 +1752,7 @@ func (s *CatalogService) GroupBatchPositions(
 			if setErr != nil {
 				if errors.Is(setErr, sql.ErrNoRows) {
 					return apierrors.NewValidationError(
-						"группировка невозможна: позиция %d deprecated или влита", posID,
+						"catalog.group_position_deprecated", posID,
 					)
 				}
 				return fmt.Errorf("ошибка SetPositionParent (pos=%d): %w", posID, setErr)
@@ -1500,10 +1788,10 @@ func (s *CatalogService) ListGroups(
 	limit, offset int32,
 ) (*api_models.ListGroupsResponse, error) {
 	if limit <= 0 {
-		return nil, apierrors.NewValidationError("параметр limit должен быть положительным числом, получено: %d", limit)
+		return nil, apierrors.NewValidationError("request.limit_positive_got", limit)
 	}
 	if offset < 0 {
-		return nil, apierrors.NewValidationError("параметр offset не может быть отрицательным, получено: %d", offset)
+		return nil, apierrors.NewValidationError("catalog.offset_negative", offset)
 	}
 
 	total, err := s.store.CountGroups(ctx)
@@ -1546,7 +1834,7 @@ func (s *CatalogService) ListGroupChildren(
 	parentID int64,
 ) (*api_models.ListGroupChildrenResponse, error) {
 	if parentID <= 0 {
-		return nil, apierrors.NewValidationError("параметр id должен быть положительным числом, получено: %d", parentID)
+		return nil, apierrors.NewValidationError("catalog.id_positive", parentID)
 	}
 
 	rows, err := s.store.ListGroupChildren(ctx, sql.NullInt64{Int64: parentID, Valid: true})
@@ -1568,6 +1856,7 @@ func (s *CatalogService) ListGroupChildren(
 			Description:      desc,
 			Kind:             row.Kind,
 			Status:           row.Status,
+			IsPinned:         row.IsPinned,
 		})
 	}
 
@@ -1587,11 +1876,11 @@ func (s *CatalogService) UngroupPosition(
 	logger := s.logger.WithField("method", "UngroupPosition").WithField("position_id", positionID)
 
 	if positionID <= 0 {
-		return apierrors.NewValidationError("параметр id должен быть положительным, получено: %d", positionID)
+		return apierrors.NewValidationError("request.id_positive_got", positionID)
 	}
 	executedBy = strings.TrimSpace(executedBy)
 	if executedBy == "" {
-		return apierrors.NewValidationError("executedBy не может быть пустым")
+		return apierrors.NewValidationError("catalog.executed_by_empty")
 	}
 
 	err := s.store.ExecTx(ctx, func(q *db.Queries) error {
@@ -1601,7 +1890,7 @@ func (s *CatalogService) UngroupPosition(
 		if txErr != nil {
 			if errors.Is(txErr, sql.ErrNoRows) {
 				return apierrors.NewValidationError(
-					"позиция %d не найдена или не состоит в группе", positionID,
+					"catalog.position_not_grouped", positionID,
 				)
 			}
 			return fmt.Errorf("ошибка UngroupPosition(%d): %w", positionID, txErr)
@@ -1626,3 +1915,84 @@ func (s *CatalogService) UngroupPosition(
 	logger.Infof("Позиция %d исключена из группы (оператор: %s)", positionID, executedBy)
 	return nil
 }
+
+// SetCatalogPositionPinned реализует PATCH /api/v1/admin/catalog/positions/:id/pin.
+// Закрепляет (pinned=true) или открепляет (pinned=false) позицию каталога.
+// Закреплённые позиции отдаются Python-ранжировщику с флагом is_pinned
+// и используются им как предпочтительные кандидаты при матчинге.
+//
+// Закрепить можно только активную позицию kind='POSITION', не влитую в другую.
+// Открепление допускается для позиции в любом статусе.
+func (s *CatalogService) SetCatalogPositionPinned(
+	ctx context.Context,
+	positionID int64,
+	pinned bool,
+	executedBy string,
+) (*api_models.CatalogPositionSummary, error) {
+	logger := s.logger.WithField("method", "SetCatalogPositionPinned").WithField("position_id", positionID)
+
+	if positionID <= 0 {
+		return nil, apierrors.NewValidationError("request.id_positive_got", positionID)
+	}
+	executedBy = strings.TrimSpace(executedBy)
+	if executedBy == "" {
+		return nil, apierrors.NewValidationError("catalog.executed_by_empty")
+	}
+
+	pos, err := s.store.SetCatalogPositionPinned(ctx, db.SetCatalogPositionPinnedParams{
+		IsPinned: pinned,
+		PinnedBy: sql.NullString{String: executedBy, Valid: true},
+		ID:       positionID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Различаем "не найдена" и "не подходит для закрепления"
+			existing, getErr := s.store.GetCatalogPositionByID(ctx, positionID)
+			if getErr != nil {
+				if errors.Is(getErr, sql.ErrNoRows) {
+					return nil, apierrors.NewNotFoundError("common.catalog_position_not_found", positionID)
+				}
+				return nil, fmt.Errorf("ошибка получения позиции %d: %w", positionID, getErr)
+			}
+			return nil, apierrors.NewValidationError(
+				"catalog.pin_not_allowed",
+				positionID, existing.Kind, existing.Status,
+			)
+		}
+		logger.Errorf("Ошибка SetCatalogPositionPinned: %v", err)
+		return nil, fmt.Errorf("ошибка SetCatalogPositionPinned(%d): %w", positionID, err)
+	}
+
+	summary := PositionToSummary(pos)
+	logger.Infof("Позиция %d: is_pinned=%t (оператор: %s)", positionID, pinned, executedBy)
+	return &summary, nil
+}
+
+// ListPinnedCatalogPositions реализует GET /api/v1/admin/catalog/pinned.
+// Возвращает все закреплённые позиции каталога. Их немного, поэтому без пагинации.
+func (s *CatalogService) ListPinnedCatalogPositions(ctx context.Context) (*api_models.ListPinnedCatalogPositionsResponse, error) {
+	rows, err := s.store.ListPinnedCatalogPositions(ctx)
+	if err != nil {
+		s.logger.Errorf("Ошибка ListPinnedCatalogPositions: %v", err)
+		return nil, fmt.Errorf("ошибка БД: %w", err)
+	}
+
+	positions := make([]api_models.CatalogPositionSummary, 0, len(rows))
+	for _, row := range rows {
+		var desc *string
+		if row.Description.Valid {
+			s := row.Description.String
+			desc = &s
+		}
+		positions = append(positions, api_models.CatalogPositionSummary{
+			ID:               row.ID,
+			StandardJobTitle: row.StandardJobTitle,
+			Description:      desc,
+			Kind:             row.Kind,
+			Status:           row.Status,
+			IsPinned:         row.IsPinned,
+		})
+	}
+
+	return &api_models.ListPinnedCatalogPositionsResponse{Positions: positions, Total: len(positions)}, nil
+}
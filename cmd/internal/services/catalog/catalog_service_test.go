@@ -17,6 +17,7 @@ import (
 	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
 	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
 )
 
@@ -50,14 +51,26 @@ SCENARIO 1: ListCatalogPositionsForEmbedding
   WHEN ListCatalogPositionsForEmbedding is called
   THEN wrapped DB error is returned
 
-SCENARIO 2: MarkCatalogItemsAsActive
-- GIVEN a list of catalog IDs
+SCENARIO 2: MarkCatalogItemsAsActive / ValidateCatalogActivation
+- GIVEN a list of catalog IDs in pending_indexing
   WHEN MarkCatalogItemsAsActive is called
-  THEN store.SetCatalogStatusActive is called with those IDs
+  THEN statuses are locked and SetCatalogStatusActive is called with those IDs
+
+- GIVEN IDs that don't exist, are already active, or are merged/deprecated
+  WHEN MarkCatalogItemsAsActive is called
+  THEN they are skipped and reported by category, only eligible IDs are updated
+
+- GIVEN only ineligible IDs
+  WHEN MarkCatalogItemsAsActive is called
+  THEN no UPDATE is issued
+
+- GIVEN the same mixed IDs (with duplicates)
+  WHEN ValidateCatalogActivation (dry-run) is called
+  THEN the same report is returned with dry_run=true and nothing is updated
 
 - GIVEN an empty list of catalog IDs
   WHEN MarkCatalogItemsAsActive is called
-  THEN no DB call is made and nil is returned
+  THEN no DB call is made and an empty report is returned
 
 - GIVEN a DB error
   WHEN MarkCatalogItemsAsActive is called
@@ -89,6 +102,16 @@ SCENARIO 4: GetAllActiveCatalogItems
   WHEN GetAllActiveCatalogItems is called
   THEN wrapped DB error is returned
 
+- GIVEN an empty cursor, then next_cursor of the previous page
+  WHEN GetActiveCatalogItemsPage is called
+  THEN limit+1 rows after the cursor (is_pinned, id) are requested,
+  pinned items first
+  AND next_cursor is set only while an extra row exists
+
+- GIVEN a malformed cursor
+  WHEN GetActiveCatalogItemsPage is called
+  THEN ValidationError is returned without DB call
+
 SCENARIO 5: buildContextString (helper)
 - GIVEN a valid description
   WHEN context string is built
@@ -205,6 +228,45 @@ SCENARIO 9: ExecuteBatchMerge (Batch Merge-to-New, Scenario 2)
 - GIVEN missing target_position_id but no new_main_title
   WHEN ExecuteBatchMerge is called
   THEN ValidationError about required target_position_id
+
+SCENARIO 10: SetCatalogPositionPinned
+- GIVEN a pending merge with a pinned duplicate
+  WHEN ListPendingMerges is called
+  THEN is_pinned is returned for both positions
+
+- GIVEN an active POSITION
+  WHEN SetCatalogPositionPinned(pinned=true) is called
+  THEN summary with is_pinned=true is returned
+
+- GIVEN a position id that doesn't exist
+  WHEN SetCatalogPositionPinned is called
+  THEN NotFoundError is returned
+
+- GIVEN a deprecated position
+  WHEN SetCatalogPositionPinned(pinned=true) is called
+  THEN ValidationError is returned
+
+- GIVEN empty executedBy or non-positive id
+  WHEN SetCatalogPositionPinned is called
+  THEN ValidationError is returned without DB call
+
+SCENARIO 11: ApproveMerge
+- GIVEN a PENDING merge B → A
+  WHEN ApproveMerge is called
+  THEN B is deprecated, position_items and matching_cache are re-pointed to A
+  AND the response reports how many rows were re-pointed
+
+- GIVEN a merge that is already REJECTED
+  WHEN ApproveMerge is called
+  THEN ValidationError is returned and nothing is re-pointed
+
+- GIVEN re-pointing position_items fails
+  WHEN ApproveMerge is called
+  THEN the DB error is returned (transaction rolled back)
+
+- GIVEN empty approvedBy or non-positive id
+  WHEN ApproveMerge is called
+  THEN ValidationError is returned without DB call
 */
 
 // setupTestService creates a CatalogService with mock store for unit testing.
@@ -217,8 +279,9 @@ func setupTestService(t *testing.T) (*CatalogService, *db.MockStore) {
 	logger := testutil.NewMockLogger()
 
 	service := &CatalogService{
-		store:  mockStore,
-		logger: logger,
+		store:     mockStore,
+		logger:    logger,
+		publisher: events.Noop{},
 	}
 
 	return service, mockStore
@@ -397,36 +460,118 @@ func TestListCatalogPositionsForEmbedding_DBError(t *testing.T) {
 // MarkCatalogItemsAsActive TESTS
 // =============================================================================
 
+// catalogStatusColumns - колонки GetCatalogPositionStatuses.
+var catalogStatusColumns = []string{"id", "status", "merged_into_id"}
+
 func TestMarkCatalogItemsAsActive_Success(t *testing.T) {
 	service, mockStore := setupTestService(t)
 
-	// GIVEN valid catalog IDs
+	// GIVEN valid catalog IDs in pending_indexing
 	ids := []int64{1, 2, 3}
-	mockStore.EXPECT().
-		SetCatalogStatusActive(gomock.Any(), ids).
-		Return(nil)
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT .+ FROM catalog_positions").
+				WithArgs(sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows(catalogStatusColumns).
+					AddRow(int64(1), "pending_indexing", nil).
+					AddRow(int64(2), "pending_indexing", nil).
+					AddRow(int64(3), "pending_indexing", nil))
+			mock.ExpectQuery("UPDATE catalog_positions").
+				WithArgs(sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).
+					AddRow(int64(1)).AddRow(int64(2)).AddRow(int64(3)))
+		}),
+	)
 
 	// WHEN
-	err := service.MarkCatalogItemsAsActive(context.Background(), ids)
+	report, err := service.MarkCatalogItemsAsActive(context.Background(), ids)
 
-	// THEN success
+	// THEN all IDs are activated
+	require.NoError(t, err)
+	assert.False(t, report.DryRun)
+	assert.Equal(t, 3, report.Requested)
+	assert.Equal(t, ids, report.Activated)
+	assert.Empty(t, report.NotFound)
+}
+
+func TestMarkCatalogItemsAsActive_SkipsAndReportsBadIDs(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	// GIVEN 1 eligible, 2 already active, 3 merged, 4 archived, 5 missing
+	ids := []int64{5, 1, 2, 3, 4}
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT .+ FROM catalog_positions").
+				WithArgs(sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows(catalogStatusColumns).
+					AddRow(int64(1), "pending_indexing", nil).
+					AddRow(int64(2), "active", nil).
+					AddRow(int64(3), "deprecated", int64(10)).
+					AddRow(int64(4), "archived", nil))
+			// Обновляется только позиция 1
+			mock.ExpectQuery("UPDATE catalog_positions").
+				WithArgs(sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)))
+		}),
+	)
+
+	// WHEN
+	report, err := service.MarkCatalogItemsAsActive(context.Background(), ids)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, 5, report.Requested)
+	assert.Equal(t, []int64{1}, report.Activated)
+	assert.Equal(t, []int64{5}, report.NotFound)
+	assert.Equal(t, []int64{2}, report.AlreadyActive)
+	assert.Equal(t, []int64{3, 4}, report.MergedOrInactive)
+}
+
+func TestMarkCatalogItemsAsActive_NothingEligible_NoUpdate(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	// GIVEN only an already active position
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT .+ FROM catalog_positions").
+				WithArgs(sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows(catalogStatusColumns).
+					AddRow(int64(42), "active", nil))
+			// UPDATE не ожидается
+		}),
+	)
+
+	// WHEN
+	report, err := service.MarkCatalogItemsAsActive(context.Background(), []int64{42})
+
+	// THEN
 	require.NoError(t, err)
+	assert.Empty(t, report.Activated)
+	assert.Equal(t, []int64{42}, report.AlreadyActive)
 }
 
-func TestMarkCatalogItemsAsActive_SingleID(t *testing.T) {
+func TestValidateCatalogActivation_DryRun(t *testing.T) {
 	service, mockStore := setupTestService(t)
 
-	// GIVEN single catalog ID
-	ids := []int64{42}
+	// GIVEN mixed IDs with a duplicate — only a read is expected
 	mockStore.EXPECT().
-		SetCatalogStatusActive(gomock.Any(), ids).
-		Return(nil)
+		GetCatalogPositionStatuses(gomock.Any(), []int64{1, 2, 7}).
+		Return([]db.GetCatalogPositionStatusesRow{
+			{ID: 1, Status: "pending_indexing"},
+			{ID: 2, Status: "active"},
+		}, nil)
 
 	// WHEN
-	err := service.MarkCatalogItemsAsActive(context.Background(), ids)
+	report, err := service.ValidateCatalogActivation(context.Background(), []int64{1, 2, 1, 7})
 
-	// THEN success
+	// THEN
 	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 3, report.Requested)
+	assert.Equal(t, []int64{1}, report.Activated)
+	assert.Equal(t, []int64{2}, report.AlreadyActive)
+	assert.Equal(t, []int64{7}, report.NotFound)
+	assert.Empty(t, report.MergedOrInactive)
 }
 
 func TestMarkCatalogItemsAsActive_EmptyList(t *testing.T) {
@@ -434,10 +579,12 @@ func TestMarkCatalogItemsAsActive_EmptyList(t *testing.T) {
 
 	// GIVEN empty list — no DB call expected (no EXPECT on mockStore)
 	// WHEN
-	err := service.MarkCatalogItemsAsActive(context.Background(), []int64{})
+	report, err := service.MarkCatalogItemsAsActive(context.Background(), []int64{})
 
-	// THEN nil returned without error
+	// THEN empty report returned without error
 	require.NoError(t, err)
+	assert.Equal(t, 0, report.Requested)
+	assert.Empty(t, report.Activated)
 }
 
 func TestMarkCatalogItemsAsActive_NilList(t *testing.T) {
@@ -445,10 +592,11 @@ func TestMarkCatalogItemsAsActive_NilList(t *testing.T) {
 
 	// GIVEN nil list
 	// WHEN
-	err := service.MarkCatalogItemsAsActive(context.Background(), nil)
+	report, err := service.MarkCatalogItemsAsActive(context.Background(), nil)
 
-	// THEN nil returned without error
+	// THEN empty report returned without error
 	require.NoError(t, err)
+	assert.NotNil(t, report.Activated)
 }
 
 func TestMarkCatalogItemsAsActive_DBError(t *testing.T) {
@@ -457,15 +605,20 @@ func TestMarkCatalogItemsAsActive_DBError(t *testing.T) {
 	// GIVEN DB returns error
 	dbErr := errors.New("deadlock detected")
 	ids := []int64{1, 2}
-	mockStore.EXPECT().
-		SetCatalogStatusActive(gomock.Any(), ids).
-		Return(dbErr)
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT .+ FROM catalog_positions").
+				WithArgs(sqlmock.AnyArg()).
+				WillReturnError(dbErr)
+		}),
+	)
 
 	// WHEN
-	err := service.MarkCatalogItemsAsActive(context.Background(), ids)
+	report, err := service.MarkCatalogItemsAsActive(context.Background(), ids)
 
 	// THEN wrapped DB error
 	require.Error(t, err)
+	assert.Nil(t, report)
 	assert.ErrorIs(t, err, dbErr)
 	assert.Contains(t, err.Error(), "ошибка БД")
 }
@@ -666,6 +819,28 @@ func TestGetAllActiveCatalogItems_WithOffset(t *testing.T) {
 	assert.Equal(t, int64(50), result[0].PositionItemID)
 }
 
+func TestGetAllActiveCatalogItems_PinnedFlagPropagated(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	// GIVEN one pinned and one regular item
+	dbRows := []db.GetActiveCatalogItemsRow{
+		{CatalogID: 1, StandardJobTitle: "бетонирование", IsPinned: true},
+		{CatalogID: 2, StandardJobTitle: "армирование", IsPinned: false},
+	}
+	mockStore.EXPECT().
+		GetActiveCatalogItems(gomock.Any(), gomock.Any()).
+		Return(dbRows, nil)
+
+	// WHEN
+	result, err := service.GetAllActiveCatalogItems(context.Background(), 10, 0)
+
+	// THEN flag reaches the ranker
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.True(t, result[0].IsPinned)
+	assert.False(t, result[1].IsPinned)
+}
+
 func TestGetAllActiveCatalogItems_EmptyResult(t *testing.T) {
 	service, mockStore := setupTestService(t)
 
@@ -727,6 +902,72 @@ func TestGetAllActiveCatalogItems_InvalidOffset_Negative(t *testing.T) {
 	assert.True(t, errors.As(err, &validationErr))
 }
 
+func TestGetActiveCatalogItemsPage_Cursor(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	// GIVEN two pinned and three regular items and pages of two
+	mockStore.EXPECT().
+		GetActiveCatalogItemsAfter(gomock.Any(), db.GetActiveCatalogItemsAfterParams{AfterPinned: true, AfterID: 0, PageLimit: 3}).
+		Return([]db.GetActiveCatalogItemsAfterRow{
+			{CatalogID: 20, StandardJobTitle: "гидроизоляция фундамент", IsPinned: true},
+			{CatalogID: 25, StandardJobTitle: "армирование плиты", IsPinned: true},
+			{CatalogID: 10, StandardJobTitle: "кладка кирпич"},
+		}, nil)
+
+	// WHEN the first page is requested
+	page, err := service.GetActiveCatalogItemsPage(context.Background(), 2, "")
+
+	// THEN it starts with the pinned items and the extra row is dropped
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	assert.True(t, page.Items[0].IsPinned)
+	assert.True(t, page.Items[1].IsPinned)
+	require.NotEmpty(t, page.NextCursor)
+
+	// next_cursor points after pinned id 25: the rest of the pinned items, then the regular ones
+	mockStore.EXPECT().
+		GetActiveCatalogItemsAfter(gomock.Any(), db.GetActiveCatalogItemsAfterParams{AfterPinned: true, AfterID: 25, PageLimit: 3}).
+		Return([]db.GetActiveCatalogItemsAfterRow{
+			{CatalogID: 10, StandardJobTitle: "кладка кирпич"},
+			{CatalogID: 30, StandardJobTitle: "утепление фасад"},
+			{CatalogID: 40, StandardJobTitle: "монтаж кровли"},
+		}, nil)
+
+	page, err = service.GetActiveCatalogItemsPage(context.Background(), 2, page.NextCursor)
+
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	assert.False(t, page.Items[1].IsPinned)
+	require.NotEmpty(t, page.NextCursor)
+
+	// next_cursor points after regular id 30: pinned items are not repeated
+	mockStore.EXPECT().
+		GetActiveCatalogItemsAfter(gomock.Any(), db.GetActiveCatalogItemsAfterParams{AfterPinned: false, AfterID: 30, PageLimit: 3}).
+		Return([]db.GetActiveCatalogItemsAfterRow{{CatalogID: 40, StandardJobTitle: "монтаж кровли"}}, nil)
+
+	// WHEN the last page is requested
+	page, err = service.GetActiveCatalogItemsPage(context.Background(), 2, page.NextCursor)
+
+	// THEN it is the last one
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, int64(40), page.Items[0].PositionItemID)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestGetActiveCatalogItemsPage_InvalidCursor(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	// GIVEN a malformed cursor
+	// WHEN
+	page, err := service.GetActiveCatalogItemsPage(context.Background(), 10, "%%%")
+
+	// THEN ValidationError without DB call
+	assert.Nil(t, page)
+	var validationErr *apierrors.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+}
+
 func TestGetAllActiveCatalogItems_DBError(t *testing.T) {
 	service, mockStore := setupTestService(t)
 
@@ -780,12 +1021,12 @@ var (
 		"id", "standard_job_title", "description", "embedding", "kind", "status",
 		"unit_id", "created_at", "updated_at", "fts_vector", "merged_into_id",
 	}
-	// fullCatalogPositionColumns — все 13 колонок CatalogPosition (включая parent_id, parameters).
+	// fullCatalogPositionColumns — все 16 колонок CatalogPosition (включая parent_id, parameters, is_pinned).
 	// Используется для запросов, возвращающих RETURNING * (GetCatalogPositionByID, CreateParentCatalogPosition).
 	fullCatalogPositionColumns = []string{
 		"id", "standard_job_title", "description", "embedding", "kind", "status",
 		"unit_id", "created_at", "updated_at", "fts_vector", "merged_into_id",
-		"parent_id", "parameters",
+		"parent_id", "parameters", "is_pinned", "pinned_at", "pinned_by",
 	}
 )
 
@@ -859,7 +1100,7 @@ func TestExecuteMerge_Success(t *testing.T) {
 						int64(200), "дубликат работа", sql.NullString{Valid: false}, nil,
 						"POSITION", "deprecated", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Int64: 100, Valid: true},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// FlattenMergeChain: path compression (B→A)
@@ -1016,7 +1257,7 @@ func TestExecuteMerge_DuplicateAlreadyMerged(t *testing.T) {
 						int64(200), "дубликат", sql.NullString{Valid: false}, nil,
 						"POSITION", "deprecated", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Int64: 99, Valid: true},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 		}),
 	)
@@ -1063,7 +1304,7 @@ func TestExecuteMerge_MasterInactive(t *testing.T) {
 						int64(200), "дубликат", sql.NullString{Valid: false}, nil,
 						"POSITION", "active", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Valid: false},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// GetCatalogPositionByID for master — deprecated
@@ -1074,7 +1315,7 @@ func TestExecuteMerge_MasterInactive(t *testing.T) {
 						int64(100), "мастер", sql.NullString{Valid: false}, nil,
 						"POSITION", "deprecated", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Int64: 50, Valid: true},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 		}),
 	)
@@ -1150,7 +1391,7 @@ func TestExecuteMerge_FlattenMergeChain_Scenario1_DBError(t *testing.T) {
 						int64(200), "дубликат", sql.NullString{Valid: false}, nil,
 						"POSITION", "deprecated", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Int64: 100, Valid: true},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// FlattenMergeChain fails
@@ -1221,7 +1462,7 @@ func TestExecuteMerge_MergeToNew_Success(t *testing.T) {
 						int64(300), "Новое название позиции", sql.NullString{String: "Новое название позиции", Valid: true}, nil,
 						"POSITION", "pending_indexing", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Valid: false},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// SetPositionMerged for A (ID=100) → deprecated, merged_into_id=300
@@ -1232,7 +1473,7 @@ func TestExecuteMerge_MergeToNew_Success(t *testing.T) {
 						int64(100), "мастер работа", sql.NullString{Valid: false}, nil,
 						"POSITION", "deprecated", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Int64: 300, Valid: true},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// SetPositionMerged for B (ID=200) → deprecated, merged_into_id=300
@@ -1243,7 +1484,7 @@ func TestExecuteMerge_MergeToNew_Success(t *testing.T) {
 						int64(200), "дубликат работа", sql.NullString{Valid: false}, nil,
 						"POSITION", "deprecated", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Int64: 300, Valid: true},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// FlattenMergeChain: path compression (A→C)
@@ -1374,7 +1615,7 @@ func TestExecuteMerge_MergeToNew_A_AlreadyDeprecated(t *testing.T) {
 						int64(300), "Объединённая позиция", sql.NullString{String: "Объединённая позиция", Valid: true}, nil,
 						"POSITION", "pending_indexing", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Valid: false},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// SetPositionMerged for A → ErrNoRows (A already deprecated)
@@ -1420,7 +1661,7 @@ func TestExecuteMerge_MergeToNew_B_AlreadyDeprecated(t *testing.T) {
 						int64(300), "Конечная позиция", sql.NullString{String: "Конечная позиция", Valid: true}, nil,
 						"POSITION", "pending_indexing", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Valid: false},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// SetPositionMerged for A → succeeds
@@ -1431,7 +1672,7 @@ func TestExecuteMerge_MergeToNew_B_AlreadyDeprecated(t *testing.T) {
 						int64(100), "мастер позиция", sql.NullString{Valid: false}, nil,
 						"POSITION", "deprecated", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Int64: 300, Valid: true},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// SetPositionMerged for B → ErrNoRows (B already deprecated)
@@ -1477,7 +1718,7 @@ func TestExecuteMerge_MergeToNew_SetPositionMerged_DBError(t *testing.T) {
 						int64(300), "Позиция XYZ", sql.NullString{String: "Позиция XYZ", Valid: true}, nil,
 						"POSITION", "pending_indexing", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Valid: false},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// SetPositionMerged for A → DB error (not ErrNoRows)
@@ -1522,7 +1763,7 @@ func TestExecuteMerge_MergeToNew_FlattenMergeChain_MasterA_DBError(t *testing.T)
 						int64(300), "Новая позиция", sql.NullString{String: "Новая позиция", Valid: true}, nil,
 						"POSITION", "pending_indexing", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Valid: false},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// SetPositionMerged for A (ID=100)
@@ -1533,7 +1774,7 @@ func TestExecuteMerge_MergeToNew_FlattenMergeChain_MasterA_DBError(t *testing.T)
 						int64(100), "мастер", sql.NullString{Valid: false}, nil,
 						"POSITION", "deprecated", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Int64: 300, Valid: true},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// SetPositionMerged for B (ID=200)
@@ -1544,7 +1785,7 @@ func TestExecuteMerge_MergeToNew_FlattenMergeChain_MasterA_DBError(t *testing.T)
 						int64(200), "дубликат", sql.NullString{Valid: false}, nil,
 						"POSITION", "deprecated", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Int64: 300, Valid: true},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// FlattenMergeChain for master A → fails
@@ -1587,7 +1828,7 @@ func TestExecuteMerge_MergeToNew_FlattenMergeChain_DuplicateB_DBError(t *testing
 						int64(300), "Новая позиция", sql.NullString{String: "Новая позиция", Valid: true}, nil,
 						"POSITION", "pending_indexing", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Valid: false},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// SetPositionMerged for A (ID=100)
@@ -1598,7 +1839,7 @@ func TestExecuteMerge_MergeToNew_FlattenMergeChain_DuplicateB_DBError(t *testing
 						int64(100), "мастер", sql.NullString{Valid: false}, nil,
 						"POSITION", "deprecated", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Int64: 300, Valid: true},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// SetPositionMerged for B (ID=200)
@@ -1609,7 +1850,7 @@ func TestExecuteMerge_MergeToNew_FlattenMergeChain_DuplicateB_DBError(t *testing
 						int64(200), "дубликат", sql.NullString{Valid: false}, nil,
 						"POSITION", "deprecated", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Int64: 300, Valid: true},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// FlattenMergeChain for master A → succeeds
@@ -1658,7 +1899,7 @@ func TestExecuteMerge_WhitespaceTitle_FallsBackToScenario1(t *testing.T) {
 						int64(200), "дубликат работа", sql.NullString{Valid: false}, nil,
 						"POSITION", "deprecated", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Int64: 100, Valid: true},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// FlattenMergeChain: path compression (B→A)
@@ -1794,7 +2035,7 @@ func TestExecuteBatchMerge_Scenario1_Success(t *testing.T) {
 						int64(2), "позиция-target", sql.NullString{Valid: false}, nil,
 						"POSITION", "active", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Valid: false},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// SetPositionMerged + FlattenMergeChain для {59, 89, 98} — сервис сортирует posID возрастающим, поэтому порядок детерминирован.
@@ -1806,7 +2047,7 @@ func TestExecuteBatchMerge_Scenario1_Success(t *testing.T) {
 							posID, "позиция", sql.NullString{Valid: false}, nil,
 							"POSITION", "deprecated", sql.NullInt64{Valid: false},
 							now, now, nil, sql.NullInt64{Int64: 2, Valid: true},
-							nil, nil,
+							nil, nil, false, nil, nil,
 						))
 
 				// FlattenMergeChain: path compression (posID→target)
@@ -1864,7 +2105,7 @@ func TestExecuteBatchMerge_Scenario1_WithRename(t *testing.T) {
 						int64(2), "позиция-target", sql.NullString{Valid: false}, nil,
 						"POSITION", "active", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Valid: false},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// SetPositionMerged for 59 + FlattenMergeChain
@@ -1875,7 +2116,7 @@ func TestExecuteBatchMerge_Scenario1_WithRename(t *testing.T) {
 						int64(59), "позиция 59", sql.NullString{Valid: false}, nil,
 						"POSITION", "deprecated", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Int64: 2, Valid: true},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 			mock.ExpectExec("UPDATE catalog_positions").
 				WithArgs(sql.NullInt64{Int64: 2, Valid: true}, sql.NullInt64{Int64: 59, Valid: true}).
@@ -1889,7 +2130,7 @@ func TestExecuteBatchMerge_Scenario1_WithRename(t *testing.T) {
 						int64(98), "позиция 98", sql.NullString{Valid: false}, nil,
 						"POSITION", "deprecated", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Int64: 2, Valid: true},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 			mock.ExpectExec("UPDATE catalog_positions").
 				WithArgs(sql.NullInt64{Int64: 2, Valid: true}, sql.NullInt64{Int64: 98, Valid: true}).
@@ -1903,7 +2144,7 @@ func TestExecuteBatchMerge_Scenario1_WithRename(t *testing.T) {
 						int64(2), "Чистое имя", sql.NullString{Valid: false}, nil,
 						"POSITION", "pending_indexing", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Valid: false},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// InvalidateRelatedActionableMerges: инвалидируем "мёртвые души" (deprecated=[59,98])
@@ -2015,7 +2256,7 @@ func TestExecuteBatchMerge_Scenario1_PositionAlreadyDeprecated(t *testing.T) {
 						int64(2), "позиция-target", sql.NullString{Valid: false}, nil,
 						"POSITION", "active", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Valid: false},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// First SetPositionMerged → ErrNoRows (already deprecated)
@@ -2063,7 +2304,7 @@ func TestExecuteBatchMerge_TargetAlreadyDeprecated(t *testing.T) {
 						int64(2), "позиция-target", sql.NullString{Valid: false}, nil,
 						"POSITION", "deprecated", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Int64: 100, Valid: true},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 		}),
 	)
@@ -2109,7 +2350,7 @@ func TestExecuteBatchMerge_Scenario2_Success(t *testing.T) {
 						int64(300), "Единая позиция", sql.NullString{String: "Единая позиция", Valid: true}, nil,
 						"POSITION", "pending_indexing", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Valid: false},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// SetPositionMerged + FlattenMergeChain для {2, 59, 89, 98} — сервис сортирует posID возрастающим, поэтому порядок детерминирован.
@@ -2121,7 +2362,7 @@ func TestExecuteBatchMerge_Scenario2_Success(t *testing.T) {
 							posID, "позиция", sql.NullString{Valid: false}, nil,
 							"POSITION", "deprecated", sql.NullInt64{Valid: false},
 							now, now, nil, sql.NullInt64{Int64: 300, Valid: true},
-							nil, nil,
+							nil, nil, false, nil, nil,
 						))
 
 				// FlattenMergeChain: path compression (posID→C)
@@ -2242,7 +2483,7 @@ func TestExecuteBatchMerge_FlattenMergeChain_Scenario1_DBError(t *testing.T) {
 						int64(2), "позиция-target", sql.NullString{Valid: false}, nil,
 						"POSITION", "active", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Valid: false},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// SetPositionMerged for 59
@@ -2253,7 +2494,7 @@ func TestExecuteBatchMerge_FlattenMergeChain_Scenario1_DBError(t *testing.T) {
 						int64(59), "позиция 59", sql.NullString{Valid: false}, nil,
 						"POSITION", "deprecated", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Int64: 2, Valid: true},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// FlattenMergeChain for 59 → fails
@@ -2299,7 +2540,7 @@ func TestExecuteBatchMerge_FlattenMergeChain_Scenario2_DBError(t *testing.T) {
 						int64(300), "Единая позиция", sql.NullString{String: "Единая позиция", Valid: true}, nil,
 						"POSITION", "pending_indexing", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Valid: false},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// SetPositionMerged for 2 (first in sorted order)
@@ -2310,7 +2551,7 @@ func TestExecuteBatchMerge_FlattenMergeChain_Scenario2_DBError(t *testing.T) {
 						int64(2), "позиция", sql.NullString{Valid: false}, nil,
 						"POSITION", "deprecated", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Int64: 300, Valid: true},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// FlattenMergeChain for 2 → fails
@@ -2592,7 +2833,7 @@ func TestExecuteMerge_InvalidateRelatedActionableMerges_DBError(t *testing.T) {
 						int64(200), "дубликат", sql.NullString{Valid: false}, nil,
 						"POSITION", "deprecated", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Int64: 100, Valid: true},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// FlattenMergeChain: path compression (B→A)
@@ -2650,7 +2891,7 @@ func TestExecuteBatchMerge_InvalidateRelatedActionableMerges_DBError(t *testing.
 						int64(2), "позиция-target", sql.NullString{Valid: false}, nil,
 						"POSITION", "active", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Valid: false},
-						nil, nil,
+						nil, nil, false, nil, nil,
 					))
 
 			// SetPositionMerged for 59 + FlattenMergeChain
@@ -2660,7 +2901,7 @@ func TestExecuteBatchMerge_InvalidateRelatedActionableMerges_DBError(t *testing.
 					AddRow(int64(59), "позиция 59", sql.NullString{Valid: false}, nil,
 						"POSITION", "deprecated", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Int64: 2, Valid: true},
-						nil, nil))
+						nil, nil, false, nil, nil))
 			mock.ExpectExec("UPDATE catalog_positions").
 				WithArgs(sql.NullInt64{Int64: 2, Valid: true}, sql.NullInt64{Int64: 59, Valid: true}).
 				WillReturnResult(sqlmock.NewResult(0, 0))
@@ -2672,7 +2913,7 @@ func TestExecuteBatchMerge_InvalidateRelatedActionableMerges_DBError(t *testing.
 					AddRow(int64(98), "позиция 98", sql.NullString{Valid: false}, nil,
 						"POSITION", "deprecated", sql.NullInt64{Valid: false},
 						now, now, nil, sql.NullInt64{Int64: 2, Valid: true},
-						nil, nil))
+						nil, nil, false, nil, nil))
 			mock.ExpectExec("UPDATE catalog_positions").
 				WithArgs(sql.NullInt64{Int64: 2, Valid: true}, sql.NullInt64{Int64: 98, Valid: true}).
 				WillReturnResult(sqlmock.NewResult(0, 0))
@@ -2796,6 +3037,31 @@ func TestListPendingMerges_Success_SingleGroupMultipleDuplicates(t *testing.T) {
 	assert.Equal(t, int64(13), result.Groups[0].Merges[2].Duplicate.ID)
 }
 
+// TestListPendingMerges_PinnedFlag проверяет, что оператор видит закрепление
+// мастер-позиции и дубликата при разборе заявки.
+func TestListPendingMerges_PinnedFlag(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	ctx := context.Background()
+
+	// GIVEN: закреплённый дубликат у незакреплённой мастер-позиции
+	row := makePendingMergesRow(1, 10, 11, "Кладка кирпича", "Кирпичная кладка", sql.NullString{Valid: false}, sql.NullString{Valid: false})
+	row.CatalogPosition_2.IsPinned = true
+
+	mockStore.EXPECT().CountPendingMerges(gomock.Any()).Return(int64(1), nil)
+	mockStore.EXPECT().CountPendingMergeGroups(gomock.Any()).Return(int64(1), nil)
+	mockStore.EXPECT().ListPendingMerges(gomock.Any(), gomock.Any()).Return([]db.ListPendingMergesRow{row}, nil)
+
+	// WHEN
+	result, err := service.ListPendingMerges(ctx, 1, 20)
+
+	// THEN — is_pinned отдаётся для обеих позиций
+	require.NoError(t, err)
+	require.Len(t, result.Groups, 1)
+	assert.False(t, result.Groups[0].MainPosition.IsPinned)
+	require.Len(t, result.Groups[0].Merges, 1)
+	assert.True(t, result.Groups[0].Merges[0].Duplicate.IsPinned)
+}
+
 // TestListPendingMerges_EmptyResult проверяет пустой результат (нет PENDING предложений).
 func TestListPendingMerges_EmptyResult(t *testing.T) {
 	service, mockStore := setupTestService(t)
@@ -2994,7 +3260,7 @@ func TestListPendingMerges_DBError_ListPendingMerges(t *testing.T) {
 }
 
 // ========================================================================
-// catalogPositionToSummary tests
+// PositionToSummary tests
 // ========================================================================
 
 // TestCatalogPositionToSummary_NullableDescription проверяет конвертацию nullable description:
@@ -3013,7 +3279,7 @@ func TestCatalogPositionToSummary_NullableDescription(t *testing.T) {
 			UpdatedAt:        now,
 		}
 
-		result := catalogPositionToSummary(pos)
+		result := PositionToSummary(pos)
 
 		assert.Equal(t, int64(42), result.ID)
 		assert.Equal(t, "Монтаж трубопровода", result.StandardJobTitle)
@@ -3034,7 +3300,7 @@ func TestCatalogPositionToSummary_NullableDescription(t *testing.T) {
 			UpdatedAt:        now,
 		}
 
-		result := catalogPositionToSummary(pos)
+		result := PositionToSummary(pos)
 
 		assert.Equal(t, int64(99), result.ID)
 		assert.Nil(t, result.Description)
@@ -3051,7 +3317,7 @@ func TestCatalogPositionToSummary_NullableDescription(t *testing.T) {
 			UpdatedAt:        now,
 		}
 
-		result := catalogPositionToSummary(pos)
+		result := PositionToSummary(pos)
 
 		// Пробельная строка обрезается → пустая → nil
 		assert.Nil(t, result.Description)
@@ -3077,7 +3343,7 @@ func TestResolveParentID_NewTitle_Success(t *testing.T) {
 				int64(50), "Окна ПВХ", sql.NullString{String: "Окна ПВХ", Valid: true}, nil,
 				"GROUP_TITLE", "pending_indexing", sql.NullInt64{Valid: false},
 				now, now, nil, sql.NullInt64{Valid: false},
-				sql.NullInt64{Valid: false}, pqtype.NullRawMessage{Valid: false},
+				sql.NullInt64{Valid: false}, pqtype.NullRawMessage{Valid: false}, false, nil, nil,
 			))
 
 	// When: resolveParentID с newTitle
@@ -3141,7 +3407,7 @@ func TestResolveParentID_ParentID_Success_GroupTitle(t *testing.T) {
 				int64(10), "Группа: Окна", sql.NullString{String: "Окна", Valid: true}, nil,
 				"GROUP_TITLE", "active", sql.NullInt64{Valid: false},
 				now, now, nil, sql.NullInt64{Valid: false},
-				sql.NullInt64{Valid: false}, pqtype.NullRawMessage{Valid: false},
+				sql.NullInt64{Valid: false}, pqtype.NullRawMessage{Valid: false}, false, nil, nil,
 			))
 
 	// When
@@ -3180,7 +3446,7 @@ func TestResolveParentID_ParentID_Deprecated(t *testing.T) {
 				int64(10), "Deprecated", sql.NullString{Valid: false}, nil,
 				"GROUP_TITLE", "deprecated", sql.NullInt64{Valid: false},
 				now, now, nil, sql.NullInt64{Valid: false},
-				sql.NullInt64{Valid: false}, pqtype.NullRawMessage{Valid: false},
+				sql.NullInt64{Valid: false}, pqtype.NullRawMessage{Valid: false}, false, nil, nil,
 			))
 
 	_, err := service.resolveParentID(context.Background(), q, 10, "", nil)
@@ -3203,7 +3469,7 @@ func TestResolveParentID_ParentID_Merged(t *testing.T) {
 				int64(10), "Merged", sql.NullString{Valid: false}, nil,
 				"GROUP_TITLE", "active", sql.NullInt64{Valid: false},
 				now, now, nil, sql.NullInt64{Int64: 5, Valid: true},
-				sql.NullInt64{Valid: false}, pqtype.NullRawMessage{Valid: false},
+				sql.NullInt64{Valid: false}, pqtype.NullRawMessage{Valid: false}, false, nil, nil,
 			))
 
 	_, err := service.resolveParentID(context.Background(), q, 10, "", nil)
@@ -3227,7 +3493,7 @@ func TestResolveParentID_ParentID_KindNotGroupTitle(t *testing.T) {
 				int64(10), "Обычная позиция", sql.NullString{Valid: false}, nil,
 				"POSITION", "active", sql.NullInt64{Valid: false},
 				now, now, nil, sql.NullInt64{Valid: false},
-				sql.NullInt64{Valid: false}, pqtype.NullRawMessage{Valid: false},
+				sql.NullInt64{Valid: false}, pqtype.NullRawMessage{Valid: false}, false, nil, nil,
 			))
 
 	// When
@@ -3255,7 +3521,7 @@ func TestResolveParentID_ParentID_KindHeader_Rejected(t *testing.T) {
 				int64(10), "Legacy HEADER", sql.NullString{Valid: false}, nil,
 				"HEADER", "active", sql.NullInt64{Valid: false},
 				now, now, nil, sql.NullInt64{Valid: false},
-				sql.NullInt64{Valid: false}, pqtype.NullRawMessage{Valid: false},
+				sql.NullInt64{Valid: false}, pqtype.NullRawMessage{Valid: false}, false, nil, nil,
 			))
 
 	// When
@@ -3281,7 +3547,7 @@ func TestResolveParentID_ParentID_InForbiddenIDs(t *testing.T) {
 				int64(10), "Группа", sql.NullString{Valid: false}, nil,
 				"GROUP_TITLE", "active", sql.NullInt64{Valid: false},
 				now, now, nil, sql.NullInt64{Valid: false},
-				sql.NullInt64{Valid: false}, pqtype.NullRawMessage{Valid: false},
+				sql.NullInt64{Valid: false}, pqtype.NullRawMessage{Valid: false}, false, nil, nil,
 			))
 
 	_, err := service.resolveParentID(context.Background(), q, 10, "", []int64{10, 20})
@@ -3304,7 +3570,7 @@ func TestResolveParentID_ParentID_NotInForbiddenIDs(t *testing.T) {
 				int64(10), "Группа", sql.NullString{Valid: false}, nil,
 				"GROUP_TITLE", "active", sql.NullInt64{Valid: false},
 				now, now, nil, sql.NullInt64{Valid: false},
-				sql.NullInt64{Valid: false}, pqtype.NullRawMessage{Valid: false},
+				sql.NullInt64{Valid: false}, pqtype.NullRawMessage{Valid: false}, false, nil, nil,
 			))
 
 	resultID, err := service.resolveParentID(context.Background(), q, 10, "", []int64{20, 30})
@@ -3328,3 +3594,201 @@ func TestResolveParentID_ParentID_DBError(t *testing.T) {
 	require.Error(t, err)
 	assert.ErrorIs(t, err, dbErr)
 }
+
+// =============================================================================
+// SetCatalogPositionPinned TESTS
+// =============================================================================
+
+func TestSetCatalogPositionPinned_Success(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().
+		SetCatalogPositionPinned(gomock.Any(), db.SetCatalogPositionPinnedParams{
+			IsPinned: true,
+			PinnedBy: sql.NullString{String: "7", Valid: true},
+			ID:       42,
+		}).
+		Return(db.CatalogPosition{
+			ID:               42,
+			StandardJobTitle: "монтаж вентиляция",
+			Kind:             "POSITION",
+			Status:           "active",
+			IsPinned:         true,
+		}, nil)
+
+	result, err := service.SetCatalogPositionPinned(context.Background(), 42, true, "7")
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, int64(42), result.ID)
+	assert.True(t, result.IsPinned)
+}
+
+func TestSetCatalogPositionPinned_NotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().
+		SetCatalogPositionPinned(gomock.Any(), gomock.Any()).
+		Return(db.CatalogPosition{}, sql.ErrNoRows)
+	mockStore.EXPECT().
+		GetCatalogPositionByID(gomock.Any(), int64(42)).
+		Return(db.CatalogPosition{}, sql.ErrNoRows)
+
+	_, err := service.SetCatalogPositionPinned(context.Background(), 42, true, "7")
+
+	var notFoundErr *apierrors.NotFoundError
+	require.True(t, errors.As(err, &notFoundErr))
+}
+
+func TestSetCatalogPositionPinned_DeprecatedPosition(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().
+		SetCatalogPositionPinned(gomock.Any(), gomock.Any()).
+		Return(db.CatalogPosition{}, sql.ErrNoRows)
+	mockStore.EXPECT().
+		GetCatalogPositionByID(gomock.Any(), int64(42)).
+		Return(db.CatalogPosition{ID: 42, Kind: "POSITION", Status: "deprecated"}, nil)
+
+	_, err := service.SetCatalogPositionPinned(context.Background(), 42, true, "7")
+
+	var validationErr *apierrors.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Contains(t, err.Error(), "deprecated")
+}
+
+func TestSetCatalogPositionPinned_InvalidInput(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.SetCatalogPositionPinned(context.Background(), 0, true, "7")
+	var validationErr *apierrors.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+
+	_, err = service.SetCatalogPositionPinned(context.Background(), 42, true, "  ")
+	assert.True(t, errors.As(err, &validationErr))
+}
+
+// =============================================================================
+// ApproveMerge TESTS
+// =============================================================================
+
+// expectApproveMergeUntilRetarget описывает шаги ApproveMerge до перевешивания ссылок:
+// перевод заявки в EXECUTED, слияние B → A и сжатие цепочки.
+func expectApproveMergeUntilRetarget(mock sqlmock.Sqlmock, now time.Time) {
+	mock.ExpectQuery("UPDATE suggested_merges").
+		WithArgs(sqlmock.AnyArg(), int64(42)).
+		WillReturnRows(sqlmock.NewRows(suggestedMergeColumns).
+			AddRow(
+				int64(42), int64(100), int64(200), float32(0.95),
+				"EXECUTED", now, now, now, "123",
+			))
+
+	mock.ExpectQuery("UPDATE catalog_positions").
+		WithArgs(sqlmock.AnyArg(), int64(200)).
+		WillReturnRows(sqlmock.NewRows(fullCatalogPositionColumns).
+			AddRow(
+				int64(200), "дубликат работа", sql.NullString{Valid: false}, nil,
+				"POSITION", "deprecated", sql.NullInt64{Valid: false},
+				now, now, nil, sql.NullInt64{Int64: 100, Valid: true},
+				nil, nil, false, nil, nil,
+			))
+
+	mock.ExpectExec("UPDATE catalog_positions").
+		WithArgs(sql.NullInt64{Int64: 100, Valid: true}, sql.NullInt64{Int64: 200, Valid: true}).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestApproveMerge_Success(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	now := time.Now()
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			expectApproveMergeUntilRetarget(mock, now)
+
+			mock.ExpectExec("UPDATE position_items").
+				WithArgs(int64(100), int64(200)).
+				WillReturnResult(sqlmock.NewResult(0, 7))
+			mock.ExpectExec("UPDATE matching_cache").
+				WithArgs(int64(100), int64(200)).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+
+			mock.ExpectExec("UPDATE suggested_merges").
+				WithArgs(sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+		}),
+	)
+
+	result, err := service.ApproveMerge(context.Background(), 42, "123")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), result.MergeID)
+	assert.Equal(t, int64(100), result.MainPositionID)
+	assert.Equal(t, int64(200), result.DuplicatePositionID)
+	assert.Equal(t, "deprecated", result.DuplicateStatus)
+	assert.Equal(t, int64(7), result.RetargetedPositionItems)
+	assert.Equal(t, int64(2), result.RetargetedCacheEntries)
+	assert.False(t, result.ResolvedAt.IsZero())
+}
+
+func TestApproveMerge_WrongStatus(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	now := time.Now()
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("UPDATE suggested_merges").
+				WithArgs(sqlmock.AnyArg(), int64(42)).
+				WillReturnError(sql.ErrNoRows)
+			mock.ExpectQuery("SELECT .+ FROM suggested_merges WHERE id").
+				WithArgs(int64(42)).
+				WillReturnRows(sqlmock.NewRows(suggestedMergeColumns).
+					AddRow(
+						int64(42), int64(100), int64(200), float32(0.95),
+						"REJECTED", now, now, now, "admin",
+					))
+		}),
+	)
+
+	result, err := service.ApproveMerge(context.Background(), 42, "123")
+
+	require.Error(t, err)
+	assert.Nil(t, result)
+	var validationErr *apierrors.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Contains(t, err.Error(), "текущий статус=REJECTED")
+}
+
+func TestApproveMerge_RetargetPositionItems_DBError(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	now := time.Now()
+	dbErr := errors.New("deadlock detected")
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			expectApproveMergeUntilRetarget(mock, now)
+
+			mock.ExpectExec("UPDATE position_items").
+				WithArgs(int64(100), int64(200)).
+				WillReturnError(dbErr)
+		}),
+	)
+
+	result, err := service.ApproveMerge(context.Background(), 42, "123")
+
+	require.Error(t, err)
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, dbErr)
+	assert.Contains(t, err.Error(), "RetargetPositionItems")
+}
+
+func TestApproveMerge_InvalidInput(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.ApproveMerge(context.Background(), 42, "  ")
+	var validationErr *apierrors.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+
+	_, err = service.ApproveMerge(context.Background(), 0, "123")
+	assert.True(t, errors.As(err, &validationErr))
+}
@@ -0,0 +1,142 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+// DeprecateCatalogPosition реализует POST /api/v1/admin/catalog/positions/:id/deprecate.
+//
+// # Назначение
+//
+// Выводит позицию из оборота с указанием замены. В отличие от слияния,
+// position_items не перевешиваются: тендеры, уже сопоставленные с позицией,
+// остаются в истории как есть. Новые сопоставления уходят на замену:
+// SetCatalogPositionID и UpsertMatchingCache подменяют позицию её replaced_by_id,
+// импорт берёт замену вместо черновой позиции. Выведенная позиция не попадает
+// в GET /catalog/unindexed и GET /catalog/active.
+//
+// # Логика выполнения (целиком в транзакции)
+//
+//  1. Проверяет, что позиция есть, а замена того же kind и в обороте
+//  2. Помечает позицию deprecated с replaced_by_id, снимает закрепление
+//  3. Перевешивает на замену позиции, ранее выведенные в пользу этой (сжатие цепочки)
+//  4. Перевешивает записи matching_cache на замену
+//  5. Инвалидирует незавершённые заявки на слияние с участием позиции
+//
+// # Возвращаемое значение
+//
+//   - NotFoundError — нет позиции или замены
+//   - ValidationError — замена совпадает с позицией, другого kind или не в обороте
+//   - ConflictError — позиция уже выведена или влита
+func (s *CatalogService) DeprecateCatalogPosition(
+	ctx context.Context,
+	positionID int64,
+	replacedByID int64,
+	executedBy string,
+) (*api_models.DeprecateCatalogPositionResponse, error) {
+	logger := s.logger.WithField("method", "DeprecateCatalogPosition").WithField("position_id", positionID)
+
+	if positionID <= 0 || replacedByID <= 0 {
+		return nil, apierrors.NewValidationError("catalog.deprecate_ids_positive")
+	}
+	if positionID == replacedByID {
+		return nil, apierrors.NewValidationError("catalog.replace_self", positionID)
+	}
+	executedBy = strings.TrimSpace(executedBy)
+	if executedBy == "" {
+		return nil, apierrors.NewValidationError("catalog.executed_by_empty")
+	}
+
+	var (
+		deprecated  db.CatalogPosition
+		replacement db.CatalogPosition
+		repointed   int64
+		retargeted  int64
+	)
+	err := s.store.ExecTx(ctx, func(q *db.Queries) error {
+		position, txErr := getCatalogPositionForDeprecation(ctx, q, positionID, "catalog.position_id_not_found")
+		if txErr != nil {
+			return txErr
+		}
+		replacement, txErr = getCatalogPositionForDeprecation(ctx, q, replacedByID, "catalog.replacement_not_found")
+		if txErr != nil {
+			return txErr
+		}
+		if replacement.Status == "deprecated" || replacement.Status == "archived" || replacement.MergedIntoID.Valid {
+			return apierrors.NewValidationError(
+				"catalog.replacement_not_active",
+				replacedByID, replacement.Status, replacement.MergedIntoID,
+			)
+		}
+		if replacement.Kind != position.Kind {
+			return apierrors.NewValidationError(
+				"catalog.replacement_kind_mismatch",
+				replacedByID, replacement.Kind, position.Kind,
+			)
+		}
+
+		deprecated, txErr = q.DeprecateCatalogPosition(ctx, db.DeprecateCatalogPositionParams{
+			ReplacedByID: replacedByID,
+			DeprecatedBy: executedBy,
+			ID:           positionID,
+		})
+		if txErr != nil {
+			if errors.Is(txErr, sql.ErrNoRows) {
+				return apierrors.NewConflictErrorf(PositionToSummary(position), "catalog.position_already_retired", positionID)
+			}
+			return fmt.Errorf("ошибка DeprecateCatalogPosition(%d): %w", positionID, txErr)
+		}
+
+		repointed, txErr = q.FlattenReplacementChain(ctx, db.FlattenReplacementChainParams{
+			NewID: replacedByID,
+			OldID: positionID,
+		})
+		if txErr != nil {
+			return fmt.Errorf("ошибка FlattenReplacementChain (%d → %d): %w", positionID, replacedByID, txErr)
+		}
+
+		retargeted, txErr = q.RetargetMatchingCache(ctx, db.RetargetMatchingCacheParams{
+			MainID:      replacedByID,
+			DuplicateID: positionID,
+		})
+		if txErr != nil {
+			return fmt.Errorf("ошибка RetargetMatchingCache (%d → %d): %w", positionID, replacedByID, txErr)
+		}
+
+		return invalidateActionableMerges(ctx, q, []int64{positionID})
+	})
+	if err != nil {
+		logger.Errorf("Ошибка вывода позиции из оборота: %v", err)
+		return nil, err
+	}
+
+	logger.Infof("Позиция %d выведена из оборота, замена %d (оператор: %s, цепочек: %d, matching_cache: %d)",
+		positionID, replacedByID, executedBy, repointed, retargeted)
+
+	return &api_models.DeprecateCatalogPositionResponse{
+		Position:               PositionToSummary(deprecated),
+		ReplacedBy:             PositionToSummary(replacement),
+		RepointedReplacements:  repointed,
+		RetargetedCacheEntries: retargeted,
+		DeprecatedAt:           deprecated.DeprecatedAt.Time,
+	}, nil
+}
+
+func getCatalogPositionForDeprecation(ctx context.Context, q *db.Queries, id int64, notFoundKey string) (db.CatalogPosition, error) {
+	pos, err := q.GetCatalogPositionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return pos, apierrors.NewNotFoundError(notFoundKey, id)
+		}
+		return pos, fmt.Errorf("ошибка получения позиции %d: %w", id, err)
+	}
+	return pos, nil
+}
@@ -0,0 +1,155 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR CATALOG POSITION DEPRECATION (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Stale matches — new tenders keep being matched to an outdated catalog entry
+2. Lost history — tenders already matched to the entry must keep their links
+3. Broken chains — A replaced by B, then B replaced by C must leave A pointing to C
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Deprecation
+- GIVEN an active position and an active replacement of the same kind
+  WHEN DeprecateCatalogPosition is called
+  THEN the position is deprecated with replaced_by_id, earlier replacements and
+  matching_cache entries are repointed, pending merges with it are invalidated
+
+SCENARIO 2: Rejections
+- GIVEN the replacement is the position itself → ValidationError without touching the DB
+- GIVEN the replacement is itself deprecated → ValidationError
+- GIVEN the position is already deprecated → ConflictError
+- GIVEN an unknown position → NotFoundError
+*/
+
+// deprecationPositionColumns — все колонки catalog_positions после миграции 000046.
+var deprecationPositionColumns = append(append([]string{}, fullCatalogPositionColumns...),
+	"work_group_code", "replaced_by_id", "deprecated_at", "deprecated_by")
+
+func deprecationPositionRow(id int64, kind, status string, replacedBy sql.NullInt64, deprecatedAt sql.NullTime, now time.Time) *sqlmock.Rows {
+	return sqlmock.NewRows(deprecationPositionColumns).AddRow(
+		id, "позиция", sql.NullString{}, nil,
+		kind, status, sql.NullInt64{},
+		now, now, nil, sql.NullInt64{},
+		nil, nil, false, nil, nil,
+		nil, replacedBy, deprecatedAt, nil,
+	)
+}
+
+func TestDeprecateCatalogPosition_Success(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	now := time.Now()
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT .+ FROM catalog_positions").WithArgs(int64(200)).
+				WillReturnRows(deprecationPositionRow(200, "POSITION", "active", sql.NullInt64{}, sql.NullTime{}, now))
+			mock.ExpectQuery("SELECT .+ FROM catalog_positions").WithArgs(int64(100)).
+				WillReturnRows(deprecationPositionRow(100, "POSITION", "active", sql.NullInt64{}, sql.NullTime{}, now))
+			mock.ExpectQuery("UPDATE catalog_positions").WithArgs(int64(100), "7", int64(200)).
+				WillReturnRows(deprecationPositionRow(200, "POSITION", "deprecated",
+					sql.NullInt64{Int64: 100, Valid: true}, sql.NullTime{Time: now, Valid: true}, now))
+			mock.ExpectExec("UPDATE catalog_positions").WithArgs(int64(100), int64(200)).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+			mock.ExpectExec("UPDATE matching_cache").WithArgs(int64(100), int64(200)).
+				WillReturnResult(sqlmock.NewResult(0, 5))
+			mock.ExpectExec("UPDATE suggested_merges").WithArgs(sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}),
+	)
+
+	result, err := service.DeprecateCatalogPosition(context.Background(), 200, 100, "7")
+
+	require.NoError(t, err)
+	assert.Equal(t, "deprecated", result.Position.Status)
+	require.NotNil(t, result.Position.ReplacedByID)
+	assert.Equal(t, int64(100), *result.Position.ReplacedByID)
+	assert.Equal(t, int64(100), result.ReplacedBy.ID)
+	assert.Equal(t, int64(2), result.RepointedReplacements)
+	assert.Equal(t, int64(5), result.RetargetedCacheEntries)
+	assert.Equal(t, now, result.DeprecatedAt)
+}
+
+func TestDeprecateCatalogPosition_SelfReplacement(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.DeprecateCatalogPosition(context.Background(), 200, 200, "7")
+
+	var validationErr *apierrors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestDeprecateCatalogPosition_ReplacementOutOfService(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	now := time.Now()
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT .+ FROM catalog_positions").WithArgs(int64(200)).
+				WillReturnRows(deprecationPositionRow(200, "POSITION", "active", sql.NullInt64{}, sql.NullTime{}, now))
+			mock.ExpectQuery("SELECT .+ FROM catalog_positions").WithArgs(int64(100)).
+				WillReturnRows(deprecationPositionRow(100, "POSITION", "deprecated",
+					sql.NullInt64{Int64: 50, Valid: true}, sql.NullTime{Time: now, Valid: true}, now))
+		}),
+	)
+
+	_, err := service.DeprecateCatalogPosition(context.Background(), 200, 100, "7")
+
+	var validationErr *apierrors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestDeprecateCatalogPosition_AlreadyDeprecated(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	now := time.Now()
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT .+ FROM catalog_positions").WithArgs(int64(200)).
+				WillReturnRows(deprecationPositionRow(200, "POSITION", "deprecated",
+					sql.NullInt64{Int64: 50, Valid: true}, sql.NullTime{Time: now, Valid: true}, now))
+			mock.ExpectQuery("SELECT .+ FROM catalog_positions").WithArgs(int64(100)).
+				WillReturnRows(deprecationPositionRow(100, "POSITION", "active", sql.NullInt64{}, sql.NullTime{}, now))
+			mock.ExpectQuery("UPDATE catalog_positions").WithArgs(int64(100), "7", int64(200)).
+				WillReturnError(sql.ErrNoRows)
+		}),
+	)
+
+	_, err := service.DeprecateCatalogPosition(context.Background(), 200, 100, "7")
+
+	var conflictErr *apierrors.ConflictError
+	assert.ErrorAs(t, err, &conflictErr)
+}
+
+func TestDeprecateCatalogPosition_NotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT .+ FROM catalog_positions").WithArgs(int64(200)).
+				WillReturnError(sql.ErrNoRows)
+		}),
+	)
+
+	_, err := service.DeprecateCatalogPosition(context.Background(), 200, 100, "7")
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
@@ -0,0 +1,195 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/db/postgres"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+// Статусы запуска перенормализации (catalog_renormalization_runs.status).
+const (
+	RenormalizationStatusRunning   = "running"
+	RenormalizationStatusCompleted = "completed"
+)
+
+// RenormalizationBatchResult — итог обработки одной порции перенормализации.
+type RenormalizationBatchResult struct {
+	RunID     int64
+	Requeued  int64
+	Completed bool
+}
+
+// GetNormVersion реализует GET /api/v1/admin/catalog/norm-version и
+// GET /internal/worker/norm-version.
+//
+// Возвращает активную версию нормализации и последний запуск перенормализации
+// (если он был).
+func (s *CatalogService) GetNormVersion(ctx context.Context) (*api_models.NormVersionResponse, error) {
+	version, err := s.store.GetActiveNormVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка GetActiveNormVersion: %w", err)
+	}
+
+	resp := &api_models.NormVersionResponse{NormVersion: version}
+
+	run, err := s.store.GetLatestCatalogRenormalizationRun(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return resp, nil
+		}
+		return nil, fmt.Errorf("ошибка GetLatestCatalogRenormalizationRun: %w", err)
+	}
+	resp.Renormalization = newRenormalizationRunResponse(run)
+	return resp, nil
+}
+
+// BumpNormVersion реализует POST /api/v1/admin/catalog/norm-version/bump.
+//
+// В одной транзакции:
+//  1. Повышает активную norm_version на единицу
+//  2. Удаляет записи matching_cache старых версий (они больше не читаются импортом)
+//  3. Создаёт запуск перенормализации по всем активным позициям каталога
+//
+// Сами позиции возвращаются в pending_indexing порциями фоновой задачей
+// (ProcessRenormalizationBatch), чтобы RAG-поиск не терял весь индекс сразу.
+// Если предыдущий запуск ещё выполняется — ConflictError.
+func (s *CatalogService) BumpNormVersion(ctx context.Context, actorID int64) (*api_models.CatalogRenormalizationRunResponse, error) {
+	logger := s.logger.WithField("method", "BumpNormVersion")
+
+	var run db.CatalogRenormalizationRun
+	err := s.store.ExecTx(ctx, func(qtx *db.Queries) error {
+		current, err := qtx.GetActiveNormVersion(ctx)
+		if err != nil {
+			return fmt.Errorf("ошибка GetActiveNormVersion: %w", err)
+		}
+		if current >= math.MaxInt16 {
+			return apierrors.NewValidationError("catalog.norm_version_max", current)
+		}
+		next := current + 1
+
+		if err := qtx.SetActiveNormVersion(ctx, db.SetActiveNormVersionParams{
+			NormVersion: next,
+			UpdatedBy:   strconv.FormatInt(actorID, 10),
+		}); err != nil {
+			return fmt.Errorf("ошибка SetActiveNormVersion: %w", err)
+		}
+
+		deleted, err := qtx.DeleteMatchingCacheBelowVersion(ctx, next)
+		if err != nil {
+			return fmt.Errorf("ошибка DeleteMatchingCacheBelowVersion: %w", err)
+		}
+
+		total, err := qtx.CountCatalogPositionsForRenormalization(ctx)
+		if err != nil {
+			return fmt.Errorf("ошибка CountCatalogPositionsForRenormalization: %w", err)
+		}
+
+		run, err = qtx.CreateCatalogRenormalizationRun(ctx, db.CreateCatalogRenormalizationRunParams{
+			NormVersion:         next,
+			TotalPositions:      total,
+			CacheEntriesDeleted: deleted,
+			StartedBy:           sql.NullInt64{Int64: actorID, Valid: true},
+		})
+		if err != nil {
+			if postgres.IsUniqueViolation(err) {
+				return apierrors.NewConflictError("catalog.renormalization_running", nil)
+			}
+			return fmt.Errorf("ошибка CreateCatalogRenormalizationRun: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("norm_version повышена до %d: удалено записей кэша %d, позиций к перенормализации %d",
+		run.NormVersion, run.CacheEntriesDeleted, run.TotalPositions)
+	return newRenormalizationRunResponse(run), nil
+}
+
+// ProcessRenormalizationBatch возвращает в pending_indexing очередную порцию
+// позиций выполняющегося запуска и сдвигает его курсор. Пустая порция завершает
+// запуск.
+//
+// Возвращает nil, если выполняющегося запуска нет (или его обрабатывает
+// другой экземпляр API).
+func (s *CatalogService) ProcessRenormalizationBatch(ctx context.Context, batchSize int32) (*RenormalizationBatchResult, error) {
+	if batchSize <= 0 {
+		return nil, apierrors.NewValidationError("catalog.batch_size_positive")
+	}
+
+	var result *RenormalizationBatchResult
+	err := s.store.ExecTx(ctx, func(qtx *db.Queries) error {
+		run, err := qtx.LockRunningCatalogRenormalizationRun(ctx)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			return fmt.Errorf("ошибка LockRunningCatalogRenormalizationRun: %w", err)
+		}
+
+		ids, err := qtx.RequeueCatalogPositionsBatch(ctx, db.RequeueCatalogPositionsBatchParams{
+			AfterID:   run.LastPositionID,
+			BatchSize: batchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка RequeueCatalogPositionsBatch (run=%d): %w", run.ID, err)
+		}
+
+		lastID := run.LastPositionID
+		for _, id := range ids {
+			if id > lastID {
+				lastID = id
+			}
+		}
+
+		updated, err := qtx.AdvanceCatalogRenormalizationRun(ctx, db.AdvanceCatalogRenormalizationRunParams{
+			Requeued:       int64(len(ids)),
+			LastPositionID: lastID,
+			ID:             run.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка AdvanceCatalogRenormalizationRun (run=%d): %w", run.ID, err)
+		}
+
+		result = &RenormalizationBatchResult{
+			RunID:     run.ID,
+			Requeued:  int64(len(ids)),
+			Completed: updated.Status == RenormalizationStatusCompleted,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func newRenormalizationRunResponse(run db.CatalogRenormalizationRun) *api_models.CatalogRenormalizationRunResponse {
+	resp := &api_models.CatalogRenormalizationRunResponse{
+		ID:                  run.ID,
+		NormVersion:         run.NormVersion,
+		Status:              run.Status,
+		TotalPositions:      run.TotalPositions,
+		RequeuedPositions:   run.RequeuedPositions,
+		CacheEntriesDeleted: run.CacheEntriesDeleted,
+		StartedAt:           run.StartedAt,
+	}
+	if run.StartedBy.Valid {
+		startedBy := run.StartedBy.Int64
+		resp.StartedBy = &startedBy
+	}
+	if run.FinishedAt.Valid {
+		finishedAt := run.FinishedAt.Time
+		resp.FinishedAt = &finishedAt
+	}
+	return resp
+}
@@ -0,0 +1,191 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR CATALOG RE-NORMALIZATION (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Stale matches — after lemmatization rules change, old matching_cache entries must not be served
+2. Empty RAG index — positions are requeued in batches, not all at once
+3. Double runs — a second bump while a run is in progress is rejected with 409
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: BumpNormVersion
+- GIVEN active norm_version 1
+  WHEN BumpNormVersion is called
+  THEN version 2 is stored, older cache entries are deleted and a run is created
+
+- GIVEN a run already in progress (unique violation)
+  THEN ConflictError
+
+SCENARIO 2: ProcessRenormalizationBatch
+- GIVEN a running run
+  WHEN a batch is processed
+  THEN positions after the cursor are requeued and the cursor moves to the max id
+
+- GIVEN an empty batch
+  THEN the run is completed
+
+- GIVEN no running run
+  THEN nil result, no error
+*/
+
+var renormalizationRunColumns = []string{
+	"id", "norm_version", "status", "total_positions", "requeued_positions",
+	"last_position_id", "cache_entries_deleted", "started_by", "started_at", "finished_at",
+}
+
+func TestBumpNormVersion_Success(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	now := time.Now()
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT COALESCE").
+				WillReturnRows(sqlmock.NewRows([]string{"norm_version"}).AddRow(int16(1)))
+			mock.ExpectExec("INSERT INTO system_settings").
+				WithArgs(int16(2), "7").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec("DELETE FROM matching_cache").
+				WithArgs(int16(2)).
+				WillReturnResult(sqlmock.NewResult(0, 15))
+			mock.ExpectQuery("SELECT COUNT").
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(int64(120)))
+			mock.ExpectQuery("INSERT INTO catalog_renormalization_runs").
+				WithArgs(int16(2), int64(120), int64(15), sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows(renormalizationRunColumns).
+					AddRow(int64(3), int16(2), "running", int64(120), int64(0), int64(0), int64(15), int64(7), now, nil))
+		}))
+
+	run, err := service.BumpNormVersion(context.Background(), 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), run.ID)
+	assert.Equal(t, int16(2), run.NormVersion)
+	assert.Equal(t, RenormalizationStatusRunning, run.Status)
+	assert.Equal(t, int64(120), run.TotalPositions)
+	assert.Equal(t, int64(15), run.CacheEntriesDeleted)
+	require.NotNil(t, run.StartedBy)
+	assert.Equal(t, int64(7), *run.StartedBy)
+	assert.Nil(t, run.FinishedAt)
+}
+
+func TestBumpNormVersion_AlreadyRunning(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT COALESCE").
+				WillReturnRows(sqlmock.NewRows([]string{"norm_version"}).AddRow(int16(2)))
+			mock.ExpectExec("INSERT INTO system_settings").WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec("DELETE FROM matching_cache").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery("SELECT COUNT").
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(int64(10)))
+			mock.ExpectQuery("INSERT INTO catalog_renormalization_runs").
+				WillReturnError(&pq.Error{Code: "23505"})
+		}))
+
+	run, err := service.BumpNormVersion(context.Background(), 7)
+
+	require.Error(t, err)
+	assert.Nil(t, run)
+	var conflictErr *apierrors.ConflictError
+	assert.True(t, errors.As(err, &conflictErr))
+}
+
+func TestProcessRenormalizationBatch(t *testing.T) {
+	now := time.Now()
+	running := func(lastID int64) *sqlmock.Rows {
+		return sqlmock.NewRows(renormalizationRunColumns).
+			AddRow(int64(3), int16(2), "running", int64(5), int64(2), lastID, int64(0), nil, now, nil)
+	}
+
+	t.Run("requeues batch and moves cursor", func(t *testing.T) {
+		service, mockStore := setupTestService(t)
+		mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).
+			DoAndReturn(execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("FOR UPDATE SKIP LOCKED").WillReturnRows(running(20))
+				mock.ExpectQuery("UPDATE catalog_positions").
+					WithArgs(int64(20), int32(3)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(31)).AddRow(int64(25)).AddRow(int64(40)))
+				mock.ExpectQuery("UPDATE catalog_renormalization_runs").
+					WithArgs(int64(3), int64(40), int64(3)).
+					WillReturnRows(sqlmock.NewRows(renormalizationRunColumns).
+						AddRow(int64(3), int16(2), "running", int64(5), int64(5), int64(40), int64(0), nil, now, nil))
+			}))
+
+		result, err := service.ProcessRenormalizationBatch(context.Background(), 3)
+
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, int64(3), result.RunID)
+		assert.Equal(t, int64(3), result.Requeued)
+		assert.False(t, result.Completed)
+	})
+
+	t.Run("empty batch completes run", func(t *testing.T) {
+		service, mockStore := setupTestService(t)
+		mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).
+			DoAndReturn(execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("FOR UPDATE SKIP LOCKED").WillReturnRows(running(40))
+				mock.ExpectQuery("UPDATE catalog_positions").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery("UPDATE catalog_renormalization_runs").
+					WithArgs(int64(0), int64(40), int64(3)).
+					WillReturnRows(sqlmock.NewRows(renormalizationRunColumns).
+						AddRow(int64(3), int16(2), "completed", int64(5), int64(5), int64(40), int64(0), nil, now, now))
+			}))
+
+		result, err := service.ProcessRenormalizationBatch(context.Background(), 3)
+
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, int64(0), result.Requeued)
+		assert.True(t, result.Completed)
+	})
+
+	t.Run("no running run", func(t *testing.T) {
+		service, mockStore := setupTestService(t)
+		mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).
+			DoAndReturn(execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("FOR UPDATE SKIP LOCKED").WillReturnError(sql.ErrNoRows)
+			}))
+
+		result, err := service.ProcessRenormalizationBatch(context.Background(), 3)
+
+		require.NoError(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestGetNormVersion_NoRuns(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	mockStore.EXPECT().GetActiveNormVersion(gomock.Any()).Return(int16(1), nil)
+	mockStore.EXPECT().GetLatestCatalogRenormalizationRun(gomock.Any()).
+		Return(db.CatalogRenormalizationRun{}, sql.ErrNoRows)
+
+	resp, err := service.GetNormVersion(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int16(1), resp.NormVersion)
+	assert.Nil(t, resp.Renormalization)
+}
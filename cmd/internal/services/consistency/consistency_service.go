@@ -0,0 +1,264 @@
+// Package consistency сверяет сохранённые итоги предложения с суммой его позиций.
+//
+// Проверка ловит ошибки извлечения парсера после импорта: потерянные или
+// задвоенные строки, неверно распознанные числа. Итог каждой главы сравнивается
+// с суммой позиций, ссылающихся на неё (chapter_ref) напрямую или через вложенные
+// главы; сумма всех позиций — с итоговыми строками предложения. Достаточно, чтобы
+// с суммой совпала хотя бы одна итоговая строка: остальные (НДС, итог с НДС)
+// законно от неё отличаются.
+//
+// Проверка полноты (coverage.go) сравнивает перечень работ предложения с
+// baseline лота: какие позиции не оценены, оценены нулём или с другим объёмом.
+//
+// Суммы считаются точно (decimal) из строк NUMERIC; допуск задаётся в
+// config.ConsistencyConfig, так как округления в XLSX дают расхождения в копейках.
+package consistency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// maxSummaryLines — защитный лимит итоговых строк одного предложения.
+const maxSummaryLines = 1000
+
+// ConsistencyService проверяет согласованность итогов предложений.
+type ConsistencyService struct {
+	store  db.Store
+	logger logging.Logger
+	cfg    config.ConsistencyConfig
+}
+
+// NewConsistencyService создаёт новый экземпляр ConsistencyService.
+func NewConsistencyService(store db.Store, logger logging.Logger, cfg config.ConsistencyConfig) *ConsistencyService {
+	return &ConsistencyService{
+		store:  store,
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+// CheckProposal пересчитывает итоги глав и предложения по позициям и сравнивает
+// их с сохранёнными. Предложения мягко удалённого тендера не находятся.
+func (s *ConsistencyService) CheckProposal(ctx context.Context, proposalID int64) (*api_models.ProposalConsistencyReport, error) {
+	logger := s.logger.WithField("method", "CheckProposal").WithField("proposal_id", proposalID)
+
+	if _, err := s.getVisibleProposal(ctx, proposalID); err != nil {
+		return nil, err
+	}
+
+	positions, err := s.store.ListPositionsForEstimate(ctx, proposalID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения позиций предложения %d: %w", proposalID, err)
+	}
+	summaries, err := s.store.ListProposalSummaryLinesByProposalID(ctx, db.ListProposalSummaryLinesByProposalIDParams{
+		ProposalID: proposalID,
+		Limit:      maxSummaryLines,
+		Offset:     0,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения итоговых строк предложения %d: %w", proposalID, err)
+	}
+
+	report := s.buildReport(proposalID, positions, summaries)
+	if !report.Consistent {
+		logger.Warnf("Итоги предложения не сходятся: расхождений %d", len(report.Discrepancies))
+	}
+	return report, nil
+}
+
+// getVisibleProposal возвращает предложение. Предложения мягко удалённого
+// тендера скрываются вместе с ним.
+func (s *ConsistencyService) getVisibleProposal(ctx context.Context, proposalID int64) (db.Proposal, error) {
+	if proposalID <= 0 {
+		return db.Proposal{}, apierrors.NewValidationError("request.id_positive_got", proposalID)
+	}
+
+	proposal, err := s.store.GetProposalByID(ctx, proposalID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.Proposal{}, apierrors.NewNotFoundError("consistency.proposal_not_found", proposalID)
+		}
+		return db.Proposal{}, fmt.Errorf("ошибка получения предложения %d: %w", proposalID, err)
+	}
+	lot, err := s.store.GetLotByID(ctx, proposal.LotID)
+	if err != nil {
+		return db.Proposal{}, fmt.Errorf("ошибка получения лота предложения %d: %w", proposalID, err)
+	}
+	tender, err := s.store.GetTenderByID(ctx, lot.TenderID)
+	if err != nil {
+		return db.Proposal{}, fmt.Errorf("ошибка получения тендера предложения %d: %w", proposalID, err)
+	}
+	if tender.DeletedAt.Valid {
+		return db.Proposal{}, apierrors.NewNotFoundError("consistency.proposal_not_found", proposalID)
+	}
+	return proposal, nil
+}
+
+// positionNode — строка предложения для пересчёта: глава или позиция.
+type positionNode struct {
+	number    string
+	parentRef string
+	title     string
+	isChapter bool
+	total     *decimal.Decimal
+}
+
+func (s *ConsistencyService) buildReport(
+	proposalID int64,
+	rows []db.ListPositionsForEstimateRow,
+	summaries []db.ProposalSummaryLine,
+) *api_models.ProposalConsistencyReport {
+	report := &api_models.ProposalConsistencyReport{
+		ProposalID:    proposalID,
+		Consistent:    true,
+		AbsTolerance:  s.cfg.AbsTolerance,
+		RelTolerance:  s.cfg.RelTolerance,
+		Chapters:      make([]api_models.ProposalChapterCheck, 0),
+		SummaryLines:  make([]api_models.ProposalSummaryCheck, 0, len(summaries)),
+		Discrepancies: make([]api_models.ImportValidationIssue, 0),
+	}
+
+	nodes := make([]positionNode, 0, len(rows))
+	children := make(map[string][]int)
+	positionsTotal := decimal.Zero
+	for _, row := range rows {
+		node := positionNode{
+			number:    strings.TrimSpace(row.ItemNumberInProposal.String),
+			parentRef: strings.TrimSpace(row.ChapterRefInProposal.String),
+			title:     row.JobTitleInProposal,
+			isChapter: row.IsChapter,
+			total:     parseNumeric(row.TotalCostTotal),
+		}
+		if node.parentRef != "" {
+			children[node.parentRef] = append(children[node.parentRef], len(nodes))
+		}
+		if !node.isChapter && node.total != nil {
+			positionsTotal = positionsTotal.Add(*node.total)
+			report.PositionsCount++
+		}
+		nodes = append(nodes, node)
+	}
+	report.PositionsTotal = api_models.NewMoney(positionsTotal)
+
+	// Главы: итог главы против суммы всех позиций её поддерева
+	for _, node := range nodes {
+		if !node.isChapter || node.number == "" {
+			continue
+		}
+		computed, count := sumSubtree(node.number, nodes, children, map[string]bool{})
+		check := api_models.ProposalChapterCheck{
+			Number:         node.number,
+			Title:          node.title,
+			ComputedTotal:  api_models.NewMoney(computed),
+			PositionsCount: count,
+			Consistent:     true,
+		}
+		if node.total != nil {
+			diff := node.total.Sub(computed)
+			check.StoredTotal = api_models.MoneyPtr(*node.total)
+			check.Difference = api_models.MoneyPtr(diff)
+			// Глава без оценённых позиций — нечего сверять
+			if count > 0 && !s.withinTolerance(diff, computed) {
+				check.Consistent = false
+				report.Discrepancies = append(report.Discrepancies, api_models.ImportValidationIssue{
+					Code: "chapter_mismatch",
+					Path: fmt.Sprintf("chapters[%q]", node.number),
+					Message: fmt.Sprintf("итог главы «%s» %s не совпадает с суммой позиций %s (разница %s)",
+						node.title, check.StoredTotal, check.ComputedTotal, check.Difference),
+				})
+			}
+		}
+		report.Chapters = append(report.Chapters, check)
+	}
+
+	// Итоговые строки: с суммой позиций должна совпасть хотя бы одна
+	var (
+		anyMatch      bool
+		pricedSummary []string
+	)
+	for _, line := range summaries {
+		check := api_models.ProposalSummaryCheck{SummaryKey: line.SummaryKey, JobTitle: line.JobTitle}
+		if total := parseNumeric(line.TotalCost); total != nil {
+			diff := total.Sub(positionsTotal)
+			check.StoredTotal = api_models.MoneyPtr(*total)
+			check.Difference = api_models.MoneyPtr(diff)
+			check.Matches = s.withinTolerance(diff, positionsTotal)
+			anyMatch = anyMatch || check.Matches
+			pricedSummary = append(pricedSummary, fmt.Sprintf("%s=%s", line.SummaryKey, check.StoredTotal))
+		}
+		report.SummaryLines = append(report.SummaryLines, check)
+	}
+	if report.PositionsCount > 0 && len(pricedSummary) > 0 && !anyMatch {
+		report.Discrepancies = append(report.Discrepancies, api_models.ImportValidationIssue{
+			Code: "summary_mismatch",
+			Path: "summary",
+			Message: fmt.Sprintf("сумма позиций %s не совпадает ни с одной итоговой строкой (%s)",
+				report.PositionsTotal, strings.Join(pricedSummary, ", ")),
+		})
+	}
+
+	report.Consistent = len(report.Discrepancies) == 0
+	return report
+}
+
+// sumSubtree суммирует итоги позиций (не глав), вложенных в главу number на
+// любую глубину. visited защищает от циклических ссылок chapter_ref.
+func sumSubtree(number string, nodes []positionNode, children map[string][]int, visited map[string]bool) (decimal.Decimal, int) {
+	sum := decimal.Zero
+	count := 0
+	if visited[number] {
+		return sum, count
+	}
+	visited[number] = true
+
+	for _, idx := range children[number] {
+		child := nodes[idx]
+		if child.isChapter {
+			if child.number == "" {
+				continue
+			}
+			childSum, childCount := sumSubtree(child.number, nodes, children, visited)
+			sum = sum.Add(childSum)
+			count += childCount
+			continue
+		}
+		if child.total != nil {
+			sum = sum.Add(*child.total)
+			count++
+		}
+	}
+	return sum, count
+}
+
+// withinTolerance — |diff| не превышает max(abs, |base| * rel).
+func (s *ConsistencyService) withinTolerance(diff, base decimal.Decimal) bool {
+	limit := decimal.Max(
+		decimal.NewFromFloat(s.cfg.AbsTolerance),
+		base.Abs().Mul(decimal.NewFromFloat(s.cfg.RelTolerance)),
+	)
+	return diff.Abs().LessThanOrEqual(limit)
+}
+
+// parseNumeric разбирает NUMERIC-строку; nil — NULL или нечисловое значение.
+func parseNumeric(v sql.NullString) *decimal.Decimal {
+	if !v.Valid {
+		return nil
+	}
+	d, err := decimal.NewFromString(strings.TrimSpace(v.String))
+	if err != nil {
+		return nil
+	}
+	return &d
+}
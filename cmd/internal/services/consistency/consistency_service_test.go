@@ -0,0 +1,197 @@
+package consistency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR PROPOSAL CONSISTENCY (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Silent parser errors — lost or duplicated rows show up as chapter / summary mismatches
+2. False alarms — rounding within the configured tolerance is not reported
+3. Leaks — proposals of a soft-deleted tender must not be visible
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Consistent proposal
+- GIVEN nested chapters whose totals equal the sum of their positions (within tolerance)
+  AND a summary line "total" equal to the positions sum, plus a VAT line
+  WHEN CheckProposal is called
+  THEN the report is consistent, nested positions count towards the parent chapter
+
+SCENARIO 2: Discrepancies
+- GIVEN a chapter total that differs from its positions beyond tolerance
+  AND no summary line matching the positions sum
+  WHEN CheckProposal is called
+  THEN chapter_mismatch and summary_mismatch issues are reported
+
+SCENARIO 3: Errors
+- GIVEN id <= 0 → ValidationError, no DB calls
+- GIVEN unknown proposal → NotFoundError
+- GIVEN proposal of a soft-deleted tender → NotFoundError
+*/
+
+func setupTestService(t *testing.T) (*ConsistencyService, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	cfg := config.ConsistencyConfig{AbsTolerance: 1, RelTolerance: 0.001}
+	return NewConsistencyService(mockStore, testutil.NewMockLogger(), cfg), mockStore
+}
+
+func ns(v string) sql.NullString {
+	return sql.NullString{String: v, Valid: true}
+}
+
+func chapter(number, ref, title, total string) db.ListPositionsForEstimateRow {
+	row := db.ListPositionsForEstimateRow{
+		ItemNumberInProposal: ns(number),
+		JobTitleInProposal:   title,
+		IsChapter:            true,
+	}
+	if ref != "" {
+		row.ChapterRefInProposal = ns(ref)
+	}
+	if total != "" {
+		row.TotalCostTotal = ns(total)
+	}
+	return row
+}
+
+func position(ref, title, total string) db.ListPositionsForEstimateRow {
+	return db.ListPositionsForEstimateRow{
+		ChapterRefInProposal: ns(ref),
+		JobTitleInProposal:   title,
+		TotalCostTotal:       ns(total),
+	}
+}
+
+func expectVisibleProposal(mockStore *db.MockStore, proposalID int64) {
+	mockStore.EXPECT().GetProposalByID(gomock.Any(), proposalID).
+		Return(db.Proposal{ID: proposalID, LotID: 5}, nil)
+	mockStore.EXPECT().GetLotByID(gomock.Any(), int64(5)).
+		Return(db.Lot{ID: 5, TenderID: 10}, nil)
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(10)).
+		Return(db.Tender{ID: 10}, nil)
+}
+
+func expectSummaryLines(mockStore *db.MockStore, proposalID int64, lines ...db.ProposalSummaryLine) {
+	mockStore.EXPECT().ListProposalSummaryLinesByProposalID(gomock.Any(), db.ListProposalSummaryLinesByProposalIDParams{
+		ProposalID: proposalID,
+		Limit:      maxSummaryLines,
+		Offset:     0,
+	}).Return(lines, nil)
+}
+
+func TestCheckProposal_Consistent(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	// GIVEN глава 1 с подглавой 1.1; итог 1 — 1500.40 (в пределах допуска от 1500.00)
+	expectVisibleProposal(mockStore, 7)
+	mockStore.EXPECT().ListPositionsForEstimate(gomock.Any(), int64(7)).Return([]db.ListPositionsForEstimateRow{
+		chapter("1", "", "Общестроительные работы", "1500.40"),
+		position("1", "Монтаж", "1000.00"),
+		chapter("1.1", "1", "Кладка", "500.00"),
+		position("1.1", "Кладка стен", "300.00"),
+		position("1.1", "Кладка перегородок", "200.00"),
+	}, nil)
+	expectSummaryLines(mockStore, 7,
+		db.ProposalSummaryLine{SummaryKey: "total", JobTitle: "Итого", TotalCost: ns("1500.00")},
+		db.ProposalSummaryLine{SummaryKey: "total_vat", JobTitle: "Итого с НДС", TotalCost: ns("1800.00")},
+	)
+
+	report, err := service.CheckProposal(context.Background(), 7)
+
+	require.NoError(t, err)
+	assert.True(t, report.Consistent)
+	assert.Equal(t, 3, report.PositionsCount)
+	assert.Equal(t, "1500.00", report.PositionsTotal.String())
+	assert.Empty(t, report.Discrepancies)
+	assert.NotNil(t, report.Discrepancies)
+
+	require.Len(t, report.Chapters, 2)
+	assert.Equal(t, "1", report.Chapters[0].Number)
+	assert.Equal(t, "1500.00", report.Chapters[0].ComputedTotal.String())
+	assert.Equal(t, 3, report.Chapters[0].PositionsCount)
+	require.NotNil(t, report.Chapters[0].Difference)
+	assert.Equal(t, "0.40", report.Chapters[0].Difference.String())
+	assert.True(t, report.Chapters[0].Consistent)
+
+	require.Len(t, report.SummaryLines, 2)
+	assert.True(t, report.SummaryLines[0].Matches)
+	assert.False(t, report.SummaryLines[1].Matches)
+}
+
+func TestCheckProposal_Discrepancies(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	// GIVEN итог главы 2 завышен на 100.00, итоговая строка не совпадает с суммой позиций
+	expectVisibleProposal(mockStore, 7)
+	mockStore.EXPECT().ListPositionsForEstimate(gomock.Any(), int64(7)).Return([]db.ListPositionsForEstimateRow{
+		chapter("2", "", "Отделка", "900.00"),
+		position("2", "Штукатурка", "500.00"),
+		position("2", "Окраска", "300.00"),
+	}, nil)
+	expectSummaryLines(mockStore, 7,
+		db.ProposalSummaryLine{SummaryKey: "total", JobTitle: "Итого", TotalCost: ns("900.00")},
+	)
+
+	report, err := service.CheckProposal(context.Background(), 7)
+
+	require.NoError(t, err)
+	assert.False(t, report.Consistent)
+	assert.Equal(t, "800.00", report.PositionsTotal.String())
+	require.Len(t, report.Chapters, 1)
+	assert.False(t, report.Chapters[0].Consistent)
+	assert.Equal(t, "100.00", report.Chapters[0].Difference.String())
+
+	require.Len(t, report.Discrepancies, 2)
+	assert.Equal(t, "chapter_mismatch", report.Discrepancies[0].Code)
+	assert.Equal(t, "summary_mismatch", report.Discrepancies[1].Code)
+}
+
+func TestCheckProposal_Errors(t *testing.T) {
+	t.Run("invalid id", func(t *testing.T) {
+		service, _ := setupTestService(t)
+		_, err := service.CheckProposal(context.Background(), 0)
+		var validationErr *apierrors.ValidationError
+		assert.True(t, errors.As(err, &validationErr))
+	})
+
+	t.Run("unknown proposal", func(t *testing.T) {
+		service, mockStore := setupTestService(t)
+		mockStore.EXPECT().GetProposalByID(gomock.Any(), int64(7)).Return(db.Proposal{}, sql.ErrNoRows)
+
+		_, err := service.CheckProposal(context.Background(), 7)
+		var notFoundErr *apierrors.NotFoundError
+		assert.True(t, errors.As(err, &notFoundErr))
+	})
+
+	t.Run("soft-deleted tender", func(t *testing.T) {
+		service, mockStore := setupTestService(t)
+		mockStore.EXPECT().GetProposalByID(gomock.Any(), int64(7)).Return(db.Proposal{ID: 7, LotID: 5}, nil)
+		mockStore.EXPECT().GetLotByID(gomock.Any(), int64(5)).Return(db.Lot{ID: 5, TenderID: 10}, nil)
+		mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(10)).
+			Return(db.Tender{ID: 10, DeletedAt: sql.NullTime{Time: time.Now(), Valid: true}}, nil)
+
+		_, err := service.CheckProposal(context.Background(), 7)
+		var notFoundErr *apierrors.NotFoundError
+		assert.True(t, errors.As(err, &notFoundErr))
+	})
+}
@@ -0,0 +1,215 @@
+package consistency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+// CheckCoverage сравнивает перечень работ предложения с baseline лота:
+// какие позиции baseline подрядчик не оценил, оценил нулём или с другим
+// объёмом, и какие позиции он добавил сам.
+func (s *ConsistencyService) CheckCoverage(ctx context.Context, proposalID int64) (*api_models.ProposalCoverageReport, error) {
+	logger := s.logger.WithField("method", "CheckCoverage").WithField("proposal_id", proposalID)
+
+	proposal, err := s.getVisibleProposal(ctx, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	if proposal.IsBaseline {
+		return nil, apierrors.NewValidationError("consistency.proposal_is_baseline", proposalID)
+	}
+
+	baseline, err := s.store.GetBaselineProposalForLot(ctx, proposal.LotID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewValidationError("consistency.no_baseline", proposal.LotID)
+		}
+		return nil, fmt.Errorf("ошибка получения baseline лота %d: %w", proposal.LotID, err)
+	}
+
+	baselineRows, err := s.store.ListPositionsForEstimate(ctx, baseline.ID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения позиций baseline %d: %w", baseline.ID, err)
+	}
+	rows, err := s.store.ListPositionsForEstimate(ctx, proposalID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения позиций предложения %d: %w", proposalID, err)
+	}
+
+	report := buildCoverage(baselineRows, rows)
+	if report.BaselineCount == 0 {
+		return nil, apierrors.NewValidationError("consistency.baseline_empty", proposal.LotID)
+	}
+	report.ProposalID = proposalID
+	report.BaselineProposalID = baseline.ID
+
+	logger.Infof("Полнота предложения %s%%: не оценено %d, нулевых цен %d, изменён объём %d, лишних %d",
+		report.CoveragePercent, report.MissingCount, report.ZeroPriceCount, report.QuantityChangedCount, report.ExtraCount)
+	return report, nil
+}
+
+// buildCoverage связывает позиции предложения с позициями baseline и
+// собирает отчёт. Позиция предложения связывается не больше чем с одной
+// позицией baseline: сначала по позиции каталога (при нескольких кандидатах —
+// с тем же ключом), затем по ключу позиции.
+func buildCoverage(baselineRows, rows []db.ListPositionsForEstimateRow) *api_models.ProposalCoverageReport {
+	report := &api_models.ProposalCoverageReport{Issues: make([]api_models.ProposalCoverageIssue, 0)}
+
+	byCatalog := make(map[int64][]int)
+	byKey := make(map[string][]int)
+	for i, row := range rows {
+		if row.IsChapter {
+			continue
+		}
+		if row.CatalogPositionID.Valid {
+			byCatalog[row.CatalogPositionID.Int64] = append(byCatalog[row.CatalogPositionID.Int64], i)
+		}
+		byKey[row.PositionKeyInProposal] = append(byKey[row.PositionKeyInProposal], i)
+	}
+	used := make(map[int]bool)
+	take := func(candidates []int, key string) (int, bool) {
+		fallback := -1
+		for _, i := range candidates {
+			if used[i] {
+				continue
+			}
+			if rows[i].PositionKeyInProposal == key {
+				return i, true
+			}
+			if fallback < 0 {
+				fallback = i
+			}
+		}
+		return fallback, fallback >= 0
+	}
+
+	for _, base := range baselineRows {
+		if base.IsChapter {
+			continue
+		}
+		report.BaselineCount++
+
+		issue := api_models.ProposalCoverageIssue{
+			PositionKey:        base.PositionKeyInProposal,
+			JobTitle:           base.JobTitleInProposal,
+			BaselinePositionID: int64Ptr(base.ID),
+			BaselineQuantity:   stringPtr(base.Quantity),
+		}
+		if base.CatalogPositionID.Valid {
+			issue.CatalogPositionID = int64Ptr(base.CatalogPositionID.Int64)
+		}
+
+		match, ok := -1, false
+		if base.CatalogPositionID.Valid {
+			match, ok = take(byCatalog[base.CatalogPositionID.Int64], base.PositionKeyInProposal)
+			issue.LinkedBy = api_models.CoverageLinkedByCatalog
+		}
+		if !ok {
+			// По ключу связываем, только если хотя бы у одной стороны нет позиции каталога:
+			// разные позиции каталога под одним ключом — это разные работы
+			for _, i := range byKey[base.PositionKeyInProposal] {
+				if !used[i] && (!rows[i].CatalogPositionID.Valid || !base.CatalogPositionID.Valid) {
+					match, ok = i, true
+					break
+				}
+			}
+			issue.LinkedBy = api_models.CoverageLinkedByKey
+		}
+		if !ok {
+			issue.Status = api_models.CoverageMissing
+			issue.LinkedBy = ""
+			report.MissingCount++
+			report.Issues = append(report.Issues, issue)
+			continue
+		}
+		used[match] = true
+		row := rows[match]
+		issue.PositionItemID = int64Ptr(row.ID)
+
+		if !isPositive(row.UnitCostTotal) && !isPositive(row.TotalCostTotal) {
+			issue.Status = api_models.CoverageZeroPrice
+			report.ZeroPriceCount++
+			report.Issues = append(report.Issues, issue)
+			continue
+		}
+		report.CoveredCount++
+
+		offered := row.SuggestedQuantity
+		if !offered.Valid {
+			offered = row.Quantity
+		}
+		if quantityChanged(base.Quantity, offered) {
+			issue.Status = api_models.CoverageQuantityChanged
+			issue.Quantity = stringPtr(offered)
+			report.QuantityChangedCount++
+			report.Issues = append(report.Issues, issue)
+		}
+	}
+
+	for i, row := range rows {
+		if row.IsChapter || used[i] {
+			continue
+		}
+		issue := api_models.ProposalCoverageIssue{
+			Status:         api_models.CoverageExtra,
+			PositionKey:    row.PositionKeyInProposal,
+			JobTitle:       row.JobTitleInProposal,
+			PositionItemID: int64Ptr(row.ID),
+			Quantity:       stringPtr(row.Quantity),
+		}
+		if row.CatalogPositionID.Valid {
+			issue.CatalogPositionID = int64Ptr(row.CatalogPositionID.Int64)
+		}
+		report.ExtraCount++
+		report.Issues = append(report.Issues, issue)
+	}
+
+	percent := decimal.NewFromInt(100)
+	if report.BaselineCount > 0 {
+		percent = decimal.NewFromInt(int64(report.CoveredCount)).
+			Mul(decimal.NewFromInt(100)).
+			Div(decimal.NewFromInt(int64(report.BaselineCount)))
+	}
+	report.CoveragePercent = percent.StringFixed(2)
+	return report
+}
+
+// quantityChanged — оба объёма заданы и различаются как числа ("10" == "10.000").
+func quantityChanged(baseline, offered sql.NullString) bool {
+	if !baseline.Valid || !offered.Valid {
+		return false
+	}
+	a, errA := decimal.NewFromString(baseline.String)
+	b, errB := decimal.NewFromString(offered.String)
+	if errA != nil || errB != nil {
+		return baseline.String != offered.String
+	}
+	return !a.Equal(b)
+}
+
+func isPositive(value sql.NullString) bool {
+	if !value.Valid {
+		return false
+	}
+	d, err := decimal.NewFromString(value.String)
+	return err == nil && d.IsPositive()
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func stringPtr(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+	return &value.String
+}
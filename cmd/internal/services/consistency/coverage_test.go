@@ -0,0 +1,193 @@
+package consistency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR PROPOSAL COVERAGE (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. A cheap proposal that is cheap only because part of the scope was not quoted
+2. Zero prices and silently changed quantities hidden among hundreds of rows
+3. Wrong linking — two different works sharing a position key treated as one
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Linking and statuses
+- GIVEN a baseline position quoted by catalog position under another key
+  THEN it is covered, linked_by catalog_position
+- GIVEN a baseline position without catalog match and a contractor row with the same key
+  THEN it is linked by position key
+- GIVEN a baseline position not quoted at all THEN missing
+- GIVEN a quoted position with NULL/zero cost THEN zero_price, not covered
+- GIVEN suggested_quantity differing from the baseline quantity THEN quantity_changed, still covered
+- GIVEN "10" vs "10.000" THEN quantities are equal
+- GIVEN a contractor row linked to nothing THEN extra
+- Chapters are ignored on both sides
+- coverage_percent = covered / baseline positions
+
+SCENARIO 2: Different catalog positions under one key
+- GIVEN baseline and contractor rows with the same key but different catalog positions
+  THEN they are not linked: missing + extra
+
+SCENARIO 3: Errors
+- GIVEN a baseline proposal → ValidationError
+- GIVEN a lot without baseline → ValidationError
+- GIVEN a baseline without positions → ValidationError
+*/
+
+func ni(v int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: v, Valid: true}
+}
+
+func item(id, catalogID int64, key, quantity, unitCost string) db.ListPositionsForEstimateRow {
+	row := db.ListPositionsForEstimateRow{
+		ID:                    id,
+		PositionKeyInProposal: key,
+		JobTitleInProposal:    "Работа " + key,
+		Quantity:              ns(quantity),
+	}
+	if catalogID > 0 {
+		row.CatalogPositionID = ni(catalogID)
+	}
+	if unitCost != "" {
+		row.UnitCostTotal = ns(unitCost)
+	}
+	return row
+}
+
+func TestBuildCoverage(t *testing.T) {
+	changed := item(23, 30, "3", "10", "50.00")
+	changed.SuggestedQuantity = ns("12")
+
+	baselineRows := []db.ListPositionsForEstimateRow{
+		chapter("1", "", "Земляные работы", ""),
+		item(1, 10, "1", "100", ""),
+		item(2, 0, "2", "5", ""),
+		item(3, 30, "3", "10", ""),
+		item(4, 40, "4", "1", ""),
+		item(5, 50, "5", "2", ""),
+		item(6, 60, "6", "10", ""),
+	}
+	rows := []db.ListPositionsForEstimateRow{
+		chapter("1", "", "Земляные работы", ""),
+		item(21, 10, "1.1", "100", "120.00"), // Другой ключ, та же позиция каталога
+		item(22, 0, "2", "5", "80.00"),       // Связь по ключу
+		changed,
+		item(25, 50, "5", "2", "0"),
+		item(26, 60, "6", "10.000", "15.00"),
+		item(27, 70, "7", "3", "9.00"),
+	}
+
+	report := buildCoverage(baselineRows, rows)
+
+	assert.Equal(t, 6, report.BaselineCount)
+	assert.Equal(t, 4, report.CoveredCount)
+	assert.Equal(t, 1, report.MissingCount)
+	assert.Equal(t, 1, report.ZeroPriceCount)
+	assert.Equal(t, 1, report.QuantityChangedCount)
+	assert.Equal(t, 1, report.ExtraCount)
+	assert.Equal(t, "66.67", report.CoveragePercent)
+
+	require.Len(t, report.Issues, 4)
+	assert.Equal(t, api_models.CoverageQuantityChanged, report.Issues[0].Status)
+	assert.Equal(t, api_models.CoverageLinkedByCatalog, report.Issues[0].LinkedBy)
+	require.NotNil(t, report.Issues[0].Quantity)
+	assert.Equal(t, "12", *report.Issues[0].Quantity)
+	assert.Equal(t, "10", *report.Issues[0].BaselineQuantity)
+
+	assert.Equal(t, api_models.CoverageMissing, report.Issues[1].Status)
+	assert.Equal(t, "4", report.Issues[1].PositionKey)
+	assert.Nil(t, report.Issues[1].PositionItemID)
+	assert.Empty(t, report.Issues[1].LinkedBy)
+
+	assert.Equal(t, api_models.CoverageZeroPrice, report.Issues[2].Status)
+	assert.Equal(t, int64(25), *report.Issues[2].PositionItemID)
+
+	assert.Equal(t, api_models.CoverageExtra, report.Issues[3].Status)
+	assert.Equal(t, int64(27), *report.Issues[3].PositionItemID)
+	assert.Nil(t, report.Issues[3].BaselinePositionID)
+}
+
+func TestBuildCoverage_DifferentCatalogSameKey(t *testing.T) {
+	report := buildCoverage(
+		[]db.ListPositionsForEstimateRow{item(1, 10, "1", "1", "")},
+		[]db.ListPositionsForEstimateRow{item(21, 11, "1", "1", "100.00")},
+	)
+
+	assert.Equal(t, 1, report.MissingCount)
+	assert.Equal(t, 1, report.ExtraCount)
+	assert.Equal(t, "0.00", report.CoveragePercent)
+}
+
+func TestCheckCoverage(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	expectVisibleProposal(mockStore, 7)
+	mockStore.EXPECT().GetBaselineProposalForLot(gomock.Any(), int64(5)).
+		Return(db.Proposal{ID: 3, LotID: 5, IsBaseline: true}, nil)
+	mockStore.EXPECT().ListPositionsForEstimate(gomock.Any(), int64(3)).
+		Return([]db.ListPositionsForEstimateRow{item(1, 10, "1", "1", "")}, nil)
+	mockStore.EXPECT().ListPositionsForEstimate(gomock.Any(), int64(7)).
+		Return([]db.ListPositionsForEstimateRow{item(21, 10, "1", "1", "100.00")}, nil)
+
+	report, err := service.CheckCoverage(context.Background(), 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), report.ProposalID)
+	assert.Equal(t, int64(3), report.BaselineProposalID)
+	assert.Equal(t, "100.00", report.CoveragePercent)
+	assert.Empty(t, report.Issues)
+	assert.NotNil(t, report.Issues)
+}
+
+func TestCheckCoverage_Errors(t *testing.T) {
+	t.Run("baseline proposal", func(t *testing.T) {
+		service, mockStore := setupTestService(t)
+		mockStore.EXPECT().GetProposalByID(gomock.Any(), int64(3)).
+			Return(db.Proposal{ID: 3, LotID: 5, IsBaseline: true}, nil)
+		mockStore.EXPECT().GetLotByID(gomock.Any(), int64(5)).Return(db.Lot{ID: 5, TenderID: 10}, nil)
+		mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(10)).Return(db.Tender{ID: 10}, nil)
+
+		_, err := service.CheckCoverage(context.Background(), 3)
+		var validationErr *apierrors.ValidationError
+		assert.True(t, errors.As(err, &validationErr))
+	})
+
+	t.Run("lot without baseline", func(t *testing.T) {
+		service, mockStore := setupTestService(t)
+		expectVisibleProposal(mockStore, 7)
+		mockStore.EXPECT().GetBaselineProposalForLot(gomock.Any(), int64(5)).Return(db.Proposal{}, sql.ErrNoRows)
+
+		_, err := service.CheckCoverage(context.Background(), 7)
+		var validationErr *apierrors.ValidationError
+		assert.True(t, errors.As(err, &validationErr))
+	})
+
+	t.Run("empty baseline", func(t *testing.T) {
+		service, mockStore := setupTestService(t)
+		expectVisibleProposal(mockStore, 7)
+		mockStore.EXPECT().GetBaselineProposalForLot(gomock.Any(), int64(5)).Return(db.Proposal{ID: 3, LotID: 5}, nil)
+		mockStore.EXPECT().ListPositionsForEstimate(gomock.Any(), int64(3)).
+			Return([]db.ListPositionsForEstimateRow{chapter("1", "", "Глава", "")}, nil)
+		mockStore.EXPECT().ListPositionsForEstimate(gomock.Any(), int64(7)).Return(nil, nil)
+
+		_, err := service.CheckCoverage(context.Background(), 7)
+		var validationErr *apierrors.ValidationError
+		assert.True(t, errors.As(err, &validationErr))
+	})
+}
@@ -0,0 +1,244 @@
+package contractor
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+const (
+	// MaxAccreditationDocuments — сколько документов можно приложить к аккредитации.
+	MaxAccreditationDocuments = 20
+
+	maxDocumentTitle  = 255
+	maxDocumentNumber = 100
+	maxDocumentURL    = 2048
+)
+
+// UpdateAccreditation заменяет статус, срок и документы аккредитации
+// подрядчика. После ручной правки импорт статус больше не меняет.
+// Действующая аккредитация (accredited) с прошедшим сроком отклоняется:
+// для неё есть статус expired.
+func (s *ContractorService) UpdateAccreditation(
+	ctx context.Context,
+	contractorID int64,
+	req api_models.UpdateAccreditationRequest,
+) (*api_models.ContractorResponse, error) {
+	logger := s.logger.WithField("method", "UpdateAccreditation").WithField("contractor_id", contractorID)
+
+	if contractorID <= 0 {
+		return nil, apierrors.NewValidationError("request.id_positive_got", contractorID)
+	}
+	if !slices.Contains(api_models.AccreditationStatuses, req.Status) {
+		return nil, apierrors.NewValidationError("contractor.accreditation_status_invalid",
+			req.Status, strings.Join(api_models.AccreditationStatuses, ", "))
+	}
+
+	validUntil := sql.NullTime{}
+	if req.ValidUntil != nil {
+		date, err := parseDate("valid_until", *req.ValidUntil)
+		if err != nil {
+			return nil, err
+		}
+		validUntil = sql.NullTime{Time: date, Valid: true}
+	}
+	if req.Status == api_models.AccreditationAccredited && validUntil.Valid && validUntil.Time.Before(dateOf(s.now())) {
+		return nil, apierrors.NewValidationError("contractor.accreditation_date_passed", validUntil.Time.Format(time.DateOnly))
+	}
+
+	documents, err := normalizeDocuments(req.Documents)
+	if err != nil {
+		return nil, err
+	}
+	rawDocuments, err := json.Marshal(documents)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации документов аккредитации: %w", err)
+	}
+
+	c, err := s.store.UpdateContractorAccreditation(ctx, db.UpdateContractorAccreditationParams{
+		ID:                      contractorID,
+		AccreditationStatus:     req.Status,
+		AccreditationValidUntil: validUntil,
+		AccreditationDocuments:  rawDocuments,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewNotFoundError("contractor.not_found", contractorID)
+		}
+		return nil, fmt.Errorf("ошибка обновления аккредитации подрядчика %d: %w", contractorID, err)
+	}
+
+	response, err := contractorToResponse(c)
+	if err != nil {
+		return nil, err
+	}
+	until := "без срока"
+	if validUntil.Valid {
+		until = "до " + validUntil.Time.Format(time.DateOnly)
+	}
+	logger.Infof("Аккредитация подрядчика изменена: %s %s, документов %d", req.Status, until, len(documents))
+	return response, nil
+}
+
+// ListExpiringAccreditations возвращает подрядчиков, чья действующая
+// аккредитация заканчивается в ближайшие withinDays дней, и тех, у кого она
+// уже истекла (status = expired). Ближайший срок первым.
+func (s *ContractorService) ListExpiringAccreditations(
+	ctx context.Context,
+	withinDays int,
+	page, pageSize int32,
+) (*api_models.ExpiringAccreditationsResponse, error) {
+	if withinDays < 0 || withinDays > config.MaxAccreditationWarnDays {
+		return nil, apierrors.NewValidationError("contractor.within_days_range", config.MaxAccreditationWarnDays, withinDays)
+	}
+	if page < 1 {
+		return nil, apierrors.NewValidationError("request.page_positive_got", page)
+	}
+	if pageSize < 1 || pageSize > MaxPageSize {
+		return nil, apierrors.NewValidationError("request.page_size_range_got", MaxPageSize, pageSize)
+	}
+
+	today := dateOf(s.now())
+	before := today.AddDate(0, 0, withinDays)
+	rows, err := s.store.ListExpiringAccreditations(ctx, db.ListExpiringAccreditationsParams{
+		ValidUntilBefore: before,
+		PageLimit:        pageSize,
+		PageOffset:       (page - 1) * pageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения истекающих аккредитаций: %w", err)
+	}
+	total, err := s.store.CountExpiringAccreditations(ctx, before)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подсчёта истекающих аккредитаций: %w", err)
+	}
+
+	items := make([]api_models.ExpiringAccreditation, 0, len(rows))
+	for _, c := range rows {
+		documents, err := decodeDocuments(c.AccreditationDocuments)
+		if err != nil {
+			return nil, fmt.Errorf("подрядчик %d: %w", c.ID, err)
+		}
+		items = append(items, api_models.ExpiringAccreditation{
+			ContractorID: c.ID,
+			Title:        c.Title,
+			Inn:          c.Inn,
+			Status:       c.AccreditationStatus,
+			ValidUntil:   c.AccreditationValidUntil.Time.Format(time.DateOnly),
+			DaysLeft:     daysBetween(today, c.AccreditationValidUntil.Time),
+			Documents:    documents,
+		})
+	}
+
+	return &api_models.ExpiringAccreditationsResponse{
+		Items:      items,
+		AsOf:       today.Format(time.DateOnly),
+		WithinDays: withinDays,
+		TotalCount: total,
+		Page:       page,
+		PageSize:   pageSize,
+	}, nil
+}
+
+// normalizeDocuments обрезает пробелы и проверяет документы: наименование
+// обязательно, даты — ГГГГ-ММ-ДД, ссылка — абсолютный адрес http(s).
+func normalizeDocuments(documents []api_models.AccreditationDocument) ([]api_models.AccreditationDocument, error) {
+	if len(documents) > MaxAccreditationDocuments {
+		return nil, apierrors.NewValidationError("contractor.accreditation_documents_max", MaxAccreditationDocuments, len(documents))
+	}
+
+	result := make([]api_models.AccreditationDocument, 0, len(documents))
+	for i, doc := range documents {
+		path := fmt.Sprintf("documents[%d]", i)
+		doc.Title = strings.TrimSpace(doc.Title)
+		doc.Number = strings.TrimSpace(doc.Number)
+		doc.URL = strings.TrimSpace(doc.URL)
+
+		if doc.Title == "" || utf8.RuneCountInString(doc.Title) > maxDocumentTitle {
+			return nil, apierrors.NewValidationError("contractor.accreditation_document_title", path, maxDocumentTitle)
+		}
+		if utf8.RuneCountInString(doc.Number) > maxDocumentNumber {
+			return nil, apierrors.NewValidationError("contractor.accreditation_document_too_long", path+".number", maxDocumentNumber)
+		}
+		dates := []struct {
+			field string
+			value *string
+		}{{"issued_on", &doc.IssuedOn}, {"valid_until", &doc.ValidUntil}}
+		for _, date := range dates {
+			if *date.value = strings.TrimSpace(*date.value); *date.value == "" {
+				continue
+			}
+			if _, err := parseDate(path+"."+date.field, *date.value); err != nil {
+				return nil, err
+			}
+		}
+		if doc.URL != "" {
+			u, err := url.Parse(doc.URL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(doc.URL) > maxDocumentURL {
+				return nil, apierrors.NewValidationError("contractor.accreditation_document_url", path+".url", maxDocumentURL)
+			}
+		}
+		result = append(result, doc)
+	}
+	return result, nil
+}
+
+func toAccreditation(status string, validUntil sql.NullTime, rawDocuments json.RawMessage, source string) (api_models.ContractorAccreditation, error) {
+	documents, err := decodeDocuments(rawDocuments)
+	if err != nil {
+		return api_models.ContractorAccreditation{}, err
+	}
+	accreditation := api_models.ContractorAccreditation{
+		Status:    status,
+		Documents: documents,
+		Source:    source,
+	}
+	if validUntil.Valid {
+		date := validUntil.Time.Format(time.DateOnly)
+		accreditation.ValidUntil = &date
+	}
+	return accreditation, nil
+}
+
+func decodeDocuments(raw json.RawMessage) ([]api_models.AccreditationDocument, error) {
+	documents := make([]api_models.AccreditationDocument, 0)
+	if len(raw) == 0 {
+		return documents, nil
+	}
+	if err := json.Unmarshal(raw, &documents); err != nil {
+		return nil, fmt.Errorf("некорректные документы аккредитации: %w", err)
+	}
+	return documents, nil
+}
+
+func parseDate(field, value string) (time.Time, error) {
+	date, err := time.Parse(time.DateOnly, strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}, apierrors.NewValidationError("contractor.accreditation_date_invalid", field, value)
+	}
+	return date, nil
+}
+
+// dateOf возвращает календарный день t (в часовом поясе t) как полночь UTC:
+// так читаются значения столбцов date.
+func dateOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// daysBetween — число дней от from до to; отрицательное, если to раньше.
+func daysBetween(from, to time.Time) int {
+	return int(dateOf(to).Sub(dateOf(from)).Hours() / 24)
+}
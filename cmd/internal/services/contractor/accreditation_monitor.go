@@ -0,0 +1,92 @@
+package contractor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// AccreditationMonitor следит за сроками аккредитации подрядчиков (задание
+// планировщика contractor_accreditation).
+//
+// За запуск аккредитации с прошедшим сроком получают статус expired и событие
+// contractor.accreditation_expired, а те, что заканчиваются в ближайшие
+// contractors.accreditation_warn_days дней, — событие
+// contractor.accreditation_expiring. О каждом сроке оповещается один раз:
+// продление (новый valid_until) оповестит заново.
+type AccreditationMonitor struct {
+	store     db.Store
+	logger    logging.Logger
+	publisher events.Publisher
+	cfg       config.ContractorsConfig
+	now       func() time.Time // Подменяется в тестах
+}
+
+// NewAccreditationMonitor создаёт задание проверки сроков аккредитации.
+func NewAccreditationMonitor(store db.Store, logger logging.Logger, publisher events.Publisher, cfg config.ContractorsConfig) *AccreditationMonitor {
+	return &AccreditationMonitor{
+		store:     store,
+		logger:    logger,
+		publisher: publisher,
+		cfg:       cfg,
+		now:       time.Now,
+	}
+}
+
+// Run выполняет одну проверку (JobFunc задания планировщика). Статус и
+// отметка об оповещении сохраняются до публикации событий: сбой БД не
+// приводит к повторным оповещениям, а потерянное событие видно в
+// GET /api/v1/contractors/expiring-accreditation.
+func (m *AccreditationMonitor) Run(ctx context.Context) (string, error) {
+	logger := m.logger.WithField("job", "contractor_accreditation")
+	today := dateOf(m.now())
+
+	expired, err := m.store.ExpireContractorAccreditations(ctx, today)
+	if err != nil {
+		return "", fmt.Errorf("ошибка ExpireContractorAccreditations: %w", err)
+	}
+	for _, c := range expired {
+		events.Emit(ctx, m.publisher, logger, events.TypeContractorAccreditationExpired, c.ID, accreditationEvent(c, today))
+	}
+
+	expiring, err := m.store.ListAccreditationsToAlert(ctx, db.ListAccreditationsToAlertParams{
+		Today:            today,
+		ValidUntilBefore: today.AddDate(0, 0, m.cfg.AccreditationWarnDays),
+		PageLimit:        m.cfg.AccreditationBatchSize,
+	})
+	if err != nil {
+		return "", fmt.Errorf("ошибка ListAccreditationsToAlert: %w", err)
+	}
+	if len(expiring) > 0 {
+		ids := make([]int64, 0, len(expiring))
+		for _, c := range expiring {
+			ids = append(ids, c.ID)
+		}
+		if _, err := m.store.MarkAccreditationsAlerted(ctx, ids); err != nil {
+			return "", fmt.Errorf("ошибка MarkAccreditationsAlerted: %w", err)
+		}
+		for _, c := range expiring {
+			events.Emit(ctx, m.publisher, logger, events.TypeContractorAccreditationExpiring, c.ID, accreditationEvent(c, today))
+		}
+	}
+
+	if len(expired) > 0 || len(expiring) > 0 {
+		logger.Infof("Аккредитации: истекло %d, скоро истекает %d", len(expired), len(expiring))
+	}
+	return fmt.Sprintf("истекло аккредитаций: %d, скоро истекает: %d", len(expired), len(expiring)), nil
+}
+
+func accreditationEvent(c db.Contractor, today time.Time) events.ContractorAccreditationData {
+	return events.ContractorAccreditationData{
+		ContractorID: c.ID,
+		Title:        c.Title,
+		Inn:          c.Inn,
+		ValidUntil:   c.AccreditationValidUntil.Time.Format(time.DateOnly),
+		DaysLeft:     daysBetween(today, c.AccreditationValidUntil.Time),
+	}
+}
@@ -0,0 +1,309 @@
+package contractor
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR CONTRACTOR ACCREDITATION (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. A winner picked among contractors whose accreditation has lapsed unnoticed
+2. Alert fatigue — the same expiry date announced on every scheduler run
+3. Garbage accreditation data (unknown statuses, broken dates and links) saved by hand
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: UpdateAccreditation
+- GIVEN a valid status, date and documents with spaces around values
+  WHEN UpdateAccreditation is called
+  THEN trimmed documents are stored as JSON and the card carries the new accreditation
+- GIVEN an unknown status, a malformed date, status accredited with a past date,
+  a document without title or with a non-http link → ValidationError, no DB calls
+- GIVEN an unknown contractor → NotFoundError
+
+SCENARIO 2: ListExpiringAccreditations
+- GIVEN within_days = 30 and today = 2025-03-10
+  WHEN ListExpiringAccreditations is called
+  THEN contractors up to 2025-04-09 are requested and days_left is counted from today
+  (negative for already expired ones)
+- GIVEN within_days out of range → ValidationError
+
+SCENARIO 3: AccreditationMonitor.Run
+- GIVEN one lapsed and two soon-expiring accreditations
+  WHEN Run is called
+  THEN the lapsed one is expired and announced, the others are marked alerted
+  before contractor.accreditation_expiring is published
+- GIVEN nothing to do → no mark query, no events
+- GIVEN the mark query fails → error, no expiring events
+*/
+
+// recordingPublisher запоминает опубликованные события.
+type recordingPublisher struct {
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, event events.Event) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *recordingPublisher) Close(context.Context) error { return nil }
+
+var accreditationToday = time.Date(2025, 3, 10, 15, 30, 0, 0, time.UTC)
+
+func date(s string) time.Time {
+	t, _ := time.Parse(time.DateOnly, s)
+	return t
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func accreditedContractor(id int64, validUntil string) db.Contractor {
+	return db.Contractor{
+		ID:                      id,
+		Title:                   "ООО Альфа",
+		Inn:                     "7700000001",
+		AccreditationStatus:     api_models.AccreditationAccredited,
+		AccreditationValidUntil: sql.NullTime{Time: date(validUntil), Valid: true},
+		AccreditationDocuments:  json.RawMessage(`[]`),
+		AccreditationSource:     api_models.AccreditationSourceManual,
+	}
+}
+
+func TestUpdateAccreditation_StoresTrimmedDocuments(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	service.now = func() time.Time { return accreditationToday }
+
+	mockStore.EXPECT().UpdateContractorAccreditation(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.UpdateContractorAccreditationParams) (db.Contractor, error) {
+			assert.Equal(t, int64(5), arg.ID)
+			assert.Equal(t, api_models.AccreditationAccredited, arg.AccreditationStatus)
+			assert.Equal(t, sql.NullTime{Time: date("2025-12-31"), Valid: true}, arg.AccreditationValidUntil)
+			assert.JSONEq(t, `[{"title":"Свидетельство СРО","number":"СРО-123","valid_until":"2025-12-31","url":"https://sro.example/123"}]`,
+				string(arg.AccreditationDocuments))
+
+			c := accreditedContractor(5, "2025-12-31")
+			c.AccreditationDocuments = arg.AccreditationDocuments
+			return c, nil
+		})
+
+	resp, err := service.UpdateAccreditation(context.Background(), 5, api_models.UpdateAccreditationRequest{
+		Status:     api_models.AccreditationAccredited,
+		ValidUntil: strPtr("2025-12-31"),
+		Documents: []api_models.AccreditationDocument{
+			{Title: " Свидетельство СРО ", Number: "СРО-123 ", ValidUntil: " 2025-12-31", URL: "https://sro.example/123"},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, api_models.AccreditationAccredited, resp.Accreditation.Status)
+	assert.Equal(t, strPtr("2025-12-31"), resp.Accreditation.ValidUntil)
+	assert.Equal(t, api_models.AccreditationSourceManual, resp.Accreditation.Source)
+	require.Len(t, resp.Accreditation.Documents, 1)
+	assert.Equal(t, "Свидетельство СРО", resp.Accreditation.Documents[0].Title)
+}
+
+func TestUpdateAccreditation_Validation(t *testing.T) {
+	cases := []struct {
+		name    string
+		req     api_models.UpdateAccreditationRequest
+		wantKey string
+	}{
+		{
+			name:    "unknown status",
+			req:     api_models.UpdateAccreditationRequest{Status: "active"},
+			wantKey: "contractor.accreditation_status_invalid",
+		},
+		{
+			name:    "malformed date",
+			req:     api_models.UpdateAccreditationRequest{Status: api_models.AccreditationAccredited, ValidUntil: strPtr("31.12.2025")},
+			wantKey: "contractor.accreditation_date_invalid",
+		},
+		{
+			name:    "accredited with a past date",
+			req:     api_models.UpdateAccreditationRequest{Status: api_models.AccreditationAccredited, ValidUntil: strPtr("2025-03-09")},
+			wantKey: "contractor.accreditation_date_passed",
+		},
+		{
+			name: "document without title",
+			req: api_models.UpdateAccreditationRequest{Status: api_models.AccreditationSuspended, Documents: []api_models.AccreditationDocument{
+				{Title: "  ", Number: "1"},
+			}},
+			wantKey: "contractor.accreditation_document_title",
+		},
+		{
+			name: "document link is not http",
+			req: api_models.UpdateAccreditationRequest{Status: api_models.AccreditationSuspended, Documents: []api_models.AccreditationDocument{
+				{Title: "Приказ", URL: "file:///etc/passwd"},
+			}},
+			wantKey: "contractor.accreditation_document_url",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, _ := setupTestService(t)
+			service.now = func() time.Time { return accreditationToday }
+
+			_, err := service.UpdateAccreditation(context.Background(), 5, tc.req)
+
+			var validationErr *apierrors.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tc.wantKey, validationErr.Key)
+		})
+	}
+
+	t.Run("expired status keeps a past date", func(t *testing.T) {
+		service, mockStore := setupTestService(t)
+		service.now = func() time.Time { return accreditationToday }
+		c := accreditedContractor(5, "2025-03-01")
+		c.AccreditationStatus = api_models.AccreditationExpired
+		mockStore.EXPECT().UpdateContractorAccreditation(gomock.Any(), gomock.Any()).Return(c, nil)
+
+		resp, err := service.UpdateAccreditation(context.Background(), 5, api_models.UpdateAccreditationRequest{
+			Status:     api_models.AccreditationExpired,
+			ValidUntil: strPtr("2025-03-01"),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, api_models.AccreditationExpired, resp.Accreditation.Status)
+		assert.NotNil(t, resp.Accreditation.Documents, "пустой список, а не null")
+	})
+}
+
+func TestUpdateAccreditation_NotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().UpdateContractorAccreditation(gomock.Any(), gomock.Any()).Return(db.Contractor{}, sql.ErrNoRows)
+
+	_, err := service.UpdateAccreditation(context.Background(), 404, api_models.UpdateAccreditationRequest{Status: api_models.AccreditationUnknown})
+
+	var notFound *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+}
+
+func TestListExpiringAccreditations_DaysLeft(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	service.now = func() time.Time { return accreditationToday }
+
+	lapsed := accreditedContractor(2, "2025-03-07")
+	lapsed.AccreditationStatus = api_models.AccreditationExpired
+	mockStore.EXPECT().ListExpiringAccreditations(gomock.Any(), db.ListExpiringAccreditationsParams{
+		ValidUntilBefore: date("2025-04-09"),
+		PageLimit:        20,
+		PageOffset:       0,
+	}).Return([]db.Contractor{lapsed, accreditedContractor(1, "2025-03-10"), accreditedContractor(3, "2025-04-09")}, nil)
+	mockStore.EXPECT().CountExpiringAccreditations(gomock.Any(), date("2025-04-09")).Return(int64(3), nil)
+
+	resp, err := service.ListExpiringAccreditations(context.Background(), 30, 1, 20)
+
+	require.NoError(t, err)
+	assert.Equal(t, "2025-03-10", resp.AsOf)
+	assert.Equal(t, 30, resp.WithinDays)
+	assert.Equal(t, int64(3), resp.TotalCount)
+	require.Len(t, resp.Items, 3)
+	assert.Equal(t, api_models.ExpiringAccreditation{
+		ContractorID: 2, Title: "ООО Альфа", Inn: "7700000001", Status: api_models.AccreditationExpired,
+		ValidUntil: "2025-03-07", DaysLeft: -3, Documents: []api_models.AccreditationDocument{},
+	}, resp.Items[0])
+	assert.Equal(t, 0, resp.Items[1].DaysLeft, "последний день действия")
+	assert.Equal(t, 30, resp.Items[2].DaysLeft)
+}
+
+func TestListExpiringAccreditations_WithinDaysRange(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.ListExpiringAccreditations(context.Background(), config.MaxAccreditationWarnDays+1, 1, 20)
+
+	var validationErr *apierrors.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "contractor.within_days_range", validationErr.Key)
+}
+
+func setupMonitor(t *testing.T) (*AccreditationMonitor, *db.MockStore, *recordingPublisher) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	publisher := &recordingPublisher{}
+	monitor := NewAccreditationMonitor(mockStore, testutil.NewMockLogger(), publisher, config.ContractorsConfig{
+		AccreditationCheckEnabled: true, AccreditationWarnDays: 30, AccreditationBatchSize: 500,
+	})
+	monitor.now = func() time.Time { return accreditationToday }
+	return monitor, mockStore, publisher
+}
+
+func TestAccreditationMonitor_Run(t *testing.T) {
+	monitor, mockStore, publisher := setupMonitor(t)
+
+	lapsed := accreditedContractor(1, "2025-03-09")
+	lapsed.AccreditationStatus = api_models.AccreditationExpired
+	gomock.InOrder(
+		mockStore.EXPECT().ExpireContractorAccreditations(gomock.Any(), date("2025-03-10")).Return([]db.Contractor{lapsed}, nil),
+		mockStore.EXPECT().ListAccreditationsToAlert(gomock.Any(), db.ListAccreditationsToAlertParams{
+			Today:            date("2025-03-10"),
+			ValidUntilBefore: date("2025-04-09"),
+			PageLimit:        500,
+		}).Return([]db.Contractor{accreditedContractor(2, "2025-03-20"), accreditedContractor(3, "2025-04-01")}, nil),
+		mockStore.EXPECT().MarkAccreditationsAlerted(gomock.Any(), []int64{2, 3}).Return(int64(2), nil),
+	)
+
+	summary, err := monitor.Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "истекло аккредитаций: 1, скоро истекает: 2", summary)
+	require.Len(t, publisher.events, 3)
+	assert.Equal(t, events.TypeContractorAccreditationExpired, publisher.events[0].Type)
+	assert.Equal(t, "1", publisher.events[0].Key)
+	assert.Equal(t, events.ContractorAccreditationData{
+		ContractorID: 1, Title: "ООО Альфа", Inn: "7700000001", ValidUntil: "2025-03-09", DaysLeft: -1,
+	}, publisher.events[0].Data)
+	assert.Equal(t, events.TypeContractorAccreditationExpiring, publisher.events[1].Type)
+	assert.Equal(t, 10, publisher.events[1].Data.(events.ContractorAccreditationData).DaysLeft)
+	assert.Equal(t, "3", publisher.events[2].Key)
+}
+
+func TestAccreditationMonitor_Run_NothingToDo(t *testing.T) {
+	monitor, mockStore, publisher := setupMonitor(t)
+
+	mockStore.EXPECT().ExpireContractorAccreditations(gomock.Any(), gomock.Any()).Return([]db.Contractor{}, nil)
+	mockStore.EXPECT().ListAccreditationsToAlert(gomock.Any(), gomock.Any()).Return([]db.Contractor{}, nil)
+
+	summary, err := monitor.Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "истекло аккредитаций: 0, скоро истекает: 0", summary)
+	assert.Empty(t, publisher.events)
+}
+
+func TestAccreditationMonitor_Run_MarkFails(t *testing.T) {
+	monitor, mockStore, publisher := setupMonitor(t)
+
+	mockStore.EXPECT().ExpireContractorAccreditations(gomock.Any(), gomock.Any()).Return([]db.Contractor{}, nil)
+	mockStore.EXPECT().ListAccreditationsToAlert(gomock.Any(), gomock.Any()).Return([]db.Contractor{accreditedContractor(2, "2025-03-20")}, nil)
+	mockStore.EXPECT().MarkAccreditationsAlerted(gomock.Any(), []int64{2}).Return(int64(0), errors.New("connection reset"))
+
+	_, err := monitor.Run(context.Background())
+
+	assert.ErrorContains(t, err, "connection reset")
+	assert.Empty(t, publisher.events, "без отметки событие повторится, поэтому не публикуется")
+}
@@ -0,0 +1,229 @@
+// Package contractor предоставляет сервисный слой для профиля подрядчика.
+//
+// Подрядчики создаются при импорте тендеров (entities.EntityManager) и здесь
+// только читаются: список с поиском, карточка и статистика участия.
+// Статистика считается в SQL (contractor.sql); baseline-предложения (смета
+// инициатора) и мягко удалённые тендеры в неё не входят.
+//
+// Вручную меняется только аккредитация (accreditation.go): статус, срок и
+// документы. За сроками следит AccreditationMonitor — задание планировщика.
+package contractor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+const (
+	// MaxPageSize — максимальный размер страницы списка подрядчиков.
+	MaxPageSize = 100
+	// DefaultRecentProposals — сколько последних предложений возвращает статистика.
+	DefaultRecentProposals = 10
+	// MaxRecentProposals — верхняя граница параметра recent.
+	MaxRecentProposals = 50
+)
+
+// ContractorService отдаёт подрядчиков и статистику их участия в тендерах.
+type ContractorService struct {
+	store  db.Store
+	logger logging.Logger
+	now    func() time.Time // Подменяется в тестах
+}
+
+// NewContractorService создаёт новый экземпляр ContractorService.
+func NewContractorService(store db.Store, logger logging.Logger) *ContractorService {
+	return &ContractorService{
+		store:  store,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// ListContractors возвращает страницу подрядчиков. search — подстрока
+// наименования (без учёта регистра) или начало ИНН; пустая строка — без фильтра.
+func (s *ContractorService) ListContractors(
+	ctx context.Context,
+	search string,
+	page, pageSize int32,
+) (*api_models.ListContractorsResponse, error) {
+	if page < 1 {
+		return nil, apierrors.NewValidationError("request.page_positive_got", page)
+	}
+	if pageSize < 1 || pageSize > MaxPageSize {
+		return nil, apierrors.NewValidationError("request.page_size_range_got", MaxPageSize, pageSize)
+	}
+
+	searchArg := sql.NullString{}
+	if search = strings.TrimSpace(search); search != "" {
+		searchArg = sql.NullString{String: search, Valid: true}
+	}
+
+	rows, err := s.store.ListContractorsPage(ctx, db.ListContractorsPageParams{
+		Search:     searchArg,
+		PageLimit:  pageSize,
+		PageOffset: (page - 1) * pageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения списка подрядчиков: %w", err)
+	}
+
+	total, err := s.store.CountContractors(ctx, searchArg)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подсчёта подрядчиков: %w", err)
+	}
+
+	contractors := make([]api_models.ContractorListItem, 0, len(rows))
+	for _, row := range rows {
+		accreditation, err := toAccreditation(row.AccreditationStatus, row.AccreditationValidUntil, row.AccreditationDocuments, row.AccreditationSource)
+		if err != nil {
+			return nil, fmt.Errorf("подрядчик %d: %w", row.ID, err)
+		}
+		contractors = append(contractors, api_models.ContractorListItem{
+			ContractorResponse: api_models.ContractorResponse{
+				ID:            row.ID,
+				Title:         row.Title,
+				Inn:           row.Inn,
+				Address:       row.Address,
+				Accreditation: accreditation,
+				CreatedAt:     row.CreatedAt,
+				UpdatedAt:     row.UpdatedAt,
+			},
+			ProposalsCount: row.ProposalsCount,
+		})
+	}
+
+	return &api_models.ListContractorsResponse{
+		Contractors: contractors,
+		TotalCount:  total,
+		Page:        page,
+		PageSize:    pageSize,
+	}, nil
+}
+
+// GetContractor возвращает карточку подрядчика.
+func (s *ContractorService) GetContractor(ctx context.Context, contractorID int64) (*api_models.ContractorResponse, error) {
+	c, err := s.getContractor(ctx, contractorID)
+	if err != nil {
+		return nil, err
+	}
+	return contractorToResponse(c)
+}
+
+// GetContractorStats возвращает статистику участия подрядчика: число тендеров
+// и предложений, победы, долю побед, среднее отклонение от baseline и recent
+// последних предложений. organizationID ограничивает статистику тендерами
+// организации (Valid=false — все).
+func (s *ContractorService) GetContractorStats(
+	ctx context.Context,
+	contractorID int64,
+	recent int32,
+	organizationID sql.NullInt64,
+) (*api_models.ContractorStatsResponse, error) {
+	logger := s.logger.WithField("method", "GetContractorStats").WithField("contractor_id", contractorID)
+
+	if recent < 0 || recent > MaxRecentProposals {
+		return nil, apierrors.NewValidationError("contractor.recent_range", MaxRecentProposals, recent)
+	}
+
+	c, err := s.getContractor(ctx, contractorID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.store.GetContractorStats(ctx, db.GetContractorStatsParams{
+		ContractorID:   contractorID,
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка расчёта статистики подрядчика %d: %w", contractorID, err)
+	}
+
+	recentProposals := make([]api_models.ContractorRecentProposal, 0, recent)
+	if recent > 0 {
+		rows, err := s.store.ListContractorRecentProposals(ctx, db.ListContractorRecentProposalsParams{
+			ContractorID:   contractorID,
+			OrganizationID: organizationID,
+			PageLimit:      recent,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ошибка получения последних предложений подрядчика %d: %w", contractorID, err)
+		}
+		for _, row := range rows {
+			recentProposals = append(recentProposals, api_models.ContractorRecentProposal{
+				ProposalID:       row.ProposalID,
+				LotID:            row.LotID,
+				LotTitle:         row.LotTitle,
+				TenderID:         row.TenderID,
+				TenderEtpID:      row.TenderEtpID,
+				TenderTitle:      row.TenderTitle,
+				TenderDate:       row.TenderDate,
+				TotalCost:        api_models.MoneyFromNullString(row.TotalCost),
+				BaselineTotal:    api_models.MoneyFromNullString(row.BaselineTotal),
+				DeviationPercent: nullStringPtr(row.DeviationPercent),
+				IsWinner:         row.IsWinner,
+			})
+		}
+	}
+
+	logger.Infof("Статистика подрядчика: %d предложений, %d побед", stats.ProposalsCount, stats.WinsCount)
+	return &api_models.ContractorStatsResponse{
+		ContractorID:           c.ID,
+		Title:                  c.Title,
+		Inn:                    c.Inn,
+		TendersCount:           stats.TendersCount,
+		ProposalsCount:         stats.ProposalsCount,
+		WinsCount:              stats.WinsCount,
+		WinRatePercent:         nullStringPtr(stats.WinRatePercent),
+		AvgDeviationPercent:    nullStringPtr(stats.AvgDeviationPercent),
+		ComparedProposalsCount: stats.ComparedProposalsCount,
+		RecentProposals:        recentProposals,
+	}, nil
+}
+
+func (s *ContractorService) getContractor(ctx context.Context, contractorID int64) (db.Contractor, error) {
+	if contractorID <= 0 {
+		return db.Contractor{}, apierrors.NewValidationError("request.id_positive_got", contractorID)
+	}
+
+	c, err := s.store.GetContractorByID(ctx, contractorID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.Contractor{}, apierrors.NewNotFoundError("contractor.not_found", contractorID)
+		}
+		return db.Contractor{}, fmt.Errorf("ошибка получения подрядчика %d: %w", contractorID, err)
+	}
+	return c, nil
+}
+
+func contractorToResponse(c db.Contractor) (*api_models.ContractorResponse, error) {
+	accreditation, err := toAccreditation(c.AccreditationStatus, c.AccreditationValidUntil, c.AccreditationDocuments, c.AccreditationSource)
+	if err != nil {
+		return nil, fmt.Errorf("подрядчик %d: %w", c.ID, err)
+	}
+	return &api_models.ContractorResponse{
+		ID:            c.ID,
+		Title:         c.Title,
+		Inn:           c.Inn,
+		Address:       c.Address,
+		Accreditation: accreditation,
+		CreatedAt:     c.CreatedAt,
+		UpdatedAt:     c.UpdatedAt,
+	}, nil
+}
+
+func nullStringPtr(ns sql.NullString) *string {
+	if !ns.Valid {
+		return nil
+	}
+	s := ns.String
+	return &s
+}
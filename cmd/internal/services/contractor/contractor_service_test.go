@@ -0,0 +1,222 @@
+package contractor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR CONTRACTOR PROFILE (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Invisible contractors — contractors created by import must be listable and searchable
+2. Wrong statistics — DB aggregates must reach the client unchanged (no float rounding)
+3. Bad input — invalid paging or ids must be rejected before hitting the DB
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: ListContractors
+- GIVEN a search string with spaces around it
+  WHEN ListContractors is called
+  THEN the trimmed search is passed to both page and count queries
+
+- GIVEN an empty search → NULL filter
+- GIVEN page < 1 or page_size out of range → ValidationError, no DB calls
+
+SCENARIO 2: GetContractor
+- GIVEN an unknown id → NotFoundError
+- GIVEN id <= 0 → ValidationError
+
+SCENARIO 3: GetContractorStats
+- GIVEN a contractor with proposals and wins
+  WHEN GetContractorStats is called
+  THEN counts, win rate, average deviation and recent proposals are returned,
+       both queries limited to the requester's organization
+
+- GIVEN recent = 0 → recent proposals are not queried, list is empty (non-nil)
+- GIVEN a DB error in aggregates → wrapped error
+*/
+
+func setupTestService(t *testing.T) (*ContractorService, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	return NewContractorService(mockStore, testutil.NewMockLogger()), mockStore
+}
+
+func ns(v string) sql.NullString {
+	return sql.NullString{String: v, Valid: true}
+}
+
+func TestListContractors_TrimmedSearch(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	search := sql.NullString{String: "альфа", Valid: true}
+
+	mockStore.EXPECT().ListContractorsPage(gomock.Any(), db.ListContractorsPageParams{
+		Search:     search,
+		PageLimit:  20,
+		PageOffset: 20,
+	}).Return([]db.ListContractorsPageRow{
+		{ID: 1, Title: "ООО Альфа", Inn: "7700000001", ProposalsCount: 3},
+	}, nil)
+	mockStore.EXPECT().CountContractors(gomock.Any(), search).Return(int64(21), nil)
+
+	resp, err := service.ListContractors(context.Background(), "  альфа ", 2, 20)
+
+	require.NoError(t, err)
+	require.Len(t, resp.Contractors, 1)
+	assert.Equal(t, "ООО Альфа", resp.Contractors[0].Title)
+	assert.Equal(t, int64(3), resp.Contractors[0].ProposalsCount)
+	assert.Equal(t, int64(21), resp.TotalCount)
+	assert.Equal(t, int32(2), resp.Page)
+}
+
+func TestListContractors_EmptySearch_NoFilter(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ListContractorsPage(gomock.Any(), db.ListContractorsPageParams{
+		PageLimit: 10,
+	}).Return(nil, nil)
+	mockStore.EXPECT().CountContractors(gomock.Any(), sql.NullString{}).Return(int64(0), nil)
+
+	resp, err := service.ListContractors(context.Background(), "", 1, 10)
+
+	require.NoError(t, err)
+	assert.NotNil(t, resp.Contractors)
+	assert.Empty(t, resp.Contractors)
+}
+
+func TestListContractors_InvalidPaging(t *testing.T) {
+	cases := map[string]struct{ page, pageSize int32 }{
+		"page zero":      {0, 20},
+		"page size zero": {1, 0},
+		"page size big":  {1, MaxPageSize + 1},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			service, _ := setupTestService(t)
+
+			_, err := service.ListContractors(context.Background(), "", tc.page, tc.pageSize)
+
+			var validationErr *apierrors.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+		})
+	}
+}
+
+func TestGetContractor_NotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetContractorByID(gomock.Any(), int64(5)).Return(db.Contractor{}, sql.ErrNoRows)
+
+	_, err := service.GetContractor(context.Background(), 5)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestGetContractor_InvalidID(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.GetContractor(context.Background(), 0)
+
+	var validationErr *apierrors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+// testOrganization — организация пользователя, запрашивающего статистику.
+var testOrganization = sql.NullInt64{Int64: 1, Valid: true}
+
+func TestGetContractorStats_Success(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	tenderDate := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	// GIVEN a contractor with 4 proposals in 3 tenders and 1 win
+	mockStore.EXPECT().GetContractorByID(gomock.Any(), int64(5)).
+		Return(db.Contractor{ID: 5, Title: "ООО Альфа", Inn: "7700000001"}, nil)
+	mockStore.EXPECT().GetContractorStats(gomock.Any(), db.GetContractorStatsParams{ContractorID: 5, OrganizationID: testOrganization}).Return(db.GetContractorStatsRow{
+		TendersCount:           3,
+		ProposalsCount:         4,
+		WinsCount:              1,
+		WinRatePercent:         ns("25.00"),
+		AvgDeviationPercent:    ns("-3.50"),
+		ComparedProposalsCount: 2,
+	}, nil)
+	mockStore.EXPECT().ListContractorRecentProposals(gomock.Any(), db.ListContractorRecentProposalsParams{
+		ContractorID:   5,
+		OrganizationID: testOrganization,
+		PageLimit:      DefaultRecentProposals,
+	}).Return([]db.ListContractorRecentProposalsRow{
+		{ProposalID: 101, LotID: 11, TenderID: 1, TenderEtpID: "ETP-1", TenderDate: tenderDate,
+			TotalCost: ns("900.00"), BaselineTotal: ns("1000.00"), DeviationPercent: ns("-10.00"), IsWinner: true},
+		{ProposalID: 102, LotID: 12, TenderID: 2, TenderEtpID: "ETP-2", TenderDate: tenderDate.AddDate(0, -1, 0)},
+	}, nil)
+
+	// WHEN
+	resp, err := service.GetContractorStats(context.Background(), 5, DefaultRecentProposals, testOrganization)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, "7700000001", resp.Inn)
+	assert.Equal(t, int64(3), resp.TendersCount)
+	assert.Equal(t, int64(4), resp.ProposalsCount)
+	assert.Equal(t, int64(1), resp.WinsCount)
+	assert.Equal(t, "25.00", *resp.WinRatePercent)
+	assert.Equal(t, "-3.50", *resp.AvgDeviationPercent)
+
+	require.Len(t, resp.RecentProposals, 2)
+	assert.True(t, resp.RecentProposals[0].IsWinner)
+	assert.Equal(t, "-10.00", *resp.RecentProposals[0].DeviationPercent)
+	assert.Equal(t, "900.00", resp.RecentProposals[0].TotalCost.String())
+	assert.Nil(t, resp.RecentProposals[1].TotalCost)
+	assert.False(t, resp.RecentProposals[1].IsWinner)
+}
+
+func TestGetContractorStats_NoRecent_SkipsQuery(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetContractorByID(gomock.Any(), int64(5)).Return(db.Contractor{ID: 5}, nil)
+	mockStore.EXPECT().GetContractorStats(gomock.Any(), db.GetContractorStatsParams{ContractorID: 5, OrganizationID: testOrganization}).Return(db.GetContractorStatsRow{}, nil)
+
+	resp, err := service.GetContractorStats(context.Background(), 5, 0, testOrganization)
+
+	require.NoError(t, err)
+	assert.NotNil(t, resp.RecentProposals)
+	assert.Empty(t, resp.RecentProposals)
+	assert.Nil(t, resp.WinRatePercent)
+}
+
+func TestGetContractorStats_InvalidRecent(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.GetContractorStats(context.Background(), 5, MaxRecentProposals+1, sql.NullInt64{})
+
+	var validationErr *apierrors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestGetContractorStats_DBError_ReturnsWrappedError(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	dbErr := errors.New("canceling statement due to statement timeout")
+	mockStore.EXPECT().GetContractorByID(gomock.Any(), int64(5)).Return(db.Contractor{ID: 5}, nil)
+	mockStore.EXPECT().GetContractorStats(gomock.Any(), db.GetContractorStatsParams{ContractorID: 5, OrganizationID: testOrganization}).Return(db.GetContractorStatsRow{}, dbErr)
+
+	_, err := service.GetContractorStats(context.Background(), 5, DefaultRecentProposals, testOrganization)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, dbErr)
+}
@@ -0,0 +1,241 @@
+package contractor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+// legalForms — организационно-правовые формы, которые не участвуют в сравнении
+// наименований: "ООО Альфа" и "АО «Альфа»" после смены формы — один подрядчик.
+var legalForms = []string{
+	"общество с ограниченной ответственностью",
+	"публичное акционерное общество",
+	"непубличное акционерное общество",
+	"закрытое акционерное общество",
+	"открытое акционерное общество",
+	"акционерное общество",
+	"индивидуальный предприниматель",
+	"ооо", "пао", "нао", "зао", "оао", "ао", "ип",
+}
+
+// ListDuplicates возвращает группы подрядчиков с одинаковым ИНН (после
+// удаления нецифровых символов). Внутри группы первым идёт подрядчик с
+// наибольшим числом предложений — он предлагается как основной.
+func (s *ContractorService) ListDuplicates(ctx context.Context) (*api_models.ContractorDuplicatesResponse, error) {
+	rows, err := s.store.ListContractorDuplicateCandidates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска дубликатов подрядчиков: %w", err)
+	}
+
+	groups := buildDuplicateGroups(rows)
+	s.logger.Infof("ListDuplicates: найдено %d групп дубликатов", len(groups))
+	return &api_models.ContractorDuplicatesResponse{Groups: groups}, nil
+}
+
+// MergeContractors переносит предложения и контактных лиц дубликата на
+// основного подрядчика и удаляет дубликат — всё в одной транзакции.
+// Сливать можно только подрядчиков с одинаковым нормализованным ИНН. Если оба
+// подали предложения в один лот, слияние отклоняется (ConflictError со списком лотов).
+func (s *ContractorService) MergeContractors(
+	ctx context.Context,
+	req api_models.MergeContractorsRequest,
+	mergedBy int64,
+) (*api_models.MergeContractorsResponse, error) {
+	logger := s.logger.WithField("method", "MergeContractors")
+
+	if req.MasterID <= 0 || req.DuplicateID <= 0 {
+		return nil, apierrors.NewValidationError("common.merge_ids_positive")
+	}
+	if req.MasterID == req.DuplicateID {
+		return nil, apierrors.NewValidationError("common.merge_ids_distinct")
+	}
+
+	response := &api_models.MergeContractorsResponse{
+		MasterID:    req.MasterID,
+		DuplicateID: req.DuplicateID,
+	}
+
+	err := s.store.ExecTx(ctx, func(q *db.Queries) error {
+		// Блокируем в порядке id, чтобы встречные слияния не взаимоблокировались
+		first, second := req.MasterID, req.DuplicateID
+		if first > second {
+			first, second = second, first
+		}
+		locked := make(map[int64]db.Contractor, 2)
+		for _, id := range []int64{first, second} {
+			c, err := q.GetContractorByIDForUpdate(ctx, id)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return apierrors.NewNotFoundError("contractor.not_found", id)
+				}
+				return fmt.Errorf("ошибка блокировки подрядчика %d: %w", id, err)
+			}
+			locked[id] = c
+		}
+
+		master, duplicate := locked[req.MasterID], locked[req.DuplicateID]
+		if normalizeINN(master.Inn) != normalizeINN(duplicate.Inn) {
+			return apierrors.NewValidationError(
+				"contractor.inn_mismatch",
+				master.Inn, duplicate.Inn)
+		}
+
+		conflictLots, err := q.ListContractorMergeConflictLots(ctx, db.ListContractorMergeConflictLotsParams{
+			MasterID:    req.MasterID,
+			DuplicateID: req.DuplicateID,
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка проверки общих лотов: %w", err)
+		}
+		if len(conflictLots) > 0 {
+			return apierrors.NewConflictErrorf(map[string][]int64{"lot_ids": conflictLots}, "contractor.merge_lot_conflict", len(conflictLots))
+		}
+
+		response.MovedProposals, err = q.ReassignContractorProposals(ctx, db.ReassignContractorProposalsParams{
+			MasterID:    req.MasterID,
+			DuplicateID: req.DuplicateID,
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка переноса предложений: %w", err)
+		}
+
+		response.MovedPersons, err = q.ReassignContractorPersons(ctx, db.ReassignContractorPersonsParams{
+			MasterID:    req.MasterID,
+			DuplicateID: req.DuplicateID,
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка переноса контактных лиц: %w", err)
+		}
+
+		if err := q.DeleteContractor(ctx, req.DuplicateID); err != nil {
+			return fmt.Errorf("ошибка удаления дубликата: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Подрядчик %d слит в %d (администратор id=%d): перенесено %d предложений, %d контактов",
+		req.DuplicateID, req.MasterID, mergedBy, response.MovedProposals, response.MovedPersons)
+	return response, nil
+}
+
+// buildDuplicateGroups группирует кандидатов по нормализованному ИНН и
+// считает сходство наименования каждого с основным подрядчиком группы.
+func buildDuplicateGroups(rows []db.ListContractorDuplicateCandidatesRow) []api_models.ContractorDuplicateGroup {
+	byINN := make(map[string][]db.ListContractorDuplicateCandidatesRow)
+	order := make([]string, 0)
+	for _, row := range rows {
+		if _, ok := byINN[row.NormalizedInn]; !ok {
+			order = append(order, row.NormalizedInn)
+		}
+		byINN[row.NormalizedInn] = append(byINN[row.NormalizedInn], row)
+	}
+
+	groups := make([]api_models.ContractorDuplicateGroup, 0, len(order))
+	for _, inn := range order {
+		members := byINN[inn]
+		if len(members) < 2 {
+			continue
+		}
+		// Основной — с наибольшим числом предложений, при равенстве — более ранний
+		sort.SliceStable(members, func(i, j int) bool {
+			if members[i].ProposalsCount != members[j].ProposalsCount {
+				return members[i].ProposalsCount > members[j].ProposalsCount
+			}
+			return members[i].ID < members[j].ID
+		})
+
+		masterTitle := normalizeContractorTitle(members[0].Title)
+		group := api_models.ContractorDuplicateGroup{
+			NormalizedInn:      inn,
+			SuggestedMasterID:  members[0].ID,
+			Contractors:        make([]api_models.ContractorDuplicateCandidate, 0, len(members)),
+			MinTitleSimilarity: 1,
+		}
+		for _, m := range members {
+			similarity := titleSimilarity(masterTitle, normalizeContractorTitle(m.Title))
+			if similarity < group.MinTitleSimilarity {
+				group.MinTitleSimilarity = similarity
+			}
+			group.Contractors = append(group.Contractors, api_models.ContractorDuplicateCandidate{
+				ID:              m.ID,
+				Title:           m.Title,
+				Inn:             m.Inn,
+				Address:         m.Address,
+				CreatedAt:       m.CreatedAt,
+				ProposalsCount:  m.ProposalsCount,
+				TitleSimilarity: similarity,
+			})
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// normalizeINN оставляет в ИНН только цифры.
+func normalizeINN(inn string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, inn)
+}
+
+// normalizeContractorTitle приводит наименование к виду для сравнения:
+// нижний регистр, ё→е, без кавычек, знаков препинания и правовой формы.
+func normalizeContractorTitle(title string) string {
+	title = strings.ReplaceAll(strings.ToLower(title), "ё", "е")
+	title = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, title)
+	title = " " + strings.Join(strings.Fields(title), " ") + " "
+	for _, form := range legalForms {
+		title = strings.ReplaceAll(title, " "+form+" ", " ")
+	}
+	return strings.Join(strings.Fields(title), " ")
+}
+
+// titleSimilarity — 1 - расстояние Левенштейна / длину большей строки (по рунам).
+// Результат в [0, 1], округлён до сотых.
+func titleSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	maxLen := max(len(ra), len(rb))
+	if maxLen == 0 {
+		return 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	similarity := 1 - float64(prev[len(rb)])/float64(maxLen)
+	return float64(int(similarity*100+0.5)) / 100
+}
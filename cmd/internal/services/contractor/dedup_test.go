@@ -0,0 +1,250 @@
+package contractor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR CONTRACTOR DEDUPLICATION (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Split history — one company imported as "7700000001" and "77 000 000 01"
+   has its proposals and stats split between two contractors
+2. Wrong merges — contractors with different INN must never be merged
+3. Broken data — merge must not produce two proposals of one contractor in a lot,
+   and must be all-or-nothing
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: ListDuplicates
+- GIVEN three rows, two of them share the normalized INN
+  WHEN ListDuplicates is called
+  THEN one group is returned, master is the contractor with most proposals
+
+SCENARIO 2: MergeContractors
+- GIVEN two contractors with the same normalized INN and no common lots
+  WHEN MergeContractors is called
+  THEN proposals and persons are moved and the duplicate is deleted in one tx
+
+- GIVEN both contractors have proposals in the same lot
+  THEN ConflictError with lot ids, nothing is moved
+
+- GIVEN different INN → ValidationError
+- GIVEN master == duplicate → ValidationError without ExecTx
+- GIVEN an unknown contractor → NotFoundError
+
+SCENARIO 3: Title normalization
+- GIVEN titles differing only by legal form, quotes and case
+  THEN normalized titles are equal and similarity is 1
+*/
+
+var contractorColumns = []string{"id", "title", "inn", "address", "created_at", "updated_at", "accreditation_status", "accreditation_valid_until", "accreditation_documents", "accreditation_source", "accreditation_alerted_until"}
+
+// execTxDoAndReturn выполняет callback ExecTx на *db.Queries поверх go-sqlmock.
+func execTxDoAndReturn(t *testing.T, setupFn func(mock sqlmock.Sqlmock)) func(ctx context.Context, fn func(*db.Queries) error) error {
+	t.Helper()
+	return func(ctx context.Context, fn func(*db.Queries) error) error {
+		sqlDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet(), "sqlmock: unmet expectations")
+			sqlDB.Close()
+		}()
+		setupFn(mock)
+		return fn(db.New(sqlDB))
+	}
+}
+
+func expectLockContractors(mock sqlmock.Sqlmock, rows ...[]interface{}) {
+	now := time.Now()
+	for _, r := range rows {
+		mock.ExpectQuery("FOR UPDATE").
+			WithArgs(r[0]).
+			WillReturnRows(sqlmock.NewRows(contractorColumns).
+				AddRow(r[0], r[1], r[2], "Москва", now, now, "unknown", nil, []byte("[]"), "import", nil))
+	}
+}
+
+func TestListDuplicates_GroupsByNormalizedINN(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ListContractorDuplicateCandidates(gomock.Any()).Return([]db.ListContractorDuplicateCandidatesRow{
+		{ID: 1, Title: "ООО «Альфа»", Inn: "7700000001", NormalizedInn: "7700000001", ProposalsCount: 1},
+		{ID: 2, Title: "Альфа ООО", Inn: "77 000 000 01", NormalizedInn: "7700000001", ProposalsCount: 5},
+		{ID: 3, Title: "АО Бета", Inn: "7800000002", NormalizedInn: "7800000002", ProposalsCount: 2},
+	}, nil)
+
+	resp, err := service.ListDuplicates(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resp.Groups, 1)
+	group := resp.Groups[0]
+	assert.Equal(t, "7700000001", group.NormalizedInn)
+	assert.Equal(t, int64(2), group.SuggestedMasterID)
+	require.Len(t, group.Contractors, 2)
+	assert.Equal(t, int64(2), group.Contractors[0].ID)
+	assert.Equal(t, 1.0, group.MinTitleSimilarity)
+}
+
+func TestMergeContractors_Success(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			// Блокировка в порядке id: сначала 3 (дубликат), затем 8 (основной)
+			expectLockContractors(mock,
+				[]interface{}{int64(3), "Альфа", "77-000-000-01"},
+				[]interface{}{int64(8), "ООО Альфа", "7700000001"},
+			)
+			mock.ExpectQuery("SELECT dp.lot_id").
+				WithArgs(int64(8), int64(3)).
+				WillReturnRows(sqlmock.NewRows([]string{"lot_id"}))
+			mock.ExpectExec("UPDATE proposals").
+				WithArgs(int64(8), int64(3)).
+				WillReturnResult(sqlmock.NewResult(0, 4))
+			mock.ExpectExec("UPDATE persons").
+				WithArgs(int64(8), int64(3)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec("DELETE FROM contractors").
+				WithArgs(int64(3)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}),
+	)
+
+	resp, err := service.MergeContractors(context.Background(),
+		api_models.MergeContractorsRequest{MasterID: 8, DuplicateID: 3}, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), resp.MasterID)
+	assert.Equal(t, int64(3), resp.DuplicateID)
+	assert.Equal(t, int64(4), resp.MovedProposals)
+	assert.Equal(t, int64(1), resp.MovedPersons)
+}
+
+func TestMergeContractors_CommonLot_ReturnsConflict(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			expectLockContractors(mock,
+				[]interface{}{int64(3), "Альфа", "7700000001 "},
+				[]interface{}{int64(8), "ООО Альфа", "7700000001"},
+			)
+			mock.ExpectQuery("SELECT dp.lot_id").
+				WithArgs(int64(8), int64(3)).
+				WillReturnRows(sqlmock.NewRows([]string{"lot_id"}).AddRow(int64(11)).AddRow(int64(12)))
+		}),
+	)
+
+	_, err := service.MergeContractors(context.Background(),
+		api_models.MergeContractorsRequest{MasterID: 8, DuplicateID: 3}, 1)
+
+	var conflictErr *apierrors.ConflictError
+	require.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, map[string][]int64{"lot_ids": {11, 12}}, conflictErr.Conflicts)
+}
+
+func TestMergeContractors_DifferentINN_ReturnsValidationError(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			expectLockContractors(mock,
+				[]interface{}{int64(3), "ООО Альфа", "7700000009"},
+				[]interface{}{int64(8), "ООО Альфа", "7700000001"},
+			)
+		}),
+	)
+
+	_, err := service.MergeContractors(context.Background(),
+		api_models.MergeContractorsRequest{MasterID: 8, DuplicateID: 3}, 1)
+
+	var validationErr *apierrors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestMergeContractors_SameID_NoTx(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.MergeContractors(context.Background(),
+		api_models.MergeContractorsRequest{MasterID: 8, DuplicateID: 8}, 1)
+
+	var validationErr *apierrors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestMergeContractors_NotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("FOR UPDATE").
+				WithArgs(int64(3)).
+				WillReturnRows(sqlmock.NewRows(contractorColumns))
+		}),
+	)
+
+	_, err := service.MergeContractors(context.Background(),
+		api_models.MergeContractorsRequest{MasterID: 8, DuplicateID: 3}, 1)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestMergeContractors_ReassignFails_ReturnsWrappedError(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	dbErr := errors.New("connection reset")
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			expectLockContractors(mock,
+				[]interface{}{int64(3), "Альфа", "7700000001"},
+				[]interface{}{int64(8), "ООО Альфа", "77 00000001"},
+			)
+			mock.ExpectQuery("SELECT dp.lot_id").
+				WillReturnRows(sqlmock.NewRows([]string{"lot_id"}))
+			mock.ExpectExec("UPDATE proposals").
+				WillReturnError(dbErr)
+		}),
+	)
+
+	_, err := service.MergeContractors(context.Background(),
+		api_models.MergeContractorsRequest{MasterID: 8, DuplicateID: 3}, 1)
+
+	assert.ErrorIs(t, err, dbErr)
+}
+
+func TestNormalizeContractorTitle(t *testing.T) {
+	cases := map[string]string{
+		`ООО «Альфа-Строй»`: "альфа строй",
+		`"Альфа Строй" ООО`: "альфа строй",
+		`Общество с ограниченной ответственностью "Альфа Строй"`: "альфа строй",
+		`АО Ёлка`:        "елка",
+		`ИП Иванов И.И.`: "иванов и и",
+		`Аолит`:          "аолит",
+	}
+	for input, want := range cases {
+		assert.Equal(t, want, normalizeContractorTitle(input), input)
+	}
+}
+
+func TestTitleSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, titleSimilarity("альфа", "альфа"))
+	assert.Equal(t, 1.0, titleSimilarity("", ""))
+	assert.Equal(t, 0.8, titleSimilarity("альфа", "альфв"))
+	assert.Equal(t, 0.0, titleSimilarity("абв", "где"))
+}
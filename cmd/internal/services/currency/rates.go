@@ -0,0 +1,58 @@
+// Package currency хранит курсы валют для сравнения предложений в разных валютах.
+//
+// Курс — стоимость единицы валюты в базовой (config.CurrencyConfig). Пересчёт
+// выполняется в SQL: курсы передаются в запрос параллельными массивами
+// (см. analytics.sql), чтобы агрегаты по лоту по-прежнему считались в БД.
+// Валюта без курса не пересчитывается: её суммы не входят в агрегаты, а не
+// складываются с суммами в базовой валюте как есть.
+package currency
+
+import (
+	"sort"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+)
+
+// Rates — курсы валют к базовой. Только для чтения, безопасен для конкурентного использования.
+type Rates struct {
+	base  string
+	rates map[string]decimal.Decimal
+}
+
+// NewRates строит таблицу курсов из конфигурации. Конфигурация проверена при
+// загрузке (CurrencyConfig.Validate), поэтому некорректные значения пропускаются.
+func NewRates(cfg config.CurrencyConfig) *Rates {
+	rates := make(map[string]decimal.Decimal, len(cfg.Rates)+1)
+	for code, value := range cfg.Rates {
+		rate, err := decimal.NewFromString(value)
+		if err != nil || !rate.IsPositive() {
+			continue
+		}
+		rates[code] = rate
+	}
+	rates[cfg.Base] = decimal.NewFromInt(1)
+	return &Rates{base: cfg.Base, rates: rates}
+}
+
+// Base возвращает базовую валюту, в которой считаются агрегаты.
+func (r *Rates) Base() string {
+	return r.base
+}
+
+// QueryArgs возвращает курсы в виде параллельных массивов для SQL-запросов:
+// коды валют и курсы десятичными строками (numeric[]). Порядок стабилен.
+func (r *Rates) QueryArgs() (currencies []string, rates []string) {
+	currencies = make([]string, 0, len(r.rates))
+	for code := range r.rates {
+		currencies = append(currencies, code)
+	}
+	sort.Strings(currencies)
+
+	rates = make([]string, len(currencies))
+	for i, code := range currencies {
+		rates[i] = r.rates[code].String()
+	}
+	return currencies, rates
+}
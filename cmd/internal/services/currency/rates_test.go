@@ -0,0 +1,52 @@
+package currency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR CURRENCY RATES (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Mixed sums — amounts in a currency without a rate must not be treated as base currency
+2. Precision — rates reach PostgreSQL as decimal strings, not floats
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Query arguments
+- GIVEN base RUB and rates for USD and EUR
+  WHEN QueryArgs is called
+  THEN codes are sorted, the base currency has rate 1, rates are decimal strings
+
+- GIVEN no configured rates
+  WHEN QueryArgs is called
+  THEN only the base currency is convertible
+*/
+
+func TestRates_QueryArgs(t *testing.T) {
+	rates := NewRates(config.CurrencyConfig{
+		Base:  "RUB",
+		Rates: map[string]string{"USD": "92.50", "EUR": "100.1234"},
+	})
+
+	currencies, values := rates.QueryArgs()
+
+	assert.Equal(t, "RUB", rates.Base())
+	assert.Equal(t, []string{"EUR", "RUB", "USD"}, currencies)
+	assert.Equal(t, []string{"100.1234", "1", "92.5"}, values)
+}
+
+func TestRates_QueryArgs_BaseOnly(t *testing.T) {
+	rates := NewRates(config.CurrencyConfig{Base: "EUR"})
+
+	currencies, values := rates.QueryArgs()
+
+	assert.Equal(t, []string{"EUR"}, currencies)
+	assert.Equal(t, []string{"1"}, values)
+}
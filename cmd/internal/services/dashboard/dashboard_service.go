@@ -0,0 +1,240 @@
+// Package dashboard собирает сводку главной страницы (GET /api/v1/dashboard):
+// тендеры по состоянию, импорты за последние ImportsDays дней, очередь
+// сопоставления с каталогом, ожидающие предложения слияния, категории с
+// наибольшим объёмом и ленту последних импортов.
+//
+// Каждая часть сводки — отдельный агрегатный запрос (dashboard.sql), запросы
+// выполняются параллельно. Готовая сводка кэшируется в памяти процесса на
+// dashboard.cache_ttl отдельно для каждой организации: страница открывается
+// часто, а цифры за несколько секунд почти не меняются.
+package dashboard
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+	"golang.org/x/sync/errgroup"
+)
+
+// ImportsDays — окно статистики импортов, дней (включая сегодняшний).
+const ImportsDays = 30
+
+// Типы событий ленты активности (DashboardActivity.Type).
+const (
+	ActivityTenderImported   = "tender_imported"
+	ActivityTenderReimported = "tender_reimported"
+)
+
+const (
+	defaultTopCategories  = 5
+	defaultRecentActivity = 10
+)
+
+type cacheEntry struct {
+	summary   *api_models.DashboardResponse
+	expiresAt time.Time
+}
+
+// DashboardService считает сводку главной страницы.
+type DashboardService struct {
+	store  db.Store
+	logger logging.Logger
+	cfg    config.DashboardConfig
+	now    func() time.Time // Подменяется в тестах
+
+	mu    sync.Mutex
+	cache map[sql.NullInt64]cacheEntry // Ключ — организация, Valid=false — все
+}
+
+// NewDashboardService создаёт новый экземпляр DashboardService.
+func NewDashboardService(store db.Store, logger logging.Logger, cfg config.DashboardConfig) *DashboardService {
+	if cfg.TopCategories <= 0 {
+		cfg.TopCategories = defaultTopCategories
+	}
+	if cfg.RecentActivity <= 0 {
+		cfg.RecentActivity = defaultRecentActivity
+	}
+	return &DashboardService{
+		store:  store,
+		logger: logger.WithField("service", "dashboard"),
+		cfg:    cfg,
+		now:    time.Now,
+		cache:  make(map[sql.NullInt64]cacheEntry),
+	}
+}
+
+// GetSummary возвращает сводку по тендерам организации (organizationID
+// Valid=false — по всем). Пока не истёк dashboard.cache_ttl, возвращается
+// ранее посчитанная сводка.
+func (s *DashboardService) GetSummary(ctx context.Context, organizationID sql.NullInt64) (*api_models.DashboardResponse, error) {
+	now := s.now()
+	if summary := s.cached(organizationID, now); summary != nil {
+		return summary, nil
+	}
+
+	summary, err := s.compute(ctx, organizationID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cfg.CacheTTL > 0 {
+		s.mu.Lock()
+		s.cache[organizationID] = cacheEntry{summary: summary, expiresAt: now.Add(s.cfg.CacheTTL)}
+		s.mu.Unlock()
+	}
+	return summary, nil
+}
+
+func (s *DashboardService) cached(organizationID sql.NullInt64, now time.Time) *api_models.DashboardResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.cache[organizationID]
+	if !ok {
+		return nil
+	}
+	if !now.Before(entry.expiresAt) {
+		delete(s.cache, organizationID)
+		return nil
+	}
+	return entry.summary
+}
+
+func (s *DashboardService) compute(ctx context.Context, organizationID sql.NullInt64, now time.Time) (*api_models.DashboardResponse, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	since := today.AddDate(0, 0, -(ImportsDays - 1))
+
+	summary := &api_models.DashboardResponse{GeneratedAt: now}
+	g, gctx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		counts, err := s.store.GetDashboardTenderStatusCounts(gctx, organizationID)
+		if err != nil {
+			return fmt.Errorf("ошибка подсчета тендеров по состоянию: %w", err)
+		}
+		summary.Tenders = api_models.DashboardTenderStatuses{
+			Total:       counts.Total,
+			Awarded:     counts.Awarded,
+			InProgress:  counts.InProgress,
+			NoProposals: counts.NoProposals,
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		rows, err := s.store.ListDashboardImportsByDay(gctx, db.ListDashboardImportsByDayParams{
+			Since:          since,
+			OrganizationID: organizationID,
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка подсчета импортов: %w", err)
+		}
+		summary.Imports = importsByDay(rows, since)
+		return nil
+	})
+
+	g.Go(func() error {
+		count, err := s.store.CountDashboardUnmatchedPositions(gctx, organizationID)
+		if err != nil {
+			return fmt.Errorf("ошибка подсчета несопоставленных позиций: %w", err)
+		}
+		summary.UnmatchedPositions = count
+		return nil
+	})
+
+	g.Go(func() error {
+		// Каталог общий для всех организаций, как и предложения слияния
+		count, err := s.store.CountPendingMerges(gctx)
+		if err != nil {
+			return fmt.Errorf("ошибка подсчета предложений слияния: %w", err)
+		}
+		summary.PendingMerges = count
+		return nil
+	})
+
+	g.Go(func() error {
+		rows, err := s.store.ListDashboardTopCategories(gctx, db.ListDashboardTopCategoriesParams{
+			OrganizationID: organizationID,
+			MaxCategories:  int32(s.cfg.TopCategories),
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка выборки категорий: %w", err)
+		}
+		categories := make([]api_models.DashboardCategory, 0, len(rows))
+		for _, row := range rows {
+			total, err := api_models.ParseMoney(row.WinnersTotal)
+			if err != nil {
+				return err
+			}
+			categories = append(categories, api_models.DashboardCategory{
+				CategoryID:   row.CategoryID,
+				Title:        row.CategoryTitle,
+				TendersCount: row.TendersCount,
+				WinnersTotal: total,
+			})
+		}
+		summary.TopCategories = categories
+		return nil
+	})
+
+	g.Go(func() error {
+		rows, err := s.store.ListDashboardRecentImports(gctx, db.ListDashboardRecentImportsParams{
+			OrganizationID: organizationID,
+			MaxItems:       int32(s.cfg.RecentActivity),
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка выборки последних импортов: %w", err)
+		}
+		activity := make([]api_models.DashboardActivity, 0, len(rows))
+		for _, row := range rows {
+			activityType := ActivityTenderImported
+			if row.Version > 1 {
+				activityType = ActivityTenderReimported
+			}
+			activity = append(activity, api_models.DashboardActivity{
+				Type:       activityType,
+				TenderID:   row.TenderID,
+				EtpID:      row.EtpID,
+				Title:      row.Title,
+				Version:    row.Version,
+				OccurredAt: row.ImportedAt,
+			})
+		}
+		summary.RecentActivity = activity
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// importsByDay раскладывает импорты по всем ImportsDays дням начиная с since:
+// дни без импортов идут с нулями, чтобы фронтенд строил график без пропусков.
+func importsByDay(rows []db.ListDashboardImportsByDayRow, since time.Time) api_models.DashboardImports {
+	byDate := make(map[string]db.ListDashboardImportsByDayRow, len(rows))
+	for _, row := range rows {
+		byDate[row.Day.Format(time.DateOnly)] = row
+	}
+
+	imports := api_models.DashboardImports{Days: ImportsDays, ByDay: make([]api_models.DashboardImportsDay, 0, ImportsDays)}
+	for i := 0; i < ImportsDays; i++ {
+		date := since.AddDate(0, 0, i).Format(time.DateOnly)
+		row := byDate[date]
+		imports.Total += row.Imports
+		imports.NewTenders += row.NewTenders
+		imports.ByDay = append(imports.ByDay, api_models.DashboardImportsDay{
+			Date:       date,
+			Imports:    row.Imports,
+			NewTenders: row.NewTenders,
+		})
+	}
+	return imports
+}
@@ -0,0 +1,159 @@
+package dashboard
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR DASHBOARD SUMMARY (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Slow home page — the summary must not rerun every aggregate on each visit
+2. Data leaks — a user must only see the numbers of their organization
+3. Broken charts — days without imports must still be present in the series
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Summary
+- GIVEN tenders, imports, backlog, merges, categories and recent imports
+  WHEN GetSummary is called for an organization
+  THEN every query is scoped to that organization and the parts are assembled
+  AND imports are laid out over all ImportsDays days with zeros for empty days
+  AND a re-import is reported as tender_reimported
+
+SCENARIO 2: Caching
+- GIVEN a summary computed less than cache_ttl ago THEN it is returned without queries
+- GIVEN cache_ttl has passed THEN the summary is computed again
+- GIVEN another organization THEN its summary is computed separately
+
+SCENARIO 3: Failures
+- GIVEN one query fails THEN the error is returned and nothing is cached
+*/
+
+var testNow = time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+
+func setupTestService(t *testing.T) (*DashboardService, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	service := NewDashboardService(mockStore, testutil.NewMockLogger(), config.DashboardConfig{CacheTTL: 30 * time.Second})
+	service.now = func() time.Time { return testNow }
+	return service, mockStore
+}
+
+func expectSummaryQueries(mockStore *db.MockStore, org sql.NullInt64) {
+	since := time.Date(2026, 4, 11, 0, 0, 0, 0, time.UTC)
+	mockStore.EXPECT().GetDashboardTenderStatusCounts(gomock.Any(), org).Return(db.GetDashboardTenderStatusCountsRow{
+		Total: 12, Awarded: 5, InProgress: 4, NoProposals: 3,
+	}, nil)
+	mockStore.EXPECT().ListDashboardImportsByDay(gomock.Any(), db.ListDashboardImportsByDayParams{
+		Since: since, OrganizationID: org,
+	}).Return([]db.ListDashboardImportsByDayRow{
+		{Day: since, Imports: 2, NewTenders: 2},
+		{Day: time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC), Imports: 3, NewTenders: 1},
+	}, nil)
+	mockStore.EXPECT().CountDashboardUnmatchedPositions(gomock.Any(), org).Return(int64(140), nil)
+	mockStore.EXPECT().CountPendingMerges(gomock.Any()).Return(int64(7), nil)
+	mockStore.EXPECT().ListDashboardTopCategories(gomock.Any(), db.ListDashboardTopCategoriesParams{
+		OrganizationID: org, MaxCategories: defaultTopCategories,
+	}).Return([]db.ListDashboardTopCategoriesRow{
+		{CategoryID: 3, CategoryTitle: "Монолитные работы", TendersCount: 4, WinnersTotal: "125000000.50"},
+	}, nil)
+	mockStore.EXPECT().ListDashboardRecentImports(gomock.Any(), db.ListDashboardRecentImportsParams{
+		OrganizationID: org, MaxItems: defaultRecentActivity,
+	}).Return([]db.ListDashboardRecentImportsRow{
+		{TenderID: 20, EtpID: "ETP-20", Title: "Фасадные работы", Version: 2, ImportedAt: testNow.Add(-time.Hour)},
+		{TenderID: 21, EtpID: "ETP-21", Title: "Кровля", Version: 1, ImportedAt: testNow.Add(-2 * time.Hour)},
+	}, nil)
+}
+
+func TestGetSummary(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	org := sql.NullInt64{Int64: 1, Valid: true}
+	expectSummaryQueries(mockStore, org)
+
+	summary, err := service.GetSummary(context.Background(), org)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), summary.Tenders.Total)
+	assert.Equal(t, int64(4), summary.Tenders.InProgress)
+	assert.Equal(t, int64(140), summary.UnmatchedPositions)
+	assert.Equal(t, int64(7), summary.PendingMerges)
+
+	assert.Equal(t, ImportsDays, summary.Imports.Days)
+	assert.Equal(t, int64(5), summary.Imports.Total)
+	assert.Equal(t, int64(3), summary.Imports.NewTenders)
+	require.Len(t, summary.Imports.ByDay, ImportsDays)
+	assert.Equal(t, "2026-04-11", summary.Imports.ByDay[0].Date)
+	assert.Equal(t, int64(2), summary.Imports.ByDay[0].Imports)
+	assert.Zero(t, summary.Imports.ByDay[1].Imports)
+	assert.Equal(t, "2026-05-10", summary.Imports.ByDay[ImportsDays-1].Date)
+	assert.Equal(t, int64(3), summary.Imports.ByDay[ImportsDays-1].Imports)
+
+	require.Len(t, summary.TopCategories, 1)
+	assert.Equal(t, "125000000.5", summary.TopCategories[0].WinnersTotal.String())
+
+	require.Len(t, summary.RecentActivity, 2)
+	assert.Equal(t, ActivityTenderReimported, summary.RecentActivity[0].Type)
+	assert.Equal(t, ActivityTenderImported, summary.RecentActivity[1].Type)
+	assert.Equal(t, testNow, summary.GeneratedAt)
+}
+
+func TestGetSummary_Cache(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	org := sql.NullInt64{Int64: 1, Valid: true}
+	expectSummaryQueries(mockStore, org)
+
+	first, err := service.GetSummary(context.Background(), org)
+	require.NoError(t, err)
+
+	// В пределах cache_ttl запросов к БД нет (лишний вызов провалит mock)
+	service.now = func() time.Time { return testNow.Add(29 * time.Second) }
+	second, err := service.GetSummary(context.Background(), org)
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	// Другая организация считается отдельно
+	expectSummaryQueries(mockStore, sql.NullInt64{})
+	_, err = service.GetSummary(context.Background(), sql.NullInt64{})
+	require.NoError(t, err)
+
+	// После cache_ttl сводка считается заново
+	service.now = func() time.Time { return testNow.Add(30 * time.Second) }
+	expectSummaryQueries(mockStore, org)
+	third, err := service.GetSummary(context.Background(), org)
+	require.NoError(t, err)
+	assert.NotSame(t, first, third)
+}
+
+func TestGetSummary_QueryError(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	dbErr := errors.New("connection reset")
+	org := sql.NullInt64{Int64: 1, Valid: true}
+
+	mockStore.EXPECT().GetDashboardTenderStatusCounts(gomock.Any(), org).Return(db.GetDashboardTenderStatusCountsRow{}, dbErr)
+	mockStore.EXPECT().ListDashboardImportsByDay(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockStore.EXPECT().CountDashboardUnmatchedPositions(gomock.Any(), gomock.Any()).Return(int64(0), nil).AnyTimes()
+	mockStore.EXPECT().CountPendingMerges(gomock.Any()).Return(int64(0), nil).AnyTimes()
+	mockStore.EXPECT().ListDashboardTopCategories(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockStore.EXPECT().ListDashboardRecentImports(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+	_, err := service.GetSummary(context.Background(), org)
+
+	assert.ErrorIs(t, err, dbErr)
+	assert.Empty(t, service.cache)
+}
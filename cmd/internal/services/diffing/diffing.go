@@ -0,0 +1,348 @@
+// Package diffing сравнивает две версии исходного JSON тендера.
+//
+// Лоты, предложения, позиции и итоговые строки сопоставляются по ключам
+// payload (ключ лота, ключ предложения, ключ позиции), а не по номерам или
+// названиям: именно ключи определяют строки в БД при импорте. Результат —
+// структурированный отчёт для отладки регрессий парсера; пакет не обращается к БД.
+package diffing
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+)
+
+// BaselineKey — ключ базового предложения организатора в отчёте.
+const BaselineKey = "baseline"
+
+// numericEpsilon — числа из XLSX приходят с шумом округления; меньшие
+// расхождения изменениями не считаются.
+var numericEpsilon = decimal.New(1, -6)
+
+// field — значение одного сравниваемого поля: строка, *string, *decimal.Decimal
+// (количества) или *api_models.Money (деньги).
+type field struct {
+	name  string
+	value interface{}
+}
+
+// CompareTenders возвращает различия между версиями from и to. TenderID и
+// номера версий заполняет вызывающий.
+func CompareTenders(from, to *api_models.FullTenderData) *api_models.TenderImportDiff {
+	diff := &api_models.TenderImportDiff{
+		Changes: compareFields(tenderFields(from), tenderFields(to)),
+		Lots:    make([]api_models.LotDiff, 0),
+	}
+
+	var common []string
+	diff.LotsAdded, diff.LotsRemoved, common = splitKeys(from.LotsData, to.LotsData)
+	diff.Stats.LotsAdded = len(diff.LotsAdded)
+	diff.Stats.LotsRemoved = len(diff.LotsRemoved)
+
+	for _, lotKey := range common {
+		lotDiff, changed := compareLots(lotKey, from.LotsData[lotKey], to.LotsData[lotKey], &diff.Stats)
+		if changed {
+			diff.Lots = append(diff.Lots, lotDiff)
+		}
+	}
+	diff.Stats.LotsChanged = len(diff.Lots)
+
+	diff.Identical = len(diff.Changes) == 0 && len(diff.LotsAdded) == 0 &&
+		len(diff.LotsRemoved) == 0 && len(diff.Lots) == 0
+	return diff
+}
+
+func compareLots(lotKey string, from, to api_models.Lot, stats *api_models.TenderImportDiffStats) (api_models.LotDiff, bool) {
+	lotDiff := api_models.LotDiff{
+		LotKey:    lotKey,
+		Changes:   compareFields([]field{{"lot_title", from.LotTitle}}, []field{{"lot_title", to.LotTitle}}),
+		Proposals: make([]api_models.ProposalDiff, 0),
+	}
+
+	var common []string
+	lotDiff.ProposalsAdded, lotDiff.ProposalsRemoved, common = splitKeys(from.ProposalData, to.ProposalData)
+	stats.ProposalsAdded += len(lotDiff.ProposalsAdded)
+	stats.ProposalsRemoved += len(lotDiff.ProposalsRemoved)
+
+	if p, changed := compareProposals(BaselineKey, from.BaseLineProposal, to.BaseLineProposal, stats); changed {
+		lotDiff.Proposals = append(lotDiff.Proposals, p)
+	}
+	for _, key := range common {
+		if p, changed := compareProposals(key, from.ProposalData[key], to.ProposalData[key], stats); changed {
+			lotDiff.Proposals = append(lotDiff.Proposals, p)
+		}
+	}
+	stats.ProposalsChanged += len(lotDiff.Proposals)
+
+	changed := len(lotDiff.Changes) > 0 || len(lotDiff.ProposalsAdded) > 0 ||
+		len(lotDiff.ProposalsRemoved) > 0 || len(lotDiff.Proposals) > 0
+	return lotDiff, changed
+}
+
+func compareProposals(key string, from, to api_models.ContractorProposalDetails, stats *api_models.TenderImportDiffStats) (api_models.ProposalDiff, bool) {
+	proposalDiff := api_models.ProposalDiff{
+		ProposalKey: key,
+		Changes: append(compareFields(proposalFields(from), proposalFields(to)),
+			compareAdditionalInfo(from.AdditionalInfo, to.AdditionalInfo)...),
+		Positions: compareItems(from.ContractorItems.Positions, to.ContractorItems.Positions,
+			positionTitle, positionTotal, positionFields),
+		Summary: compareItems(from.ContractorItems.Summary, to.ContractorItems.Summary,
+			summaryTitle, summaryTotal, summaryFields),
+	}
+
+	stats.PositionsAdded += len(proposalDiff.Positions.Added)
+	stats.PositionsRemoved += len(proposalDiff.Positions.Removed)
+	stats.PositionsChanged += len(proposalDiff.Positions.Changed)
+
+	changed := len(proposalDiff.Changes) > 0 || !itemsEmpty(proposalDiff.Positions) || !itemsEmpty(proposalDiff.Summary)
+	return proposalDiff, changed
+}
+
+// compareItems сравнивает позиции или итоговые строки предложения по ключам.
+func compareItems[T any](
+	from, to map[string]T,
+	title func(T) string,
+	total func(T) *api_models.Money,
+	fields func(T) []field,
+) api_models.DiffItems {
+	added, removed, common := splitKeys(from, to)
+	items := api_models.DiffItems{
+		Added:   make([]api_models.DiffItemRef, 0, len(added)),
+		Removed: make([]api_models.DiffItemRef, 0, len(removed)),
+		Changed: make([]api_models.DiffItemChange, 0),
+	}
+
+	for _, key := range added {
+		items.Added = append(items.Added, api_models.DiffItemRef{Key: key, Title: title(to[key]), Total: total(to[key])})
+	}
+	for _, key := range removed {
+		items.Removed = append(items.Removed, api_models.DiffItemRef{Key: key, Title: title(from[key]), Total: total(from[key])})
+	}
+	for _, key := range common {
+		changes := compareFields(fields(from[key]), fields(to[key]))
+		if len(changes) > 0 {
+			items.Changed = append(items.Changed, api_models.DiffItemChange{Key: key, Title: title(to[key]), Changes: changes})
+		}
+	}
+	return items
+}
+
+// PriceChanges считает позиции предложений (включая базовое), у которых
+// изменилась стоимость — цена за единицу или итог по любой статье.
+// Добавленные и удалённые позиции не учитываются.
+func PriceChanges(diff *api_models.TenderImportDiff) int {
+	count := 0
+	for _, lot := range diff.Lots {
+		for _, proposal := range lot.Proposals {
+			for _, item := range proposal.Positions.Changed {
+				if hasCostChange(item.Changes) {
+					count++
+				}
+			}
+		}
+	}
+	return count
+}
+
+func hasCostChange(changes []api_models.DiffFieldChange) bool {
+	for _, change := range changes {
+		if strings.HasPrefix(change.Field, "unit_cost.") || strings.HasPrefix(change.Field, "total_cost.") {
+			return true
+		}
+	}
+	return false
+}
+
+func itemsEmpty(items api_models.DiffItems) bool {
+	return len(items.Added) == 0 && len(items.Removed) == 0 && len(items.Changed) == 0
+}
+
+// compareFields сравнивает списки полей, построенные одной функцией, — поэтому
+// порядок и имена полей в from и to совпадают.
+func compareFields(from, to []field) []api_models.DiffFieldChange {
+	changes := make([]api_models.DiffFieldChange, 0)
+	for i := range from {
+		change, changed := compareValues(from[i].value, to[i].value)
+		if changed {
+			change.Field = from[i].name
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+func compareValues(from, to interface{}) (api_models.DiffFieldChange, bool) {
+	switch f := from.(type) {
+	case *decimal.Decimal:
+		return compareDecimals(f, to.(*decimal.Decimal), func(d decimal.Decimal) interface{} { return d })
+	case *api_models.Money:
+		t := to.(*api_models.Money)
+		var fd, td *decimal.Decimal
+		if f != nil {
+			fd = &f.Decimal
+		}
+		if t != nil {
+			td = &t.Decimal
+		}
+		return compareDecimals(fd, td, func(d decimal.Decimal) interface{} { return api_models.NewMoney(d) })
+	case *string:
+		t := to.(*string)
+		if f == nil && t == nil || f != nil && t != nil && *f == *t {
+			return api_models.DiffFieldChange{}, false
+		}
+		return api_models.DiffFieldChange{From: derefString(f), To: derefString(t)}, true
+	default:
+		if from == to {
+			return api_models.DiffFieldChange{}, false
+		}
+		return api_models.DiffFieldChange{From: from, To: to}, true
+	}
+}
+
+// compareDecimals сравнивает числа с допуском numericEpsilon; wrap задаёт
+// JSON-представление значений From/To (деньги выводятся как Money).
+func compareDecimals(f, t *decimal.Decimal, wrap func(decimal.Decimal) interface{}) (api_models.DiffFieldChange, bool) {
+	switch {
+	case f == nil && t == nil:
+		return api_models.DiffFieldChange{}, false
+	case f != nil && t != nil:
+		delta := t.Sub(*f)
+		if delta.Abs().LessThanOrEqual(numericEpsilon) {
+			return api_models.DiffFieldChange{}, false
+		}
+		return api_models.DiffFieldChange{From: wrap(*f), To: wrap(*t), Delta: &delta}, true
+	case f != nil:
+		return api_models.DiffFieldChange{From: wrap(*f), To: nil}, true
+	default:
+		return api_models.DiffFieldChange{From: nil, To: wrap(*t)}, true
+	}
+}
+
+func tenderFields(t *api_models.FullTenderData) []field {
+	return []field{
+		{"tender_title", t.TenderTitle},
+		{"tender_object", t.TenderObject},
+		{"tender_address", t.TenderAddress},
+		{"executor.executor_name", t.ExecutorData.ExecutorName},
+		{"executor.executor_phone", t.ExecutorData.ExecutorPhone},
+		{"executor.executor_date", t.ExecutorData.ExecutorDate},
+		{"etp_status", t.EtpStatus},
+	}
+}
+
+// proposalFields — реквизиты подрядчика. Координаты и размеры блока в таблице
+// не сравниваются: это разметка, а не данные.
+func proposalFields(p api_models.ContractorProposalDetails) []field {
+	return []field{
+		{"title", p.Title},
+		{"inn", p.Inn},
+		{"address", p.Address},
+		{"accreditation", p.Accreditation},
+	}
+}
+
+// compareAdditionalInfo сравнивает дополнительную информацию по объединению
+// ключей; отсутствующий ключ равнозначен null.
+func compareAdditionalInfo(from, to map[string]*string) []api_models.DiffFieldChange {
+	added, removed, common := splitKeys(from, to)
+	keys := append(append(added, removed...), common...)
+	sort.Strings(keys)
+
+	changes := make([]api_models.DiffFieldChange, 0)
+	for _, key := range keys {
+		if change, changed := compareValues(from[key], to[key]); changed {
+			change.Field = "additional_info." + key
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+func positionFields(p api_models.PositionItem) []field {
+	fields := []field{
+		{"number", p.Number},
+		{"chapter_number", p.ChapterNumber},
+		{"article_smr", p.ArticleSMR},
+		{"job_title", p.JobTitle},
+		{"unit", p.Unit},
+		{"is_chapter", p.IsChapter},
+		{"chapter_ref", p.ChapterRef},
+		{"quantity", p.Quantity},
+		{"suggested_quantity", p.SuggestedQuantity},
+	}
+	fields = append(fields, costFields("unit_cost", p.UnitCost)...)
+	fields = append(fields, costFields("total_cost", p.TotalCost)...)
+	return append(fields,
+		field{"total_cost_for_organizer_quantity", p.TotalCostForOrganizerQuantity},
+		field{"deviation_from_baseline_cost", p.DeviationFromBaselineCost},
+		field{"comment_organizer", p.CommentOrganizer},
+		field{"comment_contractor", p.CommentContractor},
+	)
+}
+
+func summaryFields(s api_models.SummaryLine) []field {
+	fields := []field{
+		{"job_title", s.JobTitle},
+		{"suggested_quantity", s.SuggestedQuantity},
+	}
+	fields = append(fields, costFields("unit_cost", s.UnitCost)...)
+	fields = append(fields, costFields("total_cost", s.TotalCost)...)
+	return append(fields,
+		field{"total_cost_for_organizer_quantity", s.OrganizierQuantityCost},
+		field{"deviation_from_baseline_cost", s.Deviation},
+		field{"comment_contractor", s.CommentContractor},
+	)
+}
+
+func costFields(prefix string, c api_models.Cost) []field {
+	return []field{
+		{prefix + ".materials", c.Materials},
+		{prefix + ".works", c.Works},
+		{prefix + ".indirect_costs", c.IndirectCosts},
+		{prefix + ".total", c.Total},
+	}
+}
+
+func positionTitle(p api_models.PositionItem) string {
+	if number := strings.TrimSpace(p.Number); number != "" {
+		return number + ". " + p.JobTitle
+	}
+	return p.JobTitle
+}
+
+func positionTotal(p api_models.PositionItem) *api_models.Money { return p.TotalCost.Total }
+
+func summaryTitle(s api_models.SummaryLine) string { return s.JobTitle }
+
+func summaryTotal(s api_models.SummaryLine) *api_models.Money { return s.TotalCost.Total }
+
+// splitKeys раскладывает ключи двух map на добавленные, удалённые и общие;
+// каждый список отсортирован, чтобы отчёт был детерминированным.
+func splitKeys[V any](from, to map[string]V) (added, removed, common []string) {
+	added, removed, common = make([]string, 0), make([]string, 0), make([]string, 0)
+	for key := range to {
+		if _, ok := from[key]; ok {
+			common = append(common, key)
+		} else {
+			added = append(added, key)
+		}
+	}
+	for key := range from {
+		if _, ok := to[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(common)
+	return added, removed, common
+}
+
+func derefString(v *string) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
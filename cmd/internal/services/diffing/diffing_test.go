@@ -0,0 +1,203 @@
+package diffing
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR RAW IMPORT DIFF (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Parser regressions going unnoticed — a re-import that silently drops lots,
+   proposals or positions must show up as removals
+2. Noise — identical payloads and XLSX rounding noise must not produce changes
+3. Unstable reports — map iteration order must not change the output
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Identical payloads → Identical=true, empty non-nil lists
+SCENARIO 2: Lots and proposals added/removed → keys listed, stats counted
+SCENARIO 3: Position changes
+- GIVEN a position total changed 8000 → 9000 → change with delta 1000
+- GIVEN a position removed and another added → listed with titles and totals
+- GIVEN a difference below floatEpsilon → no change
+SCENARIO 4: Baseline and additional info changes → proposal "baseline" / additional_info.<key>
+SCENARIO 5: PriceChanges counts changed positions with a cost change only
+- GIVEN one position with a new total and one with only a new unit → 1
+*/
+
+func ptr[T any](v T) *T { return &v }
+
+func money(s string) *api_models.Money { return api_models.MoneyPtr(decimal.RequireFromString(s)) }
+
+func makeTender() *api_models.FullTenderData {
+	return &api_models.FullTenderData{
+		TenderID:    "ETP-1",
+		TenderTitle: "Тендер",
+		LotsData: map[string]api_models.Lot{
+			"lot_1": {
+				LotTitle: "Лот 1",
+				BaseLineProposal: api_models.ContractorProposalDetails{
+					Title: "Initiator",
+					ContractorItems: api_models.ContractorItemsContainer{
+						Positions: map[string]api_models.PositionItem{
+							"1": {Number: "1", JobTitle: "Устройство полов", TotalCost: api_models.Cost{Total: money("8000")}},
+						},
+					},
+				},
+				ProposalData: map[string]api_models.ContractorProposalDetails{
+					"ООО Альфа": {
+						Title: "ООО Альфа",
+						Inn:   "7701000001",
+						ContractorItems: api_models.ContractorItemsContainer{
+							Positions: map[string]api_models.PositionItem{
+								"1": {Number: "1", JobTitle: "Устройство полов", TotalCost: api_models.Cost{Total: money("8000")}},
+								"2": {Number: "2", JobTitle: "Окраска стен", TotalCost: api_models.Cost{Total: money("500")}},
+							},
+							Summary: map[string]api_models.SummaryLine{
+								"total_cost": {JobTitle: "Итого", TotalCost: api_models.Cost{Total: money("8500")}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// clone returns a deep copy via JSON, the same way both versions are loaded from the DB.
+func clone(t *testing.T, src *api_models.FullTenderData) *api_models.FullTenderData {
+	t.Helper()
+	raw, err := json.Marshal(src)
+	require.NoError(t, err)
+	var dst api_models.FullTenderData
+	require.NoError(t, json.Unmarshal(raw, &dst))
+	return &dst
+}
+
+func TestCompareTenders_Identical(t *testing.T) {
+	from := makeTender()
+
+	diff := CompareTenders(from, clone(t, from))
+
+	assert.True(t, diff.Identical)
+	assert.NotNil(t, diff.Changes)
+	assert.Empty(t, diff.Changes)
+	assert.NotNil(t, diff.LotsAdded)
+	assert.NotNil(t, diff.Lots)
+	assert.Empty(t, diff.Lots)
+	assert.Equal(t, api_models.TenderImportDiffStats{}, diff.Stats)
+}
+
+func TestCompareTenders_LotsAndProposals(t *testing.T) {
+	from := makeTender()
+	to := clone(t, from)
+	to.TenderTitle = "Тендер (ред.)"
+	to.LotsData["lot_2"] = api_models.Lot{LotTitle: "Лот 2"}
+	lot := to.LotsData["lot_1"]
+	delete(lot.ProposalData, "ООО Альфа")
+	lot.ProposalData["ООО Бета"] = api_models.ContractorProposalDetails{Title: "ООО Бета"}
+	to.LotsData["lot_1"] = lot
+
+	diff := CompareTenders(from, to)
+
+	assert.False(t, diff.Identical)
+	require.Len(t, diff.Changes, 1)
+	assert.Equal(t, api_models.DiffFieldChange{Field: "tender_title", From: "Тендер", To: "Тендер (ред.)"}, diff.Changes[0])
+	assert.Equal(t, []string{"lot_2"}, diff.LotsAdded)
+	assert.Empty(t, diff.LotsRemoved)
+	require.Len(t, diff.Lots, 1)
+	assert.Equal(t, []string{"ООО Бета"}, diff.Lots[0].ProposalsAdded)
+	assert.Equal(t, []string{"ООО Альфа"}, diff.Lots[0].ProposalsRemoved)
+	assert.Empty(t, diff.Lots[0].Proposals, "removed proposals are not compared position by position")
+	assert.Equal(t, api_models.TenderImportDiffStats{
+		LotsAdded: 1, LotsChanged: 1, ProposalsAdded: 1, ProposalsRemoved: 1,
+	}, diff.Stats)
+}
+
+func TestCompareTenders_PositionChanges(t *testing.T) {
+	from := makeTender()
+	to := clone(t, from)
+	items := to.LotsData["lot_1"].ProposalData["ООО Альфа"].ContractorItems
+	changed := items.Positions["1"]
+	changed.TotalCost.Total = money("9000")
+	changed.Unit = ptr("м2")
+	items.Positions["1"] = changed
+	delete(items.Positions, "2")
+	items.Positions["3"] = api_models.PositionItem{Number: "3", JobTitle: "Грунтовка", TotalCost: api_models.Cost{Total: money("100")}}
+	items.Summary["total_cost"] = api_models.SummaryLine{JobTitle: "Итого", TotalCost: api_models.Cost{Total: money("8500.0000001")}}
+
+	diff := CompareTenders(from, to)
+
+	require.Len(t, diff.Lots, 1)
+	require.Len(t, diff.Lots[0].Proposals, 1)
+	proposal := diff.Lots[0].Proposals[0]
+	assert.Equal(t, "ООО Альфа", proposal.ProposalKey)
+	assert.Empty(t, proposal.Changes)
+
+	assert.Equal(t, []api_models.DiffItemRef{{Key: "3", Title: "3. Грунтовка", Total: money("100")}}, proposal.Positions.Added)
+	assert.Equal(t, []api_models.DiffItemRef{{Key: "2", Title: "2. Окраска стен", Total: money("500")}}, proposal.Positions.Removed)
+	require.Len(t, proposal.Positions.Changed, 1)
+	assert.Equal(t, []api_models.DiffFieldChange{
+		{Field: "unit", From: nil, To: "м2"},
+		{Field: "total_cost.total", From: *money("8000"), To: *money("9000"), Delta: ptr(decimal.RequireFromString("1000"))},
+	}, proposal.Positions.Changed[0].Changes)
+	assert.Empty(t, proposal.Summary.Changed, "rounding noise is ignored")
+
+	assert.Equal(t, 1, diff.Stats.PositionsAdded)
+	assert.Equal(t, 1, diff.Stats.PositionsRemoved)
+	assert.Equal(t, 1, diff.Stats.PositionsChanged)
+	assert.Equal(t, 1, diff.Stats.ProposalsChanged)
+}
+
+func TestCompareTenders_BaselineAndAdditionalInfo(t *testing.T) {
+	from := makeTender()
+	to := clone(t, from)
+	lot := to.LotsData["lot_1"]
+	lot.BaseLineProposal.ContractorItems.Positions = nil
+	alfa := lot.ProposalData["ООО Альфа"]
+	alfa.AdditionalInfo = map[string]*string{"Срок выполнения": ptr("60 дней")}
+	alfa.ContractorCoordinate = "B12"
+	lot.ProposalData["ООО Альфа"] = alfa
+	to.LotsData["lot_1"] = lot
+
+	diff := CompareTenders(from, to)
+
+	require.Len(t, diff.Lots, 1)
+	proposals := diff.Lots[0].Proposals
+	require.Len(t, proposals, 2)
+	assert.Equal(t, BaselineKey, proposals[0].ProposalKey)
+	assert.Len(t, proposals[0].Positions.Removed, 1)
+	assert.Equal(t, "ООО Альфа", proposals[1].ProposalKey)
+	assert.Equal(t, []api_models.DiffFieldChange{
+		{Field: "additional_info.Срок выполнения", From: nil, To: "60 дней"},
+	}, proposals[1].Changes, "layout fields are not compared")
+}
+
+func TestPriceChanges(t *testing.T) {
+	from := makeTender()
+	to := clone(t, from)
+	items := to.LotsData["lot_1"].ProposalData["ООО Альфа"].ContractorItems
+	repriced := items.Positions["1"]
+	repriced.TotalCost.Total = money("9000")
+	items.Positions["1"] = repriced
+	renamed := items.Positions["2"]
+	renamed.Unit = ptr("м2")
+	items.Positions["2"] = renamed
+	items.Positions["3"] = api_models.PositionItem{Number: "3", JobTitle: "Грунтовка", TotalCost: api_models.Cost{Total: money("100")}}
+
+	diff := CompareTenders(from, to)
+
+	assert.Equal(t, 2, diff.Stats.PositionsChanged)
+	assert.Equal(t, 1, PriceChanges(diff), "unit-only change and added position are not price changes")
+	assert.Equal(t, 0, PriceChanges(CompareTenders(from, clone(t, from))))
+}
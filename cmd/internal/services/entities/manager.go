@@ -87,25 +87,32 @@ func (em *EntityManager) getKindAndStandardTitle(posAPI api_models.PositionItem,
 	}
 
 	// --- Шаг 2: Определяем `standardJobTitle` (Лемму для БД) ---
-	// А вот здесь мы уже берем лемму, если она есть
-
-	var standardJobTitleForDB string
-	if posAPI.JobTitleNormalized != nil && strings.TrimSpace(*posAPI.JobTitleNormalized) != "" {
-		// Берем лемму из JSON: "лот 1 set 1 оч ub2_устройство свайный основание"
-		standardJobTitleForDB = strings.TrimSpace(*posAPI.JobTitleNormalized)
-	} else {
-		// Fallback: используем ту же простую нормализацию, что и на шаге 1
-		trimmedRaw := strings.TrimSpace(posAPI.JobTitle)
-		if trimmedRaw == "" {
-			return "", "", nil
-		}
-		em.logger.Warnf("Поле 'job_title_normalized' отсутствует для '%s'. Используется raw.", trimmedRaw)
-		standardJobTitleForDB = strings.ToLower(strings.Join(strings.Fields(trimmedRaw), " "))
+	standardJobTitleForDB := StandardJobTitle(posAPI)
+	if standardJobTitleForDB == "" {
+		return "", "", nil
+	}
+	if !hasNormalizedTitle(posAPI) {
+		em.logger.Warnf("Поле 'job_title_normalized' отсутствует для '%s'. Используется raw.", strings.TrimSpace(posAPI.JobTitle))
 	}
 
 	return kind, standardJobTitleForDB, nil
 }
 
+// StandardJobTitle возвращает ключ позиции в каталоге (standard_job_title):
+// лемму из JSON ("лот 1 set 1 оч ub2_устройство свайный основание"), а без
+// неё — raw-название после той же простой нормализации, что и при
+// определении kind. Пустая строка — у позиции нет названия.
+func StandardJobTitle(posAPI api_models.PositionItem) string {
+	if hasNormalizedTitle(posAPI) {
+		return strings.TrimSpace(*posAPI.JobTitleNormalized)
+	}
+	return strings.ToLower(strings.Join(strings.Fields(posAPI.JobTitle), " "))
+}
+
+func hasNormalizedTitle(posAPI api_models.PositionItem) bool {
+	return posAPI.JobTitleNormalized != nil && strings.TrimSpace(*posAPI.JobTitleNormalized) != ""
+}
+
 func (em *EntityManager) GetOrCreateObject(
 	ctx context.Context,
 	qtx db.Querier,
@@ -208,6 +215,8 @@ func (em *EntityManager) GetOrCreateContractor(
 		"entity",
 		"contractor",
 	).WithField("inn", inn)
+	// Парсер присылает аккредитацию строкой («Аккредитован», «Да», «N/A»)
+	status := api_models.ParseAccreditationStatus(accreditation)
 
 	return getOrCreateOrUpdate(
 		ctx,
@@ -219,10 +228,10 @@ func (em *EntityManager) GetOrCreateContractor(
 		func() (db.Contractor, error) {
 			opLogger.Info("Подрядчик не найден, создаем нового.")
 			return qtx.CreateContractor(ctx, db.CreateContractorParams{
-				Inn:           inn,
-				Title:         title,
-				Address:       address,
-				Accreditation: accreditation,
+				Inn:                 inn,
+				Title:               title,
+				Address:             address,
+				AccreditationStatus: status,
 			})
 		},
 		func(existing db.Contractor) (bool, db.UpdateContractorParams, error) {
@@ -242,9 +251,12 @@ func (em *EntityManager) GetOrCreateContractor(
 				updateParams.Address = sql.NullString{String: address, Valid: true}
 				needsUpdate = true
 			}
-			if existing.Accreditation != accreditation {
-				opLogger.Infof("Аккредитация подрядчика отличается: '%s' -> '%s'", existing.Accreditation, accreditation)
-				updateParams.Accreditation = sql.NullString{String: accreditation, Valid: true}
+			// Аккредитацию, внесённую вручную, импорт не меняет; нераспознанная
+			// строка парсера не затирает известный статус
+			if existing.AccreditationSource == api_models.AccreditationSourceImport &&
+				status != api_models.AccreditationUnknown && existing.AccreditationStatus != status {
+				opLogger.Infof("Аккредитация подрядчика отличается: '%s' -> '%s' (%q)", existing.AccreditationStatus, status, accreditation)
+				updateParams.AccreditationStatus = sql.NullString{String: status, Valid: true}
 				needsUpdate = true
 			}
 			return needsUpdate, updateParams, nil
@@ -327,7 +339,16 @@ func (em *EntityManager) GetOrCreateCatalogPosition(
 	return result, isNewPendingItem, err
 }
 
+// NormalizeUnitName приводит написание единицы измерения к ключу справочника:
+// без крайних пробелов и в нижнем регистре. По этому ключу ищутся и имена
+// единиц, и их синонимы (unit_aliases).
+func NormalizeUnitName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
 // GetOrCreateUnitOfMeasurement находит или создает единицу измерения.
+// Поиск идёт по имени единицы, затем по синонимам; новая единица создаётся,
+// только если написание не найдено ни там, ни там.
 // apiUnitName - это указатель на строку с названием единицы измерения из JSON (поле "unit" из PositionItem).
 // Возвращает sql.NullInt64, так как unit_id в position_items может быть NULL.
 func (em *EntityManager) GetOrCreateUnitOfMeasurement(
@@ -353,7 +374,7 @@ func (em *EntityManager) GetOrCreateUnitOfMeasurement(
 
 	// Шаг 2: Нормализуем имя для использования в качестве ключа в БД
 	// (например, приводим к нижнему регистру)
-	normalizedNameForDB := strings.ToLower(trimmedUnitName)
+	normalizedNameForDB := NormalizeUnitName(trimmedUnitName)
 
 	opLogger := em.logger.WithFields(map[string]interface{}{
 		"service_method":      "GetOrCreateUnitOfMeasurement",
@@ -365,6 +386,17 @@ func (em *EntityManager) GetOrCreateUnitOfMeasurement(
 	unit, err := qtx.GetUnitOfMeasurementByNormalizedName(ctx, normalizedNameForDB)
 	if err != nil {
 		if err == sql.ErrNoRows {
+			// Имени нет в справочнике — проверяем синонимы ("м.кв" → "м2")
+			aliased, aliasErr := qtx.GetUnitOfMeasurementByAlias(ctx, normalizedNameForDB)
+			if aliasErr == nil {
+				opLogger.Infof("Единица измерения найдена по синониму: '%s', ID: %d", aliased.NormalizedName, aliased.ID)
+				return sql.NullInt64{Int64: aliased.ID, Valid: true}, nil
+			}
+			if aliasErr != sql.ErrNoRows {
+				opLogger.Errorf("Ошибка поиска синонима единицы измерения: %v", aliasErr)
+				return sql.NullInt64{}, fmt.Errorf("ошибка поиска синонима единицы измерения '%s': %w", normalizedNameForDB, aliasErr)
+			}
+
 			// Единица измерения не найдена, создаем новую
 			opLogger.Info("Единица измерения не найдена, создается новая.")
 
@@ -0,0 +1,78 @@
+package entities
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR CONTRACTOR ACCREDITATION ON IMPORT (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Stale import status kept after the proposal reports another one
+2. Manual accreditation overwritten by parser strings
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: GetOrCreateContractor with an existing contractor
+- GIVEN an import-sourced accreditation and another recognized status
+  THEN the status is updated
+- GIVEN a manual accreditation
+  THEN the status is not updated
+*/
+
+func setupContractorImport(t *testing.T, existing db.Contractor) (*EntityManager, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	mockStore.EXPECT().GetContractorByINN(gomock.Any(), existing.Inn).Return(existing, nil)
+	return NewEntityManager(testutil.NewMockLogger()), mockStore
+}
+
+func TestGetOrCreateContractor_Accreditation(t *testing.T) {
+	contractor := func(status, source string) db.Contractor {
+		return db.Contractor{
+			ID:                  5,
+			Inn:                 "7700000001",
+			Title:               "ООО Альфа",
+			Address:             "Москва",
+			AccreditationStatus: status,
+			AccreditationSource: source,
+		}
+	}
+
+	t.Run("import status updated", func(t *testing.T) {
+		existing := contractor(api_models.AccreditationAccredited, api_models.AccreditationSourceImport)
+		manager, mockStore := setupContractorImport(t, existing)
+		mockStore.EXPECT().UpdateContractor(gomock.Any(), db.UpdateContractorParams{
+			ID:                  existing.ID,
+			AccreditationStatus: sql.NullString{String: api_models.AccreditationNotAccredited, Valid: true},
+		}).Return(contractor(api_models.AccreditationNotAccredited, api_models.AccreditationSourceImport), nil)
+
+		got, err := manager.GetOrCreateContractor(context.Background(), mockStore, existing.Inn, existing.Title, existing.Address, "Не аккредитован")
+
+		require.NoError(t, err)
+		assert.Equal(t, api_models.AccreditationNotAccredited, got.AccreditationStatus)
+	})
+
+	t.Run("manual is kept", func(t *testing.T) {
+		existing := contractor(api_models.AccreditationSuspended, api_models.AccreditationSourceManual)
+		manager, mockStore := setupContractorImport(t, existing)
+
+		got, err := manager.GetOrCreateContractor(context.Background(), mockStore, existing.Inn, existing.Title, existing.Address, "Аккредитован")
+
+		require.NoError(t, err)
+		assert.Equal(t, api_models.AccreditationSuspended, got.AccreditationStatus)
+	})
+}
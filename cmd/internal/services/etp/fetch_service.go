@@ -0,0 +1,290 @@
+// Package etp получает тендеры с электронных торговых площадок (ЭТП) через
+// парсер. По etp_id парсер сам скачивает документацию тендера, разбирает её и
+// импортирует результат тем же путём, что и загруженный файл
+// (POST /internal/worker/import-tender), а затем сообщает статус задачи
+// (POST /internal/worker/tasks/:task_id/status).
+//
+// Задача записывается в parse_tasks с source = fetch, поэтому видна в
+// GET /api/v1/tasks и в потоке событий наравне с загрузками. Повторный запрос
+// того же etp_id, пока задача не завершена (etp.pending_ttl), возвращает её же;
+// уже импортированный тендер без force повторно не запрашивается — 409.
+package etp
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/outbound"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/parsetask"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// SourceFetch — parse_tasks.source задач получения тендера.
+const SourceFetch = "fetch"
+
+// parserResponseLimit — предел ответа парсера: ответы — небольшие JSON.
+const parserResponseLimit = 1 << 20
+
+// ErrParserUnavailable — парсер не ответил, ответил 5xx или без task_id.
+// Оборачивает исходную ошибку: outbound.ErrCircuitOpen проверяется errors.Is.
+var ErrParserUnavailable = errors.New("сервис парсера недоступен")
+
+// FetchService ставит парсеру задачи получения тендеров с ЭТП.
+type FetchService struct {
+	store      db.Store
+	logger     logging.Logger
+	client     *outbound.Client
+	parseTasks *parsetask.ParseTaskService
+	parserURL  string
+	cfg        config.ETPConfig
+}
+
+// NewFetchService создаёт сервис получения тендеров. parserURL —
+// services.parser_service.url.
+func NewFetchService(
+	store db.Store,
+	logger logging.Logger,
+	client *outbound.Client,
+	parseTasks *parsetask.ParseTaskService,
+	parserURL string,
+	cfg config.ETPConfig,
+) *FetchService {
+	return &FetchService{
+		store:      store,
+		logger:     logger.WithField("service", "etp"),
+		client:     client,
+		parseTasks: parseTasks,
+		parserURL:  strings.TrimSuffix(parserURL, "/"),
+		cfg:        cfg,
+	}
+}
+
+// FetchRequest — запрос получения тендера и кто его сделал.
+type FetchRequest struct {
+	api_models.TenderFetchRequest
+	UserID         sql.NullInt64
+	OrganizationID int64 // Организация, в которую импортируется тендер
+}
+
+// fetchPayload — тело запроса к парсеру.
+type fetchPayload struct {
+	EtpID          string `json:"etp_id"`
+	URL            string `json:"url,omitempty"`
+	EnableAI       bool   `json:"enable_ai"`
+	OrganizationID int64  `json:"organization_id"`
+}
+
+// Fetch ставит парсеру задачу получения тендера и записывает её в parse_tasks.
+//
+// Тендер с этим etp_id уже импортирован — ConflictError с
+// TenderFetchConflict (с force — запрашивается заново, кроме тендера чужой
+// организации). Незавершённая задача того же etp_id — возвращается она
+// (Deduplicated). Ответ парсера 404 — NotFoundError, 400/422 —
+// ValidationError, недоступность — ErrParserUnavailable.
+func (s *FetchService) Fetch(ctx context.Context, req FetchRequest) (*api_models.TenderFetchResponse, error) {
+	etpID := strings.TrimSpace(req.EtpID)
+	if etpID == "" {
+		return nil, apierrors.NewValidationError("etp.etp_id_empty")
+	}
+
+	tender, err := s.store.GetTenderByEtpID(ctx, etpID)
+	switch {
+	case err == nil:
+		if tender.OrganizationID != req.OrganizationID {
+			return nil, apierrors.NewConflictErrorf(nil, "tender.other_organization", etpID)
+		}
+		if !req.Force {
+			return nil, apierrors.NewConflictErrorf(api_models.TenderFetchConflict{TenderID: tender.ID, Deleted: tender.DeletedAt.Valid}, "etp.tender_already_imported", etpID)
+		}
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, fmt.Errorf("ошибка GetTenderByEtpID(%s): %w", etpID, err)
+	}
+
+	pending, err := s.store.GetPendingFetchParseTask(ctx, db.GetPendingFetchParseTaskParams{
+		EtpID:          etpID,
+		OrganizationID: req.OrganizationID,
+		PendingSeconds: int32(s.cfg.PendingTTL.Seconds()),
+	})
+	switch {
+	case err == nil:
+		s.logger.Infof("Тендер %s уже получается задачей %s, новая не ставится", etpID, pending.TaskID)
+		return &api_models.TenderFetchResponse{ParseTask: parsetask.ToResponse(pending, false), Deduplicated: true}, nil
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, fmt.Errorf("ошибка GetPendingFetchParseTask(%s): %w", etpID, err)
+	}
+
+	taskID, err := s.requestFetch(ctx, fetchPayload{
+		EtpID:          etpID,
+		URL:            req.URL,
+		EnableAI:       req.EnableAI,
+		OrganizationID: req.OrganizationID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Infof("Парсер принял задачу %s получения тендера %s (organization_id=%d)", taskID, etpID, req.OrganizationID)
+
+	task, err := s.store.CreateFetchParseTask(ctx, db.CreateFetchParseTaskParams{
+		TaskID:         taskID,
+		UserID:         req.UserID,
+		OrganizationID: req.OrganizationID,
+		EnableAi:       req.EnableAI,
+		EtpID:          etpID,
+	})
+	if err != nil {
+		// Парсер задачу уже принял: ошибка клиенту привела бы к повторному запросу
+		s.logger.Errorf("Не удалось сохранить задачу %s получения тендера %s: %v", taskID, etpID, err)
+		return &api_models.TenderFetchResponse{ParseTask: api_models.ParseTask{
+			TaskID:       taskID,
+			Status:       "queued",
+			StatusSource: "polled",
+			Source:       SourceFetch,
+			EtpID:        etpID,
+			EnableAI:     req.EnableAI,
+			CreatedAt:    time.Now(),
+			UpdatedAt:    time.Now(),
+		}}, nil
+	}
+	return &api_models.TenderFetchResponse{ParseTask: parsetask.ToResponse(task, false)}, nil
+}
+
+// requestFetch отправляет парсеру запрос получения тендера и возвращает task_id.
+func (s *FetchService) requestFetch(ctx context.Context, payload fetchPayload) (string, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("ошибка сериализации запроса к парсеру: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.RequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.parserURL+s.cfg.FetchPath, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("ошибка создания запроса к парсеру: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrParserUnavailable, err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, parserResponseLimit))
+	if err != nil {
+		return "", fmt.Errorf("%w: ошибка чтения ответа: %w", ErrParserUnavailable, err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", apierrors.NewNotFoundError("etp.tender_not_found", payload.EtpID)
+	case resp.StatusCode == http.StatusBadRequest, resp.StatusCode == http.StatusUnprocessableEntity:
+		return "", apierrors.NewValidationError("etp.fetch_rejected", payload.EtpID, parserMessage(respBody))
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return "", fmt.Errorf("%w: ответ %d: %s", ErrParserUnavailable, resp.StatusCode, parserMessage(respBody))
+	}
+
+	var parsed struct {
+		TaskID string `json:"task_id"`
+	}
+	if err := json.Unmarshal(respBody, &parsed); err != nil || parsed.TaskID == "" {
+		return "", fmt.Errorf("%w: в ответе нет task_id", ErrParserUnavailable)
+	}
+	return parsed.TaskID, nil
+}
+
+// Status возвращает задачу получения тендера. Пока парсер не сообщил итог,
+// статус запрашивается у него и запоминается; если парсер задачу не знает
+// или недоступен — ответ строится по записи (Stored). organizationID —
+// организация пользователя (невалидный — без фильтра); задача другой
+// организации или загрузка файла — NotFoundError.
+func (s *FetchService) Status(ctx context.Context, taskID string, organizationID sql.NullInt64) (*api_models.ParseTask, error) {
+	task, found, err := s.parseTasks.Lookup(ctx, taskID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if !found || task.Source != SourceFetch {
+		return nil, apierrors.NewNotFoundError("etp.fetch_task_not_found", taskID)
+	}
+	stored := parsetask.ToResponse(task, true)
+	if task.FinishedAt.Valid {
+		return &stored, nil
+	}
+
+	body, ok := s.pollStatus(ctx, taskID)
+	if !ok {
+		return &stored, nil
+	}
+	if err := s.parseTasks.ApplyPolledStatus(ctx, task, body); err != nil {
+		s.logger.Errorf("Не удалось сохранить статус задачи %s: %v", taskID, err)
+		return &stored, nil
+	}
+	task, err = s.store.GetParseTaskByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка GetParseTaskByTaskID(%s): %w", taskID, err)
+	}
+	response := parsetask.ToResponse(task, false)
+	return &response, nil
+}
+
+// pollStatus запрашивает статус задачи у парсера. ok=false — парсер
+// недоступен или задачу не знает.
+func (s *FetchService) pollStatus(ctx context.Context, taskID string) ([]byte, bool) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.RequestTimeout)
+	defer cancel()
+	statusURL := fmt.Sprintf("%s/tasks/%s/status", s.parserURL, url.PathEscape(taskID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, statusURL, nil)
+	if err != nil {
+		s.logger.Errorf("Ошибка создания запроса статуса задачи %s: %v", taskID, err)
+		return nil, false
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.logger.Warnf("Парсер недоступен (%v), статус задачи %s отдан из parse_tasks", err, taskID)
+		return nil, false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, false
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, parserResponseLimit))
+	if err != nil {
+		s.logger.Warnf("Ошибка чтения статуса задачи %s: %v", taskID, err)
+		return nil, false
+	}
+	return body, true
+}
+
+// parserMessage извлекает текст ошибки из ответа парсера (FastAPI: detail)
+// или возвращает начало тела.
+func parserMessage(body []byte) string {
+	var parsed struct {
+		Detail any    `json:"detail"`
+		Error  string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &parsed); err == nil {
+		if detail, ok := parsed.Detail.(string); ok && detail != "" {
+			return detail
+		}
+		if parsed.Error != "" {
+			return parsed.Error
+		}
+	}
+	const maxLen = 200
+	text := strings.TrimSpace(string(body))
+	if runes := []rune(text); len(runes) > maxLen {
+		text = string(runes[:maxLen]) + "…"
+	}
+	return text
+}
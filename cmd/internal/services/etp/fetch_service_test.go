@@ -0,0 +1,261 @@
+package etp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/live"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/outbound"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/parsetask"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR ETP TENDER FETCHING (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Double-clicking "Fetch" makes the parser download the same tender twice
+2. Re-fetching a tender that is already imported silently creates a new version
+3. A tender of another organization is overwritten through a fetch
+4. The user cannot tell "no such tender on the ETP" from "parser is down"
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: New fetch
+- GIVEN an unknown etp_id and no pending task
+  WHEN Fetch is called
+  THEN the parser receives etp_id, enable_ai and organization_id
+  AND the task is stored with source=fetch
+
+SCENARIO 2: Deduplication
+- GIVEN an imported tender of the same organization THEN ConflictError with its ID, parser not called
+- GIVEN force=true THEN the tender is fetched again
+- GIVEN a tender of another organization THEN ConflictError even with force
+- GIVEN a pending fetch task of the same etp_id THEN it is returned with deduplicated=true
+
+SCENARIO 3: Parser errors
+- GIVEN the parser answers 404 THEN NotFoundError
+- GIVEN the parser answers 500 THEN ErrParserUnavailable
+
+SCENARIO 4: Status
+- GIVEN an unfinished fetch task THEN the parser is polled and the new status stored
+- GIVEN an upload task ID THEN NotFoundError
+*/
+
+// fakeParser — парсер, отвечающий status и body на любой запрос.
+type fakeParser struct {
+	status   int
+	body     string
+	requests []*http.Request
+	payloads []fetchPayload
+}
+
+func (p *fakeParser) start(t *testing.T) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.requests = append(p.requests, r)
+		if r.Method == http.MethodPost {
+			var payload fetchPayload
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			p.payloads = append(p.payloads, payload)
+		}
+		w.WriteHeader(p.status)
+		_, _ = w.Write([]byte(p.body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func setupTestService(t *testing.T, parser *fakeParser) (*FetchService, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	logger := testutil.NewMockLogger()
+	client := outbound.NewClient(config.OutboundHTTPConfig{
+		MaxAttempts: 1, RetryBaseDelay: time.Millisecond, RetryMaxDelay: time.Millisecond,
+		BreakerFailureThreshold: 5, BreakerOpenTimeout: time.Second,
+	}, logger)
+	hub := live.NewHub(config.LiveConfig{BufferSize: 4, MaxConnectionsPerUser: 1}, logger)
+	cfg := config.ETPConfig{FetchPath: "/fetch-tender/", RequestTimeout: 5 * time.Second, PendingTTL: 30 * time.Minute}
+	service := NewFetchService(mockStore, logger, client, parsetask.NewParseTaskService(mockStore, logger, hub), parser.start(t), cfg)
+	return service, mockStore
+}
+
+func fetchRequest(force bool) FetchRequest {
+	return FetchRequest{
+		TenderFetchRequest: api_models.TenderFetchRequest{EtpID: " ETP-7 ", EnableAI: true, Force: force},
+		UserID:             sql.NullInt64{Int64: 3, Valid: true},
+		OrganizationID:     5,
+	}
+}
+
+func TestFetch_NewTender(t *testing.T) {
+	parser := &fakeParser{status: http.StatusAccepted, body: `{"task_id":"t-1"}`}
+	service, mockStore := setupTestService(t, parser)
+
+	mockStore.EXPECT().GetTenderByEtpID(gomock.Any(), "ETP-7").Return(db.Tender{}, sql.ErrNoRows)
+	mockStore.EXPECT().GetPendingFetchParseTask(gomock.Any(), db.GetPendingFetchParseTaskParams{
+		EtpID: "ETP-7", OrganizationID: 5, PendingSeconds: 1800,
+	}).Return(db.ParseTask{}, sql.ErrNoRows)
+	mockStore.EXPECT().CreateFetchParseTask(gomock.Any(), db.CreateFetchParseTaskParams{
+		TaskID: "t-1", UserID: sql.NullInt64{Int64: 3, Valid: true}, OrganizationID: 5, EnableAi: true, EtpID: "ETP-7",
+	}).Return(db.ParseTask{TaskID: "t-1", Status: "queued", Source: SourceFetch, EtpID: sql.NullString{String: "ETP-7", Valid: true}}, nil)
+
+	result, err := service.Fetch(context.Background(), fetchRequest(false))
+
+	require.NoError(t, err)
+	assert.False(t, result.Deduplicated)
+	assert.Equal(t, "t-1", result.TaskID)
+	assert.Equal(t, "ETP-7", result.EtpID)
+	require.Len(t, parser.payloads, 1)
+	assert.Equal(t, "/fetch-tender/", parser.requests[0].URL.Path)
+	assert.Equal(t, fetchPayload{EtpID: "ETP-7", EnableAI: true, OrganizationID: 5}, parser.payloads[0])
+}
+
+func TestFetch_AlreadyImported(t *testing.T) {
+	parser := &fakeParser{status: http.StatusAccepted, body: `{"task_id":"t-1"}`}
+	service, mockStore := setupTestService(t, parser)
+
+	mockStore.EXPECT().GetTenderByEtpID(gomock.Any(), "ETP-7").Return(db.Tender{ID: 42, OrganizationID: 5}, nil)
+
+	_, err := service.Fetch(context.Background(), fetchRequest(false))
+
+	var conflictErr *apierrors.ConflictError
+	require.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, api_models.TenderFetchConflict{TenderID: 42}, conflictErr.Conflicts)
+	assert.Empty(t, parser.requests, "парсер не вызывается")
+}
+
+func TestFetch_ForceRefetch(t *testing.T) {
+	parser := &fakeParser{status: http.StatusAccepted, body: `{"task_id":"t-2"}`}
+	service, mockStore := setupTestService(t, parser)
+
+	mockStore.EXPECT().GetTenderByEtpID(gomock.Any(), "ETP-7").Return(db.Tender{ID: 42, OrganizationID: 5}, nil)
+	mockStore.EXPECT().GetPendingFetchParseTask(gomock.Any(), gomock.Any()).Return(db.ParseTask{}, sql.ErrNoRows)
+	mockStore.EXPECT().CreateFetchParseTask(gomock.Any(), gomock.Any()).Return(db.ParseTask{TaskID: "t-2", Source: SourceFetch}, nil)
+
+	result, err := service.Fetch(context.Background(), fetchRequest(true))
+
+	require.NoError(t, err)
+	assert.Equal(t, "t-2", result.TaskID)
+	assert.Len(t, parser.requests, 1)
+}
+
+func TestFetch_OtherOrganization(t *testing.T) {
+	parser := &fakeParser{status: http.StatusAccepted, body: `{"task_id":"t-1"}`}
+	service, mockStore := setupTestService(t, parser)
+
+	mockStore.EXPECT().GetTenderByEtpID(gomock.Any(), "ETP-7").Return(db.Tender{ID: 42, OrganizationID: 9}, nil)
+
+	_, err := service.Fetch(context.Background(), fetchRequest(true))
+
+	var conflictErr *apierrors.ConflictError
+	require.ErrorAs(t, err, &conflictErr)
+	assert.Nil(t, conflictErr.Conflicts, "ID чужого тендера не раскрывается")
+	assert.Empty(t, parser.requests)
+}
+
+func TestFetch_PendingTaskDeduplicated(t *testing.T) {
+	parser := &fakeParser{status: http.StatusAccepted, body: `{"task_id":"t-new"}`}
+	service, mockStore := setupTestService(t, parser)
+
+	mockStore.EXPECT().GetTenderByEtpID(gomock.Any(), "ETP-7").Return(db.Tender{}, sql.ErrNoRows)
+	mockStore.EXPECT().GetPendingFetchParseTask(gomock.Any(), gomock.Any()).
+		Return(db.ParseTask{TaskID: "t-1", Status: "processing", Source: SourceFetch}, nil)
+
+	result, err := service.Fetch(context.Background(), fetchRequest(false))
+
+	require.NoError(t, err)
+	assert.True(t, result.Deduplicated)
+	assert.Equal(t, "t-1", result.TaskID)
+	assert.Empty(t, parser.requests)
+}
+
+func TestFetch_ParserErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		check  func(t *testing.T, err error)
+	}{
+		{"not found on ETP", http.StatusNotFound, `{"detail":"tender not found"}`, func(t *testing.T, err error) {
+			var notFoundErr *apierrors.NotFoundError
+			assert.ErrorAs(t, err, &notFoundErr)
+		}},
+		{"rejected", http.StatusUnprocessableEntity, `{"detail":"unsupported ETP"}`, func(t *testing.T, err error) {
+			var validationErr *apierrors.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Contains(t, err.Error(), "unsupported ETP")
+		}},
+		{"parser down", http.StatusInternalServerError, `oops`, func(t *testing.T, err error) {
+			assert.True(t, errors.Is(err, ErrParserUnavailable))
+		}},
+		{"no task_id", http.StatusOK, `{}`, func(t *testing.T, err error) {
+			assert.True(t, errors.Is(err, ErrParserUnavailable))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockStore := setupTestService(t, &fakeParser{status: tt.status, body: tt.body})
+			mockStore.EXPECT().GetTenderByEtpID(gomock.Any(), "ETP-7").Return(db.Tender{}, sql.ErrNoRows)
+			mockStore.EXPECT().GetPendingFetchParseTask(gomock.Any(), gomock.Any()).Return(db.ParseTask{}, sql.ErrNoRows)
+
+			_, err := service.Fetch(context.Background(), fetchRequest(false))
+
+			require.Error(t, err)
+			tt.check(t, err)
+		})
+	}
+}
+
+func TestStatus_PollsParser(t *testing.T) {
+	body := `{"task_id":"t-1","status":"processing"}`
+	parser := &fakeParser{status: http.StatusOK, body: body}
+	service, mockStore := setupTestService(t, parser)
+	task := db.ParseTask{TaskID: "t-1", Status: "queued", Source: SourceFetch, OrganizationID: 5}
+
+	mockStore.EXPECT().GetParseTaskByTaskID(gomock.Any(), "t-1").Return(task, nil)
+	mockStore.EXPECT().UpdateParseTaskPolledStatus(gomock.Any(), db.UpdateParseTaskPolledStatusParams{
+		TaskID: "t-1", Status: "processing", LastStatus: []byte(body),
+	}).Return(nil)
+	updated := task
+	updated.Status = "processing"
+	mockStore.EXPECT().GetParseTaskByTaskID(gomock.Any(), "t-1").Return(updated, nil)
+
+	result, err := service.Status(context.Background(), "t-1", sql.NullInt64{Int64: 5, Valid: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, "processing", result.Status)
+	assert.False(t, result.Stored)
+	assert.Equal(t, "/tasks/t-1/status", parser.requests[0].URL.Path)
+}
+
+func TestStatus_UploadTaskNotFound(t *testing.T) {
+	parser := &fakeParser{status: http.StatusOK}
+	service, mockStore := setupTestService(t, parser)
+
+	mockStore.EXPECT().GetParseTaskByTaskID(gomock.Any(), "t-1").
+		Return(db.ParseTask{TaskID: "t-1", Source: "upload", OrganizationID: 5}, nil)
+
+	_, err := service.Status(context.Background(), "t-1", sql.NullInt64{Int64: 5, Valid: true})
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+	assert.Empty(t, parser.requests)
+}
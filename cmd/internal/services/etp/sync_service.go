@@ -0,0 +1,325 @@
+package etp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/sqlc-dev/pqtype"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/diffing"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// SourceSync — parse_tasks.source задач обновления тендера по расписанию.
+const SourceSync = "sync"
+
+// Статусы обновления тендера (tender_syncs.status).
+const (
+	SyncPending   = "pending"
+	SyncChanged   = "changed"
+	SyncUnchanged = "unchanged"
+	SyncFailed    = "failed"
+)
+
+const (
+	defaultSyncsLimit = 20
+	maxSyncsLimit     = 100
+)
+
+// SyncService обновляет открытые тендеры с ЭТП по расписанию.
+//
+// Задача планировщика (Run) ставит парсеру задачи получения тендеров, у
+// которых процедура на ЭТП не завершена (etp_status не из
+// etp.sync.final_statuses). Парсер импортирует тендер как обычно и сообщает
+// итог; Complete сравнивает версию исходного JSON до обновления с новой,
+// записывает результат в tender_syncs и, если тендер изменился, публикует
+// tender.synced — по нему лента уведомляет подписчиков.
+type SyncService struct {
+	store     db.Store
+	logger    logging.Logger
+	fetch     *FetchService
+	publisher events.Publisher
+	cfg       config.ETPSyncConfig
+}
+
+// NewSyncService создаёт сервис обновления тендеров. Запросы к парсеру идут
+// через fetch — с его путём, таймаутом и клиентом.
+func NewSyncService(
+	store db.Store,
+	logger logging.Logger,
+	fetch *FetchService,
+	publisher events.Publisher,
+	cfg config.ETPSyncConfig,
+) *SyncService {
+	return &SyncService{
+		store:     store,
+		logger:    logger.WithField("service", "etp_sync"),
+		fetch:     fetch,
+		publisher: publisher,
+		cfg:       cfg,
+	}
+}
+
+// Run запрашивает у парсера порцию тендеров, которые пора обновить
+// (JobFunc задачи планировщика etp_sync). Тендер, который парсер отклонил
+// (например, снят с ЭТП), считается запрошенным и повторяется через
+// refresh_after; недоступность парсера прерывает запуск.
+func (s *SyncService) Run(ctx context.Context) (string, error) {
+	tenders, err := s.store.ListTendersForSync(ctx, db.ListTendersForSyncParams{
+		FinalStatuses:  s.cfg.FinalStatuses,
+		RefreshSeconds: int32(s.cfg.RefreshAfter.Seconds()),
+		BatchSize:      s.cfg.BatchSize,
+	})
+	if err != nil {
+		return "", fmt.Errorf("ошибка ListTendersForSync: %w", err)
+	}
+
+	requested, rejected := 0, 0
+	for _, tender := range tenders {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		err := s.requestSync(ctx, tender)
+		switch {
+		case err == nil:
+			requested++
+		case errors.Is(err, ErrParserUnavailable):
+			return "", fmt.Errorf("запрошено тендеров: %d, далее парсер недоступен: %w", requested, err)
+		default:
+			var notFoundErr *apierrors.NotFoundError
+			var validationErr *apierrors.ValidationError
+			if !errors.As(err, &notFoundErr) && !errors.As(err, &validationErr) {
+				return "", err
+			}
+			rejected++
+			s.logger.Warnf("Парсер отклонил обновление тендера %s: %v", tender.EtpID, err)
+			if err := s.store.MarkTenderSynced(ctx, tender.ID); err != nil {
+				return "", fmt.Errorf("ошибка MarkTenderSynced(%d): %w", tender.ID, err)
+			}
+		}
+	}
+	return fmt.Sprintf("запрошено тендеров: %d, отклонено парсером: %d", requested, rejected), nil
+}
+
+// requestSync ставит парсеру задачу получения тендера и записывает её в
+// parse_tasks и tender_syncs вместе с последней версией исходного JSON.
+func (s *SyncService) requestSync(ctx context.Context, tender db.ListTendersForSyncRow) error {
+	versions, err := s.store.ListTenderRawDataVersions(ctx, tender.ID)
+	if err != nil {
+		return fmt.Errorf("ошибка ListTenderRawDataVersions(%d): %w", tender.ID, err)
+	}
+	var fromVersion sql.NullInt32
+	if len(versions) > 0 {
+		fromVersion = sql.NullInt32{Int32: versions[0].Version, Valid: true}
+	}
+
+	taskID, err := s.fetch.requestFetch(ctx, fetchPayload{EtpID: tender.EtpID, OrganizationID: tender.OrganizationID})
+	if err != nil {
+		return err
+	}
+
+	// Парсер задачу уже принял: дальше ошибки только логируются, иначе
+	// следующий запуск запросил бы тендер ещё раз
+	if _, err := s.store.CreateSyncParseTask(ctx, db.CreateSyncParseTaskParams{
+		TaskID:         taskID,
+		OrganizationID: tender.OrganizationID,
+		EtpID:          tender.EtpID,
+	}); err != nil {
+		s.logger.Errorf("Не удалось сохранить задачу %s обновления тендера %s: %v", taskID, tender.EtpID, err)
+	} else if _, err := s.store.CreateTenderSync(ctx, db.CreateTenderSyncParams{
+		TenderID:    tender.ID,
+		TaskID:      taskID,
+		FromVersion: fromVersion,
+	}); err != nil {
+		s.logger.Errorf("Не удалось записать обновление тендера %s (задача %s): %v", tender.EtpID, taskID, err)
+	}
+	if err := s.store.MarkTenderSynced(ctx, tender.ID); err != nil {
+		s.logger.Errorf("Ошибка MarkTenderSynced(%d): %v", tender.ID, err)
+	}
+	s.logger.Infof("Парсер принял задачу %s обновления тендера %s (статус на ЭТП: %s)", taskID, tender.EtpID, tender.EtpStatus.String)
+	return nil
+}
+
+// Complete записывает результат обновления, когда парсер сообщил итог задачи
+// (POST /internal/worker/tasks/:task_id/status). Задачи других источников,
+// промежуточные статусы и уже записанные обновления пропускаются.
+func (s *SyncService) Complete(ctx context.Context, task api_models.ParseTask) error {
+	if task.Source != SourceSync || task.FinishedAt == nil {
+		return nil
+	}
+	sync, err := s.store.GetTenderSyncByTaskID(ctx, task.TaskID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.Warnf("Обновление по задаче %s не записано, результат не сохраняется", task.TaskID)
+			return nil
+		}
+		return fmt.Errorf("ошибка GetTenderSyncByTaskID(%s): %w", task.TaskID, err)
+	}
+	if sync.Status != SyncPending {
+		return nil
+	}
+
+	params := db.FinishTenderSyncParams{TaskID: task.TaskID, Status: SyncUnchanged}
+	var diff *api_models.TenderImportDiff
+	if task.Status == "failed" {
+		params.Status = SyncFailed
+		params.ErrorMessage = sql.NullString{String: task.Error, Valid: task.Error != ""}
+	} else {
+		diff, err = s.compareVersions(ctx, sync, &params)
+		if err != nil {
+			return err
+		}
+	}
+
+	finished, err := s.store.FinishTenderSync(ctx, params)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("ошибка FinishTenderSync(%s): %w", task.TaskID, err)
+	}
+	s.logger.Infof("Обновление тендера %d по задаче %s: %s", sync.TenderID, task.TaskID, finished.Status)
+
+	if finished.Status == SyncChanged && diff != nil {
+		events.Emit(ctx, s.publisher, s.logger, events.TypeTenderSynced, sync.TenderID, events.TenderSyncedData{
+			TenderDBID:     sync.TenderID,
+			TenderID:       task.EtpID,
+			SyncID:         finished.ID,
+			FromVersion:    finished.FromVersion.Int32,
+			ToVersion:      finished.ToVersion.Int32,
+			ProposalsAdded: int(finished.ProposalsAdded),
+			PricesChanged:  int(finished.PricesChanged),
+		})
+	}
+	return nil
+}
+
+// compareVersions сравнивает версию исходного JSON до обновления с последней
+// и заполняет результат в params. Новой версии нет — импорта не было,
+// обновление без изменений.
+func (s *SyncService) compareVersions(ctx context.Context, sync db.TenderSync, params *db.FinishTenderSyncParams) (*api_models.TenderImportDiff, error) {
+	versions, err := s.store.ListTenderRawDataVersions(ctx, sync.TenderID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ListTenderRawDataVersions(%d): %w", sync.TenderID, err)
+	}
+	if len(versions) == 0 || sync.FromVersion.Valid && versions[0].Version <= sync.FromVersion.Int32 {
+		return nil, nil
+	}
+	params.ToVersion = sql.NullInt32{Int32: versions[0].Version, Valid: true}
+	if !sync.FromVersion.Valid {
+		params.Status = SyncChanged
+		return nil, nil
+	}
+
+	from, err := s.loadVersion(ctx, sync.TenderID, sync.FromVersion.Int32)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.loadVersion(ctx, sync.TenderID, versions[0].Version)
+	if err != nil {
+		return nil, err
+	}
+	diff := diffing.CompareTenders(from, to)
+	if diff.Identical {
+		return diff, nil
+	}
+
+	params.Status = SyncChanged
+	params.ProposalsAdded = int32(diff.Stats.ProposalsAdded)
+	params.PricesChanged = int32(diffing.PriceChanges(diff))
+	stats, err := json.Marshal(diff.Stats)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации счётчиков изменений: %w", err)
+	}
+	params.Stats = pqtype.NullRawMessage{RawMessage: stats, Valid: true}
+	return diff, nil
+}
+
+func (s *SyncService) loadVersion(ctx context.Context, tenderID int64, version int32) (*api_models.FullTenderData, error) {
+	row, err := s.store.GetTenderRawDataVersion(ctx, db.GetTenderRawDataVersionParams{TenderID: tenderID, Version: version})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка GetTenderRawDataVersion(%d, %d): %w", tenderID, version, err)
+	}
+	var payload api_models.FullTenderData
+	if err := json.Unmarshal(row.RawData, &payload); err != nil {
+		return nil, fmt.Errorf("некорректный JSON версии %d тендера %d: %w", version, tenderID, err)
+	}
+	return &payload, nil
+}
+
+// List возвращает обновления тендера, последние первыми. limit <= 0 —
+// значение по умолчанию.
+func (s *SyncService) List(ctx context.Context, tenderID int64, limit, offset int32) (*api_models.TenderSyncsResponse, error) {
+	if limit <= 0 {
+		limit = defaultSyncsLimit
+	}
+	if limit > maxSyncsLimit {
+		return nil, apierrors.NewValidationError("request.limit_max", maxSyncsLimit)
+	}
+	if offset < 0 {
+		return nil, apierrors.NewValidationError("request.offset_negative")
+	}
+
+	rows, err := s.store.ListTenderSyncs(ctx, db.ListTenderSyncsParams{
+		TenderID:   tenderID,
+		PageLimit:  limit,
+		PageOffset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ListTenderSyncs(%d): %w", tenderID, err)
+	}
+	total, err := s.store.CountTenderSyncs(ctx, tenderID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка CountTenderSyncs(%d): %w", tenderID, err)
+	}
+
+	items := make([]api_models.TenderSync, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, toSyncResponse(row))
+	}
+	return &api_models.TenderSyncsResponse{
+		TenderID: tenderID,
+		Items:    items,
+		Total:    total,
+		Limit:    limit,
+		Offset:   offset,
+	}, nil
+}
+
+func toSyncResponse(row db.TenderSync) api_models.TenderSync {
+	item := api_models.TenderSync{
+		ID:             row.ID,
+		TaskID:         row.TaskID,
+		Status:         row.Status,
+		ProposalsAdded: row.ProposalsAdded,
+		PricesChanged:  row.PricesChanged,
+		Error:          row.ErrorMessage.String,
+		RequestedAt:    row.RequestedAt,
+	}
+	if row.FromVersion.Valid {
+		version := row.FromVersion.Int32
+		item.FromVersion = &version
+	}
+	if row.ToVersion.Valid {
+		version := row.ToVersion.Int32
+		item.ToVersion = &version
+	}
+	if row.Stats.Valid {
+		var stats api_models.TenderImportDiffStats
+		if err := json.Unmarshal(row.Stats.RawMessage, &stats); err == nil {
+			item.Stats = &stats
+		}
+	}
+	if row.FinishedAt.Valid {
+		finishedAt := row.FinishedAt.Time
+		item.FinishedAt = &finishedAt
+	}
+	return item
+}
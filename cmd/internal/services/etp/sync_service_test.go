@@ -0,0 +1,237 @@
+package etp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR SCHEDULED TENDER SYNC (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. New proposals and price changes on the ETP stay unnoticed until someone re-fetches the tender
+2. A tender removed from the ETP blocks the sync queue forever
+3. A parser outage makes every tender in the batch fail one by one
+4. Followers get notified about syncs that changed nothing
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Run
+- GIVEN an open tender with two imported versions
+  WHEN Run is called
+  THEN the parser gets a fetch request, the task is stored with source=sync
+  AND tender_syncs remembers version 2 as the starting point, the tender is marked synced
+- GIVEN the parser answers 404 THEN the tender is marked synced and counted as rejected
+- GIVEN the parser answers 500 THEN Run fails with ErrParserUnavailable and nothing is marked
+
+SCENARIO 2: Complete
+- GIVEN a completed sync task and a new version with a new proposal and a new price
+  THEN the sync is recorded as changed with counters and stats AND tender.synced is published
+- GIVEN a completed sync task without a new version THEN unchanged, no event
+- GIVEN a failed sync task THEN failed with the parser error, no event
+- GIVEN an upload task or an unfinished sync task THEN nothing happens
+*/
+
+// recordingPublisher запоминает опубликованные события.
+type recordingPublisher struct {
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, event events.Event) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *recordingPublisher) Close(context.Context) error { return nil }
+
+func setupSyncService(t *testing.T, parser *fakeParser) (*SyncService, *db.MockStore, *recordingPublisher) {
+	t.Helper()
+	fetch, mockStore := setupTestService(t, parser)
+	publisher := &recordingPublisher{}
+	cfg := config.ETPSyncConfig{
+		Enabled: true, Interval: time.Hour, RefreshAfter: 24 * time.Hour, BatchSize: 20,
+		FinalStatuses: []string{"completed", "cancelled"},
+	}
+	return NewSyncService(mockStore, testutil.NewMockLogger(), fetch, publisher, cfg), mockStore, publisher
+}
+
+var openTender = db.ListTendersForSyncRow{
+	ID: 7, EtpID: "ETP-7", OrganizationID: 5, EtpStatus: sql.NullString{String: "accepting_proposals", Valid: true},
+}
+
+func expectTendersForSync(mockStore *db.MockStore) {
+	mockStore.EXPECT().ListTendersForSync(gomock.Any(), db.ListTendersForSyncParams{
+		FinalStatuses: []string{"completed", "cancelled"}, RefreshSeconds: 86400, BatchSize: 20,
+	}).Return([]db.ListTendersForSyncRow{openTender}, nil)
+	mockStore.EXPECT().ListTenderRawDataVersions(gomock.Any(), int64(7)).
+		Return([]db.ListTenderRawDataVersionsRow{{TenderID: 7, Version: 2}, {TenderID: 7, Version: 1}}, nil)
+}
+
+func TestRun_RequestsOpenTenders(t *testing.T) {
+	parser := &fakeParser{status: http.StatusAccepted, body: `{"task_id":"s-1"}`}
+	service, mockStore, _ := setupSyncService(t, parser)
+
+	expectTendersForSync(mockStore)
+	mockStore.EXPECT().CreateSyncParseTask(gomock.Any(), db.CreateSyncParseTaskParams{
+		TaskID: "s-1", OrganizationID: 5, EtpID: "ETP-7",
+	}).Return(db.ParseTask{TaskID: "s-1", Source: SourceSync}, nil)
+	mockStore.EXPECT().CreateTenderSync(gomock.Any(), db.CreateTenderSyncParams{
+		TenderID: 7, TaskID: "s-1", FromVersion: sql.NullInt32{Int32: 2, Valid: true},
+	}).Return(db.TenderSync{ID: 1}, nil)
+	mockStore.EXPECT().MarkTenderSynced(gomock.Any(), int64(7)).Return(nil)
+
+	result, err := service.Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "запрошено тендеров: 1, отклонено парсером: 0", result)
+	require.Len(t, parser.payloads, 1)
+	assert.Equal(t, fetchPayload{EtpID: "ETP-7", OrganizationID: 5}, parser.payloads[0])
+}
+
+func TestRun_RejectedTenderMarkedSynced(t *testing.T) {
+	parser := &fakeParser{status: http.StatusNotFound, body: `{"detail":"not found"}`}
+	service, mockStore, _ := setupSyncService(t, parser)
+
+	expectTendersForSync(mockStore)
+	mockStore.EXPECT().MarkTenderSynced(gomock.Any(), int64(7)).Return(nil)
+
+	result, err := service.Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "запрошено тендеров: 0, отклонено парсером: 1", result)
+}
+
+func TestRun_ParserUnavailableStops(t *testing.T) {
+	parser := &fakeParser{status: http.StatusInternalServerError, body: `oops`}
+	service, mockStore, _ := setupSyncService(t, parser)
+
+	expectTendersForSync(mockStore)
+
+	_, err := service.Run(context.Background())
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrParserUnavailable))
+}
+
+// rawVersion возвращает снимок исходного JSON тендера с одним предложением
+// (и вторым, если second) и ценой позиции total.
+func rawVersion(t *testing.T, version int32, total string, second bool) db.TenderRawDataHistory {
+	t.Helper()
+	proposal := func(title string) api_models.ContractorProposalDetails {
+		return api_models.ContractorProposalDetails{Title: title, ContractorItems: api_models.ContractorItemsContainer{
+			Positions: map[string]api_models.PositionItem{"1": {JobTitle: "Кладка", TotalCost: api_models.Cost{Total: api_models.MoneyPtr(decimal.RequireFromString(total))}}},
+		}}
+	}
+	lot := api_models.Lot{LotTitle: "Лот 1", ProposalData: map[string]api_models.ContractorProposalDetails{"ООО Альфа": proposal("ООО Альфа")}}
+	if second {
+		lot.ProposalData["ООО Бета"] = proposal("ООО Бета")
+	}
+	raw, err := json.Marshal(api_models.FullTenderData{TenderID: "ETP-7", LotsData: map[string]api_models.Lot{"lot_1": lot}})
+	require.NoError(t, err)
+	return db.TenderRawDataHistory{TenderID: 7, Version: version, RawData: raw}
+}
+
+func syncTask(status string) api_models.ParseTask {
+	finishedAt := time.Now()
+	return api_models.ParseTask{TaskID: "s-1", Status: status, Source: SourceSync, EtpID: "ETP-7", FinishedAt: &finishedAt}
+}
+
+func TestComplete_Changed(t *testing.T) {
+	service, mockStore, publisher := setupSyncService(t, &fakeParser{status: http.StatusOK})
+
+	mockStore.EXPECT().GetTenderSyncByTaskID(gomock.Any(), "s-1").
+		Return(db.TenderSync{ID: 3, TenderID: 7, TaskID: "s-1", Status: SyncPending, FromVersion: sql.NullInt32{Int32: 2, Valid: true}}, nil)
+	mockStore.EXPECT().ListTenderRawDataVersions(gomock.Any(), int64(7)).
+		Return([]db.ListTenderRawDataVersionsRow{{TenderID: 7, Version: 3}, {TenderID: 7, Version: 2}}, nil)
+	mockStore.EXPECT().GetTenderRawDataVersion(gomock.Any(), db.GetTenderRawDataVersionParams{TenderID: 7, Version: 2}).
+		Return(rawVersion(t, 2, "1000", false), nil)
+	mockStore.EXPECT().GetTenderRawDataVersion(gomock.Any(), db.GetTenderRawDataVersionParams{TenderID: 7, Version: 3}).
+		Return(rawVersion(t, 3, "900", true), nil)
+	mockStore.EXPECT().FinishTenderSync(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.FinishTenderSyncParams) (db.TenderSync, error) {
+			assert.Equal(t, SyncChanged, arg.Status)
+			assert.Equal(t, sql.NullInt32{Int32: 3, Valid: true}, arg.ToVersion)
+			assert.Equal(t, int32(1), arg.ProposalsAdded)
+			assert.Equal(t, int32(1), arg.PricesChanged)
+			require.True(t, arg.Stats.Valid)
+			var stats api_models.TenderImportDiffStats
+			require.NoError(t, json.Unmarshal(arg.Stats.RawMessage, &stats))
+			assert.Equal(t, 1, stats.PositionsChanged)
+			return db.TenderSync{ID: 3, TenderID: 7, Status: arg.Status, FromVersion: sql.NullInt32{Int32: 2, Valid: true},
+				ToVersion: arg.ToVersion, ProposalsAdded: arg.ProposalsAdded, PricesChanged: arg.PricesChanged}, nil
+		})
+
+	err := service.Complete(context.Background(), syncTask("completed"))
+
+	require.NoError(t, err)
+	require.Len(t, publisher.events, 1)
+	assert.Equal(t, events.TypeTenderSynced, publisher.events[0].Type)
+	assert.Equal(t, events.TenderSyncedData{
+		TenderDBID: 7, TenderID: "ETP-7", SyncID: 3, FromVersion: 2, ToVersion: 3, ProposalsAdded: 1, PricesChanged: 1,
+	}, publisher.events[0].Data)
+}
+
+func TestComplete_NoNewVersion(t *testing.T) {
+	service, mockStore, publisher := setupSyncService(t, &fakeParser{status: http.StatusOK})
+
+	mockStore.EXPECT().GetTenderSyncByTaskID(gomock.Any(), "s-1").
+		Return(db.TenderSync{ID: 3, TenderID: 7, Status: SyncPending, FromVersion: sql.NullInt32{Int32: 2, Valid: true}}, nil)
+	mockStore.EXPECT().ListTenderRawDataVersions(gomock.Any(), int64(7)).
+		Return([]db.ListTenderRawDataVersionsRow{{TenderID: 7, Version: 2}}, nil)
+	mockStore.EXPECT().FinishTenderSync(gomock.Any(), db.FinishTenderSyncParams{TaskID: "s-1", Status: SyncUnchanged}).
+		Return(db.TenderSync{ID: 3, TenderID: 7, Status: SyncUnchanged}, nil)
+
+	err := service.Complete(context.Background(), syncTask("completed"))
+
+	require.NoError(t, err)
+	assert.Empty(t, publisher.events)
+}
+
+func TestComplete_Failed(t *testing.T) {
+	service, mockStore, publisher := setupSyncService(t, &fakeParser{status: http.StatusOK})
+	task := syncTask("failed")
+	task.Error = "ЭТП недоступна"
+
+	mockStore.EXPECT().GetTenderSyncByTaskID(gomock.Any(), "s-1").
+		Return(db.TenderSync{ID: 3, TenderID: 7, Status: SyncPending}, nil)
+	mockStore.EXPECT().FinishTenderSync(gomock.Any(), db.FinishTenderSyncParams{
+		TaskID: "s-1", Status: SyncFailed, ErrorMessage: sql.NullString{String: "ЭТП недоступна", Valid: true},
+	}).Return(db.TenderSync{ID: 3, TenderID: 7, Status: SyncFailed}, nil)
+
+	err := service.Complete(context.Background(), task)
+
+	require.NoError(t, err)
+	assert.Empty(t, publisher.events)
+}
+
+func TestComplete_IgnoresOtherTasks(t *testing.T) {
+	service, _, publisher := setupSyncService(t, &fakeParser{status: http.StatusOK})
+
+	upload := syncTask("completed")
+	upload.Source = "upload"
+	require.NoError(t, service.Complete(context.Background(), upload))
+
+	running := syncTask("processing")
+	running.FinishedAt = nil
+	require.NoError(t, service.Complete(context.Background(), running))
+
+	assert.Empty(t, publisher.events)
+}
@@ -0,0 +1,284 @@
+// Package events публикует доменные события во внешнюю шину, чтобы
+// потребители (например, аналитика) получали изменения без опроса REST API.
+//
+// Сервисы вызывают Publisher.Publish после фиксации изменения. Реализация
+// выбирается настройкой events.driver:
+//
+//   - none  — Noop, события не публикуются (по умолчанию);
+//   - nats  — core NATS (клиент nats.go), subject <events.nats.subject_prefix>.<type>;
+//   - kafka — топик events.kafka.topic только через Kafka REST Proxy, ключ
+//     записи — Event.Key. Нативный протокол Kafka не поддерживается.
+//
+// Кроме шины события получают и внутренние потребители (лента уведомлений):
+// cmd/main объединяет их с шиной через Fanout.
+//
+// Отправка асинхронная: Publish только кладёт событие в буфер в памяти, поэтому
+// недоступность брокера не замедляет запросы. При переполнении буфера или ошибке
+// брокера событие теряется (at-most-once) — шина не заменяет REST API как
+// источник истины, а потребитель должен уметь дочитать пропущенное.
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// Типы событий.
+const (
+	TypeTenderImported  = "tender.imported"
+	TypeLotUpdated      = "lot.updated"
+	TypePositionMatched = "position.matched"
+	TypeWinnerCreated   = "winner.created"
+	TypeWinnerUpdated   = "winner.updated"
+	TypeWinnerDeleted   = "winner.deleted"
+	TypeLotAIAnalyzed   = "lot.ai_analyzed"
+	TypeMergeSuggested  = "merge.suggested"
+	TypeTenderSynced    = "tender.synced"
+
+	TypeContractorAccreditationExpiring = "contractor.accreditation_expiring"
+	TypeContractorAccreditationExpired  = "contractor.accreditation_expired"
+)
+
+// TenderImportedData — data события tender.imported (Key — ID тендера в БД).
+type TenderImportedData struct {
+	TenderDBID int64            `json:"tender_db_id"`
+	TenderID   string           `json:"tender_id"` // ID тендера на ЭТП
+	LotIDsMap  map[string]int64 `json:"lot_ids_map"`
+	// Предложения подрядчиков, которых не было до этого импорта (baseline не учитывается)
+	NewProposals int `json:"new_proposals"`
+}
+
+// TenderSyncedData — data события tender.synced (Key — ID тендера в БД):
+// обновление тендера с ЭТП по расписанию завершилось и данные изменились.
+type TenderSyncedData struct {
+	TenderDBID     int64  `json:"tender_db_id"`
+	TenderID       string `json:"tender_id"` // ID тендера на ЭТП
+	SyncID         int64  `json:"sync_id"`
+	FromVersion    int32  `json:"from_version"`
+	ToVersion      int32  `json:"to_version"`
+	ProposalsAdded int    `json:"proposals_added"`
+	PricesChanged  int    `json:"prices_changed"` // Позиций с изменившейся стоимостью
+}
+
+// ContractorAccreditationData — data событий contractor.accreditation_expiring
+// (срок наступит в пределах contractors.accreditation_warn_days) и
+// contractor.accreditation_expired (срок прошёл, статус стал expired).
+// Key — ID подрядчика.
+type ContractorAccreditationData struct {
+	ContractorID int64  `json:"contractor_id"`
+	Title        string `json:"title"`
+	Inn          string `json:"inn"`
+	ValidUntil   string `json:"valid_until"` // Последний день действия, "2026-03-31"
+	DaysLeft     int    `json:"days_left"`   // У истекшей — меньше 0
+}
+
+// LotUpdatedData — data события lot.updated (Key — ID лота).
+type LotUpdatedData struct {
+	LotID            int64                  `json:"lot_id"`
+	LotKeyParameters map[string]interface{} `json:"lot_key_parameters"`
+}
+
+// LotAIAnalyzedData — data события lot.ai_analyzed (Key — ID лота): воркер
+// прислал результаты AI-анализа лота.
+type LotAIAnalyzedData struct {
+	LotID              int64  `json:"lot_id"`
+	TenderID           int64  `json:"tender_id"` // ID тендера в БД
+	AIAnalysisResultID int64  `json:"ai_analysis_result_id"`
+	ModelName          string `json:"model_name,omitempty"`
+}
+
+// MergeSuggestedData — data события merge.suggested (Key — ID основной позиции
+// каталога): в очередь слияний добавлена или обновлена заявка.
+type MergeSuggestedData struct {
+	MainPositionID      int64   `json:"main_position_id"`
+	DuplicatePositionID int64   `json:"duplicate_position_id"`
+	SimilarityScore     float64 `json:"similarity_score"`
+}
+
+// PositionMatchedData — data события position.matched (Key — ID позиции).
+type PositionMatchedData struct {
+	PositionItemID    int64  `json:"position_item_id"`
+	CatalogPositionID int64  `json:"catalog_position_id"`
+	Hash              string `json:"hash"`
+}
+
+// WinnerCreatedData — data события winner.created (Key — ID лота).
+type WinnerCreatedData struct {
+	WinnerID   int64   `json:"winner_id"`
+	LotID      int64   `json:"lot_id"`
+	ProposalID int64   `json:"proposal_id"`
+	Rank       int32   `json:"rank"`
+	AwardPrice *string `json:"award_price,omitempty"`
+}
+
+// WinnerChangedData — data событий winner.updated и winner.deleted (Key — ID лота).
+type WinnerChangedData struct {
+	WinnerID   int64 `json:"winner_id"`
+	LotID      int64 `json:"lot_id"`
+	ProposalID int64 `json:"proposal_id"`
+}
+
+// Event — конверт доменного события.
+type Event struct {
+	ID         string    `json:"id"` // Случайный id для дедупликации на стороне потребителя
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Key        string    `json:"key"` // ID сущности; в Kafka — ключ записи (порядок событий одной сущности)
+	Data       any       `json:"data"`
+}
+
+// NewEvent создаёт событие с новым id и текущим временем.
+func NewEvent(eventType string, key int64, data any) Event {
+	raw := make([]byte, 16)
+	// crypto/rand.Read не возвращает ошибку на поддерживаемых платформах
+	_, _ = rand.Read(raw)
+	return Event{
+		ID:         hex.EncodeToString(raw),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Key:        fmt.Sprintf("%d", key),
+		Data:       data,
+	}
+}
+
+// Publisher публикует события. Реализации безопасны для параллельного использования.
+type Publisher interface {
+	// Publish ставит событие в очередь отправки. Ошибка означает, что событие не будет отправлено.
+	Publish(ctx context.Context, event Event) error
+	// Close отправляет оставшиеся в буфере события (до истечения ctx) и освобождает соединение.
+	Close(ctx context.Context) error
+}
+
+// Noop — Publisher для events.driver: none.
+type Noop struct{}
+
+func (Noop) Publish(context.Context, Event) error { return nil }
+func (Noop) Close(context.Context) error          { return nil }
+
+// Emit публикует событие после фиксации изменения. Ошибка публикации только
+// логируется: изменение уже сохранено и не должно откатываться из-за шины.
+func Emit(ctx context.Context, publisher Publisher, logger logging.Logger, eventType string, key int64, data any) {
+	if err := publisher.Publish(ctx, NewEvent(eventType, key, data)); err != nil {
+		logger.Warnf("Событие %s (key=%d) не опубликовано: %v", eventType, key, err)
+	}
+}
+
+// Fanout передаёт каждое событие всем publishers по очереди. Ошибка одного
+// не мешает остальным; Publish и Close возвращают объединённые ошибки.
+func Fanout(publishers ...Publisher) Publisher {
+	return fanout(publishers)
+}
+
+type fanout []Publisher
+
+func (f fanout) Publish(ctx context.Context, event Event) error {
+	var errs []error
+	for _, p := range f {
+		if err := p.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f fanout) Close(ctx context.Context) error {
+	var errs []error
+	for _, p := range f {
+		if err := p.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ErrBufferFull возвращается, когда брокер не успевает принимать события.
+var ErrBufferFull = errors.New("буфер событий переполнен")
+
+// sender синхронно отправляет одно событие брокеру.
+type sender interface {
+	send(ctx context.Context, event Event) error
+	close() error
+}
+
+// New создаёт Publisher по настройкам events.
+func New(cfg config.EventsConfig, logger logging.Logger) (Publisher, error) {
+	var s sender
+	switch cfg.Driver {
+	case "none", "":
+		return Noop{}, nil
+	case "nats":
+		n, err := newNATSSender(cfg.NATS, cfg.PublishTimeout, logger.WithField("events_driver", cfg.Driver))
+		if err != nil {
+			return nil, err
+		}
+		s = n
+	case "kafka":
+		s = newKafkaRESTSender(cfg.Kafka, cfg.PublishTimeout)
+	default:
+		return nil, fmt.Errorf("неизвестный драйвер событий %q", cfg.Driver)
+	}
+
+	logger.Infof("Публикация событий включена: драйвер %s", cfg.Driver)
+	return newAsyncPublisher(s, logger.WithField("events_driver", cfg.Driver), cfg.BufferSize, cfg.PublishTimeout), nil
+}
+
+// asyncPublisher отправляет события из буфера в фоновой горутине.
+type asyncPublisher struct {
+	sender  sender
+	logger  logging.Logger
+	timeout time.Duration
+
+	queue chan Event
+	done  chan struct{}
+}
+
+func newAsyncPublisher(s sender, logger logging.Logger, bufferSize int, timeout time.Duration) *asyncPublisher {
+	p := &asyncPublisher{
+		sender:  s,
+		logger:  logger,
+		timeout: timeout,
+		queue:   make(chan Event, bufferSize),
+		done:    make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+func (p *asyncPublisher) Publish(_ context.Context, event Event) error {
+	select {
+	case p.queue <- event:
+		return nil
+	default:
+		return ErrBufferFull
+	}
+}
+
+func (p *asyncPublisher) run() {
+	defer close(p.done)
+	for event := range p.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+		if err := p.sender.send(ctx, event); err != nil {
+			p.logger.Warnf("Событие %s (key=%s) не опубликовано: %v", event.Type, event.Key, err)
+		}
+		cancel()
+	}
+}
+
+// Close перестаёт принимать события и ждёт отправки буфера. Publish после Close
+// приводит к панике, поэтому вызывается только при остановке сервера.
+func (p *asyncPublisher) Close(ctx context.Context) error {
+	close(p.queue)
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		p.logger.Warnf("Остановка публикации событий: не отправлено %d событий", len(p.queue))
+	}
+	return p.sender.close()
+}
@@ -0,0 +1,180 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR EVENT PUBLISHING (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Slow or unavailable broker — requests must not wait for the bus
+2. Silent loss on shutdown — buffered events are flushed by Close
+3. Wrong routing — NATS subject and Kafka topic/key follow the documented layout
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: New
+- GIVEN driver none
+  THEN Noop publisher, nothing is sent
+
+SCENARIO 2: async publisher
+- GIVEN a blocked broker and a full buffer
+  WHEN Publish is called
+  THEN ErrBufferFull immediately
+- GIVEN a broker error
+  THEN the event is dropped with a warning, the next one is still sent
+- GIVEN buffered events
+  WHEN Close is called
+  THEN all of them are sent before Close returns
+
+SCENARIO 2a: Fanout
+- GIVEN two publishers, the first failing
+  WHEN Publish is called
+  THEN the second still gets the event and the error is returned
+
+SCENARIO 3: NATS (nats_test.go)
+- GIVEN a server with a token
+  THEN CONNECT carries auth_token and PUB goes to <prefix>.<type>
+- GIVEN -ERR after PUB → error, the next event is not affected
+- GIVEN the server dropped the connection → the unconfirmed event fails,
+  the client reconnects and the next event goes to the new connection
+
+SCENARIO 4: Kafka REST Proxy (kafka_test.go)
+- GIVEN a 200 response → record with key=Event.Key
+- GIVEN a non-2xx status or an error in offsets → error
+*/
+
+// recordingSender запоминает события; block задерживает отправку до закрытия канала.
+type recordingSender struct {
+	mu     sync.Mutex
+	events []Event
+	fail   map[string]error
+	block  chan struct{}
+	closed bool
+}
+
+func (r *recordingSender) send(_ context.Context, event Event) error {
+	if r.block != nil {
+		<-r.block
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.fail[event.Key]; err != nil {
+		return err
+	}
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *recordingSender) close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	return nil
+}
+
+func (r *recordingSender) sent() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event(nil), r.events...)
+}
+
+func TestNew_NoneDriverIsNoop(t *testing.T) {
+	publisher, err := New(config.EventsConfig{Driver: "none"}, testutil.NewMockLogger())
+
+	require.NoError(t, err)
+	assert.IsType(t, Noop{}, publisher)
+	assert.NoError(t, publisher.Publish(context.Background(), NewEvent(TypeLotUpdated, 1, nil)))
+}
+
+func TestNewEvent(t *testing.T) {
+	a := NewEvent(TypeTenderImported, 42, map[string]int{"lots": 2})
+	b := NewEvent(TypeTenderImported, 42, nil)
+
+	assert.Equal(t, "42", a.Key)
+	assert.Len(t, a.ID, 32)
+	assert.NotEqual(t, a.ID, b.ID)
+	assert.WithinDuration(t, time.Now(), a.OccurredAt, time.Minute)
+}
+
+// failingPublisher всегда возвращает err и считает вызовы.
+type failingPublisher struct {
+	err    error
+	calls  int
+	closed bool
+}
+
+func (p *failingPublisher) Publish(context.Context, Event) error {
+	p.calls++
+	return p.err
+}
+
+func (p *failingPublisher) Close(context.Context) error {
+	p.closed = true
+	return p.err
+}
+
+func TestFanout_ErrorDoesNotStopOtherPublishers(t *testing.T) {
+	broken := &failingPublisher{err: errors.New("broker down")}
+	healthy := &failingPublisher{}
+	publisher := Fanout(broken, healthy)
+
+	err := publisher.Publish(context.Background(), NewEvent(TypeLotUpdated, 1, nil))
+
+	assert.ErrorContains(t, err, "broker down")
+	assert.Equal(t, 1, healthy.calls)
+	assert.ErrorContains(t, publisher.Close(context.Background()), "broker down")
+	assert.True(t, healthy.closed)
+}
+
+func TestAsyncPublisher_BufferFull(t *testing.T) {
+	sender := &recordingSender{block: make(chan struct{})}
+	publisher := newAsyncPublisher(sender, testutil.NewMockLogger(), 1, time.Second)
+
+	// Первое событие забирает фоновая горутина, второе занимает буфер
+	require.NoError(t, publisher.Publish(context.Background(), NewEvent(TypeLotUpdated, 1, nil)))
+	require.Eventually(t, func() bool { return len(publisher.queue) == 0 }, time.Second, time.Millisecond)
+	require.NoError(t, publisher.Publish(context.Background(), NewEvent(TypeLotUpdated, 2, nil)))
+
+	err := publisher.Publish(context.Background(), NewEvent(TypeLotUpdated, 3, nil))
+
+	assert.ErrorIs(t, err, ErrBufferFull)
+	close(sender.block)
+	require.NoError(t, publisher.Close(context.Background()))
+	assert.Len(t, sender.sent(), 2)
+}
+
+func TestAsyncPublisher_ErrorIsLoggedAndCloseFlushes(t *testing.T) {
+	sender := &recordingSender{fail: map[string]error{"1": errors.New("broker down")}}
+	logger := testutil.NewMockLogger()
+	publisher := newAsyncPublisher(sender, logger, 10, time.Second)
+
+	for id := int64(1); id <= 3; id++ {
+		require.NoError(t, publisher.Publish(context.Background(), NewEvent(TypeLotUpdated, id, nil)))
+	}
+	require.NoError(t, publisher.Close(context.Background()))
+
+	sent := sender.sent()
+	require.Len(t, sent, 2)
+	assert.Equal(t, "2", sent[0].Key)
+	assert.Equal(t, "3", sent[1].Key)
+	assert.True(t, sender.closed)
+
+	records := logger.Records()
+	require.Len(t, records, 1)
+	assert.Equal(t, testutil.LevelWarn, records[0].Level)
+	assert.Contains(t, records[0].Message, "broker down")
+}
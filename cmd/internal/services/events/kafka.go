@@ -0,0 +1,91 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+)
+
+const kafkaRESTContentType = "application/vnd.kafka.json.v2+json"
+
+// kafkaRESTSender публикует события в топик через Kafka REST Proxy (API v2):
+// POST /topics/{topic} с записью {"key": Event.Key, "value": Event}. Ключ
+// направляет события одной сущности в одну партицию и сохраняет их порядок.
+type kafkaRESTSender struct {
+	endpoint string
+	client   *http.Client
+}
+
+type kafkaRecord struct {
+	Key   string `json:"key"`
+	Value Event  `json:"value"`
+}
+
+type kafkaProduceRequest struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+// kafkaProduceResponse — ответ REST Proxy; ошибки отдельных записей
+// возвращаются в offsets при статусе 200.
+type kafkaProduceResponse struct {
+	Offsets []struct {
+		ErrorCode *int   `json:"error_code"`
+		Error     string `json:"error"`
+	} `json:"offsets"`
+}
+
+func newKafkaRESTSender(cfg config.EventsKafkaConfig, timeout time.Duration) *kafkaRESTSender {
+	return &kafkaRESTSender{
+		endpoint: strings.TrimRight(cfg.RESTProxyURL, "/") + "/topics/" + url.PathEscape(cfg.Topic),
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+func (k *kafkaRESTSender) send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(kafkaProduceRequest{Records: []kafkaRecord{{Key: event.Key, Value: event}}})
+	if err != nil {
+		return fmt.Errorf("сериализация события: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, k.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", kafkaRESTContentType)
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("запрос к Kafka REST Proxy: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Kafka REST Proxy ответил %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	var produced kafkaProduceResponse
+	if err := json.Unmarshal(respBody, &produced); err != nil {
+		return fmt.Errorf("некорректный ответ Kafka REST Proxy: %w", err)
+	}
+	for _, offset := range produced.Offsets {
+		if offset.ErrorCode != nil || offset.Error != "" {
+			return fmt.Errorf("Kafka отклонила запись: %s", offset.Error)
+		}
+	}
+	return nil
+}
+
+func (k *kafkaRESTSender) close() error {
+	k.client.CloseIdleConnections()
+	return nil
+}
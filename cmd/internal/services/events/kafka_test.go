@@ -0,0 +1,70 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+)
+
+func newTestKafkaSender(url string) *kafkaRESTSender {
+	return newKafkaRESTSender(config.EventsKafkaConfig{RESTProxyURL: url + "/", Topic: "tenders.events"}, time.Second)
+}
+
+func TestKafkaRESTSender_ProducesKeyedRecord(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/topics/tenders.events", r.URL.Path)
+		assert.Equal(t, kafkaRESTContentType, r.Header.Get("Content-Type"))
+
+		var body struct {
+			Records []struct {
+				Key   string `json:"key"`
+				Value struct {
+					Type string `json:"type"`
+				} `json:"value"`
+			} `json:"records"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Len(t, body.Records, 1)
+		assert.Equal(t, "42", body.Records[0].Key)
+		assert.Equal(t, "tender.imported", body.Records[0].Value.Type)
+
+		w.Header().Set("Content-Type", "application/vnd.kafka.v2+json")
+		_, _ = w.Write([]byte(`{"offsets":[{"partition":0,"offset":17,"error_code":null,"error":null}]}`))
+	}))
+	defer proxy.Close()
+
+	err := newTestKafkaSender(proxy.URL).send(context.Background(), NewEvent(TypeTenderImported, 42, nil))
+
+	assert.NoError(t, err)
+}
+
+func TestKafkaRESTSender_Errors(t *testing.T) {
+	cases := map[string]http.HandlerFunc{
+		"http status": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error_code":40401,"message":"Topic not found."}`))
+		},
+		"record error": func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"offsets":[{"partition":null,"offset":null,"error_code":50002,"error":"Kafka error"}]}`))
+		},
+	}
+	for name, handler := range cases {
+		t.Run(name, func(t *testing.T) {
+			proxy := httptest.NewServer(handler)
+			defer proxy.Close()
+
+			err := newTestKafkaSender(proxy.URL).send(context.Background(), NewEvent(TypeLotUpdated, 1, nil))
+
+			assert.Error(t, err)
+		})
+	}
+}
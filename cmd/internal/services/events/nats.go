@@ -0,0 +1,89 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// natsSender публикует события в core NATS через клиент nats.go. Клиент сам
+// переподключается после обрыва; сообщения, опубликованные во время
+// переподключения, копятся в его буфере и уходят после восстановления связи.
+// Учётные данные (token@ или user:pass@) и TLS (tls://) берутся из URL.
+type natsSender struct {
+	conn    *nats.Conn
+	prefix  string
+	timeout time.Duration
+
+	mu sync.Mutex // Сериализует отправку: ошибка сервера сверяется с LastError соединения
+}
+
+func newNATSSender(cfg config.EventsNATSConfig, timeout time.Duration, logger logging.Logger) (*natsSender, error) {
+	conn, err := nats.Connect(cfg.URL,
+		nats.Name("tenders-go"),
+		nats.Timeout(timeout),
+		// Недоступный при старте брокер не мешает запуску сервера:
+		// подключение продолжается в фоне, как и после обрыва
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				logger.Warnf("Соединение с NATS потеряно: %v", err)
+			}
+		}),
+		// Без обработчика nats.go печатает асинхронные ошибки в stderr
+		nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, err error) {
+			logger.Warnf("Ошибка NATS: %v", err)
+		}),
+		nats.ReconnectHandler(func(c *nats.Conn) {
+			logger.Infof("Соединение с NATS восстановлено: %s", c.ConnectedUrlRedacted())
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("подключение к NATS: %w", err)
+	}
+	return &natsSender{conn: conn, prefix: cfg.SubjectPrefix, timeout: timeout}, nil
+}
+
+// send публикует событие и ждёт PONG на следующий за ним PING: ответ означает,
+// что сервер обработал сообщение. Отказ сервера (-ERR, например нарушение прав)
+// приходит до PONG и фиксируется в LastError соединения.
+func (n *natsSender) send(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("сериализация события: %w", err)
+	}
+	subject := n.prefix + "." + event.Type
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	before := n.conn.LastError()
+	if err := n.conn.Publish(subject, payload); err != nil {
+		return fmt.Errorf("отправка в NATS: %w", err)
+	}
+	// FlushWithContext требует контекст с дедлайном
+	ctx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+	if err := n.conn.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("ожидание ответа NATS: %w", err)
+	}
+	if err := n.conn.LastError(); err != nil && err != before {
+		return fmt.Errorf("NATS отклонил сообщение: %w", err)
+	}
+	return nil
+}
+
+// close закрывает соединение. Отправленные события к этому моменту уже
+// подтверждены сервером, поэтому Drain не нужен.
+func (n *natsSender) close() error {
+	n.conn.Close()
+	return nil
+}
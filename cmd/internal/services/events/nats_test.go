@@ -0,0 +1,170 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+// natsMessage — принятый фейковым сервером PUB.
+type natsMessage struct {
+	connect string
+	subject string
+	payload []byte
+}
+
+// fakeNATS поднимает минимальный сервер NATS. handle вызывается на каждый PUB
+// и возвращает строку, которую сервер отправит перед PONG на следующий PING
+// ("" — только PONG, "close" — закрыть соединение без ответа).
+func fakeNATS(t *testing.T, handle func(conn int, msg natsMessage) string) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for connNum := 1; ; connNum++ {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveNATS(conn, connNum, handle)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func serveNATS(conn net.Conn, connNum int, handle func(int, natsMessage) string) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	_, _ = io.WriteString(conn, `INFO {"server_id":"fake","max_payload":1048576}`+"\r\n")
+
+	var connect string
+	var pending *natsMessage
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case strings.HasPrefix(line, "CONNECT "):
+			connect = strings.TrimPrefix(line, "CONNECT ")
+		case strings.HasPrefix(line, "PUB "):
+			var subject string
+			var size int
+			if _, err := fmt.Sscanf(line, "PUB %s %d", &subject, &size); err != nil {
+				return
+			}
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(reader, payload); err != nil {
+				return
+			}
+			pending = &natsMessage{connect: connect, subject: subject, payload: payload[:size]}
+		case line == "PING":
+			reply := ""
+			if pending != nil {
+				reply = handle(connNum, *pending)
+				pending = nil
+			}
+			if reply == "close" {
+				return
+			}
+			if reply != "" {
+				_, _ = io.WriteString(conn, reply+"\r\n")
+			}
+			_, _ = io.WriteString(conn, "PONG\r\n")
+		}
+	}
+}
+
+func newTestNATSSender(t *testing.T, url string) *natsSender {
+	t.Helper()
+	sender, err := newNATSSender(config.EventsNATSConfig{URL: url, SubjectPrefix: "tenders"}, time.Second, testutil.NewMockLogger())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sender.close() })
+	return sender
+}
+
+func TestNATSSender_PublishesToSubject(t *testing.T) {
+	received := make(chan natsMessage, 1)
+	addr := fakeNATS(t, func(_ int, msg natsMessage) string {
+		received <- msg
+		return ""
+	})
+	sender := newTestNATSSender(t, "nats://s3cret@"+addr)
+
+	err := sender.send(context.Background(), NewEvent(TypeWinnerCreated, 7, map[string]int64{"lot_id": 3}))
+
+	require.NoError(t, err)
+	msg := <-received
+	assert.Equal(t, "tenders.winner.created", msg.subject)
+
+	var connect map[string]any
+	require.NoError(t, json.Unmarshal([]byte(msg.connect), &connect))
+	assert.Equal(t, "s3cret", connect["auth_token"])
+	assert.NotContains(t, connect, "user")
+	assert.Equal(t, "tenders-go", connect["name"])
+
+	var event struct {
+		Type string           `json:"type"`
+		Key  string           `json:"key"`
+		Data map[string]int64 `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(msg.payload, &event))
+	assert.Equal(t, "winner.created", event.Type)
+	assert.Equal(t, "7", event.Key)
+	assert.Equal(t, int64(3), event.Data["lot_id"])
+}
+
+func TestNATSSender_ServerError(t *testing.T) {
+	addr := fakeNATS(t, func(_ int, msg natsMessage) string {
+		if msg.subject == "tenders.lot.updated" {
+			return "-ERR 'Permissions Violation for Publish to tenders.lot.updated'"
+		}
+		return ""
+	})
+	sender := newTestNATSSender(t, "nats://"+addr)
+
+	err := sender.send(context.Background(), NewEvent(TypeLotUpdated, 1, nil))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Permissions Violation")
+
+	// Ошибка прошлого сообщения не приписывается следующему
+	require.NoError(t, sender.send(context.Background(), NewEvent(TypeWinnerCreated, 2, nil)))
+}
+
+func TestNATSSender_ReconnectsAfterDroppedConnection(t *testing.T) {
+	conns := make(chan int, 3)
+	addr := fakeNATS(t, func(connNum int, msg natsMessage) string {
+		conns <- connNum
+		// Первое соединение обрывается на втором сообщении
+		if connNum == 1 && len(conns) == 2 {
+			return "close"
+		}
+		return ""
+	})
+	sender := newTestNATSSender(t, "nats://"+addr)
+
+	require.NoError(t, sender.send(context.Background(), NewEvent(TypeLotUpdated, 1, nil)))
+	// Сообщение в оборванном соединении не подтверждено — ошибка, событие теряется
+	require.Error(t, sender.send(context.Background(), NewEvent(TypeLotUpdated, 2, nil)))
+
+	require.Eventually(t, sender.conn.IsConnected, 5*time.Second, 50*time.Millisecond)
+	require.NoError(t, sender.send(context.Background(), NewEvent(TypeLotUpdated, 3, nil)))
+
+	assert.Equal(t, []int{1, 1, 2}, []int{<-conns, <-conns, <-conns})
+}
@@ -0,0 +1,385 @@
+// Package feed ведёт ленту уведомлений пользователей в веб-интерфейсе.
+//
+// Уведомления создаются по доменным событиям: FeedService реализует
+// events.Publisher и подключается в cmd/main рядом с шиной событий через
+// events.Fanout, поэтому импорт по HTTP и по gRPC наполняет ленту одинаково.
+// Сейчас в ленту попадают:
+//
+//   - tender.imported (новые предложения или повторный импорт без них),
+//     tender.synced (тендер обновлён с ЭТП и изменился), lot.ai_analyzed,
+//     winner.created/updated/deleted — пользователям, которые следят за
+//     тендером (Follow);
+//   - merge.suggested — пользователям с правом catalog:manage, не чаще одного
+//     непрочитанного напоминания на пользователя;
+//   - contractor.accreditation_expiring/expired — пользователям с правом
+//     winners:manage (они выбирают подрядчиков), тоже одно непрочитанное
+//     напоминание: список подрядчиков отдаёт
+//     GET /api/v1/contractors/expiring-accreditation.
+//
+// Publish синхронный: запись идёт в запросе, вызвавшем событие, а ошибка
+// только логируется events.Emit и не откатывает само изменение.
+package feed
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/auth"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// Виды уведомлений (chk_notifications_kind, миграции 000032, 000033, 000041 и 000054).
+const (
+	KindTenderNewProposals     = "tender_new_proposals"
+	KindTenderReimported       = "tender_reimported"
+	KindTenderWinnerChanged    = "tender_winner_changed"
+	KindLotAIAnalysisCompleted = "lot_ai_analysis_completed"
+	KindMergeReviewPending     = "merge_review_pending"
+	KindTenderSynced           = "tender_synced"
+
+	KindContractorAccreditationExpiring = "contractor_accreditation_expiring"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// FeedService создаёт уведомления по событиям, отдаёт ленту пользователю и
+// ведёт его подписки на тендеры.
+type FeedService struct {
+	store  db.Store
+	logger logging.Logger
+}
+
+// NewFeedService создаёт сервис ленты уведомлений.
+func NewFeedService(store db.Store, logger logging.Logger) *FeedService {
+	return &FeedService{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Publish создаёт уведомления по событию. События, которые не попадают в
+// ленту, пропускаются без ошибки.
+func (s *FeedService) Publish(ctx context.Context, event events.Event) error {
+	switch data := event.Data.(type) {
+	case events.TenderImportedData:
+		return s.onTenderImported(ctx, data)
+	case events.TenderSyncedData:
+		return s.onTenderSynced(ctx, data)
+	case events.LotAIAnalyzedData:
+		return s.onLotAIAnalyzed(ctx, data)
+	case events.MergeSuggestedData:
+		return s.onMergeSuggested(ctx, data)
+	case events.ContractorAccreditationData:
+		return s.onAccreditationExpiring(ctx, event.Type, data)
+	case events.WinnerCreatedData:
+		return s.onWinnerChanged(ctx, event.Type, data.WinnerID, data.LotID, data.ProposalID)
+	case events.WinnerChangedData:
+		return s.onWinnerChanged(ctx, event.Type, data.WinnerID, data.LotID, data.ProposalID)
+	}
+	return nil
+}
+
+// Close ничего не делает: уведомления пишутся синхронно в Publish.
+func (s *FeedService) Close(context.Context) error { return nil }
+
+// onTenderImported сообщает подписчикам об импорте тендера: о новых
+// предложениях или, если их нет, о повторной загрузке. У только что созданного
+// тендера подписчиков нет, и уведомления не создаются.
+func (s *FeedService) onTenderImported(ctx context.Context, data events.TenderImportedData) error {
+	tender, err := s.store.GetTenderByID(ctx, data.TenderDBID)
+	if err != nil {
+		return fmt.Errorf("ошибка GetTenderByID(%d): %w", data.TenderDBID, err)
+	}
+
+	params := db.CreateTenderNotificationsParams{
+		Kind:     KindTenderReimported,
+		Title:    fmt.Sprintf("Тендер %s загружен повторно", tender.Title),
+		Body:     "Новых предложений подрядчиков нет",
+		TenderID: data.TenderDBID,
+	}
+	if data.NewProposals > 0 {
+		params.Kind = KindTenderNewProposals
+		params.Title = fmt.Sprintf("Новые предложения в тендере %s", tender.Title)
+		params.Body = fmt.Sprintf("Добавлено предложений подрядчиков: %d", data.NewProposals)
+	}
+	params.Payload, err = json.Marshal(map[string]any{"new_proposals": data.NewProposals})
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации данных уведомления: %w", err)
+	}
+	created, err := s.store.CreateTenderNotifications(ctx, params)
+	if err != nil {
+		return fmt.Errorf("ошибка CreateTenderNotifications(%s, tender_id=%d): %w", params.Kind, data.TenderDBID, err)
+	}
+	if created > 0 {
+		s.logger.Debugf("Уведомление %s по тендеру %d получили %d пользователей", params.Kind, data.TenderDBID, created)
+	}
+	return nil
+}
+
+// onTenderSynced сообщает подписчикам, что обновление с ЭТП изменило тендер:
+// сколько добавилось предложений и у скольких позиций изменилась стоимость.
+func (s *FeedService) onTenderSynced(ctx context.Context, data events.TenderSyncedData) error {
+	tender, err := s.store.GetTenderByID(ctx, data.TenderDBID)
+	if err != nil {
+		return fmt.Errorf("ошибка GetTenderByID(%d): %w", data.TenderDBID, err)
+	}
+
+	body := fmt.Sprintf("Новых предложений: %d, позиций с изменённой стоимостью: %d", data.ProposalsAdded, data.PricesChanged)
+	if data.ProposalsAdded == 0 && data.PricesChanged == 0 {
+		body = "Изменились данные тендера"
+	}
+	payload, err := json.Marshal(map[string]any{
+		"sync_id":         data.SyncID,
+		"from_version":    data.FromVersion,
+		"to_version":      data.ToVersion,
+		"proposals_added": data.ProposalsAdded,
+		"prices_changed":  data.PricesChanged,
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации данных уведомления: %w", err)
+	}
+	created, err := s.store.CreateTenderNotifications(ctx, db.CreateTenderNotificationsParams{
+		Kind:     KindTenderSynced,
+		Title:    fmt.Sprintf("Тендер %s обновлён с ЭТП", tender.Title),
+		Body:     body,
+		Payload:  payload,
+		TenderID: data.TenderDBID,
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка CreateTenderNotifications(%s, tender_id=%d): %w", KindTenderSynced, data.TenderDBID, err)
+	}
+	if created > 0 {
+		s.logger.Debugf("Уведомление об обновлении тендера %d получили %d пользователей", data.TenderDBID, created)
+	}
+	return nil
+}
+
+// onLotAIAnalyzed сообщает о завершении AI-анализа лота.
+func (s *FeedService) onLotAIAnalyzed(ctx context.Context, data events.LotAIAnalyzedData) error {
+	lot, err := s.store.GetLotByID(ctx, data.LotID)
+	if err != nil {
+		return fmt.Errorf("ошибка GetLotByID(%d): %w", data.LotID, err)
+	}
+
+	body := "Ключевые параметры лота обновлены"
+	if data.ModelName != "" {
+		body += fmt.Sprintf(" (модель %s)", data.ModelName)
+	}
+	payload, err := json.Marshal(map[string]any{"ai_analysis_result_id": data.AIAnalysisResultID})
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации данных уведомления: %w", err)
+	}
+	created, err := s.store.CreateTenderNotifications(ctx, db.CreateTenderNotificationsParams{
+		Kind:     KindLotAIAnalysisCompleted,
+		Title:    fmt.Sprintf("AI-анализ лота %s завершён", lot.LotTitle),
+		Body:     body,
+		LotID:    sql.NullInt64{Int64: lot.ID, Valid: true},
+		Payload:  payload,
+		TenderID: lot.TenderID,
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка CreateTenderNotifications(%s, lot_id=%d): %w", KindLotAIAnalysisCompleted, data.LotID, err)
+	}
+	if created > 0 {
+		s.logger.Debugf("Уведомление об AI-анализе лота %d получили %d пользователей", data.LotID, created)
+	}
+	return nil
+}
+
+// onWinnerChanged сообщает подписчикам о назначении, изменении или снятии
+// победителя лота.
+func (s *FeedService) onWinnerChanged(ctx context.Context, eventType string, winnerID, lotID, proposalID int64) error {
+	lot, err := s.store.GetLotByID(ctx, lotID)
+	if err != nil {
+		return fmt.Errorf("ошибка GetLotByID(%d): %w", lotID, err)
+	}
+
+	var title, change string
+	switch eventType {
+	case events.TypeWinnerCreated:
+		title, change = "Назначен победитель по лоту %s", "created"
+	case events.TypeWinnerDeleted:
+		title, change = "Снят победитель по лоту %s", "deleted"
+	default:
+		title, change = "Изменены данные победителя по лоту %s", "updated"
+	}
+	payload, err := json.Marshal(map[string]any{
+		"winner_id":   winnerID,
+		"proposal_id": proposalID,
+		"change":      change,
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации данных уведомления: %w", err)
+	}
+	created, err := s.store.CreateTenderNotifications(ctx, db.CreateTenderNotificationsParams{
+		Kind:     KindTenderWinnerChanged,
+		Title:    fmt.Sprintf(title, lot.LotTitle),
+		LotID:    sql.NullInt64{Int64: lot.ID, Valid: true},
+		Payload:  payload,
+		TenderID: lot.TenderID,
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка CreateTenderNotifications(%s, lot_id=%d): %w", KindTenderWinnerChanged, lotID, err)
+	}
+	if created > 0 {
+		s.logger.Debugf("Уведомление о победителе лота %d получили %d пользователей", lotID, created)
+	}
+	return nil
+}
+
+// onMergeSuggested напоминает о заявках в очереди слияний каталога.
+func (s *FeedService) onMergeSuggested(ctx context.Context, _ events.MergeSuggestedData) error {
+	created, err := s.store.CreateRoleNotifications(ctx, db.CreateRoleNotificationsParams{
+		Kind:    KindMergeReviewPending,
+		Title:   "Заявки на слияние позиций каталога ждут проверки",
+		Body:    "В очереди слияний есть новые заявки",
+		Payload: json.RawMessage(`{}`),
+		Roles:   auth.RolesWithPermission(auth.PermissionCatalogManage),
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка CreateRoleNotifications(%s): %w", KindMergeReviewPending, err)
+	}
+	if created > 0 {
+		s.logger.Debugf("Напоминание об очереди слияний получили %d пользователей", created)
+	}
+	return nil
+}
+
+// onAccreditationExpiring напоминает об истекающих и истекших аккредитациях
+// подрядчиков. Непрочитанное напоминание не дублируется: в тексте — подрядчик,
+// из-за которого оно создано, полный список отдаёт expiring-accreditation.
+func (s *FeedService) onAccreditationExpiring(ctx context.Context, eventType string, data events.ContractorAccreditationData) error {
+	body := fmt.Sprintf("Аккредитация подрядчика %s (ИНН %s) действует до %s", data.Title, data.Inn, data.ValidUntil)
+	if eventType == events.TypeContractorAccreditationExpired {
+		body = fmt.Sprintf("Аккредитация подрядчика %s (ИНН %s) истекла %s", data.Title, data.Inn, data.ValidUntil)
+	}
+	payload, err := json.Marshal(map[string]any{
+		"contractor_id": data.ContractorID,
+		"valid_until":   data.ValidUntil,
+		"days_left":     data.DaysLeft,
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации данных уведомления: %w", err)
+	}
+	created, err := s.store.CreateRoleNotifications(ctx, db.CreateRoleNotificationsParams{
+		Kind:    KindContractorAccreditationExpiring,
+		Title:   "Истекает аккредитация подрядчиков",
+		Body:    body,
+		Payload: payload,
+		Roles:   auth.RolesWithPermission(auth.PermissionWinnersManage),
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка CreateRoleNotifications(%s): %w", KindContractorAccreditationExpiring, err)
+	}
+	if created > 0 {
+		s.logger.Debugf("Напоминание об аккредитации подрядчика %d получили %d пользователей", data.ContractorID, created)
+	}
+	return nil
+}
+
+// List возвращает ленту пользователя, новые первыми. limit <= 0 — значение
+// по умолчанию.
+func (s *FeedService) List(ctx context.Context, userID int64, unreadOnly bool, limit, offset int32) (*api_models.NotificationsResponse, error) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		return nil, apierrors.NewValidationError("request.limit_max", maxLimit)
+	}
+	if offset < 0 {
+		return nil, apierrors.NewValidationError("request.offset_negative")
+	}
+
+	rows, err := s.store.ListNotificationsByUser(ctx, db.ListNotificationsByUserParams{
+		UserID:     userID,
+		UnreadOnly: unreadOnly,
+		PageLimit:  limit,
+		PageOffset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ListNotificationsByUser(%d): %w", userID, err)
+	}
+	total, err := s.store.CountNotificationsByUser(ctx, db.CountNotificationsByUserParams{
+		UserID:     userID,
+		UnreadOnly: unreadOnly,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка CountNotificationsByUser(%d): %w", userID, err)
+	}
+	unread, err := s.UnreadCount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]api_models.Notification, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, notificationToResponse(row))
+	}
+	return &api_models.NotificationsResponse{
+		Items:       items,
+		Total:       total,
+		UnreadCount: unread,
+		Limit:       limit,
+		Offset:      offset,
+	}, nil
+}
+
+// UnreadCount возвращает число непрочитанных уведомлений пользователя.
+func (s *FeedService) UnreadCount(ctx context.Context, userID int64) (int64, error) {
+	count, err := s.store.CountUnreadNotifications(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка CountUnreadNotifications(%d): %w", userID, err)
+	}
+	return count, nil
+}
+
+// MarkRead отмечает уведомление пользователя прочитанным. Чужое уведомление
+// считается несуществующим.
+func (s *FeedService) MarkRead(ctx context.Context, userID, notificationID int64) (*api_models.Notification, error) {
+	row, err := s.store.MarkNotificationRead(ctx, db.MarkNotificationReadParams{
+		ID:     notificationID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewNotFoundError("feed.notification_not_found", notificationID)
+		}
+		return nil, fmt.Errorf("ошибка MarkNotificationRead(%d): %w", notificationID, err)
+	}
+	response := notificationToResponse(row)
+	return &response, nil
+}
+
+func notificationToResponse(row db.Notification) api_models.Notification {
+	n := api_models.Notification{
+		ID:        row.ID,
+		Kind:      row.Kind,
+		Title:     row.Title,
+		Body:      row.Body,
+		Payload:   row.Payload,
+		CreatedAt: row.CreatedAt,
+	}
+	if row.TenderID.Valid {
+		id := row.TenderID.Int64
+		n.TenderID = &id
+	}
+	if row.LotID.Valid {
+		id := row.LotID.Int64
+		n.LotID = &id
+	}
+	if row.ReadAt.Valid {
+		t := row.ReadAt.Time
+		n.ReadAt = &t
+	}
+	return n
+}
@@ -0,0 +1,319 @@
+package feed
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/auth"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR NOTIFICATION FEED (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Missed updates — new proposals, re-imports, winner changes and finished AI
+   analysis must reach the users who follow the tender without polling
+2. Noise — a stream of merge suggestions must not flood the bell icon
+3. Privacy — a user must not read or mark notifications of another user
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Publish
+- GIVEN tender.imported with new proposals
+  THEN a tender notification with the tender title and the count is created
+- GIVEN tender.imported without new proposals
+  THEN a tender_reimported notification is created
+- GIVEN tender.synced
+  THEN a tender_synced notification with the added proposals and price changes is created
+- GIVEN lot.ai_analyzed
+  THEN a tender notification with lot_id and the run id in payload is created
+- GIVEN winner.created / winner.deleted
+  THEN a tender_winner_changed notification with lot_id and the change is created
+- GIVEN merge.suggested
+  THEN role notifications for roles with catalog:manage are created
+- GIVEN contractor.accreditation_expiring / contractor.accreditation_expired
+  THEN role notifications for roles with winners:manage are created
+- GIVEN an event the feed does not handle (lot.updated)
+  THEN nothing is written and no error is returned
+
+SCENARIO 2: List
+- GIVEN limit 0 → default limit; limit above max → ValidationError
+- GIVEN rows
+  THEN items, total and unread count are returned
+
+SCENARIO 3: MarkRead
+- GIVEN a notification of another user (sql.ErrNoRows) → NotFoundError
+*/
+
+func setupTestService(t *testing.T) (*FeedService, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	return NewFeedService(mockStore, testutil.NewMockLogger()), mockStore
+}
+
+func TestPublish_TenderImportedWithNewProposals(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(7)).Return(db.Tender{ID: 7, Title: "Корпус 2"}, nil)
+	mockStore.EXPECT().CreateTenderNotifications(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateTenderNotificationsParams) (int64, error) {
+			assert.Equal(t, KindTenderNewProposals, arg.Kind)
+			assert.Equal(t, int64(7), arg.TenderID)
+			assert.False(t, arg.LotID.Valid)
+			assert.Contains(t, arg.Title, "Корпус 2")
+			assert.JSONEq(t, `{"new_proposals": 3}`, string(arg.Payload))
+			return 2, nil
+		})
+
+	err := service.Publish(context.Background(), events.NewEvent(events.TypeTenderImported, 7, events.TenderImportedData{
+		TenderDBID:   7,
+		TenderID:     "ETP-1",
+		NewProposals: 3,
+	}))
+	require.NoError(t, err)
+}
+
+func TestPublish_TenderImportedWithoutNewProposals_Reimported(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(7)).Return(db.Tender{ID: 7, Title: "Корпус 2"}, nil)
+	mockStore.EXPECT().CreateTenderNotifications(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateTenderNotificationsParams) (int64, error) {
+			assert.Equal(t, KindTenderReimported, arg.Kind)
+			assert.Equal(t, int64(7), arg.TenderID)
+			assert.JSONEq(t, `{"new_proposals": 0}`, string(arg.Payload))
+			return 0, nil
+		})
+
+	err := service.Publish(context.Background(), events.NewEvent(events.TypeTenderImported, 7, events.TenderImportedData{
+		TenderDBID: 7,
+		TenderID:   "ETP-1",
+	}))
+	require.NoError(t, err)
+}
+
+func TestPublish_TenderSynced(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(7)).Return(db.Tender{ID: 7, Title: "Корпус 2"}, nil)
+	mockStore.EXPECT().CreateTenderNotifications(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateTenderNotificationsParams) (int64, error) {
+			assert.Equal(t, KindTenderSynced, arg.Kind)
+			assert.Equal(t, int64(7), arg.TenderID)
+			assert.Contains(t, arg.Title, "Корпус 2")
+			assert.Equal(t, "Новых предложений: 1, позиций с изменённой стоимостью: 4", arg.Body)
+			assert.JSONEq(t, `{"sync_id": 3, "from_version": 2, "to_version": 3, "proposals_added": 1, "prices_changed": 4}`, string(arg.Payload))
+			return 2, nil
+		})
+
+	err := service.Publish(context.Background(), events.NewEvent(events.TypeTenderSynced, 7, events.TenderSyncedData{
+		TenderDBID:     7,
+		TenderID:       "ETP-1",
+		SyncID:         3,
+		FromVersion:    2,
+		ToVersion:      3,
+		ProposalsAdded: 1,
+		PricesChanged:  4,
+	}))
+	require.NoError(t, err)
+}
+
+func TestPublish_LotAIAnalyzed(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetLotByID(gomock.Any(), int64(5)).Return(db.Lot{ID: 5, TenderID: 7, LotTitle: "Фасады"}, nil)
+	mockStore.EXPECT().CreateTenderNotifications(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateTenderNotificationsParams) (int64, error) {
+			assert.Equal(t, KindLotAIAnalysisCompleted, arg.Kind)
+			assert.Equal(t, int64(7), arg.TenderID)
+			assert.Equal(t, sql.NullInt64{Int64: 5, Valid: true}, arg.LotID)
+			assert.Contains(t, arg.Title, "Фасады")
+			assert.Contains(t, arg.Body, "gpt-4o")
+			assert.JSONEq(t, `{"ai_analysis_result_id": 11}`, string(arg.Payload))
+			return 1, nil
+		})
+
+	err := service.Publish(context.Background(), events.NewEvent(events.TypeLotAIAnalyzed, 5, events.LotAIAnalyzedData{
+		LotID:              5,
+		TenderID:           7,
+		AIAnalysisResultID: 11,
+		ModelName:          "gpt-4o",
+	}))
+	require.NoError(t, err)
+}
+
+func TestPublish_WinnerChanged(t *testing.T) {
+	testCases := []struct {
+		name      string
+		eventType string
+		data      any
+		change    string
+	}{
+		{
+			name:      "created",
+			eventType: events.TypeWinnerCreated,
+			data:      events.WinnerCreatedData{WinnerID: 4, LotID: 5, ProposalID: 9, Rank: 1},
+			change:    "created",
+		},
+		{
+			name:      "deleted",
+			eventType: events.TypeWinnerDeleted,
+			data:      events.WinnerChangedData{WinnerID: 4, LotID: 5, ProposalID: 9},
+			change:    "deleted",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, mockStore := setupTestService(t)
+
+			mockStore.EXPECT().GetLotByID(gomock.Any(), int64(5)).Return(db.Lot{ID: 5, TenderID: 7, LotTitle: "Фасады"}, nil)
+			mockStore.EXPECT().CreateTenderNotifications(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, arg db.CreateTenderNotificationsParams) (int64, error) {
+					assert.Equal(t, KindTenderWinnerChanged, arg.Kind)
+					assert.Equal(t, int64(7), arg.TenderID)
+					assert.Equal(t, sql.NullInt64{Int64: 5, Valid: true}, arg.LotID)
+					assert.Contains(t, arg.Title, "Фасады")
+					assert.JSONEq(t, `{"winner_id": 4, "proposal_id": 9, "change": "`+tc.change+`"}`, string(arg.Payload))
+					return 1, nil
+				})
+
+			err := service.Publish(context.Background(), events.NewEvent(tc.eventType, 5, tc.data))
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPublish_MergeSuggested_NotifiesCatalogManagers(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().CreateRoleNotifications(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateRoleNotificationsParams) (int64, error) {
+			assert.Equal(t, KindMergeReviewPending, arg.Kind)
+			assert.Equal(t, auth.RolesWithPermission(auth.PermissionCatalogManage), arg.Roles)
+			return 0, nil
+		})
+
+	err := service.Publish(context.Background(), events.NewEvent(events.TypeMergeSuggested, 1, events.MergeSuggestedData{
+		MainPositionID:      1,
+		DuplicatePositionID: 2,
+		SimilarityScore:     0.93,
+	}))
+	require.NoError(t, err)
+}
+
+func TestPublish_AccreditationExpired_NotifiesWinnerManagers(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().CreateRoleNotifications(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateRoleNotificationsParams) (int64, error) {
+			assert.Equal(t, KindContractorAccreditationExpiring, arg.Kind)
+			assert.Equal(t, auth.RolesWithPermission(auth.PermissionWinnersManage), arg.Roles)
+			assert.Contains(t, arg.Body, "истекла 2025-03-09")
+			assert.JSONEq(t, `{"contractor_id":7,"valid_until":"2025-03-09","days_left":-1}`, string(arg.Payload))
+			return 2, nil
+		})
+
+	err := service.Publish(context.Background(), events.NewEvent(events.TypeContractorAccreditationExpired, 7, events.ContractorAccreditationData{
+		ContractorID: 7,
+		Title:        "ООО Альфа",
+		Inn:          "7700000001",
+		ValidUntil:   "2025-03-09",
+		DaysLeft:     -1,
+	}))
+	require.NoError(t, err)
+}
+
+func TestPublish_UnhandledEvent_Ignored(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	err := service.Publish(context.Background(), events.NewEvent(events.TypeLotUpdated, 5, events.LotUpdatedData{LotID: 5}))
+	require.NoError(t, err)
+}
+
+func TestPublish_StoreError_Returned(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().CreateRoleNotifications(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("connection reset"))
+
+	err := service.Publish(context.Background(), events.NewEvent(events.TypeMergeSuggested, 1, events.MergeSuggestedData{}))
+	assert.ErrorContains(t, err, "connection reset")
+}
+
+func TestList_DefaultLimitAndCounts(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	now := time.Now()
+
+	mockStore.EXPECT().ListNotificationsByUser(gomock.Any(), db.ListNotificationsByUserParams{
+		UserID:     3,
+		UnreadOnly: true,
+		PageLimit:  defaultLimit,
+		PageOffset: 0,
+	}).Return([]db.Notification{
+		{ID: 2, UserID: 3, Kind: KindLotAIAnalysisCompleted, Title: "AI", TenderID: sql.NullInt64{Int64: 7, Valid: true}, LotID: sql.NullInt64{Int64: 5, Valid: true}, Payload: json.RawMessage(`{}`), CreatedAt: now},
+		{ID: 1, UserID: 3, Kind: KindMergeReviewPending, Title: "Слияния", Payload: json.RawMessage(`{}`), CreatedAt: now.Add(-time.Hour)},
+	}, nil)
+	mockStore.EXPECT().CountNotificationsByUser(gomock.Any(), db.CountNotificationsByUserParams{UserID: 3, UnreadOnly: true}).Return(int64(2), nil)
+	mockStore.EXPECT().CountUnreadNotifications(gomock.Any(), int64(3)).Return(int64(2), nil)
+
+	resp, err := service.List(context.Background(), 3, true, 0, 0)
+	require.NoError(t, err)
+
+	require.Len(t, resp.Items, 2)
+	assert.Equal(t, int64(2), resp.Total)
+	assert.Equal(t, int64(2), resp.UnreadCount)
+	assert.Equal(t, int32(defaultLimit), resp.Limit)
+	require.NotNil(t, resp.Items[0].LotID)
+	assert.Equal(t, int64(5), *resp.Items[0].LotID)
+	assert.Nil(t, resp.Items[1].TenderID)
+	assert.Nil(t, resp.Items[1].ReadAt)
+}
+
+func TestList_LimitTooLarge(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.List(context.Background(), 3, false, maxLimit+1, 0)
+
+	var validationErr *apierrors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestMarkRead_ForeignNotification_NotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().MarkNotificationRead(gomock.Any(), db.MarkNotificationReadParams{ID: 9, UserID: 3}).
+		Return(db.Notification{}, sql.ErrNoRows)
+
+	_, err := service.MarkRead(context.Background(), 3, 9)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestMarkRead_ReturnsReadAt(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	readAt := time.Now()
+
+	mockStore.EXPECT().MarkNotificationRead(gomock.Any(), db.MarkNotificationReadParams{ID: 9, UserID: 3}).
+		Return(db.Notification{ID: 9, UserID: 3, Kind: KindTenderNewProposals, ReadAt: sql.NullTime{Time: readAt, Valid: true}}, nil)
+
+	n, err := service.MarkRead(context.Background(), 3, 9)
+	require.NoError(t, err)
+	require.NotNil(t, n.ReadAt)
+	assert.Equal(t, readAt, *n.ReadAt)
+}
@@ -0,0 +1,98 @@
+package feed
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+// Follow подписывает пользователя на тендер. Повторная подписка не ошибка.
+// Доступ к тендеру чужой организации проверяет middleware маршрута.
+func (s *FeedService) Follow(ctx context.Context, userID, tenderID int64) (*api_models.TenderFollowResponse, error) {
+	tender, err := s.store.GetTenderByID(ctx, tenderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewNotFoundError("feed.tender_not_found", tenderID)
+		}
+		return nil, fmt.Errorf("ошибка GetTenderByID(%d): %w", tenderID, err)
+	}
+	if tender.DeletedAt.Valid {
+		return nil, apierrors.NewNotFoundError("feed.tender_not_found", tenderID)
+	}
+
+	if err := s.store.FollowTender(ctx, db.FollowTenderParams{UserID: userID, TenderID: tenderID}); err != nil {
+		return nil, fmt.Errorf("ошибка FollowTender(user_id=%d, tender_id=%d): %w", userID, tenderID, err)
+	}
+	return &api_models.TenderFollowResponse{TenderID: tenderID, Following: true}, nil
+}
+
+// Unfollow отменяет подписку. Отсутствующая подписка не ошибка: клиент
+// может повторить запрос после обрыва соединения.
+func (s *FeedService) Unfollow(ctx context.Context, userID, tenderID int64) (*api_models.TenderFollowResponse, error) {
+	removed, err := s.store.UnfollowTender(ctx, db.UnfollowTenderParams{UserID: userID, TenderID: tenderID})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка UnfollowTender(user_id=%d, tender_id=%d): %w", userID, tenderID, err)
+	}
+	if removed > 0 {
+		s.logger.Debugf("Пользователь %d больше не следит за тендером %d", userID, tenderID)
+	}
+	return &api_models.TenderFollowResponse{TenderID: tenderID, Following: false}, nil
+}
+
+// ListFollowed возвращает тендеры, за которыми следит пользователь.
+// organizationID — организация пользователя; невалидный — без фильтра (admin).
+func (s *FeedService) ListFollowed(ctx context.Context, userID int64, organizationID sql.NullInt64, limit, offset int32) (*api_models.FollowedTendersResponse, error) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		return nil, apierrors.NewValidationError("request.limit_max", maxLimit)
+	}
+	if offset < 0 {
+		return nil, apierrors.NewValidationError("request.offset_negative")
+	}
+
+	rows, err := s.store.ListFollowedTenders(ctx, db.ListFollowedTendersParams{
+		UserID:         userID,
+		OrganizationID: organizationID,
+		PageLimit:      limit,
+		PageOffset:     offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ListFollowedTenders(%d): %w", userID, err)
+	}
+	total, err := s.store.CountFollowedTenders(ctx, db.CountFollowedTendersParams{
+		UserID:         userID,
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка CountFollowedTenders(%d): %w", userID, err)
+	}
+
+	items := make([]api_models.FollowedTender, 0, len(rows))
+	for _, row := range rows {
+		item := api_models.FollowedTender{
+			ID:             row.ID,
+			EtpID:          row.EtpID,
+			Title:          row.Title,
+			ProposalsCount: row.ProposalsCount,
+			FollowedAt:     row.FollowedAt,
+		}
+		if row.DataPreparedOnDate.Valid {
+			date := row.DataPreparedOnDate.Time
+			item.DataPreparedOnDate = &date
+		}
+		items = append(items, item)
+	}
+	return &api_models.FollowedTendersResponse{
+		Items:  items,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
@@ -0,0 +1,112 @@
+package feed
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR TENDER FOLLOW SUBSCRIPTIONS (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Following a tender that does not exist (or was deleted) silently succeeds
+   and the user waits for notifications that never come
+2. A double click on "Follow" / "Unfollow" shows an error
+3. The followed list leaks tenders of another organization
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Follow
+- GIVEN a missing tender → NotFoundError
+- GIVEN a soft-deleted tender → NotFoundError, no subscription is written
+- GIVEN an existing tender → subscription is written, following=true
+
+SCENARIO 2: Unfollow
+- GIVEN no subscription → following=false without error
+
+SCENARIO 3: ListFollowed
+- GIVEN the user's organization → it is passed to the query as a filter
+- GIVEN a tender without a preparation date → the field is omitted
+*/
+
+func TestFollow_TenderNotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(7)).Return(db.Tender{}, sql.ErrNoRows)
+
+	_, err := service.Follow(context.Background(), 3, 7)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestFollow_DeletedTender_NotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(7)).
+		Return(db.Tender{ID: 7, DeletedAt: sql.NullTime{Time: time.Now(), Valid: true}}, nil)
+
+	_, err := service.Follow(context.Background(), 3, 7)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestFollow_Success(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(7)).Return(db.Tender{ID: 7}, nil)
+	mockStore.EXPECT().FollowTender(gomock.Any(), db.FollowTenderParams{UserID: 3, TenderID: 7}).Return(nil)
+
+	resp, err := service.Follow(context.Background(), 3, 7)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), resp.TenderID)
+	assert.True(t, resp.Following)
+}
+
+func TestUnfollow_NoSubscription(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().UnfollowTender(gomock.Any(), db.UnfollowTenderParams{UserID: 3, TenderID: 7}).Return(int64(0), nil)
+
+	resp, err := service.Unfollow(context.Background(), 3, 7)
+	require.NoError(t, err)
+	assert.False(t, resp.Following)
+}
+
+func TestListFollowed_OrganizationScope(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	org := sql.NullInt64{Int64: 2, Valid: true}
+	followedAt := time.Now()
+
+	mockStore.EXPECT().ListFollowedTenders(gomock.Any(), db.ListFollowedTendersParams{
+		UserID:         3,
+		OrganizationID: org,
+		PageLimit:      defaultLimit,
+		PageOffset:     0,
+	}).Return([]db.ListFollowedTendersRow{
+		{ID: 7, EtpID: "ETP-1", Title: "Корпус 2", ProposalsCount: 4, FollowedAt: followedAt},
+	}, nil)
+	mockStore.EXPECT().CountFollowedTenders(gomock.Any(), db.CountFollowedTendersParams{UserID: 3, OrganizationID: org}).Return(int64(1), nil)
+
+	resp, err := service.ListFollowed(context.Background(), 3, org, 0, 0)
+	require.NoError(t, err)
+
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, int64(1), resp.Total)
+	assert.Equal(t, "ETP-1", resp.Items[0].EtpID)
+	assert.Equal(t, int64(4), resp.Items[0].ProposalsCount)
+	assert.Nil(t, resp.Items[0].DataPreparedOnDate)
+	assert.Equal(t, followedAt, resp.Items[0].FollowedAt)
+}
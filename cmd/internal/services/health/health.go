@@ -0,0 +1,148 @@
+// Package health реализует проверки для проб оркестратора.
+//
+// Liveness (/healthz) отвечает, что процесс жив, и ничего не проверяет: его
+// провал приводит к перезапуску, а перезапуск не чинит недоступную БД.
+// Readiness (/readyz) проверяет зависимости — соединение с БД, версию схемы
+// и доступность парсера; при провале под выводится из балансировки.
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/db/migration"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/buildinfo"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Checker выполняет проверки готовности. Безопасен для конкурентного использования.
+type Checker struct {
+	db         *sql.DB
+	httpClient *http.Client
+	parserURL  string
+	timeout    time.Duration
+	logger     logging.Logger
+}
+
+// NewChecker создаёт Checker. conn — то же соединение, что использует Store.
+func NewChecker(conn *sql.DB, cfg *config.Config, logger logging.Logger) *Checker {
+	parser := cfg.Services.ParserService
+	return &Checker{
+		db: conn,
+		// Таймаут задаётся контекстом Readiness; клиент отдельный, чтобы
+		// пробы не делили пул соединений с проксированием загрузок.
+		httpClient: &http.Client{},
+		parserURL:  strings.TrimRight(parser.URL, "/") + parser.HealthPath,
+		timeout:    cfg.Health.ReadinessTimeout,
+		logger:     logger,
+	}
+}
+
+// Liveness возвращает статус процесса и версию сборки.
+func (c *Checker) Liveness() *api_models.HealthResponse {
+	return &api_models.HealthResponse{Status: StatusOK, Build: buildinfo.Get()}
+}
+
+// Readiness параллельно проверяет БД, миграции и парсер в пределах общего
+// таймаута. Порядок проверок в ответе фиксирован.
+func (c *Checker) Readiness(ctx context.Context) *api_models.ReadinessResponse {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	probes := []struct {
+		name string
+		fn   func(context.Context) (string, error)
+	}{
+		{"database", c.checkDatabase},
+		{"migrations", c.checkMigrations},
+		{"parser_service", c.checkParser},
+	}
+
+	checks := make([]api_models.ReadinessCheck, len(probes))
+	var wg sync.WaitGroup
+	for i, probe := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			message, err := probe.fn(ctx)
+			check := api_models.ReadinessCheck{
+				Name:       probe.name,
+				Status:     StatusOK,
+				Message:    message,
+				DurationMs: time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				check.Status = StatusFail
+				check.Message = err.Error()
+			}
+			checks[i] = check
+		}()
+	}
+	wg.Wait()
+
+	response := &api_models.ReadinessResponse{Status: StatusOK, Build: buildinfo.Get(), Checks: checks}
+	for _, check := range checks {
+		if check.Status != StatusOK {
+			response.Status = StatusFail
+			c.logger.Warnf("Проверка готовности %s не пройдена: %s", check.Name, check.Message)
+		}
+	}
+	return response
+}
+
+func (c *Checker) checkDatabase(ctx context.Context) (string, error) {
+	if err := c.db.PingContext(ctx); err != nil {
+		return "", fmt.Errorf("БД недоступна: %w", err)
+	}
+	return "", nil
+}
+
+// checkMigrations сравнивает версию в schema_migrations (golang-migrate)
+// с последней миграцией, встроенной в бинарник.
+func (c *Checker) checkMigrations(ctx context.Context) (string, error) {
+	status, err := migration.ReadStatus(ctx, c.db)
+	if err != nil {
+		return "", err
+	}
+	if err := status.Err(); err != nil {
+		return "", err
+	}
+	if status.Ahead() {
+		// Во время выкатки старая версия сервиса работает с уже обновлённой схемой
+		return fmt.Sprintf("схема БД новее сборки: %d > %d", status.Current, status.Expected), nil
+	}
+	return fmt.Sprintf("версия %d", status.Current), nil
+}
+
+// checkParser проверяет, что сервис парсера отвечает. Любой ответ кроме 5xx
+// считается признаком доступности.
+func (c *Checker) checkParser(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.parserURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("некорректный адрес парсера: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("сервис парсера недоступен: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return "", fmt.Errorf("сервис парсера ответил %d", resp.StatusCode)
+	}
+	return fmt.Sprintf("HTTP %d", resp.StatusCode), nil
+}
@@ -0,0 +1,191 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/db/migration"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR READINESS PROBE (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Traffic to a broken pod — a pod without DB or parser must be taken out of rotation
+2. Schema drift — a new build against an old schema must not receive traffic
+3. Hanging probes — a slow dependency must fail within the configured timeout
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: All dependencies healthy
+- GIVEN DB reachable, schema at the latest embedded version, parser answering 200
+  WHEN Readiness is called
+  THEN status ok, three checks in fixed order
+
+SCENARIO 2: Schema
+- GIVEN schema_migrations behind the embedded version → fail
+- GIVEN a dirty migration → fail
+- GIVEN schema ahead of the build (rolling deploy) → ok
+
+SCENARIO 3: Parser
+- GIVEN parser answering 503 → fail
+- GIVEN parser not answering within the timeout → fail, Readiness returns in time
+*/
+
+func setupChecker(t *testing.T, parserURL string, timeout time.Duration) (*Checker, sqlmock.Sqlmock) {
+	t.Helper()
+	conn, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	cfg := &config.Config{}
+	cfg.Services.ParserService = config.ParserServiceConfig{URL: parserURL, HealthPath: "/health"}
+	cfg.Health.ReadinessTimeout = timeout
+	return NewChecker(conn, cfg, testutil.NewMockLogger()), mock
+}
+
+func parserStub(t *testing.T, status int) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/health", r.URL.Path)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func expectSchemaVersion(mock sqlmock.Sqlmock, version uint, dirty bool) {
+	mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(version, dirty))
+}
+
+func checkByName(t *testing.T, resp *api_models.ReadinessResponse, name string) api_models.ReadinessCheck {
+	t.Helper()
+	for _, check := range resp.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("check %q not found", name)
+	return api_models.ReadinessCheck{}
+}
+
+func TestReadiness_AllHealthy(t *testing.T) {
+	latest, err := migration.LatestVersion()
+	require.NoError(t, err)
+
+	checker, mock := setupChecker(t, parserStub(t, http.StatusOK).URL, time.Second)
+	mock.MatchExpectationsInOrder(false)
+	mock.ExpectPing()
+	expectSchemaVersion(mock, latest, false)
+
+	resp := checker.Readiness(context.Background())
+
+	assert.Equal(t, StatusOK, resp.Status)
+	require.Len(t, resp.Checks, 3)
+	assert.Equal(t, []string{"database", "migrations", "parser_service"},
+		[]string{resp.Checks[0].Name, resp.Checks[1].Name, resp.Checks[2].Name})
+	for _, check := range resp.Checks {
+		assert.Equal(t, StatusOK, check.Status, check.Name)
+	}
+	assert.NotEmpty(t, resp.Build.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReadiness_DatabaseDown(t *testing.T) {
+	checker, mock := setupChecker(t, parserStub(t, http.StatusOK).URL, time.Second)
+	mock.MatchExpectationsInOrder(false)
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+		WillReturnError(errors.New("connection refused"))
+
+	resp := checker.Readiness(context.Background())
+
+	assert.Equal(t, StatusFail, resp.Status)
+	assert.Equal(t, StatusFail, checkByName(t, resp, "database").Status)
+	assert.Equal(t, StatusOK, checkByName(t, resp, "parser_service").Status)
+}
+
+func TestCheckMigrations(t *testing.T) {
+	latest, err := migration.LatestVersion()
+	require.NoError(t, err)
+
+	cases := map[string]struct {
+		version uint
+		dirty   bool
+		wantErr bool
+	}{
+		"up to date":      {latest, false, false},
+		"pending":         {latest - 1, false, true},
+		"dirty":           {latest, true, true},
+		"schema is newer": {latest + 1, false, false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			checker, mock := setupChecker(t, "http://parser", time.Second)
+			expectSchemaVersion(mock, tc.version, tc.dirty)
+
+			_, err := checker.checkMigrations(context.Background())
+
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckMigrations_NeverMigrated(t *testing.T) {
+	checker, mock := setupChecker(t, "http://parser", time.Second)
+	mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}))
+
+	_, err := checker.checkMigrations(context.Background())
+
+	assert.ErrorContains(t, err, "миграции не применялись")
+}
+
+func TestCheckParser_ServerError(t *testing.T) {
+	checker, _ := setupChecker(t, parserStub(t, http.StatusServiceUnavailable).URL, time.Second)
+
+	_, err := checker.checkParser(context.Background())
+
+	assert.ErrorContains(t, err, "503")
+}
+
+func TestReadiness_ParserTimeout(t *testing.T) {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(slow.Close)
+	t.Cleanup(func() { close(release) })
+
+	latest, err := migration.LatestVersion()
+	require.NoError(t, err)
+	checker, mock := setupChecker(t, slow.URL, 100*time.Millisecond)
+	mock.MatchExpectationsInOrder(false)
+	mock.ExpectPing()
+	expectSchemaVersion(mock, latest, false)
+
+	start := time.Now()
+	resp := checker.Readiness(context.Background())
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, StatusFail, resp.Status)
+	assert.Equal(t, StatusFail, checkByName(t, resp, "parser_service").Status)
+}
@@ -0,0 +1,237 @@
+// Package history ведёт историю ручных правок полей тендеров и лотов
+// (таблица field_changes).
+//
+// Сервисы, меняющие поля по запросу пользователя (tender.UpdateTender,
+// lot.PatchKeyParameters, lot.PromoteAIAnalysisRun), вызывают Record в своей
+// транзакции: правка без записи истории не сохраняется. Обновления от импорта и
+// AI-воркера в историю не пишутся — их след остаётся в tender_raw_data_history
+// и ai_analysis_results. HistoryService отдаёт ленту правок для
+// GET /api/v1/tenders/:id/history и GET /api/v1/lots/:id/history.
+package history
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// Виды сущностей (chk_field_changes_entity_type).
+const (
+	EntityTender = "tender"
+	EntityLot    = "lot"
+)
+
+// KeyParametersField — поле ключевых параметров лота; изменения пишутся по
+// ключам как "lot_key_parameters.<ключ>".
+const KeyParametersField = "lot_key_parameters"
+
+const (
+	defaultLimit = 50
+	maxLimit     = 200
+)
+
+// Change — изменение одного поля. Old и New сериализуются в JSON; nil — значения
+// не было (NULL в БД или отсутствующий ключ).
+type Change struct {
+	Field string
+	Old   interface{}
+	New   interface{}
+}
+
+// Record записывает изменения полей сущности. Вызывается в транзакции правки;
+// пустой changes ничего не пишет. changedBy = 0 — автор неизвестен.
+func Record(ctx context.Context, q *db.Queries, entityType string, entityID, changedBy int64, changes []Change) error {
+	for _, change := range changes {
+		oldValue, err := json.Marshal(change.Old)
+		if err != nil {
+			return fmt.Errorf("ошибка сериализации старого значения %s: %w", change.Field, err)
+		}
+		newValue, err := json.Marshal(change.New)
+		if err != nil {
+			return fmt.Errorf("ошибка сериализации нового значения %s: %w", change.Field, err)
+		}
+		if err := q.CreateFieldChange(ctx, db.CreateFieldChangeParams{
+			EntityType: entityType,
+			EntityID:   entityID,
+			Field:      change.Field,
+			OldValue:   oldValue,
+			NewValue:   newValue,
+			ChangedBy:  sql.NullInt64{Int64: changedBy, Valid: changedBy != 0},
+		}); err != nil {
+			return fmt.Errorf("ошибка записи истории %s %d (%s): %w", entityType, entityID, change.Field, err)
+		}
+	}
+	return nil
+}
+
+// DiffKeyParameters сравнивает ключевые параметры лота до и после правки и
+// возвращает изменения по ключам в алфавитном порядке. Пустое или null
+// значение — параметров не было. Значения сравниваются как JSON: 100 и "100"
+// различаются.
+func DiffKeyParameters(before, after []byte) ([]Change, error) {
+	oldParams, err := decodeObject(before)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка разбора прежних ключевых параметров: %w", err)
+	}
+	newParams, err := decodeObject(after)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка разбора новых ключевых параметров: %w", err)
+	}
+
+	keys := make([]string, 0, len(oldParams)+len(newParams))
+	for key := range oldParams {
+		keys = append(keys, key)
+	}
+	for key := range newParams {
+		if _, ok := oldParams[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []Change
+	for _, key := range keys {
+		oldValue, hadOld := oldParams[key]
+		newValue, hasNew := newParams[key]
+		if hadOld && hasNew && bytes.Equal(oldValue, newValue) {
+			continue
+		}
+		change := Change{Field: KeyParametersField + "." + key}
+		if hadOld {
+			change.Old = oldValue
+		}
+		if hasNew {
+			change.New = newValue
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// decodeObject разбирает JSON-объект в компактные значения по ключам.
+func decodeObject(raw []byte) (map[string]json.RawMessage, error) {
+	params := map[string]json.RawMessage{}
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return params, nil
+	}
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, err
+	}
+	if params == nil { // null
+		return map[string]json.RawMessage{}, nil
+	}
+	for key, value := range params {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, value); err != nil {
+			return nil, err
+		}
+		params[key] = compact.Bytes()
+	}
+	return params, nil
+}
+
+// HistoryService отдаёт историю правок тендеров и лотов.
+type HistoryService struct {
+	store  db.Store
+	logger logging.Logger
+}
+
+// NewHistoryService создаёт сервис истории правок.
+func NewHistoryService(store db.Store, logger logging.Logger) *HistoryService {
+	return &HistoryService{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// List возвращает правки сущности, новые первыми. NotFoundError — если
+// тендера или лота нет; limit <= 0 — значение по умолчанию.
+func (s *HistoryService) List(ctx context.Context, entityType string, entityID int64, limit, offset int32) (*api_models.FieldHistoryResponse, error) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		return nil, apierrors.NewValidationError("request.limit_max", maxLimit)
+	}
+	if offset < 0 {
+		return nil, apierrors.NewValidationError("request.offset_negative")
+	}
+	if err := s.checkEntity(ctx, entityType, entityID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.store.ListFieldChanges(ctx, db.ListFieldChangesParams{
+		EntityType: entityType,
+		EntityID:   entityID,
+		PageLimit:  limit,
+		PageOffset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ListFieldChanges(%s %d): %w", entityType, entityID, err)
+	}
+	total, err := s.store.CountFieldChanges(ctx, db.CountFieldChangesParams{
+		EntityType: entityType,
+		EntityID:   entityID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка CountFieldChanges(%s %d): %w", entityType, entityID, err)
+	}
+
+	items := make([]api_models.FieldChange, 0, len(rows))
+	for _, row := range rows {
+		item := api_models.FieldChange{
+			ID:        row.ID,
+			Field:     row.Field,
+			OldValue:  row.OldValue,
+			NewValue:  row.NewValue,
+			ChangedAt: row.ChangedAt,
+		}
+		if row.ChangedBy.Valid {
+			item.ChangedBy = &row.ChangedBy.Int64
+		}
+		if row.ChangedByEmail.Valid {
+			item.ChangedByEmail = &row.ChangedByEmail.String
+		}
+		items = append(items, item)
+	}
+	return &api_models.FieldHistoryResponse{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Items:      items,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+	}, nil
+}
+
+// checkEntity проверяет, что тендер или лот существует.
+func (s *HistoryService) checkEntity(ctx context.Context, entityType string, entityID int64) error {
+	var err error
+	switch entityType {
+	case EntityTender:
+		_, err = s.store.GetTenderByID(ctx, entityID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return apierrors.NewNotFoundError("tender.not_found", entityID)
+		}
+	case EntityLot:
+		_, err = s.store.GetLotByID(ctx, entityID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return apierrors.NewNotFoundError("lot.not_found", entityID)
+		}
+	default:
+		return fmt.Errorf("неизвестный вид сущности %q", entityType)
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка получения %s %d: %w", entityType, entityID, err)
+	}
+	return nil
+}
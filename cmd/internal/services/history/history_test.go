@@ -0,0 +1,157 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR FIELD HISTORY (Unit Tests)
+
+SCENARIO 1: DiffKeyParameters
+- GIVEN key parameters before and after an edit
+  WHEN they are diffed
+  THEN changed, added and removed keys are returned in key order with null for the
+  missing side; keys whose JSON value is unchanged (ignoring formatting) are skipped
+
+- GIVEN NULL or empty parameters on either side
+  WHEN they are diffed
+  THEN they are treated as an empty object
+
+- GIVEN a number and the same digits as a string
+  WHEN they are diffed
+  THEN it is a change (JSON types are compared)
+
+SCENARIO 2: List
+- GIVEN a tender with recorded changes, one of them by a deleted user
+  WHEN its history is listed
+  THEN items keep the query order, the author is empty for the deleted user,
+  and total, limit and offset are returned
+
+- GIVEN limit 0
+  THEN the default limit is used; limit above the maximum or a negative offset is rejected
+
+- GIVEN a missing tender or lot
+  WHEN its history is listed
+  THEN NotFoundError is returned and no changes are queried
+*/
+
+func setupTestService(t *testing.T) (*HistoryService, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	return NewHistoryService(mockStore, testutil.NewMockLogger()), mockStore
+}
+
+func TestDiffKeyParameters(t *testing.T) {
+	changes, err := DiffKeyParameters(
+		[]byte(`{"area": 100, "floors": 9, "type": "жилой", "meta": {"a": 1}}`),
+		[]byte(`{"area":120,"floors":9,"height":30,"meta":{"a":1}}`),
+	)
+
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	assert.Equal(t, "lot_key_parameters.area", changes[0].Field)
+	assert.Equal(t, json.RawMessage(`100`), changes[0].Old, "значения сравниваются без учёта форматирования")
+	assert.Equal(t, json.RawMessage(`120`), changes[0].New)
+	assert.Equal(t, "lot_key_parameters.height", changes[1].Field)
+	assert.Nil(t, changes[1].Old)
+	assert.Equal(t, "lot_key_parameters.type", changes[2].Field)
+	assert.Nil(t, changes[2].New)
+}
+
+func TestDiffKeyParameters_EmptySides(t *testing.T) {
+	changes, err := DiffKeyParameters(nil, []byte(`{"area":"100"}`))
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Nil(t, changes[0].Old)
+
+	changes, err = DiffKeyParameters([]byte(`null`), []byte(`{}`))
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestDiffKeyParameters_TypeChange(t *testing.T) {
+	changes, err := DiffKeyParameters([]byte(`{"area":100}`), []byte(`{"area":"100"}`))
+
+	require.NoError(t, err)
+	assert.Len(t, changes, 1)
+}
+
+func TestList_Tender(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	changedAt := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(7)).Return(db.Tender{ID: 7}, nil)
+	mockStore.EXPECT().ListFieldChanges(gomock.Any(), db.ListFieldChangesParams{
+		EntityType: EntityTender,
+		EntityID:   7,
+		PageLimit:  50,
+		PageOffset: 0,
+	}).Return([]db.ListFieldChangesRow{
+		{
+			ID:             2,
+			Field:          "title",
+			OldValue:       []byte(`"Старое"`),
+			NewValue:       []byte(`"Новое"`),
+			ChangedBy:      sql.NullInt64{Int64: 42, Valid: true},
+			ChangedByEmail: sql.NullString{String: "editor@example.com", Valid: true},
+			ChangedAt:      changedAt,
+		},
+		{ID: 1, Field: "region", OldValue: []byte(`null`), NewValue: []byte(`"77"`), ChangedAt: changedAt.Add(-time.Hour)},
+	}, nil)
+	mockStore.EXPECT().CountFieldChanges(gomock.Any(), db.CountFieldChangesParams{
+		EntityType: EntityTender,
+		EntityID:   7,
+	}).Return(int64(2), nil)
+
+	resp, err := service.List(context.Background(), EntityTender, 7, 0, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), resp.Total)
+	assert.Equal(t, int32(50), resp.Limit)
+	require.Len(t, resp.Items, 2)
+	assert.Equal(t, "title", resp.Items[0].Field)
+	require.NotNil(t, resp.Items[0].ChangedBy)
+	assert.Equal(t, int64(42), *resp.Items[0].ChangedBy)
+	assert.Equal(t, "editor@example.com", *resp.Items[0].ChangedByEmail)
+	assert.Nil(t, resp.Items[1].ChangedBy, "автор удалён")
+	assert.Nil(t, resp.Items[1].ChangedByEmail)
+}
+
+func TestList_InvalidPaging(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.List(context.Background(), EntityLot, 5, maxLimit+1, 0)
+	var validationErr *apierrors.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+
+	_, err = service.List(context.Background(), EntityLot, 5, 10, -1)
+	assert.True(t, errors.As(err, &validationErr))
+}
+
+func TestList_NotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetLotByID(gomock.Any(), int64(5)).Return(db.Lot{}, sql.ErrNoRows)
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(7)).Return(db.Tender{}, sql.ErrNoRows)
+
+	var notFoundErr *apierrors.NotFoundError
+	_, err := service.List(context.Background(), EntityLot, 5, 0, 0)
+	assert.True(t, errors.As(err, &notFoundErr))
+
+	_, err = service.List(context.Background(), EntityTender, 7, 0, 0)
+	assert.True(t, errors.As(err, &notFoundErr))
+}
@@ -0,0 +1,326 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/lib/pq"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+)
+
+// Bulk-сохранение позиций (import.bulk_positions).
+//
+// Построчный UpsertPositionItem — это round-trip на каждую позицию, и на
+// тендерах с десятками тысяч позиций импорт упирается в задержку сети. Bulk-путь
+// передаёт все позиции предложения одним COPY во временную таблицу и сливает
+// их в position_items одним INSERT ... ON CONFLICT с той же семантикой, что у
+// UpsertPositionItem (position_item.sql).
+//
+// SQL здесь написан вручную: sqlc не поддерживает COPY и не знает о временных
+// таблицах. При изменении UpsertPositionItem нужно обновить и mergeStagedPositionItems.
+
+// positionItemCopyColumns — колонки position_items, которые пишет импорт,
+// в порядке positionCopyRow.
+var positionItemCopyColumns = []string{
+	"proposal_id",
+	"catalog_position_id",
+	"position_key_in_proposal",
+	"comment_organazier",
+	"comment_contractor",
+	"item_number_in_proposal",
+	"chapter_number_in_proposal",
+	"job_title_in_proposal",
+	"unit_id",
+	"quantity",
+	"suggested_quantity",
+	"total_cost_for_organizer_quantity",
+	"unit_cost_materials",
+	"unit_cost_works",
+	"unit_cost_indirect_costs",
+	"unit_cost_total",
+	"total_cost_materials",
+	"total_cost_works",
+	"total_cost_indirect_costs",
+	"total_cost_total",
+	"deviation_from_baseline_cost",
+	"is_chapter",
+	"chapter_ref_in_proposal",
+	"article_smr",
+	"currency",
+}
+
+// numericCopyColumns — колонки positionItemCopyColumns типа NUMERIC. sqlc
+// передаёт их строками (sql.NullString), а бинарный COPY pgx текст в numeric
+// не кодирует, см. pgxPositionCopyRow.
+var numericCopyColumns = map[string]bool{
+	"quantity":                          true,
+	"suggested_quantity":                true,
+	"total_cost_for_organizer_quantity": true,
+	"unit_cost_materials":               true,
+	"unit_cost_works":                   true,
+	"unit_cost_indirect_costs":          true,
+	"unit_cost_total":                   true,
+	"total_cost_materials":              true,
+	"total_cost_works":                  true,
+	"total_cost_indirect_costs":         true,
+	"total_cost_total":                  true,
+	"deviation_from_baseline_cost":      true,
+}
+
+const positionItemsStage = "position_items_stage"
+
+var (
+	// Таблица живёт до конца транзакции и переиспользуется всеми предложениями тендера
+	createPositionItemsStage = fmt.Sprintf(
+		"CREATE TEMP TABLE IF NOT EXISTS %s ON COMMIT DROP AS SELECT %s FROM position_items WITH NO DATA",
+		positionItemsStage, strings.Join(positionItemCopyColumns, ", "))
+
+	mergeStagedPositionItems = fmt.Sprintf(`INSERT INTO position_items (%[1]s)
+SELECT %[1]s FROM %[2]s
+ON CONFLICT (proposal_id, position_key_in_proposal) DO UPDATE SET
+    catalog_position_id = EXCLUDED.catalog_position_id,
+    comment_organazier = EXCLUDED.comment_organazier,
+    comment_contractor = EXCLUDED.comment_contractor,
+    item_number_in_proposal = EXCLUDED.item_number_in_proposal,
+    chapter_number_in_proposal = EXCLUDED.chapter_number_in_proposal,
+    job_title_in_proposal = EXCLUDED.job_title_in_proposal,
+    unit_id = EXCLUDED.unit_id,
+    quantity = EXCLUDED.quantity,
+    suggested_quantity = EXCLUDED.suggested_quantity,
+    total_cost_for_organizer_quantity = EXCLUDED.total_cost_for_organizer_quantity,
+    unit_cost_materials = EXCLUDED.unit_cost_materials,
+    unit_cost_works = EXCLUDED.unit_cost_works,
+    unit_cost_indirect_costs = EXCLUDED.unit_cost_indirect_costs,
+    unit_cost_total = EXCLUDED.unit_cost_total,
+    total_cost_materials = EXCLUDED.total_cost_materials,
+    total_cost_works = EXCLUDED.total_cost_works,
+    total_cost_indirect_costs = EXCLUDED.total_cost_indirect_costs,
+    total_cost_total = EXCLUDED.total_cost_total,
+    deviation_from_baseline_cost = EXCLUDED.deviation_from_baseline_cost,
+    is_chapter = EXCLUDED.is_chapter,
+    chapter_ref_in_proposal = EXCLUDED.chapter_ref_in_proposal,
+    article_smr = EXCLUDED.article_smr,
+    currency = EXCLUDED.currency,
+    matched_by = CASE
+        WHEN position_items.catalog_position_id IS NOT DISTINCT FROM EXCLUDED.catalog_position_id
+        THEN position_items.matched_by
+    END,
+    matched_at = CASE
+        WHEN position_items.catalog_position_id IS NOT DISTINCT FROM EXCLUDED.catalog_position_id
+        THEN position_items.matched_at
+    END,
+    updated_at = NOW()`, strings.Join(positionItemCopyColumns, ", "), positionItemsStage)
+
+	truncatePositionItemsStage = "TRUNCATE " + positionItemsStage
+)
+
+// positionCopier — querier транзакции импорта, который умеет сохранять позиции
+// пачкой. Его передаёт execTx, когда включён import.bulk_positions.
+type positionCopier interface {
+	db.Querier
+	copyPositionItems(ctx context.Context, rows []db.UpsertPositionItemParams) error
+}
+
+// copyQuerier — *db.Queries поверх транзакции, открытой execTx, вместе с самой
+// транзакцией и её соединением: COPY выполняется в той же сессии, что и остальные
+// запросы импорта, иначе временная таблица и строки не попадут в транзакцию.
+type copyQuerier struct {
+	*db.Queries
+	tx   *sql.Tx
+	conn *sql.Conn
+}
+
+// copyPositionItems сохраняет позиции через временную таблицу: COPY, слияние в
+// position_items, очистка таблицы для следующего предложения.
+func (q *copyQuerier) copyPositionItems(ctx context.Context, rows []db.UpsertPositionItemParams) error {
+	if _, err := q.tx.ExecContext(ctx, createPositionItemsStage); err != nil {
+		return fmt.Errorf("не удалось создать временную таблицу позиций: %w", err)
+	}
+	if err := q.copyToStage(ctx, rows); err != nil {
+		return err
+	}
+	if _, err := q.tx.ExecContext(ctx, mergeStagedPositionItems); err != nil {
+		return fmt.Errorf("не удалось сохранить позиции из временной таблицы: %w", err)
+	}
+	if _, err := q.tx.ExecContext(ctx, truncatePositionItemsStage); err != nil {
+		return fmt.Errorf("не удалось очистить временную таблицу позиций: %w", err)
+	}
+	return nil
+}
+
+// copyToStage передаёт строки во временную таблицу. Под драйвером pgx COPY идёт
+// через pgx.Conn.CopyFrom в бинарном формате (numeric — как pgtype.Numeric),
+// под lib/pq — через pq.CopyIn в текстовом.
+func (q *copyQuerier) copyToStage(ctx context.Context, rows []db.UpsertPositionItemParams) error {
+	viaPgx := false
+	err := q.conn.Raw(func(driverConn any) error {
+		pgxConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return nil
+		}
+		viaPgx = true
+		_, err := pgxConn.Conn().CopyFrom(ctx, pgx.Identifier{positionItemsStage}, positionItemCopyColumns,
+			pgx.CopyFromSlice(len(rows), func(i int) ([]any, error) { return pgxPositionCopyRow(rows[i]) }))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("не удалось выполнить COPY позиций: %w", err)
+	}
+	if viaPgx {
+		return nil
+	}
+
+	stmt, err := q.tx.PrepareContext(ctx, pq.CopyIn(positionItemsStage, positionItemCopyColumns...))
+	if err != nil {
+		return fmt.Errorf("не удалось начать COPY позиций: %w", err)
+	}
+	for _, row := range rows {
+		if _, err := stmt.ExecContext(ctx, positionCopyRow(row)...); err != nil {
+			stmt.Close()
+			return fmt.Errorf("не удалось передать позицию '%s' в COPY: %w", row.PositionKeyInProposal, err)
+		}
+	}
+	// Вызов без аргументов завершает COPY
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		return fmt.Errorf("не удалось завершить COPY позиций: %w", err)
+	}
+	if err := stmt.Close(); err != nil {
+		return fmt.Errorf("не удалось завершить COPY позиций: %w", err)
+	}
+	return nil
+}
+
+// positionCopyRow раскладывает параметры UpsertPositionItem по positionItemCopyColumns.
+func positionCopyRow(p db.UpsertPositionItemParams) []any {
+	return []any{
+		p.ProposalID,
+		p.CatalogPositionID,
+		p.PositionKeyInProposal,
+		p.CommentOrganazier,
+		p.CommentContractor,
+		p.ItemNumberInProposal,
+		p.ChapterNumberInProposal,
+		p.JobTitleInProposal,
+		p.UnitID,
+		p.Quantity,
+		p.SuggestedQuantity,
+		p.TotalCostForOrganizerQuantity,
+		p.UnitCostMaterials,
+		p.UnitCostWorks,
+		p.UnitCostIndirectCosts,
+		p.UnitCostTotal,
+		p.TotalCostMaterials,
+		p.TotalCostWorks,
+		p.TotalCostIndirectCosts,
+		p.TotalCostTotal,
+		p.DeviationFromBaselineCost,
+		p.IsChapter,
+		p.ChapterRefInProposal,
+		p.ArticleSmr,
+		p.Currency,
+	}
+}
+
+// pgxPositionCopyRow — positionCopyRow для pgx.CopyFrom: значения NUMERIC-колонок
+// разбираются в pgtype.Numeric, пустые становятся NULL.
+func pgxPositionCopyRow(p db.UpsertPositionItemParams) ([]any, error) {
+	row := positionCopyRow(p)
+	for i, column := range positionItemCopyColumns {
+		if !numericCopyColumns[column] {
+			continue
+		}
+		value, ok := row[i].(sql.NullString)
+		if !ok {
+			continue
+		}
+		var n pgtype.Numeric
+		if value.Valid {
+			if err := n.Scan(value.String); err != nil {
+				return nil, fmt.Errorf("позиция '%s', %s = %q: %w", p.PositionKeyInProposal, column, value.String, err)
+			}
+		}
+		row[i] = n
+	}
+	return row, nil
+}
+
+// execTx выполняет fn в транзакции импорта. Без bulk-режима это store.ExecTx.
+// С ним транзакция открывается на выделенном соединении conn, потому что
+// db.Store не отдаёт ни *sql.Tx, ни соединение, а COPY нужны оба; fn получает
+// copyQuerier.
+func (s *TenderImportService) execTx(ctx context.Context, fn func(qtx db.Querier) error) error {
+	if s.bulkMinPositions == 0 {
+		return s.store.ExecTx(ctx, func(qtx *db.Queries) error { return fn(qtx) })
+	}
+
+	conn, err := s.conn.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(&copyQuerier{Queries: db.New(tx), tx: tx, conn: conn}); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("ошибка транзакции: %v, ошибка отката: %v", err, rbErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// processPositionsBulk готовит все позиции предложения (единицы измерения,
+// позиции каталога, matching_cache — как processSinglePosition) и сохраняет
+// их одним copyPositionItems.
+func (s *TenderImportService) processPositionsBulk(
+	ctx context.Context,
+	qtx positionCopier,
+	trace *importTrace,
+	lookups *positionLookups,
+	proposalID int64,
+	positions map[string]api_models.PositionItem,
+	lotTitle string,
+) (bool, error) {
+	hasNewPending := false
+	rows := make([]db.UpsertPositionItemParams, 0, len(positions))
+	for key, posAPI := range positions {
+		posStart := time.Now()
+		params, isNewPending, ok, err := s.preparePosition(ctx, qtx, trace, lookups, proposalID, key, posAPI, lotTitle)
+		trace.addPosition(time.Since(posStart))
+		if err != nil {
+			return false, fmt.Errorf("обработка позиции '%s': %w", key, err)
+		}
+		if !ok {
+			continue
+		}
+		rows = append(rows, params)
+		if isNewPending {
+			hasNewPending = true
+		}
+	}
+	if len(rows) == 0 {
+		return hasNewPending, nil
+	}
+
+	copyStart := time.Now()
+	err := qtx.copyPositionItems(ctx, rows)
+	trace.positions += time.Since(copyStart)
+	if err != nil {
+		s.logger.WithField("proposal_id", proposalID).Errorf("Не удалось сохранить позиции пачкой: %v", err)
+		return false, err
+	}
+	s.logger.WithField("proposal_id", proposalID).Debugf("Позиции сохранены пачкой: %d", len(rows))
+	return hasNewPending, nil
+}
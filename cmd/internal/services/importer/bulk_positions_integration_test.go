@@ -0,0 +1,149 @@
+// Purpose: Checks the COPY-based position insert against a real PostgreSQL database
+// and compares its speed with the row-by-row UpsertPositionItem path.
+
+//go:build integration
+
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/entities"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR BULK POSITION INSERT (Integration Tests)
+
+SCENARIO 1: Same result as row by row
+- GIVEN one payload imported once with bulk_positions off and once (another ETP ID) with it on
+  (testutil opens the pgx driver, so COPY goes through pgx CopyFrom)
+  THEN both proposals have the same position_items
+- GIVEN the bulk import is repeated with changed costs
+  THEN rows are updated in place (ON CONFLICT), not duplicated
+
+Benchmarks (go test -tags integration -run '^$' -bench ImportPositions ./cmd/internal/services/importer):
+- BenchmarkImportPositions/row_by_row/N and /copy/N import a tender with N positions
+*/
+
+func setupBulkTestDB(tb testing.TB) *sql.DB {
+	tb.Helper()
+	conn, container, err := testutil.SetupTestDatabaseNoT()
+	require.NoError(tb, err)
+	tb.Cleanup(func() { _ = testutil.TeardownTestDatabaseNoT(conn, container) })
+	require.NoError(tb, testutil.RunMigrationsNoT(conn))
+	return conn
+}
+
+func newIntegrationImportService(conn *sql.DB, bulk bool) *TenderImportService {
+	logger := testutil.NewMockLogger()
+	cfg := config.ImportConfig{BulkPositions: bulk, BulkMinPositions: 1}
+	return NewTenderImportService(db.NewStore(conn), conn, logger, entities.NewEntityManager(logger), cfg, config.OutboxConfig{}, events.Noop{}, nil)
+}
+
+// makePayloadWithPositions — тендер с одним лотом и n позициями базового предложения.
+func makePayloadWithPositions(etpID string, n int, cost string) *api_models.FullTenderData {
+	unit := "м2"
+	positions := make(map[string]api_models.PositionItem, n)
+	for i := 0; i < n; i++ {
+		positions[fmt.Sprintf("pos-%d", i)] = api_models.PositionItem{
+			Number:    fmt.Sprintf("%d", i+1),
+			JobTitle:  fmt.Sprintf("Работа %d", i),
+			Unit:      &unit,
+			Quantity:  testutil.Decimal("10"),
+			TotalCost: api_models.Cost{Total: testutil.Money(cost)},
+		}
+	}
+	payload := makeMinimalPayload()
+	payload.TenderID = etpID
+	payload.LotsData = map[string]api_models.Lot{
+		"lot-1": {
+			LotTitle: "Лот 1",
+			BaseLineProposal: api_models.ContractorProposalDetails{
+				Title:           "Initiator",
+				ContractorItems: api_models.ContractorItemsContainer{Positions: positions},
+			},
+		},
+	}
+	return payload
+}
+
+func importPayload(tb testing.TB, service *TenderImportService, payload *api_models.FullTenderData) map[string]int64 {
+	tb.Helper()
+	raw, err := json.Marshal(payload)
+	require.NoError(tb, err)
+	_, lotIDs, _, _, err := service.ImportFullTender(context.Background(), payload, raw)
+	require.NoError(tb, err)
+	return lotIDs
+}
+
+// positionSnapshot — позиции лота без идентификаторов и временных меток.
+func positionSnapshot(t *testing.T, conn *sql.DB, lotID int64) map[string]string {
+	t.Helper()
+	rows, err := conn.Query(`
+		SELECT pi.position_key_in_proposal,
+		       concat_ws('|', pi.catalog_position_id, pi.item_number_in_proposal, pi.job_title_in_proposal,
+		                 pi.unit_id, pi.quantity, pi.total_cost_total, pi.is_chapter)
+		FROM position_items pi
+		JOIN proposals p ON p.id = pi.proposal_id
+		WHERE p.lot_id = $1`, lotID)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	snapshot := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		require.NoError(t, rows.Scan(&key, &value))
+		snapshot[key] = value
+	}
+	require.NoError(t, rows.Err())
+	return snapshot
+}
+
+func TestBulkPositions_MatchesRowByRow(t *testing.T) {
+	conn := setupBulkTestDB(t)
+
+	rowLots := importPayload(t, newIntegrationImportService(conn, false), makePayloadWithPositions("ETP-ROW", 50, "100"))
+	bulkService := newIntegrationImportService(conn, true)
+	bulkLots := importPayload(t, bulkService, makePayloadWithPositions("ETP-BULK", 50, "100"))
+
+	rowSnapshot := positionSnapshot(t, conn, rowLots["lot-1"])
+	require.Len(t, rowSnapshot, 50)
+	assert.Equal(t, rowSnapshot, positionSnapshot(t, conn, bulkLots["lot-1"]))
+
+	// Повторный импорт обновляет строки на месте
+	importPayload(t, bulkService, makePayloadWithPositions("ETP-BULK", 50, "200"))
+	updated := positionSnapshot(t, conn, bulkLots["lot-1"])
+	require.Len(t, updated, 50)
+	assert.Contains(t, updated["pos-0"], "|200|")
+}
+
+func BenchmarkImportPositions(b *testing.B) {
+	conn := setupBulkTestDB(b)
+
+	for _, mode := range []struct {
+		name string
+		bulk bool
+	}{{"row_by_row", false}, {"copy", true}} {
+		for _, n := range []int{1000, 10000} {
+			b.Run(fmt.Sprintf("%s/%d", mode.name, n), func(b *testing.B) {
+				service := newIntegrationImportService(conn, mode.bulk)
+				for i := 0; i < b.N; i++ {
+					// Новый тендер на каждой итерации: замеряем вставку, а не обновление
+					importPayload(b, service, makePayloadWithPositions(fmt.Sprintf("ETP-%s-%d-%d", mode.name, n, i), n, "100"))
+				}
+			})
+		}
+	}
+}
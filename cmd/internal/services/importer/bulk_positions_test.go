@@ -0,0 +1,295 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/entities"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR BULK POSITION INSERT (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Slow imports — large proposals must be saved with one COPY instead of a query per row
+2. Diverging data — the bulk path must save the same params as UpsertPositionItem
+3. Half-written proposals — COPY and merge errors must abort the transaction
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Switch
+- GIVEN import.bulk_positions without a DB connection → bulk stays off
+- GIVEN a copier querier and a proposal with at least bulk_min_positions positions
+  WHEN processContractorItems is called
+  THEN lookups run per position, UpsertPositionItem is not called, rows go to COPY
+- GIVEN fewer positions than the threshold → positions are upserted row by row
+
+SCENARIO 2: COPY
+- GIVEN two rows on a non-pgx connection (lib/pq fallback)
+  WHEN copyPositionItems is called
+  THEN the stage table is created, both rows are copied with pq.CopyIn, merged
+  into position_items and the stage table is truncated
+  (the pgx CopyFrom path is covered by the integration test)
+- GIVEN a row for pgx CopyFrom
+  THEN NUMERIC columns are pgtype.Numeric (empty → NULL), other columns are unchanged;
+  a value that is not a number → error naming the position and column
+- GIVEN the merge fails → wrapped error
+
+SCENARIO 3: Transaction
+- GIVEN bulk mode and a failing callback → rollback, the callback error is returned
+- GIVEN bulk mode and a successful callback → commit, the callback gets a copier
+*/
+
+// fakeCopier запоминает позиции, переданные в COPY, остальные запросы идут в sqlmock.
+type fakeCopier struct {
+	*db.Queries
+	rows []db.UpsertPositionItemParams
+	err  error
+}
+
+func (f *fakeCopier) copyPositionItems(_ context.Context, rows []db.UpsertPositionItemParams) error {
+	f.rows = append(f.rows, rows...)
+	return f.err
+}
+
+func newBulkTestService(t *testing.T, conn *sql.DB, minPositions int) *TenderImportService {
+	t.Helper()
+	logger := testutil.NewMockLogger()
+	cfg := config.ImportConfig{BulkPositions: true, BulkMinPositions: minPositions}
+	return NewTenderImportService(nil, conn, logger, entities.NewEntityManager(logger), cfg, config.OutboxConfig{}, events.Noop{}, nil)
+}
+
+func TestNewTenderImportService_BulkWithoutConn_Disabled(t *testing.T) {
+	service := newBulkTestService(t, nil, 1)
+	assert.Zero(t, service.bulkMinPositions)
+}
+
+func TestProcessContractorItems_Bulk_CopiesPositions(t *testing.T) {
+	mock, q, cleanup := newMockQueries(t)
+	defer cleanup()
+	sqlDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	service := newBulkTestService(t, sqlDB, 1)
+	items := makePayloadWithOneLot().LotsData["lot-1"].BaseLineProposal.ContractorItems
+
+	// GIVEN lookups for the single position, then the summary line — no INSERT INTO position_items
+	setupNewUnitExpectations(mock)
+	setupPositionLookupExpectations(mock)
+	setupSummaryExpectations(mock, 200)
+	copier := &fakeCopier{Queries: q}
+
+	// WHEN
+	hasNew, err := service.processContractorItems(context.Background(), copier, newImportTrace(), nil, 200, items, "Лот")
+
+	// THEN
+	require.NoError(t, err)
+	assert.True(t, hasNew)
+	require.Len(t, copier.rows, 1)
+	row := copier.rows[0]
+	assert.Equal(t, int64(200), row.ProposalID)
+	assert.Equal(t, "pos-1", row.PositionKeyInProposal)
+	assert.Equal(t, sql.NullInt64{Int64: 300, Valid: true}, row.CatalogPositionID)
+	assert.Equal(t, sql.NullInt64{Int64: 10, Valid: true}, row.UnitID)
+	assert.Equal(t, "8000", row.TotalCostTotal.String)
+}
+
+func TestProcessContractorItems_BelowThreshold_UpsertsRows(t *testing.T) {
+	mock, q, cleanup := newMockQueries(t)
+	defer cleanup()
+	sqlDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	service := newBulkTestService(t, sqlDB, 2)
+	items := makePayloadWithOneLot().LotsData["lot-1"].BaseLineProposal.ContractorItems
+
+	setupPositionExpectations(mock, 200)
+	setupSummaryExpectations(mock, 200)
+	copier := &fakeCopier{Queries: q}
+
+	_, err = service.processContractorItems(context.Background(), copier, newImportTrace(), nil, 200, items, "Лот")
+
+	require.NoError(t, err)
+	assert.Empty(t, copier.rows)
+}
+
+func TestProcessContractorItems_Bulk_CopyError(t *testing.T) {
+	mock, q, cleanup := newMockQueries(t)
+	defer cleanup()
+	sqlDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	service := newBulkTestService(t, sqlDB, 1)
+	items := makePayloadWithOneLot().LotsData["lot-1"].BaseLineProposal.ContractorItems
+
+	setupNewUnitExpectations(mock)
+	setupPositionLookupExpectations(mock)
+	copier := &fakeCopier{Queries: q, err: errors.New("copy failed")}
+
+	_, err = service.processContractorItems(context.Background(), copier, newImportTrace(), nil, 200, items, "Лот")
+
+	require.EqualError(t, err, "copy failed")
+}
+
+func TestCopyQuerier_CopyPositionItems(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	rows := []db.UpsertPositionItemParams{
+		{ProposalID: 200, PositionKeyInProposal: "pos-1", JobTitleInProposal: "Устройство полов"},
+		{ProposalID: 200, PositionKeyInProposal: "pos-2", JobTitleInProposal: "Окраска стен"},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TEMP TABLE IF NOT EXISTS position_items_stage ON COMMIT DROP").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	prep := mock.ExpectPrepare(`COPY "position_items_stage" \("proposal_id", "catalog_position_id", "position_key_in_proposal"`)
+	for _, row := range rows {
+		prep.ExpectExec().WithArgs(copyRowArgs(row)...).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	prep.ExpectExec().WithArgs().WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO position_items \(proposal_id, .+\)\s+SELECT .+ FROM position_items_stage\s+ON CONFLICT`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("TRUNCATE position_items_stage").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	q := beginCopyQuerier(t, sqlDB)
+
+	require.NoError(t, q.copyPositionItems(context.Background(), rows))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPgxPositionCopyRow_Numeric(t *testing.T) {
+	row := db.UpsertPositionItemParams{
+		ProposalID:            200,
+		PositionKeyInProposal: "pos-1",
+		CommentContractor:     sql.NullString{String: "12.5", Valid: true},
+		Quantity:              sql.NullString{String: "12.5", Valid: true},
+		UnitCostTotal:         sql.NullString{String: "-1300.75", Valid: true},
+	}
+
+	values, err := pgxPositionCopyRow(row)
+
+	require.NoError(t, err)
+	require.Len(t, values, len(positionItemCopyColumns))
+	column := func(name string) any { return values[slices.Index(positionItemCopyColumns, name)] }
+
+	quantity, ok := column("quantity").(pgtype.Numeric)
+	require.True(t, ok, "quantity передаётся как pgtype.Numeric")
+	value, err := quantity.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "12.5", value)
+
+	unitCost := column("unit_cost_total").(pgtype.Numeric)
+	value, err = unitCost.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "-1300.75", value)
+
+	assert.Equal(t, pgtype.Numeric{}, column("suggested_quantity"), "пустое значение — NULL")
+	assert.Equal(t, sql.NullString{String: "12.5", Valid: true}, column("comment_contractor"), "текстовая колонка не меняется")
+
+	row.Quantity = sql.NullString{String: "12,5", Valid: true}
+	_, err = pgxPositionCopyRow(row)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pos-1")
+	assert.Contains(t, err.Error(), "quantity")
+}
+
+func TestCopyQuerier_MergeFails_ReturnsWrappedError(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TEMP TABLE").WillReturnResult(sqlmock.NewResult(0, 0))
+	prep := mock.ExpectPrepare("COPY")
+	prep.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+	prep.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO position_items").WillReturnError(errors.New("unique violation"))
+
+	q := beginCopyQuerier(t, sqlDB)
+
+	err = q.copyPositionItems(context.Background(), []db.UpsertPositionItemParams{{ProposalID: 1, PositionKeyInProposal: "k"}})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "не удалось сохранить позиции из временной таблицы")
+	assert.Contains(t, err.Error(), "unique violation")
+}
+
+func TestExecTx_Bulk_RollbackOnError(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	service := newBulkTestService(t, sqlDB, 1)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	err = service.execTx(context.Background(), func(db.Querier) error { return errors.New("boom") })
+
+	require.EqualError(t, err, "boom")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExecTx_Bulk_CommitsWithCopier(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	service := newBulkTestService(t, sqlDB, 1)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	err = service.execTx(context.Background(), func(qtx db.Querier) error {
+		_, ok := qtx.(positionCopier)
+		assert.True(t, ok, "bulk transaction must pass a positionCopier")
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// beginCopyQuerier открывает транзакцию на выделенном соединении, как execTx.
+func beginCopyQuerier(t *testing.T, sqlDB *sql.DB) *copyQuerier {
+	t.Helper()
+	conn, err := sqlDB.Conn(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	tx, err := conn.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+	return &copyQuerier{Queries: db.New(tx), tx: tx, conn: conn}
+}
+
+// copyRowArgs — ожидаемые аргументы COPY для строки: те же значения, что в positionCopyRow.
+func copyRowArgs(row db.UpsertPositionItemParams) []driver.Value {
+	values := positionCopyRow(row)
+	args := make([]driver.Value, len(values))
+	for i, v := range values {
+		if valuer, ok := v.(driver.Valuer); ok {
+			args[i], _ = valuer.Value()
+			continue
+		}
+		args[i] = v
+	}
+	return args
+}
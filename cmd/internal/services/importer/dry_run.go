@@ -0,0 +1,315 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/entities"
+)
+
+// Допуск сверки итогов с суммой позиций: округления в XLSX дают расхождения
+// в копейках, поэтому сравниваем с точностью до рубля или 0.1% суммы.
+var (
+	summaryAbsTolerance = decimal.NewFromInt(1)
+	summaryRelTolerance = decimal.RequireFromString("0.001")
+)
+
+// jsonMapFields — поля FullTenderData, которые являются map: их ключи в путях
+// пишутся как lots["lot_1"], остальные поля — через точку.
+var jsonMapFields = map[string]bool{
+	"lots":            true,
+	"proposals":       true,
+	"positions":       true,
+	"summary":         true,
+	"additional_info": true,
+}
+
+// ValidateImport выполняет проверки импорта без записи в БД (dry-run) и
+// возвращает отчёт для разработчиков парсера.
+//
+// Ошибки (Valid=false):
+//   - validation — FullTenderData.Validate, тот же отказ, что вернул бы импорт:
+//     по записи на каждое нарушение с путём поля;
+//   - duplicate_json_key — повторяющийся ключ объекта: при разборе JSON
+//     выигрывает последний, предыдущие позиции/лоты молча теряются.
+//
+// Предупреждения:
+//   - summary_mismatch — сумма позиций предложения не совпадает ни с одной итоговой строкой;
+//   - duplicate_position_number — одинаковый номер у разных позиций предложения;
+//   - unknown_unit — единицы измерения нет в справочнике, импорт её создаст.
+//
+// Из БД только читается справочник единиц измерения.
+func (s *TenderImportService) ValidateImport(
+	ctx context.Context,
+	payload *api_models.FullTenderData,
+	rawJSON []byte,
+) (*api_models.ImportValidationReport, error) {
+	report := &api_models.ImportValidationReport{
+		DryRun:    true,
+		TenderID:  payload.TenderID,
+		LotsCount: len(payload.LotsData),
+		Errors:    make([]api_models.ImportValidationIssue, 0),
+		Warnings:  make([]api_models.ImportValidationIssue, 0),
+	}
+
+	if err := payload.Validate(); err != nil {
+		var fieldErrs api_models.ValidationErrors
+		if !errors.As(err, &fieldErrs) {
+			fieldErrs = api_models.ValidationErrors{{Message: err.Error()}}
+		}
+		for _, fe := range fieldErrs {
+			report.Errors = append(report.Errors, api_models.ImportValidationIssue{
+				Code:    "validation",
+				Path:    fe.Path,
+				Message: fe.Message,
+			})
+		}
+	}
+
+	duplicates, err := findDuplicateJSONKeys(rawJSON)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка разбора JSON: %w", err)
+	}
+	for _, path := range duplicates {
+		report.Errors = append(report.Errors, api_models.ImportValidationIssue{
+			Code:    "duplicate_json_key",
+			Path:    path,
+			Message: "ключ повторяется: при импорте сохранится только последнее значение",
+		})
+	}
+
+	// Единица измерения → число позиций и первая позиция, где она встретилась
+	units := make(map[string]int)
+	unitFirstPath := make(map[string]string)
+
+	for _, lotKey := range sortedKeys(payload.LotsData) {
+		lot := payload.LotsData[lotKey]
+		lotPath := fmt.Sprintf("lots[%q]", lotKey)
+
+		proposals := []struct {
+			path     string
+			proposal api_models.ContractorProposalDetails
+		}{{lotPath + ".baseline_proposal", lot.BaseLineProposal}}
+		for _, proposalKey := range sortedKeys(lot.ProposalData) {
+			proposals = append(proposals, struct {
+				path     string
+				proposal api_models.ContractorProposalDetails
+			}{fmt.Sprintf("%s.proposals[%q]", lotPath, proposalKey), lot.ProposalData[proposalKey]})
+		}
+		report.ProposalsCount += len(lot.ProposalData)
+
+		for _, p := range proposals {
+			items := p.proposal.ContractorItems
+			report.PositionsCount += len(items.Positions)
+
+			if issue := checkSummaryTotals(p.path, items); issue != nil {
+				report.Warnings = append(report.Warnings, *issue)
+			}
+			report.Warnings = append(report.Warnings, checkDuplicatePositionNumbers(p.path, items.Positions)...)
+
+			for _, posKey := range sortedKeys(items.Positions) {
+				unit := normalizeUnitName(items.Positions[posKey].Unit)
+				if unit == "" {
+					continue
+				}
+				if units[unit] == 0 {
+					unitFirstPath[unit] = fmt.Sprintf("%s.contractor_items.positions[%q]", p.path, posKey)
+				}
+				units[unit]++
+			}
+		}
+	}
+
+	if len(units) > 0 {
+		unknown, err := s.findUnknownUnits(ctx, units)
+		if err != nil {
+			return nil, err
+		}
+		for _, unit := range unknown {
+			report.Warnings = append(report.Warnings, api_models.ImportValidationIssue{
+				Code:    "unknown_unit",
+				Path:    unitFirstPath[unit],
+				Message: fmt.Sprintf("единицы измерения %q нет в справочнике, импорт создаст её (позиций: %d)", unit, units[unit]),
+			})
+		}
+	}
+
+	report.Valid = len(report.Errors) == 0
+	s.logger.Infof("Dry-run импорта тендера %s: ошибок %d, предупреждений %d",
+		payload.TenderID, len(report.Errors), len(report.Warnings))
+	return report, nil
+}
+
+// findUnknownUnits возвращает отсортированный список единиц, которых нет в справочнике.
+func (s *TenderImportService) findUnknownUnits(ctx context.Context, units map[string]int) ([]string, error) {
+	names := sortedKeys(units)
+	existing, err := s.store.ListExistingUnitNames(ctx, names)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения справочника единиц измерения: %w", err)
+	}
+
+	known := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		known[name] = true
+	}
+	unknown := make([]string, 0)
+	for _, name := range names {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	return unknown, nil
+}
+
+// normalizeUnitName повторяет нормализацию GetOrCreateUnitOfMeasurement.
+func normalizeUnitName(unit *string) string {
+	if unit == nil {
+		return ""
+	}
+	return entities.NormalizeUnitName(*unit)
+}
+
+// checkSummaryTotals сверяет сумму total_cost.total позиций (без заголовков глав)
+// с итоговыми строками. Ключи итогов у парсера разные (с НДС, без НДС, по разделу),
+// поэтому достаточно совпадения хотя бы с одной строкой.
+func checkSummaryTotals(path string, items api_models.ContractorItemsContainer) *api_models.ImportValidationIssue {
+	var (
+		positionsSum decimal.Decimal
+		hasTotals    bool
+	)
+	for _, pos := range items.Positions {
+		if pos.IsChapter || pos.TotalCost.Total == nil {
+			continue
+		}
+		positionsSum = positionsSum.Add(pos.TotalCost.Total.Decimal)
+		hasTotals = true
+	}
+
+	summaryTotals := make([]string, 0, len(items.Summary))
+	tolerance := decimal.Max(summaryAbsTolerance, positionsSum.Abs().Mul(summaryRelTolerance))
+	for _, key := range sortedKeys(items.Summary) {
+		total := items.Summary[key].TotalCost.Total
+		if total == nil {
+			continue
+		}
+		if total.Sub(positionsSum).Abs().LessThanOrEqual(tolerance) {
+			return nil
+		}
+		summaryTotals = append(summaryTotals, fmt.Sprintf("%s=%s", key, total))
+	}
+	if !hasTotals || len(summaryTotals) == 0 {
+		return nil
+	}
+
+	return &api_models.ImportValidationIssue{
+		Code: "summary_mismatch",
+		Path: path,
+		Message: fmt.Sprintf("сумма позиций %s не совпадает ни с одной итоговой строкой (%s)",
+			api_models.NewMoney(positionsSum), strings.Join(summaryTotals, ", ")),
+	}
+}
+
+// checkDuplicatePositionNumbers находит позиции (не заголовки глав) с одинаковым номером.
+func checkDuplicatePositionNumbers(path string, positions map[string]api_models.PositionItem) []api_models.ImportValidationIssue {
+	byNumber := make(map[string][]string)
+	for _, key := range sortedKeys(positions) {
+		pos := positions[key]
+		number := strings.TrimSpace(pos.Number)
+		if pos.IsChapter || number == "" {
+			continue
+		}
+		byNumber[number] = append(byNumber[number], key)
+	}
+
+	issues := make([]api_models.ImportValidationIssue, 0)
+	for _, number := range sortedKeys(byNumber) {
+		keys := byNumber[number]
+		if len(keys) < 2 {
+			continue
+		}
+		issues = append(issues, api_models.ImportValidationIssue{
+			Code:    "duplicate_position_number",
+			Path:    path + ".contractor_items.positions",
+			Message: fmt.Sprintf("номер %q у нескольких позиций: %s", number, strings.Join(keys, ", ")),
+		})
+	}
+	return issues
+}
+
+// findDuplicateJSONKeys обходит JSON потоково и возвращает пути повторяющихся
+// ключей объектов. encoding/json при разборе в map молча оставляет последний.
+func findDuplicateJSONKeys(raw []byte) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	duplicates := make([]string, 0)
+
+	var walk func(path string, mapKeys bool) error
+	walk = func(path string, mapKeys bool) error {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		delim, ok := tok.(json.Delim)
+		if !ok {
+			return nil
+		}
+
+		switch delim {
+		case '{':
+			seen := make(map[string]bool)
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				key := keyTok.(string)
+
+				childPath := path + "." + key
+				if mapKeys {
+					childPath = fmt.Sprintf("%s[%q]", path, key)
+				}
+				if path == "" && !mapKeys {
+					childPath = key
+				}
+				if seen[key] {
+					duplicates = append(duplicates, childPath)
+				}
+				seen[key] = true
+
+				if err := walk(childPath, !mapKeys && jsonMapFields[key]); err != nil {
+					return err
+				}
+			}
+		case '[':
+			for i := 0; dec.More(); i++ {
+				if err := walk(fmt.Sprintf("%s[%d]", path, i), false); err != nil {
+					return err
+				}
+			}
+		}
+		// Закрывающий '}' или ']'
+		_, err = dec.Token()
+		return err
+	}
+
+	if err := walk("", false); err != nil {
+		return nil, err
+	}
+	return duplicates, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
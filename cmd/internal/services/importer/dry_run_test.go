@@ -0,0 +1,168 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR IMPORT DRY-RUN (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Polluted DB — parser developers must be able to check a payload without writing it
+2. Silent data loss — duplicate JSON keys drop positions/lots without any error
+3. Extraction bugs — totals that do not add up, duplicate numbers and new units
+   must be visible before the real import
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Clean payload
+- GIVEN a valid payload whose summary matches the positions and known units
+  WHEN ValidateImport is called
+  THEN Valid=true, no errors, no warnings, no transaction is opened
+
+SCENARIO 2: Errors
+- GIVEN a payload failing Validate → validation error, other checks still run
+- GIVEN raw JSON with a repeated position key → duplicate_json_key with its path
+
+SCENARIO 3: Warnings
+- GIVEN positions summing to 8000 and summary lines 9000/10800 → summary_mismatch
+- GIVEN two positions with the same number → duplicate_position_number
+- GIVEN a unit missing in units_of_measurement → unknown_unit
+
+SCENARIO 4: DB error reading units → wrapped error
+*/
+
+func marshalPayload(t *testing.T, payload *api_models.FullTenderData) []byte {
+	t.Helper()
+	raw, err := json.Marshal(payload)
+	require.NoError(t, err)
+	return raw
+}
+
+func TestValidateImport_CleanPayload(t *testing.T) {
+	service, mockStore := newTestService(t)
+	payload := makePayloadWithOneLot()
+
+	mockStore.EXPECT().ListExistingUnitNames(gomock.Any(), []string{"м2"}).Return([]string{"м2"}, nil)
+
+	report, err := service.ValidateImport(context.Background(), payload, marshalPayload(t, payload))
+
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.True(t, report.Valid)
+	assert.Empty(t, report.Errors)
+	assert.Empty(t, report.Warnings)
+	assert.Equal(t, 1, report.LotsCount)
+	assert.Equal(t, 0, report.ProposalsCount)
+	assert.Equal(t, 1, report.PositionsCount)
+}
+
+func TestValidateImport_ValidationError_OtherChecksStillRun(t *testing.T) {
+	service, mockStore := newTestService(t)
+	payload := makePayloadWithOneLot()
+	payload.TenderTitle = ""
+
+	mockStore.EXPECT().ListExistingUnitNames(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	report, err := service.ValidateImport(context.Background(), payload, marshalPayload(t, payload))
+
+	require.NoError(t, err)
+	assert.False(t, report.Valid)
+	require.Len(t, report.Errors, 1)
+	assert.Equal(t, "validation", report.Errors[0].Code)
+	assert.Equal(t, "tender_title", report.Errors[0].Path)
+	require.Len(t, report.Warnings, 1)
+	assert.Equal(t, "unknown_unit", report.Warnings[0].Code)
+	assert.Equal(t, `lots["lot-1"].baseline_proposal.contractor_items.positions["pos-1"]`, report.Warnings[0].Path)
+}
+
+func TestValidateImport_Warnings(t *testing.T) {
+	service, mockStore := newTestService(t)
+	payload := makePayloadWithOneLot()
+
+	lot := payload.LotsData["lot-1"]
+	items := lot.BaseLineProposal.ContractorItems
+	duplicate := items.Positions["pos-1"]
+	duplicate.TotalCost = api_models.Cost{}
+	items.Positions["pos-2"] = duplicate
+	items.Summary = map[string]api_models.SummaryLine{
+		"total_cost":          {TotalCost: api_models.Cost{Total: testutil.Money("9000")}},
+		"total_cost_with_vat": {TotalCost: api_models.Cost{Total: testutil.Money("10800")}},
+	}
+
+	mockStore.EXPECT().ListExistingUnitNames(gomock.Any(), []string{"м2"}).Return([]string{"м2"}, nil)
+
+	report, err := service.ValidateImport(context.Background(), payload, marshalPayload(t, payload))
+
+	require.NoError(t, err)
+	assert.True(t, report.Valid, "warnings do not block import")
+	codes := make([]string, 0, len(report.Warnings))
+	for _, w := range report.Warnings {
+		codes = append(codes, w.Code)
+	}
+	assert.ElementsMatch(t, []string{"summary_mismatch", "duplicate_position_number"}, codes)
+	for _, w := range report.Warnings {
+		if w.Code == "summary_mismatch" {
+			assert.Contains(t, w.Message, "8000.00")
+			assert.Contains(t, w.Message, "total_cost=9000.00")
+		}
+	}
+}
+
+func TestValidateImport_DBError(t *testing.T) {
+	service, mockStore := newTestService(t)
+	payload := makePayloadWithOneLot()
+
+	dbErr := errors.New("connection reset")
+	mockStore.EXPECT().ListExistingUnitNames(gomock.Any(), gomock.Any()).Return(nil, dbErr)
+
+	_, err := service.ValidateImport(context.Background(), payload, marshalPayload(t, payload))
+
+	assert.ErrorIs(t, err, dbErr)
+}
+
+func TestFindDuplicateJSONKeys(t *testing.T) {
+	raw := []byte(`{
+		"tender_id": "1",
+		"lots": {
+			"lot_1": {
+				"proposals": {
+					"ООО Альфа": {
+						"contractor_items": {
+							"positions": {"1": {"number": "1"}, "2": {}, "1": {"number": "1a"}}
+						}
+					}
+				}
+			}
+		},
+		"tender_id": "2",
+		"list": [{"a": 1, "a": 2}]
+	}`)
+
+	duplicates, err := findDuplicateJSONKeys(raw)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`lots["lot_1"].proposals["ООО Альфа"].contractor_items.positions["1"]`,
+		"tender_id",
+		"list[0].a",
+	}, duplicates)
+}
+
+func TestFindDuplicateJSONKeys_InvalidJSON(t *testing.T) {
+	_, err := findDuplicateJSONKeys([]byte(`{"a": `))
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,166 @@
+package importer
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"unicode"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+)
+
+const (
+	// DuplicateScoreThreshold — оценка сходства, начиная с которой пара
+	// тендеров ставится в очередь на проверку.
+	DuplicateScoreThreshold = 0.7
+
+	// Порог сходства наименований (триграммы) для отбора кандидатов не на
+	// том же объекте.
+	duplicateMinTitleSimilarity = 0.5
+	duplicateMaxCandidates      = 20
+	duplicateMaxPositionTitles  = 5000
+)
+
+// Веса признаков в оценке сходства пары тендеров.
+const (
+	duplicateTitleWeight     = 0.4
+	duplicateObjectWeight    = 0.2
+	duplicatePositionsWeight = 0.4
+)
+
+// flagProbableDuplicates сравнивает импортированный тендер с другими тендерами
+// его организации — на том же объекте или с похожим наименованием — по
+// наименованию, объекту и составу позиций. Пары с оценкой не ниже
+// DuplicateScoreThreshold попадают в tender_duplicates на проверку
+// администратором. Вызывается после коммита: ошибка только логируется,
+// импорт уже выполнен.
+func (s *TenderImportService) flagProbableDuplicates(ctx context.Context, tenderID int64) {
+	flagged, err := s.findDuplicates(ctx, tenderID)
+	if err != nil {
+		s.logger.Errorf("Не удалось проверить тендер %d на дубликаты: %v", tenderID, err)
+		return
+	}
+	if flagged > 0 {
+		s.logger.Warnf("Тендер %d похож на уже загруженные: вероятных дубликатов %d, нужна проверка", tenderID, flagged)
+	}
+}
+
+func (s *TenderImportService) findDuplicates(ctx context.Context, tenderID int64) (int, error) {
+	tender, err := s.store.GetTenderDuplicateFingerprint(ctx, tenderID)
+	if err != nil {
+		return 0, err
+	}
+	candidates, err := s.store.ListTenderDuplicateCandidates(ctx, db.ListTenderDuplicateCandidatesParams{
+		Title:              tender.Title,
+		OrganizationID:     tender.OrganizationID,
+		TenderID:           tenderID,
+		ObjectID:           tender.ObjectID,
+		MinTitleSimilarity: duplicateMinTitleSimilarity,
+		MaxCandidates:      duplicateMaxCandidates,
+	})
+	if err != nil || len(candidates) == 0 {
+		return 0, err
+	}
+
+	positions, err := s.positionFingerprint(ctx, tenderID)
+	if err != nil {
+		return 0, err
+	}
+
+	flagged := 0
+	for _, candidate := range candidates {
+		candidatePositions, err := s.positionFingerprint(ctx, candidate.ID)
+		if err != nil {
+			return flagged, err
+		}
+		sameObject := candidate.ObjectID == tender.ObjectID
+		positionsSimilarity := jaccard(positions, candidatePositions)
+		score := duplicateScore(float64(candidate.TitleSimilarity), sameObject, positionsSimilarity, len(positions) == 0 && len(candidatePositions) == 0)
+		if score < DuplicateScoreThreshold {
+			continue
+		}
+
+		params := db.UpsertTenderDuplicateParams{
+			TenderID:            max(tenderID, candidate.ID),
+			DuplicateOfID:       min(tenderID, candidate.ID),
+			Score:               formatSimilarity(score),
+			TitleSimilarity:     formatSimilarity(float64(candidate.TitleSimilarity)),
+			SameObject:          sameObject,
+			PositionsSimilarity: formatSimilarity(positionsSimilarity),
+		}
+		if _, err := s.store.UpsertTenderDuplicate(ctx, params); err != nil {
+			return flagged, err
+		}
+		flagged++
+		s.logger.Infof("Тендер %s (id=%d) — вероятный дубликат %s (id=%d): оценка %.3f",
+			tender.EtpID, tenderID, candidate.EtpID, candidate.ID, score)
+	}
+	return flagged, nil
+}
+
+// positionFingerprint — множество нормализованных наименований позиций тендера.
+func (s *TenderImportService) positionFingerprint(ctx context.Context, tenderID int64) (map[string]struct{}, error) {
+	titles, err := s.store.ListTenderPositionTitles(ctx, db.ListTenderPositionTitlesParams{
+		TenderID:  tenderID,
+		MaxTitles: duplicateMaxPositionTitles,
+	})
+	if err != nil {
+		return nil, err
+	}
+	fingerprint := make(map[string]struct{}, len(titles))
+	for _, title := range titles {
+		if normalized := normalizePositionTitle(title); normalized != "" {
+			fingerprint[normalized] = struct{}{}
+		}
+	}
+	return fingerprint, nil
+}
+
+// duplicateScore — взвешенная оценка сходства пары в [0, 1]. Если позиций нет
+// ни у одного тендера, их вес не учитывается: сравнивать нечего.
+func duplicateScore(titleSimilarity float64, sameObject bool, positionsSimilarity float64, noPositions bool) float64 {
+	object := 0.0
+	if sameObject {
+		object = 1
+	}
+	if noPositions {
+		return (duplicateTitleWeight*titleSimilarity + duplicateObjectWeight*object) /
+			(duplicateTitleWeight + duplicateObjectWeight)
+	}
+	return duplicateTitleWeight*titleSimilarity + duplicateObjectWeight*object + duplicatePositionsWeight*positionsSimilarity
+}
+
+// jaccard — доля общих элементов двух множеств; у пустого множества — 0.
+func jaccard(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	common := 0
+	for key := range a {
+		if _, ok := b[key]; ok {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+// normalizePositionTitle приводит наименование позиции к виду для сравнения:
+// нижний регистр, ё→е, без знаков препинания и лишних пробелов.
+func normalizePositionTitle(title string) string {
+	title = strings.ReplaceAll(strings.ToLower(title), "ё", "е")
+	title = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, title)
+	return strings.Join(strings.Fields(title), " ")
+}
+
+// formatSimilarity — значение для NUMERIC(4, 3).
+func formatSimilarity(v float64) string {
+	return strconv.FormatFloat(min(max(v, 0), 1), 'f', 3, 64)
+}
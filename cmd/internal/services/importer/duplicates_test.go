@@ -0,0 +1,122 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR DUPLICATE TENDER DETECTION (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Double counting — a re-published tender under a new ETP ID skews analytics
+2. Noise — tenders that merely share an object must not flood the review queue
+3. Broken import — a failing detector must not fail an already committed import
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Detection after import
+- GIVEN a candidate on the same object with a similar title and the same positions
+  WHEN flagProbableDuplicates is called for the newer tender
+  THEN the pair is upserted with the later tender as tender_id and the scores
+- GIVEN a candidate on the same object with different title and positions
+  THEN nothing is queued
+- GIVEN the candidate is newer than the imported tender (re-import of an old one)
+  THEN the pair is still stored later-first
+
+SCENARIO 2: Failures
+- GIVEN the candidate query fails THEN the error is only logged
+
+SCENARIO 3: Fingerprints
+- GIVEN titles differing in case, ё and punctuation THEN they normalize to the same key
+- GIVEN two tenders without positions THEN only title and object are weighed
+*/
+
+func expectFingerprint(mockStore *db.MockStore, candidates []db.ListTenderDuplicateCandidatesRow) {
+	mockStore.EXPECT().GetTenderDuplicateFingerprint(gomock.Any(), int64(20)).Return(db.GetTenderDuplicateFingerprintRow{
+		ID: 20, EtpID: "ETP-20", Title: "Монолитные работы, корпус 1", ObjectID: 3, OrganizationID: 1,
+	}, nil)
+	mockStore.EXPECT().ListTenderDuplicateCandidates(gomock.Any(), db.ListTenderDuplicateCandidatesParams{
+		Title: "Монолитные работы, корпус 1", OrganizationID: 1, TenderID: 20, ObjectID: 3,
+		MinTitleSimilarity: duplicateMinTitleSimilarity, MaxCandidates: duplicateMaxCandidates,
+	}).Return(candidates, nil)
+}
+
+func expectPositionTitles(mockStore *db.MockStore, tenderID int64, titles ...string) {
+	mockStore.EXPECT().ListTenderPositionTitles(gomock.Any(), db.ListTenderPositionTitlesParams{
+		TenderID: tenderID, MaxTitles: duplicateMaxPositionTitles,
+	}).Return(titles, nil)
+}
+
+func TestFlagProbableDuplicates(t *testing.T) {
+	t.Run("republished tender is queued", func(t *testing.T) {
+		service, mockStore := newTestService(t)
+		expectFingerprint(mockStore, []db.ListTenderDuplicateCandidatesRow{
+			{ID: 12, EtpID: "ETP-12", Title: "Монолитные работы корпус 1", ObjectID: 3, TitleSimilarity: 0.9},
+		})
+		expectPositionTitles(mockStore, 20, "бетон в15", "арматура а500с", "опалубка")
+		expectPositionTitles(mockStore, 12, "бетон в15", "арматура а500с", "опалубка щитовая")
+		mockStore.EXPECT().UpsertTenderDuplicate(gomock.Any(), db.UpsertTenderDuplicateParams{
+			TenderID: 20, DuplicateOfID: 12,
+			Score: "0.760", TitleSimilarity: "0.900", SameObject: true, PositionsSimilarity: "0.500",
+		}).Return(int64(1), nil)
+
+		service.flagProbableDuplicates(context.Background(), 20)
+	})
+
+	t.Run("same object, different works", func(t *testing.T) {
+		service, mockStore := newTestService(t)
+		expectFingerprint(mockStore, []db.ListTenderDuplicateCandidatesRow{
+			{ID: 12, EtpID: "ETP-12", Title: "Фасадные работы", ObjectID: 3, TitleSimilarity: 0.2},
+		})
+		expectPositionTitles(mockStore, 20, "бетон в15")
+		expectPositionTitles(mockStore, 12, "облицовка фасада")
+
+		service.flagProbableDuplicates(context.Background(), 20)
+	})
+
+	t.Run("newer candidate keeps later-first order", func(t *testing.T) {
+		service, mockStore := newTestService(t)
+		expectFingerprint(mockStore, []db.ListTenderDuplicateCandidatesRow{
+			{ID: 31, EtpID: "ETP-31", Title: "Монолитные работы, корпус 1", ObjectID: 3, TitleSimilarity: 1},
+		})
+		expectPositionTitles(mockStore, 20, "бетон в15")
+		expectPositionTitles(mockStore, 31, "Бетон  В15.")
+		mockStore.EXPECT().UpsertTenderDuplicate(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.UpsertTenderDuplicateParams) (int64, error) {
+				assert.Equal(t, int64(31), arg.TenderID)
+				assert.Equal(t, int64(20), arg.DuplicateOfID)
+				assert.Equal(t, "1.000", arg.Score)
+				return 1, nil
+			})
+
+		service.flagProbableDuplicates(context.Background(), 20)
+	})
+
+	t.Run("query error is only logged", func(t *testing.T) {
+		service, mockStore := newTestService(t)
+		mockStore.EXPECT().GetTenderDuplicateFingerprint(gomock.Any(), int64(20)).Return(db.GetTenderDuplicateFingerprintRow{ID: 20}, nil)
+		mockStore.EXPECT().ListTenderDuplicateCandidates(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection reset"))
+
+		service.flagProbableDuplicates(context.Background(), 20)
+	})
+}
+
+func TestNormalizePositionTitle(t *testing.T) {
+	assert.Equal(t, "устройство стяжки 50 мм", normalizePositionTitle("  Устройство стяжки (50 мм).  "))
+	assert.Equal(t, normalizePositionTitle("Щебёночная подготовка"), normalizePositionTitle("щебеночная  подготовка"))
+}
+
+func TestDuplicateScore_NoPositions(t *testing.T) {
+	score := duplicateScore(0.7, true, 0, true)
+	assert.InDelta(t, 0.8, score, 1e-9)
+	assert.InDelta(t, 0.48, duplicateScore(0.7, true, 0, false), 1e-9)
+}
@@ -3,10 +3,15 @@ package importer
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"reflect"
+	"sort"
+	"time"
 
 	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
 	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/util"
 )
 
@@ -34,14 +39,31 @@ func (s *TenderImportService) processCoreTenderData(
 		ObjectID:           dbObject.ID,
 		ExecutorID:         dbExecutor.ID,
 		DataPreparedOnDate: preparedDate,
+		OrganizationID: sql.NullInt64{
+			Int64: payload.OrganizationID,
+			Valid: payload.OrganizationID != 0,
+		},
+		EtpStatus: sql.NullString{
+			String: payload.EtpStatus,
+			Valid:  payload.EtpStatus != "",
+		},
 	}
 
 	dbTender, err := qtx.UpsertTender(ctx, tenderParams)
 	if err != nil {
+		// UpsertTender не обновляет тендер чужой организации и не возвращает строку
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewConflictErrorf(nil, "tender.other_organization", payload.TenderID)
+		}
 		return nil, fmt.Errorf("не удалось сохранить тендер: %w", err)
 	}
 
 	s.logger.Infof("Успешно сохранен тендер: ID=%d, ETP_ID=%s", dbTender.ID, dbTender.EtpID)
+	if dbTender.DeletedAt.Valid {
+		// Повторный импорт не снимает пометку удаления — только явное восстановление
+		s.logger.Warnf("Тендер ID=%d (ETP_ID=%s) помечен удалённым: данные обновлены, но тендер скрыт до восстановления",
+			dbTender.ID, dbTender.EtpID)
+	}
 	return &dbTender, nil
 }
 
@@ -51,6 +73,7 @@ func (s *TenderImportService) processCoreTenderData(
 func (s *TenderImportService) processLot(
 	ctx context.Context,
 	qtx db.Querier,
+	trace *importTrace,
 	tenderID int64,
 	lotKey string,
 	lotAPI api_models.Lot,
@@ -58,6 +81,13 @@ func (s *TenderImportService) processLot(
 	// +1 accounts for the baseline proposal
 	s.logger.Debugf("processLot: начало обработки лота %s (предложений: %d)", lotKey, len(lotAPI.ProposalData)+1)
 
+	lotStart := time.Now()
+	trace.lotTimings = append(trace.lotTimings, api_models.ImportLotTiming{
+		LotKey:         lotKey,
+		ProposalsCount: len(lotAPI.ProposalData) + 1,
+	})
+	defer func() { trace.currentLot().DurationMs = durationMs(time.Since(lotStart)) }()
+
 	// UpsertLot уже возвращает нам полную запись о лоте, включая его ID
 	dbLot, err := qtx.UpsertLot(ctx, db.UpsertLotParams{
 		TenderID: tenderID,
@@ -70,11 +100,16 @@ func (s *TenderImportService) processLot(
 	}
 	s.logger.Debugf("processLot: лот %s сохранен, DB ID: %d", lotKey, dbLot.ID)
 
+	lookups, err := s.prefetchLotLookups(ctx, qtx, trace, lotAPI)
+	if err != nil {
+		return 0, false, err
+	}
+
 	hasNewPending := false
 
 	// Обработка базового предложения
 	s.logger.Debugf("processLot: обработка базового предложения для лота %s", lotKey)
-	baselineHasNew, err := s.processProposal(ctx, qtx, dbLot.ID, &lotAPI.BaseLineProposal, true, lotAPI.LotTitle)
+	baselineHasNew, err := s.processProposal(ctx, qtx, trace, lookups, dbLot.ID, &lotAPI.BaseLineProposal, true, lotAPI.LotTitle)
 	if err != nil {
 		// Если дочерний элемент не удалось обработать, возвращаем нулевой ID и ошибку
 		return 0, false, fmt.Errorf("обработка базового предложения: %w", err)
@@ -92,7 +127,7 @@ func (s *TenderImportService) processLot(
 		s.logger.Debugf("processLot: обработка предложения %d/%d (подрядчик: %s) для лота %s",
 			proposalIdx, len(lotAPI.ProposalData), proposalDetails.Title, lotKey)
 
-		proposalHasNew, err := s.processProposal(ctx, qtx, dbLot.ID, &proposalDetails, false, lotAPI.LotTitle)
+		proposalHasNew, err := s.processProposal(ctx, qtx, trace, lookups, dbLot.ID, &proposalDetails, false, lotAPI.LotTitle)
 		if err != nil {
 			// Если дочерний элемент не удалось обработать, возвращаем нулевой ID и ошибку
 			return 0, false, fmt.Errorf("обработка предложения от '%s': %w", proposalDetails.Title, err)
@@ -107,7 +142,7 @@ func (s *TenderImportService) processLot(
 }
 
 // processProposal — унифицированный метод для обработки любого предложения
-func (s *TenderImportService) processProposal(ctx context.Context, qtx db.Querier, lotID int64, proposalAPI *api_models.ContractorProposalDetails, isBaseline bool, lotTitle string) (bool, error) {
+func (s *TenderImportService) processProposal(ctx context.Context, qtx db.Querier, trace *importTrace, lookups *positionLookups, lotID int64, proposalAPI *api_models.ContractorProposalDetails, isBaseline bool, lotTitle string) (bool, error) {
 	var inn, title, address, accreditation string
 	if isBaseline {
 		// Для базового предложения используем константы или предопределенные значения
@@ -127,18 +162,24 @@ func (s *TenderImportService) processProposal(ctx context.Context, qtx db.Querie
 		ContractorID:         dbContractor.ID,
 		IsBaseline:           isBaseline,
 		ContractorCoordinate: util.NullableString(&proposalAPI.ContractorCoordinate),
+		Currency:             proposalAPI.CurrencyCode(),
 		// ... другие поля ...
 	})
 	if err != nil {
 		return false, fmt.Errorf("не удалось сохранить предложение: %w", err)
 	}
+	// created_at и updated_at совпадают только у строки, вставленной этой
+	// транзакцией: при обновлении updated_at = NOW() текущей транзакции
+	if !isBaseline && dbProposal.CreatedAt.Equal(dbProposal.UpdatedAt) {
+		trace.newProposals++
+	}
 
 	// Вызываем уже существующие у вас публичные методы, сделав их приватными
 	if err := s.processProposalAdditionalInfo(ctx, qtx, dbProposal.ID, proposalAPI.AdditionalInfo, isBaseline); err != nil {
 		return false, err
 	}
 
-	hasNewPending, err := s.processContractorItems(ctx, qtx, dbProposal.ID, proposalAPI.ContractorItems, lotTitle)
+	hasNewPending, err := s.processContractorItems(ctx, qtx, trace, lookups, dbProposal.ID, proposalAPI.ContractorItems, lotTitle)
 	if err != nil {
 		return false, err
 	}
@@ -146,16 +187,24 @@ func (s *TenderImportService) processProposal(ctx context.Context, qtx db.Querie
 }
 
 // processContractorItems теперь только оркестрирует процесс
-func (s *TenderImportService) processContractorItems(ctx context.Context, qtx db.Querier, proposalID int64, itemsAPI api_models.ContractorItemsContainer, lotTitle string) (bool, error) {
+func (s *TenderImportService) processContractorItems(ctx context.Context, qtx db.Querier, trace *importTrace, lookups *positionLookups, proposalID int64, itemsAPI api_models.ContractorItemsContainer, lotTitle string) (bool, error) {
 	logger := s.logger.WithField("proposal_id", proposalID)
 	logger.Info("Обработка позиций и итогов")
 
 	hasNewPending := false
 
-	if itemsAPI.Positions != nil {
+	if copier, ok := qtx.(positionCopier); ok && len(itemsAPI.Positions) >= s.bulkMinPositions {
+		posHasNew, err := s.processPositionsBulk(ctx, copier, trace, lookups, proposalID, itemsAPI.Positions, lotTitle)
+		if err != nil {
+			return false, err
+		}
+		hasNewPending = posHasNew
+	} else if itemsAPI.Positions != nil {
 		for key, posAPI := range itemsAPI.Positions {
 			// Вызываем хелпер для одной позиции
-			posHasNew, err := s.processSinglePosition(ctx, qtx, proposalID, key, posAPI, lotTitle)
+			posStart := time.Now()
+			posHasNew, err := s.processSinglePosition(ctx, qtx, trace, lookups, proposalID, key, posAPI, lotTitle)
+			trace.addPosition(time.Since(posStart))
 			if err != nil {
 				// Ошибка уже залогирована внутри хелпера
 				return false, fmt.Errorf("обработка позиции '%s': %w", key, err)
@@ -176,76 +225,141 @@ func (s *TenderImportService) processContractorItems(ctx context.Context, qtx db
 		}
 	}
 	logger.Info("Итоги успешно обработаны")
+
+	if s.reconcileStaleRows {
+		if err := s.reconcileProposalItems(ctx, qtx, trace, proposalID, itemsAPI); err != nil {
+			return false, err
+		}
+	}
 	return hasNewPending, nil
 }
 
+// reconcileProposalItems удаляет позиции и итоговые строки предложения, которых
+// нет в новом payload: upsert их не трогает, и без сверки они остаются навсегда.
+func (s *TenderImportService) reconcileProposalItems(ctx context.Context, qtx db.Querier, trace *importTrace, proposalID int64, itemsAPI api_models.ContractorItemsContainer) error {
+	positionsDeleted, err := qtx.DeleteStalePositionItems(ctx, db.DeleteStalePositionItemsParams{
+		ProposalID: proposalID,
+		Keys:       sortedKeys(itemsAPI.Positions),
+	})
+	if err != nil {
+		return fmt.Errorf("не удалось удалить устаревшие позиции: %w", err)
+	}
+
+	summaryDeleted, err := qtx.DeleteStaleSummaryLines(ctx, db.DeleteStaleSummaryLinesParams{
+		ProposalID: proposalID,
+		Keys:       sortedKeys(itemsAPI.Summary),
+	})
+	if err != nil {
+		return fmt.Errorf("не удалось удалить устаревшие итоговые строки: %w", err)
+	}
+
+	trace.stalePositionsDeleted += positionsDeleted
+	trace.staleSummaryLinesDeleted += summaryDeleted
+	if positionsDeleted > 0 || summaryDeleted > 0 {
+		s.logger.WithField("proposal_id", proposalID).
+			Infof("Сверка: удалено устаревших позиций %d, итоговых строк %d", positionsDeleted, summaryDeleted)
+	}
+	return nil
+}
+
 // processSinglePosition обрабатывает одну позицию
 func (s *TenderImportService) processSinglePosition(
 	ctx context.Context,
 	qtx db.Querier,
+	trace *importTrace,
+	lookups *positionLookups,
 	proposalID int64,
 	positionKey string,
 	posAPI api_models.PositionItem,
 	lotTitle string,
 ) (bool, error) {
+	params, isNewPendingItem, ok, err := s.preparePosition(ctx, qtx, trace, lookups, proposalID, positionKey, posAPI, lotTitle)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	// 3. Выполнение запроса
+	if _, err := qtx.UpsertPositionItem(ctx, params); err != nil {
+		s.logger.WithField("position_key", positionKey).Errorf("Не удалось сохранить позицию: %v", err)
+		return false, fmt.Errorf("не удалось сохранить позицию: %w", err)
+	}
+	return isNewPendingItem, nil
+}
+
+// preparePosition готовит параметры UpsertPositionItem: создаёт единицу измерения
+// и позицию каталога, проверяет matching_cache. ok=false — позицию нужно пропустить.
+func (s *TenderImportService) preparePosition(
+	ctx context.Context,
+	qtx db.Querier,
+	trace *importTrace,
+	lookups *positionLookups,
+	proposalID int64,
+	positionKey string,
+	posAPI api_models.PositionItem,
+	lotTitle string,
+) (params db.UpsertPositionItemParams, isNewPendingItem bool, ok bool, err error) {
 	// 1. Получаем зависимости
-	unitID, err := s.Entities.GetOrCreateUnitOfMeasurement(ctx, qtx, posAPI.Unit)
+	unitID, err := s.resolveUnit(ctx, qtx, lookups, posAPI.Unit)
 	if err != nil {
-		return false, fmt.Errorf("не удалось получить/создать единицу измерения: %w", err)
+		return params, false, false, fmt.Errorf("не удалось получить/создать единицу измерения: %w", err)
 	}
 
-	catPos, isNewPendingItem, err := s.Entities.GetOrCreateCatalogPosition(ctx, qtx, posAPI, lotTitle, unitID)
+	catPos, isNewPendingItem, err := s.resolveCatalogPosition(ctx, qtx, lookups, posAPI, lotTitle, unitID)
 	if err != nil {
-		return false, fmt.Errorf("не удалось получить/создать позицию каталога: %w", err)
+		return params, false, false, fmt.Errorf("не удалось получить/создать позицию каталога: %w", err)
 	}
 
 	if catPos.ID == 0 {
 		s.logger.Warnf("Позиция каталога не была создана (возможно, пустой заголовок), пропуск: %s", posAPI.JobTitle)
-		return false, nil
+		return params, false, false, nil
 	}
 
 	var finalCatalogPositionID sql.NullInt64
 
+	// Выведенная из оборота позиция каталога заменяется своей заменой
+	catalogPositionID := catPos.ID
+	if catPos.ReplacedByID.Valid {
+		catalogPositionID = catPos.ReplacedByID.Int64
+	}
+
 	if catPos.Kind != "POSITION" {
 		// Заголовки (HEADER, LOT_HEADER) сразу привязываем
-		finalCatalogPositionID = sql.NullInt64{Int64: catPos.ID, Valid: true}
+		finalCatalogPositionID = sql.NullInt64{Int64: catalogPositionID, Valid: true}
 	} else {
 		// Для POSITION проверяем кэш
 		hashKey := util.GetSHA256Hash(catPos.StandardJobTitle)
-		const currentNormVersion = 1
 
-		cachedMatch, err := qtx.GetMatchingCache(ctx, db.GetMatchingCacheParams{
-			JobTitleHash: hashKey,
-			NormVersion:  currentNormVersion,
-		})
+		// Кэш читается только в активной версии нормализации (system_settings.norm_version)
+		lookupStart := time.Now()
+		cachedCatalogPositionID, err := s.activeMatchingCache(ctx, qtx, lookups, hashKey)
+		trace.cacheLookup += time.Since(lookupStart)
 
 		switch err {
 		case nil:
 			// === CACHE HIT ===
 			// Отлично, Python-воркер уже сделал работу.
-			finalCatalogPositionID = sql.NullInt64{Int64: cachedMatch.CatalogPositionID, Valid: true}
+			trace.cacheHits++
+			finalCatalogPositionID = sql.NullInt64{Int64: cachedCatalogPositionID, Valid: true}
 
 		case sql.ErrNoRows:
 			// === CACHE MISS ===
 			// НОВАЯ СТРАТЕГИЯ: Сохраняем ID новой позиции (draft_catalog_id)
 			// Это позволяет Python использовать его как Fallback, если RAG не найдет лучшего варианта
-			finalCatalogPositionID = sql.NullInt64{Int64: catPos.ID, Valid: true}
+			trace.cacheMisses++
+			finalCatalogPositionID = sql.NullInt64{Int64: catalogPositionID, Valid: true}
 
 		default:
 			// Другая, неожиданная ошибка БД
-			return false, fmt.Errorf("ошибка чтения matching_cache: %w", err)
+			return params, false, false, fmt.Errorf("ошибка чтения matching_cache: %w", err)
 		}
 	}
 
 	// 2. Маппинг данных
-	params := mapApiPositionToDbParams(proposalID, positionKey, finalCatalogPositionID, unitID, posAPI)
-
-	// 3. Выполнение запроса
-	if _, err := qtx.UpsertPositionItem(ctx, params); err != nil {
-		s.logger.WithField("position_key", positionKey).Errorf("Не удалось сохранить позицию: %v", err)
-		return false, fmt.Errorf("не удалось сохранить позицию: %w", err)
+	params = mapApiPositionToDbParams(proposalID, positionKey, finalCatalogPositionID, unitID, posAPI)
+	if unmapped := unmappedPositionFields(posAPI); len(unmapped) > 0 {
+		s.logger.WithField("position_key", positionKey).Warnf("Поля позиции не сохраняются в БД (нет колонки): %v", unmapped)
 	}
-	return isNewPendingItem, nil
+	return params, isNewPendingItem, true, nil
 }
 
 // processSingleSummaryLine обрабатывает одну строку итога.
@@ -309,6 +423,51 @@ func (s *TenderImportService) processProposalAdditionalInfo(
 	return nil
 }
 
+// positionFieldDestinations описывает, куда при импорте попадает каждое поле api_models.PositionItem.
+// Поле, которого здесь нет, считается несохраняемым: импорт пишет предупреждение
+// (см. unmappedPositionFields), а тест маппера падает. Так новое поле парсера
+// не сможет молча потеряться, как раньше article_smr и deviation_from_baseline_cost.
+var positionFieldDestinations = map[string]string{
+	"Number":                        "position_items.item_number_in_proposal",
+	"ChapterNumber":                 "position_items.chapter_number_in_proposal",
+	"ArticleSMR":                    "position_items.article_smr",
+	"JobTitle":                      "position_items.job_title_in_proposal",
+	"CommentOrganizer":              "position_items.comment_organazier",
+	"Unit":                          "position_items.unit_id (через units_of_measurement)",
+	"Quantity":                      "position_items.quantity",
+	"SuggestedQuantity":             "position_items.suggested_quantity",
+	"UnitCost":                      "position_items.unit_cost_*",
+	"TotalCost":                     "position_items.total_cost_*",
+	"TotalCostForOrganizerQuantity": "position_items.total_cost_for_organizer_quantity",
+	"DeviationFromBaselineCost":     "position_items.deviation_from_baseline_cost",
+	"CommentContractor":             "position_items.comment_contractor",
+	"JobTitleNormalized":            "catalog_positions.standard_job_title (через EntityManager)",
+	"IsChapter":                     "position_items.is_chapter",
+	"ChapterRef":                    "position_items.chapter_ref_in_proposal",
+	"Currency":                      "position_items.currency",
+}
+
+// unmappedPositionFields возвращает имена заполненных полей позиции, для которых
+// нет места в БД (отсортированы для стабильного лога). Пустые поля не учитываются.
+func unmappedPositionFields(posAPI api_models.PositionItem) []string {
+	v := reflect.ValueOf(posAPI)
+	t := v.Type()
+
+	var unmapped []string
+	for i := 0; i < t.NumField(); i++ {
+		if _, ok := positionFieldDestinations[t.Field(i).Name]; ok {
+			continue
+		}
+		if !v.Field(i).IsZero() {
+			unmapped = append(unmapped, t.Field(i).Name)
+		}
+	}
+	sort.Strings(unmapped)
+	return unmapped
+}
+
+// mapApiPositionToDbParams преобразует API-модель позиции в параметры для sqlc.
+// Это чистая функция без побочных эффектов; nil в API всегда превращается в NULL.
 func mapApiPositionToDbParams(
 	proposalID int64,
 	positionKey string,
@@ -326,20 +485,22 @@ func mapApiPositionToDbParams(
 		ChapterNumberInProposal:       util.NullableString(posAPI.ChapterNumber),
 		JobTitleInProposal:            posAPI.JobTitle,
 		UnitID:                        unitID, // sql.NullInt64
-		Quantity:                      util.ConvertNullFloat64ToNullString(util.NullableFloat64(posAPI.Quantity)),
-		SuggestedQuantity:             util.ConvertNullFloat64ToNullString(util.NullableFloat64(posAPI.SuggestedQuantity)),
-		TotalCostForOrganizerQuantity: util.ConvertNullFloat64ToNullString(util.NullableFloat64(posAPI.TotalCostForOrganizerQuantity)),
-		UnitCostMaterials:             util.ConvertNullFloat64ToNullString(util.NullableFloat64(posAPI.UnitCost.Materials)),
-		UnitCostWorks:                 util.ConvertNullFloat64ToNullString(util.NullableFloat64(posAPI.UnitCost.Works)),
-		UnitCostIndirectCosts:         util.ConvertNullFloat64ToNullString(util.NullableFloat64(posAPI.UnitCost.IndirectCosts)),
-		UnitCostTotal:                 util.ConvertNullFloat64ToNullString(util.NullableFloat64(posAPI.UnitCost.Total)),
-		TotalCostMaterials:            util.ConvertNullFloat64ToNullString(util.NullableFloat64(posAPI.TotalCost.Materials)),
-		TotalCostWorks:                util.ConvertNullFloat64ToNullString(util.NullableFloat64(posAPI.TotalCost.Works)),
-		TotalCostIndirectCosts:        util.ConvertNullFloat64ToNullString(util.NullableFloat64(posAPI.TotalCost.IndirectCosts)),
-		TotalCostTotal:                util.ConvertNullFloat64ToNullString(util.NullableFloat64(posAPI.TotalCost.Total)), // Убедитесь, что это поле nullable в таблице
-		DeviationFromBaselineCost:     util.ConvertNullFloat64ToNullString(util.NullableFloat64(nil)),                    // Заполните из posAPI, если есть
+		Quantity:                      util.NullableDecimal(posAPI.Quantity),
+		SuggestedQuantity:             util.NullableDecimal(posAPI.SuggestedQuantity),
+		TotalCostForOrganizerQuantity: util.NullableMoney(posAPI.TotalCostForOrganizerQuantity),
+		UnitCostMaterials:             util.NullableMoney(posAPI.UnitCost.Materials),
+		UnitCostWorks:                 util.NullableMoney(posAPI.UnitCost.Works),
+		UnitCostIndirectCosts:         util.NullableMoney(posAPI.UnitCost.IndirectCosts),
+		UnitCostTotal:                 util.NullableMoney(posAPI.UnitCost.Total),
+		TotalCostMaterials:            util.NullableMoney(posAPI.TotalCost.Materials),
+		TotalCostWorks:                util.NullableMoney(posAPI.TotalCost.Works),
+		TotalCostIndirectCosts:        util.NullableMoney(posAPI.TotalCost.IndirectCosts),
+		TotalCostTotal:                util.NullableMoney(posAPI.TotalCost.Total), // Убедитесь, что это поле nullable в таблице
+		DeviationFromBaselineCost:     util.NullableMoney(posAPI.DeviationFromBaselineCost),
 		IsChapter:                     posAPI.IsChapter,
 		ChapterRefInProposal:          util.NullableString(posAPI.ChapterRef),
+		ArticleSmr:                    util.NullableString(posAPI.ArticleSMR),
+		Currency:                      lineCurrency(posAPI.Currency),
 	}
 }
 
@@ -359,9 +520,23 @@ func mapApiSummaryToDbParams(
 		JobTitle: sumLineAPI.JobTitle,
 
 		// Данные из TotalCost (для summary обычно используется TotalCost, а не UnitCost)
-		MaterialsCost:     util.ConvertNullFloat64ToNullString(util.NullableFloat64(sumLineAPI.TotalCost.Materials)),
-		WorksCost:         util.ConvertNullFloat64ToNullString(util.NullableFloat64(sumLineAPI.TotalCost.Works)),
-		IndirectCostsCost: util.ConvertNullFloat64ToNullString(util.NullableFloat64(sumLineAPI.TotalCost.IndirectCosts)),
-		TotalCost:         util.ConvertNullFloat64ToNullString(util.NullableFloat64(sumLineAPI.TotalCost.Total)),
+		MaterialsCost:     util.NullableMoney(sumLineAPI.TotalCost.Materials),
+		WorksCost:         util.NullableMoney(sumLineAPI.TotalCost.Works),
+		IndirectCostsCost: util.NullableMoney(sumLineAPI.TotalCost.IndirectCosts),
+		TotalCost:         util.NullableMoney(sumLineAPI.TotalCost.Total),
+
+		// Валюта строки; NULL — валюта предложения
+		Currency: lineCurrency(sumLineAPI.Currency),
+	}
+}
+
+// lineCurrency приводит валюту строки к коду ISO 4217. Пустое значение означает
+// «валюта предложения» и сохраняется как NULL. Некорректный код сюда не доходит:
+// его отсекает ContractorProposalDetails.Validate.
+func lineCurrency(code string) sql.NullString {
+	normalized, err := api_models.NormalizeCurrency(code)
+	if err != nil || normalized == "" {
+		return sql.NullString{}
 	}
+	return sql.NullString{String: normalized, Valid: true}
 }
@@ -0,0 +1,112 @@
+package importer
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/diffing"
+)
+
+// payloadHash возвращает SHA-256 (hex) тела запроса импорта. По нему видно,
+// что парсер прислал тот же самый JSON, без сравнения снимков целиком.
+func payloadHash(rawJSON []byte) string {
+	sum := sha256.Sum256(rawJSON)
+	return hex.EncodeToString(sum[:])
+}
+
+// saveRawDataVersion добавляет исходный JSON в историю импортов тендера.
+// Вызывается в транзакции импорта после UpsertTender, который блокирует строку
+// тендера, поэтому номер версии не пересекается с параллельным импортом.
+func (s *TenderImportService) saveRawDataVersion(ctx context.Context, qtx db.Querier, tenderID int64, rawJSON []byte) error {
+	version, err := qtx.CreateTenderRawDataVersion(ctx, db.CreateTenderRawDataVersionParams{
+		TenderID:     tenderID,
+		RawData:      json.RawMessage(rawJSON),
+		PayloadHash:  payloadHash(rawJSON),
+		PayloadBytes: int64(len(rawJSON)),
+	})
+	if err != nil {
+		s.logger.Errorf("Ошибка при сохранении версии исходного JSON для тендера ID %d: %v", tenderID, err)
+		return fmt.Errorf("не удалось сохранить историю исходного JSON: %w", err)
+	}
+	s.logger.Debugf("Исходный JSON тендера ID %d сохранён как версия %d (sha256=%s)", tenderID, version.Version, version.PayloadHash)
+	return nil
+}
+
+// ListTenderImports возвращает версии исходного JSON тендера, новые первыми.
+// У тендера, импортированного хотя бы раз, есть минимум одна версия, поэтому
+// пустая история означает, что тендера нет.
+func (s *TenderImportService) ListTenderImports(ctx context.Context, tenderID int64) (*api_models.TenderImportsResponse, error) {
+	rows, err := s.store.ListTenderRawDataVersions(ctx, tenderID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения истории импортов тендера: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, apierrors.NewNotFoundError("import.history_not_found", tenderID)
+	}
+
+	versions := make([]api_models.TenderImportVersion, 0, len(rows))
+	for _, row := range rows {
+		versions = append(versions, api_models.TenderImportVersion{
+			Version:      row.Version,
+			PayloadHash:  row.PayloadHash,
+			PayloadBytes: row.PayloadBytes,
+			ImportedAt:   row.ImportedAt,
+		})
+	}
+	return &api_models.TenderImportsResponse{TenderID: tenderID, Versions: versions}, nil
+}
+
+// GetTenderImportRaw возвращает снимок исходного JSON тендера указанной версии.
+func (s *TenderImportService) GetTenderImportRaw(ctx context.Context, tenderID int64, version int32) (json.RawMessage, error) {
+	row, err := s.store.GetTenderRawDataVersion(ctx, db.GetTenderRawDataVersionParams{
+		TenderID: tenderID,
+		Version:  version,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewNotFoundError("import.raw_version_not_found", version, tenderID)
+		}
+		return nil, fmt.Errorf("ошибка получения версии исходного JSON: %w", err)
+	}
+	return row.RawData, nil
+}
+
+// DiffTenderImports сравнивает две сохранённые версии исходного JSON тендера.
+// Версии разбираются в FullTenderData так же, как при импорте, поэтому в отчёт
+// не попадают поля, которые импорт не читает.
+func (s *TenderImportService) DiffTenderImports(ctx context.Context, tenderID int64, fromVersion, toVersion int32) (*api_models.TenderImportDiff, error) {
+	from, err := s.loadImportVersion(ctx, tenderID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.loadImportVersion(ctx, tenderID, toVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := diffing.CompareTenders(from, to)
+	diff.TenderID = tenderID
+	diff.FromVersion = fromVersion
+	diff.ToVersion = toVersion
+	return diff, nil
+}
+
+func (s *TenderImportService) loadImportVersion(ctx context.Context, tenderID int64, version int32) (*api_models.FullTenderData, error) {
+	raw, err := s.GetTenderImportRaw(ctx, tenderID, version)
+	if err != nil {
+		return nil, err
+	}
+	var payload api_models.FullTenderData
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("некорректный JSON версии %d тендера %d: %w", version, tenderID, err)
+	}
+	return &payload, nil
+}
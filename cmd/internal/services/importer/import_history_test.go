@@ -0,0 +1,158 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR TENDER IMPORT HISTORY (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Lost evidence — a re-import must not destroy the previous raw JSON, otherwise
+   a parser regression cannot be reproduced
+2. Broken import — failing to write history must abort the import like any other step
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: ImportFullTender — history insert fails → transaction aborted
+SCENARIO 2: ListTenderImports
+- GIVEN stored versions → mapped newest first
+- GIVEN no versions → NotFoundError
+SCENARIO 3: GetTenderImportRaw
+- GIVEN a stored version → raw JSON
+- GIVEN a missing version → NotFoundError
+SCENARIO 4: payloadHash — SHA-256 hex of the request body
+SCENARIO 5: DiffTenderImports
+- GIVEN two stored versions → diff with tender ID and versions filled in
+- GIVEN a missing "to" version → NotFoundError
+*/
+
+func TestImportFullTender_HistoryInsertFails_ReturnsError(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	rawJSON := []byte(`{"tender_id":"ETP-TEST-001"}`)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			setupCoreTenderExpectations(mock)
+			mock.ExpectQuery("INSERT INTO tender_raw_data ").
+				WillReturnRows(sqlmock.NewRows(tenderRawColumns).
+					AddRow(int64(100), json.RawMessage(`{}`), now, now))
+			mock.ExpectQuery("INSERT INTO tender_raw_data_history").
+				WithArgs(int64(100), sqlmock.AnyArg(), payloadHash(rawJSON), int64(len(rawJSON))).
+				WillReturnError(errors.New("disk full"))
+		}),
+	)
+
+	tenderID, _, _, _, err := service.ImportFullTender(context.Background(), makeMinimalPayload(), rawJSON)
+
+	require.Error(t, err)
+	assert.Equal(t, int64(0), tenderID)
+	assert.Contains(t, err.Error(), "историю исходного JSON")
+}
+
+func TestListTenderImports_Found(t *testing.T) {
+	service, mockStore := newTestService(t)
+
+	mockStore.EXPECT().ListTenderRawDataVersions(gomock.Any(), int64(100)).Return([]db.ListTenderRawDataVersionsRow{
+		{TenderID: 100, Version: 2, PayloadHash: "bb", PayloadBytes: 2048, ImportedAt: now},
+		{TenderID: 100, Version: 1, PayloadHash: "aa", PayloadBytes: 1024, ImportedAt: now},
+	}, nil)
+
+	resp, err := service.ListTenderImports(context.Background(), 100)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), resp.TenderID)
+	require.Len(t, resp.Versions, 2)
+	assert.Equal(t, int32(2), resp.Versions[0].Version)
+	assert.Equal(t, "bb", resp.Versions[0].PayloadHash)
+	assert.Equal(t, int64(1024), resp.Versions[1].PayloadBytes)
+}
+
+func TestListTenderImports_Empty_NotFound(t *testing.T) {
+	service, mockStore := newTestService(t)
+
+	mockStore.EXPECT().ListTenderRawDataVersions(gomock.Any(), int64(404)).Return([]db.ListTenderRawDataVersionsRow{}, nil)
+
+	resp, err := service.ListTenderImports(context.Background(), 404)
+
+	assert.Nil(t, resp)
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestGetTenderImportRaw_Found(t *testing.T) {
+	service, mockStore := newTestService(t)
+
+	mockStore.EXPECT().GetTenderRawDataVersion(gomock.Any(), db.GetTenderRawDataVersionParams{TenderID: 100, Version: 1}).
+		Return(db.TenderRawDataHistory{TenderID: 100, Version: 1, RawData: json.RawMessage(`{"tender_id":"1"}`)}, nil)
+
+	raw, err := service.GetTenderImportRaw(context.Background(), 100, 1)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"tender_id":"1"}`, string(raw))
+}
+
+func TestGetTenderImportRaw_NotFound(t *testing.T) {
+	service, mockStore := newTestService(t)
+
+	mockStore.EXPECT().GetTenderRawDataVersion(gomock.Any(), gomock.Any()).
+		Return(db.TenderRawDataHistory{}, sql.ErrNoRows)
+
+	raw, err := service.GetTenderImportRaw(context.Background(), 100, 7)
+
+	assert.Nil(t, raw)
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestPayloadHash(t *testing.T) {
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", payloadHash(nil))
+	assert.NotEqual(t, payloadHash([]byte(`{"a":1}`)), payloadHash([]byte(`{"a": 1}`)))
+}
+
+func TestDiffTenderImports_ComparesVersions(t *testing.T) {
+	service, mockStore := newTestService(t)
+
+	mockStore.EXPECT().GetTenderRawDataVersion(gomock.Any(), db.GetTenderRawDataVersionParams{TenderID: 100, Version: 1}).
+		Return(db.TenderRawDataHistory{RawData: json.RawMessage(`{"tender_title":"A","lots":{"lot_1":{"lot_title":"Лот"}}}`)}, nil)
+	mockStore.EXPECT().GetTenderRawDataVersion(gomock.Any(), db.GetTenderRawDataVersionParams{TenderID: 100, Version: 3}).
+		Return(db.TenderRawDataHistory{RawData: json.RawMessage(`{"tender_title":"B","lots":{}}`)}, nil)
+
+	diff, err := service.DiffTenderImports(context.Background(), 100, 1, 3)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), diff.TenderID)
+	assert.Equal(t, int32(1), diff.FromVersion)
+	assert.Equal(t, int32(3), diff.ToVersion)
+	assert.False(t, diff.Identical)
+	assert.Equal(t, []string{"lot_1"}, diff.LotsRemoved)
+}
+
+func TestDiffTenderImports_MissingVersion_NotFound(t *testing.T) {
+	service, mockStore := newTestService(t)
+
+	mockStore.EXPECT().GetTenderRawDataVersion(gomock.Any(), db.GetTenderRawDataVersionParams{TenderID: 100, Version: 1}).
+		Return(db.TenderRawDataHistory{RawData: json.RawMessage(`{}`)}, nil)
+	mockStore.EXPECT().GetTenderRawDataVersion(gomock.Any(), db.GetTenderRawDataVersionParams{TenderID: 100, Version: 9}).
+		Return(db.TenderRawDataHistory{}, sql.ErrNoRows)
+
+	diff, err := service.DiffTenderImports(context.Background(), 100, 1, 9)
+
+	assert.Nil(t, diff)
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
@@ -2,12 +2,20 @@ package importer
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
 	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/entities"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/notifications"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/outbox"
 	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
 )
 
@@ -19,20 +27,77 @@ type TenderImportService struct {
 
 	// Единственная зависимость - менеджер сущностей
 	Entities *entities.EntityManager
+
+	// Удалять позиции и итоговые строки, пропавшие из повторно импортируемого тендера
+	reconcileStaleRows bool
+
+	publisher events.Publisher
+	// Оповещения об ошибках импорта по почте; nil — отключены
+	notifier *notifications.Service
+
+	// Соединение для транзакций bulk-импорта позиций (см. execTx) и
+	// блокировки параллельного импорта (см. lockTenderImport)
+	conn *sql.DB
+	// Минимальное число позиций предложения для bulk-сохранения; 0 — bulk выключен
+	bulkMinPositions int
+
+	// Записывать события для Python-воркера в outbox внутри транзакции импорта
+	outboxEnabled bool
+
+	// Искать вероятные дубликаты тендера после импорта (см. duplicates.go)
+	detectDuplicates bool
+
+	// Лотов, сохраняемых параллельно в отдельных транзакциях; 1 — весь тендер
+	// в одной транзакции (см. parallel_lots.go)
+	lotWorkers int
+	// Места для одновременных параллельных импортов: каждый занимает
+	// соединение блокировки и до lotWorkers соединений воркеров. nil — пул
+	// без ограничения (см. lockTenderImport)
+	importSlots chan struct{}
+
+	// Загружать справочники позиций лота заранее (см. lookups.go)
+	prefetchLookups bool
 }
 
 // NewTenderImportService создает новый экземпляр TenderImportService.
-// Получает все зависимости извне (Dependency Injection).
+// Получает все зависимости извне (Dependency Injection). conn нужен только для
+// import.bulk_positions и import.lot_workers: без него позиции сохраняются
+// построчно, а тендер — в одной транзакции. notifier
+// может быть nil — тогда об ошибках импорта сообщает только лог. Без
+// outbox.worker_callback_url события воркеру в outbox не пишутся.
 func NewTenderImportService(
 	store db.Store,
+	conn *sql.DB,
 	logger logging.Logger,
 	entityManager *entities.EntityManager,
+	importCfg config.ImportConfig,
+	outboxCfg config.OutboxConfig,
+	publisher events.Publisher,
+	notifier *notifications.Service,
 ) *TenderImportService {
-	return &TenderImportService{
-		store:    store,
-		logger:   logger,
-		Entities: entityManager,
+	service := &TenderImportService{
+		store:              store,
+		logger:             logger,
+		Entities:           entityManager,
+		reconcileStaleRows: importCfg.ReconcileStaleRows,
+		publisher:          publisher,
+		notifier:           notifier,
+		conn:               conn,
+		outboxEnabled:      outboxCfg.Enabled(),
+		detectDuplicates:   importCfg.DetectDuplicates,
+		lotWorkers:         1,
+		prefetchLookups:    importCfg.PrefetchLookups,
+	}
+	if importCfg.BulkPositions && conn != nil {
+		service.bulkMinPositions = importCfg.BulkMinPositions
+	}
+	if conn != nil {
+		service.lotWorkers = max(importCfg.LotWorkers, 1)
+		if maxOpen := conn.Stats().MaxOpenConnections; service.lotWorkers > 1 && maxOpen > 0 {
+			service.importSlots = make(chan struct{}, max(maxOpen/(service.lotWorkers+1), 1))
+		}
 	}
+	return service
 }
 
 // ImportFullTender выполняет полный импорт тендера из API-модели и сохраняет "сырой" JSON.
@@ -42,7 +107,16 @@ func NewTenderImportService(
 //  1. Импортирует основную информацию о тендере и связанные сущности (лоты и т.д.).
 //  2. После успешного импорта делает UPSERT исходного JSON в таблицу tender_raw_data.
 //     Перезапись допускается и желательна: при повторной загрузке данные полностью обновляются.
-//  3. При любой ошибке в транзакции изменения откатываются.
+//     Каждая загрузка дополнительно сохраняется новой версией в tender_raw_data_history.
+//  3. Если включена сверка (import.reconcile_stale_rows), у каждого предложения удаляются
+//     позиции и итоговые строки, ключей которых нет в payload.
+//  4. Если появились новые pending-позиции каталога и outbox включён, в той же
+//     транзакции записывается событие catalog.items_pending для RAG-воркера.
+//  5. При любой ошибке в транзакции изменения откатываются.
+//
+// При import.lot_workers > 1 тендер из нескольких лотов импортируется
+// importFullTenderParallel: каждый лот в своей транзакции, параллельно.
+// Импорты одного тендера в этом режиме выполняются по очереди.
 //
 // Аргументы:
 //   - ctx: контекст запроса (таймаут/отмена)
@@ -52,26 +126,51 @@ func NewTenderImportService(
 // Возвращает:
 //   - ID тендера в БД,
 //   - map[lotKey]lotDBID для всех созданных/обновлённых лотов,
+//   - признак появления новых pending-позиций каталога,
+//   - ID трассировки импорта в import_metrics (0, если её не удалось сохранить),
 //   - ошибку (nil при успехе).
+//
+// Тайминги этапов сохраняются после транзакции — и при успехе, и при ошибке.
 func (s *TenderImportService) ImportFullTender(
 	ctx context.Context,
 	payload *api_models.FullTenderData,
 	rawJSON []byte,
-) (int64, map[string]int64, bool, error) {
+) (int64, map[string]int64, bool, int64, error) {
 
 	s.logger.Infof("Начинаем импорт тендера %s, размер JSON: %d байт, количество лотов: %d",
 		payload.TenderID, len(rawJSON), len(payload.LotsData))
 
+	if s.lotWorkers > 1 {
+		// Параллельный импорт коммитит тендер по частям, поэтому импорты
+		// одного тендера выполняются по очереди под блокировкой (см. lockTenderImport)
+		unlock, err := s.lockTenderImport(ctx, payload.TenderID)
+		if err != nil {
+			s.logger.Errorf("Импорт тендера %s не начат: %v", payload.TenderID, err)
+			importID, err := s.finishImport(ctx, newImportTrace(), time.Time{}, payload.TenderID, 0, len(rawJSON), nil, false, err)
+			return 0, nil, false, importID, err
+		}
+		defer unlock()
+		if len(payload.LotsData) > 1 {
+			return s.importFullTenderParallel(ctx, payload, rawJSON)
+		}
+	}
+
 	var newTenderDBID int64
 	lotIDs := make(map[string]int64)
 	anyNewPendingItems := false
+	trace := newImportTrace()
+	var callbackDone time.Time
 
-	txErr := s.store.ExecTx(ctx, func(qtx *db.Queries) error {
+	txErr := s.execTx(ctx, func(qtx db.Querier) error {
+		trace.txWait = time.Since(trace.startedAt)
+		defer func() { callbackDone = time.Now() }()
 		s.logger.Debug("Транзакция начата")
 
 		// Шаг 1: Обработка основной информации о тендере
 		s.logger.Debug("Шаг 1: Обработка основной информации о тендере")
+		stepStart := time.Now()
 		dbTender, err := s.processCoreTenderData(ctx, qtx, payload)
+		trace.core = time.Since(stepStart)
 		if err != nil {
 			s.logger.Errorf("Ошибка на шаге 1: %v", err)
 			return err
@@ -82,11 +181,13 @@ func (s *TenderImportService) ImportFullTender(
 		// Шаг 2: Обработка лотов
 		// Примечание: порядок итерации по map не детерминирован
 		s.logger.Debugf("Шаг 2: Обработка %d лотов", len(payload.LotsData))
+		lotsStart := time.Now()
 		for lotKey, lotAPI := range payload.LotsData {
 			s.logger.Debugf("Обрабатываем лот (ключ: %s)", lotKey)
 
-			lotDBID, lotHasNewPending, err := s.processLot(ctx, qtx, dbTender.ID, lotKey, lotAPI)
+			lotDBID, lotHasNewPending, err := s.processLot(ctx, qtx, trace, dbTender.ID, lotKey, lotAPI)
 			if err != nil {
+				trace.lots = time.Since(lotsStart)
 				s.logger.Errorf("Ошибка при обработке лота '%s': %v", lotKey, err)
 				return fmt.Errorf("ошибка при обработке лота '%s': %w", lotKey, err)
 			}
@@ -95,31 +196,142 @@ func (s *TenderImportService) ImportFullTender(
 				anyNewPendingItems = true
 			}
 			s.logger.Debugf("Лот %s обработан, DB ID: %d", lotKey, lotDBID)
+			reportProgress(ctx, payload.TenderID, len(lotIDs), len(payload.LotsData))
 		}
+		trace.lots = time.Since(lotsStart)
 		s.logger.Debug("Все лоты обработаны успешно")
 
 		// Шаг 3: UPSERT "сырого" JSON в tender_raw_data в рамках той же транзакции.
-		// sqlc сгенерировал тип параметра как json.RawMessage — передаём rawJSON как есть.
-		s.logger.Debugf("Шаг 3: Сохраняем исходный JSON для тендера ID: %d (размер: %d байт)", newTenderDBID, len(rawJSON))
-		if _, err := qtx.UpsertTenderRawData(ctx, db.UpsertTenderRawDataParams{
-			TenderID: newTenderDBID,
-			RawData:  json.RawMessage(rawJSON),
-		}); err != nil {
-			s.logger.Errorf("Ошибка при сохранении tender_raw_data для тендера ID %d: %v", newTenderDBID, err)
-			return fmt.Errorf("не удалось сохранить исходный JSON (tender_raw_data): %w", err)
+		if err := s.saveRawJSON(ctx, qtx, trace, newTenderDBID, rawJSON); err != nil {
+			return err
+		}
+		if err := s.enqueueCatalogItemsPending(ctx, qtx, payload.TenderID, newTenderDBID, lotIDs, anyNewPendingItems); err != nil {
+			return err
 		}
-		s.logger.Debugf("Исходный JSON успешно сохранен для тендера ID: %d", newTenderDBID)
 		s.logger.Debug("Callback завершен, выполняем коммит транзакции")
 
 		return nil // транзакция завершится успешно
 	})
 
+	importID, err := s.finishImport(ctx, trace, callbackDone, payload.TenderID, newTenderDBID, len(rawJSON), lotIDs, anyNewPendingItems, txErr)
+	if err != nil {
+		return 0, nil, false, importID, err
+	}
+	return newTenderDBID, lotIDs, anyNewPendingItems, importID, nil
+}
+
+// saveRawJSON делает UPSERT исходного JSON в tender_raw_data и сохраняет его
+// новой версией в истории. Вызывается внутри транзакции импорта.
+func (s *TenderImportService) saveRawJSON(ctx context.Context, qtx db.Querier, trace *importTrace, tenderDBID int64, rawJSON []byte) error {
+	// sqlc сгенерировал тип параметра как json.RawMessage — передаём rawJSON как есть.
+	s.logger.Debugf("Шаг 3: Сохраняем исходный JSON для тендера ID: %d (размер: %d байт)", tenderDBID, len(rawJSON))
+	stepStart := time.Now()
+	_, err := qtx.UpsertTenderRawData(ctx, db.UpsertTenderRawDataParams{
+		TenderID: tenderDBID,
+		RawData:  json.RawMessage(rawJSON),
+	})
+	if err != nil {
+		trace.rawData = time.Since(stepStart)
+		s.logger.Errorf("Ошибка при сохранении tender_raw_data для тендера ID %d: %v", tenderDBID, err)
+		return fmt.Errorf("не удалось сохранить исходный JSON (tender_raw_data): %w", err)
+	}
+	// История хранит каждую загрузку: tender_raw_data содержит только последнюю
+	err = s.saveRawDataVersion(ctx, qtx, tenderDBID, rawJSON)
+	trace.rawData = time.Since(stepStart)
+	if err != nil {
+		return err
+	}
+	s.logger.Debugf("Исходный JSON успешно сохранен для тендера ID: %d", tenderDBID)
+	return nil
+}
+
+// enqueueCatalogItemsPending записывает в outbox событие catalog.items_pending,
+// если импорт создал новые pending-позиции каталога. Вызывается внутри
+// транзакции импорта: событие фиксируется вместе с позициями, и воркер узнает
+// о них, даже если ответ на импорт потеряется.
+func (s *TenderImportService) enqueueCatalogItemsPending(
+	ctx context.Context,
+	qtx db.Querier,
+	etpID string,
+	tenderDBID int64,
+	lotIDs map[string]int64,
+	anyNewPendingItems bool,
+) error {
+	if !s.outboxEnabled || !anyNewPendingItems {
+		return nil
+	}
+	eventID, err := outbox.Enqueue(ctx, qtx, outbox.EventCatalogItemsPending, tenderDBID, outbox.CatalogItemsPendingData{
+		TenderDBID: tenderDBID,
+		TenderID:   etpID,
+		LotIDsMap:  lotIDs,
+	})
+	if err != nil {
+		return err
+	}
+	s.logger.Debugf("Событие %s записано в outbox (id=%d)", outbox.EventCatalogItemsPending, eventID)
+	return nil
+}
+
+// finishImport завершает импорт после ExecTx: сохраняет тайминги, логирует
+// результат, публикует событие tender.imported и ищет вероятные дубликаты
+// тендера (import.detect_duplicates). Возвращает import_id и
+// обёрнутую ошибку транзакции, если она была.
+func (s *TenderImportService) finishImport(
+	ctx context.Context,
+	trace *importTrace,
+	callbackDone time.Time,
+	etpID string,
+	tenderDBID int64,
+	payloadBytes int,
+	lotIDs map[string]int64,
+	anyNewPendingItems bool,
+	txErr error,
+) (int64, error) {
+	trace.total = time.Since(trace.startedAt)
+	if !callbackDone.IsZero() {
+		trace.commit = time.Since(callbackDone)
+	}
+	importID := s.saveImportTrace(ctx, trace, etpID, tenderDBID, payloadBytes, txErr)
+
 	if txErr != nil {
-		s.logger.Errorf("Не удалось импортировать тендер ETP_ID %s: %v", payload.TenderID, txErr)
-		return 0, nil, false, fmt.Errorf("транзакция импорта тендера провалена: %w", txErr)
+		s.logger.Errorf("Не удалось импортировать тендер ETP_ID %s (import_id=%d): %v", etpID, importID, txErr)
+		s.notifyImportFailure(etpID, importID, txErr)
+		return importID, fmt.Errorf("транзакция импорта тендера провалена: %w", txErr)
 	}
 
 	s.logger.Debug("Транзакция успешно закоммичена")
-	s.logger.Infof("Тендер ETP_ID %s успешно импортирован с ID базы данных: %d, новые pending позиции: %v", payload.TenderID, newTenderDBID, anyNewPendingItems)
-	return newTenderDBID, lotIDs, anyNewPendingItems, nil
+	if s.reconcileStaleRows {
+		s.logger.Infof("Сверка тендера ETP_ID %s: удалено устаревших позиций %d, итоговых строк %d",
+			etpID, trace.stalePositionsDeleted, trace.staleSummaryLinesDeleted)
+	}
+	s.logger.Infof("Тендер ETP_ID %s успешно импортирован с ID базы данных: %d, новые pending позиции: %v", etpID, tenderDBID, anyNewPendingItems)
+	events.Emit(ctx, s.publisher, s.logger, events.TypeTenderImported, tenderDBID, events.TenderImportedData{
+		TenderDBID:   tenderDBID,
+		TenderID:     etpID,
+		LotIDsMap:    lotIDs,
+		NewProposals: trace.newProposals,
+	})
+	if s.detectDuplicates {
+		s.flagProbableDuplicates(ctx, tenderDBID)
+	}
+	return importID, nil
+}
+
+// notifyImportFailure оповещает по почте о сбое импорта. Ошибки в данных
+// (ValidationError, ConflictError) возвращаются отправителю и оповещения
+// не требуют.
+func (s *TenderImportService) notifyImportFailure(etpID string, importID int64, txErr error) {
+	if s.notifier == nil {
+		return
+	}
+	var validationErr *apierrors.ValidationError
+	var conflictErr *apierrors.ConflictError
+	if errors.As(txErr, &validationErr) || errors.As(txErr, &conflictErr) {
+		return
+	}
+	s.notifier.NotifyImportFailure(notifications.ImportFailure{
+		TenderID: etpID,
+		ImportID: importID,
+		Error:    txErr.Error(),
+	})
 }
@@ -10,18 +10,30 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"sync"
 	"testing"
+	"testing/quick"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
 	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
 	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/entities"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/notifications"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/mailer"
 )
 
 /*
@@ -60,6 +72,7 @@ SCENARIO 3: ImportFullTender — one lot with position and summary → succeeds
 - GIVEN a valid FullTenderData with 1 lot, 1 baseline proposal, 1 position, 1 summary
   WHEN ImportFullTender is called
   THEN all entities are created in sequence, raw JSON is saved, tenderID and lotIDs are returned
+  AND a context WithProgress receives "1 of 1 lots" after the lot
 
 SCENARIO 4: ImportFullTender — matching cache hit → uses cached catalog position
 - GIVEN a position where matching_cache returns a cached catalog_position_id
@@ -123,13 +136,18 @@ SCENARIO 15: ImportFullTender — unit creation fails → returns error
   WHEN ImportFullTender is called
   THEN a wrapped error about unit creation is returned
 
+SCENARIO 15a: ImportFullTender — unit spelling is an alias → no new unit
+- GIVEN the position unit is not a unit name but an alias of a canonical unit
+  WHEN ImportFullTender is called
+  THEN the canonical unit is used and no unit is created
+
 SCENARIO 16: ImportFullTender — catalog position creation fails → returns error
 - GIVEN catalog position not found and CreateCatalogPosition returns an error
   WHEN ImportFullTender is called
   THEN a wrapped error about catalog position is returned
 
 SCENARIO 17: ImportFullTender — matching cache unexpected DB error → returns error
-- GIVEN GetMatchingCache returns a real DB error (not sql.ErrNoRows)
+- GIVEN GetActiveMatchingCache returns a real DB error (not sql.ErrNoRows)
   WHEN ImportFullTender is called
   THEN a wrapped matching_cache error is returned
 
@@ -181,6 +199,62 @@ SCENARIO 26: ImportFullTender — existing entities → reuses without creation
 - GIVEN object and executor already exist in DB
   WHEN ImportFullTender is called
   THEN existing entities are reused without calling Create methods
+
+--- Import Trace ---
+
+SCENARIO 27: ImportFullTender — success → trace saved with stage counters
+- GIVEN a one-lot payload with a cache-miss position
+  WHEN ImportFullTender is called
+  THEN CreateImportMetrics gets status=success, tender_id, lot/position counts,
+       cache miss, per-lot timing, and the returned import_id is passed through
+
+SCENARIO 28: ImportFullTender — failure → trace saved as failed
+- GIVEN the transaction fails
+  WHEN ImportFullTender is called
+  THEN CreateImportMetrics gets status=failed, NULL tender_id and the error text
+
+SCENARIO 29: ImportFullTender — trace save fails → import still succeeds
+- GIVEN CreateImportMetrics returns an error
+  WHEN ImportFullTender is called
+  THEN the import succeeds with import_id=0
+
+SCENARIO 30: GetImportTrace
+- GIVEN a stored trace → response with decoded lot timings
+- GIVEN a missing ID → NotFoundError
+
+--- Stale Rows Reconciliation ---
+
+SCENARIO 31: ImportFullTender — reconciliation enabled → deletes rows missing from payload
+- GIVEN import.reconcile_stale_rows=true and a proposal with positions {pos-1}, summary {sum-1}
+  WHEN ImportFullTender is called
+  THEN after the upserts DeleteStalePositionItems/DeleteStaleSummaryLines are called
+       with the payload keys and the deleted counts are accumulated in the trace
+
+SCENARIO 32: ImportFullTender — reconciliation delete fails → returns error
+- GIVEN reconciliation enabled and DeleteStalePositionItems fails
+  WHEN ImportFullTender is called
+  THEN the transaction is aborted and a wrapped error is returned
+
+SCENARIO 33: ImportFullTender — tender belongs to another organization → ConflictError
+- GIVEN UpsertTender returns no row (the existing tender is owned by another organization)
+  WHEN ImportFullTender is called
+  THEN the transaction is aborted and an *apierrors.ConflictError is returned
+
+SCENARIO 34: ImportFullTender — failure alerts
+- GIVEN a notifier with alert recipients and ExecTx failing with a DB error
+  WHEN ImportFullTender is called
+  THEN an alert with the tender's ETP ID, import_id and the error is mailed
+- GIVEN the tender belongs to another organization (ConflictError)
+  THEN no alert is mailed: the sender gets the error in the response
+
+SCENARIO 35: ImportFullTender — transactional outbox
+- GIVEN outbox.worker_callback_url is set and the import creates pending catalog items
+  WHEN ImportFullTender is called
+  THEN a catalog.items_pending event is inserted into outbox_events inside the import transaction
+- GIVEN the outbox insert fails
+  THEN the import transaction fails (no tender without its event)
+- GIVEN the outbox is enabled but no pending items appear
+  THEN no event is written
 */
 
 // ============================================================================
@@ -193,13 +267,13 @@ var now = time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
 var (
 	objectColumns        = []string{"id", "title", "address", "created_at", "updated_at"}
 	executorColumns      = []string{"id", "name", "phone", "created_at", "updated_at"}
-	tenderColumns        = []string{"id", "etp_id", "title", "category_id", "object_id", "executor_id", "data_prepared_on_date", "created_at", "updated_at"}
+	tenderColumns        = []string{"id", "etp_id", "title", "category_id", "object_id", "executor_id", "data_prepared_on_date", "created_at", "updated_at", "deleted_at", "deleted_by", "organization_id"}
 	lotColumns           = []string{"id", "lot_key", "lot_title", "lot_key_parameters", "tender_id", "created_at", "updated_at"}
-	contractorColumns    = []string{"id", "title", "inn", "address", "accreditation", "created_at", "updated_at"}
-	proposalColumns      = []string{"id", "lot_id", "contractor_id", "is_baseline", "contractor_coordinate", "contractor_width", "contractor_height", "created_at", "updated_at"}
+	contractorColumns    = []string{"id", "title", "inn", "address", "created_at", "updated_at", "accreditation_status", "accreditation_valid_until", "accreditation_documents", "accreditation_source", "accreditation_alerted_until"}
+	proposalColumns      = []string{"id", "lot_id", "contractor_id", "is_baseline", "contractor_coordinate", "contractor_width", "contractor_height", "created_at", "updated_at", "currency"}
 	unitColumns          = []string{"id", "normalized_name", "full_name", "description", "created_at", "updated_at"}
-	catalogPosColumns    = []string{"id", "standard_job_title", "description", "embedding", "kind", "status", "unit_id", "created_at", "updated_at", "fts_vector", "merged_into_id", "parent_id", "parameters"}
-	matchingCacheColumns = []string{"job_title_hash", "norm_version", "job_title_text", "catalog_position_id", "created_at", "expires_at"}
+	catalogPosColumns    = []string{"id", "standard_job_title", "description", "embedding", "kind", "status", "unit_id", "created_at", "updated_at", "fts_vector", "merged_into_id", "parent_id", "parameters", "is_pinned", "pinned_at", "pinned_by"}
+	matchingCacheColumns = []string{"job_title_hash", "norm_version", "job_title_text", "catalog_position_id", "created_at", "expires_at", "source"}
 	positionItemColumns  = []string{
 		"id", "proposal_id", "catalog_position_id", "position_key_in_proposal",
 		"comment_organazier", "comment_contractor", "item_number_in_proposal",
@@ -208,21 +282,32 @@ var (
 		"unit_cost_materials", "unit_cost_works", "unit_cost_indirect_costs", "unit_cost_total",
 		"total_cost_materials", "total_cost_works", "total_cost_indirect_costs", "total_cost_total",
 		"deviation_from_baseline_cost", "is_chapter", "chapter_ref_in_proposal",
-		"created_at", "updated_at",
+		"created_at", "updated_at", "article_smr", "matched_by", "matched_at", "claimed_by", "claimed_until", "currency",
 	}
-	summaryLineColumns    = []string{"id", "proposal_id", "summary_key", "job_title", "materials_cost", "works_cost", "indirect_costs_cost", "total_cost", "created_at", "updated_at"}
+	summaryLineColumns    = []string{"id", "proposal_id", "summary_key", "job_title", "materials_cost", "works_cost", "indirect_costs_cost", "total_cost", "created_at", "updated_at", "currency"}
 	tenderRawColumns      = []string{"tender_id", "raw_data", "created_at", "updated_at"}
+	rawDataVersionColumns = []string{"tender_id", "version", "payload_hash", "payload_bytes", "imported_at"}
 	additionalInfoColumns = []string{"id", "proposal_id", "info_key", "info_value", "created_at", "updated_at"}
 )
 
 // setupTestService creates a TenderImportService with a mock store for unit testing.
+// Трассировка импорта сохраняется после каждого ImportFullTender; проверяется отдельно
+// в тестах с newTestService.
 func setupTestService(t *testing.T) (*TenderImportService, *db.MockStore) {
+	t.Helper()
+	service, mockStore := newTestService(t)
+	mockStore.EXPECT().CreateImportMetrics(gomock.Any(), gomock.Any()).Return(int64(1), nil).AnyTimes()
+	return service, mockStore
+}
+
+// newTestService creates a TenderImportService without default expectations.
+func newTestService(t *testing.T) (*TenderImportService, *db.MockStore) {
 	t.Helper()
 	ctrl := gomock.NewController(t)
 	mockStore := db.NewMockStore(ctrl)
 	logger := testutil.NewMockLogger()
 	entityManager := entities.NewEntityManager(logger)
-	service := NewTenderImportService(mockStore, logger, entityManager)
+	service := NewTenderImportService(mockStore, nil, logger, entityManager, config.ImportConfig{}, config.OutboxConfig{}, events.Noop{}, nil)
 	return service, mockStore
 }
 
@@ -271,10 +356,10 @@ func makeMinimalPayload() *api_models.FullTenderData {
 // makePayloadWithOneLot creates a payload with 1 lot, 1 baseline proposal with 1 position and 1 summary.
 func makePayloadWithOneLot() *api_models.FullTenderData {
 	unitName := "м2"
-	quantity := 100.0
-	totalMaterials := 5000.0
-	totalWorks := 3000.0
-	totalTotal := 8000.0
+	quantity := testutil.Decimal("100")
+	totalMaterials := testutil.Money("5000")
+	totalWorks := testutil.Money("3000")
+	totalTotal := testutil.Money("8000")
 	jobTitleNorm := "устройство полов"
 
 	payload := makeMinimalPayload()
@@ -290,11 +375,11 @@ func makePayloadWithOneLot() *api_models.FullTenderData {
 							JobTitle:           "Устройство полов",
 							JobTitleNormalized: &jobTitleNorm,
 							Unit:               &unitName,
-							Quantity:           &quantity,
+							Quantity:           quantity,
 							TotalCost: api_models.Cost{
-								Materials: &totalMaterials,
-								Works:     &totalWorks,
-								Total:     &totalTotal,
+								Materials: totalMaterials,
+								Works:     totalWorks,
+								Total:     totalTotal,
 							},
 							UnitCost:  api_models.Cost{},
 							IsChapter: false,
@@ -304,7 +389,7 @@ func makePayloadWithOneLot() *api_models.FullTenderData {
 						"sum-1": {
 							JobTitle: "Итого по лоту",
 							TotalCost: api_models.Cost{
-								Total: &totalTotal,
+								Total: totalTotal,
 							},
 						},
 					},
@@ -340,7 +425,7 @@ func setupCoreTenderExpectations(mock sqlmock.Sqlmock) {
 	// UpsertTender
 	mock.ExpectQuery("INSERT INTO tenders").
 		WillReturnRows(sqlmock.NewRows(tenderColumns).
-			AddRow(int64(100), "ETP-TEST-001", "Тестовый тендер", nil, int64(1), int64(1), nil, now, now))
+			AddRow(int64(100), "ETP-TEST-001", "Тестовый тендер", nil, int64(1), int64(1), nil, now, now, nil, nil, int64(1)))
 }
 
 // setupBaselineProposalExpectations sets up expectations for baseline proposal processing:
@@ -353,41 +438,47 @@ func setupBaselineProposalExpectations(mock sqlmock.Sqlmock, lotDBID int64) int6
 		WillReturnError(sql.ErrNoRows)
 	// CreateContractor for baseline
 	mock.ExpectQuery("INSERT INTO contractors").
-		WithArgs("Initiator", "0000000000", "N/A", "N/A").
+		WithArgs("Initiator", "0000000000", "N/A", "unknown").
 		WillReturnRows(sqlmock.NewRows(contractorColumns).
-			AddRow(int64(50), "Initiator", "0000000000", "N/A", "N/A", now, now))
-	// UpsertProposal for baseline
+			AddRow(int64(50), "Initiator", "0000000000", "N/A", now, now, "unknown", nil, []byte("[]"), "import", nil))
+	// UpsertProposal for baseline: без явной валюты сохраняется RUB
 	mock.ExpectQuery("INSERT INTO proposals").
+		WithArgs(lotDBID, int64(50), true, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "RUB").
 		WillReturnRows(sqlmock.NewRows(proposalColumns).
-			AddRow(proposalDBID, lotDBID, int64(50), true, nil, nil, nil, now, now))
+			AddRow(proposalDBID, lotDBID, int64(50), true, nil, nil, nil, now, now, "RUB"))
 
 	return proposalDBID
 }
 
 // setupPositionExpectations sets up expectations for a single position with cache miss:
-// GetUnitByNormalizedName → not found → CreateUnit →
+// GetUnitByNormalizedName → not found → GetUnitByAlias → not found → CreateUnit →
 // GetCatalogPositionByTitleAndUnit → not found → CreateCatalogPosition →
-// GetMatchingCache → cache miss → UpsertPositionItem
+// GetActiveMatchingCache → cache miss → UpsertPositionItem
 func setupPositionExpectations(mock sqlmock.Sqlmock, proposalDBID int64) {
+	setupNewUnitExpectations(mock)
+	setupPositionAfterUnitExpectations(mock, proposalDBID)
+}
+
+// setupNewUnitExpectations: "м2" нет ни среди единиц, ни среди синонимов → создаётся единица ID 10.
+func setupNewUnitExpectations(mock sqlmock.Sqlmock) {
 	// GetUnitOfMeasurementByNormalizedName → not found
 	mock.ExpectQuery("SELECT .+ FROM units_of_measurement WHERE normalized_name").
 		WithArgs("м2").
 		WillReturnError(sql.ErrNoRows)
+	// GetUnitOfMeasurementByAlias → not found
+	mock.ExpectQuery("SELECT .+ FROM unit_aliases").
+		WithArgs("м2").
+		WillReturnError(sql.ErrNoRows)
 	// CreateUnitOfMeasurement
 	mock.ExpectQuery("INSERT INTO units_of_measurement").
 		WithArgs("м2", sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows(unitColumns).
 			AddRow(int64(10), "м2", sql.NullString{String: "м2", Valid: true}, sql.NullString{Valid: false}, now, now))
-	// GetCatalogPositionByTitleAndUnit → not found
-	mock.ExpectQuery("SELECT .+ FROM catalog_positions").
-		WillReturnError(sql.ErrNoRows)
-	// CreateCatalogPosition
-	mock.ExpectQuery("INSERT INTO catalog_positions").
-		WillReturnRows(sqlmock.NewRows(catalogPosColumns).
-			AddRow(int64(300), "устройство полов", sql.NullString{String: "Устройство полов", Valid: true}, nil, "POSITION", "pending_indexing", sql.NullInt64{Int64: 10, Valid: true}, now, now, nil, nil, nil, nil))
-	// GetMatchingCache → cache miss
-	mock.ExpectQuery("SELECT .+ FROM matching_cache").
-		WillReturnError(sql.ErrNoRows)
+}
+
+// setupPositionAfterUnitExpectations: позиция с единицей ID 10 — каталог и кэш промахиваются.
+func setupPositionAfterUnitExpectations(mock sqlmock.Sqlmock, proposalDBID int64) {
+	setupPositionLookupExpectations(mock)
 	// UpsertPositionItem
 	mock.ExpectQuery("INSERT INTO position_items").
 		WillReturnRows(sqlmock.NewRows(positionItemColumns).
@@ -399,22 +490,41 @@ func setupPositionExpectations(mock sqlmock.Sqlmock, proposalDBID int64) {
 				sql.NullString{}, sql.NullString{}, sql.NullString{}, sql.NullString{},
 				sql.NullString{}, sql.NullString{}, sql.NullString{}, sql.NullString{},
 				sql.NullString{}, false, sql.NullString{},
-				now, now,
+				now, now, sql.NullString{}, sql.NullString{}, sql.NullTime{}, sql.NullString{}, sql.NullTime{}, sql.NullString{},
 			))
 }
 
+// setupPositionLookupExpectations: для позиции с единицей ID 10 каталог и кэш
+// промахиваются, создаётся позиция каталога ID 300.
+func setupPositionLookupExpectations(mock sqlmock.Sqlmock) {
+	// GetCatalogPositionByTitleAndUnit → not found
+	mock.ExpectQuery("SELECT .+ FROM catalog_positions").
+		WillReturnError(sql.ErrNoRows)
+	// CreateCatalogPosition
+	mock.ExpectQuery("INSERT INTO catalog_positions").
+		WillReturnRows(sqlmock.NewRows(catalogPosColumns).
+			AddRow(int64(300), "устройство полов", sql.NullString{String: "Устройство полов", Valid: true}, nil, "POSITION", "pending_indexing", sql.NullInt64{Int64: 10, Valid: true}, now, now, nil, nil, nil, nil, false, nil, nil))
+	// GetActiveMatchingCache → cache miss
+	mock.ExpectQuery("SELECT .+ FROM matching_cache").
+		WillReturnError(sql.ErrNoRows)
+}
+
 // setupSummaryExpectations sets up expectations for a single summary line.
 func setupSummaryExpectations(mock sqlmock.Sqlmock, proposalDBID int64) {
 	mock.ExpectQuery("INSERT INTO proposal_summary_lines").
 		WillReturnRows(sqlmock.NewRows(summaryLineColumns).
-			AddRow(int64(500), proposalDBID, "sum-1", "Итого по лоту", nil, nil, nil, sql.NullString{String: "8000", Valid: true}, now, now))
+			AddRow(int64(500), proposalDBID, "sum-1", "Итого по лоту", nil, nil, nil, sql.NullString{String: "8000", Valid: true}, now, now, sql.NullString{}))
 }
 
-// setupRawDataExpectations sets up expectations for UpsertTenderRawData.
+// setupRawDataExpectations sets up expectations for UpsertTenderRawData
+// and CreateTenderRawDataVersion.
 func setupRawDataExpectations(mock sqlmock.Sqlmock, tenderDBID int64) {
-	mock.ExpectQuery("INSERT INTO tender_raw_data").
+	mock.ExpectQuery("INSERT INTO tender_raw_data ").
 		WillReturnRows(sqlmock.NewRows(tenderRawColumns).
 			AddRow(tenderDBID, json.RawMessage(`{}`), now, now))
+	mock.ExpectQuery("INSERT INTO tender_raw_data_history").
+		WillReturnRows(sqlmock.NewRows(rawDataVersionColumns).
+			AddRow(tenderDBID, int32(1), "hash", int64(2), now))
 }
 
 // ============================================================================
@@ -428,7 +538,7 @@ func TestNewTenderImportService_ValidDeps_ReturnsService(t *testing.T) {
 	em := entities.NewEntityManager(logger)
 
 	// WHEN
-	service := NewTenderImportService(mockStore, logger, em)
+	service := NewTenderImportService(mockStore, nil, logger, em, config.ImportConfig{}, config.OutboxConfig{}, events.Noop{}, nil)
 
 	// THEN
 	require.NotNil(t, service)
@@ -459,7 +569,7 @@ func TestImportFullTender_Success_NoLots(t *testing.T) {
 	)
 
 	// WHEN
-	tenderID, lotIDs, anyNewPending, err := service.ImportFullTender(ctx, payload, rawJSON)
+	tenderID, lotIDs, anyNewPending, _, err := service.ImportFullTender(ctx, payload, rawJSON)
 
 	// THEN
 	require.NoError(t, err)
@@ -470,7 +580,10 @@ func TestImportFullTender_Success_NoLots(t *testing.T) {
 
 func TestImportFullTender_Success_WithOneLot(t *testing.T) {
 	service, mockStore := setupTestService(t)
-	ctx := context.Background()
+	var progress []string
+	ctx := WithProgress(context.Background(), func(etpID string, lotsDone, lotsTotal int) {
+		progress = append(progress, fmt.Sprintf("%s %d/%d", etpID, lotsDone, lotsTotal))
+	})
 
 	// GIVEN a valid payload with 1 lot
 	payload := makePayloadWithOneLot()
@@ -500,7 +613,7 @@ func TestImportFullTender_Success_WithOneLot(t *testing.T) {
 	)
 
 	// WHEN
-	tenderID, lotIDs, anyNewPending, err := service.ImportFullTender(ctx, payload, rawJSON)
+	tenderID, lotIDs, anyNewPending, _, err := service.ImportFullTender(ctx, payload, rawJSON)
 
 	// THEN
 	require.NoError(t, err)
@@ -508,6 +621,7 @@ func TestImportFullTender_Success_WithOneLot(t *testing.T) {
 	assert.Equal(t, map[string]int64{"lot-1": lotDBID}, lotIDs)
 	assert.True(t, anyNewPending)
 	// New catalog position created with kind=POSITION → anyNewPending = true
+	assert.Equal(t, []string{"ETP-TEST-001 1/1"}, progress)
 }
 
 func TestImportFullTender_Success_MatchingCacheHit(t *testing.T) {
@@ -534,12 +648,12 @@ func TestImportFullTender_Success_MatchingCacheHit(t *testing.T) {
 				WithArgs("0000000000").
 				WillReturnError(sql.ErrNoRows)
 			mock.ExpectQuery("INSERT INTO contractors").
-				WithArgs("Initiator", "0000000000", "N/A", "N/A").
+				WithArgs("Initiator", "0000000000", "N/A", "unknown").
 				WillReturnRows(sqlmock.NewRows(contractorColumns).
-					AddRow(int64(50), "Initiator", "0000000000", "N/A", "N/A", now, now))
+					AddRow(int64(50), "Initiator", "0000000000", "N/A", now, now, "unknown", nil, []byte("[]"), "import", nil))
 			mock.ExpectQuery("INSERT INTO proposals").
 				WillReturnRows(sqlmock.NewRows(proposalColumns).
-					AddRow(proposalDBID, lotDBID, int64(50), true, nil, nil, nil, now, now))
+					AddRow(proposalDBID, lotDBID, int64(50), true, nil, nil, nil, now, now, "RUB"))
 			// Position: unit exists
 			mock.ExpectQuery("SELECT .+ FROM units_of_measurement WHERE normalized_name").
 				WithArgs("м2").
@@ -548,11 +662,11 @@ func TestImportFullTender_Success_MatchingCacheHit(t *testing.T) {
 			// Position: catalog position exists
 			mock.ExpectQuery("SELECT .+ FROM catalog_positions").
 				WillReturnRows(sqlmock.NewRows(catalogPosColumns).
-					AddRow(int64(300), "устройство полов", sql.NullString{String: "Устройство полов", Valid: true}, nil, "POSITION", "active", sql.NullInt64{Int64: 10, Valid: true}, now, now, nil, nil, nil, nil))
-			// Position: CACHE HIT → GetMatchingCache returns cached result
+					AddRow(int64(300), "устройство полов", sql.NullString{String: "Устройство полов", Valid: true}, nil, "POSITION", "active", sql.NullInt64{Int64: 10, Valid: true}, now, now, nil, nil, nil, nil, false, nil, nil))
+			// Position: CACHE HIT → GetActiveMatchingCache returns cached result
 			mock.ExpectQuery("SELECT .+ FROM matching_cache").
 				WillReturnRows(sqlmock.NewRows(matchingCacheColumns).
-					AddRow("somehash", int16(1), sql.NullString{String: "устройство полов", Valid: true}, cachedCatalogPosID, now, sql.NullTime{}))
+					AddRow("somehash", int16(1), sql.NullString{String: "устройство полов", Valid: true}, cachedCatalogPosID, now, sql.NullTime{}, "rag"))
 			// UpsertPositionItem (with cached catalog_position_id)
 			mock.ExpectQuery("INSERT INTO position_items").
 				WillReturnRows(sqlmock.NewRows(positionItemColumns).
@@ -564,7 +678,7 @@ func TestImportFullTender_Success_MatchingCacheHit(t *testing.T) {
 						sql.NullString{}, sql.NullString{}, sql.NullString{}, sql.NullString{},
 						sql.NullString{}, sql.NullString{}, sql.NullString{}, sql.NullString{},
 						sql.NullString{}, false, sql.NullString{},
-						now, now,
+						now, now, sql.NullString{}, sql.NullString{}, sql.NullTime{}, sql.NullString{}, sql.NullTime{}, sql.NullString{},
 					))
 			// Summary
 			setupSummaryExpectations(mock, proposalDBID)
@@ -574,7 +688,7 @@ func TestImportFullTender_Success_MatchingCacheHit(t *testing.T) {
 	)
 
 	// WHEN
-	tenderID, lotIDs, anyNewPending, err := service.ImportFullTender(ctx, payload, rawJSON)
+	tenderID, lotIDs, anyNewPending, _, err := service.ImportFullTender(ctx, payload, rawJSON)
 
 	// THEN
 	require.NoError(t, err)
@@ -609,6 +723,7 @@ func TestImportFullTender_Success_ContractorProposalWithAdditionalInfo(t *testin
 					AdditionalInfo: map[string]*string{
 						"срок_выполнения": &infoVal,
 					},
+					Currency: "usd",
 				},
 			},
 		},
@@ -632,12 +747,12 @@ func TestImportFullTender_Success_ContractorProposalWithAdditionalInfo(t *testin
 				WithArgs("0000000000").
 				WillReturnError(sql.ErrNoRows)
 			mock.ExpectQuery("INSERT INTO contractors").
-				WithArgs("Initiator", "0000000000", "N/A", "N/A").
+				WithArgs("Initiator", "0000000000", "N/A", "unknown").
 				WillReturnRows(sqlmock.NewRows(contractorColumns).
-					AddRow(int64(50), "Initiator", "0000000000", "N/A", "N/A", now, now))
+					AddRow(int64(50), "Initiator", "0000000000", "N/A", now, now, "unknown", nil, []byte("[]"), "import", nil))
 			mock.ExpectQuery("INSERT INTO proposals").
 				WillReturnRows(sqlmock.NewRows(proposalColumns).
-					AddRow(baselineProposalID, lotDBID, int64(50), true, nil, nil, nil, now, now))
+					AddRow(baselineProposalID, lotDBID, int64(50), true, nil, nil, nil, now, now, "RUB"))
 			// Baseline: skip additional info, no positions, no summary
 
 			// Contractor proposal
@@ -645,12 +760,14 @@ func TestImportFullTender_Success_ContractorProposalWithAdditionalInfo(t *testin
 				WithArgs("1234567890").
 				WillReturnError(sql.ErrNoRows)
 			mock.ExpectQuery("INSERT INTO contractors").
-				WithArgs("ООО Строитель", "1234567890", "г. Москва", "Аккредитован").
+				WithArgs("ООО Строитель", "1234567890", "г. Москва", "accredited").
 				WillReturnRows(sqlmock.NewRows(contractorColumns).
-					AddRow(int64(51), "ООО Строитель", "1234567890", "г. Москва", "Аккредитован", now, now))
+					AddRow(int64(51), "ООО Строитель", "1234567890", "г. Москва", now, now, "accredited", nil, []byte("[]"), "import", nil))
+			// Валюта предложения приводится к коду ISO 4217
 			mock.ExpectQuery("INSERT INTO proposals").
+				WithArgs(lotDBID, int64(51), false, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "USD").
 				WillReturnRows(sqlmock.NewRows(proposalColumns).
-					AddRow(contractorProposalID, lotDBID, int64(51), false, sql.NullString{String: "A1", Valid: true}, nil, nil, now, now))
+					AddRow(contractorProposalID, lotDBID, int64(51), false, sql.NullString{String: "A1", Valid: true}, nil, nil, now, now, "USD"))
 			// Contractor proposal: additional info (NOT baseline)
 			// DeleteAllAdditionalInfoForProposal
 			mock.ExpectExec("DELETE FROM proposal_additional_info").
@@ -668,7 +785,7 @@ func TestImportFullTender_Success_ContractorProposalWithAdditionalInfo(t *testin
 	)
 
 	// WHEN
-	tenderID, lotIDs, _, err := service.ImportFullTender(ctx, payload, rawJSON)
+	tenderID, lotIDs, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
 
 	// THEN
 	require.NoError(t, err)
@@ -692,7 +809,7 @@ func TestImportFullTender_ExecTxFails_ReturnsWrappedError(t *testing.T) {
 		Return(errors.New("connection refused"))
 
 	// WHEN
-	tenderID, lotIDs, anyNewPending, err := service.ImportFullTender(ctx, payload, rawJSON)
+	tenderID, lotIDs, anyNewPending, _, err := service.ImportFullTender(ctx, payload, rawJSON)
 
 	// THEN
 	require.Error(t, err)
@@ -720,7 +837,7 @@ func TestImportFullTender_ObjectCreationFails_ReturnsError(t *testing.T) {
 	)
 
 	// WHEN
-	tenderID, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
+	tenderID, _, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
 
 	// THEN
 	require.Error(t, err)
@@ -754,7 +871,7 @@ func TestImportFullTender_ExecutorCreationFails_ReturnsError(t *testing.T) {
 	)
 
 	// WHEN
-	tenderID, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
+	tenderID, _, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
 
 	// THEN
 	require.Error(t, err)
@@ -788,7 +905,7 @@ func TestImportFullTender_UpsertTenderFails_ReturnsError(t *testing.T) {
 	)
 
 	// WHEN
-	tenderID, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
+	tenderID, _, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
 
 	// THEN
 	require.Error(t, err)
@@ -796,6 +913,40 @@ func TestImportFullTender_UpsertTenderFails_ReturnsError(t *testing.T) {
 	assert.Equal(t, int64(0), tenderID)
 }
 
+func TestImportFullTender_TenderOfAnotherOrganization_ReturnsConflict(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	ctx := context.Background()
+
+	// GIVEN ETP ID already imported by another organization: the upsert skips the row
+	payload := makeMinimalPayload()
+	payload.OrganizationID = 2
+	rawJSON := []byte(`{}`)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT .+ FROM objects WHERE title").
+				WithArgs("Строительство").
+				WillReturnRows(sqlmock.NewRows(objectColumns).
+					AddRow(int64(1), "Строительство", "г. Москва, ул. Тестовая, 1", now, now))
+			mock.ExpectQuery("SELECT .+ FROM executors WHERE name").
+				WithArgs("Иванов И.И.").
+				WillReturnRows(sqlmock.NewRows(executorColumns).
+					AddRow(int64(1), "Иванов И.И.", "+7-999-000-0000", now, now))
+			mock.ExpectQuery("INSERT INTO tenders").
+				WillReturnRows(sqlmock.NewRows(tenderColumns))
+		}),
+	)
+
+	// WHEN
+	tenderID, _, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
+
+	// THEN
+	var conflictErr *apierrors.ConflictError
+	require.ErrorAs(t, err, &conflictErr)
+	assert.Contains(t, err.Error(), "другой организации")
+	assert.Equal(t, int64(0), tenderID)
+}
+
 func TestImportFullTender_UpsertLotFails_ReturnsError(t *testing.T) {
 	service, mockStore := setupTestService(t)
 	ctx := context.Background()
@@ -814,7 +965,7 @@ func TestImportFullTender_UpsertLotFails_ReturnsError(t *testing.T) {
 	)
 
 	// WHEN
-	tenderID, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
+	tenderID, _, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
 
 	// THEN
 	require.Error(t, err)
@@ -842,7 +993,7 @@ func TestImportFullTender_UpsertProposalFails_ReturnsError(t *testing.T) {
 			mock.ExpectQuery("SELECT .+ FROM contractors WHERE inn").
 				WithArgs("0000000000").
 				WillReturnRows(sqlmock.NewRows(contractorColumns).
-					AddRow(int64(50), "Initiator", "0000000000", "N/A", "N/A", now, now))
+					AddRow(int64(50), "Initiator", "0000000000", "N/A", now, now, "unknown", nil, []byte("[]"), "import", nil))
 			// UpsertProposal fails
 			mock.ExpectQuery("INSERT INTO proposals").
 				WillReturnError(errors.New("serialization failure"))
@@ -850,7 +1001,7 @@ func TestImportFullTender_UpsertProposalFails_ReturnsError(t *testing.T) {
 	)
 
 	// WHEN
-	tenderID, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
+	tenderID, _, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
 
 	// THEN
 	require.Error(t, err)
@@ -884,8 +1035,8 @@ func TestImportFullTender_UpsertPositionItemFails_ReturnsError(t *testing.T) {
 			// Position: catalog position exists
 			mock.ExpectQuery("SELECT .+ FROM catalog_positions").
 				WillReturnRows(sqlmock.NewRows(catalogPosColumns).
-					AddRow(int64(300), "устройство полов", sql.NullString{String: "Устройство полов", Valid: true}, nil, "POSITION", "active", sql.NullInt64{Int64: 10, Valid: true}, now, now, nil, nil, nil, nil))
-			// GetMatchingCache → cache miss
+					AddRow(int64(300), "устройство полов", sql.NullString{String: "Устройство полов", Valid: true}, nil, "POSITION", "active", sql.NullInt64{Int64: 10, Valid: true}, now, now, nil, nil, nil, nil, false, nil, nil))
+			// GetActiveMatchingCache → cache miss
 			mock.ExpectQuery("SELECT .+ FROM matching_cache").
 				WillReturnError(sql.ErrNoRows)
 			// UpsertPositionItem fails
@@ -895,7 +1046,7 @@ func TestImportFullTender_UpsertPositionItemFails_ReturnsError(t *testing.T) {
 	)
 
 	// WHEN
-	tenderID, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
+	tenderID, _, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
 
 	// THEN
 	require.Error(t, err)
@@ -930,7 +1081,7 @@ func TestImportFullTender_UpsertTenderRawDataFails_ReturnsError(t *testing.T) {
 	)
 
 	// WHEN
-	tenderID, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
+	tenderID, _, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
 
 	// THEN
 	require.Error(t, err)
@@ -954,10 +1105,13 @@ func TestImportFullTender_UnitCreationFails_ReturnsError(t *testing.T) {
 				WillReturnRows(sqlmock.NewRows(lotColumns).
 					AddRow(lotDBID, "lot-1", "Лот №1 — Отделочные работы", nil, int64(100), now, now))
 			setupBaselineProposalExpectations(mock, lotDBID)
-			// GetUnit → not found
+			// GetUnit → not found, синонима тоже нет
 			mock.ExpectQuery("SELECT .+ FROM units_of_measurement WHERE normalized_name").
 				WithArgs("м2").
 				WillReturnError(sql.ErrNoRows)
+			mock.ExpectQuery("SELECT .+ FROM unit_aliases").
+				WithArgs("м2").
+				WillReturnError(sql.ErrNoRows)
 			// CreateUnit → fails
 			mock.ExpectQuery("INSERT INTO units_of_measurement").
 				WillReturnError(errors.New("insert conflict"))
@@ -969,7 +1123,7 @@ func TestImportFullTender_UnitCreationFails_ReturnsError(t *testing.T) {
 	)
 
 	// WHEN
-	tenderID, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
+	tenderID, _, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
 
 	// THEN
 	require.Error(t, err)
@@ -977,6 +1131,43 @@ func TestImportFullTender_UnitCreationFails_ReturnsError(t *testing.T) {
 	assert.Equal(t, int64(0), tenderID)
 }
 
+func TestImportFullTender_UnitAlias_ResolvesWithoutCreatingUnit(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	ctx := context.Background()
+
+	// GIVEN "м2" is not a unit name but an alias of the canonical unit "кв.м"
+	payload := makePayloadWithOneLot()
+	rawJSON := []byte(`{}`)
+	lotDBID := int64(150)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			setupCoreTenderExpectations(mock)
+			mock.ExpectQuery("INSERT INTO lots").
+				WillReturnRows(sqlmock.NewRows(lotColumns).
+					AddRow(lotDBID, "lot-1", "Лот №1 — Отделочные работы", nil, int64(100), now, now))
+			proposalDBID := setupBaselineProposalExpectations(mock, lotDBID)
+			mock.ExpectQuery("SELECT .+ FROM units_of_measurement WHERE normalized_name").
+				WithArgs("м2").
+				WillReturnError(sql.ErrNoRows)
+			mock.ExpectQuery("SELECT .+ FROM unit_aliases").
+				WithArgs("м2").
+				WillReturnRows(sqlmock.NewRows(unitColumns).
+					AddRow(int64(10), "кв.м", sql.NullString{String: "Квадратный метр", Valid: true}, sql.NullString{}, now, now))
+			// INSERT INTO units_of_measurement не ожидается
+			setupPositionAfterUnitExpectations(mock, proposalDBID)
+			setupSummaryExpectations(mock, proposalDBID)
+			setupRawDataExpectations(mock, 100)
+		}),
+	)
+
+	// WHEN
+	_, _, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
+
+	// THEN the position is linked to the canonical unit, no new unit is created
+	require.NoError(t, err)
+}
+
 func TestImportFullTender_CatalogPositionCreationFails_ReturnsError(t *testing.T) {
 	service, mockStore := setupTestService(t)
 	ctx := context.Background()
@@ -1008,7 +1199,7 @@ func TestImportFullTender_CatalogPositionCreationFails_ReturnsError(t *testing.T
 	)
 
 	// WHEN
-	tenderID, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
+	tenderID, _, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
 
 	// THEN
 	require.Error(t, err)
@@ -1020,7 +1211,7 @@ func TestImportFullTender_MatchingCacheDBError_ReturnsError(t *testing.T) {
 	service, mockStore := setupTestService(t)
 	ctx := context.Background()
 
-	// GIVEN GetMatchingCache returns an unexpected DB error (not sql.ErrNoRows)
+	// GIVEN GetActiveMatchingCache returns an unexpected DB error (not sql.ErrNoRows)
 	payload := makePayloadWithOneLot()
 	rawJSON := []byte(`{}`)
 	lotDBID := int64(150)
@@ -1040,15 +1231,15 @@ func TestImportFullTender_MatchingCacheDBError_ReturnsError(t *testing.T) {
 			// Catalog position found (kind=POSITION)
 			mock.ExpectQuery("SELECT .+ FROM catalog_positions").
 				WillReturnRows(sqlmock.NewRows(catalogPosColumns).
-					AddRow(int64(300), "устройство полов", sql.NullString{String: "Устройство полов", Valid: true}, nil, "POSITION", "active", sql.NullInt64{Int64: 10, Valid: true}, now, now, nil, nil, nil, nil))
-			// GetMatchingCache returns real DB error
+					AddRow(int64(300), "устройство полов", sql.NullString{String: "Устройство полов", Valid: true}, nil, "POSITION", "active", sql.NullInt64{Int64: 10, Valid: true}, now, now, nil, nil, nil, nil, false, nil, nil))
+			// GetActiveMatchingCache returns real DB error
 			mock.ExpectQuery("SELECT .+ FROM matching_cache").
 				WillReturnError(errors.New("connection lost"))
 		}),
 	)
 
 	// WHEN
-	tenderID, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
+	tenderID, _, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
 
 	// THEN
 	require.Error(t, err)
@@ -1080,7 +1271,7 @@ func TestImportFullTender_SummaryLineFails_ReturnsError(t *testing.T) {
 	)
 
 	// WHEN
-	tenderID, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
+	tenderID, _, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
 
 	// THEN
 	require.Error(t, err)
@@ -1130,20 +1321,20 @@ func TestImportFullTender_AdditionalInfoDeleteFails_ReturnsError(t *testing.T) {
 			mock.ExpectQuery("SELECT .+ FROM contractors WHERE inn").
 				WithArgs("0000000000").
 				WillReturnRows(sqlmock.NewRows(contractorColumns).
-					AddRow(int64(50), "Initiator", "0000000000", "N/A", "N/A", now, now))
+					AddRow(int64(50), "Initiator", "0000000000", "N/A", now, now, "unknown", nil, []byte("[]"), "import", nil))
 			mock.ExpectQuery("INSERT INTO proposals").
 				WillReturnRows(sqlmock.NewRows(proposalColumns).
-					AddRow(int64(200), lotDBID, int64(50), true, nil, nil, nil, now, now))
+					AddRow(int64(200), lotDBID, int64(50), true, nil, nil, nil, now, now, "RUB"))
 			// Contractor
 			mock.ExpectQuery("SELECT .+ FROM contractors WHERE inn").
 				WithArgs("1111111111").
 				WillReturnError(sql.ErrNoRows)
 			mock.ExpectQuery("INSERT INTO contractors").
 				WillReturnRows(sqlmock.NewRows(contractorColumns).
-					AddRow(int64(51), "Подрядчик", "1111111111", "Адрес", "Да", now, now))
+					AddRow(int64(51), "Подрядчик", "1111111111", "Адрес", now, now, "accredited", nil, []byte("[]"), "import", nil))
 			mock.ExpectQuery("INSERT INTO proposals").
 				WillReturnRows(sqlmock.NewRows(proposalColumns).
-					AddRow(contractorProposalID, lotDBID, int64(51), false, sql.NullString{String: "B2", Valid: true}, nil, nil, now, now))
+					AddRow(contractorProposalID, lotDBID, int64(51), false, sql.NullString{String: "B2", Valid: true}, nil, nil, now, now, "RUB"))
 			// DeleteAllAdditionalInfoForProposal fails
 			mock.ExpectExec("DELETE FROM proposal_additional_info").
 				WithArgs(contractorProposalID).
@@ -1152,7 +1343,7 @@ func TestImportFullTender_AdditionalInfoDeleteFails_ReturnsError(t *testing.T) {
 	)
 
 	// WHEN
-	tenderID, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
+	tenderID, _, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
 
 	// THEN
 	require.Error(t, err)
@@ -1208,8 +1399,8 @@ func TestImportFullTender_HeaderPosition_SkipsCache(t *testing.T) {
 			// CreateCatalogPosition with kind=HEADER
 			mock.ExpectQuery("INSERT INTO catalog_positions").
 				WillReturnRows(sqlmock.NewRows(catalogPosColumns).
-					AddRow(int64(300), "глава 1 общестроительные работы", sql.NullString{String: "Глава 1 Общестроительные работы", Valid: true}, nil, "HEADER", "pending_indexing", sql.NullInt64{}, now, now, nil, nil, nil, nil))
-			// For HEADER kind: no GetMatchingCache call (skipped)
+					AddRow(int64(300), "глава 1 общестроительные работы", sql.NullString{String: "Глава 1 Общестроительные работы", Valid: true}, nil, "HEADER", "pending_indexing", sql.NullInt64{}, now, now, nil, nil, nil, nil, false, nil, nil))
+			// For HEADER kind: no GetActiveMatchingCache call (skipped)
 			// Directly UpsertPositionItem with catalogPositionID set
 			mock.ExpectQuery("INSERT INTO position_items").
 				WillReturnRows(sqlmock.NewRows(positionItemColumns).
@@ -1221,14 +1412,14 @@ func TestImportFullTender_HeaderPosition_SkipsCache(t *testing.T) {
 						sql.NullString{}, sql.NullString{}, sql.NullString{}, sql.NullString{},
 						sql.NullString{}, sql.NullString{}, sql.NullString{}, sql.NullString{},
 						sql.NullString{}, true, sql.NullString{},
-						now, now,
+						now, now, sql.NullString{}, sql.NullString{}, sql.NullTime{}, sql.NullString{}, sql.NullTime{}, sql.NullString{},
 					))
 			setupRawDataExpectations(mock, 100)
 		}),
 	)
 
 	// WHEN
-	tenderID, lotIDs, anyNewPending, err := service.ImportFullTender(ctx, payload, rawJSON)
+	tenderID, lotIDs, anyNewPending, _, err := service.ImportFullTender(ctx, payload, rawJSON)
 
 	// THEN
 	require.NoError(t, err)
@@ -1244,45 +1435,50 @@ func TestImportFullTender_HeaderPosition_SkipsCache(t *testing.T) {
 
 func TestMapApiPositionToDbParams_FullFields_MapsCorrectly(t *testing.T) {
 	// GIVEN a fully populated API PositionItem
-	quantity := 10.5
-	suggestedQty := 12.0
-	totalOrgCost := 999.99
-	unitMaterials := 50.0
-	unitWorks := 30.0
-	unitIndirect := 10.0
-	unitTotal := 90.0
-	totalMaterials := 500.0
-	totalWorks := 300.0
-	totalIndirect := 100.0
-	totalTotal := 900.0
+	quantity := testutil.Decimal("10.5")
+	suggestedQty := testutil.Decimal("12")
+	totalOrgCost := testutil.Money("999.99")
+	unitMaterials := testutil.Money("50")
+	unitWorks := testutil.Money("30")
+	unitIndirect := testutil.Money("10")
+	unitTotal := testutil.Money("90")
+	totalMaterials := testutil.Money("500")
+	totalWorks := testutil.Money("300")
+	totalIndirect := testutil.Money("100")
+	totalTotal := testutil.Money("900.123456789")
 	comment := "организатор"
 	commentContractor := "подрядчик"
 	chapterNum := "3"
 	chapterRef := "ch-1"
+	articleSMR := "СМР-07"
+	deviation := testutil.Money("-12.5")
 
 	posAPI := api_models.PositionItem{
 		Number:                        "42",
 		ChapterNumber:                 &chapterNum,
+		ArticleSMR:                    &articleSMR,
+		DeviationFromBaselineCost:     deviation,
 		JobTitle:                      "Монтаж конструкций",
 		CommentOrganizer:              &comment,
 		CommentContractor:             &commentContractor,
-		Quantity:                      &quantity,
-		SuggestedQuantity:             &suggestedQty,
-		TotalCostForOrganizerQuantity: &totalOrgCost,
+		Quantity:                      quantity,
+		SuggestedQuantity:             suggestedQty,
+		TotalCostForOrganizerQuantity: totalOrgCost,
 		UnitCost: api_models.Cost{
-			Materials:     &unitMaterials,
-			Works:         &unitWorks,
-			IndirectCosts: &unitIndirect,
-			Total:         &unitTotal,
+			Materials:     unitMaterials,
+			Works:         unitWorks,
+			IndirectCosts: unitIndirect,
+			Total:         unitTotal,
 		},
 		TotalCost: api_models.Cost{
-			Materials:     &totalMaterials,
-			Works:         &totalWorks,
-			IndirectCosts: &totalIndirect,
-			Total:         &totalTotal,
+			Materials:     totalMaterials,
+			Works:         totalWorks,
+			IndirectCosts: totalIndirect,
+			Total:         totalTotal,
 		},
 		IsChapter:  false,
 		ChapterRef: &chapterRef,
+		Currency:   " usd",
 	}
 
 	proposalID := int64(100)
@@ -1325,6 +1521,15 @@ func TestMapApiPositionToDbParams_FullFields_MapsCorrectly(t *testing.T) {
 	assert.True(t, result.TotalCostWorks.Valid)
 	assert.True(t, result.TotalCostIndirectCosts.Valid)
 	assert.True(t, result.TotalCostTotal.Valid)
+
+	// Ранее терявшиеся поля
+	assert.Equal(t, sql.NullString{String: "СМР-07", Valid: true}, result.ArticleSmr)
+	assert.Equal(t, sql.NullString{String: "-12.5", Valid: true}, result.DeviationFromBaselineCost)
+	// Деньги пишутся в NUMERIC без float64 и без округления до копеек
+	assert.Equal(t, sql.NullString{String: "900.123456789", Valid: true}, result.TotalCostTotal)
+	assert.Equal(t, sql.NullString{String: "10.5", Valid: true}, result.Quantity)
+	// Валюта строки нормализуется к коду ISO 4217
+	assert.Equal(t, sql.NullString{String: "USD", Valid: true}, result.Currency)
 }
 
 func TestMapApiPositionToDbParams_NilFields_ReturnsInvalidNulls(t *testing.T) {
@@ -1358,23 +1563,134 @@ func TestMapApiPositionToDbParams_NilFields_ReturnsInvalidNulls(t *testing.T) {
 	assert.False(t, result.SuggestedQuantity.Valid)
 	assert.False(t, result.TotalCostTotal.Valid)
 	assert.False(t, result.UnitCostMaterials.Valid)
+	assert.False(t, result.ArticleSmr.Valid)
+	assert.False(t, result.DeviationFromBaselineCost.Valid)
+	assert.False(t, result.Currency.Valid, "без валюты строки действует валюта предложения")
+}
+
+// optionalPositionField связывает необязательное поле API-позиции с параметром БД.
+type optionalPositionField struct {
+	name  string
+	set   func(p *api_models.PositionItem, v float64)
+	param func(r db.UpsertPositionItemParams) sql.NullString
+}
+
+// optionalPositionFields — все nullable поля позиции. Для строковых полей
+// в качестве значения используется строковое представление числа.
+var optionalPositionFields = []optionalPositionField{
+	{"ChapterNumber", func(p *api_models.PositionItem, v float64) { p.ChapterNumber = floatStr(v) }, func(r db.UpsertPositionItemParams) sql.NullString { return r.ChapterNumberInProposal }},
+	{"ArticleSMR", func(p *api_models.PositionItem, v float64) { p.ArticleSMR = floatStr(v) }, func(r db.UpsertPositionItemParams) sql.NullString { return r.ArticleSmr }},
+	{"CommentOrganizer", func(p *api_models.PositionItem, v float64) { p.CommentOrganizer = floatStr(v) }, func(r db.UpsertPositionItemParams) sql.NullString { return r.CommentOrganazier }},
+	{"CommentContractor", func(p *api_models.PositionItem, v float64) { p.CommentContractor = floatStr(v) }, func(r db.UpsertPositionItemParams) sql.NullString { return r.CommentContractor }},
+	{"ChapterRef", func(p *api_models.PositionItem, v float64) { p.ChapterRef = floatStr(v) }, func(r db.UpsertPositionItemParams) sql.NullString { return r.ChapterRefInProposal }},
+	{"Quantity", func(p *api_models.PositionItem, v float64) { p.Quantity = decimalPtr(v) }, func(r db.UpsertPositionItemParams) sql.NullString { return r.Quantity }},
+	{"SuggestedQuantity", func(p *api_models.PositionItem, v float64) { p.SuggestedQuantity = decimalPtr(v) }, func(r db.UpsertPositionItemParams) sql.NullString { return r.SuggestedQuantity }},
+	{"TotalCostForOrganizerQuantity", func(p *api_models.PositionItem, v float64) { p.TotalCostForOrganizerQuantity = moneyPtr(v) }, func(r db.UpsertPositionItemParams) sql.NullString { return r.TotalCostForOrganizerQuantity }},
+	{"DeviationFromBaselineCost", func(p *api_models.PositionItem, v float64) { p.DeviationFromBaselineCost = moneyPtr(v) }, func(r db.UpsertPositionItemParams) sql.NullString { return r.DeviationFromBaselineCost }},
+	{"UnitCost.Materials", func(p *api_models.PositionItem, v float64) { p.UnitCost.Materials = moneyPtr(v) }, func(r db.UpsertPositionItemParams) sql.NullString { return r.UnitCostMaterials }},
+	{"UnitCost.Works", func(p *api_models.PositionItem, v float64) { p.UnitCost.Works = moneyPtr(v) }, func(r db.UpsertPositionItemParams) sql.NullString { return r.UnitCostWorks }},
+	{"UnitCost.IndirectCosts", func(p *api_models.PositionItem, v float64) { p.UnitCost.IndirectCosts = moneyPtr(v) }, func(r db.UpsertPositionItemParams) sql.NullString { return r.UnitCostIndirectCosts }},
+	{"UnitCost.Total", func(p *api_models.PositionItem, v float64) { p.UnitCost.Total = moneyPtr(v) }, func(r db.UpsertPositionItemParams) sql.NullString { return r.UnitCostTotal }},
+	{"TotalCost.Materials", func(p *api_models.PositionItem, v float64) { p.TotalCost.Materials = moneyPtr(v) }, func(r db.UpsertPositionItemParams) sql.NullString { return r.TotalCostMaterials }},
+	{"TotalCost.Works", func(p *api_models.PositionItem, v float64) { p.TotalCost.Works = moneyPtr(v) }, func(r db.UpsertPositionItemParams) sql.NullString { return r.TotalCostWorks }},
+	{"TotalCost.IndirectCosts", func(p *api_models.PositionItem, v float64) { p.TotalCost.IndirectCosts = moneyPtr(v) }, func(r db.UpsertPositionItemParams) sql.NullString { return r.TotalCostIndirectCosts }},
+	{"TotalCost.Total", func(p *api_models.PositionItem, v float64) { p.TotalCost.Total = moneyPtr(v) }, func(r db.UpsertPositionItemParams) sql.NullString { return r.TotalCostTotal }},
+}
+
+func floatStr(v float64) *string {
+	s := strconv.FormatFloat(v, 'f', -1, 64)
+	return &s
+}
+
+func decimalPtr(v float64) *decimal.Decimal {
+	d := decimal.NewFromFloat(v)
+	return &d
+}
+
+func moneyPtr(v float64) *api_models.Money {
+	return api_models.MoneyPtr(decimal.NewFromFloat(v))
+}
+
+func TestMapApiPositionToDbParams_AnyNilCombination_Property(t *testing.T) {
+	// Свойство: для любой комбинации nil/не-nil каждый параметр БД Valid ровно тогда,
+	// когда задано соответствующее API-поле, а значение переживает преобразование.
+	property := func(mask uint32, value float64) bool {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return true // парсер JSON не может прислать NaN/Inf
+		}
+
+		posAPI := api_models.PositionItem{Number: "1", JobTitle: "Работа"}
+		for i, f := range optionalPositionFields {
+			if mask&(1<<i) != 0 {
+				f.set(&posAPI, value)
+			}
+		}
+
+		result := mapApiPositionToDbParams(1, "pos-1", sql.NullInt64{}, sql.NullInt64{}, posAPI)
+
+		for i, f := range optionalPositionFields {
+			param := f.param(result)
+			isSet := mask&(1<<i) != 0
+			if param.Valid != isSet {
+				t.Logf("field %s: Valid=%v, expected %v (mask=%b)", f.name, param.Valid, isSet, mask)
+				return false
+			}
+			if !isSet {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(param.String, 64)
+			if err != nil || parsed != value {
+				t.Logf("field %s: %q does not round-trip %v", f.name, param.String, value)
+				return false
+			}
+		}
+		return true
+	}
+
+	require.NoError(t, quick.Check(property, &quick.Config{MaxCount: 2000}))
+}
+
+func TestPositionFieldDestinations_CoverAllApiFields(t *testing.T) {
+	apiType := reflect.TypeOf(api_models.PositionItem{})
+
+	for i := 0; i < apiType.NumField(); i++ {
+		name := apiType.Field(i).Name
+		_, ok := positionFieldDestinations[name]
+		assert.True(t, ok, "поле api_models.PositionItem.%s не сохраняется в БД: добавьте его в маппер и positionFieldDestinations", name)
+	}
+	for name := range positionFieldDestinations {
+		_, ok := apiType.FieldByName(name)
+		assert.True(t, ok, "positionFieldDestinations ссылается на несуществующее поле %s", name)
+	}
+}
+
+func TestUnmappedPositionFields_ReportsPopulatedFieldsWithoutDestination(t *testing.T) {
+	comment := "подрядчик"
+	posAPI := api_models.PositionItem{Number: "1", JobTitle: "Работа", CommentContractor: &comment}
+
+	assert.Empty(t, unmappedPositionFields(posAPI))
+
+	// Имитируем поле без колонки в БД
+	saved := positionFieldDestinations["CommentContractor"]
+	delete(positionFieldDestinations, "CommentContractor")
+	t.Cleanup(func() { positionFieldDestinations["CommentContractor"] = saved })
+
+	assert.Equal(t, []string{"CommentContractor"}, unmappedPositionFields(posAPI))
+	// Пустое поле без колонки не шумит в логах
+	assert.Empty(t, unmappedPositionFields(api_models.PositionItem{Number: "1", JobTitle: "Работа"}))
 }
 
 func TestMapApiSummaryToDbParams_FullFields_MapsCorrectly(t *testing.T) {
 	// GIVEN a fully populated SummaryLine
-	materials := 1000.0
-	works := 2000.0
-	indirect := 500.0
-	total := 3500.0
-
 	sumAPI := api_models.SummaryLine{
 		JobTitle: "Итого по разделу",
 		TotalCost: api_models.Cost{
-			Materials:     &materials,
-			Works:         &works,
-			IndirectCosts: &indirect,
-			Total:         &total,
+			Materials:     testutil.Money("1000"),
+			Works:         testutil.Money("2000"),
+			IndirectCosts: testutil.Money("500"),
+			Total:         testutil.Money("3500"),
 		},
+		Currency: "EUR",
 	}
 
 	proposalID := int64(100)
@@ -1391,6 +1707,7 @@ func TestMapApiSummaryToDbParams_FullFields_MapsCorrectly(t *testing.T) {
 	assert.True(t, result.WorksCost.Valid)
 	assert.True(t, result.IndirectCostsCost.Valid)
 	assert.True(t, result.TotalCost.Valid)
+	assert.Equal(t, sql.NullString{String: "EUR", Valid: true}, result.Currency)
 }
 
 func TestMapApiSummaryToDbParams_NilCosts_ReturnsInvalidNulls(t *testing.T) {
@@ -1409,6 +1726,7 @@ func TestMapApiSummaryToDbParams_NilCosts_ReturnsInvalidNulls(t *testing.T) {
 	assert.False(t, result.WorksCost.Valid)
 	assert.False(t, result.IndirectCostsCost.Valid)
 	assert.False(t, result.TotalCost.Valid)
+	assert.False(t, result.Currency.Valid)
 }
 
 // ============================================================================
@@ -1457,7 +1775,7 @@ func TestImportFullTender_EmptyJobTitle_SkipsPosition(t *testing.T) {
 	)
 
 	// WHEN
-	tenderID, lotIDs, _, err := service.ImportFullTender(ctx, payload, rawJSON)
+	tenderID, lotIDs, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
 
 	// THEN
 	require.NoError(t, err)
@@ -1498,7 +1816,7 @@ func TestImportFullTender_NilPositionsAndSummary_SucceedsEmpty(t *testing.T) {
 	)
 
 	// WHEN
-	tenderID, lotIDs, anyNewPending, err := service.ImportFullTender(ctx, payload, rawJSON)
+	tenderID, lotIDs, anyNewPending, _, err := service.ImportFullTender(ctx, payload, rawJSON)
 
 	// THEN
 	require.NoError(t, err)
@@ -1530,15 +1848,420 @@ func TestImportFullTender_ExistingEntities_ReusesWithoutCreation(t *testing.T) {
 			// UpsertTender
 			mock.ExpectQuery("INSERT INTO tenders").
 				WillReturnRows(sqlmock.NewRows(tenderColumns).
-					AddRow(int64(100), "ETP-TEST-001", "Тестовый тендер", nil, int64(1), int64(2), nil, now, now))
+					AddRow(int64(100), "ETP-TEST-001", "Тестовый тендер", nil, int64(1), int64(2), nil, now, now, nil, nil, int64(1)))
 			setupRawDataExpectations(mock, 100)
 		}),
 	)
 
 	// WHEN
-	tenderID, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
+	tenderID, _, _, _, err := service.ImportFullTender(ctx, payload, rawJSON)
 
 	// THEN
 	require.NoError(t, err)
 	assert.Equal(t, int64(100), tenderID)
 }
+
+// ============================================================================
+// Import Trace TESTS
+// ============================================================================
+
+func TestImportFullTender_Success_SavesTrace(t *testing.T) {
+	service, mockStore := newTestService(t)
+	ctx := context.Background()
+
+	// GIVEN a one-lot payload with a cache-miss position
+	payload := makePayloadWithOneLot()
+	rawJSON := []byte(`{"tender_id":"ETP-TEST-001"}`)
+	lotDBID := int64(150)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			setupCoreTenderExpectations(mock)
+			mock.ExpectQuery("INSERT INTO lots").
+				WillReturnRows(sqlmock.NewRows(lotColumns).
+					AddRow(lotDBID, "lot-1", "Лот №1 — Отделочные работы", nil, int64(100), now, now))
+			proposalDBID := setupBaselineProposalExpectations(mock, lotDBID)
+			setupPositionExpectations(mock, proposalDBID)
+			setupSummaryExpectations(mock, proposalDBID)
+			setupRawDataExpectations(mock, 100)
+		}),
+	)
+
+	var saved db.CreateImportMetricsParams
+	mockStore.EXPECT().CreateImportMetrics(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateImportMetricsParams) (int64, error) {
+			saved = arg
+			return int64(77), nil
+		})
+
+	// WHEN
+	_, _, _, importID, err := service.ImportFullTender(ctx, payload, rawJSON)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, int64(77), importID)
+	assert.Equal(t, "success", saved.Status)
+	assert.Equal(t, sql.NullInt64{Int64: 100, Valid: true}, saved.TenderID)
+	assert.False(t, saved.ErrorMessage.Valid)
+	assert.Equal(t, "ETP-TEST-001", saved.EtpID)
+	assert.Equal(t, int64(len(rawJSON)), saved.PayloadBytes)
+	assert.Equal(t, int32(1), saved.LotsCount)
+	assert.Equal(t, int32(1), saved.PositionsCount)
+	assert.Equal(t, int32(0), saved.CacheHits)
+	assert.Equal(t, int32(1), saved.CacheMisses)
+	assert.GreaterOrEqual(t, saved.TotalMs, saved.LotsMs)
+	assert.GreaterOrEqual(t, saved.PositionsMs, saved.CacheLookupMs)
+
+	var lots []api_models.ImportLotTiming
+	require.NoError(t, json.Unmarshal(saved.LotTimings, &lots))
+	require.Len(t, lots, 1)
+	assert.Equal(t, "lot-1", lots[0].LotKey)
+	assert.Equal(t, 1, lots[0].ProposalsCount)
+	assert.Equal(t, 1, lots[0].PositionsCount)
+}
+
+func TestImportFullTender_Failure_SavesFailedTrace(t *testing.T) {
+	service, mockStore := newTestService(t)
+	ctx := context.Background()
+
+	// GIVEN the transaction fails
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).
+		Return(errors.New("connection refused"))
+
+	var saved db.CreateImportMetricsParams
+	mockStore.EXPECT().CreateImportMetrics(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateImportMetricsParams) (int64, error) {
+			saved = arg
+			return int64(78), nil
+		})
+
+	// WHEN
+	_, _, _, importID, err := service.ImportFullTender(ctx, makeMinimalPayload(), []byte(`{}`))
+
+	// THEN
+	require.Error(t, err)
+	assert.Equal(t, int64(78), importID)
+	assert.Equal(t, "failed", saved.Status)
+	assert.False(t, saved.TenderID.Valid)
+	assert.Contains(t, saved.ErrorMessage.String, "connection refused")
+	assert.Equal(t, float64(0), saved.CommitMs, "callback не выполнялся — коммита не было")
+}
+
+func TestImportFullTender_TraceSaveFails_ImportSucceeds(t *testing.T) {
+	service, mockStore := newTestService(t)
+	ctx := context.Background()
+
+	// GIVEN CreateImportMetrics fails
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			setupCoreTenderExpectations(mock)
+			setupRawDataExpectations(mock, 100)
+		}),
+	)
+	mockStore.EXPECT().CreateImportMetrics(gomock.Any(), gomock.Any()).
+		Return(int64(0), errors.New("relation \"import_metrics\" does not exist"))
+
+	// WHEN
+	tenderID, _, _, importID, err := service.ImportFullTender(ctx, makeMinimalPayload(), []byte(`{}`))
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), tenderID)
+	assert.Equal(t, int64(0), importID)
+}
+
+func TestGetImportTrace_Found(t *testing.T) {
+	service, mockStore := newTestService(t)
+
+	// GIVEN a stored trace
+	mockStore.EXPECT().GetImportMetrics(gomock.Any(), int64(5)).Return(db.ImportMetric{
+		ID:             5,
+		TenderID:       sql.NullInt64{Int64: 100, Valid: true},
+		EtpID:          "ETP-TEST-001",
+		Status:         "success",
+		PayloadBytes:   2048,
+		LotsCount:      1,
+		PositionsCount: 3,
+		CacheHits:      2,
+		CacheMisses:    1,
+		PositionsMs:    12.5,
+		CacheLookupMs:  4.25,
+		TotalMs:        30,
+		LotTimings:     json.RawMessage(`[{"lot_key":"lot-1","duration_ms":20,"proposals_count":2,"positions_count":3}]`),
+		StartedAt:      now,
+	}, nil)
+
+	// WHEN
+	trace, err := service.GetImportTrace(context.Background(), 5)
+
+	// THEN
+	require.NoError(t, err)
+	require.NotNil(t, trace.TenderID)
+	assert.Equal(t, int64(100), *trace.TenderID)
+	assert.Nil(t, trace.Error)
+	assert.Equal(t, int32(2), trace.CacheHits)
+	assert.Equal(t, 4.25, trace.CacheLookupMs)
+	assert.Equal(t, []api_models.ImportLotTiming{
+		{LotKey: "lot-1", DurationMs: 20, ProposalsCount: 2, PositionsCount: 3},
+	}, trace.Lots)
+}
+
+func TestGetImportTrace_NotFound(t *testing.T) {
+	service, mockStore := newTestService(t)
+
+	// GIVEN no trace with this ID
+	mockStore.EXPECT().GetImportMetrics(gomock.Any(), int64(404)).
+		Return(db.ImportMetric{}, sql.ErrNoRows)
+
+	// WHEN
+	trace, err := service.GetImportTrace(context.Background(), 404)
+
+	// THEN
+	assert.Nil(t, trace)
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+// ============================================================================
+// Stale Rows Reconciliation TESTS
+// ============================================================================
+
+func TestImportFullTender_ReconcileStaleRows_DeletesMissingKeys(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	service.reconcileStaleRows = true
+	ctx := context.Background()
+
+	// GIVEN a re-import of a lot whose proposal now has only pos-1 and sum-1
+	payload := makePayloadWithOneLot()
+	lotDBID := int64(150)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			setupCoreTenderExpectations(mock)
+			mock.ExpectQuery("INSERT INTO lots").
+				WillReturnRows(sqlmock.NewRows(lotColumns).
+					AddRow(lotDBID, "lot-1", "Лот №1 — Отделочные работы", nil, int64(100), now, now))
+			proposalDBID := setupBaselineProposalExpectations(mock, lotDBID)
+			setupPositionExpectations(mock, proposalDBID)
+			setupSummaryExpectations(mock, proposalDBID)
+			// Reconciliation: two old positions and one old summary line are gone
+			mock.ExpectExec("DELETE FROM position_items").
+				WithArgs(proposalDBID, sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+			mock.ExpectExec("DELETE FROM proposal_summary_lines").
+				WithArgs(proposalDBID, sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			setupRawDataExpectations(mock, 100)
+		}),
+	)
+
+	// WHEN
+	tenderID, _, _, _, err := service.ImportFullTender(ctx, payload, []byte(`{}`))
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), tenderID)
+}
+
+func TestReconcileProposalItems_AccumulatesCounts(t *testing.T) {
+	service, _ := newTestService(t)
+	mock, q, cleanup := newMockQueries(t)
+	defer cleanup()
+
+	items := api_models.ContractorItemsContainer{
+		Positions: map[string]api_models.PositionItem{"pos-2": {}, "pos-1": {}},
+	}
+	mock.ExpectExec("DELETE FROM position_items").
+		WithArgs(int64(200), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	// Nil Summary → empty key list → all summary lines of the proposal are stale
+	mock.ExpectExec("DELETE FROM proposal_summary_lines").
+		WithArgs(int64(200), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	trace := newImportTrace()
+	trace.stalePositionsDeleted = 1
+
+	err := service.reconcileProposalItems(context.Background(), q, trace, 200, items)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), trace.stalePositionsDeleted)
+	assert.Equal(t, int64(2), trace.staleSummaryLinesDeleted)
+}
+
+func TestImportFullTender_ReconcileStaleRows_DeleteFails_ReturnsError(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	service.reconcileStaleRows = true
+	ctx := context.Background()
+
+	// GIVEN reconciliation enabled and the position delete fails
+	payload := makePayloadWithOneLot()
+	lotDBID := int64(150)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			setupCoreTenderExpectations(mock)
+			mock.ExpectQuery("INSERT INTO lots").
+				WillReturnRows(sqlmock.NewRows(lotColumns).
+					AddRow(lotDBID, "lot-1", "Лот №1 — Отделочные работы", nil, int64(100), now, now))
+			proposalDBID := setupBaselineProposalExpectations(mock, lotDBID)
+			setupPositionExpectations(mock, proposalDBID)
+			setupSummaryExpectations(mock, proposalDBID)
+			mock.ExpectExec("DELETE FROM position_items").
+				WillReturnError(errors.New("lock timeout"))
+		}),
+	)
+
+	// WHEN
+	tenderID, _, _, _, err := service.ImportFullTender(ctx, payload, []byte(`{}`))
+
+	// THEN
+	require.Error(t, err)
+	assert.Equal(t, int64(0), tenderID)
+	assert.Contains(t, err.Error(), "устаревшие позиции")
+}
+
+// alertMailer запоминает письма оповещений.
+type alertMailer struct {
+	mu   sync.Mutex
+	sent []mailer.Message
+}
+
+func (m *alertMailer) Send(_ context.Context, msg mailer.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func newServiceWithAlerts(t *testing.T) (*TenderImportService, *db.MockStore, *notifications.Service, *alertMailer) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	mockStore.EXPECT().CreateImportMetrics(gomock.Any(), gomock.Any()).Return(int64(77), nil).AnyTimes()
+	logger := testutil.NewMockLogger()
+	m := &alertMailer{}
+	notifier := notifications.NewService(m, config.MailConfig{From: "noreply@example.com"}, config.NotificationsConfig{
+		AppURL:                  "https://tenders.example.com",
+		ImportFailureRecipients: []string{"ops@example.com"},
+		ImportFailureCooldown:   time.Minute,
+	}, logger)
+	service := NewTenderImportService(mockStore, nil, logger, entities.NewEntityManager(logger), config.ImportConfig{}, config.OutboxConfig{}, events.Noop{}, notifier)
+	return service, mockStore, notifier, m
+}
+
+func TestImportFullTender_FailureAlert(t *testing.T) {
+	service, mockStore, notifier, m := newServiceWithAlerts(t)
+	payload := makeMinimalPayload()
+
+	// GIVEN ExecTx fails with a DB error
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).Return(errors.New("deadlock detected"))
+
+	// WHEN
+	_, _, _, _, err := service.ImportFullTender(context.Background(), payload, []byte(`{}`))
+	require.Error(t, err)
+
+	// THEN
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, notifier.Wait(ctx))
+	require.Len(t, m.sent, 1)
+	assert.Equal(t, []string{"ops@example.com"}, m.sent[0].To)
+	assert.Equal(t, "Ошибка импорта тендера "+payload.TenderID, m.sent[0].Subject)
+	assert.Contains(t, m.sent[0].Body, "(import_id=77)")
+	assert.Contains(t, m.sent[0].Body, "deadlock detected")
+}
+
+func TestImportFullTender_ConflictIsNotAlerted(t *testing.T) {
+	service, mockStore, notifier, m := newServiceWithAlerts(t)
+	payload := makeMinimalPayload()
+
+	// GIVEN ExecTx fails with a ConflictError
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).
+		Return(apierrors.NewConflictError("тендер принадлежит другой организации", nil))
+
+	// WHEN
+	_, _, _, _, err := service.ImportFullTender(context.Background(), payload, []byte(`{}`))
+	require.Error(t, err)
+
+	// THEN
+	require.NoError(t, notifier.Wait(context.Background()))
+	assert.Empty(t, m.sent)
+}
+
+func newServiceWithOutbox(t *testing.T) (*TenderImportService, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	mockStore.EXPECT().CreateImportMetrics(gomock.Any(), gomock.Any()).Return(int64(1), nil).AnyTimes()
+	logger := testutil.NewMockLogger()
+	outboxCfg := config.OutboxConfig{WorkerCallbackURL: "http://worker:8000/events"}
+	service := NewTenderImportService(mockStore, nil, logger, entities.NewEntityManager(logger), config.ImportConfig{}, outboxCfg, events.Noop{}, nil)
+	return service, mockStore
+}
+
+// setupOneLotExpectations — импорт makePayloadWithOneLot до сохранения сырого JSON включительно.
+func setupOneLotExpectations(mock sqlmock.Sqlmock, lotDBID int64) {
+	setupCoreTenderExpectations(mock)
+	mock.ExpectQuery("INSERT INTO lots").
+		WillReturnRows(sqlmock.NewRows(lotColumns).
+			AddRow(lotDBID, "lot-1", "Лот №1 — Отделочные работы", nil, int64(100), now, now))
+	proposalDBID := setupBaselineProposalExpectations(mock, lotDBID)
+	setupPositionExpectations(mock, proposalDBID)
+	setupSummaryExpectations(mock, proposalDBID)
+	setupRawDataExpectations(mock, 100)
+}
+
+func TestImportFullTender_Outbox_EnqueuesCatalogItemsPending(t *testing.T) {
+	service, mockStore := newServiceWithOutbox(t)
+	payload := makePayloadWithOneLot()
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			setupOneLotExpectations(mock, 150)
+			// THEN: событие пишется той же транзакцией
+			mock.ExpectQuery("INSERT INTO outbox_events").
+				WithArgs("catalog.items_pending", int64(100), sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(9)))
+		}),
+	)
+
+	_, _, anyNewPending, _, err := service.ImportFullTender(context.Background(), payload, []byte(`{}`))
+
+	require.NoError(t, err)
+	assert.True(t, anyNewPending)
+}
+
+func TestImportFullTender_Outbox_InsertFails_ReturnsError(t *testing.T) {
+	service, mockStore := newServiceWithOutbox(t)
+	payload := makePayloadWithOneLot()
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			setupOneLotExpectations(mock, 150)
+			mock.ExpectQuery("INSERT INTO outbox_events").WillReturnError(errors.New("disk full"))
+		}),
+	)
+
+	_, _, _, _, err := service.ImportFullTender(context.Background(), payload, []byte(`{}`))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "EnqueueOutboxEvent")
+}
+
+func TestImportFullTender_Outbox_NoPendingItems_NoEvent(t *testing.T) {
+	service, mockStore := newServiceWithOutbox(t)
+
+	// sqlmock завершит тест с ошибкой при неожиданном INSERT INTO outbox_events
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			setupCoreTenderExpectations(mock)
+			setupRawDataExpectations(mock, 100)
+		}),
+	)
+
+	_, _, anyNewPending, _, err := service.ImportFullTender(context.Background(), makeMinimalPayload(), []byte(`{}`))
+
+	require.NoError(t, err)
+	assert.False(t, anyNewPending)
+}
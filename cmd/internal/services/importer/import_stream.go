@@ -0,0 +1,158 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+)
+
+// StreamImportResult — результат потокового импорта: ответ API и ETP ID тендера,
+// который при потоковом разборе вызывающему заранее неизвестен.
+type StreamImportResult struct {
+	TenderID string
+	api_models.ImportTenderResponse
+}
+
+// ImportTenderStream импортирует тендер из потока JSON, не загружая его в память
+// целиком. Используется для больших payload, которые ImportFullTender держал бы
+// в памяти дважды: исходными байтами и разобранной структурой.
+//
+// Поведение:
+//  1. Тело копируется во временный файл — транзакция не ждёт медленную загрузку,
+//     а исходный JSON остаётся доступен для tender_raw_data.
+//  2. Первый проход по файлу проверяет формат и валидирует тендер и каждый лот,
+//     ничего не записывая: невалидный payload не открывает транзакцию.
+//  3. Второй проход в одной транзакции сохраняет тендер и лоты по одному, как
+//     ImportFullTender; в памяти одновременно находится только один лот.
+//  4. Исходный JSON читается из файла только для UPSERT в tender_raw_data и
+//     истории — это единственный момент, когда он целиком находится в памяти.
+//
+// Возвращает *apierrors.ValidationError для невалидного JSON. Ошибки чтения body
+// (например, *http.MaxBytesError) возвращаются обёрнутыми.
+func (s *TenderImportService) ImportTenderStream(ctx context.Context, body io.Reader) (*StreamImportResult, error) {
+	spool, err := os.CreateTemp("", "tender-import-*.json")
+	if err != nil {
+		return nil, fmt.Errorf("не удалось создать временный файл импорта: %w", err)
+	}
+	defer func() {
+		spool.Close()
+		os.Remove(spool.Name())
+	}()
+
+	size, err := io.Copy(spool, body)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения тела запроса: %w", err)
+	}
+	s.logger.Infof("Потоковый импорт: тело запроса сохранено во временный файл, размер: %d байт", size)
+
+	// Проход 1: только валидация
+	var etpID string
+	lotsCount := 0
+	if err := decodeSpool(spool, func(header *api_models.FullTenderData) error {
+		etpID = header.TenderID
+		return nil
+	}, func(string, *api_models.Lot) error {
+		lotsCount++
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Начинаем потоковый импорт тендера %s, размер JSON: %d байт, количество лотов: %d", etpID, size, lotsCount)
+
+	var newTenderDBID int64
+	lotIDs := make(map[string]int64)
+	anyNewPendingItems := false
+	trace := newImportTrace()
+	var callbackDone time.Time
+
+	// Проход 2: запись в БД
+	txErr := s.execTx(ctx, func(qtx db.Querier) error {
+		trace.txWait = time.Since(trace.startedAt)
+		defer func() { callbackDone = time.Now() }()
+		s.logger.Debug("Транзакция начата")
+
+		var lotsStart time.Time
+		lotsDone := 0
+		err := decodeSpool(spool, func(header *api_models.FullTenderData) error {
+			s.logger.Debug("Шаг 1: Обработка основной информации о тендере")
+			stepStart := time.Now()
+			dbTender, err := s.processCoreTenderData(ctx, qtx, header)
+			trace.core = time.Since(stepStart)
+			if err != nil {
+				s.logger.Errorf("Ошибка на шаге 1: %v", err)
+				return err
+			}
+			newTenderDBID = dbTender.ID
+			s.logger.Debugf("Тендер создан с DB ID: %d", newTenderDBID)
+			s.logger.Debugf("Шаг 2: Потоковая обработка %d лотов", lotsCount)
+			lotsStart = time.Now()
+			return nil
+		}, func(lotKey string, lotAPI *api_models.Lot) error {
+			lotDBID, lotHasNewPending, err := s.processLot(ctx, qtx, trace, newTenderDBID, lotKey, *lotAPI)
+			if err != nil {
+				s.logger.Errorf("Ошибка при обработке лота '%s': %v", lotKey, err)
+				return fmt.Errorf("ошибка при обработке лота '%s': %w", lotKey, err)
+			}
+			lotIDs[lotKey] = lotDBID
+			if lotHasNewPending {
+				anyNewPendingItems = true
+			}
+			s.logger.Debugf("Лот %s обработан, DB ID: %d", lotKey, lotDBID)
+			lotsDone++
+			reportProgress(ctx, etpID, lotsDone, lotsCount)
+			return nil
+		})
+		if !lotsStart.IsZero() {
+			trace.lots = time.Since(lotsStart)
+		}
+		if err != nil {
+			return err
+		}
+		s.logger.Debug("Все лоты обработаны успешно")
+
+		rawJSON, err := os.ReadFile(spool.Name())
+		if err != nil {
+			return fmt.Errorf("не удалось прочитать временный файл импорта: %w", err)
+		}
+		if err := s.saveRawJSON(ctx, qtx, trace, newTenderDBID, rawJSON); err != nil {
+			return err
+		}
+		if err := s.enqueueCatalogItemsPending(ctx, qtx, etpID, newTenderDBID, lotIDs, anyNewPendingItems); err != nil {
+			return err
+		}
+		s.logger.Debug("Callback завершен, выполняем коммит транзакции")
+		return nil
+	})
+
+	importID, err := s.finishImport(ctx, trace, callbackDone, etpID, newTenderDBID, int(size), lotIDs, anyNewPendingItems, txErr)
+	if err != nil {
+		return nil, err
+	}
+	return &StreamImportResult{
+		TenderID: etpID,
+		ImportTenderResponse: api_models.ImportTenderResponse{
+			TenderDBID:             newTenderDBID,
+			LotIDsMap:              lotIDs,
+			NewCatalogItemsPending: anyNewPendingItems,
+			ImportID:               importID,
+		},
+	}, nil
+}
+
+// decodeSpool разбирает временный файл импорта с начала.
+func decodeSpool(
+	spool *os.File,
+	onHeader func(*api_models.FullTenderData) error,
+	onLot func(string, *api_models.Lot) error,
+) error {
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("не удалось перечитать временный файл импорта: %w", err)
+	}
+	return decodeTenderStream(spool, onHeader, onLot)
+}
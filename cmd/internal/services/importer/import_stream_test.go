@@ -0,0 +1,175 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR STREAMING IMPORT (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. OOM on huge tenders — lots must be handed over one by one, not as a full map
+2. Half-imported tenders — a broken or invalid payload must fail before any write
+3. Wrong status codes — format errors must be 400, an oversized body must stay
+   recognizable as *http.MaxBytesError (413), DB errors must be wrapped (500)
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Decoding
+- GIVEN a JSON with tender fields, unknown keys and two lots
+  WHEN decodeTenderStream is called
+  THEN onHeader is called once with the fields, then onLot for each lot in order
+
+SCENARIO 2: Format and validation errors → *apierrors.ValidationError
+- GIVEN truncated JSON, a non-object, trailing data, no lots, empty lots,
+  a repeated lot key, a tender field after lots, an invalid header or lot
+
+SCENARIO 3: ImportTenderStream
+- GIVEN an invalid payload → ValidationError, no transaction is opened
+- GIVEN a body over the http.MaxBytesReader limit → error wraps *http.MaxBytesError
+- GIVEN ExecTx fails → wrapped error "транзакция импорта тендера провалена"
+*/
+
+func TestDecodeTenderStream_HeaderThenLotsInOrder(t *testing.T) {
+	lot := makePayloadWithOneLot().LotsData["lot-1"]
+	body := streamHeader(t) + `"parser_version":{"v":2},` + streamLots(t, `"lot-2":%s,"lot-1":%s`)
+
+	var header *api_models.FullTenderData
+	var keys []string
+	err := decodeTenderStream(strings.NewReader(body), func(h *api_models.FullTenderData) error {
+		require.Nil(t, header, "onHeader must be called once")
+		require.Empty(t, keys, "onHeader must precede lots")
+		header = h
+		return nil
+	}, func(lotKey string, l *api_models.Lot) error {
+		assert.Equal(t, lot.LotTitle, l.LotTitle)
+		keys = append(keys, lotKey)
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, header)
+	assert.Equal(t, "ETP-TEST-001", header.TenderID)
+	assert.Equal(t, "Иванов И.И.", header.ExecutorData.ExecutorName)
+	assert.Equal(t, []string{"lot-2", "lot-1"}, keys)
+}
+
+func TestDecodeTenderStream_CallbackErrorStopsDecoding(t *testing.T) {
+	body := streamHeader(t) + streamLots(t, `"lot-1":%s,"lot-2":%s`)
+	calls := 0
+
+	err := decodeTenderStream(strings.NewReader(body), func(*api_models.FullTenderData) error {
+		return nil
+	}, func(string, *api_models.Lot) error {
+		calls++
+		return errors.New("db down")
+	})
+
+	require.EqualError(t, err, "db down")
+	assert.Equal(t, 1, calls)
+}
+
+func TestDecodeTenderStream_ValidationErrors(t *testing.T) {
+	header := streamHeader(t)
+	valid := header + streamLots(t, `"lot-1":%s`)
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{"truncated", valid[:len(valid)/2], "некорректный JSON"},
+		{"not an object", `[1,2]`, "ожидался '{'"},
+		{"trailing data", valid + `{}`, "данные после объекта тендера"},
+		{"no lots", strings.TrimSuffix(header, ",") + `}`, "хотя бы один лот"},
+		{"empty lots", header + `"lots":{}}`, "хотя бы один лот"},
+		{"repeated lot", header + streamLots(t, `"lot-1":%s,"lot-1":%s`), "лот 'lot-1' указан дважды"},
+		{"field after lots", strings.TrimSuffix(valid, "}") + `,"tender_title":"x"}`, "tender_title должно идти в JSON до lots"},
+		{"invalid header", strings.Replace(valid, `"tender_title":"Тестовый тендер"`, `"tender_title":" "`, 1), "tender_title"},
+		{"invalid lot", header + `"lots":{"lot-1":{"lot_title":""}}}`, `lots["lot-1"].lot_title`},
+		{"wrong type", header + `"lots":{"lot-1":[]}}`, "некорректный JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := decodeTenderStream(strings.NewReader(tt.body),
+				func(*api_models.FullTenderData) error { return nil },
+				func(string, *api_models.Lot) error { return nil })
+
+			require.Error(t, err)
+			var validationErr *apierrors.ValidationError
+			assert.True(t, errors.As(err, &validationErr), "want ValidationError, got %T: %v", err, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestImportTenderStream_InvalidPayload_NoTransaction(t *testing.T) {
+	// newTestService без ожиданий: любой вызов store провалит тест
+	service, _ := newTestService(t)
+
+	result, err := service.ImportTenderStream(context.Background(), strings.NewReader(`{"tender_id":"x","lots":{}}`))
+
+	require.Error(t, err)
+	var validationErr *apierrors.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Nil(t, result)
+}
+
+func TestImportTenderStream_BodyTooLarge_KeepsMaxBytesError(t *testing.T) {
+	service, _ := newTestService(t)
+	body := streamHeader(t) + streamLots(t, `"lot-1":%s`)
+	limited := http.MaxBytesReader(httptest.NewRecorder(), io.NopCloser(strings.NewReader(body)), 16)
+
+	_, err := service.ImportTenderStream(context.Background(), limited)
+
+	var tooLarge *http.MaxBytesError
+	require.True(t, errors.As(err, &tooLarge), "got %v", err)
+	assert.Equal(t, int64(16), tooLarge.Limit)
+}
+
+func TestImportTenderStream_ExecTxFails_ReturnsWrappedError(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+
+	result, err := service.ImportTenderStream(context.Background(), strings.NewReader(streamHeader(t)+streamLots(t, `"lot-1":%s`)))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "транзакция импорта тендера провалена")
+	assert.Contains(t, err.Error(), "connection refused")
+	assert.Nil(t, result)
+}
+
+// streamHeader возвращает начало JSON тендера из makeMinimalPayload — поля
+// тендера до "lots", с завершающей запятой.
+func streamHeader(t *testing.T) string {
+	t.Helper()
+	raw := string(marshalPayload(t, makeMinimalPayload()))
+	header, _, found := strings.Cut(raw, `"lots":`)
+	require.True(t, found)
+	return header
+}
+
+// streamLots собирает окончание JSON тендера: объект lots по шаблону, где каждый
+// %s заменяется JSON лота lot-1 из makePayloadWithOneLot.
+func streamLots(t *testing.T, lotsTemplate string) string {
+	t.Helper()
+	lotJSON, err := json.Marshal(makePayloadWithOneLot().LotsData["lot-1"])
+	require.NoError(t, err)
+	return `"lots":{` + strings.ReplaceAll(lotsTemplate, "%s", string(lotJSON)) + `}}`
+}
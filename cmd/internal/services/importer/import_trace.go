@@ -0,0 +1,185 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+// importTrace собирает тайминги этапов одного импорта.
+// Синхронизации нет: при параллельном импорте лотов у каждого лота своя
+// трассировка, которая затем добавляется к общей через merge под мьютексом.
+type importTrace struct {
+	startedAt   time.Time
+	txWait      time.Duration // от вызова ExecTx до входа в callback (ожидание соединения)
+	core        time.Duration
+	lots        time.Duration
+	positions   time.Duration
+	cacheLookup time.Duration
+	rawData     time.Duration
+	commit      time.Duration // от выхода из callback до возврата ExecTx
+	total       time.Duration
+
+	lotTimings     []api_models.ImportLotTiming
+	positionsCount int
+	cacheHits      int
+	cacheMisses    int
+
+	// Предложения подрядчиков, созданные этим импортом (для события tender.imported)
+	newProposals int
+
+	// Сверка при повторном импорте (import.reconcile_stale_rows)
+	stalePositionsDeleted    int64
+	staleSummaryLinesDeleted int64
+}
+
+func newImportTrace() *importTrace {
+	return &importTrace{startedAt: time.Now()}
+}
+
+// currentLot возвращает тайминг обрабатываемого лота (последнего начатого).
+func (t *importTrace) currentLot() *api_models.ImportLotTiming {
+	if len(t.lotTimings) == 0 {
+		return nil
+	}
+	return &t.lotTimings[len(t.lotTimings)-1]
+}
+
+// addPosition учитывает обработанную позицию и её время.
+func (t *importTrace) addPosition(d time.Duration) {
+	t.positions += d
+	t.positionsCount++
+	if lot := t.currentLot(); lot != nil {
+		lot.PositionsCount++
+	}
+}
+
+// merge добавляет к трассировке импорта трассировку одного лота,
+// сохранённого в отдельной транзакции (см. parallel_lots.go). Время позиций
+// суммируется по лотам и может превышать время этапа лотов.
+func (t *importTrace) merge(lot *importTrace) {
+	t.positions += lot.positions
+	t.cacheLookup += lot.cacheLookup
+	t.lotTimings = append(t.lotTimings, lot.lotTimings...)
+	t.positionsCount += lot.positionsCount
+	t.cacheHits += lot.cacheHits
+	t.cacheMisses += lot.cacheMisses
+	t.newProposals += lot.newProposals
+	t.stalePositionsDeleted += lot.stalePositionsDeleted
+	t.staleSummaryLinesDeleted += lot.staleSummaryLinesDeleted
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// saveImportTrace сохраняет тайминги импорта вне транзакции импорта, чтобы
+// записать их и для неудачных импортов. Ошибка сохранения не ломает импорт:
+// она логируется, а вызывающему возвращается import_id = 0.
+func (s *TenderImportService) saveImportTrace(
+	ctx context.Context,
+	trace *importTrace,
+	etpID string,
+	tenderID int64,
+	payloadBytes int,
+	importErr error,
+) int64 {
+	lotTimings, err := json.Marshal(trace.lotTimings)
+	if err != nil {
+		s.logger.Warnf("Не удалось сериализовать тайминги лотов: %v", err)
+		lotTimings = []byte("[]")
+	}
+
+	params := db.CreateImportMetricsParams{
+		EtpID:          etpID,
+		Status:         "success",
+		PayloadBytes:   int64(payloadBytes),
+		LotsCount:      int32(len(trace.lotTimings)),
+		PositionsCount: int32(trace.positionsCount),
+		CacheHits:      int32(trace.cacheHits),
+		CacheMisses:    int32(trace.cacheMisses),
+		TxWaitMs:       durationMs(trace.txWait),
+		CoreMs:         durationMs(trace.core),
+		LotsMs:         durationMs(trace.lots),
+		PositionsMs:    durationMs(trace.positions),
+		CacheLookupMs:  durationMs(trace.cacheLookup),
+		RawDataMs:      durationMs(trace.rawData),
+		CommitMs:       durationMs(trace.commit),
+		TotalMs:        durationMs(trace.total),
+		LotTimings:     lotTimings,
+		StartedAt:      trace.startedAt,
+	}
+	if importErr != nil {
+		// Транзакция откатилась — тендера с этим ID может не быть
+		params.Status = "failed"
+		params.ErrorMessage = sql.NullString{String: importErr.Error(), Valid: true}
+	} else {
+		params.TenderID = sql.NullInt64{Int64: tenderID, Valid: true}
+	}
+
+	// Контекст импорта мог истечь — это как раз тот случай, который нужно записать
+	importID, err := s.store.CreateImportMetrics(context.WithoutCancel(ctx), params)
+	if err != nil {
+		s.logger.Warnf("Не удалось сохранить тайминги импорта тендера %s: %v", etpID, err)
+		return 0
+	}
+
+	s.logger.Infof("Тайминги импорта %s (import_id=%d): всего %.1f мс, ожидание tx %.1f мс, core %.1f мс, лоты %.1f мс, позиции %.1f мс (кэш %.1f мс, hit/miss %d/%d), raw %.1f мс, коммит %.1f мс",
+		etpID, importID, params.TotalMs, params.TxWaitMs, params.CoreMs, params.LotsMs,
+		params.PositionsMs, params.CacheLookupMs, params.CacheHits, params.CacheMisses,
+		params.RawDataMs, params.CommitMs)
+	return importID
+}
+
+// GetImportTrace возвращает тайминги импорта по ID записи import_metrics.
+func (s *TenderImportService) GetImportTrace(ctx context.Context, importID int64) (*api_models.ImportTraceResponse, error) {
+	m, err := s.store.GetImportMetrics(ctx, importID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewNotFoundError("import.trace_not_found", importID)
+		}
+		return nil, fmt.Errorf("ошибка получения трассировки импорта: %w", err)
+	}
+
+	lots := []api_models.ImportLotTiming{}
+	if len(m.LotTimings) > 0 {
+		if err := json.Unmarshal(m.LotTimings, &lots); err != nil {
+			return nil, fmt.Errorf("некорректные тайминги лотов в import_metrics %d: %w", importID, err)
+		}
+	}
+
+	resp := &api_models.ImportTraceResponse{
+		ID:             m.ID,
+		EtpID:          m.EtpID,
+		Status:         m.Status,
+		PayloadBytes:   m.PayloadBytes,
+		LotsCount:      m.LotsCount,
+		PositionsCount: m.PositionsCount,
+		CacheHits:      m.CacheHits,
+		CacheMisses:    m.CacheMisses,
+		TxWaitMs:       m.TxWaitMs,
+		CoreMs:         m.CoreMs,
+		LotsMs:         m.LotsMs,
+		PositionsMs:    m.PositionsMs,
+		CacheLookupMs:  m.CacheLookupMs,
+		RawDataMs:      m.RawDataMs,
+		CommitMs:       m.CommitMs,
+		TotalMs:        m.TotalMs,
+		Lots:           lots,
+		StartedAt:      m.StartedAt,
+	}
+	if m.TenderID.Valid {
+		resp.TenderID = &m.TenderID.Int64
+	}
+	if m.ErrorMessage.Valid {
+		resp.Error = &m.ErrorMessage.String
+	}
+	return resp, nil
+}
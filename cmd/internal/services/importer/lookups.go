@@ -0,0 +1,204 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/entities"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/util"
+)
+
+// positionLookups — справочники позиций одного лота, загруженные до
+// сохранения позиций (import.prefetch_lookups). Одни и те же работы и единицы
+// повторяются в базовом предложении и в предложении каждого подрядчика: без
+// предзагрузки каждое их появление — три запроса (единица, позиция каталога,
+// matching_cache).
+//
+// Промах по единице или позиции каталога уходит в построчный GetOrCreate*
+// (создание, обновление описания), а его результат запоминается для
+// следующих предложений лота. Отсутствие записи matching_cache для
+// загруженного хеша — настоящий промах кэша. Живёт в транзакции одного лота
+// и не разделяется между горутинами. nil — предзагрузка выключена.
+type positionLookups struct {
+	units   map[string]int64 // нормализованное написание → unit_id
+	catalog map[catalogKey]db.CatalogPosition
+	matches map[string]sql.NullInt64 // хеш названия → catalog_position_id; Valid=false — записи нет
+}
+
+// catalogKey — ключ позиции каталога, как в GetCatalogPositionByTitleAndUnit.
+type catalogKey struct {
+	title  string
+	unitID sql.NullInt64
+}
+
+// prefetchLotLookups собирает различные единицы измерения и названия позиций
+// всех предложений лота и загружает их тремя запросами (пустой набор — без
+// запроса). Время запроса к matching_cache учитывается в trace.cacheLookup.
+func (s *TenderImportService) prefetchLotLookups(
+	ctx context.Context,
+	qtx db.Querier,
+	trace *importTrace,
+	lotAPI api_models.Lot,
+) (*positionLookups, error) {
+	if !s.prefetchLookups {
+		return nil, nil
+	}
+	unitNames, titles, positionTitles := collectLotLookupKeys(lotAPI)
+	lookups := &positionLookups{
+		units:   make(map[string]int64, len(unitNames)),
+		catalog: make(map[catalogKey]db.CatalogPosition, len(titles)),
+		matches: make(map[string]sql.NullInt64, len(positionTitles)),
+	}
+	start := time.Now()
+
+	if len(unitNames) > 0 {
+		rows, err := qtx.ListUnitIDsByNames(ctx, unitNames)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка предзагрузки единиц измерения: %w", err)
+		}
+		for _, row := range rows {
+			lookups.units[row.LookupName] = row.UnitID
+		}
+	}
+
+	if len(titles) > 0 {
+		positions, err := qtx.ListCatalogPositionsByTitles(ctx, titles)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка предзагрузки позиций каталога: %w", err)
+		}
+		for _, catPos := range positions {
+			lookups.catalog[catalogKey{title: catPos.StandardJobTitle, unitID: catPos.UnitID}] = catPos
+		}
+	}
+
+	if len(positionTitles) > 0 {
+		hashes := make([]string, 0, len(positionTitles))
+		for _, title := range positionTitles {
+			hash := util.GetSHA256Hash(title)
+			hashes = append(hashes, hash)
+			lookups.matches[hash] = sql.NullInt64{}
+		}
+		cacheStart := time.Now()
+		cached, err := qtx.ListActiveMatchingCacheByHashes(ctx, hashes)
+		trace.cacheLookup += time.Since(cacheStart)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка предзагрузки matching_cache: %w", err)
+		}
+		for _, match := range cached {
+			lookups.matches[match.JobTitleHash] = sql.NullInt64{Int64: match.CatalogPositionID, Valid: true}
+		}
+	}
+
+	s.logger.Debugf("Справочники лота загружены за %v: единиц %d из %d, позиций каталога %d (названий %d), записей matching_cache %d",
+		time.Since(start), len(lookups.units), len(unitNames), len(lookups.catalog), len(titles), len(lookups.matches))
+	return lookups, nil
+}
+
+// collectLotLookupKeys возвращает отсортированные без повторов нормализованные
+// написания единиц, названия позиций каталога (standard_job_title) и названия
+// позиций-работ (не глав), для которых импорт читает matching_cache.
+func collectLotLookupKeys(lotAPI api_models.Lot) (unitNames, titles, positionTitles []string) {
+	units := make(map[string]struct{})
+	allTitles := make(map[string]struct{})
+	works := make(map[string]struct{})
+
+	collect := func(proposal api_models.ContractorProposalDetails) {
+		for _, posAPI := range proposal.ContractorItems.Positions {
+			if posAPI.Unit != nil {
+				if name := entities.NormalizeUnitName(*posAPI.Unit); name != "" {
+					units[name] = struct{}{}
+				}
+			}
+			title := entities.StandardJobTitle(posAPI)
+			if title == "" {
+				continue
+			}
+			allTitles[title] = struct{}{}
+			if !posAPI.IsChapter {
+				works[title] = struct{}{}
+			}
+		}
+	}
+	collect(lotAPI.BaseLineProposal)
+	for _, proposal := range lotAPI.ProposalData {
+		collect(proposal)
+	}
+	return sortedKeys(units), sortedKeys(allTitles), sortedKeys(works)
+}
+
+// resolveUnit — GetOrCreateUnitOfMeasurement с предзагруженными единицами лота.
+func (s *TenderImportService) resolveUnit(
+	ctx context.Context,
+	qtx db.Querier,
+	lookups *positionLookups,
+	apiUnitName *string,
+) (sql.NullInt64, error) {
+	var name string
+	if apiUnitName != nil {
+		name = entities.NormalizeUnitName(*apiUnitName)
+	}
+	if lookups != nil && name != "" {
+		if unitID, ok := lookups.units[name]; ok {
+			return sql.NullInt64{Int64: unitID, Valid: true}, nil
+		}
+	}
+
+	unitID, err := s.Entities.GetOrCreateUnitOfMeasurement(ctx, qtx, apiUnitName)
+	if err == nil && lookups != nil && unitID.Valid {
+		lookups.units[name] = unitID.Int64
+	}
+	return unitID, err
+}
+
+// resolveCatalogPosition — GetOrCreateCatalogPosition с предзагруженными
+// позициями лота. Позиция с другим описанием идёт построчным путём: его
+// обновляет GetOrCreateCatalogPosition.
+func (s *TenderImportService) resolveCatalogPosition(
+	ctx context.Context,
+	qtx db.Querier,
+	lookups *positionLookups,
+	posAPI api_models.PositionItem,
+	lotTitle string,
+	unitID sql.NullInt64,
+) (db.CatalogPosition, bool, error) {
+	key := catalogKey{title: entities.StandardJobTitle(posAPI), unitID: unitID}
+	if lookups != nil && key.title != "" {
+		if catPos, ok := lookups.catalog[key]; ok && catPos.Description.String == posAPI.JobTitle {
+			return catPos, false, nil
+		}
+	}
+
+	catPos, isNewPendingItem, err := s.Entities.GetOrCreateCatalogPosition(ctx, qtx, posAPI, lotTitle, unitID)
+	if err == nil && lookups != nil && catPos.ID != 0 {
+		lookups.catalog[key] = catPos
+	}
+	return catPos, isNewPendingItem, err
+}
+
+// activeMatchingCache — GetActiveMatchingCache с предзагруженными записями
+// лота: возвращает catalog_position_id из кэша или sql.ErrNoRows.
+func (s *TenderImportService) activeMatchingCache(
+	ctx context.Context,
+	qtx db.Querier,
+	lookups *positionLookups,
+	hash string,
+) (int64, error) {
+	if lookups != nil {
+		if match, ok := lookups.matches[hash]; ok {
+			if !match.Valid {
+				return 0, sql.ErrNoRows
+			}
+			return match.Int64, nil
+		}
+	}
+
+	cached, err := qtx.GetActiveMatchingCache(ctx, hash)
+	if err != nil {
+		return 0, err
+	}
+	return cached.CatalogPositionID, nil
+}
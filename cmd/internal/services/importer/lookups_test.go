@@ -0,0 +1,207 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/util"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR LOT LOOKUP PREFETCH (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Slow imports — the same work repeated in every proposal of a lot must not
+   cost three queries each time
+2. Wrong matching — prefetched data must give the same catalog links as the
+   per-row lookups
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Everything is known
+- GIVEN import.prefetch_lookups and a lot whose baseline and contractor quote the same work
+  WHEN processLot is called
+  THEN units, catalog positions and matching_cache are read once for the lot
+  AND positions are saved without per-row lookups
+
+SCENARIO 2: Misses
+- GIVEN a unit and a catalog position missing from the prefetch
+  THEN the first proposal creates them through GetOrCreate*
+  AND the next proposal reuses them without queries
+  AND a hash missing from matching_cache is a cache miss without a per-row query
+
+SCENARIO 3: Keys
+- GIVEN positions with repeated units and titles, chapters and empty titles
+  THEN units and titles are collected once and sorted; chapters are not looked up in matching_cache
+*/
+
+// makeLotWithSharedPosition — лот, в котором базовое предложение и подрядчик
+// содержат одну и ту же работу.
+func makeLotWithSharedPosition() api_models.Lot {
+	unitName := "м2"
+	jobTitleNorm := "устройство полов"
+	position := api_models.PositionItem{
+		Number:             "1",
+		JobTitle:           "Устройство полов",
+		JobTitleNormalized: &jobTitleNorm,
+		Unit:               &unitName,
+	}
+	return api_models.Lot{
+		LotTitle: "Лот №1",
+		BaseLineProposal: api_models.ContractorProposalDetails{
+			ContractorItems: api_models.ContractorItemsContainer{
+				Positions: map[string]api_models.PositionItem{"pos-1": position},
+			},
+		},
+		ProposalData: map[string]api_models.ContractorProposalDetails{
+			"contractor-1": {
+				Title:         "ООО Строитель",
+				Inn:           "1234567890",
+				Address:       "г. Москва",
+				Accreditation: "Аккредитован",
+				ContractorItems: api_models.ContractorItemsContainer{
+					Positions: map[string]api_models.PositionItem{"pos-1": position},
+				},
+			},
+		},
+	}
+}
+
+func expectLotAndPrefetch(mock sqlmock.Sqlmock, units, catalog, cache *sqlmock.Rows) {
+	mock.ExpectQuery("INSERT INTO lots").
+		WillReturnRows(sqlmock.NewRows(lotColumns).
+			AddRow(int64(150), "lot-1", "Лот №1", nil, int64(100), now, now))
+	mock.ExpectQuery("FROM units_of_measurement u\\s+WHERE u.normalized_name = ANY").WillReturnRows(units)
+	mock.ExpectQuery("FROM catalog_positions\\s+WHERE standard_job_title = ANY").WillReturnRows(catalog)
+	mock.ExpectQuery("FROM matching_cache\\s+WHERE\\s+job_title_hash = ANY").WillReturnRows(cache)
+}
+
+// expectContractorProposal: подрядчик уже есть и не изменился, предложение ID 201.
+func expectContractorProposal(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT .+ FROM contractors WHERE inn").
+		WithArgs("1234567890").
+		WillReturnRows(sqlmock.NewRows(contractorColumns).
+			AddRow(int64(51), "ООО Строитель", "1234567890", "г. Москва", now, now, "accredited", nil, []byte("[]"), "import", nil))
+	mock.ExpectQuery("INSERT INTO proposals").
+		WillReturnRows(sqlmock.NewRows(proposalColumns).
+			AddRow(int64(201), int64(150), int64(51), false, nil, nil, nil, now, now, "RUB"))
+}
+
+func expectUpsertPositionItem(mock sqlmock.Sqlmock, proposalDBID, catalogPositionID int64) {
+	mock.ExpectQuery("INSERT INTO position_items").
+		WithArgs(proposalDBID, sql.NullInt64{Int64: catalogPositionID, Valid: true}, "pos-1",
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sql.NullInt64{Int64: 10, Valid: true},
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(positionItemColumns).
+			AddRow(
+				int64(400), proposalDBID, sql.NullInt64{Int64: catalogPositionID, Valid: true}, "pos-1",
+				sql.NullString{}, sql.NullString{}, sql.NullString{String: "1", Valid: true},
+				sql.NullString{}, "Устройство полов", sql.NullInt64{Int64: 10, Valid: true},
+				sql.NullString{}, sql.NullString{}, sql.NullString{},
+				sql.NullString{}, sql.NullString{}, sql.NullString{}, sql.NullString{},
+				sql.NullString{}, sql.NullString{}, sql.NullString{}, sql.NullString{},
+				sql.NullString{}, false, sql.NullString{},
+				now, now, sql.NullString{}, sql.NullString{}, sql.NullTime{}, sql.NullString{}, sql.NullTime{}, sql.NullString{},
+			))
+}
+
+func TestProcessLot_PrefetchLookups_AllKnown(t *testing.T) {
+	service, _ := setupTestService(t)
+	service.prefetchLookups = true
+	mock, q, cleanup := newMockQueries(t)
+	defer cleanup()
+
+	cachedCatalogPosID := int64(999)
+	expectLotAndPrefetch(mock,
+		sqlmock.NewRows([]string{"lookup_name", "unit_id"}).AddRow("м2", int64(10)),
+		sqlmock.NewRows(catalogPosColumns).
+			AddRow(int64(300), "устройство полов", sql.NullString{String: "Устройство полов", Valid: true}, nil, "POSITION", "active", sql.NullInt64{Int64: 10, Valid: true}, now, now, nil, nil, nil, nil, false, nil, nil),
+		sqlmock.NewRows(matchingCacheColumns).
+			AddRow(util.GetSHA256Hash("устройство полов"), int16(1), sql.NullString{String: "устройство полов", Valid: true}, cachedCatalogPosID, now, sql.NullTime{}, "rag"),
+	)
+	// Дальше — только сохранение: ни одного построчного поиска
+	setupBaselineProposalExpectations(mock, 150)
+	expectUpsertPositionItem(mock, 200, cachedCatalogPosID)
+	expectContractorProposal(mock)
+	expectUpsertPositionItem(mock, 201, cachedCatalogPosID)
+
+	trace := newImportTrace()
+	lotDBID, hasNewPending, err := service.processLot(context.Background(), q, trace, 100, "lot-1", makeLotWithSharedPosition())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(150), lotDBID)
+	assert.False(t, hasNewPending)
+	assert.Equal(t, 2, trace.cacheHits)
+	assert.Zero(t, trace.cacheMisses)
+}
+
+func TestProcessLot_PrefetchLookups_MissesFallBack(t *testing.T) {
+	service, _ := setupTestService(t)
+	service.prefetchLookups = true
+	mock, q, cleanup := newMockQueries(t)
+	defer cleanup()
+
+	expectLotAndPrefetch(mock,
+		sqlmock.NewRows([]string{"lookup_name", "unit_id"}),
+		sqlmock.NewRows(catalogPosColumns),
+		sqlmock.NewRows(matchingCacheColumns),
+	)
+	// Базовое предложение: единица и позиция каталога создаются построчно,
+	// matching_cache построчно не читается — промах известен из предзагрузки
+	setupBaselineProposalExpectations(mock, 150)
+	setupNewUnitExpectations(mock)
+	mock.ExpectQuery("SELECT .+ FROM catalog_positions").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("INSERT INTO catalog_positions").
+		WillReturnRows(sqlmock.NewRows(catalogPosColumns).
+			AddRow(int64(300), "устройство полов", sql.NullString{String: "Устройство полов", Valid: true}, nil, "POSITION", "pending_indexing", sql.NullInt64{Int64: 10, Valid: true}, now, now, nil, nil, nil, nil, false, nil, nil))
+	expectUpsertPositionItem(mock, 200, 300)
+	// Подрядчик: созданные единица и позиция берутся из памяти
+	expectContractorProposal(mock)
+	expectUpsertPositionItem(mock, 201, 300)
+
+	trace := newImportTrace()
+	_, hasNewPending, err := service.processLot(context.Background(), q, trace, 100, "lot-1", makeLotWithSharedPosition())
+
+	require.NoError(t, err)
+	assert.True(t, hasNewPending)
+	assert.Equal(t, 2, trace.cacheMisses)
+}
+
+func TestCollectLotLookupKeys(t *testing.T) {
+	unitUpper, unitLower, empty := " М2 ", "м2", "  "
+	norm := "устройство полов"
+	lot := api_models.Lot{
+		BaseLineProposal: api_models.ContractorProposalDetails{
+			ContractorItems: api_models.ContractorItemsContainer{Positions: map[string]api_models.PositionItem{
+				"1": {JobTitle: "Устройство полов", JobTitleNormalized: &norm, Unit: &unitUpper},
+				"2": {JobTitle: "Раздел 1.  Полы", IsChapter: true},
+				"3": {JobTitle: "   ", Unit: &empty},
+			}},
+		},
+		ProposalData: map[string]api_models.ContractorProposalDetails{
+			"c-1": {ContractorItems: api_models.ContractorItemsContainer{Positions: map[string]api_models.PositionItem{
+				"1": {JobTitle: "Устройство полов", JobTitleNormalized: &norm, Unit: &unitLower},
+				"4": {JobTitle: "Окраска стен"},
+			}}},
+		},
+	}
+
+	units, titles, positionTitles := collectLotLookupKeys(lot)
+
+	assert.Equal(t, []string{"м2"}, units)
+	assert.Equal(t, []string{"окраска стен", "раздел 1. полы", "устройство полов"}, titles)
+	assert.Equal(t, []string{"окраска стен", "устройство полов"}, positionTitles)
+}
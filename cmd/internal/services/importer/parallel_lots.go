@@ -0,0 +1,417 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/db/postgres"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/retry"
+	"golang.org/x/sync/errgroup"
+)
+
+// maxLotAttempts — сколько раз сохраняется лот, транзакция которого
+// прервана параллельной (см. retryableLotError).
+const maxLotAttempts = 3
+
+// importUnlockTimeout ограничивает снятие блокировки импорта: контекст
+// импорта к этому моменту может быть уже отменён.
+const importUnlockTimeout = 5 * time.Second
+
+// Паузы между попытками взять блокировку импорта, занятую другим импортом
+// того же тендера (см. lockTenderImport).
+const (
+	importLockRetryBase = 50 * time.Millisecond
+	importLockRetryMax  = 2 * time.Second
+)
+
+// compensationSteps удаляют строки тендера, созданного неудавшимся
+// параллельным импортом, от листьев к корню (как purge в services/tender).
+// Документов, AI-результатов и уведомлений у такого тендера ещё нет.
+var compensationSteps = []struct {
+	table string
+	purge func(*db.Queries, context.Context, int64) (int64, error)
+}{
+	{"position_items", (*db.Queries).PurgeTenderPositionItems},
+	{"proposal_summary_lines", (*db.Queries).PurgeTenderSummaryLines},
+	{"proposal_additional_info", (*db.Queries).PurgeTenderAdditionalInfo},
+	{"winners", (*db.Queries).PurgeTenderWinners},
+	{"proposals", (*db.Queries).PurgeTenderProposals},
+	{"lots", (*db.Queries).PurgeTenderLots},
+}
+
+// lotRestoreSteps возвращают лот существующего тендера к снимку: сначала
+// удаляются строки, добавленные импортом (от листьев к корню), затем
+// прежние значения записываются от корня к листьям.
+var lotRestoreSteps = []struct {
+	table   string
+	restore func(*db.Queries, context.Context, json.RawMessage) (int64, error)
+}{
+	{"position_items", (*db.Queries).PruneLotPositionItemsToSnapshot},
+	{"proposal_summary_lines", (*db.Queries).PruneLotSummaryLinesToSnapshot},
+	{"proposal_additional_info", (*db.Queries).PruneLotAdditionalInfoToSnapshot},
+	{"proposals", (*db.Queries).PruneLotProposalsToSnapshot},
+	{"lots", (*db.Queries).RestoreLotFromSnapshot},
+	{"proposals", (*db.Queries).RestoreLotProposals},
+	{"proposal_additional_info", (*db.Queries).RestoreLotAdditionalInfo},
+	{"proposal_summary_lines", (*db.Queries).RestoreLotSummaryLines},
+	{"position_items", (*db.Queries).RestoreLotPositionItems},
+}
+
+// importFullTenderParallel — ImportFullTender при import.lot_workers > 1.
+//
+// Поведение:
+//  1. Основная информация о тендере сохраняется и коммитится в своей транзакции.
+//  2. Лоты сохраняются параллельно, не больше lot_workers одновременно, каждый
+//     в своей транзакции. Лот, транзакция которого прервана параллельной
+//     (взаимная блокировка на общих позициях каталога, одновременное создание
+//     подрядчика или единицы измерения), сохраняется заново до maxLotAttempts раз.
+//  3. Завершающая транзакция блокирует строку тендера, сохраняет исходный JSON
+//     с новой версией истории и событие catalog.items_pending.
+//  4. Если лот или завершающая транзакция не удались, оставшиеся лоты
+//     отменяются, а закоммиченные шаги компенсируются (compensateImport).
+//
+// До изменения тендер и каждый лот читаются в снимок (import_snapshot.sql),
+// по которому компенсация возвращает существующий тендер к прежнему
+// состоянию. Вызывающий держит блокировку импорта тендера
+// (lockTenderImport), поэтому между шагами тендер не меняет другой импорт.
+// Пока импорт не завершён, тендер виден без части лотов.
+func (s *TenderImportService) importFullTenderParallel(
+	ctx context.Context,
+	payload *api_models.FullTenderData,
+	rawJSON []byte,
+) (int64, map[string]int64, bool, int64, error) {
+	trace := newImportTrace()
+
+	// Шаг 1: основная информация о тендере
+	var (
+		tender         *db.Tender
+		tenderSnapshot json.RawMessage
+	)
+	txErr := s.execTx(ctx, func(qtx db.Querier) error {
+		trace.txWait = time.Since(trace.startedAt)
+		stepStart := time.Now()
+		var err error
+		tenderSnapshot, err = qtx.SnapshotTenderForImport(ctx, payload.TenderID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("не удалось прочитать снимок тендера: %w", err)
+		}
+		tender, err = s.processCoreTenderData(ctx, qtx, payload)
+		trace.core = time.Since(stepStart)
+		return err
+	})
+	if txErr != nil {
+		s.logger.Errorf("Ошибка на шаге 1: %v", txErr)
+		importID, err := s.finishImport(ctx, trace, time.Time{}, payload.TenderID, 0, len(rawJSON), nil, false, txErr)
+		return 0, nil, false, importID, err
+	}
+	s.logger.Debugf("Тендер сохранён с DB ID: %d (новый: %v)", tender.ID, tenderSnapshot == nil)
+
+	// Шаг 2: лоты
+	s.logger.Debugf("Шаг 2: Параллельная обработка %d лотов (воркеров: %d)", len(payload.LotsData), s.lotWorkers)
+	lotsStart := time.Now()
+	lots, anyNewPendingItems, txErr := s.processLotsParallel(ctx, trace, tender.ID, payload)
+	trace.lots = time.Since(lotsStart)
+	lotIDs := make(map[string]int64, len(lots))
+	for lotKey, lot := range lots {
+		lotIDs[lotKey] = lot.id
+	}
+
+	// Шаг 3: исходный JSON и outbox
+	var callbackDone time.Time
+	if txErr == nil {
+		txErr = s.execTx(ctx, func(qtx db.Querier) error {
+			defer func() { callbackDone = time.Now() }()
+			if err := qtx.LockTenderForImport(ctx, tender.ID); err != nil {
+				return fmt.Errorf("не удалось заблокировать тендер: %w", err)
+			}
+			if err := s.saveRawJSON(ctx, qtx, trace, tender.ID, rawJSON); err != nil {
+				return err
+			}
+			return s.enqueueCatalogItemsPending(ctx, qtx, payload.TenderID, tender.ID, lotIDs, anyNewPendingItems)
+		})
+	}
+	if txErr != nil {
+		s.compensateImport(ctx, tender, tenderSnapshot, lots)
+	}
+
+	importID, err := s.finishImport(ctx, trace, callbackDone, payload.TenderID, tender.ID, len(rawJSON), lotIDs, anyNewPendingItems, txErr)
+	if err != nil {
+		return 0, nil, false, importID, err
+	}
+	return tender.ID, lotIDs, anyNewPendingItems, importID, nil
+}
+
+// savedLot — лот, закоммиченный параллельным импортом.
+type savedLot struct {
+	id int64
+	// Лот до импорта (SnapshotLotForImport); nil — лот создан импортом
+	snapshot json.RawMessage
+}
+
+// processLotsParallel сохраняет лоты пулом из lotWorkers воркеров, каждый лот
+// в своей транзакции. Первая ошибка отменяет контекст остальных лотов: их
+// транзакции прерываются или не начинаются. Возвращает
+// лоты, которые успели закоммититься, — и при ошибке, для компенсации.
+func (s *TenderImportService) processLotsParallel(
+	ctx context.Context,
+	trace *importTrace,
+	tenderID int64,
+	payload *api_models.FullTenderData,
+) (map[string]savedLot, bool, error) {
+	lotKeys := make([]string, 0, len(payload.LotsData))
+	for lotKey := range payload.LotsData {
+		lotKeys = append(lotKeys, lotKey)
+	}
+	sort.Strings(lotKeys)
+
+	var mu sync.Mutex
+	lots := make(map[string]savedLot, len(lotKeys))
+	anyNewPendingItems := false
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(s.lotWorkers)
+	for _, lotKey := range lotKeys {
+		lotAPI := payload.LotsData[lotKey]
+		g.Go(func() error {
+			lot, lotHasNewPending, lotTrace, err := s.processLotTx(gctx, tenderID, lotKey, lotAPI)
+
+			mu.Lock()
+			defer mu.Unlock()
+			trace.merge(lotTrace)
+			if err != nil {
+				s.logger.Errorf("Ошибка при обработке лота '%s': %v", lotKey, err)
+				return fmt.Errorf("ошибка при обработке лота '%s': %w", lotKey, err)
+			}
+			lots[lotKey] = lot
+			if lotHasNewPending {
+				anyNewPendingItems = true
+			}
+			s.logger.Debugf("Лот %s обработан, DB ID: %d", lotKey, lot.id)
+			reportProgress(ctx, payload.TenderID, len(lots), len(payload.LotsData))
+			return nil
+		})
+	}
+	err := g.Wait()
+	return lots, anyNewPendingItems, err
+}
+
+// processLotTx сохраняет лот в отдельной транзакции, предварительно прочитав
+// его снимок. Транзакцию, прерванную параллельной, повторяет целиком с новой
+// трассировкой лота.
+func (s *TenderImportService) processLotTx(
+	ctx context.Context,
+	tenderID int64,
+	lotKey string,
+	lotAPI api_models.Lot,
+) (savedLot, bool, *importTrace, error) {
+	var (
+		lot              savedLot
+		lotHasNewPending bool
+		lotTrace         *importTrace
+		err              error
+	)
+	for attempt := 1; attempt <= maxLotAttempts; attempt++ {
+		lotTrace = newImportTrace()
+		err = s.execTx(ctx, func(qtx db.Querier) error {
+			snapshot, lotErr := qtx.SnapshotLotForImport(ctx, db.SnapshotLotForImportParams{
+				TenderID: tenderID,
+				LotKey:   lotKey,
+			})
+			if lotErr != nil && !errors.Is(lotErr, sql.ErrNoRows) {
+				return fmt.Errorf("не удалось прочитать снимок лота: %w", lotErr)
+			}
+			lot.snapshot = snapshot
+			lot.id, lotHasNewPending, lotErr = s.processLot(ctx, qtx, lotTrace, tenderID, lotKey, lotAPI)
+			return lotErr
+		})
+		if err == nil || !retryableLotError(err) || attempt == maxLotAttempts {
+			break
+		}
+		s.logger.Warnf("Транзакция лота '%s' прервана параллельной (попытка %d из %d): %v", lotKey, attempt, maxLotAttempts, err)
+	}
+	return lot, lotHasNewPending, lotTrace, err
+}
+
+// retryableLotError сообщает, что лот не сохранён из-за параллельной
+// транзакции другого лота: взаимная блокировка, сбой сериализации или
+// нарушение уникальности, когда две транзакции одновременно создали один
+// справочник (GetOrCreate не видит чужую незакоммиченную строку). При повторе
+// строка уже закоммичена и находится.
+func retryableLotError(err error) bool {
+	return postgres.IsTransactionConflict(err) || postgres.IsUniqueViolation(err)
+}
+
+// compensateImport откатывает закоммиченные шаги неудавшегося параллельного
+// импорта. Тендер, созданный этим импортом (tenderSnapshot = nil), удаляется
+// вместе с сохранёнными лотами. Существующий тендер возвращается к снимкам:
+// лоты, созданные импортом, удаляются, обновлённые лоты и строка тендера
+// получают прежние значения. Исходный JSON не сохранялся, поэтому история
+// импортов и синхронизация видят последнюю завершённую загрузку. Ошибка
+// компенсации только логируется: вызывающий получит исходную ошибку импорта.
+func (s *TenderImportService) compensateImport(ctx context.Context, tender *db.Tender, tenderSnapshot json.RawMessage, lots map[string]savedLot) {
+	// Контекст импорта мог быть отменён — компенсация всё равно нужна
+	ctx = context.WithoutCancel(ctx)
+
+	if tenderSnapshot != nil {
+		err := s.store.ExecTx(ctx, func(qtx *db.Queries) error {
+			return restoreTender(ctx, qtx, tenderSnapshot, lots)
+		})
+		if err != nil {
+			s.logger.Errorf("Не удалось вернуть тендер %s (ID=%d) к состоянию до неудачного импорта: %v", tender.EtpID, tender.ID, err)
+			return
+		}
+		s.logger.Warnf("Тендер %s (ID=%d) возвращён к состоянию до неудачного импорта, лотов восстановлено: %d",
+			tender.EtpID, tender.ID, len(lots))
+		return
+	}
+
+	err := s.store.ExecTx(ctx, func(qtx *db.Queries) error {
+		for _, step := range compensationSteps {
+			if _, err := step.purge(qtx, ctx, tender.ID); err != nil {
+				return fmt.Errorf("ошибка удаления %s: %w", step.table, err)
+			}
+		}
+		if _, err := qtx.PurgeTender(ctx, tender.ID); err != nil {
+			return fmt.Errorf("ошибка удаления тендера: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Errorf("Не удалось удалить тендер %s (ID=%d) после неудачного импорта: %v", tender.EtpID, tender.ID, err)
+		return
+	}
+	s.logger.Warnf("Тендер %s (ID=%d), созданный неудачным импортом, удалён вместе с сохранёнными лотами (%d)",
+		tender.EtpID, tender.ID, len(lots))
+}
+
+// restoreTender возвращает существующий тендер к снимкам в транзакции
+// компенсации. Лоты обходятся по ключу, чтобы порядок блокировок не зависел
+// от порядка коммита лотов.
+func restoreTender(ctx context.Context, qtx *db.Queries, tenderSnapshot json.RawMessage, lots map[string]savedLot) error {
+	lotKeys := make([]string, 0, len(lots))
+	for lotKey := range lots {
+		lotKeys = append(lotKeys, lotKey)
+	}
+	sort.Strings(lotKeys)
+
+	for _, lotKey := range lotKeys {
+		lot := lots[lotKey]
+		if lot.snapshot == nil {
+			if err := qtx.DeleteLot(ctx, lot.id); err != nil {
+				return fmt.Errorf("ошибка удаления лота '%s': %w", lotKey, err)
+			}
+			continue
+		}
+		for _, step := range lotRestoreSteps {
+			if _, err := step.restore(qtx, ctx, lot.snapshot); err != nil {
+				return fmt.Errorf("ошибка восстановления %s лота '%s': %w", step.table, lotKey, err)
+			}
+		}
+	}
+	if _, err := qtx.RestoreTenderFromSnapshot(ctx, tenderSnapshot); err != nil {
+		return fmt.Errorf("ошибка восстановления тендера: %w", err)
+	}
+	return nil
+}
+
+// lockTenderImport ждёт сессионную advisory-блокировку импорта тендера etpID
+// и держит её на выделенном соединении s.conn до вызова возвращённой функции.
+// Так импорты одного тендера выполняются по очереди: лоты двух импортов не
+// перемешиваются, а снимки и компенсация видят тендер только своего импорта.
+//
+// Блокировка берётся pg_try_advisory_lock с растущей паузой, и между
+// попытками соединение возвращается в пул: ожидающие импорты не занимают
+// соединения, нужные импорту, который держит блокировку. Число одновременных
+// параллельных импортов ограничено importSlots, чтобы соединения блокировок и
+// воркеров лотов помещались в пул. Ожидание прерывается отменой ctx.
+func (s *TenderImportService) lockTenderImport(ctx context.Context, etpID string) (func(), error) {
+	if s.importSlots != nil {
+		select {
+		case s.importSlots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("ожидание очереди параллельного импорта тендера %s: %w", etpID, ctx.Err())
+		}
+	}
+	releaseSlot := func() {
+		if s.importSlots != nil {
+			<-s.importSlots
+		}
+	}
+
+	key := importLockKey(etpID)
+	for attempt := 1; ; attempt++ {
+		conn, err := s.tryLockTenderImport(ctx, key)
+		if err != nil {
+			releaseSlot()
+			return nil, fmt.Errorf("блокировка импорта тендера %s: %w", etpID, err)
+		}
+		if conn != nil {
+			return func() {
+				defer releaseSlot()
+				defer conn.Close()
+				s.unlockTenderImport(conn, etpID, key)
+			}, nil
+		}
+		if attempt == 1 {
+			s.logger.Infof("Импорт тендера %s ждёт завершения другого импорта этого тендера", etpID)
+		}
+		if err := retry.Sleep(ctx, retry.Backoff(attempt, importLockRetryBase, importLockRetryMax)); err != nil {
+			releaseSlot()
+			return nil, fmt.Errorf("ожидание блокировки импорта тендера %s: %w", etpID, err)
+		}
+	}
+}
+
+// tryLockTenderImport делает одну попытку взять блокировку импорта. Возвращает
+// соединение, на котором она взята, или nil, если блокировку держит другой
+// импорт; тогда соединение уже возвращено в пул.
+func (s *TenderImportService) tryLockTenderImport(ctx context.Context, key int64) (*sql.Conn, error) {
+	conn, err := s.conn.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("соединение для блокировки импорта: %w", err)
+	}
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !acquired {
+		conn.Close()
+		return nil, nil
+	}
+	return conn, nil
+}
+
+// unlockTenderImport снимает блокировку импорта. Соединение с неснятой
+// блокировкой нельзя возвращать в пул (как в joblock), поэтому при ошибке оно
+// помечается негодным и закрывается.
+func (s *TenderImportService) unlockTenderImport(conn *sql.Conn, etpID string, key int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), importUnlockTimeout)
+	defer cancel()
+
+	var released bool
+	err := conn.QueryRowContext(ctx, "SELECT pg_advisory_unlock($1)", key).Scan(&released)
+	if err == nil && released {
+		return
+	}
+	s.logger.Warnf("Блокировка импорта тендера %s не снята (released=%t, err=%v), соединение закрывается", etpID, released, err)
+	_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+}
+
+// importLockKey — ключ advisory-блокировки импорта: FNV-1a от etp_id с
+// префиксом, отличным от ключей joblock.
+func importLockKey(etpID string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte("tenders-go:import:" + etpID))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,427 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/entities"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR PARALLEL LOT IMPORT (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Slow imports — large tenders must not save their lots one by one
+2. Half-imported tenders — a failed import must not leave a tender with part of its lots
+3. Spurious failures — two lots creating the same contractor or unit at once must not fail the import
+4. Interleaved imports — two imports of the same tender must not mix their lots
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Success
+- GIVEN import.lot_workers = 2 and a tender with two lots
+  WHEN ImportFullTender is called
+  THEN the tender import lock is taken before the first transaction and released after
+  AND the tender core, each lot and the raw JSON are saved in separate transactions
+  AND the tender and each lot are read into a snapshot before they are changed
+  AND the final transaction locks the tender row before the raw JSON version
+- GIVEN the import lock cannot be taken THEN nothing is saved and the error is returned
+- GIVEN the import lock is held by another import
+  THEN it is retried with a pause, the connection is returned to the pool between
+  attempts, and a cancelled context stops the wait
+- GIVEN database.max_open_conns THEN concurrent parallel imports are limited so
+  that each has a lock connection and lot_workers connections
+- GIVEN import.lot_workers > 1 but no connection THEN the tender is imported in one transaction
+
+SCENARIO 2: Failed import
+- GIVEN a new tender and a lot that fails
+  THEN the import fails and the tender is deleted together with its rows
+- GIVEN an existing tender and a lot that fails
+  THEN the import fails and the tender row is restored from its snapshot
+- GIVEN an existing tender whose lots were saved and a failed final transaction
+  THEN every saved lot is restored from its snapshot (rows added by the import
+  are deleted first, previous values written back), then the tender row
+
+SCENARIO 3: Conflicts between lots
+- GIVEN a lot transaction aborted by a deadlock or unique violation
+  THEN the lot is saved again in a new transaction
+- GIVEN any other error THEN the lot is not retried
+*/
+
+// makePayloadWithTwoLots — тендер из двух лотов с пустым базовым предложением.
+func makePayloadWithTwoLots() *api_models.FullTenderData {
+	payload := makeMinimalPayload()
+	payload.LotsData = map[string]api_models.Lot{
+		"lot-1": {LotTitle: "Лот №1", ProposalData: map[string]api_models.ContractorProposalDetails{}},
+		"lot-2": {LotTitle: "Лот №2", ProposalData: map[string]api_models.ContractorProposalDetails{}},
+	}
+	return payload
+}
+
+func newParallelTestService(t *testing.T) (*TenderImportService, *db.MockStore) {
+	t.Helper()
+	service, mockStore := setupTestService(t)
+	service.lotWorkers = 2
+	return service, mockStore
+}
+
+// newParallelImportService — newParallelTestService, у которого импорт
+// берёт и снимает блокировку тендера на соединении sqlmock.
+func newParallelImportService(t *testing.T) (*TenderImportService, *db.MockStore) {
+	t.Helper()
+	service, mockStore := newParallelTestService(t)
+	lockMock := newImportLockMock(t, service)
+	key := importLockKey("ETP-TEST-001")
+	lockMock.ExpectQuery("SELECT pg_try_advisory_lock").WithArgs(key).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+	lockMock.ExpectQuery("SELECT pg_advisory_unlock").WithArgs(key).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_advisory_unlock"}).AddRow(true))
+	return service, mockStore
+}
+
+// newImportLockMock подключает к сервису соединение sqlmock для блокировки импорта.
+func newImportLockMock(t *testing.T, service *TenderImportService) sqlmock.Sqlmock {
+	t.Helper()
+	conn, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, mock.ExpectationsWereMet(), "sqlmock: unmet import lock expectations")
+		conn.Close()
+	})
+	service.conn = conn
+	return mock
+}
+
+// parallelExecTx раздаёт транзакциям ExecTx ожидания sqlmock по порядку
+// вызова: первая — основная информация тендера, следующие lots — лоты
+// (порядок лотов не определён, поэтому ожидания у них общие), остальные —
+// по одной из rest.
+func parallelExecTx(
+	t *testing.T,
+	core func(sqlmock.Sqlmock),
+	lots int,
+	lot func(sqlmock.Sqlmock),
+	rest ...func(sqlmock.Sqlmock),
+) func(context.Context, func(*db.Queries) error) error {
+	t.Helper()
+	var mu sync.Mutex
+	calls := 0
+	return func(ctx context.Context, fn func(*db.Queries) error) error {
+		mu.Lock()
+		calls++
+		call := calls
+		mu.Unlock()
+
+		setup := lot
+		switch {
+		case call == 1:
+			setup = core
+		case call > lots+1:
+			setup = rest[call-lots-2]
+		}
+		return execTxDoAndReturn(t, setup)(ctx, fn)
+	}
+}
+
+const (
+	testTenderSnapshot = `{"id":100,"etp_id":"ETP-TEST-001","title":"Тестовый тендер"}`
+	testLotSnapshot    = `{"lot":{"id":150},"proposals":[],"proposal_additional_info":[],"proposal_summary_lines":[],"position_items":[]}`
+)
+
+// setupNewTenderExpectations: снимка нет (тендер новый), затем основная
+// информация тендера.
+func setupNewTenderExpectations(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT to_jsonb\\(t\\)").
+		WithArgs("ETP-TEST-001").
+		WillReturnRows(sqlmock.NewRows([]string{"snapshot"}))
+	setupCoreTenderExpectations(mock)
+}
+
+// setupExistingTenderExpectations — снимок существующего тендера, затем
+// UpsertTender обновляет строку (created_at старше updated_at).
+func setupExistingTenderExpectations(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT to_jsonb\\(t\\)").
+		WithArgs("ETP-TEST-001").
+		WillReturnRows(sqlmock.NewRows([]string{"snapshot"}).AddRow([]byte(testTenderSnapshot)))
+	mock.ExpectQuery("SELECT .+ FROM objects WHERE title").
+		WithArgs("Строительство").
+		WillReturnRows(sqlmock.NewRows(objectColumns).
+			AddRow(int64(1), "Строительство", "г. Москва, ул. Тестовая, 1", now, now))
+	mock.ExpectQuery("SELECT .+ FROM executors WHERE name").
+		WithArgs("Иванов И.И.").
+		WillReturnRows(sqlmock.NewRows(executorColumns).
+			AddRow(int64(1), "Иванов И.И.", "+7-999-000-0000", now, now))
+	mock.ExpectQuery("INSERT INTO tenders").
+		WillReturnRows(sqlmock.NewRows(tenderColumns).
+			AddRow(int64(100), "ETP-TEST-001", "Тестовый тендер", nil, int64(1), int64(1), nil, now.Add(-24*time.Hour), now, nil, nil, int64(1)))
+}
+
+// setupEmptyLotExpectations: новый лот (снимка нет) с базовым предложением
+// без позиций и итогов.
+func setupEmptyLotExpectations(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT jsonb_build_object").
+		WillReturnRows(sqlmock.NewRows([]string{"snapshot"}))
+	setupLotRowsExpectations(mock)
+}
+
+// setupExistingLotExpectations — как setupEmptyLotExpectations, но лот уже
+// был и попадает в снимок.
+func setupExistingLotExpectations(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT jsonb_build_object").
+		WithArgs(int64(100), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"snapshot"}).AddRow([]byte(testLotSnapshot)))
+	setupLotRowsExpectations(mock)
+}
+
+func setupLotRowsExpectations(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("INSERT INTO lots").
+		WillReturnRows(sqlmock.NewRows(lotColumns).
+			AddRow(int64(150), "lot-1", "Лот №1", nil, int64(100), now, now))
+	setupBaselineProposalExpectations(mock, 150)
+}
+
+func TestImportFullTender_ParallelLots_Success(t *testing.T) {
+	service, mockStore := newParallelImportService(t)
+	var mu sync.Mutex
+	var progress []int
+	ctx := WithProgress(context.Background(), func(_ string, lotsDone, _ int) {
+		mu.Lock()
+		progress = append(progress, lotsDone)
+		mu.Unlock()
+	})
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).Times(4).DoAndReturn(parallelExecTx(t,
+		setupNewTenderExpectations,
+		2, setupEmptyLotExpectations,
+		func(mock sqlmock.Sqlmock) {
+			mock.ExpectExec("SELECT id FROM tenders").
+				WithArgs(int64(100)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			setupRawDataExpectations(mock, 100)
+		},
+	))
+
+	tenderID, lotIDs, anyNewPending, _, err := service.ImportFullTender(ctx, makePayloadWithTwoLots(), []byte(`{}`))
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), tenderID)
+	assert.Len(t, lotIDs, 2)
+	assert.Contains(t, lotIDs, "lot-1")
+	assert.Contains(t, lotIDs, "lot-2")
+	assert.False(t, anyNewPending)
+	assert.ElementsMatch(t, []int{1, 2}, progress)
+}
+
+func TestImportFullTender_ParallelLots_LockFailed(t *testing.T) {
+	service, _ := newParallelTestService(t)
+	lockMock := newImportLockMock(t, service)
+	lockMock.ExpectQuery("SELECT pg_try_advisory_lock").WillReturnError(context.DeadlineExceeded)
+
+	// ExecTx не ожидается: без блокировки импорт не начинается
+	tenderID, _, _, _, err := service.ImportFullTender(context.Background(), makePayloadWithTwoLots(), []byte(`{}`))
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Zero(t, tenderID)
+}
+
+func TestLockTenderImport_WaitsForBusyLock(t *testing.T) {
+	service, _ := newParallelTestService(t)
+	lockMock := newImportLockMock(t, service)
+	key := importLockKey("ETP-TEST-001")
+	busy := func() *sqlmock.Rows { return sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(false) }
+	lockMock.ExpectQuery("SELECT pg_try_advisory_lock").WithArgs(key).WillReturnRows(busy())
+	lockMock.ExpectQuery("SELECT pg_try_advisory_lock").WithArgs(key).WillReturnRows(busy())
+	lockMock.ExpectQuery("SELECT pg_try_advisory_lock").WithArgs(key).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+	lockMock.ExpectQuery("SELECT pg_advisory_unlock").WithArgs(key).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_advisory_unlock"}).AddRow(true))
+
+	// Пул из одного соединения: если бы попытка не возвращала соединение,
+	// следующая ждала бы его до таймаута
+	service.conn.SetMaxOpenConns(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	unlock, err := service.lockTenderImport(ctx, "ETP-TEST-001")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, service.conn.Stats().InUse)
+	unlock()
+	assert.Zero(t, service.conn.Stats().InUse)
+}
+
+func TestLockTenderImport_CancelledWhileBusy(t *testing.T) {
+	service, _ := newParallelTestService(t)
+	lockMock := newImportLockMock(t, service)
+	lockMock.ExpectQuery("SELECT pg_try_advisory_lock").
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(false))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := service.lockTenderImport(ctx, "ETP-TEST-001")
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Zero(t, service.conn.Stats().InUse)
+}
+
+func TestNewTenderImportService_ImportSlotsFitPool(t *testing.T) {
+	logger := testutil.NewMockLogger()
+	conn, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.SetMaxOpenConns(25)
+
+	service := NewTenderImportService(nil, conn, logger, entities.NewEntityManager(logger), config.ImportConfig{LotWorkers: 4}, config.OutboxConfig{}, events.Noop{}, nil)
+
+	// 5 импортов × (блокировка + 4 воркера) = 25 соединений
+	assert.Equal(t, 5, cap(service.importSlots))
+}
+
+func TestNewTenderImportService_LotWorkersNeedConn(t *testing.T) {
+	logger := testutil.NewMockLogger()
+	cfg := config.ImportConfig{LotWorkers: 4}
+
+	service := NewTenderImportService(nil, nil, logger, entities.NewEntityManager(logger), cfg, config.OutboxConfig{}, events.Noop{}, nil)
+
+	assert.Equal(t, 1, service.lotWorkers)
+}
+
+func TestImportFullTender_ParallelLots_NewTenderCompensated(t *testing.T) {
+	service, mockStore := newParallelImportService(t)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).Times(4).DoAndReturn(parallelExecTx(t,
+		setupNewTenderExpectations,
+		2, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT jsonb_build_object").
+				WillReturnRows(sqlmock.NewRows([]string{"snapshot"}))
+			mock.ExpectQuery("INSERT INTO lots").WillReturnError(errors.New("disk full"))
+		},
+		func(mock sqlmock.Sqlmock) {
+			for _, table := range []string{"position_items", "proposal_summary_lines", "proposal_additional_info", "winners", "proposals", "lots", "tenders"} {
+				mock.ExpectExec("DELETE FROM " + table).
+					WithArgs(int64(100)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+		},
+	))
+
+	tenderID, _, _, _, err := service.ImportFullTender(context.Background(), makePayloadWithTwoLots(), []byte(`{}`))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "disk full")
+	assert.Zero(t, tenderID)
+}
+
+func TestImportFullTender_ParallelLots_ExistingTenderRestored(t *testing.T) {
+	service, mockStore := newParallelImportService(t)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).Times(4).DoAndReturn(parallelExecTx(t,
+		setupExistingTenderExpectations,
+		2, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT jsonb_build_object").
+				WillReturnRows(sqlmock.NewRows([]string{"snapshot"}).AddRow([]byte(testLotSnapshot)))
+			mock.ExpectQuery("INSERT INTO lots").WillReturnError(errors.New("disk full"))
+		},
+		// Ни один лот не закоммичен: восстанавливается только строка тендера
+		func(mock sqlmock.Sqlmock) {
+			mock.ExpectExec("UPDATE tenders").
+				WithArgs([]byte(testTenderSnapshot)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		},
+	))
+
+	_, _, _, _, err := service.ImportFullTender(context.Background(), makePayloadWithTwoLots(), []byte(`{}`))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ошибка при обработке лота")
+}
+
+func TestImportFullTender_ParallelLots_ExistingLotsRestored(t *testing.T) {
+	service, mockStore := newParallelImportService(t)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).Times(5).DoAndReturn(parallelExecTx(t,
+		setupExistingTenderExpectations,
+		2, setupExistingLotExpectations,
+		func(mock sqlmock.Sqlmock) {
+			mock.ExpectExec("SELECT id FROM tenders").WillReturnError(errors.New("lock timeout"))
+		},
+		func(mock sqlmock.Sqlmock) {
+			for range 2 {
+				for _, stmt := range []string{
+					"DELETE FROM position_items", "DELETE FROM proposal_summary_lines",
+					"DELETE FROM proposal_additional_info", "DELETE FROM proposals",
+					"UPDATE lots", "INSERT INTO proposals", "INSERT INTO proposal_additional_info",
+					"INSERT INTO proposal_summary_lines", "INSERT INTO position_items",
+				} {
+					mock.ExpectExec(stmt).
+						WithArgs([]byte(testLotSnapshot)).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+			}
+			mock.ExpectExec("UPDATE tenders").
+				WithArgs([]byte(testTenderSnapshot)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		},
+	))
+
+	_, _, _, _, err := service.ImportFullTender(context.Background(), makePayloadWithTwoLots(), []byte(`{}`))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "lock timeout")
+}
+
+func TestProcessLotTx_RetriesConflicts(t *testing.T) {
+	lotAPI := makePayloadWithTwoLots().LotsData["lot-1"]
+
+	t.Run("deadlock is retried", func(t *testing.T) {
+		service, mockStore := newParallelTestService(t)
+		gomock.InOrder(
+			mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).Return(&pgconn.PgError{Code: "40P01"}),
+			mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).Return(&pgconn.PgError{Code: "23505"}),
+			mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(execTxDoAndReturn(t, setupEmptyLotExpectations)),
+		)
+
+		lot, _, lotTrace, err := service.processLotTx(context.Background(), 100, "lot-1", lotAPI)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(150), lot.id)
+		assert.Nil(t, lot.snapshot)
+		assert.Len(t, lotTrace.lotTimings, 1)
+	})
+
+	t.Run("gives up after maxLotAttempts", func(t *testing.T) {
+		service, mockStore := newParallelTestService(t)
+		mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).Times(maxLotAttempts).Return(&pgconn.PgError{Code: "40P01"})
+
+		_, _, _, err := service.processLotTx(context.Background(), 100, "lot-1", lotAPI)
+
+		require.Error(t, err)
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		service, mockStore := newParallelTestService(t)
+		mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).Return(sql.ErrConnDone)
+
+		_, _, _, err := service.processLotTx(context.Background(), 100, "lot-1", lotAPI)
+
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+	})
+}
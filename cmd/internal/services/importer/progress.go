@@ -0,0 +1,23 @@
+package importer
+
+import "context"
+
+// ProgressFunc получает прогресс импорта: lotsDone из lotsTotal лотов
+// обработано. Вызывается синхронно внутри транзакции импорта, поэтому не
+// должна блокироваться.
+type ProgressFunc func(etpID string, lotsDone, lotsTotal int)
+
+type progressKey struct{}
+
+// WithProgress возвращает контекст, импорт с которым сообщает прогресс в fn
+// после каждого лота (GET /api/v1/events).
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// reportProgress сообщает прогресс, если контекст импорта его ожидает.
+func reportProgress(ctx context.Context, etpID string, lotsDone, lotsTotal int) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok {
+		fn(etpID, lotsDone, lotsTotal)
+	}
+}
@@ -0,0 +1,191 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+// decodeTenderStream разбирает JSON тендера (формат FullTenderData) по токенам,
+// не собирая его целиком: в памяти одновременно находятся только поля тендера
+// и один лот.
+//
+// onHeader вызывается один раз перед первым лотом, когда поля тендера и
+// исполнитель уже прочитаны и проверены, поэтому в JSON они должны идти до
+// "lots" (парсер пишет ключи в порядке FullTenderData). onLot получает лоты по
+// одному, уже прошедшие Lot.Validate. Неизвестные ключи пропускаются.
+//
+// Ошибки формата и валидации — *apierrors.ValidationError. Ошибки чтения r
+// возвращаются обёрнутыми, чтобы вызывающий мог распознать, например,
+// превышение лимита размера тела.
+func decodeTenderStream(
+	r io.Reader,
+	onHeader func(header *api_models.FullTenderData) error,
+	onLot func(lotKey string, lot *api_models.Lot) error,
+) error {
+	dec := json.NewDecoder(r)
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	var header api_models.FullTenderData
+	lotsSeen := false
+	for dec.More() {
+		key, err := readKey(dec)
+		if err != nil {
+			return err
+		}
+
+		var target any
+		switch key {
+		case "tender_id":
+			target = &header.TenderID
+		case "tender_title":
+			target = &header.TenderTitle
+		case "tender_object":
+			target = &header.TenderObject
+		case "tender_address":
+			target = &header.TenderAddress
+		case "executor":
+			target = &header.ExecutorData
+		case "organization_id":
+			target = &header.OrganizationID
+		case "lots":
+			if lotsSeen {
+				return apierrors.NewValidationError("import.lots_duplicated")
+			}
+			lotsSeen = true
+			if err := header.ValidateHeader(); err != nil {
+				return fieldValidationError(err, "")
+			}
+			if err := onHeader(&header); err != nil {
+				return err
+			}
+			if err := decodeLots(dec, onLot); err != nil {
+				return err
+			}
+			continue
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return decodeError(err)
+			}
+			continue
+		}
+
+		// Данные тендера уже сохранены при начале лотов — поздние значения потерялись бы
+		if lotsSeen {
+			return apierrors.NewValidationError("import.field_after_lots", key)
+		}
+		if err := dec.Decode(target); err != nil {
+			return decodeError(err)
+		}
+	}
+
+	if err := expectDelim(dec, '}'); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		if err == nil {
+			return apierrors.NewValidationError("import.json_trailing_data")
+		}
+		return decodeError(err)
+	}
+	if !lotsSeen {
+		return apierrors.NewValidationError("import.lots_required")
+	}
+	return nil
+}
+
+// decodeLots читает объект lots и передаёт onLot каждый лот сразу после разбора.
+func decodeLots(dec *json.Decoder, onLot func(string, *api_models.Lot) error) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	seen := make(map[string]bool)
+	for dec.More() {
+		lotKey, err := readKey(dec)
+		if err != nil {
+			return err
+		}
+		// json.Unmarshal молча оставил бы последний лот — при потоковом разборе первый уже сохранён
+		if seen[lotKey] {
+			return apierrors.NewValidationError("import.lot_duplicated", lotKey)
+		}
+		seen[lotKey] = true
+
+		var lot api_models.Lot
+		if err := dec.Decode(&lot); err != nil {
+			return decodeError(err)
+		}
+		if err := lot.Validate(); err != nil {
+			return fieldValidationError(err, fmt.Sprintf("lots[%q]", lotKey))
+		}
+		if err := onLot(lotKey, &lot); err != nil {
+			return err
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return err
+	}
+	if len(seen) == 0 {
+		return apierrors.NewValidationError("import.lots_required")
+	}
+	return nil
+}
+
+// readKey читает ключ объекта. Decoder сам проверяет, что на месте ключа строка.
+func readKey(dec *json.Decoder) (string, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return "", decodeError(err)
+	}
+	key, ok := tok.(string)
+	if !ok {
+		return "", apierrors.NewValidationError("import.json_expected_key")
+	}
+	return key, nil
+}
+
+// expectDelim читает следующий токен и проверяет, что это ожидаемая скобка.
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return decodeError(err)
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != want {
+		return apierrors.NewValidationError("import.json_expected_delim", want, dec.InputOffset())
+	}
+	return nil
+}
+
+// fieldValidationError оборачивает ошибку Validate в ValidationError, чьи
+// Details — нарушения по полям; prefix — путь проверенного объекта в JSON
+// тендера (пути Lot.Validate отсчитываются от лота).
+func fieldValidationError(err error, prefix string) error {
+	var fieldErrs api_models.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return apierrors.NewValidationError("%v", err)
+	}
+	fieldErrs = fieldErrs.Prefixed(prefix)
+	return apierrors.NewValidationErrorWithDetails(fieldErrs, "%v", fieldErrs)
+}
+
+// decodeError отделяет ошибки формата JSON (400) от ошибок чтения тела.
+func decodeError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return apierrors.NewValidationError("request.invalid_json", err)
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return apierrors.NewValidationError("import.json_unexpected_eof")
+	default:
+		return fmt.Errorf("ошибка чтения тела запроса: %w", err)
+	}
+}
@@ -0,0 +1,110 @@
+// Package joblock не даёт фоновым задачам выполняться одновременно на
+// нескольких экземплярах API.
+//
+// Перед запуском задача берёт сессионную advisory-блокировку PostgreSQL по
+// своему имени (pg_try_advisory_lock): экземпляр, который её получил,
+// выполняет задачу, остальные пропускают этот запуск и пробуют на следующем
+// тике. Ожидания блокировки нет — задачи периодические, и пропущенный запуск
+// выполнит тот экземпляр, который сейчас держит блокировку.
+//
+// Блокировка держится на выделенном соединении пула и снимается после
+// задачи. Если соединение оборвалось, PostgreSQL снимает блокировку сам, и
+// задача может начаться на другом экземпляре, пока первый её дорабатывает:
+// задачи по-прежнему должны быть идемпотентными.
+package joblock
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// unlockTimeout ограничивает снятие блокировки: контекст задачи к этому
+// моменту может быть уже отменён.
+const unlockTimeout = 5 * time.Second
+
+// Locker выполняет задачу под именованной блокировкой.
+type Locker interface {
+	// TryRun вызывает fn, если блокировка name свободна. ran=false — блокировку
+	// держит другой экземпляр, fn не вызывалась. err — ошибка блокировки или fn.
+	TryRun(ctx context.Context, name string, fn func(ctx context.Context) error) (ran bool, err error)
+}
+
+// Local выполняет задачи без блокировки (scheduler.lock_driver: none):
+// единственный экземпляр API или тесты.
+type Local struct{}
+
+func (Local) TryRun(ctx context.Context, _ string, fn func(ctx context.Context) error) (bool, error) {
+	return true, fn(ctx)
+}
+
+// Postgres — блокировки на advisory-locks PostgreSQL (scheduler.lock_driver: postgres).
+type Postgres struct {
+	db     *sql.DB
+	logger logging.Logger
+}
+
+// NewPostgres создаёт блокировки поверх пула conn. Каждая выполняющаяся задача
+// занимает одно соединение пула на время выполнения.
+func NewPostgres(conn *sql.DB, logger logging.Logger) *Postgres {
+	return &Postgres{db: conn, logger: logger.WithField("component", "joblock")}
+}
+
+// New выбирает реализацию по scheduler.lock_driver.
+func New(lockDriver string, conn *sql.DB, logger logging.Logger) Locker {
+	if lockDriver == "none" {
+		return Local{}
+	}
+	return NewPostgres(conn, logger)
+}
+
+func (p *Postgres) TryRun(ctx context.Context, name string, fn func(ctx context.Context) error) (bool, error) {
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("соединение для блокировки задачи %s: %w", name, err)
+	}
+	defer conn.Close()
+
+	key := lockKey(name)
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		return false, fmt.Errorf("блокировка задачи %s: %w", name, err)
+	}
+	if !acquired {
+		p.logger.Debugf("Задача %s выполняется на другом экземпляре, запуск пропущен", name)
+		return false, nil
+	}
+	defer p.unlock(conn, name, key)
+
+	return true, fn(ctx)
+}
+
+// unlock снимает блокировку. Соединение с неснятой блокировкой нельзя
+// возвращать в пул — запрос, получивший его, держал бы блокировку задачи,
+// поэтому при ошибке соединение помечается негодным и закрывается.
+func (p *Postgres) unlock(conn *sql.Conn, name string, key int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), unlockTimeout)
+	defer cancel()
+
+	var released bool
+	err := conn.QueryRowContext(ctx, "SELECT pg_advisory_unlock($1)", key).Scan(&released)
+	if err == nil && released {
+		return
+	}
+	p.logger.Warnf("Блокировка задачи %s не снята (released=%t, err=%v), соединение закрывается", name, released, err)
+	_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+}
+
+// lockKey — ключ advisory-блокировки: FNV-1a от имени задачи с префиксом
+// приложения, чтобы не пересекаться с блокировками других приложений в той же
+// базе (например, golang-migrate).
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte("tenders-go:job:" + name))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,124 @@
+package joblock
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR BACKGROUND JOB LOCKS (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Two API replicas running the same job at once (double renormalization, duplicate callbacks)
+2. A lock leaking into the connection pool and silently blocking the job forever
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Lock is free
+- GIVEN pg_try_advisory_lock returns true
+  WHEN TryRun is called THEN fn runs, its error is returned, the lock is released
+
+SCENARIO 2: Lock is held by another instance
+- GIVEN pg_try_advisory_lock returns false
+  THEN fn is not called, ran=false, no error
+
+SCENARIO 3: Failures
+- GIVEN the lock query fails THEN fn is not called and the error is returned
+- GIVEN the unlock fails THEN a warning is logged (the connection is discarded)
+
+SCENARIO 4: Keys
+- GIVEN two job names THEN their keys differ; the same name always maps to the same key
+*/
+
+func newTestPostgres(t *testing.T) (*Postgres, sqlmock.Sqlmock, *testutil.MockLogger) {
+	t.Helper()
+	conn, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, mock.ExpectationsWereMet(), "sqlmock: unmet expectations")
+		conn.Close()
+	})
+	logger := testutil.NewMockLogger()
+	return NewPostgres(conn, logger), mock, logger
+}
+
+func lockRows(acquired bool) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(acquired)
+}
+
+func TestPostgres_TryRun_Acquired(t *testing.T) {
+	p, mock, _ := newTestPostgres(t)
+	key := lockKey("catalog_renormalization")
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").WithArgs(key).WillReturnRows(lockRows(true))
+	mock.ExpectQuery("SELECT pg_advisory_unlock").WithArgs(key).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_advisory_unlock"}).AddRow(true))
+
+	called := false
+	ran, err := p.TryRun(context.Background(), "catalog_renormalization", func(context.Context) error {
+		called = true
+		return errors.New("batch failed")
+	})
+
+	assert.True(t, ran)
+	assert.True(t, called)
+	assert.EqualError(t, err, "batch failed")
+}
+
+func TestPostgres_TryRun_HeldElsewhere(t *testing.T) {
+	p, mock, _ := newTestPostgres(t)
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").WillReturnRows(lockRows(false))
+
+	ran, err := p.TryRun(context.Background(), "outbox_dispatch", func(context.Context) error {
+		t.Fatal("fn must not run without the lock")
+		return nil
+	})
+
+	assert.False(t, ran)
+	assert.NoError(t, err)
+}
+
+func TestPostgres_TryRun_LockQueryFails(t *testing.T) {
+	p, mock, _ := newTestPostgres(t)
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").WillReturnError(errors.New("connection reset"))
+
+	ran, err := p.TryRun(context.Background(), "outbox_dispatch", func(context.Context) error {
+		t.Fatal("fn must not run without the lock")
+		return nil
+	})
+
+	assert.False(t, ran)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "outbox_dispatch")
+}
+
+func TestPostgres_TryRun_UnlockFails(t *testing.T) {
+	p, mock, logger := newTestPostgres(t)
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").WillReturnRows(lockRows(true))
+	mock.ExpectQuery("SELECT pg_advisory_unlock").WillReturnError(errors.New("connection reset"))
+
+	ran, err := p.TryRun(context.Background(), "outbox_dispatch", func(context.Context) error { return nil })
+
+	assert.True(t, ran)
+	assert.NoError(t, err)
+	records := logger.Records()
+	require.NotEmpty(t, records)
+	assert.Equal(t, testutil.LevelWarn, records[len(records)-1].Level)
+}
+
+func TestLockKey(t *testing.T) {
+	assert.Equal(t, lockKey("outbox_dispatch"), lockKey("outbox_dispatch"))
+	assert.NotEqual(t, lockKey("outbox_dispatch"), lockKey("scheduled_reports"))
+}
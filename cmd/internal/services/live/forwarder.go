@@ -0,0 +1,49 @@
+package live
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// TenderOrganizationLookup возвращает организацию-владельца тендера
+// (db.Store.GetTenderOrganizationID).
+type TenderOrganizationLookup func(ctx context.Context, tenderID int64) (int64, error)
+
+// Forwarder — events.Publisher, который передаёт доменные события в поток
+// организации-владельца тендера. Подключается в cmd/main через events.Fanout
+// рядом с шиной и лентой уведомлений. Сейчас передаётся lot.ai_analyzed.
+type Forwarder struct {
+	hub    *Hub
+	lookup TenderOrganizationLookup
+	logger logging.Logger
+}
+
+// NewForwarder создаёт передачу доменных событий в hub.
+func NewForwarder(hub *Hub, lookup TenderOrganizationLookup, logger logging.Logger) *Forwarder {
+	return &Forwarder{
+		hub:    hub,
+		lookup: lookup,
+		logger: logger,
+	}
+}
+
+// Publish передаёт событие в поток. Без открытых соединений организация
+// тендера не запрашивается.
+func (f *Forwarder) Publish(ctx context.Context, event events.Event) error {
+	data, ok := event.Data.(events.LotAIAnalyzedData)
+	if !ok || !f.hub.HasSubscribers() {
+		return nil
+	}
+	organizationID, err := f.lookup(ctx, data.TenderID)
+	if err != nil {
+		return fmt.Errorf("ошибка GetTenderOrganizationID(%d): %w", data.TenderID, err)
+	}
+	f.hub.PublishOrganization(organizationID, Message{Type: TypeLotAIAnalyzed, Data: data})
+	return nil
+}
+
+// Close ничего не делает: события передаются синхронно в Publish.
+func (f *Forwarder) Close(context.Context) error { return nil }
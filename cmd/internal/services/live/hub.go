@@ -0,0 +1,167 @@
+// Package live доставляет события в открытые вкладки веб-интерфейса через
+// GET /api/v1/events (Server-Sent Events): прогресс разбора загруженного
+// файла, прогресс импорта тендера по лотам и завершение AI-анализа лота.
+//
+// Hub — шина в памяти процесса: каждое SSE-соединение подписывается на
+// события своего пользователя и его организации. Источники публикуют
+// события синхронно и не ждут клиентов: у соединения своя очередь
+// (live.buffer_size), и если клиент не успевает читать, новые события для
+// него отбрасываются. Доставка at-most-once и только в соединения этого
+// экземпляра API — после переподключения клиент дочитывает состояние через
+// REST (GET /api/v1/tasks, лента уведомлений).
+package live
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// Типы событий потока (поле event в SSE).
+const (
+	// Статус задачи парсера, о котором он сообщил сам (data — api_models.ParseTask)
+	TypeParseTaskStatus = "parse_task.status"
+	// Импорт тендера обработал очередной лот (data — ImportProgressData)
+	TypeImportProgress = "import.progress"
+	// Сохранён AI-анализ лота тендера организации (data — events.LotAIAnalyzedData)
+	TypeLotAIAnalyzed = "lot.ai_analyzed"
+)
+
+// ErrTooManyConnections — у пользователя уже открыто
+// live.max_connections_per_user соединений.
+var ErrTooManyConnections = errors.New("слишком много открытых потоков событий")
+
+// Message — событие потока.
+type Message struct {
+	Type string
+	Data any
+}
+
+// ImportProgressData — data события import.progress. Импорт идёт в одной
+// транзакции: обработанные лоты появляются в API только после последнего.
+type ImportProgressData struct {
+	TaskID    string `json:"task_id"`   // Задача парсера, загрузившая файл
+	TenderID  string `json:"tender_id"` // ID тендера на ЭТП
+	LotsDone  int    `json:"lots_done"`
+	LotsTotal int    `json:"lots_total"`
+}
+
+// Hub рассылает события подписчикам. Безопасен для параллельного использования.
+type Hub struct {
+	bufferSize int
+	maxPerUser int
+	logger     logging.Logger
+
+	mu     sync.Mutex
+	byUser map[int64]map[*Subscription]struct{}
+}
+
+// NewHub создаёт шину по настройкам live.
+func NewHub(cfg config.LiveConfig, logger logging.Logger) *Hub {
+	return &Hub{
+		bufferSize: cfg.BufferSize,
+		maxPerUser: cfg.MaxConnectionsPerUser,
+		logger:     logger.WithField("component", "live"),
+		byUser:     make(map[int64]map[*Subscription]struct{}),
+	}
+}
+
+// Subscription — подписка одного соединения.
+type Subscription struct {
+	hub            *Hub
+	userID         int64
+	organizationID int64
+	ch             chan Message
+	dropped        int
+}
+
+// Subscribe подписывает соединение пользователя userID из организации
+// organizationID. Подписку нужно закрыть (Close), когда соединение закрыто.
+func (h *Hub) Subscribe(userID, organizationID int64) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subs := h.byUser[userID]
+	if len(subs) >= h.maxPerUser {
+		return nil, ErrTooManyConnections
+	}
+	if subs == nil {
+		subs = make(map[*Subscription]struct{})
+		h.byUser[userID] = subs
+	}
+	sub := &Subscription{
+		hub:            h,
+		userID:         userID,
+		organizationID: organizationID,
+		ch:             make(chan Message, h.bufferSize),
+	}
+	subs[sub] = struct{}{}
+	return sub, nil
+}
+
+// C — события подписки.
+func (s *Subscription) C() <-chan Message {
+	return s.ch
+}
+
+// Close отписывает соединение. Повторный вызов ничего не делает.
+func (s *Subscription) Close() {
+	h := s.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subs := h.byUser[s.userID]
+	if _, ok := subs[s]; !ok {
+		return
+	}
+	delete(subs, s)
+	if len(subs) == 0 {
+		delete(h.byUser, s.userID)
+	}
+	if s.dropped > 0 {
+		h.logger.Warnf("Поток событий пользователя %d закрыт, отброшено событий: %d", s.userID, s.dropped)
+	}
+}
+
+// PublishUser отправляет событие во все соединения пользователя.
+func (h *Hub) PublishUser(userID int64, msg Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.byUser[userID] {
+		sub.send(msg)
+	}
+}
+
+// PublishOrganization отправляет событие во все соединения пользователей организации.
+func (h *Hub) PublishOrganization(organizationID int64, msg Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, subs := range h.byUser {
+		for sub := range subs {
+			if sub.organizationID == organizationID {
+				sub.send(msg)
+			}
+		}
+	}
+}
+
+// HasSubscribers сообщает, есть ли открытые соединения: источникам, которым
+// для адресата нужен запрос в БД, без подписчиков его делать незачем.
+func (h *Hub) HasSubscribers() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.byUser) > 0
+}
+
+// send кладёт событие в очередь без ожидания. Вызывается под h.mu.
+func (s *Subscription) send(msg Message) {
+	select {
+	case s.ch <- msg:
+	default:
+		s.dropped++
+	}
+}
@@ -0,0 +1,136 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR THE LIVE EVENT HUB (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. A user seeing another user's upload progress
+2. A stalled browser tab blocking the importer or the parser callback
+3. A tab left open in every window exhausting server memory
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Addressing
+- GIVEN two users with open streams
+  WHEN an event is published to user 1 THEN only user 1's streams receive it
+- GIVEN users of two organizations
+  WHEN an event is published to organization 10 THEN only its users receive it
+
+SCENARIO 2: Slow consumers
+- GIVEN a stream whose buffer is full
+  WHEN more events are published THEN Publish does not block and they are dropped
+
+SCENARIO 3: Connection limit
+- GIVEN max_connections_per_user open streams THEN the next Subscribe fails
+- WHEN one stream closes THEN a new one can be opened; Close is idempotent
+
+SCENARIO 4: Forwarding domain events
+- GIVEN no open streams THEN lot.ai_analyzed does not look up the tender
+- GIVEN an open stream of the tender's organization THEN it receives lot.ai_analyzed
+- GIVEN other event types THEN they are ignored
+- GIVEN a lookup error THEN it is returned for the log
+*/
+
+func newTestHub(t *testing.T) *Hub {
+	t.Helper()
+	return NewHub(config.LiveConfig{BufferSize: 2, MaxConnectionsPerUser: 2}, testutil.NewMockLogger())
+}
+
+func subscribe(t *testing.T, h *Hub, userID, organizationID int64) *Subscription {
+	t.Helper()
+	sub, err := h.Subscribe(userID, organizationID)
+	require.NoError(t, err)
+	t.Cleanup(sub.Close)
+	return sub
+}
+
+func TestHub_Addressing(t *testing.T) {
+	h := newTestHub(t)
+	user1 := subscribe(t, h, 1, 10)
+	user1Tab := subscribe(t, h, 1, 10)
+	user2 := subscribe(t, h, 2, 10)
+	otherOrg := subscribe(t, h, 3, 20)
+
+	h.PublishUser(1, Message{Type: TypeParseTaskStatus, Data: "t-1"})
+
+	assert.Len(t, user1.C(), 1)
+	assert.Len(t, user1Tab.C(), 1, "every tab of the user gets the event")
+	assert.Empty(t, user2.C())
+
+	h.PublishOrganization(10, Message{Type: TypeLotAIAnalyzed})
+
+	assert.Len(t, user1.C(), 2)
+	assert.Len(t, user2.C(), 1)
+	assert.Empty(t, otherOrg.C())
+	assert.Equal(t, TypeParseTaskStatus, (<-user1.C()).Type)
+}
+
+func TestHub_SlowConsumerDropsEvents(t *testing.T) {
+	h := newTestHub(t)
+	sub := subscribe(t, h, 1, 10)
+
+	for i := 0; i < 5; i++ {
+		h.PublishUser(1, Message{Type: TypeImportProgress, Data: i})
+	}
+
+	require.Len(t, sub.C(), 2)
+	assert.Equal(t, 0, (<-sub.C()).Data, "the oldest events are kept")
+	assert.Equal(t, 3, sub.dropped)
+}
+
+func TestHub_ConnectionLimit(t *testing.T) {
+	h := newTestHub(t)
+	first := subscribe(t, h, 1, 10)
+	subscribe(t, h, 1, 10)
+
+	_, err := h.Subscribe(1, 10)
+	assert.ErrorIs(t, err, ErrTooManyConnections)
+	subscribe(t, h, 2, 10)
+
+	first.Close()
+	first.Close()
+	subscribe(t, h, 1, 10)
+}
+
+func TestForwarder(t *testing.T) {
+	h := newTestHub(t)
+	lookups := 0
+	f := NewForwarder(h, func(_ context.Context, tenderID int64) (int64, error) {
+		lookups++
+		if tenderID == 404 {
+			return 0, errors.New("sql: no rows in result set")
+		}
+		return 10, nil
+	}, testutil.NewMockLogger())
+	analyzed := events.NewEvent(events.TypeLotAIAnalyzed, 5, events.LotAIAnalyzedData{LotID: 5, TenderID: 7})
+
+	require.NoError(t, f.Publish(context.Background(), analyzed))
+	assert.Zero(t, lookups, "no streams, no lookup")
+
+	sub := subscribe(t, h, 1, 10)
+	require.NoError(t, f.Publish(context.Background(), analyzed))
+	require.NoError(t, f.Publish(context.Background(), events.NewEvent(events.TypeLotUpdated, 5, events.LotUpdatedData{LotID: 5})))
+
+	require.Len(t, sub.C(), 1)
+	msg := <-sub.C()
+	assert.Equal(t, TypeLotAIAnalyzed, msg.Type)
+	assert.Equal(t, events.LotAIAnalyzedData{LotID: 5, TenderID: 7}, msg.Data)
+
+	err := f.Publish(context.Background(), events.NewEvent(events.TypeLotAIAnalyzed, 6, events.LotAIAnalyzedData{LotID: 6, TenderID: 404}))
+	assert.ErrorContains(t, err, "404")
+}
@@ -0,0 +1,128 @@
+package lot
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/sqlc-dev/pqtype"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+// ListAIAnalysisRuns возвращает сохранённые запуски AI-анализа лота, новые
+// первыми. Лот без запусков отдаёт пустой список, несуществующий — NotFoundError.
+func (s *LotService) ListAIAnalysisRuns(ctx context.Context, lotID int64) (*api_models.AIAnalysisRunsResponse, error) {
+	rows, err := s.store.ListAIAnalysisResultsByLot(ctx, lotID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения запусков AI-анализа лота: %w", err)
+	}
+	if len(rows) == 0 {
+		if _, err := s.store.GetLotByID(ctx, lotID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, apierrors.NewNotFoundError("lot.not_found", lotID)
+			}
+			return nil, fmt.Errorf("ошибка при поиске лота: %w", err)
+		}
+	}
+
+	// Текущий запуск — применённый к лоту последним
+	var current *db.AiAnalysisResult
+	for i := range rows {
+		if rows[i].PromotedAt.Valid && (current == nil || rows[i].PromotedAt.Time.After(current.PromotedAt.Time)) {
+			current = &rows[i]
+		}
+	}
+
+	runs := make([]api_models.AIAnalysisRun, 0, len(rows))
+	for i := range rows {
+		runs = append(runs, aiAnalysisRunResponse(rows[i], current != nil && rows[i].ID == current.ID))
+	}
+	return &api_models.AIAnalysisRunsResponse{LotID: lotID, Runs: runs}, nil
+}
+
+// PromoteAIAnalysisRun записывает в лот параметры ранее сохранённого запуска
+// AI-анализа, например когда последний запуск извлёк параметры с ошибками.
+// Запуск другого лота считается несуществующим. Изменённые ключи пишутся в
+// историю правок от имени changedBy.
+func (s *LotService) PromoteAIAnalysisRun(ctx context.Context, lotID, runID, changedBy int64) (*api_models.AIAnalysisRun, error) {
+	logger := s.logger.WithFields(map[string]interface{}{
+		"method": "PromoteAIAnalysisRun",
+		"lot_id": lotID,
+		"run_id": runID,
+	})
+
+	var run db.AiAnalysisResult
+	err := s.store.ExecTx(ctx, func(qtx *db.Queries) error {
+		var err error
+		run, err = qtx.PromoteAIAnalysisResult(ctx, db.PromoteAIAnalysisResultParams{
+			ID:    runID,
+			LotID: lotID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return apierrors.NewNotFoundError("lot.ai_run_not_found", runID, lotID)
+			}
+			logger.Errorf("Ошибка при отметке запуска AI-анализа: %v", err)
+			return fmt.Errorf("не удалось применить запуск AI-анализа: %w", err)
+		}
+
+		// Запуск мог быть сохранен до появления схемы категории
+		lot, err := qtx.GetLotForUpdate(ctx, lotID)
+		if err != nil {
+			return fmt.Errorf("ошибка при поиске лота: %w", err)
+		}
+		if err := s.validateKeyParameters(ctx, qtx, lot.TenderID, run.KeyParameters); err != nil {
+			return err
+		}
+
+		if _, err := qtx.UpdateLotDetails(ctx, db.UpdateLotDetailsParams{
+			ID: lotID,
+			LotKeyParameters: pqtype.NullRawMessage{
+				RawMessage: run.KeyParameters,
+				Valid:      true,
+			},
+		}); err != nil {
+			logger.Errorf("Ошибка при обновлении ключевых параметров лота: %v", err)
+			return fmt.Errorf("не удалось обновить ключевые параметры лота: %w", err)
+		}
+		return s.recordKeyParameterChanges(ctx, qtx, lotID, lot.LotKeyParameters, run.KeyParameters, changedBy)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Параметры запуска AI-анализа %d (модель %q, промпт %q) применены к лоту %d",
+		run.ID, run.ModelName, run.PromptVersion, lotID)
+
+	var keyParameters map[string]interface{}
+	if err := json.Unmarshal(run.KeyParameters, &keyParameters); err != nil {
+		logger.Warnf("Не удалось разобрать параметры запуска для события lot.updated: %v", err)
+	}
+	s.emitLotUpdated(ctx, logger, lotID, keyParameters)
+
+	response := aiAnalysisRunResponse(run, true)
+	return &response, nil
+}
+
+// aiAnalysisRunResponse преобразует строку ai_analysis_results в DTO.
+func aiAnalysisRunResponse(row db.AiAnalysisResult, isCurrent bool) api_models.AIAnalysisRun {
+	run := api_models.AIAnalysisRun{
+		ID:            row.ID,
+		LotID:         row.LotID,
+		ModelName:     row.ModelName,
+		PromptVersion: row.PromptVersion,
+		RawOutput:     row.RawOutput,
+		KeyParameters: row.KeyParameters,
+		CreatedAt:     row.CreatedAt,
+		IsCurrent:     isCurrent,
+	}
+	if row.PromotedAt.Valid {
+		promotedAt := row.PromotedAt.Time
+		run.PromotedAt = &promotedAt
+	}
+	return run
+}
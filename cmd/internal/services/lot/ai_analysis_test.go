@@ -0,0 +1,143 @@
+package lot
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR AI ANALYSIS RUNS (Unit Tests)
+
+SCENARIO 1: ListAIAnalysisRuns
+- GIVEN a lot with several runs, the older one promoted last
+  WHEN runs are listed
+  THEN all runs are returned newest first and only the last promoted one is current
+
+- GIVEN an existing lot without runs
+  WHEN runs are listed
+  THEN an empty list is returned (not 404)
+
+- GIVEN a non-existent lot
+  WHEN runs are listed
+  THEN NotFoundError is returned
+
+SCENARIO 2: PromoteAIAnalysisRun
+- GIVEN a run of the lot
+  WHEN it is promoted
+  THEN its key parameters are written to the lot, changed keys are recorded in the
+  field history on behalf of the user, and the run is returned as current
+
+- GIVEN a run of another lot or a missing run (sql.ErrNoRows)
+  WHEN it is promoted
+  THEN NotFoundError is returned and the lot is not updated
+*/
+
+func TestListAIAnalysisRuns_CurrentIsLastPromoted(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	now := time.Now()
+
+	mockStore.EXPECT().ListAIAnalysisResultsByLot(gomock.Any(), int64(5)).Return([]db.AiAnalysisResult{
+		{ID: 3, LotID: 5, ModelName: "gpt-4o", CreatedAt: now, PromotedAt: sql.NullTime{Time: now.Add(-time.Hour), Valid: true}},
+		{ID: 2, LotID: 5, ModelName: "gpt-4o-mini", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: 1, LotID: 5, ModelName: "gpt-4", CreatedAt: now.Add(-3 * time.Hour), PromotedAt: sql.NullTime{Time: now, Valid: true}},
+	}, nil)
+
+	resp, err := service.ListAIAnalysisRuns(context.Background(), 5)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(5), resp.LotID)
+	require.Len(t, resp.Runs, 3)
+	assert.Equal(t, int64(3), resp.Runs[0].ID)
+	assert.False(t, resp.Runs[0].IsCurrent)
+	assert.Nil(t, resp.Runs[1].PromotedAt)
+	assert.True(t, resp.Runs[2].IsCurrent, "текущий — запуск, применённый последним, а не самый новый")
+}
+
+func TestListAIAnalysisRuns_LotWithoutRuns_ReturnsEmptyList(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ListAIAnalysisResultsByLot(gomock.Any(), int64(5)).Return([]db.AiAnalysisResult{}, nil)
+	mockStore.EXPECT().GetLotByID(gomock.Any(), int64(5)).Return(db.Lot{ID: 5}, nil)
+
+	resp, err := service.ListAIAnalysisRuns(context.Background(), 5)
+	require.NoError(t, err)
+	assert.Empty(t, resp.Runs)
+	assert.NotNil(t, resp.Runs, "пустой список, а не null")
+}
+
+func TestListAIAnalysisRuns_LotNotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ListAIAnalysisResultsByLot(gomock.Any(), int64(5)).Return([]db.AiAnalysisResult{}, nil)
+	mockStore.EXPECT().GetLotByID(gomock.Any(), int64(5)).Return(db.Lot{}, sql.ErrNoRows)
+
+	_, err := service.ListAIAnalysisRuns(context.Background(), 5)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+}
+
+func TestPromoteAIAnalysisRun_Success(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	now := time.Now()
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("UPDATE ai_analysis_results").
+				WithArgs(int64(3), int64(5)).
+				WillReturnRows(sqlmock.NewRows(aiAnalysisColumns).
+					AddRow(int64(3), int64(5), "gpt-4", "v2", "raw", []byte(`{"area":"100"}`), now, now))
+
+			mock.ExpectQuery("SELECT .+ FROM lots WHERE id").
+				WithArgs(int64(5)).
+				WillReturnRows(sqlmock.NewRows(lotColumns).
+					AddRow(int64(5), "lot-key", "Test Lot", nil, int64(1), now, now))
+			expectNoKeyParameterSchema(mock)
+
+			mock.ExpectQuery("UPDATE lots").
+				WillReturnRows(sqlmock.NewRows(lotColumns).
+					AddRow(int64(5), "lot-key", "Test Lot", []byte(`{"area":"100"}`), int64(1), now, now))
+			mock.ExpectExec("INSERT INTO field_changes").
+				WithArgs("lot", int64(5), "lot_key_parameters.area", []byte(`null`), []byte(`"100"`), int64(42)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}),
+	)
+
+	run, err := service.PromoteAIAnalysisRun(context.Background(), 5, 3, 42)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(3), run.ID)
+	assert.Equal(t, "gpt-4", run.ModelName)
+	assert.JSONEq(t, `{"area":"100"}`, string(run.KeyParameters))
+	assert.True(t, run.IsCurrent)
+	require.NotNil(t, run.PromotedAt)
+}
+
+func TestPromoteAIAnalysisRun_RunOfAnotherLot_ReturnsNotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("UPDATE ai_analysis_results").
+				WithArgs(int64(3), int64(6)).
+				WillReturnError(sql.ErrNoRows)
+		}),
+	)
+
+	_, err := service.PromoteAIAnalysisRun(context.Background(), 6, 3, 42)
+
+	var notFoundErr *apierrors.NotFoundError
+	require.True(t, errors.As(err, &notFoundErr), "expected NotFoundError, got: %T", err)
+	assert.Contains(t, notFoundErr.Message, "3")
+}
@@ -0,0 +1,119 @@
+package lot
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/db/postgres"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/jsonschema"
+)
+
+// validateKeyParameters проверяет ключевые параметры лота по JSON Schema
+// категории тендера. Без категории или схемы параметры не проверяются.
+// Нарушения возвращаются как ValidationError с перечнем полей в Details.
+func (s *LotService) validateKeyParameters(ctx context.Context, q db.Querier, tenderID int64, keyParamsJSON []byte) error {
+	row, err := q.GetLotKeyParameterSchemaByTender(ctx, tenderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("ошибка получения схемы ключевых параметров: %w", err)
+	}
+
+	schema, err := jsonschema.Compile(row.Schema)
+	if err != nil {
+		// Схемы проверяются при сохранении; сюда попадает только схема,
+		// записанная в обход API.
+		return fmt.Errorf("некорректная схема ключевых параметров категории %d: %w", row.TenderCategoryID, err)
+	}
+
+	if err := schema.Validate(keyParamsJSON); err != nil {
+		var schemaErr *jsonschema.ValidationError
+		if errors.As(err, &schemaErr) {
+			s.logger.Warnf("Ключевые параметры не соответствуют схеме категории %d: %v", row.TenderCategoryID, schemaErr)
+			return apierrors.NewValidationErrorWithDetails(schemaErr.Errors,
+				"lot.key_parameters_invalid", row.TenderCategoryID, schemaErr.Error())
+		}
+		return fmt.Errorf("не удалось проверить ключевые параметры: %w", err)
+	}
+	return nil
+}
+
+// ListKeyParameterSchemas возвращает схемы ключевых параметров всех категорий.
+func (s *LotService) ListKeyParameterSchemas(ctx context.Context) ([]api_models.LotKeyParameterSchemaResponse, error) {
+	rows, err := s.store.ListLotKeyParameterSchemas(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения схем ключевых параметров: %w", err)
+	}
+	result := make([]api_models.LotKeyParameterSchemaResponse, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, keyParameterSchemaResponse(row))
+	}
+	return result, nil
+}
+
+// GetKeyParameterSchema возвращает схему ключевых параметров категории.
+func (s *LotService) GetKeyParameterSchema(ctx context.Context, categoryID int64) (*api_models.LotKeyParameterSchemaResponse, error) {
+	row, err := s.store.GetLotKeyParameterSchema(ctx, categoryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewNotFoundError("lot.schema_not_set", categoryID)
+		}
+		return nil, fmt.Errorf("ошибка получения схемы ключевых параметров: %w", err)
+	}
+	response := keyParameterSchemaResponse(row)
+	return &response, nil
+}
+
+// PutKeyParameterSchema задает или заменяет схему ключевых параметров категории.
+// Схема компилируется до сохранения: неподдерживаемые ключевые слова и
+// некорректные правила отклоняются с ValidationError.
+func (s *LotService) PutKeyParameterSchema(ctx context.Context, categoryID int64, schema json.RawMessage) (*api_models.LotKeyParameterSchemaResponse, error) {
+	if _, err := jsonschema.Compile(schema); err != nil {
+		return nil, apierrors.NewValidationError("lot.invalid_schema", err)
+	}
+
+	row, err := s.store.UpsertLotKeyParameterSchema(ctx, db.UpsertLotKeyParameterSchemaParams{
+		TenderCategoryID: categoryID,
+		Schema:           schema,
+	})
+	if err != nil {
+		if postgres.IsForeignKeyViolation(err) {
+			return nil, apierrors.NewNotFoundError("lot.category_not_found", categoryID)
+		}
+		return nil, fmt.Errorf("ошибка сохранения схемы ключевых параметров: %w", err)
+	}
+
+	s.logger.Infof("Схема ключевых параметров категории %d сохранена", categoryID)
+	response := keyParameterSchemaResponse(row)
+	return &response, nil
+}
+
+// DeleteKeyParameterSchema удаляет схему категории: параметры ее лотов
+// перестают проверяться.
+func (s *LotService) DeleteKeyParameterSchema(ctx context.Context, categoryID int64) error {
+	deleted, err := s.store.DeleteLotKeyParameterSchema(ctx, categoryID)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления схемы ключевых параметров: %w", err)
+	}
+	if deleted == 0 {
+		return apierrors.NewNotFoundError("lot.schema_not_set", categoryID)
+	}
+	s.logger.Infof("Схема ключевых параметров категории %d удалена", categoryID)
+	return nil
+}
+
+func keyParameterSchemaResponse(row db.LotKeyParameterSchema) api_models.LotKeyParameterSchemaResponse {
+	return api_models.LotKeyParameterSchemaResponse{
+		TenderCategoryID: row.TenderCategoryID,
+		Schema:           row.Schema,
+		CreatedAt:        row.CreatedAt,
+		UpdatedAt:        row.UpdatedAt,
+	}
+}
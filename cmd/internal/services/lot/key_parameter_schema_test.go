@@ -0,0 +1,90 @@
+package lot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR KEY PARAMETER SCHEMAS (Unit Tests)
+
+SCENARIO 1: PutKeyParameterSchema
+- GIVEN a valid schema for an existing category
+  WHEN it is saved
+  THEN it is upserted and returned
+
+- GIVEN a schema with an unsupported keyword
+  WHEN it is saved
+  THEN ValidationError is returned and nothing is written
+
+- GIVEN a non-existent category (foreign key violation)
+  WHEN a schema is saved
+  THEN NotFoundError is returned
+
+SCENARIO 2: DeleteKeyParameterSchema
+- GIVEN a category without a schema
+  WHEN the schema is deleted
+  THEN NotFoundError is returned
+*/
+
+func TestPutKeyParameterSchema_Success(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	schema := json.RawMessage(`{"type":"object","properties":{"area":{"type":"number"}}}`)
+	now := time.Now()
+
+	mockStore.EXPECT().UpsertLotKeyParameterSchema(gomock.Any(), db.UpsertLotKeyParameterSchemaParams{
+		TenderCategoryID: 3,
+		Schema:           schema,
+	}).Return(db.LotKeyParameterSchema{TenderCategoryID: 3, Schema: schema, CreatedAt: now, UpdatedAt: now}, nil)
+
+	resp, err := service.PutKeyParameterSchema(context.Background(), 3, schema)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), resp.TenderCategoryID)
+	assert.JSONEq(t, string(schema), string(resp.Schema))
+}
+
+func TestPutKeyParameterSchema_InvalidSchema(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	// No store expectation: the schema is rejected before saving
+	_, err := service.PutKeyParameterSchema(context.Background(), 3, json.RawMessage(`{"type":"object","oneOf":[]}`))
+
+	var validationErr *apierrors.ValidationError
+	require.True(t, errors.As(err, &validationErr), "expected ValidationError, got: %T", err)
+	assert.Contains(t, validationErr.Message, "oneOf")
+}
+
+func TestPutKeyParameterSchema_UnknownCategory(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().UpsertLotKeyParameterSchema(gomock.Any(), gomock.Any()).
+		Return(db.LotKeyParameterSchema{}, &pq.Error{Code: "23503"})
+
+	_, err := service.PutKeyParameterSchema(context.Background(), 999, json.RawMessage(`{"type":"object"}`))
+
+	var notFoundErr *apierrors.NotFoundError
+	require.True(t, errors.As(err, &notFoundErr), "expected NotFoundError, got: %T", err)
+	assert.Contains(t, notFoundErr.Message, "999")
+}
+
+func TestDeleteKeyParameterSchema_NotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().DeleteLotKeyParameterSchema(gomock.Any(), int64(3)).Return(int64(0), nil)
+
+	err := service.DeleteKeyParameterSchema(context.Background(), 3)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+}
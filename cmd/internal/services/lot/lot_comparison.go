@@ -0,0 +1,272 @@
+package lot
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+// moneyPrecision — знаков после запятой при переводе пересчитанных сумм в Money.
+// С запасом относительно копеек, чтобы округление происходило один раз — при выводе.
+const moneyPrecision = 6
+
+// comparisonAggregate — накопленные значения одной позиции каталога в одном предложении.
+// Одна и та же позиция каталога может встречаться в КП несколько раз (в разных главах),
+// поэтому количества и стоимости суммируются. Стоимости в разных валютах не складываются:
+// для такой позиции возвращается только количество.
+type comparisonAggregate struct {
+	quantity      *big.Rat
+	totalCost     *big.Rat
+	currency      string
+	mixedCurrency bool
+	first         db.ListLotComparisonItemsRow // исходная строка: если она одна, значения отдаются как есть
+	items         int
+}
+
+// GetLotComparison строит матрицу сравнения предложений по лоту:
+// строки — позиции каталога, колонки — предложения (baseline первым).
+//
+// Для каждой ячейки возвращаются количество, цена за единицу, стоимость
+// и отклонение от baseline в процентах. Если позиция есть у кого-то из
+// участников, но отсутствует в предложении, ячейка помечается is_missing.
+// Несопоставленные с каталогом строки в матрицу не попадают.
+func (s *LotService) GetLotComparison(ctx context.Context, lotID int64) (*api_models.LotComparisonResponse, error) {
+	logger := s.logger.WithField("method", "GetLotComparison").WithField("lot_id", lotID)
+
+	if lotID <= 0 {
+		return nil, apierrors.NewValidationError("request.id_positive_got", lotID)
+	}
+
+	lot, err := s.store.GetLotByID(ctx, lotID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewNotFoundError("common.lot_not_found", lotID)
+		}
+		return nil, fmt.Errorf("ошибка получения лота %d: %w", lotID, err)
+	}
+
+	// Лоты мягко удалённого тендера скрываются вместе с ним
+	tender, err := s.store.GetTenderByID(ctx, lot.TenderID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения тендера лота %d: %w", lotID, err)
+	}
+	if tender.DeletedAt.Valid {
+		return nil, apierrors.NewNotFoundError("common.lot_not_found", lotID)
+	}
+
+	proposals, err := s.store.GetProposalsByLotIDs(ctx, []int64{lotID})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения предложений лота %d: %w", lotID, err)
+	}
+
+	items, err := s.store.ListLotComparisonItems(ctx, lotID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения позиций лота %d: %w", lotID, err)
+	}
+
+	response := buildLotComparison(lot, proposals, items)
+	logger.Infof("Матрица сравнения: %d позиций x %d предложений", len(response.Positions), len(response.Contractors))
+	return response, nil
+}
+
+// buildLotComparison собирает матрицу из сырых строк БД. Вынесена отдельно от
+// GetLotComparison, чтобы расчеты можно было тестировать без моков БД.
+func buildLotComparison(
+	lot db.Lot,
+	proposals []db.GetProposalsByLotIDsRow,
+	items []db.ListLotComparisonItemsRow,
+) *api_models.LotComparisonResponse {
+	// 1. Колонки: baseline первым, дальше в порядке запроса (победители, затем по цене)
+	contractors := make([]api_models.LotComparisonContractor, 0, len(proposals))
+	for _, p := range proposals {
+		contractors = append(contractors, api_models.LotComparisonContractor{
+			ProposalID:     p.ID,
+			ContractorID:   p.ContractorID,
+			ContractorName: p.ContractorName,
+			ContractorInn:  p.ContractorInn,
+			IsBaseline:     p.IsBaseline,
+			TotalCost:      api_models.MoneyFromNullString(p.TotalCost),
+			Currency:       p.Currency,
+		})
+	}
+	sort.SliceStable(contractors, func(i, j int) bool {
+		return contractors[i].IsBaseline && !contractors[j].IsBaseline
+	})
+
+	var baselineID int64
+	if len(contractors) > 0 && contractors[0].IsBaseline {
+		baselineID = contractors[0].ProposalID
+	}
+
+	// 2. Агрегируем строки по (catalog_position_id, proposal_id), сохраняя порядок позиций
+	type rowMeta struct {
+		title    string
+		unitName sql.NullString
+	}
+	var order []int64
+	meta := make(map[int64]rowMeta)
+	cells := make(map[int64]map[int64]*comparisonAggregate)
+
+	for _, item := range items {
+		if !item.CatalogPositionID.Valid {
+			continue
+		}
+		catalogID := item.CatalogPositionID.Int64
+		if _, ok := meta[catalogID]; !ok {
+			order = append(order, catalogID)
+			meta[catalogID] = rowMeta{title: item.CatalogName, unitName: item.UnitName}
+			cells[catalogID] = make(map[int64]*comparisonAggregate)
+		}
+
+		agg, ok := cells[catalogID][item.ProposalID]
+		if !ok {
+			agg = &comparisonAggregate{}
+			cells[catalogID][item.ProposalID] = agg
+		}
+		if agg.items == 0 {
+			agg.first = item
+			agg.currency = item.Currency
+		} else if item.Currency != agg.currency {
+			agg.mixedCurrency = true
+			agg.totalCost = nil
+		}
+		agg.items++
+		agg.quantity = addRat(agg.quantity, item.Quantity)
+		if !agg.mixedCurrency {
+			agg.totalCost = addRat(agg.totalCost, item.TotalCostTotal)
+		}
+	}
+
+	// 3. Формируем строки матрицы
+	missing := make(map[int64]int, len(contractors))
+	positions := make([]api_models.LotComparisonRow, 0, len(order))
+	for _, catalogID := range order {
+		row := api_models.LotComparisonRow{
+			CatalogPositionID: catalogID,
+			StandardJobTitle:  meta[catalogID].title,
+			UnitName:          nullStringPtr(meta[catalogID].unitName),
+			Cells:             make([]api_models.LotComparisonCell, 0, len(contractors)),
+		}
+
+		var (
+			baselineTotal    *big.Rat
+			baselineCurrency string
+		)
+		if b, ok := cells[catalogID][baselineID]; ok && baselineID != 0 {
+			baselineTotal = b.totalCost
+			baselineCurrency = b.currency
+		}
+
+		for _, c := range contractors {
+			agg, ok := cells[catalogID][c.ProposalID]
+			if !ok {
+				missing[c.ProposalID]++
+				row.Cells = append(row.Cells, api_models.LotComparisonCell{ProposalID: c.ProposalID, IsMissing: true})
+				continue
+			}
+			row.Cells = append(row.Cells, agg.toCell(c.ProposalID, baselineTotal, baselineCurrency, c.IsBaseline))
+		}
+		positions = append(positions, row)
+	}
+
+	for i := range contractors {
+		contractors[i].MissingPositionsCount = missing[contractors[i].ProposalID]
+	}
+
+	return &api_models.LotComparisonResponse{
+		LotID:       lot.ID,
+		LotTitle:    lot.LotTitle,
+		Contractors: contractors,
+		Positions:   positions,
+	}
+}
+
+// toCell переводит агрегат в ячейку ответа. Отклонение от baseline считается
+// только в одной валюте: курсы в матрице сравнения не применяются.
+func (a *comparisonAggregate) toCell(proposalID int64, baselineTotal *big.Rat, baselineCurrency string, isBaseline bool) api_models.LotComparisonCell {
+	cell := api_models.LotComparisonCell{ProposalID: proposalID}
+	if !a.mixedCurrency {
+		cell.Currency = a.currency
+	}
+
+	// Одна строка — значения как в КП; несколько — пересчитываем из сумм
+	if a.items == 1 {
+		cell.Quantity = nullStringPtr(a.first.Quantity)
+		cell.UnitCost = api_models.MoneyFromNullString(a.first.UnitCostTotal)
+		cell.TotalCost = api_models.MoneyFromNullString(a.first.TotalCostTotal)
+	} else {
+		cell.Quantity = quantityPtr(a.quantity)
+		cell.TotalCost = moneyPtr(a.totalCost)
+		if a.quantity != nil && a.totalCost != nil && a.quantity.Sign() != 0 {
+			cell.UnitCost = moneyPtr(new(big.Rat).Quo(a.totalCost, a.quantity))
+		}
+	}
+
+	if !isBaseline && baselineTotal != nil && baselineTotal.Sign() != 0 && a.totalCost != nil && a.currency == baselineCurrency {
+		deviation := new(big.Rat).Sub(a.totalCost, baselineTotal)
+		deviation.Quo(deviation, baselineTotal)
+		deviation.Mul(deviation, big.NewRat(100, 1))
+		cell.DeviationFromBaselinePercent = ratPtr(deviation)
+	}
+
+	return cell
+}
+
+// addRat прибавляет десятичную строку из БД к сумме. NULL и нечисловые значения пропускаются.
+func addRat(sum *big.Rat, value sql.NullString) *big.Rat {
+	if !value.Valid {
+		return sum
+	}
+	v, ok := new(big.Rat).SetString(value.String)
+	if !ok {
+		return sum
+	}
+	if sum == nil {
+		return v
+	}
+	return sum.Add(sum, v)
+}
+
+// ratPtr форматирует процент с двумя знаками после запятой.
+func ratPtr(r *big.Rat) *string {
+	if r == nil {
+		return nil
+	}
+	s := r.FloatString(2)
+	return &s
+}
+
+// moneyPtr переводит точную сумму в Money; округление до копеек — при выводе в JSON.
+func moneyPtr(r *big.Rat) *api_models.Money {
+	if r == nil {
+		return nil
+	}
+	return api_models.MoneyPtr(decimal.NewFromBigRat(r, moneyPrecision))
+}
+
+// quantityPtr форматирует количество без лишних нулей (объемы бывают дробными: 0.125 м3).
+func quantityPtr(r *big.Rat) *string {
+	if r == nil {
+		return nil
+	}
+	s := strings.TrimRight(strings.TrimRight(r.FloatString(6), "0"), ".")
+	return &s
+}
+
+func nullStringPtr(ns sql.NullString) *string {
+	if !ns.Valid {
+		return nil
+	}
+	s := ns.String
+	return &s
+}
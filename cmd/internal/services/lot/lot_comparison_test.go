@@ -0,0 +1,163 @@
+package lot
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR LOT COMPARISON (Unit Tests)
+
+SCENARIO 1: buildLotComparison
+- GIVEN a baseline and two contractors, one of which lacks a position
+  WHEN the matrix is built
+  THEN baseline is the first column, the gap is flagged is_missing
+  AND missing_positions_count is incremented for that contractor
+
+- GIVEN a contractor priced above baseline
+  WHEN the matrix is built
+  THEN deviation_from_baseline_percent is computed exactly (no float rounding)
+
+- GIVEN the same catalog position twice in one proposal (different chapters)
+  WHEN the matrix is built
+  THEN quantity and total cost are summed and unit cost is recomputed
+
+- GIVEN a contractor priced in a currency other than baseline's
+  WHEN the matrix is built
+  THEN the cell carries its currency and no deviation is computed
+  AND the same position in two currencies within one proposal is not summed
+
+SCENARIO 2: GetLotComparison
+- GIVEN a non-existent lot
+  WHEN GetLotComparison is called
+  THEN NotFoundError is returned
+
+- GIVEN a lot of a soft-deleted tender
+  WHEN GetLotComparison is called
+  THEN NotFoundError is returned (the lot is hidden together with the tender)
+*/
+
+func ns(v string) sql.NullString {
+	return sql.NullString{String: v, Valid: true}
+}
+
+func cid(v int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: v, Valid: true}
+}
+
+func TestBuildLotComparison_MatrixWithBaselineAndGaps(t *testing.T) {
+	lot := db.Lot{ID: 7, LotTitle: "Лот 1"}
+	proposals := []db.GetProposalsByLotIDsRow{
+		{ID: 2, ContractorID: 20, ContractorName: "ООО Альфа", TotalCost: ns("1000")},
+		{ID: 1, ContractorID: 10, ContractorName: "Initiator", IsBaseline: true},
+		{ID: 3, ContractorID: 30, ContractorName: "ООО Бета"},
+	}
+	items := []db.ListLotComparisonItemsRow{
+		{ProposalID: 1, CatalogPositionID: cid(100), CatalogName: "бетон", Quantity: ns("10"), UnitCostTotal: ns("100"), TotalCostTotal: ns("1000")},
+		{ProposalID: 2, CatalogPositionID: cid(100), CatalogName: "бетон", Quantity: ns("10"), UnitCostTotal: ns("112.5"), TotalCostTotal: ns("1125")},
+		{ProposalID: 3, CatalogPositionID: cid(100), CatalogName: "бетон", Quantity: ns("10"), UnitCostTotal: ns("90"), TotalCostTotal: ns("900")},
+		{ProposalID: 1, CatalogPositionID: cid(200), CatalogName: "опалубка", Quantity: ns("5"), TotalCostTotal: ns("300")},
+		{ProposalID: 3, CatalogPositionID: cid(200), CatalogName: "опалубка", Quantity: ns("5"), TotalCostTotal: ns("310")},
+	}
+
+	result := buildLotComparison(lot, proposals, items)
+
+	require.Len(t, result.Contractors, 3)
+	assert.True(t, result.Contractors[0].IsBaseline, "baseline must be the first column")
+	assert.Equal(t, int64(2), result.Contractors[1].ProposalID)
+
+	require.Len(t, result.Positions, 2)
+	concrete := result.Positions[0]
+	assert.Equal(t, int64(100), concrete.CatalogPositionID)
+	require.Len(t, concrete.Cells, 3)
+	assert.Nil(t, concrete.Cells[0].DeviationFromBaselinePercent, "baseline has no deviation from itself")
+	require.NotNil(t, concrete.Cells[1].DeviationFromBaselinePercent)
+	assert.Equal(t, "12.50", *concrete.Cells[1].DeviationFromBaselinePercent)
+	assert.Equal(t, "-10.00", *concrete.Cells[2].DeviationFromBaselinePercent)
+
+	formwork := result.Positions[1]
+	assert.True(t, formwork.Cells[1].IsMissing)
+	assert.Equal(t, 1, result.Contractors[1].MissingPositionsCount)
+	assert.Equal(t, 0, result.Contractors[2].MissingPositionsCount)
+}
+
+func TestBuildLotComparison_DuplicatePositionIsAggregated(t *testing.T) {
+	proposals := []db.GetProposalsByLotIDsRow{{ID: 1, ContractorName: "ООО Альфа"}}
+	items := []db.ListLotComparisonItemsRow{
+		{ProposalID: 1, CatalogPositionID: cid(100), CatalogName: "кладка", Quantity: ns("1.5"), TotalCostTotal: ns("150.10")},
+		{ProposalID: 1, CatalogPositionID: cid(100), CatalogName: "кладка", Quantity: ns("2.5"), TotalCostTotal: ns("249.90")},
+	}
+
+	result := buildLotComparison(db.Lot{ID: 1}, proposals, items)
+
+	require.Len(t, result.Positions, 1)
+	cell := result.Positions[0].Cells[0]
+	assert.Equal(t, "4", *cell.Quantity)
+	assert.Equal(t, "400.00", cell.TotalCost.String())
+	assert.Equal(t, "100.00", cell.UnitCost.String())
+}
+
+func TestBuildLotComparison_ForeignCurrency_NoDeviation(t *testing.T) {
+	proposals := []db.GetProposalsByLotIDsRow{
+		{ID: 1, ContractorName: "Initiator", IsBaseline: true, Currency: "RUB"},
+		{ID: 2, ContractorName: "Alpha GmbH", TotalCost: ns("12"), Currency: "EUR"},
+	}
+	items := []db.ListLotComparisonItemsRow{
+		{ProposalID: 1, CatalogPositionID: cid(100), CatalogName: "бетон", Quantity: ns("1"), TotalCostTotal: ns("1000"), Currency: "RUB"},
+		{ProposalID: 2, CatalogPositionID: cid(100), CatalogName: "бетон", Quantity: ns("1"), TotalCostTotal: ns("11"), Currency: "EUR"},
+		{ProposalID: 2, CatalogPositionID: cid(200), CatalogName: "арматура", Quantity: ns("1"), TotalCostTotal: ns("5"), Currency: "EUR"},
+		{ProposalID: 2, CatalogPositionID: cid(200), CatalogName: "арматура", Quantity: ns("2"), TotalCostTotal: ns("300"), Currency: "RUB"},
+	}
+
+	result := buildLotComparison(db.Lot{ID: 1}, proposals, items)
+
+	assert.Equal(t, "EUR", result.Contractors[1].Currency)
+	require.Len(t, result.Positions, 2)
+
+	concrete := result.Positions[0].Cells[1]
+	assert.Equal(t, "EUR", concrete.Currency)
+	assert.Equal(t, "11.00", concrete.TotalCost.String())
+	assert.Nil(t, concrete.DeviationFromBaselinePercent, "суммы в разных валютах не сравниваются")
+
+	rebar := result.Positions[1].Cells[1]
+	assert.Equal(t, "3", *rebar.Quantity)
+	assert.Empty(t, rebar.Currency)
+	assert.Nil(t, rebar.TotalCost, "стоимости в разных валютах не складываются")
+	assert.Nil(t, rebar.UnitCost)
+}
+
+func TestGetLotComparison_LotNotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetLotByID(gomock.Any(), int64(5)).Return(db.Lot{}, sql.ErrNoRows)
+
+	_, err := service.GetLotComparison(context.Background(), 5)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+}
+
+func TestGetLotComparison_DeletedTender_ReturnsNotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetLotByID(gomock.Any(), int64(5)).Return(db.Lot{ID: 5, TenderID: 9}, nil)
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(9)).Return(db.Tender{
+		ID:        9,
+		DeletedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}, nil)
+
+	_, err := service.GetLotComparison(context.Background(), 5)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+}
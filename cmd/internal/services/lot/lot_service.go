@@ -8,22 +8,27 @@ import (
 	"strconv"
 
 	"github.com/sqlc-dev/pqtype"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
 	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/history"
 	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
 )
 
 // LotService управляет операциями с лотами
 type LotService struct {
-	store  db.Store
-	logger logging.Logger
+	store     db.Store
+	logger    logging.Logger
+	publisher events.Publisher
 }
 
 // NewLotService создает новый экземпляр LotService
-func NewLotService(store db.Store, logger logging.Logger) *LotService {
+func NewLotService(store db.Store, logger logging.Logger, publisher events.Publisher) *LotService {
 	return &LotService{
-		store:  store,
-		logger: logger,
+		store:     store,
+		logger:    logger,
+		publisher: publisher,
 	}
 }
 
@@ -44,13 +49,14 @@ func (s *LotService) UpdateLotKeyParameters(
 		return fmt.Errorf("не удалось сериализовать ключевые параметры: %w", err)
 	}
 
-	return s.store.ExecTx(ctx, func(qtx *db.Queries) error {
+	var updatedLotID int64
+	err = s.store.ExecTx(ctx, func(qtx *db.Queries) error {
 		// Сначала найдем тендер по ETP ID
 		tender, err := qtx.GetTenderByEtpID(ctx, tenderEtpID)
 		if err != nil {
 			if err == sql.ErrNoRows {
 				logger.Warnf("Тендер с ETP ID %s не найден", tenderEtpID)
-				return apierrors.NewNotFoundError("тендер с ID %s не найден", tenderEtpID)
+				return apierrors.NewNotFoundError("lot.tender_not_found", tenderEtpID)
 			}
 			logger.Errorf("Ошибка при поиске тендера %s: %v", tenderEtpID, err)
 			return fmt.Errorf("ошибка при поиске тендера: %w", err)
@@ -64,12 +70,16 @@ func (s *LotService) UpdateLotKeyParameters(
 		if err != nil {
 			if err == sql.ErrNoRows {
 				logger.Warnf("Лот с ключом %s не найден в тендере %s", lotKey, tenderEtpID)
-				return apierrors.NewNotFoundError("лот с ключом %s не найден в тендере %s", lotKey, tenderEtpID)
+				return apierrors.NewNotFoundError("lot.key_not_found", lotKey, tenderEtpID)
 			}
 			logger.Errorf("Ошибка при поиске лота %s в тендере %s: %v", lotKey, tenderEtpID, err)
 			return fmt.Errorf("ошибка при поиске лота: %w", err)
 		}
 
+		if err := s.validateKeyParameters(ctx, qtx, tender.ID, keyParamsJSON); err != nil {
+			return err
+		}
+
 		// Обновляем ключевые параметры лота
 		updatedLot, err := qtx.UpdateLotDetails(ctx, db.UpdateLotDetailsParams{
 			ID: lot.ID,
@@ -85,17 +95,26 @@ func (s *LotService) UpdateLotKeyParameters(
 
 		logger.Infof("Ключевые параметры успешно обновлены для лота ID %d (тендер %s, лот %s)",
 			updatedLot.ID, tenderEtpID, lotKey)
+		updatedLotID = updatedLot.ID
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	s.emitLotUpdated(ctx, logger, updatedLotID, keyParameters)
+	return nil
 }
 
-// UpdateLotKeyParametersDirectly обновляет ключевые параметры лота напрямую по lot_id (DB ID)
-// без проверки tender_id - используется когда у нас есть только внутренние ID из БД
+// UpdateLotKeyParametersDirectly сохраняет запуск AI-анализа лота в
+// ai_analysis_results и записывает его параметры в лот по lot_id (DB ID)
+// без проверки tender_id - используется когда у нас есть только внутренние ID из БД.
+// Возвращает ID сохранённого запуска.
 func (s *LotService) UpdateLotKeyParametersDirectly(
 	ctx context.Context,
 	lotIDStr string,
-	keyParameters map[string]interface{},
-) error {
+	result api_models.SimpleLotAIResult,
+) (int64, error) {
 	logger := s.logger.WithFields(map[string]interface{}{
 		"method": "UpdateLotKeyParametersDirectly",
 		"lot_id": lotIDStr,
@@ -106,33 +125,52 @@ func (s *LotService) UpdateLotKeyParametersDirectly(
 	lotID, err := strconv.ParseInt(lotIDStr, 10, 64)
 	if err != nil {
 		logger.Errorf("Неверный формат lot_id: %s", lotIDStr)
-		return apierrors.NewValidationError("неверный формат lot_id: %s", lotIDStr)
+		return 0, apierrors.NewValidationError("lot.invalid_lot_id", lotIDStr)
 	}
 
 	if lotID <= 0 {
 		logger.Errorf("Некорректный lot_id: %d", lotID)
-		return apierrors.NewValidationError("lot_id должен быть положительным числом: %s", lotIDStr)
+		return 0, apierrors.NewValidationError("lot.lot_id_positive", lotIDStr)
 	}
 
 	// Сериализуем keyParameters в JSON
-	keyParamsJSON, err := json.Marshal(keyParameters)
+	keyParamsJSON, err := json.Marshal(result.LotKeyParameters)
 	if err != nil {
 		logger.Errorf("Ошибка сериализации ключевых параметров: %v", err)
-		return fmt.Errorf("не удалось сериализовать ключевые параметры: %w", err)
+		return 0, fmt.Errorf("не удалось сериализовать ключевые параметры: %w", err)
 	}
 
-	return s.store.ExecTx(ctx, func(qtx *db.Queries) error {
+	var run db.AiAnalysisResult
+	var tenderID int64
+	err = s.store.ExecTx(ctx, func(qtx *db.Queries) error {
 		// Просто найдем лот по ID для проверки существования
 		lot, err := qtx.GetLotByID(ctx, lotID)
 		if err != nil {
 			if err == sql.ErrNoRows {
 				logger.Warnf("Лот с ID %d не найден", lotID)
-				return apierrors.NewNotFoundError("лот с ID %s не найден", lotIDStr)
+				return apierrors.NewNotFoundError("lot.not_found_by_id", lotIDStr)
 			}
 			logger.Errorf("Ошибка при поиске лота %d: %v", lotID, err)
 			return fmt.Errorf("ошибка при поиске лота: %w", err)
 		}
 
+		if err := s.validateKeyParameters(ctx, qtx, lot.TenderID, keyParamsJSON); err != nil {
+			return err
+		}
+
+		// Сохраняем запуск, чтобы параметры лота можно было отследить до модели и промпта
+		run, err = qtx.CreateAIAnalysisResult(ctx, db.CreateAIAnalysisResultParams{
+			LotID:         lot.ID,
+			ModelName:     result.ModelName,
+			PromptVersion: result.PromptVersion,
+			RawOutput:     result.RawOutput,
+			KeyParameters: keyParamsJSON,
+		})
+		if err != nil {
+			logger.Errorf("Ошибка при сохранении запуска AI-анализа лота ID %d: %v", lot.ID, err)
+			return fmt.Errorf("не удалось сохранить запуск AI-анализа: %w", err)
+		}
+
 		// Обновляем ключевые параметры лота
 		updatedLot, err := qtx.UpdateLotDetails(ctx, db.UpdateLotDetailsParams{
 			ID: lot.ID,
@@ -146,7 +184,93 @@ func (s *LotService) UpdateLotKeyParametersDirectly(
 			return fmt.Errorf("не удалось обновить ключевые параметры лота: %w", err)
 		}
 
-		logger.Infof("Ключевые параметры успешно обновлены для лота ID %d", updatedLot.ID)
+		logger.Infof("Ключевые параметры успешно обновлены для лота ID %d (запуск AI-анализа %d, модель %q)",
+			updatedLot.ID, run.ID, run.ModelName)
+		tenderID = lot.TenderID
 		return nil
 	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.emitLotUpdated(ctx, logger, lotID, result.LotKeyParameters)
+	events.Emit(ctx, s.publisher, logger, events.TypeLotAIAnalyzed, lotID, events.LotAIAnalyzedData{
+		LotID:              lotID,
+		TenderID:           tenderID,
+		AIAnalysisResultID: run.ID,
+		ModelName:          run.ModelName,
+	})
+	return run.ID, nil
+}
+
+// PatchKeyParameters заменяет ключевые параметры лота по ручной правке
+// (PATCH /api/v1/lots/:id/key-parameters) и в той же транзакции пишет
+// изменённые ключи в историю правок от имени changedBy.
+func (s *LotService) PatchKeyParameters(
+	ctx context.Context,
+	lotID int64,
+	keyParameters map[string]interface{},
+	changedBy int64,
+) (db.Lot, error) {
+	logger := s.logger.WithField("method", "PatchKeyParameters").WithField("lot_id", lotID)
+
+	keyParamsJSON, err := json.Marshal(keyParameters)
+	if err != nil {
+		logger.Errorf("Ошибка сериализации ключевых параметров: %v", err)
+		return db.Lot{}, fmt.Errorf("не удалось сериализовать ключевые параметры: %w", err)
+	}
+
+	var updated db.Lot
+	err = s.store.ExecTx(ctx, func(qtx *db.Queries) error {
+		before, err := qtx.GetLotForUpdate(ctx, lotID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return apierrors.NewNotFoundError("lot.not_found", lotID)
+			}
+			return fmt.Errorf("ошибка при поиске лота: %w", err)
+		}
+
+		updated, err = qtx.UpdateLotDetails(ctx, db.UpdateLotDetailsParams{
+			ID: lotID,
+			LotKeyParameters: pqtype.NullRawMessage{
+				RawMessage: keyParamsJSON,
+				Valid:      true,
+			},
+		})
+		if err != nil {
+			logger.Errorf("Ошибка при обновлении ключевых параметров лота: %v", err)
+			return fmt.Errorf("не удалось обновить ключевые параметры лота: %w", err)
+		}
+
+		return s.recordKeyParameterChanges(ctx, qtx, lotID, before.LotKeyParameters, keyParamsJSON, changedBy)
+	})
+	if err != nil {
+		return db.Lot{}, err
+	}
+	return updated, nil
+}
+
+// recordKeyParameterChanges пишет в историю правок ключи, которые отличаются
+// до и после обновления лота.
+func (s *LotService) recordKeyParameterChanges(
+	ctx context.Context,
+	qtx *db.Queries,
+	lotID int64,
+	before pqtype.NullRawMessage,
+	after []byte,
+	changedBy int64,
+) error {
+	changes, err := history.DiffKeyParameters(before.RawMessage, after)
+	if err != nil {
+		return err
+	}
+	return history.Record(ctx, qtx, history.EntityLot, lotID, changedBy, changes)
+}
+
+// emitLotUpdated публикует lot.updated после коммита транзакции.
+func (s *LotService) emitLotUpdated(ctx context.Context, logger logging.Logger, lotID int64, keyParameters map[string]interface{}) {
+	events.Emit(ctx, s.publisher, logger, events.TypeLotUpdated, lotID, events.LotUpdatedData{
+		LotID:            lotID,
+		LotKeyParameters: keyParameters,
+	})
 }
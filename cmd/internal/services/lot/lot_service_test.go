@@ -12,9 +12,12 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
 	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/jsonschema"
 	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
 )
 
@@ -67,7 +70,16 @@ SCENARIO 1: UpdateLotKeyParameters
 SCENARIO 2: UpdateLotKeyParametersDirectly
 - GIVEN valid lotIDStr and keyParameters with existing lot
   WHEN UpdateLotKeyParametersDirectly is called
-  THEN lot key parameters are updated successfully
+  THEN the AI run is stored with model, prompt version and raw output,
+       lot key parameters are updated and the run ID is returned
+
+- GIVEN a DB error when storing the AI run
+  WHEN UpdateLotKeyParametersDirectly is called
+  THEN wrapped DB error is returned and the lot is not updated
+
+- GIVEN a key parameter schema for the tender category that the parameters violate
+  WHEN UpdateLotKeyParametersDirectly is called
+  THEN ValidationError with per-field details is returned and nothing is stored
 
 - GIVEN an invalid (non-numeric) lotIDStr
   WHEN UpdateLotKeyParametersDirectly is called
@@ -88,6 +100,16 @@ SCENARIO 2: UpdateLotKeyParametersDirectly
 - GIVEN keyParameters that cannot be serialized to JSON
   WHEN UpdateLotKeyParametersDirectly is called
   THEN serialization error is returned without calling ExecTx
+
+SCENARIO 3: PatchKeyParameters (manual edit)
+- GIVEN a lot with key parameters
+  WHEN PatchKeyParameters changes one key, adds one and drops one
+  THEN the lot is updated and each changed key is recorded in the field history
+       with old and new values and the editing user; unchanged keys are not recorded
+
+- GIVEN a non-existent lot (sql.ErrNoRows)
+  WHEN PatchKeyParameters is called
+  THEN NotFoundError is returned and nothing is updated
 */
 
 // setupTestService creates a LotService with mock store for unit testing.
@@ -100,8 +122,9 @@ func setupTestService(t *testing.T) (*LotService, *db.MockStore) {
 	logger := testutil.NewMockLogger()
 
 	service := &LotService{
-		store:  mockStore,
-		logger: logger,
+		store:     mockStore,
+		logger:    logger,
+		publisher: events.Noop{},
 	}
 
 	return service, mockStore
@@ -109,8 +132,11 @@ func setupTestService(t *testing.T) (*LotService, *db.MockStore) {
 
 // Helper: column names for SQL result sets
 var (
-	tenderColumns = []string{"id", "etp_id", "title", "category_id", "object_id", "executor_id", "data_prepared_on_date", "created_at", "updated_at"}
+	tenderColumns = []string{"id", "etp_id", "title", "category_id", "object_id", "executor_id", "data_prepared_on_date", "created_at", "updated_at", "deleted_at", "deleted_by", "organization_id"}
 	lotColumns    = []string{"id", "lot_key", "lot_title", "lot_key_parameters", "tender_id", "created_at", "updated_at"}
+
+	keyParameterSchemaColumns = []string{"tender_category_id", "schema", "created_at", "updated_at"}
+	aiAnalysisColumns         = []string{"id", "lot_id", "model_name", "prompt_version", "raw_output", "key_parameters", "created_at", "promoted_at"}
 )
 
 // Helper: create a mock DB + Queries for use inside ExecTx DoAndReturn.
@@ -128,6 +154,13 @@ func newMockQueries(t *testing.T) (sqlmock.Sqlmock, *db.Queries, func()) {
 	return mock, q, cleanup
 }
 
+// Helper: expectNoKeyParameterSchema expects the schema lookup for the lot's
+// tender category and answers that no schema is configured.
+func expectNoKeyParameterSchema(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT .+ FROM lot_key_parameter_schemas").
+		WillReturnError(sql.ErrNoRows)
+}
+
 // Helper: execTxDoAndReturn returns a DoAndReturn function that executes
 // the ExecTx callback with a sqlmock-backed *Queries.
 // The setupFn is called to set up sqlmock expectations before running the callback.
@@ -161,7 +194,7 @@ func TestUpdateLotKeyParameters_Success(t *testing.T) {
 			mock.ExpectQuery("SELECT .+ FROM tenders WHERE etp_id").
 				WithArgs("ETP-123").
 				WillReturnRows(sqlmock.NewRows(tenderColumns).
-					AddRow(int64(1), "ETP-123", "Test Tender", nil, int64(1), int64(1), nil, now, now))
+					AddRow(int64(1), "ETP-123", "Test Tender", nil, int64(1), int64(1), nil, now, now, nil, nil, int64(1)))
 
 			// GetLotByTenderAndKey returns lot
 			mock.ExpectQuery("SELECT .+ FROM lots WHERE tender_id").
@@ -169,6 +202,8 @@ func TestUpdateLotKeyParameters_Success(t *testing.T) {
 				WillReturnRows(sqlmock.NewRows(lotColumns).
 					AddRow(int64(10), "lot-1", "Test Lot", nil, int64(1), now, now))
 
+			expectNoKeyParameterSchema(mock)
+
 			// UpdateLotDetails returns updated lot
 			mock.ExpectQuery("UPDATE lots").
 				WillReturnRows(sqlmock.NewRows(lotColumns).
@@ -217,7 +252,7 @@ func TestUpdateLotKeyParameters_LotNotFound(t *testing.T) {
 			mock.ExpectQuery("SELECT .+ FROM tenders WHERE etp_id").
 				WithArgs("ETP-123").
 				WillReturnRows(sqlmock.NewRows(tenderColumns).
-					AddRow(int64(1), "ETP-123", "Test Tender", nil, int64(1), int64(1), nil, now, now))
+					AddRow(int64(1), "ETP-123", "Test Tender", nil, int64(1), int64(1), nil, now, now, nil, nil, int64(1)))
 
 			mock.ExpectQuery("SELECT .+ FROM lots WHERE tender_id").
 				WithArgs(int64(1), "missing-lot").
@@ -272,7 +307,7 @@ func TestUpdateLotKeyParameters_LotDBError(t *testing.T) {
 			mock.ExpectQuery("SELECT .+ FROM tenders WHERE etp_id").
 				WithArgs("ETP-123").
 				WillReturnRows(sqlmock.NewRows(tenderColumns).
-					AddRow(int64(1), "ETP-123", "Test Tender", nil, int64(1), int64(1), nil, now, now))
+					AddRow(int64(1), "ETP-123", "Test Tender", nil, int64(1), int64(1), nil, now, now, nil, nil, int64(1)))
 
 			mock.ExpectQuery("SELECT .+ FROM lots WHERE tender_id").
 				WithArgs(int64(1), "lot-1").
@@ -300,13 +335,15 @@ func TestUpdateLotKeyParameters_UpdateDBError(t *testing.T) {
 			mock.ExpectQuery("SELECT .+ FROM tenders WHERE etp_id").
 				WithArgs("ETP-123").
 				WillReturnRows(sqlmock.NewRows(tenderColumns).
-					AddRow(int64(1), "ETP-123", "Test Tender", nil, int64(1), int64(1), nil, now, now))
+					AddRow(int64(1), "ETP-123", "Test Tender", nil, int64(1), int64(1), nil, now, now, nil, nil, int64(1)))
 
 			mock.ExpectQuery("SELECT .+ FROM lots WHERE tender_id").
 				WithArgs(int64(1), "lot-1").
 				WillReturnRows(sqlmock.NewRows(lotColumns).
 					AddRow(int64(10), "lot-1", "Test Lot", nil, int64(1), now, now))
 
+			expectNoKeyParameterSchema(mock)
+
 			mock.ExpectQuery("UPDATE lots").
 				WillReturnError(dbErr)
 		}),
@@ -363,13 +400,15 @@ func TestUpdateLotKeyParameters_EmptyKeyParameters(t *testing.T) {
 			mock.ExpectQuery("SELECT .+ FROM tenders WHERE etp_id").
 				WithArgs("ETP-123").
 				WillReturnRows(sqlmock.NewRows(tenderColumns).
-					AddRow(int64(1), "ETP-123", "Test Tender", nil, int64(1), int64(1), nil, now, now))
+					AddRow(int64(1), "ETP-123", "Test Tender", nil, int64(1), int64(1), nil, now, now, nil, nil, int64(1)))
 
 			mock.ExpectQuery("SELECT .+ FROM lots WHERE tender_id").
 				WithArgs(int64(1), "lot-1").
 				WillReturnRows(sqlmock.NewRows(lotColumns).
 					AddRow(int64(10), "lot-1", "Test Lot", nil, int64(1), now, now))
 
+			expectNoKeyParameterSchema(mock)
+
 			mock.ExpectQuery("UPDATE lots").
 				WillReturnRows(sqlmock.NewRows(lotColumns).
 					AddRow(int64(10), "lot-1", "Test Lot", []byte(`{}`), int64(1), now, now))
@@ -401,6 +440,14 @@ func TestUpdateLotKeyParametersDirectly_Success(t *testing.T) {
 				WillReturnRows(sqlmock.NewRows(lotColumns).
 					AddRow(int64(42), "lot-key", "Test Lot", nil, int64(1), now, now))
 
+			expectNoKeyParameterSchema(mock)
+
+			// CreateAIAnalysisResult stores the run with its provenance
+			mock.ExpectQuery("INSERT INTO ai_analysis_results").
+				WithArgs(int64(42), "gpt-4o", "v3", "raw answer", sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows(aiAnalysisColumns).
+					AddRow(int64(7), int64(42), "gpt-4o", "v3", "raw answer", []byte(`{"param":"value"}`), now, now))
+
 			// UpdateLotDetails returns updated lot
 			mock.ExpectQuery("UPDATE lots").
 				WillReturnRows(sqlmock.NewRows(lotColumns).
@@ -409,10 +456,79 @@ func TestUpdateLotKeyParametersDirectly_Success(t *testing.T) {
 	)
 
 	// WHEN
-	err := service.UpdateLotKeyParametersDirectly(ctx, "42", map[string]interface{}{"param": "value"})
+	runID, err := service.UpdateLotKeyParametersDirectly(ctx, "42", api_models.SimpleLotAIResult{
+		LotKeyParameters: map[string]interface{}{"param": "value"},
+		ModelName:        "gpt-4o",
+		PromptVersion:    "v3",
+		RawOutput:        "raw answer",
+	})
 
 	// THEN
 	assert.NoError(t, err)
+	assert.Equal(t, int64(7), runID)
+}
+
+func TestUpdateLotKeyParametersDirectly_SchemaViolation(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	// GIVEN the lot's tender category has a schema the AI output violates
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT .+ FROM lots WHERE id").
+				WithArgs(int64(42)).
+				WillReturnRows(sqlmock.NewRows(lotColumns).
+					AddRow(int64(42), "lot-key", "Test Lot", nil, int64(1), now, now))
+
+			mock.ExpectQuery("SELECT .+ FROM lot_key_parameter_schemas").
+				WithArgs(int64(1)).
+				WillReturnRows(sqlmock.NewRows(keyParameterSchemaColumns).
+					AddRow(int64(3), []byte(`{"type":"object","additionalProperties":false,"properties":{"area":{"type":"number"}}}`), now, now))
+		}),
+	)
+
+	// WHEN
+	_, err := service.UpdateLotKeyParametersDirectly(ctx, "42", api_models.SimpleLotAIResult{
+		LotKeyParameters: map[string]interface{}{"area": "много", "color": "red"},
+	})
+
+	// THEN — the run is neither stored nor applied
+	require.Error(t, err)
+	var validationErr *apierrors.ValidationError
+	require.True(t, errors.As(err, &validationErr), "expected ValidationError, got: %T", err)
+	assert.Equal(t, []jsonschema.FieldError{
+		{Path: "/area", Message: "ожидается number, получено string"},
+		{Path: "/color", Message: "неизвестное поле"},
+	}, validationErr.Details)
+}
+
+func TestUpdateLotKeyParametersDirectly_SaveRunDBError(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	// GIVEN lot exists but the run cannot be stored — lot must not be updated
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT .+ FROM lots WHERE id").
+				WithArgs(int64(42)).
+				WillReturnRows(sqlmock.NewRows(lotColumns).
+					AddRow(int64(42), "lot-key", "Test Lot", nil, int64(1), now, now))
+
+			expectNoKeyParameterSchema(mock)
+
+			mock.ExpectQuery("INSERT INTO ai_analysis_results").
+				WillReturnError(errors.New("disk full"))
+		}),
+	)
+
+	// WHEN
+	_, err := service.UpdateLotKeyParametersDirectly(ctx, "42", api_models.SimpleLotAIResult{LotKeyParameters: map[string]interface{}{"k": "v"}})
+
+	// THEN
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "не удалось сохранить запуск AI-анализа")
 }
 
 func TestUpdateLotKeyParametersDirectly_InvalidLotID_NotANumber(t *testing.T) {
@@ -420,7 +536,7 @@ func TestUpdateLotKeyParametersDirectly_InvalidLotID_NotANumber(t *testing.T) {
 	ctx := context.Background()
 
 	// GIVEN non-numeric lot ID — no ExecTx expectation
-	err := service.UpdateLotKeyParametersDirectly(ctx, "abc", map[string]interface{}{"k": "v"})
+	_, err := service.UpdateLotKeyParametersDirectly(ctx, "abc", api_models.SimpleLotAIResult{LotKeyParameters: map[string]interface{}{"k": "v"}})
 
 	// THEN
 	require.Error(t, err)
@@ -434,7 +550,7 @@ func TestUpdateLotKeyParametersDirectly_InvalidLotID_EmptyString(t *testing.T) {
 	ctx := context.Background()
 
 	// GIVEN empty lot ID string
-	err := service.UpdateLotKeyParametersDirectly(ctx, "", map[string]interface{}{"k": "v"})
+	_, err := service.UpdateLotKeyParametersDirectly(ctx, "", api_models.SimpleLotAIResult{LotKeyParameters: map[string]interface{}{"k": "v"}})
 
 	// THEN
 	require.Error(t, err)
@@ -447,7 +563,7 @@ func TestUpdateLotKeyParametersDirectly_InvalidLotID_Float(t *testing.T) {
 	ctx := context.Background()
 
 	// GIVEN float-format lot ID
-	err := service.UpdateLotKeyParametersDirectly(ctx, "3.14", map[string]interface{}{"k": "v"})
+	_, err := service.UpdateLotKeyParametersDirectly(ctx, "3.14", api_models.SimpleLotAIResult{LotKeyParameters: map[string]interface{}{"k": "v"}})
 
 	// THEN
 	require.Error(t, err)
@@ -469,7 +585,7 @@ func TestUpdateLotKeyParametersDirectly_LotNotFound(t *testing.T) {
 	)
 
 	// WHEN
-	err := service.UpdateLotKeyParametersDirectly(ctx, "999", map[string]interface{}{"k": "v"})
+	_, err := service.UpdateLotKeyParametersDirectly(ctx, "999", api_models.SimpleLotAIResult{LotKeyParameters: map[string]interface{}{"k": "v"}})
 
 	// THEN
 	require.Error(t, err)
@@ -493,7 +609,7 @@ func TestUpdateLotKeyParametersDirectly_LotDBError(t *testing.T) {
 	)
 
 	// WHEN
-	err := service.UpdateLotKeyParametersDirectly(ctx, "42", map[string]interface{}{"k": "v"})
+	_, err := service.UpdateLotKeyParametersDirectly(ctx, "42", api_models.SimpleLotAIResult{LotKeyParameters: map[string]interface{}{"k": "v"}})
 
 	// THEN
 	require.Error(t, err)
@@ -516,13 +632,19 @@ func TestUpdateLotKeyParametersDirectly_UpdateDBError(t *testing.T) {
 				WillReturnRows(sqlmock.NewRows(lotColumns).
 					AddRow(int64(42), "lot-key", "Test Lot", nil, int64(1), now, now))
 
+			expectNoKeyParameterSchema(mock)
+
+			mock.ExpectQuery("INSERT INTO ai_analysis_results").
+				WillReturnRows(sqlmock.NewRows(aiAnalysisColumns).
+					AddRow(int64(7), int64(42), "", "", "", []byte(`{"k":"v"}`), now, now))
+
 			mock.ExpectQuery("UPDATE lots").
 				WillReturnError(dbErr)
 		}),
 	)
 
 	// WHEN
-	err := service.UpdateLotKeyParametersDirectly(ctx, "42", map[string]interface{}{"k": "v"})
+	_, err := service.UpdateLotKeyParametersDirectly(ctx, "42", api_models.SimpleLotAIResult{LotKeyParameters: map[string]interface{}{"k": "v"}})
 
 	// THEN
 	require.Error(t, err)
@@ -539,7 +661,7 @@ func TestUpdateLotKeyParametersDirectly_JSONSerializationError(t *testing.T) {
 	}
 
 	// WHEN
-	err := service.UpdateLotKeyParametersDirectly(ctx, "42", badParams)
+	_, err := service.UpdateLotKeyParametersDirectly(ctx, "42", api_models.SimpleLotAIResult{LotKeyParameters: badParams})
 
 	// THEN
 	require.Error(t, err)
@@ -554,7 +676,7 @@ func TestUpdateLotKeyParametersDirectly_ExecTxError(t *testing.T) {
 	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).Return(errors.New("tx begin failed"))
 
 	// WHEN
-	err := service.UpdateLotKeyParametersDirectly(ctx, "42", map[string]interface{}{"k": "v"})
+	_, err := service.UpdateLotKeyParametersDirectly(ctx, "42", api_models.SimpleLotAIResult{LotKeyParameters: map[string]interface{}{"k": "v"}})
 
 	// THEN
 	require.Error(t, err)
@@ -576,6 +698,12 @@ func TestUpdateLotKeyParametersDirectly_LargeNumericID(t *testing.T) {
 				WillReturnRows(sqlmock.NewRows(lotColumns).
 					AddRow(int64(9223372036854775807), "lot-max", "Max Lot", nil, int64(1), now, now))
 
+			expectNoKeyParameterSchema(mock)
+
+			mock.ExpectQuery("INSERT INTO ai_analysis_results").
+				WillReturnRows(sqlmock.NewRows(aiAnalysisColumns).
+					AddRow(int64(1), int64(9223372036854775807), "", "", "", []byte(`{"k":"v"}`), now, now))
+
 			mock.ExpectQuery("UPDATE lots").
 				WillReturnRows(sqlmock.NewRows(lotColumns).
 					AddRow(int64(9223372036854775807), "lot-max", "Max Lot", []byte(`{"k":"v"}`), int64(1), now, now))
@@ -583,7 +711,7 @@ func TestUpdateLotKeyParametersDirectly_LargeNumericID(t *testing.T) {
 	)
 
 	// WHEN
-	err := service.UpdateLotKeyParametersDirectly(ctx, largeID, map[string]interface{}{"k": "v"})
+	_, err := service.UpdateLotKeyParametersDirectly(ctx, largeID, api_models.SimpleLotAIResult{LotKeyParameters: map[string]interface{}{"k": "v"}})
 
 	// THEN
 	assert.NoError(t, err)
@@ -594,7 +722,7 @@ func TestUpdateLotKeyParametersDirectly_OverflowID(t *testing.T) {
 	ctx := context.Background()
 
 	// GIVEN lot ID that overflows int64
-	err := service.UpdateLotKeyParametersDirectly(ctx, "9223372036854775808", map[string]interface{}{"k": "v"})
+	_, err := service.UpdateLotKeyParametersDirectly(ctx, "9223372036854775808", api_models.SimpleLotAIResult{LotKeyParameters: map[string]interface{}{"k": "v"}})
 
 	// THEN — ParseInt fails
 	require.Error(t, err)
@@ -610,7 +738,7 @@ func TestUpdateLotKeyParametersDirectly_NegativeID(t *testing.T) {
 	// No ExecTx expectation: validation fires before transaction
 
 	// WHEN
-	err := service.UpdateLotKeyParametersDirectly(ctx, "-1", map[string]interface{}{"k": "v"})
+	_, err := service.UpdateLotKeyParametersDirectly(ctx, "-1", api_models.SimpleLotAIResult{LotKeyParameters: map[string]interface{}{"k": "v"}})
 
 	// THEN
 	require.Error(t, err)
@@ -626,7 +754,7 @@ func TestUpdateLotKeyParametersDirectly_ZeroID(t *testing.T) {
 	// GIVEN zero lot ID — rejected before DB access (IDs must be positive)
 
 	// WHEN
-	err := service.UpdateLotKeyParametersDirectly(ctx, "0", map[string]interface{}{"k": "v"})
+	_, err := service.UpdateLotKeyParametersDirectly(ctx, "0", api_models.SimpleLotAIResult{LotKeyParameters: map[string]interface{}{"k": "v"}})
 
 	// THEN
 	require.Error(t, err)
@@ -635,6 +763,62 @@ func TestUpdateLotKeyParametersDirectly_ZeroID(t *testing.T) {
 	assert.Contains(t, err.Error(), "положительным")
 }
 
+// =============================================================================
+// PatchKeyParameters TESTS
+// =============================================================================
+
+func TestPatchKeyParameters_RecordsChangedKeys(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	now := time.Now()
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT .+ FROM lots WHERE id .+ FOR UPDATE").
+				WithArgs(int64(5)).
+				WillReturnRows(sqlmock.NewRows(lotColumns).
+					AddRow(int64(5), "lot-key", "Test Lot", []byte(`{"area": 100, "floors": 9, "type": "жилой"}`), int64(1), now, now))
+			mock.ExpectQuery("UPDATE lots").
+				WillReturnRows(sqlmock.NewRows(lotColumns).
+					AddRow(int64(5), "lot-key", "Test Lot", []byte(`{"area":120,"floors":9,"height":30}`), int64(1), now, now))
+			mock.ExpectExec("INSERT INTO field_changes").
+				WithArgs("lot", int64(5), "lot_key_parameters.area", []byte(`100`), []byte(`120`), int64(42)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec("INSERT INTO field_changes").
+				WithArgs("lot", int64(5), "lot_key_parameters.height", []byte(`null`), []byte(`30`), int64(42)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec("INSERT INTO field_changes").
+				WithArgs("lot", int64(5), "lot_key_parameters.type", []byte(`"жилой"`), []byte(`null`), int64(42)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}),
+	)
+
+	lot, err := service.PatchKeyParameters(context.Background(), 5, map[string]interface{}{
+		"area":   float64(120),
+		"floors": float64(9),
+		"height": float64(30),
+	}, 42)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), lot.ID)
+}
+
+func TestPatchKeyParameters_LotNotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("SELECT .+ FROM lots WHERE id").
+				WithArgs(int64(5)).
+				WillReturnError(sql.ErrNoRows)
+		}),
+	)
+
+	_, err := service.PatchKeyParameters(context.Background(), 5, map[string]interface{}{"area": "100"}, 42)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr), "expected NotFoundError, got: %T", err)
+}
+
 // =============================================================================
 // NewLotService TESTS
 // =============================================================================
@@ -645,7 +829,7 @@ func TestNewLotService_CreatesInstance(t *testing.T) {
 	logger := testutil.NewMockLogger()
 
 	// WHEN
-	service := NewLotService(mockStore, logger)
+	service := NewLotService(mockStore, logger, events.Noop{})
 
 	// THEN
 	require.NotNil(t, service)
@@ -0,0 +1,266 @@
+package matching
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/entities"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/util"
+)
+
+const (
+	// ManualMatchedByPrefix — префикс matched_by позиции, сопоставленной
+	// аналитиком вручную (user:<id>). Воркеры RAG такую позицию не перезаписывают.
+	ManualMatchedByPrefix = "user:"
+
+	// MaxCorrectionCommentLength ограничивает комментарий к исправлению.
+	MaxCorrectionCommentLength = 2000
+
+	// DefaultCorrectionsLimit и MaxCorrectionsLimit — размер страницы ListCorrections.
+	DefaultCorrectionsLimit = 500
+	MaxCorrectionsLimit     = 5000
+)
+
+// CorrectPositionMatch обрабатывает PATCH /api/v1/positions/:id/catalog-match.
+//
+// В одной транзакции:
+//  1. блокирует позицию КП и проверяет выбранную позицию каталога (kind POSITION;
+//     выведенная из оборота или влитая подменяется своей заменой);
+//  2. записывает сопоставление с matched_by = user:<correctedBy> — воркеры больше
+//     не перезаписывают позицию;
+//  3. пишет в matching_cache запись source = 'manual' без TTL, которую не
+//     перезаписывает RAG: следующие импорты того же названия берут исправленную позицию;
+//  4. сохраняет исправление в matching_corrections для дообучения модели.
+//
+// Ключ кэша — запись, по которой позиция получила текущее сопоставление
+// (FindMatchingCacheForPosition); если её нет, хеш считается от названия позиции
+// так же, как импорт без леммы (entities.StandardJobTitle).
+func (s *MatchingService) CorrectPositionMatch(
+	ctx context.Context,
+	positionItemID int64,
+	req api_models.CorrectPositionMatchRequest,
+	correctedBy int64,
+) (*api_models.MatchingCorrectionResponse, error) {
+	if req.CatalogPositionID <= 0 {
+		return nil, apierrors.NewValidationError("matching.catalog_position_id_positive")
+	}
+	comment := ""
+	if req.Comment != nil {
+		comment = strings.TrimSpace(*req.Comment)
+	}
+	if len([]rune(comment)) > MaxCorrectionCommentLength {
+		return nil, apierrors.NewValidationError("matching.correction_comment_too_long", MaxCorrectionCommentLength)
+	}
+
+	var correction db.MatchingCorrection
+	err := s.store.ExecTx(ctx, func(qtx *db.Queries) error {
+		position, err := lockPositionForCorrection(ctx, qtx, positionItemID)
+		if err != nil {
+			return err
+		}
+
+		catalogPositionID, err := s.resolveCorrectionTarget(ctx, qtx, req.CatalogPositionID)
+		if err != nil {
+			return err
+		}
+		if position.CatalogPositionID.Valid && position.CatalogPositionID.Int64 == catalogPositionID {
+			return apierrors.NewValidationError("matching.correction_same_position", positionItemID, catalogPositionID)
+		}
+
+		normVersion, err := qtx.GetActiveNormVersion(ctx)
+		if err != nil {
+			return fmt.Errorf("ошибка чтения активной версии нормализации: %w", err)
+		}
+		hash, err := s.correctionCacheKey(ctx, qtx, position)
+		if err != nil {
+			return err
+		}
+
+		err = qtx.SetCatalogPositionIDManual(ctx, db.SetCatalogPositionIDManualParams{
+			CatalogPositionID: catalogPositionID,
+			MatchedBy:         ManualMatchedByPrefix + strconv.FormatInt(correctedBy, 10),
+			ID:                positionItemID,
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка обновления position_items: %w", err)
+		}
+
+		err = qtx.UpsertManualMatchingCache(ctx, db.UpsertManualMatchingCacheParams{
+			JobTitleHash:      hash,
+			NormVersion:       normVersion,
+			JobTitleText:      sql.NullString{String: position.JobTitleInProposal, Valid: true},
+			CatalogPositionID: catalogPositionID,
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка обновления matching_cache: %w", err)
+		}
+
+		correction, err = qtx.CreateMatchingCorrection(ctx, db.CreateMatchingCorrectionParams{
+			PositionItemID:            positionItemID,
+			JobTitleText:              position.JobTitleInProposal,
+			JobTitleHash:              hash,
+			NormVersion:               normVersion,
+			PreviousCatalogPositionID: position.CatalogPositionID,
+			PreviousMatchedBy:         position.MatchedBy,
+			CatalogPositionID:         catalogPositionID,
+			CorrectedBy:               sql.NullInt64{Int64: correctedBy, Valid: correctedBy > 0},
+			Comment:                   sql.NullString{String: comment, Valid: comment != ""},
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка сохранения исправления: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Errorf("Ошибка CorrectPositionMatch (позиция %d): %v", positionItemID, err)
+		return nil, err
+	}
+
+	s.logger.Infof("Пользователь %d исправил сопоставление позиции %d: %d -> %d (hash: %s)",
+		correctedBy, positionItemID, correction.PreviousCatalogPositionID.Int64, correction.CatalogPositionID, correction.JobTitleHash)
+	s.emitPositionMatched(ctx, api_models.MatchPositionRequest{
+		PositionItemID:    positionItemID,
+		CatalogPositionID: correction.CatalogPositionID,
+		Hash:              correction.JobTitleHash,
+	})
+	return toMatchingCorrectionResponse(correction), nil
+}
+
+// lockPositionForCorrection блокирует позицию КП до конца транзакции; заголовки
+// разделов с каталогом не сопоставляются, поэтому и исправлять в них нечего.
+func lockPositionForCorrection(ctx context.Context, qtx *db.Queries, positionItemID int64) (db.LockPositionItemForCorrectionRow, error) {
+	position, err := qtx.LockPositionItemForCorrection(ctx, positionItemID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return position, apierrors.NewNotFoundError("matching.position_not_found", positionItemID)
+		}
+		return position, fmt.Errorf("ошибка получения позиции %d: %w", positionItemID, err)
+	}
+	if position.IsChapter {
+		return position, apierrors.NewValidationError("matching.correction_chapter", positionItemID)
+	}
+	return position, nil
+}
+
+// resolveCorrectionTarget проверяет позицию каталога, выбранную аналитиком, и
+// возвращает ID, который нужно записать: позиция, ушедшая из оборота, подменяется
+// заменой или мастер-позицией слияния.
+func (s *MatchingService) resolveCorrectionTarget(ctx context.Context, qtx *db.Queries, catalogPositionID int64) (int64, error) {
+	target, err := qtx.GetCatalogPositionMatchTarget(ctx, catalogPositionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, apierrors.NewNotFoundError("common.catalog_position_not_found", catalogPositionID)
+		}
+		return 0, fmt.Errorf("ошибка получения позиции каталога %d: %w", catalogPositionID, err)
+	}
+	if target.Kind != "POSITION" {
+		return 0, apierrors.NewValidationError("matching.correction_target_not_position", catalogPositionID, target.Kind)
+	}
+	switch {
+	case target.ReplacedByID.Valid:
+		return target.ReplacedByID.Int64, nil
+	case target.MergedIntoID.Valid:
+		return target.MergedIntoID.Int64, nil
+	case target.Status == "deprecated" || target.Status == "archived":
+		return 0, apierrors.NewValidationError("matching.correction_target_retired", catalogPositionID, target.Status)
+	}
+	return target.ID, nil
+}
+
+// correctionCacheKey возвращает хеш записи matching_cache для позиции.
+func (s *MatchingService) correctionCacheKey(ctx context.Context, qtx *db.Queries, position db.LockPositionItemForCorrectionRow) (string, error) {
+	if position.CatalogPositionID.Valid {
+		cached, err := qtx.FindMatchingCacheForPosition(ctx, db.FindMatchingCacheForPositionParams{
+			JobTitleText:      position.JobTitleInProposal,
+			CatalogPositionID: position.CatalogPositionID.Int64,
+		})
+		switch {
+		case err == nil:
+			return cached.JobTitleHash, nil
+		case !errors.Is(err, sql.ErrNoRows):
+			return "", fmt.Errorf("ошибка чтения matching_cache: %w", err)
+		}
+	}
+
+	title := entities.StandardJobTitle(api_models.PositionItem{JobTitle: position.JobTitleInProposal})
+	if title == "" {
+		return "", apierrors.NewValidationError("matching.correction_empty_title", position.ID)
+	}
+	return util.GetSHA256Hash(title), nil
+}
+
+// ListCorrections обрабатывает GET /internal/worker/matching/corrections:
+// исправления после курсора afterID в порядке id (limit 0 — DefaultCorrectionsLimit).
+func (s *MatchingService) ListCorrections(ctx context.Context, afterID int64, limit int32) (*api_models.MatchingCorrectionsPage, error) {
+	if afterID < 0 {
+		return nil, apierrors.NewValidationError("matching.after_id_negative", afterID)
+	}
+	if limit == 0 {
+		limit = DefaultCorrectionsLimit
+	}
+	if limit < 0 {
+		return nil, apierrors.NewValidationError("request.limit_positive_got", limit)
+	}
+	if limit > MaxCorrectionsLimit {
+		limit = MaxCorrectionsLimit
+	}
+
+	rows, err := s.store.ListMatchingCorrections(ctx, db.ListMatchingCorrectionsParams{
+		AfterID:  afterID,
+		RowLimit: limit,
+	})
+	if err != nil {
+		s.logger.Errorf("Ошибка ListMatchingCorrections: %v", err)
+		return nil, fmt.Errorf("ошибка БД: %w", err)
+	}
+
+	page := &api_models.MatchingCorrectionsPage{Items: make([]api_models.MatchingCorrectionResponse, 0, len(rows))}
+	for _, row := range rows {
+		item := toMatchingCorrectionResponse(db.MatchingCorrection{
+			ID:                        row.ID,
+			PositionItemID:            row.PositionItemID,
+			JobTitleText:              row.JobTitleText,
+			JobTitleHash:              row.JobTitleHash,
+			NormVersion:               row.NormVersion,
+			PreviousCatalogPositionID: row.PreviousCatalogPositionID,
+			PreviousMatchedBy:         row.PreviousMatchedBy,
+			CatalogPositionID:         row.CatalogPositionID,
+			Comment:                   row.Comment,
+			CreatedAt:                 row.CreatedAt,
+		})
+		item.PreviousStandardJobTitle = row.PreviousStandardJobTitle.String
+		item.StandardJobTitle = row.StandardJobTitle
+		page.Items = append(page.Items, *item)
+	}
+	if n := len(page.Items); n > 0 {
+		next := page.Items[n-1].ID
+		page.NextAfterID = &next
+	}
+	return page, nil
+}
+
+func toMatchingCorrectionResponse(c db.MatchingCorrection) *api_models.MatchingCorrectionResponse {
+	resp := &api_models.MatchingCorrectionResponse{
+		ID:                c.ID,
+		PositionItemID:    c.PositionItemID,
+		JobTitleText:      c.JobTitleText,
+		JobTitleHash:      c.JobTitleHash,
+		NormVersion:       c.NormVersion,
+		PreviousMatchedBy: c.PreviousMatchedBy.String,
+		CatalogPositionID: c.CatalogPositionID,
+		Comment:           c.Comment.String,
+		CreatedAt:         c.CreatedAt,
+	}
+	if c.PreviousCatalogPositionID.Valid {
+		previous := c.PreviousCatalogPositionID.Int64
+		resp.PreviousCatalogPositionID = &previous
+	}
+	return resp
+}
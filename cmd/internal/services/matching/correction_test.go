@@ -0,0 +1,293 @@
+package matching
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/util"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR MANUAL MATCH CORRECTION (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Repeated mistakes — after an analyst fixes a wrong RAG match, the next import
+   of the same title must get the corrected catalog position from matching_cache
+2. Lost feedback — every correction must be recorded for retraining, with the
+   previous match, in the same transaction as the fix
+3. Worker overwrites — the corrected position must be pinned to the analyst so
+   RAG workers do not replace it again
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: CorrectPositionMatch
+- GIVEN a position matched by a worker through a matching_cache entry
+  WHEN an analyst corrects it
+  THEN position_items gets matched_by=user:<id>, the same cache key is rewritten
+       as a manual entry and the correction stores the previous match
+
+- GIVEN a position without a cache entry
+  THEN the cache key is the hash of the normalized raw title
+
+- GIVEN a deprecated catalog position with a replacement
+  THEN the replacement is written
+
+- GIVEN a missing position, a section header, a non-POSITION target or the
+  current catalog position
+  THEN NotFoundError / ValidationError and nothing is written
+
+SCENARIO 2: ListCorrections
+- GIVEN corrections after the cursor
+  THEN they are returned with both catalog titles and next_after_id
+- GIVEN a negative after_id
+  THEN ValidationError without DB call
+*/
+
+var (
+	lockedPositionColumns = []string{"id", "catalog_position_id", "matched_by", "job_title_in_proposal", "is_chapter"}
+	matchTargetColumns    = []string{"id", "kind", "status", "replaced_by_id", "merged_into_id"}
+	matchingCacheColumns  = []string{"job_title_hash", "norm_version", "job_title_text", "catalog_position_id", "created_at", "expires_at", "source"}
+	correctionColumns     = []string{
+		"id", "position_item_id", "job_title_text", "job_title_hash", "norm_version",
+		"previous_catalog_position_id", "previous_matched_by", "catalog_position_id",
+		"corrected_by", "comment", "created_at",
+	}
+)
+
+func TestCorrectPositionMatch_RewritesWorkerCacheEntry(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	comment := "  не тот вид свай  "
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("FOR UPDATE").
+				WithArgs(int64(100)).
+				WillReturnRows(sqlmock.NewRows(lockedPositionColumns).
+					AddRow(int64(100), sql.NullInt64{Int64: 77, Valid: true}, sql.NullString{String: "worker-a", Valid: true}, "Устройство свай", false))
+			mock.ExpectQuery("FROM catalog_positions").
+				WithArgs(int64(42)).
+				WillReturnRows(sqlmock.NewRows(matchTargetColumns).
+					AddRow(int64(42), "POSITION", "active", sql.NullInt64{}, sql.NullInt64{}))
+			mock.ExpectQuery("SELECT COALESCE").
+				WillReturnRows(sqlmock.NewRows([]string{"norm_version"}).AddRow(int16(2)))
+			// Запись кэша, по которой воркер сопоставил позицию
+			mock.ExpectQuery("FROM matching_cache").
+				WithArgs("Устройство свай", int64(77)).
+				WillReturnRows(sqlmock.NewRows(matchingCacheColumns).
+					AddRow("lemma-hash", int16(2), sql.NullString{String: "Устройство свай", Valid: true}, int64(77), now, sql.NullTime{Time: now, Valid: true}, "rag"))
+
+			mock.ExpectExec("UPDATE position_items").
+				WithArgs(int64(42), "user:7", int64(100)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec("INSERT INTO matching_cache").
+				WithArgs("lemma-hash", int16(2), sql.NullString{String: "Устройство свай", Valid: true}, int64(42)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectQuery("INSERT INTO matching_corrections").
+				WithArgs(
+					int64(100), "Устройство свай", "lemma-hash", int16(2),
+					sql.NullInt64{Int64: 77, Valid: true}, sql.NullString{String: "worker-a", Valid: true},
+					int64(42), sql.NullInt64{Int64: 7, Valid: true}, sql.NullString{String: "не тот вид свай", Valid: true},
+				).
+				WillReturnRows(sqlmock.NewRows(correctionColumns).
+					AddRow(int64(1), int64(100), "Устройство свай", "lemma-hash", int16(2),
+						sql.NullInt64{Int64: 77, Valid: true}, sql.NullString{String: "worker-a", Valid: true}, int64(42),
+						sql.NullInt64{Int64: 7, Valid: true}, sql.NullString{String: "не тот вид свай", Valid: true}, now))
+		}),
+	)
+
+	resp, err := service.CorrectPositionMatch(context.Background(), 100,
+		api_models.CorrectPositionMatchRequest{CatalogPositionID: 42, Comment: &comment}, 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), resp.ID)
+	assert.Equal(t, int64(42), resp.CatalogPositionID)
+	require.NotNil(t, resp.PreviousCatalogPositionID)
+	assert.Equal(t, int64(77), *resp.PreviousCatalogPositionID)
+	assert.Equal(t, "worker-a", resp.PreviousMatchedBy)
+	assert.Equal(t, "не тот вид свай", resp.Comment)
+}
+
+func TestCorrectPositionMatch_UnmatchedPosition_HashesRawTitle(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	now := time.Now()
+	hash := util.GetSHA256Hash("устройство свай")
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("FOR UPDATE").
+				WithArgs(int64(100)).
+				WillReturnRows(sqlmock.NewRows(lockedPositionColumns).
+					AddRow(int64(100), sql.NullInt64{}, sql.NullString{}, "  Устройство   СВАЙ ", false))
+			// Позиция выведена из оборота — записывается замена
+			mock.ExpectQuery("FROM catalog_positions").
+				WithArgs(int64(42)).
+				WillReturnRows(sqlmock.NewRows(matchTargetColumns).
+					AddRow(int64(42), "POSITION", "deprecated", sql.NullInt64{Int64: 43, Valid: true}, sql.NullInt64{}))
+			mock.ExpectQuery("SELECT COALESCE").
+				WillReturnRows(sqlmock.NewRows([]string{"norm_version"}).AddRow(int16(1)))
+			// Позиция не сопоставлена — кэш не ищется
+			mock.ExpectExec("UPDATE position_items").
+				WithArgs(int64(43), "user:7", int64(100)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec("INSERT INTO matching_cache").
+				WithArgs(hash, int16(1), sqlmock.AnyArg(), int64(43)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectQuery("INSERT INTO matching_corrections").
+				WillReturnRows(sqlmock.NewRows(correctionColumns).
+					AddRow(int64(2), int64(100), "  Устройство   СВАЙ ", hash, int16(1),
+						sql.NullInt64{}, sql.NullString{}, int64(43), sql.NullInt64{Int64: 7, Valid: true}, sql.NullString{}, now))
+		}),
+	)
+
+	resp, err := service.CorrectPositionMatch(context.Background(), 100,
+		api_models.CorrectPositionMatchRequest{CatalogPositionID: 42}, 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(43), resp.CatalogPositionID)
+	assert.Equal(t, hash, resp.JobTitleHash)
+	assert.Nil(t, resp.PreviousCatalogPositionID)
+}
+
+func TestCorrectPositionMatch_Rejected(t *testing.T) {
+	cases := []struct {
+		name     string
+		position []driver.Value
+		target   []driver.Value // nil — позиция каталога не читается
+		wantErr  interface{}
+	}{
+		{
+			name:    "position not found",
+			wantErr: &apierrors.NotFoundError{},
+		},
+		{
+			name:     "section header",
+			position: []driver.Value{int64(100), sql.NullInt64{}, sql.NullString{}, "Раздел 1", true},
+			wantErr:  &apierrors.ValidationError{},
+		},
+		{
+			name:     "target is a header",
+			position: []driver.Value{int64(100), sql.NullInt64{}, sql.NullString{}, "Устройство свай", false},
+			target:   []driver.Value{int64(42), "HEADER", "active", sql.NullInt64{}, sql.NullInt64{}},
+			wantErr:  &apierrors.ValidationError{},
+		},
+		{
+			name:     "archived target",
+			position: []driver.Value{int64(100), sql.NullInt64{}, sql.NullString{}, "Устройство свай", false},
+			target:   []driver.Value{int64(42), "POSITION", "archived", sql.NullInt64{}, sql.NullInt64{}},
+			wantErr:  &apierrors.ValidationError{},
+		},
+		{
+			name:     "already matched to target",
+			position: []driver.Value{int64(100), sql.NullInt64{Int64: 42, Valid: true}, sql.NullString{String: "worker-a", Valid: true}, "Устройство свай", false},
+			target:   []driver.Value{int64(42), "POSITION", "active", sql.NullInt64{}, sql.NullInt64{}},
+			wantErr:  &apierrors.ValidationError{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, mockStore := setupTestService(t)
+			mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+				execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+					lock := mock.ExpectQuery("FOR UPDATE").WithArgs(int64(100))
+					if tc.position == nil {
+						lock.WillReturnError(sql.ErrNoRows)
+						return
+					}
+					lock.WillReturnRows(sqlmock.NewRows(lockedPositionColumns).AddRow(tc.position...))
+					if tc.target != nil {
+						mock.ExpectQuery("FROM catalog_positions").
+							WithArgs(int64(42)).
+							WillReturnRows(sqlmock.NewRows(matchTargetColumns).AddRow(tc.target...))
+					}
+					// Ни position_items, ни matching_cache не меняются
+				}),
+			)
+
+			_, err := service.CorrectPositionMatch(context.Background(), 100,
+				api_models.CorrectPositionMatchRequest{CatalogPositionID: 42}, 7)
+
+			require.Error(t, err)
+			switch tc.wantErr.(type) {
+			case *apierrors.NotFoundError:
+				var notFoundErr *apierrors.NotFoundError
+				assert.True(t, errors.As(err, &notFoundErr))
+			case *apierrors.ValidationError:
+				var validationErr *apierrors.ValidationError
+				assert.True(t, errors.As(err, &validationErr), err.Error())
+			}
+		})
+	}
+}
+
+func TestCorrectPositionMatch_InvalidRequest_NoDBCall(t *testing.T) {
+	service, _ := setupTestService(t)
+	long := strings.Repeat("я", MaxCorrectionCommentLength+1)
+
+	for _, req := range []api_models.CorrectPositionMatchRequest{
+		{CatalogPositionID: 0},
+		{CatalogPositionID: 42, Comment: &long},
+	} {
+		_, err := service.CorrectPositionMatch(context.Background(), 100, req, 7)
+		var validationErr *apierrors.ValidationError
+		assert.True(t, errors.As(err, &validationErr))
+	}
+}
+
+func TestListCorrections_ReturnsPageWithCursor(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	mockStore.EXPECT().
+		ListMatchingCorrections(ctx, db.ListMatchingCorrectionsParams{AfterID: 10, RowLimit: DefaultCorrectionsLimit}).
+		Return([]db.ListMatchingCorrectionsRow{
+			{
+				ID:                        11,
+				PositionItemID:            100,
+				JobTitleText:              "Устройство свай",
+				JobTitleHash:              "lemma-hash",
+				NormVersion:               2,
+				PreviousCatalogPositionID: sql.NullInt64{Int64: 77, Valid: true},
+				PreviousStandardJobTitle:  sql.NullString{String: "устройство шпунт", Valid: true},
+				PreviousMatchedBy:         sql.NullString{String: "worker-a", Valid: true},
+				CatalogPositionID:         42,
+				StandardJobTitle:          "устройство свая",
+				CreatedAt:                 now,
+			},
+		}, nil)
+
+	page, err := service.ListCorrections(ctx, 10, 0)
+
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "устройство шпунт", page.Items[0].PreviousStandardJobTitle)
+	assert.Equal(t, "устройство свая", page.Items[0].StandardJobTitle)
+	require.NotNil(t, page.NextAfterID)
+	assert.Equal(t, int64(11), *page.NextAfterID)
+}
+
+func TestListCorrections_NegativeAfterID(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.ListCorrections(context.Background(), -1, 0)
+
+	var validationErr *apierrors.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+}
@@ -0,0 +1,180 @@
+package matching
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+const (
+	// MaxMatchBatchSize — максимальное число записей в одном вызове MatchPositionsBatch.
+	MaxMatchBatchSize = 500
+	// matchBatchChunkSize — число записей, применяемых в одной транзакции.
+	matchBatchChunkSize = 50
+)
+
+// Статусы записи пакетного сопоставления.
+const (
+	BatchStatusMatched  = "matched"
+	BatchStatusConflict = "conflict"
+	BatchStatusNotFound = "not_found"
+	BatchStatusInvalid  = "invalid"
+	BatchStatusError    = "error"
+)
+
+// MatchPositionsBatch обрабатывает POST /internal/worker/positions/match-batch.
+//
+// Записи применяются порциями по matchBatchChunkSize в отдельных транзакциях с
+// той же логикой, что и MatchPosition. Конфликт закрепления или отсутствующая
+// позиция не прерывают порцию — они попадают в результат записи. Если порция
+// откатилась из-за ошибки БД (например, несуществующий catalog_position_id),
+// её записи применяются по одной, чтобы ошибка затронула только виновную запись.
+//
+// Ошибка возвращается только для некорректного пакета целиком (пустой или
+// больше MaxMatchBatchSize).
+func (s *MatchingService) MatchPositionsBatch(
+	ctx context.Context,
+	req api_models.MatchPositionsBatchRequest,
+) (*api_models.MatchPositionsBatchResponse, error) {
+	logger := s.logger.WithField("method", "MatchPositionsBatch")
+
+	if len(req.Matches) == 0 {
+		return nil, apierrors.NewValidationError("matching.matches_empty")
+	}
+	if len(req.Matches) > MaxMatchBatchSize {
+		return nil, apierrors.NewValidationError("matching.matches_too_many", len(req.Matches), MaxMatchBatchSize)
+	}
+
+	matches := make([]api_models.MatchPositionRequest, len(req.Matches))
+	results := make([]api_models.MatchPositionBatchItemResult, len(req.Matches))
+	workerIDs := make([]string, len(req.Matches))
+	valid := make([]int, 0, len(req.Matches))
+	for i, match := range req.Matches {
+		results[i].PositionItemID = match.PositionItemID
+		if strings.TrimSpace(match.WorkerID) == "" {
+			match.WorkerID = req.WorkerID
+		}
+		matches[i] = match
+		workerID, err := validateMatchRequest(match)
+		if err == nil {
+			err = validateMatchRecord(match)
+		}
+		if err != nil {
+			results[i].Status = BatchStatusInvalid
+			results[i].Error = err.Error()
+			continue
+		}
+		workerIDs[i] = workerID
+		valid = append(valid, i)
+	}
+
+	for start := 0; start < len(valid); start += matchBatchChunkSize {
+		chunk := valid[start:min(start+matchBatchChunkSize, len(valid))]
+
+		chunkResults, err := s.matchChunk(ctx, matches, workerIDs, chunk)
+		if err != nil {
+			logger.Warnf("Порция из %d записей откатилась, применяем по одной: %v", len(chunk), err)
+			chunkResults = make([]api_models.MatchPositionBatchItemResult, len(chunk))
+			for j, idx := range chunk {
+				var activeNormVersion int16
+				itemErr := s.store.ExecTx(ctx, func(qtx *db.Queries) error {
+					return s.applyMatch(ctx, qtx, matches[idx], workerIDs[idx], &activeNormVersion)
+				})
+				chunkResults[j] = batchItemResult(matches[idx].PositionItemID, itemErr)
+			}
+		}
+
+		for j, idx := range chunk {
+			results[idx] = chunkResults[j]
+			if results[idx].Status == BatchStatusMatched {
+				s.emitPositionMatched(ctx, matches[idx])
+			}
+		}
+	}
+
+	resp := &api_models.MatchPositionsBatchResponse{Results: results}
+	for _, r := range results {
+		if r.Status == BatchStatusMatched {
+			resp.Matched++
+		} else {
+			resp.Failed++
+		}
+	}
+
+	logger.Infof("Пакетное сопоставление: записей %d, сопоставлено %d, не применено %d",
+		len(results), resp.Matched, resp.Failed)
+	return resp, nil
+}
+
+// matchChunk применяет записи с индексами chunk в одной транзакции.
+// Конфликт и отсутствие позиции фиксируются в результате записи; любая другая
+// ошибка откатывает всю порцию и возвращается.
+func (s *MatchingService) matchChunk(
+	ctx context.Context,
+	matches []api_models.MatchPositionRequest,
+	workerIDs []string,
+	chunk []int,
+) ([]api_models.MatchPositionBatchItemResult, error) {
+	var results []api_models.MatchPositionBatchItemResult
+	err := s.store.ExecTx(ctx, func(qtx *db.Queries) error {
+		results = make([]api_models.MatchPositionBatchItemResult, 0, len(chunk))
+		var activeNormVersion int16
+		for _, idx := range chunk {
+			err := s.applyMatch(ctx, qtx, matches[idx], workerIDs[idx], &activeNormVersion)
+			var conflictErr *apierrors.ConflictError
+			var notFoundErr *apierrors.NotFoundError
+			if err != nil && !errors.As(err, &conflictErr) && !errors.As(err, &notFoundErr) {
+				return err
+			}
+			results = append(results, batchItemResult(matches[idx].PositionItemID, err))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// validateMatchRecord проверяет обязательные поля записи пакета
+// (в одиночном запросе их проверяет binding:"required").
+func validateMatchRecord(match api_models.MatchPositionRequest) error {
+	if match.PositionItemID <= 0 {
+		return apierrors.NewValidationError("matching.position_item_id_positive")
+	}
+	if match.CatalogPositionID <= 0 {
+		return apierrors.NewValidationError("matching.catalog_position_id_positive")
+	}
+	if strings.TrimSpace(match.Hash) == "" {
+		return apierrors.NewValidationError("matching.hash_empty")
+	}
+	return nil
+}
+
+func batchItemResult(positionItemID int64, err error) api_models.MatchPositionBatchItemResult {
+	result := api_models.MatchPositionBatchItemResult{PositionItemID: positionItemID, Status: BatchStatusMatched}
+	if err == nil {
+		return result
+	}
+
+	var conflictErr *apierrors.ConflictError
+	var notFoundErr *apierrors.NotFoundError
+	var validationErr *apierrors.ValidationError
+	switch {
+	case errors.As(err, &conflictErr):
+		result.Status = BatchStatusConflict
+		result.Conflict = conflictErr.Conflicts
+	case errors.As(err, &notFoundErr):
+		result.Status = BatchStatusNotFound
+	case errors.As(err, &validationErr):
+		result.Status = BatchStatusInvalid
+	default:
+		result.Status = BatchStatusError
+	}
+	result.Error = err.Error()
+	return result
+}
@@ -0,0 +1,163 @@
+package matching
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR BATCH MATCHING (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Round-trips — a large tender is matched in a few calls instead of thousands
+2. Partial failure — one bad record must not discard the rest of the batch
+3. Traceability — the worker gets a per-record status in request order
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Batch validation
+- GIVEN an empty batch or more than MaxMatchBatchSize records
+  THEN ValidationError without DB calls
+
+SCENARIO 2: Mixed batch
+- GIVEN a valid record, a record without hash and a record claimed by another worker
+  WHEN MatchPositionsBatch is called
+  THEN one transaction applies the valid record; results are matched / invalid / conflict
+
+SCENARIO 3: Chunk rollback
+- GIVEN a record failing with a DB error inside the chunk transaction
+  WHEN MatchPositionsBatch is called
+  THEN the chunk is retried record by record and only that record gets status error
+*/
+
+func TestMatchPositionsBatch_Validation(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.MatchPositionsBatch(context.Background(), api_models.MatchPositionsBatchRequest{WorkerID: "w"})
+	var validationErr *apierrors.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+
+	tooMany := api_models.MatchPositionsBatchRequest{
+		WorkerID: "w",
+		Matches:  make([]api_models.MatchPositionRequest, MaxMatchBatchSize+1),
+	}
+	_, err = service.MatchPositionsBatch(context.Background(), tooMany)
+	assert.True(t, errors.As(err, &validationErr))
+}
+
+func TestMatchPositionsBatch_MixedResults(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	claimed := positionItemRow(3, "Кладка")
+	claimed[2] = sql.NullInt64{Int64: 77, Valid: true}
+	claimed[len(claimed)-4] = sql.NullString{String: "worker-b", Valid: true}
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			// Запись 1 — сопоставлена, активная версия читается один раз
+			mock.ExpectExec("UPDATE position_items").
+				WithArgs(int64(42), "worker-a", int64(1)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectQuery("SELECT .+ FROM position_items WHERE id").
+				WithArgs(int64(1)).
+				WillReturnRows(sqlmock.NewRows(positionItemColumns).AddRow(positionItemRow(1, "Монтаж")...))
+			mock.ExpectQuery("SELECT COALESCE").
+				WillReturnRows(sqlmock.NewRows([]string{"norm_version"}).AddRow(int16(2)))
+			mock.ExpectExec("INSERT INTO matching_cache").
+				WithArgs("hash-1", int16(2), sqlmock.AnyArg(), int64(42), sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			// Запись 3 — закреплена другим воркером
+			mock.ExpectExec("UPDATE position_items").
+				WithArgs(int64(43), "worker-a", int64(3)).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery("SELECT .+ FROM position_items WHERE id").
+				WithArgs(int64(3)).
+				WillReturnRows(sqlmock.NewRows(positionItemColumns).AddRow(claimed...))
+		}),
+	)
+
+	resp, err := service.MatchPositionsBatch(context.Background(), api_models.MatchPositionsBatchRequest{
+		WorkerID: "worker-a",
+		Matches: []api_models.MatchPositionRequest{
+			{PositionItemID: 1, CatalogPositionID: 42, Hash: "hash-1"},
+			{PositionItemID: 2, CatalogPositionID: 42},
+			{PositionItemID: 3, CatalogPositionID: 43, Hash: "hash-3"},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, resp.Matched)
+	assert.Equal(t, 2, resp.Failed)
+	require.Len(t, resp.Results, 3)
+	assert.Equal(t, BatchStatusMatched, resp.Results[0].Status)
+	assert.Equal(t, BatchStatusInvalid, resp.Results[1].Status)
+	assert.Equal(t, int64(2), resp.Results[1].PositionItemID)
+	assert.Equal(t, BatchStatusConflict, resp.Results[2].Status)
+	conflict, ok := resp.Results[2].Conflict.(MatchConflict)
+	require.True(t, ok)
+	assert.Equal(t, "worker-b", conflict.MatchedBy)
+}
+
+func TestMatchPositionsBatch_ChunkRollbackFallsBackToSingleRecords(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	expectMatched := func(mock sqlmock.Sqlmock, positionID int64) {
+		mock.ExpectExec("UPDATE position_items").
+			WithArgs(int64(42), "worker-a", positionID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT .+ FROM position_items WHERE id").
+			WithArgs(positionID).
+			WillReturnRows(sqlmock.NewRows(positionItemColumns).AddRow(positionItemRow(positionID, "Монтаж")...))
+		mock.ExpectExec("INSERT INTO matching_cache").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	fkErr := errors.New("violates foreign key constraint")
+
+	gomock.InOrder(
+		// Порция целиком: запись 2 падает — транзакция откатывается
+		mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+			execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+				expectMatched(mock, 1)
+				mock.ExpectExec("UPDATE position_items").
+					WithArgs(int64(999), "worker-a", int64(2)).
+					WillReturnError(fkErr)
+			}),
+		),
+		// Повтор по одной записи
+		mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+			execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) { expectMatched(mock, 1) }),
+		),
+		mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+			execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE position_items").WillReturnError(fkErr)
+			}),
+		),
+	)
+
+	resp, err := service.MatchPositionsBatch(context.Background(), api_models.MatchPositionsBatchRequest{
+		WorkerID: "worker-a",
+		Matches: []api_models.MatchPositionRequest{
+			{PositionItemID: 1, CatalogPositionID: 42, Hash: "hash-1", NormVersion: 2},
+			{PositionItemID: 2, CatalogPositionID: 999, Hash: "hash-2", NormVersion: 2},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, resp.Matched)
+	assert.Equal(t, BatchStatusMatched, resp.Results[0].Status)
+	assert.Equal(t, BatchStatusError, resp.Results[1].Status)
+	assert.Contains(t, resp.Results[1].Error, "foreign key")
+}
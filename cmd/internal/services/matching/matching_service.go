@@ -3,26 +3,32 @@ package matching
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
 	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
 	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
 )
 
 // MatchingService управляет операциями матчинга позиций
 type MatchingService struct {
-	store  db.Store
-	logger logging.Logger
+	store     db.Store
+	logger    logging.Logger
+	publisher events.Publisher
 }
 
 // NewMatchingService создает новый экземпляр MatchingService
-func NewMatchingService(store db.Store, logger logging.Logger) *MatchingService {
+func NewMatchingService(store db.Store, logger logging.Logger, publisher events.Publisher) *MatchingService {
 	return &MatchingService{
-		store:  store,
-		logger: logger,
+		store:     store,
+		logger:    logger,
+		publisher: publisher,
 	}
 }
 
@@ -31,10 +37,26 @@ const (
 	// которое можно запросить за один вызов GetUnmatchedPositions.
 	// Это ограничение предотвращает чрезмерную нагрузку на БД и память.
 	MaxUnmatchedPositionsLimit = 1000
+
+	// DefaultClaimLimit и DefaultClaimLease — значения по умолчанию для ClaimUnmatchedPositions.
+	DefaultClaimLimit = 100
+	DefaultClaimLease = 5 * time.Minute
+	// MaxClaimLease ограничивает аренду, чтобы упавший воркер не держал позиции долго.
+	MaxClaimLease = time.Hour
 )
 
-// GetUnmatchedPositions (Версия 3: БЕЗ lot_title)
-// Возвращает позиции, для которых еще нет соответствия в catalog_positions.
+// UnmatchedPositionsFilter — параметры выборки очереди несопоставленных позиций.
+type UnmatchedPositionsFilter struct {
+	Limit        int32
+	AfterID      int64      // Курсор: позиции с id > AfterID
+	TenderID     *int64     // Только позиции тендера
+	CreatedAfter *time.Time // Только позиции, созданные не раньше
+}
+
+// GetUnmatchedPositions (Версия 4: курсор и фильтры)
+// Возвращает позиции, для которых еще нет соответствия в catalog_positions,
+// в порядке id после filter.AfterID. Позиции, арендованные воркерами
+// (ClaimUnmatchedPositions), не возвращаются до истечения аренды.
 // `rich_context_string` теперь состоит из:
 //   - `job_title_normalized` (то есть лемма самой позиции).
 //   - "Хлебных крошек" (breadcrumbs) — иерархии заголовков (HEADER и LOT_HEADER),
@@ -44,13 +66,114 @@ const (
 // опираясь на контекст вложенности.
 func (s *MatchingService) GetUnmatchedPositions(
 	ctx context.Context,
-	limit int32,
+	filter UnmatchedPositionsFilter,
 ) ([]api_models.UnmatchedPositionResponse, error) {
 
+	limit, err := s.normalizeUnmatchedLimit(filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	if filter.AfterID < 0 {
+		return nil, apierrors.NewValidationError("matching.after_id_negative", filter.AfterID)
+	}
+
+	// 1. Вызываем рекурсивный SQLC-запрос
+	// (sqlc сгенерирует row.FullParentPath, но НЕ row.LotTitle)
+	dbRows, err := s.store.GetUnmatchedPositions(ctx, unmatchedPositionsParams(filter, limit, sql.NullString{}))
+	if err != nil {
+		s.logger.Errorf("Ошибка GetUnmatchedPositions: %v", err)
+		return nil, fmt.Errorf("ошибка БД: %w", err)
+	}
+
+	response := toUnmatchedPositionResponses(dbRows)
+	s.logger.Infof("Найдено %d не сопоставленных позиций для RAG-воркера", len(response))
+	return response, nil
+}
+
+// ClaimUnmatchedPositions обрабатывает POST /internal/worker/positions/claim.
+//
+// В одной транзакции арендует за воркером следующую порцию несопоставленных
+// позиций (SKIP LOCKED — параллельные воркеры получают непересекающиеся порции)
+// и возвращает их с контекстом. Аренда снимается при сопоставлении позиции
+// или истекает через lease; повторный claim тем же воркером продлевает её.
+func (s *MatchingService) ClaimUnmatchedPositions(
+	ctx context.Context,
+	workerID string,
+	filter UnmatchedPositionsFilter,
+	lease time.Duration,
+) (*api_models.ClaimUnmatchedPositionsResponse, error) {
+	workerID = strings.TrimSpace(workerID)
+	if workerID == "" {
+		return nil, apierrors.NewValidationError("matching.worker_id_empty")
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultClaimLimit
+	}
+	limit, err := s.normalizeUnmatchedLimit(filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	if filter.AfterID < 0 {
+		return nil, apierrors.NewValidationError("matching.after_id_negative", filter.AfterID)
+	}
+	if lease == 0 {
+		lease = DefaultClaimLease
+	}
+	if lease < time.Second || lease > MaxClaimLease {
+		return nil, apierrors.NewValidationError("matching.lease_seconds_range", int(MaxClaimLease.Seconds()))
+	}
+
+	resp := &api_models.ClaimUnmatchedPositionsResponse{Positions: []api_models.UnmatchedPositionResponse{}}
+	err = s.store.ExecTx(ctx, func(qtx *db.Queries) error {
+		claimed, err := qtx.ClaimUnmatchedPositions(ctx, db.ClaimUnmatchedPositionsParams{
+			AfterID:      filter.AfterID,
+			TenderID:     nullInt64(filter.TenderID),
+			CreatedAfter: nullTime(filter.CreatedAfter),
+			WorkerID:     workerID,
+			RowLimit:     limit,
+			LeaseSeconds: int32(lease.Seconds()),
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка ClaimUnmatchedPositions: %w", err)
+		}
+		if len(claimed) == 0 {
+			return nil
+		}
+		if claimed[0].ClaimedUntil.Valid {
+			claimedUntil := claimed[0].ClaimedUntil.Time
+			resp.ClaimedUntil = &claimedUntil
+		}
+
+		// Те же условия и курсор: первые len(claimed) доступных воркеру позиций —
+		// ровно арендованные им только что
+		rows, err := qtx.GetUnmatchedPositions(ctx, unmatchedPositionsParams(
+			filter, int32(len(claimed)), sql.NullString{String: workerID, Valid: true},
+		))
+		if err != nil {
+			return fmt.Errorf("ошибка GetUnmatchedPositions: %w", err)
+		}
+		resp.Positions = toUnmatchedPositionResponses(rows)
+		return nil
+	})
+	if err != nil {
+		s.logger.Errorf("Ошибка ClaimUnmatchedPositions (worker=%s): %v", workerID, err)
+		return nil, err
+	}
+
+	if n := len(resp.Positions); n > 0 {
+		next := resp.Positions[n-1].PositionItemID
+		resp.NextAfterID = &next
+	}
+	s.logger.Infof("Воркер %s арендовал %d позиций", workerID, len(resp.Positions))
+	return resp, nil
+}
+
+// normalizeUnmatchedLimit валидирует limit и ограничивает его MaxUnmatchedPositionsLimit.
+func (s *MatchingService) normalizeUnmatchedLimit(limit int32) (int32, error) {
 	// Валидация параметра limit
 	if limit <= 0 {
 		s.logger.Warnf("Получен некорректный limit: %d (должен быть > 0)", limit)
-		return nil, apierrors.NewValidationError("параметр limit должен быть положительным числом, получено: %d", limit)
+		return 0, apierrors.NewValidationError("request.limit_positive_got", limit)
 	}
 
 	// Ограничиваем максимальное значение
@@ -59,15 +182,34 @@ func (s *MatchingService) GetUnmatchedPositions(
 			limit, MaxUnmatchedPositionsLimit)
 		limit = MaxUnmatchedPositionsLimit
 	}
+	return limit, nil
+}
 
-	// 1. Вызываем наш НОВЫЙ рекурсивный SQLC-запрос
-	// (sqlc сгенерирует row.FullParentPath, но НЕ row.LotTitle)
-	dbRows, err := s.store.GetUnmatchedPositions(ctx, limit)
-	if err != nil {
-		s.logger.Errorf("Ошибка GetUnmatchedPositions: %v", err)
-		return nil, fmt.Errorf("ошибка БД: %w", err)
+func unmatchedPositionsParams(filter UnmatchedPositionsFilter, limit int32, workerID sql.NullString) db.GetUnmatchedPositionsParams {
+	return db.GetUnmatchedPositionsParams{
+		AfterID:      filter.AfterID,
+		TenderID:     nullInt64(filter.TenderID),
+		CreatedAfter: nullTime(filter.CreatedAfter),
+		WorkerID:     workerID,
+		RowLimit:     limit,
+	}
+}
+
+func nullInt64(v *int64) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
 	}
+	return sql.NullInt64{Int64: *v, Valid: true}
+}
+
+func nullTime(v *time.Time) sql.NullTime {
+	if v == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *v, Valid: true}
+}
 
+func toUnmatchedPositionResponses(dbRows []db.GetUnmatchedPositionsRow) []api_models.UnmatchedPositionResponse {
 	response := make([]api_models.UnmatchedPositionResponse, 0, len(dbRows))
 
 	for _, row := range dbRows {
@@ -107,79 +249,177 @@ func (s *MatchingService) GetUnmatchedPositions(
 			StandardJobTitle:   standardJobTitle,
 		})
 	}
-
-	s.logger.Infof("Найдено %d не сопоставленных позиций для RAG-воркера", len(response))
-	return response, nil
+	return response
 }
 
 // MatchPosition обрабатывает POST /api/v1/positions/match
+//
+// Результат применяется по принципу «первый воркер закрепляет позицию»:
+// если позиция уже сопоставлена другим воркером (req.WorkerID) с другой записью
+// каталога, возвращается ConflictError с текущим состоянием, а matching_cache не
+// меняется. Повтор того же результата конфликтом не считается.
+//
+// Если catalog_position_id выведен из оборота, в position_items и matching_cache
+// записывается его замена (replaced_by_id) — подстановку делают сами запросы.
 func (s *MatchingService) MatchPosition(
 	ctx context.Context,
 	req api_models.MatchPositionRequest,
 ) error {
 
-	// Устанавливаем версию нормы по умолчанию, если Python ее не прислал
-	normVersion := req.NormVersion
-	if normVersion == 0 {
-		normVersion = 1 // Версия по умолчанию
+	workerID, err := validateMatchRequest(req)
+	if err != nil {
+		return err
 	}
 
 	// Выполняем оба обновления в одной транзакции
+	var activeNormVersion int16
 	txErr := s.store.ExecTx(ctx, func(qtx *db.Queries) error {
+		return s.applyMatch(ctx, qtx, req, workerID, &activeNormVersion)
+	})
 
-		// 1. Обновляем position_items, "закрывая" NULL
-		//
-		err := qtx.SetCatalogPositionID(ctx, db.SetCatalogPositionIDParams{
-			CatalogPositionID: sql.NullInt64{Int64: req.CatalogPositionID, Valid: true},
-			ID:                req.PositionItemID,
-		})
-		if err != nil {
-			s.logger.Errorf("MatchPosition: Ошибка SetCatalogPositionID: %v", err)
-			return fmt.Errorf("ошибка обновления position_items: %w", err)
-		}
+	if txErr != nil {
+		return txErr // Возвращаем ошибку транзакции
+	}
 
-		// 2. Обновляем matching_cache для будущих импортов
-		// (Ищем "сырой" job_title, чтобы сохранить в кэш для отладки)
-		posItem, err := qtx.GetPositionItemByID(ctx, req.PositionItemID)
-		if err != nil {
-			s.logger.Warnf("MatchPosition: не удалось найти %d для лога кэша: %v", req.PositionItemID, err)
-			// Инициализируем пустой posItem для безопасного использования ниже
-			posItem = db.PositionItem{}
-		}
+	s.logger.Infof("Успешно сопоставлена позиция %d -> %d (hash: %s)",
+		req.PositionItemID, req.CatalogPositionID, req.Hash)
+	s.emitPositionMatched(ctx, req)
+	return nil
+}
 
-		// Устанавливаем TTL для кэша (например, 30 дней)
-		expiresAt := sql.NullTime{
-			Time:  time.Now().AddDate(0, 0, 30), // 30 дней от сейчас
-			Valid: true,
-		}
+// validateMatchRequest проверяет запрос сопоставления и возвращает worker_id без пробелов.
+func validateMatchRequest(req api_models.MatchPositionRequest) (string, error) {
+	workerID := strings.TrimSpace(req.WorkerID)
+	if workerID == "" {
+		return "", apierrors.NewValidationError("matching.worker_id_empty")
+	}
 
-		// Определяем jobTitleText: используем реальное значение, если posItem загружен успешно
-		jobTitleText := sql.NullString{String: "", Valid: false}
-		if posItem.JobTitleInProposal != "" {
-			jobTitleText = sql.NullString{String: posItem.JobTitleInProposal, Valid: true}
-		}
+	if req.NormVersion < 0 || req.NormVersion > math.MaxInt16 {
+		return "", apierrors.NewValidationError("matching.norm_version_range", req.NormVersion)
+	}
+	return workerID, nil
+}
 
-		//
-		err = qtx.UpsertMatchingCache(ctx, db.UpsertMatchingCacheParams{
-			JobTitleHash:      req.Hash,
-			NormVersion:       int16(normVersion), // (Убедитесь, что тип int16 в sqlc)
-			JobTitleText:      jobTitleText,
-			CatalogPositionID: req.CatalogPositionID,
-			ExpiresAt:         expiresAt, // 👈 (ДОБАВЛЕНО ПОЛЕ)
-		})
-		if err != nil {
-			s.logger.Errorf("MatchPosition: Ошибка UpsertMatchingCache: %v", err)
-			return fmt.Errorf("ошибка обновления matching_cache: %w", err)
+// applyMatch закрепляет позицию за воркером и обновляет matching_cache в рамках qtx.
+//
+// activeNormVersion — активная версия нормализации, прочитанная в этой транзакции
+// (0 — ещё не читалась); позволяет пакетной обработке читать её один раз.
+func (s *MatchingService) applyMatch(
+	ctx context.Context,
+	qtx *db.Queries,
+	req api_models.MatchPositionRequest,
+	workerID string,
+	activeNormVersion *int16,
+) error {
+	// 1. Обновляем position_items, "закрывая" NULL и закрепляя позицию за воркером
+	//
+	affected, err := qtx.SetCatalogPositionID(ctx, db.SetCatalogPositionIDParams{
+		CatalogPositionID: req.CatalogPositionID,
+		MatchedBy:         workerID,
+		ID:                req.PositionItemID,
+	})
+	if err != nil {
+		s.logger.Errorf("MatchPosition: Ошибка SetCatalogPositionID: %v", err)
+		return fmt.Errorf("ошибка обновления position_items: %w", err)
+	}
+	if affected == 0 {
+		return s.matchClaimConflict(ctx, qtx, req.PositionItemID, workerID)
+	}
+
+	// 2. Обновляем matching_cache для будущих импортов
+	// (Ищем "сырой" job_title, чтобы сохранить в кэш для отладки)
+	posItem, err := qtx.GetPositionItemByID(ctx, req.PositionItemID)
+	if err != nil {
+		s.logger.Warnf("MatchPosition: не удалось найти %d для лога кэша: %v", req.PositionItemID, err)
+		// Инициализируем пустой posItem для безопасного использования ниже
+		posItem = db.PositionItem{}
+	}
+
+	// Устанавливаем TTL для кэша (например, 30 дней)
+	expiresAt := sql.NullTime{
+		Time:  time.Now().AddDate(0, 0, 30), // 30 дней от сейчас
+		Valid: true,
+	}
+
+	// Определяем jobTitleText: используем реальное значение, если posItem загружен успешно
+	jobTitleText := sql.NullString{String: "", Valid: false}
+	if posItem.JobTitleInProposal != "" {
+		jobTitleText = sql.NullString{String: posItem.JobTitleInProposal, Valid: true}
+	}
+
+	// Если Python не прислал версию нормы, кэш пишется в активную (system_settings.norm_version)
+	normVersion := int16(req.NormVersion)
+	if normVersion == 0 {
+		if *activeNormVersion == 0 {
+			*activeNormVersion, err = qtx.GetActiveNormVersion(ctx)
+			if err != nil {
+				s.logger.Errorf("MatchPosition: Ошибка GetActiveNormVersion: %v", err)
+				return fmt.Errorf("ошибка чтения активной версии нормализации: %w", err)
+			}
 		}
+		normVersion = *activeNormVersion
+	}
 
-		return nil // Commit транзакции
+	err = qtx.UpsertMatchingCache(ctx, db.UpsertMatchingCacheParams{
+		JobTitleHash:      req.Hash,
+		NormVersion:       normVersion,
+		JobTitleText:      jobTitleText,
+		CatalogPositionID: req.CatalogPositionID,
+		ExpiresAt:         expiresAt, // 👈 (ДОБАВЛЕНО ПОЛЕ)
 	})
+	if err != nil {
+		s.logger.Errorf("MatchPosition: Ошибка UpsertMatchingCache: %v", err)
+		return fmt.Errorf("ошибка обновления matching_cache: %w", err)
+	}
 
-	if txErr != nil {
-		return txErr // Возвращаем ошибку транзакции
+	return nil // Commit транзакции
+}
+
+func (s *MatchingService) emitPositionMatched(ctx context.Context, req api_models.MatchPositionRequest) {
+	events.Emit(ctx, s.publisher, s.logger, events.TypePositionMatched, req.PositionItemID, events.PositionMatchedData{
+		PositionItemID:    req.PositionItemID,
+		CatalogPositionID: req.CatalogPositionID,
+		Hash:              req.Hash,
+	})
+}
+
+// MatchConflict — текущее состояние позиции, закреплённой другим воркером.
+// Передаётся в ConflictError, чтобы воркер мог решить, что делать дальше.
+type MatchConflict struct {
+	PositionItemID    int64      `json:"position_item_id"`
+	CatalogPositionID *int64     `json:"catalog_position_id"`
+	MatchedBy         string     `json:"matched_by"`
+	MatchedAt         *time.Time `json:"matched_at,omitempty"`
+}
+
+// matchClaimConflict объясняет, почему SetCatalogPositionID не изменил строку:
+// позиции нет (NotFoundError) или она закреплена другим воркером (ConflictError).
+func (s *MatchingService) matchClaimConflict(
+	ctx context.Context,
+	qtx *db.Queries,
+	positionItemID int64,
+	workerID string,
+) error {
+	posItem, err := qtx.GetPositionItemByID(ctx, positionItemID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apierrors.NewNotFoundError("matching.position_not_found", positionItemID)
+		}
+		return fmt.Errorf("ошибка получения позиции %d: %w", positionItemID, err)
 	}
 
-	s.logger.Infof("Успешно сопоставлена позиция %d -> %d (hash: %s)",
-		req.PositionItemID, req.CatalogPositionID, req.Hash)
-	return nil
+	conflict := MatchConflict{
+		PositionItemID: posItem.ID,
+		MatchedBy:      posItem.MatchedBy.String,
+	}
+	if posItem.CatalogPositionID.Valid {
+		conflict.CatalogPositionID = &posItem.CatalogPositionID.Int64
+	}
+	if posItem.MatchedAt.Valid {
+		conflict.MatchedAt = &posItem.MatchedAt.Time
+	}
+
+	s.logger.Warnf("MatchPosition: позиция %d уже закреплена воркером %s (запрос от %s)",
+		positionItemID, conflict.MatchedBy, workerID)
+	return apierrors.NewConflictErrorf(conflict, "matching.already_matched", positionItemID, conflict.MatchedBy)
 }
@@ -19,6 +19,7 @@ import (
 	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
 	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
 )
 
@@ -34,7 +35,7 @@ What user problems does this protect us from?
 4. Error propagation — DB errors must be properly wrapped and returned
 5. Transaction safety — MatchPosition updates position_items AND matching_cache
    atomically inside ExecTx
-6. Default values — norm_version defaults to 1 when not provided
+6. Default values — norm_version defaults to the active version (system_settings) when not provided
 7. Context building — breadcrumbs (full_parent_path) vs root positions handled correctly
 
 GIVEN / WHEN / THEN Scenarios:
@@ -84,7 +85,7 @@ SCENARIO 2: MatchPosition
 
 - GIVEN valid request with norm_version = 0
   WHEN MatchPosition is called
-  THEN norm_version defaults to 1
+  THEN the active norm_version from system_settings is used
 
 - GIVEN valid request with explicit norm_version = 3
   WHEN MatchPosition is called
@@ -105,6 +106,34 @@ SCENARIO 2: MatchPosition
 - GIVEN ExecTx itself fails (e.g., tx begin error)
   WHEN MatchPosition is called
   THEN error is returned
+
+SCENARIO 3: MatchPosition claim (several worker instances)
+- GIVEN the position is already claimed by worker-b with another catalog position
+  WHEN worker-a calls MatchPosition
+  THEN ConflictError with the current claim is returned and matching_cache is untouched
+
+- GIVEN the position does not exist
+  WHEN MatchPosition is called
+  THEN NotFoundError is returned
+
+- GIVEN an empty worker_id
+  WHEN MatchPosition is called
+  THEN ValidationError is returned without DB call
+
+SCENARIO 4: Cursor, filters and claim lease (several worker instances)
+- GIVEN after_id, tender_id and created_after
+  WHEN GetUnmatchedPositions is called
+  THEN they are passed to the query and leased positions are excluded (worker_id NULL)
+
+- GIVEN free positions after the cursor
+  WHEN ClaimUnmatchedPositions is called
+  THEN they are leased to the worker in one transaction and returned with next_after_id
+
+- GIVEN an exhausted queue
+  THEN an empty list without cursor is returned
+
+- GIVEN an empty worker_id or a lease above MaxClaimLease
+  THEN ValidationError without DB call
 */
 
 // =============================================================================
@@ -119,8 +148,9 @@ func setupTestService(t *testing.T) (*MatchingService, *db.MockStore) {
 	logger := testutil.NewMockLogger()
 
 	service := &MatchingService{
-		store:  mockStore,
-		logger: logger,
+		store:     mockStore,
+		logger:    logger,
+		publisher: events.Noop{},
 	}
 
 	return service, mockStore
@@ -162,6 +192,8 @@ var positionItemColumns = []string{
 	"unit_cost_total", "total_cost_materials", "total_cost_works",
 	"total_cost_indirect_costs", "total_cost_total", "deviation_from_baseline_cost",
 	"is_chapter", "chapter_ref_in_proposal", "created_at", "updated_at",
+	"article_smr", "matched_by", "matched_at", "claimed_by", "claimed_until",
+	"currency",
 }
 
 // Helper: create a sqlmock row for position_items with given id and job_title
@@ -194,6 +226,12 @@ func positionItemRow(id int64, jobTitle string) []driver.Value {
 		sql.NullString{String: "", Valid: false},   // chapter_ref_in_proposal
 		now,                                        // created_at
 		now,                                        // updated_at
+		sql.NullString{String: "", Valid: false},   // article_smr
+		sql.NullString{String: "", Valid: false},   // matched_by
+		sql.NullTime{Valid: false},                 // matched_at
+		sql.NullString{String: "", Valid: false},   // claimed_by
+		sql.NullTime{Valid: false},                 // claimed_until
+		sql.NullString{String: "", Valid: false},   // currency
 	}
 }
 
@@ -206,7 +244,7 @@ func TestNewMatchingService(t *testing.T) {
 	mockStore := db.NewMockStore(ctrl)
 	logger := testutil.NewMockLogger()
 
-	service := NewMatchingService(mockStore, logger)
+	service := NewMatchingService(mockStore, logger, events.Noop{})
 
 	require.NotNil(t, service)
 	assert.Equal(t, mockStore, service.store)
@@ -233,11 +271,11 @@ func TestGetUnmatchedPositions_Success_WithBreadcrumbs(t *testing.T) {
 	}
 
 	mockStore.EXPECT().
-		GetUnmatchedPositions(ctx, int32(10)).
+		GetUnmatchedPositions(ctx, db.GetUnmatchedPositionsParams{RowLimit: int32(10)}).
 		Return(dbRows, nil)
 
 	// WHEN
-	result, err := service.GetUnmatchedPositions(ctx, 10)
+	result, err := service.GetUnmatchedPositions(ctx, UnmatchedPositionsFilter{Limit: 10})
 
 	// THEN
 	require.NoError(t, err)
@@ -265,11 +303,11 @@ func TestGetUnmatchedPositions_Success_WithoutBreadcrumbs(t *testing.T) {
 	}
 
 	mockStore.EXPECT().
-		GetUnmatchedPositions(ctx, int32(5)).
+		GetUnmatchedPositions(ctx, db.GetUnmatchedPositionsParams{RowLimit: int32(5)}).
 		Return(dbRows, nil)
 
 	// WHEN
-	result, err := service.GetUnmatchedPositions(ctx, 5)
+	result, err := service.GetUnmatchedPositions(ctx, UnmatchedPositionsFilter{Limit: 5})
 
 	// THEN
 	require.NoError(t, err)
@@ -294,11 +332,11 @@ func TestGetUnmatchedPositions_Success_WithDraftCatalogID(t *testing.T) {
 	}
 
 	mockStore.EXPECT().
-		GetUnmatchedPositions(ctx, int32(10)).
+		GetUnmatchedPositions(ctx, db.GetUnmatchedPositionsParams{RowLimit: int32(10)}).
 		Return(dbRows, nil)
 
 	// WHEN
-	result, err := service.GetUnmatchedPositions(ctx, 10)
+	result, err := service.GetUnmatchedPositions(ctx, UnmatchedPositionsFilter{Limit: 10})
 
 	// THEN
 	require.NoError(t, err)
@@ -339,11 +377,11 @@ func TestGetUnmatchedPositions_Success_MultiplePositions(t *testing.T) {
 	}
 
 	mockStore.EXPECT().
-		GetUnmatchedPositions(ctx, int32(50)).
+		GetUnmatchedPositions(ctx, db.GetUnmatchedPositionsParams{RowLimit: int32(50)}).
 		Return(dbRows, nil)
 
 	// WHEN
-	result, err := service.GetUnmatchedPositions(ctx, 50)
+	result, err := service.GetUnmatchedPositions(ctx, UnmatchedPositionsFilter{Limit: 50})
 
 	// THEN
 	require.NoError(t, err)
@@ -370,11 +408,11 @@ func TestGetUnmatchedPositions_EmptyResult(t *testing.T) {
 
 	// GIVEN no unmatched positions in DB
 	mockStore.EXPECT().
-		GetUnmatchedPositions(ctx, int32(10)).
+		GetUnmatchedPositions(ctx, db.GetUnmatchedPositionsParams{RowLimit: int32(10)}).
 		Return([]db.GetUnmatchedPositionsRow{}, nil)
 
 	// WHEN
-	result, err := service.GetUnmatchedPositions(ctx, 10)
+	result, err := service.GetUnmatchedPositions(ctx, UnmatchedPositionsFilter{Limit: 10})
 
 	// THEN
 	require.NoError(t, err)
@@ -388,7 +426,7 @@ func TestGetUnmatchedPositions_ValidationError_ZeroLimit(t *testing.T) {
 
 	// GIVEN zero limit
 	// WHEN
-	result, err := service.GetUnmatchedPositions(ctx, 0)
+	result, err := service.GetUnmatchedPositions(ctx, UnmatchedPositionsFilter{Limit: 0})
 
 	// THEN — ValidationError, no DB call
 	require.Error(t, err)
@@ -404,7 +442,7 @@ func TestGetUnmatchedPositions_ValidationError_NegativeLimit(t *testing.T) {
 
 	// GIVEN negative limit
 	// WHEN
-	result, err := service.GetUnmatchedPositions(ctx, -5)
+	result, err := service.GetUnmatchedPositions(ctx, UnmatchedPositionsFilter{Limit: -5})
 
 	// THEN — ValidationError, no DB call
 	require.Error(t, err)
@@ -423,11 +461,11 @@ func TestGetUnmatchedPositions_LimitCappedToMax(t *testing.T) {
 
 	// THEN DB is called with capped limit
 	mockStore.EXPECT().
-		GetUnmatchedPositions(ctx, int32(MaxUnmatchedPositionsLimit)).
+		GetUnmatchedPositions(ctx, db.GetUnmatchedPositionsParams{RowLimit: int32(MaxUnmatchedPositionsLimit)}).
 		Return([]db.GetUnmatchedPositionsRow{}, nil)
 
 	// WHEN
-	result, err := service.GetUnmatchedPositions(ctx, excessiveLimit)
+	result, err := service.GetUnmatchedPositions(ctx, UnmatchedPositionsFilter{Limit: excessiveLimit})
 
 	// THEN
 	require.NoError(t, err)
@@ -440,11 +478,11 @@ func TestGetUnmatchedPositions_LimitExactlyAtMax(t *testing.T) {
 
 	// GIVEN limit exactly at MaxUnmatchedPositionsLimit
 	mockStore.EXPECT().
-		GetUnmatchedPositions(ctx, int32(MaxUnmatchedPositionsLimit)).
+		GetUnmatchedPositions(ctx, db.GetUnmatchedPositionsParams{RowLimit: int32(MaxUnmatchedPositionsLimit)}).
 		Return([]db.GetUnmatchedPositionsRow{}, nil)
 
 	// WHEN
-	result, err := service.GetUnmatchedPositions(ctx, MaxUnmatchedPositionsLimit)
+	result, err := service.GetUnmatchedPositions(ctx, UnmatchedPositionsFilter{Limit: MaxUnmatchedPositionsLimit})
 
 	// THEN — no capping, DB called with exact value
 	require.NoError(t, err)
@@ -458,11 +496,11 @@ func TestGetUnmatchedPositions_DBError(t *testing.T) {
 	// GIVEN DB returns an error
 	dbErr := errors.New("connection refused")
 	mockStore.EXPECT().
-		GetUnmatchedPositions(ctx, int32(10)).
+		GetUnmatchedPositions(ctx, db.GetUnmatchedPositionsParams{RowLimit: int32(10)}).
 		Return(nil, dbErr)
 
 	// WHEN
-	result, err := service.GetUnmatchedPositions(ctx, 10)
+	result, err := service.GetUnmatchedPositions(ctx, UnmatchedPositionsFilter{Limit: 10})
 
 	// THEN — wrapped DB error
 	require.Error(t, err)
@@ -483,6 +521,7 @@ func TestMatchPosition_Success(t *testing.T) {
 	req := api_models.MatchPositionRequest{
 		PositionItemID:    100,
 		CatalogPositionID: 42,
+		WorkerID:          "worker-a",
 		Hash:              "abc123hash",
 		NormVersion:       2,
 	}
@@ -491,7 +530,7 @@ func TestMatchPosition_Success(t *testing.T) {
 		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
 			// SetCatalogPositionID succeeds
 			mock.ExpectExec("UPDATE position_items").
-				WithArgs(sql.NullInt64{Int64: 42, Valid: true}, int64(100)).
+				WithArgs(int64(42), "worker-a", int64(100)).
 				WillReturnResult(sqlmock.NewResult(0, 1))
 
 			// GetPositionItemByID returns position
@@ -524,10 +563,11 @@ func TestMatchPosition_DefaultNormVersion(t *testing.T) {
 	service, mockStore := setupTestService(t)
 	ctx := context.Background()
 
-	// GIVEN request with norm_version = 0 (should default to 1)
+	// GIVEN request with norm_version = 0 (should default to the active version)
 	req := api_models.MatchPositionRequest{
 		PositionItemID:    100,
 		CatalogPositionID: 42,
+		WorkerID:          "worker-a",
 		Hash:              "hash456",
 		NormVersion:       0,
 	}
@@ -535,7 +575,7 @@ func TestMatchPosition_DefaultNormVersion(t *testing.T) {
 	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
 		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
 			mock.ExpectExec("UPDATE position_items").
-				WithArgs(sql.NullInt64{Int64: 42, Valid: true}, int64(100)).
+				WithArgs(int64(42), "worker-a", int64(100)).
 				WillReturnResult(sqlmock.NewResult(0, 1))
 
 			mock.ExpectQuery("SELECT .+ FROM position_items WHERE id").
@@ -543,11 +583,15 @@ func TestMatchPosition_DefaultNormVersion(t *testing.T) {
 				WillReturnRows(sqlmock.NewRows(positionItemColumns).
 					AddRow(positionItemRow(100, "Покраска стен")...))
 
-			// norm_version should be 1 (default)
+			// GetActiveNormVersion: активная версия после перенормализации
+			mock.ExpectQuery("SELECT COALESCE").
+				WillReturnRows(sqlmock.NewRows([]string{"norm_version"}).AddRow(int16(2)))
+
+			// norm_version should be the active one
 			mock.ExpectExec("INSERT INTO matching_cache").
 				WithArgs(
 					"hash456",
-					int16(1), // default norm_version
+					int16(2), // active norm_version
 					sql.NullString{String: "Покраска стен", Valid: true},
 					int64(42),
 					sqlmock.AnyArg(),
@@ -571,6 +615,7 @@ func TestMatchPosition_ExplicitNormVersion(t *testing.T) {
 	req := api_models.MatchPositionRequest{
 		PositionItemID:    100,
 		CatalogPositionID: 42,
+		WorkerID:          "worker-a",
 		Hash:              "hash789",
 		NormVersion:       3,
 	}
@@ -578,7 +623,7 @@ func TestMatchPosition_ExplicitNormVersion(t *testing.T) {
 	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
 		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
 			mock.ExpectExec("UPDATE position_items").
-				WithArgs(sql.NullInt64{Int64: 42, Valid: true}, int64(100)).
+				WithArgs(int64(42), "worker-a", int64(100)).
 				WillReturnResult(sqlmock.NewResult(0, 1))
 
 			mock.ExpectQuery("SELECT .+ FROM position_items WHERE id").
@@ -613,6 +658,7 @@ func TestMatchPosition_SetCatalogPositionIDFails(t *testing.T) {
 	req := api_models.MatchPositionRequest{
 		PositionItemID:    100,
 		CatalogPositionID: 42,
+		WorkerID:          "worker-a",
 		Hash:              "hash-fail",
 		NormVersion:       1,
 	}
@@ -620,7 +666,7 @@ func TestMatchPosition_SetCatalogPositionIDFails(t *testing.T) {
 	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
 		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
 			mock.ExpectExec("UPDATE position_items").
-				WithArgs(sql.NullInt64{Int64: 42, Valid: true}, int64(100)).
+				WithArgs(int64(42), "worker-a", int64(100)).
 				WillReturnError(errors.New("deadlock detected"))
 		}),
 	)
@@ -641,6 +687,7 @@ func TestMatchPosition_GetPositionItemByIDFails_ContinuesWithEmptyTitle(t *testi
 	req := api_models.MatchPositionRequest{
 		PositionItemID:    999,
 		CatalogPositionID: 42,
+		WorkerID:          "worker-a",
 		Hash:              "hash-missing-pos",
 		NormVersion:       1,
 	}
@@ -648,7 +695,7 @@ func TestMatchPosition_GetPositionItemByIDFails_ContinuesWithEmptyTitle(t *testi
 	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
 		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
 			mock.ExpectExec("UPDATE position_items").
-				WithArgs(sql.NullInt64{Int64: 42, Valid: true}, int64(999)).
+				WithArgs(int64(42), "worker-a", int64(999)).
 				WillReturnResult(sqlmock.NewResult(0, 1))
 
 			// GetPositionItemByID returns not found
@@ -684,6 +731,7 @@ func TestMatchPosition_UpsertMatchingCacheFails(t *testing.T) {
 	req := api_models.MatchPositionRequest{
 		PositionItemID:    100,
 		CatalogPositionID: 42,
+		WorkerID:          "worker-a",
 		Hash:              "hash-cache-fail",
 		NormVersion:       1,
 	}
@@ -691,7 +739,7 @@ func TestMatchPosition_UpsertMatchingCacheFails(t *testing.T) {
 	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
 		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
 			mock.ExpectExec("UPDATE position_items").
-				WithArgs(sql.NullInt64{Int64: 42, Valid: true}, int64(100)).
+				WithArgs(int64(42), "worker-a", int64(100)).
 				WillReturnResult(sqlmock.NewResult(0, 1))
 
 			mock.ExpectQuery("SELECT .+ FROM position_items WHERE id").
@@ -727,6 +775,7 @@ func TestMatchPosition_ExecTxFails(t *testing.T) {
 	req := api_models.MatchPositionRequest{
 		PositionItemID:    100,
 		CatalogPositionID: 42,
+		WorkerID:          "worker-a",
 		Hash:              "hash-tx-fail",
 		NormVersion:       1,
 	}
@@ -762,7 +811,7 @@ func TestGetUnmatchedPositions_ValidationErrors_TableDriven(t *testing.T) {
 			service, _ := setupTestService(t)
 			ctx := context.Background()
 
-			result, err := service.GetUnmatchedPositions(ctx, tt.limit)
+			result, err := service.GetUnmatchedPositions(ctx, UnmatchedPositionsFilter{Limit: tt.limit})
 
 			require.Error(t, err)
 			assert.Nil(t, result)
@@ -821,10 +870,10 @@ func TestGetUnmatchedPositions_ContextStringBuilding_TableDriven(t *testing.T) {
 			}
 
 			mockStore.EXPECT().
-				GetUnmatchedPositions(ctx, int32(10)).
+				GetUnmatchedPositions(ctx, db.GetUnmatchedPositionsParams{RowLimit: int32(10)}).
 				Return(dbRows, nil)
 
-			result, err := service.GetUnmatchedPositions(ctx, 10)
+			result, err := service.GetUnmatchedPositions(ctx, UnmatchedPositionsFilter{Limit: 10})
 
 			require.NoError(t, err)
 			require.Len(t, result, 1)
@@ -843,7 +892,7 @@ func TestMatchPosition_NormVersion_TableDriven(t *testing.T) {
 		inputVersion    int
 		expectedVersion int16
 	}{
-		{name: "default (0 → 1)", inputVersion: 0, expectedVersion: 1},
+		{name: "default (0 → active)", inputVersion: 0, expectedVersion: 1},
 		{name: "explicit 1", inputVersion: 1, expectedVersion: 1},
 		{name: "explicit 2", inputVersion: 2, expectedVersion: 2},
 		{name: "explicit 5", inputVersion: 5, expectedVersion: 5},
@@ -857,6 +906,7 @@ func TestMatchPosition_NormVersion_TableDriven(t *testing.T) {
 			req := api_models.MatchPositionRequest{
 				PositionItemID:    100,
 				CatalogPositionID: 42,
+				WorkerID:          "worker-a",
 				Hash:              "test-hash",
 				NormVersion:       tt.inputVersion,
 			}
@@ -864,7 +914,7 @@ func TestMatchPosition_NormVersion_TableDriven(t *testing.T) {
 			mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
 				execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
 					mock.ExpectExec("UPDATE position_items").
-						WithArgs(sql.NullInt64{Int64: 42, Valid: true}, int64(100)).
+						WithArgs(int64(42), "worker-a", int64(100)).
 						WillReturnResult(sqlmock.NewResult(0, 1))
 
 					mock.ExpectQuery("SELECT .+ FROM position_items WHERE id").
@@ -872,6 +922,11 @@ func TestMatchPosition_NormVersion_TableDriven(t *testing.T) {
 						WillReturnRows(sqlmock.NewRows(positionItemColumns).
 							AddRow(positionItemRow(100, "Тестовая позиция")...))
 
+					if tt.inputVersion == 0 {
+						mock.ExpectQuery("SELECT COALESCE").
+							WillReturnRows(sqlmock.NewRows([]string{"norm_version"}).AddRow(int16(1)))
+					}
+
 					mock.ExpectExec("INSERT INTO matching_cache").
 						WithArgs(
 							"test-hash",
@@ -901,11 +956,11 @@ func TestGetUnmatchedPositions_LimitJustBelowMax(t *testing.T) {
 	// GIVEN limit = MaxUnmatchedPositionsLimit - 1 (should NOT be capped)
 	limit := int32(MaxUnmatchedPositionsLimit - 1)
 	mockStore.EXPECT().
-		GetUnmatchedPositions(ctx, limit).
+		GetUnmatchedPositions(ctx, db.GetUnmatchedPositionsParams{RowLimit: limit}).
 		Return([]db.GetUnmatchedPositionsRow{}, nil)
 
 	// WHEN
-	result, err := service.GetUnmatchedPositions(ctx, limit)
+	result, err := service.GetUnmatchedPositions(ctx, UnmatchedPositionsFilter{Limit: limit})
 
 	// THEN
 	require.NoError(t, err)
@@ -918,11 +973,11 @@ func TestGetUnmatchedPositions_LimitJustAboveMax(t *testing.T) {
 
 	// GIVEN limit = MaxUnmatchedPositionsLimit + 1 (should be capped)
 	mockStore.EXPECT().
-		GetUnmatchedPositions(ctx, int32(MaxUnmatchedPositionsLimit)).
+		GetUnmatchedPositions(ctx, db.GetUnmatchedPositionsParams{RowLimit: int32(MaxUnmatchedPositionsLimit)}).
 		Return([]db.GetUnmatchedPositionsRow{}, nil)
 
 	// WHEN
-	result, err := service.GetUnmatchedPositions(ctx, MaxUnmatchedPositionsLimit+1)
+	result, err := service.GetUnmatchedPositions(ctx, UnmatchedPositionsFilter{Limit: MaxUnmatchedPositionsLimit + 1})
 
 	// THEN — capped to MaxUnmatchedPositionsLimit
 	require.NoError(t, err)
@@ -945,11 +1000,11 @@ func TestGetUnmatchedPositions_LimitOne(t *testing.T) {
 	}
 
 	mockStore.EXPECT().
-		GetUnmatchedPositions(ctx, int32(1)).
+		GetUnmatchedPositions(ctx, db.GetUnmatchedPositionsParams{RowLimit: int32(1)}).
 		Return(dbRows, nil)
 
 	// WHEN
-	result, err := service.GetUnmatchedPositions(ctx, 1)
+	result, err := service.GetUnmatchedPositions(ctx, UnmatchedPositionsFilter{Limit: 1})
 
 	// THEN
 	require.NoError(t, err)
@@ -966,6 +1021,7 @@ func TestMatchPosition_EmptyJobTitle_PositionFound(t *testing.T) {
 	req := api_models.MatchPositionRequest{
 		PositionItemID:    100,
 		CatalogPositionID: 42,
+		WorkerID:          "worker-a",
 		Hash:              "hash-empty-title",
 		NormVersion:       1,
 	}
@@ -973,7 +1029,7 @@ func TestMatchPosition_EmptyJobTitle_PositionFound(t *testing.T) {
 	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
 		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
 			mock.ExpectExec("UPDATE position_items").
-				WithArgs(sql.NullInt64{Int64: 42, Valid: true}, int64(100)).
+				WithArgs(int64(42), "worker-a", int64(100)).
 				WillReturnResult(sqlmock.NewResult(0, 1))
 
 			// Position found but with empty job_title
@@ -1007,3 +1063,193 @@ func TestMaxUnmatchedPositionsLimit_Constant(t *testing.T) {
 	// THEN it should be 1000 (documented contract with external consumers)
 	assert.Equal(t, int32(1000), int32(MaxUnmatchedPositionsLimit))
 }
+
+// =============================================================================
+// MatchPosition CLAIM TESTS
+// =============================================================================
+
+func TestMatchPosition_ClaimedByAnotherWorker_ReturnsConflict(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	matchedAt := time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)
+
+	req := api_models.MatchPositionRequest{
+		PositionItemID:    100,
+		CatalogPositionID: 42,
+		Hash:              "hash-conflict",
+		WorkerID:          "worker-a",
+	}
+
+	claimed := positionItemRow(100, "Монтаж электропроводки")
+	claimed[2] = sql.NullInt64{Int64: 77, Valid: true}
+	claimed[len(claimed)-4] = sql.NullString{String: "worker-b", Valid: true}
+	claimed[len(claimed)-3] = sql.NullTime{Time: matchedAt, Valid: true}
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			// Условие claim не выполнено — строка не изменена
+			mock.ExpectExec("UPDATE position_items").
+				WithArgs(int64(42), "worker-a", int64(100)).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+
+			mock.ExpectQuery("SELECT .+ FROM position_items WHERE id").
+				WithArgs(int64(100)).
+				WillReturnRows(sqlmock.NewRows(positionItemColumns).AddRow(claimed...))
+			// UpsertMatchingCache не вызывается
+		}),
+	)
+
+	err := service.MatchPosition(context.Background(), req)
+
+	var conflictErr *apierrors.ConflictError
+	require.True(t, errors.As(err, &conflictErr))
+	conflict, ok := conflictErr.Conflicts.(MatchConflict)
+	require.True(t, ok)
+	assert.Equal(t, int64(100), conflict.PositionItemID)
+	require.NotNil(t, conflict.CatalogPositionID)
+	assert.Equal(t, int64(77), *conflict.CatalogPositionID)
+	assert.Equal(t, "worker-b", conflict.MatchedBy)
+	require.NotNil(t, conflict.MatchedAt)
+	assert.Equal(t, matchedAt, *conflict.MatchedAt)
+}
+
+func TestMatchPosition_PositionNotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	req := api_models.MatchPositionRequest{
+		PositionItemID:    999,
+		CatalogPositionID: 42,
+		Hash:              "hash-missing",
+		WorkerID:          "worker-a",
+	}
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectExec("UPDATE position_items").
+				WithArgs(int64(42), "worker-a", int64(999)).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+
+			mock.ExpectQuery("SELECT .+ FROM position_items WHERE id").
+				WithArgs(int64(999)).
+				WillReturnError(sql.ErrNoRows)
+		}),
+	)
+
+	err := service.MatchPosition(context.Background(), req)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+}
+
+func TestMatchPosition_EmptyWorkerID_ReturnsValidationError(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	err := service.MatchPosition(context.Background(), api_models.MatchPositionRequest{
+		PositionItemID:    100,
+		CatalogPositionID: 42,
+		Hash:              "hash",
+		WorkerID:          "  ",
+	})
+
+	var validationErr *apierrors.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+}
+
+// =============================================================================
+// Cursor / claim TESTS
+// =============================================================================
+
+func TestGetUnmatchedPositions_CursorAndFilters(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	ctx := context.Background()
+	tenderID := int64(7)
+	createdAfter := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mockStore.EXPECT().
+		GetUnmatchedPositions(ctx, db.GetUnmatchedPositionsParams{
+			AfterID:      500,
+			TenderID:     sql.NullInt64{Int64: 7, Valid: true},
+			CreatedAfter: sql.NullTime{Time: createdAfter, Valid: true},
+			RowLimit:     20,
+		}).
+		Return([]db.GetUnmatchedPositionsRow{{PositionItemID: 501, JobTitleInProposal: "Кладка"}}, nil)
+
+	result, err := service.GetUnmatchedPositions(ctx, UnmatchedPositionsFilter{
+		Limit:        20,
+		AfterID:      500,
+		TenderID:     &tenderID,
+		CreatedAfter: &createdAfter,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, int64(501), result[0].PositionItemID)
+}
+
+func TestClaimUnmatchedPositions_Success(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	claimedUntil := time.Date(2025, 1, 15, 10, 5, 0, 0, time.UTC)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			// Аренда: after_id, tender_id, created_after, worker_id, limit, lease_seconds
+			mock.ExpectQuery("WITH candidates AS").
+				WithArgs(int64(100), sql.NullInt64{}, sql.NullTime{}, "worker-a", int32(DefaultClaimLimit), int32(60)).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "claimed_until"}).
+					AddRow(int64(102), claimedUntil).
+					AddRow(int64(101), claimedUntil))
+
+			// Чтение арендованных позиций с контекстом
+			mock.ExpectQuery("WITH RECURSIVE Breadcrumbs").
+				WithArgs(int64(100), sql.NullInt64{}, sql.NullTime{}, sql.NullString{String: "worker-a", Valid: true}, int32(2)).
+				WillReturnRows(sqlmock.NewRows([]string{
+					"position_item_id", "job_title_in_proposal", "full_parent_path", "draft_catalog_id", "standard_job_title",
+				}).
+					AddRow(int64(101), "Кладка", "", nil, nil).
+					AddRow(int64(102), "Штукатурка", "Отделка", nil, nil))
+		}),
+	)
+
+	resp, err := service.ClaimUnmatchedPositions(context.Background(), " worker-a ",
+		UnmatchedPositionsFilter{AfterID: 100}, time.Minute)
+
+	require.NoError(t, err)
+	require.Len(t, resp.Positions, 2)
+	assert.Equal(t, "Раздел: Отделка | Позиция: Штукатурка", resp.Positions[1].RichContextString)
+	require.NotNil(t, resp.ClaimedUntil)
+	assert.Equal(t, claimedUntil, *resp.ClaimedUntil)
+	require.NotNil(t, resp.NextAfterID)
+	assert.Equal(t, int64(102), *resp.NextAfterID)
+}
+
+func TestClaimUnmatchedPositions_QueueExhausted(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("WITH candidates AS").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "claimed_until"}))
+		}),
+	)
+
+	resp, err := service.ClaimUnmatchedPositions(context.Background(), "worker-a", UnmatchedPositionsFilter{}, 0)
+
+	require.NoError(t, err)
+	assert.Empty(t, resp.Positions)
+	assert.NotNil(t, resp.Positions, "пустой массив, а не null")
+	assert.Nil(t, resp.NextAfterID)
+	assert.Nil(t, resp.ClaimedUntil)
+}
+
+func TestClaimUnmatchedPositions_ValidationErrors(t *testing.T) {
+	service, _ := setupTestService(t)
+	var validationErr *apierrors.ValidationError
+
+	_, err := service.ClaimUnmatchedPositions(context.Background(), "  ", UnmatchedPositionsFilter{}, time.Minute)
+	assert.True(t, errors.As(err, &validationErr), "empty worker_id")
+
+	_, err = service.ClaimUnmatchedPositions(context.Background(), "worker-a", UnmatchedPositionsFilter{}, 2*MaxClaimLease)
+	assert.True(t, errors.As(err, &validationErr), "lease too long")
+
+	_, err = service.ClaimUnmatchedPositions(context.Background(), "worker-a", UnmatchedPositionsFilter{AfterID: -1}, time.Minute)
+	assert.True(t, errors.As(err, &validationErr), "negative after_id")
+}
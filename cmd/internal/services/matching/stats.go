@@ -0,0 +1,137 @@
+package matching
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+// DefaultStatsPeriod — период статистики матчинга, если from не задан.
+const DefaultStatsPeriod = 30 * 24 * time.Hour
+
+// StatsQuery — параметры GET /api/v1/admin/matching/stats. Даты без времени,
+// To включительно; незаданный To — сегодня, незаданный From — To минус 30 дней.
+type StatsQuery struct {
+	From sql.NullTime
+	To   sql.NullTime
+}
+
+// GetMatchingStats собирает метрики качества матчинга: попадания в
+// matching_cache при импортах (за период и по неделям), распределение позиций
+// по способу сопоставления, ручные исправления, средняя схожесть принятых и
+// отклонённых слияний и возраст очереди несопоставленных позиций.
+func (s *MatchingService) GetMatchingStats(ctx context.Context, query StatsQuery) (*api_models.MatchingStatsResponse, error) {
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if query.To.Valid {
+		to = query.To.Time
+	}
+	from := to.Add(-DefaultStatsPeriod)
+	if query.From.Valid {
+		from = query.From.Time
+	}
+	if from.After(to) {
+		return nil, apierrors.NewValidationError("report.from_after_to")
+	}
+	// to включительный: в SQL используется полуинтервал [from, to + 1 день)
+	dateTo := to.AddDate(0, 0, 1)
+
+	weeks, err := s.store.ListImportCacheStatsByWeek(ctx, db.ListImportCacheStatsByWeekParams{
+		DateFrom: from,
+		DateTo:   dateTo,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения import_metrics: %w", err)
+	}
+	positions, err := s.store.GetPositionMatchSourceStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подсчёта позиций по способу сопоставления: %w", err)
+	}
+	corrections, err := s.store.CountMatchingCorrections(ctx, db.CountMatchingCorrectionsParams{
+		DateFrom: from,
+		DateTo:   dateTo,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подсчёта ручных исправлений: %w", err)
+	}
+	merges, err := s.store.GetMergeDecisionStats(ctx, db.GetMergeDecisionStatsParams{
+		DateFrom: from,
+		DateTo:   dateTo,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подсчёта решений по слияниям: %w", err)
+	}
+	backlog, err := s.store.GetUnmatchedBacklogAge(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка расчёта возраста очереди: %w", err)
+	}
+
+	resp := &api_models.MatchingStatsResponse{
+		From:        from.Format(time.DateOnly),
+		To:          to.Format(time.DateOnly),
+		Weekly:      make([]api_models.MatchingCacheStats, 0, len(weeks)),
+		Corrections: corrections,
+		Merges: api_models.MatchingMergeStats{
+			Accepted: merges.Accepted,
+			Rejected: merges.Rejected,
+			Pending:  merges.Pending,
+		},
+		Backlog: api_models.MatchingBacklogStats{
+			Positions:  backlog.Positions,
+			P50Seconds: backlog.P50Seconds,
+			P90Seconds: backlog.P90Seconds,
+			P99Seconds: backlog.P99Seconds,
+			MaxSeconds: backlog.MaxSeconds,
+		},
+	}
+	for _, w := range weeks {
+		weekStart := w.WeekStart.UTC()
+		resp.Weekly = append(resp.Weekly, api_models.MatchingCacheStats{
+			WeekStart:   &weekStart,
+			Imports:     w.Imports,
+			CacheHits:   w.CacheHits,
+			CacheMisses: w.CacheMisses,
+			HitRate:     ratio(w.CacheHits, w.CacheHits+w.CacheMisses),
+		})
+		resp.Cache.Imports += w.Imports
+		resp.Cache.CacheHits += w.CacheHits
+		resp.Cache.CacheMisses += w.CacheMisses
+	}
+	resp.Cache.HitRate = ratio(resp.Cache.CacheHits, resp.Cache.CacheHits+resp.Cache.CacheMisses)
+
+	if merges.Accepted > 0 {
+		resp.Merges.AcceptedAvgSimilarity = &merges.AcceptedAvgSimilarity
+	}
+	if merges.Rejected > 0 {
+		resp.Merges.RejectedAvgSimilarity = &merges.RejectedAvgSimilarity
+	}
+
+	total := positions.Cache + positions.Rag + positions.Manual + positions.Unmatched
+	resp.Positions = api_models.MatchingPositionStats{
+		Total:     total,
+		AutoCache: positions.Cache,
+		AutoRAG:   positions.Rag,
+		Manual:    positions.Manual,
+		Unmatched: positions.Unmatched,
+	}
+	if total > 0 {
+		resp.Positions.AutoShare = float64(positions.Cache+positions.Rag) / float64(total)
+		resp.Positions.ManualShare = float64(positions.Manual) / float64(total)
+		resp.Positions.UnmatchedShare = float64(positions.Unmatched) / float64(total)
+	}
+
+	return resp, nil
+}
+
+// ratio возвращает part/whole или nil при whole = 0.
+func ratio(part, whole int64) *float64 {
+	if whole == 0 {
+		return nil
+	}
+	r := float64(part) / float64(whole)
+	return &r
+}
@@ -0,0 +1,120 @@
+package matching
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR MATCHING STATS (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Misleading hit rates — weekly buckets must add up to the period totals and
+   an empty period must report "no data" rather than 0%
+2. Fake similarity averages — with no accepted merges the average is null,
+   not a zero that looks like terrible matching quality
+3. Off-by-one periods — "to" is inclusive, so the SQL upper bound is to + 1 day
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: GetMatchingStats
+- GIVEN two weeks of imports, matched positions of every kind and accepted merges
+  WHEN GetMatchingStats is called for an explicit period
+  THEN totals, hit rates and shares are computed and the SQL period is [from, to+1d)
+
+- GIVEN no imports, positions or merge decisions
+  THEN hit rates and average similarities are nil and shares are zero
+
+- GIVEN from after to
+  THEN ValidationError and no queries are made
+*/
+
+func statsDate(s string) sql.NullTime {
+	t, _ := time.Parse(time.DateOnly, s)
+	return sql.NullTime{Time: t, Valid: true}
+}
+
+func TestGetMatchingStats_AggregatesPeriod(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	from, to := statsDate("2026-09-01"), statsDate("2026-09-14")
+	period := db.ListImportCacheStatsByWeekParams{DateFrom: from.Time, DateTo: to.Time.AddDate(0, 0, 1)}
+
+	mockStore.EXPECT().ListImportCacheStatsByWeek(gomock.Any(), period).Return([]db.ListImportCacheStatsByWeekRow{
+		{WeekStart: statsDate("2026-08-31").Time, Imports: 3, CacheHits: 60, CacheMisses: 40},
+		{WeekStart: statsDate("2026-09-07").Time, Imports: 2, CacheHits: 90, CacheMisses: 10},
+	}, nil)
+	mockStore.EXPECT().GetPositionMatchSourceStats(gomock.Any()).Return(db.GetPositionMatchSourceStatsRow{
+		Unmatched: 10, Manual: 5, Rag: 25, Cache: 60,
+	}, nil)
+	mockStore.EXPECT().CountMatchingCorrections(gomock.Any(), db.CountMatchingCorrectionsParams{
+		DateFrom: period.DateFrom, DateTo: period.DateTo,
+	}).Return(int64(4), nil)
+	mockStore.EXPECT().GetMergeDecisionStats(gomock.Any(), db.GetMergeDecisionStatsParams{
+		DateFrom: period.DateFrom, DateTo: period.DateTo,
+	}).Return(db.GetMergeDecisionStatsRow{Accepted: 8, AcceptedAvgSimilarity: 0.93, Pending: 12}, nil)
+	mockStore.EXPECT().GetUnmatchedBacklogAge(gomock.Any()).Return(db.GetUnmatchedBacklogAgeRow{
+		Positions: 10, P50Seconds: 3600, P90Seconds: 86400, P99Seconds: 172800, MaxSeconds: 200000,
+	}, nil)
+
+	resp, err := service.GetMatchingStats(context.Background(), StatsQuery{From: from, To: to})
+
+	require.NoError(t, err)
+	assert.Equal(t, "2026-09-01", resp.From)
+	assert.Equal(t, "2026-09-14", resp.To)
+	assert.Equal(t, int64(5), resp.Cache.Imports)
+	assert.Equal(t, int64(150), resp.Cache.CacheHits)
+	require.NotNil(t, resp.Cache.HitRate)
+	assert.InDelta(t, 0.75, *resp.Cache.HitRate, 1e-9)
+	require.Len(t, resp.Weekly, 2)
+	assert.InDelta(t, 0.6, *resp.Weekly[0].HitRate, 1e-9)
+	assert.Equal(t, int64(100), resp.Positions.Total)
+	assert.InDelta(t, 0.85, resp.Positions.AutoShare, 1e-9)
+	assert.InDelta(t, 0.05, resp.Positions.ManualShare, 1e-9)
+	assert.InDelta(t, 0.10, resp.Positions.UnmatchedShare, 1e-9)
+	assert.Equal(t, int64(4), resp.Corrections)
+	require.NotNil(t, resp.Merges.AcceptedAvgSimilarity)
+	assert.InDelta(t, 0.93, *resp.Merges.AcceptedAvgSimilarity, 1e-9)
+	assert.Nil(t, resp.Merges.RejectedAvgSimilarity)
+	assert.Equal(t, 86400.0, resp.Backlog.P90Seconds)
+}
+
+func TestGetMatchingStats_EmptyData(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ListImportCacheStatsByWeek(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockStore.EXPECT().GetPositionMatchSourceStats(gomock.Any()).Return(db.GetPositionMatchSourceStatsRow{}, nil)
+	mockStore.EXPECT().CountMatchingCorrections(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+	mockStore.EXPECT().GetMergeDecisionStats(gomock.Any(), gomock.Any()).Return(db.GetMergeDecisionStatsRow{}, nil)
+	mockStore.EXPECT().GetUnmatchedBacklogAge(gomock.Any()).Return(db.GetUnmatchedBacklogAgeRow{}, nil)
+
+	resp, err := service.GetMatchingStats(context.Background(), StatsQuery{})
+
+	require.NoError(t, err)
+	assert.Nil(t, resp.Cache.HitRate)
+	assert.NotNil(t, resp.Weekly)
+	assert.Empty(t, resp.Weekly)
+	assert.Zero(t, resp.Positions.AutoShare)
+	assert.Nil(t, resp.Merges.AcceptedAvgSimilarity)
+	assert.Nil(t, resp.Merges.RejectedAvgSimilarity)
+}
+
+func TestGetMatchingStats_FromAfterTo(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.GetMatchingStats(context.Background(), StatsQuery{From: statsDate("2026-09-15"), To: statsDate("2026-09-01")})
+
+	var validationErr *apierrors.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+}
@@ -0,0 +1,73 @@
+package matching
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+// UnmatchPosition обрабатывает POST /api/v1/positions/:id/unmatch.
+//
+// В одной транзакции снимает с позиции catalog_position_id, закрепление и аренду
+// (позиция снова попадает в GetUnmatchedPositions и ClaimUnmatchedPositions) и
+// удаляет запись matching_cache, по которой позиция получила сопоставление, —
+// иначе следующий импорт того же названия повторит ошибку. Записи кэша, уже
+// перенаправленной на другую позицию каталога, удаление не касается.
+func (s *MatchingService) UnmatchPosition(
+	ctx context.Context,
+	positionItemID int64,
+	unmatchedBy int64,
+) (*api_models.UnmatchPositionResponse, error) {
+	var resp *api_models.UnmatchPositionResponse
+	err := s.store.ExecTx(ctx, func(qtx *db.Queries) error {
+		position, err := lockPositionForCorrection(ctx, qtx, positionItemID)
+		if err != nil {
+			return err
+		}
+		if !position.CatalogPositionID.Valid {
+			return apierrors.NewValidationError("matching.position_not_matched", positionItemID)
+		}
+		resp = &api_models.UnmatchPositionResponse{
+			PositionItemID:            positionItemID,
+			PreviousCatalogPositionID: position.CatalogPositionID.Int64,
+			PreviousMatchedBy:         position.MatchedBy.String,
+		}
+
+		cached, err := qtx.FindMatchingCacheForPosition(ctx, db.FindMatchingCacheForPositionParams{
+			JobTitleText:      position.JobTitleInProposal,
+			CatalogPositionID: position.CatalogPositionID.Int64,
+		})
+		switch {
+		case err == nil:
+			deleted, err := qtx.DeleteMatchingCacheEntry(ctx, db.DeleteMatchingCacheEntryParams{
+				JobTitleHash:      cached.JobTitleHash,
+				NormVersion:       cached.NormVersion,
+				CatalogPositionID: position.CatalogPositionID.Int64,
+			})
+			if err != nil {
+				return fmt.Errorf("ошибка удаления записи matching_cache: %w", err)
+			}
+			resp.CacheInvalidated = deleted > 0
+		case !errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("ошибка чтения matching_cache: %w", err)
+		}
+
+		if err := qtx.ClearCatalogPositionID(ctx, positionItemID); err != nil {
+			return fmt.Errorf("ошибка обновления position_items: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Errorf("Ошибка UnmatchPosition (позиция %d): %v", positionItemID, err)
+		return nil, err
+	}
+
+	s.logger.Infof("Пользователь %d отменил сопоставление позиции %d с позицией каталога %d (кэш удалён: %t)",
+		unmatchedBy, positionItemID, resp.PreviousCatalogPositionID, resp.CacheInvalidated)
+	return resp, nil
+}
@@ -0,0 +1,114 @@
+package matching
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR UNMATCH (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Permanent wrong matches — an analyst must be able to undo a catalog
+   assignment without touching the DB
+2. Re-imports repeating the mistake — the matching_cache entry behind the wrong
+   match must be removed in the same transaction
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: UnmatchPosition
+- GIVEN a position matched through a matching_cache entry
+  WHEN UnmatchPosition is called
+  THEN the cache entry is deleted and the position returns to the unmatched queue
+
+- GIVEN a matched position without a cache entry
+  THEN only the position is cleared and cache_invalidated is false
+
+- GIVEN a position without catalog_position_id
+  THEN ValidationError and nothing is changed
+*/
+
+func TestUnmatchPosition_DeletesCacheEntryAndRequeues(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	now := time.Now()
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("FOR UPDATE").
+				WithArgs(int64(100)).
+				WillReturnRows(sqlmock.NewRows(lockedPositionColumns).
+					AddRow(int64(100), sql.NullInt64{Int64: 77, Valid: true}, sql.NullString{String: "worker-a", Valid: true}, "Устройство свай", false))
+			mock.ExpectQuery("FROM matching_cache").
+				WithArgs("Устройство свай", int64(77)).
+				WillReturnRows(sqlmock.NewRows(matchingCacheColumns).
+					AddRow("lemma-hash", int16(2), sql.NullString{String: "Устройство свай", Valid: true}, int64(77), now, sql.NullTime{}, "rag"))
+			mock.ExpectExec("DELETE FROM matching_cache").
+				WithArgs("lemma-hash", int16(2), int64(77)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec("UPDATE position_items").
+				WithArgs(int64(100)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}),
+	)
+
+	resp, err := service.UnmatchPosition(context.Background(), 100, 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(77), resp.PreviousCatalogPositionID)
+	assert.Equal(t, "worker-a", resp.PreviousMatchedBy)
+	assert.True(t, resp.CacheInvalidated)
+}
+
+func TestUnmatchPosition_WithoutCacheEntry(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("FOR UPDATE").
+				WithArgs(int64(100)).
+				WillReturnRows(sqlmock.NewRows(lockedPositionColumns).
+					AddRow(int64(100), sql.NullInt64{Int64: 77, Valid: true}, sql.NullString{}, "Устройство свай", false))
+			mock.ExpectQuery("FROM matching_cache").
+				WithArgs("Устройство свай", int64(77)).
+				WillReturnError(sql.ErrNoRows)
+			mock.ExpectExec("UPDATE position_items").
+				WithArgs(int64(100)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}),
+	)
+
+	resp, err := service.UnmatchPosition(context.Background(), 100, 7)
+
+	require.NoError(t, err)
+	assert.False(t, resp.CacheInvalidated)
+}
+
+func TestUnmatchPosition_NotMatched(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("FOR UPDATE").
+				WithArgs(int64(100)).
+				WillReturnRows(sqlmock.NewRows(lockedPositionColumns).
+					AddRow(int64(100), sql.NullInt64{}, sql.NullString{}, "Устройство свай", false))
+		}),
+	)
+
+	_, err := service.UnmatchPosition(context.Background(), 100, 7)
+
+	var validationErr *apierrors.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+}
@@ -0,0 +1,106 @@
+// Package notifications отправляет служебные письма: приглашение нового
+// пользователя, ссылку сброса пароля, регулярные отчеты и оповещения об
+// ошибках импорта.
+//
+// Письма собираются из шаблонов (templates.go) и уходят через Mailer.
+// Реализация выбирается настройкой mail.host:
+//
+//   - пусто — NoopMailer, письма только пишутся в лог (по умолчанию);
+//   - задан — SMTP (cmd/pkg/mailer) с повторами при временных ошибках.
+//
+// Повтор выполняется при сетевых ошибках и ответах сервера 4xx с паузой
+// mail.retry_base_delay, удваивающейся до mail.retry_max_delay. Ответы 5xx
+// и некорректные письма (mailer.ErrInvalidMessage) не повторяются.
+package notifications
+
+import (
+	"context"
+	"errors"
+	"net/textproto"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/mailer"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/retry"
+)
+
+// Mailer отправляет письма. Реализации безопасны для параллельного использования.
+type Mailer interface {
+	Send(ctx context.Context, msg mailer.Message) error
+}
+
+// NewMailer создаёт Mailer по настройкам mail.
+func NewMailer(cfg config.MailConfig, logger logging.Logger) Mailer {
+	if !cfg.Enabled() {
+		logger.Info("Почта отключена (mail.host не задан): письма только пишутся в лог")
+		return NoopMailer{logger: logger}
+	}
+
+	smtp := mailer.NewSMTPSender(mailer.SMTPConfig{
+		Host:     cfg.Host,
+		Port:     cfg.Port,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		Timeout:  cfg.Timeout,
+	})
+	return newRetryingMailer(smtp, cfg, logger.WithField("smtp_host", cfg.Host))
+}
+
+// NoopMailer — Mailer без SMTP-сервера: письмо не отправляется, в лог
+// пишутся только тема и получатели.
+type NoopMailer struct {
+	logger logging.Logger
+}
+
+func (m NoopMailer) Send(_ context.Context, msg mailer.Message) error {
+	if m.logger != nil {
+		m.logger.Infof("Почта отключена: письмо %q для %v не отправлено", msg.Subject, msg.To)
+	}
+	return nil
+}
+
+// retryingMailer повторяет отправку при временных ошибках.
+type retryingMailer struct {
+	next   Mailer
+	cfg    config.MailConfig
+	logger logging.Logger
+	sleep  func(ctx context.Context, d time.Duration) error // Подменяется в тестах
+}
+
+func newRetryingMailer(next Mailer, cfg config.MailConfig, logger logging.Logger) *retryingMailer {
+	return &retryingMailer{next: next, cfg: cfg, logger: logger, sleep: retry.Sleep}
+}
+
+// Send делает до mail.max_attempts попыток. Возвращается ошибка последней попытки.
+func (m *retryingMailer) Send(ctx context.Context, msg mailer.Message) error {
+	for attempt := 1; ; attempt++ {
+		err := m.next.Send(ctx, msg)
+		if err == nil || isPermanent(err) || attempt >= m.cfg.MaxAttempts {
+			return err
+		}
+
+		delay := m.backoff(attempt)
+		m.logger.Warnf("Письмо %q не отправлено (попытка %d из %d), повтор через %s: %v",
+			msg.Subject, attempt, m.cfg.MaxAttempts, delay, err)
+		if m.sleep(ctx, delay) != nil {
+			return err
+		}
+	}
+}
+
+// backoff возвращает задержку перед повтором после attempt-й неуспешной попытки
+// (retry.Backoff от RetryBaseDelay до RetryMaxDelay).
+func (m *retryingMailer) backoff(attempt int) time.Duration {
+	return retry.Backoff(attempt, m.cfg.RetryBaseDelay, m.cfg.RetryMaxDelay)
+}
+
+// isPermanent сообщает, что повтор не поможет: письмо некорректно или
+// сервер отказал окончательно (5xx — неверный пароль, нет ящика получателя).
+func isPermanent(err error) bool {
+	if errors.Is(err, mailer.ErrInvalidMessage) {
+		return true
+	}
+	var smtpErr *textproto.Error
+	return errors.As(err, &smtpErr) && smtpErr.Code >= 500
+}
@@ -0,0 +1,177 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/textproto"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/mailer"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR MAIL DELIVERY (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Lost letters — a short SMTP outage must not drop an invitation or a report
+2. Hammering — a rejected password or mailbox must not be retried
+3. Broken dev setups — without an SMTP server the API must still start and work
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Retries
+- GIVEN a server failing twice with a network error, then accepting
+  WHEN Send is called with max_attempts 3
+  THEN the letter is sent on the third attempt after 2s and 4s pauses
+- GIVEN a server failing every time
+  THEN Send returns the last error after max_attempts attempts
+- GIVEN a 5xx reply or an invalid message
+  THEN Send returns at once, without retries
+- GIVEN ctx cancelled during the pause
+  THEN Send stops and returns the last send error
+
+SCENARIO 2: backoff
+- GIVEN base 2s and max 5s
+  THEN pauses are 2s, 4s, 5s, 5s
+
+SCENARIO 3: NewMailer
+- GIVEN no mail.host
+  THEN NoopMailer is returned and Send succeeds without a server
+*/
+
+// fakeMailer запоминает письма и возвращает ошибки из errs по порядку.
+type fakeMailer struct {
+	mu       sync.Mutex
+	sent     []mailer.Message
+	attempts int
+	errs     []error
+}
+
+func (f *fakeMailer) Send(_ context.Context, msg mailer.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attempts++
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		if err != nil {
+			return err
+		}
+	}
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+func (f *fakeMailer) messages() []mailer.Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]mailer.Message(nil), f.sent...)
+}
+
+func testMailConfig() config.MailConfig {
+	return config.MailConfig{
+		Host:           "smtp.example.com",
+		Port:           587,
+		From:           "Тендеры <noreply@example.com>",
+		Timeout:        time.Second,
+		MaxAttempts:    3,
+		RetryBaseDelay: 2 * time.Second,
+		RetryMaxDelay:  30 * time.Second,
+	}
+}
+
+// newTestRetryingMailer записывает паузы вместо ожидания.
+func newTestRetryingMailer(next Mailer, cfg config.MailConfig) (*retryingMailer, *[]time.Duration) {
+	var pauses []time.Duration
+	m := newRetryingMailer(next, cfg, testutil.NewMockLogger())
+	m.sleep = func(ctx context.Context, d time.Duration) error {
+		pauses = append(pauses, d)
+		return ctx.Err()
+	}
+	return m, &pauses
+}
+
+var testMessage = mailer.Message{From: "noreply@example.com", To: []string{"a@example.com"}, Subject: "Тест"}
+
+func TestRetryingMailer_RetriesTemporaryErrors(t *testing.T) {
+	next := &fakeMailer{errs: []error{
+		errors.New("подключение к SMTP: connection refused"),
+		fmt.Errorf("SMTP MAIL FROM: %w", &textproto.Error{Code: 421, Msg: "try again later"}),
+	}}
+	m, pauses := newTestRetryingMailer(next, testMailConfig())
+
+	require.NoError(t, m.Send(context.Background(), testMessage))
+	assert.Equal(t, 3, next.attempts)
+	assert.Len(t, next.messages(), 1)
+	assert.Equal(t, []time.Duration{2 * time.Second, 4 * time.Second}, *pauses)
+}
+
+func TestRetryingMailer_GivesUpAfterMaxAttempts(t *testing.T) {
+	refused := errors.New("connection refused")
+	next := &fakeMailer{errs: []error{refused, refused, refused, nil}}
+	m, pauses := newTestRetryingMailer(next, testMailConfig())
+
+	err := m.Send(context.Background(), testMessage)
+	assert.ErrorIs(t, err, refused)
+	assert.Equal(t, 3, next.attempts)
+	assert.Len(t, *pauses, 2)
+}
+
+func TestRetryingMailer_PermanentErrors(t *testing.T) {
+	cases := map[string]error{
+		"auth rejected":   fmt.Errorf("SMTP AUTH: %w", &textproto.Error{Code: 535, Msg: "bad credentials"}),
+		"no mailbox":      fmt.Errorf("SMTP RCPT TO: %w", &textproto.Error{Code: 550, Msg: "mailbox unavailable"}),
+		"invalid message": fmt.Errorf("%w: не указаны получатели", mailer.ErrInvalidMessage),
+	}
+	for name, sendErr := range cases {
+		t.Run(name, func(t *testing.T) {
+			next := &fakeMailer{errs: []error{sendErr}}
+			m, pauses := newTestRetryingMailer(next, testMailConfig())
+
+			assert.ErrorIs(t, m.Send(context.Background(), testMessage), sendErr)
+			assert.Equal(t, 1, next.attempts)
+			assert.Empty(t, *pauses)
+		})
+	}
+}
+
+func TestRetryingMailer_StopsOnContextCancel(t *testing.T) {
+	refused := errors.New("connection refused")
+	next := &fakeMailer{errs: []error{refused, refused}}
+	m, _ := newTestRetryingMailer(next, testMailConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, m.Send(ctx, testMessage), refused)
+	assert.Equal(t, 1, next.attempts)
+}
+
+func TestRetryingMailer_Backoff(t *testing.T) {
+	cfg := testMailConfig()
+	cfg.RetryMaxDelay = 5 * time.Second
+	m := newRetryingMailer(nil, cfg, testutil.NewMockLogger())
+
+	assert.Equal(t, 2*time.Second, m.backoff(1))
+	assert.Equal(t, 4*time.Second, m.backoff(2))
+	assert.Equal(t, 5*time.Second, m.backoff(3))
+	assert.Equal(t, 5*time.Second, m.backoff(10))
+}
+
+func TestNewMailer_NoopWithoutHost(t *testing.T) {
+	m := NewMailer(config.MailConfig{}, testutil.NewMockLogger())
+	require.IsType(t, NoopMailer{}, m)
+	assert.NoError(t, m.Send(context.Background(), testMessage))
+
+	_, isRetrying := NewMailer(testMailConfig(), testutil.NewMockLogger()).(*retryingMailer)
+	assert.True(t, isRetrying)
+}
@@ -0,0 +1,208 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/mailer"
+)
+
+// maxErrorLength ограничивает текст ошибки в оповещении об импорте.
+const maxErrorLength = 2000
+
+// Service формирует служебные письма по шаблонам и отправляет их через Mailer.
+//
+// SendReport синхронный: вызывающий код записывает результат отправки.
+// Notify* не ждут отправки и не возвращают ошибку: письмо уходит в фоне,
+// неудача только логируется, поэтому недоступность SMTP не замедляет запросы.
+type Service struct {
+	mailer Mailer
+	from   string
+	cfg    config.NotificationsConfig
+	logger logging.Logger
+	now    func() time.Time // Подменяется в тестах
+
+	mu sync.Mutex
+	// Время последнего оповещения об ошибке импорта по ID тендера на ЭТП
+	lastImportAlert map[string]time.Time
+
+	pending sync.WaitGroup
+}
+
+// NewService создаёт сервис уведомлений. Отправитель — mail.from.
+func NewService(m Mailer, mailCfg config.MailConfig, cfg config.NotificationsConfig, logger logging.Logger) *Service {
+	return &Service{
+		mailer:          m,
+		from:            mailCfg.From,
+		cfg:             cfg,
+		logger:          logger,
+		now:             time.Now,
+		lastImportAlert: make(map[string]time.Time),
+	}
+}
+
+// Invitation — данные письма новому пользователю.
+type Invitation struct {
+	Email string
+	Role  string
+}
+
+// PasswordReset — данные письма со ссылкой сброса пароля.
+type PasswordReset struct {
+	Email     string
+	Token     string
+	ExpiresAt time.Time
+}
+
+// Report — сводка регулярного отчета для текста письма.
+type Report struct {
+	Title  string
+	Period string // «за 03.03.2025–09.03.2025»
+	Tables []ReportTable
+}
+
+// ReportTable — таблица отчета и число ее строк.
+type ReportTable struct {
+	Title string
+	Rows  int
+}
+
+// ImportFailure — неудачный импорт тендера.
+type ImportFailure struct {
+	TenderID   string // ID тендера на ЭТП
+	ImportID   int64  // ID трассировки импорта; 0 — не сохранена
+	Error      string
+	OccurredAt time.Time
+}
+
+// SendReport отправляет регулярный отчет с вложением и возвращает ошибку отправки.
+func (s *Service) SendReport(ctx context.Context, to []string, report Report, attachment mailer.Attachment) error {
+	subject, body, err := reportTemplate.render(report)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, mailer.Message{
+		From:        s.from,
+		To:          to,
+		Subject:     subject,
+		Body:        body,
+		Attachments: []mailer.Attachment{attachment},
+	})
+}
+
+// NotifyInvitation отправляет новому пользователю адрес входа в систему.
+func (s *Service) NotifyInvitation(inv Invitation) {
+	s.dispatch("invitation", []string{inv.Email}, invitationTemplate, struct {
+		Invitation
+		LoginURL string
+	}{inv, s.link("/login", nil)})
+}
+
+// NotifyPasswordReset отправляет пользователю одноразовую ссылку сброса пароля.
+func (s *Service) NotifyPasswordReset(reset PasswordReset) {
+	s.dispatch("password_reset", []string{reset.Email}, passwordResetTemplate, struct {
+		PasswordReset
+		ResetURL string
+	}{reset, s.link("/reset-password", url.Values{"token": {reset.Token}})})
+}
+
+// NotifyImportFailure оповещает notifications.import_failure_recipients о
+// неудачном импорте. Повторные ошибки того же тендера в пределах
+// notifications.import_failure_cooldown пропускаются, чтобы воркер, повторяющий
+// импорт, не засыпал адресатов одинаковыми письмами.
+func (s *Service) NotifyImportFailure(failure ImportFailure) {
+	if len(s.cfg.ImportFailureRecipients) == 0 {
+		return
+	}
+	if failure.OccurredAt.IsZero() {
+		failure.OccurredAt = s.now()
+	}
+	if !s.allowImportAlert(failure.TenderID, failure.OccurredAt) {
+		s.logger.Debugf("Оповещение об ошибке импорта тендера %s пропущено: недавно уже отправлялось", failure.TenderID)
+		return
+	}
+	failure.Error = truncateError(failure.Error)
+
+	s.dispatch("import_failure", s.cfg.ImportFailureRecipients, importFailureTemplate, struct {
+		ImportFailure
+		Cooldown time.Duration
+	}{failure, s.cfg.ImportFailureCooldown})
+}
+
+// allowImportAlert отмечает оповещение по тендеру, если с прошлого прошло
+// больше cooldown. Устаревшие отметки удаляются, чтобы карта не росла.
+func (s *Service) allowImportAlert(tenderID string, at time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, last := range s.lastImportAlert {
+		if at.Sub(last) >= s.cfg.ImportFailureCooldown {
+			delete(s.lastImportAlert, id)
+		}
+	}
+	if _, recent := s.lastImportAlert[tenderID]; recent {
+		return false
+	}
+	if s.cfg.ImportFailureCooldown > 0 {
+		s.lastImportAlert[tenderID] = at
+	}
+	return true
+}
+
+// dispatch формирует письмо и отправляет его в фоновой горутине.
+func (s *Service) dispatch(kind string, to []string, tmpl emailTemplate, data any) {
+	subject, body, err := tmpl.render(data)
+	if err != nil {
+		s.logger.Errorf("Письмо %s не сформировано: %v", kind, err)
+		return
+	}
+	msg := mailer.Message{From: s.from, To: to, Subject: subject, Body: body}
+
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+		if err := s.mailer.Send(context.Background(), msg); err != nil {
+			s.logger.Errorf("Письмо %s для %v не отправлено: %v", kind, to, err)
+			return
+		}
+		s.logger.Infof("Письмо %s отправлено: %v", kind, to)
+	}()
+}
+
+// Wait ждёт отправки писем, поставленных Notify*, но не дольше ctx.
+// Вызывается при остановке сервера и в тестах.
+func (s *Service) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("не дождались отправки писем: %w", ctx.Err())
+	}
+}
+
+// link возвращает адрес страницы веб-интерфейса (notifications.app_url).
+func (s *Service) link(path string, query url.Values) string {
+	link := strings.TrimRight(s.cfg.AppURL, "/") + path
+	if len(query) > 0 {
+		link += "?" + query.Encode()
+	}
+	return link
+}
+
+func truncateError(msg string) string {
+	if len(msg) <= maxErrorLength {
+		return msg
+	}
+	return strings.ToValidUTF8(msg[:maxErrorLength], "") + "…"
+}
@@ -0,0 +1,204 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/mailer"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR NOTIFICATION EMAILS (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Dead links — invitation and reset links must point to the web UI (notifications.app_url)
+2. Alert storms — a worker retrying a broken tender must not send a letter per attempt
+3. Slow requests — Notify* must not wait for the SMTP server
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Invitation and password reset
+- GIVEN app_url with a trailing slash
+  WHEN NotifyInvitation / NotifyPasswordReset is called
+  THEN a letter is sent to the user from mail.from
+  AND it contains the login link / the reset link with the escaped token and its expiry
+
+SCENARIO 2: SendReport
+- GIVEN a report summary and an attachment
+  WHEN SendReport is called
+  THEN the subject is "<title> <period>", the body lists tables with row counts
+  AND the attachment is kept; the mailer error is returned to the caller
+
+SCENARIO 3: Import failure alerts
+- GIVEN recipients and a 15 minute cooldown
+  WHEN the same tender fails twice within 15 minutes
+  THEN one alert is sent; after the cooldown the next failure is sent again
+  AND failures of other tenders are not suppressed
+- GIVEN no recipients
+  THEN nothing is sent
+- GIVEN a huge error text
+  THEN it is truncated
+
+SCENARIO 4: Delivery failures
+- GIVEN a failing mailer
+  WHEN NotifyInvitation is called
+  THEN the error is logged, not returned
+*/
+
+func testNotificationsConfig() config.NotificationsConfig {
+	return config.NotificationsConfig{
+		AppURL:                  "https://tenders.example.com/",
+		ImportFailureRecipients: []string{"ops@example.com"},
+		ImportFailureCooldown:   15 * time.Minute,
+	}
+}
+
+func setupTestService(t *testing.T, cfg config.NotificationsConfig) (*Service, *fakeMailer, *testutil.MockLogger) {
+	t.Helper()
+	m := &fakeMailer{}
+	logger := testutil.NewMockLogger()
+	return NewService(m, testMailConfig(), cfg, logger), m, logger
+}
+
+func wait(t *testing.T, s *Service) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, s.Wait(ctx))
+}
+
+func TestNotifyInvitation(t *testing.T) {
+	s, m, _ := setupTestService(t, testNotificationsConfig())
+
+	s.NotifyInvitation(Invitation{Email: "new@example.com", Role: "analyst"})
+	wait(t, s)
+
+	sent := m.messages()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "Тендеры <noreply@example.com>", sent[0].From)
+	assert.Equal(t, []string{"new@example.com"}, sent[0].To)
+	assert.Equal(t, "Приглашение в систему анализа тендеров", sent[0].Subject)
+	assert.Contains(t, sent[0].Body, "new@example.com (роль: analyst)")
+	assert.Contains(t, sent[0].Body, "https://tenders.example.com/login\n")
+}
+
+func TestNotifyPasswordReset(t *testing.T) {
+	s, m, _ := setupTestService(t, testNotificationsConfig())
+
+	s.NotifyPasswordReset(PasswordReset{
+		Email:     "user@example.com",
+		Token:     "ab+cd",
+		ExpiresAt: time.Date(2025, 3, 10, 12, 30, 0, 0, time.UTC),
+	})
+	wait(t, s)
+
+	sent := m.messages()
+	require.Len(t, sent, 1)
+	assert.Equal(t, []string{"user@example.com"}, sent[0].To)
+	assert.Equal(t, "Сброс пароля", sent[0].Subject)
+	assert.Contains(t, sent[0].Body, "https://tenders.example.com/reset-password?token=ab%2Bcd\n")
+	assert.Contains(t, sent[0].Body, "до 10.03.2025 12:30 UTC")
+}
+
+func TestSendReport(t *testing.T) {
+	s, m, _ := setupTestService(t, testNotificationsConfig())
+	attachment := mailer.Attachment{Filename: "report.xlsx", ContentType: "application/zip", Data: []byte("PK")}
+
+	err := s.SendReport(context.Background(), []string{"a@example.com", "b@example.com"}, Report{
+		Title:  "Экономия",
+		Period: "за 01.03.2025–31.03.2025",
+		Tables: []ReportTable{{Title: "Тендеры", Rows: 12}, {Title: "Итого", Rows: 1}},
+	}, attachment)
+	require.NoError(t, err)
+
+	sent := m.messages()
+	require.Len(t, sent, 1)
+	assert.Equal(t, []string{"a@example.com", "b@example.com"}, sent[0].To)
+	assert.Equal(t, "Экономия за 01.03.2025–31.03.2025", sent[0].Subject)
+	assert.Contains(t, sent[0].Body, "Тендеры: 12\nИтого: 1\n")
+	assert.Equal(t, []mailer.Attachment{attachment}, sent[0].Attachments)
+
+	m.errs = []error{errors.New("connection refused")}
+	err = s.SendReport(context.Background(), []string{"a@example.com"}, Report{Title: "Экономия"}, attachment)
+	assert.EqualError(t, err, "connection refused")
+}
+
+func TestNotifyImportFailure_Cooldown(t *testing.T) {
+	s, m, _ := setupTestService(t, testNotificationsConfig())
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	s.NotifyImportFailure(ImportFailure{TenderID: "T-1", ImportID: 40, Error: "deadlock detected"})
+	now = now.Add(5 * time.Minute)
+	s.NotifyImportFailure(ImportFailure{TenderID: "T-1", ImportID: 41, Error: "deadlock detected"})
+	s.NotifyImportFailure(ImportFailure{TenderID: "T-2", Error: "connection reset"})
+	wait(t, s)
+
+	sent := m.messages()
+	require.Len(t, sent, 2)
+	subjects := []string{sent[0].Subject, sent[1].Subject}
+	assert.ElementsMatch(t, []string{"Ошибка импорта тендера T-1", "Ошибка импорта тендера T-2"}, subjects)
+	for _, msg := range sent {
+		assert.Equal(t, []string{"ops@example.com"}, msg.To)
+		if strings.HasSuffix(msg.Subject, "T-1") {
+			assert.Contains(t, msg.Body, "(import_id=40)")
+			assert.Contains(t, msg.Body, "Время: 10.03.2025 09:00:00 UTC")
+			assert.Contains(t, msg.Body, "Ошибка: deadlock detected")
+		}
+	}
+
+	// THEN: после cooldown ошибка того же тендера снова присылается
+	now = now.Add(15 * time.Minute)
+	s.NotifyImportFailure(ImportFailure{TenderID: "T-1", ImportID: 42, Error: "deadlock detected"})
+	wait(t, s)
+	assert.Len(t, m.messages(), 3)
+}
+
+func TestNotifyImportFailure_NoRecipients(t *testing.T) {
+	cfg := testNotificationsConfig()
+	cfg.ImportFailureRecipients = nil
+	s, m, _ := setupTestService(t, cfg)
+
+	s.NotifyImportFailure(ImportFailure{TenderID: "T-1", Error: "boom"})
+	wait(t, s)
+	assert.Empty(t, m.messages())
+}
+
+func TestNotifyImportFailure_TruncatesError(t *testing.T) {
+	s, m, _ := setupTestService(t, testNotificationsConfig())
+
+	s.NotifyImportFailure(ImportFailure{TenderID: "T-1", Error: strings.Repeat("я", maxErrorLength)})
+	wait(t, s)
+
+	sent := m.messages()
+	require.Len(t, sent, 1)
+	assert.Less(t, len(sent[0].Body), maxErrorLength+500)
+	assert.Contains(t, sent[0].Body, "…")
+}
+
+func TestNotify_DeliveryFailureIsLogged(t *testing.T) {
+	s, m, logger := setupTestService(t, testNotificationsConfig())
+	m.errs = []error{errors.New("connection refused")}
+
+	s.NotifyInvitation(Invitation{Email: "new@example.com", Role: "analyst"})
+	wait(t, s)
+
+	assert.Empty(t, m.messages())
+	var logged bool
+	for _, r := range logger.Records() {
+		if r.Level == testutil.LevelError && strings.Contains(r.Message, "connection refused") {
+			logged = true
+		}
+	}
+	assert.True(t, logged, "ошибка отправки записана в лог")
+}
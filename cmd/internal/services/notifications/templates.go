@@ -0,0 +1,78 @@
+package notifications
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// emailTemplate — тема и текст письма одного вида. Шаблоны text/template:
+// письма уходят обычным текстом, экранирование HTML не нужно.
+type emailTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+func mustTemplate(name, subject, body string) emailTemplate {
+	return emailTemplate{
+		subject: template.Must(template.New(name + "_subject").Parse(subject)),
+		body:    template.Must(template.New(name + "_body").Parse(body)),
+	}
+}
+
+// render возвращает тему и текст письма.
+func (t emailTemplate) render(data any) (string, string, error) {
+	var subject, body bytes.Buffer
+	if err := t.subject.Execute(&subject, data); err != nil {
+		return "", "", fmt.Errorf("ошибка шаблона %s: %w", t.subject.Name(), err)
+	}
+	if err := t.body.Execute(&body, data); err != nil {
+		return "", "", fmt.Errorf("ошибка шаблона %s: %w", t.body.Name(), err)
+	}
+	return subject.String(), body.String(), nil
+}
+
+var invitationTemplate = mustTemplate("invitation",
+	`Приглашение в систему анализа тендеров`,
+	`Здравствуйте!
+
+Для вас создана учетная запись {{.Email}} (роль: {{.Role}}).
+Войти: {{.LoginURL}}
+
+Пароль сообщит администратор. Если вы не ожидали этого письма, просто проигнорируйте его.
+
+Письмо сформировано автоматически.
+`)
+
+var passwordResetTemplate = mustTemplate("password_reset",
+	`Сброс пароля`,
+	`Здравствуйте!
+
+Администратор запросил сброс пароля для учетной записи {{.Email}}.
+Задать новый пароль: {{.ResetURL}}
+
+Ссылка одноразовая и действует до {{.ExpiresAt.UTC.Format "02.01.2006 15:04"}} UTC.
+Если вы не ожидали этого письма, сообщите администратору.
+
+Письмо сформировано автоматически.
+`)
+
+var reportTemplate = mustTemplate("report",
+	`{{.Title}} {{.Period}}`,
+	`{{.Title}} {{.Period}}.
+
+{{range .Tables}}{{.Title}}: {{.Rows}}
+{{end}}
+Отчет во вложении. Письмо сформировано автоматически.
+`)
+
+var importFailureTemplate = mustTemplate("import_failure",
+	`Ошибка импорта тендера {{.TenderID}}`,
+	`Импорт тендера {{.TenderID}} завершился ошибкой{{if .ImportID}} (import_id={{.ImportID}}){{end}}.
+
+Время: {{.OccurredAt.UTC.Format "02.01.2006 15:04:05"}} UTC
+Ошибка: {{.Error}}
+{{if .Cooldown}}
+Повторные ошибки этого тендера в ближайшие {{.Cooldown}} не присылаются.{{end}}
+Письмо сформировано автоматически.
+`)
@@ -0,0 +1,293 @@
+// Package outbound — HTTP-клиент для запросов API к внутренним сервисам
+// (парсер XLSX): повторы, circuit breaker и счётчики на каждый адрес
+// назначения (host:port из URL запроса).
+//
+// Повторяются только идемпотентные запросы (GET, HEAD, OPTIONS, PUT, DELETE),
+// тело которых можно прочитать заново, — при сетевых ошибках и ответах
+// 502/503/504. Пауза перед повтором — outbound_http.retry_base_delay,
+// удваивается до retry_max_delay. Загрузка файла (POST с потоковым телом)
+// не повторяется.
+//
+// Breaker размыкается после outbound_http.breaker_failure_threshold
+// неуспешных запросов подряд (сетевая ошибка или ответ 5xx): запросы к адресу
+// сразу завершаются ErrCircuitOpen, не нагружая упавший сервис. Через
+// breaker_open_timeout пропускается один пробный запрос; успех замыкает
+// breaker, ошибка размыкает снова. Отмена запроса клиентом API ошибкой
+// сервиса не считается.
+package outbound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/retry"
+)
+
+// ErrCircuitOpen — адрес назначения считается недоступным, запрос не отправлялся.
+var ErrCircuitOpen = errors.New("circuit breaker разомкнут: сервис временно недоступен")
+
+// Состояния breaker.
+const (
+	StateClosed   = "closed"    // Запросы проходят
+	StateOpen     = "open"      // Запросы отклоняются до breaker_open_timeout
+	StateHalfOpen = "half_open" // Выполняется пробный запрос
+)
+
+// drainLimit — сколько байт ответа дочитывается перед повтором, чтобы
+// соединение вернулось в пул.
+const drainLimit = 64 << 10
+
+// destination — состояние breaker и счётчики одного адреса.
+type destination struct {
+	state               string
+	consecutiveFailures int
+	openedAt            time.Time
+	probing             bool // Пробный запрос в полуоткрытом состоянии уже выполняется
+
+	requests, failures, retries, rejected int64
+	lastError                             string
+	lastErrorAt                           time.Time
+}
+
+// Client выполняет запросы с повторами и circuit breaker. Безопасен для
+// параллельного использования.
+type Client struct {
+	http   *http.Client
+	cfg    config.OutboundHTTPConfig
+	logger logging.Logger
+	now    func() time.Time                                 // Подменяется в тестах
+	sleep  func(ctx context.Context, d time.Duration) error // Подменяется в тестах
+
+	mu           sync.Mutex
+	destinations map[string]*destination
+}
+
+// NewClient создаёт клиент по настройкам outbound_http. Общего таймаута у
+// клиента нет: время запроса ограничивает контекст вызывающего (большой файл
+// передаётся парсеру дольше, чем длится запрос статуса).
+func NewClient(cfg config.OutboundHTTPConfig, logger logging.Logger) *Client {
+	return &Client{
+		http:         &http.Client{},
+		cfg:          cfg,
+		logger:       logger.WithField("component", "outbound"),
+		now:          time.Now,
+		sleep:        retry.Sleep,
+		destinations: make(map[string]*destination),
+	}
+}
+
+// Do выполняет запрос. Как и http.Client.Do, закрывает тело запроса, в том
+// числе когда breaker отклоняет запрос. Ответ последней попытки возвращается
+// как есть, включая 5xx; вызывающий закрывает его тело.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	attempts := 1
+	if retryable(req) {
+		attempts = c.cfg.MaxAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		if !c.allow(host) {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, fmt.Errorf("%s: %w", host, ErrCircuitOpen)
+		}
+
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				c.release(host)
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		resp, err := c.http.Do(req)
+		c.record(req.Context(), host, resp, err)
+		if attempt >= attempts || req.Context().Err() != nil || !temporary(resp, err) {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, drainLimit))
+			resp.Body.Close()
+		}
+		delay := c.backoff(attempt)
+		c.countRetry(host)
+		c.logger.Warnf("%s %s не выполнен (попытка %d из %d), повтор через %s: %s",
+			req.Method, req.URL.Redacted(), attempt, attempts, delay, describe(resp, err))
+		if sleepErr := c.sleep(req.Context(), delay); sleepErr != nil {
+			return nil, sleepErr
+		}
+	}
+}
+
+// Stats возвращает состояние breaker и счётчики каждого адреса с момента
+// запуска этого экземпляра API.
+func (c *Client) Stats() api_models.OutboundHTTPStatsResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	response := api_models.OutboundHTTPStatsResponse{
+		Destinations: make([]api_models.OutboundDestinationStats, 0, len(c.destinations)),
+	}
+	for host, d := range c.destinations {
+		stats := api_models.OutboundDestinationStats{
+			Destination:         host,
+			State:               d.state,
+			ConsecutiveFailures: d.consecutiveFailures,
+			Requests:            d.requests,
+			Failures:            d.failures,
+			Retries:             d.retries,
+			Rejected:            d.rejected,
+			LastError:           d.lastError,
+		}
+		if d.state != StateClosed {
+			openedAt := d.openedAt
+			stats.OpenedAt = &openedAt
+		}
+		if !d.lastErrorAt.IsZero() {
+			lastErrorAt := d.lastErrorAt
+			stats.LastErrorAt = &lastErrorAt
+		}
+		response.Destinations = append(response.Destinations, stats)
+	}
+	sort.Slice(response.Destinations, func(i, j int) bool {
+		return response.Destinations[i].Destination < response.Destinations[j].Destination
+	})
+	return response
+}
+
+// allow сообщает, можно ли отправить запрос на адрес. Разомкнутый breaker
+// по истечении breaker_open_timeout пропускает один пробный запрос.
+func (c *Client) allow(host string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	d := c.destination(host)
+	switch d.state {
+	case StateOpen:
+		if c.now().Sub(d.openedAt) < c.cfg.BreakerOpenTimeout {
+			d.rejected++
+			return false
+		}
+		d.state = StateHalfOpen
+		d.probing = true
+		return true
+	case StateHalfOpen:
+		if d.probing {
+			d.rejected++
+			return false
+		}
+		d.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// record учитывает результат попытки.
+func (c *Client) record(ctx context.Context, host string, resp *http.Response, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	d := c.destination(host)
+	d.probing = false
+	if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+		// Клиент API отменил запрос — о состоянии сервиса это ничего не говорит
+		return
+	}
+	d.requests++
+
+	if err == nil && resp.StatusCode < http.StatusInternalServerError {
+		if d.state != StateClosed {
+			c.logger.Infof("Сервис %s снова доступен, circuit breaker замкнут", host)
+		}
+		d.state = StateClosed
+		d.consecutiveFailures = 0
+		return
+	}
+
+	d.failures++
+	d.consecutiveFailures++
+	d.lastError = describe(resp, err)
+	d.lastErrorAt = c.now()
+	if d.state == StateHalfOpen || (d.state == StateClosed && d.consecutiveFailures >= c.cfg.BreakerFailureThreshold) {
+		d.state = StateOpen
+		d.openedAt = c.now()
+		c.logger.Warnf("Сервис %s недоступен (%d ошибок подряд, последняя: %s), circuit breaker разомкнут на %s",
+			host, d.consecutiveFailures, d.lastError, c.cfg.BreakerOpenTimeout)
+	}
+}
+
+// release снимает пробный запрос, который так и не был отправлен.
+func (c *Client) release(host string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.destination(host).probing = false
+}
+
+func (c *Client) countRetry(host string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.destination(host).retries++
+}
+
+// destination возвращает состояние адреса, создавая его при первом запросе.
+// Вызывается под c.mu.
+func (c *Client) destination(host string) *destination {
+	d, ok := c.destinations[host]
+	if !ok {
+		d = &destination{state: StateClosed}
+		c.destinations[host] = d
+	}
+	return d
+}
+
+// backoff возвращает задержку перед повтором после attempt-й неуспешной попытки
+// (retry.Backoff от RetryBaseDelay до RetryMaxDelay).
+func (c *Client) backoff(attempt int) time.Duration {
+	return retry.Backoff(attempt, c.cfg.RetryBaseDelay, c.cfg.RetryMaxDelay)
+}
+
+// retryable сообщает, можно ли повторить запрос: метод идемпотентен, а тело
+// отсутствует или может быть получено заново.
+func retryable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// temporary сообщает, что повтор может помочь: сетевая ошибка или ответ
+// 502/503/504 (сервис перезапускается или перегружен).
+func temporary(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func describe(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("ответ %d", resp.StatusCode)
+}
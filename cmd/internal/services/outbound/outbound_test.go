@@ -0,0 +1,199 @@
+package outbound
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR OUTBOUND PARSER CALLS (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. A parser restart turns every status poll into a 502 for the user
+2. A dead parser is hammered by every request while uploads hang
+3. A file upload is sent twice to the parser, creating duplicate tasks
+4. Operators cannot see that the parser is failing until users complain
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Retries
+- GIVEN a GET answered 503 twice, then 200
+  THEN the client gets 200 after two retries with 200ms and 400ms pauses
+- GIVEN a POST answered 503
+  THEN it is not retried and the 503 is returned as is
+- GIVEN a 404
+  THEN it is not retried and does not count as a failure
+
+SCENARIO 2: Circuit breaker
+- GIVEN failures reaching breaker_failure_threshold
+  THEN further requests fail with ErrCircuitOpen without reaching the parser
+- GIVEN breaker_open_timeout passed and the probe succeeds
+  THEN the breaker closes and requests pass again
+- GIVEN the probe fails
+  THEN the breaker opens again
+
+SCENARIO 3: Stats
+- GIVEN requests to a destination
+  THEN requests, failures, retries, rejections and the state are reported per host
+*/
+
+func testConfig() config.OutboundHTTPConfig {
+	return config.OutboundHTTPConfig{
+		MaxAttempts:             3,
+		RetryBaseDelay:          200 * time.Millisecond,
+		RetryMaxDelay:           2 * time.Second,
+		BreakerFailureThreshold: 2,
+		BreakerOpenTimeout:      30 * time.Second,
+	}
+}
+
+// statusServer отвечает кодами из statuses по порядку, затем последним кодом.
+func statusServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		w.WriteHeader(statuses[min(n, len(statuses))-1])
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func newTestClient(cfg config.OutboundHTTPConfig) (*Client, *[]time.Duration) {
+	client := NewClient(cfg, testutil.NewMockLogger())
+	var pauses []time.Duration
+	client.sleep = func(_ context.Context, d time.Duration) error {
+		pauses = append(pauses, d)
+		return nil
+	}
+	return client, &pauses
+}
+
+func doRequest(t *testing.T, client *Client, method, url string) (*http.Response, error) {
+	t.Helper()
+	var body io.Reader
+	if method == http.MethodPost {
+		body = strings.NewReader("file")
+	}
+	req, err := http.NewRequest(method, url, body)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	return resp, err
+}
+
+func TestDo_RetriesIdempotentRequest(t *testing.T) {
+	server, calls := statusServer(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK)
+	cfg := testConfig()
+	cfg.BreakerFailureThreshold = 5
+	client, pauses := newTestClient(cfg)
+
+	resp, err := doRequest(t, client, http.MethodGet, server.URL+"/tasks/1/status")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, []time.Duration{200 * time.Millisecond, 400 * time.Millisecond}, *pauses)
+}
+
+func TestDo_DoesNotRetryPost(t *testing.T) {
+	server, calls := statusServer(t, http.StatusServiceUnavailable)
+	client, _ := newTestClient(testConfig())
+
+	resp, err := doRequest(t, client, http.MethodPost, server.URL+"/parse-tender/")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestDo_NotFoundIsNotFailure(t *testing.T) {
+	server, calls := statusServer(t, http.StatusNotFound)
+	client, _ := newTestClient(testConfig())
+
+	for i := 0; i < 3; i++ {
+		resp, err := doRequest(t, client, http.MethodGet, server.URL+"/tasks/1/status")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	}
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, StateClosed, client.Stats().Destinations[0].State)
+}
+
+func TestDo_BreakerOpensAndRecovers(t *testing.T) {
+	server, calls := statusServer(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK)
+	client, _ := newTestClient(testConfig())
+	now := time.Now()
+	client.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		resp, err := doRequest(t, client, http.MethodPost, server.URL+"/parse-tender/")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	}
+
+	_, err := doRequest(t, client, http.MethodGet, server.URL+"/tasks/1/status")
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	assert.Equal(t, int32(2), calls.Load(), "разомкнутый breaker не должен отправлять запрос")
+
+	now = now.Add(31 * time.Second)
+	resp, err := doRequest(t, client, http.MethodGet, server.URL+"/tasks/1/status")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	stats := client.Stats().Destinations
+	require.Len(t, stats, 1)
+	assert.Equal(t, strings.TrimPrefix(server.URL, "http://"), stats[0].Destination)
+	assert.Equal(t, StateClosed, stats[0].State)
+	assert.Equal(t, int64(3), stats[0].Requests)
+	assert.Equal(t, int64(2), stats[0].Failures)
+	assert.Equal(t, int64(1), stats[0].Rejected)
+	assert.Equal(t, "ответ 500", stats[0].LastError)
+}
+
+func TestDo_FailedProbeReopensBreaker(t *testing.T) {
+	server, calls := statusServer(t, http.StatusInternalServerError)
+	cfg := testConfig()
+	cfg.MaxAttempts = 1
+	client, _ := newTestClient(cfg)
+	now := time.Now()
+	client.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		_, err := doRequest(t, client, http.MethodGet, server.URL)
+		require.NoError(t, err)
+	}
+
+	now = now.Add(31 * time.Second)
+	_, err := doRequest(t, client, http.MethodGet, server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), calls.Load())
+
+	_, err = doRequest(t, client, http.MethodGet, server.URL)
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	assert.Equal(t, StateOpen, client.Stats().Destinations[0].State)
+}
+
+func TestBackoff(t *testing.T) {
+	client, _ := newTestClient(testConfig())
+
+	assert.Equal(t, 200*time.Millisecond, client.backoff(1))
+	assert.Equal(t, 400*time.Millisecond, client.backoff(2))
+	assert.Equal(t, 800*time.Millisecond, client.backoff(3))
+	assert.Equal(t, 2*time.Second, client.backoff(5))
+}
@@ -0,0 +1,181 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/joblock"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/retry"
+)
+
+const (
+	// maxErrorLength ограничивает last_error события.
+	maxErrorLength = 1000
+	// maxResponseDrain — сколько байт ответа воркера дочитывается, чтобы переиспользовать соединение.
+	maxResponseDrain = 64 << 10
+)
+
+// envelope — тело запроса к воркеру.
+type envelope struct {
+	ID          int64           `json:"id"`
+	Event       string          `json:"event"`
+	AggregateID int64           `json:"aggregate_id"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// LockName — имя блокировки joblock, под которой экземпляр API рассылает outbox.
+const LockName = "outbox_dispatch"
+
+// Run периодически отправляет события из outbox до отмены ctx.
+// Полная пачка означает, что в очереди могут остаться события, поэтому
+// следующая пачка забирается сразу, не дожидаясь тика.
+// На каждом тике рассылает только экземпляр, взявший блокировку LockName:
+// остальные ждут следующего тика, а события не разбираются параллельно.
+// Предназначен для запуска в отдельной горутине.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration, locker joblock.Locker) {
+	logger := d.logger.WithField("method", "Run")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := locker.TryRun(ctx, LockName, d.deliverAll); err != nil {
+				logger.Errorf("Не удалось обработать outbox: %v", err)
+			}
+		}
+	}
+}
+
+// deliverAll забирает пачки, пока они приходят полными.
+func (d *Dispatcher) deliverAll(ctx context.Context) error {
+	for {
+		processed, err := d.DeliverDue(ctx)
+		if err != nil {
+			return err
+		}
+		if processed < int(d.cfg.BatchSize) || ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// DeliverDue забирает пачку событий, срок которых наступил, и отправляет их.
+// Возвращает число обработанных событий (успешных и неуспешных).
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	// События пачки отправляются последовательно; аренда покрывает худший случай,
+	// когда каждый запрос упирается в таймаут.
+	lease := time.Duration(d.cfg.BatchSize) * d.cfg.RequestTimeout
+	events, err := d.store.ClaimDueOutboxEvents(ctx, db.ClaimDueOutboxEventsParams{
+		LeasedUntil: time.Now().Add(lease),
+		BatchSize:   d.cfg.BatchSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("ошибка ClaimDueOutboxEvents: %w", err)
+	}
+
+	for _, e := range events {
+		if err := d.deliver(ctx, e); err != nil {
+			return 0, err
+		}
+	}
+	return len(events), nil
+}
+
+// deliver отправляет одно событие и записывает результат попытки.
+func (d *Dispatcher) deliver(ctx context.Context, e db.ClaimDueOutboxEventsRow) error {
+	logger := d.logger.WithField("outbox_event_id", e.ID)
+
+	statusCode, sendErr := d.send(ctx, e)
+	if sendErr == nil {
+		if err := d.store.MarkOutboxEventDelivered(ctx, db.MarkOutboxEventDeliveredParams{
+			ResponseStatus: int32(statusCode),
+			ID:             e.ID,
+		}); err != nil {
+			return fmt.Errorf("ошибка MarkOutboxEventDelivered(%d): %w", e.ID, err)
+		}
+		return nil
+	}
+
+	attempt := e.Attempts + 1
+	params := db.MarkOutboxEventAttemptFailedParams{
+		NextAttemptAt: time.Now().Add(d.backoff(attempt)),
+		LastError:     truncateError(sendErr.Error()),
+		ID:            e.ID,
+	}
+	if statusCode != 0 {
+		params.ResponseStatus = sql.NullInt32{Int32: int32(statusCode), Valid: true}
+	}
+	logger.Warnf("Попытка %d доставки %s воркеру не удалась, повтор в %s: %v",
+		attempt, e.Event, params.NextAttemptAt.Format(time.RFC3339), sendErr)
+
+	if err := d.store.MarkOutboxEventAttemptFailed(ctx, params); err != nil {
+		return fmt.Errorf("ошибка MarkOutboxEventAttemptFailed(%d): %w", e.ID, err)
+	}
+	return nil
+}
+
+// send выполняет POST воркеру. Возвращает HTTP-код (0 при сетевой ошибке)
+// и ошибку, если ответ не 2xx.
+func (d *Dispatcher) send(ctx context.Context, e db.ClaimDueOutboxEventsRow) (int, error) {
+	body, err := json.Marshal(envelope{
+		ID:          e.ID,
+		Event:       e.Event,
+		AggregateID: e.AggregateID,
+		OccurredAt:  e.CreatedAt.UTC(),
+		Data:        json.RawMessage(e.Payload),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("ошибка сериализации события: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.cfg.WorkerCallbackURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("некорректный запрос: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "tenders-go-outbox")
+	req.Header.Set("X-Outbox-Event", e.Event)
+	req.Header.Set("X-Outbox-Event-ID", strconv.FormatInt(e.ID, 10))
+	if d.cfg.WorkerCallbackToken != "" {
+		req.Header.Set("Authorization", "Bearer "+d.cfg.WorkerCallbackToken)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseDrain))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("воркер ответил %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff возвращает задержку перед повтором после attempt-й неуспешной попытки
+// (retry.Backoff от RetryBaseDelay до RetryMaxDelay).
+func (d *Dispatcher) backoff(attempt int32) time.Duration {
+	return retry.Backoff(int(attempt), d.cfg.RetryBaseDelay, d.cfg.RetryMaxDelay)
+}
+
+func truncateError(msg string) string {
+	if len(msg) <= maxErrorLength {
+		return msg
+	}
+	return strings.ToValidUTF8(msg[:maxErrorLength], "")
+}
@@ -0,0 +1,96 @@
+// Package outbox реализует transactional outbox для событий Python-воркеру.
+//
+// Событие записывается в outbox_events вызовом Enqueue внутри транзакции,
+// изменение которой оно описывает: откат транзакции удаляет и событие, а после
+// коммита событие уже не может потеряться при падении API. Dispatcher в фоне
+// отправляет события POST-запросом на outbox.worker_callback_url и повторяет
+// неуспешные попытки с экспоненциальной задержкой.
+//
+// # Формат запроса
+//
+// Тело — JSON {"id", "event", "aggregate_id", "occurred_at", "data"}. Заголовки:
+//
+//	X-Outbox-Event:    catalog.items_pending
+//	X-Outbox-Event-ID: id события (одинаков для всех повторов — для дедупликации)
+//	Authorization:     Bearer <outbox.worker_callback_token>, если токен задан
+//
+// Доставка успешна при ответе 2xx; редиректы не выполняются.
+//
+// # Гарантии
+//
+// Доставка — at-least-once: число попыток не ограничено, воркер должен быть
+// готов к повтору с тем же X-Outbox-Event-ID. Порядок событий не гарантируется.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// Event — тип события outbox.
+type Event string
+
+const (
+	// После импорта в каталоге появились позиции pending_indexing: воркеру
+	// пора забрать их через GET /internal/worker/catalog/unindexed
+	EventCatalogItemsPending Event = "catalog.items_pending"
+)
+
+// CatalogItemsPendingData — data события catalog.items_pending.
+type CatalogItemsPendingData struct {
+	TenderDBID int64            `json:"tender_db_id"`
+	TenderID   string           `json:"tender_id"` // ID тендера на ЭТП
+	LotIDsMap  map[string]int64 `json:"lot_ids_map"`
+}
+
+// Enqueue записывает событие в outbox. q должен быть Querier транзакции,
+// в которой выполняется изменение: событие фиксируется вместе с ним.
+// Возвращает ID события.
+func Enqueue(ctx context.Context, q db.Querier, event Event, aggregateID int64, data any) (int64, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка сериализации события %s: %w", event, err)
+	}
+
+	id, err := q.EnqueueOutboxEvent(ctx, db.EnqueueOutboxEventParams{
+		Event:       string(event),
+		AggregateID: aggregateID,
+		Payload:     payload,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("ошибка EnqueueOutboxEvent(%s): %w", event, err)
+	}
+	return id, nil
+}
+
+// Dispatcher отправляет события из outbox воркеру.
+type Dispatcher struct {
+	store  db.Store
+	logger logging.Logger
+	cfg    config.OutboxConfig
+	client *http.Client
+}
+
+// NewDispatcher создаёт диспетчер outbox. Запускать его имеет смысл, только
+// если cfg.Enabled().
+func NewDispatcher(store db.Store, logger logging.Logger, cfg config.OutboxConfig) *Dispatcher {
+	return &Dispatcher{
+		store:  store,
+		logger: logger,
+		cfg:    cfg,
+		client: &http.Client{
+			Timeout: cfg.RequestTimeout,
+			// Редирект считается неуспешной доставкой: токен воркера не должен
+			// уходить на адрес, которого нет в конфигурации.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
@@ -0,0 +1,206 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR TRANSACTIONAL OUTBOX (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Lost worker notifications — an event is stored in the import transaction and
+   retried until the worker accepts it, so a crash or a worker outage only delays it
+2. Duplicate processing — every attempt carries the same X-Outbox-Event-ID
+3. Retry storms — failed attempts back off exponentially up to retry_max_delay
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Enqueue
+- GIVEN event data
+  WHEN Enqueue is called with the transaction's Querier
+  THEN one insert with the JSON-encoded data and the aggregate id
+- GIVEN the insert fails
+  THEN the error is returned (and the caller's transaction rolls back)
+
+SCENARIO 2: DeliverDue
+- GIVEN a 2xx worker
+  THEN the event is delivered with the envelope, event id and bearer token
+- GIVEN a 5xx worker or a network error
+  THEN the event stays pending with the next attempt after backoff, no matter
+  how many attempts were made
+*/
+
+func testConfig(callbackURL string) config.OutboxConfig {
+	return config.OutboxConfig{
+		WorkerCallbackURL:   callbackURL,
+		WorkerCallbackToken: "callback-token",
+		DeliveryInterval:    time.Second,
+		BatchSize:           10,
+		RequestTimeout:      2 * time.Second,
+		RetryBaseDelay:      5 * time.Second,
+		RetryMaxDelay:       5 * time.Minute,
+	}
+}
+
+func setupTestDispatcher(t *testing.T, callbackURL string) (*Dispatcher, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	return NewDispatcher(mockStore, testutil.NewMockLogger(), testConfig(callbackURL)), mockStore
+}
+
+func claimed(id int64, attempts int32) db.ClaimDueOutboxEventsRow {
+	return db.ClaimDueOutboxEventsRow{
+		ID:          id,
+		Event:       string(EventCatalogItemsPending),
+		AggregateID: 42,
+		Payload:     []byte(`{"tender_db_id":42,"tender_id":"T-1","lot_ids_map":{"LOT_1":7}}`),
+		Attempts:    attempts,
+		CreatedAt:   time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestEnqueue(t *testing.T) {
+	mockStore := db.NewMockStore(gomock.NewController(t))
+
+	mockStore.EXPECT().EnqueueOutboxEvent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.EnqueueOutboxEventParams) (int64, error) {
+			assert.Equal(t, "catalog.items_pending", arg.Event)
+			assert.Equal(t, int64(42), arg.AggregateID)
+			assert.JSONEq(t, `{"tender_db_id":42,"tender_id":"T-1","lot_ids_map":{"LOT_1":7}}`, string(arg.Payload))
+			return 5, nil
+		})
+
+	id, err := Enqueue(context.Background(), mockStore, EventCatalogItemsPending, 42, CatalogItemsPendingData{
+		TenderDBID: 42,
+		TenderID:   "T-1",
+		LotIDsMap:  map[string]int64{"LOT_1": 7},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), id)
+}
+
+func TestEnqueue_DBError(t *testing.T) {
+	mockStore := db.NewMockStore(gomock.NewController(t))
+	mockStore.EXPECT().EnqueueOutboxEvent(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("connection reset"))
+
+	_, err := Enqueue(context.Background(), mockStore, EventCatalogItemsPending, 42, CatalogItemsPendingData{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "EnqueueOutboxEvent")
+}
+
+func TestDeliverDue_MarksDelivered(t *testing.T) {
+	worker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, "catalog.items_pending", r.Header.Get("X-Outbox-Event"))
+		assert.Equal(t, "11", r.Header.Get("X-Outbox-Event-ID"))
+		assert.Equal(t, "Bearer callback-token", r.Header.Get("Authorization"))
+
+		var env map[string]any
+		require.NoError(t, json.Unmarshal(body, &env))
+		assert.Equal(t, float64(11), env["id"])
+		assert.Equal(t, float64(42), env["aggregate_id"])
+		assert.Equal(t, "2026-03-01T10:00:00Z", env["occurred_at"])
+		assert.Equal(t, "T-1", env["data"].(map[string]any)["tender_id"])
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer worker.Close()
+	dispatcher, mockStore := setupTestDispatcher(t, worker.URL)
+
+	mockStore.EXPECT().ClaimDueOutboxEvents(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.ClaimDueOutboxEventsParams) ([]db.ClaimDueOutboxEventsRow, error) {
+			assert.Equal(t, int32(10), arg.BatchSize)
+			assert.True(t, arg.LeasedUntil.After(time.Now()), "claimed events are leased")
+			return []db.ClaimDueOutboxEventsRow{claimed(11, 0)}, nil
+		})
+	mockStore.EXPECT().MarkOutboxEventDelivered(gomock.Any(), db.MarkOutboxEventDeliveredParams{
+		ResponseStatus: http.StatusAccepted,
+		ID:             11,
+	}).Return(nil)
+
+	processed, err := dispatcher.DeliverDue(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+}
+
+func TestDeliverDue_FailureSchedulesRetry(t *testing.T) {
+	worker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer worker.Close()
+	dispatcher, mockStore := setupTestDispatcher(t, worker.URL)
+
+	// Сотая попытка: у outbox нет статуса failed, событие повторяется дальше
+	mockStore.EXPECT().ClaimDueOutboxEvents(gomock.Any(), gomock.Any()).
+		Return([]db.ClaimDueOutboxEventsRow{claimed(11, 99)}, nil)
+	mockStore.EXPECT().MarkOutboxEventAttemptFailed(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.MarkOutboxEventAttemptFailedParams) error {
+			assert.Equal(t, int64(11), arg.ID)
+			assert.Equal(t, int32(http.StatusServiceUnavailable), arg.ResponseStatus.Int32)
+			assert.NotEmpty(t, arg.LastError)
+			assert.WithinDuration(t, time.Now().Add(5*time.Minute), arg.NextAttemptAt, 5*time.Second)
+			return nil
+		})
+
+	_, err := dispatcher.DeliverDue(context.Background())
+
+	require.NoError(t, err)
+}
+
+func TestDeliverDue_NetworkError(t *testing.T) {
+	// Сервер закрыт до отправки
+	worker := httptest.NewServer(http.NotFoundHandler())
+	target := worker.URL
+	worker.Close()
+	dispatcher, mockStore := setupTestDispatcher(t, target)
+
+	mockStore.EXPECT().ClaimDueOutboxEvents(gomock.Any(), gomock.Any()).
+		Return([]db.ClaimDueOutboxEventsRow{claimed(11, 0)}, nil)
+	mockStore.EXPECT().MarkOutboxEventAttemptFailed(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.MarkOutboxEventAttemptFailedParams) error {
+			assert.False(t, arg.ResponseStatus.Valid, "no HTTP status on a network error")
+			assert.WithinDuration(t, time.Now().Add(5*time.Second), arg.NextAttemptAt, 2*time.Second)
+			return nil
+		})
+
+	_, err := dispatcher.DeliverDue(context.Background())
+
+	require.NoError(t, err)
+}
+
+func TestBackoff(t *testing.T) {
+	dispatcher, _ := setupTestDispatcher(t, "http://worker:8000/events")
+
+	expected := map[int32]time.Duration{
+		1:   5 * time.Second,
+		2:   10 * time.Second,
+		6:   160 * time.Second,
+		7:   5 * time.Minute, // 320 секунд — упирается в retry_max_delay
+		500: 5 * time.Minute,
+	}
+	for attempt, delay := range expected {
+		assert.Equal(t, delay, dispatcher.backoff(attempt), "attempt %d", attempt)
+	}
+}
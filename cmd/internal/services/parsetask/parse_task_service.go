@@ -0,0 +1,212 @@
+// Package parsetask ведёт историю задач парсера, поставленных загрузкой файлов
+// тендеров (POST /api/v1/upload-tender), получением тендера с ЭТП
+// (POST /api/v1/tenders/fetch, пакет etp) или его обновлением по расписанию.
+//
+// Запись создаётся, когда парсер принял файл или etp_id и вернул task_id. Статус
+// обновляется двумя путями: при запросе статуса API опрашивает парсер
+// (polled), либо парсер сам сообщает статус, ID созданного тендера или
+// ошибку (pushed). Итоговые completed и failed опросом не перезаписываются.
+// Благодаря записи загрузки не теряются при перезапуске парсера. Статус,
+// о котором сообщил парсер, сразу уходит в поток событий загрузившего
+// пользователя (live, GET /api/v1/events).
+package parsetask
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/live"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// ParseTaskService хранит задачи парсера и их статусы.
+type ParseTaskService struct {
+	store  db.Store
+	logger logging.Logger
+	live   *live.Hub
+}
+
+// NewParseTaskService создаёт сервис истории задач парсера.
+func NewParseTaskService(store db.Store, logger logging.Logger, hub *live.Hub) *ParseTaskService {
+	return &ParseTaskService{
+		store:  store,
+		logger: logger,
+		live:   hub,
+	}
+}
+
+// Upload — данные загрузки, принятой парсером.
+type Upload struct {
+	TaskID         string
+	UserID         sql.NullInt64
+	OrganizationID int64
+	FileName       string
+	FileSize       int64
+	EnableAI       bool
+}
+
+// Record сохраняет задачу, которую парсер поставил по загрузке.
+func (s *ParseTaskService) Record(ctx context.Context, upload Upload) error {
+	_, err := s.store.CreateParseTask(ctx, db.CreateParseTaskParams{
+		TaskID:         upload.TaskID,
+		UserID:         upload.UserID,
+		OrganizationID: upload.OrganizationID,
+		FileName:       upload.FileName,
+		FileSize:       upload.FileSize,
+		EnableAi:       upload.EnableAI,
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка CreateParseTask(%s): %w", upload.TaskID, err)
+	}
+	return nil
+}
+
+// Lookup ищет задачу. organizationID — организация пользователя (невалидный —
+// без фильтра). found=false — задачи нет в истории; задача другой
+// организации — NotFoundError, чтобы её статус не запрашивался у парсера.
+func (s *ParseTaskService) Lookup(ctx context.Context, taskID string, organizationID sql.NullInt64) (task db.ParseTask, found bool, err error) {
+	task, err = s.store.GetParseTaskByTaskID(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.ParseTask{}, false, nil
+		}
+		return db.ParseTask{}, false, fmt.Errorf("ошибка GetParseTaskByTaskID(%s): %w", taskID, err)
+	}
+	if organizationID.Valid && task.OrganizationID != organizationID.Int64 {
+		return db.ParseTask{}, false, apierrors.NewNotFoundError("parsetask.not_found", taskID)
+	}
+	return task, true, nil
+}
+
+// ApplyPolledStatus запоминает ответ парсера на опрос статуса; статус
+// берётся из поля status ответа. Невалидный JSON пропускается.
+func (s *ParseTaskService) ApplyPolledStatus(ctx context.Context, task db.ParseTask, parserBody []byte) error {
+	if !json.Valid(parserBody) {
+		return nil
+	}
+	var parsed struct {
+		Status string `json:"status"`
+	}
+	status := task.Status
+	if err := json.Unmarshal(parserBody, &parsed); err == nil && parsed.Status != "" {
+		status = parsed.Status
+	}
+	err := s.store.UpdateParseTaskPolledStatus(ctx, db.UpdateParseTaskPolledStatusParams{
+		TaskID:     task.TaskID,
+		Status:     status,
+		LastStatus: parserBody,
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка UpdateParseTaskPolledStatus(%s): %w", task.TaskID, err)
+	}
+	return nil
+}
+
+// ApplyPushedStatus сохраняет статус, о котором сообщил парсер, и передаёт
+// его в поток событий пользователя, загрузившего файл.
+func (s *ParseTaskService) ApplyPushedStatus(ctx context.Context, taskID string, push api_models.ParseTaskStatusPush) (*api_models.ParseTask, error) {
+	params := db.UpdateParseTaskPushedStatusParams{
+		TaskID: taskID,
+		Status: push.Status,
+	}
+	if push.TenderID != nil {
+		params.TenderID = sql.NullInt64{Int64: *push.TenderID, Valid: true}
+	}
+	if push.Error != "" {
+		params.ErrorMessage = sql.NullString{String: push.Error, Valid: true}
+	}
+
+	task, err := s.store.UpdateParseTaskPushedStatus(ctx, params)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewNotFoundError("parsetask.not_found", taskID)
+		}
+		return nil, fmt.Errorf("ошибка UpdateParseTaskPushedStatus(%s): %w", taskID, err)
+	}
+	s.logger.Infof("Парсер сообщил статус %s задачи %s", push.Status, taskID)
+	response := ToResponse(task, false)
+	if task.UserID.Valid {
+		s.live.PublishUser(task.UserID.Int64, live.Message{Type: live.TypeParseTaskStatus, Data: response})
+	}
+	return &response, nil
+}
+
+// ListForUser возвращает последние загрузки пользователя, новые первыми.
+// limit <= 0 — значение по умолчанию.
+func (s *ParseTaskService) ListForUser(ctx context.Context, userID int64, limit, offset int32) (*api_models.ParseTasksResponse, error) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		return nil, apierrors.NewValidationError("request.limit_max", maxLimit)
+	}
+	if offset < 0 {
+		return nil, apierrors.NewValidationError("request.offset_negative")
+	}
+
+	rows, err := s.store.ListParseTasksByUser(ctx, db.ListParseTasksByUserParams{
+		UserID:     sql.NullInt64{Int64: userID, Valid: true},
+		PageLimit:  limit,
+		PageOffset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ListParseTasksByUser(%d): %w", userID, err)
+	}
+	total, err := s.store.CountParseTasksByUser(ctx, sql.NullInt64{Int64: userID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка CountParseTasksByUser(%d): %w", userID, err)
+	}
+
+	items := make([]api_models.ParseTask, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, ToResponse(row, false))
+	}
+	return &api_models.ParseTasksResponse{
+		Items:  items,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+// ToResponse преобразует запись в ответ API. stored — ответ на запрос статуса
+// построен по записи, а не получен от парсера.
+func ToResponse(task db.ParseTask, stored bool) api_models.ParseTask {
+	resp := api_models.ParseTask{
+		TaskID:       task.TaskID,
+		Status:       task.Status,
+		StatusSource: task.StatusSource,
+		Source:       task.Source,
+		EtpID:        task.EtpID.String,
+		FileName:     task.FileName,
+		FileSize:     task.FileSize,
+		EnableAI:     task.EnableAi,
+		Error:        task.ErrorMessage.String,
+		Stored:       stored,
+		CreatedAt:    task.CreatedAt,
+		UpdatedAt:    task.UpdatedAt,
+	}
+	if task.TenderID.Valid {
+		id := task.TenderID.Int64
+		resp.TenderID = &id
+	}
+	if task.LastStatus.Valid {
+		resp.LastStatus = json.RawMessage(task.LastStatus.RawMessage)
+	}
+	if task.FinishedAt.Valid {
+		t := task.FinishedAt.Time
+		resp.FinishedAt = &t
+	}
+	return resp
+}
@@ -0,0 +1,190 @@
+package parsetask
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/live"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR PARSER TASK HISTORY (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. An upload "disappears" after the parser restarts — the task must be found
+   in the history
+2. Another organization learns the status of someone else's upload
+3. A late poll overwrites the final result the parser has already pushed
+4. The parser pushes a status for a task the API never saw and gets 500
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Lookup
+- GIVEN no record → found=false without error (status is asked from the parser)
+- GIVEN a record of another organization → NotFoundError
+- GIVEN a record of the user's organization → found=true
+
+SCENARIO 2: ApplyPolledStatus
+- GIVEN a parser response with status → the status and the response are stored
+- GIVEN a non-JSON response → nothing is written
+
+SCENARIO 3: ApplyPushedStatus
+- GIVEN completed with tender_id → tender_id is passed, response has the tender
+  AND the uploader's event stream receives parse_task.status
+- GIVEN an unknown task → NotFoundError
+
+SCENARIO 4: ListForUser
+- GIVEN limit 0 → default limit 20 is used
+- GIVEN limit above 100 → ValidationError, no query
+*/
+
+func setupTestService(t *testing.T) (*ParseTaskService, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	hub := live.NewHub(config.LiveConfig{BufferSize: 4, MaxConnectionsPerUser: 1}, testutil.NewMockLogger())
+	return NewParseTaskService(mockStore, testutil.NewMockLogger(), hub), mockStore
+}
+
+func TestLookup_NotRecorded(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetParseTaskByTaskID(gomock.Any(), "t-1").Return(db.ParseTask{}, sql.ErrNoRows)
+
+	_, found, err := service.Lookup(context.Background(), "t-1", sql.NullInt64{Int64: 5, Valid: true})
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestLookup_OtherOrganization(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetParseTaskByTaskID(gomock.Any(), "t-1").
+		Return(db.ParseTask{TaskID: "t-1", OrganizationID: 9}, nil)
+
+	_, found, err := service.Lookup(context.Background(), "t-1", sql.NullInt64{Int64: 5, Valid: true})
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+	assert.False(t, found)
+}
+
+func TestLookup_OwnOrganization(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetParseTaskByTaskID(gomock.Any(), "t-1").
+		Return(db.ParseTask{TaskID: "t-1", OrganizationID: 5}, nil)
+
+	task, found, err := service.Lookup(context.Background(), "t-1", sql.NullInt64{Int64: 5, Valid: true})
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "t-1", task.TaskID)
+}
+
+func TestApplyPolledStatus_StoresStatus(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	body := []byte(`{"task_id":"t-1","status":"processing"}`)
+
+	mockStore.EXPECT().UpdateParseTaskPolledStatus(gomock.Any(), db.UpdateParseTaskPolledStatusParams{
+		TaskID:     "t-1",
+		Status:     "processing",
+		LastStatus: body,
+	}).Return(nil)
+
+	err := service.ApplyPolledStatus(context.Background(), db.ParseTask{TaskID: "t-1", Status: "queued"}, body)
+	require.NoError(t, err)
+}
+
+func TestApplyPolledStatus_InvalidJSON_Skipped(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	err := service.ApplyPolledStatus(context.Background(), db.ParseTask{TaskID: "t-1"}, []byte("Internal Server Error"))
+	require.NoError(t, err)
+}
+
+func TestApplyPushedStatus_Completed(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	tenderID := int64(42)
+	finished := time.Now()
+	stream, err := service.live.Subscribe(3, 1)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	mockStore.EXPECT().UpdateParseTaskPushedStatus(gomock.Any(), db.UpdateParseTaskPushedStatusParams{
+		TaskID:   "t-1",
+		Status:   "completed",
+		TenderID: sql.NullInt64{Int64: 42, Valid: true},
+	}).Return(db.ParseTask{
+		TaskID:       "t-1",
+		Status:       "completed",
+		StatusSource: "pushed",
+		TenderID:     sql.NullInt64{Int64: 42, Valid: true},
+		UserID:       sql.NullInt64{Int64: 3, Valid: true},
+		FinishedAt:   sql.NullTime{Time: finished, Valid: true},
+	}, nil)
+
+	resp, err := service.ApplyPushedStatus(context.Background(), "t-1", api_models.ParseTaskStatusPush{Status: "completed", TenderID: &tenderID})
+	require.NoError(t, err)
+	require.NotNil(t, resp.TenderID)
+	assert.Equal(t, int64(42), *resp.TenderID)
+	assert.Equal(t, "pushed", resp.StatusSource)
+	require.NotNil(t, resp.FinishedAt)
+	assert.False(t, resp.Stored)
+
+	require.Len(t, stream.C(), 1)
+	msg := <-stream.C()
+	assert.Equal(t, live.TypeParseTaskStatus, msg.Type)
+	assert.Equal(t, *resp, msg.Data)
+}
+
+func TestApplyPushedStatus_UnknownTask(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().UpdateParseTaskPushedStatus(gomock.Any(), gomock.Any()).Return(db.ParseTask{}, sql.ErrNoRows)
+
+	_, err := service.ApplyPushedStatus(context.Background(), "t-1", api_models.ParseTaskStatusPush{Status: "failed", Error: "bad sheet"})
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestListForUser_DefaultLimit(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	userID := sql.NullInt64{Int64: 3, Valid: true}
+
+	mockStore.EXPECT().ListParseTasksByUser(gomock.Any(), db.ListParseTasksByUserParams{
+		UserID:     userID,
+		PageLimit:  20,
+		PageOffset: 0,
+	}).Return([]db.ParseTask{{TaskID: "t-2"}, {TaskID: "t-1"}}, nil)
+	mockStore.EXPECT().CountParseTasksByUser(gomock.Any(), userID).Return(int64(2), nil)
+
+	resp, err := service.ListForUser(context.Background(), 3, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int32(20), resp.Limit)
+	assert.Equal(t, int64(2), resp.Total)
+	require.Len(t, resp.Items, 2)
+	assert.Equal(t, "t-2", resp.Items[0].TaskID)
+}
+
+func TestListForUser_LimitTooLarge(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.ListForUser(context.Background(), 3, 101, 0)
+
+	var validationErr *apierrors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
@@ -0,0 +1,239 @@
+// Package priceindex ведёт индексы стоимости строительства по регионам и
+// месяцам (price_indices) и пересчитывает по ним цены к сопоставимому уровню.
+//
+// Индекс на дату — значение за последний месяц ряда, не позже даты. Регион
+// без своего ряда (и тендер без региона) пересчитывается по общероссийскому
+// ряду NationalRegion. Пересчёт цены:
+//
+//	normalized = price × index(целевой регион, целевая дата) / index(регион цены, дата цены)
+package priceindex
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/db/postgres"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// NationalRegion — код общероссийского ряда индексов.
+const NationalRegion = "RU"
+
+// regionPattern совпадает с chk_price_indices_region и chk_tenders_region.
+var regionPattern = regexp.MustCompile(`^[0-9A-Z-]{1,16}$`)
+
+// NormalizeRegion приводит код региона к виду, в котором он хранится: " ru" → "RU".
+func NormalizeRegion(region string) (string, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(region))
+	if !regionPattern.MatchString(normalized) {
+		return "", apierrors.NewValidationError("priceindex.invalid_region", region)
+	}
+	return normalized, nil
+}
+
+// ParsePeriod разбирает месяц индекса ("2025-03" или любая дата месяца
+// "2025-03-15") и возвращает первое число месяца.
+func ParsePeriod(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{"2006-01", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return monthStart(t), nil
+		}
+	}
+	return time.Time{}, apierrors.NewValidationError("priceindex.invalid_period", value)
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// PriceIndexService управляет рядами индексов.
+type PriceIndexService struct {
+	store  db.Store
+	logger logging.Logger
+}
+
+// NewPriceIndexService создаёт новый экземпляр PriceIndexService.
+func NewPriceIndexService(store db.Store, logger logging.Logger) *PriceIndexService {
+	return &PriceIndexService{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// List возвращает индексы по регионам и месяцам; region == "" — все регионы.
+func (s *PriceIndexService) List(ctx context.Context, region string) (*api_models.ListPriceIndicesResponse, error) {
+	var filter sql.NullString
+	if region != "" {
+		normalized, err := NormalizeRegion(region)
+		if err != nil {
+			return nil, err
+		}
+		filter = sql.NullString{String: normalized, Valid: true}
+	}
+
+	rows, err := s.store.ListPriceIndices(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения индексов цен: %w", err)
+	}
+	items := make([]api_models.PriceIndex, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, toResponse(row))
+	}
+	return &api_models.ListPriceIndicesResponse{Items: items}, nil
+}
+
+// Create добавляет значение индекса. На регион и месяц — одно значение.
+func (s *PriceIndexService) Create(ctx context.Context, req api_models.PriceIndexRequest) (*api_models.PriceIndex, error) {
+	region, period, value, err := parseRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := s.store.CreatePriceIndex(ctx, db.CreatePriceIndexParams{
+		Region: region,
+		Period: period,
+		Value:  value.String(),
+	})
+	if err != nil {
+		if postgres.IsUniqueViolation(err) {
+			return nil, duplicateError(region, period)
+		}
+		return nil, fmt.Errorf("ошибка CreatePriceIndex(%s, %s): %w", region, period.Format("2006-01"), err)
+	}
+	s.logger.Infof("Добавлен индекс цен %s за %s: %s", region, period.Format("2006-01"), row.Value)
+	response := toResponse(row)
+	return &response, nil
+}
+
+// Update заменяет регион, месяц и значение индекса.
+func (s *PriceIndexService) Update(ctx context.Context, id int64, req api_models.PriceIndexRequest) (*api_models.PriceIndex, error) {
+	region, period, value, err := parseRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := s.store.UpdatePriceIndex(ctx, db.UpdatePriceIndexParams{
+		Region: region,
+		Period: period,
+		Value:  value.String(),
+		ID:     id,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewNotFoundError("priceindex.not_found", id)
+		}
+		if postgres.IsUniqueViolation(err) {
+			return nil, duplicateError(region, period)
+		}
+		return nil, fmt.Errorf("ошибка UpdatePriceIndex(%d): %w", id, err)
+	}
+	s.logger.Infof("Изменён индекс цен %d: %s за %s = %s", id, region, period.Format("2006-01"), row.Value)
+	response := toResponse(row)
+	return &response, nil
+}
+
+// Delete удаляет значение индекса.
+func (s *PriceIndexService) Delete(ctx context.Context, id int64) error {
+	deleted, err := s.store.DeletePriceIndex(ctx, id)
+	if err != nil {
+		return fmt.Errorf("ошибка DeletePriceIndex(%d): %w", id, err)
+	}
+	if deleted == 0 {
+		return apierrors.NewNotFoundError("priceindex.not_found", id)
+	}
+	s.logger.Infof("Удалён индекс цен %d", id)
+	return nil
+}
+
+func parseRequest(req api_models.PriceIndexRequest) (string, time.Time, decimal.Decimal, error) {
+	region, err := NormalizeRegion(req.Region)
+	if err != nil {
+		return "", time.Time{}, decimal.Zero, err
+	}
+	period, err := ParsePeriod(req.Period)
+	if err != nil {
+		return "", time.Time{}, decimal.Zero, err
+	}
+	value, err := decimal.NewFromString(strings.TrimSpace(req.Value))
+	if err != nil || !value.IsPositive() {
+		return "", time.Time{}, decimal.Zero, apierrors.NewValidationError("priceindex.value_positive", req.Value)
+	}
+	return region, period, value, nil
+}
+
+func duplicateError(region string, period time.Time) error {
+	return apierrors.NewConflictErrorf(map[string]string{"region": region, "period": period.Format("2006-01")}, "priceindex.already_exists", region, period.Format("2006-01"))
+}
+
+func toResponse(row db.PriceIndex) api_models.PriceIndex {
+	return api_models.PriceIndex{
+		ID:        row.ID,
+		Region:    row.Region,
+		Period:    row.Period.Format("2006-01"),
+		Value:     row.Value,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
+
+// point — значение ряда за месяц.
+type point struct {
+	period time.Time
+	value  decimal.Decimal
+}
+
+// Table — ряды индексов в памяти для пересчёта многих цен без запросов к БД.
+type Table struct {
+	series map[string][]point // По возрастанию period
+}
+
+// NewTable строит таблицу из строк price_indices. Строки с нечисловым
+// значением пропускаются (CHECK в БД их не допускает).
+func NewTable(rows []db.PriceIndex) *Table {
+	table := &Table{series: make(map[string][]point)}
+	for _, row := range rows {
+		value, err := decimal.NewFromString(row.Value)
+		if err != nil {
+			continue
+		}
+		table.series[row.Region] = append(table.series[row.Region], point{period: monthStart(row.Period), value: value})
+	}
+	for _, points := range table.series {
+		sort.Slice(points, func(i, j int) bool { return points[i].period.Before(points[j].period) })
+	}
+	return table
+}
+
+// Lookup возвращает индекс региона на дату: значение за последний месяц не
+// позже at. Регион без ряда или без значений до at — по ряду NationalRegion.
+// ok=false — индекса нет ни в одном ряду.
+func (t *Table) Lookup(region string, at time.Time) (decimal.Decimal, bool) {
+	if region != "" && region != NationalRegion {
+		if value, ok := t.lookupSeries(region, at); ok {
+			return value, true
+		}
+	}
+	return t.lookupSeries(NationalRegion, at)
+}
+
+func (t *Table) lookupSeries(region string, at time.Time) (decimal.Decimal, bool) {
+	points := t.series[region]
+	month := monthStart(at)
+	// Первый месяц после at; нужный — перед ним
+	i := sort.Search(len(points), func(i int) bool { return points[i].period.After(month) })
+	if i == 0 {
+		return decimal.Zero, false
+	}
+	return points[i-1].value, true
+}
@@ -0,0 +1,149 @@
+package priceindex
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR PRICE INDICES (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. A price is adjusted with an index of the wrong month
+2. A region without its own series gets no adjustment at all
+3. Two values for the same region and month make adjustments ambiguous
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Table.Lookup
+- GIVEN monthly values THEN the last value not after the date is used
+- GIVEN a region without a series (or an empty region) THEN the RU series is used
+- GIVEN a date before every value THEN ok=false
+
+SCENARIO 2: Create / Update / Delete
+- GIVEN " 77", "2025-03-15" and "104.5"
+  THEN region 77, period 2025-03-01 and the value are stored
+- GIVEN a zero value or a malformed period → ValidationError, no query
+- GIVEN a duplicate region and month → ConflictError
+- GIVEN an unknown id → NotFoundError
+*/
+
+func setupTestService(t *testing.T) (*PriceIndexService, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	return NewPriceIndexService(mockStore, testutil.NewMockLogger()), mockStore
+}
+
+func month(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestTable_Lookup(t *testing.T) {
+	table := NewTable([]db.PriceIndex{
+		{Region: "RU", Period: month(2025, time.June), Value: "110"},
+		{Region: "RU", Period: month(2025, time.January), Value: "100"},
+		{Region: "77", Period: month(2025, time.January), Value: "130"},
+	})
+
+	value, ok := table.Lookup("RU", time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, "100", value.String())
+
+	value, ok = table.Lookup("RU", time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, "110", value.String())
+
+	value, ok = table.Lookup("77", time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, "130", value.String())
+
+	value, ok = table.Lookup("50", time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, "110", value.String(), "регион без ряда — по ряду RU")
+
+	value, ok = table.Lookup("", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, "100", value.String())
+
+	_, ok = table.Lookup("RU", time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC))
+	assert.False(t, ok)
+}
+
+func TestCreate_NormalizesInput(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().CreatePriceIndex(gomock.Any(), db.CreatePriceIndexParams{
+		Region: "77",
+		Period: month(2025, time.March),
+		Value:  "104.5",
+	}).Return(db.PriceIndex{ID: 1, Region: "77", Period: month(2025, time.March), Value: "104.5000"}, nil)
+
+	resp, err := service.Create(context.Background(), api_models.PriceIndexRequest{Region: " 77", Period: "2025-03-15", Value: "104.5"})
+	require.NoError(t, err)
+	assert.Equal(t, "2025-03", resp.Period)
+	assert.Equal(t, "104.5000", resp.Value)
+}
+
+func TestCreate_InvalidInput(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	testCases := []api_models.PriceIndexRequest{
+		{Region: "77", Period: "2025-03", Value: "0"},
+		{Region: "77", Period: "03.2025", Value: "100"},
+		{Region: "Москва", Period: "2025-03", Value: "100"},
+	}
+	for _, req := range testCases {
+		_, err := service.Create(context.Background(), req)
+
+		var validationErr *apierrors.ValidationError
+		assert.ErrorAs(t, err, &validationErr, "%+v", req)
+	}
+}
+
+func TestCreate_Duplicate(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().CreatePriceIndex(gomock.Any(), gomock.Any()).Return(db.PriceIndex{}, &pq.Error{Code: "23505"})
+
+	_, err := service.Create(context.Background(), api_models.PriceIndexRequest{Region: "RU", Period: "2025-03", Value: "100"})
+
+	var conflictErr *apierrors.ConflictError
+	assert.ErrorAs(t, err, &conflictErr)
+}
+
+func TestUpdate_NotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().UpdatePriceIndex(gomock.Any(), gomock.Any()).Return(db.PriceIndex{}, sql.ErrNoRows)
+
+	_, err := service.Update(context.Background(), 9, api_models.PriceIndexRequest{Region: "RU", Period: "2025-03", Value: "100"})
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestDelete_NotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().DeletePriceIndex(gomock.Any(), int64(9)).Return(int64(0), nil)
+
+	err := service.Delete(context.Background(), 9)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// pruneInterval — как часто Memory удаляет корзины, которые успели наполниться.
+const pruneInterval = time.Minute
+
+type memoryBucket struct {
+	limiter *rate.Limiter
+	// fullAt — не позже этого момента корзина снова полна и неотличима от новой
+	fullAt time.Time
+}
+
+// Memory — хранилище в памяти процесса (driver: memory): корзина rate.Limiter
+// на ключ. Полные корзины удаляются раз в pruneInterval, поэтому память
+// пропорциональна числу активных клиентов, а не всех, кто когда-либо заходил.
+type Memory struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastPrune time.Time
+}
+
+// NewMemory создаёт пустое хранилище в памяти.
+func NewMemory() *Memory {
+	return &Memory{buckets: make(map[string]*memoryBucket)}
+}
+
+func (m *Memory) Take(_ context.Context, key string, rule Rule, now time.Time) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.prune(now)
+
+	limit := rate.Limit(rule.RPS)
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &memoryBucket{limiter: rate.NewLimiter(limit, rule.Burst)}
+		m.buckets[key] = b
+	} else if b.limiter.Limit() != limit || b.limiter.Burst() != rule.Burst {
+		// Лимит перечитан по SIGHUP или у пользователя сменилась роль
+		b.limiter.SetLimitAt(now, limit)
+		b.limiter.SetBurstAt(now, rule.Burst)
+	}
+
+	r := b.limiter.ReserveN(now, 1)
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		return delay, nil
+	}
+	b.fullAt = now.Add(time.Duration(float64(rule.Burst) / rule.RPS * float64(time.Second)))
+	return 0, nil
+}
+
+func (m *Memory) prune(now time.Time) {
+	if now.Sub(m.lastPrune) < pruneInterval {
+		return
+	}
+	m.lastPrune = now
+	for key, b := range m.buckets {
+		if now.After(b.fullAt) {
+			delete(m.buckets, key)
+		}
+	}
+}
+
+// Len возвращает число корзин.
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.buckets)
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
@@ -0,0 +1,171 @@
+// Package ratelimit ограничивает частоту запросов к /api/v1 по пользователю:
+// у каждого ключа (user:<id> после аутентификации, ip:<адрес> до неё) своя
+// корзина токенов в каждой группе роутов.
+//
+// Группы и их лимиты задаются секцией rate_limit:
+//
+//   - user   — все защищённые роуты (user_rps / user_burst);
+//   - export — выгрузки CSV, строже и поверх лимита user (export_rps / export_burst);
+//   - auth   — публичные вход, refresh и сброс пароля по IP (auth_rps / auth_burst).
+//
+// Лимиты user и export умножаются на rate_limit.role_multipliers[роль].
+// Хранилище выбирается настройкой rate_limit.driver:
+//
+//   - none   — лимиты выключены;
+//   - memory — в памяти процесса (по умолчанию); у каждой реплики свои счётчики,
+//     поэтому фактический лимит — лимит × число реплик;
+//   - redis  — общие счётчики всех реплик (GCRA в Lua-скрипте).
+//
+// Ошибка хранилища не блокирует запрос: пользователь не должен получать 429
+// из-за недоступного Redis.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// Группы роутов с отдельными корзинами.
+const (
+	GroupUser   = "user"
+	GroupExport = "export"
+	GroupAuth   = "auth"
+)
+
+// Rule — лимит корзины: скорость пополнения в секунду и ёмкость.
+type Rule struct {
+	RPS   float64
+	Burst int
+}
+
+// Store — хранилище корзин. Реализации безопасны для параллельного использования.
+type Store interface {
+	// Take забирает токен из корзины key. Если токена нет, возвращает время
+	// до его появления (> 0); корзина при этом не меняется.
+	Take(ctx context.Context, key string, rule Rule, now time.Time) (retryAfter time.Duration, err error)
+	Close() error
+}
+
+// Limiter выбирает лимит по группе и роли и обращается к хранилищу.
+// Настройки меняются без перезапуска через Apply.
+type Limiter struct {
+	logger logging.Logger
+	now    func() time.Time
+
+	mu    sync.Mutex // сериализует Apply
+	state atomic.Pointer[limiterState]
+}
+
+type limiterState struct {
+	cfg   config.RateLimitConfig
+	store Store // nil — лимиты выключены
+}
+
+// New создаёт лимитер по настройкам rate_limit.
+func New(cfg config.RateLimitConfig, logger logging.Logger) (*Limiter, error) {
+	l := &Limiter{
+		logger: logger.WithField("component", "ratelimit"),
+		now:    time.Now,
+	}
+	if err := l.Apply(cfg); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Apply применяет перечитанные настройки. Хранилище пересоздаётся, только если
+// изменились driver или redis; иначе счётчики сохраняются, а новые лимиты
+// действуют со следующего запроса.
+func (l *Limiter) Apply(cfg config.RateLimitConfig) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cur := l.state.Load()
+	if cur != nil && cur.cfg.Driver == cfg.Driver && cur.cfg.Redis == cfg.Redis {
+		l.state.Store(&limiterState{cfg: cfg, store: cur.store})
+		return nil
+	}
+
+	store, err := newStore(cfg)
+	if err != nil {
+		return err
+	}
+	l.state.Store(&limiterState{cfg: cfg, store: store})
+	if cur != nil && cur.store != nil {
+		_ = cur.store.Close()
+	}
+
+	if store != nil {
+		l.logger.Infof("Лимиты запросов API включены: драйвер %s", cfg.Driver)
+	} else {
+		l.logger.Info("Лимиты запросов API выключены")
+	}
+	return nil
+}
+
+func newStore(cfg config.RateLimitConfig) (Store, error) {
+	switch cfg.Driver {
+	case "none", "":
+		return nil, nil
+	case "memory":
+		return NewMemory(), nil
+	case "redis":
+		return newRedis(cfg.Redis)
+	default:
+		return nil, fmt.Errorf("неизвестный драйвер лимитов запросов %q", cfg.Driver)
+	}
+}
+
+// Allow забирает токен из корзины key группы group. Если лимит исчерпан,
+// возвращает allowed=false и время до следующей попытки. При ошибке хранилища
+// запрос пропускается (allowed=true), а ошибка возвращается для лога.
+func (l *Limiter) Allow(ctx context.Context, group, role, key string) (allowed bool, retryAfter time.Duration, err error) {
+	st := l.state.Load()
+	if st.store == nil {
+		return true, 0, nil
+	}
+
+	retryAfter, err = st.store.Take(ctx, group+":"+key, st.rule(group, role), l.now())
+	if err != nil {
+		return true, 0, err
+	}
+	return retryAfter <= 0, retryAfter, nil
+}
+
+// Close закрывает хранилище.
+func (l *Limiter) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if st := l.state.Load(); st != nil && st.store != nil {
+		return st.store.Close()
+	}
+	return nil
+}
+
+// rule возвращает лимит группы с учётом множителя роли. Для auth роли нет:
+// ключ — IP неаутентифицированного клиента.
+func (s *limiterState) rule(group, role string) Rule {
+	var rule Rule
+	switch group {
+	case GroupAuth:
+		return Rule{RPS: s.cfg.AuthRPS, Burst: s.cfg.AuthBurst}
+	case GroupExport:
+		rule = Rule{RPS: s.cfg.ExportRPS, Burst: s.cfg.ExportBurst}
+	default:
+		rule = Rule{RPS: s.cfg.UserRPS, Burst: s.cfg.UserBurst}
+	}
+
+	if multiplier, ok := s.cfg.RoleMultipliers[role]; ok {
+		rule.RPS *= multiplier
+		rule.Burst = max(1, int(math.Ceil(float64(rule.Burst)*multiplier)))
+	}
+	return rule
+}
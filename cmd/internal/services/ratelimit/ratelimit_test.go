@@ -0,0 +1,181 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR API RATE LIMITS (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. One user or script exhausting the API (and the database) for everyone else
+2. Heavy CSV exports in a loop — exports need a stricter limit than browsing
+3. Password guessing on the public login endpoint
+4. A Redis outage turning into 429 for every user
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Token bucket
+- GIVEN user_rps 1 and user_burst 2
+  WHEN a user sends three requests at once
+  THEN two pass, the third is refused with retry-after ≈ 1s
+- WHEN one second passes THEN the next request passes
+- GIVEN two users THEN their buckets are independent
+
+SCENARIO 2: Groups and roles
+- GIVEN a user who exhausted the export bucket THEN regular requests still pass
+- GIVEN role_multipliers {admin: 2} THEN an admin gets twice the burst of an analyst
+- GIVEN the auth group THEN role multipliers do not apply
+
+SCENARIO 3: Reload
+- GIVEN a higher rate applied by SIGHUP THEN the bucket refills at it, counters are kept
+- GIVEN driver none THEN every request passes
+
+SCENARIO 4: Store failures
+- GIVEN a store error THEN the request passes and the error is returned for the log
+
+SCENARIO 5: Memory store
+- GIVEN buckets that refilled completely THEN they are pruned after a minute
+*/
+
+func testConfig() config.RateLimitConfig {
+	return config.RateLimitConfig{
+		Driver:      "memory",
+		UserRPS:     1,
+		UserBurst:   2,
+		ExportRPS:   0.1,
+		ExportBurst: 1,
+		AuthRPS:     1,
+		AuthBurst:   3,
+	}
+}
+
+func newTestLimiter(t *testing.T, cfg config.RateLimitConfig) (*Limiter, *time.Time) {
+	t.Helper()
+	l, err := New(cfg, testutil.NewMockLogger())
+	require.NoError(t, err)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func allow(t *testing.T, l *Limiter, group, role, key string) (bool, time.Duration) {
+	t.Helper()
+	allowed, retryAfter, err := l.Allow(context.Background(), group, role, key)
+	require.NoError(t, err)
+	return allowed, retryAfter
+}
+
+func TestLimiter_TokenBucket(t *testing.T) {
+	l, now := newTestLimiter(t, testConfig())
+
+	ok, _ := allow(t, l, GroupUser, "analyst", "user:1")
+	assert.True(t, ok)
+	ok, _ = allow(t, l, GroupUser, "analyst", "user:1")
+	assert.True(t, ok)
+	ok, retryAfter := allow(t, l, GroupUser, "analyst", "user:1")
+	assert.False(t, ok)
+	assert.Equal(t, time.Second, retryAfter)
+
+	ok, _ = allow(t, l, GroupUser, "analyst", "user:2")
+	assert.True(t, ok, "other users have their own bucket")
+
+	*now = now.Add(time.Second)
+	ok, _ = allow(t, l, GroupUser, "analyst", "user:1")
+	assert.True(t, ok)
+}
+
+func TestLimiter_GroupsAndRoles(t *testing.T) {
+	cfg := testConfig()
+	cfg.RoleMultipliers = map[string]float64{"admin": 2}
+	l, _ := newTestLimiter(t, cfg)
+
+	ok, _ := allow(t, l, GroupExport, "analyst", "user:1")
+	assert.True(t, ok)
+	ok, retryAfter := allow(t, l, GroupExport, "analyst", "user:1")
+	assert.False(t, ok)
+	assert.Equal(t, 10*time.Second, retryAfter)
+	ok, _ = allow(t, l, GroupUser, "analyst", "user:1")
+	assert.True(t, ok, "the export bucket does not consume regular requests")
+
+	for i := 0; i < 4; i++ {
+		ok, _ = allow(t, l, GroupUser, "admin", "user:9")
+		assert.True(t, ok, "request %d", i+1)
+	}
+	ok, _ = allow(t, l, GroupUser, "admin", "user:9")
+	assert.False(t, ok)
+
+	assert.Equal(t, Rule{RPS: 1, Burst: 3}, l.state.Load().rule(GroupAuth, "admin"))
+}
+
+func TestLimiter_Apply(t *testing.T) {
+	l, now := newTestLimiter(t, testConfig())
+	store := l.state.Load().store
+
+	allow(t, l, GroupUser, "analyst", "user:1")
+	allow(t, l, GroupUser, "analyst", "user:1")
+	ok, _ := allow(t, l, GroupUser, "analyst", "user:1")
+	assert.False(t, ok)
+
+	cfg := testConfig()
+	cfg.UserRPS = 10
+	require.NoError(t, l.Apply(cfg))
+	assert.Same(t, store, l.state.Load().store, "same driver keeps the counters")
+	ok, _ = allow(t, l, GroupUser, "analyst", "user:1")
+	assert.False(t, ok, "the bucket is still empty")
+	*now = now.Add(100 * time.Millisecond)
+	ok, _ = allow(t, l, GroupUser, "analyst", "user:1")
+	assert.True(t, ok, "refilled at the new rate")
+
+	cfg.Driver = "none"
+	require.NoError(t, l.Apply(cfg))
+	for i := 0; i < 10; i++ {
+		ok, _ = allow(t, l, GroupUser, "analyst", "user:1")
+		assert.True(t, ok)
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, Rule, time.Time) (time.Duration, error) {
+	return 0, errors.New("connection refused")
+}
+
+func (failingStore) Close() error { return nil }
+
+func TestLimiter_StoreErrorFailsOpen(t *testing.T) {
+	l, _ := newTestLimiter(t, testConfig())
+	l.state.Store(&limiterState{cfg: testConfig(), store: failingStore{}})
+
+	allowed, _, err := l.Allow(context.Background(), GroupUser, "analyst", "user:1")
+
+	assert.True(t, allowed)
+	assert.Error(t, err)
+}
+
+func TestMemory_PrunesFullBuckets(t *testing.T) {
+	m := NewMemory()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	rule := Rule{RPS: 1, Burst: 2}
+
+	_, err := m.Take(context.Background(), "user:user:1", rule, now)
+	require.NoError(t, err)
+	_, err = m.Take(context.Background(), "user:user:2", rule, now.Add(59*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 2, m.Len())
+
+	_, err = m.Take(context.Background(), "user:user:3", rule, now.Add(61*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 2, m.Len(), "user:1 refilled and was pruned, user:2 is still refilling")
+}
@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/redisconn"
+)
+
+// gcraScript — корзина токенов в виде GCRA: в ключе хранится теоретическое
+// время прибытия (TAT) следующего запроса в микросекундах. Запрос пропускается,
+// если новый TAT опережает now не больше чем на ёмкость корзины; иначе
+// возвращается ожидание в микросекундах. Ключ живёт, пока корзина не полна.
+//
+// ARGV: now, интервал между токенами, ёмкость × интервал (всё в микросекундах).
+// TAT записывается через string.format: число Lua в строку теряет точность.
+const gcraScript = `
+local now = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local tolerance = tonumber(ARGV[3])
+local tat = tonumber(redis.call('GET', KEYS[1])) or now
+if tat < now then tat = now end
+local next_tat = tat + interval
+local wait = next_tat - tolerance - now
+if wait > 0 then return wait end
+redis.call('SET', KEYS[1], string.format('%d', next_tat), 'PX', math.ceil((next_tat - now) / 1000))
+return 0
+`
+
+// redisStore хранит корзину в ключе <key_prefix>:<группа>:<ключ>. Время берётся
+// у реплики, а не у Redis: расхождение часов реплик сдвигает лимит на величину
+// расхождения, что для лимитов в секундах несущественно.
+type redisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func newRedis(cfg config.RateLimitRedisConfig) (*redisStore, error) {
+	client, err := redisconn.New(cfg.URL, cfg.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("rate_limit.redis.url: %w", err)
+	}
+	return &redisStore{client: client, prefix: cfg.KeyPrefix}, nil
+}
+
+func (r *redisStore) Take(ctx context.Context, key string, rule Rule, now time.Time) (time.Duration, error) {
+	interval := max(1, int64(1e6/rule.RPS))
+	wait, err := r.client.Eval(ctx, gcraScript, []string{r.prefix + ":" + key},
+		now.UnixMicro(), interval, interval*int64(rule.Burst)).Int64()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(wait) * time.Microsecond, nil
+}
+
+func (r *redisStore) Close() error {
+	return r.client.Close()
+}
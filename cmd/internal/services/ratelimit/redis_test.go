@@ -0,0 +1,121 @@
+package ratelimit
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+)
+
+// startFakeRedis отвечает на каждую команду очередным ответом из replies и
+// отправляет разобранную команду в канал. Скрипт GCRA не исполняется —
+// проверяются аргументы и разбор ответа. На рукопожатие go-redis (HELLO,
+// CLIENT ...) фейк отвечает ошибкой, и клиент продолжает по RESP2.
+func startFakeRedis(t *testing.T, replies ...string) (string, <-chan []string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	commands := make(chan []string, len(replies))
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for i := 0; i < len(replies); {
+			args, err := readCommand(reader)
+			if err != nil {
+				return
+			}
+			// go-redis отправляет имена команд в нижнем регистре
+			args[0] = strings.ToUpper(args[0])
+			reply := "-ERR unknown command\r\n"
+			if args[0] != "HELLO" && args[0] != "CLIENT" {
+				commands <- args
+				reply = replies[i]
+				i++
+			}
+			if _, err := io.WriteString(conn, reply); err != nil {
+				return
+			}
+		}
+	}()
+	return listener.Addr().String(), commands
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, count)
+	for i := range args {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "$")))
+		if err != nil {
+			return nil, err
+		}
+		payload := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			return nil, err
+		}
+		args[i] = string(payload[:size])
+	}
+	return args, nil
+}
+
+func TestRedis_Take(t *testing.T) {
+	addr, commands := startFakeRedis(t, ":0\r\n", ":250000\r\n")
+	r, err := newRedis(config.RateLimitRedisConfig{URL: "redis://" + addr, KeyPrefix: "tenders:ratelimit", Timeout: time.Second})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = r.Close() })
+
+	now := time.UnixMicro(1_700_000_000_000_000)
+	rule := Rule{RPS: 2, Burst: 5}
+
+	retryAfter, err := r.Take(context.Background(), "export:user:7", rule, now)
+	require.NoError(t, err)
+	assert.Zero(t, retryAfter)
+
+	args := <-commands
+	require.Len(t, args, 7)
+	assert.Equal(t, "EVAL", args[0])
+	assert.Equal(t, "1", args[2])
+	assert.Equal(t, "tenders:ratelimit:export:user:7", args[3])
+	assert.Equal(t, []string{"1700000000000000", "500000", "2500000"}, args[4:])
+
+	retryAfter, err = r.Take(context.Background(), "export:user:7", rule, now)
+	require.NoError(t, err)
+	assert.Equal(t, 250*time.Millisecond, retryAfter)
+}
+
+func TestRedis_ScriptError(t *testing.T) {
+	addr, _ := startFakeRedis(t, "-NOSCRIPT scripting disabled\r\n")
+	r, err := newRedis(config.RateLimitRedisConfig{URL: "redis://" + addr, KeyPrefix: "p", Timeout: time.Second})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = r.Close() })
+
+	_, err = r.Take(context.Background(), "user:user:1", Rule{RPS: 1, Burst: 1}, time.Now())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "NOSCRIPT")
+}
@@ -0,0 +1,109 @@
+package refcache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// Memory — хранилище в памяти процесса (driver: memory). При достижении
+// maxEntries сначала удаляются истёкшие значения, затем — произвольное.
+type Memory struct {
+	mu         sync.Mutex
+	groups     map[string]map[string]memoryEntry
+	size       int
+	maxEntries int
+	now        func() time.Time
+}
+
+// NewMemory создаёт хранилище в памяти не больше чем на maxEntries значений.
+func NewMemory(maxEntries int) *Memory {
+	return &Memory{
+		groups:     make(map[string]map[string]memoryEntry),
+		maxEntries: maxEntries,
+		now:        time.Now,
+	}
+}
+
+func (m *Memory) Get(_ context.Context, group, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.groups[group][key]
+	if !ok {
+		return nil, false, nil
+	}
+	if !m.now().Before(entry.expiresAt) {
+		m.delete(group, key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (m *Memory) Set(_ context.Context, group, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries, ok := m.groups[group]
+	if !ok {
+		entries = make(map[string]memoryEntry)
+		m.groups[group] = entries
+	}
+	if _, exists := entries[key]; !exists {
+		if m.size >= m.maxEntries {
+			m.evict()
+		}
+		m.size++
+	}
+	entries[key] = memoryEntry{value: value, expiresAt: m.now().Add(ttl)}
+	return nil
+}
+
+func (m *Memory) Invalidate(_ context.Context, group string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.size -= len(m.groups[group])
+	delete(m.groups, group)
+	return nil
+}
+
+// Len возвращает число значений, включая ещё не удалённые истёкшие.
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.size
+}
+
+func (m *Memory) Close() error { return nil }
+
+// evict освобождает место: удаляет истёкшие значения, а если их нет — одно произвольное.
+func (m *Memory) evict() {
+	now := m.now()
+	for group, entries := range m.groups {
+		for key, entry := range entries {
+			if !now.Before(entry.expiresAt) {
+				m.delete(group, key)
+			}
+		}
+	}
+	if m.size < m.maxEntries {
+		return
+	}
+	for group, entries := range m.groups {
+		for key := range entries {
+			m.delete(group, key)
+			return
+		}
+	}
+}
+
+func (m *Memory) delete(group, key string) {
+	delete(m.groups[group], key)
+	m.size--
+}
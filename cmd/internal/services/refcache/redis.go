@@ -0,0 +1,63 @@
+package refcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/redisconn"
+)
+
+// redisBackend хранит группу в хеше Redis <key_prefix>:<group> (поле — ключ
+// ответа), поэтому сброс группы — один DEL. TTL ставится на весь хеш при
+// каждой записи: группа живёт TTL с последнего промаха. HSET и PEXPIRE
+// отправляются одним конвейером.
+type redisBackend struct {
+	client *redis.Client
+	prefix string
+}
+
+func newRedis(cfg config.RefCacheRedisConfig) (*redisBackend, error) {
+	client, err := redisconn.New(cfg.URL, cfg.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("ref_cache.redis.url: %w", err)
+	}
+	return &redisBackend{client: client, prefix: cfg.KeyPrefix}, nil
+}
+
+func (r *redisBackend) groupKey(group string) string {
+	return r.prefix + ":" + group
+}
+
+func (r *redisBackend) Get(ctx context.Context, group, key string) ([]byte, bool, error) {
+	value, err := r.client.HGet(ctx, r.groupKey(group), key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (r *redisBackend) Set(ctx context.Context, group, key string, value []byte, ttl time.Duration) error {
+	groupKey := r.groupKey(group)
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, groupKey, key, value)
+		pipe.PExpire(ctx, groupKey, max(ttl, time.Millisecond))
+		return nil
+	})
+	return err
+}
+
+func (r *redisBackend) Invalidate(ctx context.Context, group string) error {
+	return r.client.Del(ctx, r.groupKey(group)).Err()
+}
+
+func (r *redisBackend) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,224 @@
+package refcache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+)
+
+// fakeRedis — минимальный сервер Redis: хеши, PEXPIRE (запоминается, не применяется),
+// AUTH с паролем "s3cret" и SELECT. На рукопожатие go-redis (HELLO, CLIENT ...)
+// отвечает ошибкой, и клиент продолжает по RESP2. Журнал команд без
+// рукопожатия — для проверок.
+type fakeRedis struct {
+	mu       sync.Mutex
+	hashes   map[string]map[string]string
+	expires  map[string]string
+	commands []string
+	// closeNext закрывает соединение вместо ответа на следующую команду
+	closeNext bool
+}
+
+func startFakeRedis(t *testing.T) (*fakeRedis, string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	server := &fakeRedis{hashes: map[string]map[string]string{}, expires: map[string]string{}}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server, listener.Addr().String()
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		reply, closeConn := f.handle(args)
+		if closeConn {
+			return
+		}
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, count)
+	for i := range args {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "$")))
+		if err != nil {
+			return nil, err
+		}
+		payload := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			return nil, err
+		}
+		args[i] = string(payload[:size])
+	}
+	return args, nil
+}
+
+func (f *fakeRedis) handle(args []string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closeNext {
+		f.closeNext = false
+		return "", true
+	}
+	// go-redis отправляет имена команд в нижнем регистре
+	args[0] = strings.ToUpper(args[0])
+	if args[0] == "HELLO" || args[0] == "CLIENT" {
+		return "-ERR unknown command\r\n", false
+	}
+	f.commands = append(f.commands, strings.Join(args, " "))
+
+	switch args[0] {
+	case "AUTH":
+		if args[len(args)-1] != "s3cret" {
+			return "-WRONGPASS invalid password\r\n", false
+		}
+		return "+OK\r\n", false
+	case "SELECT":
+		return "+OK\r\n", false
+	case "HGET":
+		value, ok := f.hashes[args[1]][args[2]]
+		if !ok {
+			return "$-1\r\n", false
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value), false
+	case "HSET":
+		if f.hashes[args[1]] == nil {
+			f.hashes[args[1]] = map[string]string{}
+		}
+		f.hashes[args[1]][args[2]] = args[3]
+		return ":1\r\n", false
+	case "PEXPIRE":
+		f.expires[args[1]] = args[2]
+		return ":1\r\n", false
+	case "DEL":
+		delete(f.hashes, args[1])
+		return ":1\r\n", false
+	default:
+		return "-ERR unknown command\r\n", false
+	}
+}
+
+func newTestRedis(t *testing.T, url string) *redisBackend {
+	t.Helper()
+	r, err := newRedis(config.RefCacheRedisConfig{URL: url, KeyPrefix: "tenders:refcache", Timeout: time.Second})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = r.Close() })
+	return r
+}
+
+func TestRedis_SetGetInvalidate(t *testing.T) {
+	ctx := context.Background()
+	server, addr := startFakeRedis(t)
+	r := newTestRedis(t, "redis://:s3cret@"+addr+"/2")
+
+	_, found, err := r.Get(ctx, GroupTenderTypes, "page=1")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	require.NoError(t, r.Set(ctx, GroupTenderTypes, "page=1", []byte("[{\"title\":\"Стройка\"}]\r\n"), 10*time.Minute))
+	value, found, err := r.Get(ctx, GroupTenderTypes, "page=1")
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, "[{\"title\":\"Стройка\"}]\r\n", string(value), "binary-safe bulk strings")
+
+	require.NoError(t, r.Invalidate(ctx, GroupTenderTypes))
+	_, found, err = r.Get(ctx, GroupTenderTypes, "page=1")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.Equal(t, "AUTH s3cret", server.commands[0])
+	assert.Equal(t, "SELECT 2", server.commands[1])
+	assert.Equal(t, "600000", server.expires["tenders:refcache:tender_types"])
+	assert.Contains(t, server.commands, "DEL tenders:refcache:tender_types")
+}
+
+func TestRedis_ReconnectsAfterServerClosedConnection(t *testing.T) {
+	ctx := context.Background()
+	server, addr := startFakeRedis(t)
+	r := newTestRedis(t, "redis://"+addr)
+	require.NoError(t, r.Set(ctx, GroupUnits, "all", []byte("[]"), time.Minute))
+
+	server.mu.Lock()
+	server.closeNext = true
+	server.mu.Unlock()
+
+	value, found, err := r.Get(ctx, GroupUnits, "all")
+
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "[]", string(value))
+}
+
+func TestRedis_WrongPassword(t *testing.T) {
+	_, addr := startFakeRedis(t)
+	r := newTestRedis(t, "redis://user:wrong@"+addr)
+
+	_, _, err := r.Get(context.Background(), GroupUnits, "all")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "WRONGPASS")
+}
+
+func TestRedis_Unavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	r := newTestRedis(t, "redis://"+addr)
+	_, _, err = r.Get(context.Background(), GroupUnits, "all")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connection refused")
+}
+
+func TestNewRedis_InvalidDB(t *testing.T) {
+	_, err := newRedis(config.RefCacheRedisConfig{URL: "redis://h:6379/abc", KeyPrefix: "p", Timeout: time.Second})
+	require.Error(t, err)
+}
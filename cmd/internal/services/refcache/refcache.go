@@ -0,0 +1,223 @@
+// Package refcache кэширует ответы справочников, которые меняются редко, но
+// запрашиваются при каждой загрузке страницы: типы, разделы и категории
+// тендеров, единицы измерения.
+//
+// Значения — готовые JSON-ответы, сгруппированные по справочнику. Хранилище
+// выбирается настройкой ref_cache.driver:
+//
+//   - none   — кэш выключен, каждый запрос идёт в БД;
+//   - memory — в памяти процесса (по умолчанию); у каждой реплики свой кэш;
+//   - redis  — общий для всех реплик, поэтому сброс на одной виден на всех.
+//
+// Обработчики, меняющие справочник, вызывают Invalidate для всех зависимых
+// групп (см. Dependents). TTL ограничивает устаревание при изменениях в обход
+// API, например напрямую в БД. Ошибка хранилища не ломает запрос: значение
+// считается промахом и читается из БД.
+package refcache
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// Группы кэша — по одной на справочник.
+const (
+	GroupTenderTypes      = "tender_types"
+	GroupTenderChapters   = "tender_chapters"
+	GroupTenderCategories = "tender_categories"
+	GroupUnits            = "units"
+)
+
+// Groups — все группы в порядке отображения в статистике.
+var Groups = []string{GroupTenderTypes, GroupTenderChapters, GroupTenderCategories, GroupUnits}
+
+// Dependents возвращает группу и группы, ответы которых включают её данные:
+// список разделов содержит названия типов, список категорий — названия разделов.
+func Dependents(group string) []string {
+	switch group {
+	case GroupTenderTypes:
+		return []string{GroupTenderTypes, GroupTenderChapters, GroupTenderCategories}
+	case GroupTenderChapters:
+		return []string{GroupTenderChapters, GroupTenderCategories}
+	default:
+		return []string{group}
+	}
+}
+
+// Backend — хранилище кэша. Реализации безопасны для параллельного использования.
+type Backend interface {
+	// Get возвращает значение; found=false, если ключа нет или истёк TTL.
+	Get(ctx context.Context, group, key string) (value []byte, found bool, err error)
+	Set(ctx context.Context, group, key string, value []byte, ttl time.Duration) error
+	// Invalidate удаляет все значения группы.
+	Invalidate(ctx context.Context, group string) error
+	Close() error
+}
+
+// counter — необязательный интерфейс хранилища, знающего число своих значений.
+type counter interface {
+	Len() int
+}
+
+type groupStats struct {
+	hits, misses, sets, invalidations, errors atomic.Int64
+}
+
+// Service — кэш справочников со статистикой. nil-хранилище (driver: none)
+// означает, что кэш выключен: Get всегда промах, Set и Invalidate ничего не делают.
+type Service struct {
+	driver  string
+	backend Backend
+	ttls    map[string]time.Duration
+	stats   map[string]*groupStats
+	logger  logging.Logger
+}
+
+// New создаёт кэш по настройкам ref_cache.
+func New(cfg config.RefCacheConfig, logger logging.Logger) (*Service, error) {
+	var backend Backend
+	switch cfg.Driver {
+	case "none", "":
+	case "memory":
+		backend = NewMemory(cfg.MaxEntries)
+	case "redis":
+		r, err := newRedis(cfg.Redis)
+		if err != nil {
+			return nil, err
+		}
+		backend = r
+	default:
+		return nil, fmt.Errorf("неизвестный драйвер кэша справочников %q", cfg.Driver)
+	}
+
+	ttls := map[string]time.Duration{
+		GroupTenderTypes:      cfg.TenderTypesTTL,
+		GroupTenderChapters:   cfg.TenderChaptersTTL,
+		GroupTenderCategories: cfg.TenderCategoriesTTL,
+		GroupUnits:            cfg.UnitsTTL,
+	}
+	if backend != nil {
+		logger.Infof("Кэш справочников включён: драйвер %s", cfg.Driver)
+	}
+	return NewService(cfg.Driver, backend, ttls, logger), nil
+}
+
+// NewService создаёт кэш поверх готового хранилища (nil — кэш выключен).
+func NewService(driver string, backend Backend, ttls map[string]time.Duration, logger logging.Logger) *Service {
+	stats := make(map[string]*groupStats, len(Groups))
+	for _, group := range Groups {
+		stats[group] = &groupStats{}
+	}
+	if driver == "" {
+		driver = "none"
+	}
+	return &Service{
+		driver:  driver,
+		backend: backend,
+		ttls:    ttls,
+		stats:   stats,
+		logger:  logger.WithField("component", "refcache"),
+	}
+}
+
+// Get возвращает закэшированный ответ. Ошибка хранилища логируется и считается промахом.
+func (s *Service) Get(ctx context.Context, group, key string) ([]byte, bool) {
+	st := s.groupStats(group)
+	if s.backend == nil {
+		st.misses.Add(1)
+		return nil, false
+	}
+
+	value, found, err := s.backend.Get(ctx, group, key)
+	if err != nil {
+		st.errors.Add(1)
+		s.logger.Warnf("Чтение кэша %s/%s: %v", group, key, err)
+		found = false
+	}
+	if !found {
+		st.misses.Add(1)
+		return nil, false
+	}
+	st.hits.Add(1)
+	return value, true
+}
+
+// Set сохраняет ответ на TTL группы. Ошибка хранилища только логируется.
+func (s *Service) Set(ctx context.Context, group, key string, value []byte) {
+	if s.backend == nil {
+		return
+	}
+	st := s.groupStats(group)
+	if err := s.backend.Set(ctx, group, key, value, s.ttls[group]); err != nil {
+		st.errors.Add(1)
+		s.logger.Warnf("Запись в кэш %s/%s: %v", group, key, err)
+		return
+	}
+	st.sets.Add(1)
+}
+
+// Invalidate сбрасывает группы после изменения справочника. Ошибка хранилища
+// логируется: устаревшие значения в этом случае доживут до истечения TTL.
+func (s *Service) Invalidate(ctx context.Context, groups ...string) {
+	if s.backend == nil {
+		return
+	}
+	for _, group := range groups {
+		st := s.groupStats(group)
+		if err := s.backend.Invalidate(ctx, group); err != nil {
+			st.errors.Add(1)
+			s.logger.Errorf("Сброс кэша %s: %v", group, err)
+			continue
+		}
+		st.invalidations.Add(1)
+	}
+}
+
+// Stats возвращает счётчики кэша с момента запуска этого экземпляра API.
+func (s *Service) Stats() api_models.RefCacheStatsResponse {
+	response := api_models.RefCacheStatsResponse{
+		Driver: s.driver,
+		Groups: make([]api_models.RefCacheGroupStats, 0, len(Groups)),
+	}
+	if c, ok := s.backend.(counter); ok {
+		entries := c.Len()
+		response.Entries = &entries
+	}
+	for _, group := range Groups {
+		st := s.stats[group]
+		response.Groups = append(response.Groups, api_models.RefCacheGroupStats{
+			Group:         group,
+			TTL:           s.ttls[group].String(),
+			Hits:          st.hits.Load(),
+			Misses:        st.misses.Load(),
+			Sets:          st.sets.Load(),
+			Invalidations: st.invalidations.Load(),
+			Errors:        st.errors.Load(),
+		})
+	}
+	return response
+}
+
+// Close освобождает соединение с хранилищем.
+func (s *Service) Close() error {
+	if s.backend == nil {
+		return nil
+	}
+	return s.backend.Close()
+}
+
+// groupStats возвращает счётчики группы. Группы известны заранее, поэтому
+// неизвестная группа — ошибка программиста.
+func (s *Service) groupStats(group string) *groupStats {
+	st, ok := s.stats[group]
+	if !ok {
+		panic(fmt.Sprintf("refcache: неизвестная группа %q", group))
+	}
+	return st
+}
@@ -0,0 +1,204 @@
+package refcache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR REFERENCE CACHE (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Stale dropdowns — after a type/chapter/category/unit changes, lists must not be served from cache
+2. Broken pages — a cache outage must degrade to database reads, not to errors
+3. Memory growth — the in-memory cache must stay within max_entries
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Hits and misses
+- GIVEN an empty cache
+  WHEN Get, Set, Get are called
+  THEN miss, then hit with the stored value; stats count both
+
+SCENARIO 2: Invalidation
+- GIVEN cached types, chapters and categories
+  WHEN types are invalidated with Dependents
+  THEN all three groups are empty; units stay cached
+- GIVEN a chapter change → chapters and categories are dropped, types stay
+
+SCENARIO 3: Memory backend
+- GIVEN an entry older than its TTL → miss
+- GIVEN max_entries reached → expired entries are evicted first, size never exceeds the limit
+
+SCENARIO 4: Disabled or failing cache
+- GIVEN driver none → every Get is a miss, Set is a no-op
+- GIVEN a backend error → Get is a miss, the error is counted
+*/
+
+func testTTLs() map[string]time.Duration {
+	return map[string]time.Duration{
+		GroupTenderTypes:      10 * time.Minute,
+		GroupTenderChapters:   10 * time.Minute,
+		GroupTenderCategories: 10 * time.Minute,
+		GroupUnits:            time.Minute,
+	}
+}
+
+func newMemoryService() *Service {
+	return NewService("memory", NewMemory(100), testTTLs(), testutil.NewMockLogger())
+}
+
+func TestService_HitAndMiss(t *testing.T) {
+	ctx := context.Background()
+	cache := newMemoryService()
+
+	_, found := cache.Get(ctx, GroupTenderTypes, "page=1")
+	assert.False(t, found)
+
+	cache.Set(ctx, GroupTenderTypes, "page=1", []byte(`[{"id":1}]`))
+	value, found := cache.Get(ctx, GroupTenderTypes, "page=1")
+
+	require.True(t, found)
+	assert.Equal(t, `[{"id":1}]`, string(value))
+
+	stats := cache.Stats()
+	assert.Equal(t, "memory", stats.Driver)
+	require.NotNil(t, stats.Entries)
+	assert.Equal(t, 1, *stats.Entries)
+	require.Len(t, stats.Groups, len(Groups))
+	types := stats.Groups[0]
+	assert.Equal(t, GroupTenderTypes, types.Group)
+	assert.Equal(t, "10m0s", types.TTL)
+	assert.Equal(t, int64(1), types.Hits)
+	assert.Equal(t, int64(1), types.Misses)
+	assert.Equal(t, int64(1), types.Sets)
+}
+
+func TestService_InvalidateDependents(t *testing.T) {
+	ctx := context.Background()
+	cache := newMemoryService()
+	for _, group := range Groups {
+		cache.Set(ctx, group, "all", []byte("[]"))
+	}
+
+	// WHEN a tender type changes
+	cache.Invalidate(ctx, Dependents(GroupTenderTypes)...)
+
+	// THEN chapters and categories (which show type titles) are dropped too
+	for _, group := range []string{GroupTenderTypes, GroupTenderChapters, GroupTenderCategories} {
+		_, found := cache.Get(ctx, group, "all")
+		assert.False(t, found, group)
+	}
+	_, found := cache.Get(ctx, GroupUnits, "all")
+	assert.True(t, found)
+	assert.Equal(t, int64(1), cache.Stats().Groups[1].Invalidations)
+}
+
+func TestDependents(t *testing.T) {
+	assert.Equal(t, []string{GroupTenderChapters, GroupTenderCategories}, Dependents(GroupTenderChapters))
+	assert.Equal(t, []string{GroupTenderCategories}, Dependents(GroupTenderCategories))
+	assert.Equal(t, []string{GroupUnits}, Dependents(GroupUnits))
+}
+
+func TestMemory_TTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	memory := NewMemory(10)
+	memory.now = func() time.Time { return now }
+
+	require.NoError(t, memory.Set(ctx, GroupUnits, "all", []byte("[]"), time.Minute))
+
+	now = now.Add(59 * time.Second)
+	_, found, err := memory.Get(ctx, GroupUnits, "all")
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	now = now.Add(time.Second)
+	_, found, err = memory.Get(ctx, GroupUnits, "all")
+	require.NoError(t, err)
+	assert.False(t, found)
+	assert.Zero(t, memory.Len())
+}
+
+func TestMemory_MaxEntries(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	memory := NewMemory(2)
+	memory.now = func() time.Time { return now }
+
+	require.NoError(t, memory.Set(ctx, GroupUnits, "expiring", []byte("1"), time.Second))
+	require.NoError(t, memory.Set(ctx, GroupTenderTypes, "fresh", []byte("2"), time.Hour))
+	now = now.Add(time.Minute)
+
+	// WHEN the limit is reached
+	require.NoError(t, memory.Set(ctx, GroupTenderTypes, "new", []byte("3"), time.Hour))
+
+	// THEN the expired entry is evicted, the fresh one survives
+	assert.Equal(t, 2, memory.Len())
+	_, found, _ := memory.Get(ctx, GroupTenderTypes, "fresh")
+	assert.True(t, found)
+
+	// WHEN nothing has expired, an arbitrary entry makes room
+	require.NoError(t, memory.Set(ctx, GroupTenderTypes, "newest", []byte("4"), time.Hour))
+	assert.Equal(t, 2, memory.Len())
+
+	// Overwriting an existing key does not grow the cache
+	require.NoError(t, memory.Set(ctx, GroupTenderTypes, "newest", []byte("5"), time.Hour))
+	assert.Equal(t, 2, memory.Len())
+}
+
+func TestNew_DriverNone_Disabled(t *testing.T) {
+	ctx := context.Background()
+	cache, err := New(config.RefCacheConfig{Driver: "none"}, testutil.NewMockLogger())
+	require.NoError(t, err)
+
+	cache.Set(ctx, GroupTenderTypes, "all", []byte("[]"))
+	_, found := cache.Get(ctx, GroupTenderTypes, "all")
+
+	assert.False(t, found)
+	stats := cache.Stats()
+	assert.Equal(t, "none", stats.Driver)
+	assert.Nil(t, stats.Entries)
+	assert.Zero(t, stats.Groups[0].Sets)
+	require.NoError(t, cache.Close())
+}
+
+// failingBackend — хранилище, которое всегда возвращает ошибку.
+type failingBackend struct{}
+
+var errBackendDown = errors.New("backend down")
+
+func (failingBackend) Get(context.Context, string, string) ([]byte, bool, error) {
+	return nil, false, errBackendDown
+}
+func (failingBackend) Set(context.Context, string, string, []byte, time.Duration) error {
+	return errBackendDown
+}
+func (failingBackend) Invalidate(context.Context, string) error { return errBackendDown }
+func (failingBackend) Close() error                             { return nil }
+
+func TestService_BackendErrors_DegradeToMiss(t *testing.T) {
+	ctx := context.Background()
+	cache := NewService("redis", failingBackend{}, testTTLs(), testutil.NewMockLogger())
+
+	cache.Set(ctx, GroupUnits, "all", []byte("[]"))
+	_, found := cache.Get(ctx, GroupUnits, "all")
+	cache.Invalidate(ctx, GroupUnits)
+
+	assert.False(t, found)
+	units := cache.Stats().Groups[3]
+	assert.Equal(t, int64(3), units.Errors)
+	assert.Equal(t, int64(1), units.Misses)
+	assert.Zero(t, units.Sets)
+	assert.Zero(t, units.Invalidations)
+}
@@ -0,0 +1,459 @@
+// Package refimport загружает справочники из таблицы: типы тендеров, разделы,
+// категории и синонимы единиц измерения (POST /api/v1/admin/reference/import).
+//
+// Таксономию из сотен категорий неудобно заводить по одной через
+// POST /tender-categories, поэтому её готовят в Excel и загружают файлом.
+// Файл применяется целиком в одной транзакции или не применяется вовсе:
+// отчёт перечисляет каждую строку с действием (create/update/unchanged) или
+// ошибкой. Родитель может быть создан в том же файле — строки применяются по
+// уровням (типы → разделы → категории → синонимы) независимо от порядка в файле.
+package refimport
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/entities"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// maxUnitNameLength — длина unit_aliases.alias и units_of_measurement.normalized_name.
+const maxUnitNameLength = 50
+
+// Коды ошибок строк (ReferenceImportRow.Code).
+const (
+	codeInvalidKind      = "invalid_kind"
+	codeRequired         = "required"
+	codeParentNotAllowed = "parent_not_allowed"
+	codeDuplicate        = "duplicate"
+	codeTooLong          = "too_long"
+	codeParentNotFound   = "parent_not_found"
+	codeUnitName         = "unit_name"
+	codeForbidden        = "forbidden"
+)
+
+// kindOrder — порядок применения: родитель создаётся раньше детей.
+var kindOrder = map[string]int{
+	api_models.ReferenceKindType:      0,
+	api_models.ReferenceKindChapter:   1,
+	api_models.ReferenceKindCategory:  2,
+	api_models.ReferenceKindUnitAlias: 3,
+}
+
+// kindSynonyms — русские названия видов строк.
+var kindSynonyms = map[string]string{
+	"тип":       api_models.ReferenceKindType,
+	"раздел":    api_models.ReferenceKindChapter,
+	"категория": api_models.ReferenceKindCategory,
+	"синоним":   api_models.ReferenceKindUnitAlias,
+}
+
+// errRollback откатывает транзакцию dry-run или файла с ошибками.
+var errRollback = errors.New("reference import rolled back")
+
+// Options — режим загрузки.
+type Options struct {
+	DryRun bool // Проверить и показать действия, ничего не сохраняя
+	// UnitAliases разрешает строки unit_alias: синонимы единиц — часть
+	// каталога (право catalog:manage), а не справочников тендеров
+	UnitAliases bool
+}
+
+// ImportService применяет файл справочников.
+type ImportService struct {
+	store  db.Store
+	logger logging.Logger
+}
+
+// NewImportService создаёт новый экземпляр ImportService.
+func NewImportService(store db.Store, logger logging.Logger) *ImportService {
+	return &ImportService{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Import разбирает файл (xlsx или CSV) и применяет его в одной транзакции.
+// Нечитаемый файл — ValidationError без отчёта. Ошибки в строках при
+// применении — ValidationError с нарушениями по строкам (ничего не
+// сохранено); при DryRun отчёт с ошибками возвращается без ошибки.
+func (s *ImportService) Import(ctx context.Context, data []byte, opts Options) (*api_models.ReferenceImportReport, error) {
+	logger := s.logger.WithField("method", "Import")
+
+	records, err := parseFile(data)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &api_models.ReferenceImportReport{
+		DryRun: opts.DryRun,
+		Rows:   make([]api_models.ReferenceImportRow, len(records)),
+	}
+	for i, rec := range records {
+		report.Rows[i] = checkRecord(rec, opts)
+	}
+	markDuplicates(report.Rows)
+
+	// Строки без ошибок применяются по уровням, внутри уровня — в порядке файла
+	order := make([]int, 0, len(records))
+	for i, row := range report.Rows {
+		if row.Code == "" {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return kindOrder[report.Rows[order[a]].Kind] < kindOrder[report.Rows[order[b]].Kind]
+	})
+
+	err = s.store.ExecTx(ctx, func(q *db.Queries) error {
+		a := &applier{q: q, typeIDs: map[string]int64{}, chapterIDs: map[string]int64{}}
+		for _, i := range order {
+			if err := a.apply(ctx, &report.Rows[i]); err != nil {
+				return err
+			}
+		}
+		summarize(report)
+		// В dry-run записи тоже создаются: так строки видят родителей из того
+		// же файла. Откат не возвращает значения последовательностей id
+		if opts.DryRun || report.Summary.Errors > 0 {
+			return errRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRollback) {
+		return nil, fmt.Errorf("ошибка загрузки справочников: %w", err)
+	}
+
+	report.Valid = report.Summary.Errors == 0
+	report.Applied = report.Valid && !opts.DryRun
+	if !report.Valid && !opts.DryRun {
+		return nil, apierrors.NewValidationErrorWithDetails(rowErrors(report.Rows), "reference_import.invalid", report.Summary.Errors)
+	}
+
+	if report.Applied {
+		logger.Infof("Загружены справочники: строк %d, создано %d, обновлено %d, без изменений %d",
+			report.Summary.Total, report.Summary.Created, report.Summary.Updated, report.Summary.Unchanged)
+	}
+	return report, nil
+}
+
+// checkRecord нормализует строку и проверяет её без обращения к БД.
+func checkRecord(rec record, opts Options) api_models.ReferenceImportRow {
+	row := api_models.ReferenceImportRow{
+		Sheet:  rec.sheet,
+		Row:    rec.row,
+		Kind:   strings.ToLower(rec.kind),
+		Title:  rec.title,
+		Parent: rec.parent,
+	}
+	if kind, ok := kindSynonyms[row.Kind]; ok {
+		row.Kind = kind
+	}
+
+	if _, ok := kindOrder[row.Kind]; !ok {
+		if row.Kind == "" {
+			setError(&row, codeRequired, "reference_import.kind_required")
+		} else {
+			setError(&row, codeInvalidKind, "reference_import.invalid_kind", rec.kind)
+		}
+		return row
+	}
+
+	if row.Kind == api_models.ReferenceKindUnitAlias {
+		// Синонимы и имена единиц хранятся нормализованными, как при импорте КП
+		row.Title = entities.NormalizeUnitName(row.Title)
+		row.Parent = entities.NormalizeUnitName(row.Parent)
+	}
+
+	switch {
+	case row.Title == "":
+		setError(&row, codeRequired, "reference_import.title_required")
+	case row.Kind == api_models.ReferenceKindType && row.Parent != "":
+		setError(&row, codeParentNotAllowed, "reference_import.parent_not_allowed")
+	case row.Kind != api_models.ReferenceKindType && row.Parent == "":
+		setError(&row, codeRequired, "reference_import.parent_required")
+	case row.Kind == api_models.ReferenceKindUnitAlias && !opts.UnitAliases:
+		setError(&row, codeForbidden, "reference_import.unit_aliases_forbidden")
+	case row.Kind == api_models.ReferenceKindUnitAlias &&
+		(utf8.RuneCountInString(row.Title) > maxUnitNameLength || utf8.RuneCountInString(row.Parent) > maxUnitNameLength):
+		setError(&row, codeTooLong, "reference_import.too_long", maxUnitNameLength)
+	}
+	return row
+}
+
+// markDuplicates отмечает повторы вида и наименования: какая из строк верна,
+// решает автор файла.
+func markDuplicates(rows []api_models.ReferenceImportRow) {
+	first := make(map[string]int, len(rows))
+	for i := range rows {
+		row := &rows[i]
+		if row.Code != "" {
+			continue
+		}
+		key := row.Kind + "\x00" + row.Title
+		if j, ok := first[key]; ok {
+			setError(row, codeDuplicate, "reference_import.duplicate", rowLabel(rows[j]))
+			continue
+		}
+		first[key] = i
+	}
+}
+
+// applier применяет строки в транзакции и запоминает id типов и разделов.
+type applier struct {
+	q          *db.Queries
+	typeIDs    map[string]int64
+	chapterIDs map[string]int64
+}
+
+func (a *applier) apply(ctx context.Context, row *api_models.ReferenceImportRow) error {
+	switch row.Kind {
+	case api_models.ReferenceKindType:
+		return a.applyType(ctx, row)
+	case api_models.ReferenceKindChapter:
+		return a.applyChapter(ctx, row)
+	case api_models.ReferenceKindCategory:
+		return a.applyCategory(ctx, row)
+	default:
+		return a.applyUnitAlias(ctx, row)
+	}
+}
+
+func (a *applier) applyType(ctx context.Context, row *api_models.ReferenceImportRow) error {
+	_, found, err := a.typeID(ctx, row.Title)
+	if err != nil {
+		return err
+	}
+	if found {
+		row.Action = api_models.ReferenceActionUnchanged
+		return nil
+	}
+
+	created, err := a.q.CreateTenderType(ctx, row.Title)
+	if err != nil {
+		return fmt.Errorf("ошибка создания типа '%s': %w", row.Title, err)
+	}
+	a.typeIDs[row.Title] = created.ID
+	row.Action = api_models.ReferenceActionCreate
+	return nil
+}
+
+func (a *applier) applyChapter(ctx context.Context, row *api_models.ReferenceImportRow) error {
+	typeID, found, err := a.typeID(ctx, row.Parent)
+	if err != nil {
+		return err
+	}
+	if !found {
+		setError(row, codeParentNotFound, "reference_import.type_not_found", row.Parent)
+		return nil
+	}
+
+	chapter, err := a.q.GetTenderChapterByTitle(ctx, row.Title)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		created, err := a.q.CreateTenderChapter(ctx, db.CreateTenderChapterParams{Title: row.Title, TenderTypeID: typeID})
+		if err != nil {
+			return fmt.Errorf("ошибка создания раздела '%s': %w", row.Title, err)
+		}
+		a.chapterIDs[row.Title] = created.ID
+		row.Action = api_models.ReferenceActionCreate
+	case err != nil:
+		return fmt.Errorf("ошибка поиска раздела '%s': %w", row.Title, err)
+	case chapter.TenderTypeID == typeID:
+		a.chapterIDs[row.Title] = chapter.ID
+		row.Action = api_models.ReferenceActionUnchanged
+	default:
+		if _, err := a.q.UpdateTenderChapter(ctx, db.UpdateTenderChapterParams{
+			ID:           chapter.ID,
+			TenderTypeID: sql.NullInt64{Int64: typeID, Valid: true},
+		}); err != nil {
+			return fmt.Errorf("ошибка обновления раздела '%s': %w", row.Title, err)
+		}
+		a.chapterIDs[row.Title] = chapter.ID
+		row.Action = api_models.ReferenceActionUpdate
+	}
+	return nil
+}
+
+func (a *applier) applyCategory(ctx context.Context, row *api_models.ReferenceImportRow) error {
+	chapterID, found, err := a.chapterID(ctx, row.Parent)
+	if err != nil {
+		return err
+	}
+	if !found {
+		setError(row, codeParentNotFound, "reference_import.chapter_not_found", row.Parent)
+		return nil
+	}
+
+	category, err := a.q.GetTenderCategoryByTitle(ctx, row.Title)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if _, err := a.q.CreateTenderCategory(ctx, db.CreateTenderCategoryParams{Title: row.Title, TenderChapterID: chapterID}); err != nil {
+			return fmt.Errorf("ошибка создания категории '%s': %w", row.Title, err)
+		}
+		row.Action = api_models.ReferenceActionCreate
+	case err != nil:
+		return fmt.Errorf("ошибка поиска категории '%s': %w", row.Title, err)
+	case category.TenderChapterID == chapterID:
+		row.Action = api_models.ReferenceActionUnchanged
+	default:
+		if _, err := a.q.UpdateTenderCategory(ctx, db.UpdateTenderCategoryParams{
+			ID:              category.ID,
+			TenderChapterID: sql.NullInt64{Int64: chapterID, Valid: true},
+		}); err != nil {
+			return fmt.Errorf("ошибка обновления категории '%s': %w", row.Title, err)
+		}
+		row.Action = api_models.ReferenceActionUpdate
+	}
+	return nil
+}
+
+// applyUnitAlias привязывает синоним к единице. Синоним другой единицы
+// переносится (update); имя единицы синонимом стать не может — такие
+// единицы объединяются слиянием (POST /admin/units/merge).
+func (a *applier) applyUnitAlias(ctx context.Context, row *api_models.ReferenceImportRow) error {
+	unit, found, err := a.unit(ctx, row.Parent)
+	if err != nil {
+		return err
+	}
+	if !found {
+		setError(row, codeParentNotFound, "reference_import.unit_not_found", row.Parent)
+		return nil
+	}
+
+	if _, err := a.q.GetUnitOfMeasurementByNormalizedName(ctx, row.Title); err == nil {
+		setError(row, codeUnitName, "reference_import.alias_is_unit", row.Title)
+		return nil
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("ошибка поиска единицы измерения '%s': %w", row.Title, err)
+	}
+
+	current, err := a.q.GetUnitOfMeasurementByAlias(ctx, row.Title)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if _, err := a.q.CreateUnitAlias(ctx, db.CreateUnitAliasParams{Alias: row.Title, UnitID: unit.ID}); err != nil {
+			return fmt.Errorf("ошибка сохранения синонима '%s': %w", row.Title, err)
+		}
+		row.Action = api_models.ReferenceActionCreate
+	case err != nil:
+		return fmt.Errorf("ошибка поиска синонима '%s': %w", row.Title, err)
+	case current.ID == unit.ID:
+		row.Action = api_models.ReferenceActionUnchanged
+	default:
+		if _, err := a.q.MoveUnitAlias(ctx, db.MoveUnitAliasParams{UnitID: unit.ID, Alias: row.Title}); err != nil {
+			return fmt.Errorf("ошибка переноса синонима '%s': %w", row.Title, err)
+		}
+		row.Action = api_models.ReferenceActionUpdate
+	}
+	return nil
+}
+
+// typeID находит тип по наименованию: созданный этим файлом или из справочника.
+func (a *applier) typeID(ctx context.Context, title string) (int64, bool, error) {
+	if id, ok := a.typeIDs[title]; ok {
+		return id, true, nil
+	}
+	tenderType, err := a.q.GetTenderTypeByTitle(ctx, title)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("ошибка поиска типа '%s': %w", title, err)
+	}
+	a.typeIDs[title] = tenderType.ID
+	return tenderType.ID, true, nil
+}
+
+// chapterID находит раздел по наименованию: из этого файла или из справочника.
+func (a *applier) chapterID(ctx context.Context, title string) (int64, bool, error) {
+	if id, ok := a.chapterIDs[title]; ok {
+		return id, true, nil
+	}
+	chapter, err := a.q.GetTenderChapterByTitle(ctx, title)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("ошибка поиска раздела '%s': %w", title, err)
+	}
+	a.chapterIDs[title] = chapter.ID
+	return chapter.ID, true, nil
+}
+
+// unit находит единицу по имени, затем по синониму — как импорт КП.
+func (a *applier) unit(ctx context.Context, name string) (db.UnitsOfMeasurement, bool, error) {
+	unit, err := a.q.GetUnitOfMeasurementByNormalizedName(ctx, name)
+	if err == nil {
+		return unit, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return unit, false, fmt.Errorf("ошибка поиска единицы измерения '%s': %w", name, err)
+	}
+	unit, err = a.q.GetUnitOfMeasurementByAlias(ctx, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return unit, false, nil
+	}
+	if err != nil {
+		return unit, false, fmt.Errorf("ошибка поиска синонима '%s': %w", name, err)
+	}
+	return unit, true, nil
+}
+
+func summarize(report *api_models.ReferenceImportReport) {
+	summary := api_models.ReferenceImportSummary{Total: len(report.Rows)}
+	for _, row := range report.Rows {
+		switch {
+		case row.Code != "":
+			summary.Errors++
+		case row.Action == api_models.ReferenceActionCreate:
+			summary.Created++
+		case row.Action == api_models.ReferenceActionUpdate:
+			summary.Updated++
+		case row.Action == api_models.ReferenceActionUnchanged:
+			summary.Unchanged++
+		}
+	}
+	report.Summary = summary
+}
+
+// rowErrors переводит ошибки строк в нарушения ответа 400.
+func rowErrors(rows []api_models.ReferenceImportRow) api_models.ValidationErrors {
+	var errs api_models.ValidationErrors
+	for _, row := range rows {
+		if row.Code != "" {
+			errs = append(errs, api_models.FieldError{Path: rowPath(row), Rule: row.Code, Message: row.Message})
+		}
+	}
+	return errs
+}
+
+func setError(row *api_models.ReferenceImportRow, code, key string, args ...any) {
+	row.Action = ""
+	row.Code = code
+	row.Message = i18n.T(i18n.Default, key, args...)
+}
+
+// rowPath адресует строку файла: rows[5] или sheets["Категории"].rows[5].
+func rowPath(row api_models.ReferenceImportRow) string {
+	if row.Sheet == "" {
+		return fmt.Sprintf("rows[%d]", row.Row)
+	}
+	return fmt.Sprintf("sheets[%q].rows[%d]", row.Sheet, row.Row)
+}
+
+// rowLabel называет строку в сообщении о повторе: 5 или «Категории»!5.
+func rowLabel(row api_models.ReferenceImportRow) string {
+	if row.Sheet == "" {
+		return fmt.Sprintf("%d", row.Row)
+	}
+	return fmt.Sprintf("«%s»!%d", row.Sheet, row.Row)
+}
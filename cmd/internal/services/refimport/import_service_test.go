@@ -0,0 +1,258 @@
+package refimport
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/xlsx"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR REFERENCE IMPORT (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Half-imported taxonomy — a file with a single bad row must not leave the
+   reference partially updated
+2. Order-dependent files — a category listed before its chapter (or a chapter
+   created in the same file) must still find its parent
+3. Unreadable reports — every row says what will happen or why it failed, with
+   the row number the user sees in Excel
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Apply
+- GIVEN a CSV (BOM, ";") listing a category before its chapter and type, plus a unit alias
+  WHEN Import is called
+  THEN rows are applied by level: new type and chapter created, the category
+  moved to the new chapter, the alias of another unit reassigned; the report is applied
+
+SCENARIO 2: Row errors
+- GIVEN unknown kind, a type with a parent, a duplicate, a chapter of a missing type
+  and a unit alias without catalog:manage
+  WHEN dry_run
+  THEN the report lists each error code with its row, valid=false, nothing applied
+- GIVEN the same file without dry_run → ValidationError with one detail per bad row
+
+SCENARIO 3: Parsing
+- GIVEN an xlsx with Russian headers in any column order → rows carry the sheet name
+- GIVEN a header without kind/title, a non UTF-8 CSV or a header-only file → ValidationError
+- GIVEN a tiny xlsx with a cell at XFD1 and a row at 1048576 → too_many_rows
+  without expanding the sheet in memory
+*/
+
+var (
+	typeColumns     = []string{"id", "title", "created_at", "updated_at"}
+	chapterColumns  = []string{"id", "title", "tender_type_id", "created_at", "updated_at"}
+	categoryColumns = []string{"id", "title", "tender_chapter_id", "created_at", "updated_at"}
+	unitColumns     = []string{"id", "normalized_name", "full_name", "description", "created_at", "updated_at"}
+)
+
+func setupTestService(t *testing.T) (*ImportService, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	return NewImportService(mockStore, testutil.NewMockLogger()), mockStore
+}
+
+// execTxDoAndReturn выполняет callback ExecTx на *db.Queries поверх go-sqlmock.
+func execTxDoAndReturn(t *testing.T, setupFn func(mock sqlmock.Sqlmock)) func(ctx context.Context, fn func(*db.Queries) error) error {
+	t.Helper()
+	return func(ctx context.Context, fn func(*db.Queries) error) error {
+		sqlDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet(), "sqlmock: unmet expectations")
+			sqlDB.Close()
+		}()
+		setupFn(mock)
+		return fn(db.New(sqlDB))
+	}
+}
+
+func TestImport_AppliesByLevel(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	now := time.Now()
+	file := "\xef\xbb\xbfkind;title;parent\n" +
+		"category;Мягкая кровля;Кровля\n" +
+		"\n" +
+		"chapter;Кровля;Ремонт\n" +
+		"type;Ремонт;\n" +
+		"unit_alias; М.КВ ;м2\n"
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			// type (строка 5)
+			mock.ExpectQuery("FROM tender_types").WithArgs("Ремонт").WillReturnError(sql.ErrNoRows)
+			mock.ExpectQuery("INSERT INTO tender_types").WithArgs("Ремонт").
+				WillReturnRows(sqlmock.NewRows(typeColumns).AddRow(int64(1), "Ремонт", now, now))
+			// chapter (строка 4): тип из этого же файла
+			mock.ExpectQuery("FROM tender_chapters").WithArgs("Кровля").WillReturnError(sql.ErrNoRows)
+			mock.ExpectQuery("INSERT INTO tender_chapters").WithArgs("Кровля", int64(1)).
+				WillReturnRows(sqlmock.NewRows(chapterColumns).AddRow(int64(10), "Кровля", int64(1), now, now))
+			// category (строка 2): была в другом разделе
+			mock.ExpectQuery("FROM tender_categories").WithArgs("Мягкая кровля").
+				WillReturnRows(sqlmock.NewRows(categoryColumns).AddRow(int64(100), "Мягкая кровля", int64(99), now, now))
+			mock.ExpectQuery("UPDATE tender_categories").
+				WillReturnRows(sqlmock.NewRows(categoryColumns).AddRow(int64(100), "Мягкая кровля", int64(10), now, now))
+			// unit_alias (строка 6): синоним другой единицы переносится
+			mock.ExpectQuery("FROM units_of_measurement").WithArgs("м2").
+				WillReturnRows(sqlmock.NewRows(unitColumns).AddRow(int64(5), "м2", nil, nil, now, now))
+			mock.ExpectQuery("FROM units_of_measurement").WithArgs("м.кв").WillReturnError(sql.ErrNoRows)
+			mock.ExpectQuery("FROM unit_aliases").WithArgs("м.кв").
+				WillReturnRows(sqlmock.NewRows(unitColumns).AddRow(int64(7), "кв м", nil, nil, now, now))
+			mock.ExpectExec("UPDATE unit_aliases").WithArgs(int64(5), "м.кв").WillReturnResult(sqlmock.NewResult(0, 1))
+		}),
+	)
+
+	report, err := service.Import(context.Background(), []byte(file), Options{UnitAliases: true})
+
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+	assert.True(t, report.Applied)
+	assert.Equal(t, api_models.ReferenceImportSummary{Total: 4, Created: 2, Updated: 2}, report.Summary)
+
+	actions := map[int]string{}
+	for _, row := range report.Rows {
+		actions[row.Row] = row.Action
+	}
+	assert.Equal(t, map[int]string{2: "update", 4: "create", 5: "create", 6: "update"}, actions)
+	assert.Equal(t, "м.кв", report.Rows[3].Title, "синоним нормализуется")
+}
+
+// rowErrorsFile — по строке на каждую ошибку; правильна только строка 2.
+const rowErrorsFile = "kind,title,parent\n" +
+	"type,Ремонт,\n" +
+	"section,Кровля,Ремонт\n" +
+	"type,Строительство,Ремонт\n" +
+	"type,Ремонт,\n" +
+	"chapter,Фасады,Реконструкция\n" +
+	"unit_alias,кв.м,м2\n"
+
+func expectRowErrorsQueries(mock sqlmock.Sqlmock) {
+	now := time.Now()
+	mock.ExpectQuery("FROM tender_types").WithArgs("Ремонт").
+		WillReturnRows(sqlmock.NewRows(typeColumns).AddRow(int64(1), "Ремонт", now, now))
+	mock.ExpectQuery("FROM tender_types").WithArgs("Реконструкция").WillReturnError(sql.ErrNoRows)
+}
+
+func TestImport_RowErrors(t *testing.T) {
+	t.Run("dry run reports every row", func(t *testing.T) {
+		service, mockStore := setupTestService(t)
+		mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(execTxDoAndReturn(t, expectRowErrorsQueries))
+
+		report, err := service.Import(context.Background(), []byte(rowErrorsFile), Options{DryRun: true})
+
+		require.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.False(t, report.Valid)
+		assert.False(t, report.Applied)
+		assert.Equal(t, api_models.ReferenceImportSummary{Total: 6, Unchanged: 1, Errors: 5}, report.Summary)
+
+		codes := map[int]string{}
+		for _, row := range report.Rows {
+			codes[row.Row] = row.Code
+		}
+		assert.Equal(t, map[int]string{
+			2: "",
+			3: "invalid_kind",
+			4: "parent_not_allowed",
+			5: "duplicate",
+			6: "parent_not_found",
+			7: "forbidden",
+		}, codes)
+		assert.Contains(t, report.Rows[3].Message, "2", "повтор ссылается на первую строку")
+	})
+
+	t.Run("apply is rejected as a whole", func(t *testing.T) {
+		service, mockStore := setupTestService(t)
+		mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(execTxDoAndReturn(t, expectRowErrorsQueries))
+
+		report, err := service.Import(context.Background(), []byte(rowErrorsFile), Options{})
+
+		assert.Nil(t, report)
+		var validationErr *apierrors.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "reference_import.invalid", validationErr.Key)
+		details, ok := validationErr.Details.(api_models.ValidationErrors)
+		require.True(t, ok)
+		require.Len(t, details, 5)
+		assert.Equal(t, api_models.FieldError{Path: "rows[3]", Rule: "invalid_kind", Message: details[0].Message}, details[0])
+	})
+}
+
+func TestParseFile(t *testing.T) {
+	t.Run("xlsx with Russian headers", func(t *testing.T) {
+		book := xlsx.New()
+		sheet := book.AddSheet("Категории")
+		sheet.AddHeader("Родитель", "Наименование", "Вид")
+		sheet.AddRow(xlsx.Text("Кровля"), xlsx.Text("Мягкая кровля"), xlsx.Text("категория"))
+		book.AddSheet("Пустой")
+		var buf bytes.Buffer
+		require.NoError(t, book.Write(&buf))
+
+		records, err := parseFile(buf.Bytes())
+
+		require.NoError(t, err)
+		assert.Equal(t, []record{{sheet: "Категории", row: 2, kind: "категория", title: "Мягкая кровля", parent: "Кровля"}}, records)
+		assert.Equal(t, api_models.ReferenceKindCategory, checkRecord(records[0], Options{}).Kind)
+	})
+
+	t.Run("sparse xlsx", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for name, content := range map[string]string{
+			"xl/workbook.xml":            `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="S" r:id="rId1"/></sheets></workbook>`,
+			"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+			"xl/worksheets/sheet1.xml": `<worksheet><sheetData>` +
+				`<row r="1"><c r="A1" t="inlineStr"><is><t>kind</t></is></c><c r="B1" t="inlineStr"><is><t>title</t></is></c><c r="XFD1"><v>1</v></c></row>` +
+				`<row r="1048576"><c r="XFD1048576"><v>1</v></c></row>` +
+				`</sheetData></worksheet>`,
+		} {
+			w, err := zw.Create(name)
+			require.NoError(t, err)
+			_, err = w.Write([]byte(content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, zw.Close())
+
+		_, err := parseFile(buf.Bytes())
+
+		var validationErr *apierrors.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "reference_import.too_many_rows", validationErr.Key)
+	})
+
+	cases := []struct {
+		name    string
+		data    string
+		wantKey string
+	}{
+		{"header without title", "kind;name\ntype;Ремонт\n", "reference_import.header_missing"},
+		{"not utf-8", "kind;title\ntype;\xd0\xe5\xec\xee\xed\xf2\n", "reference_import.not_utf8"},
+		{"header only", "kind,title,parent\n\n", "reference_import.empty"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseFile([]byte(tc.data))
+
+			var validationErr *apierrors.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tc.wantKey, validationErr.Key)
+		})
+	}
+}
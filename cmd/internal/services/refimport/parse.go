@@ -0,0 +1,208 @@
+package refimport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/xlsx"
+)
+
+// maxRecords — предел строк в файле: весь файл применяется одной транзакцией.
+const maxRecords = 10000
+
+// xlsxLimits — сколько книги читается в память. Строк — с запасом на заголовки
+// листов и пустые строки между записями; столбцы справочника стоят в начале
+// листа, ячейки правее не нужны.
+var xlsxLimits = xlsx.Limits{MaxRows: 2 * maxRecords, MaxColumns: 32}
+
+// xlsxSignature — сигнатура ZIP: xlsx отличается от CSV по содержимому, а не
+// по имени файла.
+var xlsxSignature = []byte("PK\x03\x04")
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// Заголовки столбцов: английские (как в шаблоне из README) и русские.
+var headerColumns = map[string]string{
+	"kind":         columnKind,
+	"вид":          columnKind,
+	"title":        columnTitle,
+	"наименование": columnTitle,
+	"название":     columnTitle,
+	"parent":       columnParent,
+	"родитель":     columnParent,
+}
+
+const (
+	columnKind   = "kind"
+	columnTitle  = "title"
+	columnParent = "parent"
+)
+
+// record — непустая строка файла с исходными значениями.
+type record struct {
+	sheet  string // Лист книги; у CSV пусто
+	row    int    // Номер строки в файле, с 1
+	kind   string
+	title  string
+	parent string
+}
+
+// parseFile разбирает xlsx (все непустые листы) или CSV в кодировке UTF-8 с
+// разделителем «;» или «,». Первая непустая строка каждого листа — заголовок
+// со столбцами kind, title и необязательным parent в любом порядке.
+func parseFile(data []byte) ([]record, error) {
+	var (
+		records []record
+		err     error
+	)
+	if bytes.HasPrefix(data, xlsxSignature) {
+		records, err = parseXLSX(data)
+	} else {
+		records, err = parseCSV(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, apierrors.NewValidationError("reference_import.empty")
+	}
+	if len(records) > maxRecords {
+		return nil, apierrors.NewValidationError("reference_import.too_many_rows", maxRecords)
+	}
+	return records, nil
+}
+
+func parseXLSX(data []byte) ([]record, error) {
+	sheets, err := xlsx.ReadSheets(bytes.NewReader(data), int64(len(data)), xlsxLimits)
+	if errors.Is(err, xlsx.ErrTooManyRows) {
+		return nil, apierrors.NewValidationError("reference_import.too_many_rows", maxRecords)
+	}
+	if err != nil {
+		return nil, apierrors.NewValidationError("reference_import.xlsx_invalid", err)
+	}
+
+	var records []record
+	for _, sheet := range sheets {
+		sheetRecords, err := tableRecords(sheet.Name, sheet.Rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, sheetRecords...)
+	}
+	return records, nil
+}
+
+func parseCSV(data []byte) ([]record, error) {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	if !utf8.Valid(data) {
+		return nil, apierrors.NewValidationError("reference_import.not_utf8")
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	// Строки CSV нумеруются по физическим строкам файла (как в редакторе):
+	// пустые строки csv.Reader пропускает, поле в кавычках может занимать несколько
+	var rows [][]string
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, apierrors.NewValidationError("reference_import.csv_invalid", err)
+		}
+		line, _ := reader.FieldPos(0)
+		for len(rows) < line-1 {
+			rows = append(rows, nil)
+		}
+		rows = append(rows, fields)
+		if len(rows) > maxRecords+1 {
+			return nil, apierrors.NewValidationError("reference_import.too_many_rows", maxRecords)
+		}
+	}
+	return tableRecords("", rows)
+}
+
+// detectDelimiter выбирает «;» (Excel с русской локалью) или «,» по первой строке.
+func detectDelimiter(data []byte) rune {
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+// tableRecords превращает строки листа (rows[i] — строка i+1) в записи по
+// заголовку. Лист без непустых строк пропускается.
+func tableRecords(sheet string, rows [][]string) ([]record, error) {
+	headerRow := -1
+	for i, row := range rows {
+		if !isBlank(row) {
+			headerRow = i
+			break
+		}
+	}
+	if headerRow < 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int)
+	for i, name := range rows[headerRow] {
+		column, ok := headerColumns[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			continue
+		}
+		if _, seen := columns[column]; !seen {
+			columns[column] = i
+		}
+	}
+	_, hasKind := columns[columnKind]
+	_, hasTitle := columns[columnTitle]
+	if !hasKind || !hasTitle {
+		if sheet == "" {
+			return nil, apierrors.NewValidationError("reference_import.header_missing", headerRow+1)
+		}
+		return nil, apierrors.NewValidationError("reference_import.sheet_header_missing", sheet, headerRow+1)
+	}
+
+	var records []record
+	for i := headerRow + 1; i < len(rows); i++ {
+		row := rows[i]
+		if isBlank(row) {
+			continue
+		}
+		records = append(records, record{
+			sheet:  sheet,
+			row:    i + 1,
+			kind:   cell(row, columns, columnKind),
+			title:  cell(row, columns, columnTitle),
+			parent: cell(row, columns, columnParent),
+		})
+	}
+	return records, nil
+}
+
+func cell(row []string, columns map[string]int, column string) string {
+	i, ok := columns[column]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+func isBlank(row []string) bool {
+	for _, v := range row {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
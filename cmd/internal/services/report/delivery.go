@@ -0,0 +1,179 @@
+package report
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/joblock"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/notifications"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/mailer"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/xlsx"
+)
+
+// maxErrorLength ограничивает error в истории запусков.
+const maxErrorLength = 1000
+
+// LockName — имя блокировки joblock, под которой экземпляр API формирует регулярные отчеты.
+const LockName = "scheduled_reports"
+
+// Run периодически формирует и рассылает отчеты, срок которых наступил,
+// до отмены ctx. Полная пачка означает, что готовые к запуску отчеты могут
+// остаться, поэтому следующая пачка забирается сразу, не дожидаясь тика.
+// На каждом тике отчеты формирует только экземпляр, взявший блокировку LockName.
+// Предназначен для запуска в отдельной горутине.
+func (s *ReportService) Run(ctx context.Context, interval time.Duration, locker joblock.Locker) {
+	logger := s.logger.WithField("method", "Run")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := locker.TryRun(ctx, LockName, s.runAllDue); err != nil {
+				logger.Errorf("Не удалось обработать регулярные отчеты: %v", err)
+			}
+		}
+	}
+}
+
+// runAllDue забирает пачки, пока они приходят полными.
+func (s *ReportService) runAllDue(ctx context.Context) error {
+	for {
+		processed, err := s.RunDue(ctx)
+		if err != nil {
+			return err
+		}
+		if processed < int(s.cfg.BatchSize) || ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// RunDue забирает пачку отчетов, срок которых наступил, формирует и отправляет их.
+// Возвращает число обработанных отчетов (отправленных и неуспешных).
+func (s *ReportService) RunDue(ctx context.Context) (int, error) {
+	due, err := s.store.ClaimDueScheduledReports(ctx, s.cfg.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка ClaimDueScheduledReports: %w", err)
+	}
+
+	for _, r := range due {
+		if err := s.runScheduled(ctx, r); err != nil {
+			return 0, err
+		}
+	}
+	return len(due), nil
+}
+
+// periodStart возвращает начало периода, который заканчивается на end.
+func periodStart(frequency string, end time.Time) time.Time {
+	switch frequency {
+	case FrequencyDaily:
+		return end.AddDate(0, 0, -1)
+	case FrequencyWeekly:
+		return end.AddDate(0, 0, -7)
+	default:
+		return end.AddDate(0, -1, 0)
+	}
+}
+
+// runScheduled формирует один отчет и записывает итог в report_runs.
+// Ошибка формирования или отправки записывается в историю запуска;
+// возвращаются только ошибки записи самой истории.
+func (s *ReportService) runScheduled(ctx context.Context, r db.ClaimDueScheduledReportsRow) error {
+	logger := s.logger.WithField("scheduled_report_id", r.ID)
+
+	to := r.ScheduledAt.UTC()
+	job := reportJob{
+		Name:           r.Name,
+		Kind:           r.Kind,
+		OrganizationID: r.OrganizationID,
+		CategoryID:     r.CategoryID,
+		From:           periodStart(r.Frequency, to),
+		To:             to,
+	}
+
+	run, err := s.store.CreateReportRun(ctx, db.CreateReportRunParams{
+		ScheduledReportID: r.ID,
+		PeriodFrom:        job.From,
+		PeriodTo:          job.To,
+		Recipients:        r.Recipients,
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка CreateReportRun(%d): %w", r.ID, err)
+	}
+
+	finish := db.FinishReportRunParams{ID: run.ID, Status: RunStatusSent}
+	doc, attachment, err := s.generate(ctx, job, r.Format)
+	if err == nil {
+		finish.FileName = sql.NullString{String: attachment.Filename, Valid: true}
+		finish.FileSize = sql.NullInt64{Int64: int64(len(attachment.Data)), Valid: true}
+		finish.RowsCount = sql.NullInt32{Int32: int32(doc.rowsCount()), Valid: true}
+		err = s.notifier.SendReport(ctx, r.Recipients, mailSummary(doc), attachment)
+	}
+	if err != nil {
+		finish.Status = RunStatusFailed
+		finish.Error = sql.NullString{String: truncateError(err.Error()), Valid: true}
+		logger.Warnf("Отчет %q (%s) не отправлен: %v", r.Name, r.Kind, err)
+	} else {
+		logger.Infof("Отчет %q (%s) отправлен: %s, %d байт, %v",
+			r.Name, r.Kind, attachment.Filename, len(attachment.Data), r.Recipients)
+	}
+
+	if err := s.store.FinishReportRun(ctx, finish); err != nil {
+		return fmt.Errorf("ошибка FinishReportRun(%d): %w", run.ID, err)
+	}
+	return nil
+}
+
+// generate собирает данные отчета и формирует файл в нужном формате.
+func (s *ReportService) generate(ctx context.Context, job reportJob, format string) (*document, mailer.Attachment, error) {
+	doc, err := s.buildDocument(ctx, job)
+	if err != nil {
+		return nil, mailer.Attachment{}, err
+	}
+
+	// Имя файла — вид отчета и включительные даты периода
+	fileName := fmt.Sprintf("%s_%s_%s.%s", job.Kind,
+		job.From.Format(DateLayout), job.To.AddDate(0, 0, -1).Format(DateLayout), format)
+
+	var data []byte
+	var contentType string
+	switch format {
+	case FormatXLSX:
+		data, err = renderXLSX(doc)
+		contentType = xlsx.ContentType
+	case FormatPDF:
+		data, err = s.renderPDF(ctx, doc)
+		contentType = pdfContentType
+	default:
+		err = fmt.Errorf("неизвестный формат %q", format)
+	}
+	if err != nil {
+		return nil, mailer.Attachment{}, err
+	}
+	return doc, mailer.Attachment{Filename: fileName, ContentType: contentType, Data: data}, nil
+}
+
+// mailSummary — сводка для текста письма: что за отчет и сколько в нем строк по каждой таблице.
+func mailSummary(doc *document) notifications.Report {
+	summary := notifications.Report{Title: doc.Title, Period: doc.Period}
+	for _, t := range doc.Tables {
+		summary.Tables = append(summary.Tables, notifications.ReportTable{Title: t.Title, Rows: len(t.Rows)})
+	}
+	return summary
+}
+
+func truncateError(msg string) string {
+	if len(msg) <= maxErrorLength {
+		return msg
+	}
+	return strings.ToValidUTF8(msg[:maxErrorLength], "")
+}
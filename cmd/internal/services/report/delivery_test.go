@@ -0,0 +1,246 @@
+package report
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/xlsx"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR SCHEDULED REPORT DELIVERY (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Wrong period — a weekly report sent on Monday must cover the previous Monday–Sunday
+2. Lost failures — an SMTP or converter error must be visible in the run history
+3. Broken files — the attachment must be a real XLSX/PDF with the report's rows
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: RunDue, XLSX
+- GIVEN a due weekly new tenders report
+  WHEN RunDue is called
+  THEN the run is recorded for [scheduled_at - 7 days, scheduled_at)
+  AND tenders of that period are queried with the report's filters
+  AND an XLSX attachment is mailed to the recipients
+  AND the run is finished as sent with file name, size and rows count
+
+SCENARIO 2: RunDue, failures
+- GIVEN the mail server rejecting the letter
+  WHEN RunDue is called
+  THEN the run is finished as failed with the error, RunDue itself succeeds
+- GIVEN the report data query failing
+  THEN the run is failed and nothing is mailed
+
+SCENARIO 3: PDF
+- GIVEN a PDF report and a converter
+  WHEN RunDue is called
+  THEN the HTML page (escaped) is posted to /forms/chromium/convert/html
+  AND the converter's PDF is mailed
+- GIVEN the converter answering 500
+  THEN the run is failed with the converter's status
+
+SCENARIO 4: periodStart
+- GIVEN daily, weekly and monthly frequencies
+  THEN the period starts 1 day, 7 days and 1 month before its end
+*/
+
+var mondayMidnight = time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+func dueReport(kind, format string) db.ClaimDueScheduledReportsRow {
+	return db.ClaimDueScheduledReportsRow{
+		ID:             7,
+		Name:           "Новые тендеры",
+		Kind:           kind,
+		Format:         format,
+		Frequency:      FrequencyWeekly,
+		Recipients:     []string{"a@example.com", "b@example.com"},
+		OrganizationID: sql.NullInt64{Int64: 2, Valid: true},
+		ScheduledAt:    mondayMidnight,
+	}
+}
+
+func newTenderRows() []db.ListNewTendersRow {
+	return []db.ListNewTendersRow{
+		{
+			ID:                 1,
+			EtpID:              "T-1",
+			Title:              `ЖК "Север" <корпус 1>`,
+			CategoryTitle:      sql.NullString{String: "Монолит", Valid: true},
+			DataPreparedOnDate: sql.NullTime{Time: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+			CreatedAt:          time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC),
+			LotsCount:          2,
+			ProposalsCount:     5,
+		},
+	}
+}
+
+func expectRun(mockStore *db.MockStore) {
+	mockStore.EXPECT().ClaimDueScheduledReports(gomock.Any(), int32(5)).
+		Return([]db.ClaimDueScheduledReportsRow{dueReport(KindNewTenders, FormatXLSX)}, nil)
+	mockStore.EXPECT().CreateReportRun(gomock.Any(), db.CreateReportRunParams{
+		ScheduledReportID: 7,
+		PeriodFrom:        time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC),
+		PeriodTo:          mondayMidnight,
+		Recipients:        []string{"a@example.com", "b@example.com"},
+	}).Return(db.ReportRun{ID: 30, ScheduledReportID: 7}, nil)
+}
+
+func TestRunDue_SendsXLSXAndRecordsRun(t *testing.T) {
+	service, mockStore, sender := setupTestServiceWith(t, testConfig())
+
+	expectRun(mockStore)
+	mockStore.EXPECT().ListNewTenders(gomock.Any(), db.ListNewTendersParams{
+		DateFrom:       time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC),
+		DateTo:         mondayMidnight,
+		OrganizationID: sql.NullInt64{Int64: 2, Valid: true},
+	}).Return(newTenderRows(), nil)
+	mockStore.EXPECT().FinishReportRun(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.FinishReportRunParams) error {
+			assert.Equal(t, int64(30), arg.ID)
+			assert.Equal(t, RunStatusSent, arg.Status)
+			assert.Equal(t, "new_tenders_2025-03-03_2025-03-09.xlsx", arg.FileName.String)
+			assert.Positive(t, arg.FileSize.Int64)
+			assert.Equal(t, sql.NullInt32{Int32: 1, Valid: true}, arg.RowsCount)
+			assert.False(t, arg.Error.Valid)
+			return nil
+		})
+
+	processed, err := service.RunDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+
+	require.Len(t, sender.sent, 1)
+	msg := sender.sent[0]
+	assert.Equal(t, "reports@example.com", msg.From)
+	assert.Equal(t, []string{"a@example.com", "b@example.com"}, msg.To)
+	assert.Equal(t, "Новые тендеры за 03.03.2025–09.03.2025", msg.Subject)
+	assert.Contains(t, msg.Body, "Новые тендеры: 1")
+	require.Len(t, msg.Attachments, 1)
+	assert.Equal(t, xlsx.ContentType, msg.Attachments[0].ContentType)
+	assert.Equal(t, "PK", string(msg.Attachments[0].Data[:2]), "XLSX — zip-архив")
+}
+
+func TestRunDue_MailFailureIsRecorded(t *testing.T) {
+	service, mockStore, sender := setupTestServiceWith(t, testConfig())
+	sender.err = errors.New("SMTP RCPT TO b@example.com: 550 mailbox unavailable")
+
+	expectRun(mockStore)
+	mockStore.EXPECT().ListNewTenders(gomock.Any(), gomock.Any()).Return(newTenderRows(), nil)
+	mockStore.EXPECT().FinishReportRun(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.FinishReportRunParams) error {
+			assert.Equal(t, RunStatusFailed, arg.Status)
+			assert.Contains(t, arg.Error.String, "550 mailbox unavailable")
+			assert.True(t, arg.FileName.Valid, "файл сформирован, не отправлен")
+			return nil
+		})
+
+	processed, err := service.RunDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+}
+
+func TestRunDue_DataFailureIsRecorded(t *testing.T) {
+	service, mockStore, sender := setupTestServiceWith(t, testConfig())
+
+	expectRun(mockStore)
+	mockStore.EXPECT().ListNewTenders(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection reset"))
+	mockStore.EXPECT().FinishReportRun(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.FinishReportRunParams) error {
+			assert.Equal(t, RunStatusFailed, arg.Status)
+			assert.Contains(t, arg.Error.String, "connection reset")
+			assert.False(t, arg.FileName.Valid)
+			return nil
+		})
+
+	_, err := service.RunDue(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, sender.sent)
+}
+
+func TestRunDue_PDF(t *testing.T) {
+	converter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, convertHTMLPath, r.URL.Path)
+		file, header, err := r.FormFile("files")
+		require.NoError(t, err)
+		assert.Equal(t, "index.html", header.Filename)
+		page, err := io.ReadAll(file)
+		require.NoError(t, err)
+
+		// THEN: данные экранированы, таблица на месте
+		assert.Contains(t, string(page), "ЖК &#34;Север&#34; &lt;корпус 1&gt;")
+		assert.Contains(t, string(page), "<th>Предложений</th>")
+		_, _ = w.Write([]byte("%PDF-1.7 test"))
+	}))
+	defer converter.Close()
+
+	cfg := testConfig()
+	cfg.PDFConverterURL = converter.URL + "/"
+	service, mockStore, sender := setupTestServiceWith(t, cfg)
+
+	mockStore.EXPECT().ClaimDueScheduledReports(gomock.Any(), gomock.Any()).
+		Return([]db.ClaimDueScheduledReportsRow{dueReport(KindNewTenders, FormatPDF)}, nil)
+	mockStore.EXPECT().CreateReportRun(gomock.Any(), gomock.Any()).Return(db.ReportRun{ID: 31}, nil)
+	mockStore.EXPECT().ListNewTenders(gomock.Any(), gomock.Any()).Return(newTenderRows(), nil)
+	mockStore.EXPECT().FinishReportRun(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.FinishReportRunParams) error {
+			assert.Equal(t, RunStatusSent, arg.Status)
+			assert.True(t, strings.HasSuffix(arg.FileName.String, ".pdf"))
+			return nil
+		})
+
+	_, err := service.RunDue(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, pdfContentType, sender.sent[0].Attachments[0].ContentType)
+	assert.Equal(t, "%PDF-1.7 test", string(sender.sent[0].Attachments[0].Data))
+}
+
+func TestRunDue_PDFConverterFailure(t *testing.T) {
+	converter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "chromium crashed", http.StatusInternalServerError)
+	}))
+	defer converter.Close()
+
+	cfg := testConfig()
+	cfg.PDFConverterURL = converter.URL
+	service, mockStore, sender := setupTestServiceWith(t, cfg)
+
+	mockStore.EXPECT().ClaimDueScheduledReports(gomock.Any(), gomock.Any()).
+		Return([]db.ClaimDueScheduledReportsRow{dueReport(KindNewTenders, FormatPDF)}, nil)
+	mockStore.EXPECT().CreateReportRun(gomock.Any(), gomock.Any()).Return(db.ReportRun{ID: 32}, nil)
+	mockStore.EXPECT().ListNewTenders(gomock.Any(), gomock.Any()).Return(newTenderRows(), nil)
+	mockStore.EXPECT().FinishReportRun(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.FinishReportRunParams) error {
+			assert.Equal(t, RunStatusFailed, arg.Status)
+			assert.Contains(t, arg.Error.String, "500")
+			assert.Contains(t, arg.Error.String, "chromium crashed")
+			return nil
+		})
+
+	_, err := service.RunDue(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, sender.sent)
+}
+
+func TestPeriodStart(t *testing.T) {
+	end := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), periodStart(FrequencyDaily, end))
+	assert.Equal(t, time.Date(2025, 2, 22, 0, 0, 0, 0, time.UTC), periodStart(FrequencyWeekly, end))
+	assert.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), periodStart(FrequencyMonthly, end))
+}
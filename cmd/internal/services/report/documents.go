@@ -0,0 +1,230 @@
+package report
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+)
+
+// displayDateLayout — формат дат в файлах и письмах.
+const displayDateLayout = "02.01.2006"
+
+// document — содержимое регулярного отчета: набор таблиц, общий для XLSX
+// (таблица — лист) и PDF (таблица — раздел HTML-страницы).
+type document struct {
+	Title  string
+	Period string // «за 03.03.2025–09.03.2025» или «на 10.03.2025»
+	Tables []table
+}
+
+// table — таблица отчета. Первая таблица документа — основная: ее строки
+// записываются в report_runs.rows_count.
+type table struct {
+	Title   string
+	Columns []string
+	Rows    [][]cell
+}
+
+// cell — значение ячейки; Numeric выравнивается вправо и в XLSX пишется числом.
+type cell struct {
+	Text    string
+	Numeric bool
+}
+
+func textCell(v string) cell { return cell{Text: v} }
+
+func countCell(n int64) cell { return cell{Text: strconv.FormatInt(n, 10), Numeric: true} }
+
+func moneyCell(m *api_models.Money) cell {
+	if m == nil {
+		return cell{}
+	}
+	return cell{Text: m.String(), Numeric: true}
+}
+
+func optionalCell(s *string, numeric bool) cell {
+	if s == nil {
+		return cell{}
+	}
+	return cell{Text: *s, Numeric: numeric}
+}
+
+func nullStringCell(ns sql.NullString) cell {
+	return textCell(ns.String)
+}
+
+// rowsCount возвращает число строк основной таблицы.
+func (d *document) rowsCount() int {
+	if len(d.Tables) == 0 {
+		return 0
+	}
+	return len(d.Tables[0].Rows)
+}
+
+// reportJob — запуск регулярного отчета за период [from, to).
+type reportJob struct {
+	Name           string
+	Kind           string
+	OrganizationID sql.NullInt64
+	CategoryID     sql.NullInt64
+	From           time.Time
+	To             time.Time
+}
+
+// buildDocument собирает данные отчета нужного вида.
+func (s *ReportService) buildDocument(ctx context.Context, job reportJob) (*document, error) {
+	switch job.Kind {
+	case KindSavings:
+		return s.buildSavingsDocument(ctx, job)
+	case KindNewTenders:
+		return s.buildNewTendersDocument(ctx, job)
+	case KindUnmatchedPositions:
+		return s.buildUnmatchedDocument(ctx, job)
+	default:
+		return nil, fmt.Errorf("неизвестный вид отчета %q", job.Kind)
+	}
+}
+
+// periodLabel описывает полуинтервал [from, to) включительными датами.
+func periodLabel(from, to time.Time) string {
+	last := to.AddDate(0, 0, -1)
+	if !last.After(from) {
+		return "за " + from.Format(displayDateLayout)
+	}
+	return fmt.Sprintf("за %s–%s", from.Format(displayDateLayout), last.Format(displayDateLayout))
+}
+
+func (s *ReportService) buildSavingsDocument(ctx context.Context, job reportJob) (*document, error) {
+	// GetSavingsReport принимает последний день периода включительно
+	report, err := s.GetSavingsReport(ctx, SavingsQuery{
+		From:           sql.NullTime{Time: job.From, Valid: true},
+		To:             sql.NullTime{Time: job.To.AddDate(0, 0, -1), Valid: true},
+		CategoryID:     job.CategoryID,
+		OrganizationID: job.OrganizationID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	money := func(title string) string { return fmt.Sprintf("%s, %s", title, report.Currency) }
+	tenders := table{
+		Title: "Тендеры",
+		Columns: []string{"Тендер", "Название", "Категория", "Дата", "Лотов", "Сравнимых лотов",
+			money("Смета"), money("Цена победителя"), money("Экономия"), "Экономия, %"},
+	}
+	for _, t := range report.Tenders {
+		tenders.Rows = append(tenders.Rows, []cell{
+			textCell(t.TenderEtpID),
+			textCell(t.TenderTitle),
+			optionalCell(t.CategoryTitle, false),
+			textCell(t.TenderDate.Format(displayDateLayout)),
+			countCell(t.LotsCount),
+			countCell(t.ComparedLotsCount),
+			moneyCell(t.BaselineCost),
+			moneyCell(t.WinningCost),
+			moneyCell(t.Savings),
+			optionalCell(t.SavingsPercent, true),
+		})
+	}
+
+	totalsColumns := []string{"Тендеров", "Сравнимых лотов", money("Смета"), money("Цена победителя"), money("Экономия"), "Экономия, %"}
+	totalsCells := func(t api_models.SavingsTotals) []cell {
+		return []cell{
+			countCell(t.TendersCount),
+			countCell(t.ComparedLotsCount),
+			moneyCell(t.BaselineCost),
+			moneyCell(t.WinningCost),
+			moneyCell(t.Savings),
+			optionalCell(t.SavingsPercent, true),
+		}
+	}
+	categories := table{Title: "По категориям", Columns: append([]string{"Категория"}, totalsColumns...)}
+	for _, c := range report.Categories {
+		title := "Без категории"
+		if c.CategoryTitle != nil {
+			title = *c.CategoryTitle
+		}
+		categories.Rows = append(categories.Rows, append([]cell{textCell(title)}, totalsCells(c.SavingsTotals)...))
+	}
+	total := table{Title: "Итого", Columns: totalsColumns, Rows: [][]cell{totalsCells(report.Total)}}
+
+	return &document{
+		Title:  job.Name,
+		Period: periodLabel(job.From, job.To),
+		Tables: []table{tenders, categories, total},
+	}, nil
+}
+
+func (s *ReportService) buildNewTendersDocument(ctx context.Context, job reportJob) (*document, error) {
+	rows, err := s.store.ListNewTenders(ctx, db.ListNewTendersParams{
+		DateFrom:       job.From,
+		DateTo:         job.To,
+		CategoryID:     job.CategoryID,
+		OrganizationID: job.OrganizationID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ListNewTenders: %w", err)
+	}
+
+	tenders := table{
+		Title:   "Новые тендеры",
+		Columns: []string{"Тендер", "Название", "Категория", "Дата подготовки", "Загружен", "Лотов", "Предложений"},
+	}
+	for _, row := range rows {
+		prepared := ""
+		if row.DataPreparedOnDate.Valid {
+			prepared = row.DataPreparedOnDate.Time.Format(displayDateLayout)
+		}
+		tenders.Rows = append(tenders.Rows, []cell{
+			textCell(row.EtpID),
+			textCell(row.Title),
+			nullStringCell(row.CategoryTitle),
+			textCell(prepared),
+			textCell(row.CreatedAt.UTC().Format(displayDateLayout)),
+			countCell(row.LotsCount),
+			countCell(row.ProposalsCount),
+		})
+	}
+
+	return &document{
+		Title:  job.Name,
+		Period: periodLabel(job.From, job.To),
+		Tables: []table{tenders},
+	}, nil
+}
+
+// buildUnmatchedDocument — снимок очереди на момент формирования: период
+// не влияет на данные, в отчет попадает не больше reports.max_rows тендеров.
+func (s *ReportService) buildUnmatchedDocument(ctx context.Context, job reportJob) (*document, error) {
+	rows, err := s.store.ListUnmatchedBacklog(ctx, db.ListUnmatchedBacklogParams{
+		OrganizationID: job.OrganizationID,
+		RowLimit:       s.cfg.MaxRows,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ListUnmatchedBacklog: %w", err)
+	}
+
+	backlog := table{
+		Title:   "Несопоставленные позиции",
+		Columns: []string{"Тендер", "Название", "Несопоставленных позиций", "Ждет с"},
+	}
+	for _, row := range rows {
+		backlog.Rows = append(backlog.Rows, []cell{
+			textCell(row.TenderEtpID),
+			textCell(row.TenderTitle),
+			countCell(row.UnmatchedCount),
+			textCell(row.OldestCreatedAt.UTC().Format(displayDateLayout)),
+		})
+	}
+
+	return &document{
+		Title:  job.Name,
+		Period: "на " + job.To.Format(displayDateLayout),
+		Tables: []table{backlog},
+	}, nil
+}
@@ -0,0 +1,151 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/xlsx"
+)
+
+const (
+	pdfContentType = "application/pdf"
+	// maxPDFSize ограничивает ответ конвертера.
+	maxPDFSize = 50 << 20
+	// convertHTMLPath — эндпоинт конвертации HTML в PDF (API Gotenberg).
+	convertHTMLPath = "/forms/chromium/convert/html"
+)
+
+// renderXLSX записывает документ в книгу Excel: каждая таблица — отдельный лист.
+func renderXLSX(doc *document) ([]byte, error) {
+	book := xlsx.New()
+	for _, t := range doc.Tables {
+		sheet := book.AddSheet(t.Title)
+		sheet.AddHeader(t.Columns...)
+		for _, row := range t.Rows {
+			cells := make([]xlsx.Cell, len(row))
+			for i, c := range row {
+				switch {
+				case c.Text == "":
+					cells[i] = xlsx.Empty()
+				case c.Numeric:
+					cells[i] = xlsx.Number(c.Text)
+				default:
+					cells[i] = xlsx.Text(c.Text)
+				}
+			}
+			sheet.AddRow(cells...)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := book.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var documentTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+  @page { size: A4 landscape; margin: 12mm; }
+  body { font-family: "DejaVu Sans", Arial, sans-serif; font-size: 9pt; color: #222; }
+  h1 { font-size: 14pt; margin: 0 0 2mm; }
+  h2 { font-size: 11pt; margin: 6mm 0 2mm; }
+  .period { color: #666; margin-bottom: 4mm; }
+  table { width: 100%; border-collapse: collapse; }
+  th, td { border: 1px solid #ccc; padding: 1mm 1.5mm; text-align: left; vertical-align: top; }
+  th { background: #f0f0f0; }
+  td.num { text-align: right; white-space: nowrap; }
+  tr { page-break-inside: avoid; }
+  .empty { color: #666; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="period">{{.Period}}</div>
+{{range .Tables}}
+<h2>{{.Title}}</h2>
+{{if .Rows}}
+<table>
+<thead><tr>{{range .Columns}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{range .Rows}}<tr>{{range .}}<td{{if .Numeric}} class="num"{{end}}>{{.Text}}</td>{{end}}</tr>
+{{end}}</tbody>
+</table>
+{{else}}
+<p class="empty">Нет данных</p>
+{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+// renderHTML возвращает документ HTML-страницей для конвертации в PDF.
+func renderHTML(doc *document) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := documentTemplate.Execute(&buf, doc); err != nil {
+		return nil, fmt.Errorf("ошибка шаблона отчета: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// renderPDF отправляет HTML документа конвертеру (reports.pdf_converter_url)
+// и возвращает полученный PDF.
+func (s *ReportService) renderPDF(ctx context.Context, doc *document) ([]byte, error) {
+	page, err := renderHTML(doc)
+	if err != nil {
+		return nil, err
+	}
+	return s.convertPDF(ctx, page)
+}
+
+// convertPDF конвертирует HTML-страницу в PDF (reports.pdf_converter_url).
+func (s *ReportService) convertPDF(ctx context.Context, page []byte) ([]byte, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	file, err := form.CreateFormFile("files", "index.html")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Write(page); err != nil {
+		return nil, err
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	endpoint := strings.TrimRight(s.cfg.PDFConverterURL, "/") + convertHTMLPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, &body)
+	if err != nil {
+		return nil, fmt.Errorf("некорректный запрос к конвертеру PDF: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("конвертер PDF недоступен: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("конвертер PDF ответил %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	pdf, err := io.ReadAll(io.LimitReader(resp.Body, maxPDFSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения PDF: %w", err)
+	}
+	if len(pdf) > maxPDFSize {
+		return nil, fmt.Errorf("PDF больше %d МБ", maxPDFSize>>20)
+	}
+	return pdf, nil
+}
@@ -0,0 +1,218 @@
+// Package report предоставляет сервисный слой управленческих отчетов.
+//
+// Отчет об экономии сравнивает смету инициатора (baseline) с ценой победителя
+// по тендерам за период. Агрегаты считаются в БД (report.sql) в базовой валюте
+// по курсам currency.Rates; сервис проверяет параметры и раскладывает итоги
+// по категориям и месяцам.
+//
+// Регулярные отчеты (schedule.go, delivery.go) администратор задает видом,
+// форматом, периодичностью и адресатами; фоновый воркер (Run) формирует их
+// за закончившийся период в XLSX или PDF и отправляет по почте, записывая
+// каждый запуск в report_runs. Письмо собирает сервис notifications.
+//
+// Протокол выбора победителя (winner_protocol.go) формируется по лоту в DOCX
+// или PDF и сохраняется документом лота (lot_attachments).
+package report
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/currency"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/notifications"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// DateLayout — формат дат периода отчета.
+const DateLayout = "2006-01-02"
+
+// ReportService строит управленческие отчеты и рассылает регулярные отчеты.
+type ReportService struct {
+	store    db.Store
+	logger   logging.Logger
+	rates    *currency.Rates
+	cfg      config.ReportsConfig
+	notifier *notifications.Service
+	client   *http.Client // Конвертер HTML -> PDF
+}
+
+// NewReportService создаёт новый экземпляр ReportService.
+func NewReportService(
+	store db.Store,
+	logger logging.Logger,
+	rates *currency.Rates,
+	cfg config.ReportsConfig,
+	notifier *notifications.Service,
+) *ReportService {
+	return &ReportService{
+		store:    store,
+		logger:   logger,
+		rates:    rates,
+		cfg:      cfg,
+		notifier: notifier,
+		client:   &http.Client{Timeout: cfg.RequestTimeout},
+	}
+}
+
+// SavingsQuery — параметры отчета об экономии. Все фильтры опциональны.
+type SavingsQuery struct {
+	From           sql.NullTime // Первый день периода
+	To             sql.NullTime // Последний день периода, включительно
+	CategoryID     sql.NullInt64
+	OrganizationID sql.NullInt64 // Valid=false — все организации
+}
+
+// ParseDate разбирает дату периода в формате ГГГГ-ММ-ДД; пустая строка — без ограничения.
+func ParseDate(name, value string) (sql.NullTime, error) {
+	if value == "" {
+		return sql.NullTime{}, nil
+	}
+	t, err := time.Parse(DateLayout, value)
+	if err != nil {
+		return sql.NullTime{}, apierrors.NewValidationError("report.date_format", name)
+	}
+	return sql.NullTime{Time: t, Valid: true}, nil
+}
+
+// GetSavingsReport возвращает экономию по каждому тендеру периода (baseline
+// против цены победителя) и итоги по категориям, месяцам и в целом.
+// В отчет входят только лоты, у которых есть и baseline, и цена победителя.
+func (s *ReportService) GetSavingsReport(ctx context.Context, query SavingsQuery) (*api_models.SavingsReportResponse, error) {
+	logger := s.logger.WithField("method", "GetSavingsReport")
+
+	if query.From.Valid && query.To.Valid && query.From.Time.After(query.To.Time) {
+		return nil, apierrors.NewValidationError("report.from_after_to")
+	}
+	if query.CategoryID.Valid && query.CategoryID.Int64 <= 0 {
+		return nil, apierrors.NewValidationError("request.category_id_positive_got", query.CategoryID.Int64)
+	}
+
+	// to включительный: в SQL используется полуинтервал [from, to + 1 день)
+	dateTo := query.To
+	if dateTo.Valid {
+		dateTo.Time = dateTo.Time.AddDate(0, 0, 1)
+	}
+	currencies, rates := s.rates.QueryArgs()
+
+	tenders, err := s.store.ListTenderSavings(ctx, db.ListTenderSavingsParams{
+		Currencies:     currencies,
+		Rates:          rates,
+		DateFrom:       query.From,
+		DateTo:         dateTo,
+		CategoryID:     query.CategoryID,
+		OrganizationID: query.OrganizationID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка расчета экономии по тендерам: %w", err)
+	}
+
+	totals, err := s.store.ListSavingsTotals(ctx, db.ListSavingsTotalsParams{
+		Currencies:     currencies,
+		Rates:          rates,
+		DateFrom:       query.From,
+		DateTo:         dateTo,
+		CategoryID:     query.CategoryID,
+		OrganizationID: query.OrganizationID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка расчета итогов экономии: %w", err)
+	}
+
+	response := buildSavingsReport(query, s.rates.Base(), tenders, totals)
+	logger.Infof("Отчет об экономии: %d тендеров, %d сравнимых лотов", len(response.Tenders), response.Total.ComparedLotsCount)
+	return response, nil
+}
+
+// buildSavingsReport собирает ответ из строк БД.
+func buildSavingsReport(
+	query SavingsQuery,
+	baseCurrency string,
+	tenders []db.ListTenderSavingsRow,
+	totals []db.ListSavingsTotalsRow,
+) *api_models.SavingsReportResponse {
+	response := &api_models.SavingsReportResponse{
+		Currency:   baseCurrency,
+		DateFrom:   formatDate(query.From),
+		DateTo:     formatDate(query.To),
+		CategoryID: nullInt64Ptr(query.CategoryID),
+		Tenders:    make([]api_models.SavingsReportTender, 0, len(tenders)),
+		Categories: []api_models.SavingsCategoryTotals{},
+		Months:     []api_models.SavingsMonthTotals{},
+	}
+
+	for _, row := range tenders {
+		response.Tenders = append(response.Tenders, api_models.SavingsReportTender{
+			TenderID:          row.TenderID,
+			TenderEtpID:       row.TenderEtpID,
+			TenderTitle:       row.TenderTitle,
+			TenderDate:        row.TenderDate,
+			CategoryID:        nullInt64Ptr(row.CategoryID),
+			CategoryTitle:     nullStringPtr(row.CategoryTitle),
+			LotsCount:         row.LotsCount,
+			ComparedLotsCount: row.ComparedLotsCount,
+			BaselineCost:      api_models.MoneyFromNullString(row.BaselineCost),
+			WinningCost:       api_models.MoneyFromNullString(row.WinningCost),
+			Savings:           api_models.MoneyFromNullString(row.SavingsAmount),
+			SavingsPercent:    nullStringPtr(row.SavingsPercent),
+		})
+	}
+
+	for _, row := range totals {
+		sums := api_models.SavingsTotals{
+			TendersCount:      row.TendersCount,
+			ComparedLotsCount: row.ComparedLotsCount,
+			BaselineCost:      api_models.MoneyFromNullString(row.BaselineCost),
+			WinningCost:       api_models.MoneyFromNullString(row.WinningCost),
+			Savings:           api_models.MoneyFromNullString(row.SavingsAmount),
+			SavingsPercent:    nullStringPtr(row.SavingsPercent),
+		}
+		switch row.GroupBy {
+		case "category":
+			response.Categories = append(response.Categories, api_models.SavingsCategoryTotals{
+				CategoryID:    nullInt64Ptr(row.CategoryID),
+				CategoryTitle: nullStringPtr(row.CategoryTitle),
+				SavingsTotals: sums,
+			})
+		case "month":
+			response.Months = append(response.Months, api_models.SavingsMonthTotals{
+				Month:         row.Month,
+				SavingsTotals: sums,
+			})
+		case "total":
+			response.Total = sums
+		}
+	}
+
+	return response
+}
+
+func formatDate(t sql.NullTime) *string {
+	if !t.Valid {
+		return nil
+	}
+	s := t.Time.Format(DateLayout)
+	return &s
+}
+
+func nullInt64Ptr(n sql.NullInt64) *int64 {
+	if !n.Valid {
+		return nil
+	}
+	v := n.Int64
+	return &v
+}
+
+func nullStringPtr(ns sql.NullString) *string {
+	if !ns.Valid {
+		return nil
+	}
+	s := ns.String
+	return &s
+}
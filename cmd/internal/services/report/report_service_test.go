@@ -0,0 +1,219 @@
+package report
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/currency"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/notifications"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/mailer"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR SAVINGS REPORT (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Wrong KPI — savings must be reported exactly as computed in the DB (NUMERIC, no float64)
+2. Off-by-one periods — "to" is inclusive: tenders of the last day must be in the report
+3. Leaks — the report must be limited to the requester's organization
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: GetSavingsReport
+- GIVEN a period, a category and an organization
+  WHEN GetSavingsReport is called
+  THEN both queries get the rates, [from, to + 1 day), the category and the organization
+  AND tenders, category totals, month totals and the grand total are returned in the base currency
+
+- GIVEN no tenders with comparable lots
+  WHEN GetSavingsReport is called
+  THEN tenders, categories and months are empty lists, not null
+
+SCENARIO 2: Validation (no DB calls)
+- GIVEN from after to, or category_id <= 0 → ValidationError
+
+SCENARIO 3: ParseDate
+- GIVEN an empty value → no limit; a malformed date → ValidationError
+*/
+
+// fakeSender запоминает письма вместо отправки.
+type fakeSender struct {
+	sent []mailer.Message
+	err  error
+}
+
+func (f *fakeSender) Send(_ context.Context, msg mailer.Message) error {
+	f.sent = append(f.sent, msg)
+	return f.err
+}
+
+func testConfig() config.ReportsConfig {
+	return config.ReportsConfig{
+		Enabled:        true,
+		CheckInterval:  time.Minute,
+		BatchSize:      5,
+		MaxRows:        100,
+		RequestTimeout: 5 * time.Second,
+	}
+}
+
+func setupTestService(t *testing.T) (*ReportService, *db.MockStore) {
+	service, mockStore, _ := setupTestServiceWith(t, testConfig())
+	return service, mockStore
+}
+
+func setupTestServiceWith(t *testing.T, cfg config.ReportsConfig) (*ReportService, *db.MockStore, *fakeSender) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	rates := currency.NewRates(config.CurrencyConfig{Base: "RUB", Rates: map[string]string{"USD": "90"}})
+	sender := &fakeSender{}
+	logger := testutil.NewMockLogger()
+	notifier := notifications.NewService(sender, config.MailConfig{From: "reports@example.com"}, config.NotificationsConfig{}, logger)
+	return NewReportService(mockStore, logger, rates, cfg, notifier), mockStore, sender
+}
+
+func money(v string) sql.NullString { return sql.NullString{String: v, Valid: true} }
+
+func TestGetSavingsReport_Success(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	category := sql.NullInt64{Int64: 4, Valid: true}
+	organization := sql.NullInt64{Int64: 2, Valid: true}
+	tenderDate := time.Date(2025, 3, 31, 15, 0, 0, 0, time.UTC)
+
+	mockStore.EXPECT().ListTenderSavings(gomock.Any(), db.ListTenderSavingsParams{
+		Currencies:     []string{"RUB", "USD"},
+		Rates:          []string{"1", "90"},
+		DateFrom:       sql.NullTime{Time: from, Valid: true},
+		DateTo:         sql.NullTime{Time: to.AddDate(0, 0, 1), Valid: true},
+		CategoryID:     category,
+		OrganizationID: organization,
+	}).Return([]db.ListTenderSavingsRow{
+		{
+			TenderID:          10,
+			TenderEtpID:       "T-10",
+			TenderTitle:       "ЖК Север",
+			CategoryID:        category,
+			CategoryTitle:     sql.NullString{String: "Монолит", Valid: true},
+			TenderDate:        tenderDate,
+			LotsCount:         3,
+			ComparedLotsCount: 2,
+			BaselineCost:      money("1000000.00"),
+			WinningCost:       money("900000.00"),
+			SavingsAmount:     money("100000.00"),
+			SavingsPercent:    money("10.00"),
+		},
+	}, nil)
+	mockStore.EXPECT().ListSavingsTotals(gomock.Any(), db.ListSavingsTotalsParams{
+		Currencies:     []string{"RUB", "USD"},
+		Rates:          []string{"1", "90"},
+		DateFrom:       sql.NullTime{Time: from, Valid: true},
+		DateTo:         sql.NullTime{Time: to.AddDate(0, 0, 1), Valid: true},
+		CategoryID:     category,
+		OrganizationID: organization,
+	}).Return([]db.ListSavingsTotalsRow{
+		{GroupBy: "category", CategoryID: category, CategoryTitle: sql.NullString{String: "Монолит", Valid: true},
+			TendersCount: 1, ComparedLotsCount: 2, BaselineCost: money("1000000.00"), WinningCost: money("900000.00"),
+			SavingsAmount: money("100000.00"), SavingsPercent: money("10.00")},
+		{GroupBy: "month", Month: "2025-03",
+			TendersCount: 1, ComparedLotsCount: 2, BaselineCost: money("1000000.00"), WinningCost: money("900000.00"),
+			SavingsAmount: money("100000.00"), SavingsPercent: money("10.00")},
+		{GroupBy: "total",
+			TendersCount: 1, ComparedLotsCount: 2, BaselineCost: money("1000000.00"), WinningCost: money("900000.00"),
+			SavingsAmount: money("100000.00"), SavingsPercent: money("10.00")},
+	}, nil)
+
+	resp, err := service.GetSavingsReport(context.Background(), SavingsQuery{
+		From:           sql.NullTime{Time: from, Valid: true},
+		To:             sql.NullTime{Time: to, Valid: true},
+		CategoryID:     category,
+		OrganizationID: organization,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "RUB", resp.Currency)
+	assert.Equal(t, "2025-01-01", *resp.DateFrom)
+	assert.Equal(t, "2025-03-31", *resp.DateTo)
+	assert.Equal(t, int64(4), *resp.CategoryID)
+
+	require.Len(t, resp.Tenders, 1)
+	tender := resp.Tenders[0]
+	assert.Equal(t, "T-10", tender.TenderEtpID)
+	assert.Equal(t, "Монолит", *tender.CategoryTitle)
+	assert.Equal(t, int64(2), tender.ComparedLotsCount)
+	assert.Equal(t, "100000.00", tender.Savings.String())
+	assert.Equal(t, "10.00", *tender.SavingsPercent)
+
+	require.Len(t, resp.Categories, 1)
+	assert.Equal(t, int64(4), *resp.Categories[0].CategoryID)
+	require.Len(t, resp.Months, 1)
+	assert.Equal(t, "2025-03", resp.Months[0].Month)
+	assert.Equal(t, int64(2), resp.Total.ComparedLotsCount)
+	assert.Equal(t, "900000.00", resp.Total.WinningCost.String())
+}
+
+func TestGetSavingsReport_Empty(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ListTenderSavings(gomock.Any(), gomock.Any()).Return([]db.ListTenderSavingsRow{}, nil)
+	mockStore.EXPECT().ListSavingsTotals(gomock.Any(), gomock.Any()).
+		Return([]db.ListSavingsTotalsRow{{GroupBy: "total"}}, nil)
+
+	resp, err := service.GetSavingsReport(context.Background(), SavingsQuery{})
+	require.NoError(t, err)
+
+	assert.NotNil(t, resp.Tenders)
+	assert.NotNil(t, resp.Categories)
+	assert.NotNil(t, resp.Months)
+	assert.Nil(t, resp.DateFrom)
+	assert.Nil(t, resp.Total.Savings)
+}
+
+func TestGetSavingsReport_Validation(t *testing.T) {
+	service, _ := setupTestService(t)
+	day := func(d int) sql.NullTime {
+		return sql.NullTime{Time: time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC), Valid: true}
+	}
+
+	cases := map[string]SavingsQuery{
+		"from after to":    {From: day(10), To: day(9)},
+		"category not > 0": {CategoryID: sql.NullInt64{Int64: 0, Valid: true}},
+	}
+	for name, query := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.GetSavingsReport(context.Background(), query)
+			var validationErr *apierrors.ValidationError
+			assert.True(t, errors.As(err, &validationErr), "expected ValidationError, got: %v", err)
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	empty, err := ParseDate("from", "")
+	require.NoError(t, err)
+	assert.False(t, empty.Valid)
+
+	parsed, err := ParseDate("from", "2025-02-28")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), parsed.Time)
+
+	_, err = ParseDate("to", "28.02.2025")
+	var validationErr *apierrors.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Contains(t, validationErr.Message, "to")
+}
@@ -0,0 +1,382 @@
+package report
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/db/postgres"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+const (
+	// Виды регулярных отчетов (chk_scheduled_reports_kind)
+	KindSavings            = "savings"
+	KindNewTenders         = "new_tenders"
+	KindUnmatchedPositions = "unmatched_positions"
+
+	// Форматы файла (chk_scheduled_reports_format)
+	FormatXLSX = "xlsx"
+	FormatPDF  = "pdf"
+
+	// Периодичность (chk_scheduled_reports_frequency)
+	FrequencyDaily   = "daily"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+
+	// Статусы запуска (chk_report_runs_status)
+	RunStatusRunning = "running"
+	RunStatusSent    = "sent"
+	RunStatusFailed  = "failed"
+
+	maxNameLength = 200
+	maxRecipients = 50
+
+	defaultRunsLimit = 50
+	maxRunsLimit     = 200
+)
+
+// CreateSchedule создаёт регулярный отчет. Первый запуск — ближайшая граница
+// периода (полночь UTC следующего дня, понедельника или первого числа месяца).
+func (s *ReportService) CreateSchedule(ctx context.Context, req api_models.CreateScheduledReportRequest, actorID int64) (*api_models.ScheduledReportResponse, error) {
+	logger := s.logger.WithField("method", "CreateSchedule")
+
+	name, err := validateName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	switch req.Kind {
+	case KindSavings, KindNewTenders, KindUnmatchedPositions:
+	default:
+		return nil, apierrors.NewValidationError("report.invalid_kind",
+			req.Kind, KindSavings, KindNewTenders, KindUnmatchedPositions)
+	}
+	if err := s.validateFormat(req.Format); err != nil {
+		return nil, err
+	}
+	if err := validateFrequency(req.Frequency); err != nil {
+		return nil, err
+	}
+	recipients, err := normalizeRecipients(req.Recipients)
+	if err != nil {
+		return nil, err
+	}
+	organization, err := positiveID("organization_id", req.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	category, err := positiveID("category_id", req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	if category.Valid && req.Kind == KindUnmatchedPositions {
+		return nil, apierrors.NewValidationError("report.category_not_supported", KindUnmatchedPositions)
+	}
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	row, err := s.store.CreateScheduledReport(ctx, db.CreateScheduledReportParams{
+		Name:           name,
+		Kind:           req.Kind,
+		Format:         req.Format,
+		Frequency:      req.Frequency,
+		Recipients:     recipients,
+		OrganizationID: organization,
+		CategoryID:     category,
+		IsActive:       isActive,
+		CreatedBy:      sql.NullInt64{Int64: actorID, Valid: true},
+	})
+	if err != nil {
+		if postgres.IsForeignKeyViolation(err) {
+			return nil, apierrors.NewValidationError("report.organization_or_category_not_found")
+		}
+		return nil, fmt.Errorf("ошибка CreateScheduledReport: %w", err)
+	}
+
+	logger.Infof("Создан регулярный отчет id=%d (%s, %s, %s) для %v (оператор: %d)",
+		row.ID, row.Kind, row.Format, row.Frequency, row.Recipients, actorID)
+	resp := scheduleToResponse(row)
+	return &resp, nil
+}
+
+// ListSchedules возвращает все регулярные отчеты, новые первыми.
+func (s *ReportService) ListSchedules(ctx context.Context) ([]api_models.ScheduledReportResponse, error) {
+	rows, err := s.store.ListScheduledReports(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ListScheduledReports: %w", err)
+	}
+
+	result := make([]api_models.ScheduledReportResponse, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, scheduleToResponse(row))
+	}
+	return result, nil
+}
+
+// UpdateSchedule меняет название, формат, периодичность, адресатов, категорию
+// или активность отчета. Смена периодичности переносит следующий запуск на
+// ближайшую границу нового периода.
+func (s *ReportService) UpdateSchedule(ctx context.Context, id int64, req api_models.UpdateScheduledReportRequest) (*api_models.ScheduledReportResponse, error) {
+	logger := s.logger.WithField("method", "UpdateSchedule").WithField("scheduled_report_id", id)
+
+	if id <= 0 {
+		return nil, apierrors.NewValidationError("request.id_positive_got", id)
+	}
+	if req.Name == nil && req.Format == nil && req.Frequency == nil && req.Recipients == nil &&
+		req.CategoryID == nil && !req.ClearCategory && req.IsActive == nil {
+		return nil, apierrors.NewValidationError("request.no_fields_to_update")
+	}
+	if req.CategoryID != nil && req.ClearCategory {
+		return nil, apierrors.NewValidationError("report.category_and_clear")
+	}
+
+	params := db.UpdateScheduledReportParams{ID: id, ClearCategory: req.ClearCategory}
+	if req.Name != nil {
+		name, err := validateName(*req.Name)
+		if err != nil {
+			return nil, err
+		}
+		params.Name = sql.NullString{String: name, Valid: true}
+	}
+	if req.Format != nil {
+		if err := s.validateFormat(*req.Format); err != nil {
+			return nil, err
+		}
+		params.Format = sql.NullString{String: *req.Format, Valid: true}
+	}
+	if req.Frequency != nil {
+		if err := validateFrequency(*req.Frequency); err != nil {
+			return nil, err
+		}
+		params.Frequency = sql.NullString{String: *req.Frequency, Valid: true}
+	}
+	if req.Recipients != nil {
+		recipients, err := normalizeRecipients(*req.Recipients)
+		if err != nil {
+			return nil, err
+		}
+		params.Recipients = recipients
+	}
+	if req.CategoryID != nil {
+		category, err := positiveID("category_id", req.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		current, err := s.store.GetScheduledReport(ctx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, apierrors.NewNotFoundError("report.schedule_not_found", id)
+			}
+			return nil, fmt.Errorf("ошибка GetScheduledReport(%d): %w", id, err)
+		}
+		if current.Kind == KindUnmatchedPositions {
+			return nil, apierrors.NewValidationError("report.category_not_supported", KindUnmatchedPositions)
+		}
+		params.CategoryID = category
+	}
+	if req.IsActive != nil {
+		params.IsActive = sql.NullBool{Bool: *req.IsActive, Valid: true}
+	}
+
+	row, err := s.store.UpdateScheduledReport(ctx, params)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewNotFoundError("report.schedule_not_found", id)
+		}
+		if postgres.IsForeignKeyViolation(err) {
+			return nil, apierrors.NewValidationError("report.category_not_found", params.CategoryID.Int64)
+		}
+		return nil, fmt.Errorf("ошибка UpdateScheduledReport(%d): %w", id, err)
+	}
+
+	logger.Infof("Регулярный отчет id=%d обновлен: %s, %s, active=%t, следующий запуск %s",
+		row.ID, row.Format, row.Frequency, row.IsActive, row.NextRunAt.Format(time.RFC3339))
+	resp := scheduleToResponse(row)
+	return &resp, nil
+}
+
+// DeleteSchedule удаляет отчет вместе с историей запусков.
+// Чтобы сохранить историю, отчет достаточно деактивировать.
+func (s *ReportService) DeleteSchedule(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return apierrors.NewValidationError("request.id_positive_got", id)
+	}
+
+	deleted, err := s.store.DeleteScheduledReport(ctx, id)
+	if err != nil {
+		return fmt.Errorf("ошибка DeleteScheduledReport(%d): %w", id, err)
+	}
+	if deleted == 0 {
+		return apierrors.NewNotFoundError("report.schedule_not_found", id)
+	}
+
+	s.logger.Infof("Регулярный отчет id=%d удален", id)
+	return nil
+}
+
+// ListRuns возвращает историю запусков отчета, новые первыми.
+// limit <= 0 — значение по умолчанию.
+func (s *ReportService) ListRuns(ctx context.Context, scheduleID int64, limit, offset int32) (*api_models.ReportRunsResponse, error) {
+	if scheduleID <= 0 {
+		return nil, apierrors.NewValidationError("request.id_positive_got", scheduleID)
+	}
+	if limit <= 0 {
+		limit = defaultRunsLimit
+	}
+	if limit > maxRunsLimit {
+		return nil, apierrors.NewValidationError("request.limit_max", maxRunsLimit)
+	}
+	if offset < 0 {
+		return nil, apierrors.NewValidationError("request.offset_negative")
+	}
+
+	exists, err := s.store.ScheduledReportExists(ctx, scheduleID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ScheduledReportExists(%d): %w", scheduleID, err)
+	}
+	if !exists {
+		return nil, apierrors.NewNotFoundError("report.schedule_not_found", scheduleID)
+	}
+
+	rows, err := s.store.ListReportRuns(ctx, db.ListReportRunsParams{
+		ScheduledReportID: scheduleID,
+		PageLimit:         limit,
+		PageOffset:        offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ListReportRuns(%d): %w", scheduleID, err)
+	}
+
+	runs := make([]api_models.ReportRunResponse, 0, len(rows))
+	for _, row := range rows {
+		runs = append(runs, runToResponse(row))
+	}
+	return &api_models.ReportRunsResponse{Runs: runs, Limit: limit, Offset: offset}, nil
+}
+
+func validateName(raw string) (string, error) {
+	name := strings.TrimSpace(raw)
+	if name == "" {
+		return "", apierrors.NewValidationError("common.name_empty")
+	}
+	if len([]rune(name)) > maxNameLength {
+		return "", apierrors.NewValidationError("report.name_too_long", maxNameLength)
+	}
+	return name, nil
+}
+
+// validateFormat проверяет формат файла. PDF доступен, только если настроен конвертер.
+func (s *ReportService) validateFormat(format string) error {
+	switch format {
+	case FormatXLSX:
+		return nil
+	case FormatPDF:
+		if s.cfg.PDFConverterURL == "" {
+			return apierrors.NewValidationError("report.format_unavailable", FormatPDF)
+		}
+		return nil
+	default:
+		return apierrors.NewValidationError("report.invalid_format", format, FormatXLSX, FormatPDF)
+	}
+}
+
+func validateFrequency(frequency string) error {
+	switch frequency {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly:
+		return nil
+	default:
+		return apierrors.NewValidationError("report.invalid_frequency",
+			frequency, FrequencyDaily, FrequencyWeekly, FrequencyMonthly)
+	}
+}
+
+// normalizeRecipients проверяет адреса и оставляет только сам адрес
+// (без отображаемого имени), убирая повторы без учёта регистра.
+func normalizeRecipients(raw []string) ([]string, error) {
+	result := make([]string, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, r := range raw {
+		addr, err := mail.ParseAddress(strings.TrimSpace(r))
+		if err != nil {
+			return nil, apierrors.NewValidationError("report.invalid_recipient", r)
+		}
+		key := strings.ToLower(addr.Address)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, addr.Address)
+	}
+	if len(result) == 0 {
+		return nil, apierrors.NewValidationError("report.recipients_empty")
+	}
+	if len(result) > maxRecipients {
+		return nil, apierrors.NewValidationError("report.recipients_too_many", maxRecipients)
+	}
+	return result, nil
+}
+
+func positiveID(name string, id *int64) (sql.NullInt64, error) {
+	if id == nil {
+		return sql.NullInt64{}, nil
+	}
+	if *id <= 0 {
+		return sql.NullInt64{}, apierrors.NewValidationError("report.param_positive", name, *id)
+	}
+	return sql.NullInt64{Int64: *id, Valid: true}, nil
+}
+
+func scheduleToResponse(row db.ScheduledReport) api_models.ScheduledReportResponse {
+	resp := api_models.ScheduledReportResponse{
+		ID:             row.ID,
+		Name:           row.Name,
+		Kind:           row.Kind,
+		Format:         row.Format,
+		Frequency:      row.Frequency,
+		Recipients:     row.Recipients,
+		OrganizationID: nullInt64Ptr(row.OrganizationID),
+		CategoryID:     nullInt64Ptr(row.CategoryID),
+		IsActive:       row.IsActive,
+		NextRunAt:      row.NextRunAt,
+		CreatedBy:      nullInt64Ptr(row.CreatedBy),
+		CreatedAt:      row.CreatedAt,
+		UpdatedAt:      row.UpdatedAt,
+	}
+	if row.LastRunAt.Valid {
+		t := row.LastRunAt.Time
+		resp.LastRunAt = &t
+	}
+	return resp
+}
+
+func runToResponse(row db.ReportRun) api_models.ReportRunResponse {
+	resp := api_models.ReportRunResponse{
+		ID:                row.ID,
+		ScheduledReportID: row.ScheduledReportID,
+		Status:            row.Status,
+		PeriodFrom:        row.PeriodFrom,
+		PeriodTo:          row.PeriodTo,
+		Recipients:        row.Recipients,
+		FileName:          nullStringPtr(row.FileName),
+		FileSize:          nullInt64Ptr(row.FileSize),
+		Error:             nullStringPtr(row.Error),
+		StartedAt:         row.StartedAt,
+	}
+	if row.RowsCount.Valid {
+		n := row.RowsCount.Int32
+		resp.RowsCount = &n
+	}
+	if row.FinishedAt.Valid {
+		t := row.FinishedAt.Time
+		resp.FinishedAt = &t
+	}
+	return resp
+}
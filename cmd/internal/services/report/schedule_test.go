@@ -0,0 +1,274 @@
+package report
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR SCHEDULED REPORTS (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Undeliverable reports — a typo in an address or an unsupported format must be
+   rejected when the schedule is saved, not discovered on the first run
+2. Spam — duplicated recipients must receive one letter
+3. Missing history — runs must be listed per report with paging
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: CreateSchedule
+- GIVEN a valid request
+  WHEN CreateSchedule is called
+  THEN the trimmed name, normalized unique recipients, filters and the operator
+       are stored; the schedule is active by default
+- GIVEN an unknown kind/format/frequency, a bad address, no recipients,
+  a category for the unmatched positions backlog or PDF without a converter
+  THEN ValidationError, no DB calls
+- GIVEN an unknown organization or category (FK violation)
+  THEN ValidationError
+
+SCENARIO 2: UpdateSchedule
+- GIVEN only is_active and a new frequency
+  THEN only those fields are sent, the rest stay NULL
+- GIVEN a category for an unmatched positions report
+  THEN ValidationError
+- GIVEN an empty request or category_id together with clear_category → ValidationError
+- GIVEN a missing report → NotFoundError
+
+SCENARIO 3: DeleteSchedule / ListRuns
+- GIVEN a missing report → NotFoundError
+- GIVEN limit above the maximum → ValidationError
+- GIVEN an existing report → runs with paging
+*/
+
+func scheduleRow(id int64, kind string) db.ScheduledReport {
+	now := time.Date(2025, 3, 5, 10, 0, 0, 0, time.UTC)
+	return db.ScheduledReport{
+		ID:         id,
+		Name:       "Экономия за неделю",
+		Kind:       kind,
+		Format:     FormatXLSX,
+		Frequency:  FrequencyWeekly,
+		Recipients: []string{"a@example.com"},
+		IsActive:   true,
+		NextRunAt:  time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+		CreatedBy:  sql.NullInt64{Int64: 1, Valid: true},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func TestCreateSchedule_Success(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().CreateScheduledReport(gomock.Any(), db.CreateScheduledReportParams{
+		Name:           "Экономия за неделю",
+		Kind:           KindSavings,
+		Format:         FormatXLSX,
+		Frequency:      FrequencyWeekly,
+		Recipients:     []string{"a@example.com", "b@example.com"},
+		OrganizationID: sql.NullInt64{Int64: 2, Valid: true},
+		CategoryID:     sql.NullInt64{Int64: 4, Valid: true},
+		IsActive:       true,
+		CreatedBy:      sql.NullInt64{Int64: 1, Valid: true},
+	}).Return(scheduleRow(7, KindSavings), nil)
+
+	resp, err := service.CreateSchedule(context.Background(), api_models.CreateScheduledReportRequest{
+		Name:           "  Экономия за неделю ",
+		Kind:           KindSavings,
+		Format:         FormatXLSX,
+		Frequency:      FrequencyWeekly,
+		Recipients:     []string{"a@example.com", "Отдел Б <b@example.com>", "A@example.com"},
+		OrganizationID: int64Ptr(2),
+		CategoryID:     int64Ptr(4),
+	}, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(7), resp.ID)
+	assert.Equal(t, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), resp.NextRunAt)
+	assert.Nil(t, resp.LastRunAt)
+	assert.Equal(t, int64(1), *resp.CreatedBy)
+}
+
+func TestCreateSchedule_ValidationErrors(t *testing.T) {
+	valid := func() api_models.CreateScheduledReportRequest {
+		return api_models.CreateScheduledReportRequest{
+			Name: "Отчет", Kind: KindNewTenders, Format: FormatXLSX, Frequency: FrequencyDaily,
+			Recipients: []string{"a@example.com"},
+		}
+	}
+	cases := map[string]func(r *api_models.CreateScheduledReportRequest){
+		"empty name":            func(r *api_models.CreateScheduledReportRequest) { r.Name = "  " },
+		"unknown kind":          func(r *api_models.CreateScheduledReportRequest) { r.Kind = "contractors" },
+		"unknown format":        func(r *api_models.CreateScheduledReportRequest) { r.Format = "csv" },
+		"pdf without converter": func(r *api_models.CreateScheduledReportRequest) { r.Format = FormatPDF },
+		"unknown frequency":     func(r *api_models.CreateScheduledReportRequest) { r.Frequency = "hourly" },
+		"bad address":           func(r *api_models.CreateScheduledReportRequest) { r.Recipients = []string{"a@example.com", "b"} },
+		"no recipients":         func(r *api_models.CreateScheduledReportRequest) { r.Recipients = nil },
+		"organization not > 0":  func(r *api_models.CreateScheduledReportRequest) { r.OrganizationID = int64Ptr(0) },
+		"category for backlog": func(r *api_models.CreateScheduledReportRequest) {
+			r.Kind = KindUnmatchedPositions
+			r.CategoryID = int64Ptr(4)
+		},
+	}
+	for name, mutate := range cases {
+		t.Run(name, func(t *testing.T) {
+			service, _ := setupTestService(t)
+			req := valid()
+			mutate(&req)
+
+			_, err := service.CreateSchedule(context.Background(), req, 1)
+
+			var validationErr *apierrors.ValidationError
+			assert.True(t, errors.As(err, &validationErr), "expected ValidationError, got: %v", err)
+		})
+	}
+}
+
+func TestCreateSchedule_PDFWithConverter(t *testing.T) {
+	cfg := testConfig()
+	cfg.PDFConverterURL = "http://gotenberg:3000"
+	service, mockStore, _ := setupTestServiceWith(t, cfg)
+
+	mockStore.EXPECT().CreateScheduledReport(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateScheduledReportParams) (db.ScheduledReport, error) {
+			assert.Equal(t, FormatPDF, arg.Format)
+			assert.False(t, arg.IsActive)
+			return scheduleRow(8, KindNewTenders), nil
+		})
+
+	inactive := false
+	_, err := service.CreateSchedule(context.Background(), api_models.CreateScheduledReportRequest{
+		Name: "Новые тендеры", Kind: KindNewTenders, Format: FormatPDF, Frequency: FrequencyDaily,
+		Recipients: []string{"a@example.com"}, IsActive: &inactive,
+	}, 1)
+	require.NoError(t, err)
+}
+
+func TestCreateSchedule_UnknownCategory(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	mockStore.EXPECT().CreateScheduledReport(gomock.Any(), gomock.Any()).
+		Return(db.ScheduledReport{}, &pq.Error{Code: "23503"})
+
+	_, err := service.CreateSchedule(context.Background(), api_models.CreateScheduledReportRequest{
+		Name: "Отчет", Kind: KindSavings, Format: FormatXLSX, Frequency: FrequencyMonthly,
+		Recipients: []string{"a@example.com"}, CategoryID: int64Ptr(999),
+	}, 1)
+
+	var validationErr *apierrors.ValidationError
+	assert.True(t, errors.As(err, &validationErr), "expected ValidationError, got: %v", err)
+}
+
+func TestUpdateSchedule_SendsOnlyGivenFields(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	inactive := false
+	monthly := FrequencyMonthly
+
+	mockStore.EXPECT().UpdateScheduledReport(gomock.Any(), db.UpdateScheduledReportParams{
+		ID:        7,
+		Frequency: sql.NullString{String: FrequencyMonthly, Valid: true},
+		IsActive:  sql.NullBool{Bool: false, Valid: true},
+	}).Return(scheduleRow(7, KindSavings), nil)
+
+	resp, err := service.UpdateSchedule(context.Background(), 7, api_models.UpdateScheduledReportRequest{
+		Frequency: &monthly,
+		IsActive:  &inactive,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), resp.ID)
+}
+
+func TestUpdateSchedule_CategoryChecksKind(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	mockStore.EXPECT().GetScheduledReport(gomock.Any(), int64(7)).Return(scheduleRow(7, KindUnmatchedPositions), nil)
+
+	_, err := service.UpdateSchedule(context.Background(), 7, api_models.UpdateScheduledReportRequest{CategoryID: int64Ptr(4)})
+
+	var validationErr *apierrors.ValidationError
+	assert.True(t, errors.As(err, &validationErr), "expected ValidationError, got: %v", err)
+}
+
+func TestUpdateSchedule_Errors(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	_, err := service.UpdateSchedule(context.Background(), 7, api_models.UpdateScheduledReportRequest{})
+	var validationErr *apierrors.ValidationError
+	assert.True(t, errors.As(err, &validationErr), "empty request: %v", err)
+
+	_, err = service.UpdateSchedule(context.Background(), 7, api_models.UpdateScheduledReportRequest{
+		CategoryID: int64Ptr(4), ClearCategory: true,
+	})
+	assert.True(t, errors.As(err, &validationErr), "category_id + clear_category: %v", err)
+
+	mockStore.EXPECT().UpdateScheduledReport(gomock.Any(), gomock.Any()).Return(db.ScheduledReport{}, sql.ErrNoRows)
+	_, err = service.UpdateSchedule(context.Background(), 7, api_models.UpdateScheduledReportRequest{ClearCategory: true})
+	var notFoundErr *apierrors.NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr), "expected NotFoundError, got: %v", err)
+}
+
+func TestDeleteSchedule_NotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	mockStore.EXPECT().DeleteScheduledReport(gomock.Any(), int64(7)).Return(int64(0), nil)
+
+	err := service.DeleteSchedule(context.Background(), 7)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr), "expected NotFoundError, got: %v", err)
+}
+
+func TestListRuns(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	_, err := service.ListRuns(context.Background(), 7, maxRunsLimit+1, 0)
+	var validationErr *apierrors.ValidationError
+	require.True(t, errors.As(err, &validationErr), "expected ValidationError, got: %v", err)
+
+	started := time.Date(2025, 3, 10, 0, 1, 0, 0, time.UTC)
+	mockStore.EXPECT().ScheduledReportExists(gomock.Any(), int64(7)).Return(true, nil)
+	mockStore.EXPECT().ListReportRuns(gomock.Any(), db.ListReportRunsParams{
+		ScheduledReportID: 7,
+		PageLimit:         defaultRunsLimit,
+		PageOffset:        10,
+	}).Return([]db.ReportRun{
+		{
+			ID:                3,
+			ScheduledReportID: 7,
+			Status:            RunStatusFailed,
+			PeriodFrom:        time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC),
+			PeriodTo:          time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+			Recipients:        []string{"a@example.com"},
+			Error:             sql.NullString{String: "SMTP RCPT TO: 550", Valid: true},
+			StartedAt:         started,
+			FinishedAt:        sql.NullTime{Time: started.Add(time.Second), Valid: true},
+		},
+	}, nil)
+
+	resp, err := service.ListRuns(context.Background(), 7, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int32(defaultRunsLimit), resp.Limit)
+	require.Len(t, resp.Runs, 1)
+	assert.Equal(t, "SMTP RCPT TO: 550", *resp.Runs[0].Error)
+	assert.Nil(t, resp.Runs[0].FileName)
+	assert.Nil(t, resp.Runs[0].RowsCount)
+
+	mockStore.EXPECT().ScheduledReportExists(gomock.Any(), int64(8)).Return(false, nil)
+	_, err = service.ListRuns(context.Background(), 8, 0, 0)
+	var notFoundErr *apierrors.NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr), "expected NotFoundError, got: %v", err)
+}
@@ -0,0 +1,340 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"html/template"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/docx"
+)
+
+const (
+	// FormatDOCX — протокол документом Word (по умолчанию, без конвертера).
+	FormatDOCX = "docx"
+
+	// AttachmentKindWinnerProtocol — lot_attachments.kind протокола выбора победителя.
+	AttachmentKindWinnerProtocol = "winner_protocol"
+)
+
+// winnerProtocol — содержимое протокола выбора победителя, общее для DOCX и PDF.
+// Суммы — в базовой валюте.
+type winnerProtocol struct {
+	TenderEtpID    string
+	TenderTitle    string
+	TenderDate     string
+	ObjectTitle    string
+	ObjectAddress  string
+	LotKey         string
+	LotTitle       string
+	ProposalsCount int64
+	Currency       string
+	Baseline       *api_models.Money
+	Winners        []protocolWinner
+	GeneratedAt    string
+}
+
+// protocolWinner — строка таблицы победителей. Экономия считается от
+// baseline до цены контракта, а если её нет — до итога предложения.
+type protocolWinner struct {
+	Rank           string
+	ContractorName string
+	ContractorInn  string
+	OfferTotal     *api_models.Money
+	AwardPrice     *api_models.Money
+	Savings        *api_models.Money
+	SavingsPercent string
+	Share          string
+	Notes          string
+}
+
+// GenerateWinnerProtocol формирует протокол выбора победителя лота (format —
+// docx или pdf, пусто — docx) и сохраняет его документом лота. Лот без
+// победителей — ConflictError; PDF без reports.pdf_converter_url — ValidationError.
+func (s *ReportService) GenerateWinnerProtocol(
+	ctx context.Context,
+	lotID int64,
+	format string,
+	userID sql.NullInt64,
+) (*api_models.LotAttachment, error) {
+	if format == "" {
+		format = FormatDOCX
+	}
+	switch format {
+	case FormatDOCX:
+	case FormatPDF:
+		if s.cfg.PDFConverterURL == "" {
+			return nil, apierrors.NewValidationError("report.format_unavailable", FormatPDF)
+		}
+	default:
+		return nil, apierrors.NewValidationError("report.invalid_format", format, FormatDOCX, FormatPDF)
+	}
+
+	protocol, err := s.buildWinnerProtocol(ctx, lotID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		data        []byte
+		contentType string
+	)
+	if format == FormatPDF {
+		page, err := renderProtocolHTML(protocol)
+		if err != nil {
+			return nil, err
+		}
+		if data, err = s.convertPDF(ctx, page); err != nil {
+			return nil, err
+		}
+		contentType = pdfContentType
+	} else {
+		var buf bytes.Buffer
+		if err := renderProtocolDOCX(protocol).Write(&buf); err != nil {
+			return nil, fmt.Errorf("ошибка формирования DOCX: %w", err)
+		}
+		data, contentType = buf.Bytes(), docx.ContentType
+	}
+
+	fileName := fmt.Sprintf("winner_protocol_lot%d_%s.%s", lotID, time.Now().Format(DateLayout), format)
+	row, err := s.store.CreateLotAttachment(ctx, db.CreateLotAttachmentParams{
+		LotID:       lotID,
+		Kind:        AttachmentKindWinnerProtocol,
+		FileName:    fileName,
+		ContentType: contentType,
+		Content:     data,
+		CreatedBy:   userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка CreateLotAttachment(lot_id=%d): %w", lotID, err)
+	}
+	s.logger.Infof("Сформирован протокол выбора победителя лота %d: %s (%d байт)", lotID, fileName, len(data))
+
+	attachment := toLotAttachment(db.ListLotAttachmentsRow(row))
+	return &attachment, nil
+}
+
+// buildWinnerProtocol собирает данные протокола: шапку лота и победителей по месту.
+func (s *ReportService) buildWinnerProtocol(ctx context.Context, lotID int64) (*winnerProtocol, error) {
+	currencies, rates := s.rates.QueryArgs()
+
+	lot, err := s.store.GetWinnerProtocolLot(ctx, db.GetWinnerProtocolLotParams{
+		LotID:      lotID,
+		Currencies: currencies,
+		Rates:      rates,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewNotFoundError("report.lot_not_found", lotID)
+		}
+		return nil, fmt.Errorf("ошибка GetWinnerProtocolLot(%d): %w", lotID, err)
+	}
+
+	winners, err := s.store.ListWinnerProtocolWinners(ctx, db.ListWinnerProtocolWinnersParams{
+		LotID:      lotID,
+		Currencies: currencies,
+		Rates:      rates,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ListWinnerProtocolWinners(%d): %w", lotID, err)
+	}
+	if len(winners) == 0 {
+		return nil, apierrors.NewConflictErrorf(nil, "report.no_winners", lotID)
+	}
+
+	protocol := &winnerProtocol{
+		TenderEtpID:    lot.TenderEtpID,
+		TenderTitle:    lot.TenderTitle,
+		TenderDate:     lot.TenderDate.Format(displayDateLayout),
+		ObjectTitle:    lot.ObjectTitle,
+		ObjectAddress:  lot.ObjectAddress,
+		LotKey:         lot.LotKey,
+		LotTitle:       lot.LotTitle,
+		ProposalsCount: lot.ProposalsCount,
+		Currency:       s.rates.Base(),
+		Baseline:       api_models.MoneyFromNullString(lot.BaselineTotal),
+		GeneratedAt:    time.Now().UTC().Format(displayDateLayout + " 15:04 UTC"),
+	}
+	for _, w := range winners {
+		winner := protocolWinner{
+			ContractorName: w.ContractorName,
+			ContractorInn:  w.ContractorInn,
+			OfferTotal:     api_models.MoneyFromNullString(w.OfferTotal),
+			AwardPrice:     api_models.MoneyFromNullString(w.AwardPrice),
+			Notes:          w.Notes.String,
+		}
+		if w.Rank.Valid {
+			winner.Rank = strconv.Itoa(int(w.Rank.Int32))
+		}
+		if w.AwardedShare.Valid {
+			winner.Share = w.AwardedShare.String
+		}
+		price := winner.AwardPrice
+		if price == nil {
+			price = winner.OfferTotal
+		}
+		if protocol.Baseline != nil && price != nil {
+			savings := protocol.Baseline.Sub(price.Decimal)
+			winner.Savings = api_models.MoneyPtr(savings)
+			if !protocol.Baseline.IsZero() {
+				winner.SavingsPercent = savings.Div(protocol.Baseline.Decimal).Mul(decimal.NewFromInt(100)).StringFixed(2)
+			}
+		}
+		protocol.Winners = append(protocol.Winners, winner)
+	}
+	return protocol, nil
+}
+
+// moneyText — сумма для протокола; нет суммы — «—».
+func moneyText(m *api_models.Money) string {
+	if m == nil {
+		return "—"
+	}
+	return m.String()
+}
+
+func renderProtocolDOCX(p *winnerProtocol) *docx.Document {
+	doc := docx.New()
+	doc.AddHeading("Протокол выбора победителя", 1)
+	doc.AddParagraph(fmt.Sprintf("Тендер: %s «%s» от %s", p.TenderEtpID, p.TenderTitle, p.TenderDate))
+	doc.AddParagraph(fmt.Sprintf("Объект: %s, %s", p.ObjectTitle, p.ObjectAddress))
+	doc.AddParagraph(fmt.Sprintf("Лот %s: %s", p.LotKey, p.LotTitle))
+	doc.AddParagraph(fmt.Sprintf("Предложений подрядчиков: %d", p.ProposalsCount))
+	doc.AddParagraph(fmt.Sprintf("Смета инициатора (baseline) с НДС, %s: %s", p.Currency, moneyText(p.Baseline)))
+
+	doc.AddHeading("Победители", 2)
+	rows := make([][]docx.Cell, 0, len(p.Winners))
+	for _, w := range p.Winners {
+		rows = append(rows, []docx.Cell{
+			docx.Text(w.Rank),
+			docx.Text(w.ContractorName),
+			docx.Text(w.ContractorInn),
+			docx.Number(moneyText(w.OfferTotal)),
+			docx.Number(moneyText(w.AwardPrice)),
+			docx.Number(moneyText(w.Savings)),
+			docx.Number(w.SavingsPercent),
+			docx.Number(w.Share),
+		})
+	}
+	doc.AddTable([]string{
+		"Место", "Подрядчик", "ИНН", "Предложение с НДС", "Цена контракта", "Экономия", "Экономия, %", "Доля, %",
+	}, rows...)
+	doc.AddParagraph(fmt.Sprintf("Суммы в %s. Экономия — от сметы инициатора до цены контракта (без неё — до предложения).", p.Currency))
+
+	for _, w := range p.Winners {
+		if w.Notes != "" {
+			doc.AddParagraph(fmt.Sprintf("Примечание (%s): %s", w.ContractorName, w.Notes))
+		}
+	}
+
+	doc.AddParagraph("\nПредседатель комиссии ____________________ / ____________________ /")
+	doc.AddParagraph("Члены комиссии ____________________ / ____________________ /")
+	doc.AddParagraph("Сформирован: " + p.GeneratedAt)
+	return doc
+}
+
+var protocolTemplate = template.Must(template.New("protocol").Funcs(template.FuncMap{"money": moneyText}).Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<title>Протокол выбора победителя</title>
+<style>
+  @page { size: A4 portrait; margin: 20mm 15mm 20mm 30mm; }
+  body { font-family: "Times New Roman", "DejaVu Serif", serif; font-size: 11pt; color: #000; }
+  h1 { font-size: 14pt; text-align: center; margin: 0 0 6mm; }
+  h2 { font-size: 12pt; margin: 6mm 0 2mm; }
+  p { margin: 0 0 1.5mm; }
+  table { width: 100%; border-collapse: collapse; font-size: 10pt; }
+  th, td { border: 1px solid #999; padding: 1mm 1.5mm; text-align: left; vertical-align: top; }
+  th { background: #f0f0f0; }
+  td.num { text-align: right; white-space: nowrap; }
+  .note { color: #444; font-size: 9pt; margin-top: 2mm; }
+  .signatures { margin-top: 12mm; }
+</style>
+</head>
+<body>
+<h1>Протокол выбора победителя</h1>
+<p>Тендер: {{.TenderEtpID}} «{{.TenderTitle}}» от {{.TenderDate}}</p>
+<p>Объект: {{.ObjectTitle}}, {{.ObjectAddress}}</p>
+<p>Лот {{.LotKey}}: {{.LotTitle}}</p>
+<p>Предложений подрядчиков: {{.ProposalsCount}}</p>
+<p>Смета инициатора (baseline) с НДС, {{.Currency}}: {{money .Baseline}}</p>
+<h2>Победители</h2>
+<table>
+<thead><tr><th>Место</th><th>Подрядчик</th><th>ИНН</th><th>Предложение с НДС</th><th>Цена контракта</th><th>Экономия</th><th>Экономия, %</th><th>Доля, %</th></tr></thead>
+<tbody>
+{{range .Winners}}<tr><td>{{.Rank}}</td><td>{{.ContractorName}}</td><td>{{.ContractorInn}}</td><td class="num">{{money .OfferTotal}}</td><td class="num">{{money .AwardPrice}}</td><td class="num">{{money .Savings}}</td><td class="num">{{.SavingsPercent}}</td><td class="num">{{.Share}}</td></tr>
+{{end}}</tbody>
+</table>
+<p class="note">Суммы в {{.Currency}}. Экономия — от сметы инициатора до цены контракта (без неё — до предложения).</p>
+{{range .Winners}}{{if .Notes}}<p>Примечание ({{.ContractorName}}): {{.Notes}}</p>
+{{end}}{{end}}
+<div class="signatures">
+<p>Председатель комиссии ____________________ / ____________________ /</p>
+<p>Члены комиссии ____________________ / ____________________ /</p>
+</div>
+<p class="note">Сформирован: {{.GeneratedAt}}</p>
+</body>
+</html>
+`))
+
+// renderProtocolHTML возвращает протокол HTML-страницей для конвертации в PDF.
+func renderProtocolHTML(p *winnerProtocol) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := protocolTemplate.Execute(&buf, p); err != nil {
+		return nil, fmt.Errorf("ошибка шаблона протокола: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ListLotAttachments возвращает документы лота без содержимого, новые первыми.
+func (s *ReportService) ListLotAttachments(ctx context.Context, lotID int64) (*api_models.LotAttachmentsResponse, error) {
+	rows, err := s.store.ListLotAttachments(ctx, lotID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ListLotAttachments(%d): %w", lotID, err)
+	}
+	items := make([]api_models.LotAttachment, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, toLotAttachment(row))
+	}
+	return &api_models.LotAttachmentsResponse{LotID: lotID, Items: items}, nil
+}
+
+// GetLotAttachment возвращает документ лота с содержимым; документ другого
+// лота — NotFoundError.
+func (s *ReportService) GetLotAttachment(ctx context.Context, lotID, attachmentID int64) (*db.LotAttachment, error) {
+	attachment, err := s.store.GetLotAttachment(ctx, db.GetLotAttachmentParams{ID: attachmentID, LotID: lotID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewNotFoundError("report.document_not_found", attachmentID, lotID)
+		}
+		return nil, fmt.Errorf("ошибка GetLotAttachment(%d): %w", attachmentID, err)
+	}
+	return &attachment, nil
+}
+
+func toLotAttachment(row db.ListLotAttachmentsRow) api_models.LotAttachment {
+	attachment := api_models.LotAttachment{
+		ID:          row.ID,
+		LotID:       row.LotID,
+		Kind:        row.Kind,
+		FileName:    row.FileName,
+		ContentType: row.ContentType,
+		FileSize:    row.FileSize,
+		CreatedAt:   row.CreatedAt,
+		DownloadURL: fmt.Sprintf("/api/v1/lots/%d/attachments/%d", row.LotID, row.ID),
+	}
+	if row.CreatedBy.Valid {
+		id := row.CreatedBy.Int64
+		attachment.CreatedBy = &id
+	}
+	return attachment
+}
@@ -0,0 +1,238 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/docx"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR WINNER PROTOCOL (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Manual paperwork — after choosing a winner the protocol must be generated, not typed
+2. Wrong figures — savings are baseline minus contract price (offer total without it)
+3. Lost documents — every generated protocol is stored as a lot attachment
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: DOCX protocol
+- GIVEN a lot with a baseline and two winners, one without a contract price
+  WHEN GenerateWinnerProtocol is called without format
+  THEN a .docx with the lot header, both winners and their savings is stored
+  AND the attachment metadata with the download URL is returned
+
+SCENARIO 2: PDF protocol
+- GIVEN a configured PDF converter
+  WHEN GenerateWinnerProtocol is called with format=pdf
+  THEN the HTML protocol is converted and stored as application/pdf
+
+SCENARIO 3: Refusals (nothing stored)
+- GIVEN an unknown format or pdf without a converter → ValidationError
+- GIVEN a missing lot → NotFoundError
+- GIVEN a lot without winners → ConflictError
+
+SCENARIO 4: Attachments
+- GIVEN an attachment of another lot
+  WHEN GetLotAttachment is called
+  THEN NotFoundError
+*/
+
+func protocolLot() db.GetWinnerProtocolLotRow {
+	return db.GetWinnerProtocolLotRow{
+		LotID:          7,
+		LotKey:         "LOT_1",
+		LotTitle:       "Бетонные работы",
+		TenderID:       3,
+		TenderEtpID:    "0373-1",
+		TenderTitle:    "ЖК Север",
+		TenderDate:     time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		ObjectTitle:    "ЖК Север, корпус 1",
+		ObjectAddress:  "г. Москва",
+		ProposalsCount: 4,
+		BaselineTotal:  money("1000000.00"),
+	}
+}
+
+func protocolWinners() []db.ListWinnerProtocolWinnersRow {
+	return []db.ListWinnerProtocolWinnersRow{
+		{
+			WinnerID:       1,
+			Rank:           sql.NullInt32{Int32: 1, Valid: true},
+			AwardedShare:   money("60.00"),
+			ContractorName: "ООО Альфа & Ко",
+			ContractorInn:  "7700000001",
+			OfferTotal:     money("950000.00"),
+			AwardPrice:     money("900000.00"),
+		},
+		{
+			WinnerID:       2,
+			Rank:           sql.NullInt32{Int32: 2, Valid: true},
+			Notes:          sql.NullString{String: "Поставка бетона", Valid: true},
+			ContractorName: "ООО Бета",
+			ContractorInn:  "7700000002",
+			OfferTotal:     money("980000.00"),
+		},
+	}
+}
+
+func expectProtocolData(mockStore *db.MockStore) {
+	mockStore.EXPECT().GetWinnerProtocolLot(gomock.Any(), db.GetWinnerProtocolLotParams{
+		LotID: 7, Currencies: []string{"RUB", "USD"}, Rates: []string{"1", "90"},
+	}).Return(protocolLot(), nil)
+	mockStore.EXPECT().ListWinnerProtocolWinners(gomock.Any(), db.ListWinnerProtocolWinnersParams{
+		LotID: 7, Currencies: []string{"RUB", "USD"}, Rates: []string{"1", "90"},
+	}).Return(protocolWinners(), nil)
+}
+
+func documentXML(t *testing.T, data []byte) string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	for _, f := range zr.File {
+		if f.Name == "word/document.xml" {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			defer rc.Close()
+			content, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			return string(content)
+		}
+	}
+	t.Fatal("word/document.xml not found")
+	return ""
+}
+
+func TestGenerateWinnerProtocol_DOCX(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	createdAt := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	user := sql.NullInt64{Int64: 5, Valid: true}
+
+	expectProtocolData(mockStore)
+	mockStore.EXPECT().CreateLotAttachment(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateLotAttachmentParams) (db.CreateLotAttachmentRow, error) {
+			assert.Equal(t, int64(7), arg.LotID)
+			assert.Equal(t, AttachmentKindWinnerProtocol, arg.Kind)
+			assert.Equal(t, docx.ContentType, arg.ContentType)
+			assert.Regexp(t, `^winner_protocol_lot7_\d{4}-\d{2}-\d{2}\.docx$`, arg.FileName)
+			assert.Equal(t, user, arg.CreatedBy)
+
+			body := documentXML(t, arg.Content)
+			assert.Contains(t, body, "Протокол выбора победителя")
+			assert.Contains(t, body, "Лот LOT_1: Бетонные работы")
+			assert.Contains(t, body, "1000000.00")
+			assert.Contains(t, body, "ООО Альфа &amp; Ко")
+			// Альфа: 1 000 000 − 900 000 (цена контракта)
+			assert.Contains(t, body, ">100000.00<")
+			assert.Contains(t, body, ">10.00<")
+			// Бета: цены контракта нет — экономия от предложения
+			assert.Contains(t, body, ">20000.00<")
+			assert.Contains(t, body, ">2.00<")
+			assert.Contains(t, body, "Примечание (ООО Бета): Поставка бетона")
+
+			return db.CreateLotAttachmentRow{
+				ID: 11, LotID: arg.LotID, Kind: arg.Kind, FileName: arg.FileName,
+				ContentType: arg.ContentType, FileSize: int64(len(arg.Content)), CreatedBy: arg.CreatedBy, CreatedAt: createdAt,
+			}, nil
+		})
+
+	attachment, err := service.GenerateWinnerProtocol(context.Background(), 7, "", user)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), attachment.ID)
+	assert.Equal(t, "/api/v1/lots/7/attachments/11", attachment.DownloadURL)
+	require.NotNil(t, attachment.CreatedBy)
+	assert.Equal(t, int64(5), *attachment.CreatedBy)
+	assert.Positive(t, attachment.FileSize)
+}
+
+func TestGenerateWinnerProtocol_PDF(t *testing.T) {
+	converter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("files")
+		require.NoError(t, err)
+		page, err := io.ReadAll(file)
+		require.NoError(t, err)
+		assert.Contains(t, string(page), "ООО Альфа &amp; Ко")
+		assert.Contains(t, string(page), `<td class="num">100000.00</td>`)
+		_, _ = w.Write([]byte("%PDF-1.7 protocol"))
+	}))
+	defer converter.Close()
+
+	cfg := testConfig()
+	cfg.PDFConverterURL = converter.URL
+	service, mockStore, _ := setupTestServiceWith(t, cfg)
+
+	expectProtocolData(mockStore)
+	mockStore.EXPECT().CreateLotAttachment(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateLotAttachmentParams) (db.CreateLotAttachmentRow, error) {
+			assert.Equal(t, pdfContentType, arg.ContentType)
+			assert.Equal(t, "%PDF-1.7 protocol", string(arg.Content))
+			return db.CreateLotAttachmentRow{ID: 12, LotID: 7, ContentType: arg.ContentType}, nil
+		})
+
+	attachment, err := service.GenerateWinnerProtocol(context.Background(), 7, FormatPDF, sql.NullInt64{})
+
+	require.NoError(t, err)
+	assert.Equal(t, pdfContentType, attachment.ContentType)
+	assert.Nil(t, attachment.CreatedBy)
+}
+
+func TestGenerateWinnerProtocol_Refusals(t *testing.T) {
+	t.Run("unknown format", func(t *testing.T) {
+		service, _ := setupTestService(t)
+		_, err := service.GenerateWinnerProtocol(context.Background(), 7, FormatXLSX, sql.NullInt64{})
+		var validationErr *apierrors.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("pdf without converter", func(t *testing.T) {
+		service, _ := setupTestService(t)
+		_, err := service.GenerateWinnerProtocol(context.Background(), 7, FormatPDF, sql.NullInt64{})
+		var validationErr *apierrors.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("missing lot", func(t *testing.T) {
+		service, mockStore := setupTestService(t)
+		mockStore.EXPECT().GetWinnerProtocolLot(gomock.Any(), gomock.Any()).Return(db.GetWinnerProtocolLotRow{}, sql.ErrNoRows)
+		_, err := service.GenerateWinnerProtocol(context.Background(), 7, FormatDOCX, sql.NullInt64{})
+		var notFoundErr *apierrors.NotFoundError
+		assert.ErrorAs(t, err, &notFoundErr)
+	})
+
+	t.Run("no winners", func(t *testing.T) {
+		service, mockStore := setupTestService(t)
+		mockStore.EXPECT().GetWinnerProtocolLot(gomock.Any(), gomock.Any()).Return(protocolLot(), nil)
+		mockStore.EXPECT().ListWinnerProtocolWinners(gomock.Any(), gomock.Any()).Return([]db.ListWinnerProtocolWinnersRow{}, nil)
+		_, err := service.GenerateWinnerProtocol(context.Background(), 7, FormatDOCX, sql.NullInt64{})
+		var conflictErr *apierrors.ConflictError
+		assert.ErrorAs(t, err, &conflictErr)
+	})
+}
+
+func TestGetLotAttachment_OtherLot(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	mockStore.EXPECT().GetLotAttachment(gomock.Any(), db.GetLotAttachmentParams{ID: 11, LotID: 8}).
+		Return(db.LotAttachment{}, sql.ErrNoRows)
+
+	_, err := service.GetLotAttachment(context.Background(), 8, 11)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
@@ -0,0 +1,322 @@
+// Package retention удаляет устаревшие данные по политикам хранения из секции
+// retention конфигурации: истекшие и давно отозванные сессии, истекший
+// matching_cache, старые версии исходного JSON импортов.
+//
+// Политика за запуск удаляет не больше retention.batch_size строк, поэтому
+// DELETE не держит долгих блокировок; остаток удаляют следующие запуски.
+// Политики из retention.dry_run только считают подходящие строки. Каждый
+// запуск записывается в retention_runs (GET /api/v1/admin/retention).
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// Статусы запуска политики (retention_runs.status).
+const (
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+)
+
+const (
+	defaultRunsLimit = 50
+	maxRunsLimit     = 200
+)
+
+// Service выполняет политики хранения.
+type Service struct {
+	store  db.Store
+	logger logging.Logger
+	cfg    config.RetentionConfig
+	now    func() time.Time // Подменяется в тестах
+}
+
+// NewService создаёт сервис политик хранения.
+func NewService(store db.Store, logger logging.Logger, cfg config.RetentionConfig) *Service {
+	return &Service{
+		store:  store,
+		logger: logger.WithField("service", "retention"),
+		cfg:    cfg,
+		now:    time.Now,
+	}
+}
+
+// RunAll выполняет включённые политики (JobFunc задачи планировщика
+// retention). Ошибка одной политики не останавливает остальные.
+func (s *Service) RunAll(ctx context.Context) (string, error) {
+	var summary []string
+	var errs []error
+	for _, policy := range config.RetentionPolicies {
+		if !s.cfg.PolicyEnabled(policy) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		run, err := s.run(ctx, policy, s.cfg.IsDryRun(policy), sql.NullInt64{})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if run.DryRun {
+			summary = append(summary, fmt.Sprintf("%s: найдено %d (пробный запуск)", policy, run.MatchedCount))
+		} else {
+			summary = append(summary, fmt.Sprintf("%s: удалено %d из %d", policy, run.DeletedCount, run.MatchedCount))
+		}
+	}
+	if len(summary) == 0 && len(errs) == 0 {
+		return "нет включённых политик", nil
+	}
+	return strings.Join(summary, "; "), errors.Join(errs...)
+}
+
+// RunPolicy выполняет одну политику по запросу администратора
+// (POST /api/v1/admin/retention/:policy/run). Политика из retention.dry_run
+// выполняется в пробном режиме независимо от dryRun.
+func (s *Service) RunPolicy(ctx context.Context, policy string, dryRun bool, triggeredBy sql.NullInt64) (*api_models.RetentionRun, error) {
+	if !slices.Contains(config.RetentionPolicies, policy) {
+		return nil, apierrors.NewNotFoundError("retention.policy_not_found", policy)
+	}
+	if !s.cfg.PolicyEnabled(policy) {
+		return nil, apierrors.NewConflictErrorf(nil, "retention.policy_disabled", policy)
+	}
+	run, err := s.run(ctx, policy, dryRun || s.cfg.IsDryRun(policy), triggeredBy)
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// run считает подходящие строки, удаляет порцию (кроме пробного запуска) и
+// записывает запуск в retention_runs — и успешный, и неудачный.
+func (s *Service) run(ctx context.Context, policy string, dryRun bool, triggeredBy sql.NullInt64) (*api_models.RetentionRun, error) {
+	startedAt := s.now()
+	cutoff := s.cutoff(policy, startedAt)
+
+	var deleted int64
+	matched, runErr := s.count(ctx, policy, cutoff)
+	if runErr == nil && !dryRun && matched > 0 {
+		deleted, runErr = s.deleteBatch(ctx, policy, cutoff)
+	}
+
+	params := db.CreateRetentionRunParams{
+		Policy:       policy,
+		DryRun:       dryRun,
+		Status:       StatusSuccess,
+		Cutoff:       cutoff,
+		MatchedCount: matched,
+		DeletedCount: deleted,
+		TriggeredBy:  triggeredBy,
+		StartedAt:    startedAt,
+	}
+	if runErr != nil {
+		params.Status = StatusFailed
+		params.Error = sql.NullString{String: runErr.Error(), Valid: true}
+	}
+	// Запись в журнал — с отдельным контекстом: отмена запуска не должна
+	// терять отчёт о нём
+	row, err := s.store.CreateRetentionRun(context.WithoutCancel(ctx), params)
+	if err != nil {
+		s.logger.Errorf("Не удалось записать запуск политики %s: %v", policy, err)
+		if runErr == nil {
+			runErr = fmt.Errorf("ошибка CreateRetentionRun(%s): %w", policy, err)
+		}
+	}
+	if runErr != nil {
+		return nil, runErr
+	}
+
+	if dryRun {
+		s.logger.Infof("Политика %s (пробный запуск): записей старше %s: %d", policy, cutoff.Format(time.RFC3339), matched)
+	} else {
+		s.logger.Infof("Политика %s: записей старше %s: %d, удалено: %d", policy, cutoff.Format(time.RFC3339), matched, deleted)
+	}
+	result := toRunResponse(row)
+	return &result, nil
+}
+
+// cutoff — граница политики: удаляются записи старше неё.
+func (s *Service) cutoff(policy string, now time.Time) time.Time {
+	switch policy {
+	case config.RetentionExpiredSessions:
+		return now.Add(-s.cfg.ExpiredSessionsAfter)
+	case config.RetentionRevokedSessions:
+		return now.Add(-s.cfg.RevokedSessionsAfter)
+	case config.RetentionRawImports:
+		return now.AddDate(0, -s.cfg.RawImportHistoryMonths, 0)
+	}
+	return now
+}
+
+func (s *Service) count(ctx context.Context, policy string, cutoff time.Time) (int64, error) {
+	var (
+		count int64
+		err   error
+	)
+	switch policy {
+	case config.RetentionExpiredSessions:
+		count, err = s.store.CountExpiredSessions(ctx, cutoff)
+	case config.RetentionRevokedSessions:
+		count, err = s.store.CountRevokedSessions(ctx, cutoff)
+	case config.RetentionMatchingCache:
+		count, err = s.store.CountExpiredMatchingCache(ctx, cutoff)
+	case config.RetentionRawImports:
+		count, err = s.store.CountOldRawImportVersions(ctx, cutoff)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("ошибка подсчёта записей политики %s: %w", policy, err)
+	}
+	return count, nil
+}
+
+func (s *Service) deleteBatch(ctx context.Context, policy string, cutoff time.Time) (int64, error) {
+	var (
+		deleted int64
+		err     error
+	)
+	switch policy {
+	case config.RetentionExpiredSessions:
+		deleted, err = s.store.DeleteExpiredSessionsBatch(ctx, db.DeleteExpiredSessionsBatchParams{
+			Cutoff: cutoff, BatchSize: s.cfg.BatchSize,
+		})
+	case config.RetentionRevokedSessions:
+		deleted, err = s.store.DeleteRevokedSessionsBatch(ctx, db.DeleteRevokedSessionsBatchParams{
+			Cutoff: cutoff, BatchSize: s.cfg.BatchSize,
+		})
+	case config.RetentionMatchingCache:
+		deleted, err = s.store.DeleteExpiredMatchingCacheBatch(ctx, db.DeleteExpiredMatchingCacheBatchParams{
+			Cutoff: cutoff, BatchSize: s.cfg.BatchSize,
+		})
+	case config.RetentionRawImports:
+		deleted, err = s.store.DeleteOldRawImportVersionsBatch(ctx, db.DeleteOldRawImportVersionsBatchParams{
+			Cutoff: cutoff, BatchSize: s.cfg.BatchSize,
+		})
+	}
+	if err != nil {
+		return 0, fmt.Errorf("ошибка удаления записей политики %s: %w", policy, err)
+	}
+	return deleted, nil
+}
+
+// ListPolicies возвращает политики с их настройками и последним запуском.
+func (s *Service) ListPolicies(ctx context.Context) (*api_models.RetentionPoliciesResponse, error) {
+	rows, err := s.store.ListLatestRetentionRuns(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ListLatestRetentionRuns: %w", err)
+	}
+	lastRuns := make(map[string]api_models.RetentionRun, len(rows))
+	for _, row := range rows {
+		lastRuns[row.Policy] = toRunResponse(row)
+	}
+
+	policies := make([]api_models.RetentionPolicy, 0, len(config.RetentionPolicies))
+	for _, policy := range config.RetentionPolicies {
+		item := api_models.RetentionPolicy{
+			Policy:    policy,
+			Enabled:   s.cfg.PolicyEnabled(policy),
+			DryRun:    s.cfg.IsDryRun(policy),
+			Retention: s.retentionPeriod(policy),
+		}
+		if run, ok := lastRuns[policy]; ok {
+			item.LastRun = &run
+		}
+		policies = append(policies, item)
+	}
+	return &api_models.RetentionPoliciesResponse{
+		Enabled:   s.cfg.Enabled,
+		Interval:  s.cfg.Interval.String(),
+		BatchSize: s.cfg.BatchSize,
+		Policies:  policies,
+	}, nil
+}
+
+// retentionPeriod описывает срок хранения политики; отключённой — пусто.
+func (s *Service) retentionPeriod(policy string) string {
+	if !s.cfg.PolicyEnabled(policy) {
+		return ""
+	}
+	switch policy {
+	case config.RetentionExpiredSessions:
+		return s.cfg.ExpiredSessionsAfter.String()
+	case config.RetentionRevokedSessions:
+		return s.cfg.RevokedSessionsAfter.String()
+	case config.RetentionRawImports:
+		return fmt.Sprintf("%d мес.", s.cfg.RawImportHistoryMonths)
+	}
+	return "expires_at"
+}
+
+// ListRuns возвращает историю запусков, новые первыми; пустая policy — все
+// политики. limit <= 0 — значение по умолчанию.
+func (s *Service) ListRuns(ctx context.Context, policy string, limit, offset int32) (*api_models.RetentionRunsResponse, error) {
+	if policy != "" && !slices.Contains(config.RetentionPolicies, policy) {
+		return nil, apierrors.NewValidationError("retention.unknown_policy",
+			policy, strings.Join(config.RetentionPolicies, ", "))
+	}
+	if limit <= 0 {
+		limit = defaultRunsLimit
+	}
+	if limit > maxRunsLimit {
+		return nil, apierrors.NewValidationError("request.limit_max", maxRunsLimit)
+	}
+	if offset < 0 {
+		return nil, apierrors.NewValidationError("request.offset_negative")
+	}
+
+	policyArg := sql.NullString{String: policy, Valid: policy != ""}
+	rows, err := s.store.ListRetentionRuns(ctx, db.ListRetentionRunsParams{
+		Policy:     policyArg,
+		PageLimit:  limit,
+		PageOffset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ListRetentionRuns: %w", err)
+	}
+	total, err := s.store.CountRetentionRuns(ctx, policyArg)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка CountRetentionRuns: %w", err)
+	}
+
+	items := make([]api_models.RetentionRun, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, toRunResponse(row))
+	}
+	return &api_models.RetentionRunsResponse{
+		Items:  items,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+func toRunResponse(row db.RetentionRun) api_models.RetentionRun {
+	run := api_models.RetentionRun{
+		ID:           row.ID,
+		Policy:       row.Policy,
+		DryRun:       row.DryRun,
+		Status:       row.Status,
+		Cutoff:       row.Cutoff,
+		MatchedCount: row.MatchedCount,
+		DeletedCount: row.DeletedCount,
+		Error:        row.Error.String,
+		StartedAt:    row.StartedAt,
+		FinishedAt:   row.FinishedAt,
+	}
+	if row.TriggeredBy.Valid {
+		userID := row.TriggeredBy.Int64
+		run.TriggeredBy = &userID
+	}
+	return run
+}
@@ -0,0 +1,219 @@
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR DATA RETENTION (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Unbounded growth — sessions, cache and import history pile up forever
+2. Long locks — one huge DELETE blocks logins and imports
+3. Surprises — an admin must see what a policy would delete before enabling it
+4. Lost history — the latest import version of a tender is needed for the next diff
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Scheduled run
+- GIVEN sessions, cache and raw history policies with their retention periods
+  WHEN RunAll is called
+  THEN each enabled policy counts rows older than its cutoff and deletes at most batch_size
+  AND every run is recorded in retention_runs; a disabled policy is not touched
+- GIVEN a policy listed in dry_run THEN it only counts, nothing is deleted
+- GIVEN one policy fails THEN the others still run, the failure is recorded and returned
+
+SCENARIO 2: Manual run
+- GIVEN dry_run=true THEN only the count is recorded, with the admin as triggered_by
+- GIVEN an unknown policy → NotFoundError; a disabled policy → ConflictError
+
+SCENARIO 3: Report
+- GIVEN recorded runs
+  WHEN ListPolicies is called
+  THEN every policy is listed with its settings and its last run
+- GIVEN an unknown policy filter or limit above the maximum → ValidationError
+*/
+
+var testNow = time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+
+func testConfig() config.RetentionConfig {
+	return config.RetentionConfig{
+		Enabled:              true,
+		Interval:             time.Hour,
+		BatchSize:            100,
+		ExpiredSessionsAfter: 168 * time.Hour,
+		RevokedSessionsAfter: 720 * time.Hour,
+		MatchingCache:        true,
+	}
+}
+
+func setupTestService(t *testing.T, cfg config.RetentionConfig) (*Service, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	service := NewService(mockStore, testutil.NewMockLogger(), cfg)
+	service.now = func() time.Time { return testNow }
+	return service, mockStore
+}
+
+// expectRecordedRun проверяет запись запуска и возвращает её как строку таблицы.
+func expectRecordedRun(t *testing.T, mockStore *db.MockStore, want db.CreateRetentionRunParams) {
+	t.Helper()
+	mockStore.EXPECT().CreateRetentionRun(gomock.Any(), want).
+		DoAndReturn(func(_ context.Context, arg db.CreateRetentionRunParams) (db.RetentionRun, error) {
+			return db.RetentionRun{
+				ID: 1, Policy: arg.Policy, DryRun: arg.DryRun, Status: arg.Status, Cutoff: arg.Cutoff,
+				MatchedCount: arg.MatchedCount, DeletedCount: arg.DeletedCount, Error: arg.Error,
+				TriggeredBy: arg.TriggeredBy, StartedAt: arg.StartedAt, FinishedAt: arg.StartedAt,
+			}, nil
+		})
+}
+
+func TestRunAll(t *testing.T) {
+	cfg := testConfig()
+	cfg.RawImportHistoryMonths = 6
+	cfg.DryRun = []string{config.RetentionRevokedSessions}
+	service, mockStore := setupTestService(t, cfg)
+
+	sessionsCutoff := testNow.Add(-168 * time.Hour)
+	revokedCutoff := testNow.Add(-720 * time.Hour)
+	rawCutoff := time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC)
+
+	mockStore.EXPECT().CountExpiredSessions(gomock.Any(), sessionsCutoff).Return(int64(250), nil)
+	mockStore.EXPECT().DeleteExpiredSessionsBatch(gomock.Any(), db.DeleteExpiredSessionsBatchParams{
+		Cutoff: sessionsCutoff, BatchSize: 100,
+	}).Return(int64(100), nil)
+	expectRecordedRun(t, mockStore, db.CreateRetentionRunParams{
+		Policy: config.RetentionExpiredSessions, Status: StatusSuccess, Cutoff: sessionsCutoff,
+		MatchedCount: 250, DeletedCount: 100, StartedAt: testNow,
+	})
+
+	mockStore.EXPECT().CountRevokedSessions(gomock.Any(), revokedCutoff).Return(int64(7), nil)
+	expectRecordedRun(t, mockStore, db.CreateRetentionRunParams{
+		Policy: config.RetentionRevokedSessions, DryRun: true, Status: StatusSuccess, Cutoff: revokedCutoff,
+		MatchedCount: 7, StartedAt: testNow,
+	})
+
+	mockStore.EXPECT().CountExpiredMatchingCache(gomock.Any(), testNow).Return(int64(0), nil)
+	expectRecordedRun(t, mockStore, db.CreateRetentionRunParams{
+		Policy: config.RetentionMatchingCache, Status: StatusSuccess, Cutoff: testNow, StartedAt: testNow,
+	})
+
+	mockStore.EXPECT().CountOldRawImportVersions(gomock.Any(), rawCutoff).Return(int64(3), nil)
+	mockStore.EXPECT().DeleteOldRawImportVersionsBatch(gomock.Any(), db.DeleteOldRawImportVersionsBatchParams{
+		Cutoff: rawCutoff, BatchSize: 100,
+	}).Return(int64(3), nil)
+	expectRecordedRun(t, mockStore, db.CreateRetentionRunParams{
+		Policy: config.RetentionRawImports, Status: StatusSuccess, Cutoff: rawCutoff,
+		MatchedCount: 3, DeletedCount: 3, StartedAt: testNow,
+	})
+
+	result, err := service.RunAll(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "expired_sessions: удалено 100 из 250; revoked_sessions: найдено 7 (пробный запуск); "+
+		"matching_cache: удалено 0 из 0; raw_import_history: удалено 3 из 3", result)
+}
+
+func TestRunAll_FailedPolicyRecorded(t *testing.T) {
+	cfg := testConfig()
+	cfg.ExpiredSessionsAfter = 0
+	cfg.RevokedSessionsAfter = 0
+	service, mockStore := setupTestService(t, cfg)
+	dbErr := errors.New("connection reset")
+
+	mockStore.EXPECT().CountExpiredMatchingCache(gomock.Any(), testNow).Return(int64(5), nil)
+	mockStore.EXPECT().DeleteExpiredMatchingCacheBatch(gomock.Any(), gomock.Any()).Return(int64(0), dbErr)
+	mockStore.EXPECT().CreateRetentionRun(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateRetentionRunParams) (db.RetentionRun, error) {
+			assert.Equal(t, StatusFailed, arg.Status)
+			assert.Equal(t, int64(5), arg.MatchedCount)
+			assert.Contains(t, arg.Error.String, "connection reset")
+			return db.RetentionRun{ID: 2}, nil
+		})
+
+	_, err := service.RunAll(context.Background())
+
+	assert.ErrorIs(t, err, dbErr)
+}
+
+func TestRunPolicy(t *testing.T) {
+	t.Run("manual dry run", func(t *testing.T) {
+		service, mockStore := setupTestService(t, testConfig())
+		admin := sql.NullInt64{Int64: 3, Valid: true}
+		mockStore.EXPECT().CountExpiredSessions(gomock.Any(), testNow.Add(-168*time.Hour)).Return(int64(40), nil)
+		expectRecordedRun(t, mockStore, db.CreateRetentionRunParams{
+			Policy: config.RetentionExpiredSessions, DryRun: true, Status: StatusSuccess,
+			Cutoff: testNow.Add(-168 * time.Hour), MatchedCount: 40, TriggeredBy: admin, StartedAt: testNow,
+		})
+
+		run, err := service.RunPolicy(context.Background(), config.RetentionExpiredSessions, true, admin)
+
+		require.NoError(t, err)
+		assert.True(t, run.DryRun)
+		assert.Equal(t, int64(40), run.MatchedCount)
+		assert.Zero(t, run.DeletedCount)
+		require.NotNil(t, run.TriggeredBy)
+		assert.Equal(t, int64(3), *run.TriggeredBy)
+	})
+
+	t.Run("unknown policy", func(t *testing.T) {
+		service, _ := setupTestService(t, testConfig())
+		_, err := service.RunPolicy(context.Background(), "idempotency_keys", false, sql.NullInt64{})
+		var notFoundErr *apierrors.NotFoundError
+		assert.ErrorAs(t, err, &notFoundErr)
+	})
+
+	t.Run("disabled policy", func(t *testing.T) {
+		service, _ := setupTestService(t, testConfig())
+		_, err := service.RunPolicy(context.Background(), config.RetentionRawImports, false, sql.NullInt64{})
+		var conflictErr *apierrors.ConflictError
+		assert.ErrorAs(t, err, &conflictErr)
+	})
+}
+
+func TestListPolicies(t *testing.T) {
+	service, mockStore := setupTestService(t, testConfig())
+	mockStore.EXPECT().ListLatestRetentionRuns(gomock.Any()).Return([]db.RetentionRun{
+		{ID: 9, Policy: config.RetentionMatchingCache, Status: StatusSuccess, DeletedCount: 12},
+	}, nil)
+
+	resp, err := service.ListPolicies(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, resp.Enabled)
+	assert.Equal(t, "1h0m0s", resp.Interval)
+	require.Len(t, resp.Policies, 4)
+	assert.Equal(t, "168h0m0s", resp.Policies[0].Retention)
+	assert.Nil(t, resp.Policies[0].LastRun)
+	require.NotNil(t, resp.Policies[2].LastRun)
+	assert.Equal(t, int64(12), resp.Policies[2].LastRun.DeletedCount)
+	assert.False(t, resp.Policies[3].Enabled)
+	assert.Empty(t, resp.Policies[3].Retention)
+}
+
+func TestListRuns_Validation(t *testing.T) {
+	service, _ := setupTestService(t, testConfig())
+	var validationErr *apierrors.ValidationError
+
+	_, err := service.ListRuns(context.Background(), "sessions", 0, 0)
+	assert.ErrorAs(t, err, &validationErr)
+
+	_, err = service.ListRuns(context.Background(), "", maxRunsLimit+1, 0)
+	assert.ErrorAs(t, err, &validationErr)
+}
@@ -0,0 +1,249 @@
+// Package scheduler запускает периодические фоновые задачи внутри процесса API
+// (очистка кэшей и т.п.) и хранит состояние последнего запуска каждой задачи
+// для GET /api/v1/admin/jobs.
+//
+// Задача выполняется сразу после Start и далее раз в свой интервал. Если
+// предыдущий запуск ещё не завершился, очередной пропускается (SkippedCount),
+// поэтому долгая задача не накапливает параллельные копии. Между экземплярами
+// API запуски разделяет joblock.Locker: задачу выполняет экземпляр, взявший
+// блокировку с её именем, остальные пропускают запуск (LockedCount).
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/joblock"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// Статусы последнего запуска задачи.
+const (
+	StatusNever   = "never"
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+)
+
+// JobFunc выполняет задачу и возвращает краткий итог для журнала и API
+// (например, число удалённых записей).
+type JobFunc func(ctx context.Context) (string, error)
+
+type job struct {
+	name     string
+	interval time.Duration
+	fn       JobFunc
+
+	mu             sync.Mutex
+	running        bool
+	nextRunAt      time.Time
+	lastStatus     string
+	lastStartedAt  time.Time
+	lastFinishedAt time.Time
+	lastDuration   time.Duration
+	lastResult     string
+	lastError      string
+	runCount       int64
+	failureCount   int64
+	skippedCount   int64
+	lockedCount    int64
+}
+
+// Scheduler — набор периодических задач.
+type Scheduler struct {
+	logger logging.Logger
+	locker joblock.Locker
+
+	mu      sync.Mutex
+	jobs    []*job
+	started bool
+	wg      sync.WaitGroup
+}
+
+// New создаёт пустой планировщик; задачи добавляются через Register до Start.
+// locker разделяет запуски между экземплярами API (joblock.Local — без блокировок).
+func New(logger logging.Logger, locker joblock.Locker) *Scheduler {
+	return &Scheduler{logger: logger.WithField("component", "scheduler"), locker: locker}
+}
+
+// Register добавляет задачу. Имена задач уникальны.
+func (s *Scheduler) Register(name string, interval time.Duration, fn JobFunc) error {
+	if interval <= 0 {
+		return fmt.Errorf("интервал задачи %s должен быть положительным", name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return fmt.Errorf("задача %s регистрируется после запуска планировщика", name)
+	}
+	for _, j := range s.jobs {
+		if j.name == name {
+			return fmt.Errorf("задача %s уже зарегистрирована", name)
+		}
+	}
+	s.jobs = append(s.jobs, &job{name: name, interval: interval, fn: fn, lastStatus: StatusNever})
+	return nil
+}
+
+// Start запускает задачи в фоне до отмены ctx.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+	s.started = true
+
+	for _, j := range s.jobs {
+		s.logger.Infof("Задача %s: запуск каждые %s", j.name, j.interval)
+		s.wg.Add(1)
+		go s.loop(ctx, j)
+	}
+}
+
+// Wait ждёт завершения циклов задач после отмены ctx, переданного в Start.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	defer s.wg.Done()
+
+	var runs sync.WaitGroup
+	defer runs.Wait()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	s.trigger(ctx, j, &runs)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.trigger(ctx, j, &runs)
+		}
+	}
+}
+
+// trigger запускает задачу в отдельной горутине, если предыдущий запуск завершён.
+func (s *Scheduler) trigger(ctx context.Context, j *job, runs *sync.WaitGroup) {
+	j.mu.Lock()
+	j.nextRunAt = time.Now().Add(j.interval)
+	if j.running {
+		j.skippedCount++
+		j.mu.Unlock()
+		s.logger.Warnf("Задача %s: предыдущий запуск ещё выполняется, запуск пропущен", j.name)
+		return
+	}
+	j.running = true
+	j.lastStartedAt = time.Now()
+	j.mu.Unlock()
+
+	runs.Add(1)
+	go func() {
+		defer runs.Done()
+		s.execute(ctx, j)
+	}()
+}
+
+func (s *Scheduler) execute(ctx context.Context, j *job) {
+	logger := s.logger.WithField("job", j.name)
+
+	j.mu.Lock()
+	startedAt := j.lastStartedAt
+	j.mu.Unlock()
+
+	var result string
+	ran, err := s.locker.TryRun(ctx, j.name, func(ctx context.Context) error {
+		var runErr error
+		result, runErr = runSafely(ctx, j.fn)
+		return runErr
+	})
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.running = false
+	if !ran && err == nil {
+		j.lockedCount++
+		return
+	}
+	j.lastFinishedAt = time.Now()
+	j.lastDuration = j.lastFinishedAt.Sub(startedAt)
+	j.runCount++
+	if err != nil {
+		j.failureCount++
+		j.lastStatus = StatusFailed
+		j.lastError = err.Error()
+		j.lastResult = ""
+		logger.Errorf("Задача завершилась с ошибкой за %s: %v", j.lastDuration, err)
+		return
+	}
+	j.lastStatus = StatusSuccess
+	j.lastError = ""
+	j.lastResult = result
+	logger.Infof("Задача выполнена за %s: %s", j.lastDuration, result)
+}
+
+// runSafely превращает панику задачи в ошибку, чтобы она не останавливала процесс.
+func runSafely(ctx context.Context, fn JobFunc) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("паника: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
+
+// Statuses возвращает состояние задач в порядке регистрации.
+func (s *Scheduler) Statuses() api_models.JobsResponse {
+	s.mu.Lock()
+	jobs := append([]*job(nil), s.jobs...)
+	s.mu.Unlock()
+
+	resp := api_models.JobsResponse{Jobs: make([]api_models.JobStatusResponse, 0, len(jobs))}
+	for _, j := range jobs {
+		resp.Jobs = append(resp.Jobs, j.status())
+	}
+	return resp
+}
+
+func (j *job) status() api_models.JobStatusResponse {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	status := api_models.JobStatusResponse{
+		Name:         j.name,
+		Interval:     j.interval.String(),
+		Running:      j.running,
+		NextRunAt:    j.nextRunAt,
+		LastStatus:   j.lastStatus,
+		RunCount:     j.runCount,
+		FailureCount: j.failureCount,
+		SkippedCount: j.skippedCount,
+		LockedCount:  j.lockedCount,
+	}
+	if !j.lastStartedAt.IsZero() {
+		startedAt := j.lastStartedAt
+		status.LastStartedAt = &startedAt
+	}
+	// При running=true last_finished_at и last_status относятся к предыдущему запуску
+	if !j.lastFinishedAt.IsZero() {
+		finishedAt := j.lastFinishedAt
+		duration := j.lastDuration.Milliseconds()
+		status.LastFinishedAt = &finishedAt
+		status.LastDurationMs = &duration
+	}
+	if j.lastResult != "" {
+		result := j.lastResult
+		status.LastResult = &result
+	}
+	if j.lastError != "" {
+		lastErr := j.lastError
+		status.LastError = &lastErr
+	}
+	return status
+}
@@ -0,0 +1,194 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/joblock"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR SCHEDULER (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Unbounded growth — cleanup jobs must actually run periodically
+2. Pile-ups — a slow job must not start a second copy on the next tick
+3. Blind operations — the last run status and error are visible via the admin API
+4. Crash on bug — a panicking job is recorded as failed, the process keeps running
+5. Double runs on several API replicas — a job locked by another instance is skipped
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Register
+- GIVEN a duplicate name, a non-positive interval or an already started scheduler
+  THEN error
+
+SCENARIO 2: Run
+- GIVEN a registered job
+  WHEN Start is called
+  THEN it runs immediately and then every interval; status is success with the result
+
+- GIVEN a job still running when the next tick fires
+  THEN the tick is skipped and counted
+
+- GIVEN a job returning an error or panicking
+  THEN status is failed with the error, later runs continue
+
+SCENARIO 3: Several instances
+- GIVEN the job lock is held by another instance
+  THEN the job is not called, the run is counted in locked_count, status stays never
+- GIVEN the lock itself fails THEN the run is recorded as failed
+*/
+
+func TestRegister_Errors(t *testing.T) {
+	s := New(testutil.NewMockLogger(), joblock.Local{})
+	noop := func(context.Context) (string, error) { return "", nil }
+
+	require.NoError(t, s.Register("cleanup", time.Hour, noop))
+	assert.Error(t, s.Register("cleanup", time.Hour, noop), "duplicate name")
+	assert.Error(t, s.Register("other", 0, noop), "zero interval")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+	assert.Error(t, s.Register("late", time.Hour, noop), "after start")
+	cancel()
+	s.Wait()
+}
+
+func TestStatuses_NeverRun(t *testing.T) {
+	s := New(testutil.NewMockLogger(), joblock.Local{})
+	require.NoError(t, s.Register("cleanup", time.Hour, func(context.Context) (string, error) { return "", nil }))
+
+	jobs := s.Statuses().Jobs
+
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "cleanup", jobs[0].Name)
+	assert.Equal(t, "1h0m0s", jobs[0].Interval)
+	assert.Equal(t, StatusNever, jobs[0].LastStatus)
+	assert.Nil(t, jobs[0].LastStartedAt)
+}
+
+func TestStart_RunsImmediatelyAndPeriodically(t *testing.T) {
+	s := New(testutil.NewMockLogger(), joblock.Local{})
+	var runs atomic.Int64
+	require.NoError(t, s.Register("cleanup", 20*time.Millisecond, func(context.Context) (string, error) {
+		runs.Add(1)
+		return "удалено записей: 3", nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+	require.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, 5*time.Millisecond)
+	cancel()
+	s.Wait()
+
+	job := s.Statuses().Jobs[0]
+	assert.Equal(t, StatusSuccess, job.LastStatus)
+	assert.False(t, job.Running)
+	assert.Equal(t, runs.Load(), job.RunCount)
+	require.NotNil(t, job.LastResult)
+	assert.Equal(t, "удалено записей: 3", *job.LastResult)
+	assert.NotNil(t, job.LastFinishedAt)
+	assert.NotNil(t, job.LastDurationMs)
+	assert.Nil(t, job.LastError)
+}
+
+func TestStart_SkipsOverlappingRuns(t *testing.T) {
+	s := New(testutil.NewMockLogger(), joblock.Local{})
+	release := make(chan struct{})
+	var runs atomic.Int64
+	require.NoError(t, s.Register("slow", 10*time.Millisecond, func(ctx context.Context) (string, error) {
+		runs.Add(1)
+		<-release
+		return "ok", nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+	require.Eventually(t, func() bool { return s.Statuses().Jobs[0].SkippedCount >= 2 }, time.Second, 5*time.Millisecond)
+
+	job := s.Statuses().Jobs[0]
+	assert.True(t, job.Running)
+	assert.Equal(t, int64(1), runs.Load(), "only one copy runs at a time")
+
+	close(release)
+	cancel()
+	s.Wait()
+}
+
+func TestStart_FailuresAreRecorded(t *testing.T) {
+	cases := map[string]JobFunc{
+		"error": func(context.Context) (string, error) { return "", errors.New("connection reset") },
+		"panic": func(context.Context) (string, error) { panic("connection reset") },
+	}
+	for name, fn := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := New(testutil.NewMockLogger(), joblock.Local{})
+			require.NoError(t, s.Register("cleanup", time.Hour, fn))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			s.Start(ctx)
+			require.Eventually(t, func() bool { return s.Statuses().Jobs[0].RunCount == 1 }, time.Second, 5*time.Millisecond)
+			cancel()
+			s.Wait()
+
+			job := s.Statuses().Jobs[0]
+			assert.Equal(t, StatusFailed, job.LastStatus)
+			assert.Equal(t, int64(1), job.FailureCount)
+			require.NotNil(t, job.LastError)
+			assert.Contains(t, *job.LastError, "connection reset")
+			assert.Nil(t, job.LastResult)
+		})
+	}
+}
+
+// heldLocker — блокировку держит другой экземпляр (или её не удалось взять).
+type heldLocker struct{ err error }
+
+func (l heldLocker) TryRun(context.Context, string, func(context.Context) error) (bool, error) {
+	return false, l.err
+}
+
+func TestStart_LockedByAnotherInstance(t *testing.T) {
+	s := New(testutil.NewMockLogger(), heldLocker{})
+	require.NoError(t, s.Register("cleanup", 10*time.Millisecond, func(context.Context) (string, error) {
+		t.Error("the job must not run without the lock")
+		return "", nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+	require.Eventually(t, func() bool { return s.Statuses().Jobs[0].LockedCount >= 2 }, time.Second, 5*time.Millisecond)
+	cancel()
+	s.Wait()
+
+	job := s.Statuses().Jobs[0]
+	assert.False(t, job.Running)
+	assert.Equal(t, StatusNever, job.LastStatus)
+	assert.Zero(t, job.RunCount)
+}
+
+func TestStart_LockFailureIsRecorded(t *testing.T) {
+	s := New(testutil.NewMockLogger(), heldLocker{err: errors.New("too many connections")})
+	require.NoError(t, s.Register("cleanup", time.Hour, func(context.Context) (string, error) { return "", nil }))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+	require.Eventually(t, func() bool { return s.Statuses().Jobs[0].RunCount == 1 }, time.Second, 5*time.Millisecond)
+	cancel()
+	s.Wait()
+
+	job := s.Statuses().Jobs[0]
+	assert.Equal(t, StatusFailed, job.LastStatus)
+	require.NotNil(t, job.LastError)
+	assert.Contains(t, *job.LastError, "too many connections")
+}
@@ -0,0 +1,232 @@
+// Package search реализует глобальный поиск (GET /api/v1/search): одна строка
+// поиска ищется сразу по тендерам, подрядчикам, объектам и позициям каталога,
+// результат сгруппирован по типу сущности.
+//
+// Каждая группа — отдельный запрос (search.sql), запросы выполняются
+// параллельно и возвращают первые limit совпадений и их общее число. Тендеры и
+// объекты ограничены организацией пользователя; подрядчики и каталог — общие
+// справочники.
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+	"golang.org/x/sync/errgroup"
+)
+
+// Типы сущностей глобального поиска (SearchHit.Type, параметр types).
+const (
+	TypeTender          = "tender"
+	TypeContractor      = "contractor"
+	TypeObject          = "object"
+	TypeCatalogPosition = "catalog_position"
+)
+
+// AllTypes — все группы в порядке ответа.
+var AllTypes = []string{TypeTender, TypeContractor, TypeObject, TypeCatalogPosition}
+
+const (
+	// MinQueryLength — короче запрос совпадает почти со всем.
+	MinQueryLength = 2
+	// MaxQueryLength — верхняя граница длины запроса в символах.
+	MaxQueryLength = 200
+	// DefaultLimit — совпадений в группе по умолчанию.
+	DefaultLimit = 5
+	// MaxLimit — верхняя граница параметра limit.
+	MaxLimit = 20
+)
+
+// likeEscaper экранирует спецсимволы LIKE: запрос ищется как подстрока буквально.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Query — параметры глобального поиска.
+type Query struct {
+	Text           string
+	Types          []string      // Пусто — все группы
+	Limit          int32         // Совпадений в каждой группе
+	OrganizationID sql.NullInt64 // Valid=false — все организации
+}
+
+// SearchService ищет сущности по одной строке поиска.
+type SearchService struct {
+	store  db.Store
+	logger logging.Logger
+}
+
+// NewSearchService создаёт новый экземпляр SearchService.
+func NewSearchService(store db.Store, logger logging.Logger) *SearchService {
+	return &SearchService{
+		store:  store,
+		logger: logger.WithField("service", "search"),
+	}
+}
+
+// Search ищет строку во всех запрошенных группах. Группа без совпадений
+// возвращается с пустым списком, чтобы фронтенд показал «ничего не найдено».
+func (s *SearchService) Search(ctx context.Context, query Query) (*api_models.SearchResponse, error) {
+	text := strings.TrimSpace(query.Text)
+	if length := utf8.RuneCountInString(text); length < MinQueryLength || length > MaxQueryLength {
+		return nil, apierrors.NewValidationError("request.query_length", MinQueryLength, MaxQueryLength)
+	}
+	if query.Limit < 1 || query.Limit > MaxLimit {
+		return nil, apierrors.NewValidationError("search.limit_range", MaxLimit, query.Limit)
+	}
+	types, err := normalizeTypes(query.Types)
+	if err != nil {
+		return nil, err
+	}
+
+	escaped := likeEscaper.Replace(text)
+	pattern, prefix := "%"+escaped+"%", escaped+"%"
+
+	groups := make([]api_models.SearchGroup, len(types))
+	g, gctx := errgroup.WithContext(ctx)
+	for i, typ := range types {
+		g.Go(func() error {
+			group, err := s.searchGroup(gctx, typ, pattern, prefix, query)
+			if err != nil {
+				return fmt.Errorf("ошибка поиска (%s): %w", typ, err)
+			}
+			groups[i] = group
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return &api_models.SearchResponse{Query: text, Limit: query.Limit, Groups: groups}, nil
+}
+
+// normalizeTypes проверяет types и раскладывает их в порядке AllTypes без повторов.
+func normalizeTypes(requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return AllTypes, nil
+	}
+	wanted := make(map[string]bool, len(requested))
+	for _, typ := range requested {
+		typ = strings.TrimSpace(typ)
+		if typ == "" {
+			continue
+		}
+		known := false
+		for _, t := range AllTypes {
+			known = known || t == typ
+		}
+		if !known {
+			return nil, apierrors.NewValidationError("search.unknown_type", typ, strings.Join(AllTypes, ", "))
+		}
+		wanted[typ] = true
+	}
+	if len(wanted) == 0 {
+		return AllTypes, nil
+	}
+	types := make([]string, 0, len(wanted))
+	for _, t := range AllTypes {
+		if wanted[t] {
+			types = append(types, t)
+		}
+	}
+	return types, nil
+}
+
+func (s *SearchService) searchGroup(ctx context.Context, typ, pattern, prefix string, query Query) (api_models.SearchGroup, error) {
+	group := api_models.SearchGroup{Type: typ, Items: make([]api_models.SearchHit, 0)}
+
+	switch typ {
+	case TypeTender:
+		rows, err := s.store.GlobalSearchTenders(ctx, db.GlobalSearchTendersParams{
+			Pattern:        pattern,
+			Prefix:         prefix,
+			OrganizationID: query.OrganizationID,
+			PageLimit:      query.Limit,
+		})
+		if err != nil {
+			return group, err
+		}
+		for _, row := range rows {
+			date := row.TenderDate
+			group.Total = row.Total
+			group.Items = append(group.Items, api_models.SearchHit{
+				Type:     typ,
+				ID:       row.ID,
+				Title:    row.Title,
+				Subtitle: row.EtpID + " · " + row.ObjectTitle,
+				Date:     &date,
+				Link:     fmt.Sprintf("/api/v1/tenders/%d", row.ID),
+			})
+		}
+
+	case TypeContractor:
+		rows, err := s.store.GlobalSearchContractors(ctx, db.GlobalSearchContractorsParams{
+			Pattern:   pattern,
+			Prefix:    prefix,
+			PageLimit: query.Limit,
+		})
+		if err != nil {
+			return group, err
+		}
+		for _, row := range rows {
+			group.Total = row.Total
+			group.Items = append(group.Items, api_models.SearchHit{
+				Type:     typ,
+				ID:       row.ID,
+				Title:    row.Title,
+				Subtitle: "ИНН " + row.Inn,
+				Link:     fmt.Sprintf("/api/v1/contractors/%d", row.ID),
+			})
+		}
+
+	case TypeObject:
+		rows, err := s.store.GlobalSearchObjects(ctx, db.GlobalSearchObjectsParams{
+			Pattern:        pattern,
+			Prefix:         prefix,
+			OrganizationID: query.OrganizationID,
+			PageLimit:      query.Limit,
+		})
+		if err != nil {
+			return group, err
+		}
+		for _, row := range rows {
+			group.Total = row.Total
+			group.Items = append(group.Items, api_models.SearchHit{
+				Type:     typ,
+				ID:       row.ID,
+				Title:    row.Title,
+				Subtitle: row.Address,
+				// Карточки объекта нет: ссылка ведёт на его тендеры
+				Link: fmt.Sprintf("/api/v1/tenders?object_id=%d", row.ID),
+			})
+		}
+
+	case TypeCatalogPosition:
+		rows, err := s.store.GlobalSearchCatalogPositions(ctx, db.GlobalSearchCatalogPositionsParams{
+			Pattern:   pattern,
+			Prefix:    prefix,
+			PageLimit: query.Limit,
+		})
+		if err != nil {
+			return group, err
+		}
+		for _, row := range rows {
+			group.Total = row.Total
+			group.Items = append(group.Items, api_models.SearchHit{
+				Type:     typ,
+				ID:       row.ID,
+				Title:    row.StandardJobTitle,
+				Subtitle: row.Description.String,
+				Link:     fmt.Sprintf("/api/v1/catalog/%d/price-history", row.ID),
+			})
+		}
+	}
+
+	return group, nil
+}
@@ -0,0 +1,168 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR GLOBAL SEARCH (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. One search box, many places — a single query must reach every entity group
+2. Leaking other organizations — tenders and objects are searched in the user's scope
+3. Wildcards — "%" and "_" typed by the user are searched literally
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: All groups
+- GIVEN a query with spaces around it and no types
+  WHEN Search is called
+  THEN all four groups are returned in fixed order with totals, links and
+       the organization scope passed to tenders and objects
+
+SCENARIO 2: Selected types
+- GIVEN types=catalog_position,tender (any order, duplicates)
+  WHEN Search is called
+  THEN only those groups are queried, in canonical order
+
+SCENARIO 3: Escaping
+- GIVEN a query with LIKE wildcards
+  WHEN Search is called
+  THEN pattern and prefix contain them escaped
+
+SCENARIO 4: Validation and errors
+- GIVEN a too short query, limit out of range or an unknown type → ValidationError, no DB calls
+- GIVEN a DB error in one group → wrapped error
+*/
+
+func setupTestService(t *testing.T) (*SearchService, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	return NewSearchService(mockStore, testutil.NewMockLogger()), mockStore
+}
+
+func TestSearch_AllGroups(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	org := sql.NullInt64{Int64: 3, Valid: true}
+	date := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	mockStore.EXPECT().GlobalSearchTenders(gomock.Any(), db.GlobalSearchTendersParams{
+		Pattern: "%бетон%", Prefix: "бетон%", OrganizationID: org, PageLimit: 5,
+	}).Return([]db.GlobalSearchTendersRow{
+		{ID: 10, EtpID: "0373", Title: "Бетонные работы", ObjectTitle: "ЖК Север", TenderDate: date, Total: 7},
+	}, nil)
+	mockStore.EXPECT().GlobalSearchContractors(gomock.Any(), db.GlobalSearchContractorsParams{
+		Pattern: "%бетон%", Prefix: "бетон%", PageLimit: 5,
+	}).Return([]db.GlobalSearchContractorsRow{
+		{ID: 20, Title: "ООО Бетон", Inn: "7700000001", Total: 1},
+	}, nil)
+	mockStore.EXPECT().GlobalSearchObjects(gomock.Any(), db.GlobalSearchObjectsParams{
+		Pattern: "%бетон%", Prefix: "бетон%", OrganizationID: org, PageLimit: 5,
+	}).Return([]db.GlobalSearchObjectsRow{}, nil)
+	mockStore.EXPECT().GlobalSearchCatalogPositions(gomock.Any(), db.GlobalSearchCatalogPositionsParams{
+		Pattern: "%бетон%", Prefix: "бетон%", PageLimit: 5,
+	}).Return([]db.GlobalSearchCatalogPositionsRow{
+		{ID: 30, StandardJobTitle: "бетон в25", Total: 12},
+	}, nil)
+
+	resp, err := service.Search(context.Background(), Query{Text: "  бетон ", Limit: 5, OrganizationID: org})
+
+	require.NoError(t, err)
+	assert.Equal(t, "бетон", resp.Query)
+	require.Len(t, resp.Groups, 4)
+	for i, typ := range AllTypes {
+		assert.Equal(t, typ, resp.Groups[i].Type)
+	}
+
+	tenders := resp.Groups[0]
+	assert.Equal(t, int64(7), tenders.Total)
+	require.Len(t, tenders.Items, 1)
+	assert.Equal(t, "/api/v1/tenders/10", tenders.Items[0].Link)
+	assert.Equal(t, "0373 · ЖК Север", tenders.Items[0].Subtitle)
+	assert.Equal(t, date, *tenders.Items[0].Date)
+
+	assert.Equal(t, "/api/v1/contractors/20", resp.Groups[1].Items[0].Link)
+	assert.Equal(t, "ИНН 7700000001", resp.Groups[1].Items[0].Subtitle)
+
+	assert.Equal(t, int64(0), resp.Groups[2].Total)
+	assert.NotNil(t, resp.Groups[2].Items)
+	assert.Empty(t, resp.Groups[2].Items)
+
+	assert.Equal(t, int64(12), resp.Groups[3].Total)
+	assert.Equal(t, "/api/v1/catalog/30/price-history", resp.Groups[3].Items[0].Link)
+}
+
+func TestSearch_SelectedTypes(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GlobalSearchTenders(gomock.Any(), gomock.Any()).Return([]db.GlobalSearchTendersRow{}, nil)
+	mockStore.EXPECT().GlobalSearchCatalogPositions(gomock.Any(), gomock.Any()).Return([]db.GlobalSearchCatalogPositionsRow{}, nil)
+
+	resp, err := service.Search(context.Background(), Query{
+		Text:  "кирпич",
+		Types: []string{"catalog_position", " tender", "catalog_position"},
+		Limit: 3,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Groups, 2)
+	assert.Equal(t, TypeTender, resp.Groups[0].Type)
+	assert.Equal(t, TypeCatalogPosition, resp.Groups[1].Type)
+}
+
+func TestSearch_EscapesLikeWildcards(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GlobalSearchContractors(gomock.Any(), db.GlobalSearchContractorsParams{
+		Pattern: `%100\%\_ok%`, Prefix: `100\%\_ok%`, PageLimit: 5,
+	}).Return([]db.GlobalSearchContractorsRow{}, nil)
+
+	_, err := service.Search(context.Background(), Query{Text: "100%_ok", Types: []string{TypeContractor}, Limit: 5})
+
+	require.NoError(t, err)
+}
+
+func TestSearch_Validation(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	cases := map[string]Query{
+		"too short":    {Text: " б ", Limit: 5},
+		"zero limit":   {Text: "бетон", Limit: 0},
+		"limit > max":  {Text: "бетон", Limit: MaxLimit + 1},
+		"unknown type": {Text: "бетон", Types: []string{"lot"}, Limit: 5},
+	}
+	for name, query := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.Search(context.Background(), query)
+
+			var validationErr *apierrors.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+		})
+	}
+}
+
+func TestSearch_GroupError(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	dbErr := errors.New("connection reset")
+
+	mockStore.EXPECT().GlobalSearchObjects(gomock.Any(), gomock.Any()).Return(nil, dbErr)
+
+	_, err := service.Search(context.Background(), Query{Text: "север", Types: []string{TypeObject}, Limit: 5})
+
+	assert.ErrorIs(t, err, dbErr)
+}
@@ -0,0 +1,19 @@
+package secrets
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+// envProvider читает секреты из переменных окружения с именем секрета в
+// верхнем регистре: JWT_SECRET, JWT_SECRET_PREVIOUS, DB_SOURCE.
+type envProvider struct{}
+
+func (envProvider) Get(_ context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(strings.ToUpper(name))
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
@@ -0,0 +1,27 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileProvider читает секрет из файла <dir>/<имя секрета>. Завершающий перевод
+// строки отбрасывается: его оставляют echo и большинство редакторов.
+type fileProvider struct {
+	dir string
+}
+
+func (p fileProvider) Get(_ context.Context, name string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(p.dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
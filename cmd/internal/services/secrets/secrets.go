@@ -0,0 +1,81 @@
+// Package secrets получает секреты приложения — секрет подписи JWT (текущий и
+// предыдущий), DSN базы и секрет клиента OIDC — из выбранного хранилища:
+//
+//   - env   — переменные окружения и YAML (по умолчанию, поведение до провайдеров);
+//   - file  — файлы в каталоге secrets.file.dir, по одному на секрет
+//     (Docker/Kubernetes secrets): jwt_secret, jwt_secret_previous, db_source,
+//     oidc_client_secret;
+//   - vault — один секрет HashiCorp Vault KV v2 с ключами с теми же именами.
+//
+// Секреты читаются при старте (Apply) и повторно по SIGHUP: так ротируется
+// секрет JWT без перезапуска. Новый DSN применяется только после перезапуска —
+// пул соединений создаётся один раз.
+//
+// Ротация JWT: новый секрет становится jwt_secret, старый — jwt_secret_previous.
+// Токены, подписанные старым, принимаются, пока не истекут (access_ttl), после
+// чего jwt_secret_previous можно убрать.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+)
+
+// Имена секретов в хранилище.
+const (
+	JWTSecret         = "jwt_secret"
+	JWTSecretPrevious = "jwt_secret_previous"
+	DBSource          = "db_source"
+	OIDCClientSecret  = "oidc_client_secret"
+)
+
+// ErrNotFound — секрета нет в хранилище.
+var ErrNotFound = errors.New("секрет не найден")
+
+// Provider — хранилище секретов. Реализации безопасны для параллельного использования.
+type Provider interface {
+	// Get возвращает значение секрета или ErrNotFound.
+	Get(ctx context.Context, name string) (string, error)
+}
+
+// New создаёт провайдер по секции secrets конфигурации.
+func New(cfg config.SecretsConfig) (Provider, error) {
+	switch cfg.Provider {
+	case config.SecretsProviderEnv, "":
+		return envProvider{}, nil
+	case config.SecretsProviderFile:
+		return fileProvider{dir: cfg.File.Dir}, nil
+	case config.SecretsProviderVault:
+		return newVaultProvider(cfg.Vault), nil
+	default:
+		return nil, fmt.Errorf("неизвестный провайдер секретов %q", cfg.Provider)
+	}
+}
+
+// Apply заполняет секреты конфигурации из провайдера и проверяет их.
+// Секрет, которого нет в хранилище, сохраняет значение из YAML/окружения.
+func Apply(ctx context.Context, provider Provider, cfg *config.Config) error {
+	targets := []struct {
+		name  string
+		value *string
+	}{
+		{JWTSecret, &cfg.Auth.JWTSecret},
+		{JWTSecretPrevious, &cfg.Auth.JWTSecretPrevious},
+		{DBSource, &cfg.Database.Source},
+		{OIDCClientSecret, &cfg.Auth.OIDC.ClientSecret},
+	}
+	for _, t := range targets {
+		value, err := provider.Get(ctx, t.name)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("ошибка получения секрета %s: %w", t.name, err)
+		}
+		*t.value = value
+	}
+	return cfg.ValidateSecrets()
+}
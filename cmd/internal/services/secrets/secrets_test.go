@@ -0,0 +1,177 @@
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR SECRETS PROVIDERS (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. JWT secret and DB password kept in plain config files or process environment
+2. Rotating the JWT secret logging out every user at once
+3. A half-configured store silently starting the API with empty secrets
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: file provider
+- GIVEN files jwt_secret and db_source with a trailing newline
+  WHEN Apply is called
+  THEN the config holds the values without the newline
+  AND jwt_secret_previous (no file) keeps its YAML/env value
+
+SCENARIO 2: Vault KV v2
+- GIVEN a Vault secret with jwt_secret, jwt_secret_previous and db_source
+  WHEN Apply is called
+  THEN all three are set AND the request carries X-Vault-Token
+- GIVEN Vault answers 403
+  THEN Apply fails naming the secret
+- GIVEN the secret path does not exist (404)
+  THEN ErrNotFound and the existing values are kept
+
+SCENARIO 3: validation after fetching
+- GIVEN a provider without jwt_secret and an empty config
+  WHEN Apply is called
+  THEN *config.ValidationError
+
+SCENARIO 4: env provider reads upper-case variable names
+*/
+
+const (
+	currentSecret  = "current-secret-0123456789abcdef01"
+	previousSecret = "previous-secret-0123456789abcdef0"
+	dsn            = "postgres://app:vault-pass@db:5432/tenders"
+)
+
+func TestApply_FileProvider(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, JWTSecret), []byte(currentSecret+"\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, DBSource), []byte(dsn+"\r\n"), 0o600))
+
+	provider, err := New(config.SecretsConfig{
+		Provider: config.SecretsProviderFile,
+		File:     config.FileSecretsConfig{Dir: dir},
+	})
+	require.NoError(t, err)
+
+	cfg := &config.Config{}
+	cfg.Auth.JWTSecretPrevious = previousSecret
+	require.NoError(t, Apply(context.Background(), provider, cfg))
+
+	assert.Equal(t, currentSecret, cfg.Auth.JWTSecret)
+	assert.Equal(t, previousSecret, cfg.Auth.JWTSecretPrevious)
+	assert.Equal(t, dsn, cfg.Database.Source)
+}
+
+func newVaultServer(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/secret/data/tenders-go", r.URL.Path)
+		assert.Equal(t, "hvs.test", r.Header.Get("X-Vault-Token"))
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func vaultConfig(address string) config.SecretsConfig {
+	return config.SecretsConfig{
+		Provider: config.SecretsProviderVault,
+		Vault: config.VaultSecretsConfig{
+			Address: address,
+			Token:   "hvs.test",
+			Mount:   "secret",
+			Path:    "tenders-go",
+			Timeout: time.Second,
+		},
+	}
+}
+
+func TestApply_Vault(t *testing.T) {
+	srv := newVaultServer(t, http.StatusOK, `{"data":{"data":{
+		"jwt_secret":"`+currentSecret+`",
+		"jwt_secret_previous":"`+previousSecret+`",
+		"db_source":"`+dsn+`"},"metadata":{"version":3}}}`)
+
+	provider, err := New(vaultConfig(srv.URL))
+	require.NoError(t, err)
+
+	cfg := &config.Config{}
+	require.NoError(t, Apply(context.Background(), provider, cfg))
+
+	assert.Equal(t, currentSecret, cfg.Auth.JWTSecret)
+	assert.Equal(t, previousSecret, cfg.Auth.JWTSecretPrevious)
+	assert.Equal(t, dsn, cfg.Database.Source)
+}
+
+func TestApply_VaultForbidden(t *testing.T) {
+	srv := newVaultServer(t, http.StatusForbidden, `{"errors":["permission denied"]}`)
+
+	provider, err := New(vaultConfig(srv.URL))
+	require.NoError(t, err)
+
+	err = Apply(context.Background(), provider, &config.Config{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), JWTSecret)
+	assert.Contains(t, err.Error(), "403")
+}
+
+func TestVaultProvider_NotFound(t *testing.T) {
+	srv := newVaultServer(t, http.StatusNotFound, `{"errors":[]}`)
+
+	provider, err := New(vaultConfig(srv.URL))
+	require.NoError(t, err)
+
+	_, err = provider.Get(context.Background(), JWTSecret)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	cfg := &config.Config{}
+	cfg.Auth.JWTSecret = currentSecret
+	cfg.Database.Source = dsn
+	require.NoError(t, Apply(context.Background(), provider, cfg))
+	assert.Equal(t, currentSecret, cfg.Auth.JWTSecret)
+}
+
+func TestApply_ValidatesSecrets(t *testing.T) {
+	provider, err := New(config.SecretsConfig{
+		Provider: config.SecretsProviderFile,
+		File:     config.FileSecretsConfig{Dir: t.TempDir()},
+	})
+	require.NoError(t, err)
+
+	err = Apply(context.Background(), provider, &config.Config{})
+
+	var validationErr *config.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Len(t, validationErr.Errors, 2)
+}
+
+func TestEnvProvider(t *testing.T) {
+	t.Setenv("JWT_SECRET_PREVIOUS", previousSecret)
+	os.Unsetenv("DB_SOURCE")
+
+	provider, err := New(config.SecretsConfig{Provider: config.SecretsProviderEnv})
+	require.NoError(t, err)
+
+	value, err := provider.Get(context.Background(), JWTSecretPrevious)
+	require.NoError(t, err)
+	assert.Equal(t, previousSecret, value)
+
+	_, err = provider.Get(context.Background(), DBSource)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,75 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+)
+
+// vaultProvider читает ключи одного секрета Vault KV v2:
+// GET <address>/v1/<mount>/data/<path>. Секрет запрашивается при каждом Get —
+// чтения редки (старт и SIGHUP), а кэш помешал бы ротации.
+type vaultProvider struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func newVaultProvider(cfg config.VaultSecretsConfig) *vaultProvider {
+	return &vaultProvider{
+		url: strings.TrimRight(cfg.Address, "/") + "/v1/" +
+			url.PathEscape(cfg.Mount) + "/data/" + strings.Trim(cfg.Path, "/"),
+		token:  cfg.Token,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// vaultKVResponse — ответ KV v2: значения лежат в data.data.
+type vaultKVResponse struct {
+	Data struct {
+		Data map[string]any `json:"data"`
+	} `json:"data"`
+}
+
+func (p *vaultProvider) Get(ctx context.Context, name string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("запрос к Vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		// Тело ответа Vault об ошибке не содержит секретов, но может быть длинным
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("vault вернул статус %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var kv vaultKVResponse
+	if err := json.NewDecoder(resp.Body).Decode(&kv); err != nil {
+		return "", fmt.Errorf("некорректный ответ Vault: %w", err)
+	}
+	raw, ok := kv.Data.Data[name]
+	if !ok || raw == nil {
+		return "", ErrNotFound
+	}
+	value, ok := raw.(string)
+	if !ok {
+		return "", fmt.Errorf("ключ %s секрета Vault должен быть строкой", name)
+	}
+	return value, nil
+}
@@ -0,0 +1,63 @@
+package servicecreds
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+// Scope — группа роутов /internal/worker, доступ к которой дает ключ.
+type Scope string
+
+const (
+	// Импорт тендеров парсером
+	ScopeImport Scope = "import"
+	// Matching позиций, индексация каталога, merge-предложения
+	ScopeRAG Scope = "rag"
+	// Результаты AI-анализа лотов
+	ScopeAIResults Scope = "ai-results"
+)
+
+// allScopes — набор scopes совпадает с CHECK chk_service_credentials_scopes (миграция 000021).
+var allScopes = []Scope{ScopeAIResults, ScopeImport, ScopeRAG}
+
+// normalizeScopes проверяет scopes из запроса и возвращает их без повторов в алфавитном порядке.
+func normalizeScopes(raw []string) ([]string, error) {
+	if len(raw) == 0 {
+		return nil, apierrors.NewValidationError("servicecreds.scopes_empty", scopesList())
+	}
+
+	seen := make(map[string]struct{}, len(raw))
+	scopes := make([]string, 0, len(raw))
+	for _, s := range raw {
+		s = strings.TrimSpace(s)
+		if !isKnownScope(s) {
+			return nil, apierrors.NewValidationError("servicecreds.invalid_scope", s, scopesList())
+		}
+		if _, ok := seen[s]; ok {
+			continue
+		}
+		seen[s] = struct{}{}
+		scopes = append(scopes, s)
+	}
+	sort.Strings(scopes)
+	return scopes, nil
+}
+
+func isKnownScope(s string) bool {
+	for _, scope := range allScopes {
+		if string(scope) == s {
+			return true
+		}
+	}
+	return false
+}
+
+func scopesList() string {
+	names := make([]string, len(allScopes))
+	for i, scope := range allScopes {
+		names[i] = string(scope)
+	}
+	return strings.Join(names, ", ")
+}
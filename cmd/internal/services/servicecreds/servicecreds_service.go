@@ -0,0 +1,428 @@
+// Package servicecreds предоставляет хранилище Bearer-ключей внутренних сервисов.
+//
+// Ключи хранятся в таблице service_credentials (только SHA-256 хеш), а для
+// проверки запросов используется in-memory кэш, который периодически
+// перечитывается из БД. Благодаря этому ротация ключа python-worker
+// (создать новый → переключить воркер → отозвать старый) не требует
+// перезапуска API.
+//
+// # Воркеры и scopes
+//
+// service_name ключа — имя воркера, которое попадает в контекст запроса и логи;
+// scopes ограничивают группы роутов /internal/worker (см. Scope). Разные воркеры
+// получают разные ключи и отзываются независимо. Последнее использование ключа
+// копится в памяти и записывается в last_used_at при периодическом обновлении.
+//
+// # Обратная совместимость
+//
+// Ключ из переменной окружения GO_SERVER_API_KEY (если задан) продолжает
+// приниматься как статический ключ сервиса python-worker со всеми scopes. Он не
+// хранится в БД и не может быть отозван через API — после переноса ключей в БД
+// переменную следует удалить.
+//
+// # Согласованность между инстансами
+//
+// Изменения через API сразу применяются к кэшу текущего инстанса (Refresh).
+// Остальные инстансы подхватывают их при следующем периодическом обновлении.
+package servicecreds
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// keyPrefixLen — сколько первых символов ключа сохраняется в открытом виде для идентификации.
+const keyPrefixLen = 8
+
+// Identity — воркер, предъявивший ключ.
+type Identity struct {
+	CredentialID int64 // 0 для статического ключа из окружения
+	ServiceName  string
+	Scopes       []string
+}
+
+// HasScope сообщает, открывает ли ключ доступ к группе роутов.
+func (i Identity) HasScope(scope Scope) bool {
+	return slices.Contains(i.Scopes, string(scope))
+}
+
+func identitiesEqual(a, b Identity) bool {
+	return a.CredentialID == b.CredentialID && a.ServiceName == b.ServiceName && slices.Equal(a.Scopes, b.Scopes)
+}
+
+// Service управляет ключами сервисов и их in-memory кэшем.
+type Service struct {
+	store  db.Store
+	logger logging.Logger
+
+	// staticKeys — ключи из окружения: hex(sha256(key)) → воркер со всеми scopes.
+	staticKeys map[string]Identity
+
+	mu sync.RWMutex
+	// keys — активные ключи из БД: hex(sha256(key)) → воркер.
+	keys map[string]Identity
+
+	usedMu sync.Mutex
+	// used — id ключей из БД, использованных после последней записи last_used_at.
+	used map[int64]struct{}
+}
+
+// NewService создаёт сервис ключей.
+// staticKeys — открытые ключи из окружения (service_name → key), пустые значения игнорируются.
+func NewService(store db.Store, logger logging.Logger, staticKeys map[string]string) *Service {
+	scopes := make([]string, len(allScopes))
+	for i, scope := range allScopes {
+		scopes[i] = string(scope)
+	}
+
+	hashed := make(map[string]Identity, len(staticKeys))
+	for serviceName, key := range staticKeys {
+		if key == "" {
+			continue
+		}
+		hashed[hashKey(key)] = Identity{ServiceName: serviceName, Scopes: scopes}
+	}
+	return &Service{
+		store:      store,
+		logger:     logger,
+		staticKeys: hashed,
+		keys:       map[string]Identity{},
+		used:       map[int64]struct{}{},
+	}
+}
+
+// hashKey возвращает hex(sha256(key)) — формат колонки key_hash.
+func hashKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// Authenticate проверяет Bearer-токен и возвращает воркера, которому выдан ключ.
+// Поиск идёт по хешу токена, поэтому время ответа не зависит от совпадения префикса.
+// Scopes проверяет вызывающий (Identity.HasScope).
+func (s *Service) Authenticate(token string) (Identity, bool) {
+	if token == "" {
+		return Identity{}, false
+	}
+	h := hashKey(token)
+
+	if identity, ok := s.staticKeys[h]; ok {
+		return identity, true
+	}
+
+	s.mu.RLock()
+	identity, ok := s.keys[h]
+	s.mu.RUnlock()
+	if !ok {
+		return Identity{}, false
+	}
+
+	s.usedMu.Lock()
+	s.used[identity.CredentialID] = struct{}{}
+	s.usedMu.Unlock()
+	return identity, true
+}
+
+// HasCredentials сообщает, есть ли хотя бы один действующий ключ (статический или из БД).
+// Используется при старте, чтобы не поднимать сервер с закрытым наглухо /internal/worker.
+func (s *Service) HasCredentials() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.staticKeys) > 0 || len(s.keys) > 0
+}
+
+// Refresh перечитывает активные ключи из БД и атомарно подменяет кэш.
+// При ошибке БД старый кэш сохраняется, чтобы кратковременный сбой не отрезал воркеров.
+func (s *Service) Refresh(ctx context.Context) error {
+	rows, err := s.store.ListActiveServiceCredentials(ctx)
+	if err != nil {
+		return fmt.Errorf("ошибка ListActiveServiceCredentials: %w", err)
+	}
+
+	keys := make(map[string]Identity, len(rows))
+	for _, row := range rows {
+		keys[row.KeyHash] = Identity{CredentialID: row.ID, ServiceName: row.ServiceName, Scopes: row.Scopes}
+	}
+
+	s.mu.Lock()
+	changed := !maps.EqualFunc(keys, s.keys, identitiesEqual)
+	s.keys = keys
+	s.mu.Unlock()
+
+	if changed {
+		s.logger.Infof("Кэш ключей сервисов обновлён: %d активных ключей", len(keys))
+	}
+	return nil
+}
+
+// FlushUsage записывает last_used_at ключей, использованных с прошлого вызова.
+// При ошибке БД id возвращаются в очередь и будут записаны при следующем вызове.
+func (s *Service) FlushUsage(ctx context.Context) error {
+	s.usedMu.Lock()
+	used := s.used
+	s.used = map[int64]struct{}{}
+	s.usedMu.Unlock()
+
+	if len(used) == 0 {
+		return nil
+	}
+
+	ids := slices.Sorted(maps.Keys(used))
+	if err := s.store.TouchServiceCredentials(ctx, db.TouchServiceCredentialsParams{
+		UsedAt: time.Now(),
+		Ids:    ids,
+	}); err != nil {
+		s.usedMu.Lock()
+		for _, id := range ids {
+			s.used[id] = struct{}{}
+		}
+		s.usedMu.Unlock()
+		return fmt.Errorf("ошибка TouchServiceCredentials: %w", err)
+	}
+	return nil
+}
+
+// Run периодически обновляет кэш и last_used_at до отмены ctx.
+// Предназначен для запуска в отдельной горутине.
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	logger := s.logger.WithField("method", "Run")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil {
+				logger.Errorf("Не удалось обновить кэш ключей сервисов (используется предыдущий): %v", err)
+			}
+			if err := s.FlushUsage(ctx); err != nil {
+				logger.Warnf("Не удалось записать last_used_at ключей сервисов: %v", err)
+			}
+		}
+	}
+}
+
+// CreateCredential генерирует новый ключ для сервиса и сохраняет его хеш.
+// Открытый ключ возвращается только в этом ответе.
+func (s *Service) CreateCredential(
+	ctx context.Context,
+	req api_models.CreateServiceCredentialRequest,
+	createdBy string,
+) (*api_models.CreateServiceCredentialResponse, error) {
+	logger := s.logger.WithField("method", "CreateCredential")
+
+	serviceName := strings.TrimSpace(req.ServiceName)
+	if serviceName == "" {
+		return nil, apierrors.NewValidationError("servicecreds.service_name_empty")
+	}
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := generateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	description := sql.NullString{}
+	if d := strings.TrimSpace(req.Description); d != "" {
+		description = sql.NullString{String: d, Valid: true}
+	}
+
+	row, err := s.store.CreateServiceCredential(ctx, db.CreateServiceCredentialParams{
+		ServiceName: serviceName,
+		KeyHash:     hashKey(key),
+		KeyPrefix:   key[:keyPrefixLen],
+		Description: description,
+		CreatedBy:   createdBy,
+		Scopes:      scopes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка CreateServiceCredential: %w", err)
+	}
+
+	if err := s.Refresh(ctx); err != nil {
+		// Ключ уже сохранён — остальные инстансы подхватят его по таймеру.
+		logger.Warnf("Ключ создан, но кэш не обновлён: %v", err)
+	}
+
+	logger.Infof("Создан ключ %s… для сервиса %s, scopes %v (оператор: %s)", row.KeyPrefix, serviceName, scopes, createdBy)
+	return &api_models.CreateServiceCredentialResponse{
+		ServiceCredentialResponse: credentialToResponse(
+			row.ID, row.ServiceName, row.KeyPrefix, row.Description, row.CreatedBy, row.CreatedAt, row.RevokedAt,
+			row.Scopes, row.LastUsedAt, row.RotatedFromID,
+		),
+		Key: key,
+	}, nil
+}
+
+// RotateCredential выпускает ключ-преемник с тем же service_name, описанием и
+// scopes и в той же транзакции отзывает старый. Старый ключ перестаёт
+// приниматься сразу, поэтому воркер нужно переключить на новый ключ; для
+// ротации без простоя — CreateCredential, переключение, RevokeCredential.
+func (s *Service) RotateCredential(ctx context.Context, id int64, rotatedBy string) (*api_models.CreateServiceCredentialResponse, error) {
+	logger := s.logger.WithField("method", "RotateCredential").WithField("credential_id", id)
+
+	if id <= 0 {
+		return nil, apierrors.NewValidationError("request.id_positive_got", id)
+	}
+
+	key, err := generateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	var row db.CreateServiceCredentialRow
+	err = s.store.ExecTx(ctx, func(q *db.Queries) error {
+		old, err := q.GetActiveServiceCredentialForUpdate(ctx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return apierrors.NewNotFoundError("servicecreds.key_not_found", id)
+			}
+			return fmt.Errorf("ошибка GetActiveServiceCredentialForUpdate(%d): %w", id, err)
+		}
+
+		if _, err := q.RevokeServiceCredential(ctx, id); err != nil {
+			return fmt.Errorf("ошибка RevokeServiceCredential(%d): %w", id, err)
+		}
+
+		row, err = q.CreateServiceCredential(ctx, db.CreateServiceCredentialParams{
+			ServiceName:   old.ServiceName,
+			KeyHash:       hashKey(key),
+			KeyPrefix:     key[:keyPrefixLen],
+			Description:   old.Description,
+			CreatedBy:     rotatedBy,
+			Scopes:        old.Scopes,
+			RotatedFromID: sql.NullInt64{Int64: id, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка CreateServiceCredential: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Refresh(ctx); err != nil {
+		logger.Warnf("Ключ ротирован, но кэш не обновлён: %v", err)
+	}
+
+	logger.Infof("Ключ id=%d сервиса %s ротирован, новый ключ %s… (оператор: %s)", id, row.ServiceName, row.KeyPrefix, rotatedBy)
+	return &api_models.CreateServiceCredentialResponse{
+		ServiceCredentialResponse: credentialToResponse(
+			row.ID, row.ServiceName, row.KeyPrefix, row.Description, row.CreatedBy, row.CreatedAt, row.RevokedAt,
+			row.Scopes, row.LastUsedAt, row.RotatedFromID,
+		),
+		Key: key,
+	}, nil
+}
+
+// generateKey возвращает новый ключ: 32 случайных байта в hex.
+func generateKey() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("ошибка генерации ключа: %w", err)
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+// RevokeCredential отзывает ключ и сразу убирает его из кэша текущего инстанса.
+func (s *Service) RevokeCredential(ctx context.Context, id int64, revokedBy string) (*api_models.ServiceCredentialResponse, error) {
+	logger := s.logger.WithField("method", "RevokeCredential").WithField("credential_id", id)
+
+	if id <= 0 {
+		return nil, apierrors.NewValidationError("request.id_positive_got", id)
+	}
+
+	row, err := s.store.RevokeServiceCredential(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewNotFoundError("servicecreds.key_not_found", id)
+		}
+		return nil, fmt.Errorf("ошибка RevokeServiceCredential(%d): %w", id, err)
+	}
+
+	if err := s.Refresh(ctx); err != nil {
+		logger.Warnf("Ключ отозван, но кэш не обновлён: %v", err)
+	}
+
+	logger.Infof("Ключ %s… сервиса %s отозван (оператор: %s)", row.KeyPrefix, row.ServiceName, revokedBy)
+	resp := credentialToResponse(row.ID, row.ServiceName, row.KeyPrefix, row.Description, row.CreatedBy, row.CreatedAt, row.RevokedAt,
+		row.Scopes, row.LastUsedAt, row.RotatedFromID)
+	return &resp, nil
+}
+
+// ListCredentials возвращает все ключи (включая отозванные) без хешей.
+func (s *Service) ListCredentials(ctx context.Context) ([]api_models.ServiceCredentialResponse, error) {
+	rows, err := s.store.ListServiceCredentials(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ListServiceCredentials: %w", err)
+	}
+
+	result := make([]api_models.ServiceCredentialResponse, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, credentialToResponse(
+			row.ID, row.ServiceName, row.KeyPrefix, row.Description, row.CreatedBy, row.CreatedAt, row.RevokedAt,
+			row.Scopes, row.LastUsedAt, row.RotatedFromID,
+		))
+	}
+	return result, nil
+}
+
+func credentialToResponse(
+	id int64,
+	serviceName, keyPrefix string,
+	description sql.NullString,
+	createdBy string,
+	createdAt time.Time,
+	revokedAt sql.NullTime,
+	scopes []string,
+	lastUsedAt sql.NullTime,
+	rotatedFromID sql.NullInt64,
+) api_models.ServiceCredentialResponse {
+	resp := api_models.ServiceCredentialResponse{
+		ID:          id,
+		ServiceName: serviceName,
+		KeyPrefix:   keyPrefix,
+		Scopes:      scopes,
+		CreatedBy:   createdBy,
+		CreatedAt:   createdAt,
+	}
+	if description.Valid {
+		d := description.String
+		resp.Description = &d
+	}
+	if revokedAt.Valid {
+		t := revokedAt.Time
+		resp.RevokedAt = &t
+	}
+	if lastUsedAt.Valid {
+		t := lastUsedAt.Time
+		resp.LastUsedAt = &t
+	}
+	if rotatedFromID.Valid {
+		id := rotatedFromID.Int64
+		resp.RotatedFromID = &id
+	}
+	return resp
+}
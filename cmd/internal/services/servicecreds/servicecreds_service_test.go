@@ -0,0 +1,351 @@
+package servicecreds
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR SERVICE CREDENTIALS (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Key rotation — a key added to the DB must be accepted after Refresh, without restart
+2. Revocation — a key removed from the DB must stop being accepted after Refresh
+3. Availability — a DB outage during Refresh must not drop the current cache
+4. Backward compatibility — GO_SERVER_API_KEY keeps working as a static key
+   with every scope
+5. Isolation — workers are told apart by their keys, and a key only opens its scopes
+6. Audit — last_used_at shows which keys are still in use before revoking them
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Authenticate
+- GIVEN a static key for python-worker
+  WHEN Authenticate is called with that key
+  THEN identity python-worker with all scopes; wrong or empty key THEN false
+
+SCENARIO 2: Refresh
+- GIVEN a DB key K1, then the DB returns only K2
+  WHEN Refresh is called twice
+  THEN K1 is rejected and K2 is accepted
+
+- GIVEN a cached key and a DB error on Refresh
+  WHEN Refresh is called
+  THEN error is returned and the cached key is still accepted
+
+SCENARIO 3: CreateCredential / RevokeCredential
+- GIVEN an empty service_name
+  WHEN CreateCredential is called
+  THEN ValidationError without DB call
+
+- GIVEN a valid request
+  WHEN CreateCredential is called
+  THEN only the hash and prefix are stored and the plaintext key is returned
+
+- GIVEN empty or unknown scopes
+  WHEN CreateCredential is called
+  THEN ValidationError without DB call; duplicates are collapsed and sorted
+
+- GIVEN an unknown or already revoked id
+  WHEN RevokeCredential is called
+  THEN NotFoundError
+
+SCENARIO 4: RotateCredential
+- GIVEN an active key
+  WHEN RotateCredential is called
+  THEN the old key is revoked and a successor with the same worker and scopes is
+  created in one transaction; the new key is accepted, the old one is not
+
+SCENARIO 5: FlushUsage
+- GIVEN DB keys used since the last flush
+  WHEN FlushUsage is called
+  THEN their ids are written once; a DB error keeps them for the next flush
+*/
+
+func setupTestService(t *testing.T, staticKeys map[string]string) (*Service, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	return NewService(mockStore, testutil.NewMockLogger(), staticKeys), mockStore
+}
+
+// authenticated возвращает имя воркера для токена или "" если токен не принят.
+func authenticated(service *Service, token string) string {
+	identity, ok := service.Authenticate(token)
+	if !ok {
+		return ""
+	}
+	return identity.ServiceName
+}
+
+func TestAuthenticate_StaticKey(t *testing.T) {
+	service, _ := setupTestService(t, map[string]string{"python-worker": "legacy-secret"})
+
+	identity, ok := service.Authenticate("legacy-secret")
+	require.True(t, ok)
+	assert.Equal(t, "python-worker", identity.ServiceName)
+	assert.Equal(t, int64(0), identity.CredentialID)
+	assert.True(t, identity.HasScope(ScopeImport))
+	assert.True(t, identity.HasScope(ScopeRAG))
+	assert.True(t, identity.HasScope(ScopeAIResults))
+
+	assert.Empty(t, authenticated(service, "wrong"))
+	assert.Empty(t, authenticated(service, ""))
+	assert.True(t, service.HasCredentials())
+}
+
+func TestAuthenticate_EmptyStaticKeyIgnored(t *testing.T) {
+	service, _ := setupTestService(t, map[string]string{"python-worker": ""})
+
+	assert.False(t, service.HasCredentials())
+	assert.Empty(t, authenticated(service, ""))
+}
+
+func TestRefresh_RotatesKeys(t *testing.T) {
+	service, mockStore := setupTestService(t, nil)
+
+	gomock.InOrder(
+		mockStore.EXPECT().ListActiveServiceCredentials(gomock.Any()).
+			Return([]db.ListActiveServiceCredentialsRow{
+				{ID: 1, ServiceName: "parser-1", KeyHash: hashKey("k1"), Scopes: []string{"import"}},
+			}, nil),
+		mockStore.EXPECT().ListActiveServiceCredentials(gomock.Any()).
+			Return([]db.ListActiveServiceCredentialsRow{
+				{ID: 2, ServiceName: "rag-worker", KeyHash: hashKey("k2"), Scopes: []string{"rag"}},
+			}, nil),
+	)
+
+	require.NoError(t, service.Refresh(context.Background()))
+	assert.Equal(t, "parser-1", authenticated(service, "k1"))
+	assert.Empty(t, authenticated(service, "k2"))
+
+	require.NoError(t, service.Refresh(context.Background()))
+	assert.Empty(t, authenticated(service, "k1"))
+	identity, ok := service.Authenticate("k2")
+	require.True(t, ok)
+	assert.Equal(t, int64(2), identity.CredentialID)
+	assert.True(t, identity.HasScope(ScopeRAG))
+	assert.False(t, identity.HasScope(ScopeImport), "a rag key must not import tenders")
+}
+
+func TestRefresh_DBError_KeepsPreviousCache(t *testing.T) {
+	service, mockStore := setupTestService(t, nil)
+
+	dbErr := errors.New("connection refused")
+	gomock.InOrder(
+		mockStore.EXPECT().ListActiveServiceCredentials(gomock.Any()).
+			Return([]db.ListActiveServiceCredentialsRow{
+				{ID: 1, ServiceName: "python-worker", KeyHash: hashKey("k1"), Scopes: []string{"import"}},
+			}, nil),
+		mockStore.EXPECT().ListActiveServiceCredentials(gomock.Any()).
+			Return(nil, dbErr),
+	)
+
+	require.NoError(t, service.Refresh(context.Background()))
+	err := service.Refresh(context.Background())
+
+	assert.ErrorIs(t, err, dbErr)
+	assert.Equal(t, "python-worker", authenticated(service, "k1"))
+}
+
+func TestCreateCredential_EmptyServiceName(t *testing.T) {
+	service, _ := setupTestService(t, nil)
+
+	_, err := service.CreateCredential(context.Background(), api_models.CreateServiceCredentialRequest{
+		ServiceName: "  ", Scopes: []string{"import"},
+	}, "1")
+
+	var validationErr *apierrors.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+}
+
+func TestCreateCredential_StoresHashOnly(t *testing.T) {
+	service, mockStore := setupTestService(t, nil)
+	now := time.Now()
+
+	var stored db.CreateServiceCredentialParams
+	mockStore.EXPECT().CreateServiceCredential(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateServiceCredentialParams) (db.CreateServiceCredentialRow, error) {
+			stored = arg
+			return db.CreateServiceCredentialRow{
+				ID:          10,
+				ServiceName: arg.ServiceName,
+				KeyPrefix:   arg.KeyPrefix,
+				Description: arg.Description,
+				CreatedBy:   arg.CreatedBy,
+				CreatedAt:   now,
+				Scopes:      arg.Scopes,
+			}, nil
+		})
+	mockStore.EXPECT().ListActiveServiceCredentials(gomock.Any()).
+		DoAndReturn(func(context.Context) ([]db.ListActiveServiceCredentialsRow, error) {
+			return []db.ListActiveServiceCredentialsRow{
+				{ID: 10, ServiceName: stored.ServiceName, KeyHash: stored.KeyHash, Scopes: stored.Scopes},
+			}, nil
+		})
+
+	result, err := service.CreateCredential(context.Background(), api_models.CreateServiceCredentialRequest{
+		ServiceName: "parser-1",
+		Scopes:      []string{"rag", "import", "rag"},
+		Description: "ротация 2026-Q4",
+	}, "1")
+
+	require.NoError(t, err)
+	require.Len(t, result.Key, 64)
+	assert.Equal(t, hashKey(result.Key), stored.KeyHash)
+	assert.NotContains(t, stored.KeyHash, result.Key)
+	assert.Equal(t, result.Key[:keyPrefixLen], result.KeyPrefix)
+	assert.Equal(t, sql.NullString{String: "ротация 2026-Q4", Valid: true}, stored.Description)
+	assert.Equal(t, []string{"import", "rag"}, stored.Scopes)
+	assert.Equal(t, []string{"import", "rag"}, result.Scopes)
+	// Новый ключ принимается сразу, без перезапуска
+	assert.Equal(t, "parser-1", authenticated(service, result.Key))
+}
+
+func TestRevokeCredential_NotFound(t *testing.T) {
+	service, mockStore := setupTestService(t, nil)
+
+	mockStore.EXPECT().RevokeServiceCredential(gomock.Any(), int64(5)).
+		Return(db.RevokeServiceCredentialRow{}, sql.ErrNoRows)
+
+	_, err := service.RevokeCredential(context.Background(), 5, "1")
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+}
+
+func TestCreateCredential_InvalidScopes(t *testing.T) {
+	cases := map[string][]string{
+		"empty":   nil,
+		"unknown": {"import", "admin"},
+	}
+	for name, scopes := range cases {
+		t.Run(name, func(t *testing.T) {
+			service, _ := setupTestService(t, nil)
+
+			_, err := service.CreateCredential(context.Background(), api_models.CreateServiceCredentialRequest{
+				ServiceName: "parser-1", Scopes: scopes,
+			}, "1")
+
+			var validationErr *apierrors.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+		})
+	}
+}
+
+func TestRotateCredential_RevokesOldAndKeepsScopes(t *testing.T) {
+	service, mockStore := setupTestService(t, nil)
+	now := time.Now()
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(*db.Queries) error) error {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer sqlDB.Close()
+
+			mock.ExpectQuery("SELECT id, service_name, description, scopes").
+				WithArgs(int64(3)).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "service_name", "description", "scopes"}).
+					AddRow(int64(3), "parser-1", "основной", "{import}"))
+			mock.ExpectQuery("UPDATE service_credentials").
+				WithArgs(int64(3)).
+				WillReturnRows(sqlmock.NewRows(credentialColumns).
+					AddRow(int64(3), "parser-1", "aaaaaaaa", "основной", "1", now, now, "{import}", nil, nil))
+			mock.ExpectQuery("INSERT INTO service_credentials").
+				WithArgs("parser-1", sqlmock.AnyArg(), sqlmock.AnyArg(), "основной", "7", pq.StringArray{"import"}, int64(3)).
+				WillReturnRows(sqlmock.NewRows(credentialColumns).
+					AddRow(int64(4), "parser-1", "bbbbbbbb", "основной", "7", now, nil, "{import}", nil, int64(3)))
+
+			err = fn(db.New(sqlDB))
+			assert.NoError(t, mock.ExpectationsWereMet())
+			return err
+		})
+	// Кэш обновляется сразу после ротации
+	mockStore.EXPECT().ListActiveServiceCredentials(gomock.Any()).
+		Return([]db.ListActiveServiceCredentialsRow{}, nil)
+
+	result, err := service.RotateCredential(context.Background(), 3, "7")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), result.ID)
+	assert.Equal(t, []string{"import"}, result.Scopes)
+	require.NotNil(t, result.RotatedFromID)
+	assert.Equal(t, int64(3), *result.RotatedFromID)
+	assert.Len(t, result.Key, 64)
+}
+
+func TestRotateCredential_NotFound(t *testing.T) {
+	service, mockStore := setupTestService(t, nil)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(*db.Queries) error) error {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer sqlDB.Close()
+			mock.ExpectQuery("SELECT id, service_name, description, scopes").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "service_name", "description", "scopes"}))
+			return fn(db.New(sqlDB))
+		})
+
+	_, err := service.RotateCredential(context.Background(), 3, "7")
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestFlushUsage(t *testing.T) {
+	service, mockStore := setupTestService(t, map[string]string{"python-worker": "legacy-secret"})
+
+	mockStore.EXPECT().ListActiveServiceCredentials(gomock.Any()).
+		Return([]db.ListActiveServiceCredentialsRow{
+			{ID: 1, ServiceName: "parser-1", KeyHash: hashKey("k1"), Scopes: []string{"import"}},
+			{ID: 2, ServiceName: "rag-worker", KeyHash: hashKey("k2"), Scopes: []string{"rag"}},
+		}, nil)
+	require.NoError(t, service.Refresh(context.Background()))
+
+	// GIVEN: both DB keys used (k2 twice), plus the static key that has no row
+	service.Authenticate("k2")
+	service.Authenticate("k1")
+	service.Authenticate("k2")
+	service.Authenticate("legacy-secret")
+
+	dbErr := errors.New("connection reset")
+	gomock.InOrder(
+		mockStore.EXPECT().TouchServiceCredentials(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.TouchServiceCredentialsParams) error {
+				assert.Equal(t, []int64{1, 2}, arg.Ids)
+				return dbErr
+			}),
+		mockStore.EXPECT().TouchServiceCredentials(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.TouchServiceCredentialsParams) error {
+				assert.Equal(t, []int64{1, 2}, arg.Ids, "ids are retried after a DB error")
+				return nil
+			}),
+	)
+
+	assert.ErrorIs(t, service.FlushUsage(context.Background()), dbErr)
+	require.NoError(t, service.FlushUsage(context.Background()))
+	// Nothing used since the last successful flush → no DB call
+	require.NoError(t, service.FlushUsage(context.Background()))
+}
+
+var credentialColumns = []string{
+	"id", "service_name", "key_prefix", "description", "created_by", "created_at", "revoked_at",
+	"scopes", "last_used_at", "rotated_from_id",
+}
@@ -46,6 +46,11 @@ func NewSettingsService(store db.Store, logger logging.Logger) *SettingsService
 // DedupDistanceThresholdKey — ключ настройки порога дедупликации.
 const DedupDistanceThresholdKey = "dedup_distance_threshold"
 
+// NormVersionKey — ключ активной версии нормализации. Меняется только через
+// POST /api/v1/admin/catalog/norm-version/bump, который заодно чистит кэш и
+// запускает перенормализацию каталога.
+const NormVersionKey = "norm_version"
+
 // UpdateSetting обновляет системную настройку и выполняет побочные эффекты.
 //
 // Бизнес-логика:
@@ -63,7 +68,10 @@ func (s *SettingsService) UpdateSetting(
 
 	// Валидация: ключ не пустой (gin binding:"required" покрывает, но double-check)
 	if strings.TrimSpace(req.Key) == "" {
-		return nil, apierrors.NewValidationError("ключ настройки (key) не может быть пустым")
+		return nil, apierrors.NewValidationError("settings.key_empty")
+	}
+	if req.Key == NormVersionKey {
+		return nil, apierrors.NewValidationError("settings.norm_version_readonly")
 	}
 
 	// Валидация: ровно одно значение должно быть задано
@@ -78,10 +86,10 @@ func (s *SettingsService) UpdateSetting(
 		valueCount++
 	}
 	if valueCount == 0 {
-		return nil, apierrors.NewValidationError("необходимо указать ровно одно значение (value_numeric, value_string или value_boolean)")
+		return nil, apierrors.NewValidationError("settings.value_required")
 	}
 	if valueCount > 1 {
-		return nil, apierrors.NewValidationError("допускается только одно значение (value_numeric, value_string или value_boolean), передано: %d", valueCount)
+		return nil, apierrors.NewValidationError("settings.too_many_values", valueCount)
 	}
 
 	var setting db.SystemSetting
@@ -146,13 +154,13 @@ func (s *SettingsService) UpdateSetting(
 // GetSetting возвращает настройку по ключу.
 func (s *SettingsService) GetSetting(ctx context.Context, key string) (*api_models.SystemSettingResponse, error) {
 	if strings.TrimSpace(key) == "" {
-		return nil, apierrors.NewValidationError("ключ настройки (key) не может быть пустым")
+		return nil, apierrors.NewValidationError("settings.key_empty")
 	}
 
 	setting, err := s.store.GetSystemSettingByKey(ctx, key)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, apierrors.NewNotFoundError("настройка %q не найдена", key)
+			return nil, apierrors.NewNotFoundError("settings.not_found", key)
 		}
 		return nil, fmt.Errorf("ошибка получения настройки %q: %w", key, err)
 	}
@@ -0,0 +1,183 @@
+package tender
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+// Статусы пары вероятных дубликатов (tender_duplicates.status).
+const (
+	DuplicatePending = "pending"
+	DuplicateLinked  = "linked"
+	DuplicateIgnored = "ignored"
+)
+
+const (
+	defaultDuplicatesLimit = 50
+	maxDuplicatesLimit     = 200
+)
+
+// ListDuplicates возвращает очередь вероятных дубликатов; пустой status — все
+// пары. limit <= 0 — значение по умолчанию.
+func (s *TenderService) ListDuplicates(ctx context.Context, status string, limit, offset int32) (*api_models.TenderDuplicatesResponse, error) {
+	switch status {
+	case "", DuplicatePending, DuplicateLinked, DuplicateIgnored:
+	default:
+		return nil, apierrors.NewValidationError("tender.unknown_duplicate_status",
+			status, DuplicatePending, DuplicateLinked, DuplicateIgnored)
+	}
+	if limit <= 0 {
+		limit = defaultDuplicatesLimit
+	}
+	if limit > maxDuplicatesLimit {
+		return nil, apierrors.NewValidationError("request.limit_max", maxDuplicatesLimit)
+	}
+	if offset < 0 {
+		return nil, apierrors.NewValidationError("request.offset_negative")
+	}
+
+	statusArg := sql.NullString{String: status, Valid: status != ""}
+	rows, err := s.store.ListTenderDuplicates(ctx, db.ListTenderDuplicatesParams{
+		Status:     statusArg,
+		PageLimit:  limit,
+		PageOffset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ListTenderDuplicates: %w", err)
+	}
+	total, err := s.store.CountTenderDuplicates(ctx, statusArg)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка CountTenderDuplicates: %w", err)
+	}
+
+	items := make([]api_models.TenderDuplicate, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, api_models.TenderDuplicate{
+			ID: row.ID,
+			Tender: api_models.TenderDuplicateTender{
+				ID: row.TenderID, EtpID: row.TenderEtpID, Title: row.TenderTitle, CreatedAt: row.TenderCreatedAt,
+			},
+			DuplicateOf: api_models.TenderDuplicateTender{
+				ID: row.DuplicateOfID, EtpID: row.DuplicateOfEtpID, Title: row.DuplicateOfTitle, CreatedAt: row.DuplicateOfCreatedAt,
+			},
+			Score:               parseSimilarity(row.Score),
+			TitleSimilarity:     parseSimilarity(row.TitleSimilarity),
+			SameObject:          row.SameObject,
+			PositionsSimilarity: parseSimilarity(row.PositionsSimilarity),
+			Status:              row.Status,
+			ResolvedBy:          nullInt64Ptr(row.ResolvedBy),
+			ResolvedAt:          nullTimePtr(row.ResolvedAt),
+			CreatedAt:           row.CreatedAt,
+		})
+	}
+	return &api_models.TenderDuplicatesResponse{
+		Items:  items,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+// ResolveDuplicate записывает решение администратора по паре: DuplicateLinked —
+// это один тендер, DuplicateIgnored — разные. Решение окончательное: для уже
+// решённой пары возвращается ConflictError, для неизвестной — NotFoundError.
+func (s *TenderService) ResolveDuplicate(ctx context.Context, id int64, status string, resolvedBy int64) (*api_models.TenderDuplicateStatusResponse, error) {
+	logger := s.logger.WithField("method", "ResolveDuplicate").WithField("duplicate_id", id)
+
+	if status != DuplicateLinked && status != DuplicateIgnored {
+		return nil, apierrors.NewValidationError("tender.duplicate_decision", DuplicateLinked, DuplicateIgnored)
+	}
+
+	row, err := s.store.ResolveTenderDuplicate(ctx, db.ResolveTenderDuplicateParams{
+		Status:     status,
+		ResolvedBy: sql.NullInt64{Int64: resolvedBy, Valid: resolvedBy != 0},
+		ID:         id,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, s.explainResolvedDuplicate(ctx, id)
+		}
+		return nil, fmt.Errorf("ошибка ResolveTenderDuplicate: %w", err)
+	}
+
+	logger.Infof("Пара тендеров %d/%d: %s (resolved_by=%d)", row.TenderID, row.DuplicateOfID, status, resolvedBy)
+	return newDuplicateStatus(row), nil
+}
+
+// explainResolvedDuplicate различает «пары нет» и «пара уже решена».
+func (s *TenderService) explainResolvedDuplicate(ctx context.Context, id int64) error {
+	row, err := s.store.GetTenderDuplicate(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apierrors.NewNotFoundError("tender.duplicate_not_found", id)
+		}
+		return fmt.Errorf("ошибка GetTenderDuplicate: %w", err)
+	}
+	return apierrors.NewConflictErrorf(newDuplicateStatus(row), "tender.duplicate_decided", id, row.Status)
+}
+
+// LinkedTenders возвращает тендеры, подтверждённые как тот же тендер под другим
+// etp_id. NotFoundError — если тендера нет.
+func (s *TenderService) LinkedTenders(ctx context.Context, tenderID int64) (*api_models.LinkedTendersResponse, error) {
+	if _, err := s.store.GetTenderByID(ctx, tenderID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewNotFoundError("tender.not_found", tenderID)
+		}
+		return nil, fmt.Errorf("ошибка получения тендера: %w", err)
+	}
+
+	rows, err := s.store.ListLinkedTenders(ctx, tenderID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ListLinkedTenders: %w", err)
+	}
+	items := make([]api_models.LinkedTender, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, api_models.LinkedTender{
+			DuplicateID: row.DuplicateID,
+			ID:          row.ID,
+			EtpID:       row.EtpID,
+			Title:       row.Title,
+			LinkedAt:    row.ResolvedAt.Time,
+		})
+	}
+	return &api_models.LinkedTendersResponse{TenderID: tenderID, Items: items}, nil
+}
+
+func newDuplicateStatus(row db.TenderDuplicate) *api_models.TenderDuplicateStatusResponse {
+	return &api_models.TenderDuplicateStatusResponse{
+		ID:            row.ID,
+		TenderID:      row.TenderID,
+		DuplicateOfID: row.DuplicateOfID,
+		Status:        row.Status,
+		ResolvedBy:    nullInt64Ptr(row.ResolvedBy),
+		ResolvedAt:    row.ResolvedAt.Time,
+	}
+}
+
+// parseSimilarity разбирает NUMERIC(4, 3); битое значение — 0.
+func parseSimilarity(v string) float64 {
+	f, _ := strconv.ParseFloat(v, 64)
+	return f
+}
+
+func nullInt64Ptr(v sql.NullInt64) *int64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int64
+}
+
+func nullTimePtr(v sql.NullTime) *time.Time {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Time
+}
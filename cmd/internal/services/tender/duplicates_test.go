@@ -0,0 +1,152 @@
+package tender
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR DUPLICATE TENDER REVIEW (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Lost decisions — a pair linked or ignored by one admin must not be flipped by another
+2. Confusing errors — "no such pair" and "already resolved" must be distinguishable (404 vs 409)
+3. Bad filters — an unknown status must be rejected, not silently return everything
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Review queue
+- GIVEN a pending pair
+  WHEN ListDuplicates("pending") is called
+  THEN both tenders, the scores as numbers and the total are returned
+- GIVEN an unknown status or a limit above the maximum THEN ValidationError
+
+SCENARIO 2: Resolution
+- GIVEN a pending pair WHEN ResolveDuplicate(linked) THEN the admin and time are recorded
+- GIVEN a resolved pair THEN ConflictError with the current decision
+- GIVEN an unknown pair THEN NotFoundError
+- GIVEN a status other than linked/ignored THEN ValidationError
+
+SCENARIO 3: Linked tenders
+- GIVEN a linked pair WHEN LinkedTenders is called for either tender THEN the other one is listed
+*/
+
+func TestListDuplicates(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	status := sql.NullString{String: DuplicatePending, Valid: true}
+	created := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	mockStore.EXPECT().ListTenderDuplicates(gomock.Any(), db.ListTenderDuplicatesParams{
+		Status: status, PageLimit: defaultDuplicatesLimit, PageOffset: 0,
+	}).Return([]db.ListTenderDuplicatesRow{{
+		ID: 5, Score: "0.760", TitleSimilarity: "0.900", SameObject: true, PositionsSimilarity: "0.500",
+		Status: DuplicatePending, CreatedAt: created,
+		TenderID: 20, TenderEtpID: "ETP-20", TenderTitle: "Монолитные работы, корпус 1",
+		DuplicateOfID: 12, DuplicateOfEtpID: "ETP-12", DuplicateOfTitle: "Монолитные работы корпус 1",
+	}}, nil)
+	mockStore.EXPECT().CountTenderDuplicates(gomock.Any(), status).Return(int64(1), nil)
+
+	resp, err := service.ListDuplicates(context.Background(), DuplicatePending, 0, 0)
+
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 1)
+	item := resp.Items[0]
+	assert.Equal(t, int64(20), item.Tender.ID)
+	assert.Equal(t, "ETP-12", item.DuplicateOf.EtpID)
+	assert.InDelta(t, 0.76, item.Score, 1e-9)
+	assert.InDelta(t, 0.5, item.PositionsSimilarity, 1e-9)
+	assert.Nil(t, item.ResolvedAt)
+	assert.Equal(t, int64(1), resp.Total)
+	assert.Equal(t, int32(defaultDuplicatesLimit), resp.Limit)
+}
+
+func TestListDuplicates_Validation(t *testing.T) {
+	service, _ := setupTestService(t)
+	var validationErr *apierrors.ValidationError
+
+	_, err := service.ListDuplicates(context.Background(), "merged", 0, 0)
+	assert.ErrorAs(t, err, &validationErr)
+
+	_, err = service.ListDuplicates(context.Background(), "", maxDuplicatesLimit+1, 0)
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestResolveDuplicate(t *testing.T) {
+	resolvedAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	t.Run("pending pair is linked", func(t *testing.T) {
+		service, mockStore := setupTestService(t)
+		mockStore.EXPECT().ResolveTenderDuplicate(gomock.Any(), db.ResolveTenderDuplicateParams{
+			Status: DuplicateLinked, ResolvedBy: sql.NullInt64{Int64: 42, Valid: true}, ID: 5,
+		}).Return(db.TenderDuplicate{
+			ID: 5, TenderID: 20, DuplicateOfID: 12, Status: DuplicateLinked,
+			ResolvedBy: sql.NullInt64{Int64: 42, Valid: true}, ResolvedAt: sql.NullTime{Time: resolvedAt, Valid: true},
+		}, nil)
+
+		resp, err := service.ResolveDuplicate(context.Background(), 5, DuplicateLinked, 42)
+
+		require.NoError(t, err)
+		assert.Equal(t, DuplicateLinked, resp.Status)
+		require.NotNil(t, resp.ResolvedBy)
+		assert.Equal(t, int64(42), *resp.ResolvedBy)
+		assert.Equal(t, resolvedAt, resp.ResolvedAt)
+	})
+
+	t.Run("already resolved", func(t *testing.T) {
+		service, mockStore := setupTestService(t)
+		mockStore.EXPECT().ResolveTenderDuplicate(gomock.Any(), gomock.Any()).Return(db.TenderDuplicate{}, sql.ErrNoRows)
+		mockStore.EXPECT().GetTenderDuplicate(gomock.Any(), int64(5)).Return(db.TenderDuplicate{
+			ID: 5, Status: DuplicateIgnored, ResolvedAt: sql.NullTime{Time: resolvedAt, Valid: true},
+		}, nil)
+
+		_, err := service.ResolveDuplicate(context.Background(), 5, DuplicateLinked, 42)
+
+		var conflictErr *apierrors.ConflictError
+		assert.ErrorAs(t, err, &conflictErr)
+	})
+
+	t.Run("unknown pair", func(t *testing.T) {
+		service, mockStore := setupTestService(t)
+		mockStore.EXPECT().ResolveTenderDuplicate(gomock.Any(), gomock.Any()).Return(db.TenderDuplicate{}, sql.ErrNoRows)
+		mockStore.EXPECT().GetTenderDuplicate(gomock.Any(), int64(5)).Return(db.TenderDuplicate{}, sql.ErrNoRows)
+
+		_, err := service.ResolveDuplicate(context.Background(), 5, DuplicateIgnored, 42)
+
+		var notFoundErr *apierrors.NotFoundError
+		assert.ErrorAs(t, err, &notFoundErr)
+	})
+
+	t.Run("invalid decision", func(t *testing.T) {
+		service, _ := setupTestService(t)
+		_, err := service.ResolveDuplicate(context.Background(), 5, DuplicatePending, 42)
+		var validationErr *apierrors.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+}
+
+func TestLinkedTenders(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	linkedAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(12)).Return(db.Tender{ID: 12}, nil)
+	mockStore.EXPECT().ListLinkedTenders(gomock.Any(), int64(12)).Return([]db.ListLinkedTendersRow{
+		{DuplicateID: 5, ID: 20, EtpID: "ETP-20", Title: "Монолитные работы, корпус 1", ResolvedAt: sql.NullTime{Time: linkedAt, Valid: true}},
+	}, nil)
+
+	resp, err := service.LinkedTenders(context.Background(), 12)
+
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, int64(20), resp.Items[0].ID)
+	assert.Equal(t, linkedAt, resp.Items[0].LinkedAt)
+}
@@ -0,0 +1,108 @@
+package tender
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+// purgeStep — удаление строк одной таблицы. Шаги идут от листьев к корню:
+// у lots.tender_id нет ON DELETE CASCADE, и каскад здесь задан явно.
+type purgeStep struct {
+	table string
+	count func(row db.CountTenderPurgeRowsRow) int64
+	purge func(q *db.Queries, ctx context.Context, tenderID int64) (int64, error)
+}
+
+var purgeSteps = []purgeStep{
+	{"position_items", func(r db.CountTenderPurgeRowsRow) int64 { return r.PositionItems }, (*db.Queries).PurgeTenderPositionItems},
+	{"proposal_summary_lines", func(r db.CountTenderPurgeRowsRow) int64 { return r.ProposalSummaryLines }, (*db.Queries).PurgeTenderSummaryLines},
+	{"proposal_additional_info", func(r db.CountTenderPurgeRowsRow) int64 { return r.ProposalAdditionalInfo }, (*db.Queries).PurgeTenderAdditionalInfo},
+	{"winners", func(r db.CountTenderPurgeRowsRow) int64 { return r.Winners }, (*db.Queries).PurgeTenderWinners},
+	{"proposals", func(r db.CountTenderPurgeRowsRow) int64 { return r.Proposals }, (*db.Queries).PurgeTenderProposals},
+	{"ai_analysis_results", func(r db.CountTenderPurgeRowsRow) int64 { return r.AiAnalysisResults }, (*db.Queries).PurgeTenderAIResults},
+	{"lots_chunks", func(r db.CountTenderPurgeRowsRow) int64 { return r.LotsChunks }, (*db.Queries).PurgeTenderLotChunks},
+	{"lots_md_documents", func(r db.CountTenderPurgeRowsRow) int64 { return r.LotsMdDocuments }, (*db.Queries).PurgeTenderLotDocuments},
+	{"notifications", func(r db.CountTenderPurgeRowsRow) int64 { return r.Notifications }, (*db.Queries).PurgeTenderNotifications},
+	{"field_changes", func(r db.CountTenderPurgeRowsRow) int64 { return r.FieldChanges }, (*db.Queries).PurgeTenderFieldChanges},
+	{"lots", func(r db.CountTenderPurgeRowsRow) int64 { return r.Lots }, (*db.Queries).PurgeTenderLots},
+	{"tender_raw_data_history", func(r db.CountTenderPurgeRowsRow) int64 { return r.TenderRawDataHistory }, (*db.Queries).PurgeTenderRawDataHistory},
+	{"tender_raw_data", func(r db.CountTenderPurgeRowsRow) int64 { return r.TenderRawData }, (*db.Queries).PurgeTenderRawData},
+	{"import_metrics", func(r db.CountTenderPurgeRowsRow) int64 { return r.ImportMetrics }, (*db.Queries).PurgeTenderImportMetrics},
+	{"user_tender_subscriptions", func(r db.CountTenderPurgeRowsRow) int64 { return r.UserTenderSubscriptions }, (*db.Queries).PurgeTenderSubscriptions},
+}
+
+// PurgeTender физически удаляет тендер со всеми зависимыми строками в одной
+// транзакции. При dryRun ничего не удаляет и возвращает число строк по таблицам.
+// В отличие от SoftDeleteTender, восстановить тендер после purge нельзя —
+// операция нужна для удаления тестовых импортов.
+// Возвращает NotFoundError, если тендера нет.
+func (s *TenderService) PurgeTender(
+	ctx context.Context,
+	tenderID int64,
+	dryRun bool,
+	purgedBy string,
+) (*api_models.TenderPurgeResponse, error) {
+	logger := s.logger.WithField("method", "PurgeTender").WithField("tender_id", tenderID)
+
+	if _, err := s.store.GetTenderByID(ctx, tenderID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewNotFoundError("tender.not_found", tenderID)
+		}
+		return nil, fmt.Errorf("ошибка получения тендера: %w", err)
+	}
+
+	resp := &api_models.TenderPurgeResponse{
+		TenderID: tenderID,
+		DryRun:   dryRun,
+		Tables:   make([]api_models.TenderPurgeTable, 0, len(purgeSteps)+1),
+	}
+
+	if dryRun {
+		counts, err := s.store.CountTenderPurgeRows(ctx, tenderID)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка подсчёта строк тендера: %w", err)
+		}
+		for _, step := range purgeSteps {
+			addPurgeTable(resp, step.table, step.count(counts))
+		}
+		addPurgeTable(resp, "tenders", 1)
+		return resp, nil
+	}
+
+	err := s.store.ExecTx(ctx, func(qtx *db.Queries) error {
+		for _, step := range purgeSteps {
+			rows, err := step.purge(qtx, ctx, tenderID)
+			if err != nil {
+				return fmt.Errorf("ошибка удаления %s: %w", step.table, err)
+			}
+			addPurgeTable(resp, step.table, rows)
+		}
+		rows, err := qtx.PurgeTender(ctx, tenderID)
+		if err != nil {
+			return fmt.Errorf("ошибка удаления тендера: %w", err)
+		}
+		if rows == 0 {
+			// Тендер удалили параллельно — откатываем транзакцию
+			return apierrors.NewNotFoundError("tender.not_found", tenderID)
+		}
+		addPurgeTable(resp, "tenders", rows)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Warnf("Тендер удалён безвозвратно (purged_by=%s): %d строк", purgedBy, resp.TotalRows)
+	return resp, nil
+}
+
+func addPurgeTable(resp *api_models.TenderPurgeResponse, table string, rows int64) {
+	resp.Tables = append(resp.Tables, api_models.TenderPurgeTable{Table: table, Rows: rows})
+	resp.TotalRows += rows
+}
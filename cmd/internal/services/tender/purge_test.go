@@ -0,0 +1,137 @@
+package tender
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR TENDER PURGE (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Test imports left in production — purge must remove the tender with every dependent row
+2. Blind deletes — dry_run must show row counts per table without deleting anything
+3. Half-deleted tenders — any failure must roll back the whole purge
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: dry_run
+- GIVEN a tender with lots and proposals
+  WHEN PurgeTender is called with dryRun=true
+  THEN counts per table are returned in deletion order, the tender last, no ExecTx
+
+SCENARIO 2: purge
+- GIVEN a tender
+  WHEN PurgeTender is called
+  THEN every table is deleted leaf-first inside one transaction and counts are summed
+
+- GIVEN the tender row disappears concurrently (0 rows deleted)
+  THEN NotFoundError and the transaction is rolled back
+
+SCENARIO 3: unknown tender → NotFoundError, nothing is counted or deleted
+*/
+
+func TestPurgeTender_DryRun(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(7)).Return(db.Tender{ID: 7}, nil)
+	mockStore.EXPECT().CountTenderPurgeRows(gomock.Any(), int64(7)).Return(db.CountTenderPurgeRowsRow{
+		PositionItems:        120,
+		ProposalSummaryLines: 6,
+		Proposals:            3,
+		Winners:              1,
+		Lots:                 2,
+		TenderRawData:        1,
+	}, nil)
+
+	resp, err := service.PurgeTender(context.Background(), 7, true, "42")
+
+	require.NoError(t, err)
+	assert.True(t, resp.DryRun)
+	assert.Equal(t, int64(134), resp.TotalRows)
+	require.Len(t, resp.Tables, len(purgeSteps)+1)
+	assert.Equal(t, "position_items", resp.Tables[0].Table)
+	assert.Equal(t, int64(120), resp.Tables[0].Rows)
+	assert.Equal(t, "tenders", resp.Tables[len(resp.Tables)-1].Table)
+	assert.Equal(t, int64(1), resp.Tables[len(resp.Tables)-1].Rows)
+}
+
+func TestPurgeTender_Success(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(7)).Return(db.Tender{ID: 7}, nil)
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(*db.Queries) error) error {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer func() {
+				assert.NoError(t, mock.ExpectationsWereMet(), "sqlmock: unmet expectations")
+				sqlDB.Close()
+			}()
+
+			for _, step := range purgeSteps {
+				mock.ExpectExec("DELETE FROM " + step.table).
+					WithArgs(int64(7)).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			}
+			mock.ExpectExec("DELETE FROM tenders").
+				WithArgs(int64(7)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			return fn(db.New(sqlDB))
+		},
+	)
+
+	resp, err := service.PurgeTender(context.Background(), 7, false, "42")
+
+	require.NoError(t, err)
+	assert.False(t, resp.DryRun)
+	assert.Equal(t, int64(2*len(purgeSteps)+1), resp.TotalRows)
+	assert.Equal(t, "lots", resp.Tables[10].Table)
+}
+
+func TestPurgeTender_TenderGoneInTx(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(7)).Return(db.Tender{ID: 7}, nil)
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(*db.Queries) error) error {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer sqlDB.Close()
+
+			for range purgeSteps {
+				mock.ExpectExec("DELETE FROM").WillReturnResult(sqlmock.NewResult(0, 0))
+			}
+			mock.ExpectExec("DELETE FROM tenders").WillReturnResult(sqlmock.NewResult(0, 0))
+			return fn(db.New(sqlDB))
+		},
+	)
+
+	_, err := service.PurgeTender(context.Background(), 7, false, "42")
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+}
+
+func TestPurgeTender_NotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(7)).Return(db.Tender{}, sql.ErrNoRows)
+
+	_, err := service.PurgeTender(context.Background(), 7, true, "42")
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+}
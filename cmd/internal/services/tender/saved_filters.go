@@ -0,0 +1,112 @@
+package tender
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/db/postgres"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+const (
+	// MaxSavedFilterNameLength — предел длины имени фильтра (символы), как в chk_saved_filters_name.
+	MaxSavedFilterNameLength = 100
+	// MaxSavedFilters — сколько фильтров может сохранить один пользователь.
+	MaxSavedFilters = 50
+)
+
+// ListSavedFilters возвращает сохранённые фильтры пользователя в порядке создания.
+func (s *TenderService) ListSavedFilters(ctx context.Context, userID int64) (*api_models.SavedFiltersResponse, error) {
+	rows, err := s.store.ListSavedFilters(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ListSavedFilters(%d): %w", userID, err)
+	}
+	items := make([]api_models.SavedFilter, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, s.newSavedFilter(row))
+	}
+	return &api_models.SavedFiltersResponse{Items: items}, nil
+}
+
+// CreateSavedFilter сохраняет именованный фильтр списка тендеров. filters уже
+// проверены хендлером теми же правилами, что и query string GET /api/v1/tenders.
+// Имя уникально у пользователя без учёта регистра: повтор — ConflictError.
+func (s *TenderService) CreateSavedFilter(ctx context.Context, userID int64, name string, filters map[string]string) (*api_models.SavedFilter, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return nil, apierrors.NewValidationError("saved_filter.name_empty")
+	}
+	if utf8.RuneCountInString(name) > MaxSavedFilterNameLength {
+		return nil, apierrors.NewValidationError("saved_filter.name_too_long", MaxSavedFilterNameLength)
+	}
+
+	count, err := s.store.CountSavedFilters(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка CountSavedFilters(%d): %w", userID, err)
+	}
+	if count >= MaxSavedFilters {
+		return nil, apierrors.NewValidationError("saved_filter.limit", MaxSavedFilters)
+	}
+
+	if filters == nil {
+		filters = map[string]string{}
+	}
+	payload, err := json.Marshal(filters)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации фильтра: %w", err)
+	}
+	row, err := s.store.CreateSavedFilter(ctx, db.CreateSavedFilterParams{
+		UserID:  userID,
+		Name:    name,
+		Filters: payload,
+	})
+	if err != nil {
+		if postgres.IsUniqueViolation(err) {
+			return nil, apierrors.NewConflictErrorf(map[string]string{"name": name}, "saved_filter.name_taken", name)
+		}
+		return nil, fmt.Errorf("ошибка CreateSavedFilter(%q): %w", name, err)
+	}
+	s.logger.Debugf("Пользователь %d сохранил фильтр %d %q", userID, row.ID, row.Name)
+	resp := s.newSavedFilter(row)
+	return &resp, nil
+}
+
+// DeleteSavedFilter удаляет фильтр пользователя. Чужой или несуществующий
+// фильтр — NotFoundError.
+func (s *TenderService) DeleteSavedFilter(ctx context.Context, userID, id int64) error {
+	removed, err := s.store.DeleteSavedFilter(ctx, db.DeleteSavedFilterParams{ID: id, UserID: userID})
+	if err != nil {
+		return fmt.Errorf("ошибка DeleteSavedFilter(id=%d, user_id=%d): %w", id, userID, err)
+	}
+	if removed == 0 {
+		return apierrors.NewNotFoundError("saved_filter.not_found", id)
+	}
+	return nil
+}
+
+// newSavedFilter собирает ответ из строки БД. Повреждённый filters (запись в
+// обход API) отдаётся пустым: вкладка откроет список без фильтров.
+func (s *TenderService) newSavedFilter(row db.SavedFilter) api_models.SavedFilter {
+	filters := map[string]string{}
+	if err := json.Unmarshal(row.Filters, &filters); err != nil {
+		s.logger.Warnf("Некорректный filters у сохранённого фильтра %d: %v", row.ID, err)
+		filters = map[string]string{}
+	}
+	values := url.Values{}
+	for name, value := range filters {
+		values.Set(name, value)
+	}
+	return api_models.SavedFilter{
+		ID:        row.ID,
+		Name:      row.Name,
+		Filters:   filters,
+		Query:     values.Encode(),
+		CreatedAt: row.CreatedAt,
+	}
+}
@@ -0,0 +1,111 @@
+package tender
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR SAVED FILTERS (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Broken tabs — the frontend renders a saved filter as a ready-to-use query string
+2. Duplicate tabs — two filters named "Бетон" and " бетон" for the same user
+3. Leaking other users' views — deleting someone else's filter must look like 404
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: CreateSavedFilter
+- GIVEN a name and list parameters WHEN CreateSavedFilter THEN they are stored as
+  JSON and returned together with the encoded query string
+- GIVEN a name taken by the user (unique violation) THEN ConflictError
+- GIVEN a user at the limit THEN ValidationError and no insert
+
+SCENARIO 2: ListSavedFilters / DeleteSavedFilter
+- GIVEN a stored filter with corrupted JSON THEN it is listed with empty filters
+- GIVEN a filter of another user (0 rows deleted) THEN NotFoundError
+*/
+
+func TestCreateSavedFilter_Success(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	created := time.Date(2026, 8, 3, 12, 0, 0, 0, time.UTC)
+
+	mockStore.EXPECT().CountSavedFilters(gomock.Any(), int64(42)).Return(int64(2), nil)
+	mockStore.EXPECT().CreateSavedFilter(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg db.CreateSavedFilterParams) (db.SavedFilter, error) {
+			assert.Equal(t, "Бетон с победителем", arg.Name)
+			assert.JSONEq(t, `{"category_id":"3","has_winner":"true"}`, string(arg.Filters))
+			return db.SavedFilter{ID: 5, UserID: 42, Name: arg.Name, Filters: arg.Filters, CreatedAt: created}, nil
+		})
+
+	resp, err := service.CreateSavedFilter(context.Background(), 42, " Бетон  с победителем ",
+		map[string]string{"category_id": "3", "has_winner": "true"})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), resp.ID)
+	assert.Equal(t, "category_id=3&has_winner=true", resp.Query)
+}
+
+func TestCreateSavedFilter_NameTaken(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().CountSavedFilters(gomock.Any(), int64(42)).Return(int64(0), nil)
+	mockStore.EXPECT().CreateSavedFilter(gomock.Any(), gomock.Any()).Return(db.SavedFilter{}, &pq.Error{Code: "23505"})
+
+	_, err := service.CreateSavedFilter(context.Background(), 42, "Бетон", nil)
+
+	var conflictErr *apierrors.ConflictError
+	assert.True(t, errors.As(err, &conflictErr))
+}
+
+func TestCreateSavedFilter_Limit(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().CountSavedFilters(gomock.Any(), int64(42)).Return(int64(MaxSavedFilters), nil)
+
+	_, err := service.CreateSavedFilter(context.Background(), 42, "Бетон", nil)
+
+	var validationErr *apierrors.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+}
+
+func TestListSavedFilters_CorruptedFilters(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ListSavedFilters(gomock.Any(), int64(42)).Return([]db.SavedFilter{
+		{ID: 1, UserID: 42, Name: "Мой регион", Filters: json.RawMessage(`{"object_id":"9"}`)},
+		{ID: 2, UserID: 42, Name: "Сломанный", Filters: json.RawMessage(`{"object_id":9}`)},
+	}, nil)
+
+	resp, err := service.ListSavedFilters(context.Background(), 42)
+
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 2)
+	assert.Equal(t, "object_id=9", resp.Items[0].Query)
+	assert.Empty(t, resp.Items[1].Filters)
+	assert.Empty(t, resp.Items[1].Query)
+}
+
+func TestDeleteSavedFilter_OtherUser(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().DeleteSavedFilter(gomock.Any(), db.DeleteSavedFilterParams{ID: 5, UserID: 42}).Return(int64(0), nil)
+
+	err := service.DeleteSavedFilter(context.Background(), 42, 5)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+}
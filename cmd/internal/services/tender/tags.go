@@ -0,0 +1,151 @@
+package tender
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/db/postgres"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+// MaxTagNameLength — предел длины названия метки (символы), как в chk_tags_name.
+const MaxTagNameLength = 64
+
+// ListTags возвращает метки организации по алфавиту с числом тендеров.
+// organizationID — организация пользователя; невалидный — все метки (admin).
+func (s *TenderService) ListTags(ctx context.Context, organizationID sql.NullInt64) (*api_models.TagsResponse, error) {
+	rows, err := s.store.ListTags(ctx, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ListTags: %w", err)
+	}
+	items := make([]api_models.Tag, 0, len(rows))
+	for _, row := range rows {
+		count := row.TendersCount
+		items = append(items, api_models.Tag{
+			ID:             row.ID,
+			OrganizationID: row.OrganizationID,
+			Name:           row.Name,
+			TendersCount:   &count,
+			CreatedAt:      row.CreatedAt,
+		})
+	}
+	return &api_models.TagsResponse{Items: items}, nil
+}
+
+// CreateTag заводит метку в организации пользователя. Название уникально в
+// организации без учёта регистра: повтор — ConflictError.
+func (s *TenderService) CreateTag(ctx context.Context, organizationID int64, req api_models.CreateTagRequest, createdBy int64) (*api_models.Tag, error) {
+	name := strings.Join(strings.Fields(req.Name), " ")
+	if name == "" {
+		return nil, apierrors.NewValidationError("tag.name_empty")
+	}
+	if utf8.RuneCountInString(name) > MaxTagNameLength {
+		return nil, apierrors.NewValidationError("tag.name_too_long", MaxTagNameLength)
+	}
+
+	tag, err := s.store.CreateTag(ctx, db.CreateTagParams{
+		OrganizationID: organizationID,
+		Name:           name,
+		CreatedBy:      sql.NullInt64{Int64: createdBy, Valid: createdBy != 0},
+	})
+	if err != nil {
+		if postgres.IsUniqueViolation(err) {
+			return nil, apierrors.NewConflictErrorf(map[string]string{"name": name}, "tag.name_taken", name)
+		}
+		return nil, fmt.Errorf("ошибка CreateTag(%q): %w", name, err)
+	}
+	s.logger.Infof("Пользователь %d создал метку %d %q (организация %d)", createdBy, tag.ID, tag.Name, organizationID)
+	resp := newTag(tag)
+	return &resp, nil
+}
+
+// TenderTags возвращает метки тендера. NotFoundError — если тендера нет.
+func (s *TenderService) TenderTags(ctx context.Context, tenderID int64) (*api_models.TenderTagsResponse, error) {
+	if _, err := s.getTender(ctx, tenderID); err != nil {
+		return nil, err
+	}
+	return s.tenderTags(ctx, tenderID)
+}
+
+// AttachTag вешает метку на тендер; повторная привязка не ошибка. Метка
+// должна принадлежать организации тендера: чужая выглядит как несуществующая.
+// Доступ к тендеру чужой организации проверяет middleware маршрута.
+func (s *TenderService) AttachTag(ctx context.Context, tenderID, tagID, attachedBy int64) (*api_models.TenderTagsResponse, error) {
+	tender, err := s.getTender(ctx, tenderID)
+	if err != nil {
+		return nil, err
+	}
+	if tender.DeletedAt.Valid {
+		return nil, apierrors.NewNotFoundError("tender.not_found", tenderID)
+	}
+	tag, err := s.store.GetTag(ctx, tagID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("ошибка GetTag(%d): %w", tagID, err)
+	}
+	if err != nil || tag.OrganizationID != tender.OrganizationID {
+		return nil, apierrors.NewNotFoundError("tag.not_found", tagID)
+	}
+
+	if err := s.store.AttachTenderTag(ctx, db.AttachTenderTagParams{
+		TenderID:  tenderID,
+		TagID:     tagID,
+		CreatedBy: sql.NullInt64{Int64: attachedBy, Valid: attachedBy != 0},
+	}); err != nil {
+		return nil, fmt.Errorf("ошибка AttachTenderTag(tender_id=%d, tag_id=%d): %w", tenderID, tagID, err)
+	}
+	return s.tenderTags(ctx, tenderID)
+}
+
+// DetachTag снимает метку с тендера. Отсутствующая привязка не ошибка:
+// клиент может повторить запрос после обрыва соединения.
+func (s *TenderService) DetachTag(ctx context.Context, tenderID, tagID int64) (*api_models.TenderTagsResponse, error) {
+	if _, err := s.getTender(ctx, tenderID); err != nil {
+		return nil, err
+	}
+	removed, err := s.store.DetachTenderTag(ctx, db.DetachTenderTagParams{TenderID: tenderID, TagID: tagID})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка DetachTenderTag(tender_id=%d, tag_id=%d): %w", tenderID, tagID, err)
+	}
+	if removed > 0 {
+		s.logger.Debugf("С тендера %d снята метка %d", tenderID, tagID)
+	}
+	return s.tenderTags(ctx, tenderID)
+}
+
+func (s *TenderService) getTender(ctx context.Context, tenderID int64) (db.Tender, error) {
+	tender, err := s.store.GetTenderByID(ctx, tenderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return tender, apierrors.NewNotFoundError("tender.not_found", tenderID)
+		}
+		return tender, fmt.Errorf("ошибка получения тендера: %w", err)
+	}
+	return tender, nil
+}
+
+func (s *TenderService) tenderTags(ctx context.Context, tenderID int64) (*api_models.TenderTagsResponse, error) {
+	rows, err := s.store.ListTenderTags(ctx, tenderID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ListTenderTags(%d): %w", tenderID, err)
+	}
+	tags := make([]api_models.Tag, 0, len(rows))
+	for _, row := range rows {
+		tags = append(tags, newTag(row))
+	}
+	return &api_models.TenderTagsResponse{TenderID: tenderID, Tags: tags}, nil
+}
+
+func newTag(tag db.Tag) api_models.Tag {
+	return api_models.Tag{
+		ID:             tag.ID,
+		OrganizationID: tag.OrganizationID,
+		Name:           tag.Name,
+		CreatedAt:      tag.CreatedAt,
+	}
+}
@@ -0,0 +1,138 @@
+package tender
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR TENDER TAGS (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Tag sprawl — "Q3 priority" and " q3  Priority" must not become two tags
+2. Cross-organization leaks — a tag of another organization must not be
+   attachable (and must look like it does not exist)
+3. Flaky clients — repeated attach/detach after a dropped connection is not an error
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: CreateTag
+- GIVEN a name with extra whitespace WHEN CreateTag THEN it is stored normalized
+- GIVEN a name already taken in the organization (unique violation) THEN ConflictError
+- GIVEN a blank name THEN ValidationError and no insert
+
+SCENARIO 2: AttachTag / DetachTag
+- GIVEN a tag of the tender's organization WHEN AttachTag THEN the tender's tags are returned
+- GIVEN a tag of another organization THEN NotFoundError and nothing is attached
+- GIVEN a deleted tender THEN NotFoundError
+- GIVEN a tag that is not attached WHEN DetachTag THEN the current tags are returned
+*/
+
+func TestCreateTag_NormalizesName(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	created := time.Date(2026, 7, 1, 9, 0, 0, 0, time.UTC)
+
+	mockStore.EXPECT().CreateTag(gomock.Any(), db.CreateTagParams{
+		OrganizationID: 3,
+		Name:           "Приоритет Q3",
+		CreatedBy:      sql.NullInt64{Int64: 42, Valid: true},
+	}).Return(db.Tag{ID: 9, OrganizationID: 3, Name: "Приоритет Q3", CreatedAt: created}, nil)
+
+	tag, err := service.CreateTag(context.Background(), 3, api_models.CreateTagRequest{Name: "  Приоритет   Q3 "}, 42)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(9), tag.ID)
+	assert.Equal(t, "Приоритет Q3", tag.Name)
+	assert.Nil(t, tag.TendersCount)
+}
+
+func TestCreateTag_NameTaken(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().CreateTag(gomock.Any(), gomock.Any()).
+		Return(db.Tag{}, &pq.Error{Code: "23505"})
+
+	_, err := service.CreateTag(context.Background(), 3, api_models.CreateTagRequest{Name: "Рамочный договор"}, 42)
+
+	var conflictErr *apierrors.ConflictError
+	assert.True(t, errors.As(err, &conflictErr))
+}
+
+func TestCreateTag_BlankName(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.CreateTag(context.Background(), 3, api_models.CreateTagRequest{Name: "   "}, 42)
+
+	var validationErr *apierrors.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+}
+
+func TestAttachTag_Success(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(7)).Return(db.Tender{ID: 7, OrganizationID: 3}, nil)
+	mockStore.EXPECT().GetTag(gomock.Any(), int64(9)).Return(db.Tag{ID: 9, OrganizationID: 3, Name: "Приоритет Q3"}, nil)
+	mockStore.EXPECT().AttachTenderTag(gomock.Any(), db.AttachTenderTagParams{
+		TenderID: 7, TagID: 9, CreatedBy: sql.NullInt64{Int64: 42, Valid: true},
+	}).Return(nil)
+	mockStore.EXPECT().ListTenderTags(gomock.Any(), int64(7)).
+		Return([]db.Tag{{ID: 9, OrganizationID: 3, Name: "Приоритет Q3"}}, nil)
+
+	resp, err := service.AttachTag(context.Background(), 7, 9, 42)
+
+	require.NoError(t, err)
+	require.Len(t, resp.Tags, 1)
+	assert.Equal(t, "Приоритет Q3", resp.Tags[0].Name)
+}
+
+func TestAttachTag_OtherOrganizationTag(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(7)).Return(db.Tender{ID: 7, OrganizationID: 3}, nil)
+	mockStore.EXPECT().GetTag(gomock.Any(), int64(9)).Return(db.Tag{ID: 9, OrganizationID: 4}, nil)
+
+	_, err := service.AttachTag(context.Background(), 7, 9, 42)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+}
+
+func TestAttachTag_DeletedTender(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(7)).Return(db.Tender{
+		ID: 7, OrganizationID: 3, DeletedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}, nil)
+
+	_, err := service.AttachTag(context.Background(), 7, 9, 42)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+}
+
+func TestDetachTag_NotAttached(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(7)).Return(db.Tender{ID: 7, OrganizationID: 3}, nil)
+	mockStore.EXPECT().DetachTenderTag(gomock.Any(), db.DetachTenderTagParams{TenderID: 7, TagID: 9}).Return(int64(0), nil)
+	mockStore.EXPECT().ListTenderTags(gomock.Any(), int64(7)).Return([]db.Tag{}, nil)
+
+	resp, err := service.DetachTag(context.Background(), 7, 9)
+
+	require.NoError(t, err)
+	assert.Empty(t, resp.Tags)
+}
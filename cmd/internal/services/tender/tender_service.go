@@ -0,0 +1,132 @@
+// Package tender предоставляет сервисный слой для жизненного цикла тендера.
+//
+// # Мягкое удаление
+//
+// DELETE /api/v1/tenders/:id не удаляет строку, а проставляет tenders.deleted_at.
+// Каскад определён здесь и намеренно «ленивый»: лоты, предложения, позиции и
+// победители не изменяются, а скрываются вместе с тендером — запросы списков и
+// деталей фильтруют по tenders.deleted_at. Благодаря этому восстановление
+// возвращает тендер ровно в том состоянии, в котором он был удалён.
+//
+// Повторный импорт тендера с тем же etp_id обновляет данные, но не снимает
+// пометку удаления: вернуть тендер в списки можно только явным восстановлением.
+//
+// # Безвозвратное удаление
+//
+// POST /api/v1/admin/tenders/:id/purge (purge.go) удаляет тендер и все зависимые
+// строки в одной транзакции — для тестовых импортов, попавших в рабочую базу.
+// Режим dry_run только считает строки по таблицам.
+//
+// # Дубликаты
+//
+// Импорт (importer) ставит в tender_duplicates пары тендеров, похожих на один
+// тендер под разными etp_id. duplicates.go — очередь на проверку и решение
+// администратора: linked (один тендер) или ignored (разные).
+//
+// # Метки
+//
+// tags.go — метки тендеров для произвольной группировки поверх категорий. У
+// каждой организации свой набор меток; тендеру можно повесить только метку его
+// организации.
+package tender
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// TenderService управляет удалением и восстановлением тендеров и проверкой
+// вероятных дубликатов.
+type TenderService struct {
+	store  db.Store
+	logger logging.Logger
+}
+
+// NewTenderService создаёт новый экземпляр TenderService.
+func NewTenderService(store db.Store, logger logging.Logger) *TenderService {
+	return &TenderService{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// SoftDeleteTender помечает тендер удалённым.
+// Возвращает NotFoundError, если тендера нет, и ConflictError, если он уже удалён.
+func (s *TenderService) SoftDeleteTender(
+	ctx context.Context,
+	tenderID int64,
+	deletedBy string,
+) (*api_models.TenderArchiveStatusResponse, error) {
+	logger := s.logger.WithField("method", "SoftDeleteTender").WithField("tender_id", tenderID)
+
+	row, err := s.store.SoftDeleteTender(ctx, db.SoftDeleteTenderParams{
+		ID:        tenderID,
+		DeletedBy: sql.NullString{String: deletedBy, Valid: deletedBy != ""},
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, s.explainNoRows(ctx, tenderID, true)
+		}
+		return nil, fmt.Errorf("ошибка удаления тендера: %w", err)
+	}
+
+	logger.Infof("Тендер помечен удалённым (deleted_by=%s)", deletedBy)
+	return newArchiveStatus(row.ID, row.DeletedAt, row.DeletedBy), nil
+}
+
+// RestoreTender снимает пометку удаления с тендера.
+// Возвращает NotFoundError, если тендера нет, и ConflictError, если он не удалён.
+func (s *TenderService) RestoreTender(
+	ctx context.Context,
+	tenderID int64,
+	restoredBy string,
+) (*api_models.TenderArchiveStatusResponse, error) {
+	logger := s.logger.WithField("method", "RestoreTender").WithField("tender_id", tenderID)
+
+	row, err := s.store.RestoreTender(ctx, tenderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, s.explainNoRows(ctx, tenderID, false)
+		}
+		return nil, fmt.Errorf("ошибка восстановления тендера: %w", err)
+	}
+
+	logger.Infof("Тендер восстановлен (restored_by=%s)", restoredBy)
+	return newArchiveStatus(row.ID, row.DeletedAt, row.DeletedBy), nil
+}
+
+// explainNoRows различает «тендер не найден» и «тендер уже в нужном состоянии»:
+// UPDATE ... WHERE deleted_at IS [NOT] NULL в обоих случаях возвращает sql.ErrNoRows.
+func (s *TenderService) explainNoRows(ctx context.Context, tenderID int64, deleting bool) error {
+	t, err := s.store.GetTenderByID(ctx, tenderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apierrors.NewNotFoundError("tender.not_found", tenderID)
+		}
+		return fmt.Errorf("ошибка получения тендера: %w", err)
+	}
+
+	status := newArchiveStatus(t.ID, t.DeletedAt, t.DeletedBy)
+	if deleting {
+		return apierrors.NewConflictErrorf(status, "tender.already_deleted", tenderID)
+	}
+	return apierrors.NewConflictErrorf(status, "tender.not_deleted", tenderID)
+}
+
+func newArchiveStatus(id int64, deletedAt sql.NullTime, deletedBy sql.NullString) *api_models.TenderArchiveStatusResponse {
+	resp := &api_models.TenderArchiveStatusResponse{ID: id}
+	if deletedAt.Valid {
+		resp.DeletedAt = &deletedAt.Time
+	}
+	if deletedBy.Valid {
+		resp.DeletedBy = &deletedBy.String
+	}
+	return resp
+}
@@ -0,0 +1,156 @@
+package tender
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR TENDER SOFT DELETE (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Data loss — DELETE must only mark the tender, restore must bring it back intact
+2. Confusing errors — "not found" and "already deleted" must be distinguishable (404 vs 409)
+3. Audit gaps — who deleted the tender must be recorded
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: SoftDeleteTender
+- GIVEN an active tender
+  WHEN SoftDeleteTender is called by user 42
+  THEN deleted_at and deleted_by="42" are returned
+
+- GIVEN a tender that is already deleted
+  WHEN SoftDeleteTender is called
+  THEN ConflictError with the current status
+
+- GIVEN an unknown tender id
+  WHEN SoftDeleteTender is called
+  THEN NotFoundError
+
+SCENARIO 2: RestoreTender
+- GIVEN a deleted tender
+  WHEN RestoreTender is called
+  THEN deleted_at and deleted_by are null
+
+- GIVEN an active tender
+  WHEN RestoreTender is called
+  THEN ConflictError
+
+SCENARIO 3: DB errors
+- GIVEN the DB fails
+  WHEN SoftDeleteTender is called
+  THEN the error is wrapped and is not an apierrors type
+*/
+
+func setupTestService(t *testing.T) (*TenderService, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	return NewTenderService(mockStore, testutil.NewMockLogger()), mockStore
+}
+
+func TestSoftDeleteTender_Success(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	now := time.Now()
+
+	mockStore.EXPECT().SoftDeleteTender(gomock.Any(), db.SoftDeleteTenderParams{
+		ID:        7,
+		DeletedBy: sql.NullString{String: "42", Valid: true},
+	}).Return(db.SoftDeleteTenderRow{
+		ID:        7,
+		DeletedAt: sql.NullTime{Time: now, Valid: true},
+		DeletedBy: sql.NullString{String: "42", Valid: true},
+	}, nil)
+
+	resp, err := service.SoftDeleteTender(context.Background(), 7, "42")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), resp.ID)
+	require.NotNil(t, resp.DeletedAt)
+	assert.Equal(t, now, *resp.DeletedAt)
+	require.NotNil(t, resp.DeletedBy)
+	assert.Equal(t, "42", *resp.DeletedBy)
+}
+
+func TestSoftDeleteTender_AlreadyDeleted_ReturnsConflict(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().SoftDeleteTender(gomock.Any(), gomock.Any()).Return(db.SoftDeleteTenderRow{}, sql.ErrNoRows)
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(7)).Return(db.Tender{
+		ID:        7,
+		DeletedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		DeletedBy: sql.NullString{String: "1", Valid: true},
+	}, nil)
+
+	_, err := service.SoftDeleteTender(context.Background(), 7, "42")
+
+	var conflictErr *apierrors.ConflictError
+	require.True(t, errors.As(err, &conflictErr))
+	assert.Contains(t, conflictErr.Message, "уже удалён")
+}
+
+func TestSoftDeleteTender_NotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().SoftDeleteTender(gomock.Any(), gomock.Any()).Return(db.SoftDeleteTenderRow{}, sql.ErrNoRows)
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(7)).Return(db.Tender{}, sql.ErrNoRows)
+
+	_, err := service.SoftDeleteTender(context.Background(), 7, "42")
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+}
+
+func TestSoftDeleteTender_DBError(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	dbErr := errors.New("connection refused")
+	mockStore.EXPECT().SoftDeleteTender(gomock.Any(), gomock.Any()).Return(db.SoftDeleteTenderRow{}, dbErr)
+
+	_, err := service.SoftDeleteTender(context.Background(), 7, "42")
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, dbErr)
+	var conflictErr *apierrors.ConflictError
+	assert.False(t, errors.As(err, &conflictErr))
+}
+
+func TestRestoreTender_Success(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().RestoreTender(gomock.Any(), int64(7)).Return(db.RestoreTenderRow{ID: 7}, nil)
+
+	resp, err := service.RestoreTender(context.Background(), 7, "42")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), resp.ID)
+	assert.Nil(t, resp.DeletedAt)
+	assert.Nil(t, resp.DeletedBy)
+}
+
+func TestRestoreTender_NotDeleted_ReturnsConflict(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().RestoreTender(gomock.Any(), int64(7)).Return(db.RestoreTenderRow{}, sql.ErrNoRows)
+	mockStore.EXPECT().GetTenderByID(gomock.Any(), int64(7)).Return(db.Tender{ID: 7}, nil)
+
+	_, err := service.RestoreTender(context.Background(), 7, "42")
+
+	var conflictErr *apierrors.ConflictError
+	require.True(t, errors.As(err, &conflictErr))
+	assert.Contains(t, conflictErr.Message, "не удалён")
+}
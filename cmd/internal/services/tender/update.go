@@ -0,0 +1,94 @@
+package tender
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/history"
+)
+
+// UpdateTender частично обновляет тендер (PATCH /api/v1/tenders/:id) и в той же
+// транзакции пишет изменённые поля в историю правок от имени changedBy.
+// Возвращает NotFoundError, если тендера нет или он удалён.
+func (s *TenderService) UpdateTender(
+	ctx context.Context,
+	params db.UpdateTenderDetailsParams,
+	changedBy int64,
+) (db.Tender, error) {
+	logger := s.logger.WithField("method", "UpdateTender").WithField("tender_id", params.ID)
+
+	var updated db.Tender
+	var changes []history.Change
+	err := s.store.ExecTx(ctx, func(qtx *db.Queries) error {
+		before, err := qtx.GetTenderForUpdate(ctx, params.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return apierrors.NewNotFoundError("tender.not_found", params.ID)
+			}
+			return fmt.Errorf("ошибка получения тендера: %w", err)
+		}
+
+		updated, err = qtx.UpdateTenderDetails(ctx, params)
+		if err != nil {
+			return fmt.Errorf("ошибка обновления тендера: %w", err)
+		}
+
+		changes = tenderChanges(before, updated)
+		return history.Record(ctx, qtx, history.EntityTender, params.ID, changedBy, changes)
+	})
+	if err != nil {
+		return db.Tender{}, err
+	}
+
+	if len(changes) > 0 {
+		logger.Infof("Тендер обновлён, изменено полей: %d (user_id=%d)", len(changes), changedBy)
+	}
+	return updated, nil
+}
+
+// tenderChanges сравнивает редактируемые поля тендера до и после правки.
+func tenderChanges(before, after db.Tender) []history.Change {
+	var changes []history.Change
+	add := func(field string, oldValue, newValue interface{}) {
+		if oldValue != newValue {
+			changes = append(changes, history.Change{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+
+	add("etp_id", before.EtpID, after.EtpID)
+	add("title", before.Title, after.Title)
+	add("category_id", nullInt64Value(before.CategoryID), nullInt64Value(after.CategoryID))
+	add("object_id", before.ObjectID, after.ObjectID)
+	add("executor_id", before.ExecutorID, after.ExecutorID)
+	add("data_prepared_on_date", nullTimeValue(before.DataPreparedOnDate), nullTimeValue(after.DataPreparedOnDate))
+	add("region", nullStringValue(before.Region), nullStringValue(after.Region))
+	return changes
+}
+
+func nullInt64Value(v sql.NullInt64) interface{} {
+	if !v.Valid {
+		return nil
+	}
+	return v.Int64
+}
+
+func nullStringValue(v sql.NullString) interface{} {
+	if !v.Valid {
+		return nil
+	}
+	return v.String
+}
+
+// nullTimeValue приводит время к строке RFC3339 в UTC: значения из разных
+// часовых поясов сравниваются как моменты времени.
+func nullTimeValue(v sql.NullTime) interface{} {
+	if !v.Valid {
+		return nil
+	}
+	return v.Time.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,99 @@
+package tender
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR TENDER UPDATE (Unit Tests)
+
+SCENARIO 1: edit with history
+- GIVEN a tender
+  WHEN UpdateTender changes the title and sets the region
+  THEN the row is locked, updated, and one history row per changed field is written
+  in the same transaction with old and new values and the editing user;
+  fields passed with their current value are not recorded
+
+SCENARIO 2: missing or soft-deleted tender
+- GIVEN the locked read finds no row
+  WHEN UpdateTender is called
+  THEN NotFoundError is returned and nothing is updated
+*/
+
+var tenderColumns = []string{
+	"id", "etp_id", "title", "category_id", "object_id", "executor_id", "data_prepared_on_date",
+	"created_at", "updated_at", "deleted_at", "deleted_by", "organization_id", "region", "etp_status", "synced_at",
+}
+
+func TestUpdateTender_RecordsChangedFields(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	now := time.Now()
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(*db.Queries) error) error {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer func() {
+				assert.NoError(t, mock.ExpectationsWereMet(), "sqlmock: unmet expectations")
+				sqlDB.Close()
+			}()
+
+			mock.ExpectQuery("SELECT .+ FROM tenders WHERE id .+ FOR UPDATE").
+				WithArgs(int64(7)).
+				WillReturnRows(sqlmock.NewRows(tenderColumns).
+					AddRow(int64(7), "ETP-7", "Старое название", int64(3), int64(1), int64(2), nil, now, now, nil, nil, int64(1), nil, nil, nil))
+			mock.ExpectQuery("UPDATE tenders").
+				WillReturnRows(sqlmock.NewRows(tenderColumns).
+					AddRow(int64(7), "ETP-7", "Новое название", int64(3), int64(1), int64(2), nil, now, now, nil, nil, int64(1), "77", nil, nil))
+			mock.ExpectExec("INSERT INTO field_changes").
+				WithArgs("tender", int64(7), "title", []byte(`"Старое название"`), []byte(`"Новое название"`), int64(42)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec("INSERT INTO field_changes").
+				WithArgs("tender", int64(7), "region", []byte(`null`), []byte(`"77"`), int64(42)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			return fn(db.New(sqlDB))
+		},
+	)
+
+	tender, err := service.UpdateTender(context.Background(), db.UpdateTenderDetailsParams{
+		ID:         7,
+		Title:      sql.NullString{String: "Новое название", Valid: true},
+		CategoryID: sql.NullInt64{Int64: 3, Valid: true},
+		Region:     sql.NullString{String: "77", Valid: true},
+	}, 42)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Новое название", tender.Title)
+}
+
+func TestUpdateTender_NotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(*db.Queries) error) error {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer sqlDB.Close()
+
+			mock.ExpectQuery("FROM tenders").WithArgs(int64(7)).WillReturnError(sql.ErrNoRows)
+			return fn(db.New(sqlDB))
+		},
+	)
+
+	_, err := service.UpdateTender(context.Background(), db.UpdateTenderDetailsParams{ID: 7}, 42)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+}
@@ -0,0 +1,337 @@
+// Package units предоставляет сервисный слой справочника единиц измерения.
+//
+// Импорт создаёт единицы «как пришли» (entities.GetOrCreateUnitOfMeasurement),
+// поэтому одна единица может оказаться в справочнике под разными написаниями.
+// Здесь администратор заводит канонические единицы с синонимами и сливает
+// дубликаты: позиции КП и каталога переносятся на основную единицу, а имя
+// дубликата становится её синонимом, чтобы следующий импорт не создал его снова.
+package units
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/entities"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// maxNameLength — длина units_of_measurement.normalized_name и unit_aliases.alias.
+const maxNameLength = 50
+
+// UnitService управляет справочником единиц измерения и их синонимами.
+type UnitService struct {
+	store  db.Store
+	logger logging.Logger
+}
+
+// NewUnitService создаёт новый экземпляр UnitService.
+func NewUnitService(store db.Store, logger logging.Logger) *UnitService {
+	return &UnitService{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// ListUnits возвращает справочник единиц с синонимами и числом позиций КП и
+// каталога, которые на них ссылаются.
+func (s *UnitService) ListUnits(ctx context.Context) (*api_models.ListUnitsResponse, error) {
+	rows, err := s.store.ListUnitsWithUsage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения справочника единиц измерения: %w", err)
+	}
+	aliases, err := s.store.ListUnitAliases(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения синонимов единиц измерения: %w", err)
+	}
+
+	byUnit := make(map[int64][]api_models.UnitAlias)
+	for _, a := range aliases {
+		byUnit[a.UnitID] = append(byUnit[a.UnitID], api_models.UnitAlias{ID: a.ID, Alias: a.Alias})
+	}
+
+	units := make([]api_models.UnitOfMeasurementAdmin, 0, len(rows))
+	for _, row := range rows {
+		unitAliases := byUnit[row.ID]
+		if unitAliases == nil {
+			unitAliases = []api_models.UnitAlias{}
+		}
+		units = append(units, api_models.UnitOfMeasurementAdmin{
+			ID:                    row.ID,
+			NormalizedName:        row.NormalizedName,
+			FullName:              nullStringPtr(row.FullName),
+			Description:           nullStringPtr(row.Description),
+			Aliases:               unitAliases,
+			PositionItemsCount:    row.PositionItemsCount,
+			CatalogPositionsCount: row.CatalogPositionsCount,
+		})
+	}
+	return &api_models.ListUnitsResponse{Units: units}, nil
+}
+
+// CreateUnit создаёт каноническую единицу вместе с синонимами в одной транзакции.
+// Ни имя, ни синонимы не должны совпадать с уже известными написаниями.
+func (s *UnitService) CreateUnit(ctx context.Context, req api_models.CreateUnitRequest) (*api_models.UnitOfMeasurementAdmin, error) {
+	name, err := normalizeName(req.Name, "name")
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := make([]string, 0, len(req.Aliases))
+	seen := map[string]bool{name: true}
+	for _, raw := range req.Aliases {
+		alias, err := normalizeName(raw, "aliases")
+		if err != nil {
+			return nil, err
+		}
+		if seen[alias] {
+			continue
+		}
+		seen[alias] = true
+		aliases = append(aliases, alias)
+	}
+
+	result := &api_models.UnitOfMeasurementAdmin{Aliases: make([]api_models.UnitAlias, 0, len(aliases))}
+	err = s.store.ExecTx(ctx, func(q *db.Queries) error {
+		if err := ensureSpellingFree(ctx, q, name); err != nil {
+			return err
+		}
+		unit, err := q.CreateUnitOfMeasurement(ctx, db.CreateUnitOfMeasurementParams{
+			NormalizedName: name,
+			FullName:       nullString(req.FullName),
+			Description:    nullString(req.Description),
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка создания единицы измерения: %w", err)
+		}
+		result.ID = unit.ID
+		result.NormalizedName = unit.NormalizedName
+		result.FullName = nullStringPtr(unit.FullName)
+		result.Description = nullStringPtr(unit.Description)
+
+		for _, alias := range aliases {
+			created, err := createAlias(ctx, q, unit.ID, alias)
+			if err != nil {
+				return err
+			}
+			result.Aliases = append(result.Aliases, created)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Создана единица измерения '%s' (id=%d), синонимов: %d", result.NormalizedName, result.ID, len(result.Aliases))
+	return result, nil
+}
+
+// AddAlias добавляет синоним существующей единице.
+func (s *UnitService) AddAlias(ctx context.Context, unitID int64, rawAlias string) (*api_models.UnitAlias, error) {
+	if unitID <= 0 {
+		return nil, apierrors.NewValidationError("request.id_positive_got", unitID)
+	}
+	alias, err := normalizeName(rawAlias, "alias")
+	if err != nil {
+		return nil, err
+	}
+
+	var result api_models.UnitAlias
+	err = s.store.ExecTx(ctx, func(q *db.Queries) error {
+		if _, err := q.GetUnitOfMeasurementByID(ctx, unitID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return apierrors.NewNotFoundError("units.not_found", unitID)
+			}
+			return fmt.Errorf("ошибка получения единицы измерения %d: %w", unitID, err)
+		}
+		if err := ensureSpellingFree(ctx, q, alias); err != nil {
+			return err
+		}
+		result, err = createAlias(ctx, q, unitID, alias)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Единице измерения id=%d добавлен синоним '%s'", unitID, alias)
+	return &result, nil
+}
+
+// DeleteAlias удаляет синоним единицы. Уже импортированные позиции не меняются:
+// новые импорты с этим написанием создадут отдельную единицу.
+func (s *UnitService) DeleteAlias(ctx context.Context, unitID, aliasID int64) error {
+	if unitID <= 0 || aliasID <= 0 {
+		return apierrors.NewValidationError("units.ids_positive")
+	}
+
+	deleted, err := s.store.DeleteUnitAlias(ctx, db.DeleteUnitAliasParams{ID: aliasID, UnitID: unitID})
+	if err != nil {
+		return fmt.Errorf("ошибка удаления синонима %d: %w", aliasID, err)
+	}
+	if deleted == 0 {
+		return apierrors.NewNotFoundError("units.alias_not_found", aliasID, unitID)
+	}
+
+	s.logger.Infof("Удалён синоним id=%d единицы измерения id=%d", aliasID, unitID)
+	return nil
+}
+
+// MergeUnits переносит позиции КП, позиции каталога и синонимы дубликата на
+// основную единицу, удаляет дубликат и добавляет его имя синонимом основной —
+// всё в одной транзакции. Если в каталоге есть одноимённые позиции в обеих
+// единицах, слияние отклоняется (ConflictError со списком позиций каталога):
+// их нужно сначала объединить слиянием каталога.
+func (s *UnitService) MergeUnits(
+	ctx context.Context,
+	req api_models.MergeUnitsRequest,
+	mergedBy int64,
+) (*api_models.MergeUnitsResponse, error) {
+	logger := s.logger.WithField("method", "MergeUnits")
+
+	if req.MasterID <= 0 || req.DuplicateID <= 0 {
+		return nil, apierrors.NewValidationError("common.merge_ids_positive")
+	}
+	if req.MasterID == req.DuplicateID {
+		return nil, apierrors.NewValidationError("common.merge_ids_distinct")
+	}
+
+	response := &api_models.MergeUnitsResponse{
+		MasterID:    req.MasterID,
+		DuplicateID: req.DuplicateID,
+	}
+
+	err := s.store.ExecTx(ctx, func(q *db.Queries) error {
+		// Блокируем в порядке id, чтобы встречные слияния не взаимоблокировались
+		first, second := req.MasterID, req.DuplicateID
+		if first > second {
+			first, second = second, first
+		}
+		locked := make(map[int64]db.UnitsOfMeasurement, 2)
+		for _, id := range []int64{first, second} {
+			u, err := q.GetUnitOfMeasurementByIDForUpdate(ctx, id)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return apierrors.NewNotFoundError("units.not_found", id)
+				}
+				return fmt.Errorf("ошибка блокировки единицы измерения %d: %w", id, err)
+			}
+			locked[id] = u
+		}
+
+		conflicts, err := q.ListUnitMergeConflictCatalogPositions(ctx, db.ListUnitMergeConflictCatalogPositionsParams{
+			MasterID:    req.MasterID,
+			DuplicateID: req.DuplicateID,
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка проверки позиций каталога: %w", err)
+		}
+		if len(conflicts) > 0 {
+			return apierrors.NewConflictErrorf(map[string][]int64{"catalog_position_ids": conflicts}, "units.merge_catalog_conflict", len(conflicts))
+		}
+
+		response.MovedPositionItems, err = q.ReassignUnitPositionItems(ctx, db.ReassignUnitPositionItemsParams{
+			MasterID:    req.MasterID,
+			DuplicateID: req.DuplicateID,
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка переноса позиций КП: %w", err)
+		}
+
+		response.MovedCatalogPositions, err = q.ReassignUnitCatalogPositions(ctx, db.ReassignUnitCatalogPositionsParams{
+			MasterID:    req.MasterID,
+			DuplicateID: req.DuplicateID,
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка переноса позиций каталога: %w", err)
+		}
+
+		response.MovedAliases, err = q.ReassignUnitAliases(ctx, db.ReassignUnitAliasesParams{
+			MasterID:    req.MasterID,
+			DuplicateID: req.DuplicateID,
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка переноса синонимов: %w", err)
+		}
+
+		if err := q.DeleteUnitOfMeasurement(ctx, req.DuplicateID); err != nil {
+			return fmt.Errorf("ошибка удаления дубликата: %w", err)
+		}
+
+		// Имя дубликата освободилось — теперь это синоним основной единицы
+		duplicateName := locked[req.DuplicateID].NormalizedName
+		if _, err := createAlias(ctx, q, req.MasterID, duplicateName); err != nil {
+			return err
+		}
+		response.AddedAlias = duplicateName
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Единица измерения %d слита в %d (администратор id=%d): перенесено %d позиций КП, %d позиций каталога, %d синонимов",
+		req.DuplicateID, req.MasterID, mergedBy, response.MovedPositionItems, response.MovedCatalogPositions, response.MovedAliases)
+	return response, nil
+}
+
+// ensureSpellingFree проверяет, что написание не занято ни единицей, ни синонимом.
+// Совпадение с именем единицы означает дубликат — его нужно сливать, а не заводить синоним.
+func ensureSpellingFree(ctx context.Context, q db.Querier, name string) error {
+	unit, err := q.GetUnitOfMeasurementByNormalizedName(ctx, name)
+	if err == nil {
+		return apierrors.NewConflictErrorf(map[string][]int64{"unit_ids": {unit.ID}}, "units.already_exists", name)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("ошибка поиска единицы измерения '%s': %w", name, err)
+	}
+
+	unit, err = q.GetUnitOfMeasurementByAlias(ctx, name)
+	if err == nil {
+		return apierrors.NewConflictErrorf(map[string][]int64{"unit_ids": {unit.ID}}, "units.alias_exists", name, unit.NormalizedName)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("ошибка поиска синонима '%s': %w", name, err)
+	}
+	return nil
+}
+
+// createAlias сохраняет синоним и переводит его в модель ответа.
+func createAlias(ctx context.Context, q db.Querier, unitID int64, alias string) (api_models.UnitAlias, error) {
+	created, err := q.CreateUnitAlias(ctx, db.CreateUnitAliasParams{Alias: alias, UnitID: unitID})
+	if err != nil {
+		return api_models.UnitAlias{}, fmt.Errorf("ошибка сохранения синонима '%s': %w", alias, err)
+	}
+	return api_models.UnitAlias{ID: created.ID, Alias: created.Alias}, nil
+}
+
+// normalizeName приводит написание к ключу справочника и проверяет его длину.
+func normalizeName(raw, field string) (string, error) {
+	name := entities.NormalizeUnitName(raw)
+	if name == "" {
+		return "", apierrors.NewValidationError("units.field_empty", field)
+	}
+	if utf8.RuneCountInString(name) > maxNameLength {
+		return "", apierrors.NewValidationError("units.field_too_long", field, maxNameLength, name)
+	}
+	return name, nil
+}
+
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+func nullStringPtr(ns sql.NullString) *string {
+	if !ns.Valid {
+		return nil
+	}
+	return &ns.String
+}
@@ -0,0 +1,282 @@
+package units
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR UNIT DICTIONARY (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Split units — "м2", "м.кв" and "кв.м" live as three units, so prices of one
+   job are compared across different catalog positions
+2. Recurring duplicates — after a merge the next import must not recreate the
+   removed spelling
+3. Broken catalog — a merge must not create two catalog positions with the same
+   title and unit, and must be all-or-nothing
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: ListUnits
+- GIVEN units and aliases
+  WHEN ListUnits is called
+  THEN aliases are attached to their units, a unit without aliases gets []
+
+SCENARIO 2: CreateUnit / AddAlias
+- GIVEN a name and aliases with different case and spaces
+  WHEN CreateUnit is called
+  THEN everything is normalized, duplicates are dropped, unit and aliases are created in one tx
+- GIVEN an alias that is already a unit name → ConflictError (merge instead)
+- GIVEN an alias of an unknown unit → NotFoundError
+
+SCENARIO 3: MergeUnits
+- GIVEN two units without conflicting catalog positions
+  WHEN MergeUnits is called
+  THEN position items, catalog positions and aliases are moved, the duplicate is
+  deleted and its name becomes an alias of the master
+- GIVEN catalog positions with the same title in both units
+  THEN ConflictError with catalog position ids, nothing is moved
+- GIVEN master == duplicate → ValidationError without ExecTx
+
+SCENARIO 4: DeleteAlias
+- GIVEN an alias that does not belong to the unit → NotFoundError
+*/
+
+var (
+	unitColumns  = []string{"id", "normalized_name", "full_name", "description", "created_at", "updated_at"}
+	aliasColumns = []string{"id", "alias", "unit_id", "created_at"}
+)
+
+func setupTestService(t *testing.T) (*UnitService, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	return NewUnitService(mockStore, testutil.NewMockLogger()), mockStore
+}
+
+// execTxDoAndReturn выполняет callback ExecTx на *db.Queries поверх go-sqlmock.
+func execTxDoAndReturn(t *testing.T, setupFn func(mock sqlmock.Sqlmock)) func(ctx context.Context, fn func(*db.Queries) error) error {
+	t.Helper()
+	return func(ctx context.Context, fn func(*db.Queries) error) error {
+		sqlDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, mock.ExpectationsWereMet(), "sqlmock: unmet expectations")
+			sqlDB.Close()
+		}()
+		setupFn(mock)
+		return fn(db.New(sqlDB))
+	}
+}
+
+// expectSpellingFree — написание не занято ни единицей, ни синонимом.
+func expectSpellingFree(mock sqlmock.Sqlmock, name string) {
+	mock.ExpectQuery("FROM units_of_measurement").WithArgs(name).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("FROM unit_aliases").WithArgs(name).WillReturnError(sql.ErrNoRows)
+}
+
+func expectCreateAlias(mock sqlmock.Sqlmock, id int64, alias string, unitID int64) {
+	mock.ExpectQuery("INSERT INTO unit_aliases").
+		WithArgs(alias, unitID).
+		WillReturnRows(sqlmock.NewRows(aliasColumns).AddRow(id, alias, unitID, time.Now()))
+}
+
+func TestListUnits_AttachesAliases(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ListUnitsWithUsage(gomock.Any()).Return([]db.ListUnitsWithUsageRow{
+		{ID: 1, NormalizedName: "м2", FullName: sql.NullString{String: "Квадратный метр", Valid: true}, PositionItemsCount: 40, CatalogPositionsCount: 3},
+		{ID: 2, NormalizedName: "шт"},
+	}, nil)
+	mockStore.EXPECT().ListUnitAliases(gomock.Any()).Return([]db.UnitAlias{
+		{ID: 10, Alias: "кв.м", UnitID: 1},
+		{ID: 11, Alias: "м.кв", UnitID: 1},
+	}, nil)
+
+	resp, err := service.ListUnits(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resp.Units, 2)
+	assert.Equal(t, []api_models.UnitAlias{{ID: 10, Alias: "кв.м"}, {ID: 11, Alias: "м.кв"}}, resp.Units[0].Aliases)
+	assert.Equal(t, "Квадратный метр", *resp.Units[0].FullName)
+	assert.Equal(t, int64(40), resp.Units[0].PositionItemsCount)
+	assert.NotNil(t, resp.Units[1].Aliases)
+	assert.Empty(t, resp.Units[1].Aliases)
+}
+
+func TestCreateUnit_NormalizesAndCreatesAliases(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	fullName := "Квадратный метр"
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			expectSpellingFree(mock, "м2")
+			mock.ExpectQuery("INSERT INTO units_of_measurement").
+				WithArgs("м2", fullName, nil).
+				WillReturnRows(sqlmock.NewRows(unitColumns).
+					AddRow(int64(5), "м2", fullName, nil, time.Now(), time.Now()))
+			expectSpellingFree(mock, "кв.м")
+			expectCreateAlias(mock, 20, "кв.м", 5)
+			expectSpellingFree(mock, "м.кв")
+			expectCreateAlias(mock, 21, "м.кв", 5)
+		}),
+	)
+
+	resp, err := service.CreateUnit(context.Background(), api_models.CreateUnitRequest{
+		Name:     " М2 ",
+		FullName: &fullName,
+		Aliases:  []string{"КВ.М", "кв.м ", "м.кв", "м2"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), resp.ID)
+	assert.Equal(t, "м2", resp.NormalizedName)
+	assert.Equal(t, []api_models.UnitAlias{{ID: 20, Alias: "кв.м"}, {ID: 21, Alias: "м.кв"}}, resp.Aliases)
+}
+
+func TestCreateUnit_EmptyName_NoTx(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.CreateUnit(context.Background(), api_models.CreateUnitRequest{Name: "   "})
+
+	var validationErr *apierrors.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+}
+
+func TestAddAlias_SpellingIsUnitName_ReturnsConflict(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("FROM units_of_measurement").
+				WithArgs(int64(5)).
+				WillReturnRows(sqlmock.NewRows(unitColumns).AddRow(int64(5), "м2", nil, nil, time.Now(), time.Now()))
+			mock.ExpectQuery("FROM units_of_measurement").
+				WithArgs("м.кв").
+				WillReturnRows(sqlmock.NewRows(unitColumns).AddRow(int64(7), "м.кв", nil, nil, time.Now(), time.Now()))
+		}),
+	)
+
+	_, err := service.AddAlias(context.Background(), 5, "М.кв")
+
+	var conflictErr *apierrors.ConflictError
+	require.True(t, errors.As(err, &conflictErr))
+	assert.Equal(t, map[string][]int64{"unit_ids": {7}}, conflictErr.Conflicts)
+}
+
+func TestAddAlias_UnitNotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("FROM units_of_measurement").
+				WithArgs(int64(404)).
+				WillReturnError(sql.ErrNoRows)
+		}),
+	)
+
+	_, err := service.AddAlias(context.Background(), 404, "шт.")
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+}
+
+func TestDeleteAlias_NotOwnedByUnit_ReturnsNotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().DeleteUnitAlias(gomock.Any(), db.DeleteUnitAliasParams{ID: 20, UnitID: 6}).Return(int64(0), nil)
+
+	err := service.DeleteAlias(context.Background(), 6, 20)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+}
+
+func TestMergeUnits_Success(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			// Блокировка в порядке id: сначала 3 (основная), затем 8 (дубликат)
+			mock.ExpectQuery("FOR UPDATE").WithArgs(int64(3)).
+				WillReturnRows(sqlmock.NewRows(unitColumns).AddRow(int64(3), "м2", nil, nil, time.Now(), time.Now()))
+			mock.ExpectQuery("FOR UPDATE").WithArgs(int64(8)).
+				WillReturnRows(sqlmock.NewRows(unitColumns).AddRow(int64(8), "м.кв", nil, nil, time.Now(), time.Now()))
+			mock.ExpectQuery("SELECT dcp.id").
+				WithArgs(int64(3), int64(8)).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			mock.ExpectExec("UPDATE position_items").
+				WithArgs(int64(3), int64(8)).
+				WillReturnResult(sqlmock.NewResult(0, 12))
+			mock.ExpectExec("UPDATE catalog_positions").
+				WithArgs(int64(3), int64(8)).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+			mock.ExpectExec("UPDATE unit_aliases").
+				WithArgs(int64(3), int64(8)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec("DELETE FROM units_of_measurement").
+				WithArgs(int64(8)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			expectCreateAlias(mock, 30, "м.кв", 3)
+		}),
+	)
+
+	resp, err := service.MergeUnits(context.Background(), api_models.MergeUnitsRequest{MasterID: 3, DuplicateID: 8}, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, &api_models.MergeUnitsResponse{
+		MasterID:              3,
+		DuplicateID:           8,
+		AddedAlias:            "м.кв",
+		MovedPositionItems:    12,
+		MovedCatalogPositions: 2,
+		MovedAliases:          1,
+	}, resp)
+}
+
+func TestMergeUnits_CatalogConflict_ReturnsConflict(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		execTxDoAndReturn(t, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("FOR UPDATE").WithArgs(int64(3)).
+				WillReturnRows(sqlmock.NewRows(unitColumns).AddRow(int64(3), "м2", nil, nil, time.Now(), time.Now()))
+			mock.ExpectQuery("FOR UPDATE").WithArgs(int64(8)).
+				WillReturnRows(sqlmock.NewRows(unitColumns).AddRow(int64(8), "м.кв", nil, nil, time.Now(), time.Now()))
+			mock.ExpectQuery("SELECT dcp.id").
+				WithArgs(int64(3), int64(8)).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(41)).AddRow(int64(42)))
+		}),
+	)
+
+	_, err := service.MergeUnits(context.Background(), api_models.MergeUnitsRequest{MasterID: 3, DuplicateID: 8}, 1)
+
+	var conflictErr *apierrors.ConflictError
+	require.True(t, errors.As(err, &conflictErr))
+	assert.Equal(t, map[string][]int64{"catalog_position_ids": {41, 42}}, conflictErr.Conflicts)
+}
+
+func TestMergeUnits_SameID_NoTx(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.MergeUnits(context.Background(), api_models.MergeUnitsRequest{MasterID: 3, DuplicateID: 3}, 1)
+
+	var validationErr *apierrors.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+}
@@ -0,0 +1,169 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/retry"
+)
+
+const (
+	// maxErrorLength ограничивает last_error в журнале доставок.
+	maxErrorLength = 1000
+	// maxResponseDrain — сколько байт ответа подписчика дочитывается, чтобы переиспользовать соединение.
+	maxResponseDrain = 64 << 10
+)
+
+// Run периодически отправляет доставки из очереди до отмены ctx.
+// Полная пачка означает, что в очереди могут остаться доставки, поэтому
+// следующая пачка забирается сразу, не дожидаясь тика.
+// Предназначен для запуска в отдельной горутине.
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	logger := s.logger.WithField("method", "Run")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				processed, err := s.DeliverDue(ctx)
+				if err != nil {
+					logger.Errorf("Не удалось обработать очередь вебхуков: %v", err)
+					break
+				}
+				if processed < int(s.cfg.BatchSize) || ctx.Err() != nil {
+					break
+				}
+			}
+		}
+	}
+}
+
+// DeliverDue забирает пачку доставок, срок которых наступил, и отправляет их.
+// Возвращает число обработанных доставок (успешных и неуспешных).
+func (s *Service) DeliverDue(ctx context.Context) (int, error) {
+	// Доставки пачки отправляются последовательно; аренда покрывает худший случай,
+	// когда каждый запрос упирается в таймаут.
+	lease := time.Duration(s.cfg.BatchSize) * s.cfg.RequestTimeout
+	deliveries, err := s.store.ClaimDueWebhookDeliveries(ctx, db.ClaimDueWebhookDeliveriesParams{
+		LeasedUntil: time.Now().Add(lease),
+		BatchSize:   s.cfg.BatchSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("ошибка ClaimDueWebhookDeliveries: %w", err)
+	}
+
+	for _, d := range deliveries {
+		if err := s.deliver(ctx, d); err != nil {
+			return 0, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// deliver отправляет одну доставку и записывает результат попытки.
+func (s *Service) deliver(ctx context.Context, d db.ClaimDueWebhookDeliveriesRow) error {
+	logger := s.logger.WithField("delivery_id", d.ID).WithField("webhook_id", d.SubscriptionID)
+
+	statusCode, sendErr := s.send(ctx, d)
+	if sendErr == nil {
+		if err := s.store.MarkWebhookDeliveryDelivered(ctx, db.MarkWebhookDeliveryDeliveredParams{
+			ResponseStatus: int32(statusCode),
+			ID:             d.ID,
+		}); err != nil {
+			return fmt.Errorf("ошибка MarkWebhookDeliveryDelivered(%d): %w", d.ID, err)
+		}
+		return nil
+	}
+
+	attempt := d.Attempts + 1
+	params := db.MarkWebhookDeliveryAttemptFailedParams{
+		Status:        StatusPending,
+		NextAttemptAt: time.Now().Add(s.backoff(attempt)),
+		LastError:     truncateError(sendErr.Error()),
+		ID:            d.ID,
+	}
+	if statusCode != 0 {
+		params.ResponseStatus = sql.NullInt32{Int32: int32(statusCode), Valid: true}
+	}
+	if attempt >= s.cfg.MaxAttempts {
+		params.Status = StatusFailed
+		logger.Warnf("Доставка %s на %s не удалась после %d попыток: %v", d.Event, d.Url, attempt, sendErr)
+	} else {
+		logger.Infof("Попытка %d доставки %s на %s не удалась, повтор в %s: %v",
+			attempt, d.Event, d.Url, params.NextAttemptAt.Format(time.RFC3339), sendErr)
+	}
+
+	if err := s.store.MarkWebhookDeliveryAttemptFailed(ctx, params); err != nil {
+		return fmt.Errorf("ошибка MarkWebhookDeliveryAttemptFailed(%d): %w", d.ID, err)
+	}
+	return nil
+}
+
+// send выполняет POST подписчику. Возвращает HTTP-код (0 при сетевой ошибке)
+// и ошибку, если ответ не 2xx.
+func (s *Service) send(ctx context.Context, d db.ClaimDueWebhookDeliveriesRow) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Url, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("некорректный запрос: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "tenders-go-webhooks")
+	req.Header.Set("X-Webhook-Event", d.Event)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatInt(d.ID, 10))
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+sign(d.Secret, timestamp, d.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseDrain))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("подписчик ответил %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// sign возвращает hex(HMAC-SHA256(secret, timestamp + "." + body)).
+// Timestamp входит в подпись, чтобы подписчик мог отклонять повторно
+// отправленные перехваченные запросы.
+func sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// backoff возвращает задержку перед повтором после attempt-й неуспешной попытки
+// (retry.Backoff от RetryBaseDelay до RetryMaxDelay).
+func (s *Service) backoff(attempt int32) time.Duration {
+	return retry.Backoff(int(attempt), s.cfg.RetryBaseDelay, s.cfg.RetryMaxDelay)
+}
+
+func truncateError(msg string) string {
+	if len(msg) <= maxErrorLength {
+		return msg
+	}
+	return strings.ToValidUTF8(msg[:maxErrorLength], "")
+}
@@ -0,0 +1,150 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func claimed(id int64, target string, attempts int32) db.ClaimDueWebhookDeliveriesRow {
+	return db.ClaimDueWebhookDeliveriesRow{
+		ID:             id,
+		SubscriptionID: 1,
+		Event:          string(EventTenderImported),
+		Payload:        []byte(`{"event":"tender.imported","data":{"tender_db_id":42}}`),
+		Attempts:       attempts,
+		Url:            target,
+		Secret:         testSecret,
+	}
+}
+
+func TestDeliverDue_SignsAndMarksDelivered(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		// THEN: подписчик может проверить подпись своим секретом
+		timestamp := r.Header.Get("X-Webhook-Timestamp")
+		assert.Equal(t, "sha256="+sign(testSecret, timestamp, body), r.Header.Get("X-Webhook-Signature"))
+		assert.Equal(t, "tender.imported", r.Header.Get("X-Webhook-Event"))
+		assert.Equal(t, "11", r.Header.Get("X-Webhook-Delivery"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer subscriber.Close()
+
+	mockStore.EXPECT().ClaimDueWebhookDeliveries(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.ClaimDueWebhookDeliveriesParams) ([]db.ClaimDueWebhookDeliveriesRow, error) {
+			assert.Equal(t, int32(10), arg.BatchSize)
+			assert.True(t, arg.LeasedUntil.After(time.Now()), "claimed deliveries are leased")
+			return []db.ClaimDueWebhookDeliveriesRow{claimed(11, subscriber.URL, 0)}, nil
+		})
+	mockStore.EXPECT().MarkWebhookDeliveryDelivered(gomock.Any(), db.MarkWebhookDeliveryDeliveredParams{
+		ResponseStatus: http.StatusAccepted,
+		ID:             11,
+	}).Return(nil)
+
+	processed, err := service.DeliverDue(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+}
+
+func TestDeliverDue_FailureSchedulesRetry(t *testing.T) {
+	cases := map[string]http.HandlerFunc{
+		"server error": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		},
+		"redirect is not followed": func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "https://elsewhere.example.com/", http.StatusFound)
+		},
+	}
+	for name, handler := range cases {
+		t.Run(name, func(t *testing.T) {
+			service, mockStore := setupTestService(t)
+			subscriber := httptest.NewServer(handler)
+			defer subscriber.Close()
+
+			mockStore.EXPECT().ClaimDueWebhookDeliveries(gomock.Any(), gomock.Any()).
+				Return([]db.ClaimDueWebhookDeliveriesRow{claimed(11, subscriber.URL, 1)}, nil)
+			mockStore.EXPECT().MarkWebhookDeliveryAttemptFailed(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, arg db.MarkWebhookDeliveryAttemptFailedParams) error {
+					assert.Equal(t, int64(11), arg.ID)
+					assert.Equal(t, StatusPending, arg.Status)
+					assert.True(t, arg.ResponseStatus.Valid)
+					assert.NotEmpty(t, arg.LastError)
+					// Вторая неуспешная попытка: задержка удвоена
+					assert.WithinDuration(t, time.Now().Add(time.Minute), arg.NextAttemptAt, 5*time.Second)
+					return nil
+				})
+
+			_, err := service.DeliverDue(context.Background())
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDeliverDue_LastAttemptMarksFailed(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	// Сетевая ошибка: сервер закрыт до отправки
+	subscriber := httptest.NewServer(http.NotFoundHandler())
+	target := subscriber.URL
+	subscriber.Close()
+
+	mockStore.EXPECT().ClaimDueWebhookDeliveries(gomock.Any(), gomock.Any()).
+		Return([]db.ClaimDueWebhookDeliveriesRow{claimed(11, target, 2)}, nil)
+	mockStore.EXPECT().MarkWebhookDeliveryAttemptFailed(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.MarkWebhookDeliveryAttemptFailedParams) error {
+			assert.Equal(t, StatusFailed, arg.Status, "max_attempts=3 reached")
+			assert.False(t, arg.ResponseStatus.Valid, "no HTTP status on a network error")
+			return nil
+		})
+
+	_, err := service.DeliverDue(context.Background())
+
+	require.NoError(t, err)
+}
+
+func TestBackoff(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	expected := map[int32]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		3:  2 * time.Minute,
+		7:  32 * time.Minute,
+		8:  time.Hour, // 64 минуты — упирается в retry_max_delay
+		60: time.Hour,
+	}
+	for attempt, delay := range expected {
+		assert.Equal(t, delay, service.backoff(attempt), "attempt %d", attempt)
+	}
+}
+
+func TestTruncateError(t *testing.T) {
+	long := make([]byte, 0, maxErrorLength+10)
+	for len(long) < maxErrorLength-1 {
+		long = append(long, 'a')
+	}
+	long = append(long, "жжж"...) // многобайтовый символ на границе обрезки
+
+	truncated := truncateError(string(long))
+
+	assert.LessOrEqual(t, len(truncated), maxErrorLength)
+	assert.Equal(t, maxErrorLength-1, len(truncated), "a split rune is dropped")
+}
@@ -0,0 +1,87 @@
+package webhooks
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+)
+
+// Event — тип события, на который подписывается внешняя система.
+type Event string
+
+const (
+	// Импорт тендера завершён (POST /internal/worker/import-tender)
+	EventTenderImported Event = "tender.imported"
+	// Получены результаты AI-анализа лота
+	EventLotAIResults Event = "lot.ai_results"
+	// Назначен победитель лота
+	EventWinnerSet Event = "winner.set"
+)
+
+// allEvents совпадает с CHECK chk_webhook_subscriptions_events (миграция 000022).
+var allEvents = []Event{EventLotAIResults, EventTenderImported, EventWinnerSet}
+
+// TenderImportedData — data события tender.imported.
+type TenderImportedData struct {
+	TenderDBID int64            `json:"tender_db_id"`
+	TenderID   string           `json:"tender_id"` // ID тендера на ЭТП
+	ImportID   int64            `json:"import_id,omitempty"`
+	LotIDsMap  map[string]int64 `json:"lot_ids_map"`
+}
+
+// LotAIResultsData — data события lot.ai_results.
+type LotAIResultsData struct {
+	LotID              int64                  `json:"lot_id"`
+	LotKeyParameters   map[string]interface{} `json:"lot_key_parameters"`
+	AIAnalysisResultID int64                  `json:"ai_analysis_result_id"` // Запуск в ai_analysis_results
+}
+
+// WinnerSetData — data события winner.set.
+type WinnerSetData struct {
+	WinnerID   int64   `json:"winner_id"`
+	LotID      int64   `json:"lot_id"`
+	ProposalID int64   `json:"proposal_id"`
+	Rank       int32   `json:"rank"`
+	AwardPrice *string `json:"award_price,omitempty"`
+}
+
+// normalizeEvents проверяет события из запроса и возвращает их без повторов в алфавитном порядке.
+func normalizeEvents(raw []string) ([]string, error) {
+	if len(raw) == 0 {
+		return nil, apierrors.NewValidationError("webhooks.events_empty", eventsList())
+	}
+
+	seen := make(map[string]struct{}, len(raw))
+	events := make([]string, 0, len(raw))
+	for _, e := range raw {
+		e = strings.TrimSpace(e)
+		if !isKnownEvent(e) {
+			return nil, apierrors.NewValidationError("webhooks.invalid_event", e, eventsList())
+		}
+		if _, ok := seen[e]; ok {
+			continue
+		}
+		seen[e] = struct{}{}
+		events = append(events, e)
+	}
+	sort.Strings(events)
+	return events, nil
+}
+
+func isKnownEvent(e string) bool {
+	for _, event := range allEvents {
+		if string(event) == e {
+			return true
+		}
+	}
+	return false
+}
+
+func eventsList() string {
+	names := make([]string, len(allEvents))
+	for i, event := range allEvents {
+		names[i] = string(event)
+	}
+	return strings.Join(names, ", ")
+}
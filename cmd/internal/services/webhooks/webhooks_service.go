@@ -0,0 +1,374 @@
+// Package webhooks доставляет события внешним подписчикам.
+//
+// Подписка (webhook_subscriptions) — URL, набор событий и секрет. Publish
+// ставит событие в очередь webhook_deliveries для всех активных подписок,
+// фоновый воркер (Run) отправляет его POST-запросом с подписью HMAC-SHA256 и
+// повторяет неуспешные доставки с экспоненциальной задержкой. Очередь
+// одновременно служит журналом доставок.
+//
+// # Формат запроса
+//
+// Тело — JSON {"event", "occurred_at", "data"}. Заголовки:
+//
+//	X-Webhook-Event:     tender.imported
+//	X-Webhook-Delivery:  id доставки (одинаков для всех повторов — для дедупликации)
+//	X-Webhook-Timestamp: unix-время отправки
+//	X-Webhook-Signature: sha256=hex(HMAC-SHA256(secret, timestamp + "." + body))
+//
+// Доставка успешна при ответе 2xx; редиректы не выполняются.
+//
+// # Гарантии
+//
+// Доставка — at-least-once: подписчик должен быть готов к повтору с тем же
+// X-Webhook-Delivery. Событие ставится в очередь после фиксации изменения,
+// поэтому при падении API между ними событие может быть потеряно.
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+const (
+	maxURLLength = 2048
+
+	// Статусы доставки (chk_webhook_deliveries_status)
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 200
+)
+
+// Service управляет подписками и доставкой событий.
+type Service struct {
+	store  db.Store
+	logger logging.Logger
+	cfg    config.WebhooksConfig
+	client *http.Client
+}
+
+// NewService создаёт сервис вебхуков.
+func NewService(store db.Store, logger logging.Logger, cfg config.WebhooksConfig) *Service {
+	return &Service{
+		store:  store,
+		logger: logger,
+		cfg:    cfg,
+		client: &http.Client{
+			Timeout: cfg.RequestTimeout,
+			// Редирект подписчика считается неуспешной доставкой: подписанное тело
+			// не должно уходить на адрес, которого администратор не указывал.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// envelope — тело запроса к подписчику.
+type envelope struct {
+	Event      Event     `json:"event"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// Publish ставит событие в очередь доставки всем активным подпискам на него.
+// Отправка выполняется фоновым воркером, поэтому Publish не ждёт подписчиков.
+func (s *Service) Publish(ctx context.Context, event Event, data any) error {
+	payload, err := json.Marshal(envelope{Event: event, OccurredAt: time.Now().UTC(), Data: data})
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации события %s: %w", event, err)
+	}
+
+	queued, err := s.store.EnqueueWebhookDeliveries(ctx, db.EnqueueWebhookDeliveriesParams{
+		Event:   string(event),
+		Payload: payload,
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка EnqueueWebhookDeliveries(%s): %w", event, err)
+	}
+	if queued > 0 {
+		s.logger.Debugf("Событие %s поставлено в очередь для %d подписок", event, queued)
+	}
+	return nil
+}
+
+// CreateSubscription создаёт подписку и генерирует для неё секрет.
+// Секрет возвращается только в этом ответе.
+func (s *Service) CreateSubscription(ctx context.Context, req api_models.CreateWebhookRequest, actorID int64) (*api_models.CreateWebhookResponse, error) {
+	logger := s.logger.WithField("method", "CreateSubscription")
+
+	target, err := validateURL(req.URL)
+	if err != nil {
+		return nil, err
+	}
+	events, err := normalizeEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	description := sql.NullString{}
+	if d := strings.TrimSpace(req.Description); d != "" {
+		description = sql.NullString{String: d, Valid: true}
+	}
+
+	row, err := s.store.CreateWebhookSubscription(ctx, db.CreateWebhookSubscriptionParams{
+		Url:         target,
+		Secret:      secret,
+		Events:      events,
+		Description: description,
+		CreatedBy:   sql.NullInt64{Int64: actorID, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка CreateWebhookSubscription: %w", err)
+	}
+
+	logger.Infof("Создана подписка id=%d на %v → %s (оператор: %d)", row.ID, events, target, actorID)
+	return &api_models.CreateWebhookResponse{
+		WebhookResponse: subscriptionToResponse(
+			row.ID, row.Url, row.Events, row.Description, row.IsActive, row.CreatedBy, row.CreatedAt, row.UpdatedAt,
+		),
+		Secret: secret,
+	}, nil
+}
+
+// ListSubscriptions возвращает все подписки без секретов.
+func (s *Service) ListSubscriptions(ctx context.Context) ([]api_models.WebhookResponse, error) {
+	rows, err := s.store.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ListWebhookSubscriptions: %w", err)
+	}
+
+	result := make([]api_models.WebhookResponse, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, subscriptionToResponse(
+			row.ID, row.Url, row.Events, row.Description, row.IsActive, row.CreatedBy, row.CreatedAt, row.UpdatedAt,
+		))
+	}
+	return result, nil
+}
+
+// UpdateSubscription меняет URL, события, описание или активность подписки.
+// Уже поставленные в очередь доставки уходят на новый URL.
+func (s *Service) UpdateSubscription(ctx context.Context, id int64, req api_models.UpdateWebhookRequest) (*api_models.WebhookResponse, error) {
+	logger := s.logger.WithField("method", "UpdateSubscription").WithField("webhook_id", id)
+
+	if id <= 0 {
+		return nil, apierrors.NewValidationError("request.id_positive_got", id)
+	}
+	if req.URL == nil && req.Events == nil && req.Description == nil && req.IsActive == nil {
+		return nil, apierrors.NewValidationError("request.no_fields_to_update")
+	}
+
+	params := db.UpdateWebhookSubscriptionParams{ID: id}
+	if req.URL != nil {
+		target, err := validateURL(*req.URL)
+		if err != nil {
+			return nil, err
+		}
+		params.Url = sql.NullString{String: target, Valid: true}
+	}
+	if req.Events != nil {
+		events, err := normalizeEvents(*req.Events)
+		if err != nil {
+			return nil, err
+		}
+		params.Events = events
+	}
+	if req.Description != nil {
+		params.Description = sql.NullString{String: strings.TrimSpace(*req.Description), Valid: true}
+	}
+	if req.IsActive != nil {
+		params.IsActive = sql.NullBool{Bool: *req.IsActive, Valid: true}
+	}
+
+	row, err := s.store.UpdateWebhookSubscription(ctx, params)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewNotFoundError("webhooks.subscription_not_found", id)
+		}
+		return nil, fmt.Errorf("ошибка UpdateWebhookSubscription(%d): %w", id, err)
+	}
+
+	logger.Infof("Подписка id=%d обновлена: events=%v, active=%t → %s", row.ID, row.Events, row.IsActive, row.Url)
+	resp := subscriptionToResponse(
+		row.ID, row.Url, row.Events, row.Description, row.IsActive, row.CreatedBy, row.CreatedAt, row.UpdatedAt,
+	)
+	return &resp, nil
+}
+
+// DeleteSubscription удаляет подписку вместе с журналом доставок.
+// Чтобы сохранить журнал, подписку достаточно деактивировать.
+func (s *Service) DeleteSubscription(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return apierrors.NewValidationError("request.id_positive_got", id)
+	}
+
+	deleted, err := s.store.DeleteWebhookSubscription(ctx, id)
+	if err != nil {
+		return fmt.Errorf("ошибка DeleteWebhookSubscription(%d): %w", id, err)
+	}
+	if deleted == 0 {
+		return apierrors.NewNotFoundError("webhooks.subscription_not_found", id)
+	}
+
+	s.logger.Infof("Подписка id=%d удалена", id)
+	return nil
+}
+
+// ListDeliveries возвращает журнал доставок подписки, новые первыми.
+// status — pending, delivered, failed или пустая строка (все).
+// limit <= 0 — значение по умолчанию.
+func (s *Service) ListDeliveries(ctx context.Context, subscriptionID int64, status string, limit, offset int32) (*api_models.WebhookDeliveriesResponse, error) {
+	if subscriptionID <= 0 {
+		return nil, apierrors.NewValidationError("request.id_positive_got", subscriptionID)
+	}
+	statusFilter := sql.NullString{}
+	switch status {
+	case "":
+	case StatusPending, StatusDelivered, StatusFailed:
+		statusFilter = sql.NullString{String: status, Valid: true}
+	default:
+		return nil, apierrors.NewValidationError("webhooks.invalid_status", status, StatusPending, StatusDelivered, StatusFailed)
+	}
+	if limit <= 0 {
+		limit = defaultDeliveriesLimit
+	}
+	if limit > maxDeliveriesLimit {
+		return nil, apierrors.NewValidationError("request.limit_max", maxDeliveriesLimit)
+	}
+	if offset < 0 {
+		return nil, apierrors.NewValidationError("request.offset_negative")
+	}
+
+	exists, err := s.store.WebhookSubscriptionExists(ctx, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка WebhookSubscriptionExists(%d): %w", subscriptionID, err)
+	}
+	if !exists {
+		return nil, apierrors.NewNotFoundError("webhooks.subscription_not_found", subscriptionID)
+	}
+
+	rows, err := s.store.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{
+		SubscriptionID: subscriptionID,
+		Status:         statusFilter,
+		PageLimit:      limit,
+		PageOffset:     offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка ListWebhookDeliveries(%d): %w", subscriptionID, err)
+	}
+
+	deliveries := make([]api_models.WebhookDeliveryResponse, 0, len(rows))
+	for _, row := range rows {
+		deliveries = append(deliveries, deliveryToResponse(row))
+	}
+	return &api_models.WebhookDeliveriesResponse{Deliveries: deliveries, Limit: limit, Offset: offset}, nil
+}
+
+// validateURL проверяет адрес подписчика: абсолютный http(s) URL с хостом.
+func validateURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", apierrors.NewValidationError("webhooks.url_empty")
+	}
+	if len(raw) > maxURLLength {
+		return "", apierrors.NewValidationError("webhooks.url_too_long", maxURLLength)
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", apierrors.NewValidationError("webhooks.url_not_absolute", raw)
+	}
+	return raw, nil
+}
+
+// generateSecret возвращает секрет подписи: 32 случайных байта в hex.
+func generateSecret() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("ошибка генерации секрета: %w", err)
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+func subscriptionToResponse(
+	id int64,
+	target string,
+	events []string,
+	description sql.NullString,
+	isActive bool,
+	createdBy sql.NullInt64,
+	createdAt, updatedAt time.Time,
+) api_models.WebhookResponse {
+	resp := api_models.WebhookResponse{
+		ID:        id,
+		URL:       target,
+		Events:    events,
+		IsActive:  isActive,
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+	}
+	if description.Valid {
+		d := description.String
+		resp.Description = &d
+	}
+	if createdBy.Valid {
+		by := createdBy.Int64
+		resp.CreatedBy = &by
+	}
+	return resp
+}
+
+func deliveryToResponse(row db.WebhookDelivery) api_models.WebhookDeliveryResponse {
+	resp := api_models.WebhookDeliveryResponse{
+		ID:             row.ID,
+		SubscriptionID: row.SubscriptionID,
+		Event:          row.Event,
+		Payload:        row.Payload,
+		Status:         row.Status,
+		Attempts:       row.Attempts,
+		CreatedAt:      row.CreatedAt,
+	}
+	if row.Status == StatusPending {
+		t := row.NextAttemptAt
+		resp.NextAttemptAt = &t
+	}
+	if row.LastAttemptAt.Valid {
+		t := row.LastAttemptAt.Time
+		resp.LastAttemptAt = &t
+	}
+	if row.ResponseStatus.Valid {
+		code := row.ResponseStatus.Int32
+		resp.ResponseStatus = &code
+	}
+	if row.LastError.Valid {
+		e := row.LastError.String
+		resp.LastError = &e
+	}
+	if row.DeliveredAt.Valid {
+		t := row.DeliveredAt.Time
+		resp.DeliveredAt = &t
+	}
+	return resp
+}
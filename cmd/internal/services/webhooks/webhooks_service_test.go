@@ -0,0 +1,250 @@
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR WEBHOOKS (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Misconfiguration — a typo in the URL or event name is rejected, not silently ignored
+2. Secret leakage — the signing secret is shown only when the subscription is created
+3. Slow subscribers — Publish only enqueues, it never waits for HTTP calls
+4. Forged callbacks — every request carries an HMAC signature over timestamp and body
+5. Flaky subscribers — failed deliveries are retried with exponential backoff and
+   give up after max_attempts, leaving a trail in the delivery log
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: CreateSubscription
+- GIVEN an invalid URL or empty/unknown events
+  WHEN CreateSubscription is called
+  THEN ValidationError without DB call
+- GIVEN a valid request
+  WHEN CreateSubscription is called
+  THEN events are de-duplicated and sorted and a random secret is returned
+
+SCENARIO 2: Update / Delete / ListDeliveries
+- GIVEN an unknown subscription id
+  THEN NotFoundError
+- GIVEN an unknown status filter
+  THEN ValidationError without DB call
+
+SCENARIO 3: Publish
+- GIVEN an event with data
+  WHEN Publish is called
+  THEN one enqueue query with the {"event","occurred_at","data"} envelope
+
+SCENARIO 4: DeliverDue (delivery_test.go)
+- GIVEN a 2xx subscriber → delivered, signature verifies with the secret
+- GIVEN a 5xx or redirecting subscriber → pending with the next attempt after backoff
+- GIVEN the last allowed attempt fails → failed
+*/
+
+func testConfig() config.WebhooksConfig {
+	return config.WebhooksConfig{
+		DeliveryInterval: time.Second,
+		BatchSize:        10,
+		RequestTimeout:   2 * time.Second,
+		MaxAttempts:      3,
+		RetryBaseDelay:   30 * time.Second,
+		RetryMaxDelay:    time.Hour,
+	}
+}
+
+func setupTestService(t *testing.T) (*Service, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	return NewService(mockStore, testutil.NewMockLogger(), testConfig()), mockStore
+}
+
+func TestCreateSubscription_ValidationErrors(t *testing.T) {
+	cases := map[string]api_models.CreateWebhookRequest{
+		"empty url":      {URL: "", Events: []string{"winner.set"}},
+		"relative url":   {URL: "/hooks", Events: []string{"winner.set"}},
+		"ftp url":        {URL: "ftp://example.com/hooks", Events: []string{"winner.set"}},
+		"no events":      {URL: "https://example.com/hooks"},
+		"unknown event":  {URL: "https://example.com/hooks", Events: []string{"tender.deleted"}},
+		"host-less http": {URL: "http://", Events: []string{"winner.set"}},
+	}
+	for name, req := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Store без ожиданий: любой вызов БД провалит тест
+			service, _ := setupTestService(t)
+
+			resp, err := service.CreateSubscription(context.Background(), req, 1)
+
+			assert.Nil(t, resp)
+			var validationErr *apierrors.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+		})
+	}
+}
+
+func TestCreateSubscription_ReturnsSecret(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	now := time.Now()
+
+	var stored db.CreateWebhookSubscriptionParams
+	mockStore.EXPECT().CreateWebhookSubscription(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateWebhookSubscriptionParams) (db.CreateWebhookSubscriptionRow, error) {
+			stored = arg
+			return db.CreateWebhookSubscriptionRow{
+				ID:        5,
+				Url:       arg.Url,
+				Events:    arg.Events,
+				IsActive:  true,
+				CreatedBy: arg.CreatedBy,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}, nil
+		})
+
+	resp, err := service.CreateSubscription(context.Background(), api_models.CreateWebhookRequest{
+		URL:    " https://erp.example.com/hooks ",
+		Events: []string{"winner.set", "tender.imported", "winner.set"},
+	}, 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://erp.example.com/hooks", stored.Url)
+	assert.Equal(t, []string{"tender.imported", "winner.set"}, stored.Events)
+	assert.Equal(t, sql.NullInt64{Int64: 7, Valid: true}, stored.CreatedBy)
+	assert.False(t, stored.Description.Valid)
+
+	assert.Equal(t, int64(5), resp.ID)
+	assert.Len(t, resp.Secret, 64)
+	assert.Equal(t, stored.Secret, resp.Secret)
+	require.NotNil(t, resp.CreatedBy)
+	assert.Equal(t, int64(7), *resp.CreatedBy)
+}
+
+func TestUpdateSubscription_NotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	active := false
+
+	mockStore.EXPECT().UpdateWebhookSubscription(gomock.Any(), db.UpdateWebhookSubscriptionParams{
+		ID:       9,
+		IsActive: sql.NullBool{Bool: false, Valid: true},
+	}).Return(db.UpdateWebhookSubscriptionRow{}, sql.ErrNoRows)
+
+	resp, err := service.UpdateSubscription(context.Background(), 9, api_models.UpdateWebhookRequest{IsActive: &active})
+
+	assert.Nil(t, resp)
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestUpdateSubscription_EmptyRequest(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.UpdateSubscription(context.Background(), 9, api_models.UpdateWebhookRequest{})
+
+	var validationErr *apierrors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestDeleteSubscription_NotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	mockStore.EXPECT().DeleteWebhookSubscription(gomock.Any(), int64(9)).Return(int64(0), nil)
+
+	err := service.DeleteSubscription(context.Background(), 9)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestListDeliveries(t *testing.T) {
+	t.Run("unknown status", func(t *testing.T) {
+		service, _ := setupTestService(t)
+
+		_, err := service.ListDeliveries(context.Background(), 1, "lost", 0, 0)
+
+		var validationErr *apierrors.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("unknown subscription", func(t *testing.T) {
+		service, mockStore := setupTestService(t)
+		mockStore.EXPECT().WebhookSubscriptionExists(gomock.Any(), int64(1)).Return(false, nil)
+
+		_, err := service.ListDeliveries(context.Background(), 1, "", 0, 0)
+
+		var notFoundErr *apierrors.NotFoundError
+		assert.ErrorAs(t, err, &notFoundErr)
+	})
+
+	t.Run("filters by status with default limit", func(t *testing.T) {
+		service, mockStore := setupTestService(t)
+		now := time.Now()
+		mockStore.EXPECT().WebhookSubscriptionExists(gomock.Any(), int64(1)).Return(true, nil)
+		mockStore.EXPECT().ListWebhookDeliveries(gomock.Any(), db.ListWebhookDeliveriesParams{
+			SubscriptionID: 1,
+			Status:         sql.NullString{String: StatusFailed, Valid: true},
+			PageLimit:      defaultDeliveriesLimit,
+			PageOffset:     0,
+		}).Return([]db.WebhookDelivery{{
+			ID:             3,
+			SubscriptionID: 1,
+			Event:          "winner.set",
+			Payload:        json.RawMessage(`{"event":"winner.set"}`),
+			Status:         StatusFailed,
+			Attempts:       3,
+			NextAttemptAt:  now,
+			ResponseStatus: sql.NullInt32{Int32: 503, Valid: true},
+			LastError:      sql.NullString{String: "подписчик ответил 503 Service Unavailable", Valid: true},
+			CreatedAt:      now,
+		}}, nil)
+
+		resp, err := service.ListDeliveries(context.Background(), 1, StatusFailed, 0, 0)
+
+		require.NoError(t, err)
+		require.Len(t, resp.Deliveries, 1)
+		d := resp.Deliveries[0]
+		assert.Nil(t, d.NextAttemptAt, "next_attempt_at is shown only for pending deliveries")
+		require.NotNil(t, d.ResponseStatus)
+		assert.Equal(t, int32(503), *d.ResponseStatus)
+		assert.Equal(t, int32(defaultDeliveriesLimit), resp.Limit)
+	})
+}
+
+func TestPublish_EnqueuesEnvelope(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().EnqueueWebhookDeliveries(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.EnqueueWebhookDeliveriesParams) (int64, error) {
+			assert.Equal(t, string(EventWinnerSet), arg.Event)
+
+			var body struct {
+				Event      string        `json:"event"`
+				OccurredAt time.Time     `json:"occurred_at"`
+				Data       WinnerSetData `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(arg.Payload, &body))
+			assert.Equal(t, "winner.set", body.Event)
+			assert.WithinDuration(t, time.Now(), body.OccurredAt, time.Minute)
+			assert.Equal(t, WinnerSetData{WinnerID: 1, LotID: 2, ProposalID: 3, Rank: 1}, body.Data)
+			return 2, nil
+		})
+
+	err := service.Publish(context.Background(), EventWinnerSet, WinnerSetData{WinnerID: 1, LotID: 2, ProposalID: 3, Rank: 1})
+
+	assert.NoError(t, err)
+}
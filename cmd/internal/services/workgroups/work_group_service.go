@@ -0,0 +1,262 @@
+// Package workgroups предоставляет сервисный слой классификатора видов работ.
+//
+// Классификатор — дерево work_groups (земляные работы → разработка грунта,
+// бетонные работы, отделка...) с иерархическими кодами вида "01.02".
+// Позиция каталога относится к узлу через catalog_positions.work_group_code,
+// а аналитика лота (analytics.GetLotWorkGroupCosts) сворачивает стоимость
+// позиций вверх по дереву.
+//
+// Группировка вариантов (catalog_positions.parent_id, GROUP_TITLE) —
+// отдельная иерархия: вариант без своего кода относится к узлу родителя.
+package workgroups
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/db/postgres"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// codePattern совпадает с chk_work_groups_code: сегменты из букв и цифр,
+// разделённые точкой или дефисом.
+var codePattern = regexp.MustCompile(`^[0-9A-Za-z]+([.-][0-9A-Za-z]+)*$`)
+
+// WorkGroupService управляет классификатором видов работ.
+type WorkGroupService struct {
+	store  db.Store
+	logger logging.Logger
+}
+
+// NewWorkGroupService создаёт новый экземпляр WorkGroupService.
+func NewWorkGroupService(store db.Store, logger logging.Logger) *WorkGroupService {
+	return &WorkGroupService{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Tree возвращает классификатор деревом с числом позиций каталога в каждом
+// узле и в его поддереве.
+func (s *WorkGroupService) Tree(ctx context.Context) (*api_models.WorkGroupTreeResponse, error) {
+	rows, err := s.store.ListWorkGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения классификатора видов работ: %w", err)
+	}
+	return &api_models.WorkGroupTreeResponse{Groups: BuildTree(rows)}, nil
+}
+
+// BuildTree собирает узлы в дерево. Узлы с неизвестным родителем
+// становятся корневыми; порядок детей — как в rows (по коду).
+func BuildTree(rows []db.ListWorkGroupsRow) []api_models.WorkGroupNode {
+	known := make(map[int64]bool, len(rows))
+	for _, row := range rows {
+		known[row.ID] = true
+	}
+	children := make(map[int64][]db.ListWorkGroupsRow)
+	var roots []db.ListWorkGroupsRow
+	for _, row := range rows {
+		if row.ParentID.Valid && known[row.ParentID.Int64] {
+			children[row.ParentID.Int64] = append(children[row.ParentID.Int64], row)
+			continue
+		}
+		roots = append(roots, row)
+	}
+
+	var build func(row db.ListWorkGroupsRow) api_models.WorkGroupNode
+	build = func(row db.ListWorkGroupsRow) api_models.WorkGroupNode {
+		node := api_models.WorkGroupNode{
+			ID:                    row.ID,
+			Code:                  row.Code,
+			Name:                  row.Name,
+			PositionsCount:        row.PositionsCount,
+			SubtreePositionsCount: row.PositionsCount,
+			Children:              []api_models.WorkGroupNode{},
+		}
+		if row.ParentID.Valid {
+			parentID := row.ParentID.Int64
+			node.ParentID = &parentID
+		}
+		for _, child := range children[row.ID] {
+			childNode := build(child)
+			node.SubtreePositionsCount += childNode.SubtreePositionsCount
+			node.Children = append(node.Children, childNode)
+		}
+		return node
+	}
+
+	nodes := make([]api_models.WorkGroupNode, 0, len(roots))
+	for _, root := range roots {
+		nodes = append(nodes, build(root))
+	}
+	return nodes
+}
+
+// Create добавляет узел классификатора. Код уникален во всём дереве.
+func (s *WorkGroupService) Create(ctx context.Context, req api_models.WorkGroupRequest) (*db.WorkGroup, error) {
+	code, name, err := normalizeRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	parentID, err := s.checkParent(ctx, req.ParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := s.store.CreateWorkGroup(ctx, db.CreateWorkGroupParams{
+		ParentID: parentID,
+		Code:     code,
+		Name:     name,
+	})
+	if err != nil {
+		if postgres.IsUniqueViolation(err) {
+			return nil, apierrors.NewConflictErrorf(map[string]string{"code": code}, "workgroups.code_taken", code)
+		}
+		return nil, fmt.Errorf("ошибка CreateWorkGroup(%s): %w", code, err)
+	}
+	s.logger.Infof("Создан узел классификатора %s %q", group.Code, group.Name)
+	return &group, nil
+}
+
+// Update меняет код, название и родителя узла. Новый код переносится на
+// позиции каталога. Узел нельзя перенести под самого себя или своего потомка.
+func (s *WorkGroupService) Update(ctx context.Context, id int64, req api_models.WorkGroupRequest) (*db.WorkGroup, error) {
+	code, name, err := normalizeRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.get(ctx, id); err != nil {
+		return nil, err
+	}
+	parentID, err := s.checkParent(ctx, req.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		cycle, err := s.store.IsWorkGroupInSubtree(ctx, db.IsWorkGroupInSubtreeParams{
+			RootID:      id,
+			CandidateID: parentID.Int64,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ошибка IsWorkGroupInSubtree(%d, %d): %w", id, parentID.Int64, err)
+		}
+		if cycle {
+			return nil, apierrors.NewValidationError("workgroups.move_into_self", id, parentID.Int64)
+		}
+	}
+
+	group, err := s.store.UpdateWorkGroup(ctx, db.UpdateWorkGroupParams{
+		ParentID: parentID,
+		Code:     code,
+		Name:     name,
+		ID:       id,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewNotFoundError("workgroups.not_found", id)
+		}
+		if postgres.IsUniqueViolation(err) {
+			return nil, apierrors.NewConflictErrorf(map[string]string{"code": code}, "workgroups.code_taken", code)
+		}
+		return nil, fmt.Errorf("ошибка UpdateWorkGroup(%d): %w", id, err)
+	}
+	s.logger.Infof("Изменён узел классификатора %d: %s %q", id, group.Code, group.Name)
+	return &group, nil
+}
+
+// Delete удаляет узел без потомков. Позиции каталога узла остаются без кода.
+func (s *WorkGroupService) Delete(ctx context.Context, id int64) error {
+	children, err := s.store.CountWorkGroupChildren(ctx, sql.NullInt64{Int64: id, Valid: true})
+	if err != nil {
+		return fmt.Errorf("ошибка CountWorkGroupChildren(%d): %w", id, err)
+	}
+	if children > 0 {
+		return apierrors.NewConflictErrorf(map[string]int64{"children_count": children}, "workgroups.has_children", id, children)
+	}
+
+	deleted, err := s.store.DeleteWorkGroup(ctx, id)
+	if err != nil {
+		return fmt.Errorf("ошибка DeleteWorkGroup(%d): %w", id, err)
+	}
+	if deleted == 0 {
+		return apierrors.NewNotFoundError("workgroups.not_found", id)
+	}
+	s.logger.Infof("Удалён узел классификатора %d", id)
+	return nil
+}
+
+// AssignPosition относит позицию каталога к узлу с кодом code; nil снимает привязку.
+func (s *WorkGroupService) AssignPosition(ctx context.Context, positionID int64, code *string) (*db.CatalogPosition, error) {
+	if positionID <= 0 {
+		return nil, apierrors.NewValidationError("request.id_positive_got", positionID)
+	}
+
+	var workGroupCode sql.NullString
+	if code != nil {
+		trimmed := strings.TrimSpace(*code)
+		if _, err := s.store.GetWorkGroupByCode(ctx, trimmed); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, apierrors.NewValidationError("workgroups.code_not_found", trimmed)
+			}
+			return nil, fmt.Errorf("ошибка GetWorkGroupByCode(%s): %w", trimmed, err)
+		}
+		workGroupCode = sql.NullString{String: trimmed, Valid: true}
+	}
+
+	pos, err := s.store.SetCatalogPositionWorkGroup(ctx, db.SetCatalogPositionWorkGroupParams{
+		WorkGroupCode: workGroupCode,
+		ID:            positionID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierrors.NewNotFoundError("common.catalog_position_not_found", positionID)
+		}
+		return nil, fmt.Errorf("ошибка SetCatalogPositionWorkGroup(%d): %w", positionID, err)
+	}
+	s.logger.Infof("Позиция каталога %d: work_group_code=%q", positionID, workGroupCode.String)
+	return &pos, nil
+}
+
+func (s *WorkGroupService) get(ctx context.Context, id int64) (db.WorkGroup, error) {
+	group, err := s.store.GetWorkGroupByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.WorkGroup{}, apierrors.NewNotFoundError("workgroups.not_found", id)
+		}
+		return db.WorkGroup{}, fmt.Errorf("ошибка GetWorkGroupByID(%d): %w", id, err)
+	}
+	return group, nil
+}
+
+// checkParent проверяет, что родитель существует; nil — корневой узел.
+func (s *WorkGroupService) checkParent(ctx context.Context, parentID *int64) (sql.NullInt64, error) {
+	if parentID == nil {
+		return sql.NullInt64{}, nil
+	}
+	if _, err := s.store.GetWorkGroupByID(ctx, *parentID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return sql.NullInt64{}, apierrors.NewValidationError("workgroups.parent_not_found", *parentID)
+		}
+		return sql.NullInt64{}, fmt.Errorf("ошибка GetWorkGroupByID(%d): %w", *parentID, err)
+	}
+	return sql.NullInt64{Int64: *parentID, Valid: true}, nil
+}
+
+func normalizeRequest(req api_models.WorkGroupRequest) (code, name string, err error) {
+	code = strings.TrimSpace(req.Code)
+	if !codePattern.MatchString(code) {
+		return "", "", apierrors.NewValidationError("workgroups.invalid_code", req.Code)
+	}
+	name = strings.TrimSpace(req.Name)
+	if name == "" {
+		return "", "", apierrors.NewValidationError("common.name_empty")
+	}
+	return code, name, nil
+}
@@ -0,0 +1,228 @@
+package workgroups
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/apierrors"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR THE WORK GROUP CLASSIFIER (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. The classifier tree shows wrong totals — a parent must count the positions
+   of all its descendants
+2. A node moved under its own descendant turns the tree into a cycle
+3. Deleting a node silently orphans its children
+4. A position is assigned to a code that does not exist
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Tree
+- GIVEN 01 → 01.01, 01.02 and 02
+  THEN two roots, children ordered by code, subtree counts summed
+- GIVEN a node whose parent is missing from the list
+  THEN it becomes a root
+
+SCENARIO 2: Create
+- GIVEN an invalid code → ValidationError, no query
+- GIVEN an unknown parent → ValidationError
+- GIVEN a duplicate code → ConflictError
+
+SCENARIO 3: Update
+- GIVEN a parent inside the node's subtree → ValidationError, no update
+- GIVEN an unknown node → NotFoundError
+
+SCENARIO 4: Delete
+- GIVEN a node with children → ConflictError, nothing deleted
+- GIVEN a missing node → NotFoundError
+
+SCENARIO 5: AssignPosition
+- GIVEN an unknown code → ValidationError
+- GIVEN nil → the binding is cleared
+- GIVEN an unknown position → NotFoundError
+*/
+
+func setupTestService(t *testing.T) (*WorkGroupService, *db.MockStore) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockStore := db.NewMockStore(ctrl)
+	return NewWorkGroupService(mockStore, testutil.NewMockLogger()), mockStore
+}
+
+func groupRow(id, parentID int64, code string, positions int64) db.ListWorkGroupsRow {
+	row := db.ListWorkGroupsRow{ID: id, Code: code, Name: "Узел " + code, PositionsCount: positions}
+	if parentID > 0 {
+		row.ParentID = sql.NullInt64{Int64: parentID, Valid: true}
+	}
+	return row
+}
+
+func TestTree(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().ListWorkGroups(gomock.Any()).Return([]db.ListWorkGroupsRow{
+		groupRow(1, 0, "01", 1),
+		groupRow(2, 1, "01.01", 3),
+		groupRow(3, 1, "01.02", 2),
+		groupRow(4, 0, "02", 0),
+	}, nil)
+
+	resp, err := service.Tree(context.Background())
+	require.NoError(t, err)
+	require.Len(t, resp.Groups, 2)
+
+	root := resp.Groups[0]
+	assert.Equal(t, "01", root.Code)
+	assert.Equal(t, int64(1), root.PositionsCount)
+	assert.Equal(t, int64(6), root.SubtreePositionsCount)
+	require.Len(t, root.Children, 2)
+	assert.Equal(t, "01.01", root.Children[0].Code)
+	assert.Equal(t, int64(1), *root.Children[0].ParentID)
+	assert.Equal(t, "01.02", root.Children[1].Code)
+
+	assert.Equal(t, "02", resp.Groups[1].Code)
+	assert.Empty(t, resp.Groups[1].Children)
+}
+
+func TestBuildTree_UnknownParentBecomesRoot(t *testing.T) {
+	nodes := BuildTree([]db.ListWorkGroupsRow{groupRow(5, 99, "05.01", 2)})
+
+	require.Len(t, nodes, 1)
+	assert.Equal(t, "05.01", nodes[0].Code)
+}
+
+func TestCreate_InvalidCode(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.Create(context.Background(), api_models.WorkGroupRequest{Code: "01 02", Name: "Бетон"})
+
+	var validationErr *apierrors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestCreate_UnknownParent(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	parentID := int64(7)
+
+	mockStore.EXPECT().GetWorkGroupByID(gomock.Any(), int64(7)).Return(db.WorkGroup{}, sql.ErrNoRows)
+
+	_, err := service.Create(context.Background(), api_models.WorkGroupRequest{ParentID: &parentID, Code: "07.01", Name: "Бетон"})
+
+	var validationErr *apierrors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestCreate_DuplicateCode(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().CreateWorkGroup(gomock.Any(), db.CreateWorkGroupParams{Code: "01", Name: "Земляные работы"}).
+		Return(db.WorkGroup{}, &pq.Error{Code: "23505"})
+
+	_, err := service.Create(context.Background(), api_models.WorkGroupRequest{Code: " 01 ", Name: " Земляные работы "})
+
+	var conflictErr *apierrors.ConflictError
+	assert.ErrorAs(t, err, &conflictErr)
+}
+
+func TestUpdate_RejectsCycle(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	parentID := int64(2)
+
+	mockStore.EXPECT().GetWorkGroupByID(gomock.Any(), int64(1)).Return(db.WorkGroup{ID: 1, Code: "01"}, nil)
+	mockStore.EXPECT().GetWorkGroupByID(gomock.Any(), int64(2)).Return(db.WorkGroup{ID: 2, Code: "01.01"}, nil)
+	mockStore.EXPECT().IsWorkGroupInSubtree(gomock.Any(), db.IsWorkGroupInSubtreeParams{RootID: 1, CandidateID: 2}).Return(true, nil)
+	mockStore.EXPECT().UpdateWorkGroup(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := service.Update(context.Background(), 1, api_models.WorkGroupRequest{ParentID: &parentID, Code: "01", Name: "Земляные работы"})
+
+	var validationErr *apierrors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestUpdate_NotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().GetWorkGroupByID(gomock.Any(), int64(9)).Return(db.WorkGroup{}, sql.ErrNoRows)
+
+	_, err := service.Update(context.Background(), 9, api_models.WorkGroupRequest{Code: "09", Name: "Кровля"})
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestDelete_HasChildren(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().CountWorkGroupChildren(gomock.Any(), sql.NullInt64{Int64: 1, Valid: true}).Return(int64(2), nil)
+	mockStore.EXPECT().DeleteWorkGroup(gomock.Any(), gomock.Any()).Times(0)
+
+	err := service.Delete(context.Background(), 1)
+
+	var conflictErr *apierrors.ConflictError
+	assert.ErrorAs(t, err, &conflictErr)
+}
+
+func TestDelete_NotFound(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().CountWorkGroupChildren(gomock.Any(), sql.NullInt64{Int64: 9, Valid: true}).Return(int64(0), nil)
+	mockStore.EXPECT().DeleteWorkGroup(gomock.Any(), int64(9)).Return(int64(0), nil)
+
+	err := service.Delete(context.Background(), 9)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestAssignPosition_UnknownCode(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	code := "99"
+
+	mockStore.EXPECT().GetWorkGroupByCode(gomock.Any(), "99").Return(db.WorkGroup{}, sql.ErrNoRows)
+	mockStore.EXPECT().SetCatalogPositionWorkGroup(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := service.AssignPosition(context.Background(), 10, &code)
+
+	var validationErr *apierrors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestAssignPosition_Clear(t *testing.T) {
+	service, mockStore := setupTestService(t)
+
+	mockStore.EXPECT().SetCatalogPositionWorkGroup(gomock.Any(), db.SetCatalogPositionWorkGroupParams{ID: 10}).
+		Return(db.CatalogPosition{ID: 10}, nil)
+
+	pos, err := service.AssignPosition(context.Background(), 10, nil)
+	require.NoError(t, err)
+	assert.False(t, pos.WorkGroupCode.Valid)
+}
+
+func TestAssignPosition_UnknownPosition(t *testing.T) {
+	service, mockStore := setupTestService(t)
+	code := "01.01"
+
+	mockStore.EXPECT().GetWorkGroupByCode(gomock.Any(), "01.01").Return(db.WorkGroup{ID: 2, Code: "01.01"}, nil)
+	mockStore.EXPECT().SetCatalogPositionWorkGroup(gomock.Any(), db.SetCatalogPositionWorkGroupParams{
+		WorkGroupCode: sql.NullString{String: "01.01", Valid: true},
+		ID:            404,
+	}).Return(db.CatalogPosition{}, sql.ErrNoRows)
+
+	_, err := service.AssignPosition(context.Background(), 404, &code)
+
+	var notFoundErr *apierrors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
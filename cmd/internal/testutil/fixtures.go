@@ -1,11 +1,14 @@
 package testutil
 
 import (
+	"encoding/json"
 	"net"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
 	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
 )
 
@@ -102,13 +105,15 @@ func CreateTestType(id int64, title string) db.TenderType {
 func CreateTestContractor(id int64, title, inn string) db.Contractor {
 	now := time.Now()
 	return db.Contractor{
-		ID:            id,
-		Title:         title,
-		Inn:           inn,
-		Address:       "Test Address",
-		Accreditation: "active",
-		CreatedAt:     now,
-		UpdatedAt:     now,
+		ID:                     id,
+		Title:                  title,
+		Inn:                    inn,
+		Address:                "Test Address",
+		CreatedAt:              now,
+		UpdatedAt:              now,
+		AccreditationStatus:    "accredited",
+		AccreditationDocuments: json.RawMessage(`[]`),
+		AccreditationSource:    "import",
 	}
 }
 
@@ -211,6 +216,17 @@ func Float64(f float64) *float64 {
 	return &f
 }
 
+// Decimal возвращает указатель на decimal.Decimal из строки ("12.5")
+func Decimal(s string) *decimal.Decimal {
+	d := decimal.RequireFromString(s)
+	return &d
+}
+
+// Money возвращает указатель на api_models.Money из строки ("1234.56")
+func Money(s string) *api_models.Money {
+	return api_models.MoneyPtr(decimal.RequireFromString(s))
+}
+
 // Bool возвращает указатель на bool
 func Bool(b bool) *bool {
 	return &b
@@ -0,0 +1,287 @@
+// Package servertest собирает HTTP-сервер API поверх db.MockStore для тестов
+// обработчиков: те же роуты, middleware и сервисы, что и в cmd/main, но без БД,
+// брокера событий, почты и фоновых воркеров.
+//
+// Пакет вынесен из testutil, потому что импортирует server и сервисы, а их
+// собственные тесты импортируют testutil. По той же причине тесты, которые его
+// используют, объявляются во внешнем пакете (package server_test):
+//
+//	h := servertest.New(t)
+//	h.Store.EXPECT().GetTenderByID(gomock.Any(), int64(7)).Return(db.Tender{ID: 7}, nil)
+//	w := h.Do(t, http.MethodPost, "/api/v1/admin/tenders/7/purge?dry_run=true", nil, servertest.Admin)
+//
+// Что отличается от рабочего сервера:
+//   - события публикуются в events.Noop (лента уведомлений не пишется);
+//   - писем нет (notifier = nil), фоновые задачи не запускаются;
+//   - у health.Checker нет соединения с БД — /readyz через харнесс не проверяется;
+//   - кэш справочников выключен, каждый запрос идёт в мок;
+//   - лимиты запросов пользователей выключены (rate_limit.driver: none);
+//   - версия access-токенов (users.token_version) всегда 0: проверка отзыва
+//     в AuthMiddleware не требует ожиданий в каждом тесте.
+package servertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/server"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/auth"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/catalog"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/currency"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/entities"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/etp"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/feed"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/health"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/importer"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/joblock"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/live"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/lot"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/matching"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/outbound"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/parsetask"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/ratelimit"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/refcache"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/report"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/retention"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/scheduler"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/servicecreds"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/webhooks"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/testutil"
+)
+
+const (
+	// JWTSecret — секрет, которым харнесс подписывает access-токены пользователей.
+	JWTSecret = "servertest-secret-key-minimum-32-chars"
+	// WorkerKey — статический ключ воркера для роутов /internal/worker (все scopes).
+	WorkerKey = "servertest-worker-key"
+
+	csrfToken = "servertest-csrf-token"
+)
+
+// User — пользователь, от имени которого выполняется запрос.
+type User struct {
+	ID             int64
+	Role           string
+	OrganizationID int64
+}
+
+// Готовые пользователи организации 1. Admin видит ресурсы всех организаций.
+var (
+	Admin   = &User{ID: 1, Role: auth.RoleAdmin, OrganizationID: 1}
+	Editor  = &User{ID: 2, Role: auth.RoleEditor, OrganizationID: 1}
+	Analyst = &User{ID: 3, Role: auth.RoleAnalyst, OrganizationID: 1}
+	Viewer  = &User{ID: 4, Role: auth.RoleViewer, OrganizationID: 1}
+)
+
+// Harness — сервер API с моком хранилища.
+type Harness struct {
+	Store   *db.MockStore
+	Logger  *testutil.MockLogger
+	Config  *config.Config
+	Server  *server.Server
+	Handler http.Handler
+	Live    *live.Hub
+}
+
+// Option меняет конфигурацию до сборки сервера.
+type Option func(cfg *config.Config)
+
+// Config возвращает конфигурацию харнесса: значения по умолчанию из config.yml
+// для секций, которые читают сервисы при создании.
+func Config() *config.Config {
+	isDebug := false
+	return &config.Config{
+		IsDebug: &isDebug,
+		Auth: config.AuthConfig{
+			JWTSecret:         JWTSecret,
+			AccessTokenTTL:    15 * time.Minute,
+			RefreshTokenTTL:   720 * time.Hour,
+			CookieAccessName:  "access_token",
+			CookieRefreshName: "refresh_token",
+			CookieHttpOnly:    true,
+			CookieSameSite:    "lax",
+
+			TokenVersionCacheTTL: 5 * time.Second,
+		},
+		Currency:    config.CurrencyConfig{Base: "RUB"},
+		Consistency: config.ConsistencyConfig{AbsTolerance: 1, RelTolerance: 0.001},
+		Anomalies: config.AnomaliesConfig{
+			ThresholdPercent: 30,
+			CriticalFactor:   3,
+			MinPeers:         2,
+			HistoryMonths:    24,
+			MinHistoryPrices: 3,
+		},
+		RefCache:    config.RefCacheConfig{Driver: "none"},
+		Contractors: config.ContractorsConfig{AccreditationWarnDays: 30},
+		RateLimit: config.RateLimitConfig{
+			ServiceRPS:   100,
+			ServiceBurst: 200,
+			Driver:       "none",
+			UserRPS:      20,
+			UserBurst:    40,
+			ExportRPS:    0.2,
+			ExportBurst:  5,
+			AuthRPS:      1,
+			AuthBurst:    10,
+		},
+		Log: config.LogConfig{Level: "trace"},
+		Live: config.LiveConfig{
+			HeartbeatInterval:     15 * time.Second,
+			BufferSize:            32,
+			MaxConnectionsPerUser: 5,
+		},
+		// Без повторов: тесты подставляют парсер через httptest.Server
+		OutboundHTTP: config.OutboundHTTPConfig{
+			MaxAttempts:             1,
+			RetryBaseDelay:          time.Millisecond,
+			RetryMaxDelay:           time.Millisecond,
+			BreakerFailureThreshold: 5,
+			BreakerOpenTimeout:      time.Second,
+		},
+		ETP: config.ETPConfig{
+			FetchPath:      "/fetch-tender/",
+			RequestTimeout: 5 * time.Second,
+			PendingTTL:     30 * time.Minute,
+		},
+	}
+}
+
+// New собирает сервер так же, как cmd/main, подставляя db.MockStore.
+// Ожидания мока проверяются в конце теста (gomock.Controller привязан к t).
+func New(t *testing.T, opts ...Option) *Harness {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := Config()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	store := db.NewMockStore(gomock.NewController(t))
+	store.EXPECT().GetUserTokenVersion(gomock.Any(), gomock.Any()).Return(int64(0), nil).AnyTimes()
+	logger := testutil.NewMockLogger()
+	publisher := events.Noop{}
+
+	refCache, err := refcache.New(cfg.RefCache, logger)
+	require.NoError(t, err)
+	rateLimiter, err := ratelimit.New(cfg.RateLimit, logger)
+	require.NoError(t, err)
+	liveHub := live.NewHub(cfg.Live, logger)
+
+	tenderService := importer.NewTenderImportService(store, nil, logger, entities.NewEntityManager(logger), cfg.Import, cfg.Outbox, publisher, nil)
+	catalogService := catalog.NewCatalogService(store, logger, publisher)
+	lotService := lot.NewLotService(store, logger, publisher)
+	matchingService := matching.NewMatchingService(store, logger, publisher)
+	serviceCreds := servicecreds.NewService(store, logger, map[string]string{"servertest-worker": WorkerKey})
+	webhookService := webhooks.NewService(store, logger, cfg.Webhooks)
+	reportService := report.NewReportService(store, logger, currency.NewRates(cfg.Currency), cfg.Reports, nil)
+	feedService := feed.NewFeedService(store, logger)
+	authService := auth.NewService(store, cfg, logger, nil)
+	parserClient := outbound.NewClient(cfg.OutboundHTTP, logger)
+	etpFetch := etp.NewFetchService(store, logger, parserClient, parsetask.NewParseTaskService(store, logger, liveHub),
+		cfg.Services.ParserService.URL, cfg.ETP)
+	etpSync := etp.NewSyncService(store, logger, etpFetch, publisher, cfg.ETP.Sync)
+	retentionService := retention.NewService(store, logger, cfg.Retention)
+
+	srv := server.NewServer(store, logger, tenderService, catalogService, lotService, matchingService,
+		serviceCreds, webhookService, reportService, feedService, publisher, scheduler.New(logger, joblock.Local{}),
+		authService, health.NewChecker(nil, cfg, logger), refCache, rateLimiter, liveHub, parserClient, etpFetch, etpSync, retentionService, cfg)
+
+	return &Harness{
+		Store:   store,
+		Logger:  logger,
+		Config:  cfg,
+		Server:  srv,
+		Handler: srv.Handler(),
+		Live:    liveHub,
+	}
+}
+
+// Do выполняет запрос от имени user (nil — без аутентификации). body кодируется
+// в JSON. Вместе с токеном передаётся CSRF-токен (cookie и заголовок): GET его
+// не проверяет, изменяющим методам он нужен.
+func (h *Harness) Do(t *testing.T, method, path string, body any, user *User) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := newRequest(t, method, path, body)
+	if user != nil {
+		req.AddCookie(&http.Cookie{Name: h.Config.Auth.CookieAccessName, Value: h.AccessToken(t, user)})
+		req.AddCookie(&http.Cookie{Name: "csrf_token", Value: csrfToken})
+		req.Header.Set("X-CSRF-Token", csrfToken)
+	}
+
+	w := httptest.NewRecorder()
+	h.Handler.ServeHTTP(w, req)
+	return w
+}
+
+// DoWorker выполняет запрос воркера к /internal/worker с ключом WorkerKey.
+func (h *Harness) DoWorker(t *testing.T, method, path string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := newRequest(t, method, path, body)
+	req.Header.Set("Authorization", "Bearer "+WorkerKey)
+
+	w := httptest.NewRecorder()
+	h.Handler.ServeHTTP(w, req)
+	return w
+}
+
+func newRequest(t *testing.T, method, path string, body any) *http.Request {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(payload)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req
+}
+
+// AccessToken выпускает access-токен пользователя, подписанный JWTSecret.
+func (h *Harness) AccessToken(t *testing.T, user *User) string {
+	t.Helper()
+
+	now := time.Now()
+	claims := auth.JWTClaims{
+		UserID:         user.ID,
+		Role:           user.Role,
+		OrganizationID: user.OrganizationID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(h.Config.Auth.AccessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "tenders-go",
+			Subject:   fmt.Sprintf("%d", user.ID),
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(h.Config.Auth.JWTSecret))
+	require.NoError(t, err)
+	return signed
+}
+
+// DecodeJSON разбирает тело ответа в target.
+func DecodeJSON(t *testing.T, w *httptest.ResponseRecorder, target any) {
+	t.Helper()
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), target), "тело ответа: %s", w.Body.String())
+}
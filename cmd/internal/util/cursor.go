@@ -0,0 +1,40 @@
+package util
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/i18n"
+)
+
+// PageCursor — позиция keyset-пагинации: ключ сортировки последней строки
+// страницы. Следующая страница начинается строго после него, поэтому глубина
+// не влияет на стоимость запроса, а вставки между запросами не сдвигают строки
+// между страницами. Какие поля заполнены, зависит от списка: ID есть всегда,
+// CreatedAt, Score и Pinned — только там, где по ним идёт сортировка.
+type PageCursor struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"t,omitzero"`
+	Score     float32   `json:"s,omitempty"`
+	Pinned    bool      `json:"p,omitempty"`
+}
+
+// EncodeCursor возвращает непрозрачную строку next_cursor для query-параметра cursor.
+func EncodeCursor(c PageCursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor разбирает параметр cursor. Пустая строка — первая страница
+// (нулевой курсор, ok=false).
+func DecodeCursor(s string) (c PageCursor, ok bool, err error) {
+	if s == "" {
+		return PageCursor{}, false, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || json.Unmarshal(raw, &c) != nil || c.ID <= 0 {
+		return PageCursor{}, false, i18n.Errorf("request.invalid_cursor")
+	}
+	return c, true, nil
+}
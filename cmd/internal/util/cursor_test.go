@@ -0,0 +1,52 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ========== Тесты для EncodeCursor / DecodeCursor ==========
+
+func TestCursor_RoundTrip(t *testing.T) {
+	cursors := []PageCursor{
+		{ID: 42},
+		{ID: 7, CreatedAt: time.Date(2026, 3, 1, 10, 30, 0, 123456000, time.UTC)},
+		{ID: 9, CreatedAt: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), Score: 0.6666667},
+		{ID: 5, Pinned: true},
+	}
+	for _, want := range cursors {
+		encoded := EncodeCursor(want)
+		got, ok, err := DecodeCursor(encoded)
+		require.NoError(t, err, encoded)
+		assert.True(t, ok)
+		assert.Equal(t, want.ID, got.ID)
+		assert.True(t, want.CreatedAt.Equal(got.CreatedAt), encoded)
+		assert.Equal(t, want.Score, got.Score)
+		assert.Equal(t, want.Pinned, got.Pinned)
+	}
+}
+
+func TestDecodeCursor_Empty(t *testing.T) {
+	c, ok, err := DecodeCursor("")
+
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Zero(t, c)
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	invalid := []string{
+		"not base64!",
+		EncodeCursor(PageCursor{})[:2] + "@@",
+		"bnVsbA",      // null
+		"eyJpZCI6MH0", // {"id":0}
+		"eyJpZCI6In0", // обрезанный JSON
+	}
+	for _, s := range invalid {
+		_, _, err := DecodeCursor(s)
+		assert.Error(t, err, s)
+	}
+}
@@ -0,0 +1,67 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxRubleIntDigits - число цифр целой части, которое вмещает NUMERIC(18,2).
+const maxRubleIntDigits = 16
+
+// ParseRubles проверяет денежную сумму в рублях с точностью до копеек и приводит
+// её к виду, в котором PostgreSQL возвращает NUMERIC(18,2): "1234.50".
+// Допускается запятая как десятичный разделитель и пробелы между разрядами
+// ("1 234,5"). Отрицательные суммы и больше двух знаков после запятой — ошибка:
+// копейки не округляются молча.
+func ParseRubles(s string) (string, error) {
+	v := strings.NewReplacer(" ", "", " ", "", " ", "").Replace(strings.TrimSpace(s))
+	if v == "" {
+		return "", fmt.Errorf("сумма не указана")
+	}
+
+	intPart, fracPart, hasFrac := strings.Cut(strings.Replace(v, ",", ".", 1), ".")
+	if intPart == "" || !isDigits(intPart) || (hasFrac && !isDigits(fracPart)) {
+		return "", fmt.Errorf("некорректная сумма %q: ожидается неотрицательное число вида 1234.56", s)
+	}
+	if len(fracPart) > 2 {
+		return "", fmt.Errorf("некорректная сумма %q: не больше двух знаков после запятой (копейки)", s)
+	}
+
+	intPart = strings.TrimLeft(intPart, "0")
+	if intPart == "" {
+		intPart = "0"
+	}
+	if len(intPart) > maxRubleIntDigits {
+		return "", fmt.Errorf("некорректная сумма %q: слишком большое значение", s)
+	}
+
+	return intPart + "." + (fracPart + "00")[:2], nil
+}
+
+// FormatRubles форматирует сумму вида "1234567.5" для отображения:
+// "1 234 567,50 ₽" (разряды разделены неразрывным пробелом).
+func FormatRubles(value string) (string, error) {
+	normalized, err := ParseRubles(value)
+	if err != nil {
+		return "", err
+	}
+	intPart, fracPart, _ := strings.Cut(normalized, ".")
+
+	var b strings.Builder
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteRune(' ')
+		}
+		b.WriteRune(r)
+	}
+	return b.String() + "," + fracPart + " ₽", nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
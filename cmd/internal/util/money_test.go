@@ -0,0 +1,65 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ========== Тесты для ParseRubles ==========
+
+func TestParseRubles(t *testing.T) {
+	valid := map[string]string{
+		"1234.56":       "1234.56",
+		"1234,5":        "1234.50",
+		"1234":          "1234.00",
+		" 1 234 567,89": "1234567.89",
+		"1 000":         "1000.00",
+		"0.01":          "0.01",
+		"000":           "0.00",
+		"007.10":        "7.10",
+	}
+	for in, want := range valid {
+		got, err := ParseRubles(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	invalid := []string{
+		"",
+		"   ",
+		"-10.00",
+		"12.345", // копейки не округляются молча
+		"1e6",
+		"12.3.4",
+		"abc",
+		".50",
+		"1234.",
+		"12,34,56",
+		"12345678901234567", // 17 цифр не помещаются в NUMERIC(18,2)
+	}
+	for _, in := range invalid {
+		_, err := ParseRubles(in)
+		assert.Error(t, err, in)
+	}
+}
+
+// ========== Тесты для FormatRubles ==========
+
+func TestFormatRubles(t *testing.T) {
+	cases := map[string]string{
+		"1234567.89": "1 234 567,89 ₽",
+		"100.5":      "100,50 ₽",
+		"0.00":       "0,00 ₽",
+		"1000":       "1 000,00 ₽",
+	}
+	for in, want := range cases {
+		got, err := FormatRubles(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	_, err := FormatRubles("12.345")
+	assert.Error(t, err)
+}
@@ -2,8 +2,10 @@ package util
 
 import (
 	"database/sql"
-	"strconv"
 	"time" // Понадобится для NullableTime
+
+	"github.com/shopspring/decimal"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
 )
 
 func Deref(s *string) string {
@@ -82,18 +84,22 @@ func IntPointerOrNil(val int) *int {
 	return &val
 }
 
-// ConvertNullFloat64ToNullString преобразует sql.NullFloat64 в sql.NullString.
-func ConvertNullFloat64ToNullString(nf sql.NullFloat64) sql.NullString {
-	if !nf.Valid {
+// NullableDecimal преобразует *decimal.Decimal в sql.NullString для колонки NUMERIC.
+// Значение пишется полностью, без округления и без промежуточного float64.
+func NullableDecimal(d *decimal.Decimal) sql.NullString {
+	if d == nil {
+		return sql.NullString{Valid: false}
+	}
+	return sql.NullString{String: d.String(), Valid: true}
+}
+
+// NullableMoney преобразует *api_models.Money в sql.NullString для колонки NUMERIC.
+// В отличие от JSON-вывода Money, значение не округляется до копеек.
+func NullableMoney(m *api_models.Money) sql.NullString {
+	if m == nil {
 		return sql.NullString{Valid: false}
 	}
-	// Преобразуем float64 в строку.
-	// strconv.FormatFloat предлагает хороший контроль над форматированием.
-	// 'f' - для стандартного десятичного представления (-ddd.dddd)
-	// -1 - для минимально необходимого количества знаков после запятой
-	// 64 - для float64
-	s := strconv.FormatFloat(nf.Float64, 'f', -1, 64)
-	return sql.NullString{String: s, Valid: true}
+	return NullableDecimal(&m.Decimal)
 }
 
 // ParseDate разбирает строку с датой в формате "ДД.ММ.ГГГГ ЧЧ:ММ:СС"
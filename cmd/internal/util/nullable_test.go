@@ -1,11 +1,13 @@
 package util
 
 import (
-	"database/sql"
 	"testing"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/api_models"
 )
 
 // ========== Тесты для Deref ==========
@@ -256,46 +258,45 @@ func TestIntPointerOrNil(t *testing.T) {
 	})
 }
 
-// ========== Тесты для ConvertNullFloat64ToNullString ==========
+// ========== Тесты для NullableDecimal / NullableMoney ==========
 
-func TestConvertNullFloat64ToNullString(t *testing.T) {
-	t.Run("валидное float64 значение", func(t *testing.T) {
-		input := sql.NullFloat64{Float64: 123.45, Valid: true}
-		result := ConvertNullFloat64ToNullString(input)
+func TestNullableDecimal(t *testing.T) {
+	t.Run("значение пишется без потери точности", func(t *testing.T) {
+		val := decimal.RequireFromString("1234567890.123456789")
+		result := NullableDecimal(&val)
 
 		assert.True(t, result.Valid)
-		assert.Equal(t, "123.45", result.String)
+		assert.Equal(t, "1234567890.123456789", result.String)
 	})
 
-	t.Run("невалидное значение", func(t *testing.T) {
-		input := sql.NullFloat64{Valid: false}
-		result := ConvertNullFloat64ToNullString(input)
+	t.Run("nil указатель", func(t *testing.T) {
+		result := NullableDecimal(nil)
 
 		assert.False(t, result.Valid)
 	})
 
 	t.Run("нулевое значение", func(t *testing.T) {
-		input := sql.NullFloat64{Float64: 0.0, Valid: true}
-		result := ConvertNullFloat64ToNullString(input)
+		val := decimal.Zero
+		result := NullableDecimal(&val)
 
-		assert.True(t, result.Valid)
+		assert.True(t, result.Valid, "0 должен быть валидным")
 		assert.Equal(t, "0", result.String)
 	})
+}
 
-	t.Run("отрицательное значение", func(t *testing.T) {
-		input := sql.NullFloat64{Float64: -99.99, Valid: true}
-		result := ConvertNullFloat64ToNullString(input)
+func TestNullableMoney(t *testing.T) {
+	t.Run("копейки не округляются при записи в БД", func(t *testing.T) {
+		m := api_models.NewMoney(decimal.RequireFromString("-99.995"))
+		result := NullableMoney(&m)
 
 		assert.True(t, result.Valid)
-		assert.Equal(t, "-99.99", result.String)
+		assert.Equal(t, "-99.995", result.String)
 	})
 
-	t.Run("очень большое число", func(t *testing.T) {
-		input := sql.NullFloat64{Float64: 1234567890.123456, Valid: true}
-		result := ConvertNullFloat64ToNullString(input)
+	t.Run("nil указатель", func(t *testing.T) {
+		result := NullableMoney(nil)
 
-		assert.True(t, result.Valid)
-		assert.Contains(t, result.String, "1234567890")
+		assert.False(t, result.Valid)
 	})
 }
 
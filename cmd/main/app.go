@@ -1,56 +1,274 @@
 package main
 
 import (
-	"database/sql"
+	"context"
+	"flag"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/db/postgres"
 	db "github.com/zhukovvlad/tenders-go/cmd/internal/db/sqlc"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/grpcapi"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/server"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/auth"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/catalog"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/contractor"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/currency"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/entities"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/etp"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/events"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/feed"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/health"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/importer"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/joblock"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/live"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/lot"
 	"github.com/zhukovvlad/tenders-go/cmd/internal/services/matching"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/notifications"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/outbound"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/outbox"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/parsetask"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/ratelimit"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/refcache"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/report"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/retention"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/scheduler"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/secrets"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/servicecreds"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/webhooks"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/buildinfo"
 	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
-
-	_ "github.com/lib/pq"
 )
 
 func main() {
+	printEffectiveConfig := flag.Bool("print-effective-config", false,
+		"print the merged configuration (secrets masked) and exit")
+	flag.Parse()
+
 	logger := logging.GetLogger()
-	logger.Info("Starting Tenders API...")
+	build := buildinfo.Get()
+	logger.Infof("Starting Tenders API (version: %s, commit: %s)...", build.Version, build.Commit)
 
-	err := godotenv.Load()
-	if err != nil {
-		logger.Fatalf("error loading .env file: %v", err)
+	// .env — только для локальной разработки; в окружениях переменные задаются снаружи.
+	if err := godotenv.Load(); err != nil {
+		logger.Warnf(".env file not loaded: %v", err)
 	}
 
 	cfg := config.GetConfig()
+	if err := logging.SetLevel(cfg.Log.Level); err != nil {
+		logger.Fatalf("error setting log level: %v", err)
+	}
 
-	conn, err := sql.Open(cfg.Database.Driver, cfg.Database.Source)
+	// jwt_secret и DSN могут храниться вне конфигурации (secrets.provider: file, vault)
+	secretsProvider, err := secrets.New(cfg.Secrets)
 	if err != nil {
-		logger.Fatalf("error connecting to database: %v", err)
+		logger.Fatalf("error creating secrets provider: %v", err)
+	}
+	if err := secrets.Apply(context.Background(), secretsProvider, cfg); err != nil {
+		logger.Fatalf("error loading secrets (provider: %s): %v", cfg.Secrets.Provider, err)
 	}
-	defer conn.Close()
 
-	if err = conn.Ping(); err != nil {
-		logger.Fatalf("error pinging database: %v", err)
+	if *printEffectiveConfig {
+		out, err := cfg.EffectiveYAML()
+		if err != nil {
+			logger.Fatalf("error rendering effective config: %v", err)
+		}
+		fmt.Print(string(out))
+		return
+	}
+
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrateCommand(context.Background(), cfg.Database, logger, flag.Args()[1:]); err != nil {
+			logger.Fatalf("migrate: %v", err)
+		}
+		return
+	}
+
+	conn, closeDB, err := postgres.Open(context.Background(), cfg.Database)
+	if err != nil {
+		logger.Fatalf("error connecting to database: %v", err)
 	}
+	defer closeDB()
 
-	logger.Info("Database connection established")
+	logger.Infof("Database connection established (driver: %s, max_open_conns: %d)", cfg.Database.Driver, cfg.Database.MaxOpenConns)
+
+	if err := ensureSchema(context.Background(), cfg.Database, conn, logger); err != nil {
+		logger.Fatalf("database schema check failed: %v", err)
+	}
 
 	store := db.NewStore(conn)
 
+	// Доменные события для внешних потребителей (events.driver: none — отключено)
+	busPublisher, err := events.New(cfg.Events, logger)
+	if err != nil {
+		logger.Fatalf("error creating event publisher: %v", err)
+	}
+	// Лента уведомлений пользователей и поток событий веб-интерфейса
+	// (GET /api/v1/events) наполняются теми же событиями, что и шина
+	feedService := feed.NewFeedService(store, logger)
+	liveHub := live.NewHub(cfg.Live, logger)
+	eventPublisher := events.Fanout(busPublisher, feedService, live.NewForwarder(liveHub, store.GetTenderOrganizationID, logger))
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = eventPublisher.Close(ctx)
+	}()
+
+	// Служебные письма (mail.host не задан — письма только пишутся в лог)
+	notifier := notifications.NewService(notifications.NewMailer(cfg.Mail, logger), cfg.Mail, cfg.Notifications, logger)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := notifier.Wait(ctx); err != nil {
+			logger.Warnf("notifications: %v", err)
+		}
+	}()
+
 	// Создаем все сервисы с внедрением зависимостей
 	entityManager := entities.NewEntityManager(logger)
-	tenderService := importer.NewTenderImportService(store, logger, entityManager)
-	catalogService := catalog.NewCatalogService(store, logger)
-	lotService := lot.NewLotService(store, logger)
-	matchingService := matching.NewMatchingService(store, logger)
+	tenderService := importer.NewTenderImportService(store, conn, logger, entityManager, cfg.Import, cfg.Outbox, eventPublisher, notifier)
+	catalogService := catalog.NewCatalogService(store, logger, eventPublisher)
+	lotService := lot.NewLotService(store, logger, eventPublisher)
+	matchingService := matching.NewMatchingService(store, logger, eventPublisher)
+
+	// Ключи внутренних сервисов: БД + legacy-ключ из окружения.
+	// Кэш обновляется в фоне, поэтому ротация ключа не требует перезапуска.
+	serviceCreds := servicecreds.NewService(store, logger, map[string]string{
+		"python-worker": os.Getenv("GO_SERVER_API_KEY"),
+	})
+	if err := serviceCreds.Refresh(context.Background()); err != nil {
+		logger.Fatalf("error loading service credentials: %v", err)
+	}
+	if !serviceCreds.HasCredentials() {
+		logger.Fatal("no service credentials found: set GO_SERVER_API_KEY (run 'make generate-env') or add a key to service_credentials")
+	}
+	go serviceCreds.Run(context.Background(), cfg.ServiceAuth.CredentialsRefreshInterval)
+
+	// Denylist access-токенов: отзывы при смене роли/деактивации читаются из БД,
+	// чтобы они действовали и на других экземплярах API.
+	authService := auth.NewService(store, cfg, logger, notifier)
+	if err := authService.SyncTokenRevocations(context.Background()); err != nil {
+		logger.Fatalf("error loading token revocations: %v", err)
+	}
+	go authService.RunTokenRevocationSync(context.Background(), cfg.Auth.RevocationSyncInterval)
+
+	// Вход через SSO: discovery провайдера загружается при первом входе,
+	// поэтому недоступный IdP не мешает старту
+	if cfg.Auth.OIDC.Enabled {
+		oidcClient, err := auth.NewOIDCClient(cfg.Auth.OIDC)
+		if err != nil {
+			logger.Fatalf("error configuring oidc: %v", err)
+		}
+		authService.EnableOIDC(oidcClient)
+		logger.Infof("oidc login enabled, issuer: %s", cfg.Auth.OIDC.Issuer)
+	}
+
+	// Вебхуки: события ставятся в очередь в БД, доставка — фоновым воркером
+	webhookService := webhooks.NewService(store, logger, cfg.Webhooks)
+	go webhookService.Run(context.Background(), cfg.Webhooks.DeliveryInterval)
+
+	// Фоновые задачи ниже на нескольких экземплярах API выполняет один из них:
+	// блокировки advisory-lock PostgreSQL по имени задачи (scheduler.lock_driver)
+	// Таймауты запросов к парсеру задаются контекстом (upload.parser_timeout,
+	// upload.status_timeout, etp.request_timeout): общий Timeout клиента обрывал
+	// бы загрузку больших файлов
+	parserClient := outbound.NewClient(cfg.OutboundHTTP, logger)
+	etpFetch := etp.NewFetchService(store, logger, parserClient, parsetask.NewParseTaskService(store, logger, liveHub),
+		cfg.Services.ParserService.URL, cfg.ETP)
+	etpSync := etp.NewSyncService(store, logger, etpFetch, eventPublisher, cfg.ETP.Sync)
+
+	jobLocker := joblock.New(cfg.Scheduler.LockDriver, conn, logger)
+
+	// Transactional outbox: события Python-воркеру пишутся в транзакции импорта,
+	// отправка с повторами до успеха — фоновым диспетчером
+	if cfg.Outbox.Enabled() {
+		outboxDispatcher := outbox.NewDispatcher(store, logger, cfg.Outbox)
+		go outboxDispatcher.Run(context.Background(), cfg.Outbox.DeliveryInterval, jobLocker)
+	}
+
+	// Регулярные отчеты: формирование по расписанию и рассылка по почте
+	reportService := report.NewReportService(store, logger, currency.NewRates(cfg.Currency), cfg.Reports, notifier)
+	if cfg.Reports.Enabled {
+		go reportService.Run(context.Background(), cfg.Reports.CheckInterval, jobLocker)
+	}
+
+	// Периодические задачи обслуживания БД
+	jobScheduler := scheduler.New(logger, jobLocker)
+	err = jobScheduler.Register("catalog_renormalization", cfg.Scheduler.RenormalizationInterval,
+		func(ctx context.Context) (string, error) {
+			result, err := catalogService.ProcessRenormalizationBatch(ctx, cfg.Scheduler.RenormalizationBatchSize)
+			if err != nil || result == nil {
+				return "нет выполняющейся перенормализации", err
+			}
+			if result.Completed {
+				return fmt.Sprintf("перенормализация %d завершена", result.RunID), nil
+			}
+			return fmt.Sprintf("перенормализация %d: возвращено в очередь позиций: %d", result.RunID, result.Requeued), nil
+		})
+	if err != nil {
+		logger.Fatalf("error registering scheduled job: %v", err)
+	}
+	// Обновление открытых тендеров с ЭТП: новые предложения и изменения цен
+	if cfg.ETP.Sync.Enabled {
+		err = jobScheduler.Register("etp_sync", cfg.ETP.Sync.Interval, etpSync.Run)
+		if err != nil {
+			logger.Fatalf("error registering scheduled job: %v", err)
+		}
+	}
+	// Политики хранения: истекшие сессии, matching_cache, старая история импортов
+	retentionService := retention.NewService(store, logger, cfg.Retention)
+	if cfg.Retention.Enabled {
+		err = jobScheduler.Register("retention", cfg.Retention.Interval, retentionService.RunAll)
+		if err != nil {
+			logger.Fatalf("error registering scheduled job: %v", err)
+		}
+	}
+	// Сроки аккредитации подрядчиков: истекшие помечаются, о скором окончании — события
+	if cfg.Contractors.AccreditationCheckEnabled {
+		accreditationMonitor := contractor.NewAccreditationMonitor(store, logger, eventPublisher, cfg.Contractors)
+		err = jobScheduler.Register("contractor_accreditation", cfg.Contractors.AccreditationCheckInterval, accreditationMonitor.Run)
+		if err != nil {
+			logger.Fatalf("error registering scheduled job: %v", err)
+		}
+	}
+	jobScheduler.Start(context.Background())
+
+	healthChecker := health.NewChecker(conn, cfg, logger)
+
+	// Кэш справочников (типы, разделы, категории, единицы измерения)
+	refCache, err := refcache.New(cfg.RefCache, logger)
+	if err != nil {
+		logger.Fatalf("error creating reference cache: %v", err)
+	}
+	defer refCache.Close()
 
-	server := server.NewServer(store, logger, tenderService, catalogService, lotService, matchingService, cfg)
+	// Лимиты запросов пользователей к /api/v1 (по user_id, до входа — по IP)
+	rateLimiter, err := ratelimit.New(cfg.RateLimit, logger)
+	if err != nil {
+		logger.Fatalf("error creating rate limiter: %v", err)
+	}
+	defer rateLimiter.Close()
+
+	server := server.NewServer(store, logger, tenderService, catalogService, lotService, matchingService, serviceCreds, webhookService, reportService, feedService, eventPublisher, jobScheduler, authService, healthChecker, refCache, rateLimiter, liveHub, parserClient, etpFetch, etpSync, retentionService, cfg)
+
+	// SIGHUP перечитывает cors, rate_limit и log без перезапуска
+	go watchConfigReload(cfg, server, authService, secretsProvider, logger)
+
+	// gRPC для воркеров — отдельный порт, те же сервисы и ключи, что у /internal/worker
+	if cfg.GRPC.Enabled {
+		grpcServer := grpcapi.NewServer(logger, tenderService, catalogService, matchingService, serviceCreds, webhookService, refCache, cfg.GRPC)
+		grpcAddress := fmt.Sprintf("%s:%s", cfg.GRPC.BindIP, cfg.GRPC.Port)
+		logger.Infof("Starting gRPC server on %s", grpcAddress)
+		go func() {
+			if err := grpcServer.Start(grpcAddress); err != nil {
+				logger.Fatalf("error starting gRPC server: %v", err)
+			}
+		}()
+		defer grpcServer.Stop()
+	}
 
 	serverAddress := fmt.Sprintf("%s:%s", cfg.Listen.BindIP, cfg.Listen.Port)
 	logger.Infof("Starting server on %s", serverAddress)
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/db/migration"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/db/postgres"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+const migrateUsage = "usage: app migrate up | down [N] | version | force V"
+
+// runMigrateCommand выполняет подкоманду migrate над встроенными миграциями:
+//
+//	migrate up        — применить все неприменённые
+//	migrate down [N]  — откатить N последних (по умолчанию 1)
+//	migrate version   — показать версию схемы
+//	migrate force V   — записать версию V и снять dirty после ручного исправления
+func runMigrateCommand(ctx context.Context, cfg config.DatabaseConfig, logger logging.Logger, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	migrator, closeMigrator, err := openMigrator(ctx, cfg, logger)
+	if err != nil {
+		return err
+	}
+	defer closeMigrator()
+
+	switch args[0] {
+	case "up":
+		if err := migrator.Up(); err != nil {
+			return err
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil {
+				return fmt.Errorf("invalid number of steps %q: %w", args[1], err)
+			}
+		}
+		if err := migrator.Down(steps); err != nil {
+			return err
+		}
+	case "force":
+		if len(args) < 2 {
+			return errors.New(migrateUsage)
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid version %q: %w", args[1], err)
+		}
+		if err := migrator.Force(version); err != nil {
+			return err
+		}
+	case "version":
+	default:
+		return fmt.Errorf("unknown migrate command %q; %s", args[0], migrateUsage)
+	}
+
+	version, dirty, err := migrator.Version()
+	if err != nil {
+		return err
+	}
+	latest, err := migration.LatestVersion()
+	if err != nil {
+		return err
+	}
+	logger.Infof("Schema version: %d (dirty: %t), latest embedded migration: %d", version, dirty, latest)
+	return nil
+}
+
+// ensureSchema сверяет схему БД со встроенными миграциями перед стартом сервера.
+// Отстающая схема доводится до актуальной при database.auto_migrate, иначе
+// сервер не запускается: расхождение всплыло бы только ошибками SQL в запросах.
+// Схема новее сборки допустима — так бывает во время выкатки.
+func ensureSchema(ctx context.Context, cfg config.DatabaseConfig, conn *sql.DB, logger logging.Logger) error {
+	status, err := migration.ReadStatus(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case status.Dirty:
+		return fmt.Errorf("%w; fix the schema and run 'migrate force %d'", status.Err(), status.Current)
+	case status.Ahead():
+		logger.Warnf("Database schema is newer than this build: %d > %d", status.Current, status.Expected)
+		return nil
+	case !status.Behind():
+		logger.Infof("Database schema is up to date (version %d)", status.Current)
+		return nil
+	case !cfg.AutoMigrate:
+		return fmt.Errorf("%w; run 'migrate up' or set database.auto_migrate", status.Err())
+	}
+
+	logger.Infof("Applying migrations: %d -> %d (database.auto_migrate)", status.Current, status.Expected)
+	migrator, closeMigrator, err := openMigrator(ctx, cfg, logger)
+	if err != nil {
+		return err
+	}
+	defer closeMigrator()
+	return migrator.Up()
+}
+
+// openMigrator открывает для миграций отдельное соединение: Migrator закрывает
+// его при Close, поэтому соединение Store ему не передаётся.
+func openMigrator(ctx context.Context, cfg config.DatabaseConfig, logger logging.Logger) (*migration.Migrator, func(), error) {
+	conn, closeDB, err := postgres.Open(ctx, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error connecting to database: %w", err)
+	}
+	migrator, err := migration.NewMigrator(conn, logger)
+	if err != nil {
+		closeDB()
+		return nil, nil, err
+	}
+	return migrator, func() {
+		if err := migrator.Close(); err != nil {
+			logger.Warnf("error closing migrator: %v", err)
+		}
+		closeDB()
+	}, nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/config"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/server"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/auth"
+	"github.com/zhukovvlad/tenders-go/cmd/internal/services/secrets"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/logging"
+)
+
+// watchConfigReload перечитывает конфигурацию по SIGHUP и применяет секции,
+// которые не требуют перезапуска: cors, rate_limit, log, а также секреты JWT
+// из провайдера секретов. Изменения остальных секций (и DSN базы) только
+// перечисляются в логе. Конфигурация или секреты с ошибками отклоняются
+// целиком — процесс продолжает работать со старыми значениями.
+//
+// Переменные окружения процесса не меняются, поэтому по SIGHUP применяются
+// правки YAML-файлов и секреты из file/vault; значение из окружения
+// по-прежнему перекрывает файл.
+func watchConfigReload(running *config.Config, srv *server.Server, authService *auth.Service, provider secrets.Provider, logger logging.Logger) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	for range hup {
+		next, applied, err := config.Load(config.DefaultConfigDir, os.Getenv(config.ProfileEnvVar))
+		if err != nil {
+			logger.Errorf("SIGHUP: конфигурация не перечитана, работаем со старой: %v", err)
+			continue
+		}
+		if err := secrets.Apply(context.Background(), provider, next); err != nil {
+			logger.Errorf("SIGHUP: секреты не получены, работаем со старой конфигурацией: %v", err)
+			continue
+		}
+
+		authService.RotateJWTSecrets(next.Auth.JWTSecret, next.Auth.JWTSecretPrevious)
+		if err := logging.SetLevel(next.Log.Level); err != nil {
+			logger.Errorf("SIGHUP: уровень логирования %q не применён: %v", next.Log.Level, err)
+		}
+		srv.ApplyReloadable(next)
+		logger.Infof("SIGHUP: применены cors, rate_limit, log и секреты JWT (слои: %v, уровень: %s, cors origins: %d, worker rps: %g, burst: %d, лимиты API: %s, user rps: %g, предыдущий секрет JWT: %t)",
+			applied, next.Log.Level, len(next.CORS.AllowedOrigins), next.RateLimit.ServiceRPS, next.RateLimit.ServiceBurst,
+			next.RateLimit.Driver, next.RateLimit.UserRPS, next.Auth.JWTSecretPrevious != "")
+
+		changed, err := config.RestartRequired(running, next)
+		if err != nil {
+			logger.Errorf("SIGHUP: не удалось сравнить конфигурации: %v", err)
+			continue
+		}
+		if len(changed) > 0 {
+			logger.Warnf("SIGHUP: изменения в секциях %v вступят в силу только после перезапуска", changed)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Package buildinfo хранит версию и коммит сборки.
+//
+// Значения задаются при сборке через -ldflags (см. make build):
+//
+//	-X github.com/zhukovvlad/tenders-go/cmd/pkg/buildinfo.Version=v1.2.3
+//	-X github.com/zhukovvlad/tenders-go/cmd/pkg/buildinfo.Commit=abc1234
+//	-X github.com/zhukovvlad/tenders-go/cmd/pkg/buildinfo.BuildTime=2025-01-01T00:00:00Z
+//
+// Если коммит не задан, он берётся из VCS-информации, которую go build
+// встраивает сам (недоступна при go run).
+package buildinfo
+
+import "runtime/debug"
+
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+// Info — сведения о сборке для /healthz и /readyz.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildTime string `json:"build_time,omitempty"`
+	Modified  bool   `json:"modified,omitempty"` // Собрано из рабочей копии с незакоммиченными изменениями
+}
+
+// Get возвращает сведения о текущей сборке.
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, BuildTime: BuildTime}
+	if info.Commit != "" {
+		return info
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Commit = setting.Value
+		case "vcs.time":
+			if info.BuildTime == "" {
+				info.BuildTime = setting.Value
+			}
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}
@@ -0,0 +1,251 @@
+// Package docx записывает простые документы Word (Office Open XML) без внешних зависимостей.
+//
+// Поддерживается ровно то, что нужно для протоколов: заголовки, абзацы и
+// таблицы с жирной строкой заголовка и рамками. Страница A4 книжная, шрифт
+// задаётся по умолчанию для всего документа.
+//
+//	doc := docx.New()
+//	doc.AddHeading("Протокол", 1)
+//	doc.AddParagraph("Лот 1: Бетонные работы")
+//	doc.AddTable([]string{"Место", "Подрядчик", "Цена"},
+//		[]docx.Cell{docx.Text("1"), docx.Text("ООО Альфа"), docx.Number("100000.00")})
+//	err := doc.Write(w)
+package docx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ContentType — MIME-тип файла .docx.
+const ContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+// textWidth — ширина текста страницы A4 с полями (twips): 11906 - 1701 - 850.
+const textWidth = 9355
+
+// Cell — значение ячейки таблицы.
+type Cell struct {
+	value string
+	right bool
+	bold  bool
+}
+
+// Text возвращает текстовую ячейку.
+func Text(v string) Cell { return Cell{value: v} }
+
+// Number возвращает ячейку, выровненную вправо (суммы, проценты).
+func Number(v string) Cell { return Cell{value: v, right: true} }
+
+// Bold возвращает ячейку жирным шрифтом (итоговые строки).
+func Bold(c Cell) Cell {
+	c.bold = true
+	return c
+}
+
+// Document — документ Word: абзацы и таблицы по порядку.
+type Document struct {
+	body strings.Builder
+}
+
+// New создаёт пустой документ.
+func New() *Document {
+	return &Document{}
+}
+
+// AddHeading добавляет заголовок: level 1 — по центру 14 pt, иначе 12 pt.
+func (d *Document) AddHeading(text string, level int) {
+	size, align := 24, ""
+	if level <= 1 {
+		size, align = 28, "center"
+	}
+	d.paragraph(text, paragraphStyle{bold: true, size: size, align: align, spaceBefore: 240})
+}
+
+// AddParagraph добавляет абзац обычного текста. Переводы строк сохраняются.
+func (d *Document) AddParagraph(text string) {
+	d.paragraph(text, paragraphStyle{})
+}
+
+// AddTable добавляет таблицу: columns — строка заголовка (жирным), rows —
+// строки значений. Ширина столбцов одинаковая, таблица занимает всю ширину текста.
+func (d *Document) AddTable(columns []string, rows ...[]Cell) {
+	cols := len(columns)
+	for _, row := range rows {
+		cols = max(cols, len(row))
+	}
+	if cols == 0 {
+		return
+	}
+	width := textWidth / cols
+
+	b := &d.body
+	b.WriteString(`<w:tbl><w:tblPr><w:tblW w:w="5000" w:type="pct"/><w:tblBorders>`)
+	for _, side := range []string{"top", "left", "bottom", "right", "insideH", "insideV"} {
+		fmt.Fprintf(b, `<w:%s w:val="single" w:sz="4" w:space="0" w:color="999999"/>`, side)
+	}
+	b.WriteString(`</w:tblBorders><w:tblCellMar><w:left w:w="80" w:type="dxa"/><w:right w:w="80" w:type="dxa"/></w:tblCellMar></w:tblPr><w:tblGrid>`)
+	for range cols {
+		fmt.Fprintf(b, `<w:gridCol w:w="%d"/>`, width)
+	}
+	b.WriteString(`</w:tblGrid>`)
+
+	header := make([]Cell, len(columns))
+	for i, title := range columns {
+		header[i] = Cell{value: title, bold: true}
+	}
+	if len(header) > 0 {
+		d.row(header, cols, width, true)
+	}
+	for _, row := range rows {
+		d.row(row, cols, width, false)
+	}
+	b.WriteString(`</w:tbl>`)
+	// Word требует абзац между таблицей и следующей таблицей или концом документа
+	d.paragraph("", paragraphStyle{})
+}
+
+func (d *Document) row(cells []Cell, cols, width int, header bool) {
+	b := &d.body
+	b.WriteString(`<w:tr>`)
+	if header {
+		b.WriteString(`<w:trPr><w:tblHeader/></w:trPr>`)
+	}
+	for i := range cols {
+		var c Cell
+		if i < len(cells) {
+			c = cells[i]
+		}
+		fmt.Fprintf(b, `<w:tc><w:tcPr><w:tcW w:w="%d" w:type="dxa"/>`, width)
+		if header {
+			b.WriteString(`<w:shd w:val="clear" w:color="auto" w:fill="F0F0F0"/>`)
+		}
+		b.WriteString(`</w:tcPr>`)
+		style := paragraphStyle{bold: c.bold, size: 20}
+		if c.right {
+			style.align = "right"
+		}
+		d.paragraph(c.value, style)
+		b.WriteString(`</w:tc>`)
+	}
+	b.WriteString(`</w:tr>`)
+}
+
+type paragraphStyle struct {
+	bold        bool
+	size        int // Полупункты; 0 — размер по умолчанию
+	align       string
+	spaceBefore int // Twips
+}
+
+func (d *Document) paragraph(text string, style paragraphStyle) {
+	b := &d.body
+	b.WriteString(`<w:p>`)
+	if style.align != "" || style.spaceBefore > 0 {
+		b.WriteString(`<w:pPr>`)
+		if style.spaceBefore > 0 {
+			fmt.Fprintf(b, `<w:spacing w:before="%d"/>`, style.spaceBefore)
+		}
+		if style.align != "" {
+			fmt.Fprintf(b, `<w:jc w:val="%s"/>`, style.align)
+		}
+		b.WriteString(`</w:pPr>`)
+	}
+	if text != "" {
+		b.WriteString(`<w:r>`)
+		if style.bold || style.size > 0 {
+			b.WriteString(`<w:rPr>`)
+			if style.bold {
+				b.WriteString(`<w:b/>`)
+			}
+			if style.size > 0 {
+				fmt.Fprintf(b, `<w:sz w:val="%d"/>`, style.size)
+			}
+			b.WriteString(`</w:rPr>`)
+		}
+		for i, line := range strings.Split(text, "\n") {
+			if i > 0 {
+				b.WriteString(`<w:br/>`)
+			}
+			fmt.Fprintf(b, `<w:t xml:space="preserve">%s</w:t>`, escape(line))
+		}
+		b.WriteString(`</w:r>`)
+	}
+	b.WriteString(`</w:p>`)
+}
+
+// Write записывает документ в формате .docx.
+func (d *Document) Write(out io.Writer) error {
+	zw := zip.NewWriter(out)
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypes},
+		{"_rels/.rels", rootRels},
+		{"word/_rels/document.xml.rels", documentRels},
+		{"word/styles.xml", styles},
+		{"word/document.xml", d.document()},
+	}
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return fmt.Errorf("docx: %s: %w", f.name, err)
+		}
+		if _, err := io.WriteString(w, f.content); err != nil {
+			return fmt.Errorf("docx: %s: %w", f.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("docx: %w", err)
+	}
+	return nil
+}
+
+func (d *Document) document() string {
+	return xmlHeader +
+		`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		d.body.String() +
+		`<w:sectPr><w:pgSz w:w="11906" w:h="16838"/>` +
+		`<w:pgMar w:top="1134" w:right="850" w:bottom="1134" w:left="1701" w:header="708" w:footer="708" w:gutter="0"/>` +
+		`</w:sectPr></w:body></w:document>`
+}
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+const contentTypes = xmlHeader +
+	`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+	`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+	`</Types>`
+
+const rootRels = xmlHeader +
+	`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+	`</Relationships>`
+
+const documentRels = xmlHeader +
+	`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+// styles: шрифт по умолчанию — Times New Roman 11 pt, абзацы без отступа после.
+const styles = xmlHeader +
+	`<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+	`<w:docDefaults><w:rPrDefault><w:rPr>` +
+	`<w:rFonts w:ascii="Times New Roman" w:hAnsi="Times New Roman" w:cs="Times New Roman" w:eastAsia="Times New Roman"/>` +
+	`<w:sz w:val="22"/><w:szCs w:val="22"/><w:lang w:val="ru-RU"/>` +
+	`</w:rPr></w:rPrDefault><w:pPrDefault><w:pPr><w:spacing w:after="60"/></w:pPr></w:pPrDefault></w:docDefaults>` +
+	`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/></w:style>` +
+	`</w:styles>`
+
+// escape экранирует текст для XML. Недопустимые в XML символы заменяются на U+FFFD.
+func escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,120 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR DOCX WRITER (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Broken files — Word refuses a document with malformed XML or a missing part
+2. Injected markup — contractor names with <, & or quotes must not break the document XML
+3. Ragged tables — rows shorter than the header must still have a cell per column
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Write
+- GIVEN a document with a heading, a multi-line paragraph and a table
+  WHEN Write is called
+  THEN the zip contains every OOXML part, each one well-formed XML
+  AND text is escaped, line breaks become <w:br/>, the header row repeats on every page
+  AND numeric cells are right-aligned
+
+SCENARIO 2: Table shape
+- GIVEN a table with 3 columns and a row of 1 cell
+  WHEN Write is called
+  THEN every row has 3 cells and the grid has 3 columns
+
+SCENARIO 3: Empty document
+- GIVEN a document without content
+  WHEN Write is called
+  THEN a valid document with only the section properties is written
+*/
+
+func readParts(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	parts := make(map[string]string, len(zr.File))
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		parts[f.Name] = string(content)
+	}
+	return parts
+}
+
+func assertWellFormed(t *testing.T, name, content string) {
+	t.Helper()
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return
+		}
+		require.NoError(t, err, "part %s is not well-formed XML", name)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	doc := New()
+	doc.AddHeading("Протокол <№1>", 1)
+	doc.AddParagraph("Тендер: A & B\nЛот 1")
+	doc.AddTable([]string{"Подрядчик", "Цена"},
+		[]Cell{Text(`ООО "Альфа"`), Number("100000.00")},
+		[]Cell{Bold(Text("Итого")), Bold(Number("100000.00"))},
+	)
+
+	var buf bytes.Buffer
+	require.NoError(t, doc.Write(&buf))
+
+	parts := readParts(t, buf.Bytes())
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "word/_rels/document.xml.rels", "word/styles.xml", "word/document.xml"} {
+		require.Contains(t, parts, name)
+		assertWellFormed(t, name, parts[name])
+	}
+
+	body := parts["word/document.xml"]
+	assert.Contains(t, body, "Протокол &lt;№1&gt;")
+	assert.Contains(t, body, `Тендер: A &amp; B</w:t><w:br/><w:t xml:space="preserve">Лот 1`)
+	assert.Contains(t, body, `ООО &#34;Альфа&#34;`)
+	assert.Contains(t, body, `<w:tblHeader/>`)
+	assert.Contains(t, body, `<w:jc w:val="right"/></w:pPr><w:r><w:rPr><w:sz w:val="20"/></w:rPr><w:t xml:space="preserve">100000.00`)
+	assert.Contains(t, body, `<w:rPr><w:b/><w:sz w:val="20"/></w:rPr><w:t xml:space="preserve">Итого`)
+}
+
+func TestWrite_PadsShortRows(t *testing.T) {
+	doc := New()
+	doc.AddTable([]string{"A", "B", "C"}, []Cell{Text("1")})
+
+	var buf bytes.Buffer
+	require.NoError(t, doc.Write(&buf))
+	body := readParts(t, buf.Bytes())["word/document.xml"]
+
+	assert.Equal(t, 3, strings.Count(body, "<w:gridCol "))
+	assert.Equal(t, 2, strings.Count(body, "<w:tr>"))
+	assert.Equal(t, 6, strings.Count(body, "<w:tc>"))
+}
+
+func TestWrite_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, New().Write(&buf))
+
+	body := readParts(t, buf.Bytes())["word/document.xml"]
+	assertWellFormed(t, "word/document.xml", body)
+	assert.Contains(t, body, "<w:body><w:sectPr>")
+}
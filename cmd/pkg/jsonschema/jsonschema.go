@@ -0,0 +1,381 @@
+// Package jsonschema проверяет JSON-документы по подмножеству JSON Schema.
+//
+// Поддерживаются ключевые слова, которых достаточно для описания плоских
+// параметров лота: type, properties, required, additionalProperties (bool или
+// схема), items, enum, minimum, maximum, minLength, maxLength, pattern, а также
+// аннотации $schema, $id, title, description. Неизвестное ключевое слово — ошибка
+// компиляции: схема, которая молча игнорирует часть правил, хуже отказа.
+//
+// Validate возвращает все найденные нарушения сразу, с путём в нотации
+// JSON Pointer (/area, /floors/0), чтобы по одной ошибке было видно весь ответ.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Типы JSON Schema.
+const (
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeString  = "string"
+	TypeNumber  = "number"
+	TypeInteger = "integer"
+	TypeBoolean = "boolean"
+	TypeNull    = "null"
+)
+
+var knownTypes = map[string]bool{
+	TypeObject: true, TypeArray: true, TypeString: true, TypeNumber: true,
+	TypeInteger: true, TypeBoolean: true, TypeNull: true,
+}
+
+// Schema — скомпилированная схема. Создаётся через Compile.
+type Schema struct {
+	types      []string
+	properties map[string]*Schema
+	required   []string
+	// additional — схема дополнительных свойств; nil вместе с
+	// noAdditional=false означает, что они разрешены без ограничений.
+	additional   *Schema
+	noAdditional bool
+	items        *Schema
+	enum         []interface{}
+	minimum      *float64
+	maximum      *float64
+	minLength    *int
+	maxLength    *int
+	pattern      *regexp.Regexp
+}
+
+// rawSchema — схема в исходном виде. Поля с несколькими допустимыми формами
+// (type, additionalProperties) разбираются вручную.
+type rawSchema struct {
+	Schema               string                     `json:"$schema"`
+	ID                   string                     `json:"$id"`
+	Title                string                     `json:"title"`
+	Description          string                     `json:"description"`
+	Type                 json.RawMessage            `json:"type"`
+	Properties           map[string]json.RawMessage `json:"properties"`
+	Required             []string                   `json:"required"`
+	AdditionalProperties json.RawMessage            `json:"additionalProperties"`
+	Items                json.RawMessage            `json:"items"`
+	Enum                 []json.RawMessage          `json:"enum"`
+	Minimum              *float64                   `json:"minimum"`
+	Maximum              *float64                   `json:"maximum"`
+	MinLength            *int                       `json:"minLength"`
+	MaxLength            *int                       `json:"maxLength"`
+	Pattern              *string                    `json:"pattern"`
+}
+
+// Compile разбирает схему и проверяет, что она корректна. Ошибка содержит путь
+// к некорректному месту схемы.
+func Compile(raw []byte) (*Schema, error) {
+	return compile(raw, "")
+}
+
+func compile(raw []byte, path string) (*Schema, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	var r rawSchema
+	if err := dec.Decode(&r); err != nil {
+		return nil, fmt.Errorf("схема %s: %w", pointer(path), err)
+	}
+
+	s := &Schema{
+		required:  r.Required,
+		minimum:   r.Minimum,
+		maximum:   r.Maximum,
+		minLength: r.MinLength,
+		maxLength: r.MaxLength,
+	}
+
+	types, err := parseTypes(r.Type)
+	if err != nil {
+		return nil, fmt.Errorf("схема %s: %w", pointer(path), err)
+	}
+	s.types = types
+
+	if len(r.Properties) > 0 {
+		s.properties = make(map[string]*Schema, len(r.Properties))
+		for name, prop := range r.Properties {
+			child, err := compile(prop, path+"/properties/"+escape(name))
+			if err != nil {
+				return nil, err
+			}
+			s.properties[name] = child
+		}
+	}
+
+	if len(r.AdditionalProperties) > 0 {
+		var allowed bool
+		if err := json.Unmarshal(r.AdditionalProperties, &allowed); err == nil {
+			s.noAdditional = !allowed
+		} else {
+			child, err := compile(r.AdditionalProperties, path+"/additionalProperties")
+			if err != nil {
+				return nil, err
+			}
+			s.additional = child
+		}
+	}
+
+	if len(r.Items) > 0 {
+		child, err := compile(r.Items, path+"/items")
+		if err != nil {
+			return nil, err
+		}
+		s.items = child
+	}
+
+	for _, value := range r.Enum {
+		v, err := decode(value)
+		if err != nil {
+			return nil, fmt.Errorf("схема %s: некорректное значение enum: %w", pointer(path), err)
+		}
+		s.enum = append(s.enum, v)
+	}
+
+	if r.Pattern != nil {
+		re, err := regexp.Compile(*r.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("схема %s: некорректный pattern: %w", pointer(path), err)
+		}
+		s.pattern = re
+	}
+
+	if (s.minLength != nil && *s.minLength < 0) || (s.maxLength != nil && *s.maxLength < 0) {
+		return nil, fmt.Errorf("схема %s: minLength и maxLength не могут быть отрицательными", pointer(path))
+	}
+	return s, nil
+}
+
+func parseTypes(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var types []string
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		types = []string{single}
+	} else if err := json.Unmarshal(raw, &types); err != nil {
+		return nil, fmt.Errorf("type должен быть строкой или массивом строк")
+	}
+	for _, t := range types {
+		if !knownTypes[t] {
+			return nil, fmt.Errorf("неизвестный type %q", t)
+		}
+	}
+	return types, nil
+}
+
+// FieldError — одно нарушение схемы.
+type FieldError struct {
+	Path    string `json:"path"` // JSON Pointer; пустой — корень документа
+	Message string `json:"message"`
+}
+
+// ValidationError — все нарушения схемы в документе.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		parts = append(parts, pointer(fe.Path)+": "+fe.Message)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Validate проверяет JSON-документ. Нарушения схемы возвращаются как
+// *ValidationError, некорректный JSON — обычной ошибкой.
+func (s *Schema) Validate(document []byte) error {
+	v, err := decode(document)
+	if err != nil {
+		return fmt.Errorf("некорректный JSON: %w", err)
+	}
+	var errs []FieldError
+	s.validate(v, "", &errs)
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// PropertyTypes возвращает допустимые типы свойства объекта name: из
+// properties, а для необъявленного свойства — из additionalProperties. Пустой
+// список — тип не ограничен; ok=false — свойство схемой запрещено.
+func (s *Schema) PropertyTypes(name string) (types []string, ok bool) {
+	if prop, declared := s.properties[name]; declared {
+		return prop.types, true
+	}
+	if s.noAdditional {
+		return nil, false
+	}
+	if s.additional != nil {
+		return s.additional.types, true
+	}
+	return nil, true
+}
+
+func (s *Schema) validate(v interface{}, path string, errs *[]FieldError) {
+	add := func(format string, args ...interface{}) {
+		*errs = append(*errs, FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(s.types) > 0 && !matchesAnyType(v, s.types) {
+		add("ожидается %s, получено %s", strings.Join(s.types, " или "), typeOf(v))
+		return
+	}
+
+	if len(s.enum) > 0 && !inEnum(v, s.enum) {
+		add("значение не входит в список допустимых")
+	}
+
+	switch value := v.(type) {
+	case map[string]interface{}:
+		s.validateObject(value, path, errs)
+	case []interface{}:
+		if s.items != nil {
+			for i, item := range value {
+				s.items.validate(item, path+"/"+strconv.Itoa(i), errs)
+			}
+		}
+	case string:
+		length := utf8.RuneCountInString(value)
+		if s.minLength != nil && length < *s.minLength {
+			add("длина строки меньше %d", *s.minLength)
+		}
+		if s.maxLength != nil && length > *s.maxLength {
+			add("длина строки больше %d", *s.maxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(value) {
+			add("строка не соответствует шаблону %s", s.pattern.String())
+		}
+	case json.Number:
+		f, _ := value.Float64()
+		if s.minimum != nil && f < *s.minimum {
+			add("значение меньше %v", *s.minimum)
+		}
+		if s.maximum != nil && f > *s.maximum {
+			add("значение больше %v", *s.maximum)
+		}
+	}
+}
+
+func (s *Schema) validateObject(obj map[string]interface{}, path string, errs *[]FieldError) {
+	for _, name := range s.required {
+		if _, ok := obj[name]; !ok {
+			*errs = append(*errs, FieldError{Path: path + "/" + escape(name), Message: "обязательное поле отсутствует"})
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		childPath := path + "/" + escape(name)
+		if prop, ok := s.properties[name]; ok {
+			prop.validate(obj[name], childPath, errs)
+			continue
+		}
+		switch {
+		case s.noAdditional:
+			*errs = append(*errs, FieldError{Path: childPath, Message: "неизвестное поле"})
+		case s.additional != nil:
+			s.additional.validate(obj[name], childPath, errs)
+		}
+	}
+}
+
+func matchesAnyType(v interface{}, types []string) bool {
+	actual := typeOf(v)
+	for _, t := range types {
+		if t == actual || (t == TypeNumber && actual == TypeInteger) {
+			return true
+		}
+	}
+	return false
+}
+
+// typeOf возвращает тип значения в терминах JSON Schema. Целые числа (в том
+// числе 10.0) — integer.
+func typeOf(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return TypeNull
+	case bool:
+		return TypeBoolean
+	case string:
+		return TypeString
+	case []interface{}:
+		return TypeArray
+	case map[string]interface{}:
+		return TypeObject
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return TypeInteger
+		}
+		if f, err := value.Float64(); err == nil && f == math.Trunc(f) && !math.IsInf(f, 0) {
+			return TypeInteger
+		}
+		return TypeNumber
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func inEnum(v interface{}, enum []interface{}) bool {
+	for _, allowed := range enum {
+		if equal(v, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// equal сравнивает значения JSON; числа — по величине (1 и 1.0 равны).
+func equal(a, b interface{}) bool {
+	na, okA := a.(json.Number)
+	nb, okB := b.(json.Number)
+	if okA && okB {
+		fa, errA := na.Float64()
+		fb, errB := nb.Float64()
+		return errA == nil && errB == nil && fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func decode(raw []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// escape экранирует имя свойства для JSON Pointer (RFC 6901).
+func escape(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
+
+func pointer(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
@@ -0,0 +1,164 @@
+package jsonschema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR JSON SCHEMA VALIDATION (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Malformed AI output — wrong types and unknown fields must be rejected before they reach the frontend
+2. Useless errors — every violation must be reported at once, with its JSON Pointer path
+3. Silently ignored rules — a schema with unsupported keywords must fail to compile
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Compile
+- GIVEN a schema with an unknown keyword, an unknown type or a bad pattern
+  WHEN Compile is called
+  THEN an error pointing at the broken place is returned
+
+SCENARIO 2: Validate
+- GIVEN an object matching the schema
+  WHEN Validate is called
+  THEN no error is returned (10.0 counts as integer, integer counts as number)
+
+- GIVEN an object with a wrong type, an unknown field, a missing required field,
+  an out-of-range number and a value outside enum
+  WHEN Validate is called
+  THEN *ValidationError lists every violation with its path, sorted by field
+
+- GIVEN additionalProperties given as a schema
+  WHEN Validate is called
+  THEN extra fields are checked against it instead of being rejected
+
+SCENARIO 3: PropertyTypes
+- GIVEN a declared property, an undeclared one with additionalProperties false,
+  a schema or no restriction
+  WHEN PropertyTypes is called
+  THEN the declared types, ok=false, the additionalProperties types or no types are returned
+*/
+
+const lotSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"required": ["area"],
+	"additionalProperties": false,
+	"properties": {
+		"area": {"type": "number", "minimum": 0},
+		"floors": {"type": "integer", "maximum": 100},
+		"class": {"type": "string", "enum": ["A", "B", "C"]},
+		"code": {"type": ["string", "null"], "pattern": "^[0-9]{3}$", "maxLength": 3},
+		"sections": {"type": "array", "items": {"type": "string", "minLength": 1}}
+	}
+}`
+
+func TestCompile_InvalidSchemas(t *testing.T) {
+	cases := map[string]string{
+		"unknown keyword":  `{"type": "object", "oneOf": []}`,
+		"unknown type":     `{"properties": {"a": {"type": "decimal"}}}`,
+		"bad type form":    `{"type": 5}`,
+		"bad pattern":      `{"type": "string", "pattern": "("}`,
+		"negative length":  `{"type": "string", "minLength": -1}`,
+		"not a JSON value": `{`,
+	}
+	for name, raw := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Compile([]byte(raw))
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := Compile([]byte(`{"properties": {"a": {"type": "decimal"}}}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "/properties/a")
+}
+
+func TestValidate_ValidDocument(t *testing.T) {
+	schema, err := Compile([]byte(lotSchema))
+	require.NoError(t, err)
+
+	assert.NoError(t, schema.Validate([]byte(`{"area": 1500.5, "floors": 12.0, "class": "B", "code": null, "sections": ["1", "2"]}`)))
+	assert.NoError(t, schema.Validate([]byte(`{"area": 10}`)))
+}
+
+func TestValidate_ReportsAllViolations(t *testing.T) {
+	schema, err := Compile([]byte(lotSchema))
+	require.NoError(t, err)
+
+	err = schema.Validate([]byte(`{"floors": 2.5, "class": "D", "code": "12a", "sections": [""], "color": "red"}`))
+
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr), "expected *ValidationError, got: %v", err)
+	assert.Equal(t, []FieldError{
+		{Path: "/area", Message: "обязательное поле отсутствует"},
+		{Path: "/class", Message: "значение не входит в список допустимых"},
+		{Path: "/code", Message: "строка не соответствует шаблону ^[0-9]{3}$"},
+		{Path: "/color", Message: "неизвестное поле"},
+		{Path: "/floors", Message: "ожидается integer, получено number"},
+		{Path: "/sections/0", Message: "длина строки меньше 1"},
+	}, validationErr.Errors)
+	assert.Contains(t, err.Error(), "/color: неизвестное поле")
+}
+
+func TestValidate_RangeAndRootType(t *testing.T) {
+	schema, err := Compile([]byte(lotSchema))
+	require.NoError(t, err)
+
+	err = schema.Validate([]byte(`{"area": -1, "floors": 101}`))
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []FieldError{
+		{Path: "/area", Message: "значение меньше 0"},
+		{Path: "/floors", Message: "значение больше 100"},
+	}, validationErr.Errors)
+
+	err = schema.Validate([]byte(`["area"]`))
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "", validationErr.Errors[0].Path)
+
+	assert.Error(t, schema.Validate([]byte(`{`)), "некорректный JSON")
+}
+
+func TestValidate_AdditionalPropertiesSchema(t *testing.T) {
+	schema, err := Compile([]byte(`{"type": "object", "additionalProperties": {"type": "string"}}`))
+	require.NoError(t, err)
+
+	assert.NoError(t, schema.Validate([]byte(`{"a": "x", "b": "y"}`)))
+
+	err = schema.Validate([]byte(`{"a": "x", "b/c": 1}`))
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []FieldError{{Path: "/b~1c", Message: "ожидается string, получено integer"}}, validationErr.Errors)
+}
+
+func TestPropertyTypes(t *testing.T) {
+	schema, err := Compile([]byte(lotSchema))
+	require.NoError(t, err)
+
+	types, ok := schema.PropertyTypes("code")
+	assert.True(t, ok)
+	assert.Equal(t, []string{TypeString, TypeNull}, types)
+
+	_, ok = schema.PropertyTypes("color")
+	assert.False(t, ok)
+
+	additional, err := Compile([]byte(`{"type": "object", "additionalProperties": {"type": "number"}}`))
+	require.NoError(t, err)
+	types, ok = additional.PropertyTypes("piles")
+	assert.True(t, ok)
+	assert.Equal(t, []string{TypeNumber}, types)
+
+	open, err := Compile([]byte(`{"type": "object"}`))
+	require.NoError(t, err)
+	types, ok = open.PropertyTypes("piles")
+	assert.True(t, ok)
+	assert.Empty(t, types)
+}
@@ -123,3 +123,20 @@ func init() {
 
 	e = logrus.NewEntry(l)
 }
+
+// ValidateLevel проверяет имя уровня логирования (trace, debug, info, warn, error, fatal, panic).
+func ValidateLevel(level string) error {
+	_, err := logrus.ParseLevel(level)
+	return err
+}
+
+// SetLevel меняет уровень глобального логгера. Безопасен для вызова во время
+// работы: уровень читается атомарно при каждой записи.
+func SetLevel(level string) error {
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	e.Logger.SetLevel(lvl)
+	return nil
+}
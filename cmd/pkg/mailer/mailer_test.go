@@ -0,0 +1,111 @@
+package mailer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR MAIL MESSAGES (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Mojibake — Cyrillic subjects, bodies and attachment names must survive any mail client
+2. Broken attachments — binary files (XLSX, PDF) must arrive byte-for-byte
+3. Silent misdelivery — malformed addresses must fail before connecting to SMTP
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Message.Bytes
+- GIVEN a message with a Cyrillic subject, body and an attachment
+  WHEN Bytes is called and the result is parsed back
+  THEN headers, the text part and the attachment decode to the original values
+
+SCENARIO 2: Validation
+- GIVEN a malformed sender, a malformed recipient or no recipients
+  WHEN Bytes is called
+  THEN ErrInvalidMessage is returned (the letter is never retried)
+*/
+
+func TestMessageBytes_RoundTrip(t *testing.T) {
+	attachment := []byte{0x50, 0x4b, 0x03, 0x04, 0x00, 0xff}
+	attachment = append(attachment, bytes.Repeat([]byte("x"), 200)...)
+
+	raw, err := Message{
+		From:    "Тендеры <reports@example.com>",
+		To:      []string{"a@example.com", "Б <b@example.com>"},
+		Subject: "Экономия за неделю",
+		Body:    "Отчет во вложении.",
+		Attachments: []Attachment{
+			{Filename: "экономия.xlsx", ContentType: "application/vnd.ms-excel", Data: attachment},
+		},
+	}.Bytes()
+	require.NoError(t, err)
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Экономия за неделю", subject)
+
+	to, err := msg.Header.AddressList("To")
+	require.NoError(t, err)
+	require.Len(t, to, 2)
+	assert.Equal(t, "b@example.com", to[1].Address)
+	assert.Equal(t, "Б", to[1].Name)
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	text, err := reader.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "Отчет во вложении.", string(decodePart(t, text)))
+
+	file, err := reader.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "экономия.xlsx", file.FileName())
+	assert.Equal(t, attachment, decodePart(t, file))
+
+	_, err = reader.NextPart()
+	assert.Equal(t, io.EOF, err)
+}
+
+func decodePart(t *testing.T, part *multipart.Part) []byte {
+	t.Helper()
+	require.Equal(t, "base64", part.Header.Get("Content-Transfer-Encoding"))
+	encoded, err := io.ReadAll(part)
+	require.NoError(t, err)
+	for _, line := range strings.Split(strings.TrimSpace(string(encoded)), "\r\n") {
+		assert.LessOrEqual(t, len(line), base64LineLength)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+	require.NoError(t, err)
+	return decoded
+}
+
+func TestMessageBytes_Validation(t *testing.T) {
+	cases := map[string]Message{
+		"bad sender":    {From: "reports", To: []string{"a@example.com"}},
+		"bad recipient": {From: "reports@example.com", To: []string{"a@example.com", "b"}},
+		"no recipients": {From: "reports@example.com"},
+	}
+	for name, msg := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := msg.Bytes()
+			assert.ErrorIs(t, err, ErrInvalidMessage)
+		})
+	}
+}
@@ -0,0 +1,410 @@
+// Package openapi собирает спецификацию OpenAPI 3.0 из типизированных описаний маршрутов.
+//
+// Маршрут описывается структурой Route: метод, путь в нотации gin, DTO запроса
+// и ответа. Схемы DTO строятся рефлексией по тем же правилам encoding/json,
+// что и у tsgen: теги json (имя, "-", omitempty), встроенные структуры без тега
+// разворачиваются в родителя, указатели становятся nullable. Именованные структуры
+// выносятся в components/schemas и подключаются через $ref.
+//
+// Обязательность полей зависит от направления: в теле запроса обязательны поля
+// с binding:"required" (так их проверяет gin), в ответе — поля без omitempty
+// (encoding/json выводит их всегда).
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Version — версия спецификации OpenAPI в документе.
+const Version = "3.0.3"
+
+// Схемы аутентификации маршрутов (Route.Auth).
+const (
+	AuthNone    = ""        // публичный маршрут
+	AuthUser    = "user"    // пользователь: access-токен в cookie, для изменяющих запросов — X-CSRF-Token
+	AuthService = "service" // внутренний сервис: Authorization: Bearer <ключ>
+)
+
+// Route — описание одного маршрута API.
+type Route struct {
+	Method string // GET, POST, PUT, PATCH, DELETE
+	Path   string // путь в нотации gin: /api/v1/tenders/:id
+	Tag    string
+	// Summary — краткое описание, Description — подробности (необязательно).
+	Summary     string
+	Description string
+	Auth        string
+	// Permission — право роли или scope ключа сервиса; попадает в описание операции.
+	Permission string
+
+	// PathParams уточняют параметры пути. Не описанный параметр считается целым числом.
+	PathParams []Param
+	Query      []Param
+
+	// Request — значение DTO тела запроса (nil — без тела).
+	Request any
+	// RequestContentType по умолчанию application/json. Для multipart/form-data
+	// поля формы задаются в Form.
+	RequestContentType string
+	Form               []Param
+
+	// Status — код успешного ответа (по умолчанию 200).
+	Status int
+	// Response — значение DTO ответа (nil — без тела).
+	Response any
+	// ResponseContentType по умолчанию application/json.
+	ResponseContentType string
+}
+
+// Param — параметр пути, строки запроса или поле формы.
+type Param struct {
+	Name        string
+	Type        string // integer (по умолчанию), string, boolean, number, file
+	Format      string // date, int32, int64, ...
+	Description string
+	Required    bool
+	Enum        []string
+	Default     any
+}
+
+// Spec — входные данные для Build.
+type Spec struct {
+	Info Info
+	// AccessCookie — имя cookie с access-токеном пользователя (auth.cookie_access_name).
+	AccessCookie string
+	Routes       []Route
+}
+
+// Info — заголовок документа.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Document — корень спецификации OpenAPI.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Tags       []Tag                `json:"tags,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Tag — группа операций.
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// PathItem — операции одного пути по HTTP-методам.
+type PathItem struct {
+	Get    *Operation `json:"get,omitempty"`
+	Post   *Operation `json:"post,omitempty"`
+	Put    *Operation `json:"put,omitempty"`
+	Patch  *Operation `json:"patch,omitempty"`
+	Delete *Operation `json:"delete,omitempty"`
+}
+
+// Operation — одна операция API.
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	OperationID string                `json:"operationId"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter — параметр операции.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody — тело запроса.
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response — ответ операции.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType — схема тела для одного Content-Type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components — переиспользуемые схемы и схемы аутентификации.
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+// SecurityScheme — способ аутентификации.
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme,omitempty"`
+	In          string `json:"in,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Schema — подмножество JSON Schema, которое использует OpenAPI 3.0.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+}
+
+// ErrorSchemaName — схема ответа с ошибкой {"code", "message", "details"}.
+const ErrorSchemaName = "Error"
+
+var pathParamPattern = regexp.MustCompile(`[:*]([A-Za-z_][A-Za-z0-9_]*)`)
+
+// Build собирает документ из описаний маршрутов. Возвращает ошибку при
+// повторном описании маршрута, неизвестном методе или конфликте имён схем.
+func Build(spec Spec) (*Document, error) {
+	g := newGenerator()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    spec.Info,
+		Paths:   make(map[string]*PathItem),
+		Components: Components{
+			Schemas: g.schemas,
+			SecuritySchemes: map[string]SecurityScheme{
+				"cookieAuth": {
+					Type:        "apiKey",
+					In:          "cookie",
+					Name:        spec.AccessCookie,
+					Description: "Access-токен пользователя (POST /api/v1/auth/login). Изменяющие запросы дополнительно передают заголовок X-CSRF-Token со значением cookie csrf_token.",
+				},
+				"serviceAuth": {
+					Type:        "http",
+					Scheme:      "bearer",
+					Description: "Ключ внутреннего сервиса (admin/service-credentials); доступ ограничен scopes ключа.",
+				},
+			},
+		},
+	}
+	g.schemas[ErrorSchemaName] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"code":    {Type: "string", Description: "Машиночитаемый код ошибки (validation_failed, not_found, access_token_expired...)"},
+			"message": {Type: "string"},
+			"details": {Type: "array", Items: &Schema{
+				Type: "object",
+				Properties: map[string]*Schema{
+					"field":   {Type: "string"},
+					"rule":    {Type: "string"},
+					"value":   {Type: "string"},
+					"message": {Type: "string"},
+				},
+				Required: []string{"message"},
+			}},
+			"conflicts": {Description: "Данные конфликта (только 409)"},
+			"error":     {Type: "string", Description: "Совпадает с message; прежний формат ответа"},
+		},
+		Required: []string{"code", "message", "error"},
+	}
+
+	seen := make(map[string]bool)
+	tags := make(map[string]bool)
+	for _, route := range spec.Routes {
+		key := route.Method + " " + route.Path
+		if seen[key] {
+			return nil, fmt.Errorf("openapi: route %s is described twice", key)
+		}
+		seen[key] = true
+
+		op, err := g.operation(route)
+		if err != nil {
+			return nil, fmt.Errorf("openapi: %s: %w", key, err)
+		}
+
+		path := pathParamPattern.ReplaceAllString(route.Path, "{$1}")
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+		if err := item.set(route.Method, op); err != nil {
+			return nil, fmt.Errorf("openapi: %s: %w", key, err)
+		}
+		if route.Tag != "" && !tags[route.Tag] {
+			tags[route.Tag] = true
+			doc.Tags = append(doc.Tags, Tag{Name: route.Tag})
+		}
+	}
+
+	g.finalizeRequired()
+	return doc, nil
+}
+
+func (p *PathItem) set(method string, op *Operation) error {
+	switch method {
+	case http.MethodGet:
+		p.Get = op
+	case http.MethodPost:
+		p.Post = op
+	case http.MethodPut:
+		p.Put = op
+	case http.MethodPatch:
+		p.Patch = op
+	case http.MethodDelete:
+		p.Delete = op
+	default:
+		return fmt.Errorf("unsupported method %q", method)
+	}
+	return nil
+}
+
+func (g *generator) operation(route Route) (*Operation, error) {
+	op := &Operation{
+		Summary:     route.Summary,
+		Description: route.Description,
+		OperationID: operationID(route.Method, route.Path),
+		Responses:   make(map[string]Response),
+	}
+	if route.Tag != "" {
+		op.Tags = []string{route.Tag}
+	}
+	if route.Permission != "" {
+		requirement := "Требуемое право: " + route.Permission
+		if route.Auth == AuthService {
+			requirement = "Требуемый scope ключа: " + route.Permission
+		}
+		if op.Description != "" {
+			op.Description += "\n\n"
+		}
+		op.Description += requirement
+	}
+	switch route.Auth {
+	case AuthNone:
+	case AuthUser:
+		op.Security = []map[string][]string{{"cookieAuth": {}}}
+	case AuthService:
+		op.Security = []map[string][]string{{"serviceAuth": {}}}
+	default:
+		return nil, fmt.Errorf("unknown auth %q", route.Auth)
+	}
+
+	declared := make(map[string]Param, len(route.PathParams))
+	for _, p := range route.PathParams {
+		declared[p.Name] = p
+	}
+	for _, match := range pathParamPattern.FindAllStringSubmatch(route.Path, -1) {
+		p, ok := declared[match[1]]
+		if !ok {
+			p = Param{Name: match[1], Type: "integer", Format: "int64"}
+		}
+		delete(declared, match[1])
+		op.Parameters = append(op.Parameters, Parameter{
+			Name: p.Name, In: "path", Description: p.Description, Required: true, Schema: p.schema(),
+		})
+	}
+	for name := range declared {
+		return nil, fmt.Errorf("path parameter %q is not in the path", name)
+	}
+	for _, p := range route.Query {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name: p.Name, In: "query", Description: p.Description, Required: p.Required, Schema: p.schema(),
+		})
+	}
+
+	switch {
+	case route.Request != nil:
+		schema, err := g.schemaFor(route.Request, directionRequest)
+		if err != nil {
+			return nil, err
+		}
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{contentType(route.RequestContentType): {Schema: schema}},
+		}
+	case len(route.Form) > 0:
+		form := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		for _, p := range route.Form {
+			form.Properties[p.Name] = p.schema()
+			if p.Required {
+				form.Required = append(form.Required, p.Name)
+			}
+		}
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{contentType(route.RequestContentType): {Schema: form}},
+		}
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := Response{Description: http.StatusText(status)}
+	if route.Response != nil {
+		schema, err := g.schemaFor(route.Response, directionResponse)
+		if err != nil {
+			return nil, err
+		}
+		success.Content = map[string]MediaType{contentType(route.ResponseContentType): {Schema: schema}}
+	}
+	op.Responses[fmt.Sprint(status)] = success
+	op.Responses["default"] = Response{
+		Description: "Ошибка",
+		Content: map[string]MediaType{
+			"application/json": {Schema: &Schema{Ref: refPrefix + ErrorSchemaName}},
+		},
+	}
+	return op, nil
+}
+
+func (p Param) schema() *Schema {
+	s := &Schema{Type: p.Type, Format: p.Format, Enum: p.Enum, Default: p.Default}
+	switch s.Type {
+	case "":
+		s.Type = "integer"
+	case "file":
+		s.Type, s.Format = "string", "binary"
+	}
+	return s
+}
+
+func contentType(ct string) string {
+	if ct == "" {
+		return "application/json"
+	}
+	return ct
+}
+
+// operationID строит стабильный идентификатор из метода и пути:
+// GET /api/v1/tenders/:id/imports → getApiV1TendersIdImports.
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
@@ -0,0 +1,250 @@
+package openapi
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR OPENAPI GENERATION (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Contract drift — schemas must describe exactly what json.Marshal produces and what gin validates
+2. Broken clients — paths, parameters and operation ids must be valid OpenAPI
+3. Silent clashes — duplicate routes and schema names must fail the build
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Response schemas
+- GIVEN a struct with json tags, pointers, slices, maps, time, RawMessage and json:"-"
+  WHEN Build is called
+  THEN names follow tags, fields without omitempty are required, pointers are nullable
+- GIVEN a type with its own MarshalJSON → empty schema, unless it declares OpenAPIType
+- GIVEN nested named structs → each is a component referenced via $ref
+
+SCENARIO 2: Request schemas
+- GIVEN a request DTO with binding:"required" and binding:"oneof=..."
+  WHEN Build is called
+  THEN only binding-required fields are required, oneof becomes an enum
+- GIVEN a type used both in a request and a response → request rules win
+
+SCENARIO 3: Operations
+- GIVEN a gin path with :params
+  THEN it becomes {params}, undeclared params are int64, declared ones keep their type
+- GIVEN Auth and Permission → security requirement and a permission note
+- GIVEN a multipart form or a CSV response → content types are kept
+- GIVEN any operation → the default response is the shared Error schema {code, message, details}
+
+SCENARIO 4: Errors
+- GIVEN the same route twice, an unknown method or a declared param missing from the path
+  WHEN Build is called
+  THEN an error is returned
+*/
+
+type testMoney struct{}
+
+func (testMoney) MarshalJSON() ([]byte, error)          { return []byte(`"0.00"`), nil }
+func (testMoney) OpenAPIType() (string, string)         { return "string", "decimal" }
+func (testOpaque) MarshalJSON() ([]byte, error)         { return []byte(`{}`), nil }
+func (*testPointerOpaque) MarshalJSON() ([]byte, error) { return []byte(`{}`), nil }
+
+type testOpaque struct{ hidden int }
+type testPointerOpaque struct{ hidden int }
+
+type testBase struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type testCell struct {
+	Value *string `json:"value"`
+}
+
+type testResponse struct {
+	testBase
+	Title    string              `json:"title"`
+	Note     *string             `json:"note,omitempty"`
+	Cells    []testCell          `json:"cells"`
+	Best     *testCell           `json:"best"`
+	ByKey    map[string]testCell `json:"by_key"`
+	Raw      json.RawMessage     `json:"raw"`
+	Total    testMoney           `json:"total"`
+	Opaque   testOpaque          `json:"opaque"`
+	Pointer  testPointerOpaque   `json:"pointer"`
+	Count    int32               `json:"count"`
+	Ratio    float64             `json:"ratio"`
+	Payload  []byte              `json:"payload"`
+	Anything any                 `json:"anything"`
+	Inline   struct {
+		OK bool `json:"ok"`
+	} `json:"inline"`
+	NullName sql.NullString `json:"null_name"`
+	Internal string         `json:"-"`
+	hidden   string
+}
+
+type testRequest struct {
+	Title  string    `json:"title" binding:"required,max=255"`
+	Status string    `json:"status" binding:"omitempty,oneof=active archived"`
+	Note   *string   `json:"note"`
+	Cell   *testCell `json:"cell"`
+}
+
+func buildOne(t *testing.T, route Route) *Document {
+	t.Helper()
+	doc, err := Build(Spec{Info: Info{Title: "Test", Version: "dev"}, AccessCookie: "access_token", Routes: []Route{route}})
+	require.NoError(t, err)
+	return doc
+}
+
+func TestBuild_ResponseSchemas(t *testing.T) {
+	doc := buildOne(t, Route{Method: http.MethodGet, Path: "/items/:id", Response: testResponse{}})
+
+	op := doc.Paths["/items/{id}"].Get
+	require.NotNil(t, op)
+	assert.Equal(t, refPrefix+"TestResponse", op.Responses["200"].Content["application/json"].Schema.Ref)
+
+	s := doc.Components.Schemas["TestResponse"]
+	require.NotNil(t, s)
+	assert.Equal(t, []string{"id", "created_at", "title", "cells", "best", "by_key", "raw", "total",
+		"opaque", "pointer", "count", "ratio", "payload", "anything", "inline", "null_name"}, s.Required)
+	assert.NotContains(t, s.Properties, "Internal")
+	assert.NotContains(t, s.Properties, "hidden")
+
+	assert.Equal(t, &Schema{Type: "integer", Format: "int64"}, s.Properties["id"])
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, s.Properties["created_at"])
+	assert.Equal(t, &Schema{Type: "string", Nullable: true}, s.Properties["note"])
+	assert.Equal(t, &Schema{Type: "array", Items: &Schema{Ref: refPrefix + "TestCell"}}, s.Properties["cells"])
+	assert.Equal(t, &Schema{AllOf: []*Schema{{Ref: refPrefix + "TestCell"}}, Nullable: true}, s.Properties["best"])
+	assert.Equal(t, &Schema{Type: "object", AdditionalProperties: &Schema{Ref: refPrefix + "TestCell"}}, s.Properties["by_key"])
+	assert.Equal(t, &Schema{}, s.Properties["raw"])
+	assert.Equal(t, &Schema{Type: "string", Format: "decimal"}, s.Properties["total"])
+	assert.Equal(t, &Schema{}, s.Properties["opaque"])
+	assert.Equal(t, &Schema{}, s.Properties["pointer"])
+	assert.Equal(t, &Schema{Type: "integer", Format: "int32"}, s.Properties["count"])
+	assert.Equal(t, &Schema{Type: "number"}, s.Properties["ratio"])
+	assert.Equal(t, &Schema{Type: "string", Format: "byte"}, s.Properties["payload"])
+	assert.Equal(t, &Schema{}, s.Properties["anything"])
+	assert.Equal(t, &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{"ok": {Type: "boolean"}},
+		Required:   []string{"ok"},
+	}, s.Properties["inline"])
+	assert.Equal(t, refPrefix+"NullString", s.Properties["null_name"].Ref)
+
+	assert.Equal(t, []string{"value"}, doc.Components.Schemas["TestCell"].Required)
+	assert.Contains(t, doc.Components.Schemas["NullString"].Properties, "Valid")
+}
+
+func TestBuild_RequestSchemas(t *testing.T) {
+	doc, err := Build(Spec{Routes: []Route{
+		{Method: http.MethodPost, Path: "/items", Request: testRequest{}, Status: http.StatusCreated, Response: testCell{}},
+	}})
+	require.NoError(t, err)
+
+	op := doc.Paths["/items"].Post
+	require.NotNil(t, op.RequestBody)
+	assert.True(t, op.RequestBody.Required)
+	assert.Contains(t, op.Responses, "201")
+
+	req := doc.Components.Schemas["TestRequest"]
+	assert.Equal(t, []string{"title"}, req.Required)
+	assert.Equal(t, []string{"active", "archived"}, req.Properties["status"].Enum)
+
+	// testCell встречается и в запросе, и в ответе — обязательность по binding
+	assert.Empty(t, doc.Components.Schemas["TestCell"].Required)
+}
+
+func TestBuild_Operations(t *testing.T) {
+	doc, err := Build(Spec{AccessCookie: "session", Routes: []Route{
+		{
+			Method: http.MethodGet, Path: "/api/v1/settings/:key", Tag: "admin", Summary: "Настройка",
+			Auth: AuthUser, Permission: "system:manage",
+			PathParams: []Param{{Name: "key", Type: "string"}},
+			Query:      []Param{{Name: "include", Type: "boolean", Default: false}},
+		},
+		{
+			Method: http.MethodDelete, Path: "/api/v1/units/:id/aliases/:aliasId", Tag: "admin",
+			Auth: AuthUser, Status: http.StatusNoContent,
+		},
+		{
+			Method: http.MethodPost, Path: "/internal/upload", Tag: "worker", Auth: AuthService, Permission: "import",
+			RequestContentType: "multipart/form-data",
+			Form:               []Param{{Name: "file", Type: "file", Required: true}, {Name: "enable_ai", Type: "boolean"}},
+			Response:           "", ResponseContentType: "text/csv",
+		},
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []Tag{{Name: "admin"}, {Name: "worker"}}, doc.Tags)
+	assert.Equal(t, "session", doc.Components.SecuritySchemes["cookieAuth"].Name)
+
+	get := doc.Paths["/api/v1/settings/{key}"].Get
+	assert.Equal(t, "getApiV1SettingsKey", get.OperationID)
+	assert.Equal(t, "Требуемое право: system:manage", get.Description)
+	assert.Equal(t, []map[string][]string{{"cookieAuth": {}}}, get.Security)
+	require.Len(t, get.Parameters, 2)
+	assert.Equal(t, Parameter{Name: "key", In: "path", Required: true, Schema: &Schema{Type: "string"}}, get.Parameters[0])
+	assert.Equal(t, Parameter{Name: "include", In: "query", Schema: &Schema{Type: "boolean", Default: false}}, get.Parameters[1])
+	assert.Equal(t, refPrefix+ErrorSchemaName, get.Responses["default"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, []string{"code", "message", "error"}, doc.Components.Schemas[ErrorSchemaName].Required)
+
+	del := doc.Paths["/api/v1/units/{id}/aliases/{aliasId}"].Delete
+	require.Len(t, del.Parameters, 2)
+	assert.Equal(t, &Schema{Type: "integer", Format: "int64"}, del.Parameters[1].Schema)
+	assert.Nil(t, del.Responses["204"].Content)
+
+	upload := doc.Paths["/internal/upload"].Post
+	assert.Equal(t, []map[string][]string{{"serviceAuth": {}}}, upload.Security)
+	assert.Equal(t, "Требуемый scope ключа: import", upload.Description)
+	form := upload.RequestBody.Content["multipart/form-data"].Schema
+	assert.Equal(t, &Schema{Type: "string", Format: "binary"}, form.Properties["file"])
+	assert.Equal(t, []string{"file"}, form.Required)
+	assert.Equal(t, &Schema{Type: "string"}, upload.Responses["200"].Content["text/csv"].Schema)
+
+	// Документ сериализуется в JSON без ошибок
+	_, err = json.Marshal(doc)
+	require.NoError(t, err)
+}
+
+// NullString совпадает по имени с sql.NullString: второй тип получает префикс пакета.
+type NullString struct {
+	Value string `json:"value"`
+}
+
+func TestBuild_SchemaNameClash_PrefixesPackage(t *testing.T) {
+	doc, err := Build(Spec{Routes: []Route{
+		{Method: http.MethodGet, Path: "/a", Response: NullString{}},
+		{Method: http.MethodGet, Path: "/b", Response: sql.NullString{}},
+	}})
+	require.NoError(t, err)
+
+	assert.Contains(t, doc.Components.Schemas, "NullString")
+	assert.Contains(t, doc.Components.Schemas, "SqlNullString")
+}
+
+func TestBuild_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes []Route
+	}{
+		{"duplicate route", []Route{{Method: http.MethodGet, Path: "/a"}, {Method: http.MethodGet, Path: "/a"}}},
+		{"unknown method", []Route{{Method: "TRACE", Path: "/a"}}},
+		{"param not in path", []Route{{Method: http.MethodGet, Path: "/a", PathParams: []Param{{Name: "id"}}}}},
+		{"unknown auth", []Route{{Method: http.MethodGet, Path: "/a", Auth: "basic"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Build(Spec{Routes: tt.routes})
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const refPrefix = "#/components/schemas/"
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	rawJSONType   = reflect.TypeOf(json.RawMessage{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	typerType     = reflect.TypeOf((*Typer)(nil)).Elem()
+)
+
+// Typer реализуют типы со своим MarshalJSON, чтобы задать тип и формат схемы
+// явно (например, денежная сумма, которая кодируется строкой). Остальные типы
+// с MarshalJSON описываются пустой схемой — их формат генератору неизвестен.
+type Typer interface {
+	OpenAPIType() (typ, format string)
+}
+
+type direction int
+
+const (
+	directionResponse direction = iota
+	directionRequest
+)
+
+type visit struct {
+	t   reflect.Type
+	dir direction
+}
+
+// generator хранит схемы одного прогона Build.
+type generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+	taken   map[string]reflect.Type
+	fields  map[reflect.Type][]field
+	visited map[visit]bool
+	// requestTypes — структуры, встреченные в телах запросов
+	requestTypes map[reflect.Type]bool
+	// order — порядок обнаружения структур, чтобы Required заполнялся детерминированно
+	order []reflect.Type
+}
+
+func newGenerator() *generator {
+	return &generator{
+		schemas:      make(map[string]*Schema),
+		names:        make(map[reflect.Type]string),
+		taken:        map[string]reflect.Type{ErrorSchemaName: nil},
+		fields:       make(map[reflect.Type][]field),
+		visited:      make(map[visit]bool),
+		requestTypes: make(map[reflect.Type]bool),
+	}
+}
+
+func (g *generator) schemaFor(value any, dir direction) (*Schema, error) {
+	return g.schema(reflect.TypeOf(value), dir)
+}
+
+// schema возвращает схему Go-типа так, как его кодирует encoding/json.
+func (g *generator) schema(t reflect.Type, dir direction) (*Schema, error) {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}, nil
+	case t == rawJSONType:
+		return &Schema{}, nil
+	case t.Kind() == reflect.Ptr:
+		elem, err := g.schema(t.Elem(), dir)
+		if err != nil {
+			return nil, err
+		}
+		return nullable(elem), nil
+	case t.Implements(typerType):
+		typ, format := reflect.Zero(t).Interface().(Typer).OpenAPIType()
+		return &Schema{Type: typ, Format: format}, nil
+	case t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType):
+		return &Schema{}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: "int32"}, nil
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}, nil // []byte кодируется в base64
+		}
+		items, err := g.schema(t.Elem(), dir)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		values, err := g.schema(t.Elem(), dir)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		if t.Name() == "" {
+			object, fields, err := g.object(t, dir)
+			if err != nil {
+				return nil, err
+			}
+			object.Required = requiredFields(fields, dir == directionRequest)
+			return object, nil
+		}
+		return g.ref(t, dir)
+	default:
+		return &Schema{}, nil // interface{} и прочее — любое значение
+	}
+}
+
+// ref выносит именованную структуру в components/schemas. Структура, впервые
+// встреченная в новом направлении, обходится повторно, чтобы вложенные типы
+// тоже узнали, что используются в запросе.
+func (g *generator) ref(t reflect.Type, dir direction) (*Schema, error) {
+	key := visit{t: t, dir: dir}
+	if !g.visited[key] {
+		g.visited[key] = true
+		if dir == directionRequest {
+			g.requestTypes[t] = true
+		}
+		if _, ok := g.names[t]; !ok {
+			if err := g.name(t); err != nil {
+				return nil, err
+			}
+			// Заглушка до обхода полей — для рекурсивных типов
+			g.schemas[g.names[t]] = &Schema{}
+			g.order = append(g.order, t)
+		}
+		object, fields, err := g.object(t, dir)
+		if err != nil {
+			return nil, err
+		}
+		*g.schemas[g.names[t]] = *object
+		g.fields[t] = fields
+	}
+	return &Schema{Ref: refPrefix + g.names[t]}, nil
+}
+
+// name закрепляет имя схемы за типом. При совпадении имён типов из разных
+// пакетов второй получает префикс пакета: db.Tender → DbTender.
+func (g *generator) name(t reflect.Type) error {
+	name := schemaName(t.Name())
+	if other, ok := g.taken[name]; ok && other != t {
+		name = schemaName(path.Base(t.PkgPath())) + name
+		if other, ok := g.taken[name]; ok && other != t {
+			return fmt.Errorf("schema name %q is used by both %v and %s", name, other, t)
+		}
+	}
+	g.names[t] = name
+	g.taken[name] = t
+	return nil
+}
+
+func (g *generator) object(t reflect.Type, dir direction) (*Schema, []field, error) {
+	object := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	fields := jsonFields(t)
+	for _, f := range fields {
+		schema, err := g.schema(f.Type, dir)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(f.oneOf) > 0 && schema.Type == "string" {
+			schema.Enum = f.oneOf
+		}
+		object.Properties[f.jsonName] = schema
+	}
+	return object, fields, nil
+}
+
+// finalizeRequired заполняет Required именованных схем, когда известно, где они используются.
+func (g *generator) finalizeRequired() {
+	for _, t := range g.order {
+		g.schemas[g.names[t]].Required = requiredFields(g.fields[t], g.requestTypes[t])
+	}
+}
+
+func requiredFields(fields []field, request bool) []string {
+	var required []string
+	for _, f := range fields {
+		if request && f.bindingRequired || !request && !f.omitempty {
+			required = append(required, f.jsonName)
+		}
+	}
+	return required
+}
+
+func nullable(s *Schema) *Schema {
+	if s.Ref != "" {
+		return &Schema{AllOf: []*Schema{s}, Nullable: true}
+	}
+	copied := *s
+	copied.Nullable = true
+	return &copied
+}
+
+// field — поле структуры в том виде, в котором оно попадает в JSON.
+type field struct {
+	reflect.StructField
+	jsonName        string
+	omitempty       bool
+	bindingRequired bool
+	oneOf           []string
+}
+
+// jsonFields возвращает JSON-поля структуры с учётом тегов и встроенных структур.
+func jsonFields(t reflect.Type) []field {
+	var result []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		// Встроенная структура без имени в теге разворачивается, как в encoding/json
+		if f.Anonymous && name == "" && indirect(f.Type).Kind() == reflect.Struct {
+			result = append(result, jsonFields(indirect(f.Type))...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fld := field{
+			StructField: f,
+			jsonName:    name,
+			omitempty:   strings.Contains(","+opts+",", ",omitempty,"),
+		}
+		for _, rule := range strings.Split(f.Tag.Get("binding"), ",") {
+			switch {
+			case rule == "required":
+				fld.bindingRequired = true
+			case strings.HasPrefix(rule, "oneof="):
+				fld.oneOf = strings.Fields(strings.TrimPrefix(rule, "oneof="))
+			}
+		}
+		result = append(result, fld)
+	}
+	return result
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// schemaName делает первую букву заглавной и убирает символы, недопустимые
+// в имени схемы (например, у параметризованных типов).
+func schemaName(name string) string {
+	r := []rune(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return -1
+	}, name))
+	if len(r) == 0 {
+		return ""
+	}
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
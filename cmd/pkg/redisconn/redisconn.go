@@ -0,0 +1,27 @@
+// Package redisconn создаёт клиент Redis (go-redis) для кэша справочников и
+// лимитов запросов по адресу из конфигурации.
+//
+// Клиент держит пул соединений, сам переподключается после сетевых ошибок и
+// поддерживает TLS (схема rediss://).
+package redisconn
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// New разбирает адрес redis[s]://[[user]:password@]host[:6379][/db].
+// Соединения открываются при первой команде: недоступный Redis проявится
+// ошибкой первой команды. timeout ограничивает подключение, чтение и запись.
+func New(rawURL string, timeout time.Duration) (*redis.Client, error) {
+	opts, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("некорректный адрес Redis: %w", err)
+	}
+	opts.DialTimeout = timeout
+	opts.ReadTimeout = timeout
+	opts.WriteTimeout = timeout
+	return redis.NewClient(opts), nil
+}
@@ -0,0 +1,33 @@
+// Package retry содержит общие помощники повторных попыток: экспоненциальную
+// задержку между попытками и ожидание с учётом отмены контекста.
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// Backoff возвращает задержку перед повтором после attempt-й неуспешной попытки:
+// base, затем вдвое больше каждый раз, но не более maxDelay.
+func Backoff(attempt int, base, maxDelay time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= maxDelay {
+			return maxDelay
+		}
+	}
+	return min(delay, maxDelay)
+}
+
+// Sleep ждёт d или отмены ctx.
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,50 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR RETRY HELPERS (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Retry storms — delays must grow between attempts and stay capped
+2. Hung shutdown — waiting between attempts must stop when the context is cancelled
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Backoff
+- GIVEN base 1s and max 5s
+  WHEN attempts 1..4 fail
+  THEN delays are 1s, 2s, 4s, 5s
+- GIVEN base above max
+  THEN max is returned
+
+SCENARIO 2: Sleep
+- GIVEN a cancelled context
+  WHEN Sleep is called with a long delay
+  THEN it returns ctx.Err() immediately
+*/
+
+func TestBackoff(t *testing.T) {
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 50: 5 * time.Second} {
+		assert.Equal(t, want, Backoff(attempt, time.Second, 5*time.Second), "attempt %d", attempt)
+	}
+	assert.Equal(t, time.Second, Backoff(1, 2*time.Second, time.Second))
+}
+
+func TestSleep(t *testing.T) {
+	assert.NoError(t, Sleep(context.Background(), time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	assert.ErrorIs(t, Sleep(ctx, time.Hour), context.Canceled)
+	assert.Less(t, time.Since(start), time.Second)
+}
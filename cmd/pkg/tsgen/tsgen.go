@@ -0,0 +1,269 @@
+// Package tsgen генерирует TypeScript-интерфейсы из Go-структур по правилам encoding/json.
+//
+// Генератор смотрит на типы так же, как их видит фронтенд после json.Marshal:
+// учитываются теги json (имя, "-", omitempty), встроенные структуры без тега
+// разворачиваются в родителя, указатели становятся "T | null". Типы со своим
+// MarshalJSON (кроме time.Time) описываются как unknown — их формат генератору неизвестен,
+// если тип не сообщает его сам через TypeScriptType (см. Typer).
+//
+// Вложенные структуры обходятся рекурсивно и выводятся отдельными интерфейсами
+// в порядке обнаружения, поэтому вывод детерминирован и удобен для diff в PR.
+package tsgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Root — тип верхнего уровня для генерации.
+type Root struct {
+	// Name — имя интерфейса в TypeScript. Пустое — имя Go-типа с заглавной буквы.
+	Name string
+	// Value — значение типа (обычно нулевое: SomeResponse{}).
+	Value any
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	rawJSONType   = reflect.TypeOf(json.RawMessage{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	typerType     = reflect.TypeOf((*Typer)(nil)).Elem()
+)
+
+// Typer реализуют типы со своим MarshalJSON, чтобы задать TypeScript-тип явно
+// (например, денежная сумма, которая кодируется строкой).
+type Typer interface {
+	TypeScriptType() string
+}
+
+// generator хранит состояние одного прогона.
+type generator struct {
+	names map[reflect.Type]string
+	taken map[string]reflect.Type
+	order []reflect.Type
+}
+
+// Generate пишет в w TypeScript-интерфейсы для roots и всех вложенных структур.
+// Возвращает ошибку, если два разных Go-типа получают одинаковое имя в TypeScript.
+func Generate(w io.Writer, roots []Root) error {
+	g := &generator{
+		names: make(map[reflect.Type]string),
+		taken: make(map[string]reflect.Type),
+	}
+
+	// Сначала резервируем явные имена, чтобы вложенные типы не заняли их раньше
+	for _, root := range roots {
+		t := indirect(reflect.TypeOf(root.Value))
+		if t.Kind() != reflect.Struct {
+			return fmt.Errorf("tsgen: root %s is not a struct", t)
+		}
+		if err := g.name(t, root.Name); err != nil {
+			return err
+		}
+	}
+	for _, root := range roots {
+		if err := g.visit(indirect(reflect.TypeOf(root.Value))); err != nil {
+			return err
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("// Code generated by tsgen. DO NOT EDIT.\n")
+	for _, t := range g.order {
+		b.WriteString("\n")
+		g.writeInterface(&b, t)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// name закрепляет TS-имя за типом. Повторный вызов для того же типа ничего не меняет.
+func (g *generator) name(t reflect.Type, name string) error {
+	if _, ok := g.names[t]; ok {
+		return nil
+	}
+	if name == "" {
+		name = exportedName(t.Name())
+	}
+	if other, ok := g.taken[name]; ok && other != t {
+		return fmt.Errorf("tsgen: name %q is used by both %s and %s, set Root.Name explicitly", name, other, t)
+	}
+	g.names[t] = name
+	g.taken[name] = t
+	return nil
+}
+
+// visit добавляет структуру и все вложенные в неё структуры в порядок вывода.
+func (g *generator) visit(t reflect.Type) error {
+	for _, seen := range g.order {
+		if seen == t {
+			return nil
+		}
+	}
+	if err := g.name(t, ""); err != nil {
+		return err
+	}
+	g.order = append(g.order, t)
+
+	for _, f := range fields(t) {
+		for _, nested := range nestedStructs(f.Type) {
+			if err := g.visit(nested); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (g *generator) writeInterface(b *strings.Builder, t reflect.Type) {
+	fmt.Fprintf(b, "export interface %s {\n", g.names[t])
+	for _, f := range fields(t) {
+		optional := ""
+		if f.omitempty {
+			optional = "?"
+		}
+		fmt.Fprintf(b, "  %s%s: %s;\n", f.jsonName, optional, g.tsType(f.Type))
+	}
+	b.WriteString("}\n")
+}
+
+// tsType возвращает TypeScript-тип для Go-типа поля.
+func (g *generator) tsType(t reflect.Type) string {
+	switch {
+	case t == timeType:
+		return "string"
+	case t == rawJSONType:
+		return "unknown"
+	case t.Kind() == reflect.Ptr:
+		return g.tsType(t.Elem()) + " | null"
+	case t.Implements(typerType):
+		return reflect.Zero(t).Interface().(Typer).TypeScriptType()
+	case t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType):
+		return "unknown"
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string" // []byte кодируется в base64
+		}
+		elem := g.tsType(t.Elem())
+		if strings.Contains(elem, " ") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case reflect.Map:
+		return "Record<string, " + g.tsType(t.Elem()) + ">"
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.inlineStruct(t)
+		}
+		return g.names[t]
+	default:
+		return "unknown"
+	}
+}
+
+// inlineStruct описывает анонимную структуру литералом объекта.
+func (g *generator) inlineStruct(t reflect.Type) string {
+	parts := make([]string, 0, t.NumField())
+	for _, f := range fields(t) {
+		optional := ""
+		if f.omitempty {
+			optional = "?"
+		}
+		parts = append(parts, fmt.Sprintf("%s%s: %s", f.jsonName, optional, g.tsType(f.Type)))
+	}
+	return "{ " + strings.Join(parts, "; ") + " }"
+}
+
+// field — поле структуры в том виде, в котором оно попадает в JSON.
+type field struct {
+	reflect.StructField
+	jsonName  string
+	omitempty bool
+}
+
+// fields возвращает JSON-поля структуры с учётом тегов и встроенных структур.
+func fields(t reflect.Type) []field {
+	var result []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		// Встроенная структура без имени в теге разворачивается, как в encoding/json
+		if f.Anonymous && name == "" && indirect(f.Type).Kind() == reflect.Struct {
+			result = append(result, fields(indirect(f.Type))...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		result = append(result, field{
+			StructField: f,
+			jsonName:    name,
+			omitempty:   strings.Contains(","+opts+",", ",omitempty,"),
+		})
+	}
+	return result
+}
+
+// nestedStructs возвращает структуры, которые нужно описать отдельными интерфейсами.
+func nestedStructs(t reflect.Type) []reflect.Type {
+	switch {
+	case t == timeType || t == rawJSONType:
+		return nil
+	case t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map:
+		return nestedStructs(t.Elem())
+	case t.Implements(typerType):
+		return nil
+	case t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType):
+		return nil
+	case t.Kind() == reflect.Struct && t.Name() == "":
+		var nested []reflect.Type
+		for _, f := range fields(t) {
+			nested = append(nested, nestedStructs(f.Type)...)
+		}
+		return nested
+	case t.Kind() == reflect.Struct:
+		return []reflect.Type{t}
+	}
+	return nil
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// exportedName делает первую букву заглавной: listTendersResponse → ListTendersResponse.
+func exportedName(name string) string {
+	if name == "" {
+		return name
+	}
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
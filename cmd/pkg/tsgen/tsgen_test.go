@@ -0,0 +1,154 @@
+package tsgen
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR TYPESCRIPT GENERATION (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Frontend drift — TS types must describe exactly what json.Marshal produces
+2. Noisy diffs — output order must be stable between runs
+3. Silent clashes — two Go types with the same TS name must fail generation
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Field mapping
+- GIVEN a struct with json tags, pointers, slices, maps, time, RawMessage and json:"-"
+  WHEN Generate is called
+  THEN names follow tags, omitempty → optional, pointer → "| null", "-" is skipped
+
+- GIVEN a type with its own MarshalJSON
+  WHEN Generate is called
+  THEN it is "unknown", unless it declares its TS type via TypeScriptType
+
+- GIVEN an embedded struct without json tag
+  WHEN Generate is called
+  THEN its fields are flattened into the parent
+
+SCENARIO 2: Nested types
+- GIVEN nested structs (including unexported and sql.Null*)
+  WHEN Generate is called
+  THEN each is emitted once as its own interface, in discovery order
+
+SCENARIO 3: Name clash
+- GIVEN two different types with the same TS name
+  WHEN Generate is called
+  THEN an error is returned
+*/
+
+type testCell struct {
+	Value *string `json:"value"`
+}
+
+// testMoney кодируется строкой и сообщает об этом генератору.
+type testMoney struct{}
+
+func (testMoney) MarshalJSON() ([]byte, error) { return []byte(`"0.00"`), nil }
+func (testMoney) TypeScriptType() string       { return "string" }
+
+// testOpaque кодируется сам, но тип не сообщает.
+type testOpaque struct{}
+
+func (testOpaque) MarshalJSON() ([]byte, error) { return []byte(`null`), nil }
+
+type testBase struct {
+	ID int64 `json:"id"`
+}
+
+type testResponse struct {
+	testBase
+	Title     string            `json:"title"`
+	Note      *string           `json:"note,omitempty"`
+	Count     int32             `json:"count"`
+	Ratio     float64           `json:"ratio"`
+	Active    bool              `json:"active"`
+	Cells     []testCell        `json:"cells"`
+	Optional  []*testCell       `json:"optional_cells"`
+	Info      map[string]string `json:"info"`
+	CreatedAt time.Time         `json:"created_at"`
+	Params    json.RawMessage   `json:"params"`
+	Category  sql.NullInt64     `json:"category_id"`
+	Total     *testMoney        `json:"total"`
+	Opaque    testOpaque        `json:"opaque"`
+	Secret    string            `json:"-"`
+	NoTag     string
+	internal  string //nolint:unused // неэкспортируемое поле не попадает в JSON
+}
+
+func TestGenerate_FieldMapping(t *testing.T) {
+	var out strings.Builder
+
+	err := Generate(&out, []Root{{Value: testResponse{}}})
+
+	require.NoError(t, err)
+	expected := `// Code generated by tsgen. DO NOT EDIT.
+
+export interface TestResponse {
+  id: number;
+  title: string;
+  note?: string | null;
+  count: number;
+  ratio: number;
+  active: boolean;
+  cells: TestCell[];
+  optional_cells: (TestCell | null)[];
+  info: Record<string, string>;
+  created_at: string;
+  params: unknown;
+  category_id: NullInt64;
+  total: string | null;
+  opaque: unknown;
+  NoTag: string;
+}
+
+export interface TestCell {
+  value: string | null;
+}
+
+export interface NullInt64 {
+  Int64: number;
+  Valid: boolean;
+}
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestGenerate_RootNameAndDeterministicOrder(t *testing.T) {
+	roots := []Root{{Name: "Page", Value: &testResponse{}}, {Value: testCell{}}}
+
+	var first, second strings.Builder
+	require.NoError(t, Generate(&first, roots))
+	require.NoError(t, Generate(&second, roots))
+
+	assert.Equal(t, first.String(), second.String())
+	assert.Contains(t, first.String(), "export interface Page {")
+	assert.Equal(t, 1, strings.Count(first.String(), "export interface TestCell {"))
+}
+
+type otherTestCell struct{}
+
+func TestGenerate_NameClash_ReturnsError(t *testing.T) {
+	err := Generate(&strings.Builder{}, []Root{
+		{Value: testResponse{}},
+		{Name: "TestCell", Value: otherTestCell{}},
+	})
+
+	assert.Error(t, err)
+}
+
+func TestGenerate_RootMustBeStruct(t *testing.T) {
+	err := Generate(&strings.Builder{}, []Root{{Value: "not a struct"}})
+
+	assert.Error(t, err)
+}
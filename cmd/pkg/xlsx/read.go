@@ -0,0 +1,281 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Ограничения Excel на размер листа: ссылка за их пределами — повреждённый файл.
+const (
+	maxRows    = 1 << 20
+	maxColumns = 1 << 14
+)
+
+// maxPartSize — предел распакованного размера одной части книги: небольшой
+// файл не должен разворачиваться в гигабайты XML (zip-бомба).
+const maxPartSize = 64 << 20
+
+// ErrPartTooLarge — часть книги больше maxPartSize после распаковки.
+var ErrPartTooLarge = errors.New("xlsx: part exceeds size limit")
+
+// ErrTooManyRows — в книге больше строк, чем разрешает Limits.MaxRows.
+var ErrTooManyRows = errors.New("xlsx: too many rows")
+
+// Limits ограничивает то, что ReadSheets разворачивает в память. Пропущенные
+// строки и ячейки заполняются пустыми, поэтому крошечный файл со ссылкой на
+// XFD1048576 без ограничений занял бы гигабайты; файлы от пользователей
+// читаются только с обоими пределами. Ноль — без ограничения.
+type Limits struct {
+	// MaxRows — сколько строк всех листов вместе, включая пропущенные, можно
+	// прочитать; при превышении ReadSheets возвращает ErrTooManyRows.
+	MaxRows int
+	// MaxColumns — сколько первых столбцов читается; ячейки правее
+	// отбрасываются.
+	MaxColumns int
+}
+
+// SheetData — значения ячеек листа, прочитанного ReadSheets.
+type SheetData struct {
+	Name string
+	// Rows[i] — строка i+1 листа; пропущенные строки и ячейки пустые, так что
+	// номер строки в сообщениях совпадает с номером в Excel.
+	Rows [][]string
+}
+
+// ReadSheets читает значения ячеек всех листов книги в порядке вкладок.
+// Значения возвращаются текстом: числа — как записаны в файле, логические —
+// TRUE/FALSE, у формул — последнее вычисленное значение. Стили и даты не
+// интерпретируются.
+func ReadSheets(r io.ReaderAt, size int64, limits Limits) ([]SheetData, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("xlsx: %w", err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[strings.TrimPrefix(f.Name, "/")] = f
+	}
+
+	var book struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodePart(files, "xl/workbook.xml", &book); err != nil {
+		return nil, err
+	}
+
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodePart(files, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string, len(rels.Relationships))
+	for _, rel := range rels.Relationships {
+		targets[rel.ID] = partPath(rel.Target)
+	}
+
+	// sharedStrings отсутствует, если все строки записаны inline (как у Write)
+	var shared []string
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		var sst struct {
+			Items []richText `xml:"si"`
+		}
+		if err := decodePart(files, "xl/sharedStrings.xml", &sst); err != nil {
+			return nil, err
+		}
+		shared = make([]string, len(sst.Items))
+		for i, item := range sst.Items {
+			shared[i] = item.text()
+		}
+	}
+
+	sheets := make([]SheetData, 0, len(book.Sheets))
+	rowsLeft := -1 // Без предела
+	if limits.MaxRows > 0 {
+		rowsLeft = limits.MaxRows
+	}
+	for _, s := range book.Sheets {
+		target, ok := targets[s.RID]
+		if !ok {
+			return nil, fmt.Errorf("xlsx: sheet %q: relationship %q not found", s.Name, s.RID)
+		}
+		rows, err := readSheet(files, target, shared, rowsLeft, limits.MaxColumns)
+		if err != nil {
+			return nil, fmt.Errorf("xlsx: sheet %q: %w", s.Name, err)
+		}
+		if rowsLeft >= 0 {
+			rowsLeft -= len(rows)
+		}
+		sheets = append(sheets, SheetData{Name: s.Name, Rows: rows})
+	}
+	return sheets, nil
+}
+
+// richText — строка sharedStrings или inline-ячейки: простой текст <t> или
+// форматированные фрагменты <r><t>. Фонетические подсказки <rPh> пропускаются.
+type richText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (rt richText) text() string {
+	if len(rt.Runs) == 0 {
+		return rt.T
+	}
+	var b strings.Builder
+	b.WriteString(rt.T)
+	for _, run := range rt.Runs {
+		b.WriteString(run.T)
+	}
+	return b.String()
+}
+
+// readSheet читает лист, заполняя пропуски. Пределы проверяются до заполнения:
+// не больше maxRowCount строк (отрицательное — без предела) и maxColumnCount
+// столбцов (ноль — без предела).
+func readSheet(files map[string]*zip.File, name string, shared []string, maxRowCount, maxColumnCount int) ([][]string, error) {
+	var sheet struct {
+		Rows []struct {
+			R     int `xml:"r,attr"`
+			Cells []struct {
+				R  string   `xml:"r,attr"`
+				T  string   `xml:"t,attr"`
+				V  string   `xml:"v"`
+				Is richText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decodePart(files, name, &sheet); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for _, row := range sheet.Rows {
+		// Без атрибута r строка следует за предыдущей
+		index := len(rows)
+		if row.R > 0 {
+			index = row.R - 1
+		}
+		if index < len(rows) || index >= maxRows {
+			return nil, fmt.Errorf("row %d is out of order or out of range", index+1)
+		}
+		if maxRowCount >= 0 && index >= maxRowCount {
+			return nil, ErrTooManyRows
+		}
+		for len(rows) < index {
+			rows = append(rows, nil)
+		}
+
+		var values []string
+		for _, cell := range row.Cells {
+			column := len(values)
+			if cell.R != "" {
+				parsed, err := columnIndex(cell.R)
+				if err != nil {
+					return nil, err
+				}
+				column = parsed
+			}
+			if column < len(values) {
+				return nil, fmt.Errorf("cell %s is out of order", cell.R)
+			}
+			if maxColumnCount > 0 && column >= maxColumnCount {
+				break // Ячейки упорядочены: остальные тоже правее предела
+			}
+
+			var value string
+			switch cell.T {
+			case "s":
+				i, err := strconv.Atoi(strings.TrimSpace(cell.V))
+				if err != nil || i < 0 || i >= len(shared) {
+					return nil, fmt.Errorf("cell %s: invalid shared string index %q", cell.R, cell.V)
+				}
+				value = shared[i]
+			case "inlineStr":
+				value = cell.Is.text()
+			case "b":
+				value = "FALSE"
+				if strings.TrimSpace(cell.V) == "1" {
+					value = "TRUE"
+				}
+			default: // n, str (формула), e (ошибка), d (дата ISO 8601)
+				value = cell.V
+			}
+
+			for len(values) < column {
+				values = append(values, "")
+			}
+			values = append(values, value)
+		}
+		rows = append(rows, values)
+	}
+	return rows, nil
+}
+
+// columnIndex возвращает номер столбца ссылки на ячейку: A1 → 0, AA7 → 26.
+func columnIndex(ref string) (int, error) {
+	index := 0
+	letters := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A') + 1
+		letters++
+		if index > maxColumns {
+			return 0, fmt.Errorf("cell %s: column out of range", ref)
+		}
+	}
+	if letters == 0 {
+		return 0, fmt.Errorf("invalid cell reference %q", ref)
+	}
+	return index - 1, nil
+}
+
+// partPath переводит Target связи книги в имя части архива: пути
+// указываются относительно xl/ или от корня пакета.
+func partPath(target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join("xl", target)
+}
+
+func decodePart(files map[string]*zip.File, name string, v any) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("xlsx: part %s not found", name)
+	}
+	if f.UncompressedSize64 > maxPartSize {
+		return fmt.Errorf("%w: %s", ErrPartTooLarge, name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("xlsx: %s: %w", name, err)
+	}
+	defer rc.Close()
+
+	// Заявленный в архиве размер может быть подделан: читаем не больше лимита
+	limited := &io.LimitedReader{R: rc, N: maxPartSize + 1}
+	if err := xml.NewDecoder(limited).Decode(v); err != nil {
+		if limited.N <= 0 {
+			return fmt.Errorf("%w: %s", ErrPartTooLarge, name)
+		}
+		return fmt.Errorf("xlsx: %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR XLSX READER (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Lost values — Excel stores text in sharedStrings, sometimes as formatted runs;
+   a reader that only knows inline strings sees empty cells
+2. Wrong row numbers — Excel skips empty rows and cells, so errors must still
+   point at the row the user sees in Excel
+3. Zip bombs — a small upload must not expand into gigabytes of XML
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Round trip
+- GIVEN a workbook produced by Write
+  WHEN ReadSheets is called
+  THEN sheet names and cell values come back in order
+
+SCENARIO 2: Excel-produced workbook
+- GIVEN shared strings (plain and rich), booleans, formulas, skipped rows and
+  cells, an absolute relationship target
+  THEN values are resolved and gaps are filled so Rows[i] is Excel row i+1
+
+SCENARIO 3: Broken files
+- GIVEN not a zip, a missing workbook part or a bad shared string index → error
+- GIVEN a part larger than the limit → ErrPartTooLarge
+
+SCENARIO 4: Sparse workbook
+- GIVEN a tiny file with a cell at XFD1 and a row at 1048576
+  WHEN ReadSheets is called with limits
+  THEN cells beyond MaxColumns are dropped and a row beyond MaxRows (counted
+  across all sheets) → ErrTooManyRows, without padding up to the reference
+*/
+
+// buildPackage собирает zip из частей name → содержимое.
+func buildPackage(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range parts {
+		require.NoError(t, writeFile(zw, name, content))
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func readBytes(data []byte) ([]SheetData, error) {
+	return ReadSheets(bytes.NewReader(data), int64(len(data)), Limits{})
+}
+
+func TestReadSheets_RoundTrip(t *testing.T) {
+	book := New()
+	sheet := book.AddSheet("Справочник")
+	sheet.AddHeader("kind", "title", "parent")
+	sheet.AddRow(Text("type"), Text(`Ремонт & "отделка"`))
+	sheet.AddRow(Text("chapter"), Text("Кровля"), Text(`Ремонт & "отделка"`))
+	book.AddSheet("Числа").AddRow(Empty(), Number("-12.50"))
+
+	var buf bytes.Buffer
+	require.NoError(t, book.Write(&buf))
+
+	sheets, err := readBytes(buf.Bytes())
+
+	require.NoError(t, err)
+	require.Len(t, sheets, 2)
+	assert.Equal(t, "Справочник", sheets[0].Name)
+	assert.Equal(t, [][]string{
+		{"kind", "title", "parent"},
+		{"type", `Ремонт & "отделка"`},
+		{"chapter", "Кровля", `Ремонт & "отделка"`},
+	}, sheets[0].Rows)
+	assert.Equal(t, [][]string{{"", "-12.50"}}, sheets[1].Rows)
+}
+
+func TestReadSheets_ExcelFeatures(t *testing.T) {
+	data := buildPackage(t, map[string]string{
+		"xl/workbook.xml": xmlHeader +
+			`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Лист1" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": xmlHeader +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId3" Type="worksheet" Target="/xl/worksheets/data.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": xmlHeader +
+			`<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" count="3" uniqueCount="3">` +
+			`<si><t>kind</t></si>` +
+			`<si><r><rPr><b/></rPr><t>Отделочные </t></r><r><t>работы</t></r></si>` +
+			`<si><t>категория</t><rPh sb="0" eb="1"><t>подсказка</t></rPh></si>` +
+			`</sst>`,
+		"xl/worksheets/data.xml": xmlHeader +
+			`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c></row>` +
+			`<row r="3"><c r="B3" t="s"><v>1</v></c><c r="D3" t="b"><v>1</v></c></row>` +
+			`<row><c t="str"><f>A1</f><v>kind</v></c><c><v>42</v></c><c t="s"><v>2</v></c></row>` +
+			`</sheetData></worksheet>`,
+	})
+
+	sheets, err := readBytes(data)
+
+	require.NoError(t, err)
+	require.Len(t, sheets, 1)
+	assert.Equal(t, "Лист1", sheets[0].Name)
+	assert.Equal(t, [][]string{
+		{"kind"},
+		nil,
+		{"", "Отделочные работы", "", "TRUE"},
+		{"kind", "42", "категория"},
+	}, sheets[0].Rows)
+}
+
+func TestReadSheets_RejectsBrokenFiles(t *testing.T) {
+	validBook := map[string]string{
+		"xl/workbook.xml": xmlHeader +
+			`<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="S" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+	}
+
+	t.Run("not a zip", func(t *testing.T) {
+		_, err := readBytes([]byte("kind;title\ntype;Ремонт\n"))
+		assert.Error(t, err)
+	})
+
+	t.Run("missing workbook", func(t *testing.T) {
+		_, err := readBytes(buildPackage(t, map[string]string{"docProps/app.xml": "<x/>"}))
+		assert.ErrorContains(t, err, "xl/workbook.xml")
+	})
+
+	t.Run("bad shared string index", func(t *testing.T) {
+		parts := map[string]string{
+			"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row r="1"><c r="A1" t="s"><v>5</v></c></row></sheetData></worksheet>`,
+		}
+		for k, v := range validBook {
+			parts[k] = v
+		}
+		_, err := readBytes(buildPackage(t, parts))
+		assert.ErrorContains(t, err, "shared string")
+	})
+
+	t.Run("part too large", func(t *testing.T) {
+		parts := map[string]string{
+			"xl/worksheets/sheet1.xml": `<worksheet><sheetData>` + strings.Repeat(" ", maxPartSize) + `</sheetData></worksheet>`,
+		}
+		for k, v := range validBook {
+			parts[k] = v
+		}
+		_, err := readBytes(buildPackage(t, parts))
+		assert.ErrorIs(t, err, ErrPartTooLarge)
+	})
+}
+
+func TestReadSheets_SparseWorkbookLimits(t *testing.T) {
+	book := func(sheets ...string) []byte {
+		parts := map[string]string{}
+		var names, rels strings.Builder
+		for i, content := range sheets {
+			id := strconv.Itoa(i + 1)
+			names.WriteString(`<sheet name="S` + id + `" r:id="rId` + id + `"/>`)
+			rels.WriteString(`<Relationship Id="rId` + id + `" Target="worksheets/sheet` + id + `.xml"/>`)
+			parts["xl/worksheets/sheet"+id+".xml"] = `<worksheet><sheetData>` + content + `</sheetData></worksheet>`
+		}
+		parts["xl/workbook.xml"] = `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>` + names.String() + `</sheets></workbook>`
+		parts["xl/_rels/workbook.xml.rels"] = `<Relationships>` + rels.String() + `</Relationships>`
+		return buildPackage(t, parts)
+	}
+	read := func(data []byte) ([]SheetData, error) {
+		return ReadSheets(bytes.NewReader(data), int64(len(data)), Limits{MaxRows: 10, MaxColumns: 3})
+	}
+
+	t.Run("far column dropped", func(t *testing.T) {
+		sheets, err := read(book(`<row r="1"><c r="A1"><v>1</v></c><c r="C1"><v>3</v></c><c r="XFD1"><v>x</v></c></row>`))
+
+		require.NoError(t, err)
+		require.Len(t, sheets, 1)
+		assert.Equal(t, [][]string{{"1", "", "3"}}, sheets[0].Rows)
+	})
+
+	t.Run("far row rejected", func(t *testing.T) {
+		_, err := read(book(`<row r="1"><c r="A1"><v>1</v></c></row><row r="1048576"><c r="A1048576"><v>1</v></c></row>`))
+
+		assert.ErrorIs(t, err, ErrTooManyRows)
+	})
+
+	t.Run("budget shared by sheets", func(t *testing.T) {
+		_, err := read(book(`<row r="6"><c r="A6"><v>1</v></c></row>`, `<row r="5"><c r="A5"><v>1</v></c></row>`))
+
+		assert.ErrorIs(t, err, ErrTooManyRows)
+	})
+
+	t.Run("within budget", func(t *testing.T) {
+		sheets, err := read(book(`<row r="5"><c r="A5"><v>1</v></c></row>`, `<row r="5"><c r="A5"><v>2</v></c></row>`))
+
+		require.NoError(t, err)
+		require.Len(t, sheets, 2)
+		assert.Len(t, sheets[1].Rows, 5)
+	})
+}
+
+func TestColumnIndex(t *testing.T) {
+	for ref, want := range map[string]int{"A1": 0, "Z9": 25, "AA7": 26, "ZZ1": 701} {
+		got, err := columnIndex(ref)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, ref)
+	}
+	_, err := columnIndex("17")
+	assert.Error(t, err)
+	_, err = columnIndex("ZZZZ1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,299 @@
+// Package xlsx записывает и читает простые книги Excel (Office Open XML) без
+// внешних зависимостей.
+//
+// Поддерживается ровно то, что нужно для выгрузок: несколько листов, строка
+// заголовка жирным шрифтом, текстовые и числовые ячейки. Строки пишутся
+// inline (без sharedStrings), поэтому книга собирается в один проход.
+// ReadSheets возвращает значения ячеек как текст — для загрузки таблиц,
+// подготовленных в Excel (см. read.go).
+//
+//	book := xlsx.New()
+//	sheet := book.AddSheet("Экономия")
+//	sheet.AddHeader("Тендер", "Экономия")
+//	sheet.AddRow(xlsx.Text("T-1"), xlsx.Number("100000.00"))
+//	err := book.Write(w)
+//
+//	sheets, err := xlsx.ReadSheets(bytes.NewReader(data), int64(len(data)))
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ContentType — MIME-тип файла .xlsx.
+const ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// maxSheetName — ограничение Excel на длину имени листа.
+const maxSheetName = 31
+
+type cellKind int
+
+const (
+	kindEmpty cellKind = iota
+	kindText
+	kindNumber
+)
+
+// Cell — значение ячейки.
+type Cell struct {
+	kind  cellKind
+	value string
+	bold  bool
+}
+
+// Text возвращает текстовую ячейку.
+func Text(v string) Cell { return Cell{kind: kindText, value: v} }
+
+// Number возвращает числовую ячейку. v — десятичная запись вида "-1234.50"
+// (как у NUMERIC и decimal.Decimal); любая другая строка записывается как текст.
+func Number(v string) Cell {
+	if !isDecimal(v) {
+		return Text(v)
+	}
+	return Cell{kind: kindNumber, value: v}
+}
+
+// Empty возвращает пустую ячейку.
+func Empty() Cell { return Cell{} }
+
+// Sheet — лист книги.
+type Sheet struct {
+	name string
+	rows [][]Cell
+}
+
+// Workbook — книга из одного или нескольких листов.
+type Workbook struct {
+	sheets []*Sheet
+}
+
+// New создаёт пустую книгу.
+func New() *Workbook {
+	return &Workbook{}
+}
+
+// AddSheet добавляет лист. Недопустимые в Excel символы имени заменяются
+// пробелом, имя обрезается до 31 символа; повторяющиеся имена получают суффикс.
+func (w *Workbook) AddSheet(name string) *Sheet {
+	name = sheetName(name, len(w.sheets)+1)
+	base := name
+	for i := 2; w.hasSheet(name); i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		name = truncateRunes(base, maxSheetName-len([]rune(suffix))) + suffix
+	}
+	sheet := &Sheet{name: name}
+	w.sheets = append(w.sheets, sheet)
+	return sheet
+}
+
+func (w *Workbook) hasSheet(name string) bool {
+	for _, s := range w.sheets {
+		if strings.EqualFold(s.name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddHeader добавляет строку заголовка (жирный шрифт).
+func (s *Sheet) AddHeader(titles ...string) {
+	row := make([]Cell, len(titles))
+	for i, title := range titles {
+		row[i] = Cell{kind: kindText, value: title, bold: true}
+	}
+	s.rows = append(s.rows, row)
+}
+
+// AddRow добавляет строку значений.
+func (s *Sheet) AddRow(cells ...Cell) {
+	s.rows = append(s.rows, cells)
+}
+
+// Rows возвращает число строк листа, включая заголовок.
+func (s *Sheet) Rows() int {
+	return len(s.rows)
+}
+
+// Write записывает книгу в формате .xlsx. Книга без листов получает один пустой лист.
+func (w *Workbook) Write(out io.Writer) error {
+	if len(w.sheets) == 0 {
+		w.AddSheet("")
+	}
+
+	zw := zip.NewWriter(out)
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", w.contentTypes()},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", w.workbook()},
+		{"xl/_rels/workbook.xml.rels", w.workbookRels()},
+		{"xl/styles.xml", styles},
+	}
+	for _, f := range files {
+		if err := writeFile(zw, f.name, f.content); err != nil {
+			return err
+		}
+	}
+	for i, sheet := range w.sheets {
+		if err := writeFile(zw, fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), sheet.xml()); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("xlsx: %w", err)
+	}
+	return nil
+}
+
+func writeFile(zw *zip.Writer, name, content string) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("xlsx: %s: %w", name, err)
+	}
+	if _, err := io.WriteString(f, content); err != nil {
+		return fmt.Errorf("xlsx: %s: %w", name, err)
+	}
+	return nil
+}
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+const rootRels = xmlHeader +
+	`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// styles: cellXfs 0 — обычная ячейка, 1 — жирный шрифт (заголовок).
+const styles = xmlHeader +
+	`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+	`</styleSheet>`
+
+func (w *Workbook) contentTypes() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := range w.sheets {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+func (w *Workbook) workbook() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, sheet := range w.sheets {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(sheet.name), i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+func (w *Workbook) workbookRels() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := range w.sheets {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	// Стили идут после листов, чтобы rId листов совпадали с их номерами
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(w.sheets)+1)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+func (s *Sheet) xml() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for r, row := range s.rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for c, cell := range row {
+			ref := columnName(c) + strconv.Itoa(r+1)
+			style := ""
+			if cell.bold {
+				style = ` s="1"`
+			}
+			switch cell.kind {
+			case kindText:
+				fmt.Fprintf(&b, `<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, style, escape(cell.value))
+			case kindNumber:
+				fmt.Fprintf(&b, `<c r="%s"%s><v>%s</v></c>`, ref, style, cell.value)
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+// columnName возвращает буквенное имя столбца: 0 → A, 25 → Z, 26 → AA.
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+func isDecimal(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	intPart, fracPart, hasPoint := strings.Cut(s, ".")
+	if intPart == "" || (hasPoint && fracPart == "") {
+		return false
+	}
+	for _, r := range intPart + fracPart {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// escape экранирует текст для XML. Недопустимые в XML символы заменяются на U+FFFD.
+func escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func sheetName(name string, index int) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return ' '
+		}
+		return r
+	}, name)
+	name = strings.Trim(strings.TrimSpace(name), "'")
+	if name == "" {
+		name = fmt.Sprintf("Sheet%d", index)
+	}
+	return truncateRunes(name, maxSheetName)
+}
+
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,130 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+BEHAVIORAL SCENARIOS FOR XLSX WRITER (Unit Tests)
+
+What user problems does this protect us from?
+================================================================================
+1. Broken files — Excel refuses a workbook with malformed XML or a missing part
+2. Numbers as text — sums in a report must be numeric cells, not strings
+3. Injected markup — titles with <, & or quotes must not break the sheet XML
+
+GIVEN / WHEN / THEN Scenarios:
+================================================================================
+
+SCENARIO 1: Write
+- GIVEN a workbook with two sheets, a header and text/number/empty cells
+  WHEN Write is called
+  THEN the zip contains every OOXML part, each one well-formed XML
+  AND the header is bold, decimals are <v> cells, anything else (NaN too) is escaped inline text
+
+SCENARIO 2: Sheet names
+- GIVEN names with forbidden characters, empty names, long names and duplicates
+  WHEN AddSheet is called
+  THEN names are sanitized, truncated to 31 characters and made unique
+
+SCENARIO 3: columnName
+- GIVEN column indexes 0, 25, 26, 701
+  THEN A, Z, AA, ZZ are returned
+*/
+
+func readParts(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	parts := make(map[string]string, len(zr.File))
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		parts[f.Name] = string(content)
+	}
+	return parts
+}
+
+func assertWellFormed(t *testing.T, name, content string) {
+	t.Helper()
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return
+		}
+		require.NoError(t, err, "part %s is not well-formed XML", name)
+	}
+}
+
+func TestWrite_ProducesValidWorkbook(t *testing.T) {
+	book := New()
+	sheet := book.AddSheet("Экономия")
+	sheet.AddHeader("Тендер", "Экономия", "Комментарий")
+	sheet.AddRow(Text(`ЖК "Север" <1> & Co`), Number("100000.50"), Empty())
+	sheet.AddRow(Text("T-2"), Number("не число"), Number("NaN"))
+	book.AddSheet("Итоги").AddRow(Number("-3"))
+
+	var buf bytes.Buffer
+	require.NoError(t, book.Write(&buf))
+	parts := readParts(t, buf.Bytes())
+
+	for _, name := range []string{
+		"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels",
+		"xl/styles.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml",
+	} {
+		require.Contains(t, parts, name)
+		assertWellFormed(t, name, parts[name])
+	}
+
+	assert.Contains(t, parts["xl/workbook.xml"], `<sheet name="Экономия" sheetId="1" r:id="rId1"/>`)
+	assert.Contains(t, parts["xl/workbook.xml"], `<sheet name="Итоги" sheetId="2" r:id="rId2"/>`)
+
+	first := parts["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, first, `<c r="A1" s="1" t="inlineStr"><is><t xml:space="preserve">Тендер</t></is></c>`)
+	assert.Contains(t, first, `ЖК &#34;Север&#34; &lt;1&gt; &amp; Co`)
+	assert.Contains(t, first, `<c r="B2"><v>100000.50</v></c>`)
+	assert.NotContains(t, first, `r="C2"`, "пустая ячейка не пишется")
+	assert.Contains(t, first, `<c r="B3" t="inlineStr"><is><t xml:space="preserve">не число</t></is></c>`)
+	assert.Contains(t, first, `<c r="C3" t="inlineStr"><is><t xml:space="preserve">NaN</t></is></c>`)
+	assert.Equal(t, 3, sheet.Rows())
+}
+
+func TestWrite_EmptyWorkbookGetsOneSheet(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, New().Write(&buf))
+	parts := readParts(t, buf.Bytes())
+	assert.Contains(t, parts, "xl/worksheets/sheet1.xml")
+	assert.Contains(t, parts["xl/workbook.xml"], `name="Sheet1"`)
+}
+
+func TestAddSheet_SanitizesNames(t *testing.T) {
+	book := New()
+	assert.Equal(t, "Итоги 2025 01", book.AddSheet("Итоги 2025/01").name)
+	assert.Equal(t, "Sheet2", book.AddSheet("  ").name)
+
+	long := book.AddSheet(strings.Repeat("ж", 40)).name
+	assert.Equal(t, 31, len([]rune(long)))
+
+	duplicate := book.AddSheet(strings.Repeat("ж", 40)).name
+	assert.Equal(t, strings.Repeat("ж", 27)+" (2)", duplicate)
+	assert.Equal(t, "итоги 2025 01 (2)", book.AddSheet("итоги 2025 01").name, "имена сравниваются без учёта регистра")
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "ZZ", columnName(701))
+}
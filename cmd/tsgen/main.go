@@ -0,0 +1,49 @@
+// Команда tsgen генерирует TypeScript-интерфейсы для DTO ответов API.
+//
+// Запуск: make ts-types (или go generate ./cmd/internal/server/).
+// Список типов задаётся в server.TypeScriptDTOs.
+//
+// Логгер приложения здесь не используется: он пишет в stdout и создаёт
+// каталог logs/ в текущей директории, что мешает выводу в stdout и go generate.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/zhukovvlad/tenders-go/cmd/internal/server"
+	"github.com/zhukovvlad/tenders-go/cmd/pkg/tsgen"
+)
+
+func main() {
+	out := flag.String("out", "", "output .ts file (stdout if empty)")
+	flag.Parse()
+
+	var buf bytes.Buffer
+	if err := tsgen.Generate(&buf, server.TypeScriptDTOs()); err != nil {
+		fatalf("error generating TypeScript types: %v", err)
+	}
+
+	if *out == "" {
+		if _, err := os.Stdout.Write(buf.Bytes()); err != nil {
+			fatalf("error writing to stdout: %v", err)
+		}
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(*out), 0o755); err != nil {
+		fatalf("error creating output directory: %v", err)
+	}
+	if err := os.WriteFile(*out, buf.Bytes(), 0o644); err != nil {
+		fatalf("error writing %s: %v", *out, err)
+	}
+	fmt.Fprintf(os.Stderr, "TypeScript types written to %s\n", *out)
+}
+
+func fatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}